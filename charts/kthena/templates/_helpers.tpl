@@ -49,3 +49,16 @@ Selector labels
 app.kubernetes.io/name: {{ include "kthena.name" . }}
 app.kubernetes.io/instance: {{ .Release.Name }}
 {{- end }}
+
+{{/*
+IP families of the Services, set in IPv6 single-stack and dual-stack clusters
+*/}}
+{{- define "kthena.ipFamilies" -}}
+{{- with .Values.global.ipFamilyPolicy }}
+ipFamilyPolicy: {{ . }}
+{{- end }}
+{{- with .Values.global.ipFamilies }}
+ipFamilies:
+{{- toYaml . | nindent 2 }}
+{{- end }}
+{{- end }}
@@ -19,11 +19,16 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 )
 
 // describeCmd represents the describe command
@@ -183,6 +188,15 @@ func runDescribeModelServing(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Created: %s\n", modelServing.CreationTimestamp.Time.Format(time.RFC3339))
 	fmt.Printf("Age: %s\n\n", time.Since(modelServing.CreationTimestamp.Time).Truncate(time.Second))
 
+	if len(modelServing.Status.Groups) > 0 {
+		fmt.Println("ServingGroups:")
+		fmt.Println("==============")
+		if err := printServingGroups(modelServing.Status.Groups); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
 	// Output the full resource as YAML
 	data, err := yaml.Marshal(modelServing)
 	if err != nil {
@@ -196,6 +210,28 @@ func runDescribeModelServing(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printServingGroups prints the phase, revision and ready role replicas of the ServingGroups.
+func printServingGroups(groups []workloadv1alpha1.ServingGroupStatus) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPHASE\tREVISION\tROLES\tSINCE")
+	for _, group := range groups {
+		roles := make([]string, 0, len(group.Roles))
+		for _, role := range group.Roles {
+			roles = append(roles, fmt.Sprintf("%s %d/%d", role.Name, role.ReadyReplicas, role.Replicas))
+		}
+		phase := string(group.Phase)
+		if group.Standby {
+			phase += " (standby)"
+		}
+		since := "<unknown>"
+		if !group.LastTransitionTime.IsZero() {
+			since = time.Since(group.LastTransitionTime.Time).Truncate(time.Second).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", group.Name, phase, group.Revision, strings.Join(roles, ", "), since)
+	}
+	return w.Flush()
+}
+
 func runDescribeAutoscalingPolicy(cmd *cobra.Command, args []string) error {
 	policyName := args[0]
 
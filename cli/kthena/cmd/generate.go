@@ -0,0 +1,500 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/strvals"
+	sigsyaml "sigs.k8s.io/yaml"
+
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+var (
+	generateProfile      string
+	generateModel        string
+	generateName         string
+	generateNamespace    string
+	generateOutput       string
+	generateOutputDir    string
+	generateProfilesFile string
+	generateValues       []string
+)
+
+const (
+	// generateDir holds the templates, the default values and the built-in profiles of the generate command.
+	generateDir = "helm/generate"
+	// defaultProfilesFile is the profiles file read when --profiles-file is not set, relative to the home directory.
+	defaultProfilesFile = ".kthena/profiles.yaml"
+)
+
+// generateTemplates are the templates of the generated manifests, in the order they are output.
+var generateTemplates = []string{"model-serving.yaml", "autoscaling.yaml", "routing.yaml"}
+
+// GenerateProfile is a named set of values, e.g. the hardware a model is served on.
+type GenerateProfile struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Values      map[string]interface{} `json:"values,omitempty"`
+	// Source is where the profile comes from: built-in or the path of the profiles file.
+	Source string `json:"source"`
+}
+
+// generateCmd represents the generate command
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate the manifests serving a model with a profile",
+	Long: `Generate a complete and commented set of manifests serving a model: a ModelServing
+running the inference engine, an AutoscalingPolicy with its binding, and a ModelServer
+with a ModelRoute exposing the model through kthena-router.
+
+The hardware the model runs on is described by a profile: the accelerators of each
+replica, the tensor parallel size, the CPU, memory and node selector. Profiles are
+built in, and can be overridden or added in ~/.kthena/profiles.yaml (see
+"kthena get profiles"). Any value can be overridden with --set.
+
+Output formats:
+  yaml       the manifests, printed or written to <output-dir>/<name>.yaml
+  kustomize  one file per manifest and a kustomization.yaml, written to --output-dir
+  helm       the commented Helm values, printed, or a complete chart written to --output-dir
+
+Examples:
+  kthena generate --profile a100-tp2 --model meta-llama/Llama-3-70B
+  kthena generate --profile a100-tp2 --model meta-llama/Llama-3-70B --set engine.name=SGLang,maxModelLen=16384
+  kthena generate --profile l4-tp1 --model Qwen/Qwen3-8B -o kustomize --output-dir ./qwen3-8b
+  kthena generate --profile h100-tp8 --model deepseek-ai/DeepSeek-R1 -o helm --output-dir ./charts/deepseek-r1`,
+	Args: cobra.NoArgs,
+	RunE: runGenerate,
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+
+	generateCmd.Flags().StringVarP(&generateProfile, "profile", "p", "", "Profile of the hardware the model is served on (required)")
+	generateCmd.Flags().StringVarP(&generateModel, "model", "m", "", "Hugging Face id of the model, or its path in the image (required)")
+	generateCmd.Flags().StringVar(&generateName, "name", "", "Name of the generated resources (default: the model name)")
+	generateCmd.Flags().StringVarP(&generateNamespace, "namespace", "n", "default", "Namespace of the generated resources")
+	generateCmd.Flags().StringVarP(&generateOutput, "output", "o", "yaml", "Output format: yaml, kustomize or helm")
+	generateCmd.Flags().StringVar(&generateOutputDir, "output-dir", "", "Directory the output is written to instead of the standard output")
+	generateCmd.Flags().StringVar(&generateProfilesFile, "profiles-file", "", "File overriding and adding profiles (default: ~/.kthena/profiles.yaml)")
+	generateCmd.Flags().StringArrayVar(&generateValues, "set", nil, "Set values, e.g. engine.name=SGLang,autoscaling.maxReplicas=8 (can specify multiple)")
+
+	for _, flag := range []string{"profile", "model"} {
+		if err := generateCmd.MarkFlagRequired(flag); err != nil {
+			panic(fmt.Sprintf("failed to mark %s flag as required: %v", flag, err))
+		}
+	}
+}
+
+func runGenerate(cmd *cobra.Command, args []string) error {
+	switch generateOutput {
+	case "yaml", "helm":
+	case "kustomize":
+		if generateOutputDir == "" {
+			return fmt.Errorf("--output-dir is required with the kustomize output")
+		}
+	default:
+		return fmt.Errorf("unsupported output format %q, must be yaml, kustomize or helm", generateOutput)
+	}
+
+	profiles, err := loadGenerateProfiles(generateProfilesFile)
+	if err != nil {
+		return err
+	}
+	profile, ok := profiles[generateProfile]
+	if !ok {
+		return fmt.Errorf("profile %q not found, see \"kthena get profiles\"", generateProfile)
+	}
+	values, err := generateValuesOf(profile)
+	if err != nil {
+		return err
+	}
+	name, manifests, err := renderGenerateTemplates(values)
+	if err != nil {
+		return err
+	}
+
+	header := fmt.Sprintf("# Generated by \"kthena generate --profile %s --model %s\".\n# Profile %s: %s\n",
+		profile.Name, generateModel, profile.Name, profile.Description)
+	switch generateOutput {
+	case "kustomize":
+		return writeKustomization(generateOutputDir, header, values["namespace"], manifests)
+	case "helm":
+		valuesYAML, err := commentedValues(values)
+		if err != nil {
+			return err
+		}
+		valuesYAML = append([]byte(header), valuesYAML...)
+		if generateOutputDir == "" {
+			fmt.Print(string(valuesYAML))
+			return nil
+		}
+		return writeChart(generateOutputDir, name, profile, valuesYAML)
+	}
+
+	var out strings.Builder
+	out.WriteString(header)
+	for _, file := range generateTemplates {
+		if manifests[file] == "" {
+			continue
+		}
+		out.WriteString("---\n")
+		out.WriteString(manifests[file])
+	}
+	if generateOutputDir == "" {
+		fmt.Print(out.String())
+		return nil
+	}
+	return writeGeneratedFiles(generateOutputDir, map[string]string{name + ".yaml": out.String()})
+}
+
+// loadGenerateProfiles returns the built-in profiles, overridden by the profiles of the file. The values of a
+// profile of the file are merged into the built-in profile of the same name. The default file may not exist.
+func loadGenerateProfiles(file string) (map[string]*GenerateProfile, error) {
+	data, err := templatesFS.ReadFile(path.Join(generateDir, "profiles.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the built-in profiles: %v", err)
+	}
+	profiles, err := parseGenerateProfiles(data, "built-in")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the built-in profiles: %v", err)
+	}
+
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return profiles, nil
+		}
+		file = filepath.Join(home, defaultProfilesFile)
+		if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
+			return profiles, nil
+		}
+	}
+	data, err = os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles file: %v", err)
+	}
+	overrides, err := parseGenerateProfiles(data, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profiles file %s: %v", file, err)
+	}
+	for name, override := range overrides {
+		profile, ok := profiles[name]
+		if !ok {
+			profiles[name] = override
+			continue
+		}
+		if override.Description != "" {
+			profile.Description = override.Description
+		}
+		profile.Values = mergeValues(profile.Values, override.Values)
+		profile.Source = file
+	}
+	return profiles, nil
+}
+
+func parseGenerateProfiles(data []byte, source string) (map[string]*GenerateProfile, error) {
+	profiles := make(map[string]*GenerateProfile)
+	if err := sigsyaml.Unmarshal(data, &profiles); err != nil {
+		return nil, err
+	}
+	for name, profile := range profiles {
+		if profile == nil {
+			profile = &GenerateProfile{}
+			profiles[name] = profile
+		}
+		profile.Name = name
+		profile.Source = source
+	}
+	return profiles, nil
+}
+
+// sortedGenerateProfiles returns the profiles sorted by name.
+func sortedGenerateProfiles(profiles map[string]*GenerateProfile) []*GenerateProfile {
+	sorted := make([]*GenerateProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		sorted = append(sorted, profile)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// generateValuesOf returns the values of the templates: the defaults, overridden by the profile, the flags
+// and the --set values, in that order.
+func generateValuesOf(profile *GenerateProfile) (map[string]interface{}, error) {
+	data, err := templatesFS.ReadFile(path.Join(generateDir, "values.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the default values: %v", err)
+	}
+	values := make(map[string]interface{})
+	if err := sigsyaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse the default values: %v", err)
+	}
+
+	values = mergeValues(values, profile.Values)
+	values["model"] = generateModel
+	values["namespace"] = generateNamespace
+	if generateName != "" {
+		values["name"] = generateName
+	}
+	for _, set := range generateValues {
+		if err := strvals.ParseInto(set, values); err != nil {
+			return nil, fmt.Errorf("invalid --set %q: %v", set, err)
+		}
+	}
+	return values, validateGenerateValues(values)
+}
+
+// validateGenerateValues checks the values the templates cannot render sensibly.
+func validateGenerateValues(values map[string]interface{}) error {
+	engineValues, _ := values["engine"].(map[string]interface{})
+	switch engineName := fmt.Sprint(engineValues["name"]); engineName {
+	case string(networkingv1alpha1.VLLM), string(networkingv1alpha1.SGLang):
+	default:
+		return fmt.Errorf("unsupported engine %q, must be %s or %s", engineName, networkingv1alpha1.VLLM, networkingv1alpha1.SGLang)
+	}
+
+	resources, _ := values["resources"].(map[string]interface{})
+	tp, count := fmt.Sprint(values["tensorParallelSize"]), fmt.Sprint(resources["count"])
+	var tpSize, accelerators float64
+	if _, err := fmt.Sscan(tp, &tpSize); err != nil || tpSize < 1 {
+		return fmt.Errorf("tensorParallelSize must be a positive integer, got %s", tp)
+	}
+	if _, err := fmt.Sscan(count, &accelerators); err != nil || accelerators < tpSize {
+		return fmt.Errorf("resources.count must be at least the tensorParallelSize %s, got %s", tp, count)
+	}
+	return nil
+}
+
+// mergeValues returns the values of dst overridden by those of src, merging the nested maps.
+func mergeValues(dst, src map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(dst)+len(src))
+	for k, v := range dst {
+		merged[k] = v
+	}
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := merged[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			merged[k] = mergeValues(dstMap, srcMap)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// generateChartFiles returns the embedded templates of the generate command, by their path in the chart.
+func generateChartFiles() ([]*chart.File, error) {
+	entries, err := templatesFS.ReadDir(path.Join(generateDir, "templates"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the generate templates: %v", err)
+	}
+	var files []*chart.File
+	for _, entry := range entries {
+		data, err := templatesFS.ReadFile(path.Join(generateDir, "templates", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %v", entry.Name(), err)
+		}
+		files = append(files, &chart.File{Name: "templates/" + entry.Name(), Data: data})
+	}
+	return files, nil
+}
+
+// renderGenerateTemplates renders the templates with the values. It returns the name of the resources and the
+// manifests by template file, without the templates rendering nothing.
+func renderGenerateTemplates(values map[string]interface{}) (string, map[string]string, error) {
+	files, err := generateChartFiles()
+	if err != nil {
+		return "", nil, err
+	}
+	files = append(files, &chart.File{Name: "templates/name", Data: []byte(`{{ include "kthena.name" . }}`)})
+	helmChart := &chart.Chart{
+		Metadata:  &chart.Metadata{Name: "kthena-generate", Version: "1.0.0"},
+		Templates: files,
+	}
+	rendered, err := engine.Render(helmChart, map[string]interface{}{"Values": values})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to render the manifests: %v", err)
+	}
+
+	name := strings.TrimSpace(rendered["kthena-generate/templates/name"])
+	if name == "" {
+		return "", nil, fmt.Errorf("the name of the resources cannot be derived from the model, set it with --name")
+	}
+	manifests := make(map[string]string)
+	for _, file := range generateTemplates {
+		manifest := strings.TrimSpace(rendered["kthena-generate/templates/"+file])
+		if manifest != "" {
+			manifests[file] = manifest + "\n"
+		}
+	}
+	return name, manifests, nil
+}
+
+// commentedValues returns the values as the default values file, keeping its comments.
+func commentedValues(values map[string]interface{}) ([]byte, error) {
+	data, err := templatesFS.ReadFile(path.Join(generateDir, "values.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the default values: %v", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse the default values: %v", err)
+	}
+	if len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("the default values are not a map")
+	}
+	if err := setValueNodes(doc.Content[0], values); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to marshal the values: %v", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// setValueNodes sets the values in the mapping node. The keys already in the node keep their place and their
+// comments, the others are appended in order.
+func setValueNodes(node *yaml.Node, values map[string]interface{}) error {
+	set := make(map[string]bool, len(values))
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, valueNode := node.Content[i].Value, node.Content[i+1]
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		set[key] = true
+		if nested, isMap := value.(map[string]interface{}); isMap && valueNode.Kind == yaml.MappingNode {
+			if len(nested) > 0 {
+				valueNode.Style = 0
+			}
+			if err := setValueNodes(valueNode, nested); err != nil {
+				return err
+			}
+			continue
+		}
+		var newNode yaml.Node
+		if err := newNode.Encode(value); err != nil {
+			return fmt.Errorf("failed to marshal value %s: %v", key, err)
+		}
+		newNode.HeadComment, newNode.LineComment, newNode.FootComment = valueNode.HeadComment, valueNode.LineComment, valueNode.FootComment
+		node.Content[i+1] = &newNode
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !set[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var keyNode, valueNode yaml.Node
+		keyNode.SetString(key)
+		if err := valueNode.Encode(values[key]); err != nil {
+			return fmt.Errorf("failed to marshal value %s: %v", key, err)
+		}
+		node.Content = append(node.Content, &keyNode, &valueNode)
+	}
+	return nil
+}
+
+// writeKustomization writes each manifest to its own file, with a kustomization.yaml listing them.
+func writeKustomization(dir, header string, namespace interface{}, manifests map[string]string) error {
+	files := make(map[string]string)
+	var resources strings.Builder
+	for _, file := range generateTemplates {
+		if manifests[file] == "" {
+			continue
+		}
+		files[file] = header + manifests[file]
+		resources.WriteString("  - " + file + "\n")
+	}
+	files["kustomization.yaml"] = fmt.Sprintf("%sapiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nnamespace: %v\nresources:\n%s",
+		header, namespace, resources.String())
+	return writeGeneratedFiles(dir, files)
+}
+
+// writeChart writes a Helm chart rendering the manifests with the values.
+func writeChart(dir, name string, profile *GenerateProfile, values []byte) error {
+	templates, err := generateChartFiles()
+	if err != nil {
+		return err
+	}
+	metadata, err := sigsyaml.Marshal(&chart.Metadata{
+		APIVersion:  chart.APIVersionV2,
+		Name:        name,
+		Description: fmt.Sprintf("Serves %s on the %s profile with Kthena", generateModel, profile.Name),
+		Type:        "application",
+		Version:     "0.1.0",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Chart.yaml: %v", err)
+	}
+
+	files := map[string]string{
+		"Chart.yaml":  string(metadata),
+		"values.yaml": string(values),
+	}
+	for _, file := range templates {
+		files[file.Name] = string(file.Data)
+	}
+	return writeGeneratedFiles(dir, files)
+}
+
+// writeGeneratedFiles writes the files, by their path relative to the directory.
+func writeGeneratedFiles(dir string, files map[string]string) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(file, []byte(files[name]), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", file, err)
+		}
+		fmt.Printf("Wrote %s\n", file)
+	}
+	return nil
+}
@@ -27,6 +27,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/volcano-sh/kthena/client-go/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/yaml"
 )
@@ -43,10 +45,11 @@ var getCmd = &cobra.Command{
 	Short: "Display one or many resources",
 	Long: `Display one or many resources.
 
-You can get templates, models, model-servings, and autoscaling policies.
+You can get templates, generate profiles, models, model-servings, and autoscaling policies.
 
 Examples:
   kthena get templates
+  kthena get profiles
   kthena get template deepseek-r1-distill-llama-8b
   kthena get template deepseek-r1-distill-llama-8b -o yaml
   kthena get model-boosters
@@ -76,6 +79,19 @@ Use -o yaml flag to output the template content in YAML format.`,
 	RunE: runGetTemplate,
 }
 
+// getProfilesCmd represents the get profiles command
+var getProfilesCmd = &cobra.Command{
+	Use:     "profiles",
+	Aliases: []string{"profile"},
+	Short:   "List the profiles of the generate command",
+	Long: `List the profiles the manifests of "kthena generate" can be generated with.
+
+The built-in profiles are overridden, and new profiles are added, by the profiles
+file, ~/.kthena/profiles.yaml by default. Use -o yaml to print their values.`,
+	Args: cobra.NoArgs,
+	RunE: runGetProfiles,
+}
+
 // getModelBoostersCmd represents the get model-boosters command
 var getModelBoostersCmd = &cobra.Command{
 	Use:     "model-boosters [NAME]",
@@ -119,6 +135,7 @@ func init() {
 	rootCmd.AddCommand(getCmd)
 	getCmd.AddCommand(getTemplatesCmd)
 	getCmd.AddCommand(getTemplateCmd)
+	getCmd.AddCommand(getProfilesCmd)
 	getCmd.AddCommand(getModelBoostersCmd)
 	getCmd.AddCommand(getModelServingsCmd)
 	getCmd.AddCommand(getAutoscalingPoliciesCmd)
@@ -130,6 +147,8 @@ func init() {
 	// Add namespace flags
 	getCmd.PersistentFlags().StringVarP(&getNamespace, "namespace", "n", "", "Kubernetes namespace (default: current context namespace)")
 	getCmd.PersistentFlags().BoolVarP(&getAllNamespaces, "all-namespaces", "A", false, "List resources across all namespaces")
+
+	getProfilesCmd.Flags().StringVar(&generateProfilesFile, "profiles-file", "", "File overriding and adding profiles (default: ~/.kthena/profiles.yaml)")
 }
 
 func runGetTemplates(cmd *cobra.Command, args []string) error {
@@ -189,6 +208,30 @@ func runGetTemplates(cmd *cobra.Command, args []string) error {
 	return w.Flush()
 }
 
+func runGetProfiles(cmd *cobra.Command, args []string) error {
+	profiles, err := loadGenerateProfiles(generateProfilesFile)
+	if err != nil {
+		return err
+	}
+	sorted := sortedGenerateProfiles(profiles)
+
+	if outputFormat == "yaml" {
+		data, err := yaml.Marshal(sorted)
+		if err != nil {
+			return fmt.Errorf("failed to marshal to YAML: %v", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSOURCE\tDESCRIPTION")
+	for _, profile := range sorted {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", profile.Name, profile.Source, profile.Description)
+	}
+	return w.Flush()
+}
+
 func runGetTemplate(cmd *cobra.Command, args []string) error {
 	templateName := args[0]
 
@@ -217,11 +260,19 @@ func runGetTemplate(cmd *cobra.Command, args []string) error {
 	return w.Flush()
 }
 
-func getKthenaClient() (*versioned.Clientset, error) {
+func getRestConfig() (*rest.Config, error) {
 	config, err := clientcmd.BuildConfigFromFlags("", clientcmd.RecommendedHomeFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
 	}
+	return config, nil
+}
+
+func getKthenaClient() (*versioned.Clientset, error) {
+	config, err := getRestConfig()
+	if err != nil {
+		return nil, err
+	}
 
 	client, err := versioned.NewForConfig(config)
 	if err != nil {
@@ -231,6 +282,20 @@ func getKthenaClient() (*versioned.Clientset, error) {
 	return client, nil
 }
 
+func getKubeClient() (kubernetes.Interface, error) {
+	config, err := getRestConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %v", err)
+	}
+
+	return client, nil
+}
+
 func resolveGetNamespace() string {
 	if getAllNamespaces {
 		return ""
@@ -0,0 +1,371 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+var (
+	logsNamespace  string
+	logsRole       string
+	logsGroup      string
+	logsContainer  string
+	logsSince      time.Duration
+	logsTail       int64
+	logsFollow     bool
+	logsTimestamps bool
+	logsPrefix     bool
+	logsColor      string
+)
+
+const (
+	// logsPollInterval is how often the pods are listed again while following, to stream the new ones.
+	logsPollInterval = 5 * time.Second
+	// logsMaxBackoff bounds the delay between two reconnections to the logs of a container.
+	logsMaxBackoff = 30 * time.Second
+)
+
+// logColors are the ANSI colors of the prefixes, one per container.
+var logColors = []string{"31", "32", "33", "34", "35", "36", "91", "92", "93", "94", "95", "96"}
+
+// logsCmd represents the logs command
+var logsCmd = &cobra.Command{
+	Use:   "logs model-serving/NAME",
+	Short: "Print the logs of all the pods of a model serving workload",
+	Long: `Print the logs of all the pods of a ModelServing, merged line by line.
+
+Each line is prefixed with the pod and the container it comes from, so that the
+pods generated by the controller do not have to be looked up. The pods can be
+narrowed down to a role or a ServingGroup.
+
+With --follow, the logs are streamed until interrupted: the streams are reconnected
+when they are cut, and the pods created afterwards, e.g. when a ServingGroup is
+recreated, are streamed too.
+
+Examples:
+  kthena logs model-serving/my-serving
+  kthena logs ms/my-serving --role decode --since 10m --follow
+  kthena logs my-serving --group my-serving-0 -c engine --tail 100`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogs,
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	logsCmd.Flags().StringVarP(&logsNamespace, "namespace", "n", "", "Kubernetes namespace (default: current context namespace)")
+	logsCmd.Flags().StringVar(&logsRole, "role", "", "Only print the logs of the pods of this role")
+	logsCmd.Flags().StringVar(&logsGroup, "group", "", "Only print the logs of the pods of this ServingGroup")
+	logsCmd.Flags().StringVarP(&logsContainer, "container", "c", "", "Only print the logs of this container (default: all the containers)")
+	logsCmd.Flags().DurationVar(&logsSince, "since", 0, "Only print the logs newer than a relative duration like 10m or 1h")
+	logsCmd.Flags().Int64Var(&logsTail, "tail", -1, "Number of recent lines printed per container, -1 for all of them")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Stream the logs until interrupted")
+	logsCmd.Flags().BoolVar(&logsTimestamps, "timestamps", false, "Include the timestamp of each line")
+	logsCmd.Flags().BoolVar(&logsPrefix, "prefix", true, "Prefix each line with its pod and container")
+	logsCmd.Flags().StringVar(&logsColor, "color", "auto", "Color the prefixes: auto, always or never")
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	name, err := parseLogsTarget(args[0])
+	if err != nil {
+		return err
+	}
+	if logsColor != "auto" && logsColor != "always" && logsColor != "never" {
+		return fmt.Errorf("invalid --color %q, must be auto, always or never", logsColor)
+	}
+
+	client, err := getKthenaClient()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := getKubeClient()
+	if err != nil {
+		return err
+	}
+
+	namespace := logsNamespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if _, err := client.WorkloadV1alpha1().ModelServings(namespace).Get(ctx, name, metav1.GetOptions{}); err != nil {
+		return fmt.Errorf("failed to get ModelServing '%s': %v", name, err)
+	}
+
+	selector := labels.Set{workloadv1alpha1.ModelServingNameLabelKey: name}
+	if logsRole != "" {
+		selector[workloadv1alpha1.RoleLabelKey] = logsRole
+	}
+	if logsGroup != "" {
+		selector[workloadv1alpha1.GroupNameLabelKey] = logsGroup
+	}
+	streamer := &logStreamer{
+		client:    kubeClient,
+		namespace: namespace,
+		selector:  selector.AsSelector().String(),
+		out:       os.Stdout,
+		color:     logsColor == "always" || (logsColor == "auto" && isTerminal(os.Stdout)),
+		streams:   make(map[string]bool),
+		colors:    make(map[string]string),
+	}
+	return streamer.run(ctx)
+}
+
+// parseLogsTarget returns the name of the ModelServing, given as NAME or model-serving/NAME.
+func parseLogsTarget(target string) (string, error) {
+	kind, name, found := strings.Cut(target, "/")
+	if !found {
+		return target, nil
+	}
+	switch strings.ToLower(kind) {
+	case "model-serving", "modelserving", "model-servings", "modelservings", "ms":
+	default:
+		return "", fmt.Errorf("unsupported resource type %q, only model-serving is supported", kind)
+	}
+	if name == "" {
+		return "", fmt.Errorf("the name of the model serving must be specified")
+	}
+	return name, nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// logStreamer streams the logs of the containers of the selected pods, and writes them line by line.
+type logStreamer struct {
+	client    kubernetes.Interface
+	namespace string
+	selector  string
+	out       io.Writer
+	color     bool
+
+	mutex sync.Mutex
+	// streams are the containers being streamed, by pod UID and container name
+	streams map[string]bool
+	// colors are the colors of the prefixes, by pod and container name
+	colors map[string]string
+	wg     sync.WaitGroup
+}
+
+func (s *logStreamer) run(ctx context.Context) error {
+	pods, err := s.listPods(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 && !logsFollow {
+		return fmt.Errorf("no pods found for selector %s", s.selector)
+	}
+	s.startStreams(ctx, pods, true)
+	if !logsFollow {
+		s.wg.Wait()
+		return nil
+	}
+
+	ticker := time.NewTicker(logsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+			return nil
+		case <-ticker.C:
+			pods, err := s.listPods(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					fmt.Fprintf(os.Stderr, "failed to list pods: %v\n", err)
+				}
+				continue
+			}
+			// The pods created since are printed from their start
+			s.startStreams(ctx, pods, false)
+		}
+	}
+}
+
+// listPods returns the pods of the selector, sorted by name so that their colors are stable.
+func (s *logStreamer) listPods(ctx context.Context) ([]corev1.Pod, error) {
+	list, err := s.client.CoreV1().Pods(s.namespace).List(ctx, metav1.ListOptions{LabelSelector: s.selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+	return list.Items, nil
+}
+
+// startStreams streams the containers of the pods which are not streamed yet. Only the initial streams
+// honor --since and --tail.
+func (s *logStreamer) startStreams(ctx context.Context, pods []corev1.Pod, initial bool) {
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodPending {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			if logsContainer != "" && container.Name != logsContainer {
+				continue
+			}
+			key := string(pod.UID) + "/" + container.Name
+			s.mutex.Lock()
+			streaming := s.streams[key]
+			s.streams[key] = true
+			s.mutex.Unlock()
+			if streaming {
+				continue
+			}
+
+			prefix := s.prefix(pod.Name, container.Name)
+			s.wg.Add(1)
+			go func(pod *corev1.Pod, container string) {
+				defer s.wg.Done()
+				s.stream(ctx, pod.Name, pod.UID, container, prefix, initial)
+				// A container whose pod still exists is streamed again by the next poll
+				s.mutex.Lock()
+				delete(s.streams, key)
+				s.mutex.Unlock()
+			}(pod, container.Name)
+		}
+	}
+}
+
+// stream prints the logs of a container. While following, the stream is reconnected from the last printed line
+// until the pod is deleted or has completed.
+func (s *logStreamer) stream(ctx context.Context, podName string, podUID types.UID, container, prefix string, initial bool) {
+	options := &corev1.PodLogOptions{Container: container, Follow: logsFollow, Timestamps: true}
+	if initial {
+		if logsSince > 0 {
+			options.SinceSeconds = ptr.To(int64(logsSince.Seconds()))
+		}
+		if logsTail >= 0 {
+			options.TailLines = ptr.To(logsTail)
+		}
+	}
+
+	var last time.Time
+	backoff := time.Second
+	for {
+		logs, err := s.client.CoreV1().Pods(s.namespace).GetLogs(podName, options).Stream(ctx)
+		if err == nil {
+			if printed := s.copyLines(logs, prefix, last); printed.After(last) {
+				last = printed
+				backoff = time.Second
+			}
+			logs.Close()
+		} else if ctx.Err() == nil && !logsFollow {
+			fmt.Fprintf(os.Stderr, "failed to get the logs of %s/%s: %v\n", podName, container, err)
+		}
+		if !logsFollow || ctx.Err() != nil || !s.running(ctx, podName, podUID) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, logsMaxBackoff)
+		options.SinceSeconds, options.TailLines = nil, nil
+		if !last.IsZero() {
+			options.SinceTime = &metav1.Time{Time: last}
+		}
+	}
+}
+
+// running reports whether the pod still exists and may write more logs.
+func (s *logStreamer) running(ctx context.Context, podName string, podUID types.UID) bool {
+	pod, err := s.client.CoreV1().Pods(s.namespace).Get(ctx, podName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false
+	}
+	if err != nil {
+		// The API server may be unreachable for a while
+		return true
+	}
+	return pod.UID == podUID && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed
+}
+
+// copyLines prints the lines of the logs newer than after, and returns the timestamp of the last printed one.
+// The lines of a reconnected stream starting at the same time as the last printed one are skipped.
+func (s *logStreamer) copyLines(logs io.Reader, prefix string, after time.Time) time.Time {
+	last := after
+	reader := bufio.NewReader(logs)
+	for {
+		line, err := reader.ReadString('\n')
+		if text := strings.TrimSuffix(line, "\n"); line != "" {
+			if timestamp, message, ok := strings.Cut(text, " "); ok {
+				if t, parseErr := time.Parse(time.RFC3339Nano, timestamp); parseErr == nil {
+					if !t.After(after) {
+						continue
+					}
+					last = t
+					if !logsTimestamps {
+						text = message
+					}
+				}
+			}
+			s.mutex.Lock()
+			fmt.Fprintln(s.out, prefix+text)
+			s.mutex.Unlock()
+		}
+		if err != nil {
+			return last
+		}
+	}
+}
+
+// prefix returns the prefix of the lines of a container, colored with a color of its own when enabled.
+func (s *logStreamer) prefix(podName, container string) string {
+	if !logsPrefix {
+		return ""
+	}
+	name := podName + "/" + container
+	if !s.color {
+		return "[" + name + "] "
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	color, ok := s.colors[name]
+	if !ok {
+		color = logColors[len(s.colors)%len(logColors)]
+		s.colors[name] = color
+	}
+	return "\x1b[" + color + "m[" + name + "]\x1b[0m "
+}
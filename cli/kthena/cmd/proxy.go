@@ -0,0 +1,333 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+var (
+	proxyAddress         string
+	proxyPort            int
+	proxyRouterNamespace string
+	proxyNamespace       string
+	proxyPod             string
+	proxyTargetPort      int
+	proxyToken           string
+)
+
+const (
+	// routerSelector selects the pods of kthena-router deployed by the Helm chart.
+	routerSelector = "app.kubernetes.io/component=kthena-router"
+	// routerDefaultPort is the port of kthena-router when its pods do not name it.
+	routerDefaultPort = 8080
+	// proxyTokenEnv is the environment variable holding the token sent by the proxy.
+	proxyTokenEnv = "KTHENA_TOKEN"
+	// proxyRetryInterval is the delay before forwarding again once the forwarded pod is gone.
+	proxyRetryInterval = 2 * time.Second
+)
+
+// proxyCmd represents the proxy command
+var proxyCmd = &cobra.Command{
+	Use:   "proxy MODEL",
+	Short: "Serve a cluster model on a local OpenAI-compatible endpoint",
+	Long: `Serve a model of the cluster on a local OpenAI-compatible endpoint.
+
+The requests to the local endpoint are forwarded to a kthena-router pod, or to a
+selected pod with --pod, through the Kubernetes API server, like kubectl port-forward.
+The model is set in the requests which do not name one, and the token, from --token
+or the KTHENA_TOKEN environment variable, is sent as a bearer token in the requests
+which have no Authorization header. When the forwarded pod goes away, another one
+is picked.
+
+Local tools using the OpenAI API only need to be pointed at the local endpoint:
+
+  export OPENAI_BASE_URL=http://127.0.0.1:8000/v1
+
+Examples:
+  kthena proxy deepseek-r1
+  kthena proxy deepseek-r1 --port 9000 --token "$(cat token.jwt)"
+  kthena proxy deepseek-r1 -n team-a --pod deepseek-r1-0-leader-0 --target-port 8000`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProxy,
+}
+
+func init() {
+	rootCmd.AddCommand(proxyCmd)
+
+	proxyCmd.Flags().StringVar(&proxyAddress, "address", "127.0.0.1", "Local address the endpoint listens on")
+	proxyCmd.Flags().IntVarP(&proxyPort, "port", "p", 8000, "Local port the endpoint listens on")
+	proxyCmd.Flags().StringVar(&proxyRouterNamespace, "router-namespace", "kthena-system", "Namespace of kthena-router")
+	proxyCmd.Flags().StringVarP(&proxyNamespace, "namespace", "n", "", "Kubernetes namespace of the pod selected with --pod (default: current context namespace)")
+	proxyCmd.Flags().StringVar(&proxyPod, "pod", "", "Forward to this pod instead of kthena-router, e.g. an inference engine")
+	proxyCmd.Flags().IntVar(&proxyTargetPort, "target-port", 8000, "Port of the pod selected with --pod")
+	proxyCmd.Flags().StringVar(&proxyToken, "token", "", "Bearer token sent in the requests (default: $"+proxyTokenEnv+")")
+}
+
+func runProxy(cmd *cobra.Command, args []string) error {
+	model := args[0]
+	config, err := getRestConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := getKubeClient()
+	if err != nil {
+		return err
+	}
+	token := proxyToken
+	if token == "" {
+		token = os.Getenv(proxyTokenEnv)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	forwarder := &podForwarder{config: config, client: kubeClient}
+	if proxyPod != "" {
+		namespace := proxyNamespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		forwarder.pick = func(ctx context.Context) (*corev1.Pod, int, error) {
+			pod, err := kubeClient.CoreV1().Pods(namespace).Get(ctx, proxyPod, metav1.GetOptions{})
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to get pod '%s': %v", proxyPod, err)
+			}
+			return pod, proxyTargetPort, nil
+		}
+	} else {
+		forwarder.pick = func(ctx context.Context) (*corev1.Pod, int, error) {
+			return pickRouterPod(ctx, kubeClient, proxyRouterNamespace)
+		}
+	}
+	if err := forwarder.start(ctx); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(proxyAddress, strconv.Itoa(proxyPort)))
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s:%d: %v", proxyAddress, proxyPort, err)
+	}
+	server := &http.Server{Handler: newModelProxy(forwarder, model, token)}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving model %q on http://%s/v1\n", model, listener.Addr())
+	fmt.Printf("  export OPENAI_BASE_URL=http://%s/v1\n", listener.Addr())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// pickRouterPod returns a ready kthena-router pod and its HTTP port.
+func pickRouterPod(ctx context.Context, client kubernetes.Interface, namespace string) (*corev1.Pod, int, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: routerSelector})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list kthena-router pods: %v", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || !podReady(pod) {
+			continue
+		}
+		port := routerDefaultPort
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == "http" {
+					port = int(containerPort.ContainerPort)
+				}
+			}
+		}
+		return pod, port, nil
+	}
+	return nil, 0, fmt.Errorf("no ready kthena-router pod found in namespace %s", namespace)
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podForwarder forwards a local port to a pod through the API server. When the forwarding stops, e.g. because
+// the pod was deleted, another pod is picked and forwarded to another local port.
+type podForwarder struct {
+	config *rest.Config
+	client kubernetes.Interface
+	pick   func(ctx context.Context) (*corev1.Pod, int, error)
+	// localPort is the local port currently forwarded to a pod, 0 while none is.
+	localPort atomic.Int32
+}
+
+// start forwards to a first pod, and keeps forwarding to a pod in the background until the context is done.
+func (f *podForwarder) start(ctx context.Context) error {
+	done, err := f.forward(ctx)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+			}
+			f.localPort.Store(0)
+			if ctx.Err() != nil {
+				return
+			}
+			for {
+				if done, err = f.forward(ctx); err == nil {
+					break
+				}
+				fmt.Fprintf(os.Stderr, "%v, retrying\n", err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(proxyRetryInterval):
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// forward picks a pod and forwards a local port to it. The returned channel is closed when the forwarding stops.
+func (f *podForwarder) forward(ctx context.Context) (<-chan struct{}, error) {
+	pod, port, err := f.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(f.config)
+	if err != nil {
+		return nil, err
+	}
+	url := f.client.CoreV1().RESTClient().Post().Resource("pods").Namespace(pod.Namespace).Name(pod.Name).SubResource("portforward").URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	stop, ready, done := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", port)}, stop, ready, io.Discard, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	errs := make(chan error, 1)
+	go func() {
+		defer close(done)
+		errs <- forwarder.ForwardPorts()
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			close(stop)
+		case <-done:
+		}
+	}()
+
+	select {
+	case <-ready:
+	case err := <-errs:
+		return nil, fmt.Errorf("failed to forward to pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		close(stop)
+		return nil, fmt.Errorf("failed to forward to pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+	f.localPort.Store(int32(ports[0].Local))
+	fmt.Fprintf(os.Stderr, "Forwarding to pod %s/%s port %d\n", pod.Namespace, pod.Name, port)
+	return done, nil
+}
+
+// newModelProxy proxies the requests to the forwarded port, with the model set in the requests which do not name one
+// and the token in the requests without an Authorization header.
+func newModelProxy(forwarder *podForwarder, model, token string) http.Handler {
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.Out.URL.Scheme = "http"
+			r.Out.URL.Host = fmt.Sprintf("127.0.0.1:%d", forwarder.localPort.Load())
+			if token != "" && r.Out.Header.Get("Authorization") == "" {
+				r.Out.Header.Set("Authorization", "Bearer "+token)
+			}
+		},
+		// The streamed responses are flushed as they come
+		FlushInterval: -1,
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if forwarder.localPort.Load() == 0 {
+			http.Error(w, "not forwarded to any pod yet, retry later", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			body = setDefaultModel(body, model)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+		}
+		proxy.ServeHTTP(w, r)
+	})
+}
+
+// setDefaultModel sets the model in a JSON request body which does not name one. Other bodies are left untouched.
+func setDefaultModel(body []byte, model string) []byte {
+	var request map[string]interface{}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return body
+	}
+	if name, _ := request["model"].(string); name != "" {
+		return body
+	}
+	request["model"] = model
+	updated, err := json.Marshal(request)
+	if err != nil {
+		return body
+	}
+	return updated
+}
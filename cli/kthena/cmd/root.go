@@ -30,16 +30,22 @@ var rootCmd = &cobra.Command{
 
 It allows you to:
 - Create manifests from predefined templates with custom values
+- Generate the manifests serving a model on the hardware of a profile
 - List and view Kthena resources in Kubernetes clusters
 - Manage inference workloads, models, and autoscaling policies
+- Print the merged logs of all the pods of a workload
+- Serve a cluster model on a local OpenAI-compatible endpoint
 
 Examples:
   kthena get templates
   kthena describe template DeepSeek-R1-Distill-Qwen-32B
   kthena get template DeepSeek-R1-Distill-Qwen-32B -o yaml
   kthena create manifest --name my-model --template DeepSeek-R1-Distill-Qwen-32B
+  kthena generate --profile a100-tp2 --model meta-llama/Llama-3-70B
   kthena get model-boosters
-  kthena get model-servings --all-namespaces`,
+  kthena get model-servings --all-namespaces
+  kthena logs model-serving/my-serving --role decode --follow
+  kthena proxy deepseek-r1`,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -0,0 +1,139 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/snapshot"
+)
+
+var (
+	routerNamespace       string
+	routerRollbackVersion int64
+)
+
+// routerCmd represents the router command
+var routerCmd = &cobra.Command{
+	Use:   "router",
+	Short: "Manage the routing configuration of kthena-router",
+	Long: `Manage the routing configuration of kthena-router.
+
+Every accepted ModelRoute change is persisted by the router as a versioned snapshot,
+and a ModelRoute can be restored from any of them.
+
+Examples:
+  kthena router history my-route
+  kthena router rollback my-route --to 3 -n production`,
+}
+
+// routerHistoryCmd represents the router history command
+var routerHistoryCmd = &cobra.Command{
+	Use:   "history [MODELROUTE]",
+	Short: "List the snapshots of a ModelRoute",
+	Long:  `List the snapshots of a ModelRoute, from the oldest to the latest version.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRouterHistory,
+}
+
+// routerRollbackCmd represents the router rollback command
+var routerRollbackCmd = &cobra.Command{
+	Use:   "rollback [MODELROUTE]",
+	Short: "Roll back a ModelRoute to a previous snapshot",
+	Long: `Roll back a ModelRoute to a previous snapshot.
+
+The whole spec of the ModelRoute is replaced in a single update. The rollback is
+itself recorded as a new snapshot, so it can be undone the same way.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRouterRollback,
+}
+
+func init() {
+	rootCmd.AddCommand(routerCmd)
+	routerCmd.AddCommand(routerHistoryCmd)
+	routerCmd.AddCommand(routerRollbackCmd)
+
+	routerCmd.PersistentFlags().StringVarP(&routerNamespace, "namespace", "n", "", "Kubernetes namespace (default: current context namespace)")
+	routerRollbackCmd.Flags().Int64Var(&routerRollbackVersion, "to", 0, "Snapshot version to roll back to")
+	_ = routerRollbackCmd.MarkFlagRequired("to")
+}
+
+func getSnapshotManager() (*snapshot.Manager, error) {
+	kubeClient, err := getKubeClient()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := getKthenaClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot.NewManager(kubeClient, client, 0), nil
+}
+
+func resolveRouterNamespace() string {
+	if routerNamespace != "" {
+		return routerNamespace
+	}
+	return "default"
+}
+
+func runRouterHistory(cmd *cobra.Command, args []string) error {
+	manager, err := getSnapshotManager()
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := manager.List(context.Background(), resolveRouterNamespace(), args[0])
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		fmt.Printf("No snapshots found for ModelRoute '%s'.\n", args[0])
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tMODEL\tRULES\tAGE")
+	for _, s := range snapshots {
+		age := time.Since(s.CreationTimestamp.Time).Truncate(time.Second)
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", s.Version, s.Spec.ModelName, len(s.Spec.Rules), age)
+	}
+	return w.Flush()
+}
+
+func runRouterRollback(cmd *cobra.Command, args []string) error {
+	manager, err := getSnapshotManager()
+	if err != nil {
+		return err
+	}
+
+	mr, err := manager.Rollback(context.Background(), resolveRouterNamespace(), args[0], routerRollbackVersion)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("ModelRoute '%s' rolled back to snapshot %d (generation %d).\n", mr.Name, routerRollbackVersion, mr.Generation)
+	return nil
+}
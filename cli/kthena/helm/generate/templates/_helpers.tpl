@@ -0,0 +1,36 @@
+{{/* Name of the generated resources, the model name by default. */}}
+{{- define "kthena.name" -}}
+{{- default (.Values.model | base | lower | replace "." "-" | replace "_" "-") .Values.name | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{/* Name the model is requested with. */}}
+{{- define "kthena.servedModelName" -}}
+{{- default .Values.model .Values.servedModelName -}}
+{{- end -}}
+
+{{/* Container image of the engine. */}}
+{{- define "kthena.image" -}}
+{{- if .Values.engine.image -}}
+{{- .Values.engine.image -}}
+{{- else if eq .Values.engine.name "SGLang" -}}
+lmsysorg/sglang:latest
+{{- else -}}
+vllm/vllm-openai:v0.10.1
+{{- end -}}
+{{- end -}}
+
+{{/* Engine metric of the requests waiting to be scheduled. */}}
+{{- define "kthena.metricName" -}}
+{{- if .Values.autoscaling.metricName -}}
+{{- .Values.autoscaling.metricName -}}
+{{- else if eq .Values.engine.name "SGLang" -}}
+sglang:num_queue_reqs
+{{- else -}}
+vllm:num_requests_waiting
+{{- end -}}
+{{- end -}}
+
+{{- define "kthena.labels" -}}
+app.kubernetes.io/name: {{ include "kthena.name" . | quote }}
+app.kubernetes.io/managed-by: kthena-cli
+{{- end -}}
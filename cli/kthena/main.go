@@ -25,7 +25,7 @@ import (
 	"github.com/volcano-sh/kthena/cli/kthena/cmd"
 )
 
-//go:embed helm/templates/**/*.yaml
+//go:embed helm/templates/**/*.yaml helm/generate/*.yaml helm/generate/templates/*
 var templatesFS embed.FS
 
 func main() {
@@ -25,10 +25,14 @@ import (
 // ModelRouteSpecApplyConfiguration represents a declarative configuration of the ModelRouteSpec type for use
 // with apply.
 type ModelRouteSpecApplyConfiguration struct {
-	ModelName    *string                      `json:"modelName,omitempty"`
-	LoraAdapters []string                     `json:"loraAdapters,omitempty"`
-	Rules        []*networkingv1alpha1.Rule   `json:"rules,omitempty"`
-	RateLimit    *RateLimitApplyConfiguration `json:"rateLimit,omitempty"`
+	ModelName      *string                             `json:"modelName,omitempty"`
+	LoraAdapters   []string                            `json:"loraAdapters,omitempty"`
+	Rules          []*networkingv1alpha1.Rule          `json:"rules,omitempty"`
+	RateLimit      *RateLimitApplyConfiguration        `json:"rateLimit,omitempty"`
+	TrafficCompare *TrafficCompareApplyConfiguration   `json:"trafficCompare,omitempty"`
+	Guardrails     *GuardrailsApplyConfiguration       `json:"guardrails,omitempty"`
+	Concurrency    *ConcurrencyLimitApplyConfiguration `json:"concurrency,omitempty"`
+	Fallback       *FallbackChainApplyConfiguration    `json:"fallback,omitempty"`
 }
 
 // ModelRouteSpecApplyConfiguration constructs a declarative configuration of the ModelRouteSpec type for use with
@@ -75,3 +79,35 @@ func (b *ModelRouteSpecApplyConfiguration) WithRateLimit(value *RateLimitApplyCo
 	b.RateLimit = value
 	return b
 }
+
+// WithTrafficCompare sets the TrafficCompare field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TrafficCompare field is set to the value of the last call.
+func (b *ModelRouteSpecApplyConfiguration) WithTrafficCompare(value *TrafficCompareApplyConfiguration) *ModelRouteSpecApplyConfiguration {
+	b.TrafficCompare = value
+	return b
+}
+
+// WithGuardrails sets the Guardrails field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Guardrails field is set to the value of the last call.
+func (b *ModelRouteSpecApplyConfiguration) WithGuardrails(value *GuardrailsApplyConfiguration) *ModelRouteSpecApplyConfiguration {
+	b.Guardrails = value
+	return b
+}
+
+// WithConcurrency sets the Concurrency field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Concurrency field is set to the value of the last call.
+func (b *ModelRouteSpecApplyConfiguration) WithConcurrency(value *ConcurrencyLimitApplyConfiguration) *ModelRouteSpecApplyConfiguration {
+	b.Concurrency = value
+	return b
+}
+
+// WithFallback sets the Fallback field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Fallback field is set to the value of the last call.
+func (b *ModelRouteSpecApplyConfiguration) WithFallback(value *FallbackChainApplyConfiguration) *ModelRouteSpecApplyConfiguration {
+	b.Fallback = value
+	return b
+}
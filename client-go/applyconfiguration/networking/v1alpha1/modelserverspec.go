@@ -25,12 +25,14 @@ import (
 // ModelServerSpecApplyConfiguration represents a declarative configuration of the ModelServerSpec type for use
 // with apply.
 type ModelServerSpecApplyConfiguration struct {
-	Model            *string                             `json:"model,omitempty"`
-	InferenceEngine  *networkingv1alpha1.InferenceEngine `json:"inferenceEngine,omitempty"`
-	WorkloadSelector *WorkloadSelectorApplyConfiguration `json:"workloadSelector,omitempty"`
-	WorkloadPort     *WorkloadPortApplyConfiguration     `json:"workloadPort,omitempty"`
-	TrafficPolicy    *TrafficPolicyApplyConfiguration    `json:"trafficPolicy,omitempty"`
-	KVConnector      *KVConnectorSpecApplyConfiguration  `json:"kvConnector,omitempty"`
+	Model            *string                                  `json:"model,omitempty"`
+	InferenceEngine  *networkingv1alpha1.InferenceEngine      `json:"inferenceEngine,omitempty"`
+	WorkloadSelector *WorkloadSelectorApplyConfiguration      `json:"workloadSelector,omitempty"`
+	WorkloadPort     *WorkloadPortApplyConfiguration          `json:"workloadPort,omitempty"`
+	TrafficPolicy    *TrafficPolicyApplyConfiguration         `json:"trafficPolicy,omitempty"`
+	KVConnector      *KVConnectorSpecApplyConfiguration       `json:"kvConnector,omitempty"`
+	SchedulingPolicy *SchedulingPolicyApplyConfiguration      `json:"schedulingPolicy,omitempty"`
+	SLO              *ServiceLevelObjectiveApplyConfiguration `json:"slo,omitempty"`
 }
 
 // ModelServerSpecApplyConfiguration constructs a declarative configuration of the ModelServerSpec type for use with
@@ -86,3 +88,19 @@ func (b *ModelServerSpecApplyConfiguration) WithKVConnector(value *KVConnectorSp
 	b.KVConnector = value
 	return b
 }
+
+// WithSchedulingPolicy sets the SchedulingPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SchedulingPolicy field is set to the value of the last call.
+func (b *ModelServerSpecApplyConfiguration) WithSchedulingPolicy(value *SchedulingPolicyApplyConfiguration) *ModelServerSpecApplyConfiguration {
+	b.SchedulingPolicy = value
+	return b
+}
+
+// WithSLO sets the SLO field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SLO field is set to the value of the last call.
+func (b *ModelServerSpecApplyConfiguration) WithSLO(value *ServiceLevelObjectiveApplyConfiguration) *ModelServerSpecApplyConfiguration {
+	b.SLO = value
+	return b
+}
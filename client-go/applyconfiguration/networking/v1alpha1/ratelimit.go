@@ -25,10 +25,11 @@ import (
 // RateLimitApplyConfiguration represents a declarative configuration of the RateLimit type for use
 // with apply.
 type RateLimitApplyConfiguration struct {
-	InputTokensPerUnit  *uint32                            `json:"inputTokensPerUnit,omitempty"`
-	OutputTokensPerUnit *uint32                            `json:"outputTokensPerUnit,omitempty"`
-	Unit                *networkingv1alpha1.RateLimitUnit  `json:"unit,omitempty"`
-	Global              *GlobalRateLimitApplyConfiguration `json:"global,omitempty"`
+	InputTokensPerUnit   *uint32                            `json:"inputTokensPerUnit,omitempty"`
+	OutputTokensPerUnit  *uint32                            `json:"outputTokensPerUnit,omitempty"`
+	Unit                 *networkingv1alpha1.RateLimitUnit  `json:"unit,omitempty"`
+	MaxConcurrentStreams *uint32                            `json:"maxConcurrentStreams,omitempty"`
+	Global               *GlobalRateLimitApplyConfiguration `json:"global,omitempty"`
 }
 
 // RateLimitApplyConfiguration constructs a declarative configuration of the RateLimit type for use with
@@ -61,6 +62,14 @@ func (b *RateLimitApplyConfiguration) WithUnit(value networkingv1alpha1.RateLimi
 	return b
 }
 
+// WithMaxConcurrentStreams sets the MaxConcurrentStreams field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxConcurrentStreams field is set to the value of the last call.
+func (b *RateLimitApplyConfiguration) WithMaxConcurrentStreams(value uint32) *RateLimitApplyConfiguration {
+	b.MaxConcurrentStreams = &value
+	return b
+}
+
 // WithGlobal sets the Global field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Global field is set to the value of the last call.
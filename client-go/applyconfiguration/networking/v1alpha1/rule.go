@@ -28,6 +28,7 @@ type RuleApplyConfiguration struct {
 	Name         *string                           `json:"name,omitempty"`
 	ModelMatch   *ModelMatchApplyConfiguration     `json:"modelMatch,omitempty"`
 	TargetModels []*networkingv1alpha1.TargetModel `json:"targetModels,omitempty"`
+	Mirror       *TrafficMirrorApplyConfiguration  `json:"mirror,omitempty"`
 }
 
 // RuleApplyConfiguration constructs a declarative configuration of the Rule type for use with
@@ -64,3 +65,11 @@ func (b *RuleApplyConfiguration) WithTargetModels(values ...**networkingv1alpha1
 	}
 	return b
 }
+
+// WithMirror sets the Mirror field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Mirror field is set to the value of the last call.
+func (b *RuleApplyConfiguration) WithMirror(value *TrafficMirrorApplyConfiguration) *RuleApplyConfiguration {
+	b.Mirror = value
+	return b
+}
@@ -25,8 +25,9 @@ import (
 // TrafficPolicyApplyConfiguration represents a declarative configuration of the TrafficPolicy type for use
 // with apply.
 type TrafficPolicyApplyConfiguration struct {
-	Timeout *v1.Duration             `json:"timeout,omitempty"`
-	Retry   *RetryApplyConfiguration `json:"retry,omitempty"`
+	Timeout *v1.Duration               `json:"timeout,omitempty"`
+	Retry   *RetryApplyConfiguration   `json:"retry,omitempty"`
+	Hedging *HedgingApplyConfiguration `json:"hedging,omitempty"`
 }
 
 // TrafficPolicyApplyConfiguration constructs a declarative configuration of the TrafficPolicy type for use with
@@ -50,3 +51,11 @@ func (b *TrafficPolicyApplyConfiguration) WithRetry(value *RetryApplyConfigurati
 	b.Retry = value
 	return b
 }
+
+// WithHedging sets the Hedging field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Hedging field is set to the value of the last call.
+func (b *TrafficPolicyApplyConfiguration) WithHedging(value *HedgingApplyConfiguration) *TrafficPolicyApplyConfiguration {
+	b.Hedging = value
+	return b
+}
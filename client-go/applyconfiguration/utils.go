@@ -22,8 +22,10 @@ import (
 	internal "github.com/volcano-sh/kthena/client-go/applyconfiguration/internal"
 	networkingv1alpha1 "github.com/volcano-sh/kthena/client-go/applyconfiguration/networking/v1alpha1"
 	applyconfigurationworkloadv1alpha1 "github.com/volcano-sh/kthena/client-go/applyconfiguration/workload/v1alpha1"
+	workloadv1beta1 "github.com/volcano-sh/kthena/client-go/applyconfiguration/workload/v1beta1"
 	v1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
 	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	v1beta1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1beta1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	testing "k8s.io/client-go/testing"
@@ -34,12 +36,40 @@ import (
 func ForKind(kind schema.GroupVersionKind) interface{} {
 	switch kind {
 	// Group=networking.serving.volcano.sh, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithKind("AcceleratorCost"):
+		return &networkingv1alpha1.AcceleratorCostApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("AcceleratorPolicy"):
+		return &networkingv1alpha1.AcceleratorPolicyApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("AvailabilityObjective"):
+		return &networkingv1alpha1.AvailabilityObjectiveApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("BodyMatch"):
 		return &networkingv1alpha1.BodyMatchApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ConcurrencyLimit"):
+		return &networkingv1alpha1.ConcurrencyLimitApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ConsistentHash"):
+		return &networkingv1alpha1.ConsistentHashApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ExternalModel"):
+		return &networkingv1alpha1.ExternalModelApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("FallbackChain"):
+		return &networkingv1alpha1.FallbackChainApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("FallbackTarget"):
+		return &networkingv1alpha1.FallbackTargetApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("GlobalRateLimit"):
 		return &networkingv1alpha1.GlobalRateLimitApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("GuardrailFilter"):
+		return &networkingv1alpha1.GuardrailFilterApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("Guardrails"):
+		return &networkingv1alpha1.GuardrailsApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("Hedging"):
+		return &networkingv1alpha1.HedgingApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("HTTPGuardrail"):
+		return &networkingv1alpha1.HTTPGuardrailApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("KeywordGuardrail"):
+		return &networkingv1alpha1.KeywordGuardrailApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("KVConnectorSpec"):
 		return &networkingv1alpha1.KVConnectorSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("LatencyObjective"):
+		return &networkingv1alpha1.LatencyObjectiveApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("ModelMatch"):
 		return &networkingv1alpha1.ModelMatchApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("ModelRoute"):
@@ -50,20 +80,34 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &networkingv1alpha1.ModelServerApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("ModelServerSpec"):
 		return &networkingv1alpha1.ModelServerSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ModelServerStatus"):
+		return &networkingv1alpha1.ModelServerStatusApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("PDGroup"):
 		return &networkingv1alpha1.PDGroupApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("RateLimit"):
 		return &networkingv1alpha1.RateLimitApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("RedisConfig"):
 		return &networkingv1alpha1.RedisConfigApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("RegexGuardrail"):
+		return &networkingv1alpha1.RegexGuardrailApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("Retry"):
 		return &networkingv1alpha1.RetryApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("Rule"):
 		return &networkingv1alpha1.RuleApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("SchedulingPolicy"):
+		return &networkingv1alpha1.SchedulingPolicyApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ScoreWeight"):
+		return &networkingv1alpha1.ScoreWeightApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ServiceLevelObjective"):
+		return &networkingv1alpha1.ServiceLevelObjectiveApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("StringMatch"):
 		return &networkingv1alpha1.StringMatchApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("TargetModel"):
 		return &networkingv1alpha1.TargetModelApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("TrafficCompare"):
+		return &networkingv1alpha1.TrafficCompareApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("TrafficMirror"):
+		return &networkingv1alpha1.TrafficMirrorApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("TrafficPolicy"):
 		return &networkingv1alpha1.TrafficPolicyApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("WorkloadPort"):
@@ -80,20 +124,42 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationworkloadv1alpha1.AutoscalingPolicyBindingApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("AutoscalingPolicyBindingSpec"):
 		return &applyconfigurationworkloadv1alpha1.AutoscalingPolicyBindingSpecApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("AutoscalingPolicyBindingStatus"):
+		return &applyconfigurationworkloadv1alpha1.AutoscalingPolicyBindingStatusApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("AutoscalingPolicyMetric"):
 		return &applyconfigurationworkloadv1alpha1.AutoscalingPolicyMetricApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("AutoscalingPolicyPanicPolicy"):
 		return &applyconfigurationworkloadv1alpha1.AutoscalingPolicyPanicPolicyApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("AutoscalingPolicyPredictive"):
+		return &applyconfigurationworkloadv1alpha1.AutoscalingPolicyPredictiveApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("AutoscalingPolicyScaleUpPolicy"):
 		return &applyconfigurationworkloadv1alpha1.AutoscalingPolicyScaleUpPolicyApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("AutoscalingPolicySpec"):
 		return &applyconfigurationworkloadv1alpha1.AutoscalingPolicySpecApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("AutoscalingPolicyStablePolicy"):
 		return &applyconfigurationworkloadv1alpha1.AutoscalingPolicyStablePolicyApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("AutoscalingPolicyVerticalRecommendation"):
+		return &applyconfigurationworkloadv1alpha1.AutoscalingPolicyVerticalRecommendationApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("BackendLoraAdapter"):
+		return &applyconfigurationworkloadv1alpha1.BackendLoraAdapterApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("BatchInference"):
+		return &applyconfigurationworkloadv1alpha1.BatchInferenceApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("BatchInferenceSpec"):
+		return &applyconfigurationworkloadv1alpha1.BatchInferenceSpecApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("BatchInferenceStatus"):
+		return &applyconfigurationworkloadv1alpha1.BatchInferenceStatusApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("BatchRequestCounts"):
+		return &applyconfigurationworkloadv1alpha1.BatchRequestCountsApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("GangPolicy"):
 		return &applyconfigurationworkloadv1alpha1.GangPolicyApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("GPUSharing"):
+		return &applyconfigurationworkloadv1alpha1.GPUSharingApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("LoraAdapter"):
 		return &applyconfigurationworkloadv1alpha1.LoraAdapterApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("LoraAdapterSpec"):
+		return &applyconfigurationworkloadv1alpha1.LoraAdapterSpecApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("LoraAdapterStatus"):
+		return &applyconfigurationworkloadv1alpha1.LoraAdapterStatusApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("Metadata"):
 		return &applyconfigurationworkloadv1alpha1.MetadataApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("MetricEndpoint"):
@@ -106,6 +172,12 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationworkloadv1alpha1.ModelBoosterApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("ModelBoosterSpec"):
 		return &applyconfigurationworkloadv1alpha1.ModelBoosterSpecApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("ModelCard"):
+		return &applyconfigurationworkloadv1alpha1.ModelCardApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("ModelDownloadPolicy"):
+		return &applyconfigurationworkloadv1alpha1.ModelDownloadPolicyApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("ModelDownloadStatus"):
+		return &applyconfigurationworkloadv1alpha1.ModelDownloadStatusApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("ModelServing"):
 		return &applyconfigurationworkloadv1alpha1.ModelServingApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("ModelServingSpec"):
@@ -122,21 +194,77 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationworkloadv1alpha1.OptimizerParamApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("PodTemplateSpec"):
 		return &applyconfigurationworkloadv1alpha1.PodTemplateSpecApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("RecoveryBackoff"):
+		return &applyconfigurationworkloadv1alpha1.RecoveryBackoffApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("ResourceRecommendation"):
+		return &applyconfigurationworkloadv1alpha1.ResourceRecommendationApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("Role"):
 		return &applyconfigurationworkloadv1alpha1.RoleApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("RolePlacement"):
+		return &applyconfigurationworkloadv1alpha1.RolePlacementApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("RoleReplicaStatus"):
+		return &applyconfigurationworkloadv1alpha1.RoleReplicaStatusApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("RoleTopologySpread"):
+		return &applyconfigurationworkloadv1alpha1.RoleTopologySpreadApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("RollingUpdateConfiguration"):
 		return &applyconfigurationworkloadv1alpha1.RollingUpdateConfigurationApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("RolloutStrategy"):
 		return &applyconfigurationworkloadv1alpha1.RolloutStrategyApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("ScalingConfiguration"):
 		return &applyconfigurationworkloadv1alpha1.ScalingConfigurationApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("ScalingDecision"):
+		return &applyconfigurationworkloadv1alpha1.ScalingDecisionApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("ServingGroup"):
 		return &applyconfigurationworkloadv1alpha1.ServingGroupApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("ServingGroupStatus"):
+		return &applyconfigurationworkloadv1alpha1.ServingGroupStatusApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("Target"):
 		return &applyconfigurationworkloadv1alpha1.TargetApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("TenancyPolicy"):
+		return &applyconfigurationworkloadv1alpha1.TenancyPolicyApplyConfiguration{}
+	case workloadv1alpha1.SchemeGroupVersion.WithKind("TenancyPolicySpec"):
+		return &applyconfigurationworkloadv1alpha1.TenancyPolicySpecApplyConfiguration{}
 	case workloadv1alpha1.SchemeGroupVersion.WithKind("TopologySpreadConstraint"):
 		return &applyconfigurationworkloadv1alpha1.TopologySpreadConstraintApplyConfiguration{}
 
+		// Group=workload.serving.volcano.sh, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithKind("GangPolicy"):
+		return &workloadv1beta1.GangPolicyApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("GPUSharing"):
+		return &workloadv1beta1.GPUSharingApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("Metadata"):
+		return &workloadv1beta1.MetadataApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ModelServing"):
+		return &workloadv1beta1.ModelServingApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ModelServingSpec"):
+		return &workloadv1beta1.ModelServingSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ModelServingStatus"):
+		return &workloadv1beta1.ModelServingStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("PodTemplateSpec"):
+		return &workloadv1beta1.PodTemplateSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RecoveryBackoff"):
+		return &workloadv1beta1.RecoveryBackoffApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ResourceRecommendation"):
+		return &workloadv1beta1.ResourceRecommendationApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("Role"):
+		return &workloadv1beta1.RoleApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RolePlacement"):
+		return &workloadv1beta1.RolePlacementApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RoleReplicaStatus"):
+		return &workloadv1beta1.RoleReplicaStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RoleTopologySpread"):
+		return &workloadv1beta1.RoleTopologySpreadApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RollingUpdateConfiguration"):
+		return &workloadv1beta1.RollingUpdateConfigurationApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RolloutStrategy"):
+		return &workloadv1beta1.RolloutStrategyApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ServingGroup"):
+		return &workloadv1beta1.ServingGroupApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ServingGroupStatus"):
+		return &workloadv1beta1.ServingGroupStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("TopologySpreadConstraint"):
+		return &workloadv1beta1.TopologySpreadConstraintApplyConfiguration{}
+
 	}
 	return nil
 }
@@ -21,9 +21,12 @@ package v1alpha1
 // AutoscalingPolicySpecApplyConfiguration represents a declarative configuration of the AutoscalingPolicySpec type for use
 // with apply.
 type AutoscalingPolicySpecApplyConfiguration struct {
-	TolerancePercent *int32                                       `json:"tolerancePercent,omitempty"`
-	Metrics          []AutoscalingPolicyMetricApplyConfiguration  `json:"metrics,omitempty"`
-	Behavior         *AutoscalingPolicyBehaviorApplyConfiguration `json:"behavior,omitempty"`
+	TolerancePercent       *int32                                                     `json:"tolerancePercent,omitempty"`
+	Metrics                []AutoscalingPolicyMetricApplyConfiguration                `json:"metrics,omitempty"`
+	Behavior               *AutoscalingPolicyBehaviorApplyConfiguration               `json:"behavior,omitempty"`
+	Predictive             *AutoscalingPolicyPredictiveApplyConfiguration             `json:"predictive,omitempty"`
+	VerticalRecommendation *AutoscalingPolicyVerticalRecommendationApplyConfiguration `json:"verticalRecommendation,omitempty"`
+	DryRun                 *bool                                                      `json:"dryRun,omitempty"`
 }
 
 // AutoscalingPolicySpecApplyConfiguration constructs a declarative configuration of the AutoscalingPolicySpec type for use with
@@ -60,3 +63,27 @@ func (b *AutoscalingPolicySpecApplyConfiguration) WithBehavior(value *Autoscalin
 	b.Behavior = value
 	return b
 }
+
+// WithPredictive sets the Predictive field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Predictive field is set to the value of the last call.
+func (b *AutoscalingPolicySpecApplyConfiguration) WithPredictive(value *AutoscalingPolicyPredictiveApplyConfiguration) *AutoscalingPolicySpecApplyConfiguration {
+	b.Predictive = value
+	return b
+}
+
+// WithVerticalRecommendation sets the VerticalRecommendation field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the VerticalRecommendation field is set to the value of the last call.
+func (b *AutoscalingPolicySpecApplyConfiguration) WithVerticalRecommendation(value *AutoscalingPolicyVerticalRecommendationApplyConfiguration) *AutoscalingPolicySpecApplyConfiguration {
+	b.VerticalRecommendation = value
+	return b
+}
+
+// WithDryRun sets the DryRun field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DryRun field is set to the value of the last call.
+func (b *AutoscalingPolicySpecApplyConfiguration) WithDryRun(value bool) *AutoscalingPolicySpecApplyConfiguration {
+	b.DryRun = &value
+	return b
+}
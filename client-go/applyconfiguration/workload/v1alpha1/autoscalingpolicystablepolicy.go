@@ -31,6 +31,7 @@ type AutoscalingPolicyStablePolicyApplyConfiguration struct {
 	Period              *v1.Duration                       `json:"period,omitempty"`
 	SelectPolicy        *workloadv1alpha1.SelectPolicyType `json:"selectPolicy,omitempty"`
 	StabilizationWindow *v1.Duration                       `json:"stabilizationWindow,omitempty"`
+	TolerancePercent    *int32                             `json:"tolerancePercent,omitempty"`
 }
 
 // AutoscalingPolicyStablePolicyApplyConfiguration constructs a declarative configuration of the AutoscalingPolicyStablePolicy type for use with
@@ -78,3 +79,11 @@ func (b *AutoscalingPolicyStablePolicyApplyConfiguration) WithStabilizationWindo
 	b.StabilizationWindow = &value
 	return b
 }
+
+// WithTolerancePercent sets the TolerancePercent field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TolerancePercent field is set to the value of the last call.
+func (b *AutoscalingPolicyStablePolicyApplyConfiguration) WithTolerancePercent(value int32) *AutoscalingPolicyStablePolicyApplyConfiguration {
+	b.TolerancePercent = &value
+	return b
+}
@@ -31,6 +31,7 @@ type ModelBackendApplyConfiguration struct {
 	Type                   *workloadv1alpha1.ModelBackendType       `json:"type,omitempty"`
 	ModelURI               *string                                  `json:"modelURI,omitempty"`
 	CacheURI               *string                                  `json:"cacheURI,omitempty"`
+	Download               *ModelDownloadPolicyApplyConfiguration   `json:"download,omitempty"`
 	EnvFrom                []v1.EnvFromSource                       `json:"envFrom,omitempty"`
 	Env                    []v1.EnvVar                              `json:"env,omitempty"`
 	MinReplicas            *int32                                   `json:"minReplicas,omitempty"`
@@ -39,7 +40,7 @@ type ModelBackendApplyConfiguration struct {
 	RouteWeight            *uint32                                  `json:"routeWeight,omitempty"`
 	ScaleToZeroGracePeriod *metav1.Duration                         `json:"scaleToZeroGracePeriod,omitempty"`
 	Workers                []ModelWorkerApplyConfiguration          `json:"workers,omitempty"`
-	LoraAdapters           []LoraAdapterApplyConfiguration          `json:"loraAdapters,omitempty"`
+	LoraAdapters           []BackendLoraAdapterApplyConfiguration   `json:"loraAdapters,omitempty"`
 	AutoscalingPolicy      *AutoscalingPolicySpecApplyConfiguration `json:"autoscalingPolicy,omitempty"`
 	SchedulerName          *string                                  `json:"schedulerName,omitempty"`
 }
@@ -82,6 +83,14 @@ func (b *ModelBackendApplyConfiguration) WithCacheURI(value string) *ModelBacken
 	return b
 }
 
+// WithDownload sets the Download field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Download field is set to the value of the last call.
+func (b *ModelBackendApplyConfiguration) WithDownload(value *ModelDownloadPolicyApplyConfiguration) *ModelBackendApplyConfiguration {
+	b.Download = value
+	return b
+}
+
 // WithEnvFrom adds the given value to the EnvFrom field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the EnvFrom field.
@@ -158,7 +167,7 @@ func (b *ModelBackendApplyConfiguration) WithWorkers(values ...*ModelWorkerApply
 // WithLoraAdapters adds the given value to the LoraAdapters field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the LoraAdapters field.
-func (b *ModelBackendApplyConfiguration) WithLoraAdapters(values ...*LoraAdapterApplyConfiguration) *ModelBackendApplyConfiguration {
+func (b *ModelBackendApplyConfiguration) WithLoraAdapters(values ...*BackendLoraAdapterApplyConfiguration) *ModelBackendApplyConfiguration {
 	for i := range values {
 		if values[i] == nil {
 			panic("nil value passed to WithLoraAdapters")
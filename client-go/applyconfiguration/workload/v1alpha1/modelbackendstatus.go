@@ -21,8 +21,9 @@ package v1alpha1
 // ModelBackendStatusApplyConfiguration represents a declarative configuration of the ModelBackendStatus type for use
 // with apply.
 type ModelBackendStatusApplyConfiguration struct {
-	Name     *string `json:"name,omitempty"`
-	Replicas *int32  `json:"replicas,omitempty"`
+	Name     *string                                `json:"name,omitempty"`
+	Replicas *int32                                 `json:"replicas,omitempty"`
+	Download *ModelDownloadStatusApplyConfiguration `json:"download,omitempty"`
 }
 
 // ModelBackendStatusApplyConfiguration constructs a declarative configuration of the ModelBackendStatus type for use with
@@ -46,3 +47,11 @@ func (b *ModelBackendStatusApplyConfiguration) WithReplicas(value int32) *ModelB
 	b.Replicas = &value
 	return b
 }
+
+// WithDownload sets the Download field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Download field is set to the value of the last call.
+func (b *ModelBackendStatusApplyConfiguration) WithDownload(value *ModelDownloadStatusApplyConfiguration) *ModelBackendStatusApplyConfiguration {
+	b.Download = value
+	return b
+}
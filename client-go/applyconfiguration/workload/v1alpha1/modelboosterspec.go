@@ -31,6 +31,7 @@ type ModelBoosterSpecApplyConfiguration struct {
 	AutoscalingPolicy        *AutoscalingPolicySpecApplyConfiguration         `json:"autoscalingPolicy,omitempty"`
 	CostExpansionRatePercent *int32                                           `json:"costExpansionRatePercent,omitempty"`
 	ModelMatch               *networkingv1alpha1.ModelMatchApplyConfiguration `json:"modelMatch,omitempty"`
+	Card                     *ModelCardApplyConfiguration                     `json:"card,omitempty"`
 }
 
 // ModelBoosterSpecApplyConfiguration constructs a declarative configuration of the ModelBoosterSpec type for use with
@@ -91,3 +92,11 @@ func (b *ModelBoosterSpecApplyConfiguration) WithModelMatch(value *networkingv1a
 	b.ModelMatch = value
 	return b
 }
+
+// WithCard sets the Card field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Card field is set to the value of the last call.
+func (b *ModelBoosterSpecApplyConfiguration) WithCard(value *ModelCardApplyConfiguration) *ModelBoosterSpecApplyConfiguration {
+	b.Card = value
+	return b
+}
@@ -26,10 +26,15 @@ import (
 // with apply.
 type ModelServingSpecApplyConfiguration struct {
 	Replicas                  *int32                                       `json:"replicas,omitempty"`
+	StandbyReplicas           *int32                                       `json:"standbyReplicas,omitempty"`
 	SchedulerName             *string                                      `json:"schedulerName,omitempty"`
 	Template                  *ServingGroupApplyConfiguration              `json:"template,omitempty"`
 	RolloutStrategy           *RolloutStrategyApplyConfiguration           `json:"rolloutStrategy,omitempty"`
 	RecoveryPolicy            *workloadv1alpha1.RecoveryPolicy             `json:"recoveryPolicy,omitempty"`
+	RecoveryBackoff           *RecoveryBackoffApplyConfiguration           `json:"recoveryBackoff,omitempty"`
+	DisruptionPolicy          *workloadv1alpha1.DisruptionPolicy           `json:"disruptionPolicy,omitempty"`
+	Paused                    *bool                                        `json:"paused,omitempty"`
+	Suspend                   *bool                                        `json:"suspend,omitempty"`
 	TopologySpreadConstraints []TopologySpreadConstraintApplyConfiguration `json:"topologySpreadConstraints,omitempty"`
 }
 
@@ -47,6 +52,14 @@ func (b *ModelServingSpecApplyConfiguration) WithReplicas(value int32) *ModelSer
 	return b
 }
 
+// WithStandbyReplicas sets the StandbyReplicas field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StandbyReplicas field is set to the value of the last call.
+func (b *ModelServingSpecApplyConfiguration) WithStandbyReplicas(value int32) *ModelServingSpecApplyConfiguration {
+	b.StandbyReplicas = &value
+	return b
+}
+
 // WithSchedulerName sets the SchedulerName field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the SchedulerName field is set to the value of the last call.
@@ -79,6 +92,38 @@ func (b *ModelServingSpecApplyConfiguration) WithRecoveryPolicy(value workloadv1
 	return b
 }
 
+// WithRecoveryBackoff sets the RecoveryBackoff field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RecoveryBackoff field is set to the value of the last call.
+func (b *ModelServingSpecApplyConfiguration) WithRecoveryBackoff(value *RecoveryBackoffApplyConfiguration) *ModelServingSpecApplyConfiguration {
+	b.RecoveryBackoff = value
+	return b
+}
+
+// WithDisruptionPolicy sets the DisruptionPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DisruptionPolicy field is set to the value of the last call.
+func (b *ModelServingSpecApplyConfiguration) WithDisruptionPolicy(value workloadv1alpha1.DisruptionPolicy) *ModelServingSpecApplyConfiguration {
+	b.DisruptionPolicy = &value
+	return b
+}
+
+// WithPaused sets the Paused field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Paused field is set to the value of the last call.
+func (b *ModelServingSpecApplyConfiguration) WithPaused(value bool) *ModelServingSpecApplyConfiguration {
+	b.Paused = &value
+	return b
+}
+
+// WithSuspend sets the Suspend field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Suspend field is set to the value of the last call.
+func (b *ModelServingSpecApplyConfiguration) WithSuspend(value bool) *ModelServingSpecApplyConfiguration {
+	b.Suspend = &value
+	return b
+}
+
 // WithTopologySpreadConstraints adds the given value to the TopologySpreadConstraints field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the TopologySpreadConstraints field.
@@ -25,12 +25,17 @@ import (
 // ModelServingStatusApplyConfiguration represents a declarative configuration of the ModelServingStatus type for use
 // with apply.
 type ModelServingStatusApplyConfiguration struct {
-	ObservedGeneration *int64                           `json:"observedGeneration,omitempty"`
-	Replicas           *int32                           `json:"replicas,omitempty"`
-	CurrentReplicas    *int32                           `json:"currentReplicas,omitempty"`
-	UpdatedReplicas    *int32                           `json:"updatedReplicas,omitempty"`
-	AvailableReplicas  *int32                           `json:"availableReplicas,omitempty"`
-	Conditions         []v1.ConditionApplyConfiguration `json:"conditions,omitempty"`
+	ObservedGeneration      *int64                                     `json:"observedGeneration,omitempty"`
+	Replicas                *int32                                     `json:"replicas,omitempty"`
+	CurrentReplicas         *int32                                     `json:"currentReplicas,omitempty"`
+	UpdatedReplicas         *int32                                     `json:"updatedReplicas,omitempty"`
+	AvailableReplicas       *int32                                     `json:"availableReplicas,omitempty"`
+	StandbyReplicas         *int32                                     `json:"standbyReplicas,omitempty"`
+	LoadingPercentage       *int32                                     `json:"loadingPercentage,omitempty"`
+	Conditions              []v1.ConditionApplyConfiguration           `json:"conditions,omitempty"`
+	LabelSelector           *string                                    `json:"labelSelector,omitempty"`
+	ResourceRecommendations []ResourceRecommendationApplyConfiguration `json:"resourceRecommendations,omitempty"`
+	Groups                  []ServingGroupStatusApplyConfiguration     `json:"groups,omitempty"`
 }
 
 // ModelServingStatusApplyConfiguration constructs a declarative configuration of the ModelServingStatus type for use with
@@ -79,6 +84,22 @@ func (b *ModelServingStatusApplyConfiguration) WithAvailableReplicas(value int32
 	return b
 }
 
+// WithStandbyReplicas sets the StandbyReplicas field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StandbyReplicas field is set to the value of the last call.
+func (b *ModelServingStatusApplyConfiguration) WithStandbyReplicas(value int32) *ModelServingStatusApplyConfiguration {
+	b.StandbyReplicas = &value
+	return b
+}
+
+// WithLoadingPercentage sets the LoadingPercentage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LoadingPercentage field is set to the value of the last call.
+func (b *ModelServingStatusApplyConfiguration) WithLoadingPercentage(value int32) *ModelServingStatusApplyConfiguration {
+	b.LoadingPercentage = &value
+	return b
+}
+
 // WithConditions adds the given value to the Conditions field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the Conditions field.
@@ -91,3 +112,37 @@ func (b *ModelServingStatusApplyConfiguration) WithConditions(values ...*v1.Cond
 	}
 	return b
 }
+
+// WithLabelSelector sets the LabelSelector field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LabelSelector field is set to the value of the last call.
+func (b *ModelServingStatusApplyConfiguration) WithLabelSelector(value string) *ModelServingStatusApplyConfiguration {
+	b.LabelSelector = &value
+	return b
+}
+
+// WithResourceRecommendations adds the given value to the ResourceRecommendations field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the ResourceRecommendations field.
+func (b *ModelServingStatusApplyConfiguration) WithResourceRecommendations(values ...*ResourceRecommendationApplyConfiguration) *ModelServingStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithResourceRecommendations")
+		}
+		b.ResourceRecommendations = append(b.ResourceRecommendations, *values[i])
+	}
+	return b
+}
+
+// WithGroups adds the given value to the Groups field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Groups field.
+func (b *ModelServingStatusApplyConfiguration) WithGroups(values ...*ServingGroupStatusApplyConfiguration) *ModelServingStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithGroups")
+		}
+		b.Groups = append(b.Groups, *values[i])
+	}
+	return b
+}
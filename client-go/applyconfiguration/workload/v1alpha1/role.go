@@ -21,11 +21,14 @@ package v1alpha1
 // RoleApplyConfiguration represents a declarative configuration of the Role type for use
 // with apply.
 type RoleApplyConfiguration struct {
-	Name           *string                            `json:"name,omitempty"`
-	Replicas       *int32                             `json:"replicas,omitempty"`
-	EntryTemplate  *PodTemplateSpecApplyConfiguration `json:"entryTemplate,omitempty"`
-	WorkerReplicas *int32                             `json:"workerReplicas,omitempty"`
-	WorkerTemplate *PodTemplateSpecApplyConfiguration `json:"workerTemplate,omitempty"`
+	Name              *string                            `json:"name,omitempty"`
+	Replicas          *int32                             `json:"replicas,omitempty"`
+	EntryTemplate     *PodTemplateSpecApplyConfiguration `json:"entryTemplate,omitempty"`
+	WorkerReplicas    *int32                             `json:"workerReplicas,omitempty"`
+	WorkerTemplate    *PodTemplateSpecApplyConfiguration `json:"workerTemplate,omitempty"`
+	Placement         *RolePlacementApplyConfiguration   `json:"placement,omitempty"`
+	GPUSharing        *GPUSharingApplyConfiguration      `json:"gpuSharing,omitempty"`
+	PriorityClassName *string                            `json:"priorityClassName,omitempty"`
 }
 
 // RoleApplyConfiguration constructs a declarative configuration of the Role type for use with
@@ -73,3 +76,27 @@ func (b *RoleApplyConfiguration) WithWorkerTemplate(value *PodTemplateSpecApplyC
 	b.WorkerTemplate = value
 	return b
 }
+
+// WithPlacement sets the Placement field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Placement field is set to the value of the last call.
+func (b *RoleApplyConfiguration) WithPlacement(value *RolePlacementApplyConfiguration) *RoleApplyConfiguration {
+	b.Placement = value
+	return b
+}
+
+// WithGPUSharing sets the GPUSharing field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the GPUSharing field is set to the value of the last call.
+func (b *RoleApplyConfiguration) WithGPUSharing(value *GPUSharingApplyConfiguration) *RoleApplyConfiguration {
+	b.GPUSharing = value
+	return b
+}
+
+// WithPriorityClassName sets the PriorityClassName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PriorityClassName field is set to the value of the last call.
+func (b *RoleApplyConfiguration) WithPriorityClassName(value string) *RoleApplyConfiguration {
+	b.PriorityClassName = &value
+	return b
+}
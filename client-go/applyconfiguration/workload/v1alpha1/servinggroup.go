@@ -28,6 +28,7 @@ type ServingGroupApplyConfiguration struct {
 	RestartGracePeriodSeconds *int64                        `json:"restartGracePeriodSeconds,omitempty"`
 	GangPolicy                *GangPolicyApplyConfiguration `json:"gangPolicy,omitempty"`
 	NetworkTopology           *v1beta1.NetworkTopologySpec  `json:"networkTopology,omitempty"`
+	PriorityClassName         *string                       `json:"priorityClassName,omitempty"`
 	Roles                     []RoleApplyConfiguration      `json:"roles,omitempty"`
 }
 
@@ -61,6 +62,14 @@ func (b *ServingGroupApplyConfiguration) WithNetworkTopology(value v1beta1.Netwo
 	return b
 }
 
+// WithPriorityClassName sets the PriorityClassName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PriorityClassName field is set to the value of the last call.
+func (b *ServingGroupApplyConfiguration) WithPriorityClassName(value string) *ServingGroupApplyConfiguration {
+	b.PriorityClassName = &value
+	return b
+}
+
 // WithRoles adds the given value to the Roles field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the Roles field.
@@ -26,6 +26,7 @@ import (
 // with apply.
 type TargetApplyConfiguration struct {
 	TargetRef             *v1.ObjectReference               `json:"targetRef,omitempty"`
+	RoleName              *string                           `json:"roleName,omitempty"`
 	AdditionalMatchLabels map[string]string                 `json:"additionalMatchLabels,omitempty"`
 	MetricEndpoint        *MetricEndpointApplyConfiguration `json:"metricEndpoint,omitempty"`
 }
@@ -44,6 +45,14 @@ func (b *TargetApplyConfiguration) WithTargetRef(value v1.ObjectReference) *Targ
 	return b
 }
 
+// WithRoleName sets the RoleName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RoleName field is set to the value of the last call.
+func (b *TargetApplyConfiguration) WithRoleName(value string) *TargetApplyConfiguration {
+	b.RoleName = &value
+	return b
+}
+
 // WithAdditionalMatchLabels puts the entries into the AdditionalMatchLabels field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, the entries provided by each call will be put on the AdditionalMatchLabels field,
@@ -24,6 +24,7 @@ import (
 
 	networkingv1alpha1 "github.com/volcano-sh/kthena/client-go/clientset/versioned/typed/networking/v1alpha1"
 	workloadv1alpha1 "github.com/volcano-sh/kthena/client-go/clientset/versioned/typed/workload/v1alpha1"
+	workloadv1beta1 "github.com/volcano-sh/kthena/client-go/clientset/versioned/typed/workload/v1beta1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -33,6 +34,7 @@ type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	NetworkingV1alpha1() networkingv1alpha1.NetworkingV1alpha1Interface
 	WorkloadV1alpha1() workloadv1alpha1.WorkloadV1alpha1Interface
+	WorkloadV1beta1() workloadv1beta1.WorkloadV1beta1Interface
 }
 
 // Clientset contains the clients for groups.
@@ -40,6 +42,7 @@ type Clientset struct {
 	*discovery.DiscoveryClient
 	networkingV1alpha1 *networkingv1alpha1.NetworkingV1alpha1Client
 	workloadV1alpha1   *workloadv1alpha1.WorkloadV1alpha1Client
+	workloadV1beta1    *workloadv1beta1.WorkloadV1beta1Client
 }
 
 // NetworkingV1alpha1 retrieves the NetworkingV1alpha1Client
@@ -52,6 +55,11 @@ func (c *Clientset) WorkloadV1alpha1() workloadv1alpha1.WorkloadV1alpha1Interfac
 	return c.workloadV1alpha1
 }
 
+// WorkloadV1beta1 retrieves the WorkloadV1beta1Client
+func (c *Clientset) WorkloadV1beta1() workloadv1beta1.WorkloadV1beta1Interface {
+	return c.workloadV1beta1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -104,6 +112,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.workloadV1beta1, err = workloadv1beta1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -127,6 +139,7 @@ func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.networkingV1alpha1 = networkingv1alpha1.New(c)
 	cs.workloadV1alpha1 = workloadv1alpha1.New(c)
+	cs.workloadV1beta1 = workloadv1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
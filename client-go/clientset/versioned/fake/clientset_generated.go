@@ -25,6 +25,8 @@ import (
 	fakenetworkingv1alpha1 "github.com/volcano-sh/kthena/client-go/clientset/versioned/typed/networking/v1alpha1/fake"
 	workloadv1alpha1 "github.com/volcano-sh/kthena/client-go/clientset/versioned/typed/workload/v1alpha1"
 	fakeworkloadv1alpha1 "github.com/volcano-sh/kthena/client-go/clientset/versioned/typed/workload/v1alpha1/fake"
+	workloadv1beta1 "github.com/volcano-sh/kthena/client-go/clientset/versioned/typed/workload/v1beta1"
+	fakeworkloadv1beta1 "github.com/volcano-sh/kthena/client-go/clientset/versioned/typed/workload/v1beta1/fake"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
@@ -136,3 +138,8 @@ func (c *Clientset) NetworkingV1alpha1() networkingv1alpha1.NetworkingV1alpha1In
 func (c *Clientset) WorkloadV1alpha1() workloadv1alpha1.WorkloadV1alpha1Interface {
 	return &fakeworkloadv1alpha1.FakeWorkloadV1alpha1{Fake: &c.Fake}
 }
+
+// WorkloadV1beta1 retrieves the WorkloadV1beta1Client
+func (c *Clientset) WorkloadV1beta1() workloadv1beta1.WorkloadV1beta1Interface {
+	return &fakeworkloadv1beta1.FakeWorkloadV1beta1{Fake: &c.Fake}
+}
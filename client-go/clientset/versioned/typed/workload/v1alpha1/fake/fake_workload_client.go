@@ -36,6 +36,14 @@ func (c *FakeWorkloadV1alpha1) AutoscalingPolicyBindings(namespace string) v1alp
 	return newFakeAutoscalingPolicyBindings(c, namespace)
 }
 
+func (c *FakeWorkloadV1alpha1) BatchInferences(namespace string) v1alpha1.BatchInferenceInterface {
+	return newFakeBatchInferences(c, namespace)
+}
+
+func (c *FakeWorkloadV1alpha1) LoraAdapters(namespace string) v1alpha1.LoraAdapterInterface {
+	return newFakeLoraAdapters(c, namespace)
+}
+
 func (c *FakeWorkloadV1alpha1) ModelBoosters(namespace string) v1alpha1.ModelBoosterInterface {
 	return newFakeModelBoosters(c, namespace)
 }
@@ -44,6 +52,10 @@ func (c *FakeWorkloadV1alpha1) ModelServings(namespace string) v1alpha1.ModelSer
 	return newFakeModelServings(c, namespace)
 }
 
+func (c *FakeWorkloadV1alpha1) TenancyPolicies() v1alpha1.TenancyPolicyInterface {
+	return newFakeTenancyPolicies(c)
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeWorkloadV1alpha1) RESTClient() rest.Interface {
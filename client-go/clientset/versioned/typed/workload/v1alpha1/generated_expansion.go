@@ -22,6 +22,12 @@ type AutoscalingPolicyExpansion interface{}
 
 type AutoscalingPolicyBindingExpansion interface{}
 
+type BatchInferenceExpansion interface{}
+
+type LoraAdapterExpansion interface{}
+
 type ModelBoosterExpansion interface{}
 
 type ModelServingExpansion interface{}
+
+type TenancyPolicyExpansion interface{}
@@ -30,8 +30,11 @@ type WorkloadV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	AutoscalingPoliciesGetter
 	AutoscalingPolicyBindingsGetter
+	BatchInferencesGetter
+	LoraAdaptersGetter
 	ModelBoostersGetter
 	ModelServingsGetter
+	TenancyPoliciesGetter
 }
 
 // WorkloadV1alpha1Client is used to interact with features provided by the workload.serving.volcano.sh group.
@@ -47,6 +50,14 @@ func (c *WorkloadV1alpha1Client) AutoscalingPolicyBindings(namespace string) Aut
 	return newAutoscalingPolicyBindings(c, namespace)
 }
 
+func (c *WorkloadV1alpha1Client) BatchInferences(namespace string) BatchInferenceInterface {
+	return newBatchInferences(c, namespace)
+}
+
+func (c *WorkloadV1alpha1Client) LoraAdapters(namespace string) LoraAdapterInterface {
+	return newLoraAdapters(c, namespace)
+}
+
 func (c *WorkloadV1alpha1Client) ModelBoosters(namespace string) ModelBoosterInterface {
 	return newModelBoosters(c, namespace)
 }
@@ -55,6 +66,10 @@ func (c *WorkloadV1alpha1Client) ModelServings(namespace string) ModelServingInt
 	return newModelServings(c, namespace)
 }
 
+func (c *WorkloadV1alpha1Client) TenancyPolicies() TenancyPolicyInterface {
+	return newTenancyPolicies(c)
+}
+
 // NewForConfig creates a new WorkloadV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -23,6 +23,7 @@ import (
 
 	v1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
 	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	v1beta1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1beta1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -64,10 +65,20 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().AutoscalingPolicies().Informer()}, nil
 	case workloadv1alpha1.SchemeGroupVersion.WithResource("autoscalingpolicybindings"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().AutoscalingPolicyBindings().Informer()}, nil
+	case workloadv1alpha1.SchemeGroupVersion.WithResource("batchinferences"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().BatchInferences().Informer()}, nil
+	case workloadv1alpha1.SchemeGroupVersion.WithResource("loraadapters"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().LoraAdapters().Informer()}, nil
 	case workloadv1alpha1.SchemeGroupVersion.WithResource("modelboosters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().ModelBoosters().Informer()}, nil
 	case workloadv1alpha1.SchemeGroupVersion.WithResource("modelservings"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().ModelServings().Informer()}, nil
+	case workloadv1alpha1.SchemeGroupVersion.WithResource("tenancypolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().TenancyPolicies().Informer()}, nil
+
+		// Group=workload.serving.volcano.sh, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithResource("modelservings"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1beta1().ModelServings().Informer()}, nil
 
 	}
 
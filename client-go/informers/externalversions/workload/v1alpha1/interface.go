@@ -28,10 +28,16 @@ type Interface interface {
 	AutoscalingPolicies() AutoscalingPolicyInformer
 	// AutoscalingPolicyBindings returns a AutoscalingPolicyBindingInformer.
 	AutoscalingPolicyBindings() AutoscalingPolicyBindingInformer
+	// BatchInferences returns a BatchInferenceInformer.
+	BatchInferences() BatchInferenceInformer
+	// LoraAdapters returns a LoraAdapterInformer.
+	LoraAdapters() LoraAdapterInformer
 	// ModelBoosters returns a ModelBoosterInformer.
 	ModelBoosters() ModelBoosterInformer
 	// ModelServings returns a ModelServingInformer.
 	ModelServings() ModelServingInformer
+	// TenancyPolicies returns a TenancyPolicyInformer.
+	TenancyPolicies() TenancyPolicyInformer
 }
 
 type version struct {
@@ -55,6 +61,16 @@ func (v *version) AutoscalingPolicyBindings() AutoscalingPolicyBindingInformer {
 	return &autoscalingPolicyBindingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// BatchInferences returns a BatchInferenceInformer.
+func (v *version) BatchInferences() BatchInferenceInformer {
+	return &batchInferenceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// LoraAdapters returns a LoraAdapterInformer.
+func (v *version) LoraAdapters() LoraAdapterInformer {
+	return &loraAdapterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // ModelBoosters returns a ModelBoosterInformer.
 func (v *version) ModelBoosters() ModelBoosterInformer {
 	return &modelBoosterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -64,3 +80,8 @@ func (v *version) ModelBoosters() ModelBoosterInformer {
 func (v *version) ModelServings() ModelServingInformer {
 	return &modelServingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// TenancyPolicies returns a TenancyPolicyInformer.
+func (v *version) TenancyPolicies() TenancyPolicyInformer {
+	return &tenancyPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
@@ -34,6 +34,22 @@ type AutoscalingPolicyBindingListerExpansion interface{}
 // AutoscalingPolicyBindingNamespaceLister.
 type AutoscalingPolicyBindingNamespaceListerExpansion interface{}
 
+// BatchInferenceListerExpansion allows custom methods to be added to
+// BatchInferenceLister.
+type BatchInferenceListerExpansion interface{}
+
+// BatchInferenceNamespaceListerExpansion allows custom methods to be added to
+// BatchInferenceNamespaceLister.
+type BatchInferenceNamespaceListerExpansion interface{}
+
+// LoraAdapterListerExpansion allows custom methods to be added to
+// LoraAdapterLister.
+type LoraAdapterListerExpansion interface{}
+
+// LoraAdapterNamespaceListerExpansion allows custom methods to be added to
+// LoraAdapterNamespaceLister.
+type LoraAdapterNamespaceListerExpansion interface{}
+
 // ModelBoosterListerExpansion allows custom methods to be added to
 // ModelBoosterLister.
 type ModelBoosterListerExpansion interface{}
@@ -49,3 +65,7 @@ type ModelServingListerExpansion interface{}
 // ModelServingNamespaceListerExpansion allows custom methods to be added to
 // ModelServingNamespaceLister.
 type ModelServingNamespaceListerExpansion interface{}
+
+// TenancyPolicyListerExpansion allows custom methods to be added to
+// TenancyPolicyLister.
+type TenancyPolicyListerExpansion interface{}
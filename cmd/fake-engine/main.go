@@ -0,0 +1,91 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// fake-engine is an HTTP server mimicking the vLLM API, /metrics and /health endpoints without any model.
+// It is used by the e2e scenarios and the local demos of the router and the autoscaler, which then need no GPU.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/fake-engine/server"
+	"github.com/volcano-sh/kthena/pkg/util/app"
+)
+
+func main() {
+	var (
+		port   int
+		config server.Config
+	)
+
+	app.InitFlags()
+	pflag.IntVar(&port, "port", 8000, "The port to serve the API, the metrics and the health check on")
+	pflag.StringVar(&config.Model, "model", os.Getenv("MODEL_NAME"), "The name of the served model, defaults to $MODEL_NAME")
+	pflag.DurationVar(&config.TimeToFirstToken, "latency", 10*time.Millisecond, "The time to first token of every answer")
+	pflag.Float64Var(&config.TokenRate, "token-rate", 100, "The output tokens generated per second after the first one, 0 for no delay")
+	pflag.IntVar(&config.OutputTokens, "output-tokens", 16, "The number of tokens of an answer, unless the request sets a lower max_tokens")
+	pflag.IntVar(&config.MaxRunning, "max-running-requests", 0, "The requests served concurrently before the others wait, 0 for no limit")
+	defer klog.Flush()
+	pflag.Parse()
+	if _, err := app.ApplyComponentConfig("fake-engine"); err != nil {
+		klog.Fatal(err)
+	}
+
+	if port <= 0 || port > 65535 {
+		klog.Fatalf("invalid port: %d", port)
+	}
+	if config.Model == "" {
+		klog.Fatal("the served model must be set with --model or $MODEL_NAME")
+	}
+	if config.OutputTokens <= 0 {
+		klog.Fatalf("invalid output tokens: %d", config.OutputTokens)
+	}
+
+	httpServer := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: server.NewServer(config).Handler(),
+	}
+
+	err := app.Run(context.Background(), app.NewComponent("fake engine", func(ctx context.Context) error {
+		errCh := make(chan error, 1)
+		go func() {
+			klog.Infof("Serving fake model %s on port %d", config.Model, port)
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+		}()
+		select {
+		case err := <-errCh:
+			return fmt.Errorf("serve failed: %v", err)
+		case <-ctx.Done():
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return httpServer.Shutdown(shutdownCtx)
+	}))
+	if err != nil {
+		klog.Flush()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/model-cache-agent/agent"
+	"github.com/volcano-sh/kthena/pkg/util/app"
+)
+
+func main() {
+	var (
+		kubeconfig string
+		masterURL  string
+		config     agent.Config
+	)
+
+	app.InitFlags()
+	pflag.StringVar(&kubeconfig, "kubeconfig", "", "kubeconfig file path")
+	pflag.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	pflag.StringVar(&config.NodeName, "node-name", os.Getenv("NODE_NAME"), "The node the agent manages the model caches of. Defaults to the NODE_NAME environment variable")
+	pflag.StringSliceVar(&config.CacheDirs, "cache-dir", []string{"/models"}, "Host paths used as model caches by hostpath:// cache URIs, must be mounted at the same path in the agent")
+	pflag.IntVar(&config.HighWatermarkPercent, "high-watermark", 85, "Disk usage percentage of a cache above which least recently used models are evicted")
+	pflag.IntVar(&config.LowWatermarkPercent, "low-watermark", 70, "Disk usage percentage of a cache that eviction brings it back to")
+	pflag.DurationVar(&config.Interval, "interval", time.Minute, "Period between two scans of the model caches")
+	defer klog.Flush()
+	pflag.Parse()
+	if _, err := app.ApplyComponentConfig("kthena-cache-agent"); err != nil {
+		klog.Fatal(err)
+	}
+
+	restConfig, err := clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
+	if err != nil {
+		klog.Fatalf("build client config: %v", err)
+	}
+	kubeClient := kubernetes.NewForConfigOrDie(restConfig)
+	cacheAgent, err := agent.NewAgent(kubeClient, config)
+	if err != nil {
+		klog.Fatalf("Failed to create model cache agent: %v", err)
+	}
+
+	err = app.Run(context.Background(), app.NewComponent("model cache agent", func(ctx context.Context) error {
+		cacheAgent.Run(ctx)
+		return nil
+	}))
+	if err != nil {
+		klog.Flush()
+		os.Exit(1)
+	}
+}
@@ -18,22 +18,19 @@ package main
 
 import (
 	"context"
-	"crypto/tls"
-	"errors"
-	"flag"
 	"fmt"
-	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/spf13/pflag"
 	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
 	"github.com/volcano-sh/kthena/pkg/controller"
-	"github.com/volcano-sh/kthena/pkg/model-booster-webhook/handlers"
-	"github.com/volcano-sh/kthena/pkg/model-serving-controller/webhook"
-	webhookcert "github.com/volcano-sh/kthena/pkg/webhook/cert"
+	"github.com/volcano-sh/kthena/pkg/util/app"
+	"github.com/volcano-sh/kthena/pkg/util/diagnostics"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
+	"github.com/volcano-sh/kthena/pkg/webhook"
+	webhookserver "github.com/volcano-sh/kthena/pkg/webhook/server"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
@@ -42,198 +39,116 @@ import (
 type webhookConfig struct {
 	tlsCertFile    string
 	tlsPrivateKey  string
+	bindAddress    string
 	port           int
 	webhookTimeout int
 	certSecretName string
 	serviceName    string
+	certExtraSANs  []string
 }
 
 func main() {
 	var enableWebhook bool
+	var healthAddr string
+	var diagnosticsAddr, profileDir string
 	var wc webhookConfig
 	var cc controller.Config
-	// Initialize klog flags
-	klog.InitFlags(nil)
-	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	app.InitFlags()
 	pflag.StringVar(&cc.Kubeconfig, "kubeconfig", "", "kubeconfig file path")
 	pflag.BoolVar(&enableWebhook, "enable-webhook", true, "If true, webhook will be used. Default is true")
 	pflag.StringVar(&cc.MasterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
 	pflag.StringVar(&wc.tlsCertFile, "tls-cert-file", "/etc/tls/tls.crt", "File containing the x509 Certificate for HTTPS")
 	pflag.StringVar(&wc.tlsPrivateKey, "tls-private-key-file", "/etc/tls/tls.key", "File containing the x509 private key to --tls-cert-file")
+	pflag.StringVar(&wc.bindAddress, "bind-address", "", "IPv4 or IPv6 address the webhook listens on, all the addresses of both families when empty")
 	pflag.IntVar(&wc.port, "port", 8443, "Secure port that the webhook listens on")
 	pflag.IntVar(&wc.webhookTimeout, "webhook-timeout", 30, "Timeout for webhook operations in seconds")
 	pflag.StringVar(&wc.certSecretName, "cert-secret-name", "kthena-controller-manager-webhook-certs", "Name of the secret to store auto-generated certificates")
 	pflag.StringVar(&wc.serviceName, "service-name", "kthena-controller-manager-webhook", "Service name for the webhook server")
+	pflag.StringSliceVar(&wc.certExtraSANs, "cert-extra-sans", nil, "Additional DNS names or IPv4 and IPv6 addresses of the auto-generated webhook certificate")
 	pflag.BoolVar(&cc.EnableLeaderElection, "leader-elect", false, "Enable leader election for controller. "+
 		"Enabling this will ensure there is only one active controller. Default is false.")
 	pflag.IntVar(&cc.Workers, "workers", 5, "number of workers to run. Default is 5")
+	pflag.StringVar(&cc.MetricsAddr, "metrics-bind-address", ":8080", "The address the Prometheus metrics are served on. Set it to empty to disable metrics")
+	pflag.BoolVar(&cc.AutoscalerDryRun, "autoscaler-dry-run", false, "If true, the autoscaler records the scaling decisions of the AutoscalingPolicyBindings in their status and metrics without scaling the workloads")
+	pflag.BoolVar(&cc.EnableIstioIntegration, "enable-istio-integration", false, "If true, the external models of the ModelRoutes are registered in the Istio service mesh with ServiceEntries and DestinationRules")
+	pflag.StringVar(&healthAddr, "health-probe-bind-address", ":8081", "The address the /healthz, /livez and /readyz endpoints are served on")
+	pflag.StringVar(&diagnosticsAddr, "diagnostics-bind-address", "", "The address the pprof endpoints and the captures of profile bundles are served on, without authentication. Set it to empty to disable them")
+	pflag.StringVar(&profileDir, "profile-dir", diagnostics.DefaultDir, "The directory keeping the captured profile bundles")
+	defer klog.Flush()
 	pflag.Parse()
-	pflag.CommandLine.VisitAll(func(f *pflag.Flag) {
-		klog.Infof("Flag: %s, Value: %s", f.Name, f.Value.String())
-	})
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-ch
-		klog.Info("Received termination, signaling shutdown")
-		cancel()
-	}()
-	if enableWebhook {
-		go func() {
-			if err := setupWebhook(ctx, wc); err != nil {
-				os.Exit(1)
-			}
-		}()
+	if _, err := app.ApplyComponentConfig("kthena-controller-manager"); err != nil {
+		klog.Fatal(err)
 	}
-	controller.SetupController(ctx, cc)
-}
 
-const validatingWebhookName = "kthena-controller-manager-validating-webhook"
-const mutatingWebhookName = "kthena-controller-manager-mutating-webhook"
+	if enableWebhook && (wc.port <= 0 || wc.port > 65535) {
+		klog.Fatalf("invalid webhook port: %d", wc.port)
+	}
+	if err := netutil.ValidateBindAddress(wc.bindAddress); err != nil {
+		klog.Fatal(err)
+	}
 
-// ensureWebhookCertificate generates a certificate into the secret and returns the CA bundle.
-func ensureWebhookCertificate(ctx context.Context, kubeClient kubernetes.Interface, wc webhookConfig) ([]byte, error) {
-	namespace := getNamespace()
-	dnsNames := []string{
-		fmt.Sprintf("%s.%s.svc", wc.serviceName, namespace),
-		fmt.Sprintf("%s.%s.svc.cluster.local", wc.serviceName, namespace),
+	components, err := controller.NewComponents(cc)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	health := app.NewHealthServer(healthAddr)
+	health.AddReadyCheck("controllers", controller.Ready)
+	health.AddLiveCheck("controllers", controller.Live)
+	components = append(components, health)
+	if diagnosticsAddr != "" {
+		components = append(components, diagnostics.NewServer(diagnosticsAddr, diagnostics.NewCapturer("kthena-controller-manager", profileDir)))
+	}
+	if enableWebhook {
+		server, err := setupWebhook(wc)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		health.AddReadyCheck(server.Name(), server.Ready)
+		components = append(components, server)
+	}
+	if err := app.Run(context.Background(), components...); err != nil {
+		klog.Flush()
+		os.Exit(1)
 	}
-	klog.Infof("Auto-generating certificate for webhook server (secret=%s service=%s)", wc.certSecretName, wc.serviceName)
-	return webhookcert.EnsureCertificate(ctx, kubeClient, namespace, wc.certSecretName, dnsNames)
 }
 
-func setupWebhook(ctx context.Context, wc webhookConfig) error {
+func setupWebhook(wc webhookConfig) (*webhookserver.Server, error) {
 	cfg, err := rest.InClusterConfig()
 	if err != nil {
-		klog.Fatalf("build client config: %v", err)
-		return err
+		return nil, fmt.Errorf("build client config: %v", err)
 	}
 
 	kubeClient, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
-		klog.Fatalf("failed to create kubeClient: %v", err)
-		return err
+		return nil, fmt.Errorf("failed to create kubeClient: %v", err)
 	}
 
 	kthenaClient, err := clientset.NewForConfig(cfg)
 	if err != nil {
-		klog.Fatalf("failed to create kthenaClient: %v", err)
-		return err
-	}
-
-	// Secret -> File -> Generate precedence for CA bundle selection
-	namespace := getNamespace()
-	var caBundle []byte
-
-	// 1. Try secret first.
-	if bundle, err := webhookcert.LoadCertBundleFromSecret(ctx, kubeClient, namespace, wc.certSecretName); err != nil {
-		klog.Warningf("Error reading CA bundle from secret %s: %v", wc.certSecretName, err)
-	} else if bundle != nil {
-		klog.Infof("Loaded CA bundle from secret %s", wc.certSecretName)
-		caBundle = bundle.CAPEM
-	}
-
-	// 2. If not from secret, try existing cert file.
-	if caBundle == nil {
-		if !fileExists(wc.tlsPrivateKey) || !fileExists(wc.tlsCertFile) {
-			b, err := ensureWebhookCertificate(ctx, kubeClient, wc)
-			if err != nil {
-				klog.Fatalf("Failed to auto-generate webhook certificates: %v", err)
-			}
-			caBundle = b
-		}
-	}
-
-	if caBundle != nil {
-		// Always update both webhook configurations with the chosen CA bundle
-		if err := webhookcert.UpdateValidatingWebhookCABundle(ctx, kubeClient, validatingWebhookName, caBundle); err != nil {
-			klog.Warningf("Failed to update ValidatingWebhookConfiguration CA bundle: %v", err)
-		}
-		if err := webhookcert.UpdateMutatingWebhookCABundle(ctx, kubeClient, mutatingWebhookName, caBundle); err != nil {
-			klog.Warningf("Failed to update MutatingWebhookConfiguration CA bundle: %v", err)
-		}
+		return nil, fmt.Errorf("failed to create kthenaClient: %v", err)
 	}
 
-	mux := http.NewServeMux()
-
-	modelServingValidator := webhook.NewModelServingValidator()
-	mux.HandleFunc("/validate-workload-ai-v1alpha1-modelServing", modelServingValidator.Handle)
-
-	modelValidator := handlers.NewModelValidator()
-	modelMutator := handlers.NewModelMutator()
-	autoscalingPolicyValidator := handlers.NewAutoscalingPolicyValidator()
-	autoscalingPolicyMutator := handlers.NewAutoscalingPolicyMutator()
-	autoscalingBindingValidator := handlers.NewAutoscalingBindingValidator(kthenaClient)
-	mux.HandleFunc("/validate/modelbooster", modelValidator.Handle)
-	mux.HandleFunc("/mutate/modelbooster", modelMutator.Handle)
-	mux.HandleFunc("/validate/autoscalingpolicy", autoscalingPolicyValidator.Handle)
-	mux.HandleFunc("/mutate/autoscalingpolicy", autoscalingPolicyMutator.Handle)
-	mux.HandleFunc("/validate/autoscalingpolicybinding", autoscalingBindingValidator.Handle)
-
-	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		if _, err := w.Write([]byte("ok")); err != nil {
-			klog.Errorf("failed to write health check response: %v", err)
-		}
-	})
-
-	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", wc.port),
-		Handler:      mux,
-		ReadTimeout:  time.Duration(wc.webhookTimeout) * time.Second,
-		WriteTimeout: time.Duration(wc.webhookTimeout) * time.Second,
-		TLSConfig: &tls.Config{
-			MinVersion: tls.VersionTLS12,
-		},
-	}
-
-	// Wait for both cert and key files to exist (in case they are mounted by Kubernetes)
-	ok := waitForCertsReady(wc.tlsPrivateKey, wc.tlsCertFile)
-	if !ok {
-		return fmt.Errorf("TLS cert/key files not found, webhook server cannot start")
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamicClient: %v", err)
 	}
 
-	go func() {
-		klog.Infof("Starting webhook server on %s", server.Addr)
-		if err := server.ListenAndServeTLS(wc.tlsCertFile, wc.tlsPrivateKey); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			klog.Fatalf("failed to start unified webhook server: %v", err)
-		}
-	}()
-	<-ctx.Done()
-	ctxTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	_ = server.Shutdown(ctxTimeout)
-	return nil
-}
-
-// getNamespace returns the current pod namespace or "default".
-func getNamespace() string {
-	return os.Getenv("POD_NAMESPACE")
-}
-
-// fileExists returns true if the file exists.
-func fileExists(path string) bool {
-	if path == "" {
-		return false
+	server := webhookserver.New(webhookserver.Config{
+		BindAddress:    wc.bindAddress,
+		Port:           wc.port,
+		CertFile:       wc.tlsCertFile,
+		KeyFile:        wc.tlsPrivateKey,
+		Timeout:        time.Duration(wc.webhookTimeout) * time.Second,
+		Namespace:      os.Getenv("POD_NAMESPACE"),
+		CertSecretName: wc.certSecretName,
+		ServiceName:    wc.serviceName,
+		CertExtraSANs:  wc.certExtraSANs,
+	}, kubeClient, dynamicClient)
+	set, err := webhook.Lookup(webhook.Workload)
+	if err != nil {
+		return nil, err
 	}
-	_, err := os.Stat(path)
-	return err == nil
-}
+	set.Register(server, webhook.Clients{Kube: kubeClient, Kthena: kthenaClient})
 
-func waitForCertsReady(keyFile, CertFile string) bool {
-	waitTimeout := 30 * time.Second
-	waitInterval := 500 * time.Millisecond
-	start := time.Now()
-	for {
-		if fileExists(CertFile) && fileExists(keyFile) {
-			return true
-		}
-		if time.Since(start) > waitTimeout {
-			klog.Warningf("timeout waiting for TLS cert/key files to appear at %s and %s", keyFile, CertFile)
-			return false
-		}
-		time.Sleep(waitInterval)
-	}
+	return server, nil
 }
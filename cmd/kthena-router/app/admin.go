@@ -0,0 +1,150 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"flag"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/admin"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/debug"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/router"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/tokenization"
+	"github.com/volcano-sh/kthena/pkg/util/diagnostics"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
+)
+
+// startAdmin serves the admin API on its own port, so that it is never exposed through the Service of the router.
+// Every request must carry the bearer token stored in the admin token file.
+func (s *Server) startAdmin(router *router.Router, store datastore.Store) *http.Server {
+	engine := gin.New()
+	engine.Use(gin.Logger(), gin.Recovery())
+	engine.Use(admin.TokenAuth(s.AdminTokenFile))
+
+	adminGroup := engine.Group("/admin")
+
+	// Datastore contents
+	debugHandler := debug.NewDebugHandler(store)
+	configDumpGroup := adminGroup.Group("/config_dump")
+	{
+		configDumpGroup.GET("/modelroutes", debugHandler.ListModelRoutes)
+		configDumpGroup.GET("/modelservers", debugHandler.ListModelServers)
+		configDumpGroup.GET("/pods", debugHandler.ListPods)
+		configDumpGroup.GET("/namespaces/:namespace/modelroutes/:name", debugHandler.GetModelRoute)
+		configDumpGroup.GET("/namespaces/:namespace/modelservers/:name", debugHandler.GetModelServer)
+		configDumpGroup.GET("/namespaces/:namespace/pods/:name", debugHandler.GetPod)
+	}
+
+	// Effective scheduling configuration and plugin toggles
+	schedulerHandler := admin.NewSchedulerHandler(router.Scheduler)
+	adminGroup.GET("/scheduler", schedulerHandler.GetConfig)
+	adminGroup.PUT("/scheduler/plugins/:name", schedulerHandler.SetPluginEnabled)
+
+	// Scheduling decisions, which name the pods and the request IDs
+	decisionHandler := debug.NewDecisionHandler(router.DecisionStore())
+	adminGroup.GET("/scheduling/decisions", decisionHandler.ListDecisions)
+
+	// Health-check states
+	healthHandler := admin.NewHealthHandler(s.HasSynced, s.drainer, tokenization.DefaultHealthTracker)
+	adminGroup.GET("/health", healthHandler.GetHealth)
+
+	// Log verbosity
+	if verbosity := flag.CommandLine.Lookup("v"); verbosity != nil {
+		loggingHandler := admin.NewLoggingHandler(verbosity.Value)
+		adminGroup.GET("/logging", loggingHandler.GetLevel)
+		adminGroup.PUT("/logging", loggingHandler.SetLevel)
+	}
+
+	// Drain
+	drainHandler := admin.NewDrainHandler(s.drainer)
+	adminGroup.GET("/drain", drainHandler.GetStatus)
+	adminGroup.POST("/drain", drainHandler.Drain)
+	adminGroup.DELETE("/drain", drainHandler.Cancel)
+
+	// Fault injection rules
+	if faults := router.Faults(); faults != nil {
+		faultHandler := admin.NewFaultHandler(faults)
+		adminGroup.GET("/faults", faultHandler.GetRules)
+		adminGroup.PUT("/faults", faultHandler.SetRules)
+		adminGroup.DELETE("/faults", faultHandler.DeleteRules)
+	}
+
+	// ModelRoutes registered before they are synced from the API server
+	routeHandler := admin.NewRouteHandler(s.registry)
+	routeGroup := adminGroup.Group("/routes")
+	{
+		routeGroup.GET("", routeHandler.ListRoutes)
+		routeGroup.PUT("/namespaces/:namespace/modelroutes/:name", routeHandler.RegisterRoute)
+		routeGroup.DELETE("/namespaces/:namespace/modelroutes/:name", routeHandler.UnregisterRoute)
+	}
+
+	// ModelRoute snapshots
+	if s.snapshots != nil {
+		snapshotHandler := admin.NewSnapshotHandler(s.snapshots)
+		snapshotGroup := adminGroup.Group("/snapshots")
+		{
+			snapshotGroup.GET("/namespaces/:namespace/modelroutes/:name", snapshotHandler.ListSnapshots)
+			snapshotGroup.GET("/namespaces/:namespace/modelroutes/:name/versions/:version", snapshotHandler.GetSnapshot)
+			snapshotGroup.POST("/namespaces/:namespace/modelroutes/:name/rollback", snapshotHandler.Rollback)
+		}
+	}
+
+	// Traffic compare reports, which hold the responses of the mirrored requests
+	compareHandler := debug.NewCompareHandler(router.CompareStore())
+	compareGroup := adminGroup.Group("/compare")
+	{
+		compareGroup.GET("/modelroutes", compareHandler.ListReports)
+		compareGroup.GET("/namespaces/:namespace/modelroutes/:name", compareHandler.GetReport)
+		compareGroup.DELETE("/namespaces/:namespace/modelroutes/:name", compareHandler.DeleteSamples)
+	}
+
+	// Profile bundles
+	profileHandler := admin.NewProfileHandler(diagnostics.NewCapturer("kthena-router", profileDir))
+	profileGroup := adminGroup.Group("/profiles")
+	{
+		profileGroup.GET("", profileHandler.ListProfiles)
+		profileGroup.POST("", profileHandler.CaptureProfile)
+		profileGroup.GET("/:name", profileHandler.GetProfile)
+	}
+
+	// Go runtime profiles, behind the same token as the admin API
+	if pprofEnabled {
+		engine.Any("/debug/pprof/*path", gin.WrapH(diagnostics.PprofHandler()))
+	}
+
+	server := &http.Server{
+		Addr:    netutil.ListenAddress(s.BindAddress, s.AdminPort),
+		Handler: engine.Handler(),
+	}
+	go func() {
+		klog.Infof("Admin server running on port %s", s.AdminPort)
+		var err error
+		if s.EnableTLS {
+			err = server.ListenAndServeTLS(s.TLSCertFile, s.TLSKeyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			klog.Fatalf("admin server listen failed: %v", err)
+		}
+	}()
+	return server
+}
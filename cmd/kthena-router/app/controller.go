@@ -17,19 +17,46 @@ limitations under the License.
 package app
 
 import (
+	"context"
+	"os"
+
+	"istio.io/istio/pkg/env"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
+	gatewayclientset "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+	gatewayinformers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
 
 	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
 	kthenaInformers "github.com/volcano-sh/kthena/client-go/informers/externalversions"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/controller"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/persistence"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/router"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/tokenization"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/snapshot"
+	apputil "github.com/volcano-sh/kthena/pkg/util/app"
+)
+
+const (
+	leaderElectionName = "kthena.router"
+	leaseName          = "lease.kthena.router"
+)
+
+var (
+	leaderElectionEnabled     = env.RegisterBoolVar("ROUTER_LEADER_ELECTION_ENABLED", true, "Only let the leader replica write ModelRoute snapshots and ModelServer statuses, every replica serves requests").Get()
+	routeSnapshotEnabled      = env.RegisterBoolVar("ROUTE_SNAPSHOT_ENABLED", true, "Persist every accepted ModelRoute change as a snapshot that can be rolled back to").Get()
+	routeSnapshotHistoryLimit = env.RegisterIntVar("ROUTE_SNAPSHOT_HISTORY_LIMIT", snapshot.DefaultHistoryLimit, "Number of snapshots kept for each ModelRoute").Get()
+	sloModelServingStatus     = env.RegisterBoolVar("SLO_MODEL_SERVING_STATUS", false, "Also set the ErrorBudgetAvailable condition on the ModelServings serving the ModelServers with service level objectives").Get()
+	gatewayAPIEnabled         = env.RegisterBoolVar("ROUTER_GATEWAY_API_ENABLED", false, "Route the models matched by the Gateway API HTTPRoutes attached to the Gateways of the kthena-router GatewayClasses, the Gateway API CRDs must be installed").Get()
 )
 
 type Controller interface {
 	HasSynced() bool
+	// Live returns an error if the controller is stuck and doesn't update the datastore anymore.
+	Live() error
 }
 
 type aggregatedController struct {
@@ -38,7 +65,11 @@ type aggregatedController struct {
 
 var _ Controller = &aggregatedController{}
 
-func startControllers(store datastore.Store, stop <-chan struct{}) Controller {
+// startControllers starts the controllers feeding the datastore, which run in every replica. It returns the
+// controllers writing to the API server as a component, which is leader elected unless ROUTER_LEADER_ELECTION_ENABLED
+// is false, so that the replicas don't race on the same objects. The leader also snapshots the learned routing state.
+// The router reads the Secrets of the external fallback models with the same client.
+func startControllers(store datastore.Store, r *router.Router, stop <-chan struct{}) (Controller, *snapshot.Manager, *persistence.Persister, apputil.Component) {
 	cfg, err := clientcmd.BuildConfigFromFlags("", "")
 	if err != nil {
 		klog.Fatalf("Error building kubeconfig: %s", err.Error())
@@ -49,6 +80,8 @@ func startControllers(store datastore.Store, stop <-chan struct{}) Controller {
 		klog.Fatalf("Error building kubernetes clientset: %s", err.Error())
 	}
 
+	r.SetKubeClient(kubeClient)
+
 	kthenaClient, err := clientset.NewForConfig(cfg)
 	if err != nil {
 		klog.Fatalf("Error building kthena clientset: %s", err.Error())
@@ -57,8 +90,55 @@ func startControllers(store datastore.Store, stop <-chan struct{}) Controller {
 	kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
 	kthenaInformerFactory := kthenaInformers.NewSharedInformerFactory(kthenaClient, 0)
 
+	var snapshots *snapshot.Manager
+	if routeSnapshotEnabled {
+		snapshots = snapshot.NewManager(kubeClient, kthenaClient, routeSnapshotHistoryLimit)
+	}
+
 	modelRouteController := controller.NewModelRouteController(kthenaInformerFactory, store)
 	modelServerController := controller.NewModelServerController(kthenaInformerFactory, kubeInformerFactory, store)
+	modelServerStatusUpdater := controller.NewModelServerStatusUpdater(kthenaClient, kthenaInformerFactory, tokenization.DefaultHealthTracker)
+	sloStatusUpdater := controller.NewSLOStatusUpdater(kthenaClient, kthenaInformerFactory, store, sloModelServingStatus)
+	writers := []apputil.Component{
+		apputil.NewComponent("ModelServer status updater", func(ctx context.Context) error {
+			modelServerStatusUpdater.Run(ctx.Done())
+			return nil
+		}),
+		apputil.NewComponent("SLO status updater", func(ctx context.Context) error {
+			sloStatusUpdater.Run(ctx.Done())
+			return nil
+		}),
+	}
+	if snapshots != nil {
+		writers = append(writers, apputil.NewComponent("ModelRoute snapshot controller",
+			controller.NewModelRouteSnapshotController(kthenaInformerFactory, snapshots).Run))
+	}
+	persister := newPersister(store, kubeClient)
+	if persister != nil {
+		writers = append(writers, apputil.NewComponent("learned state persister", persister.Run))
+	}
+
+	controllers := []Controller{modelRouteController, modelServerController}
+	if gatewayAPIEnabled {
+		gatewayClient, err := gatewayclientset.NewForConfig(cfg)
+		if err != nil {
+			klog.Fatalf("Error building gateway clientset: %s", err.Error())
+		}
+		gatewayInformerFactory := gatewayinformers.NewSharedInformerFactory(gatewayClient, 0)
+		httpRouteController := controller.NewHTTPRouteController(gatewayInformerFactory, store)
+		httpRouteStatusUpdater := controller.NewHTTPRouteStatusUpdater(gatewayClient, gatewayInformerFactory, kthenaInformerFactory)
+		writers = append(writers, apputil.NewComponent("HTTPRoute status updater", func(ctx context.Context) error {
+			httpRouteStatusUpdater.Run(ctx.Done())
+			return nil
+		}))
+		gatewayInformerFactory.Start(stop)
+		go func() {
+			if err := httpRouteController.Run(stop); err != nil {
+				klog.Fatalf("Error running HTTPRoute controller: %s", err.Error())
+			}
+		}()
+		controllers = append(controllers, httpRouteController)
+	}
 
 	kubeInformerFactory.Start(stop)
 	kthenaInformerFactory.Start(stop)
@@ -76,11 +156,29 @@ func startControllers(store datastore.Store, stop <-chan struct{}) Controller {
 	}()
 
 	return &aggregatedController{
-		controllers: []Controller{
-			modelRouteController,
-			modelServerController,
-		},
+		controllers: controllers,
+	}, snapshots, persister, newWriters(kubeClient, writers)
+}
+
+// newWriters returns a component running the controllers writing to the API server.
+func newWriters(kubeClient kubernetes.Interface, writers []apputil.Component) apputil.Component {
+	namespace := os.Getenv("POD_NAMESPACE")
+	if !leaderElectionEnabled || namespace == "" {
+		klog.Info("Leader election is disabled, every replica writes to the API server")
+		return apputil.NewComponent("writers", func(ctx context.Context) error {
+			return apputil.RunComponents(ctx, writers...)
+		})
 	}
+	return apputil.LeaderElected(apputil.LeaderElection{
+		Client:           kubeClient,
+		Name:             leaderElectionName,
+		Namespace:        namespace,
+		LeaseName:        leaseName,
+		OnStartedLeading: func() { metrics.DefaultMetrics.SetLeader(true) },
+		OnStoppedLeading: func() { metrics.DefaultMetrics.SetLeader(false) },
+		// The replica keeps serving requests when it loses the lease
+		Recampaign: true,
+	}, writers...)
 }
 
 func (c *aggregatedController) HasSynced() bool {
@@ -91,3 +189,12 @@ func (c *aggregatedController) HasSynced() bool {
 	}
 	return true
 }
+
+func (c *aggregatedController) Live() error {
+	for _, controller := range c.controllers {
+		if err := controller.Live(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
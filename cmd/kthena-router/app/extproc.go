@@ -0,0 +1,79 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"net"
+	"time"
+
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/router"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
+)
+
+// startExtProc serves the Envoy external processing API on its own port, so that Envoy based gateways can have
+// their requests scheduled by the router without proxying them through it. The router certificate is used when
+// TLS is enabled. The gRPC health service reports the server as serving.
+func (s *Server) startExtProc(router *router.Router) *grpc.Server {
+	var opts []grpc.ServerOption
+	if s.EnableTLS {
+		creds, err := credentials.NewServerTLSFromFile(s.TLSCertFile, s.TLSKeyFile)
+		if err != nil {
+			klog.Fatalf("Failed to load the TLS certificate of the external processing server: %v", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	server := grpc.NewServer(opts...)
+	extprocv3.RegisterExternalProcessorServer(server, router.ExtProcServer())
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(extprocv3.ExternalProcessor_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	listener, err := net.Listen("tcp", netutil.ListenAddress(s.BindAddress, s.ExtProcPort))
+	if err != nil {
+		klog.Fatalf("External processing server listen failed: %v", err)
+	}
+	go func() {
+		klog.Infof("Serving the Envoy external processing API on port %s", s.ExtProcPort)
+		if err := server.Serve(listener); err != nil {
+			klog.Fatalf("External processing server failed: %v", err)
+		}
+	}()
+	return server
+}
+
+// stopExtProc waits up to the drain timeout for the streams of the requests still proxied by the gateways,
+// which last until their responses are received, then closes them.
+func (s *Server) stopExtProc(server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(s.DrainTimeout):
+		klog.Errorf("Drain timeout exceeded, closing the external processing streams")
+		server.Stop()
+	}
+}
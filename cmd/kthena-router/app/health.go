@@ -0,0 +1,79 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"istio.io/istio/pkg/env"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+// The router is not ready until each critical model has a pod to serve it, so that a rollout of the router
+// doesn't send traffic to a replica which would fail the requests of these models.
+var criticalModels = parseModels(env.RegisterStringVar("ROUTER_CRITICAL_MODELS", "", "Comma separated models which must have a serving pod for the router to be ready").Get())
+
+func parseModels(value string) []string {
+	var models []string
+	for _, model := range strings.Split(value, ",") {
+		if model = strings.TrimSpace(model); model != "" {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// modelsAvailable returns an error naming the models with no pod to serve them. A model is available when one of
+// the model servers targeted by the ModelRoutes of the model, or of the LoRA adapter, has a pod.
+func modelsAvailable(store datastore.Store, models []string) error {
+	if len(models) == 0 {
+		return nil
+	}
+	routes := store.GetAllModelRoutes()
+	var unavailable []string
+	for _, model := range models {
+		if !modelAvailable(store, routes, model) {
+			unavailable = append(unavailable, model)
+		}
+	}
+	if len(unavailable) > 0 {
+		return fmt.Errorf("no pod is serving the models %s", strings.Join(unavailable, ", "))
+	}
+	return nil
+}
+
+func modelAvailable(store datastore.Store, routes map[string]*aiv1alpha1.ModelRoute, model string) bool {
+	for _, route := range routes {
+		if route.Spec.ModelName != model && !slices.Contains(route.Spec.LoraAdapters, model) {
+			continue
+		}
+		for _, rule := range route.Spec.Rules {
+			for _, target := range rule.TargetModels {
+				pods, err := store.GetPodsByModelServer(types.NamespacedName{Namespace: route.Namespace, Name: target.ModelServerName})
+				if err == nil && len(pods) > 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
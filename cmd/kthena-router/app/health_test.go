@@ -0,0 +1,60 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"istio.io/istio/pkg/util/sets"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+func TestParseModels(t *testing.T) {
+	assert.Nil(t, parseModels(""))
+	assert.Equal(t, []string{"llama", "qwen"}, parseModels(" llama,, qwen ,"))
+}
+
+func TestModelsAvailable(t *testing.T) {
+	store := datastore.New()
+	require.NoError(t, store.AddOrUpdateModelRoute(&aiv1alpha1.ModelRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "llama"},
+		Spec: aiv1alpha1.ModelRouteSpec{
+			ModelName:    "llama",
+			LoraAdapters: []string{"llama-lora"},
+			Rules: []*aiv1alpha1.Rule{{
+				TargetModels: []*aiv1alpha1.TargetModel{{ModelServerName: "llama-server"}},
+			}},
+		},
+	}))
+	modelServer := &aiv1alpha1.ModelServer{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "llama-server"}}
+	require.NoError(t, store.AddOrUpdateModelServer(modelServer, sets.New[types.NamespacedName]()))
+
+	assert.NoError(t, modelsAvailable(store, nil))
+	assert.EqualError(t, modelsAvailable(store, []string{"llama", "qwen"}), "no pod is serving the models llama, qwen")
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "llama-0"}}
+	require.NoError(t, store.AddOrUpdatePod(pod, []*aiv1alpha1.ModelServer{modelServer}))
+	assert.NoError(t, modelsAvailable(store, []string{"llama", "llama-lora"}))
+	assert.EqualError(t, modelsAvailable(store, []string{"llama", "qwen"}), "no pod is serving the models qwen")
+}
@@ -24,16 +24,26 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"istio.io/istio/pkg/env"
 	"k8s.io/klog/v2"
 
 	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/debug"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/dialect"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/router"
+	"github.com/volcano-sh/kthena/pkg/util/diagnostics"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
 )
 
-const (
-	gracefulShutdownTimeout = 15 * time.Second
-	routerConfigFile        = "/etc/config/routerConfiguration.yaml"
+const routerConfigFile = "/etc/config/routerConfiguration.yaml"
+
+var (
+	// The admin API changes the routing configuration, so it has to be enabled explicitly.
+	adminAPIEnabled = env.RegisterBoolVar("ROUTER_ADMIN_API_ENABLED", false, "Serve the authenticated admin API of the router on the admin port").Get()
+	pprofEnabled    = env.RegisterBoolVar("ROUTER_PPROF_ENABLED", false, "Serve the Go pprof endpoints under /debug/pprof/ on the admin port, the admin API must be enabled").Get()
+	profileDir      = env.RegisterStringVar("ROUTER_PROFILE_DIR", diagnostics.DefaultDir, "Directory keeping the profile bundles captured through the admin API").Get()
+	apiDialects     = env.RegisterStringVar("ROUTER_API_DIALECTS", "", "Comma separated API dialects translated to the OpenAI API of the model servers: anthropic, gemini").Get()
 )
 
 func NewRouter(store datastore.Store) *router.Router {
@@ -42,13 +52,20 @@ func NewRouter(store datastore.Store) *router.Router {
 
 // Starts router
 func (s *Server) startRouter(ctx context.Context, router *router.Router, store datastore.Store) {
+	dialects, err := dialect.Parse(apiDialects)
+	if err != nil {
+		klog.Fatalf("invalid ROUTER_API_DIALECTS: %v", err)
+	}
+
 	gin.SetMode(gin.ReleaseMode)
 	engine := gin.New()
-	engine.Use(gin.LoggerWithWriter(gin.DefaultWriter, "/healthz", "/readyz", "/metrics"), gin.Recovery())
+	engine.Use(gin.LoggerWithWriter(gin.DefaultWriter, "/healthz", "/livez", "/readyz", "/metrics"), gin.Recovery())
 
 	// Add middleware
-	engine.Use(AccessLogMiddleware(router))
-	engine.Use(AuthMiddleware(router))
+	engine.Use(s.drainer.Middleware())
+	engine.Use(AccessLogMiddleware(router, dialects))
+	engine.Use(s.Limits.Middleware())
+	engine.Use(AuthMiddleware(router, dialects))
 
 	engine.GET("/healthz", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -56,15 +73,40 @@ func (s *Server) startRouter(ctx context.Context, router *router.Router, store d
 		})
 	})
 
+	// A stuck controller serves stale routes forever, the router is restarted
+	engine.GET("/livez", func(c *gin.Context) {
+		if err := s.controllers.Live(); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message": "ok",
+		})
+	})
+
 	engine.GET("/readyz", func(c *gin.Context) {
-		if s.HasSynced() {
-			c.JSON(http.StatusOK, gin.H{
-				"message": "router is ready",
+		switch {
+		case s.drainer.Draining():
+			// Being unready removes the router from the endpoints of its Service.
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"message": "router is draining",
 			})
-		} else {
+		case !s.HasSynced():
 			c.JSON(http.StatusServiceUnavailable, gin.H{
 				"message": "router is not ready",
 			})
+		default:
+			if err := modelsAvailable(store, criticalModels); err != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{
+					"message": err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{
+				"message": "router is ready",
+			})
 		}
 	})
 
@@ -74,6 +116,12 @@ func (s *Server) startRouter(ctx context.Context, router *router.Router, store d
 	// Handle all paths under /v1/
 	engine.Any("/v1/*path", router.HandlerFunc())
 
+	// Handle the APIs of other providers, translated to the OpenAI API
+	for _, d := range dialects {
+		engine.POST(d.Prefix()+"/*path", dialect.Handler(d, router.HandlerFunc()))
+		klog.Infof("Serving the %s API under %s/", d.Name(), d.Prefix())
+	}
+
 	// Debug endpoints
 	debugHandler := debug.NewDebugHandler(store)
 	debugGroup := engine.Group("/debug/config_dump")
@@ -90,8 +138,10 @@ func (s *Server) startRouter(ctx context.Context, router *router.Router, store d
 	}
 
 	server := &http.Server{
-		Addr:    ":" + s.Port,
-		Handler: engine.Handler(),
+		Addr:              netutil.ListenAddress(s.BindAddress, s.Port),
+		Handler:           router.TenantHandler(engine.Handler()),
+		ReadHeaderTimeout: s.ReadHeaderTimeout,
+		IdleTimeout:       s.IdleTimeout,
 	}
 	go func() {
 		// service connections
@@ -109,21 +159,59 @@ func (s *Server) startRouter(ctx context.Context, router *router.Router, store d
 		}
 	}()
 
+	var adminServer *http.Server
+	if adminAPIEnabled {
+		adminServer = s.startAdmin(router, store)
+	}
+	var extProcServer *grpc.Server
+	if s.ExtProcPort != "" {
+		extProcServer = s.startExtProc(router)
+	}
+
 	<-ctx.Done()
-	// graceful shutdown
-	klog.Info("Shutting down HTTP server ...")
-	ctx, cancel := context.WithTimeout(context.Background(), gracefulShutdownTimeout)
+	s.shutdown(server, router)
+	if extProcServer != nil {
+		s.stopExtProc(extProcServer)
+	}
+	if adminServer != nil {
+		// The admin server is stopped last, so that the drain can be followed until the end.
+		if err := adminServer.Close(); err != nil {
+			klog.Errorf("Admin server close failed: %v", err)
+		}
+	}
+}
+
+// shutdown drains the router before stopping it. The router reports itself as not ready and waits
+// for the endpoints of its Service to be updated, then stops accepting connections and waits for the
+// in-flight requests, streams included, until the drain timeout. Once drained, the router leaves the
+// state shared with the other replicas.
+func (s *Server) shutdown(server *http.Server, router *router.Router) {
+	if s.drainer.Start() {
+		klog.Info("Router is draining")
+	}
+	// The delay is counted from the start of the drain, which may have been requested through the admin API.
+	if remaining := s.DrainDelay - time.Since(s.drainer.Since()); remaining > 0 {
+		klog.Infof("Waiting %s for the router to be removed from the service endpoints", remaining)
+		time.Sleep(remaining)
+	}
+
+	klog.Infof("Shutting down HTTP server, waiting up to %s for %d in-flight requests ...", s.DrainTimeout, s.drainer.InFlight())
+	ctx, cancel := context.WithTimeout(context.Background(), s.DrainTimeout)
 	defer cancel()
 	if err := server.Shutdown(ctx); err != nil {
-		klog.Errorf("Server shutdown failed: %v", err)
+		klog.Errorf("Drain timeout exceeded, closing %d in-flight requests: %v", s.drainer.InFlight(), err)
+		if err := server.Close(); err != nil {
+			klog.Errorf("Server close failed: %v", err)
+		}
 	}
+	router.Deregister()
 	klog.Info("HTTP server exited")
 }
 
-func AccessLogMiddleware(gwRouter *router.Router) gin.HandlerFunc {
+func AccessLogMiddleware(gwRouter *router.Router, dialects []dialect.Dialect) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Access log for "/v1/" only
-		if !strings.HasPrefix(c.Request.URL.Path, "/v1/") {
+		// Access log for "/v1/" and the API dialects only
+		if !servesModels(c.Request.URL.Path, dialects) {
 			c.Next()
 			return
 		}
@@ -133,10 +221,10 @@ func AccessLogMiddleware(gwRouter *router.Router) gin.HandlerFunc {
 	}
 }
 
-func AuthMiddleware(gwRouter *router.Router) gin.HandlerFunc {
+func AuthMiddleware(gwRouter *router.Router, dialects []dialect.Dialect) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Auth for "/v1/" only
-		if !strings.HasPrefix(c.Request.URL.Path, "/v1/") {
+		// Auth for "/v1/" and the API dialects only
+		if !servesModels(c.Request.URL.Path, dialects) {
 			c.Next()
 			return
 		}
@@ -150,3 +238,8 @@ func AuthMiddleware(gwRouter *router.Router) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// servesModels reports whether the path is served by the models, through the OpenAI API or an API dialect.
+func servesModels(path string, dialects []dialect.Dialect) bool {
+	return strings.HasPrefix(path, "/v1/") || dialect.Matches(dialects, path)
+}
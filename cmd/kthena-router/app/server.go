@@ -18,29 +18,84 @@ package app
 
 import (
 	"context"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
 	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/drain"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/limits"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/persistence"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/registration"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/tokenization"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/snapshot"
+	apputil "github.com/volcano-sh/kthena/pkg/util/app"
+)
+
+const (
+	DefaultDrainDelay     = 5 * time.Second
+	DefaultDrainTimeout   = 45 * time.Second
+	DefaultAdminPort      = "8081"
+	DefaultAdminTokenFile = "/etc/kthena-router/admin/token"
+
+	DefaultMaxRequestBodyBytes = 32 << 20
+	DefaultReadHeaderTimeout   = 10 * time.Second
+	DefaultBodyReadTimeout     = time.Minute
+	DefaultIdleTimeout         = 90 * time.Second
 )
 
 type Server struct {
 	store       datastore.Store
 	controllers Controller
+	snapshots   *snapshot.Manager
+	registry    *registration.Registry
+	drainer     *drain.Drainer
 	EnableTLS   bool
 	TLSCertFile string
 	TLSKeyFile  string
+	// BindAddress is the IPv4 or IPv6 address the router, admin and external processing servers listen on,
+	// all the addresses of both families when empty.
+	BindAddress string
 	Port        string
+	// DrainDelay is the time left for the endpoints of the router Service to be updated once draining,
+	// before the router stops accepting connections.
+	DrainDelay time.Duration
+	// DrainTimeout is the time the in-flight requests are waited for on shutdown, before they are closed.
+	DrainTimeout time.Duration
+	// AdminPort is the port of the admin API, served when ROUTER_ADMIN_API_ENABLED is set.
+	AdminPort string
+	// AdminTokenFile holds the bearer token authenticating the admin API requests.
+	AdminTokenFile string
+	// ReadHeaderTimeout is the time a client has to send the request headers.
+	ReadHeaderTimeout time.Duration
+	// IdleTimeout is the time an idle keep-alive connection is kept open.
+	IdleTimeout time.Duration
+	// Limits are the limits of the request bodies and durations.
+	Limits limits.Limits
+	// ExtProcPort is the port of the Envoy external processing API, not served when empty.
+	ExtProcPort string
 }
 
 func NewServer(port string, enableTLS bool, cert, key string) *Server {
 	return &Server{
-		store:       nil,
-		EnableTLS:   enableTLS,
-		TLSCertFile: cert,
-		TLSKeyFile:  key,
-		Port:        port,
+		store:             nil,
+		drainer:           drain.New(),
+		EnableTLS:         enableTLS,
+		TLSCertFile:       cert,
+		TLSKeyFile:        key,
+		Port:              port,
+		DrainDelay:        DefaultDrainDelay,
+		DrainTimeout:      DefaultDrainTimeout,
+		AdminPort:         DefaultAdminPort,
+		AdminTokenFile:    DefaultAdminTokenFile,
+		ReadHeaderTimeout: DefaultReadHeaderTimeout,
+		IdleTimeout:       DefaultIdleTimeout,
+		Limits: limits.Limits{
+			MaxRequestBodyBytes: DefaultMaxRequestBodyBytes,
+			BodyReadTimeout:     DefaultBodyReadTimeout,
+		},
 	}
 }
 
@@ -48,16 +103,49 @@ func (s *Server) Run(ctx context.Context) {
 	// create store
 	store := datastore.New()
 	s.store = store
+	// The engine metrics scraped from the pods are exported per model by the /metrics of the router
+	if err := prometheus.Register(datastore.NewEngineMetricsCollector(store)); err != nil {
+		klog.Errorf("Failed to register the engine metrics collector: %v", err)
+	}
+	if err := prometheus.Register(datastore.NewSLOCollector(store)); err != nil {
+		klog.Errorf("Failed to register the SLO metrics collector: %v", err)
+	}
 
 	// must be run before the controller, because it will register callbacks
 	r := NewRouter(store)
+	go func() {
+		// The router keeps running with its current configuration if the file can't be watched
+		if err := r.WatchConfig(ctx); err != nil {
+			klog.Errorf("Failed to watch the router config, it is not reloaded on change: %v", err)
+		}
+	}()
+	store.RegisterState(tokenization.NewHealthState(tokenization.DefaultHealthTracker, store))
+	s.registry = registration.New(store)
+	go s.registry.Run(ctx)
 	// start controller
-	s.controllers = startControllers(store, ctx.Done())
+	var writers apputil.Component
+	var persister *persistence.Persister
+	s.controllers, s.snapshots, persister, writers = startControllers(store, r, ctx.Done())
+	go func() {
+		if err := writers.Run(ctx); err != nil {
+			klog.Errorf("Failed to run %s: %v", writers.Name(), err)
+		}
+	}()
 
 	// Start store's periodic update loop after controllers have synced
 	if !cache.WaitForCacheSync(ctx.Done(), s.controllers.HasSynced) {
+		if ctx.Err() != nil {
+			klog.Info("Shut down before the controllers have synced")
+			return
+		}
 		klog.Fatalf("Failed to sync controllers")
 	}
+	if persister != nil {
+		// The pods and model servers are known once synced, the state of the ones which are gone is dropped
+		if err := persister.Restore(ctx); err != nil {
+			klog.Errorf("Starting without the learned routing state: %v", err)
+		}
+	}
 	klog.Infof("Controllers have synced, starting store periodic update loop")
 	store.Run(ctx)
 	// start router
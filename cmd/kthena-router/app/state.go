@@ -0,0 +1,68 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"os"
+
+	"istio.io/istio/pkg/env"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/persistence"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/utils"
+)
+
+const (
+	stateBackendRedis     = "redis"
+	stateBackendConfigMap = "configmap"
+)
+
+var (
+	learnedStateBackend   = env.RegisterStringVar("ROUTER_LEARNED_STATE_BACKEND", "", "Where the learned routing state, e.g. the prefix index, is persisted across restarts: redis, configmap, or empty to disable it").Get()
+	learnedStateInterval  = env.RegisterDurationVar("ROUTER_LEARNED_STATE_INTERVAL", persistence.DefaultInterval, "Interval between two snapshots of the learned routing state").Get()
+	learnedStateConfigMap = env.RegisterStringVar("ROUTER_LEARNED_STATE_CONFIGMAP", persistence.DefaultConfigMapName, "ConfigMap holding the learned routing state, in the namespace of the router").Get()
+)
+
+// newPersister returns the persister of the learned states of the store, nil when they are not persisted.
+func newPersister(store datastore.Store, kubeClient kubernetes.Interface) *persistence.Persister {
+	var backend persistence.Backend
+	switch learnedStateBackend {
+	case "":
+		return nil
+	case stateBackendRedis:
+		client := utils.TryGetRedisClient()
+		if client == nil {
+			klog.Errorf("Redis is not available, the learned routing state is not persisted")
+			return nil
+		}
+		backend = persistence.NewRedisBackend(client, persistence.DefaultRedisKey)
+	case stateBackendConfigMap:
+		namespace := os.Getenv("POD_NAMESPACE")
+		if namespace == "" {
+			klog.Errorf("POD_NAMESPACE is not set, the learned routing state is not persisted")
+			return nil
+		}
+		backend = persistence.NewConfigMapBackend(kubeClient, namespace, learnedStateConfigMap)
+	default:
+		klog.Errorf("Unknown learned state backend %q, the learned routing state is not persisted", learnedStateBackend)
+		return nil
+	}
+	klog.Infof("Persisting the learned routing state to %s every %v", learnedStateBackend, learnedStateInterval)
+	return persistence.NewPersister(store, backend, learnedStateInterval)
+}
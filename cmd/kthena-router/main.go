@@ -18,51 +18,80 @@ package main
 
 import (
 	"context"
-	"flag"
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/spf13/pflag"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
 	"github.com/volcano-sh/kthena/cmd/kthena-router/app"
-	"github.com/volcano-sh/kthena/pkg/kthena-router/webhook"
-	webhookcert "github.com/volcano-sh/kthena/pkg/webhook/cert"
+	apputil "github.com/volcano-sh/kthena/pkg/util/app"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
+	"github.com/volcano-sh/kthena/pkg/webhook"
+	webhookserver "github.com/volcano-sh/kthena/pkg/webhook/server"
 )
 
-const validatingWebhookConfigurationName = "kthena-router-validating-webhook"
+// webhookTimeout bounds the reading and writing of the admission requests.
+const webhookTimeout = 30 * time.Second
 
 func main() {
 	var (
+		bindAddress    string
 		routerPort     string
 		tlsCert        string
 		tlsKey         string
 		enableWebhook  bool
+		webhookBind    string
 		webhookPort    int
 		webhookCert    string
 		webhookKey     string
 		certSecretName string
 		serviceName    string
+		webhookSANs    []string
+		drainDelay     time.Duration
+		drainTimeout   time.Duration
+		adminPort      string
+		adminTokenFile string
+		maxBodyBytes   int64
+		headerTimeout  time.Duration
+		bodyTimeout    time.Duration
+		requestTimeout time.Duration
+		idleTimeout    time.Duration
+		extProcPort    string
 	)
 
-	klog.InitFlags(nil)
-	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	apputil.InitFlags()
+	pflag.StringVar(&bindAddress, "bind-address", "", "IPv4 or IPv6 address the router, admin and external processing servers listen on, all the addresses of both families when empty")
 	pflag.StringVar(&routerPort, "port", "8080", "Server listen port")
 	pflag.StringVar(&tlsCert, "tls-cert", "", "TLS certificate file path")
 	pflag.StringVar(&tlsKey, "tls-key", "", "TLS key file path")
 	pflag.BoolVar(&enableWebhook, "enable-webhook", true, "Enable built-in admission webhook server")
+	pflag.StringVar(&webhookBind, "webhook-bind-address", "", "IPv4 or IPv6 address the webhook server listens on, all the addresses of both families when empty")
 	pflag.IntVar(&webhookPort, "webhook-port", 8443, "The port for the webhook server")
 	pflag.StringVar(&webhookCert, "webhook-tls-cert-file", "/etc/tls/tls.crt", "Path to the webhook TLS certificate file")
 	pflag.StringVar(&webhookKey, "webhook-tls-private-key-file", "/etc/tls/tls.key", "Path to the webhook TLS private key file")
 	pflag.StringVar(&certSecretName, "cert-secret-name", "kthena-router-webhook-certs", "Name of the secret to store auto-generated webhook certificates")
 	pflag.StringVar(&serviceName, "webhook-service-name", "kthena-router-webhook", "Service name for the webhook server")
+	pflag.StringSliceVar(&webhookSANs, "webhook-cert-extra-sans", nil, "Additional DNS names or IPv4 and IPv6 addresses of the auto-generated webhook certificate")
+	pflag.DurationVar(&drainDelay, "drain-delay", app.DefaultDrainDelay, "Time left on shutdown for the router to be removed from the service endpoints before it stops accepting connections")
+	pflag.DurationVar(&drainTimeout, "drain-timeout", app.DefaultDrainTimeout, "Time the in-flight requests, streams included, are waited for on shutdown before they are closed")
+	pflag.StringVar(&adminPort, "admin-port", app.DefaultAdminPort, "The port of the admin API, served when ROUTER_ADMIN_API_ENABLED is true")
+	pflag.StringVar(&adminTokenFile, "admin-token-file", app.DefaultAdminTokenFile, "Path to the file holding the bearer token of the admin API")
+	pflag.Int64Var(&maxBodyBytes, "max-request-body-bytes", app.DefaultMaxRequestBodyBytes, "Largest request body accepted, larger ones are rejected with 413, 0 disables the limit")
+	pflag.DurationVar(&headerTimeout, "read-header-timeout", app.DefaultReadHeaderTimeout, "Time a client has to send the request headers")
+	pflag.DurationVar(&bodyTimeout, "request-body-timeout", app.DefaultBodyReadTimeout, "Time a client has to send the request body, slower clients are rejected with 408, 0 disables the timeout")
+	pflag.DurationVar(&requestTimeout, "request-timeout", 0, "Time a request, streams included, is served for before it is cancelled, 0 disables the timeout")
+	pflag.DurationVar(&idleTimeout, "idle-timeout", app.DefaultIdleTimeout, "Time an idle keep-alive client connection is kept open")
+	pflag.StringVar(&extProcPort, "ext-proc-port", "", "The port of the Envoy external processing gRPC API picking the endpoints of the requests of a gateway, e.g. 9002, not served when empty")
 	defer klog.Flush()
 	pflag.Parse()
+	if _, err := apputil.ApplyComponentConfig("kthena-router"); err != nil {
+		klog.Fatal(err)
+	}
 
 	if (tlsCert != "" && tlsKey == "") || (tlsCert == "" && tlsKey != "") {
 		klog.Fatal("tls-cert and tls-key must be specified together")
@@ -72,122 +101,82 @@ func main() {
 		klog.Fatalf("invalid webhook port: %d", webhookPort)
 	}
 
-	pflag.CommandLine.VisitAll(func(f *pflag.Flag) {
-		klog.Infof("Flag: %s, Value: %s", f.Name, f.Value.String())
-	})
+	for _, address := range []string{bindAddress, webhookBind} {
+		if err := netutil.ValidateBindAddress(address); err != nil {
+			klog.Fatal(err)
+		}
+	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	if drainDelay < 0 || drainTimeout < 0 {
+		klog.Fatal("drain-delay and drain-timeout must not be negative")
+	}
 
-	signalCh := make(chan os.Signal, 1)
-	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		<-signalCh
-		klog.Info("Received termination, signaling shutdown")
-		cancel()
-	}()
+	if maxBodyBytes < 0 || headerTimeout < 0 || bodyTimeout < 0 || requestTimeout < 0 || idleTimeout < 0 {
+		klog.Fatal("max-request-body-bytes, read-header-timeout, request-body-timeout, request-timeout and idle-timeout must not be negative")
+	}
 
+	server := app.NewServer(routerPort, tlsCert != "" && tlsKey != "", tlsCert, tlsKey)
+	server.BindAddress = bindAddress
+	server.DrainDelay = drainDelay
+	server.DrainTimeout = drainTimeout
+	server.AdminPort = adminPort
+	server.AdminTokenFile = adminTokenFile
+	server.ReadHeaderTimeout = headerTimeout
+	server.IdleTimeout = idleTimeout
+	server.Limits.MaxRequestBodyBytes = maxBodyBytes
+	server.Limits.BodyReadTimeout = bodyTimeout
+	server.Limits.RequestTimeout = requestTimeout
+	server.ExtProcPort = extProcPort
+	components := []apputil.Component{
+		apputil.NewComponent("router", func(ctx context.Context) error {
+			server.Run(ctx)
+			return nil
+		}),
+	}
 	if enableWebhook {
-		go runWebhook(ctx, webhookPort, webhookCert, webhookKey, certSecretName, serviceName)
+		components = append(components, apputil.NewComponent("webhook server", func(ctx context.Context) error {
+			return runWebhook(ctx, webhookserver.Config{
+				BindAddress:    webhookBind,
+				Port:           webhookPort,
+				CertFile:       webhookCert,
+				KeyFile:        webhookKey,
+				Timeout:        webhookTimeout,
+				Namespace:      os.Getenv("POD_NAMESPACE"),
+				CertSecretName: certSecretName,
+				ServiceName:    serviceName,
+				CertExtraSANs:  webhookSANs,
+			})
+		}))
 	} else {
 		klog.Info("Webhook server is disabled")
 	}
-
-	app.NewServer(routerPort, tlsCert != "" && tlsKey != "", tlsCert, tlsKey).Run(ctx)
-}
-
-// ensureWebhookCertificate generates a certificate secret if needed and returns the CA bundle.
-func ensureWebhookCertificate(ctx context.Context, kubeClient kubernetes.Interface, secretName, serviceName string) ([]byte, error) {
-	namespace := getNamespace()
-	dnsNames := []string{
-		fmt.Sprintf("%s.%s.svc", serviceName, namespace),
-		fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, namespace),
+	if err := apputil.Run(context.Background(), components...); err != nil {
+		klog.Flush()
+		os.Exit(1)
 	}
-	klog.Infof("Auto-generating certificate for webhook server (secret=%s service=%s)", secretName, serviceName)
-	return webhookcert.EnsureCertificate(ctx, kubeClient, namespace, secretName, dnsNames)
 }
 
-// runWebhook starts the webhook server and manages certificate acquisition with precedence:
+// runWebhook serves the networking admission webhooks, and manages certificate acquisition with precedence:
 // Secret -> existing cert files -> auto-generate new certs.
-func runWebhook(ctx context.Context, port int, certFile, keyFile, secretName, serviceName string) {
+func runWebhook(ctx context.Context, webhookConfig webhookserver.Config) error {
 	config, err := rest.InClusterConfig()
 	if err != nil {
-		klog.Fatalf("Failed to get kube config: %v", err)
+		return fmt.Errorf("failed to get kube config: %v", err)
 	}
 	kubeClient, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		klog.Fatalf("Failed to get kube client: %v", err)
-	}
-
-	namespace := getNamespace()
-	var caBundle []byte
-
-	// 1. Try secret first.
-	if bundle, err := webhookcert.LoadCertBundleFromSecret(ctx, kubeClient, namespace, secretName); err != nil {
-		klog.Warningf("Error reading CA bundle from secret %s: %v", secretName, err)
-	} else if bundle != nil {
-		klog.Infof("Loaded CA bundle from secret %s", secretName)
-		caBundle = bundle.CAPEM
-	}
-
-	// 2. If not from secret, try existing cert file.
-	if caBundle == nil {
-		if !fileExists(certFile) || !fileExists(keyFile) {
-			b, err := ensureWebhookCertificate(ctx, kubeClient, secretName, serviceName)
-			if err != nil {
-				klog.Fatalf("Failed to auto-generate webhook certificates: %v", err)
-			}
-			caBundle = b
-		}
+		return fmt.Errorf("failed to get kube client: %v", err)
 	}
-
-	if caBundle != nil {
-		// attempt to update the ValidatingWebhookConfiguration CA bundle.
-		if err := webhookcert.UpdateValidatingWebhookCABundle(ctx, kubeClient, validatingWebhookConfigurationName, caBundle); err != nil {
-			klog.Warningf("Failed to update ValidatingWebhookConfiguration CA bundle: %v", err)
-		} else {
-			klog.Infof("Updated ValidatingWebhookConfiguration %s CA bundle", validatingWebhookConfigurationName)
-		}
-	}
-
-	validator := webhook.NewKthenaRouterValidator(kubeClient, port)
-
-	// Wait for both cert and key files to exist (in case they are mounted by Kubernetes)
-	ok := waitForCertsReady(keyFile, certFile)
-	if !ok {
-		klog.Fatalf("TLS cert/key files not found, webhook server cannot start")
-		return
-	}
-	go validator.Run(ctx, certFile, keyFile)
-	klog.Infof("Webhook server running on port %d", port)
-}
-
-func waitForCertsReady(keyFile, CertFile string) bool {
-	waitTimeout := 30 * time.Second
-	waitInterval := 500 * time.Millisecond
-	start := time.Now()
-	for {
-		if fileExists(CertFile) && fileExists(keyFile) {
-			return true
-		}
-		if time.Since(start) > waitTimeout {
-			klog.Warningf("timeout waiting for TLS cert/key files to appear at %s and %s", keyFile, CertFile)
-			return false
-		}
-		time.Sleep(waitInterval)
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to get dynamic client: %v", err)
 	}
-}
 
-// getNamespace returns the current pod namespace or "default".
-func getNamespace() string {
-	return os.Getenv("POD_NAMESPACE")
-}
-
-// fileExists returns true if the file exists.
-func fileExists(path string) bool {
-	if path == "" {
-		return false
+	server := webhookserver.New(webhookConfig, kubeClient, dynamicClient)
+	set, err := webhook.Lookup(webhook.Networking)
+	if err != nil {
+		return err
 	}
-	_, err := os.Stat(path)
-	return err == nil
+	set.Register(server, webhook.Clients{Kube: kubeClient})
+	return server.Run(ctx)
 }
@@ -0,0 +1,102 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/tokenizer/api/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/tokenizer/server"
+	"github.com/volcano-sh/kthena/pkg/util/app"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
+)
+
+func main() {
+	var (
+		bindAddress string
+		port        int
+		configFile  string
+	)
+
+	app.InitFlags()
+	pflag.StringVar(&bindAddress, "bind-address", "", "IPv4 or IPv6 address the gRPC tokenizer service listens on, all the addresses of both families when empty")
+	pflag.IntVar(&port, "port", 9090, "The port the gRPC tokenizer service listens on")
+	pflag.StringVar(&configFile, "config", "/etc/kthena/tokenizer-server.yaml", "Path to the file configuring the tokenizers of the models")
+	defer klog.Flush()
+	pflag.Parse()
+	if _, err := app.ApplyComponentConfig("kthena-tokenizer-server"); err != nil {
+		klog.Fatal(err)
+	}
+
+	if port <= 0 || port > 65535 {
+		klog.Fatalf("invalid port: %d", port)
+	}
+	if err := netutil.ValidateBindAddress(bindAddress); err != nil {
+		klog.Fatal(err)
+	}
+
+	config, err := server.LoadConfig(configFile)
+	if err != nil {
+		klog.Fatalf("Failed to load config: %v", err)
+	}
+	tokenizerServer, err := server.NewServer(config)
+	if err != nil {
+		klog.Fatalf("Failed to create tokenizer server: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", netutil.ListenAddress(bindAddress, strconv.Itoa(port)))
+	if err != nil {
+		klog.Fatalf("listen failed: %v", err)
+	}
+
+	grpcServer := grpc.NewServer()
+	v1alpha1.RegisterTokenizerServer(grpcServer, tokenizerServer)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	err = app.Run(context.Background(), app.NewComponent("tokenizer server", func(ctx context.Context) error {
+		errCh := make(chan error, 1)
+		go func() {
+			klog.Infof("Tokenizer server listening on port %d", port)
+			if err := grpcServer.Serve(listener); err != nil {
+				errCh <- err
+			}
+		}()
+		select {
+		case err := <-errCh:
+			return fmt.Errorf("serve failed: %v", err)
+		case <-ctx.Done():
+		}
+		healthServer.Shutdown()
+		grpcServer.GracefulStop()
+		return nil
+	}))
+	if err != nil {
+		klog.Flush()
+		os.Exit(1)
+	}
+}
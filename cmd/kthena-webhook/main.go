@@ -0,0 +1,130 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
+	"github.com/volcano-sh/kthena/pkg/util/app"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
+	"github.com/volcano-sh/kthena/pkg/webhook"
+	webhookserver "github.com/volcano-sh/kthena/pkg/webhook/server"
+)
+
+// kthena-webhook serves the admission webhooks of kthena from a single deployment, instead of
+// the webhook servers embedded in kthena-controller-manager and kthena-router.
+func main() {
+	var (
+		kubeconfig     string
+		masterURL      string
+		webhooks       []string
+		bindAddress    string
+		port           int
+		certFile       string
+		keyFile        string
+		webhookTimeout time.Duration
+		certSecretName string
+		serviceName    string
+		certExtraSANs  []string
+	)
+
+	app.InitFlags()
+	pflag.StringVar(&kubeconfig, "kubeconfig", "", "kubeconfig file path")
+	pflag.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	pflag.StringSliceVar(&webhooks, "webhooks", webhook.Names(), "The sets of webhooks to serve, the sets whose API is not installed are skipped")
+	pflag.StringVar(&bindAddress, "bind-address", "", "IPv4 or IPv6 address the webhook listens on, all the addresses of both families when empty")
+	pflag.IntVar(&port, "port", 8443, "Secure port that the webhook listens on")
+	pflag.StringVar(&certFile, "tls-cert-file", "/etc/tls/tls.crt", "File containing the x509 Certificate for HTTPS")
+	pflag.StringVar(&keyFile, "tls-private-key-file", "/etc/tls/tls.key", "File containing the x509 private key to --tls-cert-file")
+	pflag.DurationVar(&webhookTimeout, "webhook-timeout", 30*time.Second, "Timeout for reading and writing the admission requests")
+	pflag.StringVar(&certSecretName, "cert-secret-name", "kthena-webhook-certs", "Name of the secret to store auto-generated certificates")
+	pflag.StringVar(&serviceName, "service-name", "kthena-webhook", "Service name for the webhook server")
+	pflag.StringSliceVar(&certExtraSANs, "cert-extra-sans", nil, "Additional DNS names or IPv4 and IPv6 addresses of the auto-generated certificate")
+	defer klog.Flush()
+	pflag.Parse()
+	if _, err := app.ApplyComponentConfig("kthena-webhook"); err != nil {
+		klog.Fatal(err)
+	}
+
+	if port <= 0 || port > 65535 {
+		klog.Fatalf("invalid port: %d", port)
+	}
+	if err := netutil.ValidateBindAddress(bindAddress); err != nil {
+		klog.Fatal(err)
+	}
+	sets := make([]webhook.Set, 0, len(webhooks))
+	for _, name := range webhooks {
+		set, err := webhook.Lookup(name)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		sets = append(sets, set)
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
+	if err != nil {
+		klog.Fatalf("build client config: %v", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		klog.Fatalf("failed to create kubeClient: %v", err)
+	}
+	kthenaClient, err := clientset.NewForConfig(config)
+	if err != nil {
+		klog.Fatalf("failed to create kthenaClient: %v", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		klog.Fatalf("failed to create dynamicClient: %v", err)
+	}
+
+	server := webhookserver.New(webhookserver.Config{
+		BindAddress:    bindAddress,
+		Port:           port,
+		CertFile:       certFile,
+		KeyFile:        keyFile,
+		Timeout:        webhookTimeout,
+		Namespace:      os.Getenv("POD_NAMESPACE"),
+		CertSecretName: certSecretName,
+		ServiceName:    serviceName,
+		CertExtraSANs:  certExtraSANs,
+	}, kubeClient, dynamicClient)
+	clients := webhook.Clients{Kube: kubeClient, Kthena: kthenaClient}
+	for _, set := range sets {
+		// The CRDs of a set may not be installed, e.g. when only the networking chart is deployed.
+		if _, err := kubeClient.Discovery().ServerResourcesForGroupVersion(set.GroupVersion); err != nil {
+			klog.Warningf("Skipping the %s webhooks, %s is not served: %v", set.Name, set.GroupVersion, err)
+			continue
+		}
+		klog.Infof("Serving the %s webhooks", set.Name)
+		set.Register(server, clients)
+	}
+
+	if err := app.Run(context.Background(), server); err != nil {
+		klog.Flush()
+		os.Exit(1)
+	}
+}
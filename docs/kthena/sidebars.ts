@@ -50,7 +50,10 @@ const sidebars: SidebarsConfig = {
         'user-guide/config-router',
         'user-guide/autoscaler',
         'user-guide/rate-limit',
+        'user-guide/guardrails',
+        'user-guide/batch-inference',
         'user-guide/runtime',
+        'user-guide/multi-tenancy',
         'user-guide/gateway-inference-extension-support',
         {
           type: 'category',
@@ -68,7 +71,7 @@ const sidebars: SidebarsConfig = {
     {
       type: 'category',
       label: 'General',
-      items: ['general/prometheus', 'general/cert-manager', 'general/faq'],
+      items: ['general/prometheus', 'general/cert-manager', 'general/component-config', 'general/faq'],
     },
     {
       type: 'category',
@@ -78,6 +81,9 @@ const sidebars: SidebarsConfig = {
         'developer-guide/model-serving-rolling-update',
         'developer-guide/ci',
         'developer-guide/model-serving-scaling',
+        'developer-guide/fake-engine',
+        'developer-guide/benchmarks',
+        'developer-guide/api-versions',
       ],
     },
     {
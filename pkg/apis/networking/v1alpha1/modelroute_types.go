@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -47,6 +48,29 @@ type ModelRouteSpec struct {
 	// There is no limitation if this field is not set.
 	// +optional
 	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+
+	// TrafficCompare replays a sample of the requests of this route to a baseline and a candidate
+	// model server out-of-band, so that their responses can be compared before the candidate is promoted.
+	// The responses of the replayed requests are never returned to the client.
+	// +optional
+	TrafficCompare *TrafficCompare `json:"trafficCompare,omitempty"`
+
+	// Guardrails check the content of the requests of this route before they are routed, and of their
+	// non-streaming responses before they are returned, to reject or annotate the unsafe ones.
+	// +optional
+	Guardrails *Guardrails `json:"guardrails,omitempty"`
+
+	// Concurrency caps the requests of the model served concurrently by each router replica. The excess
+	// requests wait for a slot in a bounded FIFO queue, and spill over to the fallback model when the queue
+	// is full or they waited too long. There is no limit if this field is not set.
+	// +optional
+	Concurrency *ConcurrencyLimit `json:"concurrency,omitempty"`
+
+	// Fallback is the ordered chain of models serving the requests of the route while its model is degraded:
+	// its model server has no pods, none of them can be scheduled, or it exceeds its latency budget.
+	// The model serving the request is returned in the X-Kthena-Served-Model response header.
+	// +optional
+	Fallback *FallbackChain `json:"fallback,omitempty"`
 }
 
 type Rule struct {
@@ -59,6 +83,11 @@ type Rule struct {
 	ModelMatch *ModelMatch `json:"modelMatch,omitempty"`
 	// +kubebuilder:validation:MaxItems=16
 	TargetModels []*TargetModel `json:"targetModels"`
+	// Mirror sends a copy of a percentage of the requests matching the rule to a shadow model server,
+	// e.g. running a new model version, so that it is validated with real traffic. The responses of the
+	// mirrored requests are never returned to the client.
+	// +optional
+	Mirror *TrafficMirror `json:"mirror,omitempty"`
 }
 
 // ModelMatch defines the predicate used to match LLM inference requests to a given
@@ -127,12 +156,83 @@ type RateLimit struct {
 	// +kubebuilder:default=second
 	// +kubebuilder:validation:Enum=second;minute;hour;day;month
 	Unit RateLimitUnit `json:"unit"`
+	// MaxConcurrentStreams is the maximum number of streaming requests served concurrently for the model.
+	// When Global is set, the limit is shared by all router replicas and each replica is admitted
+	// up to its fair share of the limit. If this field is not set, there is no limit on concurrent streams.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentStreams *uint32 `json:"maxConcurrentStreams,omitempty"`
 	// Global contains configuration for global rate limiting using distributed storage.
 	// If this field is set, global rate limiting will be used; otherwise, local rate limiting will be used.
 	// +optional
 	Global *GlobalRateLimit `json:"global,omitempty"`
 }
 
+// ConcurrencyLimit bounds the requests of a model in flight in a router replica.
+type ConcurrencyLimit struct {
+	// MaxConcurrentRequests is the maximum number of requests of the model proxied concurrently by a router replica.
+	//
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentRequests uint32 `json:"maxConcurrentRequests"`
+	// MaxQueueSize is the maximum number of requests waiting for a slot, the requests arriving once it is full
+	// are not queued. 0 disables the queue.
+	//
+	// +optional
+	// +kubebuilder:default=100
+	MaxQueueSize *uint32 `json:"maxQueueSize,omitempty"`
+	// MaxQueueWait is the time a request may wait for a slot, 30s by default.
+	// +optional
+	MaxQueueWait *metav1.Duration `json:"maxQueueWait,omitempty"`
+	// FallbackModel is the model the requests which can not be queued, or waited too long, are sent to instead,
+	// for instance a smaller distilled model. It must be routed by another ModelRoute, its own concurrency
+	// limit applies without spilling over again. The requests are rejected with an HTTP 429 status code
+	// if this field is not set.
+	// +optional
+	FallbackModel string `json:"fallbackModel,omitempty"`
+}
+
+// FallbackChain lists the models the requests fall back to, in order.
+type FallbackChain struct {
+	// LatencyBudget is the time to first token the model server of the route may take. The model is degraded
+	// when the 90th percentile of its recent times to first token, observed by the router, exceeds it. A small
+	// share of the requests is still sent to a degraded model, so that its recovery is observed.
+	// +optional
+	LatencyBudget *metav1.Duration `json:"latencyBudget,omitempty"`
+	// Targets are tried in order, the request is served by the first one which is not degraded.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=8
+	Targets []FallbackTarget `json:"targets"`
+}
+
+// FallbackTarget is a model routed by another ModelRoute, or an external API. Exactly one of ModelName and
+// External must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.modelName) != has(self.external)", message="exactly one of modelName and external must be set"
+type FallbackTarget struct {
+	// ModelName is a model routed by another ModelRoute. It is not degraded when its own model server
+	// has pods and is within the latency budget of its ModelRoute, its own fallback chain is not followed.
+	// +optional
+	ModelName string `json:"modelName,omitempty"`
+	// External is an OpenAI compatible API outside of the cluster, which is never considered degraded.
+	// +optional
+	External *ExternalModel `json:"external,omitempty"`
+}
+
+// ExternalModel is a model served by an OpenAI compatible API.
+type ExternalModel struct {
+	// URL is the base URL of the API, such as https://api.openai.com. The path of the request is appended to it.
+	//
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+	// Model is the model requested from the API, the model of the request by default.
+	// +optional
+	Model string `json:"model,omitempty"`
+	// APIKeySecretRef selects the key of a Secret in the namespace of the ModelRoute holding the API key,
+	// sent as a bearer token.
+	// +optional
+	APIKeySecretRef *corev1.SecretKeySelector `json:"apiKeySecretRef,omitempty"`
+}
+
 // GlobalRateLimit contains configuration for global rate limiting
 type GlobalRateLimit struct {
 	// Redis contains configuration for Redis-based global rate limiting.
@@ -147,6 +247,137 @@ type RedisConfig struct {
 	Address string `json:"address"`
 }
 
+// TrafficCompare defines the model servers whose responses are compared for sampled requests.
+// +kubebuilder:validation:XValidation:rule="self.baselineModelServerName != self.candidateModelServerName", message="baselineModelServerName and candidateModelServerName must be different"
+type TrafficCompare struct {
+	// BaselineModelServerName is the model server running the current model revision, within the same namespace.
+	//
+	// +kubebuilder:validation:MinLength=1
+	BaselineModelServerName string `json:"baselineModelServerName"`
+	// CandidateModelServerName is the model server running the candidate model revision, within the same namespace.
+	//
+	// +kubebuilder:validation:MinLength=1
+	CandidateModelServerName string `json:"candidateModelServerName"`
+	// SamplePercent is the percentage of non-streaming requests of the route that are replayed to both model servers.
+	// The value should be in the range of [1, 100].
+	//
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	SamplePercent *uint32 `json:"samplePercent,omitempty"`
+}
+
+// TrafficMirror defines the shadow model server the requests of a rule are mirrored to.
+type TrafficMirror struct {
+	// ModelServerName is the shadow model server, within the same namespace.
+	//
+	// +kubebuilder:validation:MinLength=1
+	ModelServerName string `json:"modelServerName"`
+	// Percent is the percentage of the requests of the rule that are mirrored.
+	// The value should be in the range of [1, 100].
+	//
+	// +optional
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percent *uint32 `json:"percent,omitempty"`
+	// ComparePercent is the percentage of the mirrored non-streaming requests whose responses from both model
+	// servers are compared, in the traffic compare report of the ModelRoute. None are compared when unset.
+	// The value should be in the range of [0, 100].
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	ComparePercent *uint32 `json:"comparePercent,omitempty"`
+}
+
+// Guardrails defines the content filters applied to the requests and responses of a route.
+type Guardrails struct {
+	// Filters are run in order, the first filter rejecting the content stops the chain.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	Filters []GuardrailFilter `json:"filters"`
+}
+
+// GuardrailFilter flags the content matching keywords or regular expressions, or classified as unsafe
+// by an external HTTP service. Exactly one of Keywords, Regex and HTTP must be set.
+// +kubebuilder:validation:XValidation:rule="(has(self.keywords) ? 1 : 0) + (has(self.regex) ? 1 : 0) + (has(self.http) ? 1 : 0) == 1", message="exactly one of keywords, regex and http must be set"
+type GuardrailFilter struct {
+	// Name identifies the filter in the responses and the metrics.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Stages is where the filter runs, on the prompt of the requests and/or on the content of the responses.
+	//
+	// +optional
+	// +kubebuilder:default={request}
+	Stages []GuardrailStage `json:"stages,omitempty"`
+	// Action is what happens to the flagged content. Rejected requests and responses are answered with
+	// an HTTP 400 status code, annotated ones are served with the name of the filter in a response header.
+	//
+	// +optional
+	// +kubebuilder:default=reject
+	Action GuardrailAction `json:"action,omitempty"`
+	// Keywords flags the content containing any of the keywords.
+	// +optional
+	Keywords *KeywordGuardrail `json:"keywords,omitempty"`
+	// Regex flags the content matching any of the regular expressions.
+	// +optional
+	Regex *RegexGuardrail `json:"regex,omitempty"`
+	// HTTP flags the content classified as unsafe by an external classifier.
+	// +optional
+	HTTP *HTTPGuardrail `json:"http,omitempty"`
+}
+
+// KeywordGuardrail matches the content against a list of keywords.
+type KeywordGuardrail struct {
+	// +kubebuilder:validation:MinItems=1
+	Keywords []string `json:"keywords"`
+	// CaseSensitive makes the keywords match only with the same case.
+	// +optional
+	CaseSensitive bool `json:"caseSensitive,omitempty"`
+}
+
+// RegexGuardrail matches the content against regular expressions in the RE2 syntax.
+type RegexGuardrail struct {
+	// +kubebuilder:validation:MinItems=1
+	Patterns []string `json:"patterns"`
+}
+
+// HTTPGuardrail sends the content to an external classifier. The classifier receives a POST request with
+// the JSON body {"model": ..., "stage": ..., "content": ...} and answers with {"flagged": bool, "reason": string}.
+type HTTPGuardrail struct {
+	// URL of the classifier endpoint.
+	//
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+	// Timeout of the classifier requests, 1s by default.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// FailOpen lets the content through when the classifier fails, otherwise the request is answered
+	// with an HTTP 503 status code.
+	// +optional
+	FailOpen bool `json:"failOpen,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=request;response
+type GuardrailStage string
+
+const (
+	GuardrailStageRequest  GuardrailStage = "request"
+	GuardrailStageResponse GuardrailStage = "response"
+)
+
+// +kubebuilder:validation:Enum=reject;annotate
+type GuardrailAction string
+
+const (
+	GuardrailActionReject   GuardrailAction = "reject"
+	GuardrailActionAnnotate GuardrailAction = "annotate"
+)
+
 // +kubebuilder:validation:Enum=second;minute;hour;day;month
 type RateLimitUnit string
 
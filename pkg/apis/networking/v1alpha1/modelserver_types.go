@@ -47,11 +47,168 @@ type ModelServerSpec struct {
 	// KVConnector specifies the KV connector configuration for PD disaggregated routing
 	// +optional
 	KVConnector *KVConnectorSpec `json:"kvConnector,omitempty"`
+
+	// SchedulingPolicy tunes how the router picks the pods of the model server.
+	// By default, the scores of the plugins configured in the router are summed up as they are.
+	// +optional
+	SchedulingPolicy *SchedulingPolicy `json:"schedulingPolicy,omitempty"`
+
+	// SLO declares the service level objectives of the model server. The router measures the requests it
+	// serves against them, and exports their compliance and the remaining error budget.
+	// +optional
+	SLO *ServiceLevelObjective `json:"slo,omitempty"`
 }
 
+// ServiceLevelObjective declares the share of the requests of a model server which must be served
+// successfully, and quickly, over a rolling window.
+type ServiceLevelObjective struct {
+	// Window is the rolling window the objectives are measured over.
+	// +optional
+	// +kubebuilder:default="720h"
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// Availability is the objective for the requests served without a server error.
+	// +optional
+	Availability *AvailabilityObjective `json:"availability,omitempty"`
+
+	// Latency is the objective for the requests whose response starts within a threshold.
+	// +optional
+	Latency *LatencyObjective `json:"latency,omitempty"`
+}
+
+// AvailabilityObjective is met by the requests answered without a 5xx status, the requests the router
+// fails to schedule or to proxy to a pod miss it.
+type AvailabilityObjective struct {
+	// Target is the percentage of the requests which must meet the objective, e.g. "99.9".
+	// +kubebuilder:validation:Pattern=`^[0-9]{1,2}(\.[0-9]+)?$`
+	Target string `json:"target"`
+}
+
+// LatencyObjective is met by the requests whose first byte of response is sent within the threshold,
+// which is the time to first token of the streaming requests.
+type LatencyObjective struct {
+	// Target is the percentage of the requests which must meet the objective, e.g. "99".
+	// +kubebuilder:validation:Pattern=`^[0-9]{1,2}(\.[0-9]+)?$`
+	Target string `json:"target"`
+
+	// Threshold is the latency of the first byte of the response.
+	Threshold metav1.Duration `json:"threshold"`
+}
+
+// SchedulingPolicy defines how the scores of the router score plugins are combined for a model server.
+// The scores of each plugin are normalized to [0, 100] across the candidate pods before they are weighted,
+// so that the weights reflect the relative importance of the objectives.
+type SchedulingPolicy struct {
+	// Mode selects how the pods are picked once the filter plugins have run.
+	// Score ranks the pods with the score plugins, ConsistentHash maps the prompt of the request to a pod
+	// with a consistent hash ring, which is cheaper and suits the embedding and rerank models.
+	// +optional
+	// +kubebuilder:default=Score
+	Mode SchedulingMode `json:"mode,omitempty"`
+
+	// ConsistentHash tunes the ConsistentHash mode.
+	// +optional
+	ConsistentHash *ConsistentHash `json:"consistentHash,omitempty"`
+
+	// ScoreWeights are the weights of the score plugins. Score plugins which are enabled in the router
+	// but not listed here are left out. Defaults to kvcache-aware 50, least-request 30 and least-latency 20.
+	// +optional
+	// +listType=map
+	// +listMapKey=plugin
+	ScoreWeights []ScoreWeight `json:"scoreWeights,omitempty"`
+
+	// TieBreak selects among the pods with the same score.
+	// +optional
+	// +kubebuilder:default=Random
+	TieBreak TieBreakPolicy `json:"tieBreak,omitempty"`
+
+	// Accelerators selects the hardware classes of the pods serving the model, from the labels of their nodes.
+	// +optional
+	Accelerators *AcceleratorPolicy `json:"accelerators,omitempty"`
+}
+
+// AcceleratorPolicy requires or prefers hardware classes for the pods of a model server, e.g. H100 for a 70B model,
+// when its pods run in pools of different accelerators. A class matches the accelerator of a pod when it is the
+// accelerator, or one of the words of its name, ignoring the case: H100 matches NVIDIA-H100-80GB-HBM3.
+type AcceleratorPolicy struct {
+	// Required are the classes the pods must have. The pods of other or unknown accelerators are not scheduled.
+	// +optional
+	Required []string `json:"required,omitempty"`
+
+	// Preferred are the classes preferred by the accelerator score plugin, the other pods are used when they are busy.
+	// +optional
+	Preferred []string `json:"preferred,omitempty"`
+
+	// Costs are the relative costs of the classes. When several classes can serve the model, the accelerator
+	// score plugin favors the cheaper ones, the pods of classes without a cost score like the most expensive.
+	// +optional
+	// +listType=map
+	// +listMapKey=accelerator
+	Costs []AcceleratorCost `json:"costs,omitempty"`
+}
+
+// AcceleratorCost is the relative cost of a hardware class.
+type AcceleratorCost struct {
+	// Accelerator is the hardware class, e.g. A100, H100 or Ascend910B.
+	// +kubebuilder:validation:MinLength=1
+	Accelerator string `json:"accelerator"`
+
+	// Cost is the relative cost of the class, e.g. its hourly price.
+	// +kubebuilder:validation:Minimum=0
+	Cost int32 `json:"cost"`
+}
+
+// ScoreWeight is the weight of a score plugin.
+type ScoreWeight struct {
+	// Plugin is the name of the score plugin, e.g. kvcache-aware, least-request or least-latency.
+	// +kubebuilder:validation:MinLength=1
+	Plugin string `json:"plugin"`
+
+	// Weight is the relative weight of the plugin.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
+}
+
+// SchedulingMode selects how the pods of a model server are picked.
+//
+// +kubebuilder:validation:Enum=Score;ConsistentHash
+type SchedulingMode string
+
+const (
+	// SchedulingModeScore picks the pods with the highest scores of the score plugins.
+	SchedulingModeScore SchedulingMode = "Score"
+	// SchedulingModeConsistentHash picks the pod the prompt hashes to, the next pods on the ring
+	// taking over when it is overloaded. The score plugins are not run.
+	SchedulingModeConsistentHash SchedulingMode = "ConsistentHash"
+)
+
+// ConsistentHash bounds the load of the pods in the ConsistentHash mode. A pod whose running and waiting
+// requests exceed maxLoadPercent of the average of the pods is skipped, and the request goes to the next
+// pod on the ring.
+type ConsistentHash struct {
+	// MaxLoadPercent is the load a pod may take, in percent of the average load of the pods.
+	// +optional
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:default=125
+	MaxLoadPercent int32 `json:"maxLoadPercent,omitempty"`
+}
+
+// TieBreakPolicy selects among the pods with the same score.
+//
+// +kubebuilder:validation:Enum=Random;LeastRequest
+type TieBreakPolicy string
+
+const (
+	// TieBreakRandom selects randomly among the pods with the same score.
+	TieBreakRandom TieBreakPolicy = "Random"
+	// TieBreakLeastRequest selects the pod with the least running and waiting requests among the pods with the same score.
+	TieBreakLeastRequest TieBreakPolicy = "LeastRequest"
+)
+
 // InferenceEngine defines the inference framework used by the modelServer to serve LLM requests.
 //
-// +kubebuilder:validation:Enum=vLLM;SGLang
+// +kubebuilder:validation:Enum=vLLM;SGLang;TGI;TensorRT-LLM
 type InferenceEngine string
 
 const (
@@ -59,6 +216,10 @@ const (
 	VLLM InferenceEngine = "vLLM"
 	// https://github.com/sgl-project/sglang
 	SGLang InferenceEngine = "SGLang"
+	// https://github.com/huggingface/text-generation-inference
+	TGI InferenceEngine = "TGI"
+	// https://github.com/NVIDIA/TensorRT-LLM
+	TensorRTLLM InferenceEngine = "TensorRT-LLM"
 )
 
 // WorkloadSelector is used to match the model serving instances.
@@ -127,6 +288,9 @@ type TrafficPolicy struct {
 	// The retry policy for the inference request.
 	// +optional
 	Retry *Retry `json:"retry,omitempty"`
+	// Hedging duplicates the requests whose first token is late to the second best pod.
+	// +optional
+	Hedging *Hedging `json:"hedging,omitempty"`
 
 	// TODO: add LoadBalancer policy
 }
@@ -141,10 +305,47 @@ type Retry struct {
 	RetryInterval *metav1.Duration `json:"retryInterval,omitempty"`
 }
 
+// Hedging sends a duplicate of a request to the second best pod when its first token has not arrived within a delay,
+// and uses whichever pod responds first. The request to the other pod is cancelled.
+// The delay is a percentile of the recent times to first token of the model server, bounded by minDelay and maxDelay.
+type Hedging struct {
+	// Percentile of the recent times to first token after which a request is hedged.
+	// +optional
+	// +kubebuilder:validation:Minimum=50
+	// +kubebuilder:validation:Maximum=99
+	// +kubebuilder:default=95
+	Percentile int32 `json:"percentile,omitempty"`
+	// MinDelay is the minimum delay before a request is hedged.
+	// +optional
+	// +kubebuilder:default="100ms"
+	MinDelay *metav1.Duration `json:"minDelay,omitempty"`
+	// MaxDelay is the maximum delay before a request is hedged. It is also the delay until enough times to first token
+	// have been observed.
+	// +optional
+	// +kubebuilder:default="2s"
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+}
+
+type ModelServerConditionType string
+
+// There is a condition type of a modelServer
+const (
+	// ModelServerTokenizerAvailable reports whether the router can tokenize prompts for the model.
+	// When it is false, the kvcache-aware plugin cannot compute KV-cache affinity for the pods of
+	// the ModelServer and scores them with its configured fallback strategy instead.
+	ModelServerTokenizerAvailable ModelServerConditionType = "TokenizerAvailable"
+	// ModelServerErrorBudgetAvailable reports whether the error budgets of the service level objectives
+	// of the ModelServer are left. It is false once one of them is exhausted.
+	ModelServerErrorBudgetAvailable ModelServerConditionType = "ErrorBudgetAvailable"
+)
+
 // ModelServerStatus defines the observed state of ModelServer.
 type ModelServerStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Conditions track the condition of the ModelServer as observed by the router.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
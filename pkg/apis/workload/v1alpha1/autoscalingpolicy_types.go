@@ -37,6 +37,19 @@ type AutoscalingPolicySpec struct {
 	// Behavior defines the scaling behavior for both scale up and scale down.
 	// +optional
 	Behavior AutoscalingPolicyBehavior `json:"behavior"`
+	// Predictive enables predictive scaling, which pre-scales the target ahead of recurring traffic peaks
+	// forecast from the request rate observed in the past seasons. Only applies to scaling configurations.
+	// +optional
+	Predictive *AutoscalingPolicyPredictive `json:"predictive,omitempty"`
+	// VerticalRecommendation enables recommending the GPU memory utilization and tensor parallel size of the
+	// inference engine from its KV-cache and batch metrics. The recommendation is written to the status of the
+	// target ModelServing, it is never applied automatically. Only applies to scaling configurations.
+	// +optional
+	VerticalRecommendation *AutoscalingPolicyVerticalRecommendation `json:"verticalRecommendation,omitempty"`
+	// DryRun makes the autoscaler compute and record the scaling decisions of the bindings of the policy
+	// without scaling their targets, so that a new policy can be validated against the production traffic.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // AutoscalingPolicyMetric defines a metric and its target value for scaling.
@@ -72,7 +85,8 @@ type AutoscalingPolicyScaleUpPolicy struct {
 
 // AutoscalingPolicyStablePolicy defines the policy for stable scaling up or scaling down.
 type AutoscalingPolicyStablePolicy struct {
-	// Instances is the maximum number of instances to scale.
+	// Instances is the maximum number of instances to scale within a Period, for example instances 4 with
+	// a period of 1m limits the velocity to 4 instances per minute.
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:default=1
 	Instances *int32 `json:"instances,omitempty"`
@@ -93,6 +107,12 @@ type AutoscalingPolicyStablePolicy struct {
 	// StabilizationWindow is the time window to stabilize scaling up actions.
 	// +optional
 	StabilizationWindow *metav1.Duration `json:"stabilizationWindow,omitempty"`
+	// TolerancePercent overrides the TolerancePercent of the policy in this direction, so that for example
+	// scaling up reacts to small increases of the load while scaling down waits for a larger decrease.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	TolerancePercent *int32 `json:"tolerancePercent,omitempty"`
 }
 
 // SelectPolicyType defines the type of select olicy.
@@ -123,6 +143,71 @@ type AutoscalingPolicyPanicPolicy struct {
 	PanicModeHold *metav1.Duration `json:"panicModeHold,omitempty"`
 }
 
+// AutoscalingPolicyPredictive defines the policy for predictive scaling.
+// The request rate of the target is recorded per time slot of a season. The request rate expected after Lookahead
+// is forecast as the average of the same slot in the past seasons, and the forecast instances are blended with the
+// instances recommended from the current metrics according to the confidence of the forecast.
+// Predictive scaling only scales up ahead of the reactive recommendation, it never scales below it.
+type AutoscalingPolicyPredictive struct {
+	// RequestMetricName is the name of the counter metric of the requests served by an instance,
+	// for example vllm:request_success_total.
+	RequestMetricName string `json:"requestMetricName"`
+	// TargetRequestRate is the number of requests per second an instance is expected to serve.
+	TargetRequestRate resource.Quantity `json:"targetRequestRate"`
+	// SeasonPeriod is the period after which the traffic profile repeats.
+	// +kubebuilder:default="24h"
+	// +optional
+	SeasonPeriod *metav1.Duration `json:"seasonPeriod,omitempty"`
+	// Seasons is the number of past seasons the forecast is computed from.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=14
+	// +kubebuilder:default=7
+	// +optional
+	Seasons *int32 `json:"seasons,omitempty"`
+	// Lookahead is how far ahead of the forecast traffic the target is scaled, usually the time an instance takes to become ready.
+	// +kubebuilder:default="5m"
+	// +optional
+	Lookahead *metav1.Duration `json:"lookahead,omitempty"`
+	// MinConfidencePercent is the confidence below which the forecast is ignored.
+	// The confidence is lower when fewer past seasons were recorded, or when they disagree.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=50
+	// +optional
+	MinConfidencePercent *int32 `json:"minConfidencePercent,omitempty"`
+}
+
+// AutoscalingPolicyVerticalRecommendation defines how the engine resources of the target are recommended.
+// The peak KV-cache usage and batch size of an instance are observed over a window. When the KV cache is
+// fuller than the target, a higher GPU memory utilization is recommended, or a larger tensor parallel size
+// once the GPU memory is exhausted. When it is far emptier than the target, a lower GPU memory utilization
+// is recommended, or a smaller tensor parallel size once the utilization can not be lowered anymore.
+type AutoscalingPolicyVerticalRecommendation struct {
+	// KVCacheUsageMetricName is the name of the gauge metric of the KV-cache usage of an instance, ranging from 0 to 1.
+	// +kubebuilder:default="vllm:gpu_cache_usage_perc"
+	// +optional
+	KVCacheUsageMetricName string `json:"kvCacheUsageMetricName,omitempty"`
+	// BatchSizeMetricName is the name of the gauge metric of the requests running in a batch of an instance.
+	// +kubebuilder:default="vllm:num_requests_running"
+	// +optional
+	BatchSizeMetricName string `json:"batchSizeMetricName,omitempty"`
+	// GPUMemoryUsageMetricName is the name of the gauge metric of the used fraction of the GPU memory of an instance,
+	// ranging from 0 to 1. When it is set, a larger tensor parallel size is recommended instead of a higher GPU
+	// memory utilization once the GPU memory is exhausted.
+	// +optional
+	GPUMemoryUsageMetricName string `json:"gpuMemoryUsageMetricName,omitempty"`
+	// TargetKVCacheUsagePercent is the peak KV-cache usage an instance is sized for.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=80
+	// +optional
+	TargetKVCacheUsagePercent *int32 `json:"targetKVCacheUsagePercent,omitempty"`
+	// Window is the duration the peak usage is observed over before a recommendation is made.
+	// +kubebuilder:default="1h"
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+}
+
 // AutoscalingPolicyStatus defines the observed state of AutoscalingPolicy.
 type AutoscalingPolicyStatus struct {
 }
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -79,6 +80,12 @@ type OptimizerConfiguration struct {
 type Target struct {
 	// TargetRef references the target object.
 	TargetRef corev1.ObjectReference `json:"targetRef"`
+	// RoleName is the name of a role of the target ModelServing, e.g. prefill or decode.
+	// When set, the replicas of the role are scaled instead of the replicas of the ModelServing,
+	// and metrics are only collected from the entry pods of the role.
+	// Only supported in scalingConfiguration.
+	// +optional
+	RoleName string `json:"roleName,omitempty"`
 	// AdditionalMatchLabels is the additional labels to match the target object.
 	// +optional
 	AdditionalMatchLabels map[string]string `json:"additionalMatchLabels,omitempty"`
@@ -120,8 +127,78 @@ type AutoscalingPolicyBinding struct {
 
 // AutoscalingPolicyBindingStatus defines the status of a autoscaling policy binding.
 type AutoscalingPolicyBindingStatus struct {
+	// LastScaleTime is the last time the autoscaler scaled the targets.
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+	// Decisions are the latest decisions of the autoscaler, the most recent last. A decision identical to
+	// the previous one but for its time and metric values is not recorded again.
+	// +optional
+	// +listType=atomic
+	Decisions []ScalingDecision `json:"decisions,omitempty"`
+}
+
+// ScalingDecision is a decision of the autoscaler about the replicas of the targets of a binding.
+type ScalingDecision struct {
+	// Time is when the decision was made.
+	Time metav1.Time `json:"time"`
+	// Reason is why the targets were scaled or not.
+	Reason ScalingDecisionReason `json:"reason"`
+	// Message describes the decision.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Metrics are the values of the metrics of the policy, summed over the ready instances of the targets.
+	// +optional
+	Metrics map[string]resource.Quantity `json:"metrics,omitempty"`
+	// CurrentReplicas is the number of replicas of the targets when the decision was made.
+	CurrentReplicas int32 `json:"currentReplicas"`
+	// RecommendedReplicas is the number of replicas recommended from the metrics, before the behavior of
+	// the policy is applied.
+	// +optional
+	RecommendedReplicas int32 `json:"recommendedReplicas,omitempty"`
+	// DesiredReplicas is the number of replicas the targets are scaled to.
+	DesiredReplicas int32 `json:"desiredReplicas"`
+	// ClampedBy is the limit the desired replicas were bounded by.
+	// +optional
+	ClampedBy ScalingLimit `json:"clampedBy,omitempty"`
+	// DryRun is true when the decision was made in dry-run mode, in which the targets are not scaled to the
+	// desired replicas.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
+// ScalingDecisionReason is the reason of a scaling decision.
+// +kubebuilder:validation:Enum=ScaledUp;ScaledDown;Unchanged;MissingMetrics;Paused
+type ScalingDecisionReason string
+
+const (
+	ScalingDecisionScaledUp   ScalingDecisionReason = "ScaledUp"
+	ScalingDecisionScaledDown ScalingDecisionReason = "ScaledDown"
+	// ScalingDecisionUnchanged means the replicas were kept, because the metrics are within the tolerance
+	// of their targets or the desired replicas were clamped.
+	ScalingDecisionUnchanged ScalingDecisionReason = "Unchanged"
+	// ScalingDecisionMissingMetrics means no metric could be collected from the instances of the targets,
+	// for example because some of them are not ready.
+	ScalingDecisionMissingMetrics ScalingDecisionReason = "MissingMetrics"
+	// ScalingDecisionPaused means a target is paused or suspended.
+	ScalingDecisionPaused ScalingDecisionReason = "Paused"
+)
+
+// ScalingLimit is a limit bounding the replicas recommended from the metrics.
+// +kubebuilder:validation:Enum=MinReplicas;MaxReplicas;ScaleUpStabilizationWindow;ScaleDownStabilizationWindow;ScaleUpRate;ScaleDownRate;PanicScaleUpRate
+type ScalingLimit string
+
+const (
+	ScalingLimitMinReplicas                  ScalingLimit = "MinReplicas"
+	ScalingLimitMaxReplicas                  ScalingLimit = "MaxReplicas"
+	ScalingLimitScaleUpStabilizationWindow   ScalingLimit = "ScaleUpStabilizationWindow"
+	ScalingLimitScaleDownStabilizationWindow ScalingLimit = "ScaleDownStabilizationWindow"
+	// ScalingLimitScaleUpRate and ScalingLimitScaleDownRate are the instances and percent allowed within
+	// the period of the stable policies.
+	ScalingLimitScaleUpRate      ScalingLimit = "ScaleUpRate"
+	ScalingLimitScaleDownRate    ScalingLimit = "ScaleDownRate"
+	ScalingLimitPanicScaleUpRate ScalingLimit = "PanicScaleUpRate"
+)
+
 // +kubebuilder:object:root=true
 
 // AutoscalingPolicyBindingList contains a list of AutoscalingPolicyBinding.
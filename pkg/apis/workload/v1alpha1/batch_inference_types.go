@@ -0,0 +1,177 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BatchInferenceSpec defines the desired state of BatchInference.
+type BatchInferenceSpec struct {
+	// InputFileURI is the URI of the file of requests, in the JSONL format of the OpenAI Batch API.
+	// Each line contains a `custom_id`, and the `body` of the request. Support s3://, pvc://.
+	// +kubebuilder:validation:Pattern=`^(s3://|pvc://).+`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="inputFileURI is immutable"
+	InputFileURI string `json:"inputFileURI"`
+	// OutputFileURI is the URI where the results are written, in the JSONL format of the OpenAI Batch API.
+	// Support s3://, pvc://.
+	// +kubebuilder:validation:Pattern=`^(s3://|pvc://).+`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="outputFileURI is immutable"
+	OutputFileURI string `json:"outputFileURI"`
+	// Endpoint is the API endpoint the requests are sent to.
+	// +optional
+	// +kubebuilder:default=/v1/chat/completions
+	// +kubebuilder:validation:Enum=/v1/chat/completions;/v1/completions;/v1/embeddings
+	Endpoint string `json:"endpoint,omitempty"`
+	// Model overrides the `model` of every request in the input file.
+	// +optional
+	Model string `json:"model,omitempty"`
+	// RouterURL is the base URL of the kthena router the requests are sent through.
+	// Default is the kthena-router service in the namespace of the controller.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^https?://.+`
+	RouterURL string `json:"routerURL,omitempty"`
+	// Concurrency is the maximum number of requests in flight at the same time.
+	// +optional
+	// +kubebuilder:default=16
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1024
+	Concurrency *int32 `json:"concurrency,omitempty"`
+	// MaxRetries is the maximum number of retries of a request failing with a retryable error,
+	// such as 429 or 5xx responses.
+	// +optional
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+	// CompletionWindow is the time frame within which the batch must be processed, counted from the creation
+	// of the BatchInference, as the completion_window of the OpenAI Batch API. The batch expires once the window
+	// has passed: its runner is stopped, and the requests which were not sent are reported as expired.
+	// +optional
+	// +kubebuilder:default="24h"
+	CompletionWindow *metav1.Duration `json:"completionWindow,omitempty"`
+	// Cancel stops the batch: its runner is stopped, and the requests which were not sent are reported
+	// as cancelled. A cancelled batch cannot be resumed.
+	// +optional
+	Cancel bool `json:"cancel,omitempty"`
+	// List of environment variables to set in the batch runner.
+	// Supported names:
+	// "ENDPOINT": When you read or write files in s3, you have to specify it.
+	// "ACCESS_KEY": The access key of s3.
+	// "SECRET_KEY": The secret key of s3.
+	// "API_KEY": The API key sent to the router as a bearer token.
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=name
+	Env []corev1.EnvVar `json:"env,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+	// List of sources to populate environment variables in the batch runner, e.g. the credentials of s3.
+	// +optional
+	// +listType=atomic
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+}
+
+// BatchInferencePhase is the lifecycle phase of a BatchInference.
+type BatchInferencePhase string
+
+const (
+	// BatchInferencePending means the batch runner has not started yet.
+	BatchInferencePending BatchInferencePhase = "Pending"
+	// BatchInferenceRunning means the requests are being sent through the router.
+	BatchInferenceRunning BatchInferencePhase = "Running"
+	// BatchInferenceSucceeded means all requests have been processed and the results written.
+	// Requests failing individually are reported in the output file and in the failed count.
+	BatchInferenceSucceeded BatchInferencePhase = "Succeeded"
+	// BatchInferenceFailed means the batch could not be processed, e.g. the input file could not be read.
+	BatchInferenceFailed BatchInferencePhase = "Failed"
+	// BatchInferenceCancelled means the batch was cancelled before it finished.
+	BatchInferenceCancelled BatchInferencePhase = "Cancelled"
+	// BatchInferenceExpired means the batch did not finish within its completion window.
+	BatchInferenceExpired BatchInferencePhase = "Expired"
+)
+
+// BatchInferenceConditionType is a condition type of a BatchInference.
+type BatchInferenceConditionType string
+
+const (
+	// BatchInferenceComplete means the batch has finished, successfully or not.
+	BatchInferenceComplete BatchInferenceConditionType = "Complete"
+)
+
+// BatchRequestCounts is the number of requests of the batch in each state.
+type BatchRequestCounts struct {
+	// Total is the number of requests in the input file.
+	Total int32 `json:"total"`
+	// Completed is the number of requests which have been answered successfully.
+	Completed int32 `json:"completed"`
+	// Failed is the number of requests which failed after all retries.
+	Failed int32 `json:"failed"`
+}
+
+// BatchInferenceStatus defines the observed state of BatchInference.
+type BatchInferenceStatus struct {
+	// Phase is the lifecycle phase of the batch.
+	// +optional
+	Phase BatchInferencePhase `json:"phase,omitempty"`
+	// RequestCounts reports the progress of the batch.
+	// +optional
+	RequestCounts BatchRequestCounts `json:"requestCounts,omitempty"`
+	// JobName is the name of the Job running the batch.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+	// StartTime is the time the batch runner started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the batch finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Conditions track the condition of the BatchInference.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=`.status.requestCounts.total`
+// +kubebuilder:printcolumn:name="Completed",type=integer,JSONPath=`.status.requestCounts.completed`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.requestCounts.failed`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+
+// BatchInference is the Schema for the batch inference API. It sends the requests of an input file
+// through the kthena router at a controlled concurrency, and writes the results to an output file.
+type BatchInference struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              BatchInferenceSpec   `json:"spec,omitempty"`
+	Status            BatchInferenceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BatchInferenceList contains a list of BatchInference
+type BatchInferenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BatchInference `json:"items"`
+}
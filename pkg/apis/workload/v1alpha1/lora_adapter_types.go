@@ -0,0 +1,94 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LoraAdapterSpec defines the desired state of LoraAdapter.
+type LoraAdapterSpec struct {
+	// BaseModel is the name of the ModelBooster, in the same namespace, the adapter is applied to.
+	// Requests for the model "<baseModel>:<name of the LoraAdapter>" are routed to the pods the adapter is loaded on.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="baseModel is immutable"
+	BaseModel string `json:"baseModel"`
+	// Backends are the names of the backends of the base model the adapter is loaded on.
+	// Default is all the vLLM backends allowing runtime LoRA updates, with VLLM_ALLOW_RUNTIME_LORA_UPDATING set to true.
+	// +optional
+	// +listType=set
+	Backends []string `json:"backends,omitempty"`
+	// ArtifactURL is the URL where the LoRA adapter artifact is stored.
+	// +kubebuilder:validation:Pattern=`^(hf://|s3://|pvc://).+`
+	ArtifactURL string `json:"artifactURL"`
+}
+
+// LoraAdapterConditionType is a condition type of a LoraAdapter.
+type LoraAdapterConditionType string
+
+const (
+	// LoraAdapterReady means the adapter is loaded on all the running pods of the base model.
+	LoraAdapterReady LoraAdapterConditionType = "Ready"
+)
+
+// LoraAdapterStatus defines the observed state of LoraAdapter.
+type LoraAdapterStatus struct {
+	// ObservedGeneration is the most recent generation observed for the LoraAdapter.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Replicas is the number of running pods of the base model the adapter should be loaded on.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// LoadedReplicas is the number of pods the adapter is loaded on.
+	// +optional
+	LoadedReplicas int32 `json:"loadedReplicas,omitempty"`
+	// ArtifactURL is the URL of the artifact loaded on all the pods.
+	// +optional
+	ArtifactURL string `json:"artifactURL,omitempty"`
+	// Conditions track the condition of the LoraAdapter.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Base Model",type=string,JSONPath=`.spec.baseModel`
+// +kubebuilder:printcolumn:name="Loaded",type=integer,JSONPath=`.status.loadedReplicas`
+// +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=`.status.replicas`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+
+// LoraAdapter is the Schema for the LoRA adapter API. The adapter is loaded dynamically on the running
+// pods of its base model, and requests for "<baseModel>:<name>" are only routed to the pods it is loaded on.
+type LoraAdapter struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              LoraAdapterSpec   `json:"spec,omitempty"`
+	Status            LoraAdapterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// LoraAdapterList contains a list of LoraAdapter
+type LoraAdapterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LoraAdapter `json:"items"`
+}
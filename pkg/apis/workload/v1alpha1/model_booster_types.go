@@ -53,6 +53,26 @@ type ModelBoosterSpec struct {
 	// evaluate to true only if all conditions are satisfied.
 	// +optional
 	ModelMatch *networking.ModelMatch `json:"modelMatch,omitempty"`
+	// Card describes the model. The model policy of the cluster may require some of its fields,
+	// and deny the registration of models under some licenses.
+	// +optional
+	Card *ModelCard `json:"card,omitempty"`
+}
+
+// ModelCard is the metadata of a model, as published in its model card.
+type ModelCard struct {
+	// License is the license of the model, preferably its SPDX identifier, e.g. apache-2.0 or llama3.1.
+	// +optional
+	License string `json:"license,omitempty"`
+	// IntendedUse describes the use cases the model is intended for.
+	// +optional
+	IntendedUse string `json:"intendedUse,omitempty"`
+	// SafetyTier is the safety tier the model was assessed at, as defined by the organization.
+	// +optional
+	SafetyTier string `json:"safetyTier,omitempty"`
+	// URL is the address of the model card, e.g. on Hugging Face.
+	// +optional
+	URL string `json:"url,omitempty"`
 }
 
 // ModelBackend defines the configuration for a model backend.
@@ -69,6 +89,9 @@ type ModelBackend struct {
 	// CacheURI is the URI where the downloaded model stored. Support hostpath://, pvc://.
 	// +kubebuilder:validation:Pattern=`^(hostpath://|pvc://).+`
 	CacheURI string `json:"cacheURI,omitempty"`
+	// Download configures how the model is downloaded and verified.
+	// +optional
+	Download *ModelDownloadPolicy `json:"download,omitempty"`
 	// List of sources to populate environment variables in the container.
 	// The keys defined within a source must be a C_IDENTIFIER. All invalid keys
 	// will be reported as an event when the container is starting. When a key exists in multiple
@@ -118,9 +141,9 @@ type ModelBackend struct {
 	// +kubebuilder:validation:MinItems=1
 	// +kubebuilder:validation:MaxItems=1000
 	Workers []ModelWorker `json:"workers"`
-	// LoraAdapter is a list of LoRA adapters.
+	// LoraAdapters is a list of LoRA adapters loaded with the model.
 	// +optional
-	LoraAdapters []LoraAdapter `json:"loraAdapters,omitempty"`
+	LoraAdapters []BackendLoraAdapter `json:"loraAdapters,omitempty"`
 	// AutoscalingPolicyRef references the autoscaling policy for this backend.
 	// +optional
 	AutoscalingPolicy *AutoscalingPolicySpec `json:"autoscalingPolicy,omitempty"`
@@ -129,8 +152,8 @@ type ModelBackend struct {
 	SchedulerName string `json:"schedulerName,omitempty"`
 }
 
-// LoraAdapter defines a LoRA (Low-Rank Adaptation) adapter configuration.
-type LoraAdapter struct {
+// BackendLoraAdapter defines a LoRA (Low-Rank Adaptation) adapter loaded with the model of a backend.
+type BackendLoraAdapter struct {
 	// Name is the name of the LoRA adapter.
 	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
 	Name string `json:"name"`
@@ -139,6 +162,39 @@ type LoraAdapter struct {
 	ArtifactURL string `json:"artifactURL"`
 }
 
+// ModelDownloadPolicy defines how model artifacts are downloaded and verified.
+type ModelDownloadPolicy struct {
+	// Checksums maps file paths, relative to the model root, to their expected SHA256 digests.
+	// Files downloaded from Hugging Face are always verified against the repository manifest,
+	// entries here take precedence. Files that fail verification are downloaded again.
+	// +optional
+	Checksums map[string]string `json:"checksums,omitempty"`
+	// MaxConcurrency is the maximum number of files, or parts of a file for S3, downloaded in parallel.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=128
+	// +optional
+	MaxConcurrency *int32 `json:"maxConcurrency,omitempty"`
+	// ChunkSizeMB is the part size in MB used for multipart S3 downloads.
+	// +kubebuilder:validation:Minimum=5
+	// +kubebuilder:validation:Maximum=5120
+	// +optional
+	ChunkSizeMB *int32 `json:"chunkSizeMB,omitempty"`
+	// MaxRetries is the number of times a failed download or checksum verification is retried.
+	// Retries resume from the files already downloaded.
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+	// Attestation references the key of a ConfigMap, in the namespace of the ModelBooster, holding the DSSE
+	// envelope, or the Sigstore bundle, of an in-toto statement signed by the publisher of the model. Its
+	// subjects are the files of the model with their SHA256 digests. The signers of the attestation are
+	// checked against the provenance policy of the namespace, and the downloader verifies the files against
+	// the digests of the subjects.
+	// +optional
+	Attestation *corev1.ConfigMapKeySelector `json:"attestation,omitempty"`
+}
+
 // ModelBackendType defines the type of model backend.
 // +kubebuilder:validation:Enum=vLLM;vLLMDisaggregated;SGLang;MindIE;MindIEDisaggregated
 type ModelBackendType string
@@ -220,6 +276,9 @@ const (
 	ModelStatusConditionTypeInitialized ModelStatusConditionType = "Initialized"
 	ModelStatusConditionTypeActive      ModelStatusConditionType = "Active"
 	ModelStatusConditionTypeFailed      ModelStatusConditionType = "Failed"
+	// ModelStatusConditionTypeProvenanceVerified reports whether the images and the model artifacts of the
+	// backends are signed as required by the provenance policy of the namespace. It is only set when a policy applies.
+	ModelStatusConditionTypeProvenanceVerified ModelStatusConditionType = "ProvenanceVerified"
 )
 
 // ModelBackendStatus defines the status of a model backend.
@@ -228,6 +287,47 @@ type ModelBackendStatus struct {
 	Name string `json:"name"`
 	// Replicas is the number of replicas currently running for the backend.
 	Replicas int32 `json:"replicas"`
+	// Download reports the progress of the model download of the backend.
+	// +optional
+	Download *ModelDownloadStatus `json:"download,omitempty"`
+}
+
+// ModelDownloadPhase is the phase of a model download.
+type ModelDownloadPhase string
+
+const (
+	ModelDownloadPhaseDownloading ModelDownloadPhase = "Downloading"
+	ModelDownloadPhaseVerifying   ModelDownloadPhase = "Verifying"
+	ModelDownloadPhaseCompleted   ModelDownloadPhase = "Completed"
+	ModelDownloadPhaseFailed      ModelDownloadPhase = "Failed"
+)
+
+// ModelDownloadProgressAnnotationKey is set by the model downloader on its pod to report download progress.
+const ModelDownloadProgressAnnotationKey = "modelbooster.volcano.sh/download-progress"
+
+// ModelCacheNodeLabelPrefix prefixes the node labels set by the model cache agent for every model
+// cached on a node. The label name is the directory of the model in the cache, the md5 of its URI.
+const ModelCacheNodeLabelPrefix = "cache.modelbooster.volcano.sh/"
+
+// ModelDownloadStatus defines the progress of a model download. When several pods download
+// the model, the least advanced one is reported.
+type ModelDownloadStatus struct {
+	// Phase is the phase of the download.
+	Phase ModelDownloadPhase `json:"phase"`
+	// DownloadedBytes is the number of bytes downloaded so far.
+	DownloadedBytes int64 `json:"downloadedBytes"`
+	// TotalBytes is the size of the model, if known.
+	// +optional
+	TotalBytes int64 `json:"totalBytes,omitempty"`
+	// Percentage is the downloaded percentage, if the size of the model is known.
+	// +optional
+	Percentage int32 `json:"percentage,omitempty"`
+	// EstimatedTimeRemaining is the estimated time until the download completes.
+	// +optional
+	EstimatedTimeRemaining *metav1.Duration `json:"estimatedTimeRemaining,omitempty"`
+	// LastUpdateTime is when the downloader last reported progress.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -35,6 +35,51 @@ const (
 
 	// RevisionLabelKey is the revision label for the model serving.
 	RevisionLabelKey = "modelserving.volcano.sh/revision"
+	// SpecRevisionLabelKey is the revision of the roles without the fields which can be updated in place:
+	// the pod labels and annotations and the container images. The pods whose spec revision is unchanged
+	// are patched in rolling updates instead of being recreated.
+	SpecRevisionLabelKey = "modelserving.volcano.sh/spec-revision"
+	// StandbyLabelKey is set to "true" on the pods of standby ServingGroups, which are not routed to.
+	StandbyLabelKey = "modelserving.volcano.sh/standby"
+
+	// ProvisioningClassAnnotationKey is the ModelServing annotation key of the ProvisioningRequest class
+	// which the cluster autoscaler should use to provision nodes for pods waiting for accelerators.
+	ProvisioningClassAnnotationKey = "modelserving.volcano.sh/provisioning-class-name"
+	// ProvisioningDurationAnnotationKey is the ModelServing annotation key of the expected time, as a
+	// duration string, for a new node to be provisioned. It is used to estimate when waiting pods will be ready.
+	ProvisioningDurationAnnotationKey = "modelserving.volcano.sh/expected-provisioning-duration"
+	// ProvisioningPriorityClassAnnotationKey is the ModelServing annotation key of the PriorityClass of the pods which
+	// set none, in their template, role or ServingGroup. The cluster autoscaler only provisions nodes for the pods above
+	// its expendable priority cutoff, and the pending pods of a higher priority are scheduled first on the new nodes.
+	ProvisioningPriorityClassAnnotationKey = "modelserving.volcano.sh/provisioning-priority-class-name"
+	// StartupEndpointAnnotationKey is the pod annotation key of the endpoint, in the form ":<port><path>", which reports
+	// the startup progress of the inference engine. It is probed to populate the loading conditions of the ModelServing.
+	StartupEndpointAnnotationKey = "modelserving.volcano.sh/startup-endpoint"
+	// ServedModelNameAnnotationKey is the ModelServing annotation key of the model name served by the inference
+	// engines. It is passed to the vLLM and SGLang containers which do not set a served model name.
+	ServedModelNameAnnotationKey = "modelserving.volcano.sh/served-model-name"
+	// MaxModelLenAnnotationKey is the ModelServing annotation key of the context length of the model. It is
+	// passed to the vLLM and SGLang containers which do not set a maximum model length.
+	MaxModelLenAnnotationKey = "modelserving.volcano.sh/max-model-len"
+	// EngineArgsDefaultingAnnotationKey disables the defaulting of the engine arguments of a ModelServing when set to "false".
+	EngineArgsDefaultingAnnotationKey = "modelserving.volcano.sh/engine-args-defaulting"
+	// ModelSizeAnnotationKey is the ModelServing annotation key of the size of the model weights, as a quantity
+	// such as "140Gi". The engine containers which do not request ephemeral storage request it to download them.
+	ModelSizeAnnotationKey = "modelserving.volcano.sh/model-size"
+	// StorageDefaultingAnnotationKey disables the defaulting of the /dev/shm volume and of the ephemeral storage
+	// of the engine containers of a ModelServing when set to "false".
+	StorageDefaultingAnnotationKey = "modelserving.volcano.sh/storage-defaulting"
+	// ResetRecoveryBackoffAnnotationKey resets the recovery backoff of all the ServingGroups of a ModelServing,
+	// including the ones which exceeded their retries, each time its value changes, e.g. to the current time.
+	ResetRecoveryBackoffAnnotationKey = "modelserving.volcano.sh/reset-recovery-backoff"
+
+	// SchedulerNameVolcano gang schedules the ServingGroups with Volcano PodGroups.
+	SchedulerNameVolcano = "volcano"
+	// SchedulerNameKueue admits each ServingGroup as a Kueue Workload, the pods are bound by the default scheduler.
+	SchedulerNameKueue = "kueue"
+	// SchedulerNameCoscheduling gang schedules the ServingGroups with the PodGroups of the coscheduling
+	// plugin of scheduler-plugins, which is deployed as the scheduler-plugins-scheduler.
+	SchedulerNameCoscheduling = "scheduler-plugins-scheduler"
 
 	// Environment injected to the worker pods.
 	EntryAddressEnv = "ENTRY_ADDRESS"
@@ -43,6 +88,21 @@ const (
 	WorkerIndexEnv = "WORKER_INDEX"
 	// GroupSizeEnv is the environment variable for the group size.
 	GroupSizeEnv = "GROUP_SIZE"
+
+	// Environment injected to the pods of the roles with workers, for the multi-node inference engines.
+	// They are not injected when the template already sets them.
+	// MasterAddrEnv is the address of the entry pod, which hosts the rank 0 of the distributed engine.
+	MasterAddrEnv = "MASTER_ADDR"
+	// MasterPortEnv is the port of the distributed engine rendezvous on the entry pod.
+	MasterPortEnv = "MASTER_PORT"
+	// DistInitAddrEnv is the rendezvous address in the form "<MASTER_ADDR>:<MASTER_PORT>", e.g. for the --dist-init-addr of SGLang.
+	DistInitAddrEnv = "DIST_INIT_ADDR"
+	// NNodesEnv is the number of pods of the role replica, the same as GROUP_SIZE.
+	NNodesEnv = "NNODES"
+	// NodeRankEnv is the rank of the pod in the role replica, the same as WORKER_INDEX.
+	NodeRankEnv = "NODE_RANK"
+	// WorldSizeEnv is the number of accelerators of the role replica. It is only injected when every pod requests accelerators.
+	WorldSizeEnv = "WORLD_SIZE"
 )
 
 // ModelServingSpec defines the specification of the ModelServing resource.
@@ -54,7 +114,18 @@ type ModelServingSpec struct {
 	// +kubebuilder:default=1
 	Replicas *int32 `json:"replicas,omitempty"`
 
-	// SchedulerName defines the name of the scheduler used by ModelServing
+	// StandbyReplicas is the number of extra ServingGroups kept warm, with the model loaded, on top of Replicas.
+	// The pods of standby ServingGroups are labeled with modelserving.volcano.sh/standby and are not routed to.
+	// Scaling up activates the standby ServingGroups first, and a new standby ServingGroup is created in their place.
+	// Default to 0.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	StandbyReplicas *int32 `json:"standbyReplicas,omitempty"`
+
+	// SchedulerName defines the name of the scheduler used by ModelServing.
+	// It also selects the gang scheduling backend: volcano uses Volcano PodGroups, kueue admits each
+	// ServingGroup as a Kueue Workload and scheduler-plugins-scheduler uses the PodGroups of the coscheduling plugin.
 	SchedulerName string `json:"schedulerName"`
 
 	// Template defines the template for ServingGroup
@@ -68,7 +139,33 @@ type ModelServingSpec struct {
 	// +kubebuilder:default=RoleRecreate
 	// +kubebuilder:validation:Enum={ServingGroupRecreate,RoleRecreate,None}
 	// +optional
-	RecoveryPolicy            RecoveryPolicy             `json:"recoveryPolicy,omitempty"`
+	RecoveryPolicy RecoveryPolicy `json:"recoveryPolicy,omitempty"`
+
+	// RecoveryBackoff delays the recreation of the ServingGroups whose pods keep failing, so that a bad image or
+	// configuration is not recreated in a hot loop. The delay doubles with each failure in a row, from 10s up to
+	// 5m by default, and is reset once the ServingGroup is running.
+	// +optional
+	RecoveryBackoff *RecoveryBackoff `json:"recoveryBackoff,omitempty"`
+
+	// DisruptionPolicy defines the PodDisruptionBudgets managed for the ServingGroups, so that voluntary
+	// disruptions such as node drains never evict a part of a ServingGroup or of a role replica.
+	// +kubebuilder:default=None
+	// +kubebuilder:validation:Enum={ServingGroupIntact,RoleIntact,None}
+	// +optional
+	DisruptionPolicy DisruptionPolicy `json:"disruptionPolicy,omitempty"`
+
+	// Paused freezes the reconciliation of the ModelServing while leaving its pods running:
+	// ServingGroups are neither scaled nor rolled out, failed pods are not recreated and the autoscaler
+	// skips the ModelServing. It allows safe manual intervention during incidents.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Suspend scales the ModelServing to zero: the pods of all its ServingGroups are deleted, while the
+	// ServingGroups keep their headless services and gang scheduling PodGroups and spec.replicas is kept,
+	// so that resuming only recreates the pods of the same ServingGroups.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
 	TopologySpreadConstraints []TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 }
 
@@ -90,6 +187,45 @@ const (
 	NoneRestartPolicy RecoveryPolicy = "None"
 )
 
+// RecoveryBackoff defines the exponential backoff of the recreation of the failed ServingGroups and roles.
+type RecoveryBackoff struct {
+	// InitialDelaySeconds is the delay before recreating a ServingGroup after its first failure.
+	// Default to 10.
+	// +optional
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+
+	// MaxDelaySeconds caps the delay, which doubles with each failure in a row.
+	// Default to 300.
+	// +optional
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=1
+	MaxDelaySeconds int32 `json:"maxDelaySeconds,omitempty"`
+
+	// MaxRetries is the number of times a ServingGroup failing in a row is recreated, it is then left failed until its
+	// backoff is reset with the modelserving.volcano.sh/reset-recovery-backoff annotation.
+	// Default to 0, which recreates it forever.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries int32 `json:"maxRetries,omitempty"`
+}
+
+type DisruptionPolicy string
+
+const (
+	// ServingGroupIntact creates a PodDisruptionBudget for each ServingGroup, which blocks the
+	// voluntary eviction of any pod of the group.
+	ServingGroupIntact DisruptionPolicy = "ServingGroupIntact"
+
+	// RoleIntact creates a PodDisruptionBudget for each role replica, which blocks the
+	// voluntary eviction of any pod of the role replica.
+	RoleIntact DisruptionPolicy = "RoleIntact"
+
+	// DisruptionPolicyNone does not create PodDisruptionBudgets.
+	DisruptionPolicyNone DisruptionPolicy = "None"
+)
+
 // RolloutStrategy defines the strategy that the ModelServing controller
 // will use to perform replica updates.
 type RolloutStrategy struct {
@@ -174,6 +310,45 @@ const (
 	// When the entry or worker template is updated, modelServing controller enters the upgrade process and
 	// UpdateInProgress is set to true.
 	ModelServingUpdateInProgress ModelServingConditionType = "UpdateInProgress"
+
+	// ModelServingWaitingForNodeProvisioning indicates that pods of the modelServing can not be scheduled
+	// because no node has enough accelerators, and are waiting for the cluster autoscaler to provision nodes.
+	// The condition message carries the estimated time the nodes will be ready.
+	ModelServingWaitingForNodeProvisioning ModelServingConditionType = "WaitingForNodeProvisioning"
+
+	// ModelServingUnschedulable indicates that ServingGroups of the modelServing are not running and their pods
+	// do not fit on the nodes of the cluster, by their resources, node selectors, affinities, tolerations or
+	// colocation. The condition message carries the reasons. It is cleared once nodes they fit on join the cluster.
+	ModelServingUnschedulable ModelServingConditionType = "Unschedulable"
+
+	// ModelServingRecoveryBackOff indicates that failed ServingGroups of the modelServing are waiting for their
+	// recovery backoff before being recreated, or are no longer recreated after exceeding their retries.
+	// The condition message lists them with their failures in a row.
+	ModelServingRecoveryBackOff ModelServingConditionType = "RecoveryBackOff"
+
+	// ModelServingPreempted indicates that pods of ServingGroups of the modelServing were preempted by the
+	// scheduler in favor of workloads of a higher priority. The condition message lists the ServingGroups with
+	// the reason given by the scheduler, it is unset once they are running again.
+	ModelServingPreempted ModelServingConditionType = "Preempted"
+
+	// ModelServingWeightsLoaded indicates that the engines of all the pods reporting their startup progress
+	// have loaded the model weights.
+	ModelServingWeightsLoaded ModelServingConditionType = "WeightsLoaded"
+
+	// ModelServingEngineWarmedUp indicates that the engines of all the pods reporting their startup progress
+	// have been warmed up, so that the first user request does not hit a cold engine.
+	ModelServingEngineWarmedUp ModelServingConditionType = "EngineWarmedUp"
+
+	// ModelServingPaused indicates that the reconciliation of the modelServing is paused by spec.paused.
+	ModelServingPaused ModelServingConditionType = "Paused"
+
+	// ModelServingSuspended indicates that the modelServing is scaled to zero by spec.suspend.
+	ModelServingSuspended ModelServingConditionType = "Suspended"
+
+	// ModelServingErrorBudgetAvailable is set by the router, when it is configured to, on the ModelServings
+	// serving a ModelServer with service level objectives. It is false once one of their error budgets is
+	// exhausted, so that the autoscaling or the alerting can act on it.
+	ModelServingErrorBudgetAvailable ModelServingConditionType = "ErrorBudgetAvailable"
 )
 
 // ModelServingStatus defines the observed state of ModelServing
@@ -183,7 +358,7 @@ type ModelServingStatus struct {
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
-	// Replicas track the total number of ServingGroup that have been created (updated or not, ready or not)
+	// Replicas track the total number of active ServingGroup that have been created (updated or not, ready or not)
 	Replicas int32 `json:"replicas,omitempty"`
 
 	// CurrentReplicas is the number of ServingGroup created by the ModelServing controller from the ModelServing version
@@ -195,12 +370,119 @@ type ModelServingStatus struct {
 	// AvailableReplicas track the number of ServingGroup that are in ready state (updated or not).
 	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
 
+	// StandbyReplicas track the number of standby ServingGroup that are in ready state.
+	// Standby ServingGroups are not counted in the other replicas of the status.
+	// +optional
+	StandbyReplicas int32 `json:"standbyReplicas,omitempty"`
+
+	// LoadingPercentage is the average startup progress, from weight loading to engine warm-up, of the pods
+	// reporting their startup progress.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	LoadingPercentage int32 `json:"loadingPercentage,omitempty"`
+
 	// Conditions track the condition of the ModelServing.
+	// +listType=map
+	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LabelSelector is the label selector of the pods of the ModelServing, in the string form. It is the
+	// selector of the scale subresource, used by kubectl scale and the HorizontalPodAutoscaler.
+	// +optional
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// ResourceRecommendations are the engine resources recommended by the autoscaler for the ModelServing
+	// or its roles, when vertical recommendation is enabled in the autoscaling policy.
+	// +optional
+	ResourceRecommendations []ResourceRecommendation `json:"resourceRecommendations,omitempty"`
+
+	// Groups are the status of the ServingGroups, ordered by ordinal. At most MaxServingGroupStatuses of them
+	// are listed, the ServingGroups which are not running first.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	Groups []ServingGroupStatus `json:"groups,omitempty"`
+}
+
+// MaxServingGroupStatuses is the maximum number of ServingGroups listed in the status of a ModelServing,
+// which bounds its size.
+const MaxServingGroupStatuses = 100
+
+// ServingGroupPhase is the lifecycle phase of a ServingGroup.
+type ServingGroupPhase string
+
+const (
+	// ServingGroupPhaseCreating is a ServingGroup whose pods are not all running and ready yet.
+	ServingGroupPhaseCreating ServingGroupPhase = "Creating"
+	// ServingGroupPhaseRunning is a ServingGroup whose pods are all running and ready.
+	ServingGroupPhaseRunning ServingGroupPhase = "Running"
+	// ServingGroupPhaseScaling is a ServingGroup whose roles are scaled.
+	ServingGroupPhaseScaling ServingGroupPhase = "Scaling"
+	// ServingGroupPhaseDeleting is a ServingGroup being deleted, to be recreated or scaled in.
+	ServingGroupPhaseDeleting ServingGroupPhase = "Deleting"
+	// ServingGroupPhaseSuspended is a ServingGroup without pods while its ModelServing is suspended.
+	ServingGroupPhaseSuspended ServingGroupPhase = "Suspended"
+)
+
+// ServingGroupStatus is the status of a ServingGroup of a ModelServing.
+type ServingGroupStatus struct {
+	// Name is the name of the ServingGroup, the name of the ModelServing and its ordinal.
+	Name string `json:"name"`
+	// Phase is the lifecycle phase of the ServingGroup.
+	Phase ServingGroupPhase `json:"phase"`
+	// Revision is the revision of the roles the pods of the ServingGroup were created from.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+	// Standby is set for the standby ServingGroups, which do not serve traffic.
+	// +optional
+	Standby bool `json:"standby,omitempty"`
+	// Roles are the replicas of each role of the ServingGroup.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Roles []RoleReplicaStatus `json:"roles,omitempty"`
+	// LastTransitionTime is the last time the phase of the ServingGroup changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// RoleReplicaStatus is the number of replicas of a role in a ServingGroup.
+type RoleReplicaStatus struct {
+	// Name is the name of the role.
+	Name string `json:"name"`
+	// Replicas is the number of replicas of the role created in the ServingGroup.
+	Replicas int32 `json:"replicas"`
+	// ReadyReplicas is the number of replicas of the role whose entry and worker pods are all running and ready.
+	ReadyReplicas int32 `json:"readyReplicas"`
+}
+
+// ResourceRecommendation is the engine resources recommended for the instances of a ModelServing role.
+type ResourceRecommendation struct {
+	// Role is the name of the role the recommendation is made for, empty if it is made for the whole ModelServing.
+	// +optional
+	Role string `json:"role,omitempty"`
+	// GPUMemoryUtilizationPercent is the recommended fraction of the GPU memory used by the engine, in percent.
+	// For vLLM it is the --gpu-memory-utilization argument multiplied by 100.
+	GPUMemoryUtilizationPercent int32 `json:"gpuMemoryUtilizationPercent"`
+	// TensorParallelSize is the recommended number of GPUs a model instance is sharded across.
+	TensorParallelSize int32 `json:"tensorParallelSize"`
+	// PeakKVCacheUsagePercent is the peak KV-cache usage of an instance observed over the window.
+	PeakKVCacheUsagePercent int32 `json:"peakKVCacheUsagePercent"`
+	// PeakBatchSize is the peak number of requests running in a batch of an instance observed over the window.
+	PeakBatchSize int32 `json:"peakBatchSize"`
+	// Reason is a human readable explanation of the recommendation.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// LastUpdateTime is the last time the recommendation changed.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.labelSelector
 // +kubebuilder:storageversion
 // +genclient
 
@@ -44,6 +44,9 @@ var (
 	ModelKind                       = SchemeGroupVersion.WithKind("ModelBooster")
 	AutoscalingPolicyKind           = SchemeGroupVersion.WithKind("AutoscalingPolicy")
 	AutoscalingPolicyBindingKind    = SchemeGroupVersion.WithKind("AutoscalingPolicyBinding")
+	BatchInferenceKind              = SchemeGroupVersion.WithKind("BatchInference")
+	LoraAdapterKind                 = SchemeGroupVersion.WithKind("LoraAdapter")
+	TenancyPolicyKind               = SchemeGroupVersion.WithKind("TenancyPolicy")
 	ModelServingEntryPodLeaderLabel = "leader"
 )
 
@@ -74,6 +77,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&AutoscalingPolicyList{},
 		&AutoscalingPolicyBinding{},
 		&AutoscalingPolicyBindingList{},
+		&BatchInference{},
+		&BatchInferenceList{},
+		&LoraAdapter{},
+		&LoraAdapterList{},
+		&TenancyPolicy{},
+		&TenancyPolicyList{},
 	)
 	// AddToGroupVersion allows the serialization of client types like ListOptions.
 	v1.AddToGroupVersion(scheme, SchemeGroupVersion)
@@ -60,6 +60,80 @@ type Role struct {
 	// WorkerTemplate defines the template for the worker pod of a role.
 	// +optional
 	WorkerTemplate *PodTemplateSpec `json:"workerTemplate,omitempty"`
+
+	// Placement defines the topology-aware placement of the pods of the role.
+	// +optional
+	Placement *RolePlacement `json:"placement,omitempty"`
+
+	// GPUSharing runs the pods of the role on a fraction of a GPU. The GPUs requested by the containers of the
+	// templates, as nvidia.com/gpu, are translated into the resources of the MIG instances or time-sliced GPUs.
+	// +optional
+	GPUSharing *GPUSharing `json:"gpuSharing,omitempty"`
+
+	// PriorityClassName is the PriorityClass of the pods of the role, overriding the one of the ServingGroup.
+	// The priority class set in a pod template wins.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// GPUSharingMode is the way a GPU is shared by several pods.
+// +kubebuilder:validation:Enum={MIG,TimeSlicing}
+type GPUSharingMode string
+
+const (
+	// GPUSharingMIG runs the pods on MIG instances, advertised as nvidia.com/mig-<profile> by the NVIDIA device
+	// plugin with the mixed MIG strategy.
+	GPUSharingMIG GPUSharingMode = "MIG"
+	// GPUSharingTimeSlicing runs the pods on GPUs shared by time-slicing, advertised as nvidia.com/gpu.shared by
+	// the NVIDIA device plugin when its shared resources are renamed.
+	GPUSharingTimeSlicing GPUSharingMode = "TimeSlicing"
+)
+
+// GPUSharing defines how the pods of a role share GPUs.
+// +kubebuilder:validation:XValidation:rule="self.mode == 'MIG' ? has(self.migProfile) : !has(self.migProfile)",message="migProfile must be set with, and only with, the MIG mode"
+type GPUSharing struct {
+	// Mode is the way the GPUs are shared.
+	Mode GPUSharingMode `json:"mode"`
+
+	// MIGProfile is the profile of the MIG instances, e.g. 1g.10gb. It must be advertised by a node of the cluster.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+g\.[0-9]+gb(\+me)?$`
+	MIGProfile string `json:"migProfile,omitempty"`
+}
+
+// RolePlacement defines how the pods of a role are placed across the topology of the cluster.
+// It is translated into the affinity and topology spread constraints of the pods.
+type RolePlacement struct {
+	// ColocationTopologyKey keeps the entry and worker pods of each role replica in the same topology domain,
+	// e.g. `kubernetes.io/hostname` for the same node, or the node label of the NVLink domain.
+	// +optional
+	ColocationTopologyKey string `json:"colocationTopologyKey,omitempty"`
+
+	// TopologySpread spreads the replicas of the role, across all the ServingGroups, over topology domains such as zones.
+	// The constraints apply to the entry pods, the worker pods follow them when ColocationTopologyKey is set.
+	// +optional
+	// +kubebuilder:validation:MaxItems=4
+	TopologySpread []RoleTopologySpread `json:"topologySpread,omitempty"`
+}
+
+// RoleTopologySpread defines a topology spread constraint of the replicas of a role.
+type RoleTopologySpread struct {
+	// TopologyKey is the key of node labels. Nodes that have a label with this key
+	// and identical values are considered to be in the same topology.
+	// +kubebuilder:validation:MinLength=1
+	TopologyKey string `json:"topologyKey"`
+
+	// MaxSkew describes the degree to which the replicas of the role may be unevenly distributed.
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+
+	// WhenUnsatisfiable indicates how to deal with a replica if it doesn't satisfy the spread constraint.
+	// +optional
+	// +kubebuilder:default=ScheduleAnyway
+	// +kubebuilder:validation:Enum={DoNotSchedule,ScheduleAnyway}
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
 }
 
 // PodTemplateSpec describes the data a pod should have when created from a template
@@ -104,6 +178,12 @@ type ServingGroup struct {
 	// NetworkTopology defines the network topology affinity scheduling policy for the roles of the group, it works only when the scheduler supports network topology feature.	// +optional
 	NetworkTopology *volcanoV1Beta1.NetworkTopologySpec `json:"networkTopology,omitempty"`
 
+	// PriorityClassName is the PriorityClass of the pods of the ServingGroups and of their Volcano PodGroups.
+	// The ServingGroups of a lower priority, e.g. of batch inference, are preempted in favor of the ones of a
+	// higher priority, e.g. of online serving, when they do not fit together.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// +kubebuilder:validation:MaxItems=4
 	// +kubebuilder:validation:MinItems=1
 	// +kubebuilder:validation:XValidation:rule="self.all(x, self.exists_one(y, y.name == x.name))", message="roles name must be unique"
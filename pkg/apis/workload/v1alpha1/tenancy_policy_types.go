@@ -0,0 +1,64 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TenancyPolicySpec defines the guardrails enforced by the admission webhooks in the namespaces selected by a TenancyPolicy.
+type TenancyPolicySpec struct {
+	// NamespaceSelector selects the namespaces the policy applies to, all the namespaces when it is not set.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// AllowedModels are the models the ModelRoutes and the ModelServers of the namespaces may reference, as glob
+	// patterns such as "llama-*". Any model may be referenced when it is empty. A namespace selected by several
+	// policies may only reference the models allowed by all of them.
+	// +optional
+	AllowedModels []string `json:"allowedModels,omitempty"`
+	// MaxGPUs caps the accelerators requested by all the ModelServings of each namespace, counting every replica
+	// of every role. The accelerators are not capped when it is not set. The lowest cap of the policies selecting
+	// a namespace applies.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxGPUs *int64 `json:"maxGPUs,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Max GPUs",type=integer,JSONPath=`.spec.maxGPUs`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+
+// TenancyPolicy is the Schema for the tenancy policies API. It constrains the models the namespaces it selects
+// may serve and the accelerators their ModelServings may request.
+type TenancyPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              TenancyPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TenancyPolicyList contains a list of TenancyPolicy
+type TenancyPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TenancyPolicy `json:"items"`
+}
@@ -23,6 +23,7 @@ package v1alpha1
 import (
 	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
@@ -78,7 +79,7 @@ func (in *AutoscalingPolicyBinding) DeepCopyInto(out *AutoscalingPolicyBinding)
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingPolicyBinding.
@@ -160,6 +161,17 @@ func (in *AutoscalingPolicyBindingSpec) DeepCopy() *AutoscalingPolicyBindingSpec
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoscalingPolicyBindingStatus) DeepCopyInto(out *AutoscalingPolicyBindingStatus) {
 	*out = *in
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Decisions != nil {
+		in, out := &in.Decisions, &out.Decisions
+		*out = make([]ScalingDecision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingPolicyBindingStatus.
@@ -251,6 +263,42 @@ func (in *AutoscalingPolicyPanicPolicy) DeepCopy() *AutoscalingPolicyPanicPolicy
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingPolicyPredictive) DeepCopyInto(out *AutoscalingPolicyPredictive) {
+	*out = *in
+	out.TargetRequestRate = in.TargetRequestRate.DeepCopy()
+	if in.SeasonPeriod != nil {
+		in, out := &in.SeasonPeriod, &out.SeasonPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Seasons != nil {
+		in, out := &in.Seasons, &out.Seasons
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Lookahead != nil {
+		in, out := &in.Lookahead, &out.Lookahead
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MinConfidencePercent != nil {
+		in, out := &in.MinConfidencePercent, &out.MinConfidencePercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingPolicyPredictive.
+func (in *AutoscalingPolicyPredictive) DeepCopy() *AutoscalingPolicyPredictive {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingPolicyPredictive)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoscalingPolicyScaleUpPolicy) DeepCopyInto(out *AutoscalingPolicyScaleUpPolicy) {
 	*out = *in
@@ -279,6 +327,16 @@ func (in *AutoscalingPolicySpec) DeepCopyInto(out *AutoscalingPolicySpec) {
 		}
 	}
 	in.Behavior.DeepCopyInto(&out.Behavior)
+	if in.Predictive != nil {
+		in, out := &in.Predictive, &out.Predictive
+		*out = new(AutoscalingPolicyPredictive)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VerticalRecommendation != nil {
+		in, out := &in.VerticalRecommendation, &out.VerticalRecommendation
+		*out = new(AutoscalingPolicyVerticalRecommendation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingPolicySpec.
@@ -314,6 +372,11 @@ func (in *AutoscalingPolicyStablePolicy) DeepCopyInto(out *AutoscalingPolicyStab
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.TolerancePercent != nil {
+		in, out := &in.TolerancePercent, &out.TolerancePercent
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingPolicyStablePolicy.
@@ -341,6 +404,210 @@ func (in *AutoscalingPolicyStatus) DeepCopy() *AutoscalingPolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingPolicyVerticalRecommendation) DeepCopyInto(out *AutoscalingPolicyVerticalRecommendation) {
+	*out = *in
+	if in.TargetKVCacheUsagePercent != nil {
+		in, out := &in.TargetKVCacheUsagePercent, &out.TargetKVCacheUsagePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingPolicyVerticalRecommendation.
+func (in *AutoscalingPolicyVerticalRecommendation) DeepCopy() *AutoscalingPolicyVerticalRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingPolicyVerticalRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendLoraAdapter) DeepCopyInto(out *BackendLoraAdapter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendLoraAdapter.
+func (in *BackendLoraAdapter) DeepCopy() *BackendLoraAdapter {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendLoraAdapter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInference) DeepCopyInto(out *BatchInference) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInference.
+func (in *BatchInference) DeepCopy() *BatchInference {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BatchInference) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceList) DeepCopyInto(out *BatchInferenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BatchInference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceList.
+func (in *BatchInferenceList) DeepCopy() *BatchInferenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BatchInferenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceSpec) DeepCopyInto(out *BatchInferenceSpec) {
+	*out = *in
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CompletionWindow != nil {
+		in, out := &in.CompletionWindow, &out.CompletionWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceSpec.
+func (in *BatchInferenceSpec) DeepCopy() *BatchInferenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceStatus) DeepCopyInto(out *BatchInferenceStatus) {
+	*out = *in
+	out.RequestCounts = in.RequestCounts
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceStatus.
+func (in *BatchInferenceStatus) DeepCopy() *BatchInferenceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchRequestCounts) DeepCopyInto(out *BatchRequestCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchRequestCounts.
+func (in *BatchRequestCounts) DeepCopy() *BatchRequestCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchRequestCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUSharing) DeepCopyInto(out *GPUSharing) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUSharing.
+func (in *GPUSharing) DeepCopy() *GPUSharing {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUSharing)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GangPolicy) DeepCopyInto(out *GangPolicy) {
 	*out = *in
@@ -366,6 +633,10 @@ func (in *GangPolicy) DeepCopy() *GangPolicy {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoraAdapter) DeepCopyInto(out *LoraAdapter) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoraAdapter.
@@ -378,6 +649,88 @@ func (in *LoraAdapter) DeepCopy() *LoraAdapter {
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LoraAdapter) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoraAdapterList) DeepCopyInto(out *LoraAdapterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LoraAdapter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoraAdapterList.
+func (in *LoraAdapterList) DeepCopy() *LoraAdapterList {
+	if in == nil {
+		return nil
+	}
+	out := new(LoraAdapterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LoraAdapterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoraAdapterSpec) DeepCopyInto(out *LoraAdapterSpec) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoraAdapterSpec.
+func (in *LoraAdapterSpec) DeepCopy() *LoraAdapterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoraAdapterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoraAdapterStatus) DeepCopyInto(out *LoraAdapterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoraAdapterStatus.
+func (in *LoraAdapterStatus) DeepCopy() *LoraAdapterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LoraAdapterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Metadata) DeepCopyInto(out *Metadata) {
 	*out = *in
@@ -425,6 +778,11 @@ func (in *MetricEndpoint) DeepCopy() *MetricEndpoint {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelBackend) DeepCopyInto(out *ModelBackend) {
 	*out = *in
+	if in.Download != nil {
+		in, out := &in.Download, &out.Download
+		*out = new(ModelDownloadPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.EnvFrom != nil {
 		in, out := &in.EnvFrom, &out.EnvFrom
 		*out = make([]corev1.EnvFromSource, len(*in))
@@ -458,7 +816,7 @@ func (in *ModelBackend) DeepCopyInto(out *ModelBackend) {
 	}
 	if in.LoraAdapters != nil {
 		in, out := &in.LoraAdapters, &out.LoraAdapters
-		*out = make([]LoraAdapter, len(*in))
+		*out = make([]BackendLoraAdapter, len(*in))
 		copy(*out, *in)
 	}
 	if in.AutoscalingPolicy != nil {
@@ -481,6 +839,11 @@ func (in *ModelBackend) DeepCopy() *ModelBackend {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelBackendStatus) DeepCopyInto(out *ModelBackendStatus) {
 	*out = *in
+	if in.Download != nil {
+		in, out := &in.Download, &out.Download
+		*out = new(ModelDownloadStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelBackendStatus.
@@ -577,6 +940,11 @@ func (in *ModelBoosterSpec) DeepCopyInto(out *ModelBoosterSpec) {
 		*out = new(networkingv1alpha1.ModelMatch)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Card != nil {
+		in, out := &in.Card, &out.Card
+		*out = new(ModelCard)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelBoosterSpec.
@@ -589,6 +957,87 @@ func (in *ModelBoosterSpec) DeepCopy() *ModelBoosterSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelCard) DeepCopyInto(out *ModelCard) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelCard.
+func (in *ModelCard) DeepCopy() *ModelCard {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelCard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelDownloadPolicy) DeepCopyInto(out *ModelDownloadPolicy) {
+	*out = *in
+	if in.Checksums != nil {
+		in, out := &in.Checksums, &out.Checksums
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MaxConcurrency != nil {
+		in, out := &in.MaxConcurrency, &out.MaxConcurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ChunkSizeMB != nil {
+		in, out := &in.ChunkSizeMB, &out.ChunkSizeMB
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Attestation != nil {
+		in, out := &in.Attestation, &out.Attestation
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelDownloadPolicy.
+func (in *ModelDownloadPolicy) DeepCopy() *ModelDownloadPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelDownloadPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelDownloadStatus) DeepCopyInto(out *ModelDownloadStatus) {
+	*out = *in
+	if in.EstimatedTimeRemaining != nil {
+		in, out := &in.EstimatedTimeRemaining, &out.EstimatedTimeRemaining
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelDownloadStatus.
+func (in *ModelDownloadStatus) DeepCopy() *ModelDownloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelDownloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelServing) DeepCopyInto(out *ModelServing) {
 	*out = *in
@@ -656,12 +1105,22 @@ func (in *ModelServingSpec) DeepCopyInto(out *ModelServingSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.StandbyReplicas != nil {
+		in, out := &in.StandbyReplicas, &out.StandbyReplicas
+		*out = new(int32)
+		**out = **in
+	}
 	in.Template.DeepCopyInto(&out.Template)
 	if in.RolloutStrategy != nil {
 		in, out := &in.RolloutStrategy, &out.RolloutStrategy
 		*out = new(RolloutStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RecoveryBackoff != nil {
+		in, out := &in.RecoveryBackoff, &out.RecoveryBackoff
+		*out = new(RecoveryBackoff)
+		**out = **in
+	}
 	if in.TopologySpreadConstraints != nil {
 		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
 		*out = make([]TopologySpreadConstraint, len(*in))
@@ -691,6 +1150,20 @@ func (in *ModelServingStatus) DeepCopyInto(out *ModelServingStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ResourceRecommendations != nil {
+		in, out := &in.ResourceRecommendations, &out.ResourceRecommendations
+		*out = make([]ResourceRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]ServingGroupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelServingStatus.
@@ -716,7 +1189,9 @@ func (in *ModelStatus) DeepCopyInto(out *ModelStatus) {
 	if in.BackendStatuses != nil {
 		in, out := &in.BackendStatuses, &out.BackendStatuses
 		*out = make([]ModelBackendStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -807,6 +1282,37 @@ func (in *PodTemplateSpec) DeepCopy() *PodTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryBackoff) DeepCopyInto(out *RecoveryBackoff) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryBackoff.
+func (in *RecoveryBackoff) DeepCopy() *RecoveryBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendation) DeepCopyInto(out *ResourceRecommendation) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendation.
+func (in *ResourceRecommendation) DeepCopy() *ResourceRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Role) DeepCopyInto(out *Role) {
 	*out = *in
@@ -821,6 +1327,16 @@ func (in *Role) DeepCopyInto(out *Role) {
 		*out = new(PodTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(RolePlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GPUSharing != nil {
+		in, out := &in.GPUSharing, &out.GPUSharing
+		*out = new(GPUSharing)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Role.
@@ -833,6 +1349,56 @@ func (in *Role) DeepCopy() *Role {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolePlacement) DeepCopyInto(out *RolePlacement) {
+	*out = *in
+	if in.TopologySpread != nil {
+		in, out := &in.TopologySpread, &out.TopologySpread
+		*out = make([]RoleTopologySpread, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolePlacement.
+func (in *RolePlacement) DeepCopy() *RolePlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(RolePlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleReplicaStatus) DeepCopyInto(out *RoleReplicaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleReplicaStatus.
+func (in *RoleReplicaStatus) DeepCopy() *RoleReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleTopologySpread) DeepCopyInto(out *RoleTopologySpread) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTopologySpread.
+func (in *RoleTopologySpread) DeepCopy() *RoleTopologySpread {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleTopologySpread)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RollingUpdateConfiguration) DeepCopyInto(out *RollingUpdateConfiguration) {
 	*out = *in
@@ -891,6 +1457,29 @@ func (in *ScalingConfiguration) DeepCopy() *ScalingConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingDecision) DeepCopyInto(out *ScalingDecision) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make(map[string]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingDecision.
+func (in *ScalingDecision) DeepCopy() *ScalingDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServingGroup) DeepCopyInto(out *ServingGroup) {
 	*out = *in
@@ -928,6 +1517,27 @@ func (in *ServingGroup) DeepCopy() *ServingGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServingGroupStatus) DeepCopyInto(out *ServingGroupStatus) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleReplicaStatus, len(*in))
+		copy(*out, *in)
+	}
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServingGroupStatus.
+func (in *ServingGroupStatus) DeepCopy() *ServingGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServingGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Target) DeepCopyInto(out *Target) {
 	*out = *in
@@ -952,6 +1562,94 @@ func (in *Target) DeepCopy() *Target {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenancyPolicy) DeepCopyInto(out *TenancyPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenancyPolicy.
+func (in *TenancyPolicy) DeepCopy() *TenancyPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TenancyPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenancyPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenancyPolicyList) DeepCopyInto(out *TenancyPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TenancyPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenancyPolicyList.
+func (in *TenancyPolicyList) DeepCopy() *TenancyPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenancyPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenancyPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenancyPolicySpec) DeepCopyInto(out *TenancyPolicySpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedModels != nil {
+		in, out := &in.AllowedModels, &out.AllowedModels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxGPUs != nil {
+		in, out := &in.MaxGPUs, &out.MaxGPUs
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenancyPolicySpec.
+func (in *TenancyPolicySpec) DeepCopy() *TenancyPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenancyPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopologySpreadConstraint) DeepCopyInto(out *TopologySpreadConstraint) {
 	*out = *in
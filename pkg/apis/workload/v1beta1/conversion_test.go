@@ -0,0 +1,101 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/randfill"
+
+	"github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+// roundTrips is the number of random objects converted in each direction.
+const roundTrips = 200
+
+// quantityFuncs fill the quantities with canonical values, the random internal fields of a quantity are not comparable.
+func quantityFuncs(_ serializer.CodecFactory) []interface{} {
+	return []interface{}{
+		func(q *resource.Quantity, c randfill.Continue) {
+			*q = *resource.NewQuantity(c.Int63n(1000), resource.DecimalSI)
+		},
+	}
+}
+
+// TestModelServingRoundTrip converts random ModelServings to the other version and back, which must not lose any field.
+// A field added, renamed or defaulted in one version only fails here until its conversion functions are written.
+func TestModelServingRoundTrip(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, AddToScheme(scheme))
+	codecs := serializer.NewCodecFactory(scheme)
+	seed := rand.Int63()
+	t.Logf("seed: %d", seed)
+	filler := fuzzer.FuzzerFor(fuzzer.MergeFuzzerFuncs(metafuzzer.Funcs, quantityFuncs), rand.NewSource(seed), codecs).NilChance(0.2)
+
+	tests := []struct {
+		name     string
+		newHub   func() runtime.Object
+		newSpoke func() runtime.Object
+	}{
+		{
+			name:     "v1alpha1 to v1beta1",
+			newHub:   func() runtime.Object { return &v1alpha1.ModelServing{} },
+			newSpoke: func() runtime.Object { return &ModelServing{} },
+		},
+		{
+			name:     "v1beta1 to v1alpha1",
+			newHub:   func() runtime.Object { return &ModelServing{} },
+			newSpoke: func() runtime.Object { return &v1alpha1.ModelServing{} },
+		},
+		{
+			name:     "v1alpha1 list to v1beta1",
+			newHub:   func() runtime.Object { return &v1alpha1.ModelServingList{} },
+			newSpoke: func() runtime.Object { return &ModelServingList{} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < roundTrips; i++ {
+				original := tt.newHub()
+				filler.Fill(original)
+				// The type is set by the serializer, not by the conversion
+				original.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+
+				spoke := tt.newSpoke()
+				require.NoError(t, scheme.Convert(original.DeepCopyObject(), spoke, nil))
+				roundTripped := tt.newHub()
+				require.NoError(t, scheme.Convert(spoke, roundTripped, nil))
+				roundTripped.GetObjectKind().SetGroupVersionKind(original.GetObjectKind().GroupVersionKind())
+
+				if !apiequality.Semantic.DeepEqual(original, roundTripped) {
+					t.Fatalf("round trip %d changed the object:\n%s", i, cmp.Diff(original, roundTripped))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta1 is the v1beta1 version of the workload.serving.volcano.sh API. It serves the ModelServings
+// alongside v1alpha1, which remains the storage version. The objects are converted between the versions by
+// the conversion webhook of kthena-controller-manager, with the functions generated by conversion-gen.
+// The fields renamed or defaulted differently in v1beta1 are converted by functions written next to them.
+// +kubebuilder:object:generate=true
+// +k8s:conversion-gen=github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1
+// +groupName=workload.serving.volcano.sh
+package v1beta1
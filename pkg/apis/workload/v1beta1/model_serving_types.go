@@ -0,0 +1,419 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ModelServingSpec defines the specification of the ModelServing resource.
+type ModelServingSpec struct {
+	// Number of ServingGroups. That is the number of instances that run serving tasks
+	// Default to 1.
+	//
+	// +optional
+	// +kubebuilder:default=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// StandbyReplicas is the number of extra ServingGroups kept warm, with the model loaded, on top of Replicas.
+	// The pods of standby ServingGroups are labeled with modelserving.volcano.sh/standby and are not routed to.
+	// Scaling up activates the standby ServingGroups first, and a new standby ServingGroup is created in their place.
+	// Default to 0.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	StandbyReplicas *int32 `json:"standbyReplicas,omitempty"`
+
+	// SchedulerName defines the name of the scheduler used by ModelServing.
+	// It also selects the gang scheduling backend: volcano uses Volcano PodGroups, kueue admits each
+	// ServingGroup as a Kueue Workload and scheduler-plugins-scheduler uses the PodGroups of the coscheduling plugin.
+	SchedulerName string `json:"schedulerName"`
+
+	// Template defines the template for ServingGroup
+	Template ServingGroup `json:"template"`
+
+	// RolloutStrategy defines the strategy that will be applied to update replicas
+	// +optional
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+
+	// RecoveryPolicy defines the recovery policy for the failed Pod to be rebuilt
+	// +kubebuilder:default=RoleRecreate
+	// +kubebuilder:validation:Enum={ServingGroupRecreate,RoleRecreate,None}
+	// +optional
+	RecoveryPolicy RecoveryPolicy `json:"recoveryPolicy,omitempty"`
+
+	// RecoveryBackoff delays the recreation of the ServingGroups whose pods keep failing, so that a bad image or
+	// configuration is not recreated in a hot loop. The delay doubles with each failure in a row, from 10s up to
+	// 5m by default, and is reset once the ServingGroup is running.
+	// +optional
+	RecoveryBackoff *RecoveryBackoff `json:"recoveryBackoff,omitempty"`
+
+	// DisruptionPolicy defines the PodDisruptionBudgets managed for the ServingGroups, so that voluntary
+	// disruptions such as node drains never evict a part of a ServingGroup or of a role replica.
+	// +kubebuilder:default=None
+	// +kubebuilder:validation:Enum={ServingGroupIntact,RoleIntact,None}
+	// +optional
+	DisruptionPolicy DisruptionPolicy `json:"disruptionPolicy,omitempty"`
+
+	// Paused freezes the reconciliation of the ModelServing while leaving its pods running:
+	// ServingGroups are neither scaled nor rolled out, failed pods are not recreated and the autoscaler
+	// skips the ModelServing. It allows safe manual intervention during incidents.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Suspend scales the ModelServing to zero: the pods of all its ServingGroups are deleted, while the
+	// ServingGroups keep their headless services and gang scheduling PodGroups and spec.replicas is kept,
+	// so that resuming only recreates the pods of the same ServingGroups.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	TopologySpreadConstraints []TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+type RecoveryPolicy string
+
+const (
+	// ServingGroupRecreate will recreate all the pods in the ServingGroup if
+	// 1. Any individual pod in the group is recreated; 2. Any containers/init-containers
+	// in a pod is restarted. This is to ensure all pods/containers in the group will be
+	// started in the same time.
+	ServingGroupRecreate RecoveryPolicy = "ServingGroupRecreate"
+
+	// RoleRecreate will recreate all pods in one Role if
+	// 1. Any individual pod in the group is recreated; 2. Any containers/init-containers
+	// in a pod is restarted.
+	RoleRecreate RecoveryPolicy = "RoleRecreate"
+
+	// NoneRestartPolicy will follow the same behavior as the default pod or deployment.
+	NoneRestartPolicy RecoveryPolicy = "None"
+)
+
+// RecoveryBackoff defines the exponential backoff of the recreation of the failed ServingGroups and roles.
+type RecoveryBackoff struct {
+	// InitialDelaySeconds is the delay before recreating a ServingGroup after its first failure.
+	// Default to 10.
+	// +optional
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+
+	// MaxDelaySeconds caps the delay, which doubles with each failure in a row.
+	// Default to 300.
+	// +optional
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=1
+	MaxDelaySeconds int32 `json:"maxDelaySeconds,omitempty"`
+
+	// MaxRetries is the number of times a ServingGroup failing in a row is recreated, it is then left failed until its
+	// backoff is reset with the modelserving.volcano.sh/reset-recovery-backoff annotation.
+	// Default to 0, which recreates it forever.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries int32 `json:"maxRetries,omitempty"`
+}
+
+type DisruptionPolicy string
+
+const (
+	// ServingGroupIntact creates a PodDisruptionBudget for each ServingGroup, which blocks the
+	// voluntary eviction of any pod of the group.
+	ServingGroupIntact DisruptionPolicy = "ServingGroupIntact"
+
+	// RoleIntact creates a PodDisruptionBudget for each role replica, which blocks the
+	// voluntary eviction of any pod of the role replica.
+	RoleIntact DisruptionPolicy = "RoleIntact"
+
+	// DisruptionPolicyNone does not create PodDisruptionBudgets.
+	DisruptionPolicyNone DisruptionPolicy = "None"
+)
+
+// RolloutStrategy defines the strategy that the ModelServing controller
+// will use to perform replica updates.
+type RolloutStrategy struct {
+	// Type defines the rollout strategy, it can only be “ServingGroupRollingUpdate” for now.
+	//
+	// +kubebuilder:validation:Enum={ServingGroupRollingUpdate}
+	// +kubebuilder:default=ServingGroupRollingUpdate
+	Type RolloutStrategyType `json:"type"`
+
+	// RollingUpdateConfiguration defines the parameters to be used when type is RollingUpdateStrategyType.
+	// optional
+	RollingUpdateConfiguration *RollingUpdateConfiguration `json:"rollingUpdateConfiguration,omitempty"`
+}
+
+type RolloutStrategyType string
+
+const (
+	// ServingGroupRollingUpdate indicates that ServingGroup replicas will be updated one by one.
+	ServingGroupRollingUpdate RolloutStrategyType = "ServingGroupRollingUpdate"
+)
+
+// RollingUpdateConfiguration defines the parameters to be used for RollingUpdateStrategyType.
+type RollingUpdateConfiguration struct {
+	// The maximum number of replicas that can be unavailable during the update.
+	// Value can be an absolute number (ex: 5) or a percentage of total replicas at the start of update (ex: 10%).
+	// Absolute number is calculated from percentage by rounding down.
+	// This can not be 0 if MaxSurge is 0.
+	// By default, a fixed value of 1 is used.
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:default=1
+	MaxUnavailable intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// The maximum number of replicas that can be scheduled above the original number of
+	// replicas.
+	// Value can be an absolute number (ex: 5) or a percentage of total replicas at
+	// the start of the update (ex: 10%).
+	// Absolute number is calculated from percentage by rounding up.
+	// By default, a value of 0 is used.
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:default=0
+	MaxSurge intstr.IntOrString `json:"maxSurge,omitempty"`
+	// Partition indicates the ordinal at which the ModelServing should be partitioned
+	// for updates. During a rolling update, all ServingGroups from ordinal Replicas-1 to
+	// Partition are updated. All ServingGroups from ordinal Partition-1 to 0 remain untouched.
+	// The default value is 0.
+	// +optional
+	Partition *int32 `json:"partition,omitempty"`
+}
+
+// TopologySpreadConstraint defines the topology spread constraint.
+type TopologySpreadConstraint struct {
+	// MaxSkew describes the degree to which ServingGroup may be unevenly distributed.
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+
+	// TopologyKey is the key of node labels. Nodes that have a label with this key
+	// and identical values are considered to be in the same topology.
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// WhenUnsatisfiable indicates how to deal with an ServingGroup if it doesn't satisfy
+	// the spread constraint.
+	WhenUnsatisfiable string `json:"whenUnsatisfiable,omitempty"`
+
+	// LabelSelector is used to find matching ServingGroups.
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+type ModelServingConditionType string
+
+// There is a condition type of a modelServing
+const (
+	// ModelServingSetAvailable means the modelServing is available,
+	// at least the minimum available groups are up and running.
+	ModelServingAvailable ModelServingConditionType = "Available"
+
+	// The ModelServing enters the ModelServingSetProgressing state whenever there are ongoing changes,
+	// such as the creation of new groups or the scaling of pods within a group.
+	// A group remains in the progressing state until all its pods become ready.
+	// As long as at least one group is progressing, the entire ModelServing Set is also considered progressing.
+	ModelServingProgressing ModelServingConditionType = "Progressing"
+
+	// ModelServingSetUpdateInProgress indicates that modelServing is performing a rolling update.
+	// When the entry or worker template is updated, modelServing controller enters the upgrade process and
+	// UpdateInProgress is set to true.
+	ModelServingUpdateInProgress ModelServingConditionType = "UpdateInProgress"
+
+	// ModelServingWaitingForNodeProvisioning indicates that pods of the modelServing can not be scheduled
+	// because no node has enough accelerators, and are waiting for the cluster autoscaler to provision nodes.
+	// The condition message carries the estimated time the nodes will be ready.
+	ModelServingWaitingForNodeProvisioning ModelServingConditionType = "WaitingForNodeProvisioning"
+
+	// ModelServingUnschedulable indicates that ServingGroups of the modelServing are not created because their pods
+	// do not fit on the nodes of the cluster, by their resources, node selectors, affinities, tolerations or
+	// colocation. The condition message carries the reasons. They are created once nodes they fit on join the cluster.
+	ModelServingUnschedulable ModelServingConditionType = "Unschedulable"
+
+	// ModelServingRecoveryBackOff indicates that failed ServingGroups of the modelServing are waiting for their
+	// recovery backoff before being recreated, or are no longer recreated after exceeding their retries.
+	// The condition message lists them with their failures in a row.
+	ModelServingRecoveryBackOff ModelServingConditionType = "RecoveryBackOff"
+
+	// ModelServingPreempted indicates that pods of ServingGroups of the modelServing were preempted by the
+	// scheduler in favor of workloads of a higher priority. The condition message lists the ServingGroups with
+	// the reason given by the scheduler, it is unset once they are running again.
+	ModelServingPreempted ModelServingConditionType = "Preempted"
+
+	// ModelServingWeightsLoaded indicates that the engines of all the pods reporting their startup progress
+	// have loaded the model weights.
+	ModelServingWeightsLoaded ModelServingConditionType = "WeightsLoaded"
+
+	// ModelServingEngineWarmedUp indicates that the engines of all the pods reporting their startup progress
+	// have been warmed up, so that the first user request does not hit a cold engine.
+	ModelServingEngineWarmedUp ModelServingConditionType = "EngineWarmedUp"
+
+	// ModelServingPaused indicates that the reconciliation of the modelServing is paused by spec.paused.
+	ModelServingPaused ModelServingConditionType = "Paused"
+
+	// ModelServingSuspended indicates that the modelServing is scaled to zero by spec.suspend.
+	ModelServingSuspended ModelServingConditionType = "Suspended"
+
+	// ModelServingErrorBudgetAvailable is set by the router, when it is configured to, on the ModelServings
+	// serving a ModelServer with service level objectives. It is false once one of their error budgets is
+	// exhausted, so that the autoscaling or the alerting can act on it.
+	ModelServingErrorBudgetAvailable ModelServingConditionType = "ErrorBudgetAvailable"
+)
+
+// ModelServingStatus defines the observed state of ModelServing
+type ModelServingStatus struct {
+	// observedGeneration is the most recent generation observed for ModelServing. It corresponds to the
+	// ModelServing's generation, which is updated on mutation by the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Replicas track the total number of active ServingGroup that have been created (updated or not, ready or not)
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// CurrentReplicas is the number of ServingGroup created by the ModelServing controller from the ModelServing version
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+
+	// UpdatedReplicas track the number of ServingGroup that have been updated (ready or not).
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
+	// AvailableReplicas track the number of ServingGroup that are in ready state (updated or not).
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+
+	// StandbyReplicas track the number of standby ServingGroup that are in ready state.
+	// Standby ServingGroups are not counted in the other replicas of the status.
+	// +optional
+	StandbyReplicas int32 `json:"standbyReplicas,omitempty"`
+
+	// LoadingPercentage is the average startup progress, from weight loading to engine warm-up, of the pods
+	// reporting their startup progress.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	LoadingPercentage int32 `json:"loadingPercentage,omitempty"`
+
+	// Conditions track the condition of the ModelServing.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LabelSelector is the label selector of the pods of the ModelServing, in the string form. It is the
+	// selector of the scale subresource, used by kubectl scale and the HorizontalPodAutoscaler.
+	// +optional
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// ResourceRecommendations are the engine resources recommended by the autoscaler for the ModelServing
+	// or its roles, when vertical recommendation is enabled in the autoscaling policy.
+	// +optional
+	ResourceRecommendations []ResourceRecommendation `json:"resourceRecommendations,omitempty"`
+
+	// Groups are the status of the ServingGroups, ordered by ordinal. At most MaxServingGroupStatuses of them
+	// are listed, the ServingGroups which are not running first.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	Groups []ServingGroupStatus `json:"groups,omitempty"`
+}
+
+// MaxServingGroupStatuses is the maximum number of ServingGroups listed in the status of a ModelServing,
+// which bounds its size.
+const MaxServingGroupStatuses = 100
+
+// ServingGroupPhase is the lifecycle phase of a ServingGroup.
+type ServingGroupPhase string
+
+const (
+	// ServingGroupPhaseCreating is a ServingGroup whose pods are not all running and ready yet.
+	ServingGroupPhaseCreating ServingGroupPhase = "Creating"
+	// ServingGroupPhaseRunning is a ServingGroup whose pods are all running and ready.
+	ServingGroupPhaseRunning ServingGroupPhase = "Running"
+	// ServingGroupPhaseScaling is a ServingGroup whose roles are scaled.
+	ServingGroupPhaseScaling ServingGroupPhase = "Scaling"
+	// ServingGroupPhaseDeleting is a ServingGroup being deleted, to be recreated or scaled in.
+	ServingGroupPhaseDeleting ServingGroupPhase = "Deleting"
+	// ServingGroupPhaseSuspended is a ServingGroup without pods while its ModelServing is suspended.
+	ServingGroupPhaseSuspended ServingGroupPhase = "Suspended"
+)
+
+// ServingGroupStatus is the status of a ServingGroup of a ModelServing.
+type ServingGroupStatus struct {
+	// Name is the name of the ServingGroup, the name of the ModelServing and its ordinal.
+	Name string `json:"name"`
+	// Phase is the lifecycle phase of the ServingGroup.
+	Phase ServingGroupPhase `json:"phase"`
+	// Revision is the revision of the roles the pods of the ServingGroup were created from.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+	// Standby is set for the standby ServingGroups, which do not serve traffic.
+	// +optional
+	Standby bool `json:"standby,omitempty"`
+	// Roles are the replicas of each role of the ServingGroup.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Roles []RoleReplicaStatus `json:"roles,omitempty"`
+	// LastTransitionTime is the last time the phase of the ServingGroup changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// RoleReplicaStatus is the number of replicas of a role in a ServingGroup.
+type RoleReplicaStatus struct {
+	// Name is the name of the role.
+	Name string `json:"name"`
+	// Replicas is the number of replicas of the role created in the ServingGroup.
+	Replicas int32 `json:"replicas"`
+	// ReadyReplicas is the number of replicas of the role whose entry and worker pods are all running and ready.
+	ReadyReplicas int32 `json:"readyReplicas"`
+}
+
+// ResourceRecommendation is the engine resources recommended for the instances of a ModelServing role.
+type ResourceRecommendation struct {
+	// Role is the name of the role the recommendation is made for, empty if it is made for the whole ModelServing.
+	// +optional
+	Role string `json:"role,omitempty"`
+	// GPUMemoryUtilizationPercent is the recommended fraction of the GPU memory used by the engine, in percent.
+	// For vLLM it is the --gpu-memory-utilization argument multiplied by 100.
+	GPUMemoryUtilizationPercent int32 `json:"gpuMemoryUtilizationPercent"`
+	// TensorParallelSize is the recommended number of GPUs a model instance is sharded across.
+	TensorParallelSize int32 `json:"tensorParallelSize"`
+	// PeakKVCacheUsagePercent is the peak KV-cache usage of an instance observed over the window.
+	PeakKVCacheUsagePercent int32 `json:"peakKVCacheUsagePercent"`
+	// PeakBatchSize is the peak number of requests running in a batch of an instance observed over the window.
+	PeakBatchSize int32 `json:"peakBatchSize"`
+	// Reason is a human readable explanation of the recommendation.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// LastUpdateTime is the last time the recommendation changed.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.labelSelector
+// +genclient
+
+// ModelServing is the Schema for the LLM Serving API
+type ModelServing struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ModelServingSpec   `json:"spec,omitempty"`
+	Status            ModelServingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ModelServingList contains a list of ModelServing
+type ModelServingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ModelServing `json:"items"`
+}
@@ -0,0 +1,191 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	volcanoV1Beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+// GangPolicy defines the gang scheduling configuration.
+// +kubebuilder:validation:XValidation:rule="!has(oldSelf.minRoleReplicas) || has(self.minRoleReplicas)", message="minRoleReplicas is required once set"
+type GangPolicy struct {
+	// MinRoleReplicas defines the minimum number of replicas required for each role
+	// in gang scheduling. This map allows users to specify different
+	// minimum replica requirements for different roles.
+	// Key: role name
+	// Value: minimum number of replicas required for that role
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="minRoleReplicas is immutable"
+	MinRoleReplicas map[string]int32 `json:"minRoleReplicas,omitempty"`
+}
+
+// Role defines the specific pod instance role that performs the inference task.
+type Role struct {
+	// The name of a role. Name must be unique within an ServingGroup
+	// +kubebuilder:validation:MaxLength=12
+	// +kubebuilder:validation:Pattern=^[a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?$
+	Name string `json:"name"`
+
+	// The number of a certain role.
+	// For example, in Disaggregated Prefilling, setting the replica count for both the P and D roles to 1 results in 1P1D deployment configuration.
+	// This approach can similarly be applied to configure a xPyD deployment scenario.
+	// Default to 1.
+	// +optional
+	// +kubebuilder:default=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// EntryTemplate defines the template for the entry pod of a role.
+	// Required: Currently, a role must have only one entry-pod.
+	EntryTemplate PodTemplateSpec `json:"entryTemplate"`
+
+	// WorkerReplicas defines the number for the worker pod of a role.
+	// Required: Need to set the number of worker-pod replicas.
+	WorkerReplicas int32 `json:"workerReplicas"`
+
+	// WorkerTemplate defines the template for the worker pod of a role.
+	// +optional
+	WorkerTemplate *PodTemplateSpec `json:"workerTemplate,omitempty"`
+
+	// Placement defines the topology-aware placement of the pods of the role.
+	// +optional
+	Placement *RolePlacement `json:"placement,omitempty"`
+
+	// GPUSharing runs the pods of the role on a fraction of a GPU. The GPUs requested by the containers of the
+	// templates, as nvidia.com/gpu, are translated into the resources of the MIG instances or time-sliced GPUs.
+	// +optional
+	GPUSharing *GPUSharing `json:"gpuSharing,omitempty"`
+
+	// PriorityClassName is the PriorityClass of the pods of the role, overriding the one of the ServingGroup.
+	// The priority class set in a pod template wins.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// GPUSharingMode is the way a GPU is shared by several pods.
+// +kubebuilder:validation:Enum={MIG,TimeSlicing}
+type GPUSharingMode string
+
+const (
+	// GPUSharingMIG runs the pods on MIG instances, advertised as nvidia.com/mig-<profile> by the NVIDIA device
+	// plugin with the mixed MIG strategy.
+	GPUSharingMIG GPUSharingMode = "MIG"
+	// GPUSharingTimeSlicing runs the pods on GPUs shared by time-slicing, advertised as nvidia.com/gpu.shared by
+	// the NVIDIA device plugin when its shared resources are renamed.
+	GPUSharingTimeSlicing GPUSharingMode = "TimeSlicing"
+)
+
+// GPUSharing defines how the pods of a role share GPUs.
+// +kubebuilder:validation:XValidation:rule="self.mode == 'MIG' ? has(self.migProfile) : !has(self.migProfile)",message="migProfile must be set with, and only with, the MIG mode"
+type GPUSharing struct {
+	// Mode is the way the GPUs are shared.
+	Mode GPUSharingMode `json:"mode"`
+
+	// MIGProfile is the profile of the MIG instances, e.g. 1g.10gb. It must be advertised by a node of the cluster.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+g\.[0-9]+gb(\+me)?$`
+	MIGProfile string `json:"migProfile,omitempty"`
+}
+
+// RolePlacement defines how the pods of a role are placed across the topology of the cluster.
+// It is translated into the affinity and topology spread constraints of the pods.
+type RolePlacement struct {
+	// ColocationTopologyKey keeps the entry and worker pods of each role replica in the same topology domain,
+	// e.g. `kubernetes.io/hostname` for the same node, or the node label of the NVLink domain.
+	// +optional
+	ColocationTopologyKey string `json:"colocationTopologyKey,omitempty"`
+
+	// TopologySpread spreads the replicas of the role, across all the ServingGroups, over topology domains such as zones.
+	// The constraints apply to the entry pods, the worker pods follow them when ColocationTopologyKey is set.
+	// +optional
+	// +kubebuilder:validation:MaxItems=4
+	TopologySpread []RoleTopologySpread `json:"topologySpread,omitempty"`
+}
+
+// RoleTopologySpread defines a topology spread constraint of the replicas of a role.
+type RoleTopologySpread struct {
+	// TopologyKey is the key of node labels. Nodes that have a label with this key
+	// and identical values are considered to be in the same topology.
+	// +kubebuilder:validation:MinLength=1
+	TopologyKey string `json:"topologyKey"`
+
+	// MaxSkew describes the degree to which the replicas of the role may be unevenly distributed.
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+
+	// WhenUnsatisfiable indicates how to deal with a replica if it doesn't satisfy the spread constraint.
+	// +optional
+	// +kubebuilder:default=ScheduleAnyway
+	// +kubebuilder:validation:Enum={DoNotSchedule,ScheduleAnyway}
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+}
+
+// PodTemplateSpec describes the data a pod should have when created from a template
+type PodTemplateSpec struct {
+	// Object's metadata.
+	// +optional
+	Metadata *Metadata `json:"metadata,omitempty"`
+	// Specification of the desired behavior of the pod.
+	// +optional
+	Spec corev1.PodSpec `json:"spec,omitempty"`
+}
+
+// Metadata is a simplified version of ObjectMeta in Kubernetes.
+type Metadata struct {
+	// Map of string keys and values that can be used to organize and categorize
+	// (scope and select) objects. May match selectors of replication controllers
+	// and services.
+	// More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/labels
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations is an unstructured key value map stored with a resource that may be
+	// set by external tools to store and retrieve arbitrary metadata. They are not
+	// queryable and should be preserved when modifying objects.
+	// More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ServingGroup is the smallest unit to complete the inference task
+// +kubebuilder:validation:XValidation:rule="!has(oldSelf.gangPolicy) || has(self.gangPolicy)", message="gangPolicy is required once set"
+type ServingGroup struct {
+	// RestartGracePeriodSeconds defines the grace time for the controller to rebuild the ServingGroup when an error occurs
+	// Defaults to 0 (ServingGroup will be rebuilt immediately after an error)
+	// +optional
+	// +kubebuilder:default=0
+	RestartGracePeriodSeconds *int64 `json:"restartGracePeriodSeconds,omitempty"`
+
+	// GangPolicy defines the gang scheduler config.
+	// +optional
+	GangPolicy *GangPolicy `json:"gangPolicy,omitempty"`
+
+	// NetworkTopology defines the network topology affinity scheduling policy for the roles of the group, it works only when the scheduler supports network topology feature.	// +optional
+	NetworkTopology *volcanoV1Beta1.NetworkTopologySpec `json:"networkTopology,omitempty"`
+
+	// PriorityClassName is the PriorityClass of the pods of the ServingGroups and of their Volcano PodGroups.
+	// The ServingGroups of a lower priority, e.g. of batch inference, are preempted in favor of the ones of a
+	// higher priority, e.g. of online serving, when they do not fit together.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// +kubebuilder:validation:MaxItems=4
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:XValidation:rule="self.all(x, self.exists_one(y, y.name == x.name))", message="roles name must be unique"
+	Roles []Role `json:"roles"`
+}
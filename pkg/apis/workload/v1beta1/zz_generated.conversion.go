@@ -0,0 +1,751 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by conversion-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	unsafe "unsafe"
+
+	v1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+func init() {
+	localSchemeBuilder.Register(RegisterConversions)
+}
+
+// RegisterConversions adds conversion functions to the given scheme.
+// Public to allow building arbitrary schemes.
+func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*GPUSharing)(nil), (*v1alpha1.GPUSharing)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_GPUSharing_To_v1alpha1_GPUSharing(a.(*GPUSharing), b.(*v1alpha1.GPUSharing), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.GPUSharing)(nil), (*GPUSharing)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_GPUSharing_To_v1beta1_GPUSharing(a.(*v1alpha1.GPUSharing), b.(*GPUSharing), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*GangPolicy)(nil), (*v1alpha1.GangPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_GangPolicy_To_v1alpha1_GangPolicy(a.(*GangPolicy), b.(*v1alpha1.GangPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.GangPolicy)(nil), (*GangPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_GangPolicy_To_v1beta1_GangPolicy(a.(*v1alpha1.GangPolicy), b.(*GangPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*Metadata)(nil), (*v1alpha1.Metadata)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Metadata_To_v1alpha1_Metadata(a.(*Metadata), b.(*v1alpha1.Metadata), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.Metadata)(nil), (*Metadata)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Metadata_To_v1beta1_Metadata(a.(*v1alpha1.Metadata), b.(*Metadata), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ModelServing)(nil), (*v1alpha1.ModelServing)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ModelServing_To_v1alpha1_ModelServing(a.(*ModelServing), b.(*v1alpha1.ModelServing), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.ModelServing)(nil), (*ModelServing)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ModelServing_To_v1beta1_ModelServing(a.(*v1alpha1.ModelServing), b.(*ModelServing), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ModelServingList)(nil), (*v1alpha1.ModelServingList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ModelServingList_To_v1alpha1_ModelServingList(a.(*ModelServingList), b.(*v1alpha1.ModelServingList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.ModelServingList)(nil), (*ModelServingList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ModelServingList_To_v1beta1_ModelServingList(a.(*v1alpha1.ModelServingList), b.(*ModelServingList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ModelServingSpec)(nil), (*v1alpha1.ModelServingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ModelServingSpec_To_v1alpha1_ModelServingSpec(a.(*ModelServingSpec), b.(*v1alpha1.ModelServingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.ModelServingSpec)(nil), (*ModelServingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ModelServingSpec_To_v1beta1_ModelServingSpec(a.(*v1alpha1.ModelServingSpec), b.(*ModelServingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ModelServingStatus)(nil), (*v1alpha1.ModelServingStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ModelServingStatus_To_v1alpha1_ModelServingStatus(a.(*ModelServingStatus), b.(*v1alpha1.ModelServingStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.ModelServingStatus)(nil), (*ModelServingStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ModelServingStatus_To_v1beta1_ModelServingStatus(a.(*v1alpha1.ModelServingStatus), b.(*ModelServingStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PodTemplateSpec)(nil), (*v1alpha1.PodTemplateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_PodTemplateSpec_To_v1alpha1_PodTemplateSpec(a.(*PodTemplateSpec), b.(*v1alpha1.PodTemplateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.PodTemplateSpec)(nil), (*PodTemplateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PodTemplateSpec_To_v1beta1_PodTemplateSpec(a.(*v1alpha1.PodTemplateSpec), b.(*PodTemplateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RecoveryBackoff)(nil), (*v1alpha1.RecoveryBackoff)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RecoveryBackoff_To_v1alpha1_RecoveryBackoff(a.(*RecoveryBackoff), b.(*v1alpha1.RecoveryBackoff), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.RecoveryBackoff)(nil), (*RecoveryBackoff)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RecoveryBackoff_To_v1beta1_RecoveryBackoff(a.(*v1alpha1.RecoveryBackoff), b.(*RecoveryBackoff), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ResourceRecommendation)(nil), (*v1alpha1.ResourceRecommendation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ResourceRecommendation_To_v1alpha1_ResourceRecommendation(a.(*ResourceRecommendation), b.(*v1alpha1.ResourceRecommendation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.ResourceRecommendation)(nil), (*ResourceRecommendation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ResourceRecommendation_To_v1beta1_ResourceRecommendation(a.(*v1alpha1.ResourceRecommendation), b.(*ResourceRecommendation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*Role)(nil), (*v1alpha1.Role)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Role_To_v1alpha1_Role(a.(*Role), b.(*v1alpha1.Role), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.Role)(nil), (*Role)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Role_To_v1beta1_Role(a.(*v1alpha1.Role), b.(*Role), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RolePlacement)(nil), (*v1alpha1.RolePlacement)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RolePlacement_To_v1alpha1_RolePlacement(a.(*RolePlacement), b.(*v1alpha1.RolePlacement), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.RolePlacement)(nil), (*RolePlacement)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RolePlacement_To_v1beta1_RolePlacement(a.(*v1alpha1.RolePlacement), b.(*RolePlacement), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RoleReplicaStatus)(nil), (*v1alpha1.RoleReplicaStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RoleReplicaStatus_To_v1alpha1_RoleReplicaStatus(a.(*RoleReplicaStatus), b.(*v1alpha1.RoleReplicaStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.RoleReplicaStatus)(nil), (*RoleReplicaStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RoleReplicaStatus_To_v1beta1_RoleReplicaStatus(a.(*v1alpha1.RoleReplicaStatus), b.(*RoleReplicaStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RoleTopologySpread)(nil), (*v1alpha1.RoleTopologySpread)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RoleTopologySpread_To_v1alpha1_RoleTopologySpread(a.(*RoleTopologySpread), b.(*v1alpha1.RoleTopologySpread), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.RoleTopologySpread)(nil), (*RoleTopologySpread)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RoleTopologySpread_To_v1beta1_RoleTopologySpread(a.(*v1alpha1.RoleTopologySpread), b.(*RoleTopologySpread), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RollingUpdateConfiguration)(nil), (*v1alpha1.RollingUpdateConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RollingUpdateConfiguration_To_v1alpha1_RollingUpdateConfiguration(a.(*RollingUpdateConfiguration), b.(*v1alpha1.RollingUpdateConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.RollingUpdateConfiguration)(nil), (*RollingUpdateConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RollingUpdateConfiguration_To_v1beta1_RollingUpdateConfiguration(a.(*v1alpha1.RollingUpdateConfiguration), b.(*RollingUpdateConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RolloutStrategy)(nil), (*v1alpha1.RolloutStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RolloutStrategy_To_v1alpha1_RolloutStrategy(a.(*RolloutStrategy), b.(*v1alpha1.RolloutStrategy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.RolloutStrategy)(nil), (*RolloutStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RolloutStrategy_To_v1beta1_RolloutStrategy(a.(*v1alpha1.RolloutStrategy), b.(*RolloutStrategy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ServingGroup)(nil), (*v1alpha1.ServingGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ServingGroup_To_v1alpha1_ServingGroup(a.(*ServingGroup), b.(*v1alpha1.ServingGroup), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.ServingGroup)(nil), (*ServingGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ServingGroup_To_v1beta1_ServingGroup(a.(*v1alpha1.ServingGroup), b.(*ServingGroup), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ServingGroupStatus)(nil), (*v1alpha1.ServingGroupStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ServingGroupStatus_To_v1alpha1_ServingGroupStatus(a.(*ServingGroupStatus), b.(*v1alpha1.ServingGroupStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.ServingGroupStatus)(nil), (*ServingGroupStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ServingGroupStatus_To_v1beta1_ServingGroupStatus(a.(*v1alpha1.ServingGroupStatus), b.(*ServingGroupStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TopologySpreadConstraint)(nil), (*v1alpha1.TopologySpreadConstraint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_TopologySpreadConstraint_To_v1alpha1_TopologySpreadConstraint(a.(*TopologySpreadConstraint), b.(*v1alpha1.TopologySpreadConstraint), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.TopologySpreadConstraint)(nil), (*TopologySpreadConstraint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_TopologySpreadConstraint_To_v1beta1_TopologySpreadConstraint(a.(*v1alpha1.TopologySpreadConstraint), b.(*TopologySpreadConstraint), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1beta1_GPUSharing_To_v1alpha1_GPUSharing(in *GPUSharing, out *v1alpha1.GPUSharing, s conversion.Scope) error {
+	out.Mode = v1alpha1.GPUSharingMode(in.Mode)
+	out.MIGProfile = in.MIGProfile
+	return nil
+}
+
+// Convert_v1beta1_GPUSharing_To_v1alpha1_GPUSharing is an autogenerated conversion function.
+func Convert_v1beta1_GPUSharing_To_v1alpha1_GPUSharing(in *GPUSharing, out *v1alpha1.GPUSharing, s conversion.Scope) error {
+	return autoConvert_v1beta1_GPUSharing_To_v1alpha1_GPUSharing(in, out, s)
+}
+
+func autoConvert_v1alpha1_GPUSharing_To_v1beta1_GPUSharing(in *v1alpha1.GPUSharing, out *GPUSharing, s conversion.Scope) error {
+	out.Mode = GPUSharingMode(in.Mode)
+	out.MIGProfile = in.MIGProfile
+	return nil
+}
+
+// Convert_v1alpha1_GPUSharing_To_v1beta1_GPUSharing is an autogenerated conversion function.
+func Convert_v1alpha1_GPUSharing_To_v1beta1_GPUSharing(in *v1alpha1.GPUSharing, out *GPUSharing, s conversion.Scope) error {
+	return autoConvert_v1alpha1_GPUSharing_To_v1beta1_GPUSharing(in, out, s)
+}
+
+func autoConvert_v1beta1_GangPolicy_To_v1alpha1_GangPolicy(in *GangPolicy, out *v1alpha1.GangPolicy, s conversion.Scope) error {
+	out.MinRoleReplicas = *(*map[string]int32)(unsafe.Pointer(&in.MinRoleReplicas))
+	return nil
+}
+
+// Convert_v1beta1_GangPolicy_To_v1alpha1_GangPolicy is an autogenerated conversion function.
+func Convert_v1beta1_GangPolicy_To_v1alpha1_GangPolicy(in *GangPolicy, out *v1alpha1.GangPolicy, s conversion.Scope) error {
+	return autoConvert_v1beta1_GangPolicy_To_v1alpha1_GangPolicy(in, out, s)
+}
+
+func autoConvert_v1alpha1_GangPolicy_To_v1beta1_GangPolicy(in *v1alpha1.GangPolicy, out *GangPolicy, s conversion.Scope) error {
+	out.MinRoleReplicas = *(*map[string]int32)(unsafe.Pointer(&in.MinRoleReplicas))
+	return nil
+}
+
+// Convert_v1alpha1_GangPolicy_To_v1beta1_GangPolicy is an autogenerated conversion function.
+func Convert_v1alpha1_GangPolicy_To_v1beta1_GangPolicy(in *v1alpha1.GangPolicy, out *GangPolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_GangPolicy_To_v1beta1_GangPolicy(in, out, s)
+}
+
+func autoConvert_v1beta1_Metadata_To_v1alpha1_Metadata(in *Metadata, out *v1alpha1.Metadata, s conversion.Scope) error {
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
+	return nil
+}
+
+// Convert_v1beta1_Metadata_To_v1alpha1_Metadata is an autogenerated conversion function.
+func Convert_v1beta1_Metadata_To_v1alpha1_Metadata(in *Metadata, out *v1alpha1.Metadata, s conversion.Scope) error {
+	return autoConvert_v1beta1_Metadata_To_v1alpha1_Metadata(in, out, s)
+}
+
+func autoConvert_v1alpha1_Metadata_To_v1beta1_Metadata(in *v1alpha1.Metadata, out *Metadata, s conversion.Scope) error {
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
+	return nil
+}
+
+// Convert_v1alpha1_Metadata_To_v1beta1_Metadata is an autogenerated conversion function.
+func Convert_v1alpha1_Metadata_To_v1beta1_Metadata(in *v1alpha1.Metadata, out *Metadata, s conversion.Scope) error {
+	return autoConvert_v1alpha1_Metadata_To_v1beta1_Metadata(in, out, s)
+}
+
+func autoConvert_v1beta1_ModelServing_To_v1alpha1_ModelServing(in *ModelServing, out *v1alpha1.ModelServing, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_ModelServingSpec_To_v1alpha1_ModelServingSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_ModelServingStatus_To_v1alpha1_ModelServingStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_ModelServing_To_v1alpha1_ModelServing is an autogenerated conversion function.
+func Convert_v1beta1_ModelServing_To_v1alpha1_ModelServing(in *ModelServing, out *v1alpha1.ModelServing, s conversion.Scope) error {
+	return autoConvert_v1beta1_ModelServing_To_v1alpha1_ModelServing(in, out, s)
+}
+
+func autoConvert_v1alpha1_ModelServing_To_v1beta1_ModelServing(in *v1alpha1.ModelServing, out *ModelServing, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha1_ModelServingSpec_To_v1beta1_ModelServingSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_ModelServingStatus_To_v1beta1_ModelServingStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_ModelServing_To_v1beta1_ModelServing is an autogenerated conversion function.
+func Convert_v1alpha1_ModelServing_To_v1beta1_ModelServing(in *v1alpha1.ModelServing, out *ModelServing, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ModelServing_To_v1beta1_ModelServing(in, out, s)
+}
+
+func autoConvert_v1beta1_ModelServingList_To_v1alpha1_ModelServingList(in *ModelServingList, out *v1alpha1.ModelServingList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]v1alpha1.ModelServing)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1beta1_ModelServingList_To_v1alpha1_ModelServingList is an autogenerated conversion function.
+func Convert_v1beta1_ModelServingList_To_v1alpha1_ModelServingList(in *ModelServingList, out *v1alpha1.ModelServingList, s conversion.Scope) error {
+	return autoConvert_v1beta1_ModelServingList_To_v1alpha1_ModelServingList(in, out, s)
+}
+
+func autoConvert_v1alpha1_ModelServingList_To_v1beta1_ModelServingList(in *v1alpha1.ModelServingList, out *ModelServingList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]ModelServing)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1alpha1_ModelServingList_To_v1beta1_ModelServingList is an autogenerated conversion function.
+func Convert_v1alpha1_ModelServingList_To_v1beta1_ModelServingList(in *v1alpha1.ModelServingList, out *ModelServingList, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ModelServingList_To_v1beta1_ModelServingList(in, out, s)
+}
+
+func autoConvert_v1beta1_ModelServingSpec_To_v1alpha1_ModelServingSpec(in *ModelServingSpec, out *v1alpha1.ModelServingSpec, s conversion.Scope) error {
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.StandbyReplicas = (*int32)(unsafe.Pointer(in.StandbyReplicas))
+	out.SchedulerName = in.SchedulerName
+	if err := Convert_v1beta1_ServingGroup_To_v1alpha1_ServingGroup(&in.Template, &out.Template, s); err != nil {
+		return err
+	}
+	out.RolloutStrategy = (*v1alpha1.RolloutStrategy)(unsafe.Pointer(in.RolloutStrategy))
+	out.RecoveryPolicy = v1alpha1.RecoveryPolicy(in.RecoveryPolicy)
+	out.RecoveryBackoff = (*v1alpha1.RecoveryBackoff)(unsafe.Pointer(in.RecoveryBackoff))
+	out.DisruptionPolicy = v1alpha1.DisruptionPolicy(in.DisruptionPolicy)
+	out.Paused = in.Paused
+	out.Suspend = in.Suspend
+	out.TopologySpreadConstraints = *(*[]v1alpha1.TopologySpreadConstraint)(unsafe.Pointer(&in.TopologySpreadConstraints))
+	return nil
+}
+
+// Convert_v1beta1_ModelServingSpec_To_v1alpha1_ModelServingSpec is an autogenerated conversion function.
+func Convert_v1beta1_ModelServingSpec_To_v1alpha1_ModelServingSpec(in *ModelServingSpec, out *v1alpha1.ModelServingSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_ModelServingSpec_To_v1alpha1_ModelServingSpec(in, out, s)
+}
+
+func autoConvert_v1alpha1_ModelServingSpec_To_v1beta1_ModelServingSpec(in *v1alpha1.ModelServingSpec, out *ModelServingSpec, s conversion.Scope) error {
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.StandbyReplicas = (*int32)(unsafe.Pointer(in.StandbyReplicas))
+	out.SchedulerName = in.SchedulerName
+	if err := Convert_v1alpha1_ServingGroup_To_v1beta1_ServingGroup(&in.Template, &out.Template, s); err != nil {
+		return err
+	}
+	out.RolloutStrategy = (*RolloutStrategy)(unsafe.Pointer(in.RolloutStrategy))
+	out.RecoveryPolicy = RecoveryPolicy(in.RecoveryPolicy)
+	out.RecoveryBackoff = (*RecoveryBackoff)(unsafe.Pointer(in.RecoveryBackoff))
+	out.DisruptionPolicy = DisruptionPolicy(in.DisruptionPolicy)
+	out.Paused = in.Paused
+	out.Suspend = in.Suspend
+	out.TopologySpreadConstraints = *(*[]TopologySpreadConstraint)(unsafe.Pointer(&in.TopologySpreadConstraints))
+	return nil
+}
+
+// Convert_v1alpha1_ModelServingSpec_To_v1beta1_ModelServingSpec is an autogenerated conversion function.
+func Convert_v1alpha1_ModelServingSpec_To_v1beta1_ModelServingSpec(in *v1alpha1.ModelServingSpec, out *ModelServingSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ModelServingSpec_To_v1beta1_ModelServingSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_ModelServingStatus_To_v1alpha1_ModelServingStatus(in *ModelServingStatus, out *v1alpha1.ModelServingStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.Replicas = in.Replicas
+	out.CurrentReplicas = in.CurrentReplicas
+	out.UpdatedReplicas = in.UpdatedReplicas
+	out.AvailableReplicas = in.AvailableReplicas
+	out.StandbyReplicas = in.StandbyReplicas
+	out.LoadingPercentage = in.LoadingPercentage
+	out.Conditions = *(*[]v1.Condition)(unsafe.Pointer(&in.Conditions))
+	out.LabelSelector = in.LabelSelector
+	out.ResourceRecommendations = *(*[]v1alpha1.ResourceRecommendation)(unsafe.Pointer(&in.ResourceRecommendations))
+	out.Groups = *(*[]v1alpha1.ServingGroupStatus)(unsafe.Pointer(&in.Groups))
+	return nil
+}
+
+// Convert_v1beta1_ModelServingStatus_To_v1alpha1_ModelServingStatus is an autogenerated conversion function.
+func Convert_v1beta1_ModelServingStatus_To_v1alpha1_ModelServingStatus(in *ModelServingStatus, out *v1alpha1.ModelServingStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_ModelServingStatus_To_v1alpha1_ModelServingStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_ModelServingStatus_To_v1beta1_ModelServingStatus(in *v1alpha1.ModelServingStatus, out *ModelServingStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.Replicas = in.Replicas
+	out.CurrentReplicas = in.CurrentReplicas
+	out.UpdatedReplicas = in.UpdatedReplicas
+	out.AvailableReplicas = in.AvailableReplicas
+	out.StandbyReplicas = in.StandbyReplicas
+	out.LoadingPercentage = in.LoadingPercentage
+	out.Conditions = *(*[]v1.Condition)(unsafe.Pointer(&in.Conditions))
+	out.LabelSelector = in.LabelSelector
+	out.ResourceRecommendations = *(*[]ResourceRecommendation)(unsafe.Pointer(&in.ResourceRecommendations))
+	out.Groups = *(*[]ServingGroupStatus)(unsafe.Pointer(&in.Groups))
+	return nil
+}
+
+// Convert_v1alpha1_ModelServingStatus_To_v1beta1_ModelServingStatus is an autogenerated conversion function.
+func Convert_v1alpha1_ModelServingStatus_To_v1beta1_ModelServingStatus(in *v1alpha1.ModelServingStatus, out *ModelServingStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ModelServingStatus_To_v1beta1_ModelServingStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_PodTemplateSpec_To_v1alpha1_PodTemplateSpec(in *PodTemplateSpec, out *v1alpha1.PodTemplateSpec, s conversion.Scope) error {
+	out.Metadata = (*v1alpha1.Metadata)(unsafe.Pointer(in.Metadata))
+	out.Spec = in.Spec
+	return nil
+}
+
+// Convert_v1beta1_PodTemplateSpec_To_v1alpha1_PodTemplateSpec is an autogenerated conversion function.
+func Convert_v1beta1_PodTemplateSpec_To_v1alpha1_PodTemplateSpec(in *PodTemplateSpec, out *v1alpha1.PodTemplateSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_PodTemplateSpec_To_v1alpha1_PodTemplateSpec(in, out, s)
+}
+
+func autoConvert_v1alpha1_PodTemplateSpec_To_v1beta1_PodTemplateSpec(in *v1alpha1.PodTemplateSpec, out *PodTemplateSpec, s conversion.Scope) error {
+	out.Metadata = (*Metadata)(unsafe.Pointer(in.Metadata))
+	out.Spec = in.Spec
+	return nil
+}
+
+// Convert_v1alpha1_PodTemplateSpec_To_v1beta1_PodTemplateSpec is an autogenerated conversion function.
+func Convert_v1alpha1_PodTemplateSpec_To_v1beta1_PodTemplateSpec(in *v1alpha1.PodTemplateSpec, out *PodTemplateSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PodTemplateSpec_To_v1beta1_PodTemplateSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_RecoveryBackoff_To_v1alpha1_RecoveryBackoff(in *RecoveryBackoff, out *v1alpha1.RecoveryBackoff, s conversion.Scope) error {
+	out.InitialDelaySeconds = in.InitialDelaySeconds
+	out.MaxDelaySeconds = in.MaxDelaySeconds
+	out.MaxRetries = in.MaxRetries
+	return nil
+}
+
+// Convert_v1beta1_RecoveryBackoff_To_v1alpha1_RecoveryBackoff is an autogenerated conversion function.
+func Convert_v1beta1_RecoveryBackoff_To_v1alpha1_RecoveryBackoff(in *RecoveryBackoff, out *v1alpha1.RecoveryBackoff, s conversion.Scope) error {
+	return autoConvert_v1beta1_RecoveryBackoff_To_v1alpha1_RecoveryBackoff(in, out, s)
+}
+
+func autoConvert_v1alpha1_RecoveryBackoff_To_v1beta1_RecoveryBackoff(in *v1alpha1.RecoveryBackoff, out *RecoveryBackoff, s conversion.Scope) error {
+	out.InitialDelaySeconds = in.InitialDelaySeconds
+	out.MaxDelaySeconds = in.MaxDelaySeconds
+	out.MaxRetries = in.MaxRetries
+	return nil
+}
+
+// Convert_v1alpha1_RecoveryBackoff_To_v1beta1_RecoveryBackoff is an autogenerated conversion function.
+func Convert_v1alpha1_RecoveryBackoff_To_v1beta1_RecoveryBackoff(in *v1alpha1.RecoveryBackoff, out *RecoveryBackoff, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RecoveryBackoff_To_v1beta1_RecoveryBackoff(in, out, s)
+}
+
+func autoConvert_v1beta1_ResourceRecommendation_To_v1alpha1_ResourceRecommendation(in *ResourceRecommendation, out *v1alpha1.ResourceRecommendation, s conversion.Scope) error {
+	out.Role = in.Role
+	out.GPUMemoryUtilizationPercent = in.GPUMemoryUtilizationPercent
+	out.TensorParallelSize = in.TensorParallelSize
+	out.PeakKVCacheUsagePercent = in.PeakKVCacheUsagePercent
+	out.PeakBatchSize = in.PeakBatchSize
+	out.Reason = in.Reason
+	out.LastUpdateTime = in.LastUpdateTime
+	return nil
+}
+
+// Convert_v1beta1_ResourceRecommendation_To_v1alpha1_ResourceRecommendation is an autogenerated conversion function.
+func Convert_v1beta1_ResourceRecommendation_To_v1alpha1_ResourceRecommendation(in *ResourceRecommendation, out *v1alpha1.ResourceRecommendation, s conversion.Scope) error {
+	return autoConvert_v1beta1_ResourceRecommendation_To_v1alpha1_ResourceRecommendation(in, out, s)
+}
+
+func autoConvert_v1alpha1_ResourceRecommendation_To_v1beta1_ResourceRecommendation(in *v1alpha1.ResourceRecommendation, out *ResourceRecommendation, s conversion.Scope) error {
+	out.Role = in.Role
+	out.GPUMemoryUtilizationPercent = in.GPUMemoryUtilizationPercent
+	out.TensorParallelSize = in.TensorParallelSize
+	out.PeakKVCacheUsagePercent = in.PeakKVCacheUsagePercent
+	out.PeakBatchSize = in.PeakBatchSize
+	out.Reason = in.Reason
+	out.LastUpdateTime = in.LastUpdateTime
+	return nil
+}
+
+// Convert_v1alpha1_ResourceRecommendation_To_v1beta1_ResourceRecommendation is an autogenerated conversion function.
+func Convert_v1alpha1_ResourceRecommendation_To_v1beta1_ResourceRecommendation(in *v1alpha1.ResourceRecommendation, out *ResourceRecommendation, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ResourceRecommendation_To_v1beta1_ResourceRecommendation(in, out, s)
+}
+
+func autoConvert_v1beta1_Role_To_v1alpha1_Role(in *Role, out *v1alpha1.Role, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	if err := Convert_v1beta1_PodTemplateSpec_To_v1alpha1_PodTemplateSpec(&in.EntryTemplate, &out.EntryTemplate, s); err != nil {
+		return err
+	}
+	out.WorkerReplicas = in.WorkerReplicas
+	out.WorkerTemplate = (*v1alpha1.PodTemplateSpec)(unsafe.Pointer(in.WorkerTemplate))
+	out.Placement = (*v1alpha1.RolePlacement)(unsafe.Pointer(in.Placement))
+	out.GPUSharing = (*v1alpha1.GPUSharing)(unsafe.Pointer(in.GPUSharing))
+	out.PriorityClassName = in.PriorityClassName
+	return nil
+}
+
+// Convert_v1beta1_Role_To_v1alpha1_Role is an autogenerated conversion function.
+func Convert_v1beta1_Role_To_v1alpha1_Role(in *Role, out *v1alpha1.Role, s conversion.Scope) error {
+	return autoConvert_v1beta1_Role_To_v1alpha1_Role(in, out, s)
+}
+
+func autoConvert_v1alpha1_Role_To_v1beta1_Role(in *v1alpha1.Role, out *Role, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	if err := Convert_v1alpha1_PodTemplateSpec_To_v1beta1_PodTemplateSpec(&in.EntryTemplate, &out.EntryTemplate, s); err != nil {
+		return err
+	}
+	out.WorkerReplicas = in.WorkerReplicas
+	out.WorkerTemplate = (*PodTemplateSpec)(unsafe.Pointer(in.WorkerTemplate))
+	out.Placement = (*RolePlacement)(unsafe.Pointer(in.Placement))
+	out.GPUSharing = (*GPUSharing)(unsafe.Pointer(in.GPUSharing))
+	out.PriorityClassName = in.PriorityClassName
+	return nil
+}
+
+// Convert_v1alpha1_Role_To_v1beta1_Role is an autogenerated conversion function.
+func Convert_v1alpha1_Role_To_v1beta1_Role(in *v1alpha1.Role, out *Role, s conversion.Scope) error {
+	return autoConvert_v1alpha1_Role_To_v1beta1_Role(in, out, s)
+}
+
+func autoConvert_v1beta1_RolePlacement_To_v1alpha1_RolePlacement(in *RolePlacement, out *v1alpha1.RolePlacement, s conversion.Scope) error {
+	out.ColocationTopologyKey = in.ColocationTopologyKey
+	out.TopologySpread = *(*[]v1alpha1.RoleTopologySpread)(unsafe.Pointer(&in.TopologySpread))
+	return nil
+}
+
+// Convert_v1beta1_RolePlacement_To_v1alpha1_RolePlacement is an autogenerated conversion function.
+func Convert_v1beta1_RolePlacement_To_v1alpha1_RolePlacement(in *RolePlacement, out *v1alpha1.RolePlacement, s conversion.Scope) error {
+	return autoConvert_v1beta1_RolePlacement_To_v1alpha1_RolePlacement(in, out, s)
+}
+
+func autoConvert_v1alpha1_RolePlacement_To_v1beta1_RolePlacement(in *v1alpha1.RolePlacement, out *RolePlacement, s conversion.Scope) error {
+	out.ColocationTopologyKey = in.ColocationTopologyKey
+	out.TopologySpread = *(*[]RoleTopologySpread)(unsafe.Pointer(&in.TopologySpread))
+	return nil
+}
+
+// Convert_v1alpha1_RolePlacement_To_v1beta1_RolePlacement is an autogenerated conversion function.
+func Convert_v1alpha1_RolePlacement_To_v1beta1_RolePlacement(in *v1alpha1.RolePlacement, out *RolePlacement, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RolePlacement_To_v1beta1_RolePlacement(in, out, s)
+}
+
+func autoConvert_v1beta1_RoleReplicaStatus_To_v1alpha1_RoleReplicaStatus(in *RoleReplicaStatus, out *v1alpha1.RoleReplicaStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Replicas = in.Replicas
+	out.ReadyReplicas = in.ReadyReplicas
+	return nil
+}
+
+// Convert_v1beta1_RoleReplicaStatus_To_v1alpha1_RoleReplicaStatus is an autogenerated conversion function.
+func Convert_v1beta1_RoleReplicaStatus_To_v1alpha1_RoleReplicaStatus(in *RoleReplicaStatus, out *v1alpha1.RoleReplicaStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_RoleReplicaStatus_To_v1alpha1_RoleReplicaStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_RoleReplicaStatus_To_v1beta1_RoleReplicaStatus(in *v1alpha1.RoleReplicaStatus, out *RoleReplicaStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Replicas = in.Replicas
+	out.ReadyReplicas = in.ReadyReplicas
+	return nil
+}
+
+// Convert_v1alpha1_RoleReplicaStatus_To_v1beta1_RoleReplicaStatus is an autogenerated conversion function.
+func Convert_v1alpha1_RoleReplicaStatus_To_v1beta1_RoleReplicaStatus(in *v1alpha1.RoleReplicaStatus, out *RoleReplicaStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RoleReplicaStatus_To_v1beta1_RoleReplicaStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_RoleTopologySpread_To_v1alpha1_RoleTopologySpread(in *RoleTopologySpread, out *v1alpha1.RoleTopologySpread, s conversion.Scope) error {
+	out.TopologyKey = in.TopologyKey
+	out.MaxSkew = in.MaxSkew
+	out.WhenUnsatisfiable = corev1.UnsatisfiableConstraintAction(in.WhenUnsatisfiable)
+	return nil
+}
+
+// Convert_v1beta1_RoleTopologySpread_To_v1alpha1_RoleTopologySpread is an autogenerated conversion function.
+func Convert_v1beta1_RoleTopologySpread_To_v1alpha1_RoleTopologySpread(in *RoleTopologySpread, out *v1alpha1.RoleTopologySpread, s conversion.Scope) error {
+	return autoConvert_v1beta1_RoleTopologySpread_To_v1alpha1_RoleTopologySpread(in, out, s)
+}
+
+func autoConvert_v1alpha1_RoleTopologySpread_To_v1beta1_RoleTopologySpread(in *v1alpha1.RoleTopologySpread, out *RoleTopologySpread, s conversion.Scope) error {
+	out.TopologyKey = in.TopologyKey
+	out.MaxSkew = in.MaxSkew
+	out.WhenUnsatisfiable = corev1.UnsatisfiableConstraintAction(in.WhenUnsatisfiable)
+	return nil
+}
+
+// Convert_v1alpha1_RoleTopologySpread_To_v1beta1_RoleTopologySpread is an autogenerated conversion function.
+func Convert_v1alpha1_RoleTopologySpread_To_v1beta1_RoleTopologySpread(in *v1alpha1.RoleTopologySpread, out *RoleTopologySpread, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RoleTopologySpread_To_v1beta1_RoleTopologySpread(in, out, s)
+}
+
+func autoConvert_v1beta1_RollingUpdateConfiguration_To_v1alpha1_RollingUpdateConfiguration(in *RollingUpdateConfiguration, out *v1alpha1.RollingUpdateConfiguration, s conversion.Scope) error {
+	out.MaxUnavailable = in.MaxUnavailable
+	out.MaxSurge = in.MaxSurge
+	out.Partition = (*int32)(unsafe.Pointer(in.Partition))
+	return nil
+}
+
+// Convert_v1beta1_RollingUpdateConfiguration_To_v1alpha1_RollingUpdateConfiguration is an autogenerated conversion function.
+func Convert_v1beta1_RollingUpdateConfiguration_To_v1alpha1_RollingUpdateConfiguration(in *RollingUpdateConfiguration, out *v1alpha1.RollingUpdateConfiguration, s conversion.Scope) error {
+	return autoConvert_v1beta1_RollingUpdateConfiguration_To_v1alpha1_RollingUpdateConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_RollingUpdateConfiguration_To_v1beta1_RollingUpdateConfiguration(in *v1alpha1.RollingUpdateConfiguration, out *RollingUpdateConfiguration, s conversion.Scope) error {
+	out.MaxUnavailable = in.MaxUnavailable
+	out.MaxSurge = in.MaxSurge
+	out.Partition = (*int32)(unsafe.Pointer(in.Partition))
+	return nil
+}
+
+// Convert_v1alpha1_RollingUpdateConfiguration_To_v1beta1_RollingUpdateConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_RollingUpdateConfiguration_To_v1beta1_RollingUpdateConfiguration(in *v1alpha1.RollingUpdateConfiguration, out *RollingUpdateConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RollingUpdateConfiguration_To_v1beta1_RollingUpdateConfiguration(in, out, s)
+}
+
+func autoConvert_v1beta1_RolloutStrategy_To_v1alpha1_RolloutStrategy(in *RolloutStrategy, out *v1alpha1.RolloutStrategy, s conversion.Scope) error {
+	out.Type = v1alpha1.RolloutStrategyType(in.Type)
+	out.RollingUpdateConfiguration = (*v1alpha1.RollingUpdateConfiguration)(unsafe.Pointer(in.RollingUpdateConfiguration))
+	return nil
+}
+
+// Convert_v1beta1_RolloutStrategy_To_v1alpha1_RolloutStrategy is an autogenerated conversion function.
+func Convert_v1beta1_RolloutStrategy_To_v1alpha1_RolloutStrategy(in *RolloutStrategy, out *v1alpha1.RolloutStrategy, s conversion.Scope) error {
+	return autoConvert_v1beta1_RolloutStrategy_To_v1alpha1_RolloutStrategy(in, out, s)
+}
+
+func autoConvert_v1alpha1_RolloutStrategy_To_v1beta1_RolloutStrategy(in *v1alpha1.RolloutStrategy, out *RolloutStrategy, s conversion.Scope) error {
+	out.Type = RolloutStrategyType(in.Type)
+	out.RollingUpdateConfiguration = (*RollingUpdateConfiguration)(unsafe.Pointer(in.RollingUpdateConfiguration))
+	return nil
+}
+
+// Convert_v1alpha1_RolloutStrategy_To_v1beta1_RolloutStrategy is an autogenerated conversion function.
+func Convert_v1alpha1_RolloutStrategy_To_v1beta1_RolloutStrategy(in *v1alpha1.RolloutStrategy, out *RolloutStrategy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RolloutStrategy_To_v1beta1_RolloutStrategy(in, out, s)
+}
+
+func autoConvert_v1beta1_ServingGroup_To_v1alpha1_ServingGroup(in *ServingGroup, out *v1alpha1.ServingGroup, s conversion.Scope) error {
+	out.RestartGracePeriodSeconds = (*int64)(unsafe.Pointer(in.RestartGracePeriodSeconds))
+	out.GangPolicy = (*v1alpha1.GangPolicy)(unsafe.Pointer(in.GangPolicy))
+	out.NetworkTopology = (*schedulingv1beta1.NetworkTopologySpec)(unsafe.Pointer(in.NetworkTopology))
+	out.PriorityClassName = in.PriorityClassName
+	out.Roles = *(*[]v1alpha1.Role)(unsafe.Pointer(&in.Roles))
+	return nil
+}
+
+// Convert_v1beta1_ServingGroup_To_v1alpha1_ServingGroup is an autogenerated conversion function.
+func Convert_v1beta1_ServingGroup_To_v1alpha1_ServingGroup(in *ServingGroup, out *v1alpha1.ServingGroup, s conversion.Scope) error {
+	return autoConvert_v1beta1_ServingGroup_To_v1alpha1_ServingGroup(in, out, s)
+}
+
+func autoConvert_v1alpha1_ServingGroup_To_v1beta1_ServingGroup(in *v1alpha1.ServingGroup, out *ServingGroup, s conversion.Scope) error {
+	out.RestartGracePeriodSeconds = (*int64)(unsafe.Pointer(in.RestartGracePeriodSeconds))
+	out.GangPolicy = (*GangPolicy)(unsafe.Pointer(in.GangPolicy))
+	out.NetworkTopology = (*schedulingv1beta1.NetworkTopologySpec)(unsafe.Pointer(in.NetworkTopology))
+	out.PriorityClassName = in.PriorityClassName
+	out.Roles = *(*[]Role)(unsafe.Pointer(&in.Roles))
+	return nil
+}
+
+// Convert_v1alpha1_ServingGroup_To_v1beta1_ServingGroup is an autogenerated conversion function.
+func Convert_v1alpha1_ServingGroup_To_v1beta1_ServingGroup(in *v1alpha1.ServingGroup, out *ServingGroup, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ServingGroup_To_v1beta1_ServingGroup(in, out, s)
+}
+
+func autoConvert_v1beta1_ServingGroupStatus_To_v1alpha1_ServingGroupStatus(in *ServingGroupStatus, out *v1alpha1.ServingGroupStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Phase = v1alpha1.ServingGroupPhase(in.Phase)
+	out.Revision = in.Revision
+	out.Standby = in.Standby
+	out.Roles = *(*[]v1alpha1.RoleReplicaStatus)(unsafe.Pointer(&in.Roles))
+	out.LastTransitionTime = in.LastTransitionTime
+	return nil
+}
+
+// Convert_v1beta1_ServingGroupStatus_To_v1alpha1_ServingGroupStatus is an autogenerated conversion function.
+func Convert_v1beta1_ServingGroupStatus_To_v1alpha1_ServingGroupStatus(in *ServingGroupStatus, out *v1alpha1.ServingGroupStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_ServingGroupStatus_To_v1alpha1_ServingGroupStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_ServingGroupStatus_To_v1beta1_ServingGroupStatus(in *v1alpha1.ServingGroupStatus, out *ServingGroupStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Phase = ServingGroupPhase(in.Phase)
+	out.Revision = in.Revision
+	out.Standby = in.Standby
+	out.Roles = *(*[]RoleReplicaStatus)(unsafe.Pointer(&in.Roles))
+	out.LastTransitionTime = in.LastTransitionTime
+	return nil
+}
+
+// Convert_v1alpha1_ServingGroupStatus_To_v1beta1_ServingGroupStatus is an autogenerated conversion function.
+func Convert_v1alpha1_ServingGroupStatus_To_v1beta1_ServingGroupStatus(in *v1alpha1.ServingGroupStatus, out *ServingGroupStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ServingGroupStatus_To_v1beta1_ServingGroupStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_TopologySpreadConstraint_To_v1alpha1_TopologySpreadConstraint(in *TopologySpreadConstraint, out *v1alpha1.TopologySpreadConstraint, s conversion.Scope) error {
+	out.MaxSkew = in.MaxSkew
+	out.TopologyKey = in.TopologyKey
+	out.WhenUnsatisfiable = in.WhenUnsatisfiable
+	out.LabelSelector = (*v1.LabelSelector)(unsafe.Pointer(in.LabelSelector))
+	return nil
+}
+
+// Convert_v1beta1_TopologySpreadConstraint_To_v1alpha1_TopologySpreadConstraint is an autogenerated conversion function.
+func Convert_v1beta1_TopologySpreadConstraint_To_v1alpha1_TopologySpreadConstraint(in *TopologySpreadConstraint, out *v1alpha1.TopologySpreadConstraint, s conversion.Scope) error {
+	return autoConvert_v1beta1_TopologySpreadConstraint_To_v1alpha1_TopologySpreadConstraint(in, out, s)
+}
+
+func autoConvert_v1alpha1_TopologySpreadConstraint_To_v1beta1_TopologySpreadConstraint(in *v1alpha1.TopologySpreadConstraint, out *TopologySpreadConstraint, s conversion.Scope) error {
+	out.MaxSkew = in.MaxSkew
+	out.TopologyKey = in.TopologyKey
+	out.WhenUnsatisfiable = in.WhenUnsatisfiable
+	out.LabelSelector = (*v1.LabelSelector)(unsafe.Pointer(in.LabelSelector))
+	return nil
+}
+
+// Convert_v1alpha1_TopologySpreadConstraint_To_v1beta1_TopologySpreadConstraint is an autogenerated conversion function.
+func Convert_v1alpha1_TopologySpreadConstraint_To_v1beta1_TopologySpreadConstraint(in *v1alpha1.TopologySpreadConstraint, out *TopologySpreadConstraint, s conversion.Scope) error {
+	return autoConvert_v1alpha1_TopologySpreadConstraint_To_v1beta1_TopologySpreadConstraint(in, out, s)
+}
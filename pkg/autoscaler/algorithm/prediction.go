@@ -0,0 +1,46 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algorithm
+
+import (
+	"math"
+)
+
+type PredictedInstancesAlgorithm struct {
+	MinInstances         int32
+	MaxInstances         int32
+	RecommendedInstances int32
+	ForecastRequestRate  float64
+	TargetRequestRate    float64
+	Confidence           float64
+	MinConfidence        float64
+}
+
+// GetPredictedInstances blends the instances needed to serve the forecast request rate with the recommended instances,
+// weighted by the confidence of the forecast. The forecast only pre-scales: the result is never below the recommendation.
+func (alg PredictedInstancesAlgorithm) GetPredictedInstances() int32 {
+	if alg.TargetRequestRate <= 0 || alg.Confidence < alg.MinConfidence {
+		return alg.RecommendedInstances
+	}
+	forecastInstances := int32(math.Ceil(alg.ForecastRequestRate / alg.TargetRequestRate))
+	if forecastInstances <= alg.RecommendedInstances {
+		return alg.RecommendedInstances
+	}
+	confidence := math.Min(alg.Confidence, 1)
+	blended := int32(math.Ceil(float64(alg.RecommendedInstances) + confidence*float64(forecastInstances-alg.RecommendedInstances)))
+	return min(max(blended, alg.MinInstances), alg.MaxInstances)
+}
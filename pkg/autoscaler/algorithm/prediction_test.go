@@ -0,0 +1,78 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algorithm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPredictedInstances(t *testing.T) {
+	testcases := []struct {
+		name     string
+		args     PredictedInstancesAlgorithm
+		expected int32
+	}{
+		{
+			name: "givenLowConfidence_thenReturnRecommended",
+			args: PredictedInstancesAlgorithm{
+				MinInstances: 1, MaxInstances: 10, RecommendedInstances: 2,
+				ForecastRequestRate: 80, TargetRequestRate: 10, Confidence: 0.3, MinConfidence: 0.5,
+			},
+			expected: 2,
+		},
+		{
+			name: "givenForecastBelowRecommended_thenReturnRecommended",
+			args: PredictedInstancesAlgorithm{
+				MinInstances: 1, MaxInstances: 10, RecommendedInstances: 4,
+				ForecastRequestRate: 20, TargetRequestRate: 10, Confidence: 1, MinConfidence: 0.5,
+			},
+			expected: 4,
+		},
+		{
+			name: "givenFullConfidence_thenReturnForecast",
+			args: PredictedInstancesAlgorithm{
+				MinInstances: 1, MaxInstances: 10, RecommendedInstances: 2,
+				ForecastRequestRate: 75, TargetRequestRate: 10, Confidence: 1, MinConfidence: 0.5,
+			},
+			expected: 8,
+		},
+		{
+			name: "givenPartialConfidence_thenReturnBlended",
+			args: PredictedInstancesAlgorithm{
+				MinInstances: 1, MaxInstances: 10, RecommendedInstances: 2,
+				ForecastRequestRate: 80, TargetRequestRate: 10, Confidence: 0.5, MinConfidence: 0.5,
+			},
+			expected: 5,
+		},
+		{
+			name: "givenForecastAboveMax_thenReturnMax",
+			args: PredictedInstancesAlgorithm{
+				MinInstances: 1, MaxInstances: 6, RecommendedInstances: 2,
+				ForecastRequestRate: 200, TargetRequestRate: 10, Confidence: 1, MinConfidence: 0.5,
+			},
+			expected: 6,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.args.GetPredictedInstances())
+		})
+	}
+}
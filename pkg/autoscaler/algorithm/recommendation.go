@@ -20,6 +20,8 @@ import (
 	"math"
 
 	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 )
 
 type Metrics = map[string]float64
@@ -28,20 +30,25 @@ type RecommendedInstancesAlgorithm struct {
 	MinInstances          int32
 	MaxInstances          int32
 	CurrentInstancesCount int32
-	Tolerance             float64
+	// ScaleUpTolerance and ScaleDownTolerance are the deviations of the metrics from their targets
+	// tolerated before scaling up and down.
+	ScaleUpTolerance      float64
+	ScaleDownTolerance    float64
 	MetricTargets         Metrics
 	UnreadyInstancesCount int32
 	ReadyInstancesMetrics []Metrics
 	ExternalMetrics       Metrics
 }
 
-func (alg *RecommendedInstancesAlgorithm) GetRecommendedInstances() (recommendedInstances int32, skip bool) {
+// GetRecommendedInstances returns the instances recommended from the metrics, within the minimum and maximum
+// instances, and the limit they were clamped by. It skips the recommendation when no metric was collected.
+func (alg *RecommendedInstancesAlgorithm) GetRecommendedInstances() (recommendedInstances int32, clampedBy v1alpha1.ScalingLimit, skip bool) {
 	klog.InfoS("start to getRecommendedInstances", "args", alg)
 	if alg.CurrentInstancesCount < alg.MinInstances {
-		return alg.MinInstances, false
+		return alg.MinInstances, v1alpha1.ScalingLimitMinReplicas, false
 	}
 	if alg.CurrentInstancesCount > alg.MaxInstances {
-		return alg.MaxInstances, false
+		return alg.MaxInstances, v1alpha1.ScalingLimitMaxReplicas, false
 	}
 	recommendedInstances = 0
 	skip = true
@@ -51,14 +58,14 @@ func (alg *RecommendedInstancesAlgorithm) GetRecommendedInstances() (recommended
 			updateRecommendation(&recommendedInstances, &skip,
 				getDesiredInstancesForSingleExternalMetric(
 					alg.CurrentInstancesCount,
-					alg.Tolerance,
+					alg.withinTolerance,
 					target,
 					externalMetric,
 				))
 		} else {
 			if desired, ok := getDesiredInstancesForSingleInstanceMetric(
 				alg.CurrentInstancesCount,
-				alg.Tolerance,
+				alg.withinTolerance,
 				name,
 				target,
 				alg.UnreadyInstancesCount,
@@ -69,9 +76,13 @@ func (alg *RecommendedInstancesAlgorithm) GetRecommendedInstances() (recommended
 		}
 	}
 	if !skip {
-		recommendedInstances = min(max(recommendedInstances, alg.MinInstances), alg.MaxInstances)
+		if recommendedInstances < alg.MinInstances {
+			recommendedInstances, clampedBy = alg.MinInstances, v1alpha1.ScalingLimitMinReplicas
+		} else if recommendedInstances > alg.MaxInstances {
+			recommendedInstances, clampedBy = alg.MaxInstances, v1alpha1.ScalingLimitMaxReplicas
+		}
 	}
-	return recommendedInstances, skip
+	return recommendedInstances, clampedBy, skip
 }
 
 func updateRecommendation(recommendedInstances *int32, skip *bool, desired int32) {
@@ -83,15 +94,25 @@ func updateRecommendation(recommendedInstances *int32, skip *bool, desired int32
 	}
 }
 
+// toleranceFunc reports whether the ratio of a metric to its target is close enough to 1 to keep the instances.
+type toleranceFunc func(ratio float64) bool
+
+func (alg *RecommendedInstancesAlgorithm) withinTolerance(ratio float64) bool {
+	if ratio >= 1.0 {
+		return ratio-1.0 <= alg.ScaleUpTolerance
+	}
+	return 1.0-ratio <= alg.ScaleDownTolerance
+}
+
 func getDesiredInstancesForSingleExternalMetric(
 	currentCount int32,
-	tolerance float64,
+	tolerance toleranceFunc,
 	target float64,
 	metric float64,
 ) int32 {
 	desired := metric / target
 	ratio := desired / float64(currentCount)
-	if math.Abs(ratio-1.0) <= tolerance {
+	if tolerance(ratio) {
 		return currentCount
 	}
 	return getCeilDesiredInstances(desired)
@@ -99,7 +120,7 @@ func getDesiredInstancesForSingleExternalMetric(
 
 func getDesiredInstancesForSingleInstanceMetric(
 	currentCount int32,
-	tolerance float64,
+	tolerance toleranceFunc,
 	name string,
 	target float64,
 	unreadyCount int32,
@@ -123,9 +144,9 @@ func getDesiredInstancesForSingleInstanceMetric(
 	ratio := currentMetricSum / float64(metricsCount) / target
 	shouldAddUnready := unreadyCount > 0 && getDirection(ratio) > 0
 	klog.InfoS("recommendation", "metricsCount", metricsCount, "currentMetricSum", currentMetricSum, "ratio", ratio,
-		"unreadyCount", unreadyCount, "shouldAddUnready", shouldAddUnready, "missingCount", missingCount, "tolerance", tolerance)
+		"unreadyCount", unreadyCount, "shouldAddUnready", shouldAddUnready, "missingCount", missingCount)
 	if !shouldAddUnready && missingCount == 0 {
-		if math.Abs(ratio-1.0) <= tolerance {
+		if tolerance(ratio) {
 			return currentCount, true
 		}
 		return getCeilDesiredInstances(ratio * float64(metricsCount)), true
@@ -138,7 +159,7 @@ func getDesiredInstancesForSingleInstanceMetric(
 		metricsCount += unreadyCount
 	}
 	newRatio := currentMetricSum / float64(metricsCount) / target
-	if math.Abs(newRatio-1.0) <= tolerance || getDirection(ratio) != getDirection(newRatio) {
+	if tolerance(newRatio) || getDirection(ratio) != getDirection(newRatio) {
 		return currentCount, true
 	}
 	desired = getCeilDesiredInstances(newRatio * float64(metricsCount))
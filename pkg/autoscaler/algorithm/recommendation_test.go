@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 )
 
 func TestGetRecommendedInstances(t *testing.T) {
@@ -28,6 +30,7 @@ func TestGetRecommendedInstances(t *testing.T) {
 		name                string
 		args                RecommendedInstancesAlgorithm
 		expectedRecommended int32
+		expectedClampedBy   v1alpha1.ScalingLimit
 		expectedSkip        bool
 	}
 
@@ -38,13 +41,15 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(5),
 				MaxInstances:          int32(10),
 				CurrentInstancesCount: int32(4),
-				Tolerance:             0.1,
+				ScaleUpTolerance:      0.1,
+				ScaleDownTolerance:    0.1,
 				MetricTargets:         Metrics{},
 				UnreadyInstancesCount: int32(4),
 				ReadyInstancesMetrics: []Metrics{},
 				ExternalMetrics:       Metrics{},
 			},
 			expectedRecommended: int32(5),
+			expectedClampedBy:   v1alpha1.ScalingLimitMinReplicas,
 			expectedSkip:        false,
 		},
 		{
@@ -53,13 +58,15 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(5),
 				MaxInstances:          int32(10),
 				CurrentInstancesCount: int32(11),
-				Tolerance:             0.1,
+				ScaleUpTolerance:      0.1,
+				ScaleDownTolerance:    0.1,
 				MetricTargets:         Metrics{},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: []Metrics{},
 				ExternalMetrics:       Metrics{},
 			},
 			expectedRecommended: int32(10),
+			expectedClampedBy:   v1alpha1.ScalingLimitMaxReplicas,
 			expectedSkip:        false,
 		},
 		{
@@ -68,7 +75,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(5),
 				MaxInstances:          int32(10),
 				CurrentInstancesCount: int32(7),
-				Tolerance:             0.1,
+				ScaleUpTolerance:      0.1,
+				ScaleDownTolerance:    0.1,
 				MetricTargets: Metrics{
 					"a": 0.5,
 					"b": 8.0,
@@ -85,7 +93,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(1000000),
 				CurrentInstancesCount: int32(3),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets: Metrics{
 					"a": 3.0,
 					"b": 5.0,
@@ -108,13 +117,15 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(1),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 0.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: []Metrics{{"a": 1.0}},
 				ExternalMetrics:       Metrics{},
 			},
 			expectedRecommended: int32(100),
+			expectedClampedBy:   v1alpha1.ScalingLimitMaxReplicas,
 			expectedSkip:        false,
 		},
 		{
@@ -123,13 +134,15 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(1),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 1e-100},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: []Metrics{{"a": 1e100}},
 				ExternalMetrics:       Metrics{},
 			},
 			expectedRecommended: int32(100),
+			expectedClampedBy:   v1alpha1.ScalingLimitMaxReplicas,
 			expectedSkip:        false,
 		},
 		{
@@ -138,7 +151,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(3),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: []Metrics{
@@ -157,13 +171,15 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(5),
 				MaxInstances:          int32(20),
 				CurrentInstancesCount: int32(10),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: slices.Repeat([]Metrics{{"a": 0.1}}, 10),
 				ExternalMetrics:       Metrics{},
 			},
 			expectedRecommended: int32(5),
+			expectedClampedBy:   v1alpha1.ScalingLimitMinReplicas,
 			expectedSkip:        false,
 		},
 		{
@@ -172,13 +188,15 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(5),
 				MaxInstances:          int32(20),
 				CurrentInstancesCount: int32(10),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: slices.Repeat([]Metrics{{"a": 3.0}}, 10),
 				ExternalMetrics:       Metrics{},
 			},
 			expectedRecommended: int32(20),
+			expectedClampedBy:   v1alpha1.ScalingLimitMaxReplicas,
 			expectedSkip:        false,
 		},
 		{
@@ -187,7 +205,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(9),
-				Tolerance:             0.5,
+				ScaleUpTolerance:      0.5,
+				ScaleDownTolerance:    0.5,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: slices.Repeat([]Metrics{{"a": 0.51}}, 10),
@@ -202,7 +221,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(9),
-				Tolerance:             0.5,
+				ScaleUpTolerance:      0.5,
+				ScaleDownTolerance:    0.5,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: slices.Repeat([]Metrics{{"a": 0.49}}, 10),
@@ -217,7 +237,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(9),
-				Tolerance:             0.5,
+				ScaleUpTolerance:      0.5,
+				ScaleDownTolerance:    0.5,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: slices.Repeat([]Metrics{{"a": 1.49}}, 10),
@@ -232,7 +253,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(9),
-				Tolerance:             0.5,
+				ScaleUpTolerance:      0.5,
+				ScaleDownTolerance:    0.5,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: slices.Repeat([]Metrics{{"a": 1.51}}, 10),
@@ -241,13 +263,46 @@ func TestGetRecommendedInstances(t *testing.T) {
 			expectedRecommended: int32(16),
 			expectedSkip:        false,
 		},
+		{
+			name: "givenAsymmetricTolerances_whenLoadIncreases_thenScaleUp",
+			args: RecommendedInstancesAlgorithm{
+				MinInstances:          int32(1),
+				MaxInstances:          int32(100),
+				CurrentInstancesCount: int32(10),
+				ScaleUpTolerance:      0.05,
+				ScaleDownTolerance:    0.3,
+				MetricTargets:         Metrics{"a": 1.0},
+				UnreadyInstancesCount: int32(0),
+				ReadyInstancesMetrics: slices.Repeat([]Metrics{{"a": 1.1}}, 10),
+				ExternalMetrics:       Metrics{},
+			},
+			expectedRecommended: int32(11),
+			expectedSkip:        false,
+		},
+		{
+			name: "givenAsymmetricTolerances_whenLoadDecreasesAsMuch_thenReturnCurrent",
+			args: RecommendedInstancesAlgorithm{
+				MinInstances:          int32(1),
+				MaxInstances:          int32(100),
+				CurrentInstancesCount: int32(10),
+				ScaleUpTolerance:      0.05,
+				ScaleDownTolerance:    0.3,
+				MetricTargets:         Metrics{"a": 1.0},
+				UnreadyInstancesCount: int32(0),
+				ReadyInstancesMetrics: slices.Repeat([]Metrics{{"a": 0.9}}, 10),
+				ExternalMetrics:       Metrics{},
+			},
+			expectedRecommended: int32(10),
+			expectedSkip:        false,
+		},
 		{
 			name: "givenUnreadyInstances_whenShouldScaleDown_thenIgnoreUnreadyInstances",
 			args: RecommendedInstancesAlgorithm{
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(58),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(50),
 				ReadyInstancesMetrics: slices.Repeat([]Metrics{{"a": 0.15}}, 8),
@@ -262,7 +317,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(18),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(10),
 				ReadyInstancesMetrics: slices.Repeat([]Metrics{{"a": 3.9}}, 8),
@@ -277,7 +333,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(58),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(50),
 				ReadyInstancesMetrics: slices.Repeat([]Metrics{{"a": 3.9}}, 8),
@@ -292,7 +349,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(10),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: slices.Concat(
@@ -310,7 +368,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(10),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: slices.Concat(
@@ -328,7 +387,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(58),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: slices.Concat(
@@ -346,7 +406,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(58),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 1.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: slices.Concat(
@@ -364,7 +425,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(10),
-				Tolerance:             0.51,
+				ScaleUpTolerance:      0.51,
+				ScaleDownTolerance:    0.51,
 				MetricTargets:         Metrics{"a": 3.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: []Metrics{},
@@ -379,7 +441,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(10),
-				Tolerance:             0.49,
+				ScaleUpTolerance:      0.49,
+				ScaleDownTolerance:    0.49,
 				MetricTargets:         Metrics{"a": 3.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: []Metrics{},
@@ -394,7 +457,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(10),
-				Tolerance:             0.51,
+				ScaleUpTolerance:      0.51,
+				ScaleDownTolerance:    0.51,
 				MetricTargets:         Metrics{"a": 3.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: []Metrics{},
@@ -409,7 +473,8 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(10),
-				Tolerance:             0.49,
+				ScaleUpTolerance:      0.49,
+				ScaleDownTolerance:    0.49,
 				MetricTargets:         Metrics{"a": 3.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: []Metrics{},
@@ -424,13 +489,15 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(1),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 0.0},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: []Metrics{},
 				ExternalMetrics:       Metrics{"a": 1.0},
 			},
 			expectedRecommended: int32(100),
+			expectedClampedBy:   v1alpha1.ScalingLimitMaxReplicas,
 			expectedSkip:        false,
 		},
 		{
@@ -439,13 +506,15 @@ func TestGetRecommendedInstances(t *testing.T) {
 				MinInstances:          int32(1),
 				MaxInstances:          int32(100),
 				CurrentInstancesCount: int32(1),
-				Tolerance:             0.0,
+				ScaleUpTolerance:      0.0,
+				ScaleDownTolerance:    0.0,
 				MetricTargets:         Metrics{"a": 1e-100},
 				UnreadyInstancesCount: int32(0),
 				ReadyInstancesMetrics: []Metrics{},
 				ExternalMetrics:       Metrics{"a": 1e100},
 			},
 			expectedRecommended: int32(100),
+			expectedClampedBy:   v1alpha1.ScalingLimitMaxReplicas,
 			expectedSkip:        false,
 		},
 	}
@@ -453,8 +522,9 @@ func TestGetRecommendedInstances(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			assert := assert.New(t)
-			recommended, skip := tc.args.GetRecommendedInstances()
+			recommended, clampedBy, skip := tc.args.GetRecommendedInstances()
 			assert.Equal(tc.expectedRecommended, recommended)
+			assert.Equal(tc.expectedClampedBy, clampedBy)
 			assert.Equal(tc.expectedSkip, skip)
 		})
 	}
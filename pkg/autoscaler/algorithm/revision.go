@@ -41,43 +41,56 @@ type History struct {
 	MinCorrectedForPanic  *datastructure.RmqLineChartSlidingWindow[int32]
 }
 
-func (alg CorrectedInstancesAlgorithm) GetCorrectedInstances() int32 {
+// GetCorrectedInstances applies the behavior of the policy to the recommended instances, and returns the
+// corrected instances with the limit they were clamped by.
+func (alg CorrectedInstancesAlgorithm) GetCorrectedInstances() (int32, v1alpha1.ScalingLimit) {
 	var corrected int32
+	var clampedBy v1alpha1.ScalingLimit
 	if alg.IsPanic {
-		corrected = alg.getCorrectedInstancesForPanic()
+		corrected, clampedBy = alg.getCorrectedInstancesForPanic()
 	} else {
-		corrected = alg.getCorrectedInstancesForStable()
+		corrected, clampedBy = alg.getCorrectedInstancesForStable()
 	}
-	return min(max(corrected, alg.MinInstances), alg.MaxInstances)
+	if corrected < alg.MinInstances {
+		return alg.MinInstances, v1alpha1.ScalingLimitMinReplicas
+	}
+	if corrected > alg.MaxInstances {
+		return alg.MaxInstances, v1alpha1.ScalingLimitMaxReplicas
+	}
+	return corrected, clampedBy
 }
 
-func (alg CorrectedInstancesAlgorithm) getCorrectedInstancesForPanic() int32 {
+func (alg CorrectedInstancesAlgorithm) getCorrectedInstancesForPanic() (int32, v1alpha1.ScalingLimit) {
 	corrected := alg.RecommendedInstances
+	var clampedBy v1alpha1.ScalingLimit
 	if pastSample, ok := alg.History.MinCorrectedForPanic.GetBest(alg.CurrentInstances); ok {
 		relativeConstraint := pastSample + int32(float64(pastSample)*float64(*alg.Behavior.ScaleUp.PanicPolicy.Percent)/100.0)
-		corrected = min(corrected, relativeConstraint)
+		if relativeConstraint < corrected {
+			corrected, clampedBy = relativeConstraint, v1alpha1.ScalingLimitPanicScaleUpRate
+		}
+	}
+	if corrected < alg.CurrentInstances {
+		corrected, clampedBy = alg.CurrentInstances, ""
 	}
-	corrected = max(corrected, alg.CurrentInstances)
-	return corrected
+	return corrected, clampedBy
 }
 
-func (alg CorrectedInstancesAlgorithm) getCorrectedInstancesForStable() int32 {
-	var corrected int32
+func (alg CorrectedInstancesAlgorithm) getCorrectedInstancesForStable() (int32, v1alpha1.ScalingLimit) {
 	switch {
 	case alg.RecommendedInstances < alg.CurrentInstances:
-		corrected = alg.getCorrectedInstancesForStableScaleDown()
+		return alg.getCorrectedInstancesForStableScaleDown()
 	case alg.RecommendedInstances > alg.CurrentInstances:
-		corrected = alg.getCorrectedInstancesForStableScaleUp()
+		return alg.getCorrectedInstancesForStableScaleUp()
 	default:
-		corrected = alg.RecommendedInstances
+		return alg.RecommendedInstances, ""
 	}
-	return corrected
 }
 
-func (alg CorrectedInstancesAlgorithm) getCorrectedInstancesForStableScaleDown() int32 {
+func (alg CorrectedInstancesAlgorithm) getCorrectedInstancesForStableScaleDown() (int32, v1alpha1.ScalingLimit) {
 	corrected := alg.RecommendedInstances
-	if betterRecommendation, ok := alg.History.MaxRecommendation.GetBest(); ok {
-		corrected = max(corrected, betterRecommendation)
+	var clampedBy v1alpha1.ScalingLimit
+	if betterRecommendation, ok := alg.History.MaxRecommendation.GetBest(); ok && betterRecommendation > corrected {
+		corrected, clampedBy = betterRecommendation, v1alpha1.ScalingLimitScaleDownStabilizationWindow
 	}
 	if pastSample, ok := alg.History.MaxCorrected.GetBest(alg.CurrentInstances); ok {
 		absoluteConstraint := pastSample - *alg.Behavior.ScaleDown.Instances
@@ -91,16 +104,18 @@ func (alg CorrectedInstancesAlgorithm) getCorrectedInstancesForStableScaleDown()
 		default:
 			constraint = math.MinInt32
 		}
-		corrected = max(corrected, constraint)
+		if constraint > corrected {
+			corrected, clampedBy = constraint, v1alpha1.ScalingLimitScaleDownRate
+		}
 	}
-	corrected = min(corrected, alg.CurrentInstances)
-	return corrected
+	return min(corrected, alg.CurrentInstances), clampedBy
 }
 
-func (alg CorrectedInstancesAlgorithm) getCorrectedInstancesForStableScaleUp() int32 {
+func (alg CorrectedInstancesAlgorithm) getCorrectedInstancesForStableScaleUp() (int32, v1alpha1.ScalingLimit) {
 	corrected := alg.RecommendedInstances
-	if betterRecommendation, ok := alg.History.MinRecommendation.GetBest(); ok {
-		corrected = min(corrected, betterRecommendation)
+	var clampedBy v1alpha1.ScalingLimit
+	if betterRecommendation, ok := alg.History.MinRecommendation.GetBest(); ok && betterRecommendation < corrected {
+		corrected, clampedBy = betterRecommendation, v1alpha1.ScalingLimitScaleUpStabilizationWindow
 	}
 	if pastSample, ok := alg.History.MinCorrectedForStable.GetBest(alg.CurrentInstances); ok {
 		absoluteConstraint := pastSample + *alg.Behavior.ScaleUp.StablePolicy.Instances
@@ -114,8 +129,9 @@ func (alg CorrectedInstancesAlgorithm) getCorrectedInstancesForStableScaleUp() i
 		default:
 			constraint = math.MaxInt32
 		}
-		corrected = min(corrected, constraint)
+		if constraint < corrected {
+			corrected, clampedBy = constraint, v1alpha1.ScalingLimitScaleUpRate
+		}
 	}
-	corrected = max(corrected, alg.CurrentInstances)
-	return corrected
+	return max(corrected, alg.CurrentInstances), clampedBy
 }
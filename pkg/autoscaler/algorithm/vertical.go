@@ -0,0 +1,77 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algorithm
+
+import (
+	"fmt"
+
+	"github.com/volcano-sh/kthena/pkg/autoscaler/util"
+)
+
+type VerticalRecommendationAlgorithm struct {
+	CurrentGPUMemoryUtilizationPercent int32
+	CurrentTensorParallelSize          int32
+	TargetKVCacheUsagePercent          int32
+	PeakKVCacheUsagePercent            int32
+	PeakBatchSize                      int32
+	// PeakGPUMemoryUsagePercent is negative when the GPU memory usage is not observed
+	PeakGPUMemoryUsagePercent int32
+}
+
+type VerticalRecommendation struct {
+	GPUMemoryUtilizationPercent int32
+	TensorParallelSize          int32
+	Reason                      string
+}
+
+// GetRecommendation sizes the KV cache of an instance so that its peak usage meets the target.
+// The GPU memory utilization is adjusted in steps first, and the tensor parallel size is only changed
+// once the utilization can not be adjusted anymore, since resharding the model is far more disruptive.
+func (alg VerticalRecommendationAlgorithm) GetRecommendation() VerticalRecommendation {
+	recommendation := VerticalRecommendation{
+		GPUMemoryUtilizationPercent: alg.CurrentGPUMemoryUtilizationPercent,
+		TensorParallelSize:          max(alg.CurrentTensorParallelSize, 1),
+	}
+	observed := fmt.Sprintf("peak KV-cache usage %d%% at a peak batch size of %d", alg.PeakKVCacheUsagePercent, alg.PeakBatchSize)
+
+	switch {
+	case alg.PeakKVCacheUsagePercent > alg.TargetKVCacheUsagePercent:
+		memoryExhausted := alg.CurrentGPUMemoryUtilizationPercent >= util.VerticalMaxGPUMemoryUtilizationPercent ||
+			alg.PeakGPUMemoryUsagePercent >= util.VerticalMaxGPUMemoryUtilizationPercent
+		if !memoryExhausted {
+			recommendation.GPUMemoryUtilizationPercent = min(alg.CurrentGPUMemoryUtilizationPercent+util.VerticalGPUMemoryUtilizationStepPercent,
+				util.VerticalMaxGPUMemoryUtilizationPercent)
+			recommendation.Reason = fmt.Sprintf("%s exceeds the target %d%%, raise the GPU memory utilization", observed, alg.TargetKVCacheUsagePercent)
+		} else {
+			recommendation.TensorParallelSize *= 2
+			recommendation.Reason = fmt.Sprintf("%s exceeds the target %d%% and the GPU memory is exhausted, shard the model across more GPUs", observed, alg.TargetKVCacheUsagePercent)
+		}
+	case alg.PeakKVCacheUsagePercent < alg.TargetKVCacheUsagePercent/2:
+		if alg.CurrentGPUMemoryUtilizationPercent-util.VerticalGPUMemoryUtilizationStepPercent >= util.VerticalMinGPUMemoryUtilizationPercent {
+			recommendation.GPUMemoryUtilizationPercent -= util.VerticalGPUMemoryUtilizationStepPercent
+			recommendation.Reason = fmt.Sprintf("%s is far below the target %d%%, lower the GPU memory utilization", observed, alg.TargetKVCacheUsagePercent)
+		} else if recommendation.TensorParallelSize > 1 && alg.PeakKVCacheUsagePercent < alg.TargetKVCacheUsagePercent/4 {
+			recommendation.TensorParallelSize /= 2
+			recommendation.Reason = fmt.Sprintf("%s is far below the target %d%%, shard the model across fewer GPUs", observed, alg.TargetKVCacheUsagePercent)
+		} else {
+			recommendation.Reason = fmt.Sprintf("%s is below the target %d%%, but the resources can not be lowered further", observed, alg.TargetKVCacheUsagePercent)
+		}
+	default:
+		recommendation.Reason = fmt.Sprintf("%s meets the target %d%%", observed, alg.TargetKVCacheUsagePercent)
+	}
+	return recommendation
+}
@@ -0,0 +1,104 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algorithm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetVerticalRecommendation(t *testing.T) {
+	testcases := []struct {
+		name                       string
+		args                       VerticalRecommendationAlgorithm
+		expectedGPUMemoryUtilPct   int32
+		expectedTensorParallelSize int32
+	}{
+		{
+			name: "givenUsageWithinTarget_thenKeepResources",
+			args: VerticalRecommendationAlgorithm{
+				CurrentGPUMemoryUtilizationPercent: 90, CurrentTensorParallelSize: 2,
+				TargetKVCacheUsagePercent: 80, PeakKVCacheUsagePercent: 70, PeakBatchSize: 16, PeakGPUMemoryUsagePercent: -1,
+			},
+			expectedGPUMemoryUtilPct:   90,
+			expectedTensorParallelSize: 2,
+		},
+		{
+			name: "givenUsageAboveTarget_thenRaiseUtilization",
+			args: VerticalRecommendationAlgorithm{
+				CurrentGPUMemoryUtilizationPercent: 85, CurrentTensorParallelSize: 1,
+				TargetKVCacheUsagePercent: 80, PeakKVCacheUsagePercent: 95, PeakBatchSize: 32, PeakGPUMemoryUsagePercent: -1,
+			},
+			expectedGPUMemoryUtilPct:   90,
+			expectedTensorParallelSize: 1,
+		},
+		{
+			name: "givenUsageAboveTargetAndUtilizationAtMax_thenDoubleTensorParallel",
+			args: VerticalRecommendationAlgorithm{
+				CurrentGPUMemoryUtilizationPercent: 95, CurrentTensorParallelSize: 1,
+				TargetKVCacheUsagePercent: 80, PeakKVCacheUsagePercent: 99, PeakBatchSize: 32, PeakGPUMemoryUsagePercent: -1,
+			},
+			expectedGPUMemoryUtilPct:   95,
+			expectedTensorParallelSize: 2,
+		},
+		{
+			name: "givenUsageAboveTargetAndGPUMemoryExhausted_thenDoubleTensorParallel",
+			args: VerticalRecommendationAlgorithm{
+				CurrentGPUMemoryUtilizationPercent: 85, CurrentTensorParallelSize: 2,
+				TargetKVCacheUsagePercent: 80, PeakKVCacheUsagePercent: 99, PeakBatchSize: 32, PeakGPUMemoryUsagePercent: 97,
+			},
+			expectedGPUMemoryUtilPct:   85,
+			expectedTensorParallelSize: 4,
+		},
+		{
+			name: "givenUsageFarBelowTarget_thenLowerUtilization",
+			args: VerticalRecommendationAlgorithm{
+				CurrentGPUMemoryUtilizationPercent: 90, CurrentTensorParallelSize: 2,
+				TargetKVCacheUsagePercent: 80, PeakKVCacheUsagePercent: 10, PeakBatchSize: 2, PeakGPUMemoryUsagePercent: -1,
+			},
+			expectedGPUMemoryUtilPct:   85,
+			expectedTensorParallelSize: 2,
+		},
+		{
+			name: "givenUsageFarBelowTargetAndUtilizationAtMin_thenHalveTensorParallel",
+			args: VerticalRecommendationAlgorithm{
+				CurrentGPUMemoryUtilizationPercent: 50, CurrentTensorParallelSize: 4,
+				TargetKVCacheUsagePercent: 80, PeakKVCacheUsagePercent: 10, PeakBatchSize: 2, PeakGPUMemoryUsagePercent: -1,
+			},
+			expectedGPUMemoryUtilPct:   50,
+			expectedTensorParallelSize: 2,
+		},
+		{
+			name: "givenUsageBelowTargetAndResourcesAtMin_thenKeepResources",
+			args: VerticalRecommendationAlgorithm{
+				CurrentGPUMemoryUtilizationPercent: 50, CurrentTensorParallelSize: 1,
+				TargetKVCacheUsagePercent: 80, PeakKVCacheUsagePercent: 10, PeakBatchSize: 2, PeakGPUMemoryUsagePercent: -1,
+			},
+			expectedGPUMemoryUtilPct:   50,
+			expectedTensorParallelSize: 1,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			recommendation := tc.args.GetRecommendation()
+			assert.Equal(t, tc.expectedGPUMemoryUtilPct, recommendation.GPUMemoryUtilizationPercent)
+			assert.Equal(t, tc.expectedTensorParallelSize, recommendation.TensorParallelSize)
+			assert.NotEmpty(t, recommendation.Reason)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/autoscaler/algorithm"
+	"github.com/volcano-sh/kthena/pkg/autoscaler/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// newScalingDecision describes the decision to scale the targets from the current to the desired instances.
+// The recommended instances are clamped by the limit the recommendation algorithm applied, unless the behavior
+// of the policy changed them.
+func newScalingDecision(metrics []algorithm.Metrics, current, recommended, desired int32,
+	recommendedClampedBy, correctedClampedBy workload.ScalingLimit) *workload.ScalingDecision {
+	clampedBy := correctedClampedBy
+	if clampedBy == "" && desired == recommended {
+		clampedBy = recommendedClampedBy
+	}
+	decision := &workload.ScalingDecision{
+		Time:                metav1.Now(),
+		Metrics:             sumMetrics(metrics),
+		CurrentReplicas:     current,
+		RecommendedReplicas: recommended,
+		DesiredReplicas:     desired,
+		ClampedBy:           clampedBy,
+	}
+	switch {
+	case desired > current:
+		decision.Reason = workload.ScalingDecisionScaledUp
+		decision.Message = fmt.Sprintf("scaled up from %d to %d replicas", current, desired)
+	case desired < current:
+		decision.Reason = workload.ScalingDecisionScaledDown
+		decision.Message = fmt.Sprintf("scaled down from %d to %d replicas", current, desired)
+	default:
+		decision.Reason = workload.ScalingDecisionUnchanged
+		decision.Message = fmt.Sprintf("kept %d replicas", current)
+		if clampedBy == "" && recommended == current {
+			decision.Message += ", the metrics are within the tolerance of their targets"
+		}
+	}
+	if clampedBy != "" {
+		decision.Message += fmt.Sprintf(", %d replicas were recommended and clamped by %s", recommended, clampedBy)
+	}
+	return decision
+}
+
+// newMissingMetricsDecision describes the decision to keep the targets when none of their metrics were collected.
+func newMissingMetricsDecision(current int32) *workload.ScalingDecision {
+	return &workload.ScalingDecision{
+		Time:            metav1.Now(),
+		Reason:          workload.ScalingDecisionMissingMetrics,
+		Message:         fmt.Sprintf("kept %d replicas, no metric was collected from the ready instances of the targets", current),
+		CurrentReplicas: current,
+		DesiredReplicas: current,
+	}
+}
+
+// newPausedDecision describes the decision to keep the targets while one of them is paused or suspended.
+func newPausedDecision(current int32, target string) *workload.ScalingDecision {
+	return &workload.ScalingDecision{
+		Time:            metav1.Now(),
+		Reason:          workload.ScalingDecisionPaused,
+		Message:         fmt.Sprintf("kept %d replicas, %s is paused or suspended", current, target),
+		CurrentReplicas: current,
+		DesiredReplicas: current,
+	}
+}
+
+// markDryRun marks the decision as made in dry-run mode, in which the targets are not scaled.
+func markDryRun(decision *workload.ScalingDecision) {
+	decision.DryRun = true
+	decision.Message = "dry run, would have " + decision.Message
+}
+
+// sumMetrics sums the metrics collected from the instances of all the targets.
+func sumMetrics(metrics []algorithm.Metrics) map[string]resource.Quantity {
+	sums := make(algorithm.Metrics)
+	for _, m := range metrics {
+		for name, value := range m {
+			sums[name] += value
+		}
+	}
+	if len(sums) == 0 {
+		return nil
+	}
+	quantities := make(map[string]resource.Quantity, len(sums))
+	for name, value := range sums {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		quantities[name] = *resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI)
+	}
+	return quantities
+}
+
+// sameScalingDecision reports whether two decisions only differ by their time and metric values.
+func sameScalingDecision(a, b *workload.ScalingDecision) bool {
+	return a.Reason == b.Reason && a.CurrentReplicas == b.CurrentReplicas && a.RecommendedReplicas == b.RecommendedReplicas &&
+		a.DesiredReplicas == b.DesiredReplicas && a.ClampedBy == b.ClampedBy && a.DryRun == b.DryRun
+}
+
+// RecordScalingDecision appends the decision to the status of the binding and records it as an event of the
+// binding, unless it repeats the last decision. Only the latest decisions are kept.
+func RecordScalingDecision(ctx context.Context, client clientset.Interface, recorder record.EventRecorder,
+	binding *workload.AutoscalingPolicyBinding, decision *workload.ScalingDecision) error {
+	if decision == nil {
+		return nil
+	}
+	if n := len(binding.Status.Decisions); n > 0 && sameScalingDecision(&binding.Status.Decisions[n-1], decision) {
+		return nil
+	}
+	eventType := corev1.EventTypeNormal
+	if decision.Reason == workload.ScalingDecisionMissingMetrics {
+		eventType = corev1.EventTypeWarning
+	}
+	recorder.Event(binding, eventType, string(decision.Reason), decision.Message)
+
+	binding = binding.DeepCopy()
+	binding.Status.Decisions = append(binding.Status.Decisions, *decision)
+	if n := len(binding.Status.Decisions); n > util.ScalingDecisionHistoryLimit {
+		binding.Status.Decisions = binding.Status.Decisions[n-util.ScalingDecisionHistoryLimit:]
+	}
+	if !decision.DryRun && (decision.Reason == workload.ScalingDecisionScaledUp || decision.Reason == workload.ScalingDecisionScaledDown) {
+		binding.Status.LastScaleTime = &decision.Time
+	}
+	_, err := client.WorkloadV1alpha1().AutoscalingPolicyBindings(binding.Namespace).UpdateStatus(ctx, binding, metav1.UpdateOptions{})
+	return err
+}
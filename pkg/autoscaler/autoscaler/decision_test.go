@@ -0,0 +1,157 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/autoscaler/algorithm"
+	"github.com/volcano-sh/kthena/pkg/autoscaler/util"
+)
+
+func TestNewScalingDecision(t *testing.T) {
+	metrics := []algorithm.Metrics{{"kv_cache": 0.5}, {"kv_cache": 0.25}}
+	tests := []struct {
+		name                 string
+		current              int32
+		recommended          int32
+		desired              int32
+		recommendedClampedBy workload.ScalingLimit
+		correctedClampedBy   workload.ScalingLimit
+		wantReason           workload.ScalingDecisionReason
+		wantClampedBy        workload.ScalingLimit
+	}{
+		{
+			name:        "scale up",
+			current:     2,
+			recommended: 4,
+			desired:     4,
+			wantReason:  workload.ScalingDecisionScaledUp,
+		},
+		{
+			name:               "scale up clamped by the rate",
+			current:            2,
+			recommended:        8,
+			desired:            4,
+			correctedClampedBy: workload.ScalingLimitScaleUpRate,
+			wantReason:         workload.ScalingDecisionScaledUp,
+			wantClampedBy:      workload.ScalingLimitScaleUpRate,
+		},
+		{
+			name:                 "scale down clamped by the minimum replicas",
+			current:              4,
+			recommended:          1,
+			desired:              1,
+			recommendedClampedBy: workload.ScalingLimitMinReplicas,
+			wantReason:           workload.ScalingDecisionScaledDown,
+			wantClampedBy:        workload.ScalingLimitMinReplicas,
+		},
+		{
+			name:               "scale down held by the stabilization window",
+			current:            4,
+			recommended:        2,
+			desired:            4,
+			correctedClampedBy: workload.ScalingLimitScaleDownStabilizationWindow,
+			wantReason:         workload.ScalingDecisionUnchanged,
+			wantClampedBy:      workload.ScalingLimitScaleDownStabilizationWindow,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := newScalingDecision(metrics, tt.current, tt.recommended, tt.desired, tt.recommendedClampedBy, tt.correctedClampedBy)
+			assert.Equal(t, tt.wantReason, decision.Reason)
+			assert.Equal(t, tt.wantClampedBy, decision.ClampedBy)
+			assert.Equal(t, tt.desired, decision.DesiredReplicas)
+			value := decision.Metrics["kv_cache"]
+			assert.Equal(t, "750m", value.String())
+		})
+	}
+}
+
+func TestRecordScalingDecision(t *testing.T) {
+	binding := &workload.AutoscalingPolicyBinding{ObjectMeta: metav1.ObjectMeta{Name: "binding", Namespace: "default"}}
+	client := fake.NewSimpleClientset(binding)
+	recorder := record.NewFakeRecorder(100)
+	ctx := context.Background()
+
+	get := func() *workload.AutoscalingPolicyBinding {
+		b, err := client.WorkloadV1alpha1().AutoscalingPolicyBindings("default").Get(ctx, "binding", metav1.GetOptions{})
+		require.NoError(t, err)
+		return b
+	}
+
+	require.NoError(t, RecordScalingDecision(ctx, client, recorder, get(), newScalingDecision(nil, 1, 2, 2, "", "")))
+	b := get()
+	require.Len(t, b.Status.Decisions, 1)
+	assert.Equal(t, workload.ScalingDecisionScaledUp, b.Status.Decisions[0].Reason)
+	assert.NotNil(t, b.Status.LastScaleTime)
+	assert.Len(t, recorder.Events, 1)
+
+	// A repeated decision is neither recorded nor emitted again
+	require.NoError(t, RecordScalingDecision(ctx, client, recorder, b, newScalingDecision(nil, 1, 2, 2, "", "")))
+	assert.Len(t, get().Status.Decisions, 1)
+	assert.Len(t, recorder.Events, 1)
+
+	for i := int32(0); i < int32(util.ScalingDecisionHistoryLimit)+2; i++ {
+		require.NoError(t, RecordScalingDecision(ctx, client, recorder, get(), newScalingDecision(nil, 2+i, 3+i, 3+i, "", "")))
+	}
+	b = get()
+	require.Len(t, b.Status.Decisions, util.ScalingDecisionHistoryLimit)
+	assert.Equal(t, int32(util.ScalingDecisionHistoryLimit)+4, b.Status.Decisions[util.ScalingDecisionHistoryLimit-1].DesiredReplicas)
+
+	require.NoError(t, RecordScalingDecision(ctx, client, recorder, get(), newMissingMetricsDecision(3)))
+	event := <-recorder.Events
+	assert.Contains(t, event, "Normal ScaledUp")
+	for len(recorder.Events) > 1 {
+		<-recorder.Events
+	}
+	assert.Contains(t, <-recorder.Events, "Warning MissingMetrics")
+}
+
+func TestRecordDryRunScalingDecision(t *testing.T) {
+	binding := &workload.AutoscalingPolicyBinding{ObjectMeta: metav1.ObjectMeta{Name: "binding", Namespace: "default"}}
+	client := fake.NewSimpleClientset(binding)
+	recorder := record.NewFakeRecorder(10)
+	ctx := context.Background()
+
+	decision := newScalingDecision(nil, 2, 4, 4, "", "")
+	markDryRun(decision)
+	assert.Equal(t, "dry run, would have scaled up from 2 to 4 replicas", decision.Message)
+	require.NoError(t, RecordScalingDecision(ctx, client, recorder, binding, decision))
+
+	b, err := client.WorkloadV1alpha1().AutoscalingPolicyBindings("default").Get(ctx, "binding", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, b.Status.Decisions, 1)
+	assert.True(t, b.Status.Decisions[0].DryRun)
+	// The targets were not scaled
+	assert.Nil(t, b.Status.LastScaleTime)
+
+	// The same decision made once the dry-run mode is disabled is recorded
+	require.NoError(t, RecordScalingDecision(ctx, client, recorder, b, newScalingDecision(nil, 2, 4, 4, "", "")))
+	b, err = client.WorkloadV1alpha1().AutoscalingPolicyBindings("default").Get(ctx, "binding", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Len(t, b.Status.Decisions, 2)
+	assert.NotNil(t, b.Status.LastScaleTime)
+}
@@ -18,7 +18,6 @@ package autoscaler
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -32,6 +31,7 @@ import (
 	"github.com/volcano-sh/kthena/pkg/autoscaler/histogram"
 	"github.com/volcano-sh/kthena/pkg/autoscaler/util"
 	inferControllerUtils "github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
 	"istio.io/istio/pkg/util/sets"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -45,6 +45,8 @@ type MetricCollector struct {
 	Target          *v1alpha1.Target
 	Scope           Scope
 	WatchMetricList sets.String
+	// ReadyInstancesCount is the number of instances the metrics were last summed over
+	ReadyInstancesCount int32
 }
 
 func NewMetricCollector(target *v1alpha1.Target, binding *v1alpha1.AutoscalingPolicyBinding, metricTargets map[string]float64) *MetricCollector {
@@ -104,6 +106,7 @@ func (collector *MetricCollector) UpdateMetrics(ctx context.Context, podLister l
 		return
 	}
 	readyInstancesMetric = instanceInfo.MetricsMap
+	collector.ReadyInstancesCount = int32(len(pods))
 	collector.PastHistograms.Append(currentHistograms)
 	return
 }
@@ -132,7 +135,7 @@ func (collector *MetricCollector) fetchMetricsFromPods(ctx context.Context, pods
 			ip := pod.Status.PodIP
 			podCtx, cancel := context.WithTimeout(ctx, util.AutoscaleCtxTimeoutSeconds*time.Second)
 			defer cancel()
-			url := fmt.Sprintf("http://%s:%d%s", ip, collector.Target.MetricEndpoint.Port, collector.Target.MetricEndpoint.Uri)
+			url := "http://" + netutil.HostPort(ip, collector.Target.MetricEndpoint.Port) + collector.Target.MetricEndpoint.Uri
 
 			req, _ := http.NewRequestWithContext(podCtx, http.MethodGet, url, nil)
 			resp, err := http.DefaultClient.Do(req)
@@ -25,7 +25,9 @@ import (
 	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 	"github.com/volcano-sh/kthena/pkg/autoscaler/algorithm"
 	"github.com/volcano-sh/kthena/pkg/autoscaler/util"
+	corev1 "k8s.io/api/core/v1"
 	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 )
@@ -139,7 +141,7 @@ func NewOptimizer(behavior *workload.AutoscalingPolicyBehavior, binding *workloa
 	}
 }
 
-func (optimizer *Optimizer) Optimize(ctx context.Context, client clientset.Interface, modelInferLister workloadLister.ModelServingLister, podLister listerv1.PodLister, autoscalePolicy *workload.AutoscalingPolicy) error {
+func (optimizer *Optimizer) Optimize(ctx context.Context, client clientset.Interface, recorder record.EventRecorder, modelInferLister workloadLister.ModelServingLister, podLister listerv1.PodLister, autoscalePolicy *workload.AutoscalingPolicy, dryRun bool) (*workload.ScalingDecision, error) {
 	size := len(optimizer.Meta.Config.Params)
 	unreadyInstancesCount := int32(0)
 	readyInstancesMetrics := make([]algorithm.Metrics, 0, size)
@@ -157,7 +159,12 @@ func (optimizer *Optimizer) Optimize(ctx context.Context, client clientset.Inter
 		modelInfer, err := util.GetModelInferTarget(modelInferLister, optimizer.Meta.Scope.Namespace, param.Target.TargetRef.Name)
 		if err != nil {
 			klog.Errorf("get model infer error: %v", err)
-			return err
+			return nil, err
+		}
+		if modelInfer.Spec.Paused || modelInfer.Spec.Suspend {
+			// The replicas are distributed across all the targets, none of them is scaled while one is paused or suspended
+			klog.InfoS("skip optimizing since modelInfer is paused or suspended", "modelInfer", klog.KObj(modelInfer))
+			return newPausedDecision(currentInstancesCount+*modelInfer.Spec.Replicas, modelInfer.Name), nil
 		}
 		currentInstancesCount += *modelInfer.Spec.Replicas
 		klog.Infof("ModelBooster infer:%s, current replicas:%d", modelInfer.Name, modelInfer.Spec.Replicas)
@@ -172,24 +179,28 @@ func (optimizer *Optimizer) Optimize(ctx context.Context, client clientset.Inter
 		modelInferList = append(modelInferList, modelInfer)
 	}
 	// Get recommended replicas of all model infer instances
+	scaleUpTolerance, scaleDownTolerance := tolerances(&autoscalePolicy.Spec)
 	instancesAlgorithm := algorithm.RecommendedInstancesAlgorithm{
 		MinInstances:          optimizer.Meta.MinReplicas,
 		MaxInstances:          optimizer.Meta.MaxReplicas,
 		CurrentInstancesCount: currentInstancesCount,
-		Tolerance:             float64(autoscalePolicy.Spec.TolerancePercent) * 0.01,
+		ScaleUpTolerance:      scaleUpTolerance,
+		ScaleDownTolerance:    scaleDownTolerance,
 		MetricTargets:         optimizer.Meta.MetricTargets,
 		UnreadyInstancesCount: unreadyInstancesCount,
 		ReadyInstancesMetrics: readyInstancesMetrics,
 		ExternalMetrics:       make(algorithm.Metrics),
 	}
-	recommendedInstances, skip := instancesAlgorithm.GetRecommendedInstances()
+	recommendedInstances, recommendedClampedBy, skip := instancesAlgorithm.GetRecommendedInstances()
 	if skip {
 		klog.Warning("skip recommended instances")
-		return nil
+		return newMissingMetricsDecision(currentInstancesCount), nil
 	}
 	if recommendedInstances*100 >= currentInstancesCount*(*autoscalePolicy.Spec.Behavior.ScaleUp.PanicPolicy.PanicThresholdPercent) {
 		optimizer.Status.RefreshPanicMode()
 	}
+	// The stabilization windows hold the recommendations before they are limited by the scaling velocity
+	optimizer.Status.AppendRecommendation(recommendedInstances)
 	CorrectedInstancesAlgorithm := algorithm.CorrectedInstancesAlgorithm{
 		IsPanic:              optimizer.Status.IsPanicMode(),
 		History:              optimizer.Status.History,
@@ -198,11 +209,18 @@ func (optimizer *Optimizer) Optimize(ctx context.Context, client clientset.Inter
 		MaxInstances:         optimizer.Meta.MaxReplicas,
 		CurrentInstances:     currentInstancesCount,
 		RecommendedInstances: recommendedInstances}
-	recommendedInstances = CorrectedInstancesAlgorithm.GetCorrectedInstances()
-
-	klog.InfoS("autoscale controller", "recommendedInstances", recommendedInstances, "correctedInstances", recommendedInstances)
-	optimizer.Status.AppendRecommendation(recommendedInstances)
-	optimizer.Status.AppendCorrected(recommendedInstances)
+	correctedInstances, correctedClampedBy := CorrectedInstancesAlgorithm.GetCorrectedInstances()
+
+	klog.InfoS("autoscale controller", "recommendedInstances", recommendedInstances, "correctedInstances", correctedInstances)
+	optimizer.Status.AppendCorrected(correctedInstances)
+	decision := newScalingDecision(readyInstancesMetrics, currentInstancesCount, recommendedInstances,
+		correctedInstances, recommendedClampedBy, correctedClampedBy)
+	recommendedInstances = correctedInstances
+	if dryRun {
+		markDryRun(decision)
+		klog.InfoS("dry run, modelInfer replicas are not updated", "replicas", recommendedInstances)
+		return decision, nil
+	}
 
 	replicasMap := optimizer.Meta.RestoreReplicasOfEachBackend(recommendedInstances)
 
@@ -217,8 +235,9 @@ func (optimizer *Optimizer) Optimize(ctx context.Context, client clientset.Inter
 		err := util.UpdateModelInfer(ctx, client, modelInferCopy)
 		if err != nil {
 			klog.Errorf("failed to update modelInfer replicas for modelInfer.Name: %s, error: %v", modelInfer.Name, err)
-			return err
+			return nil, err
 		}
+		recorder.Eventf(modelInfer, corev1.EventTypeNormal, util.SuccessfulRescaleReason, "Scaled from %d to %d replicas", *modelInfer.Spec.Replicas, *modelInferCopy.Spec.Replicas)
 	}
-	return nil
+	return decision, nil
 }
@@ -0,0 +1,131 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"time"
+
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/autoscaler/algorithm"
+	"github.com/volcano-sh/kthena/pkg/autoscaler/datastructure"
+	"github.com/volcano-sh/kthena/pkg/autoscaler/util"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/klog/v2"
+)
+
+// Predictor records the request rate of a target per slot of a season, and forecasts it from the past seasons.
+type Predictor struct {
+	RequestMetricName    string
+	TargetRequestRate    float64
+	LookaheadMillis      int64
+	MinConfidence        float64
+	RequestRates         *datastructure.SeasonalRingBuffer
+	lastRequestCount     float64
+	lastRequestTimestamp int64
+	getCurrentTimestamp  func() int64
+	// spec is the predictive policy the predictor was built from.
+	spec         *workload.AutoscalingPolicyPredictive
+	seasonPeriod time.Duration
+	seasons      int32
+}
+
+func NewPredictor(predictive *workload.AutoscalingPolicyPredictive) *Predictor {
+	seasonPeriod := util.PredictiveDefaultSeasonPeriod
+	if predictive.SeasonPeriod != nil && predictive.SeasonPeriod.Duration > 0 {
+		seasonPeriod = predictive.SeasonPeriod.Duration
+	}
+	seasons := int32(util.PredictiveDefaultSeasons)
+	if predictive.Seasons != nil && *predictive.Seasons > 0 {
+		seasons = *predictive.Seasons
+	}
+	lookahead := util.PredictiveDefaultLookahead
+	if predictive.Lookahead != nil {
+		lookahead = predictive.Lookahead.Duration
+	}
+	// A lookahead shorter than a slot would forecast the current slot, which the reactive scaling already serves
+	lookahead = max(lookahead, util.PredictiveSlotSeconds*time.Second)
+	minConfidencePercent := int32(util.PredictiveDefaultMinConfidencePercent)
+	if predictive.MinConfidencePercent != nil {
+		minConfidencePercent = *predictive.MinConfidencePercent
+	}
+	return &Predictor{
+		RequestMetricName: predictive.RequestMetricName,
+		TargetRequestRate: predictive.TargetRequestRate.AsFloat64Slow(),
+		LookaheadMillis:   lookahead.Milliseconds(),
+		MinConfidence:     float64(minConfidencePercent) * 0.01,
+		RequestRates: datastructure.NewSeasonalRingBuffer(seasonPeriod.Milliseconds(),
+			(util.PredictiveSlotSeconds * time.Second).Milliseconds(), int64(seasons)),
+		getCurrentTimestamp: util.GetCurrentTimestamp,
+		spec:                predictive.DeepCopy(),
+		seasonPeriod:        seasonPeriod,
+		seasons:             seasons,
+	}
+}
+
+// Update returns the predictor of the changed predictive policy, the predictor itself when the policy is unchanged.
+// The recorded request rates are kept when they are still recorded the same way.
+func (predictor *Predictor) Update(predictive *workload.AutoscalingPolicyPredictive) *Predictor {
+	if equality.Semantic.DeepEqual(predictor.spec, predictive) {
+		return predictor
+	}
+	updated := NewPredictor(predictive)
+	updated.getCurrentTimestamp = predictor.getCurrentTimestamp
+	if updated.RequestMetricName == predictor.RequestMetricName && updated.seasonPeriod == predictor.seasonPeriod && updated.seasons == predictor.seasons {
+		updated.RequestRates = predictor.RequestRates
+		updated.lastRequestCount = predictor.lastRequestCount
+		updated.lastRequestTimestamp = predictor.lastRequestTimestamp
+	}
+	klog.InfoS("predictive policy changed", "metric", updated.RequestMetricName, "keepRecordedRates", updated.RequestRates == predictor.RequestRates)
+	return updated
+}
+
+// Record derives the request rate from the request counter summed over the ready instances.
+// A decreasing counter, caused by instances being removed or restarted, resets the rate computation.
+func (predictor *Predictor) Record(readyInstancesMetrics algorithm.Metrics) {
+	requestCount, ok := readyInstancesMetrics[predictor.RequestMetricName]
+	if !ok {
+		return
+	}
+	currentTimestamp := predictor.getCurrentTimestamp()
+	if predictor.lastRequestTimestamp > 0 && currentTimestamp > predictor.lastRequestTimestamp && requestCount >= predictor.lastRequestCount {
+		requestRate := (requestCount - predictor.lastRequestCount) * 1000 / float64(currentTimestamp-predictor.lastRequestTimestamp)
+		predictor.RequestRates.Append(requestRate)
+		klog.V(4).InfoS("record request rate", "metric", predictor.RequestMetricName, "requestRate", requestRate)
+	}
+	predictor.lastRequestCount = requestCount
+	predictor.lastRequestTimestamp = currentTimestamp
+}
+
+// Predict returns the instances to scale to, given the instances recommended from the current metrics.
+func (predictor *Predictor) Predict(recommendedInstances int32, minInstances int32, maxInstances int32) int32 {
+	forecastRequestRate, confidence, ok := predictor.RequestRates.Forecast(predictor.LookaheadMillis)
+	if !ok {
+		return recommendedInstances
+	}
+	predicted := algorithm.PredictedInstancesAlgorithm{
+		MinInstances:         minInstances,
+		MaxInstances:         maxInstances,
+		RecommendedInstances: recommendedInstances,
+		ForecastRequestRate:  forecastRequestRate,
+		TargetRequestRate:    predictor.TargetRequestRate,
+		Confidence:           confidence,
+		MinConfidence:        predictor.MinConfidence,
+	}.GetPredictedInstances()
+	klog.V(4).InfoS("predictive scaling", "forecastRequestRate", forecastRequestRate, "confidence", confidence,
+		"recommendedInstances", recommendedInstances, "predictedInstances", predicted)
+	return predicted
+}
@@ -0,0 +1,83 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/autoscaler/algorithm"
+)
+
+func newPredictivePolicy(lookahead time.Duration) *workload.AutoscalingPolicyPredictive {
+	return &workload.AutoscalingPolicyPredictive{
+		RequestMetricName: "vllm:request_success_total",
+		TargetRequestRate: resource.MustParse("5"),
+		SeasonPeriod:      &metav1.Duration{Duration: time.Hour},
+		Seasons:           ptr.To[int32](2),
+		Lookahead:         &metav1.Duration{Duration: lookahead},
+	}
+}
+
+func TestNewPredictorLookahead(t *testing.T) {
+	assert.Equal(t, (5 * time.Minute).Milliseconds(), NewPredictor(newPredictivePolicy(5*time.Minute)).LookaheadMillis)
+	// The forecast is always for a later slot than the current one
+	assert.Equal(t, time.Minute.Milliseconds(), NewPredictor(newPredictivePolicy(0)).LookaheadMillis)
+	assert.Equal(t, time.Minute.Milliseconds(), NewPredictor(newPredictivePolicy(10*time.Second)).LookaheadMillis)
+}
+
+func TestPredictorUpdate(t *testing.T) {
+	predictor := NewPredictor(newPredictivePolicy(5 * time.Minute))
+	predictor.Record(algorithm.Metrics{"vllm:request_success_total": 10})
+
+	// An unchanged policy keeps the predictor
+	assert.Same(t, predictor, predictor.Update(newPredictivePolicy(5*time.Minute)))
+
+	// A new lookahead or target keeps the recorded request rates
+	changed := newPredictivePolicy(10 * time.Minute)
+	changed.TargetRequestRate = resource.MustParse("8")
+	updated := predictor.Update(changed)
+	assert.NotSame(t, predictor, updated)
+	assert.Equal(t, (10 * time.Minute).Milliseconds(), updated.LookaheadMillis)
+	assert.Equal(t, 8.0, updated.TargetRequestRate)
+	assert.Same(t, predictor.RequestRates, updated.RequestRates)
+	assert.Equal(t, 10.0, updated.lastRequestCount)
+
+	// A new season drops them, they were recorded for another period
+	reseasoned := changed.DeepCopy()
+	reseasoned.SeasonPeriod = &metav1.Duration{Duration: 24 * time.Hour}
+	rebuilt := updated.Update(reseasoned)
+	assert.NotSame(t, updated.RequestRates, rebuilt.RequestRates)
+	assert.Zero(t, rebuilt.lastRequestCount)
+}
+
+func TestAutoscalerUpdatePredictive(t *testing.T) {
+	autoscaler := &Autoscaler{Collector: NewMetricCollector(&workload.Target{}, &workload.AutoscalingPolicyBinding{}, nil)}
+
+	autoscaler.UpdatePredictive(newPredictivePolicy(5 * time.Minute))
+	assert.NotNil(t, autoscaler.Predictor)
+	assert.True(t, autoscaler.Collector.WatchMetricList.Contains("vllm:request_success_total"))
+
+	autoscaler.UpdatePredictive(nil)
+	assert.Nil(t, autoscaler.Predictor)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"math"
+
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/autoscaler/algorithm"
+	"github.com/volcano-sh/kthena/pkg/autoscaler/datastructure"
+	"github.com/volcano-sh/kthena/pkg/autoscaler/util"
+	"k8s.io/klog/v2"
+)
+
+// Recommender records the peak KV-cache usage, batch size and GPU memory usage of an instance of a target,
+// and recommends the engine resources the instances should be started with.
+type Recommender struct {
+	KVCacheUsageMetricName    string
+	BatchSizeMetricName       string
+	GPUMemoryUsageMetricName  string
+	TargetKVCacheUsagePercent int32
+	WindowMillis              int64
+	PeakKVCacheUsage          *datastructure.RmqRecordSlidingWindow[float64]
+	PeakBatchSize             *datastructure.RmqRecordSlidingWindow[float64]
+	PeakGPUMemoryUsage        *datastructure.RmqRecordSlidingWindow[float64]
+	firstRecordTimestamp      int64
+	getCurrentTimestamp       func() int64
+}
+
+func NewRecommender(vertical *workload.AutoscalingPolicyVerticalRecommendation) *Recommender {
+	kvCacheUsageMetricName := vertical.KVCacheUsageMetricName
+	if kvCacheUsageMetricName == "" {
+		kvCacheUsageMetricName = util.VerticalDefaultKVCacheUsageMetricName
+	}
+	batchSizeMetricName := vertical.BatchSizeMetricName
+	if batchSizeMetricName == "" {
+		batchSizeMetricName = util.VerticalDefaultBatchSizeMetricName
+	}
+	targetKVCacheUsagePercent := int32(util.VerticalDefaultTargetKVCacheUsagePercent)
+	if vertical.TargetKVCacheUsagePercent != nil && *vertical.TargetKVCacheUsagePercent > 0 {
+		targetKVCacheUsagePercent = *vertical.TargetKVCacheUsagePercent
+	}
+	window := util.VerticalDefaultWindow
+	if vertical.Window != nil && vertical.Window.Duration > 0 {
+		window = vertical.Window.Duration
+	}
+	return &Recommender{
+		KVCacheUsageMetricName:    kvCacheUsageMetricName,
+		BatchSizeMetricName:       batchSizeMetricName,
+		GPUMemoryUsageMetricName:  vertical.GPUMemoryUsageMetricName,
+		TargetKVCacheUsagePercent: targetKVCacheUsagePercent,
+		WindowMillis:              window.Milliseconds(),
+		PeakKVCacheUsage:          datastructure.NewMaximumRecordSlidingWindow[float64](window.Milliseconds()),
+		PeakBatchSize:             datastructure.NewMaximumRecordSlidingWindow[float64](window.Milliseconds()),
+		PeakGPUMemoryUsage:        datastructure.NewMaximumRecordSlidingWindow[float64](window.Milliseconds()),
+		getCurrentTimestamp:       util.GetCurrentTimestamp,
+	}
+}
+
+// MetricNames returns the metrics the recommender needs to be collected.
+func (recommender *Recommender) MetricNames() []string {
+	names := []string{recommender.KVCacheUsageMetricName, recommender.BatchSizeMetricName}
+	if recommender.GPUMemoryUsageMetricName != "" {
+		names = append(names, recommender.GPUMemoryUsageMetricName)
+	}
+	return names
+}
+
+// Record records the metrics summed over the ready instances as the average of an instance.
+func (recommender *Recommender) Record(readyInstancesMetrics algorithm.Metrics, readyInstancesCount int32) {
+	kvCacheUsage, ok := readyInstancesMetrics[recommender.KVCacheUsageMetricName]
+	if !ok || readyInstancesCount <= 0 {
+		return
+	}
+	instances := float64(readyInstancesCount)
+	recommender.PeakKVCacheUsage.Append(kvCacheUsage / instances)
+	recommender.PeakBatchSize.Append(readyInstancesMetrics[recommender.BatchSizeMetricName] / instances)
+	if gpuMemoryUsage, ok := readyInstancesMetrics[recommender.GPUMemoryUsageMetricName]; ok && recommender.GPUMemoryUsageMetricName != "" {
+		recommender.PeakGPUMemoryUsage.Append(gpuMemoryUsage / instances)
+	}
+	if recommender.firstRecordTimestamp == 0 {
+		recommender.firstRecordTimestamp = recommender.getCurrentTimestamp()
+	}
+}
+
+// Recommend returns the engine resources recommended for the instances of the target, given the resources
+// they are currently started with. No recommendation is made until the metrics were observed over a whole window.
+func (recommender *Recommender) Recommend(role string, gpuMemoryUtilizationPercent int32, tensorParallelSize int32) (workload.ResourceRecommendation, bool) {
+	if recommender.firstRecordTimestamp == 0 || recommender.getCurrentTimestamp()-recommender.firstRecordTimestamp < recommender.WindowMillis {
+		return workload.ResourceRecommendation{}, false
+	}
+	peakKVCacheUsage, ok := recommender.PeakKVCacheUsage.GetBest()
+	if !ok {
+		return workload.ResourceRecommendation{}, false
+	}
+	peakBatchSize, _ := recommender.PeakBatchSize.GetBest()
+	peakGPUMemoryUsagePercent := int32(-1)
+	if peakGPUMemoryUsage, ok := recommender.PeakGPUMemoryUsage.GetBest(); ok {
+		peakGPUMemoryUsagePercent = toPercent(peakGPUMemoryUsage)
+	}
+
+	alg := algorithm.VerticalRecommendationAlgorithm{
+		CurrentGPUMemoryUtilizationPercent: gpuMemoryUtilizationPercent,
+		CurrentTensorParallelSize:          tensorParallelSize,
+		TargetKVCacheUsagePercent:          recommender.TargetKVCacheUsagePercent,
+		PeakKVCacheUsagePercent:            toPercent(peakKVCacheUsage),
+		PeakBatchSize:                      int32(math.Ceil(peakBatchSize)),
+		PeakGPUMemoryUsagePercent:          peakGPUMemoryUsagePercent,
+	}
+	recommended := alg.GetRecommendation()
+	klog.V(4).InfoS("vertical recommendation", "role", role, "peakKVCacheUsagePercent", alg.PeakKVCacheUsagePercent, "peakBatchSize", alg.PeakBatchSize,
+		"gpuMemoryUtilizationPercent", recommended.GPUMemoryUtilizationPercent, "tensorParallelSize", recommended.TensorParallelSize)
+	return workload.ResourceRecommendation{
+		Role:                        role,
+		GPUMemoryUtilizationPercent: recommended.GPUMemoryUtilizationPercent,
+		TensorParallelSize:          recommended.TensorParallelSize,
+		PeakKVCacheUsagePercent:     alg.PeakKVCacheUsagePercent,
+		PeakBatchSize:               alg.PeakBatchSize,
+		Reason:                      recommended.Reason,
+	}, true
+}
+
+func toPercent(fraction float64) int32 {
+	return int32(math.Round(fraction * 100))
+}
@@ -24,8 +24,11 @@ import (
 	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 	"github.com/volcano-sh/kthena/pkg/autoscaler/algorithm"
 	"github.com/volcano-sh/kthena/pkg/autoscaler/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 )
 
@@ -33,6 +36,10 @@ type Autoscaler struct {
 	Collector *MetricCollector
 	Status    *Status
 	Meta      *ScalingMeta
+	// Predictor is nil unless predictive scaling is enabled in the policy
+	Predictor *Predictor
+	// Recommender is nil unless vertical recommendation is enabled in the policy
+	Recommender *Recommender
 }
 type ScalingMeta struct {
 	Config        *workload.ScalingConfiguration
@@ -41,8 +48,8 @@ type ScalingMeta struct {
 	Namespace     string
 }
 
-func NewAutoscaler(behavior *workload.AutoscalingPolicyBehavior, binding *workload.AutoscalingPolicyBinding, metricTargets map[string]float64) *Autoscaler {
-	return &Autoscaler{
+func NewAutoscaler(behavior *workload.AutoscalingPolicyBehavior, predictive *workload.AutoscalingPolicyPredictive, vertical *workload.AutoscalingPolicyVerticalRecommendation, binding *workload.AutoscalingPolicyBinding, metricTargets map[string]float64) *Autoscaler {
+	scaler := &Autoscaler{
 		Status:    NewStatus(behavior),
 		Collector: NewMetricCollector(&binding.Spec.ScalingConfiguration.Target, binding, metricTargets),
 		Meta: &ScalingMeta{
@@ -52,42 +59,92 @@ func NewAutoscaler(behavior *workload.AutoscalingPolicyBehavior, binding *worklo
 			MetricTargets: metricTargets,
 		},
 	}
+	if predictive != nil {
+		scaler.Predictor = NewPredictor(predictive)
+		scaler.Collector.WatchMetricList.Insert(predictive.RequestMetricName)
+	}
+	if vertical != nil {
+		scaler.Recommender = NewRecommender(vertical)
+		scaler.Collector.WatchMetricList.InsertAll(scaler.Recommender.MetricNames()...)
+	}
+	return scaler
 }
 
-func (autoscaler *Autoscaler) Scale(ctx context.Context, client clientset.Interface, modelServingLister workloadLister.ModelServingLister, podLister listerv1.PodLister, autoscalePolicy *workload.AutoscalingPolicy) error {
+// UpdatePredictive rebuilds the predictor when the predictive policy changed, nil disables predictive scaling.
+func (autoscaler *Autoscaler) UpdatePredictive(predictive *workload.AutoscalingPolicyPredictive) {
+	switch {
+	case predictive == nil:
+		autoscaler.Predictor = nil
+	case autoscaler.Predictor == nil:
+		autoscaler.Predictor = NewPredictor(predictive)
+	default:
+		autoscaler.Predictor = autoscaler.Predictor.Update(predictive)
+	}
+	if predictive != nil {
+		autoscaler.Collector.WatchMetricList.Insert(predictive.RequestMetricName)
+	}
+}
+
+func (autoscaler *Autoscaler) Scale(ctx context.Context, client clientset.Interface, recorder record.EventRecorder, modelServingLister workloadLister.ModelServingLister, podLister listerv1.PodLister, autoscalePolicy *workload.AutoscalingPolicy, dryRun bool) (*workload.ScalingDecision, error) {
 	// Get autoscaler target(model infer) instance
 	modelInfer, err := util.GetModelInferTarget(modelServingLister, autoscaler.Meta.Namespace, autoscaler.Meta.Config.Target.TargetRef.Name)
 	if err != nil {
 		klog.Errorf("get model infer error: %v", err)
-		return err
+		return nil, err
 	}
-	currentInstancesCount := *modelInfer.Spec.Replicas
-	klog.InfoS("doAutoscale modelInfer", "currentInstancesCount", currentInstancesCount)
+	target := &autoscaler.Meta.Config.Target
+	currentInstancesCount, err := util.GetTargetReplicas(modelInfer, target)
+	if err != nil {
+		klog.Errorf("get target replicas error: %v", err)
+		return nil, err
+	}
+	if modelInfer.Spec.Paused || modelInfer.Spec.Suspend {
+		klog.InfoS("skip autoscaling paused or suspended modelInfer", "modelInfer", klog.KObj(modelInfer))
+		return newPausedDecision(currentInstancesCount, modelInfer.Name), nil
+	}
+	klog.InfoS("doAutoscale modelInfer", "role", target.RoleName, "currentInstancesCount", currentInstancesCount)
 
 	unreadyInstancesCount, readyInstancesMetrics, err := autoscaler.Collector.UpdateMetrics(ctx, podLister)
 	if err != nil {
 		klog.Errorf("update metrics error: %v", err)
-		return err
+		return nil, err
+	}
+	if autoscaler.Predictor != nil {
+		autoscaler.Predictor.Record(readyInstancesMetrics)
+	}
+	if autoscaler.Recommender != nil {
+		autoscaler.Recommender.Record(readyInstancesMetrics, autoscaler.Collector.ReadyInstancesCount)
+		// A failed recommendation must not block scaling
+		if err := autoscaler.recommendResources(ctx, client, modelInfer); err != nil {
+			klog.Errorf("recommend resources error: %v", err)
+		}
 	}
 	// minInstance <- AutoscaleScope, currentInstancesCount(replicas) <- workload
+	scaleUpTolerance, scaleDownTolerance := tolerances(&autoscalePolicy.Spec)
 	instancesAlgorithm := algorithm.RecommendedInstancesAlgorithm{
 		MinInstances:          autoscaler.Meta.Config.MinReplicas,
 		MaxInstances:          autoscaler.Meta.Config.MaxReplicas,
 		CurrentInstancesCount: currentInstancesCount,
-		Tolerance:             float64(autoscalePolicy.Spec.TolerancePercent) * 0.01,
+		ScaleUpTolerance:      scaleUpTolerance,
+		ScaleDownTolerance:    scaleDownTolerance,
 		MetricTargets:         autoscaler.Meta.MetricTargets,
 		UnreadyInstancesCount: unreadyInstancesCount,
 		ReadyInstancesMetrics: []algorithm.Metrics{readyInstancesMetrics},
 		ExternalMetrics:       make(algorithm.Metrics),
 	}
-	recommendedInstances, skip := instancesAlgorithm.GetRecommendedInstances()
+	recommendedInstances, recommendedClampedBy, skip := instancesAlgorithm.GetRecommendedInstances()
 	if skip {
 		klog.Warning("skip recommended instances")
-		return nil
+		return newMissingMetricsDecision(currentInstancesCount), nil
 	}
 	if autoscalePolicy.Spec.Behavior.ScaleUp.PanicPolicy.PanicThresholdPercent != nil && recommendedInstances*100 >= currentInstancesCount*(*autoscalePolicy.Spec.Behavior.ScaleUp.PanicPolicy.PanicThresholdPercent) {
 		autoscaler.Status.RefreshPanicMode()
 	}
+	if autoscaler.Predictor != nil {
+		recommendedInstances = autoscaler.Predictor.Predict(recommendedInstances, autoscaler.Meta.Config.MinReplicas, autoscaler.Meta.Config.MaxReplicas)
+	}
+	// The stabilization windows hold the recommendations before they are limited by the scaling velocity
+	autoscaler.Status.AppendRecommendation(recommendedInstances)
 	CorrectedInstancesAlgorithm := algorithm.CorrectedInstancesAlgorithm{
 		IsPanic:              autoscaler.Status.IsPanicMode(),
 		History:              autoscaler.Status.History,
@@ -97,20 +154,57 @@ func (autoscaler *Autoscaler) Scale(ctx context.Context, client clientset.Interf
 		CurrentInstances:     currentInstancesCount,
 		RecommendedInstances: recommendedInstances,
 	}
-	recommendedInstances = CorrectedInstancesAlgorithm.GetCorrectedInstances()
+	correctedInstances, correctedClampedBy := CorrectedInstancesAlgorithm.GetCorrectedInstances()
 
-	klog.InfoS("autoscale controller", "recommendedInstances", recommendedInstances, "correctedInstances", recommendedInstances)
-	autoscaler.Status.AppendRecommendation(recommendedInstances)
-	autoscaler.Status.AppendCorrected(recommendedInstances)
+	klog.InfoS("autoscale controller", "recommendedInstances", recommendedInstances, "correctedInstances", correctedInstances)
+	autoscaler.Status.AppendCorrected(correctedInstances)
+	decision := newScalingDecision(instancesAlgorithm.ReadyInstancesMetrics, currentInstancesCount, recommendedInstances,
+		correctedInstances, recommendedClampedBy, correctedClampedBy)
+	recommendedInstances = correctedInstances
 
-	if modelInfer.Spec.Replicas == nil || *modelInfer.Spec.Replicas == recommendedInstances {
+	if dryRun {
+		markDryRun(decision)
+		klog.InfoS("dry run, modelInfer replicas are not updated", "modelInfer", klog.KObj(modelInfer), "replicas", recommendedInstances)
+		return decision, nil
+	}
+	if currentInstancesCount == recommendedInstances {
 		klog.InfoS("modelInfer replicas no need to update")
-		return nil
+		return decision, nil
+	}
+	// The lister returns the cached object, which must not be mutated
+	modelInfer = modelInfer.DeepCopy()
+	if err = util.SetTargetReplicas(modelInfer, target, recommendedInstances); err != nil {
+		return nil, err
 	}
-	*modelInfer.Spec.Replicas = recommendedInstances
 	if err = util.UpdateModelInfer(ctx, client, modelInfer); err != nil {
 		klog.Errorf("failed to update modelInfer replicas for modelInfer.Name: %s, error: %v", modelInfer.Name, err)
+		return nil, err
+	}
+	if target.RoleName == "" {
+		recorder.Eventf(modelInfer, corev1.EventTypeNormal, util.SuccessfulRescaleReason, "Scaled from %d to %d replicas", currentInstancesCount, recommendedInstances)
+	} else {
+		recorder.Eventf(modelInfer, corev1.EventTypeNormal, util.SuccessfulRescaleReason, "Scaled role %s from %d to %d replicas", target.RoleName, currentInstancesCount, recommendedInstances)
+	}
+	return decision, nil
+}
+
+// recommendResources writes the engine resources recommended for the target to the status of the modelInfer.
+func (autoscaler *Autoscaler) recommendResources(ctx context.Context, client clientset.Interface, modelInfer *workload.ModelServing) error {
+	target := &autoscaler.Meta.Config.Target
+	gpuMemoryUtilizationPercent, tensorParallelSize, err := util.GetTargetEngineResources(modelInfer, target)
+	if err != nil {
 		return err
 	}
-	return nil
+	recommendation, ok := autoscaler.Recommender.Recommend(target.RoleName, gpuMemoryUtilizationPercent, tensorParallelSize)
+	if !ok {
+		return nil
+	}
+	if current := util.GetResourceRecommendation(modelInfer, target.RoleName); current != nil {
+		recommendation.LastUpdateTime = current.LastUpdateTime
+		if *current == recommendation {
+			return nil
+		}
+	}
+	recommendation.LastUpdateTime = metav1.Now()
+	return util.UpdateResourceRecommendation(ctx, client, modelInfer.Namespace, modelInfer.Name, recommendation)
 }
@@ -85,3 +85,16 @@ func (s *Status) RefreshPanicMode() {
 func (s *Status) IsPanicMode() bool {
 	return s.PanicModeHoldMilliseconds > 0 && util.GetCurrentTimestamp() <= s.PanicModeEndsAt
 }
+
+// tolerances returns the tolerances of the policy for scaling up and down, the tolerance of a direction
+// overriding the tolerance of the policy.
+func tolerances(spec *v1alpha1.AutoscalingPolicySpec) (scaleUp, scaleDown float64) {
+	scaleUpPercent, scaleDownPercent := spec.TolerancePercent, spec.TolerancePercent
+	if spec.Behavior.ScaleUp.StablePolicy.TolerancePercent != nil {
+		scaleUpPercent = *spec.Behavior.ScaleUp.StablePolicy.TolerancePercent
+	}
+	if spec.Behavior.ScaleDown.TolerancePercent != nil {
+		scaleDownPercent = *spec.Behavior.ScaleDown.TolerancePercent
+	}
+	return float64(scaleUpPercent) * 0.01, float64(scaleDownPercent) * 0.01
+}
@@ -18,6 +18,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/volcano-sh/kthena/pkg/autoscaler/autoscaler"
@@ -28,7 +29,11 @@ import (
 	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 	"github.com/volcano-sh/kthena/pkg/autoscaler/algorithm"
 	"github.com/volcano-sh/kthena/pkg/autoscaler/util"
+	"github.com/volcano-sh/kthena/pkg/controller/events"
+	"github.com/volcano-sh/kthena/pkg/controller/metrics"
+	"github.com/volcano-sh/kthena/pkg/features"
 	"istio.io/istio/pkg/util/sets"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
@@ -38,15 +43,19 @@ import (
 	"k8s.io/client-go/kubernetes"
 	listerv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const autoscaleControllerName = "autoscaler"
+
 type AutoscaleController struct {
 	// Client for k8s. Use it to call K8S API
 	kubeClient kubernetes.Interface
 	// client for custom resource
 	client                             clientset.Interface
+	recorder                           record.EventRecorder
 	namespace                          string
 	autoscalingPoliciesLister          workloadLister.AutoscalingPolicyLister
 	autoscalingPoliciesInformer        cache.Controller
@@ -58,9 +67,13 @@ type AutoscaleController struct {
 	podsInformer                       cache.Controller
 	scalerMap                          map[string]*autoscaler.Autoscaler
 	optimizerMap                       map[string]*autoscaler.Optimizer
+	// dryRun computes and records the scaling decisions of all the bindings without scaling their targets.
+	dryRun bool
+	// bindings are the names of the bindings whose decisions are reported in the metrics.
+	bindings sets.Set[string]
 }
 
-func NewAutoscaleController(kubeClient kubernetes.Interface, client clientset.Interface, namespace string) *AutoscaleController {
+func NewAutoscaleController(kubeClient kubernetes.Interface, client clientset.Interface, namespace string, dryRun bool) *AutoscaleController {
 	informerFactory := informersv1alpha1.NewSharedInformerFactory(client, 0)
 	modelInferInformer := informerFactory.Workload().V1alpha1().ModelServings()
 	autoscalingPoliciesInformer := informerFactory.Workload().V1alpha1().AutoscalingPolicies()
@@ -80,6 +93,7 @@ func NewAutoscaleController(kubeClient kubernetes.Interface, client clientset.In
 	ac := &AutoscaleController{
 		kubeClient:                         kubeClient,
 		client:                             client,
+		recorder:                           events.NewRecorder(kubeClient, autoscaleControllerName),
 		namespace:                          namespace,
 		autoscalingPoliciesLister:          autoscalingPoliciesInformer.Lister(),
 		autoscalingPoliciesInformer:        autoscalingPoliciesInformer.Informer(),
@@ -91,6 +105,8 @@ func NewAutoscaleController(kubeClient kubernetes.Interface, client clientset.In
 		podsInformer:                       podsInformer.Informer(),
 		scalerMap:                          make(map[string]*autoscaler.Autoscaler),
 		optimizerMap:                       make(map[string]*autoscaler.Optimizer),
+		dryRun:                             dryRun,
+		bindings:                           sets.New[string](),
 	}
 	return ac
 }
@@ -103,7 +119,7 @@ func (ac *AutoscaleController) Run(ctx context.Context) {
 	go ac.autoscalingPoliciesBindingInformer.RunWithContext(ctx)
 	go ac.modelServingInformer.RunWithContext(ctx)
 	go ac.podsInformer.RunWithContext(ctx)
-	cache.WaitForCacheSync(ctx.Done(),
+	metrics.WaitForCacheSync(autoscaleControllerName, ctx.Done(),
 		ac.autoscalingPoliciesInformer.HasSynced,
 		ac.autoscalingPoliciesBindingInformer.HasSynced,
 		ac.modelServingInformer.HasSynced,
@@ -123,18 +139,26 @@ func (ac *AutoscaleController) Run(ctx context.Context) {
 // move the current state of the cluster closer to the desired state.
 func (ac *AutoscaleController) Reconcile(ctx context.Context) {
 	klog.InfoS("start to reconcile")
+	start := time.Now()
+	var reconcileErr error
+	defer func() {
+		metrics.ObserveReconcile(autoscaleControllerName, start, reconcileErr)
+	}()
 	ctx, cancel := context.WithTimeout(ctx, util.AutoscaleCtxTimeoutSeconds*time.Second)
 	defer cancel()
 	bindingList, err := ac.client.WorkloadV1alpha1().AutoscalingPolicyBindings(ac.namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		klog.Errorf("failed to list autoscaling policy bindings, err:%v", err)
+		reconcileErr = err
 		return
 	}
 
 	scalerSet := sets.New[string]()
 	optimizerSet := sets.New[string]()
+	bindingSet := sets.New[string]()
 
 	for _, binding := range bindingList.Items {
+		bindingSet.Insert(binding.Name)
 		policyName := binding.Spec.PolicyRef.Name
 		klog.InfoS("global", "autoscalingPolicyName", policyName)
 		if policyName == "" {
@@ -142,7 +166,7 @@ func (ac *AutoscaleController) Reconcile(ctx context.Context) {
 		}
 		if binding.Spec.ScalingConfiguration != nil {
 			target := binding.Spec.ScalingConfiguration.Target
-			instanceKey := formatAutoscalerMapKey(binding.Name, target.TargetRef.Name)
+			instanceKey := formatAutoscalerMapKey(binding.Name, formatTargetName(&target))
 			scalerSet.Insert(instanceKey)
 		} else if binding.Spec.OptimizerConfiguration != nil {
 			autoscalerMapKey := formatAutoscalerMapKey(binding.ObjectMeta.Name, "")
@@ -162,11 +186,18 @@ func (ac *AutoscaleController) Reconcile(ctx context.Context) {
 		}
 	}
 
+	for name := range ac.bindings.Difference(bindingSet) {
+		metrics.DeleteAutoscalerReplicas(ac.namespace, name)
+		ac.bindings.Delete(name)
+	}
+
 	klog.InfoS("start to process autoscale")
 	for _, binding := range bindingList.Items {
 		err := ac.schedule(ctx, &binding)
 		if err != nil {
 			klog.Errorf("failed to process autoscale,err:%v", err)
+			ac.recorder.Eventf(&binding, corev1.EventTypeWarning, util.FailedRescaleReason, "Failed to autoscale: %v", err)
+			reconcileErr = errors.Join(reconcileErr, err)
 			continue
 		}
 	}
@@ -180,6 +211,7 @@ func (ac *AutoscaleController) schedule(ctx context.Context, binding *workload.A
 		return err
 	}
 	metricTargets := getMetricTargets(autoscalePolicy)
+	dryRun := ac.dryRun || autoscalePolicy.Spec.DryRun
 	if binding.Spec.OptimizerConfiguration != nil {
 		optimizerKey := formatAutoscalerMapKey(binding.Name, "")
 		optimizer, ok := ac.optimizerMap[optimizerKey]
@@ -187,22 +219,34 @@ func (ac *AutoscaleController) schedule(ctx context.Context, binding *workload.A
 			optimizer = autoscaler.NewOptimizer(&autoscalePolicy.Spec.Behavior, binding, metricTargets)
 			ac.optimizerMap[optimizerKey] = optimizer
 		}
-		if err := optimizer.Optimize(ctx, ac.client, ac.modelServingLister, ac.podsLister, autoscalePolicy); err != nil {
+		decision, err := optimizer.Optimize(ctx, ac.client, ac.recorder, ac.modelServingLister, ac.podsLister, autoscalePolicy, dryRun)
+		if err != nil {
 			klog.Errorf("failed to do optimize, err: %v", err)
 			return err
 		}
+		ac.recordScalingDecision(ctx, binding, decision, dryRun)
 	} else if binding.Spec.ScalingConfiguration != nil {
 		target := binding.Spec.ScalingConfiguration.Target
-		instanceKey := formatAutoscalerMapKey(binding.Name, target.TargetRef.Name)
+		instanceKey := formatAutoscalerMapKey(binding.Name, formatTargetName(&target))
+		predictive := autoscalePolicy.Spec.Predictive
+		if predictive != nil && !features.DefaultFeatureGate.Enabled(features.PredictiveAutoscaling) {
+			klog.V(2).Infof("feature gate %s is disabled, ignoring the predictive policy of %s/%s", features.PredictiveAutoscaling, autoscalePolicy.Namespace, autoscalePolicy.Name)
+			predictive = nil
+		}
 		scalingAutoscaler, ok := ac.scalerMap[instanceKey]
 		if !ok {
-			scalingAutoscaler = autoscaler.NewAutoscaler(&autoscalePolicy.Spec.Behavior, binding, metricTargets)
+			scalingAutoscaler = autoscaler.NewAutoscaler(&autoscalePolicy.Spec.Behavior, predictive, autoscalePolicy.Spec.VerticalRecommendation, binding, metricTargets)
 			ac.scalerMap[instanceKey] = scalingAutoscaler
+		} else {
+			// The predictive policy may have changed since the autoscaler was created
+			scalingAutoscaler.UpdatePredictive(predictive)
 		}
-		if err := scalingAutoscaler.Scale(ctx, ac.client, ac.modelServingLister, ac.podsLister, autoscalePolicy); err != nil {
+		decision, err := scalingAutoscaler.Scale(ctx, ac.client, ac.recorder, ac.modelServingLister, ac.podsLister, autoscalePolicy, dryRun)
+		if err != nil {
 			klog.Errorf("failed to do scaling, err: %v", err)
 			return err
 		}
+		ac.recordScalingDecision(ctx, binding, decision, dryRun)
 	} else {
 		klog.Warningf("binding %s has no scalingConfiguration and optimizerConfiguration", binding.Name)
 	}
@@ -211,6 +255,18 @@ func (ac *AutoscaleController) schedule(ctx context.Context, binding *workload.A
 	return nil
 }
 
+// recordScalingDecision records the decision in the status of the binding and in the metrics. A failure is only
+// logged, the targets have been scaled already.
+func (ac *AutoscaleController) recordScalingDecision(ctx context.Context, binding *workload.AutoscalingPolicyBinding, decision *workload.ScalingDecision, dryRun bool) {
+	if decision != nil {
+		metrics.SetAutoscalerReplicas(binding.Namespace, binding.Name, decision.CurrentReplicas, decision.DesiredReplicas, dryRun)
+		ac.bindings.Insert(binding.Name)
+	}
+	if err := autoscaler.RecordScalingDecision(ctx, ac.client, ac.recorder, binding, decision); err != nil {
+		klog.Errorf("failed to record scaling decision of binding %s/%s: %v", binding.Namespace, binding.Name, err)
+	}
+}
+
 func (ac *AutoscaleController) getAutoscalePolicy(autoscalingPolicyName string, namespace string) (*workload.AutoscalingPolicy, error) {
 	autoscalingPolicy, err := ac.autoscalingPoliciesLister.AutoscalingPolicies(namespace).Get(autoscalingPolicyName)
 	if err != nil {
@@ -227,6 +283,14 @@ func formatAutoscalerMapKey(bindingName string, instanceName string) string {
 	return bindingName + "#" + instanceName
 }
 
+// formatTargetName distinguishes the roles of a ModelServing, which are scaled independently
+func formatTargetName(target *workload.Target) string {
+	if target.RoleName == "" {
+		return target.TargetRef.Name
+	}
+	return target.TargetRef.Name + "/" + target.RoleName
+}
+
 func getMetricTargets(autoscalePolicy *workload.AutoscalingPolicy) algorithm.Metrics {
 	metricTargets := algorithm.Metrics{}
 	if autoscalePolicy == nil {
@@ -0,0 +1,106 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastructure
+
+import (
+	"math"
+
+	"github.com/volcano-sh/kthena/pkg/autoscaler/util"
+)
+
+type seasonalSlot struct {
+	// index is the absolute slot index the values were recorded in, used to detect overwritten slots
+	index int64
+	sum   float64
+	count int64
+}
+
+// SeasonalRingBuffer records a time series in fixed-width slots for a number of past seasons,
+// and forecasts a value from the same slot of the previous seasons.
+type SeasonalRingBuffer struct {
+	slots               []seasonalSlot
+	slotMilliseconds    int64
+	slotsPerSeason      int64
+	seasons             int64
+	getCurrentTimestamp func() int64
+}
+
+func NewSeasonalRingBuffer(seasonMilliseconds int64, slotMilliseconds int64, seasons int64) *SeasonalRingBuffer {
+	slotsPerSeason := max(seasonMilliseconds/slotMilliseconds, 1)
+	seasons = max(seasons, 1)
+	return &SeasonalRingBuffer{
+		slots:               make([]seasonalSlot, slotsPerSeason*seasons),
+		slotMilliseconds:    slotMilliseconds,
+		slotsPerSeason:      slotsPerSeason,
+		seasons:             seasons,
+		getCurrentTimestamp: util.GetCurrentTimestamp,
+	}
+}
+
+func (buffer *SeasonalRingBuffer) slot(index int64) *seasonalSlot {
+	return &buffer.slots[index%int64(len(buffer.slots))]
+}
+
+// Append records a value in the slot of the current time.
+func (buffer *SeasonalRingBuffer) Append(value float64) {
+	index := buffer.getCurrentTimestamp() / buffer.slotMilliseconds
+	slot := buffer.slot(index)
+	if slot.index != index {
+		*slot = seasonalSlot{index: index}
+	}
+	slot.sum += value
+	slot.count++
+}
+
+// Forecast returns the average of the values recorded in the same slot of the past seasons, aheadMilliseconds from now.
+// The confidence, between 0 and 1, is the fraction of the past seasons recorded, reduced by the coefficient of
+// variation of their values.
+func (buffer *SeasonalRingBuffer) Forecast(aheadMilliseconds int64) (value float64, confidence float64, ok bool) {
+	target := (buffer.getCurrentTimestamp() + aheadMilliseconds) / buffer.slotMilliseconds
+	samples := make([]float64, 0, buffer.seasons)
+	for season := int64(1); season <= buffer.seasons; season++ {
+		index := target - season*buffer.slotsPerSeason
+		if index < 0 {
+			break
+		}
+		slot := buffer.slot(index)
+		if slot.index != index || slot.count == 0 {
+			continue
+		}
+		samples = append(samples, slot.sum/float64(slot.count))
+	}
+	if len(samples) == 0 {
+		return 0, 0, false
+	}
+
+	mean := 0.0
+	for _, sample := range samples {
+		mean += sample
+	}
+	mean /= float64(len(samples))
+	variance := 0.0
+	for _, sample := range samples {
+		variance += (sample - mean) * (sample - mean)
+	}
+	variance /= float64(len(samples))
+
+	confidence = float64(len(samples)) / float64(buffer.seasons)
+	if mean > 0 {
+		confidence *= 1 - math.Min(math.Sqrt(variance)/mean, 1)
+	}
+	return mean, confidence, true
+}
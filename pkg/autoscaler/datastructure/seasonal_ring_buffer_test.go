@@ -0,0 +1,76 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastructure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func hourFunc(day int, hour int, minute int) func() int64 {
+	return func() int64 { return time.Date(2025, 1, day, hour, minute, 0, 0, time.UTC).UnixMilli() }
+}
+
+func Test_seasonalRingBuffer(t *testing.T) {
+	assert := assert.New(t)
+
+	day := (24 * time.Hour).Milliseconds()
+	buffer := NewSeasonalRingBuffer(day, time.Minute.Milliseconds(), 3)
+
+	buffer.getCurrentTimestamp = hourFunc(1, 9, 0)
+	_, _, ok := buffer.Forecast(0)
+	assert.False(ok)
+
+	// two values in the same slot are averaged
+	buffer.getCurrentTimestamp = hourFunc(1, 10, 0)
+	buffer.Append(90)
+	buffer.Append(110)
+	buffer.getCurrentTimestamp = hourFunc(2, 10, 0)
+	buffer.Append(100)
+
+	// forecast 10:00 of day 3 from 9:55
+	buffer.getCurrentTimestamp = hourFunc(3, 9, 55)
+	value, confidence, ok := buffer.Forecast((5 * time.Minute).Milliseconds())
+	assert.True(ok)
+	assert.Equal(100.0, value)
+	// two of three seasons recorded, no variation
+	assert.InDelta(2.0/3, confidence, 1e-9)
+
+	// no data recorded for 11:00
+	_, _, ok = buffer.Forecast((65 * time.Minute).Milliseconds())
+	assert.False(ok)
+
+	buffer.getCurrentTimestamp = hourFunc(3, 10, 0)
+	buffer.Append(200)
+
+	// seasons disagree, confidence is reduced
+	buffer.getCurrentTimestamp = hourFunc(4, 9, 55)
+	value, confidence, ok = buffer.Forecast((5 * time.Minute).Milliseconds())
+	assert.True(ok)
+	assert.InDelta(133.33, value, 0.01)
+	assert.Less(confidence, 0.7)
+	assert.Greater(confidence, 0.5)
+
+	// the oldest season expires after three days
+	buffer.getCurrentTimestamp = hourFunc(5, 9, 55)
+	value, confidence, ok = buffer.Forecast((5 * time.Minute).Milliseconds())
+	assert.True(ok)
+	assert.Equal(150.0, value)
+	assert.Less(confidence, 2.0/3)
+}
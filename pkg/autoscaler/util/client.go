@@ -18,6 +18,7 @@ package util
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
@@ -32,7 +33,8 @@ import (
 )
 
 const (
-	ModelInferEntryPodLabel = "leader"
+	ModelInferEntryPodLabel      = "leader"
+	ModelInferEntryPodLabelValue = "true"
 )
 
 func GetModelInferTarget(lister workloadLister.ModelServingLister, namespace string, name string) (*workload.ModelServing, error) {
@@ -67,6 +69,44 @@ func UpdateModelInfer(ctx context.Context, client clientset.Interface, modelInfe
 	return nil
 }
 
+// UpdateResourceRecommendation sets the resource recommendation of a role in the status of the modelInfer.
+func UpdateResourceRecommendation(ctx context.Context, client clientset.Interface, namespace string, name string, recommendation workload.ResourceRecommendation) error {
+	modelInferCtx, cancel := context.WithTimeout(ctx, AutoscaleCtxTimeoutSeconds*time.Second)
+	defer cancel()
+	modelInfer, err := client.WorkloadV1alpha1().ModelServings(namespace).Get(modelInferCtx, name, metav1.GetOptions{})
+	if err != nil {
+		klog.Errorf("failed to get modelInfer,err: %v", err)
+		return err
+	}
+	recommendations := modelInfer.Status.ResourceRecommendations
+	found := false
+	for i := range recommendations {
+		if recommendations[i].Role == recommendation.Role {
+			recommendations[i] = recommendation
+			found = true
+		}
+	}
+	if !found {
+		recommendations = append(recommendations, recommendation)
+	}
+	modelInfer.Status.ResourceRecommendations = recommendations
+	if _, err := client.WorkloadV1alpha1().ModelServings(namespace).UpdateStatus(modelInferCtx, modelInfer, metav1.UpdateOptions{}); err != nil {
+		klog.Errorf("failed to update modelInfer status,err: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetResourceRecommendation returns the resource recommendation of a role in the status of the modelInfer.
+func GetResourceRecommendation(modelInfer *workload.ModelServing, roleName string) *workload.ResourceRecommendation {
+	for i := range modelInfer.Status.ResourceRecommendations {
+		if modelInfer.Status.ResourceRecommendations[i].Role == roleName {
+			return &modelInfer.Status.ResourceRecommendations[i]
+		}
+	}
+	return nil
+}
+
 func GetTargetLabels(target *workload.Target) map[string]string {
 	if target.TargetRef.Kind == workload.ModelServingKind.Kind {
 		lbs := map[string]string{}
@@ -74,8 +114,56 @@ func GetTargetLabels(target *workload.Target) map[string]string {
 			lbs = maps.Clone(target.AdditionalMatchLabels)
 		}
 		lbs[workload.ModelServingNameLabelKey] = target.TargetRef.Name
-		lbs[workload.RoleLabelKey] = ModelInferEntryPodLabel
+		if target.RoleName != "" {
+			// Only the entry pod of each replica of the role serves requests
+			lbs[workload.RoleLabelKey] = target.RoleName
+			lbs[workload.EntryLabelKey] = ModelInferEntryPodLabelValue
+		} else {
+			lbs[workload.RoleLabelKey] = ModelInferEntryPodLabel
+		}
 		return lbs
 	}
 	return nil
 }
+
+// GetTargetReplicas returns the replicas scaled by the target: the replicas of the role
+// when the target references a role, the replicas of the ModelServing otherwise.
+func GetTargetReplicas(modelInfer *workload.ModelServing, target *workload.Target) (int32, error) {
+	if target.RoleName == "" {
+		if modelInfer.Spec.Replicas == nil {
+			return 1, nil
+		}
+		return *modelInfer.Spec.Replicas, nil
+	}
+	role, err := getTargetRole(modelInfer, target.RoleName)
+	if err != nil {
+		return 0, err
+	}
+	if role.Replicas == nil {
+		return 1, nil
+	}
+	return *role.Replicas, nil
+}
+
+// SetTargetReplicas sets the replicas scaled by the target.
+func SetTargetReplicas(modelInfer *workload.ModelServing, target *workload.Target, replicas int32) error {
+	if target.RoleName == "" {
+		modelInfer.Spec.Replicas = &replicas
+		return nil
+	}
+	role, err := getTargetRole(modelInfer, target.RoleName)
+	if err != nil {
+		return err
+	}
+	role.Replicas = &replicas
+	return nil
+}
+
+func getTargetRole(modelInfer *workload.ModelServing, roleName string) (*workload.Role, error) {
+	for i := range modelInfer.Spec.Template.Roles {
+		if modelInfer.Spec.Template.Roles[i].Name == roleName {
+			return &modelInfer.Spec.Template.Roles[i], nil
+		}
+	}
+	return nil, fmt.Errorf("role %s not found in modelInfer %s/%s", roleName, modelInfer.Namespace, modelInfer.Name)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func newPDModelServing() *workload.ModelServing {
+	ms := &workload.ModelServing{}
+	ms.Name = "pd"
+	ms.Spec.Replicas = ptr.To[int32](2)
+	ms.Spec.Template.Roles = []workload.Role{
+		{Name: "prefill", Replicas: ptr.To[int32](1)},
+		{Name: "decode"},
+	}
+	return ms
+}
+
+func TestGetTargetLabels(t *testing.T) {
+	target := &workload.Target{TargetRef: corev1.ObjectReference{Kind: workload.ModelServingKind.Kind, Name: "pd"}}
+	assert.Equal(t, map[string]string{
+		workload.ModelServingNameLabelKey: "pd",
+		workload.RoleLabelKey:             ModelInferEntryPodLabel,
+	}, GetTargetLabels(target))
+
+	target.RoleName = "decode"
+	assert.Equal(t, map[string]string{
+		workload.ModelServingNameLabelKey: "pd",
+		workload.RoleLabelKey:             "decode",
+		workload.EntryLabelKey:            ModelInferEntryPodLabelValue,
+	}, GetTargetLabels(target))
+}
+
+func TestTargetReplicas(t *testing.T) {
+	ms := newPDModelServing()
+
+	replicas, err := GetTargetReplicas(ms, &workload.Target{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), replicas)
+
+	prefill := &workload.Target{RoleName: "prefill"}
+	replicas, err = GetTargetReplicas(ms, prefill)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), replicas)
+
+	// Replicas of a role default to 1
+	decode := &workload.Target{RoleName: "decode"}
+	replicas, err = GetTargetReplicas(ms, decode)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), replicas)
+
+	// Only the replicas of the role are updated
+	require.NoError(t, SetTargetReplicas(ms, decode, 3))
+	assert.Equal(t, int32(3), *ms.Spec.Template.Roles[1].Replicas)
+	assert.Equal(t, int32(1), *ms.Spec.Template.Roles[0].Replicas)
+	assert.Equal(t, int32(2), *ms.Spec.Replicas)
+
+	require.NoError(t, SetTargetReplicas(ms, &workload.Target{}, 4))
+	assert.Equal(t, int32(4), *ms.Spec.Replicas)
+
+	_, err = GetTargetReplicas(ms, &workload.Target{RoleName: "worker"})
+	assert.Error(t, err)
+	assert.Error(t, SetTargetReplicas(ms, &workload.Target{RoleName: "worker"}, 1))
+}
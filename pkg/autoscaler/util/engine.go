@@ -0,0 +1,76 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	networking "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/engines"
+)
+
+// GetTargetEngineResources returns the GPU memory utilization percent and the tensor parallel size the engine
+// of the target is started with. They are read from the engine arguments of the entry template of the target role,
+// or of the first role when the target is the whole ModelServing, and default to the vLLM defaults when unset.
+// The arguments of a command line running no known engine are read as vLLM arguments.
+func GetTargetEngineResources(modelInfer *workload.ModelServing, target *workload.Target) (gpuMemoryUtilizationPercent int32, tensorParallelSize int32, err error) {
+	var role *workload.Role
+	if target.RoleName != "" {
+		if role, err = getTargetRole(modelInfer, target.RoleName); err != nil {
+			return 0, 0, err
+		}
+	} else if len(modelInfer.Spec.Template.Roles) > 0 {
+		role = &modelInfer.Spec.Template.Roles[0]
+	} else {
+		return 0, 0, fmt.Errorf("modelInfer %s/%s has no role", modelInfer.Namespace, modelInfer.Name)
+	}
+
+	var args []string
+	for _, container := range role.EntryTemplate.Spec.Containers {
+		// Arguments may also be passed as a single shell command line
+		for _, arg := range append(append([]string{}, container.Command...), container.Args...) {
+			args = append(args, strings.Fields(arg)...)
+		}
+	}
+
+	engine := engines.Detect(args)
+	if engine == nil {
+		engine = engines.Get(string(networking.VLLM))
+	}
+
+	gpuMemoryUtilizationPercent = VerticalDefaultGPUMemoryUtilizationPercent
+	if value, ok := engines.FlagValue(args, engine.Args().GPUMemoryUtilization...); ok {
+		utilization, err := strconv.ParseFloat(value, 64)
+		if err != nil || utilization <= 0 || utilization > 1 {
+			return 0, 0, fmt.Errorf("invalid gpu memory utilization %q", value)
+		}
+		gpuMemoryUtilizationPercent = int32(math.Round(utilization * 100))
+	}
+	tensorParallelSize = 1
+	if value, ok := engines.FlagValue(args, engine.Args().TensorParallelSize...); ok {
+		size, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || size < 1 {
+			return 0, 0, fmt.Errorf("invalid tensor parallel size %q", value)
+		}
+		tensorParallelSize = int32(size)
+	}
+	return gpuMemoryUtilizationPercent, tensorParallelSize, nil
+}
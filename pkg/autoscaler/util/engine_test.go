@@ -0,0 +1,72 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetTargetEngineResources(t *testing.T) {
+	ms := newPDModelServing()
+	ms.Spec.Template.Roles[0].EntryTemplate.Spec.Containers = []corev1.Container{{
+		Command: []string{"sh", "-c", "python3 -m vllm.entrypoints.openai.api_server --model Qwen/Qwen3-8B --tensor-parallel-size 2"},
+	}}
+	ms.Spec.Template.Roles[1].EntryTemplate.Spec.Containers = []corev1.Container{{
+		Args: []string{"--model", "Qwen/Qwen3-8B", "--gpu-memory-utilization=0.85", "-tp", "4"},
+	}}
+
+	// The first role is used when the target is the whole ModelServing
+	utilization, tensorParallelSize, err := GetTargetEngineResources(ms, &workload.Target{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(VerticalDefaultGPUMemoryUtilizationPercent), utilization)
+	assert.Equal(t, int32(2), tensorParallelSize)
+
+	utilization, tensorParallelSize, err = GetTargetEngineResources(ms, &workload.Target{RoleName: "decode"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(85), utilization)
+	assert.Equal(t, int32(4), tensorParallelSize)
+
+	_, _, err = GetTargetEngineResources(ms, &workload.Target{RoleName: "unknown"})
+	assert.Error(t, err)
+
+	// The flags of the engine run by the container are read
+	ms.Spec.Template.Roles[1].EntryTemplate.Spec.Containers[0].Command = []string{"text-generation-launcher"}
+	ms.Spec.Template.Roles[1].EntryTemplate.Spec.Containers[0].Args = []string{"--cuda-memory-fraction", "0.8", "--num-shard", "2", "-tp", "4"}
+	utilization, tensorParallelSize, err = GetTargetEngineResources(ms, &workload.Target{RoleName: "decode"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(80), utilization)
+	assert.Equal(t, int32(2), tensorParallelSize)
+
+	ms.Spec.Template.Roles[1].EntryTemplate.Spec.Containers[0].Command = nil
+	ms.Spec.Template.Roles[1].EntryTemplate.Spec.Containers[0].Args = []string{"--gpu-memory-utilization", "85"}
+	_, _, err = GetTargetEngineResources(ms, &workload.Target{RoleName: "decode"})
+	assert.Error(t, err)
+}
+
+func TestGetResourceRecommendation(t *testing.T) {
+	ms := newPDModelServing()
+	ms.Status.ResourceRecommendations = []workload.ResourceRecommendation{
+		{Role: "prefill", GPUMemoryUtilizationPercent: 90, TensorParallelSize: 1},
+	}
+	assert.Equal(t, int32(90), GetResourceRecommendation(ms, "prefill").GPUMemoryUtilizationPercent)
+	assert.Nil(t, GetResourceRecommendation(ms, "decode"))
+}
@@ -16,10 +16,39 @@ limitations under the License.
 
 package util
 
+import "time"
+
 const (
 	AutoscalingSyncPeriodSeconds    = 15
 	SloQuantileSlidingWindowSeconds = 60
 	SloQuantileDataKeepSeconds      = 300
 	SloQuantilePercentile           = 95
 	AutoscaleCtxTimeoutSeconds      = 3
+	// ScalingDecisionHistoryLimit is the number of decisions kept in the status of a binding
+	ScalingDecisionHistoryLimit = 10
+)
+
+const (
+	PredictiveSlotSeconds                 = 60
+	PredictiveDefaultSeasonPeriod         = 24 * time.Hour
+	PredictiveDefaultSeasons              = 7
+	PredictiveDefaultLookahead            = 5 * time.Minute
+	PredictiveDefaultMinConfidencePercent = 50
+)
+
+const (
+	VerticalDefaultKVCacheUsageMetricName      = "vllm:gpu_cache_usage_perc"
+	VerticalDefaultBatchSizeMetricName         = "vllm:num_requests_running"
+	VerticalDefaultTargetKVCacheUsagePercent   = 80
+	VerticalDefaultWindow                      = time.Hour
+	VerticalDefaultGPUMemoryUtilizationPercent = 90
+	VerticalMinGPUMemoryUtilizationPercent     = 50
+	VerticalMaxGPUMemoryUtilizationPercent     = 95
+	VerticalGPUMemoryUtilizationStepPercent    = 5
+)
+
+// Reasons of the events recorded by the autoscaler, named after the ones of the HorizontalPodAutoscaler
+const (
+	SuccessfulRescaleReason = "SuccessfulRescale"
+	FailedRescaleReason     = "FailedRescale"
 )
@@ -0,0 +1,519 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
+	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
+	workloadLister "github.com/volcano-sh/kthena/client-go/listers/workload/v1alpha1"
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/controller/metrics"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
+)
+
+const (
+	batchInferenceControllerName = "batch-inference"
+
+	// progressInterval is how often the progress of a running batch is refreshed
+	progressInterval = 10 * time.Second
+
+	// defaultCompletionWindow is the completion window of the batches which do not set one
+	defaultCompletionWindow = 24 * time.Hour
+
+	reasonJobCreated     = "JobCreated"
+	reasonBatchSucceeded = "BatchSucceeded"
+	reasonBatchFailed    = "BatchFailed"
+	reasonBatchCancelled = "BatchCancelled"
+	reasonBatchExpired   = "BatchExpired"
+)
+
+// batchProgress is reported by the batch runner, on its progress endpoint while running
+// and in its termination message once finished.
+type batchProgress struct {
+	Total     int32  `json:"total"`
+	Completed int32  `json:"completed"`
+	Failed    int32  `json:"failed"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BatchInferenceController runs every BatchInference as a Job, and reflects the progress
+// reported by the batch runner in the BatchInference status.
+type BatchInferenceController struct {
+	kubeClient kubernetes.Interface
+	client     clientset.Interface
+	httpClient *http.Client
+
+	syncHandler            func(ctx context.Context, key string) error
+	batchInferenceLister   workloadLister.BatchInferenceLister
+	batchInferenceInformer cache.SharedIndexInformer
+	jobsLister             batchlisters.JobLister
+	jobsInformer           cache.SharedIndexInformer
+	podsLister             listerv1.PodLister
+	podsInformer           cache.SharedIndexInformer
+	kthenaInformerFactory  informersv1alpha1.SharedInformerFactory
+	kubeInformerFactory    informers.SharedInformerFactory
+	workQueue              workqueue.TypedRateLimitingInterface[any]
+	defaultRouterURL       string
+	progressFetcher        func(ctx context.Context, pod *corev1.Pod) (*batchProgress, error)
+}
+
+func NewBatchInferenceController(kubeClient kubernetes.Interface, client clientset.Interface, namespace string) *BatchInferenceController {
+	kthenaInformerFactory := informersv1alpha1.NewSharedInformerFactory(client, 0)
+	batchInferenceInformer := kthenaInformerFactory.Workload().V1alpha1().BatchInferences()
+
+	// Only watch the Jobs and pods created for BatchInferences
+	kubeInformerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0,
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = BatchInferenceNameLabelKey
+		}))
+	jobsInformer := kubeInformerFactory.Batch().V1().Jobs()
+	podsInformer := kubeInformerFactory.Core().V1().Pods()
+
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	c := &BatchInferenceController{
+		kubeClient:             kubeClient,
+		client:                 client,
+		httpClient:             &http.Client{Timeout: 5 * time.Second},
+		batchInferenceLister:   batchInferenceInformer.Lister(),
+		batchInferenceInformer: batchInferenceInformer.Informer(),
+		jobsLister:             jobsInformer.Lister(),
+		jobsInformer:           jobsInformer.Informer(),
+		podsLister:             podsInformer.Lister(),
+		podsInformer:           podsInformer.Informer(),
+		kthenaInformerFactory:  kthenaInformerFactory,
+		kubeInformerFactory:    kubeInformerFactory,
+		defaultRouterURL:       fmt.Sprintf("http://kthena-router.%s.svc", namespace),
+		workQueue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[any](),
+			workqueue.TypedRateLimitingQueueConfig[any]{Name: batchInferenceControllerName}),
+	}
+	c.syncHandler = c.reconcile
+	c.progressFetcher = c.fetchProgress
+
+	_, err := c.batchInferenceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueBatchInference,
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueBatchInference(newObj)
+		},
+	})
+	if err != nil {
+		klog.Fatal("Unable to add BatchInference event handler")
+		return nil
+	}
+	_, err = c.jobsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueOwner,
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueOwner(newObj)
+		},
+		DeleteFunc: c.enqueueOwner,
+	})
+	if err != nil {
+		klog.Fatal("Unable to add Job event handler")
+		return nil
+	}
+	_, err = c.podsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueOwner(newObj)
+		},
+	})
+	if err != nil {
+		klog.Fatal("Unable to add pod event handler")
+		return nil
+	}
+	return c
+}
+
+func (c *BatchInferenceController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.workQueue.ShutDown()
+
+	c.kthenaInformerFactory.Start(ctx.Done())
+	c.kubeInformerFactory.Start(ctx.Done())
+
+	metrics.WaitForCacheSync(batchInferenceControllerName, ctx.Done(),
+		c.batchInferenceInformer.HasSynced,
+		c.jobsInformer.HasSynced,
+		c.podsInformer.HasSynced,
+	)
+
+	klog.Info("start batch inference controller")
+	for i := 0; i < workers; i++ {
+		go c.worker(ctx)
+	}
+	<-ctx.Done()
+	klog.Info("shut down batch inference controller")
+}
+
+func (c *BatchInferenceController) worker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *BatchInferenceController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := c.workQueue.Get()
+	if quit {
+		return false
+	}
+	defer c.workQueue.Done(key)
+
+	start := time.Now()
+	err := c.syncHandler(ctx, key.(string))
+	metrics.ObserveReconcile(batchInferenceControllerName, start, err)
+	if err == nil {
+		c.workQueue.Forget(key)
+		return true
+	}
+	utilruntime.HandleError(fmt.Errorf("sync %q failed with %v", key, err))
+	c.workQueue.AddRateLimited(key)
+	return true
+}
+
+func (c *BatchInferenceController) enqueueBatchInference(obj interface{}) {
+	if key, err := cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+	} else {
+		c.workQueue.Add(key)
+	}
+}
+
+// enqueueOwner enqueues the BatchInference of a Job or a pod
+func (c *BatchInferenceController) enqueueOwner(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		klog.Errorf("failed to get the object of BatchInference: %v", err)
+		return
+	}
+	name, ok := object.GetLabels()[BatchInferenceNameLabelKey]
+	if !ok {
+		return
+	}
+	c.workQueue.Add(object.GetNamespace() + "/" + name)
+}
+
+// reconcile creates the Job of a BatchInference and keeps its status up to date until the batch finishes.
+func (c *BatchInferenceController) reconcile(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return fmt.Errorf("invalid resource key: %s", err)
+	}
+	bi, err := c.batchInferenceLister.BatchInferences(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The Job is garbage collected through its owner reference
+			return nil
+		}
+		return err
+	}
+	if isFinished(bi) {
+		return nil
+	}
+
+	newStatus := bi.Status.DeepCopy()
+	job, err := c.jobsLister.Jobs(namespace).Get(jobName(bi))
+	if apierrors.IsNotFound(err) {
+		job = nil
+	} else if err != nil {
+		return err
+	} else if !metav1.IsControlledBy(job, bi) {
+		return fmt.Errorf("job %s/%s already exists and is not owned by BatchInference %s", namespace, job.Name, bi.Name)
+	}
+
+	// A cancelled or expired batch is stopped, unless its Job has already finished
+	if job == nil || !isJobFinished(job) {
+		if phase, condition, stopped := stopCondition(bi, time.Now()); stopped {
+			if job != nil {
+				// Deleting the Job terminates the runner, which reports the requests it did not send
+				err := c.kubeClient.BatchV1().Jobs(namespace).Delete(ctx, job.Name, metav1.DeleteOptions{
+					PropagationPolicy: ptr.To(metav1.DeletePropagationBackground),
+					Preconditions:     &metav1.Preconditions{UID: &job.UID},
+				})
+				if err != nil && !apierrors.IsNotFound(err) {
+					return fmt.Errorf("failed to delete Job of BatchInference %s: %v", key, err)
+				}
+				klog.V(2).Infof("Stopped Job of BatchInference %s: %s", key, condition.Message)
+			}
+			newStatus.Phase = phase
+			now := metav1.Now()
+			newStatus.CompletionTime = &now
+			meta.SetStatusCondition(&newStatus.Conditions, condition)
+			return c.updateStatus(ctx, bi, newStatus)
+		}
+		// Expire the batch at the end of its completion window
+		c.workQueue.AddAfter(key, time.Until(expirationTime(bi)))
+	}
+
+	if job == nil {
+		job, err = c.kubeClient.BatchV1().Jobs(namespace).Create(ctx, c.buildJob(bi), metav1.CreateOptions{})
+		if err == nil {
+			klog.V(2).Infof("Created Job of BatchInference %s", key)
+			newStatus.JobName = job.Name
+			newStatus.Phase = workload.BatchInferencePending
+			meta.SetStatusCondition(&newStatus.Conditions, metav1.Condition{
+				Type:    string(workload.BatchInferenceComplete),
+				Status:  metav1.ConditionFalse,
+				Reason:  reasonJobCreated,
+				Message: fmt.Sprintf("Job %s is created to run the batch", job.Name),
+			})
+			return c.updateStatus(ctx, bi, newStatus)
+		}
+		if !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create Job of BatchInference %s: %v", key, err)
+		}
+		// The Job is not in the cache yet, or belongs to someone else: only carry on with the Job of this batch
+		job, err = c.kubeClient.BatchV1().Jobs(namespace).Get(ctx, jobName(bi), metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get Job of BatchInference %s: %v", key, err)
+		}
+		if !metav1.IsControlledBy(job, bi) {
+			return fmt.Errorf("job %s/%s already exists and is not owned by BatchInference %s", namespace, job.Name, bi.Name)
+		}
+	}
+
+	newStatus.JobName = job.Name
+	if job.Status.StartTime != nil && newStatus.StartTime == nil {
+		newStatus.StartTime = job.Status.StartTime
+	}
+
+	pod := c.getRunnerPod(job)
+	switch {
+	case isJobConditionTrue(job, batchv1.JobComplete):
+		c.finish(newStatus, pod, job, workload.BatchInferenceSucceeded)
+	case isJobConditionTrue(job, batchv1.JobFailed):
+		c.finish(newStatus, pod, job, workload.BatchInferenceFailed)
+	case pod != nil && pod.Status.Phase == corev1.PodRunning:
+		newStatus.Phase = workload.BatchInferenceRunning
+		if progress, err := c.progressFetcher(ctx, pod); err != nil {
+			klog.V(4).Infof("failed to get the progress of BatchInference %s: %v", key, err)
+		} else {
+			setRequestCounts(newStatus, progress)
+		}
+		// The runner does not notify its progress, poll it while the batch is running
+		c.workQueue.AddAfter(key, progressInterval)
+	}
+
+	return c.updateStatus(ctx, bi, newStatus)
+}
+
+// finish sets the final status of the batch, from the termination message of the runner
+func (c *BatchInferenceController) finish(status *workload.BatchInferenceStatus, pod *corev1.Pod, job *batchv1.Job, phase workload.BatchInferencePhase) {
+	status.Phase = phase
+	status.CompletionTime = job.Status.CompletionTime
+	if status.CompletionTime == nil {
+		now := metav1.Now()
+		status.CompletionTime = &now
+	}
+
+	condition := metav1.Condition{
+		Type:    string(workload.BatchInferenceComplete),
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonBatchSucceeded,
+		Message: "All requests of the batch have been processed",
+	}
+	if phase == workload.BatchInferenceFailed {
+		condition.Reason = reasonBatchFailed
+		condition.Message = jobFailureMessage(job)
+	}
+
+	if progress := terminationProgress(pod); progress != nil {
+		setRequestCounts(status, progress)
+		if progress.Error != "" {
+			condition.Message = progress.Error
+		}
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// getRunnerPod returns the latest pod of the Job
+func (c *BatchInferenceController) getRunnerPod(job *batchv1.Job) *corev1.Pod {
+	selector := labels.SelectorFromSet(map[string]string{
+		BatchInferenceNameLabelKey: job.Labels[BatchInferenceNameLabelKey],
+	})
+	pods, err := c.podsLister.Pods(job.Namespace).List(selector)
+	if err != nil {
+		klog.Errorf("failed to list pods of Job %s/%s: %v", job.Namespace, job.Name, err)
+		return nil
+	}
+	var latest *corev1.Pod
+	for _, pod := range pods {
+		if !metav1.IsControlledBy(pod, job) {
+			continue
+		}
+		if latest == nil || latest.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			latest = pod
+		}
+	}
+	return latest
+}
+
+// fetchProgress gets the progress from the endpoint served by the batch runner
+func (c *BatchInferenceController) fetchProgress(ctx context.Context, pod *corev1.Pod) (*batchProgress, error) {
+	if pod.Status.PodIP == "" {
+		return nil, fmt.Errorf("pod %s/%s has no IP", pod.Namespace, pod.Name)
+	}
+	url := "http://" + netutil.HostPort(pod.Status.PodIP, progressPort) + progressPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	progress := &batchProgress{}
+	if err := json.NewDecoder(resp.Body).Decode(progress); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+func (c *BatchInferenceController) updateStatus(ctx context.Context, bi *workload.BatchInference, newStatus *workload.BatchInferenceStatus) error {
+	if equalStatus(&bi.Status, newStatus) {
+		return nil
+	}
+	biCopy := bi.DeepCopy()
+	biCopy.Status = *newStatus
+	_, err := c.client.WorkloadV1alpha1().BatchInferences(bi.Namespace).UpdateStatus(ctx, biCopy, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update status of BatchInference %s/%s: %v", bi.Namespace, bi.Name, err)
+	}
+	return nil
+}
+
+// terminationProgress parses the final progress written by the runner in its termination message
+func terminationProgress(pod *corev1.Pod) *batchProgress {
+	if pod == nil {
+		return nil
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != runnerContainerName || status.State.Terminated == nil {
+			continue
+		}
+		progress := &batchProgress{}
+		if err := json.Unmarshal([]byte(status.State.Terminated.Message), progress); err != nil {
+			klog.V(4).Infof("failed to parse termination message of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+			return nil
+		}
+		return progress
+	}
+	return nil
+}
+
+func setRequestCounts(status *workload.BatchInferenceStatus, progress *batchProgress) {
+	status.RequestCounts = workload.BatchRequestCounts{
+		Total:     progress.Total,
+		Completed: progress.Completed,
+		Failed:    progress.Failed,
+	}
+}
+
+func jobFailureMessage(job *batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Message != "" {
+			return condition.Message
+		}
+	}
+	return "The batch runner failed"
+}
+
+func isJobConditionTrue(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// stopCondition returns the final phase and condition of a batch which is cancelled, or which has expired at now.
+func stopCondition(bi *workload.BatchInference, now time.Time) (workload.BatchInferencePhase, metav1.Condition, bool) {
+	condition := metav1.Condition{
+		Type:   string(workload.BatchInferenceComplete),
+		Status: metav1.ConditionTrue,
+	}
+	switch {
+	case bi.Spec.Cancel:
+		condition.Reason = reasonBatchCancelled
+		condition.Message = "The batch was cancelled"
+		return workload.BatchInferenceCancelled, condition, true
+	case !now.Before(expirationTime(bi)):
+		condition.Reason = reasonBatchExpired
+		condition.Message = fmt.Sprintf("The batch did not finish within its completion window of %v", completionWindow(bi))
+		return workload.BatchInferenceExpired, condition, true
+	}
+	return "", condition, false
+}
+
+// expirationTime returns the end of the completion window of the batch, which starts when the batch is created.
+func expirationTime(bi *workload.BatchInference) time.Time {
+	return bi.CreationTimestamp.Add(completionWindow(bi))
+}
+
+func completionWindow(bi *workload.BatchInference) time.Duration {
+	if bi.Spec.CompletionWindow == nil || bi.Spec.CompletionWindow.Duration <= 0 {
+		return defaultCompletionWindow
+	}
+	return bi.Spec.CompletionWindow.Duration
+}
+
+func isJobFinished(job *batchv1.Job) bool {
+	return isJobConditionTrue(job, batchv1.JobComplete) || isJobConditionTrue(job, batchv1.JobFailed)
+}
+
+func isFinished(bi *workload.BatchInference) bool {
+	switch bi.Status.Phase {
+	case workload.BatchInferenceSucceeded, workload.BatchInferenceFailed, workload.BatchInferenceCancelled, workload.BatchInferenceExpired:
+		return true
+	}
+	return false
+}
+
+func equalStatus(a, b *workload.BatchInferenceStatus) bool {
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return string(aJSON) == string(bJSON)
+}
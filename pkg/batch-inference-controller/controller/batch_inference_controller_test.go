@@ -0,0 +1,266 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+func newTestBatchInference() *workload.BatchInference {
+	return &workload.BatchInference{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "batch",
+			Namespace:         "default",
+			UID:               "batch-uid",
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: workload.BatchInferenceSpec{
+			InputFileURI:  "pvc://data/batches/input.jsonl",
+			OutputFileURI: "s3://results/output.jsonl",
+			Model:         "llama",
+			Concurrency:   ptr.To[int32](4),
+		},
+	}
+}
+
+func TestBuildJob(t *testing.T) {
+	c := NewBatchInferenceController(fake.NewClientset(), kthenafake.NewSimpleClientset(), "kthena-system")
+	bi := newTestBatchInference()
+
+	job := c.buildJob(bi)
+	assert.Equal(t, "batch", job.Name)
+	assert.True(t, metav1.IsControlledBy(job, bi))
+	assert.Equal(t, "batch", job.Spec.Template.Labels[BatchInferenceNameLabelKey])
+
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{
+		"--input", "/mnt/batch/data/batches/input.jsonl",
+		"--output", "s3://results/output.jsonl",
+		"--router-url", "http://kthena-router.kthena-system.svc",
+		"--endpoint", "/v1/chat/completions",
+		"--concurrency", "4",
+		"--max-retries", "3",
+		"--progress-port", "8090",
+		"--model", "llama",
+	}, container.Args)
+
+	// Only the PVC input is mounted
+	require.Len(t, job.Spec.Template.Spec.Volumes, 1)
+	assert.Equal(t, "data", job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	assert.Equal(t, "/mnt/batch/data", container.VolumeMounts[0].MountPath)
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	bi := newTestBatchInference()
+	kubeClient := fake.NewClientset()
+	kthenaClient := kthenafake.NewSimpleClientset(bi)
+	c := NewBatchInferenceController(kubeClient, kthenaClient, "kthena-system")
+	c.progressFetcher = func(ctx context.Context, pod *corev1.Pod) (*batchProgress, error) {
+		return &batchProgress{Total: 10, Completed: 3, Failed: 1}, nil
+	}
+
+	getBatchInference := func() *workload.BatchInference {
+		latest, err := kthenaClient.WorkloadV1alpha1().BatchInferences(bi.Namespace).Get(ctx, bi.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NoError(t, c.batchInferenceInformer.GetIndexer().Update(latest))
+		return latest
+	}
+	require.NoError(t, c.batchInferenceInformer.GetIndexer().Add(bi))
+
+	// Step1: the Job is created
+	require.NoError(t, c.reconcile(ctx, "default/batch"))
+	job, err := kubeClient.BatchV1().Jobs(bi.Namespace).Get(ctx, "batch", metav1.GetOptions{})
+	require.NoError(t, err)
+	latest := getBatchInference()
+	assert.Equal(t, workload.BatchInferencePending, latest.Status.Phase)
+	assert.Equal(t, "batch", latest.Status.JobName)
+
+	// Step2: the runner pod is running, progress is reported
+	require.NoError(t, c.jobsInformer.GetIndexer().Add(job))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "batch-runner",
+			Namespace: bi.Namespace,
+			Labels:    map[string]string{BatchInferenceNameLabelKey: bi.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(job, batchv1.SchemeGroupVersion.WithKind("Job")),
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+	}
+	require.NoError(t, c.podsInformer.GetIndexer().Add(pod))
+	require.NoError(t, c.reconcile(ctx, "default/batch"))
+	latest = getBatchInference()
+	assert.Equal(t, workload.BatchInferenceRunning, latest.Status.Phase)
+	assert.Equal(t, workload.BatchRequestCounts{Total: 10, Completed: 3, Failed: 1}, latest.Status.RequestCounts)
+
+	// Step3: the Job completes, the final progress is read from the termination message
+	job = job.DeepCopy()
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	require.NoError(t, c.jobsInformer.GetIndexer().Update(job))
+	pod = pod.DeepCopy()
+	pod.Status.Phase = corev1.PodSucceeded
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name: runnerContainerName,
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Message: `{"total": 10, "completed": 9, "failed": 1}`,
+		}},
+	}}
+	require.NoError(t, c.podsInformer.GetIndexer().Update(pod))
+	require.NoError(t, c.reconcile(ctx, "default/batch"))
+	latest = getBatchInference()
+	assert.Equal(t, workload.BatchInferenceSucceeded, latest.Status.Phase)
+	assert.Equal(t, workload.BatchRequestCounts{Total: 10, Completed: 9, Failed: 1}, latest.Status.RequestCounts)
+	assert.NotNil(t, latest.Status.CompletionTime)
+	assert.True(t, meta.IsStatusConditionTrue(latest.Status.Conditions, string(workload.BatchInferenceComplete)))
+}
+
+func TestReconcileFailedJob(t *testing.T) {
+	ctx := context.Background()
+	bi := newTestBatchInference()
+	kubeClient := fake.NewClientset()
+	kthenaClient := kthenafake.NewSimpleClientset(bi)
+	c := NewBatchInferenceController(kubeClient, kthenaClient, "kthena-system")
+	require.NoError(t, c.batchInferenceInformer.GetIndexer().Add(bi))
+
+	job := c.buildJob(bi)
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+	require.NoError(t, c.jobsInformer.GetIndexer().Add(job))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "batch-runner",
+			Namespace:       bi.Namespace,
+			Labels:          map[string]string{BatchInferenceNameLabelKey: bi.Name},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(job, batchv1.SchemeGroupVersion.WithKind("Job"))},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: runnerContainerName,
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					Message: `{"total": 0, "completed": 0, "failed": 0, "error": "invalid request at line 3"}`,
+				}},
+			}},
+		},
+	}
+	require.NoError(t, c.podsInformer.GetIndexer().Add(pod))
+
+	require.NoError(t, c.reconcile(ctx, "default/batch"))
+	latest, err := kthenaClient.WorkloadV1alpha1().BatchInferences(bi.Namespace).Get(ctx, bi.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, workload.BatchInferenceFailed, latest.Status.Phase)
+	condition := meta.FindStatusCondition(latest.Status.Conditions, string(workload.BatchInferenceComplete))
+	require.NotNil(t, condition)
+	assert.Equal(t, reasonBatchFailed, condition.Reason)
+	assert.Equal(t, "invalid request at line 3", condition.Message)
+}
+
+func TestReconcileExistingJob(t *testing.T) {
+	ctx := context.Background()
+	bi := newTestBatchInference()
+
+	// The Job of the batch is not in the cache yet, it is adopted
+	c := NewBatchInferenceController(fake.NewClientset(), kthenafake.NewSimpleClientset(bi), "kthena-system")
+	require.NoError(t, c.batchInferenceInformer.GetIndexer().Add(bi))
+	job := c.buildJob(bi)
+	job.Status.StartTime = ptr.To(metav1.Now())
+	_, err := c.kubeClient.BatchV1().Jobs(bi.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, c.reconcile(ctx, "default/batch"))
+	latest, err := c.client.WorkloadV1alpha1().BatchInferences(bi.Namespace).Get(ctx, bi.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "batch", latest.Status.JobName)
+	assert.NotNil(t, latest.Status.StartTime)
+
+	// A Job of the same name which belongs to someone else is left untouched
+	other := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "batch", Namespace: bi.Namespace}}
+	c = NewBatchInferenceController(fake.NewClientset(other), kthenafake.NewSimpleClientset(bi), "kthena-system")
+	require.NoError(t, c.batchInferenceInformer.GetIndexer().Add(bi))
+	assert.ErrorContains(t, c.reconcile(ctx, "default/batch"), "not owned by BatchInference")
+	latest, err = c.client.WorkloadV1alpha1().BatchInferences(bi.Namespace).Get(ctx, bi.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, latest.Status.JobName)
+}
+
+func TestReconcileStoppedBatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(bi *workload.BatchInference)
+		phase  workload.BatchInferencePhase
+		reason string
+	}{
+		{
+			name:   "cancelled",
+			update: func(bi *workload.BatchInference) { bi.Spec.Cancel = true },
+			phase:  workload.BatchInferenceCancelled,
+			reason: reasonBatchCancelled,
+		},
+		{
+			name: "expired",
+			update: func(bi *workload.BatchInference) {
+				bi.Spec.CompletionWindow = &metav1.Duration{Duration: time.Hour}
+				bi.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+			},
+			phase:  workload.BatchInferenceExpired,
+			reason: reasonBatchExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			bi := newTestBatchInference()
+			tt.update(bi)
+			kubeClient := fake.NewClientset()
+			kthenaClient := kthenafake.NewSimpleClientset(bi)
+			c := NewBatchInferenceController(kubeClient, kthenaClient, "kthena-system")
+			require.NoError(t, c.batchInferenceInformer.GetIndexer().Add(bi))
+
+			// The running Job of the batch is deleted to stop the runner
+			job, err := kubeClient.BatchV1().Jobs(bi.Namespace).Create(ctx, c.buildJob(bi), metav1.CreateOptions{})
+			require.NoError(t, err)
+			require.NoError(t, c.jobsInformer.GetIndexer().Add(job))
+			require.NoError(t, c.reconcile(ctx, "default/batch"))
+			_, err = kubeClient.BatchV1().Jobs(bi.Namespace).Get(ctx, job.Name, metav1.GetOptions{})
+			assert.True(t, apierrors.IsNotFound(err))
+
+			latest, err := kthenaClient.WorkloadV1alpha1().BatchInferences(bi.Namespace).Get(ctx, bi.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.phase, latest.Status.Phase)
+			assert.NotNil(t, latest.Status.CompletionTime)
+			condition := meta.FindStatusCondition(latest.Status.Conditions, string(workload.BatchInferenceComplete))
+			require.NotNil(t, condition)
+			assert.Equal(t, metav1.ConditionTrue, condition.Status)
+			assert.Equal(t, tt.reason, condition.Reason)
+		})
+	}
+}
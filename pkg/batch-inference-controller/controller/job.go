@@ -0,0 +1,178 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-booster-controller/config"
+)
+
+const (
+	// BatchInferenceNameLabelKey is set on the Job and the pod running a BatchInference.
+	BatchInferenceNameLabelKey = "batchinference.volcano.sh/name"
+
+	runnerContainerName = "batch-runner"
+	// progressPort is the port the batch runner reports its progress on.
+	progressPort = 8090
+	progressPath = "/progress"
+
+	pvcURIPrefix = "pvc://"
+	pvcMountRoot = "/mnt/batch"
+
+	defaultEndpoint    = "/v1/chat/completions"
+	defaultConcurrency = 16
+	defaultMaxRetries  = 3
+)
+
+// buildJob builds the Job running the batch runner of the BatchInference. The runner is shipped in the
+// downloader image, which already contains the tooling to read and write files in s3.
+func (c *BatchInferenceController) buildJob(bi *workload.BatchInference) *batchv1.Job {
+	labels := map[string]string{
+		BatchInferenceNameLabelKey: bi.Name,
+	}
+
+	volumes, mounts := pvcVolumes(bi.Spec.InputFileURI, bi.Spec.OutputFileURI)
+	args := []string{
+		"--input", localPath(bi.Spec.InputFileURI),
+		"--output", localPath(bi.Spec.OutputFileURI),
+		"--router-url", c.routerURL(bi),
+		"--endpoint", stringOrDefault(bi.Spec.Endpoint, defaultEndpoint),
+		"--concurrency", strconv.Itoa(int(ptr.Deref(bi.Spec.Concurrency, defaultConcurrency))),
+		"--max-retries", strconv.Itoa(int(ptr.Deref(bi.Spec.MaxRetries, defaultMaxRetries))),
+		"--progress-port", strconv.Itoa(progressPort),
+	}
+	if bi.Spec.Model != "" {
+		args = append(args, "--model", bi.Spec.Model)
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName(bi),
+			Namespace: bi.Namespace,
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(bi, workload.BatchInferenceKind),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			// The runner keeps no checkpoint, a retried pod would send all the requests again
+			BackoffLimit: ptr.To[int32](0),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    runnerContainerName,
+							Image:   config.Config.DownloaderImage(),
+							Command: []string{"python", "kthena/batch/app.py"},
+							Args:    args,
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "progress",
+									ContainerPort: progressPort,
+								},
+							},
+							Env:                      bi.Spec.Env,
+							EnvFrom:                  bi.Spec.EnvFrom,
+							VolumeMounts:             mounts,
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
+
+func jobName(bi *workload.BatchInference) string {
+	return bi.Name
+}
+
+func (c *BatchInferenceController) routerURL(bi *workload.BatchInference) string {
+	if bi.Spec.RouterURL != "" {
+		return bi.Spec.RouterURL
+	}
+	return c.defaultRouterURL
+}
+
+// pvcVolumes returns the volumes and mounts of the PVCs referenced by the file URIs.
+// Every claim is mounted once, at a path derived from its name.
+func pvcVolumes(uris ...string) ([]corev1.Volume, []corev1.VolumeMount) {
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+	seen := make(map[string]bool)
+	for _, uri := range uris {
+		claim, _, ok := parsePVCURI(uri)
+		if !ok || seen[claim] {
+			continue
+		}
+		seen[claim] = true
+		volumeName := fmt.Sprintf("pvc-%d", len(volumes))
+		volumes = append(volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claim,
+				},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: path.Join(pvcMountRoot, claim),
+		})
+	}
+	return volumes, mounts
+}
+
+// localPath returns the path of the file in the runner, s3 URIs are handled by the runner itself.
+func localPath(uri string) string {
+	claim, filePath, ok := parsePVCURI(uri)
+	if !ok {
+		return uri
+	}
+	return path.Join(pvcMountRoot, claim, filePath)
+}
+
+// parsePVCURI parses a URI of the form pvc://<claim>/<path>
+func parsePVCURI(uri string) (string, string, bool) {
+	if !strings.HasPrefix(uri, pvcURIPrefix) {
+		return "", "", false
+	}
+	claim, filePath, _ := strings.Cut(strings.TrimPrefix(uri, pvcURIPrefix), "/")
+	return claim, filePath, true
+}
+
+func stringOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -21,4 +21,10 @@ type Config struct {
 	Workers              int
 	Kubeconfig           string
 	MasterURL            string
+	// MetricsAddr is the address the metrics are served on, metrics are not served if empty.
+	MetricsAddr string
+	// AutoscalerDryRun makes the autoscaler record its scaling decisions without scaling the workloads.
+	AutoscalerDryRun bool
+	// EnableIstioIntegration registers the external models of the ModelRoutes in the Istio service mesh.
+	EnableIstioIntegration bool
 }
@@ -18,120 +18,122 @@ package controller
 
 import (
 	"context"
-	"os"
+	"fmt"
+	"strings"
 	"time"
 
 	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
 	autoscaler "github.com/volcano-sh/kthena/pkg/autoscaler/controller"
+	batchinference "github.com/volcano-sh/kthena/pkg/batch-inference-controller/controller"
+	"github.com/volcano-sh/kthena/pkg/controller/metrics"
+	istio "github.com/volcano-sh/kthena/pkg/istio-controller/controller"
 	modelbooster "github.com/volcano-sh/kthena/pkg/model-booster-controller/controller"
 	"github.com/volcano-sh/kthena/pkg/model-booster-controller/utils"
 	modelserving "github.com/volcano-sh/kthena/pkg/model-serving-controller/controller"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/uuid"
+	"github.com/volcano-sh/kthena/pkg/util/app"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/tools/leaderelection"
-	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/klog/v2"
 	volcanoClientSet "volcano.sh/apis/pkg/client/clientset/versioned"
 )
 
 const (
-	defaultLeaseDuration = 15 * time.Second
-	defaultRenewDeadline = 10 * time.Second
-	defaultRetryPeriod   = 2 * time.Second
-	leaderElectionId     = "kthena.controller-manager"
-	leaseName            = "lease.kthena.controller-manager"
+	leaderElectionId = "kthena.controller-manager"
+	leaseName        = "lease.kthena.controller-manager"
+
+	// stalledWorkqueueThreshold is how long an item may be processed before the controller is considered stuck.
+	stalledWorkqueueThreshold = 10 * time.Minute
 )
 
-func SetupController(ctx context.Context, cc Config) {
+// NewComponents creates the controllers, which only run in the leader replica when leader election is
+// enabled, and the metrics server.
+func NewComponents(cc Config) ([]app.Component, error) {
 	config, err := clientcmd.BuildConfigFromFlags(cc.MasterURL, cc.Kubeconfig)
 	if err != nil {
-		klog.Fatalf("build client config: %v", err)
+		return nil, fmt.Errorf("build client config: %v", err)
 	}
 	kubeClient := kubernetes.NewForConfigOrDie(config)
 	client := clientset.NewForConfigOrDie(config)
 	volcanoClient, err := volcanoClientSet.NewForConfig(config)
 	if err != nil {
-		klog.Fatalf("failed to create volcano client: %v", err)
+		return nil, fmt.Errorf("failed to create volcano client: %v", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %v", err)
 	}
 	mc := modelbooster.NewModelBoosterController(kubeClient, client)
-	msc, err := modelserving.NewModelServingController(kubeClient, client, volcanoClient)
+	lc := modelbooster.NewLoraAdapterController(kubeClient, client)
+	msc, err := modelserving.NewModelServingController(kubeClient, client, volcanoClient, dynamicClient)
 	if err != nil {
-		klog.Fatalf("failed to create ModelServing controller: %v", err)
+		return nil, fmt.Errorf("failed to create ModelServing controller: %v", err)
 	}
 	namespace, err := utils.GetInClusterNameSpace()
 	if err != nil {
-		klog.Fatalf("create Autoscaler client: %v", err)
+		return nil, fmt.Errorf("create Autoscaler client: %v", err)
+	}
+	ac := autoscaler.NewAutoscaleController(kubeClient, client, namespace, cc.AutoscalerDryRun)
+	bc := batchinference.NewBatchInferenceController(kubeClient, client, namespace)
+
+	controllers := []app.Component{
+		runUntilDone("ModelBooster controller", func(ctx context.Context) { mc.Run(ctx, cc.Workers) }),
+		runUntilDone("LoraAdapter controller", func(ctx context.Context) { lc.Run(ctx, cc.Workers) }),
+		runUntilDone("ModelServing controller", func(ctx context.Context) { msc.Run(ctx, cc.Workers) }),
+		runUntilDone("Autoscale controller", ac.Run),
+		runUntilDone("BatchInference controller", func(ctx context.Context) { bc.Run(ctx, cc.Workers) }),
+	}
+	if cc.EnableIstioIntegration {
+		ic := istio.NewIstioController(client, dynamicClient)
+		controllers = append(controllers, runUntilDone("Istio controller", func(ctx context.Context) { ic.Run(ctx, cc.Workers) }))
+	}
+
+	var components []app.Component
+	if cc.MetricsAddr != "" {
+		components = append(components, runUntilDone("metrics server", func(ctx context.Context) {
+			metrics.Serve(ctx, cc.MetricsAddr)
+		}))
 	}
-	ac := autoscaler.NewAutoscaleController(kubeClient, client, namespace)
 	if cc.EnableLeaderElection {
-		startedLeading := func(ctx context.Context) {
-			go mc.Run(ctx, cc.Workers)
-			go msc.Run(ctx, cc.Workers)
-			go ac.Run(ctx)
-			klog.Info("Start as leader")
-		}
-		leaderElector, err := initLeaderElector(kubeClient, startedLeading)
-		if err != nil {
-			panic(err)
-		}
-		leaderElector.Run(ctx)
+		metrics.SetLeader(leaderElectionId, false)
+		components = append(components, app.LeaderElected(app.LeaderElection{
+			Client:           kubeClient,
+			Name:             leaderElectionId,
+			Namespace:        namespace,
+			LeaseName:        leaseName,
+			OnStartedLeading: func() { metrics.SetLeader(leaderElectionId, true) },
+			OnStoppedLeading: func() { metrics.SetLeader(leaderElectionId, false) },
+		}, controllers...))
 	} else {
-		go mc.Run(ctx, cc.Workers)
-		go msc.Run(ctx, cc.Workers)
-		go ac.Run(ctx)
-		klog.Info("Started controller without leader election")
+		metrics.SetLeader(leaderElectionId, true)
+		klog.Info("Starting controllers without leader election")
+		components = append(components, controllers...)
 	}
-	<-ctx.Done()
+	return components, nil
 }
 
-// initLeaderElector inits a leader elector for leader election
-func initLeaderElector(kubeClient kubernetes.Interface, startedLeading func(ctx context.Context)) (*leaderelection.LeaderElector, error) {
-	resourceLock, err := newResourceLock(kubeClient)
-	if err != nil {
-		return nil, err
-	}
-	leaderElector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
-		Lock:          resourceLock,
-		LeaseDuration: defaultLeaseDuration,
-		RenewDeadline: defaultRenewDeadline,
-		RetryPeriod:   defaultRetryPeriod,
-		Callbacks: leaderelection.LeaderCallbacks{
-			OnStartedLeading: startedLeading,
-			OnStoppedLeading: func() {
-				klog.Error("leader election lost")
-			},
-		},
-		ReleaseOnCancel: false,
-		Name:            leaderElectionId,
+// runUntilDone returns a component running a function which returns once the context is done.
+func runUntilDone(name string, run func(ctx context.Context)) app.Component {
+	return app.NewComponent(name, func(ctx context.Context) error {
+		run(ctx)
+		return nil
 	})
-	if err != nil {
-		return nil, err
-	}
-	return leaderElector, nil
 }
 
-// newResourceLock returns a lease lock which is used to elect leader
-func newResourceLock(client kubernetes.Interface) (*resourcelock.LeaseLock, error) {
-	namespace, err := utils.GetInClusterNameSpace()
-	if err != nil {
-		return nil, err
+// Ready fails while the controllers are waiting for their informer caches to sync. The controllers of a replica
+// which is not the leader don't run, so that it is ready to take over.
+func Ready() error {
+	if controllers := metrics.UnsyncedControllers(); len(controllers) > 0 {
+		return fmt.Errorf("waiting for the caches of %s to sync", strings.Join(controllers, ", "))
 	}
-	// Leader id, should be unique
-	id, err := os.Hostname()
-	if err != nil {
-		return nil, err
+	return nil
+}
+
+// Live fails when a worker of a controller has been processing the same item for too long,
+// which usually means that it is stuck and the process should be restarted.
+func Live() error {
+	if queues := metrics.StalledWorkqueues(stalledWorkqueueThreshold); len(queues) > 0 {
+		return fmt.Errorf("workqueues %s have an item processed for more than %s", strings.Join(queues, ", "), stalledWorkqueueThreshold)
 	}
-	id = id + "_" + string(uuid.NewUUID())
-	return &resourcelock.LeaseLock{
-		LeaseMeta: metav1.ObjectMeta{
-			Name:      leaseName,
-			Namespace: namespace,
-		},
-		Client: client.CoordinationV1(),
-		LockConfig: resourcelock.ResourceLockConfig{
-			Identity: id,
-		},
-	}, nil
+	return nil
 }
@@ -0,0 +1,65 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	LabelNamespace = "namespace"
+	LabelBinding   = "binding"
+	LabelDryRun    = "dry_run"
+)
+
+// The autoscaler metrics report the last decision of each AutoscalingPolicyBinding, including the decisions
+// of the bindings in dry-run mode, whose targets are not scaled.
+var (
+	autoscalerCurrentReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kthena_autoscaler_current_replicas",
+		Help: "Replicas of the targets of an autoscaling policy binding at its last scaling decision",
+	}, []string{LabelNamespace, LabelBinding, LabelDryRun})
+	autoscalerDesiredReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kthena_autoscaler_desired_replicas",
+		Help: "Replicas the targets of an autoscaling policy binding are scaled to, or would be in dry-run mode",
+	}, []string{LabelNamespace, LabelBinding, LabelDryRun})
+)
+
+func registerAutoscalerMetrics(registry prometheus.Registerer) {
+	registry.MustRegister(
+		autoscalerCurrentReplicas,
+		autoscalerDesiredReplicas,
+	)
+}
+
+// SetAutoscalerReplicas records the last scaling decision of the binding.
+func SetAutoscalerReplicas(namespace, binding string, current, desired int32, dryRun bool) {
+	// The series of the other mode are removed when the binding switches mode
+	DeleteAutoscalerReplicas(namespace, binding)
+	labels := []string{namespace, binding, strconv.FormatBool(dryRun)}
+	autoscalerCurrentReplicas.WithLabelValues(labels...).Set(float64(current))
+	autoscalerDesiredReplicas.WithLabelValues(labels...).Set(float64(desired))
+}
+
+// DeleteAutoscalerReplicas removes the series of a binding which no longer exists.
+func DeleteAutoscalerReplicas(namespace, binding string) {
+	match := prometheus.Labels{LabelNamespace: namespace, LabelBinding: binding}
+	autoscalerCurrentReplicas.DeletePartialMatch(match)
+	autoscalerDesiredReplicas.DeletePartialMatch(match)
+}
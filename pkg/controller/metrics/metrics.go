@@ -0,0 +1,162 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics exposes the Prometheus metrics shared by the controllers of the controller manager:
+// workqueue, reconcile, informer cache sync and leader election metrics, and the decisions of the autoscaler.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// Label names
+	LabelController = "controller"
+	LabelResult     = "result"
+	LabelName       = "name"
+
+	// Reconcile result values
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+var (
+	// Registry holds the metrics of the controller manager.
+	Registry = prometheus.NewRegistry()
+
+	reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kthena_controller_reconcile_total",
+		Help: "Total number of reconciliations per controller and result",
+	}, []string{LabelController, LabelResult})
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kthena_controller_reconcile_errors_total",
+		Help: "Total number of reconciliation errors per controller",
+	}, []string{LabelController})
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kthena_controller_reconcile_duration_seconds",
+		Help:    "Duration of the reconciliations per controller",
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{LabelController})
+	informerSynced = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kthena_controller_informer_synced",
+		Help: "Whether the informer caches of a controller have synced (1) or not (0)",
+	}, []string{LabelController})
+	leaderElectionStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kthena_controller_leader_election_status",
+		Help: "Whether the controller manager is the leader (1) or not (0)",
+	}, []string{LabelName})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		reconcileTotal,
+		reconcileErrors,
+		reconcileDuration,
+		informerSynced,
+		leaderElectionStatus,
+	)
+	registerWorkqueueMetrics(Registry)
+	registerAutoscalerMetrics(Registry)
+}
+
+// ObserveReconcile records a reconciliation of the controller which started at start.
+func ObserveReconcile(controller string, start time.Time, err error) {
+	reconcileDuration.WithLabelValues(controller).Observe(time.Since(start).Seconds())
+	if err != nil {
+		reconcileTotal.WithLabelValues(controller, ResultError).Inc()
+		reconcileErrors.WithLabelValues(controller).Inc()
+		return
+	}
+	reconcileTotal.WithLabelValues(controller, ResultSuccess).Inc()
+}
+
+// WaitForCacheSync waits for the informer caches of the controller to sync like cache.WaitForCacheSync,
+// and reports whether they have synced.
+func WaitForCacheSync(controller string, stopCh <-chan struct{}, cacheSyncs ...cache.InformerSynced) bool {
+	informerSynced.WithLabelValues(controller).Set(0)
+	cacheSynced.Store(controller, false)
+	synced := cache.WaitForCacheSync(stopCh, cacheSyncs...)
+	if synced {
+		informerSynced.WithLabelValues(controller).Set(1)
+		cacheSynced.Store(controller, true)
+	} else {
+		// The controller is stopping
+		cacheSynced.Delete(controller)
+	}
+	return synced
+}
+
+// cacheSynced records whether the informer caches of the running controllers have synced, it backs the readiness check.
+var cacheSynced sync.Map
+
+// UnsyncedControllers returns the controllers waiting for their informer caches to sync, sorted by name.
+func UnsyncedControllers() []string {
+	var controllers []string
+	cacheSynced.Range(func(key, value any) bool {
+		if !value.(bool) {
+			controllers = append(controllers, key.(string))
+		}
+		return true
+	})
+	sort.Strings(controllers)
+	return controllers
+}
+
+// SetLeader reports whether the controller manager holds the leader election lock of the given name.
+func SetLeader(name string, leader bool) {
+	value := 0.0
+	if leader {
+		value = 1
+	}
+	leaderElectionStatus.WithLabelValues(name).Set(value)
+}
+
+// Serve serves the metrics on /metrics at addr until ctx is done.
+func Serve(ctx context.Context, addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("Failed to shut down metrics server: %v", err)
+		}
+	}()
+
+	klog.Infof("Starting metrics server on %s", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		klog.Errorf("Metrics server failed: %v", err)
+	}
+}
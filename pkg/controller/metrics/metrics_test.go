@@ -0,0 +1,114 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestObserveReconcile(t *testing.T) {
+	ObserveReconcile("test-reconcile", time.Now(), nil)
+	ObserveReconcile("test-reconcile", time.Now(), nil)
+	ObserveReconcile("test-reconcile", time.Now(), errors.New("failed"))
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(reconcileTotal.WithLabelValues("test-reconcile", ResultSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(reconcileTotal.WithLabelValues("test-reconcile", ResultError)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(reconcileErrors.WithLabelValues("test-reconcile")))
+	assert.Equal(t, 1, testutil.CollectAndCount(reconcileDuration, "kthena_controller_reconcile_duration_seconds"))
+}
+
+func TestWaitForCacheSync(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	assert.True(t, WaitForCacheSync("test-synced", stopCh, func() bool { return true }))
+	assert.Equal(t, 1.0, testutil.ToFloat64(informerSynced.WithLabelValues("test-synced")))
+	assert.Empty(t, UnsyncedControllers())
+
+	synced := make(chan struct{})
+	done := make(chan bool)
+	go func() {
+		done <- WaitForCacheSync("test-syncing", stopCh, func() bool {
+			select {
+			case <-synced:
+				return true
+			default:
+				return false
+			}
+		})
+	}()
+	assert.Eventually(t, func() bool { return len(UnsyncedControllers()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"test-syncing"}, UnsyncedControllers())
+	close(synced)
+	assert.True(t, <-done)
+	assert.Empty(t, UnsyncedControllers())
+
+	stopped := make(chan struct{})
+	close(stopped)
+	assert.False(t, WaitForCacheSync("test-not-synced", stopped, func() bool { return false }))
+	assert.Equal(t, 0.0, testutil.ToFloat64(informerSynced.WithLabelValues("test-not-synced")))
+}
+
+func TestSetLeader(t *testing.T) {
+	SetLeader("test-lock", true)
+	assert.Equal(t, 1.0, testutil.ToFloat64(leaderElectionStatus.WithLabelValues("test-lock")))
+	SetLeader("test-lock", false)
+	assert.Equal(t, 0.0, testutil.ToFloat64(leaderElectionStatus.WithLabelValues("test-lock")))
+}
+
+func TestAutoscalerReplicas(t *testing.T) {
+	SetAutoscalerReplicas("default", "test-binding", 2, 4, false)
+	assert.Equal(t, 2.0, testutil.ToFloat64(autoscalerCurrentReplicas.WithLabelValues("default", "test-binding", "false")))
+	assert.Equal(t, 4.0, testutil.ToFloat64(autoscalerDesiredReplicas.WithLabelValues("default", "test-binding", "false")))
+
+	SetAutoscalerReplicas("default", "test-binding", 2, 3, true)
+	assert.Equal(t, 1, testutil.CollectAndCount(autoscalerDesiredReplicas, "kthena_autoscaler_desired_replicas"))
+	assert.Equal(t, 3.0, testutil.ToFloat64(autoscalerDesiredReplicas.WithLabelValues("default", "test-binding", "true")))
+
+	DeleteAutoscalerReplicas("default", "test-binding")
+	assert.Equal(t, 0, testutil.CollectAndCount(autoscalerCurrentReplicas, "kthena_autoscaler_current_replicas"))
+}
+
+func TestWorkqueueMetrics(t *testing.T) {
+	queue := workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[string](),
+		workqueue.TypedRateLimitingQueueConfig[string]{Name: "test-queue"})
+	defer queue.ShutDown()
+
+	queue.Add("a")
+	queue.Add("b")
+	assert.Equal(t, 2.0, testutil.ToFloat64(workqueueAdds.WithLabelValues("test-queue")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(workqueueDepth.WithLabelValues("test-queue")))
+
+	item, _ := queue.Get()
+	queue.AddRateLimited(item)
+	queue.Done(item)
+	assert.Equal(t, 1.0, testutil.ToFloat64(workqueueRetries.WithLabelValues("test-queue")))
+}
+
+func TestStalledWorkqueues(t *testing.T) {
+	provider := workqueueMetricsProvider{}
+	provider.NewLongestRunningProcessorSecondsMetric("test-stuck").Set(600)
+	provider.NewLongestRunningProcessorSecondsMetric("test-busy").Set(1)
+	assert.Equal(t, []string{"test-stuck"}, StalledWorkqueues(5*time.Minute))
+	assert.Equal(t, 600.0, testutil.ToFloat64(workqueueLongestRunningProcessor.WithLabelValues("test-stuck")))
+}
@@ -0,0 +1,135 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// The workqueue metrics follow the names of the metrics of the Kubernetes controllers.
+var (
+	workqueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "workqueue_depth",
+		Help: "Current depth of workqueue",
+	}, []string{LabelName})
+	workqueueAdds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workqueue_adds_total",
+		Help: "Total number of adds handled by workqueue",
+	}, []string{LabelName})
+	workqueueLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "workqueue_queue_duration_seconds",
+		Help:    "How long in seconds an item stays in workqueue before being requested",
+		Buckets: prometheus.ExponentialBuckets(10e-9, 10, 12),
+	}, []string{LabelName})
+	workqueueWorkDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "workqueue_work_duration_seconds",
+		Help:    "How long in seconds processing an item from workqueue takes",
+		Buckets: prometheus.ExponentialBuckets(10e-9, 10, 12),
+	}, []string{LabelName})
+	workqueueUnfinishedWork = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "workqueue_unfinished_work_seconds",
+		Help: "How many seconds of work has been done that is in progress and hasn't been observed by work_duration. " +
+			"Large values indicate stuck threads.",
+	}, []string{LabelName})
+	workqueueLongestRunningProcessor = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "workqueue_longest_running_processor_seconds",
+		Help: "How many seconds has the longest running processor for workqueue been running",
+	}, []string{LabelName})
+	workqueueRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workqueue_retries_total",
+		Help: "Total number of retries handled by workqueue",
+	}, []string{LabelName})
+)
+
+func registerWorkqueueMetrics(registry prometheus.Registerer) {
+	registry.MustRegister(
+		workqueueDepth,
+		workqueueAdds,
+		workqueueLatency,
+		workqueueWorkDuration,
+		workqueueUnfinishedWork,
+		workqueueLongestRunningProcessor,
+		workqueueRetries,
+	)
+	// Only the queues created with a name are measured
+	workqueue.SetProvider(workqueueMetricsProvider{})
+}
+
+var _ workqueue.MetricsProvider = workqueueMetricsProvider{}
+
+type workqueueMetricsProvider struct{}
+
+func (workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return workqueueDepth.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return workqueueAdds.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return workqueueLatency.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return workqueueWorkDuration.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return workqueueUnfinishedWork.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return &longestRunningProcessor{name: name, gauge: workqueueLongestRunningProcessor.WithLabelValues(name)}
+}
+
+// longestRunningProcessors holds the seconds the longest running processor of each workqueue has been running,
+// which is updated periodically by the workqueues.
+var longestRunningProcessors sync.Map
+
+type longestRunningProcessor struct {
+	name  string
+	gauge prometheus.Gauge
+}
+
+func (p *longestRunningProcessor) Set(seconds float64) {
+	p.gauge.Set(seconds)
+	longestRunningProcessors.Store(p.name, seconds)
+}
+
+// StalledWorkqueues returns the workqueues with an item processed for longer than the threshold, sorted by name.
+// A stuck worker of a controller never finishes processing its item.
+func StalledWorkqueues(threshold time.Duration) []string {
+	var queues []string
+	longestRunningProcessors.Range(func(key, value any) bool {
+		if value.(float64) > threshold.Seconds() {
+			queues = append(queues, key.(string))
+		}
+		return true
+	})
+	sort.Strings(queues)
+	return queues
+}
+
+func (workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return workqueueRetries.WithLabelValues(name)
+}
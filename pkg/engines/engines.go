@@ -0,0 +1,314 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package engines describes the behaviors specific to each inference engine: its API, the metrics it
+// exposes and the flags of its command line. The router, the autoscaler and the ModelServing webhooks
+// look the engine up here instead of comparing engine names.
+package engines
+
+import (
+	"encoding/json"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// Names of the engine metrics once parsed by ParseMetrics.
+const (
+	GPUCacheUsage     = "gpu_usage"
+	RequestWaitingNum = "request_waiting_num"
+	RequestRunningNum = "request_running_num"
+	TPOT              = "TPOT"
+	TTFT              = "TTFT"
+)
+
+// gib is a gibibyte, in bytes.
+const gib int64 = 1 << 30
+
+// ModelLabel is the label of the served model in the metrics of the engines serving several models from a pod.
+const ModelLabel = "model_name"
+
+// InferenceEngine is implemented by each supported inference engine.
+type InferenceEngine interface {
+	// Name is the engine name, as set in the inferenceEngine of the ModelServers.
+	Name() string
+	// Port is the default port of the engine HTTP server.
+	Port() int32
+	// HealthPath is the path of the health endpoint.
+	HealthPath() string
+	// MetricsPath is the path of the Prometheus metrics, empty when the engine exposes none.
+	MetricsPath() string
+	// ParseMetrics converts the scraped metric families into the metrics named above. The histograms are
+	// returned as is, their values are the averages since previousHistogram.
+	ParseMetrics(allMetrics map[string]*dto.MetricFamily, previousHistogram map[string]*dto.Histogram) (map[string]float64, map[string]*dto.Histogram)
+	// ModelsPath is the path listing the served models in the OpenAI format, empty when it is not supported.
+	ModelsPath() string
+	// StreamUsage reports whether the engine honors stream_options.include_usage in streaming requests.
+	StreamUsage() bool
+	// HTTP2 reports whether the engine HTTP server accepts HTTP/2 without TLS, with prior knowledge.
+	HTTP2() bool
+	// WarmupRequest returns the path and the body of a minimal request warming up the model.
+	WarmupRequest(model string) (string, []byte)
+	// LoRAPaths returns the paths loading and unloading LoRA adapters at runtime, empty when it is not supported.
+	LoRAPaths() (load, unload string)
+	// Args returns the flags of the engine command line.
+	Args() *Args
+	// SharedMemory returns the size of /dev/shm, in bytes, the engine needs with the accelerators of its container.
+	// The default 64Mi of the container runtimes crashes the engines exchanging tensors between processes.
+	SharedMemory(accelerators int64) int64
+	// Matches reports whether the command line runs the engine.
+	Matches(cmdline []string) bool
+}
+
+// Args are the flags of an engine command line, each with its aliases. The first one is used to set it.
+// A flag the engine does not have is empty.
+type Args struct {
+	ServedModelName      []string
+	TensorParallelSize   []string
+	MaxModelLen          []string
+	GPUMemoryUtilization []string
+	// Config is the flag loading all the arguments from a file.
+	Config string
+}
+
+// registry lists the engines, in the order they are detected.
+var registry = []InferenceEngine{
+	&vllm{},
+	&sglang{},
+	&tgi{},
+	&trtllm{},
+}
+
+// Get returns the engine with the name, nil if it is unknown.
+func Get(name string) InferenceEngine {
+	for _, engine := range registry {
+		if engine.Name() == name {
+			return engine
+		}
+	}
+	return nil
+}
+
+// Detect returns the engine run by the command line, nil when it runs no known engine.
+func Detect(cmdline []string) InferenceEngine {
+	for _, engine := range registry {
+		if engine.Matches(cmdline) {
+			return engine
+		}
+	}
+	return nil
+}
+
+// HasFlag reports whether one of the flags is set, either as "--flag value" or "--flag=value".
+func HasFlag(cmdline []string, flags ...string) bool {
+	for _, arg := range cmdline {
+		for _, flag := range flags {
+			if flag != "" && (arg == flag || strings.HasPrefix(arg, flag+"=")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FlagValue finds the value of one of the flags, set as "--flag value" or "--flag=value". The last one wins.
+func FlagValue(cmdline []string, flags ...string) (value string, found bool) {
+	for i, arg := range cmdline {
+		for _, flag := range flags {
+			if flag == "" {
+				continue
+			}
+			if arg == flag && i+1 < len(cmdline) {
+				value, found = cmdline[i+1], true
+			} else if strings.HasPrefix(arg, flag+"=") {
+				value, found = strings.TrimPrefix(arg, flag+"="), true
+			}
+		}
+	}
+	return value, found
+}
+
+// ParseModels parses the model list returned by the ModelsPath of an engine.
+func ParseModels(body []byte) ([]string, error) {
+	var modelList struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &modelList); err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(modelList.Data))
+	for _, model := range modelList.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
+
+// parseMetrics converts the metric families named in gauges and histograms, which map the engine metric
+// names to the names above. The series of a family, e.g. one per served model, are added up: the requests
+// are summed, the other gauges take their highest value, and the histograms are merged.
+func parseMetrics(allMetrics map[string]*dto.MetricFamily, previousHistogram map[string]*dto.Histogram,
+	gauges, histograms map[string]string) (map[string]float64, map[string]*dto.Histogram) {
+	wantMetrics := make(map[string]float64)
+	histogramMetrics := make(map[string]*dto.Histogram)
+	for metricName, name := range gauges {
+		metricInfo, exist := allMetrics[metricName]
+		if !exist {
+			continue
+		}
+		for i, metric := range metricInfo.Metric {
+			value := metricValue(metricInfo.GetType(), metric)
+			switch {
+			case i == 0:
+				wantMetrics[name] = value
+			case name == RequestWaitingNum || name == RequestRunningNum:
+				wantMetrics[name] += value
+			default:
+				wantMetrics[name] = max(wantMetrics[name], value)
+			}
+		}
+	}
+	for metricName, name := range histograms {
+		metricInfo, exist := allMetrics[metricName]
+		if !exist || len(metricInfo.Metric) == 0 {
+			continue
+		}
+		metricValue := metricInfo.Metric[0].GetHistogram()
+		if len(metricInfo.Metric) > 1 {
+			metricValue = mergeHistograms(metricInfo.Metric)
+		}
+		histogramMetrics[name] = metricValue
+		previousMetric := previousHistogram[name]
+		if previousMetric == nil {
+			// Ignore the effects of history and give each pod a fair chance at the initial.
+			wantMetrics[name] = float64(0.0)
+		} else {
+			wantMetrics[name] = lastPeriodAvg(previousMetric, metricValue)
+		}
+	}
+	return wantMetrics, histogramMetrics
+}
+
+// mergeHistograms adds up the histograms. Their buckets are merged when they have the same bounds, which they have
+// when they come from the same engine, otherwise they are left out as only the averages are needed to schedule.
+func mergeHistograms(metrics []*dto.Metric) *dto.Histogram {
+	var count uint64
+	var sum float64
+	var buckets []*dto.Bucket
+	for i, metric := range metrics {
+		h := metric.GetHistogram()
+		count += h.GetSampleCount()
+		sum += h.GetSampleSum()
+		switch {
+		case i == 0:
+			for _, b := range h.GetBucket() {
+				buckets = append(buckets, &dto.Bucket{UpperBound: proto.Float64(b.GetUpperBound()), CumulativeCount: proto.Uint64(b.GetCumulativeCount())})
+			}
+		case len(h.GetBucket()) != len(buckets):
+			buckets = nil
+		default:
+			for j, b := range h.GetBucket() {
+				if b.GetUpperBound() != buckets[j].GetUpperBound() {
+					buckets = nil
+					break
+				}
+				*buckets[j].CumulativeCount += b.GetCumulativeCount()
+			}
+		}
+	}
+	return &dto.Histogram{SampleCount: &count, SampleSum: &sum, Bucket: buckets}
+}
+
+// SplitByModel splits the metric families by the value of their ModelLabel, the series without it are under "".
+// It returns nil when the metrics are not labeled by model.
+func SplitByModel(allMetrics map[string]*dto.MetricFamily) map[string]map[string]*dto.MetricFamily {
+	labeled := false
+	for _, family := range allMetrics {
+		for _, metric := range family.Metric {
+			labeled = labeled || modelOf(metric) != ""
+		}
+	}
+	if !labeled {
+		return nil
+	}
+
+	split := make(map[string]map[string]*dto.MetricFamily)
+	for name, family := range allMetrics {
+		for _, metric := range family.Metric {
+			model := modelOf(metric)
+			families := split[model]
+			if families == nil {
+				families = make(map[string]*dto.MetricFamily)
+				split[model] = families
+			}
+			modelFamily := families[name]
+			if modelFamily == nil {
+				modelFamily = &dto.MetricFamily{Name: family.Name, Help: family.Help, Type: family.Type}
+				families[name] = modelFamily
+			}
+			modelFamily.Metric = append(modelFamily.Metric, metric)
+		}
+	}
+	return split
+}
+
+// modelOf returns the value of the ModelLabel of the series, empty without it.
+func modelOf(metric *dto.Metric) string {
+	for _, label := range metric.Label {
+		if label.GetName() == ModelLabel {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
+// metricValue returns the value of a gauge, counter or untyped metric.
+func metricValue(metricType dto.MetricType, metric *dto.Metric) float64 {
+	switch metricType {
+	case dto.MetricType_COUNTER:
+		return metric.GetCounter().GetValue()
+	case dto.MetricType_UNTYPED:
+		return metric.GetUntyped().GetValue()
+	default:
+		return metric.GetGauge().GetValue()
+	}
+}
+
+func lastPeriodAvg(previous, current *dto.Histogram) float64 {
+	deltaSum := current.GetSampleSum() - previous.GetSampleSum()
+	deltaCount := current.GetSampleCount() - previous.GetSampleCount()
+	if deltaCount == 0 {
+		return 0
+	}
+	return deltaSum / float64(deltaCount)
+}
+
+// chatWarmupRequest is the warm-up request of the engines serving the OpenAI chat completions API.
+func chatWarmupRequest(model string) (string, []byte) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"model":      model,
+		"messages":   []map[string]string{{"role": "user", "content": "hi"}},
+		"max_tokens": 1,
+	})
+	return "/v1/chat/completions", body
+}
+
+// isCommand reports whether the argument runs the named executable.
+func isCommand(arg, name string) bool {
+	return arg == name || strings.HasSuffix(arg, "/"+name)
+}
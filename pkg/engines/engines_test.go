@@ -0,0 +1,181 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engines
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	for _, name := range []string{"vLLM", "SGLang", "TGI", "TensorRT-LLM"} {
+		engine := Get(name)
+		require.NotNil(t, engine, name)
+		assert.Equal(t, name, engine.Name())
+	}
+	assert.Nil(t, Get("unknown"))
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		cmdline []string
+		want    string
+	}{
+		{cmdline: []string{"vllm", "serve", "/models/llama"}, want: "vLLM"},
+		{cmdline: []string{"/usr/local/bin/vllm", "serve"}, want: "vLLM"},
+		{cmdline: []string{"python3", "-m", "vllm.entrypoints.openai.api_server"}, want: "vLLM"},
+		{cmdline: []string{"python3", "-m", "sglang.launch_server", "--model-path", "/models/llama"}, want: "SGLang"},
+		{cmdline: []string{"text-generation-launcher", "--model-id", "/models/llama"}, want: "TGI"},
+		{cmdline: []string{"trtllm-serve", "/models/llama"}, want: "TensorRT-LLM"},
+		{cmdline: []string{"vllm"}},
+		{cmdline: []string{"sh", "-c", "vllm serve /models/llama"}},
+	}
+	for _, tt := range tests {
+		engine := Detect(tt.cmdline)
+		if tt.want == "" {
+			assert.Nil(t, engine, tt.cmdline)
+			continue
+		}
+		require.NotNil(t, engine, tt.cmdline)
+		assert.Equal(t, tt.want, engine.Name())
+	}
+}
+
+func TestSharedMemory(t *testing.T) {
+	tests := []struct {
+		engine       string
+		accelerators int64
+		want         int64
+	}{
+		{engine: "vLLM", accelerators: 0, want: 2 * gib},
+		{engine: "vLLM", accelerators: 4, want: 8 * gib},
+		{engine: "SGLang", accelerators: 1, want: 8 * gib},
+		{engine: "SGLang", accelerators: 8, want: 32 * gib},
+		{engine: "TGI", accelerators: 2, want: 2 * gib},
+		{engine: "TensorRT-LLM", accelerators: 1, want: 2 * gib},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Get(tt.engine).SharedMemory(tt.accelerators), "%s with %d accelerators", tt.engine, tt.accelerators)
+	}
+}
+
+func TestFlags(t *testing.T) {
+	cmdline := []string{"--tp-size", "2", "--context-length=4096", "--tp", "4"}
+	assert.True(t, HasFlag(cmdline, "--context-length"))
+	assert.False(t, HasFlag(cmdline, "--context"))
+	assert.False(t, HasFlag(cmdline, ""))
+
+	value, found := FlagValue(cmdline, Get("SGLang").Args().TensorParallelSize...)
+	assert.True(t, found)
+	assert.Equal(t, "4", value)
+	value, found = FlagValue(cmdline, "--context-length")
+	assert.True(t, found)
+	assert.Equal(t, "4096", value)
+	_, found = FlagValue(cmdline, "--max-model-len")
+	assert.False(t, found)
+}
+
+func TestParseMetrics(t *testing.T) {
+	var parser expfmt.TextParser
+	allMetrics, err := parser.TextToMetricFamilies(strings.NewReader(`# TYPE sglang:token_usage gauge
+sglang:token_usage 0.5
+# TYPE sglang:num_queue_reqs gauge
+sglang:num_queue_reqs 3
+# TYPE sglang:num_running_reqs gauge
+sglang:num_running_reqs 7
+# TYPE sglang:time_to_first_token_seconds histogram
+sglang:time_to_first_token_seconds_bucket{le="+Inf"} 4
+sglang:time_to_first_token_seconds_sum 2
+sglang:time_to_first_token_seconds_count 4
+`))
+	require.NoError(t, err)
+
+	values, histograms := Get("SGLang").ParseMetrics(allMetrics, nil)
+	assert.Equal(t, map[string]float64{GPUCacheUsage: 0.5, RequestWaitingNum: 3, RequestRunningNum: 7, TTFT: 0}, values)
+	require.Contains(t, histograms, TTFT)
+
+	previous, err := parser.TextToMetricFamilies(strings.NewReader(`# TYPE sglang:time_to_first_token_seconds histogram
+sglang:time_to_first_token_seconds_bucket{le="+Inf"} 2
+sglang:time_to_first_token_seconds_sum 0.5
+sglang:time_to_first_token_seconds_count 2
+`))
+	require.NoError(t, err)
+	_, previousHistograms := Get("SGLang").ParseMetrics(previous, nil)
+	values, _ = Get("SGLang").ParseMetrics(allMetrics, previousHistograms)
+	assert.Equal(t, 0.75, values[TTFT])
+
+	// The metrics of other engines are ignored
+	values, histograms = Get("vLLM").ParseMetrics(allMetrics, nil)
+	assert.Empty(t, values)
+	assert.Empty(t, histograms)
+}
+
+func TestParseMetricsOfSeveralModels(t *testing.T) {
+	var parser expfmt.TextParser
+	allMetrics, err := parser.TextToMetricFamilies(strings.NewReader(`# TYPE vllm:gpu_cache_usage_perc gauge
+vllm:gpu_cache_usage_perc{model_name="llama"} 0.5
+vllm:gpu_cache_usage_perc{model_name="qwen"} 0.25
+# TYPE vllm:num_requests_waiting gauge
+vllm:num_requests_waiting{model_name="llama"} 3
+vllm:num_requests_waiting{model_name="qwen"} 1
+# TYPE vllm:num_requests_running gauge
+vllm:num_requests_running{model_name="llama"} 4
+vllm:num_requests_running{model_name="qwen"} 2
+# TYPE vllm:time_to_first_token_seconds histogram
+vllm:time_to_first_token_seconds_bucket{model_name="llama",le="+Inf"} 2
+vllm:time_to_first_token_seconds_sum{model_name="llama"} 1
+vllm:time_to_first_token_seconds_count{model_name="llama"} 2
+vllm:time_to_first_token_seconds_bucket{model_name="qwen",le="+Inf"} 6
+vllm:time_to_first_token_seconds_sum{model_name="qwen"} 2
+vllm:time_to_first_token_seconds_count{model_name="qwen"} 6
+`))
+	require.NoError(t, err)
+
+	// The requests of the models are added up, the other gauges take the highest value
+	values, histograms := Get("vLLM").ParseMetrics(allMetrics, nil)
+	assert.Equal(t, map[string]float64{GPUCacheUsage: 0.5, RequestWaitingNum: 4, RequestRunningNum: 6, TTFT: 0}, values)
+	assert.Equal(t, uint64(8), histograms[TTFT].GetSampleCount())
+	assert.Equal(t, float64(3), histograms[TTFT].GetSampleSum())
+	require.Len(t, histograms[TTFT].GetBucket(), 1)
+	assert.Equal(t, uint64(8), histograms[TTFT].GetBucket()[0].GetCumulativeCount())
+
+	split := SplitByModel(allMetrics)
+	require.Len(t, split, 2)
+	values, histograms = Get("vLLM").ParseMetrics(split["qwen"], nil)
+	assert.Equal(t, map[string]float64{GPUCacheUsage: 0.25, RequestWaitingNum: 1, RequestRunningNum: 2, TTFT: 0}, values)
+	assert.Equal(t, uint64(6), histograms[TTFT].GetSampleCount())
+
+	// The metrics without a model are not split
+	allMetrics, err = parser.TextToMetricFamilies(strings.NewReader(`# TYPE vllm:num_requests_waiting gauge
+vllm:num_requests_waiting 3
+`))
+	require.NoError(t, err)
+	assert.Nil(t, SplitByModel(allMetrics))
+}
+
+func TestParseModels(t *testing.T) {
+	models, err := ParseModels([]byte(`{"object":"list","data":[{"id":"llama"},{"id":"llama-lora"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"llama", "llama-lora"}, models)
+
+	_, err = ParseModels([]byte(`not json`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engines
+
+import (
+	dto "github.com/prometheus/client_model/go"
+
+	networking "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+// https://github.com/sgl-project/sglang
+type sglang struct{}
+
+var (
+	sglangGauges = map[string]string{
+		"sglang:token_usage":      GPUCacheUsage,
+		"sglang:num_queue_reqs":   RequestWaitingNum,
+		"sglang:num_running_reqs": RequestRunningNum,
+	}
+	sglangHistograms = map[string]string{
+		"sglang:time_per_output_token_seconds": TPOT,
+		"sglang:time_to_first_token_seconds":   TTFT,
+	}
+	sglangArgs = &Args{
+		ServedModelName:      []string{"--served-model-name"},
+		TensorParallelSize:   []string{"--tp-size", "--tensor-parallel-size", "--tp"},
+		MaxModelLen:          []string{"--context-length"},
+		GPUMemoryUtilization: []string{"--mem-fraction-static"},
+		Config:               "--config",
+	}
+)
+
+func (e *sglang) Name() string       { return string(networking.SGLang) }
+func (e *sglang) Port() int32        { return 30000 }
+func (e *sglang) HealthPath() string { return "/health" }
+
+// The metrics are exposed when the server is started with --enable-metrics.
+func (e *sglang) MetricsPath() string {
+	return "/metrics"
+}
+
+func (e *sglang) ParseMetrics(allMetrics map[string]*dto.MetricFamily, previousHistogram map[string]*dto.Histogram) (map[string]float64, map[string]*dto.Histogram) {
+	return parseMetrics(allMetrics, previousHistogram, sglangGauges, sglangHistograms)
+}
+
+func (e *sglang) ModelsPath() string { return "/v1/models" }
+func (e *sglang) StreamUsage() bool  { return true }
+func (e *sglang) HTTP2() bool        { return false }
+
+func (e *sglang) WarmupRequest(model string) (string, []byte) {
+	return chatWarmupRequest(model)
+}
+
+func (e *sglang) LoRAPaths() (string, string) {
+	return "/load_lora_adapter", "/unload_lora_adapter"
+}
+
+func (e *sglang) Args() *Args { return sglangArgs }
+
+// The scheduler, tokenizer and detokenizer processes of SGLang also exchange the requests through the shared memory.
+func (e *sglang) SharedMemory(accelerators int64) int64 {
+	return max(8, 4*accelerators) * gib
+}
+
+// Matches the server module.
+func (e *sglang) Matches(cmdline []string) bool {
+	for _, arg := range cmdline {
+		if arg == "sglang.launch_server" {
+			return true
+		}
+	}
+	return false
+}
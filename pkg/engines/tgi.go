@@ -0,0 +1,95 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engines
+
+import (
+	dto "github.com/prometheus/client_model/go"
+
+	networking "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+// https://github.com/huggingface/text-generation-inference
+type tgi struct{}
+
+var (
+	tgiGauges = map[string]string{
+		"tgi_queue_size":         RequestWaitingNum,
+		"tgi_batch_current_size": RequestRunningNum,
+	}
+	tgiHistograms = map[string]string{
+		"tgi_request_mean_time_per_token_duration": TPOT,
+	}
+	tgiArgs = &Args{
+		ServedModelName:      []string{"--served-model-name"},
+		TensorParallelSize:   []string{"--num-shard"},
+		MaxModelLen:          []string{"--max-total-tokens"},
+		GPUMemoryUtilization: []string{"--cuda-memory-fraction"},
+	}
+)
+
+func (e *tgi) Name() string { return string(networking.TGI) }
+
+// The launcher listens on 3000, the port of its container image is 80.
+func (e *tgi) Port() int32 {
+	return 80
+}
+
+func (e *tgi) HealthPath() string  { return "/health" }
+func (e *tgi) MetricsPath() string { return "/metrics" }
+
+// TGI reports neither the KV cache usage nor the time to first token.
+func (e *tgi) ParseMetrics(allMetrics map[string]*dto.MetricFamily, previousHistogram map[string]*dto.Histogram) (map[string]float64, map[string]*dto.Histogram) {
+	return parseMetrics(allMetrics, previousHistogram, tgiGauges, tgiHistograms)
+}
+
+func (e *tgi) ModelsPath() string { return "/v1/models" }
+
+// The usage is only returned in the last chunk of the streams of the recent versions, without stream_options.
+func (e *tgi) StreamUsage() bool {
+	return false
+}
+
+// The router of TGI is served by hyper, which detects HTTP/2 connections without TLS.
+func (e *tgi) HTTP2() bool {
+	return true
+}
+
+func (e *tgi) WarmupRequest(model string) (string, []byte) {
+	return chatWarmupRequest(model)
+}
+
+// The adapters are set with --lora-adapters when the server starts.
+func (e *tgi) LoRAPaths() (string, string) {
+	return "", ""
+}
+
+func (e *tgi) Args() *Args { return tgiArgs }
+
+// The shards of TGI only use the shared memory for NCCL.
+func (e *tgi) SharedMemory(accelerators int64) int64 {
+	return max(1, accelerators) * gib
+}
+
+// Matches the launcher, the container image runs it as its entrypoint and cannot be detected.
+func (e *tgi) Matches(cmdline []string) bool {
+	for _, arg := range cmdline {
+		if isCommand(arg, "text-generation-launcher") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engines
+
+import (
+	dto "github.com/prometheus/client_model/go"
+
+	networking "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+// https://github.com/NVIDIA/TensorRT-LLM
+type trtllm struct{}
+
+var trtllmArgs = &Args{
+	TensorParallelSize: []string{"--tp_size"},
+	MaxModelLen:        []string{"--max_seq_len"},
+}
+
+func (e *trtllm) Name() string       { return string(networking.TensorRTLLM) }
+func (e *trtllm) Port() int32        { return 8000 }
+func (e *trtllm) HealthPath() string { return "/health" }
+
+// trtllm-serve reports its iteration statistics in JSON, they are not scraped.
+func (e *trtllm) MetricsPath() string {
+	return ""
+}
+
+func (e *trtllm) ParseMetrics(map[string]*dto.MetricFamily, map[string]*dto.Histogram) (map[string]float64, map[string]*dto.Histogram) {
+	return map[string]float64{}, map[string]*dto.Histogram{}
+}
+
+func (e *trtllm) ModelsPath() string { return "/v1/models" }
+func (e *trtllm) StreamUsage() bool  { return true }
+func (e *trtllm) HTTP2() bool        { return false }
+
+func (e *trtllm) WarmupRequest(model string) (string, []byte) {
+	return chatWarmupRequest(model)
+}
+
+// The LoRA adapters are passed along with each request.
+func (e *trtllm) LoRAPaths() (string, string) {
+	return "", ""
+}
+
+func (e *trtllm) Args() *Args { return trtllmArgs }
+
+// The MPI ranks of TensorRT-LLM exchange tensors through the shared memory.
+func (e *trtllm) SharedMemory(accelerators int64) int64 {
+	return max(2, 2*accelerators) * gib
+}
+
+// Matches the OpenAI API server CLI.
+func (e *trtllm) Matches(cmdline []string) bool {
+	for _, arg := range cmdline {
+		if isCommand(arg, "trtllm-serve") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engines
+
+import (
+	dto "github.com/prometheus/client_model/go"
+
+	networking "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+// https://github.com/vllm-project/vllm
+type vllm struct{}
+
+var (
+	vllmGauges = map[string]string{
+		"vllm:gpu_cache_usage_perc": GPUCacheUsage,
+		"vllm:num_requests_waiting": RequestWaitingNum,
+		"vllm:num_requests_running": RequestRunningNum,
+	}
+	vllmHistograms = map[string]string{
+		"vllm:time_per_output_token_seconds": TPOT,
+		"vllm:time_to_first_token_seconds":   TTFT,
+	}
+	vllmArgs = &Args{
+		ServedModelName:      []string{"--served-model-name"},
+		TensorParallelSize:   []string{"--tensor-parallel-size", "-tp"},
+		MaxModelLen:          []string{"--max-model-len"},
+		GPUMemoryUtilization: []string{"--gpu-memory-utilization"},
+		Config:               "--config",
+	}
+)
+
+func (e *vllm) Name() string       { return string(networking.VLLM) }
+func (e *vllm) Port() int32        { return 8000 }
+func (e *vllm) HealthPath() string { return "/health" }
+func (e *vllm) MetricsPath() string {
+	return "/metrics"
+}
+
+func (e *vllm) ParseMetrics(allMetrics map[string]*dto.MetricFamily, previousHistogram map[string]*dto.Histogram) (map[string]float64, map[string]*dto.Histogram) {
+	return parseMetrics(allMetrics, previousHistogram, vllmGauges, vllmHistograms)
+}
+
+func (e *vllm) ModelsPath() string { return "/v1/models" }
+func (e *vllm) StreamUsage() bool  { return true }
+
+// The OpenAI server of vLLM runs on uvicorn, which only serves HTTP/1.1.
+func (e *vllm) HTTP2() bool { return false }
+
+func (e *vllm) WarmupRequest(model string) (string, []byte) {
+	return chatWarmupRequest(model)
+}
+
+// LoRA adapters are loaded at runtime when VLLM_ALLOW_RUNTIME_LORA_UPDATING is set.
+func (e *vllm) LoRAPaths() (string, string) {
+	return "/v1/load_lora_adapter", "/v1/unload_lora_adapter"
+}
+
+func (e *vllm) Args() *Args { return vllmArgs }
+
+// The workers of the tensor parallel ranks exchange tensors through the shared memory.
+func (e *vllm) SharedMemory(accelerators int64) int64 {
+	return max(2, 2*accelerators) * gib
+}
+
+// Matches the "vllm serve" CLI and the OpenAI API server module.
+func (e *vllm) Matches(cmdline []string) bool {
+	for i, arg := range cmdline {
+		if isCommand(arg, "vllm") && i+1 < len(cmdline) && cmdline[i+1] == "serve" {
+			return true
+		}
+		if arg == "vllm.entrypoints.openai.api_server" {
+			return true
+		}
+	}
+	return false
+}
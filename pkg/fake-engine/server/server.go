@@ -0,0 +1,354 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package server implements a fake inference engine serving the subset of the vLLM API used by kthena,
+// without any model. It lets the router and the autoscaler be tested and demoed without GPUs.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// The metrics exposed like vLLM does, which are scraped by the router and the autoscaler
+const (
+	NumRequestsRunning    = "vllm:num_requests_running"
+	NumRequestsWaiting    = "vllm:num_requests_waiting"
+	GPUCacheUsagePerc     = "vllm:gpu_cache_usage_perc"
+	RequestSuccessTotal   = "vllm:request_success_total"
+	TimeToFirstToken      = "vllm:time_to_first_token_seconds"
+	TimePerOutputToken    = "vllm:time_per_output_token_seconds"
+	PromptTokensTotal     = "vllm:prompt_tokens_total"
+	GenerationTokensTotal = "vllm:generation_tokens_total"
+
+	completionResponseID = "cmpl-fake"
+)
+
+// Config configures the behavior of the fake engine.
+type Config struct {
+	// Model is the name of the served model, requests to other models are rejected.
+	Model string
+	// TimeToFirstToken is the time spent before the first token of every answer.
+	TimeToFirstToken time.Duration
+	// TokenRate is the number of output tokens generated per second after the first one.
+	// Tokens are generated instantly when it is not positive.
+	TokenRate float64
+	// OutputTokens is the number of tokens of an answer, unless the request sets a lower max_tokens.
+	OutputTokens int
+	// MaxRunning is the number of requests served concurrently, the others wait like in the vLLM scheduler.
+	// The requests are not limited when it is not positive.
+	MaxRunning int
+}
+
+// Server mimics the OpenAI compatible API of vLLM. It answers every request with a generated completion
+// identifying the serving host, and the metrics it exposes can be set through the /fake/metrics endpoint.
+type Server struct {
+	config   Config
+	hostname string
+	slots    chan struct{}
+
+	registry *prometheus.Registry
+	gauges   map[string]prometheus.Gauge
+	// overridden holds the gauges set through /fake/metrics, which are no longer updated by the requests
+	overridden sync.Map
+	success    prometheus.Counter
+	prompt     prometheus.Counter
+	generation prometheus.Counter
+	ttft       prometheus.Histogram
+	tpot       prometheus.Histogram
+}
+
+// NewServer creates a fake engine serving the model of the config.
+func NewServer(config Config) *Server {
+	hostname, _ := os.Hostname()
+	s := &Server{
+		config:   config,
+		hostname: hostname,
+		registry: prometheus.NewRegistry(),
+		gauges:   make(map[string]prometheus.Gauge),
+		success: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: RequestSuccessTotal,
+			Help: "Count of successfully processed requests.",
+		}),
+		prompt: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: PromptTokensTotal,
+			Help: "Number of prefill tokens processed.",
+		}),
+		generation: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: GenerationTokensTotal,
+			Help: "Number of generation tokens processed.",
+		}),
+		ttft: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    TimeToFirstToken,
+			Help:    "Histogram of time to first token in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		tpot: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    TimePerOutputToken,
+			Help:    "Histogram of time per output token in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}),
+	}
+	if config.MaxRunning > 0 {
+		s.slots = make(chan struct{}, config.MaxRunning)
+	}
+	for _, name := range []string{NumRequestsRunning, NumRequestsWaiting, GPUCacheUsagePerc} {
+		s.gauges[name] = prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: "Fake " + name})
+		s.registry.MustRegister(s.gauges[name])
+	}
+	s.registry.MustRegister(s.success, s.prompt, s.generation, s.ttft, s.tpot)
+	return s
+}
+
+// Handler returns the handler of the API, health and metrics endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("GET /v1/models", s.listModels)
+	mux.HandleFunc("POST /v1/chat/completions", s.complete)
+	mux.HandleFunc("POST /v1/completions", s.complete)
+	mux.Handle("GET /metrics", promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("POST /fake/metrics", s.setMetrics)
+	return mux
+}
+
+func (s *Server) listModels(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"object": "list",
+		"data": []map[string]interface{}{
+			{"id": s.config.Model, "object": "model", "owned_by": "fake-engine"},
+		},
+	})
+}
+
+type completionRequest struct {
+	Model     string          `json:"model"`
+	Stream    bool            `json:"stream"`
+	MaxTokens int             `json:"max_tokens"`
+	Prompt    json.RawMessage `json:"prompt"`
+	Messages  json.RawMessage `json:"messages"`
+}
+
+func (s *Server) complete(w http.ResponseWriter, r *http.Request) {
+	var req completionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if req.Model != s.config.Model {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("model %q does not exist", req.Model)})
+		return
+	}
+
+	if !s.acquire(r) {
+		return
+	}
+	defer s.release()
+
+	chat := strings.HasSuffix(r.URL.Path, "/chat/completions")
+	tokens := s.tokens(req.MaxTokens)
+	// A token is roughly 4 characters of the prompt, like for English text.
+	promptTokens := (len(req.Prompt) + len(req.Messages) + 3) / 4
+	usage := map[string]int{"prompt_tokens": promptTokens, "completion_tokens": len(tokens), "total_tokens": promptTokens + len(tokens)}
+	s.prompt.Add(float64(promptTokens))
+
+	var flusher http.Flusher
+	if req.Stream {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher, _ = w.(http.Flusher)
+	}
+	start := time.Now()
+	if !sleep(r, s.config.TimeToFirstToken) {
+		return
+	}
+	s.ttft.Observe(time.Since(start).Seconds())
+
+	var answer strings.Builder
+	for i, token := range tokens {
+		if i > 0 {
+			tokenStart := time.Now()
+			if !sleep(r, s.tokenInterval()) {
+				return
+			}
+			s.tpot.Observe(time.Since(tokenStart).Seconds())
+		}
+		s.generation.Inc()
+		if !req.Stream {
+			answer.WriteString(token)
+			continue
+		}
+		c := choice(chat, true, token)
+		if i < len(tokens)-1 {
+			c["finish_reason"] = nil
+		}
+		s.writeChunk(w, chat, map[string]interface{}{"choices": []interface{}{c}})
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	s.success.Inc()
+
+	if req.Stream {
+		s.writeChunk(w, chat, map[string]interface{}{"choices": []interface{}{}, "usage": usage})
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":      completionResponseID,
+		"object":  objectType(chat, false),
+		"created": time.Now().Unix(),
+		"model":   s.config.Model,
+		"choices": []interface{}{choice(chat, false, answer.String())},
+		"usage":   usage,
+	})
+}
+
+// acquire waits for a slot to serve the request, it returns false when the request is canceled meanwhile.
+func (s *Server) acquire(r *http.Request) bool {
+	if s.slots != nil {
+		s.track(NumRequestsWaiting, 1)
+		defer s.track(NumRequestsWaiting, -1)
+		select {
+		case s.slots <- struct{}{}:
+		case <-r.Context().Done():
+			return false
+		}
+	}
+	s.track(NumRequestsRunning, 1)
+	return true
+}
+
+func (s *Server) release() {
+	s.track(NumRequestsRunning, -1)
+	if s.slots != nil {
+		<-s.slots
+	}
+}
+
+// tokens splits the answer into tokens, which identifies the host so that the scenarios can check where
+// the request was routed. The answer is repeated or truncated to the expected number of tokens.
+func (s *Server) tokens(maxTokens int) []string {
+	count := s.config.OutputTokens
+	if maxTokens > 0 && maxTokens < count {
+		count = maxTokens
+	}
+	words := strings.Fields(fmt.Sprintf("This is a fake answer of %s served by %s.", s.config.Model, s.hostname))
+	tokens := make([]string, count)
+	for i := range tokens {
+		tokens[i] = words[i%len(words)]
+		if i > 0 {
+			tokens[i] = " " + tokens[i]
+		}
+	}
+	return tokens
+}
+
+func (s *Server) tokenInterval() time.Duration {
+	if s.config.TokenRate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / s.config.TokenRate)
+}
+
+func (s *Server) writeChunk(w http.ResponseWriter, chat bool, chunk map[string]interface{}) {
+	chunk["id"] = completionResponseID
+	chunk["object"] = objectType(chat, true)
+	chunk["model"] = s.config.Model
+	data, _ := json.Marshal(chunk)
+	fmt.Fprintf(w, "data: %s\n\n", data)
+}
+
+// track updates a gauge from the requests, unless it has been set through /fake/metrics.
+func (s *Server) track(name string, delta float64) {
+	if _, ok := s.overridden.Load(name); !ok {
+		s.gauges[name].Add(delta)
+	}
+}
+
+// setMetrics sets the gauges given as a JSON object of metric names to values.
+func (s *Server) setMetrics(w http.ResponseWriter, r *http.Request) {
+	values := map[string]float64{}
+	if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	for name := range values {
+		if _, ok := s.gauges[name]; !ok {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("metric %q can not be set", name)})
+			return
+		}
+	}
+	for name, value := range values {
+		s.overridden.Store(name, struct{}{})
+		s.gauges[name].Set(value)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sleep waits for the duration, it returns false when the request is canceled meanwhile.
+func sleep(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return r.Context().Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+func objectType(chat, stream bool) string {
+	switch {
+	case chat && stream:
+		return "chat.completion.chunk"
+	case chat:
+		return "chat.completion"
+	default:
+		return "text_completion"
+	}
+}
+
+func choice(chat, stream bool, text string) map[string]interface{} {
+	c := map[string]interface{}{"index": 0, "finish_reason": "stop"}
+	switch {
+	case !chat:
+		c["text"] = text
+	case stream:
+		c["delta"] = map[string]string{"role": "assistant", "content": text}
+	default:
+		c["message"] = map[string]string{"role": "assistant", "content": text}
+	}
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func post(t *testing.T, ts *httptest.Server, path, body string) (*http.Response, string) {
+	resp, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(data)
+}
+
+func TestChatCompletion(t *testing.T) {
+	s := NewServer(Config{Model: "fake", OutputTokens: 4})
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	resp, body := post(t, ts, "/v1/chat/completions", `{"model": "fake", "messages": [{"role": "user", "content": "hi"}]}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var completion struct {
+		Object  string `json:"object"`
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &completion))
+	assert.Equal(t, "chat.completion", completion.Object)
+	require.Len(t, completion.Choices, 1)
+	assert.Equal(t, "This is a fake", completion.Choices[0].Message.Content)
+	assert.Equal(t, 4, completion.Usage.CompletionTokens)
+	assert.Equal(t, float64(1), testutil.ToFloat64(s.success))
+	assert.Equal(t, float64(4), testutil.ToFloat64(s.generation))
+
+	resp, _ = post(t, ts, "/v1/completions", `{"model": "fake", "prompt": "hi", "max_tokens": 2}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, float64(6), testutil.ToFloat64(s.generation))
+
+	resp, _ = post(t, ts, "/v1/chat/completions", `{"model": "other"}`)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestStreamingCompletion(t *testing.T) {
+	s := NewServer(Config{Model: "fake", OutputTokens: 3, TokenRate: 1000})
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	resp, body := post(t, ts, "/v1/completions", `{"model": "fake", "prompt": "hi", "stream": true}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	var events []string
+	for _, line := range strings.Split(body, "\n") {
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			events = append(events, data)
+		}
+	}
+	// One chunk per token, the usage chunk and the end of the stream.
+	require.Len(t, events, 5)
+	assert.Equal(t, "[DONE]", events[4])
+	var chunk struct {
+		Choices []struct {
+			Text         string  `json:"text"`
+			FinishReason *string `json:"finish_reason"`
+		} `json:"choices"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(events[0]), &chunk))
+	assert.Equal(t, "This", chunk.Choices[0].Text)
+	assert.Nil(t, chunk.Choices[0].FinishReason)
+	require.NoError(t, json.Unmarshal([]byte(events[2]), &chunk))
+	assert.Equal(t, " a", chunk.Choices[0].Text)
+	require.NotNil(t, chunk.Choices[0].FinishReason)
+	assert.Equal(t, "stop", *chunk.Choices[0].FinishReason)
+}
+
+func TestMaxRunningRequests(t *testing.T) {
+	s := NewServer(Config{Model: "fake", OutputTokens: 1, TimeToFirstToken: 200 * time.Millisecond, MaxRunning: 1})
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	done := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		go func() {
+			if resp, err := http.Post(ts.URL+"/v1/completions", "application/json", strings.NewReader(`{"model": "fake", "prompt": "hi"}`)); err == nil {
+				resp.Body.Close()
+			}
+			done <- struct{}{}
+		}()
+	}
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(s.gauges[NumRequestsRunning]) == 1 && testutil.ToFloat64(s.gauges[NumRequestsWaiting]) == 1
+	}, time.Second, 10*time.Millisecond)
+	<-done
+	<-done
+	assert.Equal(t, float64(0), testutil.ToFloat64(s.gauges[NumRequestsRunning]))
+	assert.Equal(t, float64(0), testutil.ToFloat64(s.gauges[NumRequestsWaiting]))
+}
+
+func TestSetMetrics(t *testing.T) {
+	s := NewServer(Config{Model: "fake", OutputTokens: 1})
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	resp, _ := post(t, ts, "/fake/metrics", `{"vllm:num_requests_waiting": 15}`)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	// The overridden gauge is no longer updated by the requests.
+	post(t, ts, "/v1/completions", `{"model": "fake", "prompt": "hi"}`)
+
+	metrics, err := http.Get(ts.URL + "/metrics")
+	require.NoError(t, err)
+	defer metrics.Body.Close()
+	data, err := io.ReadAll(metrics.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "vllm:num_requests_waiting 15")
+	assert.Contains(t, string(data), "vllm:request_success_total 1")
+
+	resp, _ = post(t, ts, "/fake/metrics", `{"vllm:request_success_total": 1}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package features lists the gated features of kthena, shared by all its binaries.
+package features
+
+import (
+	"github.com/volcano-sh/kthena/pkg/util/featuregate"
+)
+
+const (
+	// InPlacePodUpdate patches the pods of the outdated ServingGroups during the rolling updates of a ModelServing,
+	// when only their labels, annotations or container images changed, instead of recreating the ServingGroups.
+	InPlacePodUpdate featuregate.Feature = "InPlacePodUpdate"
+
+	// PDDisaggregation routes the requests of the ModelServers with a pdGroup to a prefill and a decode pod.
+	// When it is disabled, the router rejects these requests and its webhook the ModelServers with a pdGroup.
+	PDDisaggregation featuregate.Feature = "PDDisaggregation"
+
+	// PredictiveAutoscaling pre-scales the targets of the AutoscalingPolicies with a predictive policy ahead of
+	// the forecast traffic peaks. When it is disabled, the predictive policy is ignored.
+	PredictiveAutoscaling featuregate.Feature = "PredictiveAutoscaling"
+
+	// ResponseCache serves the deterministic completions from the response cache of the router, configured by
+	// the RESPONSE_CACHE_* environment variables. The cache only serves the exact matches of a request of the
+	// same consumer, similar prompts are not matched semantically.
+	ResponseCache featuregate.Feature = "ResponseCache"
+
+	// ServingGroupFitCheck checks that the pods of the ServingGroups fit on the nodes of the cluster. The ServingGroups
+	// which are not running and do not fit are reported by the Unschedulable condition of their ModelServing, their
+	// pods are created all the same so that the cluster autoscaler may add the nodes they fit on.
+	ServingGroupFitCheck featuregate.Feature = "ServingGroupFitCheck"
+)
+
+// DefaultMutableFeatureGate is the feature gate of the binary, set by the --feature-gates flag and the component config.
+var DefaultMutableFeatureGate = featuregate.NewFeatureGate()
+
+// DefaultFeatureGate is the read-only view of DefaultMutableFeatureGate, checked by the code of the features.
+var DefaultFeatureGate featuregate.FeatureGate = DefaultMutableFeatureGate
+
+var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	InPlacePodUpdate:      {Default: true, PreRelease: featuregate.Beta},
+	PDDisaggregation:      {Default: true, PreRelease: featuregate.Beta},
+	PredictiveAutoscaling: {Default: false, PreRelease: featuregate.Alpha},
+	ResponseCache:         {Default: false, PreRelease: featuregate.Alpha},
+	ServingGroupFitCheck:  {Default: true, PreRelease: featuregate.Beta},
+}
+
+func init() {
+	if err := DefaultMutableFeatureGate.Add(defaultFeatureGates); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
+	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
+	networkingLister "github.com/volcano-sh/kthena/client-go/listers/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/controller/metrics"
+)
+
+const (
+	istioControllerName = "istio"
+	fieldManager        = "kthena-controller-manager"
+)
+
+// IstioController registers the external models of the ModelRoutes in the Istio service mesh, with a ServiceEntry
+// and DestinationRules, so that the requests the router sends to them follow the policies of the mesh.
+type IstioController struct {
+	dynamicClient dynamic.Interface
+
+	syncHandler           func(ctx context.Context, key string) error
+	modelRouteLister      networkingLister.ModelRouteLister
+	modelRouteInformer    cache.SharedIndexInformer
+	kthenaInformerFactory informersv1alpha1.SharedInformerFactory
+	workQueue             workqueue.TypedRateLimitingInterface[any]
+}
+
+func NewIstioController(client clientset.Interface, dynamicClient dynamic.Interface) *IstioController {
+	kthenaInformerFactory := informersv1alpha1.NewSharedInformerFactory(client, 0)
+	modelRouteInformer := kthenaInformerFactory.Networking().V1alpha1().ModelRoutes()
+
+	c := &IstioController{
+		dynamicClient:         dynamicClient,
+		modelRouteLister:      modelRouteInformer.Lister(),
+		modelRouteInformer:    modelRouteInformer.Informer(),
+		kthenaInformerFactory: kthenaInformerFactory,
+		workQueue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[any](),
+			workqueue.TypedRateLimitingQueueConfig[any]{Name: istioControllerName}),
+	}
+	c.syncHandler = c.reconcile
+
+	_, err := c.modelRouteInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueModelRoute,
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueModelRoute(newObj)
+		},
+	})
+	if err != nil {
+		klog.Fatal("Unable to add ModelRoute event handler")
+		return nil
+	}
+	return c
+}
+
+func (c *IstioController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.workQueue.ShutDown()
+
+	c.kthenaInformerFactory.Start(ctx.Done())
+
+	metrics.WaitForCacheSync(istioControllerName, ctx.Done(), c.modelRouteInformer.HasSynced)
+
+	klog.Info("start istio controller")
+	for i := 0; i < workers; i++ {
+		go c.worker(ctx)
+	}
+	<-ctx.Done()
+	klog.Info("shut down istio controller")
+}
+
+func (c *IstioController) worker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *IstioController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := c.workQueue.Get()
+	if quit {
+		return false
+	}
+	defer c.workQueue.Done(key)
+
+	start := time.Now()
+	err := c.syncHandler(ctx, key.(string))
+	metrics.ObserveReconcile(istioControllerName, start, err)
+	if err == nil {
+		c.workQueue.Forget(key)
+		return true
+	}
+	utilruntime.HandleError(fmt.Errorf("sync %q failed with %v", key, err))
+	c.workQueue.AddRateLimited(key)
+	return true
+}
+
+func (c *IstioController) enqueueModelRoute(obj interface{}) {
+	if key, err := cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+	} else {
+		c.workQueue.Add(key)
+	}
+}
+
+// reconcile makes the Istio resources of a ModelRoute match its external models. The resources of a deleted
+// ModelRoute are garbage collected through their owner reference.
+func (c *IstioController) reconcile(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return fmt.Errorf("invalid resource key: %s", err)
+	}
+	mr, err := c.modelRouteLister.ModelRoutes(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	desired, err := desiredResources(mr)
+	if err != nil {
+		return err
+	}
+	if err := c.apply(ctx, mr.Namespace, mr.Name, serviceEntryGVR, serviceEntryKind, desired); err != nil {
+		return fmt.Errorf("failed to apply the ServiceEntries of ModelRoute %s: %v", key, err)
+	}
+	if err := c.apply(ctx, mr.Namespace, mr.Name, destinationRuleGVR, destinationRuleKind, desired); err != nil {
+		return fmt.Errorf("failed to apply the DestinationRules of ModelRoute %s: %v", key, err)
+	}
+	return nil
+}
+
+// apply creates or updates the desired resources of the kind, and deletes the other resources of the ModelRoute.
+func (c *IstioController) apply(ctx context.Context, namespace, modelRoute string, gvr schema.GroupVersionResource, kind string, desired []*unstructured.Unstructured) error {
+	client := c.dynamicClient.Resource(gvr).Namespace(namespace)
+	list, err := client.List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{ModelRouteLabelKey: modelRoute}).String(),
+	})
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*unstructured.Unstructured, len(list.Items))
+	for i := range list.Items {
+		existing[list.Items[i].GetName()] = &list.Items[i]
+	}
+
+	for _, obj := range desired {
+		if obj.GetKind() != kind {
+			continue
+		}
+		current, ok := existing[obj.GetName()]
+		delete(existing, obj.GetName())
+		if !ok {
+			if _, err := client.Create(ctx, obj, metav1.CreateOptions{FieldManager: fieldManager}); err != nil && !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+			klog.V(2).Infof("Created %s %s/%s", kind, namespace, obj.GetName())
+			continue
+		}
+		if equality.Semantic.DeepEqual(current.Object["spec"], obj.Object["spec"]) {
+			continue
+		}
+		updated := current.DeepCopy()
+		updated.Object["spec"] = obj.Object["spec"]
+		if _, err := client.Update(ctx, updated, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
+			return err
+		}
+		klog.V(2).Infof("Updated %s %s/%s", kind, namespace, obj.GetName())
+	}
+
+	for name := range existing {
+		if err := client.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		klog.V(2).Infof("Deleted %s %s/%s", kind, namespace, name)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	networking "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+func newFallbackModelRoute(urls ...string) *networking.ModelRoute {
+	mr := &networking.ModelRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "llama", UID: "uid"},
+		Spec: networking.ModelRouteSpec{
+			ModelName: "llama",
+			Fallback:  &networking.FallbackChain{Targets: []networking.FallbackTarget{{ModelName: "llama-8b"}}},
+		},
+	}
+	for _, url := range urls {
+		mr.Spec.Fallback.Targets = append(mr.Spec.Fallback.Targets, networking.FallbackTarget{
+			External: &networking.ExternalModel{URL: url},
+		})
+	}
+	return mr
+}
+
+func TestDesiredResources(t *testing.T) {
+	mr := newFallbackModelRoute("https://api.openai.com", "http://llm.example.com:8000/v1", "https://API.openai.com/v1", "http://10.0.0.1")
+
+	resources, err := desiredResources(mr)
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	serviceEntry := resources[0]
+	assert.Equal(t, "ServiceEntry", serviceEntry.GetKind())
+	assert.Equal(t, "llama-external", serviceEntry.GetName())
+	assert.Equal(t, "llama", serviceEntry.GetLabels()[ModelRouteLabelKey])
+	require.Len(t, serviceEntry.GetOwnerReferences(), 1)
+	assert.Equal(t, "llama", serviceEntry.GetOwnerReferences()[0].Name)
+	var seSpec serviceEntrySpec
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(serviceEntry.Object["spec"].(map[string]interface{}), &seSpec))
+	assert.Equal(t, serviceEntrySpec{
+		Hosts: []string{"api.openai.com", "llm.example.com"},
+		Ports: []serviceEntryPort{
+			{Number: 443, Name: "https-443", Protocol: "HTTPS"},
+			{Number: 8000, Name: "http-8000", Protocol: "HTTP"},
+		},
+		Location:   "MESH_EXTERNAL",
+		Resolution: "DNS",
+		ExportTo:   []string{"."},
+	}, seSpec)
+
+	assert.Equal(t, "DestinationRule", resources[1].GetKind())
+	assert.Equal(t, "llama-api.openai.com", resources[1].GetName())
+	var drSpec destinationRuleSpec
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(resources[1].Object["spec"].(map[string]interface{}), &drSpec))
+	assert.Equal(t, "api.openai.com", drSpec.Host)
+	assert.Equal(t, "DISABLE", drSpec.TrafficPolicy.TLS.Mode)
+	assert.Equal(t, int64(outlierConsecutiveErrors), drSpec.TrafficPolicy.OutlierDetection.Consecutive5xxErrors)
+	assert.Equal(t, "llama-llm.example.com", resources[2].GetName())
+
+	resources, err = desiredResources(newFallbackModelRoute())
+	require.NoError(t, err)
+	assert.Empty(t, resources)
+}
+
+func TestIstioControllerReconcile(t *testing.T) {
+	ctx := context.Background()
+	mr := newFallbackModelRoute("https://api.openai.com", "https://api.anthropic.com")
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		serviceEntryGVR:    "ServiceEntryList",
+		destinationRuleGVR: "DestinationRuleList",
+	})
+	kthenaClient := kthenafake.NewSimpleClientset(mr)
+	c := NewIstioController(kthenaClient, dynamicClient)
+	stop := make(chan struct{})
+	defer close(stop)
+	c.kthenaInformerFactory.Start(stop)
+	require.True(t, cache.WaitForCacheSync(stop, c.modelRouteInformer.HasSynced))
+
+	list := func(gvr schema.GroupVersionResource) []unstructured.Unstructured {
+		l, err := dynamicClient.Resource(gvr).Namespace("default").List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		return l.Items
+	}
+
+	require.NoError(t, c.reconcile(ctx, "default/llama"))
+	assert.Len(t, list(serviceEntryGVR), 1)
+	assert.Len(t, list(destinationRuleGVR), 2)
+
+	// Reconciling again changes nothing
+	dynamicClient.ClearActions()
+	require.NoError(t, c.reconcile(ctx, "default/llama"))
+	for _, action := range dynamicClient.Actions() {
+		assert.Equal(t, "list", action.GetVerb())
+	}
+
+	// The external model removed from the chain is removed from the mesh
+	updated := newFallbackModelRoute("https://api.openai.com")
+	require.NoError(t, c.modelRouteInformer.GetStore().Update(updated))
+	require.NoError(t, c.reconcile(ctx, "default/llama"))
+	destinationRules := list(destinationRuleGVR)
+	require.Len(t, destinationRules, 1)
+	assert.Equal(t, "llama-api.openai.com", destinationRules[0].GetName())
+	serviceEntries := list(serviceEntryGVR)
+	require.Len(t, serviceEntries, 1)
+	hosts, _, _ := unstructured.NestedStringSlice(serviceEntries[0].Object, "spec", "hosts")
+	assert.Equal(t, []string{"api.openai.com"}, hosts)
+
+	// Without external models, all the resources are deleted
+	require.NoError(t, c.modelRouteInformer.GetStore().Update(newFallbackModelRoute()))
+	require.NoError(t, c.reconcile(ctx, "default/llama"))
+	assert.Empty(t, list(serviceEntryGVR))
+	assert.Empty(t, list(destinationRuleGVR))
+}
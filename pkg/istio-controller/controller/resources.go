@@ -0,0 +1,229 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	networking "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+// ModelRouteLabelKey is the label of the Istio resources generated for the external models of a ModelRoute.
+const ModelRouteLabelKey = "networking.serving.volcano.sh/modelroute"
+
+const (
+	serviceEntryKind    = "ServiceEntry"
+	destinationRuleKind = "DestinationRule"
+)
+
+var (
+	serviceEntryGVR = schema.GroupVersionResource{
+		Group:    "networking.istio.io",
+		Version:  "v1",
+		Resource: "serviceentries",
+	}
+	destinationRuleGVR = schema.GroupVersionResource{
+		Group:    "networking.istio.io",
+		Version:  "v1",
+		Resource: "destinationrules",
+	}
+)
+
+// The outlier detection of the external APIs, which ejects an address of an API failing repeatedly.
+const (
+	outlierConsecutiveErrors = 5
+	outlierInterval          = "10s"
+	outlierBaseEjectionTime  = "30s"
+)
+
+// serviceEntrySpec mirrors the spec of the Istio ServiceEntry API.
+type serviceEntrySpec struct {
+	Hosts      []string           `json:"hosts"`
+	Ports      []serviceEntryPort `json:"ports"`
+	Location   string             `json:"location"`
+	Resolution string             `json:"resolution"`
+	ExportTo   []string           `json:"exportTo,omitempty"`
+}
+
+type serviceEntryPort struct {
+	Number   int64  `json:"number"`
+	Name     string `json:"name"`
+	Protocol string `json:"protocol"`
+}
+
+// destinationRuleSpec mirrors the spec of the Istio DestinationRule API.
+type destinationRuleSpec struct {
+	Host          string         `json:"host"`
+	ExportTo      []string       `json:"exportTo,omitempty"`
+	TrafficPolicy *trafficPolicy `json:"trafficPolicy,omitempty"`
+}
+
+type trafficPolicy struct {
+	TLS              *clientTLSSettings `json:"tls,omitempty"`
+	OutlierDetection *outlierDetection  `json:"outlierDetection,omitempty"`
+}
+
+type clientTLSSettings struct {
+	Mode string `json:"mode"`
+}
+
+type outlierDetection struct {
+	Consecutive5xxErrors int64  `json:"consecutive5xxErrors"`
+	Interval             string `json:"interval"`
+	BaseEjectionTime     string `json:"baseEjectionTime"`
+	MaxEjectionPercent   int64  `json:"maxEjectionPercent"`
+}
+
+// externalEndpoint is the host and port of an external API.
+type externalEndpoint struct {
+	host     string
+	port     int64
+	protocol string
+}
+
+// externalEndpoints returns the endpoints of the external models of the fallback chain of the ModelRoute, sorted.
+// The APIs addressed by an IP are left out, they are not resolved by DNS.
+func externalEndpoints(mr *networking.ModelRoute) []externalEndpoint {
+	if mr.Spec.Fallback == nil {
+		return nil
+	}
+	seen := make(map[externalEndpoint]bool)
+	var endpoints []externalEndpoint
+	for _, target := range mr.Spec.Fallback.Targets {
+		if target.External == nil {
+			continue
+		}
+		u, err := url.Parse(target.External.URL)
+		if err != nil || u.Hostname() == "" {
+			klog.Warningf("ModelRoute %s/%s has an invalid external URL %q", mr.Namespace, mr.Name, target.External.URL)
+			continue
+		}
+		if net.ParseIP(u.Hostname()) != nil {
+			continue
+		}
+		endpoint := externalEndpoint{host: strings.ToLower(u.Hostname()), port: 443, protocol: "HTTPS"}
+		if u.Scheme == "http" {
+			endpoint.port, endpoint.protocol = 80, "HTTP"
+		}
+		if port, err := strconv.ParseInt(u.Port(), 10, 32); err == nil {
+			endpoint.port = port
+		}
+		if !seen[endpoint] {
+			seen[endpoint] = true
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].host != endpoints[j].host {
+			return endpoints[i].host < endpoints[j].host
+		}
+		return endpoints[i].port < endpoints[j].port
+	})
+	return endpoints
+}
+
+// desiredResources returns the ServiceEntry registering the external APIs of the ModelRoute in the mesh, and a
+// DestinationRule for each of them. The router originates the TLS of the HTTPS APIs, and the APIs don't terminate
+// the mTLS of the mesh, so the DestinationRules disable it. The resources are only visible in the namespace of
+// the ModelRoute, whose router proxies the requests.
+func desiredResources(mr *networking.ModelRoute) ([]*unstructured.Unstructured, error) {
+	endpoints := externalEndpoints(mr)
+	if len(endpoints) == 0 {
+		return nil, nil
+	}
+
+	seSpec := serviceEntrySpec{
+		Location:   "MESH_EXTERNAL",
+		Resolution: "DNS",
+		ExportTo:   []string{"."},
+	}
+	hosts := make(map[string]bool)
+	ports := make(map[int64]bool)
+	for _, endpoint := range endpoints {
+		if !hosts[endpoint.host] {
+			hosts[endpoint.host] = true
+			seSpec.Hosts = append(seSpec.Hosts, endpoint.host)
+		}
+		if !ports[endpoint.port] {
+			ports[endpoint.port] = true
+			seSpec.Ports = append(seSpec.Ports, serviceEntryPort{
+				Number:   endpoint.port,
+				Name:     fmt.Sprintf("%s-%d", strings.ToLower(endpoint.protocol), endpoint.port),
+				Protocol: endpoint.protocol,
+			})
+		}
+	}
+	serviceEntry, err := newResource(mr, serviceEntryGVR, serviceEntryKind, mr.Name+"-external", &seSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := []*unstructured.Unstructured{serviceEntry}
+	for _, host := range seSpec.Hosts {
+		drSpec := destinationRuleSpec{
+			Host:     host,
+			ExportTo: []string{"."},
+			TrafficPolicy: &trafficPolicy{
+				TLS: &clientTLSSettings{Mode: "DISABLE"},
+				OutlierDetection: &outlierDetection{
+					Consecutive5xxErrors: outlierConsecutiveErrors,
+					Interval:             outlierInterval,
+					BaseEjectionTime:     outlierBaseEjectionTime,
+					MaxEjectionPercent:   100,
+				},
+			},
+		}
+		destinationRule, err := newResource(mr, destinationRuleGVR, destinationRuleKind, mr.Name+"-"+host, &drSpec)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, destinationRule)
+	}
+	return resources, nil
+}
+
+// newResource returns an Istio resource of the ModelRoute, which is garbage collected with it.
+func newResource(mr *networking.ModelRoute, gvr schema.GroupVersionResource, kind, name string, spec interface{}) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(gvr.GroupVersion().String())
+	obj.SetKind(kind)
+	obj.SetNamespace(mr.Namespace)
+	obj.SetName(name)
+	obj.SetLabels(map[string]string{ModelRouteLabelKey: mr.Name})
+	obj.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(mr, networking.SchemeGroupVersion.WithKind(networking.ModelRouteKind)),
+	})
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert spec of %s %s: %v", kind, name, err)
+	}
+	if err := unstructured.SetNestedMap(obj.Object, content, "spec"); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
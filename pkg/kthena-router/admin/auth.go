@@ -0,0 +1,55 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+)
+
+// TokenAuth authenticates the admin requests with the bearer token stored in tokenFile. The file is read
+// on every request, so that the token mounted from a Secret can be rotated without restarting the router.
+// All requests are rejected if the token can not be read or is empty.
+func TokenAuth(tokenFile string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			klog.Errorf("Failed to read the admin token: %v", err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "admin token is not configured"})
+			return
+		}
+		token = bytes.TrimSpace(token)
+		if len(token) == 0 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "admin token is not configured"})
+			return
+		}
+
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(provided)), token) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="kthena-router-admin"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid admin token"})
+			return
+		}
+		c.Next()
+	}
+}
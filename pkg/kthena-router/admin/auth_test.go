@@ -0,0 +1,65 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	engine := gin.New()
+	engine.Use(TokenAuth(tokenFile))
+	engine.GET("/admin/health", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	get := func(authorization string) int {
+		req := httptest.NewRequest(http.MethodGet, "/admin/health", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Everything is rejected without a token
+	assert.Equal(t, http.StatusUnauthorized, get("Bearer secret"))
+	require.NoError(t, os.WriteFile(tokenFile, []byte("\n"), 0o600))
+	assert.Equal(t, http.StatusUnauthorized, get("Bearer "))
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+	assert.Equal(t, http.StatusOK, get("Bearer secret"))
+	assert.Equal(t, http.StatusUnauthorized, get(""))
+	assert.Equal(t, http.StatusUnauthorized, get("Bearer other"))
+	assert.Equal(t, http.StatusUnauthorized, get("Basic secret"))
+
+	// The token is rotated without restarting
+	require.NoError(t, os.WriteFile(tokenFile, []byte("rotated"), 0o600))
+	assert.Equal(t, http.StatusUnauthorized, get("Bearer secret"))
+	assert.Equal(t, http.StatusOK, get("Bearer rotated"))
+}
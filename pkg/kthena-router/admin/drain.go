@@ -0,0 +1,60 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/drain"
+)
+
+// DrainHandler provides the endpoints draining a router replica, e.g. before a node maintenance
+type DrainHandler struct {
+	drainer *drain.Drainer
+}
+
+// NewDrainHandler creates a new drain handler
+func NewDrainHandler(drainer *drain.Drainer) *DrainHandler {
+	return &DrainHandler{
+		drainer: drainer,
+	}
+}
+
+// GetStatus handles GET /admin/drain
+func (h *DrainHandler) GetStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.drainer.Status())
+}
+
+// Drain handles POST /admin/drain
+func (h *DrainHandler) Drain(c *gin.Context) {
+	if h.drainer.Start() {
+		klog.Info("Router is draining, requested through the admin API")
+	}
+	c.JSON(http.StatusOK, h.drainer.Status())
+}
+
+// Cancel handles DELETE /admin/drain
+func (h *DrainHandler) Cancel(c *gin.Context) {
+	if h.drainer.Draining() {
+		klog.Info("Router draining canceled through the admin API")
+	}
+	h.drainer.Cancel()
+	c.JSON(http.StatusOK, h.drainer.Status())
+}
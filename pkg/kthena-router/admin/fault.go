@@ -0,0 +1,77 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/fault"
+)
+
+// FaultHandler provides the endpoints reading and setting the fault injection rules of the router
+type FaultHandler struct {
+	injector *fault.Injector
+}
+
+// NewFaultHandler creates a new fault handler
+func NewFaultHandler(injector *fault.Injector) *FaultHandler {
+	return &FaultHandler{
+		injector: injector,
+	}
+}
+
+type faultRules struct {
+	Rules []fault.Rule `json:"rules"`
+}
+
+// GetRules handles GET /admin/faults
+func (h *FaultHandler) GetRules(c *gin.Context) {
+	h.respond(c)
+}
+
+// SetRules handles PUT /admin/faults, the rules replace the current ones
+func (h *FaultHandler) SetRules(c *gin.Context) {
+	var rules faultRules
+	if err := c.ShouldBindJSON(&rules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": `the request body must be {"rules": [...]}: ` + err.Error()})
+		return
+	}
+	if err := h.injector.SetRules(rules.Rules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	klog.Infof("%d fault injection rules set, requested through the admin API", len(rules.Rules))
+	h.respond(c)
+}
+
+// DeleteRules handles DELETE /admin/faults
+func (h *FaultHandler) DeleteRules(c *gin.Context) {
+	_ = h.injector.SetRules(nil)
+	klog.Info("Fault injection rules removed, requested through the admin API")
+	h.respond(c)
+}
+
+func (h *FaultHandler) respond(c *gin.Context) {
+	rules := h.injector.Rules()
+	if rules == nil {
+		rules = []fault.Rule{}
+	}
+	c.JSON(http.StatusOK, faultRules{Rules: rules})
+}
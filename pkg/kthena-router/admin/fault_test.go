@@ -0,0 +1,62 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/fault"
+)
+
+func TestFaultHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	injector := fault.NewInjector()
+	handler := NewFaultHandler(injector)
+	engine := gin.New()
+	engine.GET("/admin/faults", handler.GetRules)
+	engine.PUT("/admin/faults", handler.SetRules)
+	engine.DELETE("/admin/faults", handler.DeleteRules)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/faults", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"rules": []}`, w.Body.String())
+
+	rules := `{"rules": [{"model": "llama", "abort": {"percentage": 10, "statusCode": 503}}]}`
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/faults", strings.NewReader(rules)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, rules, w.Body.String())
+	assert.Len(t, injector.Rules(), 1)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/faults", strings.NewReader(`{"rules": [{"model": "llama"}]}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Len(t, injector.Rules(), 1, "invalid rules are not applied")
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/faults", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"rules": []}`, w.Body.String())
+	assert.Empty(t, injector.Rules())
+}
@@ -0,0 +1,86 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/drain"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/tokenization"
+)
+
+// HealthHandler reports the health-check states tracked by the router
+type HealthHandler struct {
+	ready   func() bool
+	drainer *drain.Drainer
+	tracker *tokenization.HealthTracker
+}
+
+// NewHealthHandler creates a new health handler, ready reports whether the router has synced
+func NewHealthHandler(ready func() bool, drainer *drain.Drainer, tracker *tokenization.HealthTracker) *HealthHandler {
+	return &HealthHandler{
+		ready:   ready,
+		drainer: drainer,
+		tracker: tracker,
+	}
+}
+
+// HealthResponse is the health of the router and of the ModelServers it routes to.
+type HealthResponse struct {
+	Ready      bool              `json:"ready"`
+	Drain      drain.Status      `json:"drain"`
+	Tokenizers []TokenizerHealth `json:"tokenizers"`
+}
+
+// TokenizerHealth is the tokenization state of a ModelServer, which degrades KV-cache aware scoring.
+type TokenizerHealth struct {
+	ModelServer string    `json:"modelServer"`
+	Degraded    bool      `json:"degraded"`
+	Reason      string    `json:"reason"`
+	Message     string    `json:"message,omitempty"`
+	Since       time.Time `json:"since"`
+}
+
+// GetHealth handles GET /admin/health
+func (h *HealthHandler) GetHealth(c *gin.Context) {
+	response := HealthResponse{
+		Ready:      h.ready(),
+		Drain:      h.drainer.Status(),
+		Tokenizers: []TokenizerHealth{},
+	}
+	for _, ms := range h.tracker.List() {
+		health, ok := h.tracker.Get(ms)
+		if !ok {
+			continue
+		}
+		response.Tokenizers = append(response.Tokenizers, TokenizerHealth{
+			ModelServer: ms.String(),
+			Degraded:    health.Degraded,
+			Reason:      health.Reason,
+			Message:     health.Message,
+			Since:       health.Since,
+		})
+	}
+	sort.Slice(response.Tokenizers, func(i, j int) bool {
+		return response.Tokenizers[i].ModelServer < response.Tokenizers[j].ModelServer
+	})
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"flag"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+)
+
+// LoggingHandler provides the endpoints reading and changing the log verbosity of the router at runtime
+type LoggingHandler struct {
+	verbosity flag.Value
+}
+
+// NewLoggingHandler creates a new logging handler, verbosity is the value of the klog -v flag
+func NewLoggingHandler(verbosity flag.Value) *LoggingHandler {
+	return &LoggingHandler{
+		verbosity: verbosity,
+	}
+}
+
+type logLevel struct {
+	Verbosity *int `json:"verbosity"`
+}
+
+// GetLevel handles GET /admin/logging
+func (h *LoggingHandler) GetLevel(c *gin.Context) {
+	h.respond(c)
+}
+
+// SetLevel handles PUT /admin/logging
+func (h *LoggingHandler) SetLevel(c *gin.Context) {
+	var level logLevel
+	if err := c.ShouldBindJSON(&level); err != nil || level.Verbosity == nil || *level.Verbosity < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": `the request body must be {"verbosity": <non-negative integer>}`})
+		return
+	}
+	if err := h.verbosity.Set(strconv.Itoa(*level.Verbosity)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	klog.Infof("Log verbosity set to %d, requested through the admin API", *level.Verbosity)
+	h.respond(c)
+}
+
+func (h *LoggingHandler) respond(c *gin.Context) {
+	verbosity, err := strconv.Atoi(h.verbosity.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"verbosity": verbosity})
+}
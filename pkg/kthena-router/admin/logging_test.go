@@ -0,0 +1,53 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggingHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Int("v", 2, "log verbosity")
+	handler := NewLoggingHandler(flags.Lookup("v").Value)
+	engine := gin.New()
+	engine.GET("/admin/logging", handler.GetLevel)
+	engine.PUT("/admin/logging", handler.SetLevel)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/logging", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"verbosity": 2}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/logging", strings.NewReader(`{"verbosity": 4}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"verbosity": 4}`, w.Body.String())
+	assert.Equal(t, "4", flags.Lookup("v").Value.String())
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/logging", strings.NewReader(`{"verbosity": -1}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volcano-sh/kthena/pkg/util/diagnostics"
+)
+
+// ProfileHandler provides the endpoints capturing bundles of the CPU, heap and goroutine profiles of the router
+type ProfileHandler struct {
+	capturer *diagnostics.Capturer
+}
+
+// NewProfileHandler creates a new profile handler
+func NewProfileHandler(capturer *diagnostics.Capturer) *ProfileHandler {
+	return &ProfileHandler{
+		capturer: capturer,
+	}
+}
+
+// ListProfiles handles GET /admin/profiles
+func (h *ProfileHandler) ListProfiles(c *gin.Context) {
+	bundles, err := h.capturer.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, bundles)
+}
+
+// CaptureProfile handles POST /admin/profiles?seconds=30&upload_url=<url>, the request returns once the
+// bundle is captured.
+func (h *ProfileHandler) CaptureProfile(c *gin.Context) {
+	duration, err := diagnostics.ParseSeconds(c.Query("seconds"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	bundle, err := h.capturer.Capture(c.Request.Context(), duration, c.Query("upload_url"))
+	if err != nil {
+		c.JSON(diagnostics.StatusOf(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, bundle)
+}
+
+// GetProfile handles GET /admin/profiles/:name
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	name := c.Param("name")
+	path, err := h.capturer.Path(name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("bundle %s not found", name)})
+		return
+	}
+	c.FileAttachment(path, name)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcano-sh/kthena/pkg/util/diagnostics"
+)
+
+func TestProfileHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	handler := NewProfileHandler(diagnostics.NewCapturer("kthena-router", dir))
+	engine := gin.New()
+	engine.GET("/admin/profiles", handler.ListProfiles)
+	engine.POST("/admin/profiles", handler.CaptureProfile)
+	engine.GET("/admin/profiles/:name", handler.GetProfile)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/profiles", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/profiles?seconds=-1", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/profiles?seconds=600", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/profiles?seconds=1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var bundle diagnostics.Bundle
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bundle))
+	assert.Equal(t, filepath.Join(dir, bundle.Name), bundle.Location)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/profiles/"+bundle.Name, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	data, err := os.ReadFile(bundle.Location)
+	require.NoError(t, err)
+	assert.Equal(t, data, w.Body.Bytes())
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/profiles/missing.tar.gz", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
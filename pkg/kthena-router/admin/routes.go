@@ -0,0 +1,85 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/registration"
+)
+
+// RouteHandler provides the endpoints registering ModelRoutes in the router before they are synced from the API server
+type RouteHandler struct {
+	registry *registration.Registry
+}
+
+// NewRouteHandler creates a new route handler
+func NewRouteHandler(registry *registration.Registry) *RouteHandler {
+	return &RouteHandler{
+		registry: registry,
+	}
+}
+
+// ListRoutes handles GET /admin/routes
+func (h *RouteHandler) ListRoutes(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"routes": h.registry.List()})
+}
+
+// RegisterRoute handles PUT /admin/routes/namespaces/{namespace}/modelroutes/{name}?ttl={duration}, the body is
+// the ModelRoute. The route is served until the ModelRoute is synced from the API server, or its ttl expires.
+func (h *RouteHandler) RegisterRoute(c *gin.Context) {
+	ttl := registration.DefaultTTL
+	if value := c.Query("ttl"); value != "" {
+		var err error
+		if ttl, err = time.ParseDuration(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ttl: " + err.Error()})
+			return
+		}
+	}
+	var route networkingv1alpha1.ModelRoute
+	if err := c.ShouldBindJSON(&route); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "the request body must be a ModelRoute: " + err.Error()})
+		return
+	}
+	route.Namespace = c.Param("namespace")
+	route.Name = c.Param("name")
+
+	registered, err := h.registry.Register(&route, ttl)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, registration.ErrConflict) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, registered)
+}
+
+// UnregisterRoute handles DELETE /admin/routes/namespaces/{namespace}/modelroutes/{name}
+func (h *RouteHandler) UnregisterRoute(c *gin.Context) {
+	if !h.registry.Unregister(c.Param("namespace"), c.Param("name")) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "the ModelRoute is not registered through the admin API"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"istio.io/istio/pkg/util/sets"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/registration"
+)
+
+func TestRouteHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := datastore.New()
+	ms := &networkingv1alpha1.ModelServer{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "llama"}}
+	require.NoError(t, store.AddOrUpdateModelServer(ms, sets.New[types.NamespacedName]()))
+	handler := NewRouteHandler(registration.New(store))
+	engine := gin.New()
+	engine.GET("/admin/routes", handler.ListRoutes)
+	engine.PUT("/admin/routes/namespaces/:namespace/modelroutes/:name", handler.RegisterRoute)
+	engine.DELETE("/admin/routes/namespaces/:namespace/modelroutes/:name", handler.UnregisterRoute)
+
+	route := `{"spec": {"modelName": "llama-3", "rules": [{"name": "default", "targetModels": [{"modelServerName": "llama"}]}]}}`
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/routes/namespaces/default/modelroutes/llama?ttl=30s", strings.NewReader(route)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"modelName":"llama-3"`)
+	assert.NotNil(t, store.GetModelRoute("default/llama"))
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/routes/namespaces/default/modelroutes/other", strings.NewReader(route)))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/routes/namespaces/default/modelroutes/llama?ttl=soon", strings.NewReader(route)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/routes", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"llama"`)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/routes/namespaces/default/modelroutes/llama", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Nil(t, store.GetModelRoute("default/llama"))
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/routes/namespaces/default/modelroutes/llama", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler"
+)
+
+// SchedulerHandler provides the endpoints reporting the effective scheduling configuration and toggling its plugins
+type SchedulerHandler struct {
+	// scheduler returns the current scheduler, which is replaced when the router configuration is reloaded
+	scheduler func() scheduler.Scheduler
+}
+
+// NewSchedulerHandler creates a new scheduler handler
+func NewSchedulerHandler(s func() scheduler.Scheduler) *SchedulerHandler {
+	return &SchedulerHandler{
+		scheduler: s,
+	}
+}
+
+type pluginToggle struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// GetConfig handles GET /admin/scheduler
+func (h *SchedulerHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, h.scheduler().Config())
+}
+
+// SetPluginEnabled handles PUT /admin/scheduler/plugins/{name}
+func (h *SchedulerHandler) SetPluginEnabled(c *gin.Context) {
+	name := c.Param("name")
+	var toggle pluginToggle
+	if err := c.ShouldBindJSON(&toggle); err != nil || toggle.Enabled == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": `the request body must be {"enabled": true|false}`})
+		return
+	}
+	if err := h.scheduler().SetPluginEnabled(name, *toggle.Enabled); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	klog.Infof("Scheduler plugin %s enabled=%t, requested through the admin API", name, *toggle.Enabled)
+	c.JSON(http.StatusOK, h.scheduler().Config())
+}
@@ -0,0 +1,92 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+type fakeScheduler struct {
+	config scheduler.Config
+}
+
+func (f *fakeScheduler) Schedule(ctx *framework.Context, pods []*datastore.PodInfo) error {
+	return nil
+}
+
+func (f *fakeScheduler) RunPostHooks(ctx *framework.Context, index int) {}
+
+func (f *fakeScheduler) ObserveResponse(ctx *framework.Context, index int, response framework.Response) {
+}
+
+func (f *fakeScheduler) Config() scheduler.Config {
+	return f.config
+}
+
+func (f *fakeScheduler) SetPluginEnabled(name string, enabled bool) error {
+	for i := range f.config.ScorePlugins {
+		if f.config.ScorePlugins[i].Name == name {
+			f.config.ScorePlugins[i].Enabled = enabled
+			return nil
+		}
+	}
+	return fmt.Errorf("plugin %q is not configured", name)
+}
+
+func TestSchedulerHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &fakeScheduler{config: scheduler.Config{
+		ScorePlugins: []scheduler.ScorePluginConfig{{Name: "least-request", Enabled: true, Weight: 1, Timeout: "100ms"}},
+	}}
+	handler := NewSchedulerHandler(func() scheduler.Scheduler { return s })
+	engine := gin.New()
+	engine.GET("/admin/scheduler", handler.GetConfig)
+	engine.PUT("/admin/scheduler/plugins/:name", handler.SetPluginEnabled)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/scheduler", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var config scheduler.Config
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &config))
+	assert.Equal(t, s.config, config)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/scheduler/plugins/least-request", strings.NewReader(`{"enabled": false}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, s.config.ScorePlugins[0].Enabled)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/scheduler/plugins/least-request", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/scheduler/plugins/unknown", strings.NewReader(`{"enabled": true}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/snapshot"
+)
+
+// SnapshotHandler provides the ModelRoute snapshot and rollback endpoints for the router
+type SnapshotHandler struct {
+	snapshots *snapshot.Manager
+}
+
+// NewSnapshotHandler creates a new snapshot handler
+func NewSnapshotHandler(snapshots *snapshot.Manager) *SnapshotHandler {
+	return &SnapshotHandler{
+		snapshots: snapshots,
+	}
+}
+
+// ListSnapshots handles GET /admin/snapshots/namespaces/{namespace}/modelroutes/{name}
+func (h *SnapshotHandler) ListSnapshots(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	snapshots, err := h.snapshots.List(c.Request.Context(), namespace, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"snapshots": snapshots})
+}
+
+// GetSnapshot handles GET /admin/snapshots/namespaces/{namespace}/modelroutes/{name}/versions/{version}
+func (h *SnapshotHandler) GetSnapshot(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	version, err := strconv.ParseInt(c.Param("version"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid snapshot version"})
+		return
+	}
+
+	s, err := h.snapshots.Get(c.Request.Context(), namespace, name, version)
+	if err != nil {
+		c.JSON(statusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+// Rollback handles POST /admin/snapshots/namespaces/{namespace}/modelroutes/{name}/rollback?to={version}
+func (h *SnapshotHandler) Rollback(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	version, err := strconv.ParseInt(c.Query("to"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "the snapshot version to roll back to must be specified with the `to` query parameter"})
+		return
+	}
+
+	mr, err := h.snapshots.Rollback(c.Request.Context(), namespace, name, version)
+	if err != nil {
+		c.JSON(statusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mr)
+}
+
+func statusCode(err error) int {
+	switch {
+	case apierrors.IsNotFound(err):
+		return http.StatusNotFound
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/snapshot"
+)
+
+func TestSnapshotHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	mr := &networkingv1alpha1.ModelRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "default", Generation: 1},
+		Spec:       networkingv1alpha1.ModelRouteSpec{ModelName: "llama"},
+	}
+	manager := snapshot.NewManager(kubefake.NewSimpleClientset(), kthenafake.NewSimpleClientset(mr), 0)
+	require.NoError(t, manager.Record(ctx, mr))
+	updated := mr.DeepCopy()
+	updated.Generation = 2
+	updated.Spec.ModelName = "llama-v2"
+	require.NoError(t, manager.Record(ctx, updated))
+
+	handler := NewSnapshotHandler(manager)
+	engine := gin.New()
+	engine.GET("/admin/snapshots/namespaces/:namespace/modelroutes/:name", handler.ListSnapshots)
+	engine.GET("/admin/snapshots/namespaces/:namespace/modelroutes/:name/versions/:version", handler.GetSnapshot)
+	engine.POST("/admin/snapshots/namespaces/:namespace/modelroutes/:name/rollback", handler.Rollback)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/snapshots/namespaces/default/modelroutes/route", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Snapshots []snapshot.Snapshot `json:"snapshots"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Snapshots, 2)
+	assert.Equal(t, "llama-v2", list.Snapshots[1].Spec.ModelName)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/snapshots/namespaces/default/modelroutes/route/versions/1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/snapshots/namespaces/default/modelroutes/route/versions/3", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/snapshots/namespaces/default/modelroutes/route/rollback", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/snapshots/namespaces/default/modelroutes/route/rollback?to=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var restored networkingv1alpha1.ModelRoute
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &restored))
+	assert.Equal(t, "llama", restored.Spec.ModelName)
+}
@@ -0,0 +1,221 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the inference requests of the opted-in models to an audit sink, for the environments
+// which must keep track of who used which model. The prompts are only recorded when a model opts in, once
+// redacted of the personal data.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+const (
+	defaultBatchSize     = 100
+	defaultFlushInterval = 5 * time.Second
+	defaultBufferSize    = 10000
+	// closeTimeout bounds the writing of the pending records on close
+	closeTimeout = 10 * time.Second
+
+	resultWritten = "written"
+	resultFailed  = "failed"
+	resultDropped = "dropped"
+)
+
+// Record is the audit record of a request.
+type Record struct {
+	Timestamp   time.Time `json:"timestamp"`
+	RequestID   string    `json:"request_id"`
+	User        string    `json:"user,omitempty"`
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	StatusCode  int       `json:"status_code"`
+	Model       string    `json:"model"`
+	ModelRoute  string    `json:"model_route,omitempty"`
+	ModelServer string    `json:"model_server,omitempty"`
+	Pod         string    `json:"pod,omitempty"`
+	// InputTokens and OutputTokens are the token counts known to the router
+	InputTokens  int    `json:"input_tokens,omitempty"`
+	OutputTokens int    `json:"output_tokens,omitempty"`
+	DurationMs   int64  `json:"duration_ms"`
+	Error        string `json:"error,omitempty"`
+	// Prompt is only recorded for the models including it, once redacted
+	Prompt string `json:"prompt,omitempty"`
+}
+
+// Auditor batches the audit records and writes them to the sink in the background. Recording never blocks
+// a request: the records are dropped when the sink can't keep up.
+type Auditor struct {
+	models        []conf.AuditModel
+	redactors     []Redactor
+	sink          Sink
+	batchSize     int
+	flushInterval time.Duration
+
+	records   chan *Record
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates an auditor writing to the configured sink. It returns nil if auditing is not configured,
+// a nil auditor audits no model.
+func New(config conf.AuditConfig) (*Auditor, error) {
+	if len(config.Models) == 0 || config.Sink.Type == "" {
+		return nil, nil
+	}
+	for _, model := range config.Models {
+		if _, err := path.Match(model.Name, ""); err != nil {
+			return nil, fmt.Errorf("invalid audited model pattern %q: %w", model.Name, err)
+		}
+	}
+	redactors, err := newRedactors(config.Redaction)
+	if err != nil {
+		return nil, err
+	}
+	flushInterval := defaultFlushInterval
+	if config.FlushInterval != "" {
+		if flushInterval, err = time.ParseDuration(config.FlushInterval); err != nil || flushInterval <= 0 {
+			return nil, fmt.Errorf("invalid audit flush interval %q", config.FlushInterval)
+		}
+	}
+	sink, err := newSink(config.Sink)
+	if err != nil {
+		return nil, err
+	}
+	a := &Auditor{
+		models:        config.Models,
+		redactors:     redactors,
+		sink:          sink,
+		batchSize:     orDefault(config.BatchSize, defaultBatchSize),
+		flushInterval: flushInterval,
+		records:       make(chan *Record, orDefault(config.BufferSize, defaultBufferSize)),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go a.run()
+	klog.Infof("Auditing the requests of %d model patterns to the %s sink", len(config.Models), config.Sink.Type)
+	return a, nil
+}
+
+func orDefault(value, defaultValue int) int {
+	if value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// Audited reports whether the requests of the model are audited, and whether their prompt is recorded.
+func (a *Auditor) Audited(model string) (audited bool, includePrompt bool) {
+	if a == nil {
+		return false, false
+	}
+	for _, m := range a.models {
+		if matched, _ := path.Match(m.Name, model); matched {
+			return true, m.IncludePrompt
+		}
+	}
+	return false, false
+}
+
+// Audit queues the record to be written, after redacting its prompt. The record is dropped if the queue is full.
+func (a *Auditor) Audit(record *Record) {
+	if a == nil {
+		return
+	}
+	if record.Prompt != "" {
+		record.Prompt = a.redact(record.Prompt)
+	}
+	select {
+	case a.records <- record:
+	default:
+		metrics.DefaultMetrics.RecordAuditRecords(resultDropped, 1)
+	}
+}
+
+func (a *Auditor) redact(prompt string) string {
+	for _, r := range a.redactors {
+		prompt = r.Redact(prompt)
+	}
+	return prompt
+}
+
+// Close writes the pending records and closes the sink.
+func (a *Auditor) Close() error {
+	if a == nil {
+		return nil
+	}
+	a.closeOnce.Do(func() {
+		close(a.stop)
+	})
+	<-a.done
+	return a.sink.Close()
+}
+
+func (a *Auditor) run() {
+	defer close(a.done)
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+	batch := make([]*Record, 0, a.batchSize)
+	flush := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		if err := a.sink.Write(ctx, batch); err != nil {
+			klog.Errorf("Failed to write %d audit records: %v", len(batch), err)
+			metrics.DefaultMetrics.RecordAuditRecords(resultFailed, len(batch))
+		} else {
+			metrics.DefaultMetrics.RecordAuditRecords(resultWritten, len(batch))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case record := <-a.records:
+			batch = append(batch, record)
+			if len(batch) >= a.batchSize {
+				flush(context.Background())
+			}
+		case <-ticker.C:
+			flush(context.Background())
+		case <-a.stop:
+			ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+			defer cancel()
+			for {
+				select {
+				case record := <-a.records:
+					batch = append(batch, record)
+					if len(batch) >= a.batchSize {
+						flush(ctx)
+					}
+				default:
+					flush(ctx)
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+func TestNewDisabled(t *testing.T) {
+	auditor, err := New(conf.AuditConfig{Sink: conf.AuditSink{Type: "file"}})
+	require.NoError(t, err)
+	assert.Nil(t, auditor, "no model is audited")
+
+	// A nil auditor audits nothing
+	audited, _ := auditor.Audited("llama")
+	assert.False(t, audited)
+	auditor.Audit(&Record{Model: "llama"})
+	assert.NoError(t, auditor.Close())
+}
+
+func TestNewInvalid(t *testing.T) {
+	models := []conf.AuditModel{{Name: "llama"}}
+	for name, config := range map[string]conf.AuditConfig{
+		"unknown sink":     {Models: models, Sink: conf.AuditSink{Type: "unknown"}},
+		"no file path":     {Models: models, Sink: conf.AuditSink{Type: "file"}},
+		"invalid pattern":  {Models: []conf.AuditModel{{Name: "["}}, Sink: conf.AuditSink{Type: "file"}},
+		"unknown redactor": {Models: models, Sink: conf.AuditSink{Type: "file"}, Redaction: conf.AuditRedaction{Redactors: []string{"unknown"}}},
+		"invalid interval": {Models: models, Sink: conf.AuditSink{Type: "file"}, FlushInterval: "often"},
+	} {
+		_, err := New(config)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestAuditor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditor, err := New(conf.AuditConfig{
+		Models: []conf.AuditModel{
+			{Name: "llama-*", IncludePrompt: true},
+			{Name: "qwen"},
+		},
+		Sink:      conf.AuditSink{Type: "file", File: conf.AuditFileSink{Path: path}},
+		Redaction: conf.AuditRedaction{Redactors: []string{"email"}, Patterns: []string{`ACME-\d+`}},
+	})
+	require.NoError(t, err)
+
+	audited, includePrompt := auditor.Audited("llama-3-8b")
+	assert.True(t, audited)
+	assert.True(t, includePrompt)
+	audited, includePrompt = auditor.Audited("qwen")
+	assert.True(t, audited)
+	assert.False(t, includePrompt)
+	audited, _ = auditor.Audited("mistral")
+	assert.False(t, audited)
+
+	auditor.Audit(&Record{RequestID: "1", Model: "llama-3-8b", Prompt: "mail jane@example.com about ACME-42"})
+	auditor.Audit(&Record{RequestID: "2", Model: "qwen", StatusCode: 200})
+	// Closing writes the pending records
+	require.NoError(t, auditor.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 2)
+	assert.Equal(t, "mail [REDACTED] about [REDACTED]", records[0].Prompt)
+	assert.Equal(t, "qwen", records[1].Model)
+	assert.Empty(t, records[1].Prompt)
+}
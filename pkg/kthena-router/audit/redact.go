@@ -0,0 +1,88 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+// redacted replaces the redacted personal data.
+const redacted = "[REDACTED]"
+
+// Redactor removes personal data from a prompt.
+type Redactor interface {
+	Redact(prompt string) string
+}
+
+// RedactorFunc adapts a function to a Redactor.
+type RedactorFunc func(prompt string) string
+
+func (f RedactorFunc) Redact(prompt string) string {
+	return f(prompt)
+}
+
+var (
+	redactorsMu sync.RWMutex
+	redactors   = map[string]Redactor{
+		"email":       patternRedactor(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+		"phone":       patternRedactor(`\+?\d{1,3}[ .-]?\(?\d{2,4}\)?[ .-]?\d{3,4}[ .-]?\d{3,4}\b`),
+		"credit-card": patternRedactor(`\b(?:\d[ -]?){12,18}\d\b`),
+		"ipv4":        patternRedactor(`\b(?:\d{1,3}\.){3}\d{1,3}\b`),
+	}
+)
+
+// RegisterRedactor registers a redactor which the audit configuration can select by name, it replaces
+// the redactor registered with the same name.
+func RegisterRedactor(name string, redactor Redactor) {
+	redactorsMu.Lock()
+	defer redactorsMu.Unlock()
+	redactors[name] = redactor
+}
+
+func patternRedactor(pattern string) Redactor {
+	expr := regexp.MustCompile(pattern)
+	return RedactorFunc(func(prompt string) string {
+		return expr.ReplaceAllString(prompt, redacted)
+	})
+}
+
+func newRedactors(config conf.AuditRedaction) ([]Redactor, error) {
+	redactorsMu.RLock()
+	defer redactorsMu.RUnlock()
+	var result []Redactor
+	for _, name := range config.Redactors {
+		redactor, ok := redactors[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown audit redactor %q", name)
+		}
+		result = append(result, redactor)
+	}
+	for _, pattern := range config.Patterns {
+		expr, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit redaction pattern %q: %w", pattern, err)
+		}
+		result = append(result, RedactorFunc(func(prompt string) string {
+			return expr.ReplaceAllString(prompt, redacted)
+		}))
+	}
+	return result, nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+func TestBuiltinRedactors(t *testing.T) {
+	for name, prompt := range map[string]string{
+		"email":       "contact john.doe+ai@example.co.uk now",
+		"phone":       "call +1 415-555-0132 now",
+		"credit-card": "pay with 4111 1111 1111 1111 now",
+		"ipv4":        "connect to 10.0.12.7 now",
+	} {
+		redactors, err := newRedactors(conf.AuditRedaction{Redactors: []string{name}})
+		require.NoError(t, err)
+		redacted := redactors[0].Redact(prompt)
+		assert.True(t, strings.HasSuffix(redacted, " [REDACTED] now"), "%s: %s", name, redacted)
+	}
+}
+
+func TestRegisterRedactor(t *testing.T) {
+	RegisterRedactor("upper", RedactorFunc(strings.ToUpper))
+	redactors, err := newRedactors(conf.AuditRedaction{Redactors: []string{"upper"}})
+	require.NoError(t, err)
+	assert.Equal(t, "SECRET", redactors[0].Redact("secret"))
+
+	_, err = newRedactors(conf.AuditRedaction{Patterns: []string{"("}})
+	assert.Error(t, err)
+}
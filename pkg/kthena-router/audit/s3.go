@@ -0,0 +1,182 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+const (
+	s3Service   = "s3"
+	s3Algorithm = "AWS4-HMAC-SHA256"
+	// s3Timeout bounds the upload of a batch
+	s3Timeout = 30 * time.Second
+)
+
+// s3Sink writes each batch of records as a JSON lines object, partitioned by hour.
+type s3Sink struct {
+	bucket *S3Bucket
+	now    func() time.Time
+}
+
+func newS3Sink(config conf.AuditSink) (Sink, error) {
+	bucket, err := NewS3Bucket(config.S3, "audit")
+	if err != nil {
+		return nil, err
+	}
+	return &s3Sink{bucket: bucket, now: time.Now}, nil
+}
+
+func (s *s3Sink) Write(ctx context.Context, records []*Record) error {
+	data, err := encodeLines(records)
+	if err != nil {
+		return err
+	}
+	now := s.now().UTC()
+	key := fmt.Sprintf("%s/%s-%s.jsonl", now.Format("2006/01/02/15"), now.Format("20060102T150405Z"), uuid.NewString())
+	return s.bucket.Put(ctx, key, "application/x-ndjson", data, now)
+}
+
+func (s *s3Sink) Close() error {
+	s.bucket.Close()
+	return nil
+}
+
+// S3Bucket puts objects in a bucket, signed with AWS Signature Version 4 so that it works with AWS S3 and
+// the S3 compatible object storages. The credentials are read from the AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY environment variables.
+type S3Bucket struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	prefix    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+// NewS3Bucket creates the client of the bucket, the usage names the objects in the errors, e.g. "audit".
+func NewS3Bucket(config conf.AuditS3Sink, usage string) (*S3Bucket, error) {
+	if config.Endpoint == "" || config.Bucket == "" || config.Region == "" {
+		return nil, fmt.Errorf("the endpoint, region and bucket of the %s S3 sink must be set", usage)
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid %s S3 endpoint %q", usage, config.Endpoint)
+	}
+	accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set for the %s S3 sink", usage)
+	}
+	return &S3Bucket{
+		endpoint:  endpoint,
+		region:    config.Region,
+		bucket:    config.Bucket,
+		prefix:    config.Prefix,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: s3Timeout},
+	}, nil
+}
+
+// Put uploads the object under the key, prefixed with the prefix of the bucket, signed at now.
+func (b *S3Bucket) Put(ctx context.Context, key, contentType string, data []byte, now time.Time) error {
+	key = b.prefix + key
+	// Path-style addressing works with every S3 compatible storage
+	target := *b.endpoint
+	target.Path = strings.TrimSuffix(target.Path, "/") + "/" + b.bucket + "/" + key
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	b.sign(req, data, now.UTC())
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to put object %s: %s %s", key, resp.Status, body)
+	}
+	return nil
+}
+
+// sign adds the AWS Signature Version 4 headers to the request.
+func (b *S3Bucket) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n",
+		req.Header.Get("Content-Type"), req.URL.Host, payloadHash, amzDate)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, b.region, s3Service)
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+b.secretKey), date)
+	key = hmacSHA256(key, b.region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, b.accessKey, scope, signedHeaders, signature))
+}
+
+// Close releases the idle connections of the client.
+func (b *S3Bucket) Close() {
+	b.client.CloseIdleConnections()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+const defaultWebhookTimeout = 10 * time.Second
+
+// Sink writes batches of audit records.
+type Sink interface {
+	Write(ctx context.Context, records []*Record) error
+	Close() error
+}
+
+// SinkFactory creates a sink from the audit sink configuration.
+type SinkFactory func(config conf.AuditSink) (Sink, error)
+
+var (
+	sinksMu sync.RWMutex
+	sinks   = map[string]SinkFactory{
+		"file":    newFileSink,
+		"s3":      newS3Sink,
+		"webhook": newWebhookSink,
+	}
+)
+
+// RegisterSink registers a sink which the audit configuration can select by type, it replaces the sink
+// registered with the same type.
+func RegisterSink(sinkType string, factory SinkFactory) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks[sinkType] = factory
+}
+
+func newSink(config conf.AuditSink) (Sink, error) {
+	sinksMu.RLock()
+	factory, ok := sinks[config.Type]
+	sinksMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown audit sink type %q", config.Type)
+	}
+	return factory(config)
+}
+
+// encodeLines encodes the records as JSON lines.
+func encodeLines(records []*Record) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// fileSink appends the records to a file as JSON lines.
+type fileSink struct {
+	file *os.File
+}
+
+func newFileSink(config conf.AuditSink) (Sink, error) {
+	if config.File.Path == "" {
+		return nil, fmt.Errorf("the path of the audit file is not set")
+	}
+	file, err := os.OpenFile(config.File.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file %s: %w", config.File.Path, err)
+	}
+	return &fileSink{file: file}, nil
+}
+
+func (s *fileSink) Write(ctx context.Context, records []*Record) error {
+	data, err := encodeLines(records)
+	if err != nil {
+		return err
+	}
+	_, err = s.file.Write(data)
+	return err
+}
+
+func (s *fileSink) Close() error {
+	return s.file.Close()
+}
+
+// webhookSink posts the records as a JSON array.
+type webhookSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newWebhookSink(config conf.AuditSink) (Sink, error) {
+	if config.Webhook.URL == "" {
+		return nil, fmt.Errorf("the URL of the audit webhook is not set")
+	}
+	timeout := defaultWebhookTimeout
+	if config.Webhook.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(config.Webhook.Timeout); err != nil {
+			return nil, fmt.Errorf("invalid audit webhook timeout %q: %w", config.Webhook.Timeout, err)
+		}
+	}
+	s := &webhookSink{url: config.Webhook.URL, client: &http.Client{Timeout: timeout}}
+	if config.Webhook.TokenFile != "" {
+		token, err := os.ReadFile(config.Webhook.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the audit webhook token: %w", err)
+		}
+		s.token = strings.TrimSpace(string(token))
+	}
+	return s, nil
+}
+
+func (s *webhookSink) Write(ctx context.Context, records []*Record) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("audit webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *webhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
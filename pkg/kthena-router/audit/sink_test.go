@@ -0,0 +1,101 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+func TestWebhookSink(t *testing.T) {
+	var received []Record
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0600))
+
+	sink, err := newSink(conf.AuditSink{Type: "webhook", Webhook: conf.AuditWebhookSink{URL: server.URL, TokenFile: tokenFile}})
+	require.NoError(t, err)
+	defer sink.Close()
+	require.NoError(t, sink.Write(context.Background(), []*Record{{RequestID: "1"}, {RequestID: "2"}}))
+	require.Len(t, received, 2)
+	assert.Equal(t, "2", received[1].RequestID)
+}
+
+func TestWebhookSinkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	sink, err := newSink(conf.AuditSink{Type: "webhook", Webhook: conf.AuditWebhookSink{URL: server.URL}})
+	require.NoError(t, err)
+	assert.Error(t, sink.Write(context.Background(), []*Record{{RequestID: "1"}}))
+}
+
+func TestS3Sink(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	var path, authorization, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, sha256Hex(mustReadAll(t, r.Body, &body)), r.Header.Get("X-Amz-Content-Sha256"))
+		path, authorization = r.URL.Path, r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	sink, err := newSink(conf.AuditSink{Type: "s3", S3: conf.AuditS3Sink{
+		Endpoint: server.URL, Region: "us-east-1", Bucket: "audit", Prefix: "router/",
+	}})
+	require.NoError(t, err)
+	sink.(*s3Sink).now = func() time.Time { return time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC) }
+	require.NoError(t, sink.Write(context.Background(), []*Record{{RequestID: "1"}, {RequestID: "2"}}))
+
+	assert.True(t, strings.HasPrefix(path, "/audit/router/2026/03/04/05/20260304T050607Z-"), path)
+	assert.True(t, strings.HasSuffix(path, ".jsonl"), path)
+	assert.Equal(t, 2, strings.Count(body, "\n"), "one line per record")
+	assert.True(t, strings.HasPrefix(authorization,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20260304/us-east-1/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature="), authorization)
+}
+
+func TestS3SinkRequiresCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	_, err := newSink(conf.AuditSink{Type: "s3", S3: conf.AuditS3Sink{Endpoint: "https://s3.example.com", Region: "us-east-1", Bucket: "audit"}})
+	assert.Error(t, err)
+}
+
+func mustReadAll(t *testing.T, r io.Reader, into *string) []byte {
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	*into = string(data)
+	return data
+}
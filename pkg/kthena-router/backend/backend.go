@@ -18,65 +18,107 @@ package backend
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 
 	dto "github.com/prometheus/client_model/go"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 
-	"github.com/volcano-sh/kthena/pkg/kthena-router/backend/sglang"
-	"github.com/volcano-sh/kthena/pkg/kthena-router/backend/vllm"
+	"github.com/volcano-sh/kthena/pkg/engines"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/backend/metrics"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
 )
 
-type MetricsProvider interface {
-	GetPodMetrics(pod *corev1.Pod) (map[string]*dto.MetricFamily, error)
-	GetPodModels(pod *corev1.Pod) ([]string, error)
-	GetCountMetricsInfo(allMetrics map[string]*dto.MetricFamily) map[string]float64
-	GetHistogramPodMetrics(allMetrics map[string]*dto.MetricFamily, previousHistogram map[string]*dto.Histogram) (map[string]float64, map[string]*dto.Histogram)
-}
-
-var engineRegistry = map[string]MetricsProvider{
-	"SGLang": sglang.NewSglangEngine(),
-	"vLLM":   vllm.NewVllmEngine(),
-}
-
 func GetPodMetrics(engine string, pod *corev1.Pod, previousHistogram map[string]*dto.Histogram) (map[string]float64, map[string]*dto.Histogram) {
-	provider, err := GetMetricsProvider(engine)
+	inferenceEngine, err := getEngine(engine)
 	if err != nil {
 		klog.Errorf("Failed to get inference engine: %v", err)
 		return nil, nil
 	}
+	if inferenceEngine.MetricsPath() == "" {
+		return nil, nil
+	}
 
-	allMetrics, err := provider.GetPodMetrics(pod)
+	url := fmt.Sprintf("http://%s%s", netutil.HostPort(pod.Status.PodIP, inferenceEngine.Port()), inferenceEngine.MetricsPath())
+	allMetrics, err := metrics.ParseMetricsURL(url)
 	if err != nil {
 		klog.V(4).Infof("failed to get metrics of pod: %s/%s: %v", pod.GetNamespace(), pod.GetName(), err)
 		return nil, nil
 	}
 
-	countMetricsInfo := provider.GetCountMetricsInfo(allMetrics)
-	histogramMetricsInfo, histogramMetrics := provider.GetHistogramPodMetrics(allMetrics, previousHistogram)
+	return inferenceEngine.ParseMetrics(allMetrics, previousHistogram)
+}
 
-	for name, value := range histogramMetricsInfo {
-		// Since the key in countMetricInfo must not be the same as the key in histogramMetricsInfo.
-		// You don't have to worry about overriding the value
-		countMetricsInfo[name] = value
+// GetPodModelMetrics scrapes the metrics of a pod serving several models once, and returns the metrics of the
+// pod and of each model, when the engine labels its metrics by model. previousModelHistogram holds the histograms
+// of the last scrape of each model.
+func GetPodModelMetrics(engine string, pod *corev1.Pod, previousHistogram map[string]*dto.Histogram,
+	previousModelHistogram map[string]map[string]*dto.Histogram) (map[string]float64, map[string]*dto.Histogram, map[string]ModelMetrics) {
+	inferenceEngine, err := getEngine(engine)
+	if err != nil {
+		klog.Errorf("Failed to get inference engine: %v", err)
+		return nil, nil, nil
+	}
+	if inferenceEngine.MetricsPath() == "" {
+		return nil, nil, nil
 	}
 
-	return countMetricsInfo, histogramMetrics
-}
+	url := fmt.Sprintf("http://%s%s", netutil.HostPort(pod.Status.PodIP, inferenceEngine.Port()), inferenceEngine.MetricsPath())
+	allMetrics, err := metrics.ParseMetricsURL(url)
+	if err != nil {
+		klog.V(4).Infof("failed to get metrics of pod: %s/%s: %v", pod.GetNamespace(), pod.GetName(), err)
+		return nil, nil, nil
+	}
 
-func GetMetricsProvider(engine string) (MetricsProvider, error) {
-	if provider, exists := engineRegistry[engine]; exists {
-		return provider, nil
+	gauges, histograms := inferenceEngine.ParseMetrics(allMetrics, previousHistogram)
+	var modelMetrics map[string]ModelMetrics
+	for model, families := range engines.SplitByModel(allMetrics) {
+		if model == "" {
+			continue
+		}
+		if modelMetrics == nil {
+			modelMetrics = make(map[string]ModelMetrics)
+		}
+		modelGauges, modelHistograms := inferenceEngine.ParseMetrics(families, previousModelHistogram[model])
+		modelMetrics[model] = ModelMetrics{Gauges: modelGauges, Histograms: modelHistograms}
 	}
-	return nil, fmt.Errorf("unsupported engine: %s", engine)
+	return gauges, histograms, modelMetrics
+}
+
+// ModelMetrics are the metrics of one of the models served by a pod.
+type ModelMetrics struct {
+	Gauges     map[string]float64
+	Histograms map[string]*dto.Histogram
 }
 
 func GetPodModels(engine string, pod *corev1.Pod) ([]string, error) {
-	provider, err := GetMetricsProvider(engine)
+	inferenceEngine, err := getEngine(engine)
 	if err != nil {
 		klog.Errorf("Failed to get inference engine: %v", err)
 		return nil, nil
 	}
+	if inferenceEngine.ModelsPath() == "" {
+		return nil, nil
+	}
+
+	url := fmt.Sprintf("http://%s%s", netutil.HostPort(pod.Status.PodIP, inferenceEngine.Port()), inferenceEngine.ModelsPath())
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-	return provider.GetPodModels(pod)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return engines.ParseModels(body)
+}
+
+func getEngine(engine string) (engines.InferenceEngine, error) {
+	if inferenceEngine := engines.Get(engine); inferenceEngine != nil {
+		return inferenceEngine, nil
+	}
+	return nil, fmt.Errorf("unsupported engine: %s", engine)
 }
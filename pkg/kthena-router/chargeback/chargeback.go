@@ -0,0 +1,277 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package chargeback attributes the tokens and the GPU time of the inference requests to their consumers,
+// the namespaces of their model servers and their models, so that a shared inference platform can charge
+// its usage back to the teams. The usage is exported as metrics and written in periodic CSV reports.
+package chargeback
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+	msutils "github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+const (
+	defaultAPIKeyHeader   = "X-API-Key"
+	defaultReportInterval = time.Hour
+	// closeTimeout bounds the writing of the last report on close
+	closeTimeout = 10 * time.Second
+
+	// AnonymousConsumer is the consumer of the requests carrying neither a JWT nor an API key.
+	AnonymousConsumer = "anonymous"
+	// apiKeyFingerprintLength is the number of hex digits of the API key hash identifying a consumer
+	apiKeyFingerprintLength = 12
+
+	resultWritten = "written"
+	resultFailed  = "failed"
+)
+
+// Usage is the usage of a single request.
+type Usage struct {
+	Consumer     string
+	Namespace    string
+	Model        string
+	InputTokens  int
+	OutputTokens int
+	GPUSeconds   float64
+}
+
+// Row is the usage of a model by a consumer over the period of a report.
+type Row struct {
+	Consumer     string
+	Namespace    string
+	Model        string
+	Requests     int64
+	InputTokens  int64
+	OutputTokens int64
+	GPUSeconds   float64
+}
+
+// Report is the usage of all the consumers over a period, sorted by consumer, namespace and model.
+type Report struct {
+	Start time.Time
+	End   time.Time
+	Rows  []Row
+}
+
+type rowKey struct {
+	consumer, namespace, model string
+}
+
+// Reporter records the usage of the requests as metrics, and aggregates it into a report written to the
+// sink at the end of each period. The periods are aligned on the report interval, so that the reports of
+// the router replicas cover the same periods.
+type Reporter struct {
+	apiKeyHeader string
+	interval     time.Duration
+	sink         Sink
+
+	mu    sync.Mutex
+	start time.Time
+	rows  map[rowKey]*Row
+
+	now       func() time.Time
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates a reporter from the chargeback configuration. It returns nil if chargeback is disabled,
+// a nil reporter records nothing.
+func New(config conf.ChargebackConfig) (*Reporter, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+	interval := defaultReportInterval
+	if config.ReportInterval != "" {
+		var err error
+		if interval, err = time.ParseDuration(config.ReportInterval); err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid chargeback report interval %q", config.ReportInterval)
+		}
+	}
+	r := &Reporter{
+		apiKeyHeader: config.APIKeyHeader,
+		interval:     interval,
+		rows:         make(map[rowKey]*Row),
+		now:          time.Now,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	if r.apiKeyHeader == "" {
+		r.apiKeyHeader = defaultAPIKeyHeader
+	}
+	r.start = r.now()
+	if config.Sink.Type == "" {
+		close(r.done)
+		klog.Infof("Chargeback enabled, exporting the usage as metrics only")
+		return r, nil
+	}
+	sink, err := newSink(config.Sink)
+	if err != nil {
+		return nil, err
+	}
+	r.sink = sink
+	go r.run()
+	klog.Infof("Chargeback enabled, writing a report every %s to the %s sink", interval, config.Sink.Type)
+	return r, nil
+}
+
+// Consumer identifies the consumer of a request: the subject of its JWT, else the fingerprint of its API key,
+// so that the API keys are never exposed in the metrics and the reports.
+func (r *Reporter) Consumer(header http.Header, user string) string {
+	if user != "" {
+		return user
+	}
+	if r == nil {
+		return AnonymousConsumer
+	}
+	if apiKey := header.Get(r.apiKeyHeader); apiKey != "" {
+		return APIKeyFingerprint(apiKey)
+	}
+	return AnonymousConsumer
+}
+
+// APIKeyFingerprint returns the consumer identifying an API key, "apikey-" followed by the first hex digits
+// of its SHA-256 hash.
+func APIKeyFingerprint(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return "apikey-" + hex.EncodeToString(sum[:])[:apiKeyFingerprintLength]
+}
+
+// GPUShare returns the GPUs of the pod attributed to a request, its accelerators being shared evenly with
+// the requests already running on the pod when the request was scheduled.
+func GPUShare(pod *corev1.Pod, runningRequests float64) float64 {
+	if pod == nil {
+		return 0
+	}
+	var gpus int64
+	for i := range pod.Spec.Containers {
+		gpus += msutils.AcceleratorCount(&pod.Spec.Containers[i])
+	}
+	return float64(gpus) / (max(runningRequests, 0) + 1)
+}
+
+// Record records the usage of a request.
+func (r *Reporter) Record(usage Usage) {
+	if r == nil {
+		return
+	}
+	metrics.DefaultMetrics.RecordChargeback(usage.Consumer, usage.Namespace, usage.Model,
+		usage.InputTokens, usage.OutputTokens, usage.GPUSeconds)
+	if r.sink == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := rowKey{consumer: usage.Consumer, namespace: usage.Namespace, model: usage.Model}
+	row, ok := r.rows[key]
+	if !ok {
+		row = &Row{Consumer: usage.Consumer, Namespace: usage.Namespace, Model: usage.Model}
+		r.rows[key] = row
+	}
+	row.Requests++
+	row.InputTokens += int64(usage.InputTokens)
+	row.OutputTokens += int64(usage.OutputTokens)
+	row.GPUSeconds += usage.GPUSeconds
+}
+
+// Close writes the report of the current period and closes the sink.
+func (r *Reporter) Close() error {
+	if r == nil || r.sink == nil {
+		return nil
+	}
+	r.closeOnce.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
+	return r.sink.Close()
+}
+
+func (r *Reporter) run() {
+	defer close(r.done)
+	for {
+		timer := time.NewTimer(time.Until(r.periodEnd()))
+		select {
+		case <-timer.C:
+			r.flush(context.Background(), r.periodEnd())
+		case <-r.stop:
+			timer.Stop()
+			ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+			defer cancel()
+			r.flush(ctx, r.now())
+			return
+		}
+	}
+}
+
+// periodEnd returns the end of the current period, the next multiple of the report interval.
+func (r *Reporter) periodEnd() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.start.Truncate(r.interval).Add(r.interval)
+}
+
+// flush ends the current period at end and writes its report, if any request was recorded.
+func (r *Reporter) flush(ctx context.Context, end time.Time) {
+	report := r.cut(end)
+	if len(report.Rows) == 0 {
+		return
+	}
+	if err := r.sink.Write(ctx, report); err != nil {
+		klog.Errorf("Failed to write the chargeback report of %s: %v", report.Start.UTC().Format(time.RFC3339), err)
+		metrics.DefaultMetrics.RecordChargebackReport(resultFailed)
+		return
+	}
+	metrics.DefaultMetrics.RecordChargebackReport(resultWritten)
+}
+
+// cut returns the report of the current period ending at end, and starts the next period.
+func (r *Reporter) cut(end time.Time) *Report {
+	r.mu.Lock()
+	report := &Report{Start: r.start, End: end, Rows: make([]Row, 0, len(r.rows))}
+	for _, row := range r.rows {
+		report.Rows = append(report.Rows, *row)
+	}
+	r.start = end
+	r.rows = make(map[rowKey]*Row)
+	r.mu.Unlock()
+
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if a.Consumer != b.Consumer {
+			return a.Consumer < b.Consumer
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Model < b.Model
+	})
+	return report
+}
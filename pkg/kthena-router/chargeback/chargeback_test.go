@@ -0,0 +1,154 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chargeback
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+func TestNew(t *testing.T) {
+	reporter, err := New(conf.ChargebackConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, reporter, "chargeback is disabled by default")
+	// A nil reporter records nothing
+	reporter.Record(Usage{Consumer: "alice"})
+	assert.NoError(t, reporter.Close())
+
+	_, err = New(conf.ChargebackConfig{Enabled: true, ReportInterval: "hourly"})
+	assert.Error(t, err)
+	_, err = New(conf.ChargebackConfig{Enabled: true, Sink: conf.ChargebackSink{Type: "kafka"}})
+	assert.Error(t, err)
+	_, err = New(conf.ChargebackConfig{Enabled: true, Sink: conf.ChargebackSink{Type: "file"}})
+	assert.Error(t, err, "the directory is required")
+
+	reporter, err = New(conf.ChargebackConfig{Enabled: true})
+	require.NoError(t, err)
+	reporter.Record(Usage{Consumer: "alice", Namespace: "default", Model: "llama", InputTokens: 10})
+	assert.Empty(t, reporter.rows, "the usage is only aggregated for a sink")
+	assert.NoError(t, reporter.Close())
+}
+
+func TestConsumer(t *testing.T) {
+	reporter, err := New(conf.ChargebackConfig{Enabled: true, APIKeyHeader: "Authorization-Key"})
+	require.NoError(t, err)
+
+	header := http.Header{}
+	assert.Equal(t, AnonymousConsumer, reporter.Consumer(header, ""))
+	header.Set("Authorization-Key", "sk-secret")
+	consumer := reporter.Consumer(header, "")
+	assert.Equal(t, APIKeyFingerprint("sk-secret"), consumer)
+	assert.True(t, strings.HasPrefix(consumer, "apikey-"), consumer)
+	assert.Len(t, consumer, len("apikey-")+apiKeyFingerprintLength)
+	assert.NotContains(t, consumer, "secret", "the API key is not exposed")
+	assert.Equal(t, "alice", reporter.Consumer(header, "alice"), "the JWT subject wins")
+}
+
+func TestGPUShare(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		{Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("4")}}},
+		{Name: "sidecar"},
+	}}}
+	assert.Equal(t, 4.0, GPUShare(pod, 0))
+	assert.Equal(t, 1.0, GPUShare(pod, 3))
+	assert.Equal(t, 0.0, GPUShare(&corev1.Pod{}, 0))
+	assert.Equal(t, 0.0, GPUShare(nil, 0))
+}
+
+func TestReporterFileSink(t *testing.T) {
+	dir := t.TempDir()
+	reporter, err := New(conf.ChargebackConfig{
+		Enabled: true,
+		Sink:    conf.ChargebackSink{Type: "file", File: conf.ChargebackFileSink{Directory: dir}},
+	})
+	require.NoError(t, err)
+	reporter.Record(Usage{Consumer: "bob", Namespace: "team-b", Model: "qwen", InputTokens: 5, OutputTokens: 7, GPUSeconds: 0.5})
+	reporter.Record(Usage{Consumer: "alice", Namespace: "team-a", Model: "llama", InputTokens: 10, OutputTokens: 20, GPUSeconds: 1.25})
+	reporter.Record(Usage{Consumer: "alice", Namespace: "team-a", Model: "llama", InputTokens: 1, OutputTokens: 2, GPUSeconds: 0.25})
+	// The report of the current period is written on close
+	require.NoError(t, reporter.Close())
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasPrefix(files[0].Name(), "chargeback-"), files[0].Name())
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "period_start,period_end,consumer,namespace,model,requests,input_tokens,output_tokens,gpu_seconds", lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], ",alice,team-a,llama,2,11,22,1.500"), lines[1])
+	assert.True(t, strings.HasSuffix(lines[2], ",bob,team-b,qwen,1,5,7,0.500"), lines[2])
+}
+
+func TestReportPeriods(t *testing.T) {
+	start := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	reporter := &Reporter{interval: time.Hour, start: start, rows: make(map[rowKey]*Row), sink: &fileSink{}}
+	assert.Equal(t, time.Date(2026, 3, 4, 6, 0, 0, 0, time.UTC), reporter.periodEnd(), "the periods are aligned on the interval")
+
+	reporter.Record(Usage{Consumer: "alice", Namespace: "default", Model: "llama", InputTokens: 3})
+	report := reporter.cut(reporter.periodEnd())
+	assert.Equal(t, start, report.Start)
+	require.Len(t, report.Rows, 1)
+	assert.Equal(t, int64(3), report.Rows[0].InputTokens)
+
+	assert.Empty(t, reporter.cut(reporter.periodEnd()).Rows, "the next period starts empty")
+	assert.Equal(t, time.Date(2026, 3, 4, 8, 0, 0, 0, time.UTC), reporter.periodEnd())
+}
+
+func TestS3Sink(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	var path, contentType, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		path, contentType, body = r.URL.Path, r.Header.Get("Content-Type"), string(data)
+	}))
+	defer server.Close()
+
+	sink, err := newSink(conf.ChargebackSink{Type: "s3", S3: conf.AuditS3Sink{
+		Endpoint: server.URL, Region: "us-east-1", Bucket: "billing", Prefix: "router/",
+	}})
+	require.NoError(t, err)
+	defer sink.Close()
+	start := time.Date(2026, 3, 4, 5, 0, 0, 0, time.UTC)
+	require.NoError(t, sink.Write(context.Background(), &Report{
+		Start: start,
+		End:   start.Add(time.Hour),
+		Rows:  []Row{{Consumer: "alice", Namespace: "default", Model: "llama", Requests: 1, GPUSeconds: 2}},
+	}))
+
+	assert.True(t, strings.HasPrefix(path, "/billing/router/2026/03/04/chargeback-20260304T050000Z-"), path)
+	assert.True(t, strings.HasSuffix(path, ".csv"), path)
+	assert.Equal(t, "text/csv", contentType)
+	assert.Contains(t, body, "2026-03-04T05:00:00Z,2026-03-04T06:00:00Z,alice,default,llama,1,0,0,2.000")
+}
@@ -0,0 +1,134 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chargeback
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/audit"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+const reportTimeFormat = "20060102T150405Z"
+
+// csvHeader is the first line of the reports.
+var csvHeader = []string{"period_start", "period_end", "consumer", "namespace", "model",
+	"requests", "input_tokens", "output_tokens", "gpu_seconds"}
+
+// Sink writes the chargeback reports.
+type Sink interface {
+	Write(ctx context.Context, report *Report) error
+	Close() error
+}
+
+func newSink(config conf.ChargebackSink) (Sink, error) {
+	replica, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the name of the router replica: %w", err)
+	}
+	switch config.Type {
+	case "file":
+		if config.File.Directory == "" {
+			return nil, fmt.Errorf("the directory of the chargeback file sink is not set")
+		}
+		if err := os.MkdirAll(config.File.Directory, 0750); err != nil {
+			return nil, fmt.Errorf("failed to create chargeback directory %s: %w", config.File.Directory, err)
+		}
+		return &fileSink{directory: config.File.Directory, replica: replica}, nil
+	case "s3":
+		bucket, err := audit.NewS3Bucket(config.S3, "chargeback")
+		if err != nil {
+			return nil, err
+		}
+		return &s3Sink{bucket: bucket, replica: replica}, nil
+	default:
+		return nil, fmt.Errorf("unknown chargeback sink type %q", config.Type)
+	}
+}
+
+// reportName is the name of the report file of a replica, each replica writing its own reports.
+func reportName(report *Report, replica string) string {
+	return fmt.Sprintf("chargeback-%s-%s.csv", report.Start.UTC().Format(reportTimeFormat), replica)
+}
+
+// EncodeCSV encodes the report as CSV, with a header line and one line per row.
+func EncodeCSV(report *Report) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	start, end := report.Start.UTC().Format(time.RFC3339), report.End.UTC().Format(time.RFC3339)
+	for _, row := range report.Rows {
+		record := []string{start, end, row.Consumer, row.Namespace, row.Model,
+			strconv.FormatInt(row.Requests, 10),
+			strconv.FormatInt(row.InputTokens, 10),
+			strconv.FormatInt(row.OutputTokens, 10),
+			strconv.FormatFloat(row.GPUSeconds, 'f', 3, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// fileSink writes each report to a file of the directory.
+type fileSink struct {
+	directory string
+	replica   string
+}
+
+func (s *fileSink) Write(ctx context.Context, report *Report) error {
+	data, err := EncodeCSV(report)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.directory, reportName(report, s.replica)), data, 0600)
+}
+
+func (s *fileSink) Close() error {
+	return nil
+}
+
+// s3Sink writes each report as an object, partitioned by day.
+type s3Sink struct {
+	bucket  *audit.S3Bucket
+	replica string
+}
+
+func (s *s3Sink) Write(ctx context.Context, report *Report) error {
+	data, err := EncodeCSV(report)
+	if err != nil {
+		return err
+	}
+	key := report.Start.UTC().Format("2006/01/02/") + reportName(report, s.replica)
+	return s.bucket.Put(ctx, key, "text/csv", data, time.Now())
+}
+
+func (s *s3Sink) Close() error {
+	s.bucket.Close()
+	return nil
+}
@@ -18,9 +18,28 @@ package common
 
 const (
 	UserIdKey     = "user_id"
+	UserClaimsKey = "user_claims"
 	TokenUsageKey = "token_usage"
 )
 
+// RequestType is the kind of inference request, determined by the API endpoint
+type RequestType string
+
+const (
+	// RequestTypeGeneration is a completions or chat completions request
+	RequestTypeGeneration RequestType = "generation"
+	// RequestTypeEmbedding is an embeddings request
+	RequestTypeEmbedding RequestType = "embedding"
+	// RequestTypeRerank is a rerank request
+	RequestTypeRerank RequestType = "rerank"
+)
+
+// IsGenerative reports whether the request generates tokens, i.e. it may be streamed
+// and served by PD disaggregated model servers.
+func (t RequestType) IsGenerative() bool {
+	return t == RequestTypeGeneration || t == ""
+}
+
 // Message represents a single message in a chat conversation
 type Message struct {
 	Role    string `json:"role"`
@@ -34,4 +53,7 @@ type ChatMessage struct {
 
 	// Messages is used for chat conversation input (chat mode)
 	Messages []Message `json:"messages,omitempty"`
+
+	// Tools are the JSON definitions of the tools the model may call
+	Tools []string `json:"tools,omitempty"`
 }
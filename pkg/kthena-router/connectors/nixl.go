@@ -29,6 +29,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/upstream"
 )
 
 // NIXLConnector implements high-performance distributed in-memory KV cache using NIXL
@@ -121,7 +122,7 @@ func (n *NIXLConnector) prefill(req *http.Request, prefillAddr string) (interfac
 	klog.V(4).Infof("%s prefill: sending to %s", n.name, req.URL.String())
 
 	// Send prefill request
-	resp, err := http.DefaultTransport.RoundTrip(req)
+	resp, err := upstream.Default().HTTP1().RoundTrip(req)
 	if err != nil {
 		return nil, err
 	}
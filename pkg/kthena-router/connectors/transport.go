@@ -27,11 +27,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/handlers"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/upstream"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/utils"
 	"k8s.io/klog/v2"
 )
 
 func prefillerProxy(_ *gin.Context, req *http.Request) error {
-	resp, err := http.DefaultTransport.RoundTrip(req)
+	resp, err := upstream.Default().HTTP1().RoundTrip(req)
 	if err != nil {
 		return fmt.Errorf("prefill request failed: %w", err)
 	}
@@ -46,7 +48,7 @@ func prefillerProxy(_ *gin.Context, req *http.Request) error {
 }
 
 func decoderProxy(c *gin.Context, req *http.Request) (int, error) {
-	resp, err := http.DefaultTransport.RoundTrip(req)
+	resp, err := upstream.Default().HTTP1().RoundTrip(req)
 	if err != nil {
 		return 0, fmt.Errorf("decode request failed: %w", err)
 	}
@@ -87,6 +89,9 @@ func preparePrefillBody(reqBody map[string]interface{}) {
 	if reqBody["max_completion_tokens"] != nil {
 		reqBody["max_completion_tokens"] = 1
 	}
+	if reqBody["max_output_tokens"] != nil {
+		reqBody["max_output_tokens"] = 1
+	}
 }
 
 func buildPrefillRequest(req *http.Request, modelRequest map[string]interface{}) *http.Request {
@@ -125,6 +130,10 @@ func BuildDecodeRequest(c *gin.Context, req *http.Request, modelRequest map[stri
 // addTokenUsage adds token usage to the request body if it is not already present
 // should be used for decode requests or non PD disaggregated mode
 func addTokenUsage(c *gin.Context, reqBody map[string]interface{}) map[string]interface{} {
+	// The Responses API always reports the usage, and rejects the options of the other APIs
+	if c.Request != nil && c.Request.URL != nil && utils.IsResponsesAPI(c.Request.URL.Path) {
+		return reqBody
+	}
 	// Check if streaming is enabled
 	if isStreamingRequest(reqBody) {
 		if !isTokenUsageEnabled(reqBody) {
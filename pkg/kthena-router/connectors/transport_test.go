@@ -250,6 +250,15 @@ func TestBuildPrefillRequest(t *testing.T) {
 			},
 			expectNil: false,
 		},
+		{
+			name: "responses API request with max_output_tokens",
+			modelRequest: map[string]interface{}{
+				"model":             "test-model",
+				"input":             "hello",
+				"max_output_tokens": 200,
+			},
+			expectNil: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -285,6 +294,9 @@ func TestBuildPrefillRequest(t *testing.T) {
 			if tt.modelRequest["max_completion_tokens"] != nil {
 				assert.Equal(t, float64(1), parsedRequest["max_completion_tokens"])
 			}
+			if tt.modelRequest["max_output_tokens"] != nil {
+				assert.Equal(t, float64(1), parsedRequest["max_output_tokens"])
+			}
 
 			// URL scheme should be http
 			assert.Equal(t, "http", result.URL.Scheme)
@@ -373,6 +385,27 @@ func TestBuildDecodeRequest(t *testing.T) {
 	}
 }
 
+func TestBuildDecodeRequestResponsesAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, stream := range []bool{true, false} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("POST", "/v1/responses", nil)
+
+		result := BuildDecodeRequest(c, c.Request, map[string]interface{}{"model": "test-model", "input": "hello", "stream": stream})
+		require.NotNil(t, result)
+		body, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		var parsedRequest map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &parsedRequest))
+
+		// The Responses API always reports the usage, and rejects the usage options of the other APIs
+		assert.NotContains(t, parsedRequest, "stream_options")
+		assert.NotContains(t, parsedRequest, "include_usage")
+		_, exists := c.Get(common.TokenUsageKey)
+		assert.False(t, exists)
+	}
+}
+
 func TestHandleNonStreamingResponse(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -0,0 +1,466 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"istio.io/istio/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayinformers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
+	gatewaylisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+const (
+	// GatewayControllerName is the controllerName of the GatewayClasses whose Gateways are served by the router.
+	GatewayControllerName gatewayv1.GatewayController = "networking.serving.volcano.sh/kthena-router"
+	// GatewayModelNameHeader is the header an HTTPRoute matches to select the model of the requests. The router
+	// reads the model from the request body, the clients don't have to send it.
+	GatewayModelNameHeader = "X-Gateway-Model-Name"
+)
+
+// HTTPRouteController programs the datastore with the HTTPRoutes attached to the Gateways served by the router.
+// Each model matched by an HTTPRoute becomes a ModelRoute routing to the ModelServers of its backendRefs.
+type HTTPRouteController struct {
+	httpRouteLister gatewaylisters.HTTPRouteLister
+	parents         *gatewayParents
+	registrations   []cache.ResourceEventHandlerRegistration
+
+	workqueue   workqueue.TypedRateLimitingInterface[any]
+	initialSync *atomic.Bool
+	progress    workerProgress
+	store       datastore.Store
+	// programmed are the keys of the ModelRoutes added to the datastore for each HTTPRoute.
+	programmed map[string]sets.Set[string]
+}
+
+func NewHTTPRouteController(
+	gatewayInformerFactory gatewayinformers.SharedInformerFactory,
+	store datastore.Store,
+) *HTTPRouteController {
+	httpRouteInformer := gatewayInformerFactory.Gateway().V1().HTTPRoutes()
+	gatewayInformer := gatewayInformerFactory.Gateway().V1().Gateways()
+	gatewayClassInformer := gatewayInformerFactory.Gateway().V1().GatewayClasses()
+
+	controller := &HTTPRouteController{
+		httpRouteLister: httpRouteInformer.Lister(),
+		parents:         newGatewayParents(gatewayInformerFactory),
+		workqueue:       workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[any]()),
+		initialSync:     &atomic.Bool{},
+		store:           store,
+		programmed:      make(map[string]sets.Set[string]),
+	}
+
+	routeRegistration, _ := httpRouteInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueHTTPRoute,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueueHTTPRoute(new)
+		},
+		DeleteFunc: controller.enqueueHTTPRoute,
+	})
+	// The Gateways and GatewayClasses decide which HTTPRoutes are served, they change rarely
+	parentHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueAllHTTPRoutes,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueueAllHTTPRoutes(new)
+		},
+		DeleteFunc: controller.enqueueAllHTTPRoutes,
+	}
+	gatewayRegistration, _ := gatewayInformer.Informer().AddEventHandler(parentHandler)
+	gatewayClassRegistration, _ := gatewayClassInformer.Informer().AddEventHandler(parentHandler)
+	controller.registrations = []cache.ResourceEventHandlerRegistration{routeRegistration, gatewayRegistration, gatewayClassRegistration}
+
+	return controller
+}
+
+func (c *HTTPRouteController) Run(stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+
+	synced := make([]cache.InformerSynced, 0, len(c.registrations))
+	for _, registration := range c.registrations {
+		synced = append(synced, registration.HasSynced)
+	}
+	if ok := cache.WaitForCacheSync(stopCh, synced...); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+	// add initialSync signal
+	c.workqueue.Add(initialSyncSignal)
+
+	go wait.Until(c.runWorker, time.Second, stopCh)
+
+	<-stopCh
+	return nil
+}
+
+func (c *HTTPRouteController) HasSynced() bool {
+	return c.initialSync.Load()
+}
+
+// Live returns an error if the controller is stuck processing an item, the datastore is not updated anymore.
+func (c *HTTPRouteController) Live() error {
+	if err := c.progress.stalled(stallTimeout); err != nil {
+		return fmt.Errorf("HTTPRoute controller is stuck: %w", err)
+	}
+	return nil
+}
+
+func (c *HTTPRouteController) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *HTTPRouteController) processNextWorkItem() bool {
+	obj, shutdown := c.workqueue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.workqueue.Done(obj)
+	c.progress.start()
+	defer c.progress.done()
+
+	if obj == initialSyncSignal {
+		klog.V(2).Info("initial HTTPRoutes have been synced")
+		c.workqueue.Forget(obj)
+		c.initialSync.Store(true)
+		return true
+	}
+
+	var key string
+	var ok bool
+	if key, ok = obj.(string); !ok {
+		c.workqueue.Forget(obj)
+		utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+		return true
+	}
+
+	if err := c.syncHandler(key); err != nil {
+		if c.workqueue.NumRequeues(key) < maxRetries {
+			klog.V(2).Infof("error syncing HTTPRoute %q: %s, requeuing", key, err.Error())
+			c.workqueue.AddRateLimited(key)
+			return true
+		}
+		klog.V(2).Infof("giving up on syncing HTTPRoute %q after %d retries: %s", key, maxRetries, err)
+		c.workqueue.Forget(obj)
+	}
+	return true
+}
+
+func (c *HTTPRouteController) syncHandler(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	route, err := c.httpRouteLister.HTTPRoutes(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		c.program(key, nil)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !c.parents.accepts(route) {
+		c.program(key, nil)
+		return nil
+	}
+	modelRoutes, routeErr := convertHTTPRoute(route)
+	if routeErr != nil {
+		// The error is reported in the status of the HTTPRoute
+		klog.V(2).Infof("HTTPRoute %s is not programmed: %s", key, routeErr.message)
+		c.program(key, nil)
+		return nil
+	}
+	c.program(key, modelRoutes)
+	return nil
+}
+
+// program replaces the ModelRoutes of the HTTPRoute in the datastore.
+func (c *HTTPRouteController) program(key string, modelRoutes []*aiv1alpha1.ModelRoute) {
+	current := sets.New[string]()
+	for _, mr := range modelRoutes {
+		if err := c.store.AddOrUpdateModelRoute(mr); err != nil {
+			klog.Errorf("failed to add ModelRoute of HTTPRoute %s: %v", key, err)
+			continue
+		}
+		current.Insert(mr.Namespace + "/" + mr.Name)
+	}
+	for stale := range c.programmed[key].Difference(current) {
+		_ = c.store.DeleteModelRoute(stale)
+	}
+	if current.Len() == 0 {
+		delete(c.programmed, key)
+		return
+	}
+	c.programmed[key] = current
+}
+
+func (c *HTTPRouteController) enqueueHTTPRoute(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+func (c *HTTPRouteController) enqueueAllHTTPRoutes(interface{}) {
+	routes, err := c.httpRouteLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, route := range routes {
+		c.enqueueHTTPRoute(route)
+	}
+}
+
+// gatewayParents finds the parents of the HTTPRoutes which are Gateways served by the router.
+type gatewayParents struct {
+	gatewayLister      gatewaylisters.GatewayLister
+	gatewayClassLister gatewaylisters.GatewayClassLister
+}
+
+func newGatewayParents(gatewayInformerFactory gatewayinformers.SharedInformerFactory) *gatewayParents {
+	return &gatewayParents{
+		gatewayLister:      gatewayInformerFactory.Gateway().V1().Gateways().Lister(),
+		gatewayClassLister: gatewayInformerFactory.Gateway().V1().GatewayClasses().Lister(),
+	}
+}
+
+// resolve returns whether the parent of the HTTPRoute is a Gateway served by the router, and whether its
+// listeners allow the routes of the HTTPRoute namespace.
+func (p *gatewayParents) resolve(route *gatewayv1.HTTPRoute, ref gatewayv1.ParentReference) (served, allowed bool) {
+	if ref.Group != nil && *ref.Group != gatewayv1.GroupName {
+		return false, false
+	}
+	if ref.Kind != nil && *ref.Kind != "Gateway" {
+		return false, false
+	}
+	namespace := route.Namespace
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	gateway, err := p.gatewayLister.Gateways(namespace).Get(string(ref.Name))
+	if err != nil {
+		return false, false
+	}
+	gatewayClass, err := p.gatewayClassLister.Get(string(gateway.Spec.GatewayClassName))
+	if err != nil || gatewayClass.Spec.ControllerName != GatewayControllerName {
+		return false, false
+	}
+	for _, listener := range gateway.Spec.Listeners {
+		from := gatewayv1.NamespacesFromSame
+		if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil && listener.AllowedRoutes.Namespaces.From != nil {
+			from = *listener.AllowedRoutes.Namespaces.From
+		}
+		// The namespace selectors are not supported
+		if from == gatewayv1.NamespacesFromAll || (from == gatewayv1.NamespacesFromSame && gateway.Namespace == route.Namespace) {
+			return true, true
+		}
+	}
+	return true, false
+}
+
+// accepts returns whether the HTTPRoute is attached to a Gateway served by the router.
+func (p *gatewayParents) accepts(route *gatewayv1.HTTPRoute) bool {
+	for _, ref := range route.Spec.ParentRefs {
+		if served, allowed := p.resolve(route, ref); served && allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// routeError is why an HTTPRoute cannot be programmed, with the reason of its Accepted condition.
+type routeError struct {
+	reason  gatewayv1.RouteConditionReason
+	message string
+}
+
+// convertHTTPRoute converts the HTTPRoute into a ModelRoute for each model it matches. The rules of a ModelRoute
+// are the matches of the HTTPRoute rules on the model, in order. The backendRefs which are not ModelServers of the
+// HTTPRoute namespace are left out, they are reported by the ResolvedRefs condition.
+func convertHTTPRoute(route *gatewayv1.HTTPRoute) ([]*aiv1alpha1.ModelRoute, *routeError) {
+	var models []string
+	rules := make(map[string][]*aiv1alpha1.Rule)
+	for i, rule := range route.Spec.Rules {
+		ruleName := fmt.Sprintf("rule-%d", i)
+		if rule.Name != nil {
+			ruleName = string(*rule.Name)
+		}
+		if len(rule.Filters) > 0 {
+			return nil, &routeError{reason: gatewayv1.RouteReasonIncompatibleFilters,
+				message: fmt.Sprintf("rule %s has filters, they are not supported", ruleName)}
+		}
+		for _, ref := range rule.BackendRefs {
+			if len(ref.Filters) > 0 {
+				return nil, &routeError{reason: gatewayv1.RouteReasonIncompatibleFilters,
+					message: fmt.Sprintf("a backendRef of rule %s has filters, they are not supported", ruleName)}
+			}
+		}
+		if len(rule.Matches) == 0 {
+			return nil, &routeError{reason: gatewayv1.RouteReasonUnsupportedValue,
+				message: fmt.Sprintf("rule %s has no match on the %s header", ruleName, GatewayModelNameHeader)}
+		}
+		targets := targetModelsOf(route.Namespace, rule.BackendRefs)
+		for _, match := range rule.Matches {
+			model, modelMatch, err := convertHTTPRouteMatch(match)
+			if err != nil {
+				err.message = fmt.Sprintf("rule %s: %s", ruleName, err.message)
+				return nil, err
+			}
+			if len(targets) == 0 {
+				continue
+			}
+			if _, ok := rules[model]; !ok {
+				models = append(models, model)
+			}
+			rules[model] = append(rules[model], &aiv1alpha1.Rule{
+				Name:         ruleName,
+				ModelMatch:   modelMatch,
+				TargetModels: targets,
+			})
+		}
+	}
+
+	modelRoutes := make([]*aiv1alpha1.ModelRoute, 0, len(models))
+	for _, model := range models {
+		modelRoutes = append(modelRoutes, &aiv1alpha1.ModelRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: route.Namespace,
+				Name:      httpRouteModelRouteName(route.Name, model),
+			},
+			Spec: aiv1alpha1.ModelRouteSpec{
+				ModelName: model,
+				Rules:     rules[model],
+			},
+		})
+	}
+	return modelRoutes, nil
+}
+
+// httpRouteModelRouteName is the name of the ModelRoute of the model of an HTTPRoute. It cannot collide with the
+// name of a ModelRoute object.
+func httpRouteModelRouteName(route, model string) string {
+	return fmt.Sprintf("httproute/%s/%s", route, model)
+}
+
+// convertHTTPRouteMatch returns the model of the match and the ModelMatch of its other conditions.
+func convertHTTPRouteMatch(match gatewayv1.HTTPRouteMatch) (string, *aiv1alpha1.ModelMatch, *routeError) {
+	if len(match.QueryParams) > 0 || match.Method != nil {
+		return "", nil, &routeError{reason: gatewayv1.RouteReasonUnsupportedValue,
+			message: "query parameter and method matches are not supported"}
+	}
+
+	var model string
+	modelMatch := &aiv1alpha1.ModelMatch{}
+	for _, header := range match.Headers {
+		regex := header.Type != nil && *header.Type == gatewayv1.HeaderMatchRegularExpression
+		if strings.EqualFold(string(header.Name), GatewayModelNameHeader) {
+			if regex {
+				return "", nil, &routeError{reason: gatewayv1.RouteReasonUnsupportedValue,
+					message: fmt.Sprintf("the %s header must be matched exactly", GatewayModelNameHeader)}
+			}
+			model = header.Value
+			continue
+		}
+		value := header.Value
+		if modelMatch.Headers == nil {
+			modelMatch.Headers = make(map[string]*aiv1alpha1.StringMatch)
+		}
+		if regex {
+			modelMatch.Headers[string(header.Name)] = &aiv1alpha1.StringMatch{Regex: &value}
+		} else {
+			modelMatch.Headers[string(header.Name)] = &aiv1alpha1.StringMatch{Exact: &value}
+		}
+	}
+	if model == "" {
+		return "", nil, &routeError{reason: gatewayv1.RouteReasonUnsupportedValue,
+			message: fmt.Sprintf("a match has no match on the %s header", GatewayModelNameHeader)}
+	}
+
+	if path := match.Path; path != nil && path.Value != nil {
+		value := *path.Value
+		pathType := gatewayv1.PathMatchPathPrefix
+		if path.Type != nil {
+			pathType = *path.Type
+		}
+		switch pathType {
+		case gatewayv1.PathMatchExact:
+			modelMatch.Uri = &aiv1alpha1.StringMatch{Exact: &value}
+		case gatewayv1.PathMatchRegularExpression:
+			modelMatch.Uri = &aiv1alpha1.StringMatch{Regex: &value}
+		default:
+			// Every path starts with /
+			if value != "/" {
+				modelMatch.Uri = &aiv1alpha1.StringMatch{Prefix: &value}
+			}
+		}
+	}
+
+	if modelMatch.Headers == nil && modelMatch.Uri == nil {
+		return model, nil, nil
+	}
+	return model, modelMatch, nil
+}
+
+// targetModelsOf returns the ModelServers the backendRefs refer to, weighted as in the HTTPRoute.
+func targetModelsOf(namespace string, refs []gatewayv1.HTTPBackendRef) []*aiv1alpha1.TargetModel {
+	var targets []*aiv1alpha1.TargetModel
+	for _, ref := range refs {
+		if reason, _ := checkBackendRef(namespace, ref.BackendObjectReference); reason != "" {
+			continue
+		}
+		weight := uint32(1)
+		if ref.Weight != nil {
+			weight = uint32(*ref.Weight)
+		}
+		targets = append(targets, &aiv1alpha1.TargetModel{
+			ModelServerName: string(ref.Name),
+			Weight:          &weight,
+		})
+	}
+	return targets
+}
+
+// checkBackendRef returns why the backendRef is not a ModelServer of the namespace, empty if it is one.
+func checkBackendRef(namespace string, ref gatewayv1.BackendObjectReference) (gatewayv1.RouteConditionReason, string) {
+	if ref.Group == nil || string(*ref.Group) != aiv1alpha1.GroupName || ref.Kind == nil || *ref.Kind != "ModelServer" {
+		return gatewayv1.RouteReasonInvalidKind, fmt.Sprintf("backendRef %s is not a %s ModelServer", ref.Name, aiv1alpha1.GroupName)
+	}
+	if ref.Namespace != nil && string(*ref.Namespace) != namespace {
+		return gatewayv1.RouteReasonRefNotPermitted, fmt.Sprintf("backendRef %s refers to another namespace", ref.Name)
+	}
+	return "", ""
+}
@@ -0,0 +1,242 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
+	gatewayinformers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+// newTestGatewayClient returns a client with a Gateway served by the router and one served by another controller.
+// The Gateways are created through the client, the fake object tracker guesses a wrong resource for their kind.
+func newTestGatewayClient(t *testing.T, objects ...runtime.Object) *gatewayfake.Clientset {
+	objects = append(objects,
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "kthena"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: GatewayControllerName},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "other"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: "example.com/other"},
+		},
+	)
+	client := gatewayfake.NewSimpleClientset(objects...)
+	for name, class := range map[string]string{"inference": "kthena", "web": "other"} {
+		_, err := client.GatewayV1().Gateways("default").Create(context.Background(), &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: gatewayv1.ObjectName(class),
+				Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+			},
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	return client
+}
+
+func modelServerBackendRef(name string, weight int32) gatewayv1.HTTPBackendRef {
+	return gatewayv1.HTTPBackendRef{BackendRef: gatewayv1.BackendRef{
+		BackendObjectReference: gatewayv1.BackendObjectReference{
+			Group: ptr.To(gatewayv1.Group(aiv1alpha1.GroupName)),
+			Kind:  ptr.To(gatewayv1.Kind("ModelServer")),
+			Name:  gatewayv1.ObjectName(name),
+		},
+		Weight: ptr.To(weight),
+	}}
+}
+
+func modelHeaderMatch(model string) gatewayv1.HTTPRouteMatch {
+	return gatewayv1.HTTPRouteMatch{
+		Headers: []gatewayv1.HTTPHeaderMatch{{Name: GatewayModelNameHeader, Value: model}},
+	}
+}
+
+func newTestHTTPRoute(parent string, rules ...gatewayv1.HTTPRouteRule) *gatewayv1.HTTPRoute {
+	return &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "llama", Generation: 3},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(parent)}},
+			},
+			Rules: rules,
+		},
+	}
+}
+
+func TestConvertHTTPRoute(t *testing.T) {
+	canaryMatch := modelHeaderMatch("llama")
+	canaryMatch.Headers = append(canaryMatch.Headers, gatewayv1.HTTPHeaderMatch{Name: "x-canary", Value: "true"})
+	canaryMatch.Path = &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/v1/chat")}
+	route := newTestHTTPRoute("inference",
+		gatewayv1.HTTPRouteRule{
+			Name:        ptr.To(gatewayv1.SectionName("canary")),
+			Matches:     []gatewayv1.HTTPRouteMatch{canaryMatch},
+			BackendRefs: []gatewayv1.HTTPBackendRef{modelServerBackendRef("llama-canary", 1)},
+		},
+		gatewayv1.HTTPRouteRule{
+			Matches: []gatewayv1.HTTPRouteMatch{modelHeaderMatch("llama"), modelHeaderMatch("qwen")},
+			BackendRefs: []gatewayv1.HTTPBackendRef{
+				modelServerBackendRef("llama-v1", 90),
+				modelServerBackendRef("llama-v2", 10),
+				{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "svc"}}},
+			},
+		},
+	)
+
+	modelRoutes, routeErr := convertHTTPRoute(route)
+	require.Nil(t, routeErr)
+	require.Len(t, modelRoutes, 2)
+
+	llama := modelRoutes[0]
+	assert.Equal(t, "default", llama.Namespace)
+	assert.Equal(t, "httproute/llama/llama", llama.Name)
+	assert.Equal(t, "llama", llama.Spec.ModelName)
+	require.Len(t, llama.Spec.Rules, 2)
+	assert.Equal(t, "canary", llama.Spec.Rules[0].Name)
+	assert.Equal(t, &aiv1alpha1.ModelMatch{
+		Headers: map[string]*aiv1alpha1.StringMatch{"x-canary": {Exact: ptr.To("true")}},
+		Uri:     &aiv1alpha1.StringMatch{Prefix: ptr.To("/v1/chat")},
+	}, llama.Spec.Rules[0].ModelMatch)
+	assert.Equal(t, "rule-1", llama.Spec.Rules[1].Name)
+	assert.Nil(t, llama.Spec.Rules[1].ModelMatch)
+	// The Service backendRef is left out
+	assert.Equal(t, []*aiv1alpha1.TargetModel{
+		{ModelServerName: "llama-v1", Weight: ptr.To(uint32(90))},
+		{ModelServerName: "llama-v2", Weight: ptr.To(uint32(10))},
+	}, llama.Spec.Rules[1].TargetModels)
+
+	qwen := modelRoutes[1]
+	assert.Equal(t, "qwen", qwen.Spec.ModelName)
+	require.Len(t, qwen.Spec.Rules, 1)
+	assert.Equal(t, llama.Spec.Rules[1].TargetModels, qwen.Spec.Rules[0].TargetModels)
+}
+
+func TestConvertHTTPRouteErrors(t *testing.T) {
+	backends := []gatewayv1.HTTPBackendRef{modelServerBackendRef("llama", 1)}
+	tests := []struct {
+		name   string
+		rule   gatewayv1.HTTPRouteRule
+		reason gatewayv1.RouteConditionReason
+	}{
+		{
+			name: "filters",
+			rule: gatewayv1.HTTPRouteRule{
+				Matches:     []gatewayv1.HTTPRouteMatch{modelHeaderMatch("llama")},
+				Filters:     []gatewayv1.HTTPRouteFilter{{Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier}},
+				BackendRefs: backends,
+			},
+			reason: gatewayv1.RouteReasonIncompatibleFilters,
+		},
+		{
+			name: "no model header",
+			rule: gatewayv1.HTTPRouteRule{
+				Matches:     []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Value: ptr.To("/")}}},
+				BackendRefs: backends,
+			},
+			reason: gatewayv1.RouteReasonUnsupportedValue,
+		},
+		{
+			name: "regular expression model header",
+			rule: gatewayv1.HTTPRouteRule{
+				Matches: []gatewayv1.HTTPRouteMatch{{Headers: []gatewayv1.HTTPHeaderMatch{{
+					Type: ptr.To(gatewayv1.HeaderMatchRegularExpression), Name: GatewayModelNameHeader, Value: "llama.*",
+				}}}},
+				BackendRefs: backends,
+			},
+			reason: gatewayv1.RouteReasonUnsupportedValue,
+		},
+		{
+			name: "method",
+			rule: gatewayv1.HTTPRouteRule{
+				Matches: []gatewayv1.HTTPRouteMatch{{
+					Headers: modelHeaderMatch("llama").Headers,
+					Method:  ptr.To(gatewayv1.HTTPMethodPost),
+				}},
+				BackendRefs: backends,
+			},
+			reason: gatewayv1.RouteReasonUnsupportedValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, routeErr := convertHTTPRoute(newTestHTTPRoute("inference", tt.rule))
+			require.NotNil(t, routeErr)
+			assert.Equal(t, tt.reason, routeErr.reason)
+		})
+	}
+}
+
+func TestHTTPRouteController(t *testing.T) {
+	route := newTestHTTPRoute("inference", gatewayv1.HTTPRouteRule{
+		Matches:     []gatewayv1.HTTPRouteMatch{modelHeaderMatch("llama"), modelHeaderMatch("qwen")},
+		BackendRefs: []gatewayv1.HTTPBackendRef{modelServerBackendRef("llama", 1)},
+	})
+	other := newTestHTTPRoute("web", gatewayv1.HTTPRouteRule{
+		Matches:     []gatewayv1.HTTPRouteMatch{modelHeaderMatch("mistral")},
+		BackendRefs: []gatewayv1.HTTPBackendRef{modelServerBackendRef("mistral", 1)},
+	})
+	other.Name = "web"
+	gatewayClient := newTestGatewayClient(t, route, other)
+	gatewayInformerFactory := gatewayinformers.NewSharedInformerFactory(gatewayClient, 0)
+	store := datastore.New()
+	controller := NewHTTPRouteController(gatewayInformerFactory, store)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	gatewayInformerFactory.Start(stop)
+	gatewayInformerFactory.WaitForCacheSync(stop)
+
+	require.NoError(t, controller.syncHandler("default/llama"))
+	require.NoError(t, controller.syncHandler("default/web"))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	server, _, _, err := store.MatchModelServer("llama", req)
+	require.NoError(t, err)
+	assert.Equal(t, "llama", server.Name)
+	_, _, _, err = store.MatchModelServer("qwen", req)
+	assert.NoError(t, err)
+	// The HTTPRoute attached to the Gateway of another controller is ignored
+	_, _, _, err = store.MatchModelServer("mistral", req)
+	assert.Error(t, err)
+
+	// The model no longer matched is removed
+	updated := route.DeepCopy()
+	updated.Spec.Rules[0].Matches = updated.Spec.Rules[0].Matches[:1]
+	require.NoError(t, gatewayInformerFactory.Gateway().V1().HTTPRoutes().Informer().GetStore().Update(updated))
+	require.NoError(t, controller.syncHandler("default/llama"))
+	_, _, _, err = store.MatchModelServer("qwen", req)
+	assert.Error(t, err)
+
+	require.NoError(t, gatewayInformerFactory.Gateway().V1().HTTPRoutes().Informer().GetStore().Delete(updated))
+	require.NoError(t, controller.syncHandler("default/llama"))
+	_, _, _, err = store.MatchModelServer("llama", req)
+	assert.Error(t, err)
+}
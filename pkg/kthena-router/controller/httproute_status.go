@@ -0,0 +1,162 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayclientset "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+	gatewayinformers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
+	gatewaylisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1"
+
+	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
+	listerv1alpha1 "github.com/volcano-sh/kthena/client-go/listers/networking/v1alpha1"
+)
+
+// HTTPRouteStatusUpdater sets the Accepted and ResolvedRefs conditions of the HTTPRoutes attached to the
+// Gateways served by the router, leaving the parents of the other controllers untouched.
+type HTTPRouteStatusUpdater struct {
+	gatewayClient     gatewayclientset.Interface
+	httpRouteLister   gatewaylisters.HTTPRouteLister
+	parents           *gatewayParents
+	modelServerLister listerv1alpha1.ModelServerLister
+}
+
+func NewHTTPRouteStatusUpdater(
+	gatewayClient gatewayclientset.Interface,
+	gatewayInformerFactory gatewayinformers.SharedInformerFactory,
+	kthenaInformerFactory informersv1alpha1.SharedInformerFactory,
+) *HTTPRouteStatusUpdater {
+	return &HTTPRouteStatusUpdater{
+		gatewayClient:     gatewayClient,
+		httpRouteLister:   gatewayInformerFactory.Gateway().V1().HTTPRoutes().Lister(),
+		parents:           newGatewayParents(gatewayInformerFactory),
+		modelServerLister: kthenaInformerFactory.Networking().V1alpha1().ModelServers().Lister(),
+	}
+}
+
+func (u *HTTPRouteStatusUpdater) Run(stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	wait.Until(u.syncAll, statusSyncInterval, stopCh)
+}
+
+func (u *HTTPRouteStatusUpdater) syncAll() {
+	routes, err := u.httpRouteLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list HTTPRoutes: %v", err)
+		return
+	}
+	for _, route := range routes {
+		if err := u.updateStatus(route); err != nil {
+			klog.Errorf("failed to update status of HTTPRoute %s/%s: %v", route.Namespace, route.Name, err)
+		}
+	}
+}
+
+func (u *HTTPRouteStatusUpdater) updateStatus(route *gatewayv1.HTTPRoute) error {
+	var parents []gatewayv1.RouteParentStatus
+	for _, parent := range route.Status.Parents {
+		if parent.ControllerName != GatewayControllerName {
+			parents = append(parents, parent)
+		}
+	}
+
+	_, routeErr := convertHTTPRoute(route)
+	resolvedRefs := u.resolvedRefsCondition(route)
+	for _, ref := range route.Spec.ParentRefs {
+		served, allowed := u.parents.resolve(route, ref)
+		if !served {
+			continue
+		}
+		accepted := metav1.Condition{
+			Type:               string(gatewayv1.RouteConditionAccepted),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: route.Generation,
+			Reason:             string(gatewayv1.RouteReasonAccepted),
+			Message:            "Route is programmed in the kthena-router",
+		}
+		switch {
+		case !allowed:
+			accepted.Status = metav1.ConditionFalse
+			accepted.Reason = string(gatewayv1.RouteReasonNotAllowedByListeners)
+			accepted.Message = "No listener of the Gateway allows the routes of the namespace"
+		case routeErr != nil:
+			accepted.Status = metav1.ConditionFalse
+			accepted.Reason = string(routeErr.reason)
+			accepted.Message = routeErr.message
+		}
+
+		status := gatewayv1.RouteParentStatus{ParentRef: ref, ControllerName: GatewayControllerName}
+		// The conditions are updated in place, so that their transition times are kept
+		for _, previous := range route.Status.Parents {
+			if previous.ControllerName == GatewayControllerName && equality.Semantic.DeepEqual(previous.ParentRef, ref) {
+				status.Conditions = append(status.Conditions, previous.Conditions...)
+			}
+		}
+		meta.SetStatusCondition(&status.Conditions, accepted)
+		meta.SetStatusCondition(&status.Conditions, resolvedRefs)
+		parents = append(parents, status)
+	}
+
+	if equality.Semantic.DeepEqual(route.Status.Parents, parents) {
+		return nil
+	}
+	newRoute := route.DeepCopy()
+	newRoute.Status.Parents = parents
+	_, err := u.gatewayClient.GatewayV1().HTTPRoutes(route.Namespace).UpdateStatus(context.TODO(), newRoute, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	klog.V(2).Infof("HTTPRoute %s/%s status updated", route.Namespace, route.Name)
+	return nil
+}
+
+// resolvedRefsCondition reports the first backendRef of the HTTPRoute which is not an existing ModelServer.
+func (u *HTTPRouteStatusUpdater) resolvedRefsCondition(route *gatewayv1.HTTPRoute) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               string(gatewayv1.RouteConditionResolvedRefs),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: route.Generation,
+		Reason:             string(gatewayv1.RouteReasonResolvedRefs),
+		Message:            "All the backendRefs are resolved",
+	}
+	for _, rule := range route.Spec.Rules {
+		for _, ref := range rule.BackendRefs {
+			reason, message := checkBackendRef(route.Namespace, ref.BackendObjectReference)
+			if reason == "" {
+				if _, err := u.modelServerLister.ModelServers(route.Namespace).Get(string(ref.Name)); err != nil {
+					reason, message = gatewayv1.RouteReasonBackendNotFound, "ModelServer "+string(ref.Name)+" is not found"
+				}
+			}
+			if reason != "" {
+				condition.Status = metav1.ConditionFalse
+				condition.Reason = string(reason)
+				condition.Message = message
+				return condition
+			}
+		}
+	}
+	return condition
+}
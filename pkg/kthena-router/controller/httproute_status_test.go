@@ -0,0 +1,95 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayinformers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+func TestHTTPRouteStatusUpdater(t *testing.T) {
+	route := newTestHTTPRoute("inference", gatewayv1.HTTPRouteRule{
+		Matches:     []gatewayv1.HTTPRouteMatch{modelHeaderMatch("llama")},
+		BackendRefs: []gatewayv1.HTTPBackendRef{modelServerBackendRef("llama", 1), modelServerBackendRef("missing", 1)},
+	})
+	route.Spec.ParentRefs = append(route.Spec.ParentRefs, gatewayv1.ParentReference{Name: "web"})
+	otherParent := gatewayv1.RouteParentStatus{
+		ParentRef:      gatewayv1.ParentReference{Name: "web"},
+		ControllerName: "example.com/other",
+		Conditions:     []metav1.Condition{{Type: "Accepted", Status: metav1.ConditionTrue, Reason: "Accepted"}},
+	}
+	route.Status.Parents = []gatewayv1.RouteParentStatus{otherParent}
+	ms := &aiv1alpha1.ModelServer{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "llama"}}
+
+	gatewayClient := newTestGatewayClient(t, route)
+	gatewayInformerFactory := gatewayinformers.NewSharedInformerFactory(gatewayClient, 0)
+	kthenaClient := kthenafake.NewSimpleClientset(ms)
+	kthenaInformerFactory := informersv1alpha1.NewSharedInformerFactory(kthenaClient, 0)
+	updater := NewHTTPRouteStatusUpdater(gatewayClient, gatewayInformerFactory, kthenaInformerFactory)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	gatewayInformerFactory.Start(stop)
+	kthenaInformerFactory.Start(stop)
+	gatewayInformerFactory.WaitForCacheSync(stop)
+	require.True(t, cache.WaitForCacheSync(stop, kthenaInformerFactory.Networking().V1alpha1().ModelServers().Informer().HasSynced))
+
+	updater.syncAll()
+
+	got, err := gatewayClient.GatewayV1().HTTPRoutes("default").Get(context.Background(), "llama", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, got.Status.Parents, 2)
+	assert.Equal(t, otherParent, got.Status.Parents[0])
+	parent := got.Status.Parents[1]
+	assert.Equal(t, GatewayControllerName, parent.ControllerName)
+	assert.Equal(t, gatewayv1.ObjectName("inference"), parent.ParentRef.Name)
+	accepted := meta.FindStatusCondition(parent.Conditions, string(gatewayv1.RouteConditionAccepted))
+	require.NotNil(t, accepted)
+	assert.Equal(t, metav1.ConditionTrue, accepted.Status)
+	assert.Equal(t, int64(3), accepted.ObservedGeneration)
+	resolvedRefs := meta.FindStatusCondition(parent.Conditions, string(gatewayv1.RouteConditionResolvedRefs))
+	require.NotNil(t, resolvedRefs)
+	assert.Equal(t, metav1.ConditionFalse, resolvedRefs.Status)
+	assert.Equal(t, string(gatewayv1.RouteReasonBackendNotFound), resolvedRefs.Reason)
+
+	// An unchanged status is not written again
+	gatewayClient.ClearActions()
+	require.NoError(t, updater.updateStatus(got))
+	assert.Empty(t, gatewayClient.Actions())
+
+	// A route with filters is not accepted
+	got.Spec.Rules[0].Filters = []gatewayv1.HTTPRouteFilter{{Type: gatewayv1.HTTPRouteFilterRequestRedirect}}
+	require.NoError(t, updater.updateStatus(got))
+	got, err = gatewayClient.GatewayV1().HTTPRoutes("default").Get(context.Background(), "llama", metav1.GetOptions{})
+	require.NoError(t, err)
+	accepted = meta.FindStatusCondition(got.Status.Parents[1].Conditions, string(gatewayv1.RouteConditionAccepted))
+	require.NotNil(t, accepted)
+	assert.Equal(t, metav1.ConditionFalse, accepted.Status)
+	assert.Equal(t, string(gatewayv1.RouteReasonIncompatibleFilters), accepted.Reason)
+}
@@ -40,6 +40,7 @@ type ModelRouteController struct {
 
 	workqueue   workqueue.TypedRateLimitingInterface[any]
 	initialSync *atomic.Bool
+	progress    workerProgress
 	store       datastore.Store
 }
 
@@ -88,6 +89,14 @@ func (c *ModelRouteController) HasSynced() bool {
 	return c.initialSync.Load()
 }
 
+// Live returns an error if the controller is stuck processing an item, the datastore is not updated anymore.
+func (c *ModelRouteController) Live() error {
+	if err := c.progress.stalled(stallTimeout); err != nil {
+		return fmt.Errorf("ModelRoute controller is stuck: %w", err)
+	}
+	return nil
+}
+
 func (c *ModelRouteController) runWorker() {
 	for c.processNextWorkItem() {
 	}
@@ -99,6 +108,8 @@ func (c *ModelRouteController) processNextWorkItem() bool {
 		return false
 	}
 	defer c.workqueue.Done(obj)
+	c.progress.start()
+	defer c.progress.done()
 
 	if obj == initialSyncSignal {
 		klog.V(2).Info("initial model routes have been synced")
@@ -0,0 +1,138 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
+	listerv1alpha1 "github.com/volcano-sh/kthena/client-go/listers/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/snapshot"
+)
+
+// ModelRouteSnapshotController records a snapshot of every accepted ModelRoute change. It writes to the
+// API server, so it only runs in the leader router replica, and it can be run again after it stopped.
+type ModelRouteSnapshotController struct {
+	modelRouteInformer cache.SharedIndexInformer
+	modelRouteLister   listerv1alpha1.ModelRouteLister
+	snapshots          *snapshot.Manager
+}
+
+func NewModelRouteSnapshotController(
+	kthenaInformerFactory informersv1alpha1.SharedInformerFactory,
+	snapshots *snapshot.Manager,
+) *ModelRouteSnapshotController {
+	modelRouteInformer := kthenaInformerFactory.Networking().V1alpha1().ModelRoutes()
+	return &ModelRouteSnapshotController{
+		modelRouteInformer: modelRouteInformer.Informer(),
+		modelRouteLister:   modelRouteInformer.Lister(),
+		snapshots:          snapshots,
+	}
+}
+
+// Run records the snapshots until the context is done. The event handler is registered on every run,
+// so that the ModelRoutes changed while another replica was the leader are replayed.
+func (c *ModelRouteSnapshotController) Run(ctx context.Context) error {
+	defer utilruntime.HandleCrash()
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+	defer queue.ShutDown()
+
+	enqueue := func(obj interface{}) {
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+		queue.Add(key)
+	}
+	registration, err := c.modelRouteInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			enqueue(new)
+		},
+		DeleteFunc: enqueue,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.modelRouteInformer.RemoveEventHandler(registration); err != nil {
+			klog.Errorf("failed to remove the ModelRoute snapshot event handler: %v", err)
+		}
+	}()
+
+	if ok := cache.WaitForCacheSync(ctx.Done(), registration.HasSynced); !ok {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		for c.processNextWorkItem(ctx, queue) {
+		}
+	}, time.Second)
+
+	<-ctx.Done()
+	return nil
+}
+
+func (c *ModelRouteSnapshotController) processNextWorkItem(ctx context.Context, queue workqueue.TypedRateLimitingInterface[string]) bool {
+	key, shutdown := queue.Get()
+	if shutdown {
+		return false
+	}
+	defer queue.Done(key)
+
+	if err := c.syncHandler(ctx, key); err != nil {
+		if queue.NumRequeues(key) < maxRetries {
+			klog.V(2).Infof("error recording snapshot of modelRoute %q: %s, requeuing", key, err.Error())
+			queue.AddRateLimited(key)
+			return true
+		}
+		klog.V(2).Infof("giving up on recording snapshot of modelRoute %q after %d retries: %s", key, maxRetries, err)
+	}
+	queue.Forget(key)
+	return true
+}
+
+func (c *ModelRouteSnapshotController) syncHandler(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	mr, err := c.modelRouteLister.ModelRoutes(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		c.snapshots.Forget(namespace, name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return c.snapshots.Record(ctx, mr)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/snapshot"
+)
+
+func TestModelRouteSnapshotController(t *testing.T) {
+	mr := &aiv1alpha1.ModelRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route", Generation: 1},
+		Spec:       aiv1alpha1.ModelRouteSpec{ModelName: "llama"},
+	}
+	kubeClient := kubefake.NewSimpleClientset()
+	kthenaClient := kthenafake.NewSimpleClientset(mr)
+	kthenaInformerFactory := informersv1alpha1.NewSharedInformerFactory(kthenaClient, 0)
+	c := NewModelRouteSnapshotController(kthenaInformerFactory, snapshot.NewManager(kubeClient, kthenaClient, 0))
+
+	stop := make(chan struct{})
+	defer close(stop)
+	kthenaInformerFactory.Start(stop)
+
+	hasSnapshot := func(version int64) func() bool {
+		return func() bool {
+			_, err := kubeClient.CoreV1().ConfigMaps("default").Get(context.Background(), snapshot.Name("route", version), metav1.GetOptions{})
+			return err == nil
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- c.Run(ctx)
+	}()
+	assert.Eventually(t, hasSnapshot(1), 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	// The changes made while the controller was stopped, e.g. while another replica was the leader, are recorded
+	// once it runs again
+	mr = mr.DeepCopy()
+	mr.Generation = 2
+	_, err := kthenaClient.NetworkingV1alpha1().ModelRoutes("default").Update(context.Background(), mr, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		done <- c.Run(ctx)
+	}()
+	assert.Eventually(t, hasSnapshot(2), 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -38,6 +38,7 @@ import (
 	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
 	listerv1alpha1 "github.com/volcano-sh/kthena/client-go/listers/networking/v1alpha1"
 	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/utils"
 )
@@ -48,6 +49,7 @@ type ResourceType string
 const (
 	ResourceTypeModelServer ResourceType = "ModelServer"
 	ResourceTypePod         ResourceType = "Pod"
+	ResourceTypeNode        ResourceType = "Node"
 )
 
 // QueueItem represents an item in the work queue
@@ -59,6 +61,7 @@ type QueueItem struct {
 type ModelServerController struct {
 	modelServerLister listerv1alpha1.ModelServerLister
 	podLister         corelisters.PodLister
+	nodeLister        corelisters.NodeLister
 
 	modelServerSynced cache.InformerSynced
 	podSynced         cache.InformerSynced
@@ -66,9 +69,11 @@ type ModelServerController struct {
 	// Event handler registrations
 	modelServerRegistration cache.ResourceEventHandlerRegistration
 	podRegistration         cache.ResourceEventHandlerRegistration
+	nodeRegistration        cache.ResourceEventHandlerRegistration
 
 	workqueue   workqueue.TypedRateLimitingInterface[QueueItem]
 	initialSync *atomic.Bool
+	progress    workerProgress
 	store       datastore.Store
 }
 
@@ -79,10 +84,12 @@ func NewModelServerController(
 ) *ModelServerController {
 	modelServerInformer := kthenaInformerFactory.Networking().V1alpha1().ModelServers()
 	podInformer := kubeInformerFactory.Core().V1().Pods()
+	nodeInformer := kubeInformerFactory.Core().V1().Nodes()
 
 	controller := &ModelServerController{
 		modelServerLister: modelServerInformer.Lister(),
 		podLister:         podInformer.Lister(),
+		nodeLister:        nodeInformer.Lister(),
 		modelServerSynced: modelServerInformer.Informer().HasSynced,
 		podSynced:         podInformer.Informer().HasSynced,
 		workqueue:         workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[QueueItem]()),
@@ -108,6 +115,20 @@ func NewModelServerController(
 		DeleteFunc: controller.enqueuePod,
 	})
 
+	// Register Node event handlers, only the accelerator of the nodes is kept
+	controller.nodeRegistration, _ = nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueNode,
+		UpdateFunc: func(old, new interface{}) {
+			oldNode, ok := old.(*corev1.Node)
+			newNode, ok2 := new.(*corev1.Node)
+			if ok && ok2 && datastore.AcceleratorOf(oldNode) == datastore.AcceleratorOf(newNode) {
+				return
+			}
+			controller.enqueueNode(new)
+		},
+		DeleteFunc: controller.enqueueNode,
+	})
+
 	return controller
 }
 
@@ -115,7 +136,7 @@ func (c *ModelServerController) Run(stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
 
-	if ok := cache.WaitForCacheSync(stopCh, c.modelServerRegistration.HasSynced, c.podRegistration.HasSynced); !ok {
+	if ok := cache.WaitForCacheSync(stopCh, c.modelServerRegistration.HasSynced, c.podRegistration.HasSynced, c.nodeRegistration.HasSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 	// add initialSync signal
@@ -131,6 +152,14 @@ func (c *ModelServerController) HasSynced() bool {
 	return c.initialSync.Load()
 }
 
+// Live returns an error if the controller is stuck processing an item, the datastore is not updated anymore.
+func (c *ModelServerController) Live() error {
+	if err := c.progress.stalled(stallTimeout); err != nil {
+		return fmt.Errorf("ModelServer controller is stuck: %w", err)
+	}
+	return nil
+}
+
 func (c *ModelServerController) runWorker() {
 	for c.processNextWorkItem() {
 	}
@@ -142,6 +171,8 @@ func (c *ModelServerController) processNextWorkItem() bool {
 		return false
 	}
 	defer c.workqueue.Done(obj)
+	c.progress.start()
+	defer c.progress.done()
 
 	// Handle initial sync signal
 	if obj.ResourceType == "" && obj.Key == "" {
@@ -157,6 +188,8 @@ func (c *ModelServerController) processNextWorkItem() bool {
 		err = c.syncModelServerHandler(obj.Key)
 	case ResourceTypePod:
 		err = c.syncPodHandler(obj.Key)
+	case ResourceTypeNode:
+		err = c.syncNodeHandler(obj.Key)
 	default:
 		c.workqueue.Forget(obj)
 		utilruntime.HandleError(fmt.Errorf("unexpected resource type in workqueue: %s", obj.ResourceType))
@@ -203,7 +236,7 @@ func (c *ModelServerController) syncModelServerHandler(key string) error {
 
 	pods := sets.NewWithLength[types.NamespacedName](len(podList))
 	for _, pod := range podList {
-		if isPodReady(pod) {
+		if isPodRoutable(pod) {
 			pods.Insert(utils.GetNamespaceName(pod))
 		}
 	}
@@ -228,7 +261,7 @@ func (c *ModelServerController) syncPodHandler(key string) error {
 		return err
 	}
 
-	if !isPodReady(pod) {
+	if !isPodRoutable(pod) {
 		_ = c.store.DeletePod(types.NamespacedName{Namespace: namespace, Name: name})
 		return nil
 	}
@@ -258,6 +291,19 @@ func (c *ModelServerController) syncPodHandler(key string) error {
 	return nil
 }
 
+func (c *ModelServerController) syncNodeHandler(name string) error {
+	node, err := c.nodeLister.Get(name)
+	if errors.IsNotFound(err) {
+		_ = c.store.DeleteNode(name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.store.AddOrUpdateNode(node)
+}
+
 func (c *ModelServerController) enqueueModelServer(obj interface{}) {
 	var key string
 	var err error
@@ -284,6 +330,23 @@ func (c *ModelServerController) enqueuePod(obj interface{}) {
 	})
 }
 
+func (c *ModelServerController) enqueueNode(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(QueueItem{
+		ResourceType: ResourceTypeNode,
+		Key:          key,
+	})
+}
+
+// isPodRoutable checks if the pod is ready and does not belong to a standby ServingGroup of a ModelServing.
+func isPodRoutable(pod *corev1.Pod) bool {
+	return isPodReady(pod) && pod.Labels[workloadv1alpha1.StandbyLabelKey] != "true"
+}
+
 // isPodReady checks if the pod is in a running state and has a PodReady condition set to true.
 func isPodReady(pod *corev1.Pod) bool {
 	if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
@@ -35,6 +35,7 @@ import (
 	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
 	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
 	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/backend"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/utils"
@@ -496,6 +497,96 @@ func TestModelServerController_PodLifecycle(t *testing.T) {
 	})
 }
 
+func TestModelServerController_NodeAccelerator(t *testing.T) {
+	patch := setupMockBackend()
+	defer patch.Reset()
+
+	kubeClient := kubefake.NewSimpleClientset()
+	kthenaClient := kthenafake.NewSimpleClientset()
+
+	ms := &aiv1alpha1.ModelServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-modelserver-nodes",
+		},
+		Spec: aiv1alpha1.ModelServerSpec{
+			InferenceEngine: aiv1alpha1.VLLM,
+			WorkloadSelector: &aiv1alpha1.WorkloadSelector{
+				MatchLabels: map[string]string{
+					"app": "test-model-nodes",
+				},
+			},
+		},
+	}
+	_, err := kthenaClient.NetworkingV1alpha1().ModelServers("default").Create(
+		context.Background(), ms, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	kthenaInformerFactory := informersv1alpha1.NewSharedInformerFactory(kthenaClient, 0)
+	store := datastore.New()
+	controller := NewModelServerController(
+		kthenaInformerFactory,
+		kubeInformerFactory,
+		store,
+	)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go controller.Run(stop)
+
+	kthenaInformerFactory.Start(stop)
+	kubeInformerFactory.Start(stop)
+	waitForCacheSync(t, 5*time.Second, controller.modelServerSynced, controller.podSynced)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod-h100",
+			Labels: map[string]string{
+				"app": "test-model-nodes",
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: "gpu-node"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{
+					Type:   corev1.PodReady,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+	_, err = kubeClient.CoreV1().Pods("default").Create(context.Background(), pod, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	acceleratorIs := func(accelerator string) func() bool {
+		return func() bool {
+			podInfo := store.GetPodInfo(utils.GetNamespaceName(pod))
+			return podInfo != nil && podInfo.Accelerator() == accelerator
+		}
+	}
+	assert.True(t, waitForObjectInCache(t, 2*time.Second, acceleratorIs("")), "Pod should be found in store without accelerator")
+
+	// The pod gets the accelerator of its node once the node is known
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "gpu-node",
+			Labels: map[string]string{"nvidia.com/gpu.product": "NVIDIA-H100-80GB-HBM3"},
+		},
+	}
+	_, err = kubeClient.CoreV1().Nodes().Create(context.Background(), node, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	assert.True(t, waitForObjectInCache(t, 2*time.Second, acceleratorIs("NVIDIA-H100-80GB-HBM3")), "Pod should get the accelerator of its node")
+
+	// Relabeling the node changes the accelerator of its pods
+	node.Labels[datastore.AcceleratorLabelKey] = "H100-pool"
+	_, err = kubeClient.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.True(t, waitForObjectInCache(t, 2*time.Second, acceleratorIs("H100-pool")), "Pod should get the new accelerator of its node")
+}
+
 func TestModelServerController_ErrorHandling(t *testing.T) {
 	// Create fake clients
 	kubeClient := kubefake.NewSimpleClientset()
@@ -943,3 +1034,48 @@ func setupMockBackend() *gomonkey.Patches {
 	})
 	return patch
 }
+
+func TestIsPodRoutable(t *testing.T) {
+	readyStatus := corev1.PodStatus{
+		Phase: corev1.PodRunning,
+		Conditions: []corev1.PodCondition{
+			{
+				Type:   corev1.PodReady,
+				Status: corev1.ConditionTrue,
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		pod    *corev1.Pod
+		expect bool
+	}{
+		{
+			name:   "ready pod",
+			pod:    &corev1.Pod{Status: readyStatus},
+			expect: true,
+		},
+		{
+			name:   "pending pod",
+			pod:    &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending}},
+			expect: false,
+		},
+		{
+			name: "ready pod of a standby ServingGroup",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{workloadv1alpha1.StandbyLabelKey: "true"},
+				},
+				Status: readyStatus,
+			},
+			expect: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expect, isPodRoutable(tt.pod))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
+	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
+	listerv1alpha1 "github.com/volcano-sh/kthena/client-go/listers/networking/v1alpha1"
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/tokenization"
+)
+
+// statusSyncInterval is how often the tokenizer health observed by the router is written to the ModelServer status.
+const statusSyncInterval = 10 * time.Second
+
+// ModelServerStatusUpdater reflects the tokenizer health of the ModelServers observed by the
+// scheduler into the TokenizerAvailable condition of their status.
+type ModelServerStatusUpdater struct {
+	kthenaClient      clientset.Interface
+	modelServerLister listerv1alpha1.ModelServerLister
+	healthTracker     *tokenization.HealthTracker
+}
+
+func NewModelServerStatusUpdater(
+	kthenaClient clientset.Interface,
+	kthenaInformerFactory informersv1alpha1.SharedInformerFactory,
+	healthTracker *tokenization.HealthTracker,
+) *ModelServerStatusUpdater {
+	return &ModelServerStatusUpdater{
+		kthenaClient:      kthenaClient,
+		modelServerLister: kthenaInformerFactory.Networking().V1alpha1().ModelServers().Lister(),
+		healthTracker:     healthTracker,
+	}
+}
+
+func (u *ModelServerStatusUpdater) Run(stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	wait.Until(u.syncAll, statusSyncInterval, stopCh)
+}
+
+func (u *ModelServerStatusUpdater) syncAll() {
+	for _, key := range u.healthTracker.List() {
+		ms, err := u.modelServerLister.ModelServers(key.Namespace).Get(key.Name)
+		if errors.IsNotFound(err) {
+			u.healthTracker.Delete(key)
+			continue
+		}
+		if err != nil {
+			klog.Errorf("failed to get ModelServer %s: %v", key, err)
+			continue
+		}
+		health, exists := u.healthTracker.Get(key)
+		if !exists {
+			continue
+		}
+		if err := u.updateStatus(ms, health); err != nil {
+			klog.Errorf("failed to update status of ModelServer %s: %v", key, err)
+		}
+	}
+}
+
+func (u *ModelServerStatusUpdater) updateStatus(ms *aiv1alpha1.ModelServer, health tokenization.TokenizerHealth) error {
+	condition := tokenizerCondition(ms, health)
+	current := meta.FindStatusCondition(ms.Status.Conditions, condition.Type)
+	if current != nil && current.Status == condition.Status && current.Reason == condition.Reason {
+		return nil
+	}
+
+	newMS := ms.DeepCopy()
+	meta.SetStatusCondition(&newMS.Status.Conditions, condition)
+	_, err := u.kthenaClient.NetworkingV1alpha1().ModelServers(ms.Namespace).UpdateStatus(context.TODO(), newMS, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	klog.V(2).Infof("ModelServer %s/%s condition %s is %s: %s", ms.Namespace, ms.Name, condition.Type, condition.Status, condition.Message)
+	return nil
+}
+
+func tokenizerCondition(ms *aiv1alpha1.ModelServer, health tokenization.TokenizerHealth) metav1.Condition {
+	status := metav1.ConditionTrue
+	if health.Degraded {
+		status = metav1.ConditionFalse
+	}
+	return metav1.Condition{
+		Type:               string(aiv1alpha1.ModelServerTokenizerAvailable),
+		Status:             status,
+		ObservedGeneration: ms.Generation,
+		LastTransitionTime: metav1.NewTime(health.Since),
+		Reason:             health.Reason,
+		Message:            health.Message,
+	}
+}
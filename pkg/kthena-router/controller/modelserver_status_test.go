@@ -0,0 +1,82 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/tokenization"
+)
+
+func TestModelServerStatusUpdater(t *testing.T) {
+	ms := &aiv1alpha1.ModelServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "default",
+			Name:       "test-modelserver",
+			Generation: 2,
+		},
+	}
+	kthenaClient := kthenafake.NewSimpleClientset(ms)
+	kthenaInformerFactory := informersv1alpha1.NewSharedInformerFactory(kthenaClient, 0)
+	tracker := tokenization.NewHealthTracker()
+	updater := NewModelServerStatusUpdater(kthenaClient, kthenaInformerFactory, tracker)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	kthenaInformerFactory.Start(stop)
+	informer := kthenaInformerFactory.Networking().V1alpha1().ModelServers().Informer()
+	require.True(t, cache.WaitForCacheSync(stop, informer.HasSynced))
+
+	key := types.NamespacedName{Namespace: "default", Name: "test-modelserver"}
+	deleted := types.NamespacedName{Namespace: "default", Name: "deleted"}
+	tracker.ReportFailure([]types.NamespacedName{key, deleted}, tokenization.ReasonTokenizerUnavailable, "no tokenizer available for model test")
+	updater.syncAll()
+
+	got, err := kthenaClient.NetworkingV1alpha1().ModelServers("default").Get(context.Background(), "test-modelserver", metav1.GetOptions{})
+	require.NoError(t, err)
+	condition := meta.FindStatusCondition(got.Status.Conditions, string(aiv1alpha1.ModelServerTokenizerAvailable))
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, tokenization.ReasonTokenizerUnavailable, condition.Reason)
+	assert.Equal(t, int64(2), condition.ObservedGeneration)
+
+	// The deleted ModelServer is forgotten
+	_, exists := tracker.Get(deleted)
+	assert.False(t, exists)
+
+	tracker.ReportSuccess([]types.NamespacedName{key})
+	health, _ := tracker.Get(key)
+	require.NoError(t, updater.updateStatus(got, health))
+
+	got, err = kthenaClient.NetworkingV1alpha1().ModelServers("default").Get(context.Background(), "test-modelserver", metav1.GetOptions{})
+	require.NoError(t, err)
+	condition = meta.FindStatusCondition(got.Status.Conditions, string(aiv1alpha1.ModelServerTokenizerAvailable))
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, tokenization.ReasonTokenizerReady, condition.Reason)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// stallTimeout is how long the worker of a controller may process an item before the controller is considered stuck.
+const stallTimeout = 5 * time.Minute
+
+// workerProgress tracks the item being processed by the single worker of a controller.
+type workerProgress struct {
+	// started is when the worker started processing its current item in unix nanoseconds, zero when it is idle.
+	started atomic.Int64
+}
+
+func (p *workerProgress) start() {
+	p.started.Store(time.Now().UnixNano())
+}
+
+func (p *workerProgress) done() {
+	p.started.Store(0)
+}
+
+// stalled returns an error if the worker has been processing the same item for longer than the timeout.
+func (p *workerProgress) stalled(timeout time.Duration) error {
+	started := p.started.Load()
+	if started == 0 {
+		return nil
+	}
+	if elapsed := time.Since(time.Unix(0, started)); elapsed > timeout {
+		return fmt.Errorf("the worker has been processing the same item for %s", elapsed.Round(time.Second))
+	}
+	return nil
+}
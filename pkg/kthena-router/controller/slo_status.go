@@ -0,0 +1,196 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
+	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
+	listerv1alpha1 "github.com/volcano-sh/kthena/client-go/listers/networking/v1alpha1"
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+// sloMinRequests is the number of requests in the window under which an error budget is not reported exhausted,
+// so that a few failed requests to an idle model server do not flip its condition.
+const sloMinRequests = 100
+
+// Reasons of the ErrorBudgetAvailable condition.
+const (
+	ReasonWithinErrorBudget    = "WithinErrorBudget"
+	ReasonErrorBudgetExhausted = "ErrorBudgetExhausted"
+)
+
+// SLOStatusUpdater reflects the error budgets of the ModelServers measured by the router into the ErrorBudgetAvailable
+// condition of their status and, when updateModelServings is set, of the ModelServings whose pods serve them.
+type SLOStatusUpdater struct {
+	kthenaClient        clientset.Interface
+	modelServerLister   listerv1alpha1.ModelServerLister
+	store               datastore.Store
+	updateModelServings bool
+	// modelServingConditions are the last conditions written to the ModelServings, which are not watched.
+	modelServingConditions map[types.NamespacedName]metav1.Condition
+}
+
+func NewSLOStatusUpdater(
+	kthenaClient clientset.Interface,
+	kthenaInformerFactory informersv1alpha1.SharedInformerFactory,
+	store datastore.Store,
+	updateModelServings bool,
+) *SLOStatusUpdater {
+	return &SLOStatusUpdater{
+		kthenaClient:           kthenaClient,
+		modelServerLister:      kthenaInformerFactory.Networking().V1alpha1().ModelServers().Lister(),
+		store:                  store,
+		updateModelServings:    updateModelServings,
+		modelServingConditions: make(map[types.NamespacedName]metav1.Condition),
+	}
+}
+
+func (u *SLOStatusUpdater) Run(stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	wait.Until(u.syncAll, statusSyncInterval, stopCh)
+}
+
+func (u *SLOStatusUpdater) syncAll() {
+	for key := range u.store.GetAllModelServers() {
+		statuses := u.store.GetSLOStatus(key)
+		if len(statuses) == 0 {
+			continue
+		}
+		ms, err := u.modelServerLister.ModelServers(key.Namespace).Get(key.Name)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			klog.Errorf("failed to get ModelServer %s: %v", key, err)
+			continue
+		}
+		condition := errorBudgetCondition(ms.Generation, statuses)
+		if err := u.updateStatus(ms, condition); err != nil {
+			klog.Errorf("failed to update status of ModelServer %s: %v", key, err)
+		}
+		if !u.updateModelServings {
+			continue
+		}
+		for _, name := range u.modelServingsOf(key) {
+			if err := u.updateModelServingStatus(name, condition); err != nil {
+				klog.Errorf("failed to update status of ModelServing %s: %v", name, err)
+			}
+		}
+	}
+}
+
+func (u *SLOStatusUpdater) updateStatus(ms *aiv1alpha1.ModelServer, condition metav1.Condition) error {
+	if sameCondition(meta.FindStatusCondition(ms.Status.Conditions, condition.Type), condition) {
+		return nil
+	}
+	newMS := ms.DeepCopy()
+	meta.SetStatusCondition(&newMS.Status.Conditions, condition)
+	_, err := u.kthenaClient.NetworkingV1alpha1().ModelServers(ms.Namespace).UpdateStatus(context.TODO(), newMS, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	klog.V(2).Infof("ModelServer %s/%s condition %s is %s: %s", ms.Namespace, ms.Name, condition.Type, condition.Status, condition.Message)
+	return nil
+}
+
+// modelServingsOf returns the ModelServings of the pods of the ModelServer.
+func (u *SLOStatusUpdater) modelServingsOf(key types.NamespacedName) []types.NamespacedName {
+	pods, err := u.store.GetPodsByModelServer(key)
+	if err != nil {
+		return nil
+	}
+	seen := make(map[types.NamespacedName]struct{})
+	var names []types.NamespacedName
+	for _, pod := range pods {
+		if pod.Pod == nil || pod.Pod.Labels[workloadv1alpha1.ModelServingNameLabelKey] == "" {
+			continue
+		}
+		name := types.NamespacedName{Namespace: pod.Pod.Namespace, Name: pod.Pod.Labels[workloadv1alpha1.ModelServingNameLabelKey]}
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (u *SLOStatusUpdater) updateModelServingStatus(name types.NamespacedName, condition metav1.Condition) error {
+	condition.Type = string(workloadv1alpha1.ModelServingErrorBudgetAvailable)
+	if last, ok := u.modelServingConditions[name]; ok && sameCondition(&last, condition) {
+		return nil
+	}
+	client := u.kthenaClient.WorkloadV1alpha1().ModelServings(name.Namespace)
+	ms, err := client.Get(context.TODO(), name.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	// The generation observed is the one of the ModelServing
+	condition.ObservedGeneration = ms.Generation
+	if !sameCondition(meta.FindStatusCondition(ms.Status.Conditions, condition.Type), condition) {
+		meta.SetStatusCondition(&ms.Status.Conditions, condition)
+		if _, err := client.UpdateStatus(context.TODO(), ms, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		klog.V(2).Infof("ModelServing %s condition %s is %s: %s", name, condition.Type, condition.Status, condition.Message)
+	}
+	u.modelServingConditions[name] = condition
+	return nil
+}
+
+func sameCondition(current *metav1.Condition, condition metav1.Condition) bool {
+	return current != nil && current.Status == condition.Status && current.Reason == condition.Reason && current.Message == condition.Message
+}
+
+// errorBudgetCondition is false when the error budget of one of the objectives is exhausted, once enough
+// requests have been measured in the window.
+func errorBudgetCondition(generation int64, statuses []datastore.SLOStatus) metav1.Condition {
+	var exhausted []string
+	for _, status := range statuses {
+		if status.Total >= sloMinRequests && status.ErrorBudgetRemaining() <= 0 {
+			exhausted = append(exhausted, status.Name)
+		}
+	}
+	condition := metav1.Condition{
+		Type:               string(aiv1alpha1.ModelServerErrorBudgetAvailable),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             ReasonWithinErrorBudget,
+		Message:            "The error budgets of the service level objectives are left",
+	}
+	if len(exhausted) > 0 {
+		sort.Strings(exhausted)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonErrorBudgetExhausted
+		condition.Message = fmt.Sprintf("Error budget exhausted: %s", strings.Join(exhausted, ", "))
+	}
+	return condition
+}
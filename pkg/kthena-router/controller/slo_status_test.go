@@ -0,0 +1,103 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"istio.io/istio/pkg/util/sets"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+func TestSLOStatusUpdater(t *testing.T) {
+	ms := &aiv1alpha1.ModelServer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "llama", Generation: 3},
+		Spec: aiv1alpha1.ModelServerSpec{
+			SLO: &aiv1alpha1.ServiceLevelObjective{
+				Availability: &aiv1alpha1.AvailabilityObjective{Target: "99"},
+			},
+		},
+	}
+	serving := &workloadv1alpha1.ModelServing{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "llama-serving", Generation: 5},
+	}
+	kthenaClient := kthenafake.NewSimpleClientset(ms, serving)
+	kthenaInformerFactory := informersv1alpha1.NewSharedInformerFactory(kthenaClient, 0)
+
+	store := datastore.New()
+	key := types.NamespacedName{Namespace: "default", Name: "llama"}
+	podName := types.NamespacedName{Namespace: "default", Name: "llama-0"}
+	require.NoError(t, store.AddOrUpdateModelServer(ms, sets.New(podName)))
+	require.NoError(t, store.AddOrUpdatePod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "llama-0",
+		Labels:    map[string]string{workloadv1alpha1.ModelServingNameLabelKey: "llama-serving"},
+	}}, []*aiv1alpha1.ModelServer{ms}))
+
+	updater := NewSLOStatusUpdater(kthenaClient, kthenaInformerFactory, store, true)
+	stop := make(chan struct{})
+	defer close(stop)
+	kthenaInformerFactory.Start(stop)
+	informer := kthenaInformerFactory.Networking().V1alpha1().ModelServers().Informer()
+	require.True(t, cache.WaitForCacheSync(stop, informer.HasSynced))
+
+	// Too few requests have been measured to exhaust the budget
+	for i := 0; i < sloMinRequests-1; i++ {
+		store.RecordSLO(key, true, time.Millisecond)
+	}
+	updater.syncAll()
+	got, err := kthenaClient.NetworkingV1alpha1().ModelServers("default").Get(context.Background(), "llama", metav1.GetOptions{})
+	require.NoError(t, err)
+	condition := meta.FindStatusCondition(got.Status.Conditions, string(aiv1alpha1.ModelServerErrorBudgetAvailable))
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonWithinErrorBudget, condition.Reason)
+	assert.Equal(t, int64(3), condition.ObservedGeneration)
+
+	store.RecordSLO(key, true, time.Millisecond)
+	updater.syncAll()
+	got, err = kthenaClient.NetworkingV1alpha1().ModelServers("default").Get(context.Background(), "llama", metav1.GetOptions{})
+	require.NoError(t, err)
+	condition = meta.FindStatusCondition(got.Status.Conditions, string(aiv1alpha1.ModelServerErrorBudgetAvailable))
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonErrorBudgetExhausted, condition.Reason)
+	assert.Equal(t, "Error budget exhausted: availability", condition.Message)
+
+	// The ModelServing of the pods gets the same condition
+	gotServing, err := kthenaClient.WorkloadV1alpha1().ModelServings("default").Get(context.Background(), "llama-serving", metav1.GetOptions{})
+	require.NoError(t, err)
+	condition = meta.FindStatusCondition(gotServing.Status.Conditions, string(workloadv1alpha1.ModelServingErrorBudgetAvailable))
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonErrorBudgetExhausted, condition.Reason)
+	assert.Equal(t, int64(5), condition.ObservedGeneration)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"strings"
+	"unicode"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AcceleratorLabelKey sets the accelerator of the pods of a node, overriding the labels set by the device plugins.
+const AcceleratorLabelKey = "networking.serving.volcano.sh/accelerator"
+
+// acceleratorLabelKeys are the node labels the accelerator is read from, in order: the label set by the
+// administrator, the product labeled by the NVIDIA GPU feature discovery, and the label of the Ascend NPU nodes.
+var acceleratorLabelKeys = []string{
+	AcceleratorLabelKey,
+	"nvidia.com/gpu.product",
+	"accelerator",
+}
+
+// AcceleratorOf returns the accelerator of the pods running on the node, empty when its labels do not tell.
+func AcceleratorOf(node *corev1.Node) string {
+	if node == nil {
+		return ""
+	}
+	for _, key := range acceleratorLabelKeys {
+		if accelerator := node.Labels[key]; accelerator != "" {
+			return accelerator
+		}
+	}
+	return ""
+}
+
+// MatchesAccelerator reports whether the accelerator belongs to the hardware class, ignoring the case. The class is
+// either the accelerator, or one of the words of its name: H100 matches NVIDIA-H100-80GB-HBM3 but A10 does not
+// match A100.
+func MatchesAccelerator(accelerator, class string) bool {
+	if accelerator == "" || class == "" {
+		return false
+	}
+	if strings.EqualFold(accelerator, class) {
+		return true
+	}
+	words := strings.FieldsFunc(accelerator, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if strings.EqualFold(word, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesAnyAccelerator reports whether the accelerator belongs to one of the hardware classes.
+func MatchesAnyAccelerator(accelerator string, classes []string) bool {
+	for _, class := range classes {
+		if MatchesAccelerator(accelerator, class) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+func TestAcceleratorOf(t *testing.T) {
+	node := func(labels map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: labels}}
+	}
+	assert.Equal(t, "", AcceleratorOf(nil))
+	assert.Equal(t, "", AcceleratorOf(node(nil)))
+	assert.Equal(t, "NVIDIA-H100-80GB-HBM3", AcceleratorOf(node(map[string]string{"nvidia.com/gpu.product": "NVIDIA-H100-80GB-HBM3"})))
+	assert.Equal(t, "huawei-Ascend910B", AcceleratorOf(node(map[string]string{"accelerator": "huawei-Ascend910B"})))
+	assert.Equal(t, "H200", AcceleratorOf(node(map[string]string{
+		AcceleratorLabelKey:      "H200",
+		"nvidia.com/gpu.product": "NVIDIA-H100-80GB-HBM3",
+	})), "the label set by the administrator wins")
+}
+
+func TestMatchesAccelerator(t *testing.T) {
+	tests := []struct {
+		accelerator string
+		class       string
+		want        bool
+	}{
+		{accelerator: "NVIDIA-H100-80GB-HBM3", class: "H100", want: true},
+		{accelerator: "NVIDIA-H100-80GB-HBM3", class: "h100", want: true},
+		{accelerator: "NVIDIA-H100-80GB-HBM3", class: "nvidia-h100-80gb-hbm3", want: true},
+		{accelerator: "NVIDIA-A100-SXM4-80GB", class: "A10", want: false},
+		{accelerator: "NVIDIA-A10", class: "A10", want: true},
+		{accelerator: "huawei-Ascend910B", class: "Ascend910B", want: true},
+		{accelerator: "huawei-Ascend910B", class: "Ascend910", want: false},
+		{accelerator: "", class: "H100", want: false},
+		{accelerator: "H100", class: "", want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchesAccelerator(tt.accelerator, tt.class), "%s in %s", tt.accelerator, tt.class)
+	}
+	assert.True(t, MatchesAnyAccelerator("NVIDIA-H100-80GB-HBM3", []string{"A100", "H100"}))
+	assert.False(t, MatchesAnyAccelerator("NVIDIA-H100-80GB-HBM3", nil))
+}
+
+func TestAddOrUpdateNode(t *testing.T) {
+	s := New()
+	ms := &aiv1alpha1.ModelServer{ObjectMeta: metav1.ObjectMeta{Name: "ms", Namespace: "default"}}
+	newPod := func(name, node string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: node},
+		}
+	}
+	h100 := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"nvidia.com/gpu.product": "NVIDIA-H100-80GB-HBM3"}}}
+
+	// The pods added before their node get its accelerator once it is known
+	assert.NoError(t, s.AddOrUpdatePod(newPod("pod-1", "node-1"), []*aiv1alpha1.ModelServer{ms}))
+	pod1 := types.NamespacedName{Namespace: "default", Name: "pod-1"}
+	assert.Equal(t, "", s.GetPodInfo(pod1).Accelerator())
+	assert.NoError(t, s.AddOrUpdateNode(h100))
+	assert.Equal(t, "NVIDIA-H100-80GB-HBM3", s.GetPodInfo(pod1).Accelerator())
+
+	// The pods added after it get it right away, and keep it when they are updated
+	assert.NoError(t, s.AddOrUpdatePod(newPod("pod-2", "node-1"), []*aiv1alpha1.ModelServer{ms}))
+	assert.NoError(t, s.AddOrUpdatePod(newPod("pod-3", "node-2"), []*aiv1alpha1.ModelServer{ms}))
+	assert.NoError(t, s.AddOrUpdatePod(newPod("pod-1", "node-1"), []*aiv1alpha1.ModelServer{ms}))
+	assert.Equal(t, "NVIDIA-H100-80GB-HBM3", s.GetPodInfo(pod1).Accelerator())
+	assert.Equal(t, "NVIDIA-H100-80GB-HBM3", s.GetPodInfo(types.NamespacedName{Namespace: "default", Name: "pod-2"}).Accelerator())
+	assert.Equal(t, "", s.GetPodInfo(types.NamespacedName{Namespace: "default", Name: "pod-3"}).Accelerator())
+
+	// A relabeled node changes the accelerator of its pods
+	unlabeled := h100.DeepCopy()
+	unlabeled.Labels = nil
+	assert.NoError(t, s.AddOrUpdateNode(unlabeled))
+	assert.Equal(t, "", s.GetPodInfo(pod1).Accelerator())
+
+	assert.NoError(t, s.AddOrUpdateNode(h100))
+	assert.NoError(t, s.DeleteNode("node-1"))
+	assert.NoError(t, s.AddOrUpdatePod(newPod("pod-4", "node-1"), []*aiv1alpha1.ModelServer{ms}))
+	assert.Equal(t, "", s.GetPodInfo(types.NamespacedName{Namespace: "default", Name: "pod-4"}).Accelerator())
+}
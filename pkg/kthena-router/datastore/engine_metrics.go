@@ -0,0 +1,188 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"math"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"istio.io/istio/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+var engineMetricLabels = []string{"namespace", "model", "revision"}
+
+var (
+	enginePodsDesc = prometheus.NewDesc("kthena_router_engine_pods",
+		"Number of pods of the model whose engine metrics are aggregated", engineMetricLabels, nil)
+	engineRunningRequestsDesc = prometheus.NewDesc("kthena_router_engine_running_requests",
+		"Requests running in the inference engines of the model, summed across its pods", engineMetricLabels, nil)
+	engineWaitingRequestsDesc = prometheus.NewDesc("kthena_router_engine_waiting_requests",
+		"Requests waiting in the inference engines of the model, summed across its pods", engineMetricLabels, nil)
+	engineKVCacheUsageDesc = prometheus.NewDesc("kthena_router_engine_kv_cache_usage_ratio",
+		"KV-cache usage of the inference engines of the model, averaged across its pods", engineMetricLabels, nil)
+	engineTTFTDesc = prometheus.NewDesc("kthena_router_engine_time_to_first_token_seconds",
+		"Time to first token reported by the inference engines of the model, merged across its pods", engineMetricLabels, nil)
+)
+
+// engineMetricsKey identifies the pods whose engine metrics are aggregated together.
+type engineMetricsKey struct {
+	namespace string
+	model     string
+	revision  string
+}
+
+// engineMetrics are the engine metrics aggregated over the pods of a model revision.
+type engineMetrics struct {
+	pods         sets.Set[types.NamespacedName]
+	running      float64
+	waiting      float64
+	kvCacheUsage float64
+
+	ttftCount   uint64
+	ttftSum     float64
+	ttftBuckets map[float64]uint64
+}
+
+// EngineMetricsCollector exports the metrics scraped from the inference engines by the router, aggregated per model
+// and revision, so that the dashboards do not need to scrape every pod. The series are computed on each collection
+// from the latest scrape of each pod, the ones of the models which are gone disappear with them.
+type EngineMetricsCollector struct {
+	store Store
+}
+
+var _ prometheus.Collector = &EngineMetricsCollector{}
+
+func NewEngineMetricsCollector(store Store) *EngineMetricsCollector {
+	return &EngineMetricsCollector{store: store}
+}
+
+func (c *EngineMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- enginePodsDesc
+	ch <- engineRunningRequestsDesc
+	ch <- engineWaitingRequestsDesc
+	ch <- engineKVCacheUsageDesc
+	ch <- engineTTFTDesc
+}
+
+func (c *EngineMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	for key, m := range c.aggregate() {
+		labels := []string{key.namespace, key.model, key.revision}
+		pods := float64(m.pods.Len())
+		ch <- prometheus.MustNewConstMetric(enginePodsDesc, prometheus.GaugeValue, pods, labels...)
+		ch <- prometheus.MustNewConstMetric(engineRunningRequestsDesc, prometheus.GaugeValue, m.running, labels...)
+		ch <- prometheus.MustNewConstMetric(engineWaitingRequestsDesc, prometheus.GaugeValue, m.waiting, labels...)
+		ch <- prometheus.MustNewConstMetric(engineKVCacheUsageDesc, prometheus.GaugeValue, m.kvCacheUsage/pods, labels...)
+		if m.ttftBuckets != nil {
+			ch <- prometheus.MustNewConstHistogram(engineTTFTDesc, m.ttftCount, m.ttftSum, m.ttftBuckets, labels...)
+		}
+	}
+}
+
+// aggregate sums the metrics of the pods of each model revision. A pod selected by several ModelServers
+// of the same model is counted once, a pod serving several models is counted with the metrics of each model.
+func (c *EngineMetricsCollector) aggregate() map[engineMetricsKey]*engineMetrics {
+	aggregated := make(map[engineMetricsKey]*engineMetrics)
+	for name, ms := range c.store.GetAllModelServers() {
+		model := name.Name
+		if ms.Spec.Model != nil && *ms.Spec.Model != "" {
+			model = *ms.Spec.Model
+		}
+		pods, err := c.store.GetPodsByModelServer(name)
+		if err != nil {
+			continue
+		}
+		for _, pod := range pods {
+			if pod.Pod == nil {
+				continue
+			}
+			key := engineMetricsKey{
+				namespace: name.Namespace,
+				model:     model,
+				revision:  pod.Pod.Labels[workloadv1alpha1.RevisionLabelKey],
+			}
+			m, ok := aggregated[key]
+			if !ok {
+				m = &engineMetrics{pods: sets.New[types.NamespacedName]()}
+				aggregated[key] = m
+			}
+			podName := types.NamespacedName{Namespace: pod.Pod.Namespace, Name: pod.Pod.Name}
+			if m.pods.Contains(podName) {
+				continue
+			}
+			m.pods.Insert(podName)
+			m.add(pod, model)
+		}
+	}
+	return aggregated
+}
+
+// add adds the metrics of the model on the pod, which are the metrics of the pod unless it serves several models.
+func (m *engineMetrics) add(pod *PodInfo, model string) {
+	pod.mutex.RLock()
+	defer pod.mutex.RUnlock()
+	if metrics, ok := pod.modelMetrics[model]; ok {
+		m.running += metrics.RequestRunningNum
+		m.waiting += metrics.RequestWaitingNum
+		m.kvCacheUsage += metrics.GPUCacheUsage
+		if metrics.timeToFirstToken != nil {
+			m.addTTFT(metrics.timeToFirstToken)
+		}
+		return
+	}
+	m.running += pod.RequestRunningNum
+	m.waiting += pod.RequestWaitingNum
+	m.kvCacheUsage += pod.GPUCacheUsage
+	if pod.TimeToFirstToken != nil {
+		m.addTTFT(pod.TimeToFirstToken)
+	}
+}
+
+// addTTFT merges the histogram of a pod. The pods of a model run the same engine, a pod whose buckets differ
+// from the ones merged so far can not be merged and is left out.
+func (m *engineMetrics) addTTFT(h *dto.Histogram) {
+	buckets := make(map[float64]uint64, len(h.GetBucket()))
+	for _, b := range h.GetBucket() {
+		// The +Inf bucket is the count of the histogram
+		if !math.IsInf(b.GetUpperBound(), 1) {
+			buckets[b.GetUpperBound()] = b.GetCumulativeCount()
+		}
+	}
+	if m.ttftBuckets == nil {
+		m.ttftBuckets = buckets
+	} else {
+		if len(buckets) != len(m.ttftBuckets) {
+			klog.V(4).Info("Leaving out a time to first token histogram with different buckets")
+			return
+		}
+		for bound := range buckets {
+			if _, ok := m.ttftBuckets[bound]; !ok {
+				klog.V(4).Info("Leaving out a time to first token histogram with different buckets")
+				return
+			}
+		}
+		for bound, count := range buckets {
+			m.ttftBuckets[bound] += count
+		}
+	}
+	m.ttftCount += h.GetSampleCount()
+	m.ttftSum += h.GetSampleSum()
+}
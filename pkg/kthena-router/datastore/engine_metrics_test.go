@@ -0,0 +1,117 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"istio.io/istio/pkg/util/sets"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+func ttftHistogram(count uint64, sum float64, cumulative ...uint64) *dto.Histogram {
+	bounds := []float64{0.1, 1}
+	h := &dto.Histogram{SampleCount: ptr(count), SampleSum: ptr(sum)}
+	for i, c := range cumulative {
+		h.Bucket = append(h.Bucket, &dto.Bucket{UpperBound: ptr(bounds[i]), CumulativeCount: ptr(c)})
+	}
+	return h
+}
+
+func TestEngineMetricsCollector(t *testing.T) {
+	s := New()
+	newPod := func(name, revision string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{workloadv1alpha1.RevisionLabelKey: revision},
+		}}
+	}
+	pods := []*corev1.Pod{newPod("pod-1", "r1"), newPod("pod-2", "r1"), newPod("pod-3", "r2")}
+	podNames := sets.New[types.NamespacedName]()
+	for _, pod := range pods {
+		podNames.Insert(types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name})
+	}
+	// Two ModelServers of the same model select the same pods, which are counted once
+	var modelServers []*aiv1alpha1.ModelServer
+	for _, name := range []string{"llama", "llama-canary"} {
+		ms := &aiv1alpha1.ModelServer{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       aiv1alpha1.ModelServerSpec{Model: ptr("llama-3-8b"), InferenceEngine: aiv1alpha1.VLLM},
+		}
+		require.NoError(t, s.AddOrUpdateModelServer(ms, podNames))
+		modelServers = append(modelServers, ms)
+	}
+	for _, pod := range pods {
+		require.NoError(t, s.AddOrUpdatePod(pod, modelServers))
+	}
+
+	set := func(name string, running, waiting, kvCache float64, ttft *dto.Histogram) {
+		pod := s.GetPodInfo(types.NamespacedName{Namespace: "default", Name: name})
+		pod.RequestRunningNum = running
+		pod.RequestWaitingNum = waiting
+		pod.GPUCacheUsage = kvCache
+		pod.TimeToFirstToken = ttft
+	}
+	set("pod-1", 4, 1, 0.5, ttftHistogram(10, 2, 6, 9))
+	set("pod-2", 2, 0, 0.25, ttftHistogram(5, 1, 4, 5))
+	set("pod-3", 1, 3, 0.8, nil)
+
+	expected := `
+# HELP kthena_router_engine_kv_cache_usage_ratio KV-cache usage of the inference engines of the model, averaged across its pods
+# TYPE kthena_router_engine_kv_cache_usage_ratio gauge
+kthena_router_engine_kv_cache_usage_ratio{model="llama-3-8b",namespace="default",revision="r1"} 0.375
+kthena_router_engine_kv_cache_usage_ratio{model="llama-3-8b",namespace="default",revision="r2"} 0.8
+# HELP kthena_router_engine_pods Number of pods of the model whose engine metrics are aggregated
+# TYPE kthena_router_engine_pods gauge
+kthena_router_engine_pods{model="llama-3-8b",namespace="default",revision="r1"} 2
+kthena_router_engine_pods{model="llama-3-8b",namespace="default",revision="r2"} 1
+# HELP kthena_router_engine_running_requests Requests running in the inference engines of the model, summed across its pods
+# TYPE kthena_router_engine_running_requests gauge
+kthena_router_engine_running_requests{model="llama-3-8b",namespace="default",revision="r1"} 6
+kthena_router_engine_running_requests{model="llama-3-8b",namespace="default",revision="r2"} 1
+# HELP kthena_router_engine_time_to_first_token_seconds Time to first token reported by the inference engines of the model, merged across its pods
+# TYPE kthena_router_engine_time_to_first_token_seconds histogram
+kthena_router_engine_time_to_first_token_seconds_bucket{model="llama-3-8b",namespace="default",revision="r1",le="0.1"} 10
+kthena_router_engine_time_to_first_token_seconds_bucket{model="llama-3-8b",namespace="default",revision="r1",le="1"} 14
+kthena_router_engine_time_to_first_token_seconds_bucket{model="llama-3-8b",namespace="default",revision="r1",le="+Inf"} 15
+kthena_router_engine_time_to_first_token_seconds_sum{model="llama-3-8b",namespace="default",revision="r1"} 3
+kthena_router_engine_time_to_first_token_seconds_count{model="llama-3-8b",namespace="default",revision="r1"} 15
+# HELP kthena_router_engine_waiting_requests Requests waiting in the inference engines of the model, summed across its pods
+# TYPE kthena_router_engine_waiting_requests gauge
+kthena_router_engine_waiting_requests{model="llama-3-8b",namespace="default",revision="r1"} 1
+kthena_router_engine_waiting_requests{model="llama-3-8b",namespace="default",revision="r2"} 3
+`
+	require.NoError(t, testutil.CollectAndCompare(NewEngineMetricsCollector(s), strings.NewReader(expected)))
+}
+
+func TestEngineMetricsMismatchedBuckets(t *testing.T) {
+	m := &engineMetrics{}
+	m.addTTFT(ttftHistogram(10, 2, 6, 9))
+	m.addTTFT(ttftHistogram(5, 1, 4))
+	require.Equal(t, uint64(10), m.ttftCount, "a histogram with other buckets is left out")
+	require.Equal(t, map[float64]uint64{0.1: 6, 1: 9}, m.ttftBuckets)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// ttftWindowSize is the number of recent times to first token kept per model server.
+	ttftWindowSize = 512
+	// minTTFTSamples is the number of times to first token needed before their percentiles are used.
+	minTTFTSamples = 20
+)
+
+// HedgingStats accounts the hedged requests of a model server.
+type HedgingStats struct {
+	// Hedged is the number of requests duplicated to a second pod.
+	Hedged int64
+	// Won is the number of hedged requests answered first by the duplicate.
+	Won int64
+	// DuplicateTokens is the number of prompt tokens processed by both pods.
+	DuplicateTokens int64
+}
+
+// hedgingState holds the recent times to first token observed by the router and the hedging stats of a model server.
+type hedgingState struct {
+	mutex sync.Mutex
+	// ttfts is a ring buffer, next is the index of the next sample.
+	ttfts []time.Duration
+	next  int
+	stats HedgingStats
+}
+
+func (h *hedgingState) recordTTFT(ttft time.Duration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if len(h.ttfts) < ttftWindowSize {
+		h.ttfts = append(h.ttfts, ttft)
+		return
+	}
+	h.ttfts[h.next] = ttft
+	h.next = (h.next + 1) % ttftWindowSize
+}
+
+// samples returns the recent times to first token, from the oldest to the latest.
+func (h *hedgingState) samples() []time.Duration {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return append(slices.Clone(h.ttfts[h.next:]), h.ttfts[:h.next]...)
+}
+
+func (h *hedgingState) ttftPercentile(percentile int32) (time.Duration, bool) {
+	h.mutex.Lock()
+	if len(h.ttfts) < minTTFTSamples {
+		h.mutex.Unlock()
+		return 0, false
+	}
+	ttfts := slices.Clone(h.ttfts)
+	h.mutex.Unlock()
+
+	slices.Sort(ttfts)
+	// Nearest-rank percentile
+	rank := (int(percentile)*len(ttfts) + 99) / 100
+	return ttfts[max(rank, 1)-1], true
+}
+
+func (h *hedgingState) recordHedge(won bool, duplicateTokens int) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.stats.Hedged++
+	if won {
+		h.stats.Won++
+	}
+	h.stats.DuplicateTokens += int64(duplicateTokens)
+}
+
+func (h *hedgingState) getStats() HedgingStats {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.stats
+}
+
+func (s *store) getModelServer(name types.NamespacedName) *modelServer {
+	if value, ok := s.modelServer.Load(name); ok {
+		return value.(*modelServer)
+	}
+	return nil
+}
+
+func (s *store) RecordTimeToFirstToken(name types.NamespacedName, ttft time.Duration) {
+	if ms := s.getModelServer(name); ms != nil {
+		ms.hedging.recordTTFT(ttft)
+	}
+}
+
+func (s *store) GetTimeToFirstTokenPercentile(name types.NamespacedName, percentile int32) (time.Duration, bool) {
+	if ms := s.getModelServer(name); ms != nil {
+		return ms.hedging.ttftPercentile(percentile)
+	}
+	return 0, false
+}
+
+func (s *store) RecordHedgedRequest(name types.NamespacedName, won bool, duplicateTokens int) {
+	if ms := s.getModelServer(name); ms != nil {
+		ms.hedging.recordHedge(won, duplicateTokens)
+	}
+}
+
+func (s *store) GetHedgingStats(name types.NamespacedName) HedgingStats {
+	if ms := s.getModelServer(name); ms != nil {
+		return ms.hedging.getStats()
+	}
+	return HedgingStats{}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"istio.io/istio/pkg/util/sets"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+func TestHedgingState(t *testing.T) {
+	s := New()
+	name := types.NamespacedName{Namespace: "default", Name: "ms-1"}
+	ms := &aiv1alpha1.ModelServer{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ms-1"}}
+	s.AddOrUpdateModelServer(ms, sets.New[types.NamespacedName]())
+
+	for i := 1; i < minTTFTSamples; i++ {
+		s.RecordTimeToFirstToken(name, time.Duration(i)*time.Millisecond)
+	}
+	_, ok := s.GetTimeToFirstTokenPercentile(name, 95)
+	assert.False(t, ok, "not enough samples")
+
+	for i := minTTFTSamples; i <= 100; i++ {
+		s.RecordTimeToFirstToken(name, time.Duration(i)*time.Millisecond)
+	}
+	p95, ok := s.GetTimeToFirstTokenPercentile(name, 95)
+	assert.True(t, ok)
+	assert.Equal(t, 95*time.Millisecond, p95)
+	p50, _ := s.GetTimeToFirstTokenPercentile(name, 50)
+	assert.Equal(t, 50*time.Millisecond, p50)
+
+	// The oldest samples are replaced once the window is full
+	for i := 0; i < ttftWindowSize; i++ {
+		s.RecordTimeToFirstToken(name, time.Second)
+	}
+	p50, _ = s.GetTimeToFirstTokenPercentile(name, 50)
+	assert.Equal(t, time.Second, p50)
+
+	s.RecordHedgedRequest(name, true, 100)
+	s.RecordHedgedRequest(name, false, 50)
+	assert.Equal(t, HedgingStats{Hedged: 2, Won: 1, DuplicateTokens: 150}, s.GetHedgingStats(name))
+
+	// The state survives updates of the model server
+	s.AddOrUpdateModelServer(ms, sets.New[types.NamespacedName]())
+	assert.Equal(t, int64(2), s.GetHedgingStats(name).Hedged)
+
+	unknown := types.NamespacedName{Namespace: "default", Name: "unknown"}
+	s.RecordTimeToFirstToken(unknown, time.Second)
+	_, ok = s.GetTimeToFirstTokenPercentile(unknown, 95)
+	assert.False(t, ok)
+	assert.Equal(t, HedgingStats{}, s.GetHedgingStats(unknown))
+}
@@ -0,0 +1,219 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultMinScrapeInterval is the scrape interval of fully loaded pods.
+	defaultMinScrapeInterval = 200 * time.Millisecond
+	// defaultMaxScrapeInterval is the scrape interval of idle pods.
+	defaultMaxScrapeInterval = 1 * time.Second
+	defaultScrapeWorkers     = 16
+	// scrapeJitter spreads the scrapes of the pods over time, as a fraction of the interval.
+	scrapeJitter = 0.1
+)
+
+// scrapeState tracks when the metrics of a pod should be scraped next.
+type scrapeState struct {
+	next     time.Time
+	inflight bool
+}
+
+type scrapeJob struct {
+	name types.NamespacedName
+	pod  *PodInfo
+}
+
+// metricsFetcher scrapes the metrics and models of the pods in the store with a bounded pool of workers.
+// Each pod is scraped at its own interval, which shrinks from maxInterval down to minInterval as the pod
+// gets loaded, so that routing decisions on busy pods rely on fresh metrics without scraping idle pods as often.
+type metricsFetcher struct {
+	store       *store
+	minInterval time.Duration
+	maxInterval time.Duration
+	workers     int
+
+	mutex    sync.Mutex
+	schedule map[types.NamespacedName]*scrapeState
+}
+
+// newMetricsFetcher creates a metrics fetcher with configuration from environment variables
+func newMetricsFetcher(s *store) *metricsFetcher {
+	f := &metricsFetcher{
+		store:       s,
+		minInterval: defaultMinScrapeInterval,
+		maxInterval: defaultMaxScrapeInterval,
+		workers:     defaultScrapeWorkers,
+		schedule:    make(map[types.NamespacedName]*scrapeState),
+	}
+
+	if value := os.Getenv("METRICS_SCRAPE_MIN_INTERVAL"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			f.minInterval = d
+		} else {
+			klog.Warningf("Invalid METRICS_SCRAPE_MIN_INTERVAL: %s, using default", value)
+		}
+	}
+	if value := os.Getenv("METRICS_SCRAPE_MAX_INTERVAL"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			f.maxInterval = d
+		} else {
+			klog.Warningf("Invalid METRICS_SCRAPE_MAX_INTERVAL: %s, using default", value)
+		}
+	}
+	if f.maxInterval < f.minInterval {
+		klog.Warningf("METRICS_SCRAPE_MAX_INTERVAL is less than METRICS_SCRAPE_MIN_INTERVAL, using %s for both", f.minInterval)
+		f.maxInterval = f.minInterval
+	}
+	if value := os.Getenv("METRICS_SCRAPE_WORKERS"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			f.workers = n
+		} else {
+			klog.Warningf("Invalid METRICS_SCRAPE_WORKERS: %s, using default", value)
+		}
+	}
+
+	return f
+}
+
+// run scrapes all the pods once before marking the store as synced, then keeps scraping each pod when it is due.
+func (f *metricsFetcher) run(ctx context.Context) {
+	jobs := make(chan scrapeJob, f.workers)
+	var wg sync.WaitGroup
+	for i := 0; i < f.workers; i++ {
+		go func() {
+			for job := range jobs {
+				f.scrape(job.name, job.pod)
+				wg.Done()
+			}
+		}()
+	}
+	defer close(jobs)
+
+	f.dispatch(ctx, jobs, &wg)
+	wg.Wait()
+	f.store.initialSynced.Store(true)
+
+	// Dispatch often enough to honour the shortest interval.
+	ticker := time.NewTicker(f.minInterval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.dispatch(ctx, jobs, &wg)
+		}
+	}
+}
+
+// dispatch submits the pods which are due to the workers, blocking while all of them are busy.
+func (f *metricsFetcher) dispatch(ctx context.Context, jobs chan<- scrapeJob, wg *sync.WaitGroup) {
+	now := time.Now()
+	seen := make(map[types.NamespacedName]bool)
+	f.store.pods.Range(func(key, value any) bool {
+		name, ok := key.(types.NamespacedName)
+		if !ok {
+			return true
+		}
+		pod, ok := value.(*PodInfo)
+		if !ok {
+			return true
+		}
+		seen[name] = true
+		if !f.markDue(name, now) {
+			return true
+		}
+		wg.Add(1)
+		select {
+		case jobs <- scrapeJob{name: name, pod: pod}:
+			return true
+		case <-ctx.Done():
+			wg.Done()
+			return false
+		}
+	})
+
+	// Forget the pods which have been removed from the store.
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for name := range f.schedule {
+		if !seen[name] {
+			delete(f.schedule, name)
+		}
+	}
+}
+
+// markDue returns true and marks the pod as being scraped if it is due at the given time.
+func (f *metricsFetcher) markDue(name types.NamespacedName, now time.Time) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	state, ok := f.schedule[name]
+	if !ok {
+		state = &scrapeState{}
+		f.schedule[name] = state
+	}
+	if state.inflight || now.Before(state.next) {
+		return false
+	}
+	state.inflight = true
+	return true
+}
+
+func (f *metricsFetcher) scrape(name types.NamespacedName, pod *PodInfo) {
+	f.store.updatePodMetrics(pod)
+	f.store.updatePodModels(pod)
+
+	next := time.Now().Add(f.nextInterval(pod))
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if state, ok := f.schedule[name]; ok {
+		state.inflight = false
+		state.next = next
+	}
+}
+
+// nextInterval interpolates the scrape interval of the pod between maxInterval when idle and minInterval
+// when fully loaded, with some jitter so that the pods scraped together drift apart.
+func (f *metricsFetcher) nextInterval(pod *PodInfo) time.Duration {
+	load := podLoad(pod)
+	interval := float64(f.maxInterval) - float64(f.maxInterval-f.minInterval)*load
+	interval *= 1 + scrapeJitter*(2*rand.Float64()-1)
+	return time.Duration(interval)
+}
+
+// podLoad estimates the load of a pod in [0, 1] from its last metrics.
+func podLoad(pod *PodInfo) float64 {
+	pod.mutex.RLock()
+	defer pod.mutex.RUnlock()
+	if pod.RequestWaitingNum > 0 {
+		// requests are queueing, the pod is saturated
+		return 1
+	}
+	return min(max(pod.GPUCacheUsage, 0), 1)
+}
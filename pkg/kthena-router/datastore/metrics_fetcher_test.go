@@ -0,0 +1,134 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"istio.io/istio/pkg/util/sets"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/backend"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/utils"
+)
+
+func TestMetricsFetcherNextInterval(t *testing.T) {
+	f := &metricsFetcher{minInterval: 100 * time.Millisecond, maxInterval: time.Second}
+
+	tests := []struct {
+		name string
+		pod  *PodInfo
+		want time.Duration
+	}{
+		{
+			name: "idle pod",
+			pod:  &PodInfo{},
+			want: time.Second,
+		},
+		{
+			name: "half loaded pod",
+			pod:  &PodInfo{GPUCacheUsage: 0.5},
+			want: 550 * time.Millisecond,
+		},
+		{
+			name: "pod with waiting requests",
+			pod:  &PodInfo{GPUCacheUsage: 0.1, RequestWaitingNum: 3},
+			want: 100 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := f.nextInterval(tt.pod)
+			assert.InDelta(t, float64(tt.want), float64(got), float64(tt.want)*scrapeJitter)
+		})
+	}
+}
+
+func TestMetricsFetcherMarkDue(t *testing.T) {
+	f := &metricsFetcher{schedule: make(map[types.NamespacedName]*scrapeState)}
+	name := types.NamespacedName{Namespace: "default", Name: "pod1"}
+	now := time.Now()
+
+	assert.True(t, f.markDue(name, now))
+	// A pod is never scraped twice concurrently
+	assert.False(t, f.markDue(name, now))
+
+	f.schedule[name].inflight = false
+	f.schedule[name].next = now.Add(time.Second)
+	assert.False(t, f.markDue(name, now))
+	assert.True(t, f.markDue(name, now.Add(time.Second)))
+}
+
+func TestMetricsFetcherRun(t *testing.T) {
+	s := New().(*store)
+	podName := types.NamespacedName{Namespace: "default", Name: "pod1"}
+	s.pods.Store(podName, &PodInfo{
+		Pod:               &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod1"}},
+		engine:            "vLLM",
+		RequestRunningNum: 4,
+		models:            sets.New[string](),
+	})
+
+	var scrapes atomic.Int32
+	patch := gomonkey.NewPatches()
+	patch.ApplyFunc(backend.GetPodMetrics, func(engine string, pod *corev1.Pod, previousHistogram map[string]*dto.Histogram) (map[string]float64, map[string]*dto.Histogram) {
+		scrapes.Add(1)
+		// RequestRunningNum is missing from the scrape
+		return map[string]float64{
+			utils.GPUCacheUsage:     1,
+			utils.RequestWaitingNum: 2,
+		}, nil
+	})
+	patch.ApplyFunc(backend.GetPodModels, func(engine string, pod *corev1.Pod) ([]string, error) {
+		return []string{"llama"}, nil
+	})
+	defer patch.Reset()
+
+	f := newMetricsFetcher(s)
+	f.minInterval = 10 * time.Millisecond
+	f.maxInterval = time.Minute
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go f.run(ctx)
+
+	assert.Eventually(t, s.HasSynced, time.Second, 5*time.Millisecond)
+	pod := s.GetPodInfo(podName)
+	assert.Equal(t, 1.0, pod.GPUCacheUsage)
+	assert.Equal(t, 2.0, pod.RequestWaitingNum)
+	assert.Equal(t, 4.0, pod.RequestRunningNum)
+	assert.True(t, pod.Contains("llama"))
+
+	// The loaded pod is scraped at the minimum interval instead of once a minute
+	assert.Eventually(t, func() bool { return scrapes.Load() >= 3 }, time.Second, 5*time.Millisecond)
+
+	// Removed pods are forgotten
+	s.pods.Delete(podName)
+	assert.Eventually(t, func() bool {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		return len(f.schedule) == 0
+	}, time.Second, 5*time.Millisecond)
+}
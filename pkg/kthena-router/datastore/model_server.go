@@ -18,6 +18,7 @@ package datastore
 
 import (
 	"sync"
+	"sync/atomic"
 
 	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
 	"istio.io/istio/pkg/util/sets"
@@ -25,6 +26,7 @@ import (
 )
 
 type modelServer struct {
+	// mutex serializes the writers. The scheduling hot path reads the snapshot instead.
 	mutex sync.RWMutex
 
 	modelServer *aiv1alpha1.ModelServer
@@ -34,6 +36,25 @@ type modelServer struct {
 	// Key: PD group value (the actual value of the group key label)
 	// Value: PDGroupPods containing categorized decode/prefill pods
 	pdGroups map[string]*PDGroupPods
+
+	// snapshot is an immutable copy of the pods, rebuilt lazily by the first reader after a change.
+	// It is nil when stale.
+	snapshot atomic.Pointer[modelServerSnapshot]
+
+	// hedging is kept across the updates of the ModelServer
+	hedging hedgingState
+	// slo measures the requests against the service level objectives of the ModelServer
+	slo sloState
+}
+
+// modelServerSnapshot is an immutable view of the pods of a model server.
+// Its slices are shared by all readers and must not be modified.
+type modelServerSnapshot struct {
+	pods        []types.NamespacedName
+	decodePods  []types.NamespacedName
+	prefillPods []types.NamespacedName
+	// prefillPodsByGroup maps a PD group value to its prefill pods
+	prefillPodsByGroup map[string][]types.NamespacedName
 }
 
 func newModelServer(ms *aiv1alpha1.ModelServer) *modelServer {
@@ -44,27 +65,69 @@ func newModelServer(ms *aiv1alpha1.ModelServer) *modelServer {
 	}
 }
 
-func (m *modelServer) getPods() []types.NamespacedName {
+// getSnapshot returns the current snapshot of the pods, rebuilding it if it is stale.
+// Concurrent readers never block each other, and only block writers while rebuilding.
+func (m *modelServer) getSnapshot() *modelServerSnapshot {
+	if snapshot := m.snapshot.Load(); snapshot != nil {
+		return snapshot
+	}
+
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
-	podNames := make([]types.NamespacedName, 0, m.pods.Len())
+	snapshot := &modelServerSnapshot{
+		pods:               m.pods.UnsortedList(),
+		prefillPodsByGroup: make(map[string][]types.NamespacedName, len(m.pdGroups)),
+	}
+	for groupValue, pdGroupPods := range m.pdGroups {
+		prefillPods := pdGroupPods.GetPrefillPods()
+		snapshot.decodePods = append(snapshot.decodePods, pdGroupPods.GetDecodePods()...)
+		snapshot.prefillPods = append(snapshot.prefillPods, prefillPods...)
+		snapshot.prefillPodsByGroup[groupValue] = prefillPods
+	}
+	// Writers invalidate the snapshot while holding the write lock, so it cannot be stale here.
+	m.snapshot.Store(snapshot)
+	return snapshot
+}
 
-	for podName := range m.pods {
-		podNames = append(podNames, podName)
-	}
-	return podNames
+// invalidateSnapshot must be called with the write lock held after any change.
+func (m *modelServer) invalidateSnapshot() {
+	m.snapshot.Store(nil)
+}
+
+func (m *modelServer) setModelServer(ms *aiv1alpha1.ModelServer) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.modelServer = ms
+	m.invalidateSnapshot()
+}
+
+func (m *modelServer) setPods(pods sets.Set[types.NamespacedName]) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.pods = pods
+	m.invalidateSnapshot()
+}
+
+// getPods returns the pods of the model server. The returned slice must not be modified.
+func (m *modelServer) getPods() []types.NamespacedName {
+	return m.getSnapshot().pods
 }
 
 func (m *modelServer) addPod(podName types.NamespacedName) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
+	if m.pods.Contains(podName) {
+		return
+	}
 	m.pods.Insert(podName)
+	m.invalidateSnapshot()
 }
 
 func (m *modelServer) deletePod(podName types.NamespacedName) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 	m.pods.Delete(podName)
+	m.invalidateSnapshot()
 }
 
 // categorizePodForPDGroup categorizes a pod based on PDGroup labels and adds it to appropriate categories
@@ -82,6 +145,7 @@ func (m *modelServer) categorizePodForPDGroup(podName types.NamespacedName, podL
 	}
 	pdGroupPods := m.pdGroups[pdGroupValue]
 	pdGroup := m.modelServer.Spec.WorkloadSelector.PDGroup
+	m.invalidateSnapshot()
 	// Check if pod matches decode labels
 	isDecodePod := matchesLabels(podLabels, pdGroup.DecodeLabels)
 	if isDecodePod {
@@ -117,6 +181,7 @@ func (m *modelServer) removePodFromPDGroups(podName types.NamespacedName, labels
 	defer m.mutex.Unlock()
 	if pdGroup, ok := m.pdGroups[pdGroupName]; ok {
 		pdGroup.RemovePod(podName)
+		m.invalidateSnapshot()
 		// Clean up empty PDGroupPods
 		if pdGroup.IsEmpty() {
 			delete(m.pdGroups, pdGroupName)
@@ -124,51 +189,25 @@ func (m *modelServer) removePodFromPDGroups(podName types.NamespacedName, labels
 	}
 }
 
-// getAllDecodePods returns all decode pods across all PD groups
+// getAllDecodePods returns all decode pods across all PD groups. The returned slice must not be modified.
 func (m *modelServer) getAllDecodePods() []types.NamespacedName {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-
-	var result []types.NamespacedName
-	for _, pdGroupPods := range m.pdGroups {
-		result = append(result, pdGroupPods.GetDecodePods()...)
-	}
-	return result
+	return m.getSnapshot().decodePods
 }
 
-// getAllPrefillPods returns all prefill pods across all PD groups
+// getAllPrefillPods returns all prefill pods across all PD groups. The returned slice must not be modified.
 func (m *modelServer) getAllPrefillPods() []types.NamespacedName {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-
-	var result []types.NamespacedName
-	for _, pdGroupPods := range m.pdGroups {
-		result = append(result, pdGroupPods.GetPrefillPods()...)
-	}
-	return result
+	return m.getSnapshot().prefillPods
 }
 
-// getPrefillPodsForDecodeGroup returns prefill pods that match the same PD group as a decode pod
+// getPrefillPodsForDecodeGroup returns prefill pods that match the same PD group as a decode pod.
+// The returned slice must not be modified.
 func (m *modelServer) getPrefillPodsForDecodeGroup(pod *PodInfo) []types.NamespacedName {
-	// Check if this modelServer has PDGroup configuration
-	if m.modelServer.Spec.WorkloadSelector == nil || m.modelServer.Spec.WorkloadSelector.PDGroup == nil {
-		return nil
-	}
-
-	pdGroup := m.modelServer.Spec.WorkloadSelector.PDGroup
-	pdGroupValue, hasPDGroupKey := pod.Pod.Labels[pdGroup.GroupKey]
-	if !hasPDGroupKey {
+	pdGroupValue := m.getPDGroupName(pod.Pod.Labels)
+	if pdGroupValue == "" {
 		return nil
 	}
-
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
 	// Return prefill pods for the same PD group value
-	if pdGroupPods, exists := m.pdGroups[pdGroupValue]; exists {
-		return pdGroupPods.GetPrefillPods()
-	}
-
-	return nil
+	return m.getSnapshot().prefillPodsByGroup[pdGroupValue]
 }
 
 // matchesLabels checks if pod labels match the required labels
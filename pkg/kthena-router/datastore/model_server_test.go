@@ -0,0 +1,65 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+func TestModelServerSnapshot(t *testing.T) {
+	ms := newModelServer(&aiv1alpha1.ModelServer{
+		Spec: aiv1alpha1.ModelServerSpec{
+			WorkloadSelector: &aiv1alpha1.WorkloadSelector{
+				PDGroup: &aiv1alpha1.PDGroup{
+					GroupKey:      "pd-group",
+					DecodeLabels:  map[string]string{"role": "decode"},
+					PrefillLabels: map[string]string{"role": "prefill"},
+				},
+			},
+		},
+	})
+	decode := types.NamespacedName{Namespace: "default", Name: "decode"}
+	prefill := types.NamespacedName{Namespace: "default", Name: "prefill"}
+
+	ms.addPod(decode)
+	ms.categorizePodForPDGroup(decode, map[string]string{"pd-group": "a", "role": "decode"})
+	pods := ms.getPods()
+	assert.Equal(t, []types.NamespacedName{decode}, pods)
+	// The snapshot is reused until the next change
+	assert.Same(t, ms.getSnapshot(), ms.getSnapshot())
+
+	ms.addPod(prefill)
+	ms.categorizePodForPDGroup(prefill, map[string]string{"pd-group": "a", "role": "prefill"})
+	// A snapshot taken before the change is left untouched
+	assert.Equal(t, []types.NamespacedName{decode}, pods)
+	assert.ElementsMatch(t, []types.NamespacedName{decode, prefill}, ms.getPods())
+	assert.Equal(t, []types.NamespacedName{decode}, ms.getAllDecodePods())
+	assert.Equal(t, []types.NamespacedName{prefill}, ms.getAllPrefillPods())
+	decodePod := &PodInfo{Pod: createTestPod("default", "decode")}
+	decodePod.Pod.Labels = map[string]string{"pd-group": "a"}
+	assert.Equal(t, []types.NamespacedName{prefill}, ms.getPrefillPodsForDecodeGroup(decodePod))
+
+	ms.removePodFromPDGroups(prefill, map[string]string{"pd-group": "a", "role": "prefill"})
+	ms.deletePod(prefill)
+	assert.Equal(t, []types.NamespacedName{decode}, ms.getPods())
+	assert.Empty(t, ms.getPrefillPodsForDecodeGroup(decodePod))
+}
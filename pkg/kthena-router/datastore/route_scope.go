@@ -0,0 +1,74 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"slices"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+// RouteScope restricts the ModelRoutes the model of a request is resolved among, by their namespace, so that
+// the same model name can be routed differently for different tenants of the router.
+// The zero value resolves the models among all the ModelRoutes.
+type RouteScope struct {
+	// Namespaces are the namespaces of the ModelRoutes, searched in order. All the namespaces when empty.
+	Namespaces []string
+	// ExcludedNamespaces are left out when all the namespaces are searched.
+	ExcludedNamespaces []string
+}
+
+type routeScopeKey struct{}
+
+// WithRouteScope returns a copy of the context carrying the route scope of the request.
+func WithRouteScope(ctx context.Context, scope RouteScope) context.Context {
+	return context.WithValue(ctx, routeScopeKey{}, scope)
+}
+
+// RouteScopeFrom returns the route scope of the request context, the zero value if it has none.
+func RouteScopeFrom(ctx context.Context) RouteScope {
+	scope, _ := ctx.Value(routeScopeKey{}).(RouteScope)
+	return scope
+}
+
+// lookup finds the route of the model in the scope. global holds the route of each model in all the namespaces,
+// index the routes by namespace.
+func (scope RouteScope) lookup(global map[string]*aiv1alpha1.ModelRoute, index map[string]map[string]*aiv1alpha1.ModelRoute, model string) (*aiv1alpha1.ModelRoute, bool) {
+	if len(scope.Namespaces) > 0 {
+		for _, namespace := range scope.Namespaces {
+			if mr, ok := index[namespace][model]; ok {
+				return mr, true
+			}
+		}
+		return nil, false
+	}
+
+	mr, ok := global[model]
+	if !ok || !slices.Contains(scope.ExcludedNamespaces, mr.Namespace) {
+		return mr, ok
+	}
+	for _, namespace := range sortedKeys(index) {
+		if slices.Contains(scope.ExcludedNamespaces, namespace) {
+			continue
+		}
+		if mr, ok := index[namespace][model]; ok {
+			return mr, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,142 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+func newScopedRoute(namespace, name, model, server string, loras ...string) *aiv1alpha1.ModelRoute {
+	return &aiv1alpha1.ModelRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: aiv1alpha1.ModelRouteSpec{
+			ModelName:    model,
+			LoraAdapters: loras,
+			Rules: []*aiv1alpha1.Rule{{
+				Name:         "default",
+				TargetModels: []*aiv1alpha1.TargetModel{{ModelServerName: server}},
+			}},
+		},
+	}
+}
+
+func TestMatchModelServerRouteScope(t *testing.T) {
+	s := New().(*store)
+	require.NoError(t, s.AddOrUpdateModelRoute(newScopedRoute("tenant-a", "llama", "llama", "llama-a", "sql-lora")))
+	require.NoError(t, s.AddOrUpdateModelRoute(newScopedRoute("tenant-b", "llama", "llama", "llama-b")))
+	require.NoError(t, s.AddOrUpdateModelRoute(newScopedRoute("shared", "qwen", "qwen", "qwen")))
+
+	match := func(scope RouteScope, model string) (types.NamespacedName, error) {
+		req := &http.Request{URL: &url.URL{Path: "/v1/chat/completions"}}
+		req = req.WithContext(WithRouteScope(req.Context(), scope))
+		server, _, _, err := s.MatchModelServer(model, req)
+		return server, err
+	}
+
+	tests := []struct {
+		name       string
+		scope      RouteScope
+		model      string
+		wantServer types.NamespacedName
+		wantErr    bool
+	}{
+		{
+			name:       "tenant a",
+			scope:      RouteScope{Namespaces: []string{"tenant-a"}},
+			model:      "llama",
+			wantServer: types.NamespacedName{Namespace: "tenant-a", Name: "llama-a"},
+		},
+		{
+			name:       "tenant b",
+			scope:      RouteScope{Namespaces: []string{"tenant-b"}},
+			model:      "llama",
+			wantServer: types.NamespacedName{Namespace: "tenant-b", Name: "llama-b"},
+		},
+		{
+			name:       "namespaces searched in order",
+			scope:      RouteScope{Namespaces: []string{"tenant-b", "shared"}},
+			model:      "qwen",
+			wantServer: types.NamespacedName{Namespace: "shared", Name: "qwen"},
+		},
+		{
+			name:    "model of another tenant",
+			scope:   RouteScope{Namespaces: []string{"tenant-b"}},
+			model:   "sql-lora",
+			wantErr: true,
+		},
+		{
+			name:       "lora adapter of the tenant",
+			scope:      RouteScope{Namespaces: []string{"tenant-a"}},
+			model:      "sql-lora",
+			wantServer: types.NamespacedName{Namespace: "tenant-a", Name: "llama-a"},
+		},
+		{
+			name:       "excluded namespaces",
+			scope:      RouteScope{ExcludedNamespaces: []string{"tenant-b"}},
+			model:      "llama",
+			wantServer: types.NamespacedName{Namespace: "tenant-a", Name: "llama-a"},
+		},
+		{
+			name:    "all the routes of the model excluded",
+			scope:   RouteScope{ExcludedNamespaces: []string{"tenant-a", "tenant-b"}},
+			model:   "llama",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := match(tt.scope, tt.model)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantServer, server)
+		})
+	}
+}
+
+func TestDeleteModelRouteOfOneNamespace(t *testing.T) {
+	s := New().(*store)
+	routeA := newScopedRoute("tenant-a", "llama", "llama", "llama-a")
+	routeB := newScopedRoute("tenant-b", "llama", "llama", "llama-b")
+	require.NoError(t, s.AddOrUpdateModelRoute(routeA))
+	require.NoError(t, s.AddOrUpdateModelRoute(routeB))
+	assert.Equal(t, routeB, s.routes["llama"])
+
+	// The model keeps being routed by the route of the other namespace
+	require.NoError(t, s.DeleteModelRoute("tenant-b/llama"))
+	assert.Equal(t, routeA, s.routes["llama"])
+	assert.Nil(t, s.GetModelRoute("tenant-b/llama"))
+	assert.Equal(t, routeA, s.GetModelRoute("tenant-a/llama"))
+
+	// A route renaming its model no longer routes the previous one
+	renamed := newScopedRoute("tenant-a", "llama", "llama-3", "llama-a")
+	require.NoError(t, s.AddOrUpdateModelRoute(renamed))
+	assert.Nil(t, s.routes["llama"])
+	assert.Equal(t, renamed, s.routes["llama-3"])
+	assert.Len(t, s.GetAllModelRoutes(), 1)
+}
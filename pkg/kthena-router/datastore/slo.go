@@ -0,0 +1,343 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+// Names of the service level objectives of a model server.
+const (
+	SLOAvailability = "availability"
+	SLOLatency      = "latency"
+)
+
+const (
+	// sloStateName is the name of the service level indicators of the model servers in the snapshots.
+	sloStateName = "slo"
+	// sloBuckets is the number of buckets the window of the objectives is split into.
+	sloBuckets = 1440
+	// minSLOBucket is the shortest bucket, for the short windows.
+	minSLOBucket = time.Minute
+	// defaultSLOWindow is the window of the objectives when it is not set.
+	defaultSLOWindow = 30 * 24 * time.Hour
+)
+
+// BurnRateWindows are the windows the burn rates of the error budgets are computed over, the short and long
+// windows of the usual multiwindow alerts, by name. They are capped to the window of the objectives.
+var BurnRateWindows = map[string]time.Duration{
+	"1h": time.Hour,
+	"6h": 6 * time.Hour,
+}
+
+// SLOStatus is the compliance of a model server with one of its service level objectives over their window.
+type SLOStatus struct {
+	// Name is the objective, availability or latency.
+	Name string
+	// Target is the ratio of the requests which must meet the objective.
+	Target float64
+	Window time.Duration
+	// Total is the number of requests measured in the window, Bad the ones which missed the objective.
+	Total uint64
+	Bad   uint64
+	// BurnRates are the rates the error budget is spent at over the BurnRateWindows. At 1, the budget is
+	// exactly spent at the end of the window.
+	BurnRates map[string]float64
+}
+
+// Compliance is the ratio of the requests which met the objective, 1 without requests.
+func (s SLOStatus) Compliance() float64 {
+	if s.Total == 0 {
+		return 1
+	}
+	return 1 - float64(s.Bad)/float64(s.Total)
+}
+
+// ErrorBudgetRemaining is the ratio of the error budget left, negative once it is overspent.
+func (s SLOStatus) ErrorBudgetRemaining() float64 {
+	return 1 - burnRate(s.Total, s.Bad, s.Target)
+}
+
+func burnRate(total, bad uint64, target float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(bad) / float64(total) / (1 - target)
+}
+
+// sloBucket counts the requests of a model server in a slice of the window. The latency objective is only
+// measured on the requests which did not fail.
+type sloBucket struct {
+	// Index is the start of the bucket, in bucket sizes since the epoch.
+	Index    int64  `json:"index"`
+	Requests uint64 `json:"requests"`
+	Failed   uint64 `json:"failed"`
+	Slow     uint64 `json:"slow"`
+}
+
+// sloState holds the service level indicators of a model server over the window of its objectives, in a ring
+// of buckets. It is kept across the updates of the ModelServer, and reset when the window changes.
+type sloState struct {
+	mutex   sync.Mutex
+	window  time.Duration
+	bucket  time.Duration
+	buckets []sloBucket
+}
+
+func sloBucketSize(window time.Duration) time.Duration {
+	return max(window/sloBuckets, minSLOBucket)
+}
+
+// reset must be called with the lock held. It drops the indicators measured over another window.
+func (s *sloState) reset(window time.Duration) {
+	if s.window == window {
+		return
+	}
+	s.window = window
+	s.bucket = sloBucketSize(window)
+	s.buckets = make([]sloBucket, (window+s.bucket-1)/s.bucket)
+}
+
+func (s *sloState) record(window time.Duration, now time.Time, failed, slow bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.reset(window)
+	index := now.UnixNano() / int64(s.bucket)
+	b := &s.buckets[index%int64(len(s.buckets))]
+	if b.Index != index {
+		*b = sloBucket{Index: index}
+	}
+	b.Requests++
+	if failed {
+		b.Failed++
+	} else if slow {
+		b.Slow++
+	}
+}
+
+// sum returns the indicators of the buckets covering the span until now.
+func (s *sloState) sum(window time.Duration, now time.Time, span time.Duration) sloBucket {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.reset(window)
+	index := now.UnixNano() / int64(s.bucket)
+	count := min(int64((span+s.bucket-1)/s.bucket), int64(len(s.buckets)))
+	var total sloBucket
+	for _, b := range s.buckets {
+		if b.Index > index-count && b.Index <= index {
+			total.Requests += b.Requests
+			total.Failed += b.Failed
+			total.Slow += b.Slow
+		}
+	}
+	return total
+}
+
+func (s *sloState) snapshot() (time.Duration, []sloBucket) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var buckets []sloBucket
+	for _, b := range s.buckets {
+		if b.Requests > 0 {
+			buckets = append(buckets, b)
+		}
+	}
+	return s.window, buckets
+}
+
+func (s *sloState) restore(window, savedWindow time.Duration, buckets []sloBucket) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.reset(window)
+	if savedWindow != window {
+		return
+	}
+	for _, b := range buckets {
+		s.buckets[b.Index%int64(len(s.buckets))] = b
+	}
+}
+
+// sloTarget parses the target percentage of an objective into a ratio, false when it is invalid.
+func sloTarget(target string) (float64, bool) {
+	percent, err := strconv.ParseFloat(target, 64)
+	if err != nil || percent <= 0 || percent >= 100 {
+		return 0, false
+	}
+	return percent / 100, true
+}
+
+func sloWindow(slo *aiv1alpha1.ServiceLevelObjective) time.Duration {
+	if slo.Window == nil || slo.Window.Duration <= 0 {
+		return defaultSLOWindow
+	}
+	return slo.Window.Duration
+}
+
+func (s *store) RecordSLO(name types.NamespacedName, failed bool, latency time.Duration) map[string]bool {
+	ms := s.getModelServer(name)
+	if ms == nil || ms.modelServer.Spec.SLO == nil {
+		return nil
+	}
+	slo := ms.modelServer.Spec.SLO
+	slow := slo.Latency != nil && latency > slo.Latency.Threshold.Duration
+	ms.slo.record(sloWindow(slo), time.Now(), failed, slow)
+
+	met := make(map[string]bool, 2)
+	if slo.Availability != nil {
+		met[SLOAvailability] = !failed
+	}
+	if slo.Latency != nil && !failed {
+		met[SLOLatency] = !slow
+	}
+	return met
+}
+
+func (s *store) GetSLOStatus(name types.NamespacedName) []SLOStatus {
+	ms := s.getModelServer(name)
+	if ms == nil || ms.modelServer.Spec.SLO == nil {
+		return nil
+	}
+	slo := ms.modelServer.Spec.SLO
+	window := sloWindow(slo)
+	now := time.Now()
+	total := ms.slo.sum(window, now, window)
+	burns := make(map[string]sloBucket, len(BurnRateWindows))
+	for burnWindow, span := range BurnRateWindows {
+		burns[burnWindow] = ms.slo.sum(window, now, min(span, window))
+	}
+
+	var statuses []SLOStatus
+	if slo.Availability != nil {
+		if target, ok := sloTarget(slo.Availability.Target); ok {
+			status := SLOStatus{Name: SLOAvailability, Target: target, Window: window, Total: total.Requests, Bad: total.Failed,
+				BurnRates: make(map[string]float64, len(burns))}
+			for burnWindow, b := range burns {
+				status.BurnRates[burnWindow] = burnRate(b.Requests, b.Failed, target)
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	if slo.Latency != nil {
+		if target, ok := sloTarget(slo.Latency.Target); ok {
+			status := SLOStatus{Name: SLOLatency, Target: target, Window: window, Total: total.Requests - total.Failed, Bad: total.Slow,
+				BurnRates: make(map[string]float64, len(burns))}
+			for burnWindow, b := range burns {
+				status.BurnRates[burnWindow] = burnRate(b.Requests-b.Failed, b.Slow, target)
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// sloLearnedState persists the service level indicators of the model servers, so that a restarted replica does not
+// start over with a full error budget.
+type sloLearnedState struct {
+	store *store
+}
+
+type savedSLOState struct {
+	Window  time.Duration `json:"window"`
+	Buckets []sloBucket   `json:"buckets"`
+}
+
+func (t *sloLearnedState) Name() string {
+	return sloStateName
+}
+
+func (t *sloLearnedState) Save() ([]byte, error) {
+	states := make(map[string]savedSLOState)
+	t.store.modelServer.Range(func(key, value any) bool {
+		if window, buckets := value.(*modelServer).slo.snapshot(); len(buckets) > 0 {
+			states[key.(types.NamespacedName).String()] = savedSLOState{Window: window, Buckets: buckets}
+		}
+		return true
+	})
+	return json.Marshal(states)
+}
+
+// Restore only loads the indicators of the model servers whose window did not change.
+func (t *sloLearnedState) Restore(data []byte) error {
+	var states map[string]savedSLOState
+	if err := json.Unmarshal(data, &states); err != nil {
+		return err
+	}
+	t.store.modelServer.Range(func(key, value any) bool {
+		ms := value.(*modelServer)
+		state, ok := states[key.(types.NamespacedName).String()]
+		if ok && ms.modelServer.Spec.SLO != nil {
+			ms.slo.restore(sloWindow(ms.modelServer.Spec.SLO), state.Window, state.Buckets)
+		}
+		return true
+	})
+	return nil
+}
+
+var sloMetricLabels = []string{"model_server", "slo"}
+
+var (
+	sloTargetDesc = prometheus.NewDesc("kthena_router_slo_target_ratio",
+		"Ratio of the requests which must meet the service level objective", sloMetricLabels, nil)
+	sloComplianceDesc = prometheus.NewDesc("kthena_router_slo_compliance_ratio",
+		"Ratio of the requests which met the service level objective over its window", sloMetricLabels, nil)
+	sloErrorBudgetDesc = prometheus.NewDesc("kthena_router_slo_error_budget_remaining_ratio",
+		"Ratio of the error budget of the service level objective left over its window, negative once overspent", sloMetricLabels, nil)
+	sloBurnRateDesc = prometheus.NewDesc("kthena_router_slo_burn_rate",
+		"Rate the error budget of the service level objective is spent at, 1 spending it exactly over its window",
+		append(sloMetricLabels, "window"), nil)
+)
+
+// SLOCollector exports the compliance of the model servers with their service level objectives, as measured
+// on the requests served by the router replica.
+type SLOCollector struct {
+	store Store
+}
+
+var _ prometheus.Collector = &SLOCollector{}
+
+func NewSLOCollector(store Store) *SLOCollector {
+	return &SLOCollector{store: store}
+}
+
+func (c *SLOCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- sloTargetDesc
+	ch <- sloComplianceDesc
+	ch <- sloErrorBudgetDesc
+	ch <- sloBurnRateDesc
+}
+
+func (c *SLOCollector) Collect(ch chan<- prometheus.Metric) {
+	for name := range c.store.GetAllModelServers() {
+		for _, status := range c.store.GetSLOStatus(name) {
+			labels := []string{name.String(), status.Name}
+			ch <- prometheus.MustNewConstMetric(sloTargetDesc, prometheus.GaugeValue, status.Target, labels...)
+			ch <- prometheus.MustNewConstMetric(sloComplianceDesc, prometheus.GaugeValue, status.Compliance(), labels...)
+			ch <- prometheus.MustNewConstMetric(sloErrorBudgetDesc, prometheus.GaugeValue, status.ErrorBudgetRemaining(), labels...)
+			for burnWindow, rate := range status.BurnRates {
+				ch <- prometheus.MustNewConstMetric(sloBurnRateDesc, prometheus.GaugeValue, rate, append(labels, burnWindow)...)
+			}
+		}
+	}
+}
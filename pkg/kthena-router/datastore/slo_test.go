@@ -0,0 +1,127 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"istio.io/istio/pkg/util/sets"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+func newSLOModelServer(window time.Duration) *aiv1alpha1.ModelServer {
+	return &aiv1alpha1.ModelServer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "llama"},
+		Spec: aiv1alpha1.ModelServerSpec{
+			SLO: &aiv1alpha1.ServiceLevelObjective{
+				Window:       &metav1.Duration{Duration: window},
+				Availability: &aiv1alpha1.AvailabilityObjective{Target: "99"},
+				Latency:      &aiv1alpha1.LatencyObjective{Target: "90", Threshold: metav1.Duration{Duration: time.Second}},
+			},
+		},
+	}
+}
+
+func TestSLOStateWindow(t *testing.T) {
+	var state sloState
+	window := time.Hour
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		state.record(window, start.Add(time.Duration(i)*time.Minute), i == 0, i == 1)
+	}
+	assert.Equal(t, sloBucket{Requests: 10, Failed: 1, Slow: 1}, state.sum(window, start.Add(10*time.Minute), window))
+	// Only the buckets within the span are summed
+	assert.Equal(t, uint64(5), state.sum(window, start.Add(9*time.Minute), 5*time.Minute).Requests)
+
+	// The requests older than the window are forgotten
+	later := start.Add(65 * time.Minute)
+	state.record(window, later, false, false)
+	assert.Equal(t, sloBucket{Requests: 5}, state.sum(window, later, window))
+
+	// A new window drops the indicators measured so far
+	state.record(2*time.Hour, later, false, false)
+	assert.Equal(t, uint64(1), state.sum(2*time.Hour, later, 2*time.Hour).Requests)
+}
+
+func TestRecordSLO(t *testing.T) {
+	s := New()
+	ms := newSLOModelServer(time.Hour)
+	name := types.NamespacedName{Namespace: "default", Name: "llama"}
+	require.NoError(t, s.AddOrUpdateModelServer(ms, sets.New[types.NamespacedName]()))
+
+	assert.Equal(t, map[string]bool{SLOAvailability: false}, s.RecordSLO(name, true, time.Millisecond))
+	assert.Equal(t, map[string]bool{SLOAvailability: true, SLOLatency: false}, s.RecordSLO(name, false, 2*time.Second))
+	for i := 0; i < 98; i++ {
+		s.RecordSLO(name, i == 0, 100*time.Millisecond)
+	}
+
+	statuses := s.GetSLOStatus(name)
+	require.Len(t, statuses, 2)
+	availability, latency := statuses[0], statuses[1]
+	assert.Equal(t, SLOAvailability, availability.Name)
+	assert.Equal(t, uint64(100), availability.Total)
+	assert.Equal(t, uint64(2), availability.Bad)
+	assert.InDelta(t, 0.98, availability.Compliance(), 1e-9)
+	// Twice the budget of 1% of the requests is spent
+	assert.InDelta(t, -1, availability.ErrorBudgetRemaining(), 1e-9)
+	assert.InDelta(t, 2, availability.BurnRates["1h"], 1e-9)
+	assert.InDelta(t, 2, availability.BurnRates["6h"], 1e-9)
+
+	// The failed requests are not measured against the latency objective
+	assert.Equal(t, SLOLatency, latency.Name)
+	assert.Equal(t, uint64(98), latency.Total)
+	assert.Equal(t, uint64(1), latency.Bad)
+	assert.InDelta(t, 1-(1.0/98)/0.1, latency.ErrorBudgetRemaining(), 1e-9)
+
+	// A model server without objectives is not measured
+	ms = ms.DeepCopy()
+	ms.Spec.SLO = nil
+	require.NoError(t, s.AddOrUpdateModelServer(ms, sets.New[types.NamespacedName]()))
+	assert.Nil(t, s.RecordSLO(name, true, 0))
+	assert.Nil(t, s.GetSLOStatus(name))
+}
+
+func TestSLOLearnedState(t *testing.T) {
+	name := types.NamespacedName{Namespace: "default", Name: "llama"}
+	s := New().(*store)
+	require.NoError(t, s.AddOrUpdateModelServer(newSLOModelServer(time.Hour), sets.New[types.NamespacedName]()))
+	for i := 0; i < 10; i++ {
+		s.RecordSLO(name, i < 3, 0)
+	}
+	data, err := (&sloLearnedState{store: s}).Save()
+	require.NoError(t, err)
+
+	restored := New().(*store)
+	require.NoError(t, restored.AddOrUpdateModelServer(newSLOModelServer(time.Hour), sets.New[types.NamespacedName]()))
+	require.NoError(t, (&sloLearnedState{store: restored}).Restore(data))
+	statuses := restored.GetSLOStatus(name)
+	require.NotEmpty(t, statuses)
+	assert.Equal(t, uint64(10), statuses[0].Total)
+	assert.Equal(t, uint64(3), statuses[0].Bad)
+
+	// The indicators measured over another window are dropped
+	other := New().(*store)
+	require.NoError(t, other.AddOrUpdateModelServer(newSLOModelServer(24*time.Hour), sets.New[types.NamespacedName]()))
+	require.NoError(t, (&sloLearnedState{store: other}).Restore(data))
+	assert.Equal(t, uint64(0), other.GetSLOStatus(name)[0].Total)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ttftStateName is the name of the recent times to first token of the model servers in the snapshots.
+const ttftStateName = "ttft"
+
+// LearnedState is a state the router learns from the requests it routes, e.g. the prompt prefixes cached by each
+// pod. It is persisted across restarts, so that a restarted replica routes as well as the others right away.
+type LearnedState interface {
+	// Name identifies the state in the snapshots.
+	Name() string
+	// Save encodes the state.
+	Save() ([]byte, error)
+	// Restore loads a state saved by a previous replica. It is called once the store has synced, so that the
+	// state of the pods and model servers which are gone is dropped.
+	Restore(data []byte) error
+}
+
+// learnedStates holds the learned states registered by their owners.
+type learnedStates struct {
+	mutex  sync.RWMutex
+	states map[string]LearnedState
+}
+
+// RegisterState registers a learned state to persist. A state registered again under the same name,
+// e.g. by a scheduler rebuilt on a configuration reload, replaces the previous one.
+func (s *store) RegisterState(state LearnedState) {
+	s.learned.mutex.Lock()
+	defer s.learned.mutex.Unlock()
+	if s.learned.states == nil {
+		s.learned.states = make(map[string]LearnedState)
+	}
+	s.learned.states[state.Name()] = state
+}
+
+// LearnedStates returns the registered learned states, sorted by name.
+func (s *store) LearnedStates() []LearnedState {
+	s.learned.mutex.RLock()
+	defer s.learned.mutex.RUnlock()
+	states := make([]LearnedState, 0, len(s.learned.states))
+	for _, state := range s.learned.states {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name() < states[j].Name()
+	})
+	return states
+}
+
+// ttftState persists the recent times to first token of the model servers, which set the delays of the hedged requests.
+type ttftState struct {
+	store *store
+}
+
+func (t *ttftState) Name() string {
+	return ttftStateName
+}
+
+func (t *ttftState) Save() ([]byte, error) {
+	ttfts := make(map[string][]time.Duration)
+	t.store.modelServer.Range(func(key, value any) bool {
+		if samples := value.(*modelServer).hedging.samples(); len(samples) > 0 {
+			ttfts[key.(types.NamespacedName).String()] = samples
+		}
+		return true
+	})
+	return json.Marshal(ttfts)
+}
+
+func (t *ttftState) Restore(data []byte) error {
+	var ttfts map[string][]time.Duration
+	if err := json.Unmarshal(data, &ttfts); err != nil {
+		return err
+	}
+	t.store.modelServer.Range(func(key, value any) bool {
+		for _, ttft := range ttfts[key.(types.NamespacedName).String()] {
+			value.(*modelServer).hedging.recordTTFT(ttft)
+		}
+		return true
+	})
+	return nil
+}
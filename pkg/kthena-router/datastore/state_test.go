@@ -0,0 +1,100 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"istio.io/istio/pkg/util/sets"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+func TestTTFTStateSaveRestore(t *testing.T) {
+	name := types.NamespacedName{Namespace: "default", Name: "ms-1"}
+	gone := types.NamespacedName{Namespace: "default", Name: "ms-gone"}
+	newStore := func(names ...types.NamespacedName) Store {
+		s := New()
+		for _, n := range names {
+			ms := &aiv1alpha1.ModelServer{ObjectMeta: metav1.ObjectMeta{Namespace: n.Namespace, Name: n.Name}}
+			require.NoError(t, s.AddOrUpdateModelServer(ms, sets.New[types.NamespacedName]()))
+		}
+		return s
+	}
+
+	old := newStore(name, gone)
+	// Fill the window past its size, so that the ring buffer has wrapped around
+	for i := 1; i <= ttftWindowSize+10; i++ {
+		old.RecordTimeToFirstToken(name, time.Duration(i)*time.Millisecond)
+		old.RecordTimeToFirstToken(gone, time.Second)
+	}
+	states := old.LearnedStates()
+	require.Len(t, states, 2)
+	assert.Equal(t, sloStateName, states[0].Name())
+	assert.Equal(t, ttftStateName, states[1].Name())
+	data, err := states[1].Save()
+	require.NoError(t, err)
+
+	restarted := newStore(name)
+	_, ok := restarted.GetTimeToFirstTokenPercentile(name, 50)
+	assert.False(t, ok)
+	require.NoError(t, restarted.LearnedStates()[1].Restore(data))
+
+	for _, percentile := range []int32{50, 95} {
+		want, _ := old.GetTimeToFirstTokenPercentile(name, percentile)
+		got, ok := restarted.GetTimeToFirstTokenPercentile(name, percentile)
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+	// The latest samples are kept in order, the oldest restored samples are replaced first
+	for i := 0; i < ttftWindowSize/2+1; i++ {
+		restarted.RecordTimeToFirstToken(name, time.Hour)
+	}
+	p50, _ := restarted.GetTimeToFirstTokenPercentile(name, 50)
+	assert.Equal(t, time.Hour, p50)
+	_, ok = restarted.GetTimeToFirstTokenPercentile(gone, 50)
+	assert.False(t, ok)
+}
+
+type namedState struct {
+	name string
+}
+
+func (n *namedState) Name() string              { return n.name }
+func (n *namedState) Save() ([]byte, error)     { return nil, nil }
+func (n *namedState) Restore(data []byte) error { return nil }
+
+func TestRegisterState(t *testing.T) {
+	s := New()
+	first := &namedState{name: "prefix-cache"}
+	s.RegisterState(first)
+	s.RegisterState(&namedState{name: "a"})
+	replacement := &namedState{name: "prefix-cache"}
+	s.RegisterState(replacement)
+
+	states := s.LearnedStates()
+	require.Len(t, states, 4)
+	assert.Equal(t, "a", states[0].Name())
+	assert.Same(t, replacement, states[1])
+	assert.Equal(t, sloStateName, states[2].Name())
+	assert.Equal(t, ttftStateName, states[3].Name())
+}
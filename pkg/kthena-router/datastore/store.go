@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -58,7 +59,6 @@ var (
 const (
 	// Configuration constants for fairness scheduling
 	defaultQueueQPS = 100
-	uppdateInterval = 1 * time.Second
 )
 
 // createTokenTracker creates a token tracker with configuration from environment variables
@@ -139,6 +139,10 @@ type Store interface {
 	AddOrUpdatePod(pod *corev1.Pod, modelServer []*aiv1alpha1.ModelServer) error
 	// Refresh Store and ModelServer when delete a pod
 	DeletePod(podName types.NamespacedName) error
+	// AddOrUpdateNode records the accelerator of the node and refreshes the pods running on it
+	AddOrUpdateNode(node *corev1.Node) error
+	// DeleteNode forgets the accelerator of the node
+	DeleteNode(nodeName string) error
 
 	// New methods for routing functionality
 	MatchModelServer(modelName string, request *http.Request) (types.NamespacedName, bool, *aiv1alpha1.ModelRoute, error)
@@ -155,6 +159,10 @@ type Store interface {
 
 	// New methods for callback management
 	RegisterCallback(kind string, callback CallbackFunc)
+	// RegisterState registers a learned state persisted across restarts
+	RegisterState(state LearnedState)
+	// LearnedStates returns the registered learned states
+	LearnedStates() []LearnedState
 	// Run to update pod info periodically
 	Run(context.Context)
 
@@ -168,6 +176,23 @@ type Store interface {
 	// UpdateTokenCount updates token usage for a user and model
 	UpdateTokenCount(userId, modelName string, inputTokens, outputTokens float64) error
 
+	// RecordTimeToFirstToken records the time to first token of a request to the model server, as observed by the router
+	RecordTimeToFirstToken(modelServerName types.NamespacedName, ttft time.Duration)
+	// GetTimeToFirstTokenPercentile returns a percentile of the recent times to first token of the model server,
+	// false until enough of them have been recorded
+	GetTimeToFirstTokenPercentile(modelServerName types.NamespacedName, percentile int32) (time.Duration, bool)
+	// RecordHedgedRequest accounts a request duplicated to a second pod, won reports whether the duplicate answered first
+	RecordHedgedRequest(modelServerName types.NamespacedName, won bool, duplicateTokens int)
+	// GetHedgingStats returns the hedged requests of the model server
+	GetHedgingStats(modelServerName types.NamespacedName) HedgingStats
+
+	// RecordSLO measures a request against the service level objectives of the model server. failed reports
+	// that the request was not served or answered with a server error, latency is the time to its first byte of
+	// response. It returns whether the request met each objective it was measured against, by name.
+	RecordSLO(modelServerName types.NamespacedName, failed bool, latency time.Duration) map[string]bool
+	// GetSLOStatus returns the compliance of the model server with its service level objectives, nil without objectives
+	GetSLOStatus(modelServerName types.NamespacedName) []SLOStatus
+
 	// Enqueue adds a request to the fair queue
 	Enqueue(*Request) error
 
@@ -200,10 +225,27 @@ type PodInfo struct {
 	TPOT               float64
 	TTFT               float64
 
-	mutex sync.RWMutex // Protects concurrent access to Models and modelServer fields
+	mutex sync.RWMutex // Protects concurrent access to Models and modelServer fields, and serializes metrics updates
 	// Protected fields - use accessor methods for thread-safe access
 	models      sets.Set[string]               // running models. Including base model and lora adapters.
 	modelServer sets.Set[types.NamespacedName] // The modelservers this pod belongs to
+	// modelMetrics are the metrics of each model of the pods backing several ModelServers, when their
+	// engine labels its metrics by model.
+	modelMetrics map[string]*ModelMetrics
+	// accelerator is the accelerator of the node of the pod, from its labels.
+	accelerator string
+}
+
+// ModelMetrics are the metrics of one of the models served by a pod.
+type ModelMetrics struct {
+	GPUCacheUsage     float64
+	RequestWaitingNum float64
+	RequestRunningNum float64
+	TPOT              float64
+	TTFT              float64
+
+	timeToFirstToken   *dto.Histogram
+	timePerOutputToken *dto.Histogram
 }
 
 // modelRouteInfo stores the mapping between a ModelRoute resource and its associated models.
@@ -221,12 +263,18 @@ type modelRouteInfo struct {
 type store struct {
 	modelServer sync.Map // map[types.NamespacedName]*modelServer
 	pods        sync.Map // map[types.NamespacedName]*PodInfo
+	// nodeAccelerators are the accelerators of the nodes labeled with one
+	nodeAccelerators sync.Map // map[string]string
 
 	routeMutex sync.RWMutex
 	// Model routing fields
 	routeInfo  map[string]*modelRouteInfo
 	routes     map[string]*aiv1alpha1.ModelRoute
 	loraRoutes map[string]*aiv1alpha1.ModelRoute
+	// namespaceRoutes and namespaceLoraRoutes index the routes by namespace, then by model or LoRA adapter,
+	// as the same model may be routed differently in several namespaces.
+	namespaceRoutes     map[string]map[string]*aiv1alpha1.ModelRoute
+	namespaceLoraRoutes map[string]map[string]*aiv1alpha1.ModelRoute
 
 	// New fields for callback management
 	callbacks map[string][]CallbackFunc
@@ -236,42 +284,32 @@ type store struct {
 	// model -> RequestPriorityQueue
 	requestWaitingQueue sync.Map
 	tokenTracker        TokenTracker
+	// learned holds the states persisted across restarts
+	learned learnedStates
 }
 
 func New() Store {
-	return &store{
+	s := &store{
 		modelServer:         sync.Map{},
 		pods:                sync.Map{},
 		routeInfo:           make(map[string]*modelRouteInfo),
 		routes:              make(map[string]*aiv1alpha1.ModelRoute),
 		loraRoutes:          make(map[string]*aiv1alpha1.ModelRoute),
+		namespaceRoutes:     make(map[string]map[string]*aiv1alpha1.ModelRoute),
+		namespaceLoraRoutes: make(map[string]map[string]*aiv1alpha1.ModelRoute),
 		callbacks:           make(map[string][]CallbackFunc),
 		initialSynced:       &atomic.Bool{},
 		requestWaitingQueue: sync.Map{},
 		// Create token tracker with environment-based configuration
 		tokenTracker: createTokenTracker(),
 	}
+	s.RegisterState(&ttftState{store: s})
+	s.RegisterState(&sloLearnedState{store: s})
+	return s
 }
 
 func (s *store) Run(ctx context.Context) {
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			default:
-				s.pods.Range(func(key, value any) bool {
-					if p, ok := value.(*PodInfo); ok {
-						s.updatePodMetrics(p)
-						s.updatePodModels(p)
-					}
-					return true
-				})
-				s.initialSynced.Store(true)
-				time.Sleep(uppdateInterval)
-			}
-		}
-	}()
+	go newMetricsFetcher(s).run(ctx)
 }
 func (s *store) GetTokenCount(userID, model string) (float64, error) {
 	return s.tokenTracker.GetTokenCount(userID, model)
@@ -338,12 +376,12 @@ func (s *store) AddOrUpdateModelServer(ms *aiv1alpha1.ModelServer, pods sets.Set
 		modelServerObj = newModelServer(ms)
 	} else {
 		modelServerObj = value.(*modelServer)
-		modelServerObj.modelServer = ms
+		modelServerObj.setModelServer(ms)
 	}
 
 	if len(pods) != 0 {
 		// do not operate s.pods here, which are done within pod handler
-		modelServerObj.pods = pods
+		modelServerObj.setPods(pods)
 	}
 	s.modelServer.Store(name, modelServerObj)
 	return nil
@@ -470,6 +508,9 @@ func (s *store) AddOrUpdatePod(pod *corev1.Pod, modelServers []*aiv1alpha1.Model
 		modelServer: sets.Set[types.NamespacedName]{},
 		models:      sets.New[string](),
 	}
+	if accelerator, ok := s.nodeAccelerators.Load(pod.Spec.NodeName); ok {
+		newPodInfo.accelerator = accelerator.(string)
+	}
 
 	for _, ms := range modelServers {
 		modelServerName := utils.GetNamespaceName(ms)
@@ -499,6 +540,11 @@ func (s *store) AddOrUpdatePod(pod *corev1.Pod, modelServers []*aiv1alpha1.Model
 		}
 	}
 
+	if oldPodInfo != nil {
+		// Keep the metrics and models scraped so far, they are refreshed by the metrics fetcher.
+		oldPodInfo.copyRuntimeInfoTo(newPodInfo)
+	}
+
 	s.pods.Store(podName, newPodInfo)
 
 	if oldPodInfo == nil {
@@ -535,9 +581,36 @@ func (s *store) DeletePod(podName types.NamespacedName) error {
 }
 
 // Model routing methods
+func (s *store) AddOrUpdateNode(node *corev1.Node) error {
+	accelerator := AcceleratorOf(node)
+	if accelerator == "" {
+		s.nodeAccelerators.Delete(node.Name)
+	} else {
+		s.nodeAccelerators.Store(node.Name, accelerator)
+	}
+	s.pods.Range(func(_, value any) bool {
+		if pod := value.(*PodInfo); pod.Pod.Spec.NodeName == node.Name {
+			pod.setAccelerator(accelerator)
+		}
+		return true
+	})
+	return nil
+}
+
+func (s *store) DeleteNode(nodeName string) error {
+	s.nodeAccelerators.Delete(nodeName)
+	return nil
+}
+
 func (s *store) AddOrUpdateModelRoute(mr *aiv1alpha1.ModelRoute) error {
 	s.routeMutex.Lock()
 	key := mr.Namespace + "/" + mr.Name
+	// The models of the previous version of the route are no longer routed by it
+	s.removeRouteLocked(key)
+	if s.namespaceRoutes == nil {
+		s.namespaceRoutes = make(map[string]map[string]*aiv1alpha1.ModelRoute)
+		s.namespaceLoraRoutes = make(map[string]map[string]*aiv1alpha1.ModelRoute)
+	}
 	s.routeInfo[key] = &modelRouteInfo{
 		model: mr.Spec.ModelName,
 		loras: mr.Spec.LoraAdapters,
@@ -545,10 +618,12 @@ func (s *store) AddOrUpdateModelRoute(mr *aiv1alpha1.ModelRoute) error {
 
 	if mr.Spec.ModelName != "" {
 		s.routes[mr.Spec.ModelName] = mr
+		indexRoute(s.namespaceRoutes, mr.Namespace, mr.Spec.ModelName, mr)
 	}
 
 	for _, lora := range mr.Spec.LoraAdapters {
 		s.loraRoutes[lora] = mr
+		indexRoute(s.namespaceLoraRoutes, mr.Namespace, lora, mr)
 	}
 	s.routeMutex.Unlock()
 
@@ -566,12 +641,8 @@ func (s *store) DeleteModelRoute(namespacedName string) error {
 	var modelName string
 	if info != nil {
 		modelName = info.model
-		delete(s.routes, info.model)
-		for _, lora := range info.loras {
-			delete(s.loraRoutes, lora)
-		}
 	}
-	delete(s.routeInfo, namespacedName)
+	s.removeRouteLocked(namespacedName)
 	s.routeMutex.Unlock()
 	if modelName != "" {
 		// Clean up associated waiting queue if exists
@@ -592,21 +663,84 @@ func (s *store) DeleteModelRoute(namespacedName string) error {
 	return nil
 }
 
+// removeRouteLocked removes the route from the indexes. A model routed by the route in all the namespaces
+// falls back to the route of another namespace, if any. The route mutex must be held.
+func (s *store) removeRouteLocked(namespacedName string) {
+	info := s.routeInfo[namespacedName]
+	if info == nil {
+		return
+	}
+	namespace, name, _ := strings.Cut(namespacedName, "/")
+	if info.model != "" {
+		unindexRoute(s.routes, s.namespaceRoutes, namespace, name, info.model)
+	}
+	for _, lora := range info.loras {
+		unindexRoute(s.loraRoutes, s.namespaceLoraRoutes, namespace, name, lora)
+	}
+	delete(s.routeInfo, namespacedName)
+}
+
+func indexRoute(index map[string]map[string]*aiv1alpha1.ModelRoute, namespace, model string, mr *aiv1alpha1.ModelRoute) {
+	routes := index[namespace]
+	if routes == nil {
+		routes = make(map[string]*aiv1alpha1.ModelRoute)
+		index[namespace] = routes
+	}
+	routes[model] = mr
+}
+
+// unindexRoute removes the route of the model in the namespace, unless another route of the namespace replaced it.
+// The route of the model in all the namespaces is replaced by the route of the first other namespace routing it,
+// in alphabetical order.
+func unindexRoute(global map[string]*aiv1alpha1.ModelRoute, index map[string]map[string]*aiv1alpha1.ModelRoute, namespace, name, model string) {
+	routes := index[namespace]
+	mr := routes[model]
+	if mr == nil || mr.Name != name {
+		return
+	}
+	delete(routes, model)
+	if len(routes) == 0 {
+		delete(index, namespace)
+	}
+	if global[model] != mr {
+		return
+	}
+	delete(global, model)
+	for _, ns := range sortedKeys(index) {
+		if other, ok := index[ns][model]; ok {
+			global[model] = other
+			return
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MatchModelServer matches the model server of a request for the model, among the ModelRoutes of the route scope
+// of the request context.
 func (s *store) MatchModelServer(model string, req *http.Request) (types.NamespacedName, bool, *aiv1alpha1.ModelRoute, error) {
 	s.routeMutex.RLock()
 	defer s.routeMutex.RUnlock()
 
+	scope := RouteScopeFrom(req.Context())
 	var isLora bool
-	mr, ok := s.routes[model]
+	mr, ok := scope.lookup(s.routes, s.namespaceRoutes, model)
 	if !ok {
-		mr, ok = s.loraRoutes[model]
+		mr, ok = scope.lookup(s.loraRoutes, s.namespaceLoraRoutes, model)
 		if !ok {
 			return types.NamespacedName{}, false, nil, fmt.Errorf("not found route rules for model %s", model)
 		}
 		isLora = true
 	}
 
-	rule, err := s.selectRule(model, req, mr.Spec.Rules)
+	rule, err := SelectRule(model, req, mr.Spec.Rules)
 	if err != nil {
 		return types.NamespacedName{}, false, nil, fmt.Errorf("failed to select route rule: %v", err)
 	}
@@ -619,7 +753,8 @@ func (s *store) MatchModelServer(model string, req *http.Request) (types.Namespa
 	return types.NamespacedName{Namespace: mr.Namespace, Name: dst.ModelServerName}, isLora, mr, nil
 }
 
-func (s *store) selectRule(modelName string, req *http.Request, rules []*aiv1alpha1.Rule) (*aiv1alpha1.Rule, error) {
+// SelectRule returns the first rule matching the request of the model.
+func SelectRule(modelName string, req *http.Request, rules []*aiv1alpha1.Rule) (*aiv1alpha1.Rule, error) {
 	for _, rule := range rules {
 		if rule.ModelMatch == nil {
 			return rule, nil
@@ -737,10 +872,33 @@ func (s *store) updatePodMetrics(pod *PodInfo) {
 		return
 	}
 
+	pod.mutex.RLock()
 	previousHistogram := getPreviousHistogram(pod)
+	// The pods backing several ModelServers may serve several models, whose metrics are kept apart
+	multiModel := len(pod.modelServer) > 1
+	previousModelHistogram := getPreviousModelHistogram(pod)
+	pod.mutex.RUnlock()
+	if multiModel {
+		gaugeMetrics, histogramMetrics, modelMetrics := backend.GetPodModelMetrics(pod.engine, pod.Pod, previousHistogram, previousModelHistogram)
+
+		pod.mutex.Lock()
+		defer pod.mutex.Unlock()
+		updateGaugeMetricsInfo(pod, gaugeMetrics)
+		updateHistogramMetrics(pod, histogramMetrics)
+		// A failed scrape keeps the metrics of the models
+		if gaugeMetrics != nil {
+			updateModelMetrics(pod, modelMetrics)
+		}
+		return
+	}
+	// Scrape without holding the lock, then merge the result into the pod info.
 	gaugeMetrics, histogramMetrics := backend.GetPodMetrics(pod.engine, pod.Pod, previousHistogram)
+
+	pod.mutex.Lock()
+	defer pod.mutex.Unlock()
 	updateGaugeMetricsInfo(pod, gaugeMetrics)
 	updateHistogramMetrics(pod, histogramMetrics)
+	pod.modelMetrics = nil
 }
 
 func (s *store) updatePodModels(podInfo *PodInfo) {
@@ -768,6 +926,72 @@ func getPreviousHistogram(podinfo *PodInfo) map[string]*dto.Histogram {
 	return previousHistogram
 }
 
+func getPreviousModelHistogram(podinfo *PodInfo) map[string]map[string]*dto.Histogram {
+	if len(podinfo.modelMetrics) == 0 {
+		return nil
+	}
+	previousHistogram := make(map[string]map[string]*dto.Histogram, len(podinfo.modelMetrics))
+	for model, metrics := range podinfo.modelMetrics {
+		histograms := make(map[string]*dto.Histogram)
+		if metrics.timePerOutputToken != nil {
+			histograms[utils.TPOT] = metrics.timePerOutputToken
+		}
+		if metrics.timeToFirstToken != nil {
+			histograms[utils.TTFT] = metrics.timeToFirstToken
+		}
+		previousHistogram[model] = histograms
+	}
+	return previousHistogram
+}
+
+// updateModelMetrics replaces the metrics of the models of the pod with the scraped ones, like
+// updateGaugeMetricsInfo and updateHistogramMetrics do for the pod. The models not scraped anymore are removed.
+func updateModelMetrics(podinfo *PodInfo, modelMetrics map[string]backend.ModelMetrics) {
+	if len(modelMetrics) == 0 {
+		podinfo.modelMetrics = nil
+		return
+	}
+	updated := make(map[string]*ModelMetrics, len(modelMetrics))
+	for model, scraped := range modelMetrics {
+		metrics := &ModelMetrics{}
+		if previous, ok := podinfo.modelMetrics[model]; ok {
+			*metrics = *previous
+		}
+		metrics.update(scraped.Gauges, scraped.Histograms)
+		updated[model] = metrics
+	}
+	podinfo.modelMetrics = updated
+}
+
+func (m *ModelMetrics) update(gauges map[string]float64, histograms map[string]*dto.Histogram) {
+	for name, value := range gauges {
+		switch name {
+		case utils.GPUCacheUsage:
+			m.GPUCacheUsage = value
+		case utils.RequestWaitingNum:
+			m.RequestWaitingNum = value
+		case utils.RequestRunningNum:
+			m.RequestRunningNum = value
+		case utils.TPOT:
+			if value != 0 {
+				m.TPOT = value
+			}
+		case utils.TTFT:
+			if value != 0 {
+				m.TTFT = value
+			}
+		}
+	}
+	if h := histograms[utils.TPOT]; h != nil {
+		m.timePerOutputToken = h
+	}
+	if h := histograms[utils.TTFT]; h != nil {
+		m.timeToFirstToken = h
+	}
+}
+
+// updateGaugeMetricsInfo merges the scraped metrics into the pod info.
+// Metrics missing from the scrape, e.g. because it failed, keep their previous value.
 func updateGaugeMetricsInfo(podinfo *PodInfo, metricsInfo map[string]float64) {
 	updateFuncs := map[string]func(float64){
 		utils.GPUCacheUsage: func(f float64) {
@@ -794,8 +1018,12 @@ func updateGaugeMetricsInfo(podinfo *PodInfo, metricsInfo map[string]float64) {
 	}
 
 	for _, name := range metricsName {
+		value, scraped := metricsInfo[name]
+		if !scraped {
+			continue
+		}
 		if updateFunc, exist := updateFuncs[name]; exist {
-			updateFunc(metricsInfo[name])
+			updateFunc(value)
 		} else {
 			klog.V(4).Infof("Unknown metric: %s", name)
 		}
@@ -813,8 +1041,12 @@ func updateHistogramMetrics(podinfo *PodInfo, histogramMetrics map[string]*dto.H
 	}
 
 	for _, name := range histogramMetricsName {
+		histogram, scraped := histogramMetrics[name]
+		if !scraped || histogram == nil {
+			continue
+		}
 		if updateFunc, exist := updateFuncs[name]; exist {
-			updateFunc(histogramMetrics[name])
+			updateFunc(histogram)
 		} else {
 			klog.V(4).Infof("Unknown histogram metric: %s", name)
 		}
@@ -841,6 +1073,68 @@ func (s *store) triggerCallbacks(kind string, data EventData) {
 
 // PodInfo methods for thread-safe access to models and modelServer fields
 
+// copyRuntimeInfoTo copies the metrics and models of the pod to another pod info of the same pod.
+func (p *PodInfo) copyRuntimeInfoTo(dst *PodInfo) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	dst.GPUCacheUsage = p.GPUCacheUsage
+	dst.RequestWaitingNum = p.RequestWaitingNum
+	dst.RequestRunningNum = p.RequestRunningNum
+	dst.TimeToFirstToken = p.TimeToFirstToken
+	dst.TimePerOutputToken = p.TimePerOutputToken
+	dst.TPOT = p.TPOT
+	dst.TTFT = p.TTFT
+	dst.models = p.models.Copy()
+	dst.modelMetrics = p.modelMetrics
+}
+
+// MetricsOf returns the metrics of the model when the pod serves several models and its engine reports them
+// by model, otherwise the metrics of the pod.
+func (p *PodInfo) MetricsOf(model string) ModelMetrics {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if metrics, ok := p.modelMetrics[model]; ok {
+		return *metrics
+	}
+	return ModelMetrics{
+		GPUCacheUsage:     p.GPUCacheUsage,
+		RequestWaitingNum: p.RequestWaitingNum,
+		RequestRunningNum: p.RequestRunningNum,
+		TPOT:              p.TPOT,
+		TTFT:              p.TTFT,
+	}
+}
+
+// ModelMetricsList returns the names of the models whose metrics are reported apart, sorted.
+func (p *PodInfo) ModelMetricsList() []string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	models := make([]string, 0, len(p.modelMetrics))
+	for model := range p.modelMetrics {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+	return models
+}
+
+// Accelerator returns the accelerator of the node of the pod, empty when its node is not labeled with one.
+func (p *PodInfo) Accelerator() string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.accelerator
+}
+
+func (p *PodInfo) setAccelerator(accelerator string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.accelerator = accelerator
+}
+
+// Engine returns the inference engine of the pod, set from its ModelServer when the pod is added.
+func (p *PodInfo) Engine() string {
+	return p.engine
+}
+
 // GetModels returns a copy of the models set
 func (p *PodInfo) GetModels() sets.Set[string] {
 	p.mutex.RLock()
@@ -861,6 +1155,25 @@ func (p *PodInfo) Contains(model string) bool {
 	return p.models != nil && p.models.Contains(model)
 }
 
+// NumModels returns the number of models served by the pod
+func (p *PodInfo) NumModels() int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.models.Len()
+}
+
+// AddModel adds a model to the models set
+func (p *PodInfo) AddModel(model string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.models == nil {
+		p.models = sets.New[string]()
+	}
+	p.models.Insert(model)
+}
+
 // UpdateModels updates the models set with a new list of models
 func (p *PodInfo) UpdateModels(models []string) {
 	p.mutex.Lock()
@@ -964,18 +1277,9 @@ func (s *store) GetAllModelRoutes() map[string]*aiv1alpha1.ModelRoute {
 	defer s.routeMutex.RUnlock()
 
 	result := make(map[string]*aiv1alpha1.ModelRoute)
-	for key, info := range s.routeInfo {
-		if info.model != "" {
-			if route, ok := s.routes[info.model]; ok {
-				result[key] = route
-			}
-		}
-		// Also check lora routes
-		for _, lora := range info.loras {
-			if route, ok := s.loraRoutes[lora]; ok {
-				result[key] = route
-				break // Same route for all loras in a ModelRoute
-			}
+	for key := range s.routeInfo {
+		if route := s.getModelRouteLocked(key); route != nil {
+			result[key] = route
 		}
 	}
 	return result
@@ -1013,25 +1317,26 @@ func (s *store) GetAllPods() map[types.NamespacedName]*PodInfo {
 func (s *store) GetModelRoute(namespacedName string) *aiv1alpha1.ModelRoute {
 	s.routeMutex.RLock()
 	defer s.routeMutex.RUnlock()
+	return s.getModelRouteLocked(namespacedName)
+}
 
+// getModelRouteLocked finds the route in the indexes of its namespace, from its primary model or its LoRA adapters.
+func (s *store) getModelRouteLocked(namespacedName string) *aiv1alpha1.ModelRoute {
 	info, exists := s.routeInfo[namespacedName]
 	if !exists {
 		return nil
 	}
+	namespace, _, _ := strings.Cut(namespacedName, "/")
 
-	// Try to find the route from the primary model
 	if info.model != "" {
-		if route, ok := s.routes[info.model]; ok {
+		if route, ok := s.namespaceRoutes[namespace][info.model]; ok {
 			return route
 		}
 	}
-
-	// Try to find the route from lora adapters
 	for _, lora := range info.loras {
-		if route, ok := s.loraRoutes[lora]; ok {
+		if route, ok := s.namespaceLoraRoutes[namespace][lora]; ok {
 			return route
 		}
 	}
-
 	return nil
 }
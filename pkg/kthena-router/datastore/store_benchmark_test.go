@@ -0,0 +1,83 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+func newBenchmarkStore(b *testing.B, podCount int) (Store, *aiv1alpha1.ModelServer) {
+	store := New()
+	modelServer := createTestModelServer("default", "test-model", aiv1alpha1.VLLM)
+	if err := store.AddOrUpdateModelServer(modelServer, nil); err != nil {
+		b.Fatal(err)
+	}
+	for _, pod := range createBenchmarkTestPods(podCount) {
+		if err := store.AddOrUpdatePod(pod, []*aiv1alpha1.ModelServer{modelServer}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return store, modelServer
+}
+
+// BenchmarkGetPodsByModelServer benchmarks the scheduling hot path with concurrent readers
+func BenchmarkGetPodsByModelServer(b *testing.B) {
+	store, _ := newBenchmarkStore(b, 1000)
+	name := types.NamespacedName{Namespace: "default", Name: "test-model"}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = store.GetPodsByModelServer(name)
+		}
+	})
+}
+
+// BenchmarkGetPodsByModelServerWithUpdates benchmarks the scheduling hot path while the controllers keep updating pods
+func BenchmarkGetPodsByModelServerWithUpdates(b *testing.B) {
+	store, modelServer := newBenchmarkStore(b, 1000)
+	name := types.NamespacedName{Namespace: "default", Name: "test-model"}
+	updates := createBenchmarkTestPods(10)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				_ = store.AddOrUpdatePod(updates[i%len(updates)], []*aiv1alpha1.ModelServer{modelServer})
+			}
+		}
+	}()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = store.GetPodsByModelServer(name)
+		}
+	})
+	b.StopTimer()
+	close(stop)
+	<-done
+}
@@ -1217,3 +1217,58 @@ func TestStoreMatchModelServer(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdatePodMetricsOfSeveralModels(t *testing.T) {
+	s := New().(*store)
+	podinfo := &PodInfo{
+		Pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod1"}},
+		engine:      "vLLM",
+		modelServer: sets.New(types.NamespacedName{Namespace: "default", Name: "llama"}),
+	}
+
+	patch := gomonkey.NewPatches()
+	defer patch.Reset()
+	patch.ApplyFunc(backend.GetPodMetrics, func(string, *corev1.Pod, map[string]*dto.Histogram) (map[string]float64, map[string]*dto.Histogram) {
+		return map[string]float64{utils.RequestWaitingNum: 3}, nil
+	})
+	ttftSum, ttftCount := 4.0, uint64(8)
+	patch.ApplyFunc(backend.GetPodModelMetrics, func(string, *corev1.Pod, map[string]*dto.Histogram,
+		map[string]map[string]*dto.Histogram) (map[string]float64, map[string]*dto.Histogram, map[string]backend.ModelMetrics) {
+		return map[string]float64{utils.RequestWaitingNum: 5, utils.RequestRunningNum: 7},
+			nil,
+			map[string]backend.ModelMetrics{
+				"llama": {
+					Gauges:     map[string]float64{utils.RequestWaitingNum: 5, utils.RequestRunningNum: 1, utils.TTFT: 0.5},
+					Histograms: map[string]*dto.Histogram{utils.TTFT: {SampleSum: &ttftSum, SampleCount: &ttftCount}},
+				},
+				"qwen": {Gauges: map[string]float64{utils.RequestRunningNum: 6}},
+			}
+	})
+
+	// A pod of a single ModelServer has the metrics of the pod only
+	s.updatePodMetrics(podinfo)
+	assert.Equal(t, float64(3), podinfo.RequestWaitingNum)
+	assert.Empty(t, podinfo.ModelMetricsList())
+	assert.Equal(t, float64(3), podinfo.MetricsOf("llama").RequestWaitingNum)
+
+	podinfo.AddModelServer(types.NamespacedName{Namespace: "default", Name: "qwen"})
+	s.updatePodMetrics(podinfo)
+	assert.Equal(t, float64(5), podinfo.RequestWaitingNum)
+	assert.Equal(t, float64(7), podinfo.RequestRunningNum)
+	assert.Equal(t, []string{"llama", "qwen"}, podinfo.ModelMetricsList())
+	assert.Equal(t, ModelMetrics{RequestWaitingNum: 5, RequestRunningNum: 1, TTFT: 0.5,
+		timeToFirstToken: &dto.Histogram{SampleSum: &ttftSum, SampleCount: &ttftCount}}, podinfo.MetricsOf("llama"))
+	assert.Equal(t, float64(6), podinfo.MetricsOf("qwen").RequestRunningNum)
+	// The models without metrics of their own have the metrics of the pod
+	assert.Equal(t, float64(7), podinfo.MetricsOf("mistral").RequestRunningNum)
+
+	// The histograms of each model are passed to the next scrape
+	previousModelHistogram := getPreviousModelHistogram(podinfo)
+	assert.Equal(t, &ttftCount, previousModelHistogram["llama"][utils.TTFT].SampleCount)
+	assert.Empty(t, previousModelHistogram["qwen"])
+
+	// The metrics of the models are dropped once the pod backs a single ModelServer again
+	podinfo.RemoveModelServer(types.NamespacedName{Namespace: "default", Name: "qwen"})
+	s.updatePodMetrics(podinfo)
+	assert.Empty(t, podinfo.ModelMetricsList())
+}
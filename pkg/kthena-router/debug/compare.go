@@ -0,0 +1,90 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/compare"
+)
+
+// CompareHandler provides the traffic compare report endpoints for the router
+type CompareHandler struct {
+	store *compare.Store
+}
+
+// NewCompareHandler creates a new traffic compare handler
+func NewCompareHandler(store *compare.Store) *CompareHandler {
+	return &CompareHandler{
+		store: store,
+	}
+}
+
+type CompareReportResponse struct {
+	*compare.Report
+	SampleList []*compare.Sample `json:"sampleList,omitempty"`
+}
+
+// ListReports handles GET /admin/compare/modelroutes
+func (h *CompareHandler) ListReports(c *gin.Context) {
+	reports := []*compare.Report{}
+	for _, modelRoute := range h.store.ModelRoutes() {
+		reports = append(reports, h.store.Report(modelRoute))
+	}
+	c.JSON(http.StatusOK, gin.H{"reports": reports})
+}
+
+// GetReport handles GET /admin/compare/namespaces/{namespace}/modelroutes/{name}
+// The compared samples are included when the `samples` query parameter is true.
+func (h *CompareHandler) GetReport(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	if namespace == "" || name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace and name parameters are required"})
+		return
+	}
+
+	modelRoute := namespace + "/" + name
+	samples := h.store.List(modelRoute)
+	response := CompareReportResponse{
+		Report: compare.BuildReport(modelRoute, samples),
+	}
+	if includeSamples, _ := strconv.ParseBool(c.Query("samples")); includeSamples {
+		response.SampleList = samples
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteSamples handles DELETE /admin/compare/namespaces/{namespace}/modelroutes/{name}
+// to start a new comparison, e.g. after the candidate has been updated.
+func (h *CompareHandler) DeleteSamples(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	if namespace == "" || name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace and name parameters are required"})
+		return
+	}
+
+	h.store.Delete(namespace + "/" + name)
+	c.Status(http.StatusNoContent)
+}
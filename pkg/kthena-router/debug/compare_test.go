@@ -0,0 +1,70 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/compare"
+)
+
+func TestCompareHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := compare.NewStore(10)
+	store.Add("default/route", &compare.Sample{
+		RequestID: "req-1",
+		Baseline:  compare.Result{Output: "hello", LatencyMilliseconds: 100},
+		Candidate: compare.Result{Output: "hello", LatencyMilliseconds: 50},
+	})
+	handler := NewCompareHandler(store)
+
+	engine := gin.New()
+	engine.GET("/admin/compare/modelroutes", handler.ListReports)
+	engine.GET("/admin/compare/namespaces/:namespace/modelroutes/:name", handler.GetReport)
+	engine.DELETE("/admin/compare/namespaces/:namespace/modelroutes/:name", handler.DeleteSamples)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/compare/modelroutes", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Reports []compare.Report `json:"reports"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list.Reports, 1)
+	assert.Equal(t, "default/route", list.Reports[0].ModelRoute)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/compare/namespaces/default/modelroutes/route?samples=true", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response CompareReportResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Samples)
+	assert.Equal(t, 1.0, response.ExactMatchRate)
+	assert.Equal(t, -50.0, response.AvgLatencyDeltaMilliseconds)
+	assert.Len(t, response.SampleList, 1)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/compare/namespaces/default/modelroutes/route", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, store.List("default/route"))
+}
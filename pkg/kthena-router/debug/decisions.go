@@ -0,0 +1,81 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+// DecisionHandler provides the scheduling decision endpoints for the router
+type DecisionHandler struct {
+	store *scheduler.DecisionStore
+}
+
+// NewDecisionHandler creates a new scheduling decision handler
+func NewDecisionHandler(store *scheduler.DecisionStore) *DecisionHandler {
+	return &DecisionHandler{
+		store: store,
+	}
+}
+
+// ListDecisions handles GET /admin/scheduling/decisions
+// The decisions of all models are returned newest first and can be narrowed down with the `model`,
+// `requestID` and `limit` query parameters, the limit applying to the merged decisions. Model names may contain slashes,
+// which is why the model is a query parameter rather than a path parameter.
+func (h *DecisionHandler) ListDecisions(c *gin.Context) {
+	models := h.store.Models()
+	if model := c.Query("model"); model != "" {
+		models = []string{model}
+	}
+
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a non-negative integer"})
+			return
+		}
+	}
+	requestID := c.Query("requestID")
+
+	decisions := []*framework.Decision{}
+	for _, model := range models {
+		for _, decision := range h.store.List(model) {
+			if requestID != "" && decision.RequestID != requestID {
+				continue
+			}
+			decisions = append(decisions, decision)
+		}
+	}
+	// The decisions of each model are newest first, the stable sort keeps that order for equal times.
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return decisions[i].Time.After(decisions[j].Time)
+	})
+	if limit > 0 && len(decisions) > limit {
+		decisions = decisions[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"decisions": decisions})
+}
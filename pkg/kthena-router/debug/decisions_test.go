@@ -0,0 +1,70 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+func TestDecisionHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := scheduler.NewDecisionStore(10)
+	now := time.Now()
+	store.Add(&framework.Decision{RequestID: "req-1", Model: "deepseek-ai/DeepSeek-R1", Time: now})
+	store.Add(&framework.Decision{RequestID: "req-2", Model: "deepseek-ai/DeepSeek-R1", Time: now.Add(2 * time.Second)})
+	store.Add(&framework.Decision{RequestID: "req-3", Model: "qwen", Time: now.Add(time.Second)})
+	handler := NewDecisionHandler(store)
+
+	engine := gin.New()
+	engine.GET("/admin/scheduling/decisions", handler.ListDecisions)
+
+	list := func(query string) []string {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/scheduling/decisions"+query, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Decisions []framework.Decision `json:"decisions"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		requestIDs := []string{}
+		for _, decision := range response.Decisions {
+			requestIDs = append(requestIDs, decision.RequestID)
+		}
+		return requestIDs
+	}
+
+	assert.Equal(t, []string{"req-2", "req-3", "req-1"}, list(""))
+	assert.Equal(t, []string{"req-2", "req-1"}, list("?model=deepseek-ai/DeepSeek-R1"))
+	assert.Equal(t, []string{"req-2"}, list("?limit=1"))
+	assert.Equal(t, []string{"req-2", "req-3"}, list("?limit=2"))
+	assert.Equal(t, []string{"req-1"}, list("?requestID=req-1"))
+	assert.Equal(t, []string{}, list("?model=unknown"))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/scheduling/decisions?limit=-1", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -58,16 +58,27 @@ type ModelServerResponse struct {
 	Namespace      string                     `json:"namespace"`
 	Spec           aiv1alpha1.ModelServerSpec `json:"spec"`
 	AssociatedPods []string                   `json:"associatedPods,omitempty"`
+	Hedging        *HedgingStats              `json:"hedging,omitempty"`
+}
+
+type HedgingStats struct {
+	Hedged          int64 `json:"hedged"`
+	Won             int64 `json:"won"`
+	DuplicateTokens int64 `json:"duplicateTokens"`
 }
 
 type PodResponse struct {
-	Name         string   `json:"name"`
-	Namespace    string   `json:"namespace"`
-	PodInfo      *PodInfo `json:"podInfo,omitempty"`
-	Engine       string   `json:"engine"`
-	Metrics      *Metrics `json:"metrics,omitempty"`
-	Models       []string `json:"models"`
-	ModelServers []string `json:"modelServers"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	PodInfo   *PodInfo `json:"podInfo,omitempty"`
+	Engine    string   `json:"engine"`
+	// Accelerator is the accelerator of the node of the pod, from its labels.
+	Accelerator string   `json:"accelerator,omitempty"`
+	Metrics     *Metrics `json:"metrics,omitempty"`
+	// ModelMetrics are the metrics of each model of the pods serving several models.
+	ModelMetrics map[string]*Metrics `json:"modelMetrics,omitempty"`
+	Models       []string            `json:"models"`
+	ModelServers []string            `json:"modelServers"`
 }
 
 type PodInfo struct {
@@ -220,6 +231,15 @@ func (h *DebugHandler) GetModelServer(c *gin.Context) {
 		response.AssociatedPods = podNames
 	}
 
+	if ms.Spec.TrafficPolicy != nil && ms.Spec.TrafficPolicy.Hedging != nil {
+		stats := h.store.GetHedgingStats(namespacedName)
+		response.Hedging = &HedgingStats{
+			Hedged:          stats.Hedged,
+			Won:             stats.Won,
+			DuplicateTokens: stats.DuplicateTokens,
+		}
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -252,10 +272,11 @@ func (h *DebugHandler) GetPod(c *gin.Context) {
 
 func (h *DebugHandler) convertPodInfoToResponse(namespacedName types.NamespacedName, podInfo *datastore.PodInfo, includeDetails bool) PodResponse {
 	response := PodResponse{
-		Name:      namespacedName.Name,
-		Namespace: namespacedName.Namespace,
-		Engine:    podInfo.GetEngine(),
-		Models:    podInfo.GetModelsList(),
+		Name:        namespacedName.Name,
+		Namespace:   namespacedName.Namespace,
+		Engine:      podInfo.GetEngine(),
+		Accelerator: podInfo.Accelerator(),
+		Models:      podInfo.GetModelsList(),
 	}
 
 	// Convert model servers
@@ -274,6 +295,19 @@ func (h *DebugHandler) convertPodInfoToResponse(namespacedName types.NamespacedN
 		TPOT:              podInfo.TPOT,
 		TTFT:              podInfo.TTFT,
 	}
+	for _, model := range podInfo.ModelMetricsList() {
+		if response.ModelMetrics == nil {
+			response.ModelMetrics = make(map[string]*Metrics)
+		}
+		metrics := podInfo.MetricsOf(model)
+		response.ModelMetrics[model] = &Metrics{
+			GPUCacheUsage:     metrics.GPUCacheUsage,
+			RequestWaitingNum: metrics.RequestWaitingNum,
+			RequestRunningNum: metrics.RequestRunningNum,
+			TPOT:              metrics.TPOT,
+			TTFT:              metrics.TTFT,
+		}
+	}
 
 	// Add pod info if details are requested
 	if includeDetails && podInfo.Pod != nil {
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -76,6 +77,16 @@ func (m *MockStore) DeletePod(podName types.NamespacedName) error {
 	return args.Error(0)
 }
 
+func (m *MockStore) AddOrUpdateNode(node *corev1.Node) error {
+	args := m.Called(node)
+	return args.Error(0)
+}
+
+func (m *MockStore) DeleteNode(nodeName string) error {
+	args := m.Called(nodeName)
+	return args.Error(0)
+}
+
 func (m *MockStore) MatchModelServer(modelName string, request *http.Request) (types.NamespacedName, bool, *aiv1alpha1.ModelRoute, error) {
 	args := m.Called(modelName, request)
 	var modelRoute *aiv1alpha1.ModelRoute
@@ -123,6 +134,15 @@ func (m *MockStore) RegisterCallback(kind string, callback datastore.CallbackFun
 	m.Called(kind, callback)
 }
 
+func (m *MockStore) RegisterState(state datastore.LearnedState) {
+	m.Called(state)
+}
+
+func (m *MockStore) LearnedStates() []datastore.LearnedState {
+	args := m.Called()
+	return args.Get(0).([]datastore.LearnedState)
+}
+
 func (m *MockStore) Run(ctx context.Context) {
 	m.Called(ctx)
 }
@@ -155,6 +175,34 @@ func (m *MockStore) Enqueue(req *datastore.Request) error {
 	return args.Error(0)
 }
 
+func (m *MockStore) RecordTimeToFirstToken(modelServerName types.NamespacedName, ttft time.Duration) {
+	m.Called(modelServerName, ttft)
+}
+
+func (m *MockStore) GetTimeToFirstTokenPercentile(modelServerName types.NamespacedName, percentile int32) (time.Duration, bool) {
+	args := m.Called(modelServerName, percentile)
+	return args.Get(0).(time.Duration), args.Bool(1)
+}
+
+func (m *MockStore) RecordHedgedRequest(modelServerName types.NamespacedName, won bool, duplicateTokens int) {
+	m.Called(modelServerName, won, duplicateTokens)
+}
+
+func (m *MockStore) GetHedgingStats(modelServerName types.NamespacedName) datastore.HedgingStats {
+	args := m.Called(modelServerName)
+	return args.Get(0).(datastore.HedgingStats)
+}
+
+func (m *MockStore) RecordSLO(modelServerName types.NamespacedName, failed bool, latency time.Duration) map[string]bool {
+	args := m.Called(modelServerName, failed, latency)
+	return args.Get(0).(map[string]bool)
+}
+
+func (m *MockStore) GetSLOStatus(modelServerName types.NamespacedName) []datastore.SLOStatus {
+	args := m.Called(modelServerName)
+	return args.Get(0).([]datastore.SLOStatus)
+}
+
 func (m *MockStore) GetRequestWaitingQueueStats() []datastore.QueueStat {
 	args := m.Called()
 	if args.Get(0) == nil {
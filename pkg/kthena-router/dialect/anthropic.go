@@ -0,0 +1,406 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dialect
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// anthropic serves the Anthropic Messages API, POST /anthropic/v1/messages, so that the Anthropic SDKs
+// can use the router as their base URL.
+type anthropic struct{}
+
+var anthropicStopReasons = map[string]string{
+	"stop":           "end_turn",
+	"length":         "max_tokens",
+	"tool_calls":     "tool_use",
+	"function_call":  "tool_use",
+	"content_filter": "refusal",
+}
+
+// anthropicSamplingFields are the sampling fields of the Messages API, with their chat completions names.
+var anthropicSamplingFields = map[string]string{
+	"max_tokens":     "max_tokens",
+	"temperature":    "temperature",
+	"top_p":          "top_p",
+	"top_k":          "top_k",
+	"stop_sequences": "stop",
+}
+
+func (a *anthropic) Name() string {
+	return "anthropic"
+}
+
+func (a *anthropic) Prefix() string {
+	return "/anthropic"
+}
+
+func (a *anthropic) translateRequest(c *gin.Context, body []byte) (*request, error) {
+	if path := strings.TrimPrefix(c.Request.URL.Path, a.Prefix()); path != "/v1/messages" {
+		return nil, fmt.Errorf("unsupported path %s", path)
+	}
+	var in map[string]interface{}
+	if err := json.Unmarshal(body, &in); err != nil {
+		return nil, err
+	}
+	model, _ := in["model"].(string)
+	if model == "" {
+		return nil, fmt.Errorf("model is required")
+	}
+	stream, _ := in["stream"].(bool)
+
+	out := map[string]interface{}{"model": model}
+	copyFields(out, in, anthropicSamplingFields)
+
+	var messages []interface{}
+	if system := anthropicText(in["system"]); system != "" {
+		messages = append(messages, map[string]interface{}{"role": "system", "content": system})
+	}
+	inMessages, _ := in["messages"].([]interface{})
+	for _, m := range inMessages {
+		message, ok := m.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid message")
+		}
+		translated, err := anthropicMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, translated...)
+	}
+	out["messages"] = messages
+
+	if tools, ok := in["tools"].([]interface{}); ok && len(tools) > 0 {
+		var functions []interface{}
+		for _, t := range tools {
+			tool, _ := t.(map[string]interface{})
+			function := map[string]interface{}{"name": tool["name"]}
+			copyFields(function, tool, map[string]string{"description": "description", "input_schema": "parameters"})
+			functions = append(functions, map[string]interface{}{"type": "function", "function": function})
+		}
+		out["tools"] = functions
+	}
+	if choice, ok := in["tool_choice"].(map[string]interface{}); ok {
+		switch choice["type"] {
+		case "auto":
+			out["tool_choice"] = "auto"
+		case "any":
+			out["tool_choice"] = "required"
+		case "none":
+			out["tool_choice"] = "none"
+		case "tool":
+			out["tool_choice"] = map[string]interface{}{"type": "function", "function": map[string]interface{}{"name": choice["name"]}}
+		}
+		if disable, _ := choice["disable_parallel_tool_use"].(bool); disable {
+			out["parallel_tool_calls"] = false
+		}
+	}
+	return &request{model: model, stream: stream, body: out}, nil
+}
+
+// anthropicMessage translates a message to chat completions messages. The tool results of a user message
+// become tool messages, sent before the rest of its content.
+func anthropicMessage(message map[string]interface{}) ([]interface{}, error) {
+	role, _ := message["role"].(string)
+	if role != "user" && role != "assistant" {
+		return nil, fmt.Errorf("invalid message role %q", role)
+	}
+	if text, ok := message["content"].(string); ok {
+		return []interface{}{map[string]interface{}{"role": role, "content": text}}, nil
+	}
+	blocks, _ := message["content"].([]interface{})
+
+	var messages, parts, toolCalls []interface{}
+	for _, b := range blocks {
+		block, _ := b.(map[string]interface{})
+		switch block["type"] {
+		case "text":
+			parts = append(parts, map[string]interface{}{"type": "text", "text": block["text"]})
+		case "image":
+			source, _ := block["source"].(map[string]interface{})
+			url, _ := source["url"].(string)
+			if source["type"] == "base64" {
+				url = fmt.Sprintf("data:%v;base64,%v", source["media_type"], source["data"])
+			}
+			parts = append(parts, map[string]interface{}{"type": "image_url", "image_url": map[string]interface{}{"url": url}})
+		case "tool_use":
+			toolCalls = append(toolCalls, map[string]interface{}{
+				"id":   block["id"],
+				"type": "function",
+				"function": map[string]interface{}{
+					"name":      block["name"],
+					"arguments": marshalArguments(block["input"]),
+				},
+			})
+		case "tool_result":
+			messages = append(messages, map[string]interface{}{
+				"role":         "tool",
+				"tool_call_id": block["tool_use_id"],
+				"content":      anthropicText(block["content"]),
+			})
+		case "thinking", "redacted_thinking":
+			// The reasoning of previous turns is not sent back to the model
+		default:
+			return nil, fmt.Errorf("unsupported content block type %v", block["type"])
+		}
+	}
+
+	if len(parts) == 0 && len(toolCalls) == 0 {
+		return messages, nil
+	}
+	translated := map[string]interface{}{"role": role}
+	if role == "assistant" {
+		// The assistant messages of the chat completions API only carry text
+		translated["content"] = partsText(parts)
+	} else {
+		translated["content"] = parts
+	}
+	if len(toolCalls) > 0 {
+		translated["tool_calls"] = toolCalls
+	}
+	return append(messages, translated), nil
+}
+
+// anthropicText returns the text of a string or of text blocks, e.g. the system prompt or a tool result.
+func anthropicText(v interface{}) string {
+	if text, ok := v.(string); ok {
+		return text
+	}
+	blocks, _ := v.([]interface{})
+	var texts []string
+	for _, b := range blocks {
+		if block, ok := b.(map[string]interface{}); ok && block["type"] == "text" {
+			if text, ok := block["text"].(string); ok {
+				texts = append(texts, text)
+			}
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func partsText(parts []interface{}) string {
+	var texts []string
+	for _, p := range parts {
+		if part, ok := p.(map[string]interface{}); ok {
+			if text, ok := part["text"].(string); ok {
+				texts = append(texts, text)
+			}
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func (a *anthropic) translateResponse(req *request, resp *chatCompletion) interface{} {
+	content := []interface{}{}
+	stopReason := "end_turn"
+	if len(resp.Choices) > 0 {
+		choice := resp.Choices[0]
+		if choice.Message.Content != "" {
+			content = append(content, map[string]interface{}{"type": "text", "text": choice.Message.Content})
+		}
+		for _, call := range choice.Message.ToolCalls {
+			content = append(content, map[string]interface{}{
+				"type":  "tool_use",
+				"id":    call.ID,
+				"name":  call.Function.Name,
+				"input": parseArguments(call.Function.Arguments),
+			})
+		}
+		stopReason = finishReason(anthropicStopReasons, choice.FinishReason, "end_turn")
+	}
+	return map[string]interface{}{
+		"id":            anthropicMessageID(resp.ID),
+		"type":          "message",
+		"role":          "assistant",
+		"model":         req.model,
+		"content":       content,
+		"stop_reason":   stopReason,
+		"stop_sequence": nil,
+		"usage":         anthropicUsage(resp),
+	}
+}
+
+func anthropicMessageID(id string) string {
+	return "msg_" + strings.TrimPrefix(id, "chatcmpl-")
+}
+
+func anthropicUsage(resp *chatCompletion) map[string]interface{} {
+	usage := map[string]interface{}{"input_tokens": 0, "output_tokens": 0}
+	if resp.Usage != nil {
+		usage["input_tokens"] = resp.Usage.PromptTokens
+		usage["output_tokens"] = resp.Usage.CompletionTokens
+	}
+	return usage
+}
+
+func (a *anthropic) errorBody(status int, message string) interface{} {
+	errorType := "api_error"
+	switch status {
+	case http.StatusBadRequest:
+		errorType = "invalid_request_error"
+	case http.StatusNotFound:
+		errorType = "not_found_error"
+	case http.StatusUnauthorized:
+		errorType = "authentication_error"
+	case http.StatusForbidden:
+		errorType = "permission_error"
+	case http.StatusRequestEntityTooLarge:
+		errorType = "request_too_large"
+	case http.StatusTooManyRequests:
+		errorType = "rate_limit_error"
+	case http.StatusServiceUnavailable:
+		errorType = "overloaded_error"
+	}
+	return map[string]interface{}{
+		"type":  "error",
+		"error": map[string]interface{}{"type": errorType, "message": message},
+	}
+}
+
+func (a *anthropic) newStream(req *request) stream {
+	return &anthropicStream{req: req, block: -1}
+}
+
+// anthropicStream translates the chunks into the events of the Messages API: message_start, then the
+// content blocks, text or tool use, each started, updated and stopped, then message_delta and message_stop.
+type anthropicStream struct {
+	req     *request
+	started bool
+	// block is the index of the last content block, -1 before the first one.
+	block int
+	open  bool
+	// tool is the index in the chunks of the tool call of the open block, -1 when it is a text block.
+	tool       int
+	stopReason string
+	usage      *chatCompletion
+}
+
+func (s *anthropicStream) contentType() string {
+	return "text/event-stream"
+}
+
+func (s *anthropicStream) chunk(chunk *chatCompletion) []byte {
+	var events []byte
+	if chunk.Error != nil {
+		return sseEvent("error", (&anthropic{}).errorBody(http.StatusInternalServerError, chunk.Error.Message))
+	}
+	if !s.started {
+		s.started = true
+		events = append(events, sseEvent("message_start", map[string]interface{}{
+			"type": "message_start",
+			"message": map[string]interface{}{
+				"id":            anthropicMessageID(chunk.ID),
+				"type":          "message",
+				"role":          "assistant",
+				"model":         s.req.model,
+				"content":       []interface{}{},
+				"stop_reason":   nil,
+				"stop_sequence": nil,
+				"usage":         anthropicUsage(chunk),
+			},
+		})...)
+	}
+	if chunk.Usage != nil {
+		s.usage = chunk
+	}
+	for _, choice := range chunk.Choices {
+		if choice.Index != 0 {
+			continue
+		}
+		if choice.Delta.Content != "" {
+			if !s.open || s.tool >= 0 {
+				events = append(events, s.startBlock(-1, map[string]interface{}{"type": "text", "text": ""})...)
+			}
+			events = append(events, s.delta(map[string]interface{}{"type": "text_delta", "text": choice.Delta.Content})...)
+		}
+		for _, call := range choice.Delta.ToolCalls {
+			if !s.open || s.tool != call.Index {
+				events = append(events, s.startBlock(call.Index, map[string]interface{}{
+					"type":  "tool_use",
+					"id":    call.ID,
+					"name":  call.Function.Name,
+					"input": map[string]interface{}{},
+				})...)
+			}
+			if call.Function.Arguments != "" {
+				events = append(events, s.delta(map[string]interface{}{"type": "input_json_delta", "partial_json": call.Function.Arguments})...)
+			}
+		}
+		if choice.FinishReason != "" {
+			s.stopReason = finishReason(anthropicStopReasons, choice.FinishReason, "end_turn")
+		}
+	}
+	return events
+}
+
+func (s *anthropicStream) startBlock(tool int, block map[string]interface{}) []byte {
+	events := s.stopBlock()
+	s.block++
+	s.open = true
+	s.tool = tool
+	return append(events, sseEvent("content_block_start", map[string]interface{}{
+		"type":          "content_block_start",
+		"index":         s.block,
+		"content_block": block,
+	})...)
+}
+
+func (s *anthropicStream) delta(delta map[string]interface{}) []byte {
+	return sseEvent("content_block_delta", map[string]interface{}{
+		"type":  "content_block_delta",
+		"index": s.block,
+		"delta": delta,
+	})
+}
+
+func (s *anthropicStream) stopBlock() []byte {
+	if !s.open {
+		return nil
+	}
+	s.open = false
+	return sseEvent("content_block_stop", map[string]interface{}{"type": "content_block_stop", "index": s.block})
+}
+
+func (s *anthropicStream) done() []byte {
+	var events []byte
+	if !s.started {
+		events = s.chunk(&chatCompletion{})
+	}
+	events = append(events, s.stopBlock()...)
+	if s.stopReason == "" {
+		s.stopReason = "end_turn"
+	}
+	usage := map[string]interface{}{"output_tokens": 0}
+	if s.usage != nil {
+		usage = anthropicUsage(s.usage)
+	}
+	events = append(events, sseEvent("message_delta", map[string]interface{}{
+		"type":  "message_delta",
+		"delta": map[string]interface{}{"stop_reason": s.stopReason, "stop_sequence": nil},
+		"usage": usage,
+	})...)
+	return append(events, sseEvent("message_stop", map[string]interface{}{"type": "message_stop"})...)
+}
+
+func (s *anthropicStream) interrupted() []byte {
+	return sseEvent("error", (&anthropic{}).errorBody(http.StatusBadGateway, "the model server closed the stream before its end"))
+}
@@ -0,0 +1,167 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dialect
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnthropicTranslateRequest(t *testing.T) {
+	body := `{
+		"model": "llama",
+		"max_tokens": 100,
+		"temperature": 0.5,
+		"stop_sequences": ["END"],
+		"system": [{"type": "text", "text": "Be brief."}],
+		"messages": [
+			{"role": "user", "content": [
+				{"type": "text", "text": "What is in the image?"},
+				{"type": "image", "source": {"type": "base64", "media_type": "image/png", "data": "aGk="}}
+			]},
+			{"role": "assistant", "content": [
+				{"type": "text", "text": "Let me look."},
+				{"type": "tool_use", "id": "toolu_1", "name": "lookup", "input": {"q": "cat"}}
+			]},
+			{"role": "user", "content": [
+				{"type": "tool_result", "tool_use_id": "toolu_1", "content": [{"type": "text", "text": "a cat"}]},
+				{"type": "text", "text": "Thanks"}
+			]}
+		],
+		"tools": [{"name": "lookup", "description": "Look up", "input_schema": {"type": "object"}}],
+		"tool_choice": {"type": "any"}
+	}`
+	var translated map[string]interface{}
+	w := serve(t, &anthropic{}, "/anthropic/v1/messages", body, func(c *gin.Context) {
+		translated = chatRequest(t, c)
+		c.JSON(http.StatusOK, gin.H{"id": "chatcmpl-1", "choices": []interface{}{}})
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	expected := map[string]interface{}{
+		"model":       "llama",
+		"max_tokens":  float64(100),
+		"temperature": 0.5,
+		"stop":        []interface{}{"END"},
+		"messages": []interface{}{
+			map[string]interface{}{"role": "system", "content": "Be brief."},
+			map[string]interface{}{"role": "user", "content": []interface{}{
+				map[string]interface{}{"type": "text", "text": "What is in the image?"},
+				map[string]interface{}{"type": "image_url", "image_url": map[string]interface{}{"url": "data:image/png;base64,aGk="}},
+			}},
+			map[string]interface{}{"role": "assistant", "content": "Let me look.", "tool_calls": []interface{}{
+				map[string]interface{}{"id": "toolu_1", "type": "function", "function": map[string]interface{}{"name": "lookup", "arguments": `{"q":"cat"}`}},
+			}},
+			map[string]interface{}{"role": "tool", "tool_call_id": "toolu_1", "content": "a cat"},
+			map[string]interface{}{"role": "user", "content": []interface{}{
+				map[string]interface{}{"type": "text", "text": "Thanks"},
+			}},
+		},
+		"tools": []interface{}{
+			map[string]interface{}{"type": "function", "function": map[string]interface{}{
+				"name": "lookup", "description": "Look up", "parameters": map[string]interface{}{"type": "object"},
+			}},
+		},
+		"tool_choice": "required",
+	}
+	assert.Equal(t, expected, translated)
+}
+
+func TestAnthropicResponse(t *testing.T) {
+	w := serve(t, &anthropic{}, "/anthropic/v1/messages", `{"model":"llama","max_tokens":10,"messages":[{"role":"user","content":"hi"}]}`, func(c *gin.Context) {
+		c.Header("Content-Length", "1000")
+		c.JSON(http.StatusOK, gin.H{
+			"id":    "chatcmpl-42",
+			"model": "meta-llama/Llama-3-8B",
+			"choices": []interface{}{gin.H{
+				"index":         0,
+				"finish_reason": "tool_calls",
+				"message": gin.H{
+					"role":       "assistant",
+					"content":    "Checking.",
+					"tool_calls": []interface{}{gin.H{"id": "call_1", "type": "function", "function": gin.H{"name": "lookup", "arguments": `{"q":"cat"}`}}},
+				},
+			}},
+			"usage": gin.H{"prompt_tokens": 12, "completion_tokens": 7, "total_tokens": 19},
+		})
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"id": "msg_42",
+		"type": "message",
+		"role": "assistant",
+		"model": "llama",
+		"content": [
+			{"type": "text", "text": "Checking."},
+			{"type": "tool_use", "id": "call_1", "name": "lookup", "input": {"q": "cat"}}
+		],
+		"stop_reason": "tool_use",
+		"stop_sequence": null,
+		"usage": {"input_tokens": 12, "output_tokens": 7}
+	}`, w.Body.String())
+}
+
+func TestAnthropicStream(t *testing.T) {
+	var translated map[string]interface{}
+	w := serve(t, &anthropic{}, "/anthropic/v1/messages", `{"model":"llama","max_tokens":10,"stream":true,"messages":[{"role":"user","content":"hi"}]}`, func(c *gin.Context) {
+		translated = chatRequest(t, c)
+		streamChunks(c,
+			`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"role":"assistant","content":""}}]}`,
+			`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"Hel"}}]}`,
+			`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"lo"}}]}`,
+			`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","function":{"name":"lookup","arguments":""}}]}}]}`,
+			`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"q\":"}}]}}]}`,
+			`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"cat\"}"}}]},"finish_reason":"tool_calls"}]}`,
+			`{"id":"chatcmpl-1","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":9,"total_tokens":14}}`,
+		)
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, true, translated["stream"])
+	assert.Equal(t, map[string]interface{}{"include_usage": true}, translated["stream_options"])
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Get("Content-Length"))
+
+	names, events := sseData(t, w.Body.String())
+	assert.Equal(t, []string{
+		"message_start",
+		"content_block_start", "content_block_delta", "content_block_delta", "content_block_stop",
+		"content_block_start", "content_block_delta", "content_block_delta", "content_block_stop",
+		"message_delta", "message_stop",
+	}, names)
+	assert.Equal(t, "msg_1", events[0]["message"].(map[string]interface{})["id"])
+	assert.Equal(t, map[string]interface{}{"type": "text_delta", "text": "Hel"}, events[2]["delta"])
+	assert.Equal(t, float64(1), events[5]["index"])
+	assert.Equal(t, map[string]interface{}{"type": "tool_use", "id": "call_1", "name": "lookup", "input": map[string]interface{}{}}, events[5]["content_block"])
+	assert.Equal(t, map[string]interface{}{"type": "input_json_delta", "partial_json": `"cat"}`}, events[7]["delta"])
+	assert.Equal(t, map[string]interface{}{"stop_reason": "tool_use", "stop_sequence": nil}, events[9]["delta"])
+	assert.Equal(t, map[string]interface{}{"input_tokens": float64(5), "output_tokens": float64(9)}, events[9]["usage"])
+}
+
+func TestAnthropicStreamInterrupted(t *testing.T) {
+	w := serve(t, &anthropic{}, "/anthropic/v1/messages", `{"model":"llama","max_tokens":10,"stream":true,"messages":[{"role":"user","content":"hi"}]}`, func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Status(http.StatusOK)
+		_, _ = c.Writer.WriteString(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"Hel"}}]}` + "\n\n")
+	})
+	names, events := sseData(t, w.Body.String())
+	assert.Equal(t, []string{"message_start", "content_block_start", "content_block_delta", "error"}, names)
+	assert.Equal(t, "api_error", events[3]["error"].(map[string]interface{})["type"])
+}
@@ -0,0 +1,285 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dialect serves the APIs of other providers, the Anthropic Messages API and the Gemini
+// generateContent API, by translating their requests to the OpenAI chat completions API served by
+// the model servers, and the responses, streams included, back.
+package dialect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/handlers"
+)
+
+// chatCompletionsPath is the path the translated requests are routed as.
+const chatCompletionsPath = "/v1/chat/completions"
+
+// Dialect translates the requests of an API to the OpenAI chat completions API, and the responses back.
+type Dialect interface {
+	// Name is the name of the dialect, as listed in the router settings.
+	Name() string
+	// Prefix is the path prefix the requests of the dialect are served under.
+	Prefix() string
+
+	translateRequest(c *gin.Context, body []byte) (*request, error)
+	translateResponse(req *request, resp *chatCompletion) interface{}
+	newStream(req *request) stream
+	errorBody(status int, message string) interface{}
+}
+
+// stream translates the chunks of a chat completions stream.
+type stream interface {
+	contentType() string
+	// chunk returns the events sent to the client for a chunk.
+	chunk(chunk *chatCompletion) []byte
+	// done returns the events ending the stream, once the model server sent [DONE].
+	done() []byte
+	// interrupted returns the events ending a stream cut before [DONE].
+	interrupted() []byte
+}
+
+// request is a translated request.
+type request struct {
+	// model is the model requested by the client.
+	model  string
+	stream bool
+	// sse sends the streams of the Gemini API as server-sent events, instead of a JSON array.
+	sse bool
+	// body is the OpenAI chat completions request.
+	body map[string]interface{}
+}
+
+// chatCompletion is an OpenAI chat completion, or a chunk of its stream.
+type chatCompletion struct {
+	ID      string          `json:"id"`
+	Model   string          `json:"model"`
+	Choices []chatChoice    `json:"choices"`
+	Usage   *handlers.Usage `json:"usage"`
+	Error   *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type chatChoice struct {
+	Index        int         `json:"index"`
+	Message      chatMessage `json:"message"`
+	Delta        chatMessage `json:"delta"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+type chatMessage struct {
+	Content   string     `json:"content"`
+	ToolCalls []toolCall `json:"tool_calls"`
+}
+
+type toolCall struct {
+	Index    int    `json:"index"`
+	ID       string `json:"id"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// registry lists the supported dialects.
+var registry = []Dialect{
+	&anthropic{},
+	&gemini{},
+}
+
+// Parse returns the dialects of the comma separated names.
+func Parse(names string) ([]Dialect, error) {
+	var dialects []Dialect
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		d := get(name)
+		if d == nil {
+			return nil, fmt.Errorf("unknown API dialect %q", name)
+		}
+		dialects = append(dialects, d)
+	}
+	return dialects, nil
+}
+
+func get(name string) Dialect {
+	for _, d := range registry {
+		if d.Name() == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the path is served by one of the dialects.
+func Matches(dialects []Dialect, path string) bool {
+	for _, d := range dialects {
+		if strings.HasPrefix(path, d.Prefix()+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler translates the request of the dialect, and serves it with next as a chat completions request.
+func Handler(d Dialect, next gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			writeError(c, d, http.StatusInternalServerError, err.Error())
+			return
+		}
+		req, err := d.translateRequest(c, body)
+		if err != nil {
+			accesslog.SetError(c, "request_parsing", err.Error())
+			writeError(c, d, http.StatusBadRequest, err.Error())
+			return
+		}
+		if req.stream {
+			// The usage ends the translated streams
+			req.body["stream"] = true
+			req.body["stream_options"] = map[string]interface{}{"include_usage": true}
+		}
+		translated, err := json.Marshal(req.body)
+		if err != nil {
+			writeError(c, d, http.StatusInternalServerError, err.Error())
+			return
+		}
+		klog.V(4).Infof("translated %s request of model %s", d.Name(), req.model)
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(translated))
+		c.Request.ContentLength = int64(len(translated))
+		c.Request.Header.Set("Content-Length", fmt.Sprint(len(translated)))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.URL.Path = chatCompletionsPath
+		c.Request.URL.RawPath = ""
+
+		writer := newResponseWriter(c.Writer, d, req)
+		c.Writer = writer
+		next(c)
+		writer.finish()
+		c.Writer = writer.ResponseWriter
+	}
+}
+
+func writeError(c *gin.Context, d Dialect, status int, message string) {
+	c.AbortWithStatusJSON(status, d.errorBody(status, message))
+}
+
+// errorMessage extracts the message of an error response of the router or of a model server.
+func errorMessage(status int, body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		if message := strings.TrimSpace(string(body)); message != "" {
+			return message
+		}
+		return http.StatusText(status)
+	}
+	for {
+		switch value := v.(type) {
+		case string:
+			return value
+		case map[string]interface{}:
+			if e, ok := value["error"]; ok {
+				v = e
+				continue
+			}
+			if message, ok := value["message"].(string); ok {
+				return message
+			}
+		}
+		if len(body) > 0 {
+			return string(body)
+		}
+		return http.StatusText(status)
+	}
+}
+
+// finishReason maps the OpenAI finish reasons to the ones of the dialect, unknown ones map to def.
+func finishReason(reasons map[string]string, reason, def string) string {
+	if mapped, ok := reasons[reason]; ok {
+		return mapped
+	}
+	return def
+}
+
+// sseEvent formats a server-sent event, without a name when event is empty.
+func sseEvent(event string, data interface{}) []byte {
+	body, err := json.Marshal(data)
+	if err != nil {
+		klog.Errorf("failed to marshal event %s: %v", event, err)
+		return nil
+	}
+	var b bytes.Buffer
+	if event != "" {
+		b.WriteString("event: " + event + "\n")
+	}
+	b.WriteString("data: ")
+	b.Write(body)
+	b.WriteString("\n\n")
+	return b.Bytes()
+}
+
+// parseArguments parses the JSON arguments of a tool call, the invalid ones are kept as a string.
+func parseArguments(arguments string) interface{} {
+	if strings.TrimSpace(arguments) == "" {
+		return map[string]interface{}{}
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(arguments), &v); err != nil {
+		return arguments
+	}
+	return v
+}
+
+// marshalArguments formats the arguments of a tool call as the JSON string of the OpenAI API.
+func marshalArguments(v interface{}) string {
+	if v == nil {
+		return "{}"
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	body, err := json.Marshal(v)
+	if err != nil {
+		return "{}"
+	}
+	return string(body)
+}
+
+// copyFields copies the fields of from present in the request into to, renamed.
+func copyFields(to, from map[string]interface{}, names map[string]string) {
+	if from == nil {
+		return
+	}
+	for name, openAIName := range names {
+		if v, ok := from[name]; ok && v != nil {
+			to[openAIName] = v
+		}
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dialect
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type closeNotifyRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r closeNotifyRecorder) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+// serve sends the request to the handler of the dialect, next playing the router.
+func serve(t *testing.T, d Dialect, path, body string, next gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.POST(d.Prefix()+"/*path", Handler(d, next))
+	w := closeNotifyRecorder{httptest.NewRecorder()}
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	engine.ServeHTTP(w, req)
+	return w.ResponseRecorder
+}
+
+// chatRequest reads the chat completions request translated by the dialect.
+func chatRequest(t *testing.T, c *gin.Context) map[string]interface{} {
+	assert.Equal(t, chatCompletionsPath, c.Request.URL.Path)
+	body, err := io.ReadAll(c.Request.Body)
+	require.NoError(t, err)
+	var req map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &req))
+	return req
+}
+
+func streamChunks(c *gin.Context, chunks ...string) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Content-Length", "1000")
+	c.Status(http.StatusOK)
+	c.Stream(func(w io.Writer) bool {
+		for _, chunk := range chunks {
+			// The chunks are split across writes
+			_, _ = io.WriteString(w, "data: "+chunk[:len(chunk)/2])
+			_, _ = io.WriteString(w, chunk[len(chunk)/2:]+"\n\n")
+		}
+		_, _ = io.WriteString(w, "data: [DONE]\n\n")
+		return false
+	})
+}
+
+// sseData returns the data of the events of a stream, and their names.
+func sseData(t *testing.T, body string) ([]string, []map[string]interface{}) {
+	var names []string
+	var events []map[string]interface{}
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "event: "); ok {
+			names = append(names, name)
+		}
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			var event map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(data), &event))
+			events = append(events, event)
+		}
+	}
+	return names, events
+}
+
+func TestParse(t *testing.T) {
+	dialects, err := Parse(" anthropic, gemini ,")
+	require.NoError(t, err)
+	require.Len(t, dialects, 2)
+	assert.Equal(t, "anthropic", dialects[0].Name())
+	assert.Equal(t, "gemini", dialects[1].Name())
+	assert.True(t, Matches(dialects, "/gemini/v1beta/models/llama:generateContent"))
+	assert.False(t, Matches(dialects, "/v1/chat/completions"))
+
+	dialects, err = Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, dialects)
+
+	_, err = Parse("cohere")
+	assert.Error(t, err)
+}
+
+func TestErrorMessage(t *testing.T) {
+	assert.Equal(t, "model not found", errorMessage(http.StatusNotFound, []byte(`"model not found"`)))
+	assert.Equal(t, "rate limited", errorMessage(http.StatusTooManyRequests, []byte(`{"error":"rate limited"}`)))
+	assert.Equal(t, "bad prompt", errorMessage(http.StatusBadRequest, []byte(`{"error":{"message":"bad prompt"}}`)))
+	assert.Equal(t, "upstream down", errorMessage(http.StatusBadGateway, []byte("upstream down")))
+	assert.Equal(t, "Too Many Requests", errorMessage(http.StatusTooManyRequests, nil))
+}
+
+func TestHandlerError(t *testing.T) {
+	w := serve(t, &anthropic{}, "/anthropic/v1/messages", `{"model":"llama","max_tokens":10,"messages":[]}`, func(c *gin.Context) {
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "token usage exceeds rate limit"})
+	})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.JSONEq(t, `{"type":"error","error":{"type":"rate_limit_error","message":"token usage exceeds rate limit"}}`, w.Body.String())
+
+	w = serve(t, &gemini{}, "/gemini/v1beta/models/llama:generateContent", `{"contents":[]}`, func(c *gin.Context) {
+		c.AbortWithStatusJSON(http.StatusNotFound, "can't find corresponding model server: llama")
+	})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":{"code":404,"message":"can't find corresponding model server: llama","status":"NOT_FOUND"}}`, w.Body.String())
+
+	w = serve(t, &anthropic{}, "/anthropic/v1/messages", `{"messages":[]}`, func(c *gin.Context) {
+		t.Fatal("an invalid request must not be routed")
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_request_error")
+}
@@ -0,0 +1,466 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dialect
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/handlers"
+)
+
+// gemini serves the generateContent and streamGenerateContent methods of the Gemini API,
+// POST /gemini/v1beta/models/{model}:generateContent, so that the Gemini SDKs can use the router as their base URL.
+type gemini struct{}
+
+// geminiPath matches the paths of the methods, the model may contain slashes.
+var geminiPath = regexp.MustCompile(`^/v1(?:beta|alpha)?/models/(.+):(generateContent|streamGenerateContent)$`)
+
+var geminiFinishReasons = map[string]string{
+	"stop":           "STOP",
+	"length":         "MAX_TOKENS",
+	"tool_calls":     "STOP",
+	"function_call":  "STOP",
+	"content_filter": "SAFETY",
+}
+
+// geminiGenerationFields are the fields of the generation config, with their chat completions names.
+var geminiGenerationFields = map[string]string{
+	"temperature":      "temperature",
+	"topP":             "top_p",
+	"topK":             "top_k",
+	"maxOutputTokens":  "max_tokens",
+	"stopSequences":    "stop",
+	"candidateCount":   "n",
+	"seed":             "seed",
+	"presencePenalty":  "presence_penalty",
+	"frequencyPenalty": "frequency_penalty",
+}
+
+// geminiAPIKeyHeader is the header of the API key of the Gemini SDKs, which is also accepted in the key query parameter.
+const geminiAPIKeyHeader = "X-Goog-Api-Key"
+
+func (g *gemini) Name() string {
+	return "gemini"
+}
+
+func (g *gemini) Prefix() string {
+	return "/gemini"
+}
+
+func (g *gemini) translateRequest(c *gin.Context, body []byte) (*request, error) {
+	match := geminiPath.FindStringSubmatch(strings.TrimPrefix(c.Request.URL.Path, g.Prefix()))
+	if match == nil {
+		return nil, fmt.Errorf("unsupported path %s", c.Request.URL.Path)
+	}
+	model := strings.TrimPrefix(match[1], "models/")
+	req := &request{model: model, stream: match[2] == "streamGenerateContent", sse: c.Query("alt") == "sse"}
+
+	// The API key of the Gemini SDKs is the API key of the consumer
+	if c.Request.Header.Get("X-API-Key") == "" {
+		if key := c.Request.Header.Get(geminiAPIKeyHeader); key != "" {
+			c.Request.Header.Set("X-API-Key", key)
+		} else if key := c.Query("key"); key != "" {
+			c.Request.Header.Set("X-API-Key", key)
+		}
+	}
+
+	var in map[string]interface{}
+	if err := json.Unmarshal(body, &in); err != nil {
+		return nil, err
+	}
+	out := map[string]interface{}{"model": model}
+	req.body = out
+	config, _ := geminiField(in, "generationConfig").(map[string]interface{})
+	for name, openAIName := range geminiGenerationFields {
+		if v := geminiField(config, name); v != nil {
+			out[openAIName] = v
+		}
+	}
+	if geminiField(config, "responseMimeType") == "application/json" {
+		out["response_format"] = map[string]interface{}{"type": "json_object"}
+		if schema := geminiField(config, "responseSchema"); schema != nil {
+			out["response_format"] = map[string]interface{}{
+				"type":        "json_schema",
+				"json_schema": map[string]interface{}{"name": "response", "schema": schema},
+			}
+		}
+	}
+
+	var messages []interface{}
+	if system, ok := geminiField(in, "systemInstruction").(map[string]interface{}); ok {
+		parts, _ := system["parts"].([]interface{})
+		messages = append(messages, map[string]interface{}{"role": "system", "content": geminiText(parts)})
+	}
+	contents, _ := in["contents"].([]interface{})
+	calls := &geminiCalls{ids: map[string]string{}}
+	for _, c := range contents {
+		content, ok := c.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid content")
+		}
+		translated, err := geminiContent(content, calls)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, translated...)
+	}
+	out["messages"] = messages
+
+	var functions []interface{}
+	tools, _ := in["tools"].([]interface{})
+	for _, t := range tools {
+		tool, _ := t.(map[string]interface{})
+		declarations, _ := geminiField(tool, "functionDeclarations").([]interface{})
+		for _, d := range declarations {
+			declaration, _ := d.(map[string]interface{})
+			function := map[string]interface{}{"name": declaration["name"]}
+			copyFields(function, declaration, map[string]string{"description": "description", "parameters": "parameters"})
+			if schema := geminiField(declaration, "parametersJsonSchema"); schema != nil {
+				function["parameters"] = schema
+			}
+			functions = append(functions, map[string]interface{}{"type": "function", "function": function})
+		}
+	}
+	if len(functions) > 0 {
+		out["tools"] = functions
+	}
+	if toolConfig, ok := geminiField(in, "toolConfig").(map[string]interface{}); ok {
+		callingConfig, _ := geminiField(toolConfig, "functionCallingConfig").(map[string]interface{})
+		allowed, _ := geminiField(callingConfig, "allowedFunctionNames").([]interface{})
+		switch callingConfig["mode"] {
+		case "AUTO":
+			out["tool_choice"] = "auto"
+		case "NONE":
+			out["tool_choice"] = "none"
+		case "ANY":
+			out["tool_choice"] = "required"
+			if len(allowed) == 1 {
+				out["tool_choice"] = map[string]interface{}{"type": "function", "function": map[string]interface{}{"name": allowed[0]}}
+			}
+		}
+	}
+	return req, nil
+}
+
+// geminiField returns the field of the object, named in lower camel case or in snake case, which the API both accept.
+func geminiField(object map[string]interface{}, name string) interface{} {
+	if object == nil {
+		return nil
+	}
+	if v, ok := object[name]; ok {
+		return v
+	}
+	var snake strings.Builder
+	for _, r := range name {
+		if unicode.IsUpper(r) {
+			snake.WriteByte('_')
+			r = unicode.ToLower(r)
+		}
+		snake.WriteRune(r)
+	}
+	return object[snake.String()]
+}
+
+// geminiCalls names the function calls of a conversation, the Gemini API only names them optionally.
+// A function response answers the last call of its function without an id.
+type geminiCalls struct {
+	n   int
+	ids map[string]string
+}
+
+func (g *geminiCalls) call(name string, id interface{}) string {
+	callID, _ := id.(string)
+	if callID == "" {
+		g.n++
+		callID = fmt.Sprintf("call_%d", g.n)
+	}
+	g.ids[name] = callID
+	return callID
+}
+
+func (g *geminiCalls) response(name string, id interface{}) string {
+	if callID, _ := id.(string); callID != "" {
+		return callID
+	}
+	return g.ids[name]
+}
+
+// geminiContent translates a content to chat completions messages. The function responses become tool messages.
+func geminiContent(content map[string]interface{}, calls *geminiCalls) ([]interface{}, error) {
+	role := "user"
+	switch content["role"] {
+	case "model":
+		role = "assistant"
+	case "user", "function", nil, "":
+	default:
+		return nil, fmt.Errorf("invalid content role %v", content["role"])
+	}
+	parts, _ := content["parts"].([]interface{})
+
+	var messages, texts, toolCalls []interface{}
+	for _, p := range parts {
+		part, _ := p.(map[string]interface{})
+		if thought, _ := part["thought"].(bool); thought {
+			continue
+		}
+		if text, ok := part["text"].(string); ok {
+			texts = append(texts, map[string]interface{}{"type": "text", "text": text})
+			continue
+		}
+		if data, ok := geminiField(part, "inlineData").(map[string]interface{}); ok {
+			url := fmt.Sprintf("data:%v;base64,%v", geminiField(data, "mimeType"), data["data"])
+			texts = append(texts, map[string]interface{}{"type": "image_url", "image_url": map[string]interface{}{"url": url}})
+			continue
+		}
+		if data, ok := geminiField(part, "fileData").(map[string]interface{}); ok {
+			texts = append(texts, map[string]interface{}{"type": "image_url", "image_url": map[string]interface{}{"url": geminiField(data, "fileUri")}})
+			continue
+		}
+		if call, ok := geminiField(part, "functionCall").(map[string]interface{}); ok {
+			name, _ := call["name"].(string)
+			toolCalls = append(toolCalls, map[string]interface{}{
+				"id":   calls.call(name, call["id"]),
+				"type": "function",
+				"function": map[string]interface{}{
+					"name":      name,
+					"arguments": marshalArguments(call["args"]),
+				},
+			})
+			continue
+		}
+		if response, ok := geminiField(part, "functionResponse").(map[string]interface{}); ok {
+			name, _ := response["name"].(string)
+			messages = append(messages, map[string]interface{}{
+				"role":         "tool",
+				"tool_call_id": calls.response(name, response["id"]),
+				"content":      marshalArguments(response["response"]),
+			})
+			continue
+		}
+		return nil, fmt.Errorf("unsupported part %v", part)
+	}
+
+	if len(texts) == 0 && len(toolCalls) == 0 {
+		return messages, nil
+	}
+	translated := map[string]interface{}{"role": role}
+	if role == "assistant" {
+		translated["content"] = partsText(texts)
+	} else {
+		translated["content"] = texts
+	}
+	if len(toolCalls) > 0 {
+		translated["tool_calls"] = toolCalls
+	}
+	return append(messages, translated), nil
+}
+
+func geminiText(parts []interface{}) string {
+	var texts []string
+	for _, p := range parts {
+		if part, ok := p.(map[string]interface{}); ok {
+			if text, ok := part["text"].(string); ok {
+				texts = append(texts, text)
+			}
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func (g *gemini) translateResponse(req *request, resp *chatCompletion) interface{} {
+	candidates := []interface{}{}
+	for _, choice := range resp.Choices {
+		candidates = append(candidates, geminiCandidate(choice.Index, choice.Message.Content, choice.Message.ToolCalls, choice.FinishReason))
+	}
+	return geminiResponse(req, resp.ID, candidates, resp.Usage)
+}
+
+func geminiCandidate(index int, text string, toolCalls []toolCall, reason string) map[string]interface{} {
+	parts := []interface{}{}
+	if text != "" {
+		parts = append(parts, map[string]interface{}{"text": text})
+	}
+	for _, call := range toolCalls {
+		parts = append(parts, map[string]interface{}{
+			"functionCall": map[string]interface{}{
+				"id":   call.ID,
+				"name": call.Function.Name,
+				"args": parseArguments(call.Function.Arguments),
+			},
+		})
+	}
+	candidate := map[string]interface{}{
+		"index":   index,
+		"content": map[string]interface{}{"role": "model", "parts": parts},
+	}
+	if reason != "" {
+		candidate["finishReason"] = finishReason(geminiFinishReasons, reason, "OTHER")
+	}
+	return candidate
+}
+
+func geminiResponse(req *request, id string, candidates []interface{}, usage *handlers.Usage) map[string]interface{} {
+	response := map[string]interface{}{
+		"candidates":   candidates,
+		"modelVersion": req.model,
+		"responseId":   id,
+	}
+	if usage != nil {
+		response["usageMetadata"] = map[string]interface{}{
+			"promptTokenCount":     usage.PromptTokens,
+			"candidatesTokenCount": usage.CompletionTokens,
+			"totalTokenCount":      usage.PromptTokens + usage.CompletionTokens,
+		}
+	}
+	return response
+}
+
+func (g *gemini) errorBody(status int, message string) interface{} {
+	code := "INTERNAL"
+	switch status {
+	case http.StatusUnauthorized:
+		code = "UNAUTHENTICATED"
+	case http.StatusForbidden:
+		code = "PERMISSION_DENIED"
+	case http.StatusNotFound:
+		code = "NOT_FOUND"
+	case http.StatusTooManyRequests:
+		code = "RESOURCE_EXHAUSTED"
+	case http.StatusServiceUnavailable:
+		code = "UNAVAILABLE"
+	case http.StatusGatewayTimeout:
+		code = "DEADLINE_EXCEEDED"
+	default:
+		if status < http.StatusInternalServerError {
+			code = "INVALID_ARGUMENT"
+		}
+	}
+	return map[string]interface{}{
+		"error": map[string]interface{}{"code": status, "message": message, "status": code},
+	}
+}
+
+func (g *gemini) newStream(req *request) stream {
+	return &geminiStream{req: req, calls: map[int][]toolCall{}}
+}
+
+// geminiStream translates the chunks into GenerateContentResponses, sent as server-sent events with alt=sse,
+// else as the elements of a JSON array. The function calls, whose arguments are streamed, are sent whole with
+// the finish reason of their candidate, and the last response carries the usage.
+type geminiStream struct {
+	req     *request
+	id      string
+	started bool
+	// calls are the function calls of the candidates being streamed.
+	calls map[int][]toolCall
+	// finished are the last candidates, held back until the usage is known.
+	finished []interface{}
+	usage    *handlers.Usage
+}
+
+func (s *geminiStream) contentType() string {
+	if s.req.sse {
+		return "text/event-stream"
+	}
+	return "application/json"
+}
+
+func (s *geminiStream) chunk(chunk *chatCompletion) []byte {
+	if chunk.Error != nil {
+		return s.element((&gemini{}).errorBody(http.StatusInternalServerError, chunk.Error.Message))
+	}
+	if s.id == "" {
+		s.id = chunk.ID
+	}
+	if chunk.Usage != nil {
+		s.usage = chunk.Usage
+	}
+	var candidates []interface{}
+	for _, choice := range chunk.Choices {
+		for _, call := range choice.Delta.ToolCalls {
+			calls := s.calls[choice.Index]
+			if len(calls) == 0 || calls[len(calls)-1].Index != call.Index {
+				calls = append(calls, call)
+			} else {
+				calls[len(calls)-1].Function.Arguments += call.Function.Arguments
+			}
+			s.calls[choice.Index] = calls
+		}
+		if choice.FinishReason != "" {
+			s.finished = append(s.finished, geminiCandidate(choice.Index, choice.Delta.Content, s.calls[choice.Index], choice.FinishReason))
+			continue
+		}
+		if choice.Delta.Content != "" {
+			candidates = append(candidates, geminiCandidate(choice.Index, choice.Delta.Content, nil, ""))
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return s.element(geminiResponse(s.req, s.id, candidates, nil))
+}
+
+// element formats a response as an event or an element of the array.
+func (s *geminiStream) element(response interface{}) []byte {
+	if s.req.sse {
+		return sseEvent("", response)
+	}
+	body, err := json.Marshal(response)
+	if err != nil {
+		return nil
+	}
+	separator := ",\r\n"
+	if !s.started {
+		separator = "["
+	}
+	s.started = true
+	return append([]byte(separator), body...)
+}
+
+func (s *geminiStream) end() []byte {
+	if !s.req.sse {
+		if !s.started {
+			return []byte("[]")
+		}
+		return []byte("]")
+	}
+	return nil
+}
+
+func (s *geminiStream) done() []byte {
+	var events []byte
+	if len(s.finished) > 0 || s.usage != nil {
+		candidates := s.finished
+		if candidates == nil {
+			candidates = []interface{}{}
+		}
+		events = s.element(geminiResponse(s.req, s.id, candidates, s.usage))
+	}
+	return append(events, s.end()...)
+}
+
+func (s *geminiStream) interrupted() []byte {
+	events := s.element((&gemini{}).errorBody(http.StatusBadGateway, "the model server closed the stream before its end"))
+	return append(events, s.end()...)
+}
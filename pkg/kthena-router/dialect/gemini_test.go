@@ -0,0 +1,149 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dialect
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeminiTranslateRequest(t *testing.T) {
+	body := `{
+		"systemInstruction": {"parts": [{"text": "Be brief."}]},
+		"contents": [
+			{"role": "user", "parts": [{"text": "Weather in Paris?"}, {"inline_data": {"mime_type": "image/jpeg", "data": "aGk="}}]},
+			{"role": "model", "parts": [{"functionCall": {"name": "weather", "args": {"city": "Paris"}}}]},
+			{"role": "user", "parts": [{"functionResponse": {"name": "weather", "response": {"sky": "clear"}}}]}
+		],
+		"generationConfig": {"temperature": 0.2, "maxOutputTokens": 64, "candidateCount": 2, "responseMimeType": "application/json"},
+		"tools": [{"functionDeclarations": [{"name": "weather", "description": "Weather of a city", "parameters": {"type": "object"}}]}],
+		"toolConfig": {"functionCallingConfig": {"mode": "ANY", "allowedFunctionNames": ["weather"]}}
+	}`
+	var translated map[string]interface{}
+	var apiKey string
+	w := serve(t, &gemini{}, "/gemini/v1beta/models/meta-llama/Llama-3:generateContent?key=secret", body, func(c *gin.Context) {
+		translated = chatRequest(t, c)
+		apiKey = c.Request.Header.Get("X-API-Key")
+		c.JSON(http.StatusOK, gin.H{"id": "chatcmpl-1", "choices": []interface{}{}})
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "secret", apiKey)
+
+	expected := map[string]interface{}{
+		"model":           "meta-llama/Llama-3",
+		"temperature":     0.2,
+		"max_tokens":      float64(64),
+		"n":               float64(2),
+		"response_format": map[string]interface{}{"type": "json_object"},
+		"messages": []interface{}{
+			map[string]interface{}{"role": "system", "content": "Be brief."},
+			map[string]interface{}{"role": "user", "content": []interface{}{
+				map[string]interface{}{"type": "text", "text": "Weather in Paris?"},
+				map[string]interface{}{"type": "image_url", "image_url": map[string]interface{}{"url": "data:image/jpeg;base64,aGk="}},
+			}},
+			map[string]interface{}{"role": "assistant", "content": "", "tool_calls": []interface{}{
+				map[string]interface{}{"id": "call_1", "type": "function", "function": map[string]interface{}{"name": "weather", "arguments": `{"city":"Paris"}`}},
+			}},
+			map[string]interface{}{"role": "tool", "tool_call_id": "call_1", "content": `{"sky":"clear"}`},
+		},
+		"tools": []interface{}{
+			map[string]interface{}{"type": "function", "function": map[string]interface{}{
+				"name": "weather", "description": "Weather of a city", "parameters": map[string]interface{}{"type": "object"},
+			}},
+		},
+		"tool_choice": map[string]interface{}{"type": "function", "function": map[string]interface{}{"name": "weather"}},
+	}
+	assert.Equal(t, expected, translated)
+}
+
+func TestGeminiUnsupportedPath(t *testing.T) {
+	w := serve(t, &gemini{}, "/gemini/v1beta/models/llama:countTokens", `{"contents":[]}`, func(c *gin.Context) {
+		t.Fatal("an unsupported method must not be routed")
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_ARGUMENT")
+}
+
+func TestGeminiResponse(t *testing.T) {
+	w := serve(t, &gemini{}, "/gemini/v1beta/models/llama:generateContent", `{"contents":[{"parts":[{"text":"hi"}]}]}`, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"id": "chatcmpl-1",
+			"choices": []interface{}{
+				gin.H{"index": 0, "finish_reason": "length", "message": gin.H{"role": "assistant", "content": "Hello"}},
+				gin.H{"index": 1, "finish_reason": "tool_calls", "message": gin.H{"role": "assistant", "content": nil,
+					"tool_calls": []interface{}{gin.H{"id": "call_1", "type": "function", "function": gin.H{"name": "weather", "arguments": `{"city":"Paris"}`}}}}},
+			},
+			"usage": gin.H{"prompt_tokens": 3, "completion_tokens": 4, "total_tokens": 7},
+		})
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"candidates": [
+			{"index": 0, "content": {"role": "model", "parts": [{"text": "Hello"}]}, "finishReason": "MAX_TOKENS"},
+			{"index": 1, "content": {"role": "model", "parts": [{"functionCall": {"id": "call_1", "name": "weather", "args": {"city": "Paris"}}}]}, "finishReason": "STOP"}
+		],
+		"modelVersion": "llama",
+		"responseId": "chatcmpl-1",
+		"usageMetadata": {"promptTokenCount": 3, "candidatesTokenCount": 4, "totalTokenCount": 7}
+	}`, w.Body.String())
+}
+
+var geminiChunks = []string{
+	`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"}}]}`,
+	`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","function":{"name":"weather","arguments":"{\"city\":"}}]}}]}`,
+	`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]},"finish_reason":"tool_calls"}]}`,
+	`{"id":"chatcmpl-1","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":4,"total_tokens":7}}`,
+}
+
+func TestGeminiStreamSSE(t *testing.T) {
+	w := serve(t, &gemini{}, "/gemini/v1beta/models/llama:streamGenerateContent?alt=sse", `{"contents":[{"parts":[{"text":"hi"}]}]}`, func(c *gin.Context) {
+		streamChunks(c, geminiChunks...)
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	_, events := sseData(t, w.Body.String())
+	require.Len(t, events, 2)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"index":   float64(0),
+		"content": map[string]interface{}{"role": "model", "parts": []interface{}{map[string]interface{}{"text": "Hel"}}},
+	}}, events[0]["candidates"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"index":        float64(0),
+		"finishReason": "STOP",
+		"content": map[string]interface{}{"role": "model", "parts": []interface{}{map[string]interface{}{
+			"functionCall": map[string]interface{}{"id": "call_1", "name": "weather", "args": map[string]interface{}{"city": "Paris"}},
+		}}},
+	}}, events[1]["candidates"])
+	assert.Equal(t, map[string]interface{}{"promptTokenCount": float64(3), "candidatesTokenCount": float64(4), "totalTokenCount": float64(7)}, events[1]["usageMetadata"])
+}
+
+func TestGeminiStreamArray(t *testing.T) {
+	w := serve(t, &gemini{}, "/gemini/v1beta/models/llama:streamGenerateContent", `{"contents":[{"parts":[{"text":"hi"}]}]}`, func(c *gin.Context) {
+		streamChunks(c, geminiChunks...)
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var responses []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &responses))
+	require.Len(t, responses, 2)
+	assert.NotNil(t, responses[1]["usageMetadata"])
+}
@@ -0,0 +1,185 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dialect
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+)
+
+// responseWriter wraps the gin ResponseWriter and translates the chat completions response written by
+// the router. The streams are translated chunk by chunk as they are written, the other responses, errors
+// included, are held back until finish.
+type responseWriter struct {
+	gin.ResponseWriter
+	dialect Dialect
+	req     *request
+
+	status      int
+	wroteHeader bool
+	// streaming is decided on the first write, from the status and the content type of the response.
+	streaming  *bool
+	translator stream
+	done       bool
+	// body is the response held back, or the incomplete line of a stream.
+	body bytes.Buffer
+	size int
+}
+
+func newResponseWriter(w gin.ResponseWriter, d Dialect, req *request) *responseWriter {
+	return &responseWriter{ResponseWriter: w, dialect: d, req: req, status: http.StatusOK}
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if code > 0 && !w.wroteHeader {
+		w.status = code
+	}
+}
+
+func (w *responseWriter) WriteHeaderNow() {
+	w.wroteHeader = true
+}
+
+func (w *responseWriter) Write(data []byte) (int, error) {
+	w.wroteHeader = true
+	if w.streaming == nil {
+		streaming := w.status < http.StatusBadRequest &&
+			strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream")
+		w.streaming = &streaming
+		if streaming {
+			w.translator = w.dialect.newStream(w.req)
+			w.Header().Del("Content-Length")
+			w.Header().Set("Content-Type", w.translator.contentType())
+			w.ResponseWriter.WriteHeader(w.status)
+		}
+	}
+	w.body.Write(data)
+	if *w.streaming {
+		w.translateLines()
+	}
+	return len(data), nil
+}
+
+func (w *responseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush only sends the translated streams, the other responses are sent by finish.
+func (w *responseWriter) Flush() {
+	if w.streaming != nil && *w.streaming {
+		w.ResponseWriter.Flush()
+	}
+}
+
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+func (w *responseWriter) Size() int {
+	if !w.wroteHeader {
+		return -1
+	}
+	return w.size
+}
+
+func (w *responseWriter) Written() bool {
+	return w.wroteHeader
+}
+
+// translateLines translates the complete lines of the stream, the last incomplete one is kept.
+func (w *responseWriter) translateLines() {
+	for {
+		line, err := w.body.ReadBytes('\n')
+		if err != nil {
+			// The incomplete line is put back
+			rest := append([]byte(nil), line...)
+			w.body.Reset()
+			w.body.Write(rest)
+			return
+		}
+		data, ok := bytes.CutPrefix(bytes.TrimSpace(line), []byte("data:"))
+		if !ok || w.done {
+			continue
+		}
+		data = bytes.TrimSpace(data)
+		if string(data) == "[DONE]" {
+			w.done = true
+			w.send(w.translator.done())
+			continue
+		}
+		var chunk chatCompletion
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			klog.Errorf("invalid chat completion chunk: %v", err)
+			continue
+		}
+		w.send(w.translator.chunk(&chunk))
+	}
+}
+
+func (w *responseWriter) send(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	n, err := w.ResponseWriter.Write(data)
+	w.size += n
+	if err != nil {
+		klog.Errorf("failed to write %s stream: %v", w.dialect.Name(), err)
+	}
+}
+
+// finish sends the response held back, translated, or ends a stream cut before its end.
+func (w *responseWriter) finish() {
+	if w.streaming != nil && *w.streaming {
+		if !w.done {
+			w.send(w.translator.interrupted())
+		}
+		w.ResponseWriter.Flush()
+		return
+	}
+	if !w.wroteHeader {
+		return
+	}
+
+	var response interface{}
+	status := w.status
+	if status >= http.StatusBadRequest {
+		response = w.dialect.errorBody(status, errorMessage(status, w.body.Bytes()))
+	} else {
+		var completion chatCompletion
+		if err := json.Unmarshal(w.body.Bytes(), &completion); err != nil {
+			klog.Errorf("invalid chat completion response: %v", err)
+			status = http.StatusBadGateway
+			response = w.dialect.errorBody(status, "invalid response of the model server")
+		} else {
+			response = w.dialect.translateResponse(w.req, &completion)
+		}
+	}
+	body, err := json.Marshal(response)
+	if err != nil {
+		klog.Errorf("failed to marshal %s response: %v", w.dialect.Name(), err)
+		return
+	}
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", "application/json")
+	w.ResponseWriter.WriteHeader(status)
+	w.send(body)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pollInterval is how often Wait checks the in-flight requests.
+const pollInterval = 100 * time.Millisecond
+
+// Drainer tracks the in-flight requests of the router and its draining state.
+//
+// A draining router reports itself as not ready, so that it is removed from the endpoints of its
+// Service, and asks the clients to close their keep-alive connections, so that they reconnect to
+// another replica. It keeps serving the requests it still receives until it is shut down.
+type Drainer struct {
+	inFlight atomic.Int64
+
+	mu      sync.RWMutex
+	started time.Time
+}
+
+// Status is the draining state reported by the drain endpoint.
+type Status struct {
+	Draining bool       `json:"draining"`
+	Since    *time.Time `json:"since,omitempty"`
+	InFlight int64      `json:"inFlight"`
+}
+
+// New creates a Drainer which is not draining.
+func New() *Drainer {
+	return &Drainer{}
+}
+
+// Start starts draining, it returns false if the router was already draining.
+func (d *Drainer) Start() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.started.IsZero() {
+		return false
+	}
+	d.started = time.Now()
+	return true
+}
+
+// Cancel stops draining, the router is ready again.
+func (d *Drainer) Cancel() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.started = time.Time{}
+}
+
+// Draining reports whether the router is draining.
+func (d *Drainer) Draining() bool {
+	return !d.Since().IsZero()
+}
+
+// Since returns when the router started draining, or the zero time if it is not draining.
+func (d *Drainer) Since() time.Time {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.started
+}
+
+// InFlight returns the number of requests being served.
+func (d *Drainer) InFlight() int64 {
+	return d.inFlight.Load()
+}
+
+// Status returns the draining state of the router.
+func (d *Drainer) Status() Status {
+	status := Status{InFlight: d.InFlight()}
+	if since := d.Since(); !since.IsZero() {
+		status.Draining = true
+		status.Since = &since
+	}
+	return status
+}
+
+// Wait blocks until there is no in-flight request, it returns the context error if the context is done before.
+func (d *Drainer) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for d.InFlight() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// Middleware counts the in-flight requests, and closes the client connections once the router is draining.
+func (d *Drainer) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		d.inFlight.Add(1)
+		defer d.inFlight.Add(-1)
+		if d.Draining() {
+			c.Header("Connection", "close")
+		}
+		c.Next()
+	}
+}
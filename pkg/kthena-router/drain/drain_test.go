@@ -0,0 +1,88 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainerStartCancel(t *testing.T) {
+	d := New()
+	assert.False(t, d.Draining())
+	assert.False(t, d.Status().Draining)
+
+	assert.True(t, d.Start())
+	assert.False(t, d.Start(), "draining twice should be reported")
+	assert.True(t, d.Draining())
+	status := d.Status()
+	assert.True(t, status.Draining)
+	require.NotNil(t, status.Since)
+
+	d.Cancel()
+	assert.False(t, d.Draining())
+	assert.Nil(t, d.Status().Since)
+}
+
+func TestDrainerMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	d := New()
+	release := make(chan struct{})
+	engine := gin.New()
+	engine.Use(d.Middleware())
+	engine.GET("/slow", func(c *gin.Context) {
+		<-release
+		c.Status(http.StatusOK)
+	})
+	engine.GET("/fast", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	assert.Empty(t, w.Header().Get("Connection"))
+
+	done := make(chan struct{})
+	go func() {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+		close(done)
+	}()
+	assert.Eventually(t, func() bool { return d.InFlight() == 1 }, time.Second, 10*time.Millisecond)
+
+	// Requests received while draining are served, but the connection is closed afterwards.
+	d.Start()
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "close", w.Header().Get("Connection"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, d.Wait(ctx), context.DeadlineExceeded, "the slow request is still in flight")
+
+	close(release)
+	<-done
+	assert.NoError(t, d.Wait(context.Background()))
+	assert.Equal(t, int64(0), d.InFlight())
+}
@@ -0,0 +1,64 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// apiKeyHashScheme prefixes the API key hashes, which read "sha256:<salt>:<digest>". The digest is the hex
+// encoded SHA-256 of the salt followed by the API key.
+const apiKeyHashScheme = "sha256"
+
+// apiKeyHash is the salted hash of an API key, so that the router configuration never holds the keys themselves.
+type apiKeyHash struct {
+	salt   string
+	digest []byte
+}
+
+// HashAPIKey returns the hash of the API key with the salt, as configured in the consumers.
+func HashAPIKey(salt, apiKey string) string {
+	return fmt.Sprintf("%s:%s:%s", apiKeyHashScheme, salt, hex.EncodeToString(digestAPIKey(salt, apiKey)))
+}
+
+func parseAPIKeyHash(value string) (apiKeyHash, error) {
+	scheme, rest, _ := strings.Cut(value, ":")
+	salt, digest, ok := strings.Cut(rest, ":")
+	if scheme != apiKeyHashScheme || !ok || salt == "" {
+		return apiKeyHash{}, fmt.Errorf("invalid API key hash %q, expected %s:<salt>:<digest>", value, apiKeyHashScheme)
+	}
+	decoded, err := hex.DecodeString(digest)
+	if err != nil || len(decoded) != sha256.Size {
+		return apiKeyHash{}, fmt.Errorf("invalid digest of API key hash %q, expected %d hex encoded bytes", value, sha256.Size)
+	}
+	return apiKeyHash{salt: salt, digest: decoded}, nil
+}
+
+// matches compares the hash of the API key in constant time, so that the timing of the comparison reveals nothing
+// about the configured hash.
+func (h apiKeyHash) matches(apiKey string) bool {
+	return subtle.ConstantTimeCompare(digestAPIKey(h.salt, apiKey), h.digest) == 1
+}
+
+func digestAPIKey(salt, apiKey string) []byte {
+	sum := sha256.Sum256([]byte(salt + apiKey))
+	return sum[:]
+}
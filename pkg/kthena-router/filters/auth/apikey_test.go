@@ -0,0 +1,65 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+func TestAPIKeyHash(t *testing.T) {
+	// echo -n "6b74sk-team-a" | sha256sum
+	value := "sha256:6b74:35e348f7f90b1dfb90ae84c02a1cb9c99871706fbc9c32c0618c6aa251c65aef"
+	assert.Equal(t, value, HashAPIKey("6b74", "sk-team-a"))
+
+	hash, err := parseAPIKeyHash(value)
+	require.NoError(t, err)
+	assert.True(t, hash.matches("sk-team-a"))
+	assert.False(t, hash.matches("sk-team-b"))
+	assert.False(t, hash.matches(""))
+
+	// The same key hashed with another salt has another hash
+	assert.NotEqual(t, value, HashAPIKey("other", "sk-team-a"))
+
+	for _, invalid := range []string{
+		"sk-team-a",
+		"md5:6b74:35e348f7f90b1dfb90ae84c02a1cb9c99871706fbc9c32c0618c6aa251c65aef",
+		"sha256::35e348f7f90b1dfb90ae84c02a1cb9c99871706fbc9c32c0618c6aa251c65aef",
+		"sha256:6b74:35e348f7",
+		"sha256:6b74:not-hex",
+	} {
+		_, err := parseAPIKeyHash(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestValidateAccessControl(t *testing.T) {
+	assert.NoError(t, ValidateAccessControl(conf.AccessControlConfig{
+		Policies: []conf.AccessPolicy{{Name: "a", Consumers: conf.ConsumerSelect{APIKeyHashes: []string{HashAPIKey("salt", "key-a")}}}},
+	}))
+	// The API keys cannot be configured in plaintext
+	assert.Error(t, ValidateAccessControl(conf.AccessControlConfig{
+		Policies: []conf.AccessPolicy{{Name: "a", Consumers: conf.ConsumerSelect{APIKeyHashes: []string{"key-a"}}}},
+	}))
+	assert.Error(t, ValidateAccessControl(conf.AccessControlConfig{
+		Policies: []conf.AccessPolicy{{Name: "a"}},
+	}))
+}
@@ -79,26 +79,41 @@ func (j *JWTAuthenticator) Close() {
 	}
 }
 
-// authenticate validates the token and returns the subject
-func (j *JWTAuthenticator) authenticate(tokenStr string) (string, error) {
+// authenticate validates the token and returns it
+func (j *JWTAuthenticator) authenticate(tokenStr string) (jwt.Token, error) {
 	// Get current JWKS from rotator
 	jwksValue := j.rotator.GetJwks()
 	if jwksValue.Jwks == nil {
-		return "", fmt.Errorf("no JWKS available for token validation")
+		return nil, fmt.Errorf("no JWKS available for token validation")
 	}
 
 	token, err := jwt.Parse([]byte(tokenStr), jwt.WithKeySet(jwksValue.Jwks, jws.WithInferAlgorithmFromKey(true)))
 	if err != nil {
-		return "", fmt.Errorf("failed to parse jwt: %w", err)
+		return nil, fmt.Errorf("failed to parse jwt: %w", err)
 	}
 
 	// Validate the claims in the token
 	if err := j.validateClaims(token, jwksValue); err != nil {
-		return "", fmt.Errorf("failed to validate claims: %w", err)
+		return nil, fmt.Errorf("failed to validate claims: %w", err)
 	}
 
+	return token, nil
+}
+
+// setUserInfo stores the subject and the claims of a validated token in the context,
+// the claims are used to identify the consumer when authorizing access to models.
+func setUserInfo(c *gin.Context, token jwt.Token) {
 	sub, _ := token.Subject()
-	return sub, nil
+	c.Set(common.UserIdKey, sub)
+
+	claims := make(map[string]interface{}, len(token.Keys()))
+	for _, key := range token.Keys() {
+		var value interface{}
+		if err := token.Get(key, &value); err == nil {
+			claims[key] = value
+		}
+	}
+	c.Set(common.UserClaimsKey, claims)
 }
 
 func (j *JWTAuthenticator) validateClaims(token jwt.Token, jwks *Jwks) error {
@@ -293,12 +308,12 @@ func (j *JWTAuthenticator) ValidateToken(ctx context.Context, c *gin.Context, to
 		return fmt.Errorf("authorization header missing or empty")
 	}
 
-	sub, err := j.authenticate(token)
+	validToken, err := j.authenticate(token)
 	if err != nil {
 		return fmt.Errorf("authentication failed: %w", err)
 	}
 
-	c.Set(common.UserIdKey, sub)
+	setUserInfo(c, validToken)
 	return nil
 }
 
@@ -318,12 +333,12 @@ func (j *JWTAuthenticator) Authenticate() gin.HandlerFunc {
 				return
 			}
 
-			sub, err := j.authenticate(token)
+			validToken, err := j.authenticate(token)
 			if err != nil {
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("Unauthorized: %v", err)})
 				return
 			}
-			c.Set(common.UserIdKey, sub)
+			setUserInfo(c, validToken)
 		}
 		c.Next()
 	}
@@ -17,8 +17,259 @@ limitations under the License.
 package auth
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+const (
+	defaultAPIKeyHeader = "X-API-Key"
+
+	ActionAllow = "allow"
+	ActionDeny  = "deny"
 )
 
-func Authorize(ctx *gin.Context) {
+// ModelAccessDeniedError is returned when a consumer is not allowed to use the requested model.
+type ModelAccessDeniedError struct {
+	Model string
+}
+
+func (e *ModelAccessDeniedError) Error() string {
+	return fmt.Sprintf("access to model %q is denied", e.Model)
+}
+
+// ModelAuthorizer enforces the access policies restricting the models each consumer may use,
+// so that a single router can expose different model catalogs to different client groups.
+type ModelAuthorizer struct {
+	enabled      bool
+	apiKeyHeader string
+	defaultAllow bool
+	policies     []*accessPolicy
+}
+
+type accessPolicy struct {
+	name      string
+	consumers *ConsumerSelector
+	allowed   []*regexp.Regexp
+	denied    []*regexp.Regexp
+}
+
+// ConsumerSelector selects the consumers presenting one of its API keys, or a JWT carrying all of its claims.
+type ConsumerSelector struct {
+	apiKeys []apiKeyHash
+	claims  map[string]sets.Set[string]
+}
+
+// NewConsumerSelector creates a ConsumerSelector from its configuration, the invalid API key hashes are ignored.
+func NewConsumerSelector(config conf.ConsumerSelect) *ConsumerSelector {
+	s := &ConsumerSelector{
+		claims: make(map[string]sets.Set[string], len(config.Claims)),
+	}
+	for _, value := range config.APIKeyHashes {
+		hash, err := parseAPIKeyHash(value)
+		if err != nil {
+			klog.Warningf("Ignoring the API key hash of a consumer: %v", err)
+			continue
+		}
+		s.apiKeys = append(s.apiKeys, hash)
+	}
+	for claim, values := range config.Claims {
+		s.claims[claim] = sets.New(values...)
+	}
+	return s
+}
+
+// Empty returns whether the selector selects no consumers.
+func (s *ConsumerSelector) Empty() bool {
+	return len(s.apiKeys) == 0 && len(s.claims) == 0
+}
+
+// ValidateConsumers checks the API key hashes of the consumer selector.
+func ValidateConsumers(config conf.ConsumerSelect) error {
+	for _, value := range config.APIKeyHashes {
+		if _, err := parseAPIKeyHash(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateAccessControl checks the access control configuration, NewModelAuthorizer ignores the invalid settings.
+func ValidateAccessControl(config conf.AccessControlConfig) error {
+	switch strings.ToLower(config.DefaultAction) {
+	case ActionAllow, ActionDeny, "":
+	default:
+		return fmt.Errorf("invalid access control default action %q", config.DefaultAction)
+	}
+	for _, policy := range config.Policies {
+		if len(policy.Consumers.APIKeyHashes) == 0 && len(policy.Consumers.Claims) == 0 {
+			return fmt.Errorf("access policy %q selects no consumers", policy.Name)
+		}
+		if err := ValidateConsumers(policy.Consumers); err != nil {
+			return fmt.Errorf("access policy %q: %v", policy.Name, err)
+		}
+	}
+	return nil
+}
+
+// NewModelAuthorizer creates a ModelAuthorizer from the access control configuration
+func NewModelAuthorizer(routerConfig *conf.RouterConfiguration) *ModelAuthorizer {
+	if routerConfig == nil || len(routerConfig.Access.Policies) == 0 {
+		klog.V(4).Info("Access policies not configured, model authorization disabled")
+		return &ModelAuthorizer{enabled: false}
+	}
+
+	config := routerConfig.Access
+	a := &ModelAuthorizer{
+		enabled:      true,
+		apiKeyHeader: config.APIKeyHeader,
+	}
+	if a.apiKeyHeader == "" {
+		a.apiKeyHeader = defaultAPIKeyHeader
+	}
+	switch strings.ToLower(config.DefaultAction) {
+	case ActionAllow:
+		a.defaultAllow = true
+	case ActionDeny, "":
+		a.defaultAllow = false
+	default:
+		klog.Warningf("Invalid access control default action %q, denying consumers not matched by any policy", config.DefaultAction)
+	}
+
+	for _, policy := range config.Policies {
+		p := &accessPolicy{
+			name:      policy.Name,
+			consumers: NewConsumerSelector(policy.Consumers),
+			allowed:   compilePatterns(policy.AllowedModels),
+			denied:    compilePatterns(policy.DeniedModels),
+		}
+		if p.consumers.Empty() {
+			klog.Warningf("Access policy %q selects no consumers, ignoring it", policy.Name)
+			continue
+		}
+		a.policies = append(a.policies, p)
+	}
+
+	return a
+}
+
+// APIKeyHeader returns the header carrying the API keys of the consumers.
+func (a *ModelAuthorizer) APIKeyHeader() string {
+	if a == nil || a.apiKeyHeader == "" {
+		return defaultAPIKeyHeader
+	}
+	return a.apiKeyHeader
+}
+
+// IsEnabled returns whether model authorization is enabled
+func (a *ModelAuthorizer) IsEnabled() bool {
+	return a.enabled
+}
+
+// Authorize checks whether the consumer of the request is allowed to use the model.
+// Consumers are identified by their API key or by the JWT claims set during authentication.
+func (a *ModelAuthorizer) Authorize(c *gin.Context, model string) error {
+	if !a.enabled {
+		return nil
+	}
+
+	apiKey := c.GetHeader(a.apiKeyHeader)
+	claims, _ := c.Get(common.UserClaimsKey)
+	claimsMap, _ := claims.(map[string]interface{})
+
+	matched, allowed := false, false
+	for _, policy := range a.policies {
+		if !policy.consumers.Selects(apiKey, claimsMap) {
+			continue
+		}
+		matched = true
+		if matchAny(policy.denied, model) {
+			klog.V(4).Infof("Model %s is denied by access policy %s", model, policy.name)
+			return &ModelAccessDeniedError{Model: model}
+		}
+		if len(policy.allowed) == 0 || matchAny(policy.allowed, model) {
+			allowed = true
+		}
+	}
+
+	if !matched {
+		allowed = a.defaultAllow
+	}
+	if !allowed {
+		return &ModelAccessDeniedError{Model: model}
+	}
+	return nil
+}
+
+// Selects returns whether the consumer presenting the API key and the claims is selected
+func (s *ConsumerSelector) Selects(apiKey string, claims map[string]interface{}) bool {
+	if apiKey != "" {
+		for _, hash := range s.apiKeys {
+			if hash.matches(apiKey) {
+				return true
+			}
+		}
+	}
+	if len(s.claims) == 0 || claims == nil {
+		return false
+	}
+	for claim, values := range s.claims {
+		if !claimHasValue(claims[claim], values) {
+			return false
+		}
+	}
+	return true
+}
+
+// claimHasValue returns whether the claim, which is a single value or a list of values, contains one of the values
+func claimHasValue(claim interface{}, values sets.Set[string]) bool {
+	switch v := claim.(type) {
+	case string:
+		return values.Has(v)
+	case []string:
+		for _, item := range v {
+			if values.Has(item) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && values.Has(s) {
+				return true
+			}
+		}
+	case nil:
+		return false
+	default:
+		return values.Has(fmt.Sprint(v))
+	}
+	return false
+}
+
+// compilePatterns converts model name patterns with '*' and '?' wildcards to regular expressions
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	result := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		expr := regexp.QuoteMeta(pattern)
+		expr = strings.ReplaceAll(expr, `\*`, ".*")
+		expr = strings.ReplaceAll(expr, `\?`, ".")
+		result = append(result, regexp.MustCompile("^"+expr+"$"))
+	}
+	return result
+}
+
+func matchAny(patterns []*regexp.Regexp, model string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(model) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,161 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+func TestNewModelAuthorizer(t *testing.T) {
+	t.Run("nil config", func(t *testing.T) {
+		authorizer := NewModelAuthorizer(nil)
+		assert.False(t, authorizer.IsEnabled())
+		assert.NoError(t, authorizer.Authorize(newTestContext("", nil), "any-model"))
+	})
+
+	t.Run("no policies", func(t *testing.T) {
+		authorizer := NewModelAuthorizer(&conf.RouterConfiguration{})
+		assert.False(t, authorizer.IsEnabled())
+	})
+}
+
+func TestModelAuthorizer_Authorize(t *testing.T) {
+	config := &conf.RouterConfiguration{
+		Access: conf.AccessControlConfig{
+			Policies: []conf.AccessPolicy{
+				{
+					Name:          "team-a",
+					Consumers:     conf.ConsumerSelect{APIKeyHashes: []string{HashAPIKey("salt-a", "key-a")}},
+					AllowedModels: []string{"llama-*"},
+					DeniedModels:  []string{"llama-*-70b"},
+				},
+				{
+					Name: "research",
+					Consumers: conf.ConsumerSelect{Claims: map[string][]string{
+						"groups": {"research"},
+						"tier":   {"gold", "silver"},
+					}},
+				},
+				{
+					Name:         "no-deepseek",
+					Consumers:    conf.ConsumerSelect{Claims: map[string][]string{"tier": {"silver"}}},
+					DeniedModels: []string{"deepseek-ai/*"},
+				},
+			},
+		},
+	}
+	authorizer := NewModelAuthorizer(config)
+	assert.True(t, authorizer.IsEnabled())
+
+	tests := []struct {
+		name    string
+		apiKey  string
+		claims  map[string]interface{}
+		model   string
+		allowed bool
+	}{
+		{
+			name:    "api key allowed model",
+			apiKey:  "key-a",
+			model:   "llama-3-8b",
+			allowed: true,
+		},
+		{
+			name:   "api key model not in allow list",
+			apiKey: "key-a",
+			model:  "qwen-7b",
+		},
+		{
+			name:   "deny takes precedence over allow",
+			apiKey: "key-a",
+			model:  "llama-3-70b",
+		},
+		{
+			name:   "unknown api key is denied by default",
+			apiKey: "key-b",
+			model:  "llama-3-8b",
+		},
+		{
+			name:    "claims select policy without allow list",
+			claims:  map[string]interface{}{"groups": []interface{}{"dev", "research"}, "tier": "gold"},
+			model:   "deepseek-ai/DeepSeek-R1",
+			allowed: true,
+		},
+		{
+			name:   "claims missing one required claim",
+			claims: map[string]interface{}{"groups": []interface{}{"research"}},
+			model:  "llama-3-8b",
+		},
+		{
+			name:   "deny of another matching policy applies",
+			claims: map[string]interface{}{"groups": []interface{}{"research"}, "tier": "silver"},
+			model:  "deepseek-ai/DeepSeek-R1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizer.Authorize(newTestContext(tt.apiKey, tt.claims), tt.model)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.IsType(t, &ModelAccessDeniedError{}, err)
+			}
+		})
+	}
+}
+
+func TestModelAuthorizer_DefaultAllow(t *testing.T) {
+	authorizer := NewModelAuthorizer(&conf.RouterConfiguration{
+		Access: conf.AccessControlConfig{
+			APIKeyHeader:  "X-Consumer-Key",
+			DefaultAction: "allow",
+			Policies: []conf.AccessPolicy{
+				{
+					Name:         "restricted",
+					Consumers:    conf.ConsumerSelect{APIKeyHashes: []string{HashAPIKey("salt", "restricted-key")}},
+					DeniedModels: []string{"*"},
+				},
+			},
+		},
+	})
+
+	assert.NoError(t, authorizer.Authorize(newTestContext("", nil), "llama-3-8b"))
+
+	c := newTestContext("", nil)
+	c.Request.Header.Set("X-Consumer-Key", "restricted-key")
+	assert.Error(t, authorizer.Authorize(c, "llama-3-8b"))
+}
+
+func newTestContext(apiKey string, claims map[string]interface{}) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/v1/chat/completions", nil)
+	if apiKey != "" {
+		c.Request.Header.Set(defaultAPIKeyHeader, apiKey)
+	}
+	if claims != nil {
+		c.Set(common.UserClaimsKey, claims)
+	}
+	return c
+}
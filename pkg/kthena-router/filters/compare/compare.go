@@ -0,0 +1,230 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compare
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Config holds the traffic compare configuration.
+type Config struct {
+	// MaxSamples bounds the number of samples kept for each ModelRoute.
+	MaxSamples int
+	// MaxConcurrency bounds the number of requests being replayed at the same time.
+	// Samples arriving while the comparator is saturated are dropped.
+	MaxConcurrency int
+	// Timeout bounds how long a replayed request may take.
+	Timeout time.Duration
+	// MaxBodyBytes is the largest response body that is read from a model server.
+	MaxBodyBytes int
+	// Embedder scores the similarity of the outputs, nil to only compare them exactly.
+	Embedder *Embedder
+	// Exporters receive the samples once they are compared.
+	Exporters []Exporter
+}
+
+// Target is a model server a sampled request is replayed to.
+type Target struct {
+	// ModelServer is the namespaced name of the model server.
+	ModelServer string
+	// Pod is the name of the pod serving the replayed request.
+	Pod string
+	// URL is the endpoint of the pod the request is sent to.
+	URL string
+	// Body is the request body, with the model rewritten for the model server.
+	Body []byte
+}
+
+// Comparator replays sampled requests to a baseline and a candidate model server
+// out-of-band and records both responses in its Store.
+type Comparator struct {
+	store        *Store
+	client       *http.Client
+	slots        chan struct{}
+	timeout      time.Duration
+	maxBodyBytes int
+	embedder     *Embedder
+	exporters    []Exporter
+}
+
+func NewComparator(config *Config) *Comparator {
+	return &Comparator{
+		store:        NewStore(config.MaxSamples),
+		client:       &http.Client{},
+		slots:        make(chan struct{}, config.MaxConcurrency),
+		timeout:      config.Timeout,
+		maxBodyBytes: config.MaxBodyBytes,
+		embedder:     config.Embedder,
+		exporters:    config.Exporters,
+	}
+}
+
+// Store returns the store holding the compared samples.
+func (c *Comparator) Store() *Store {
+	return c.store
+}
+
+// MaxBodyBytes is the largest response body that is compared.
+func (c *Comparator) MaxBodyBytes() int {
+	return c.maxBodyBytes
+}
+
+// Close writes the samples the exporters have not exported yet.
+func (c *Comparator) Close() error {
+	var errs []string
+	for _, exporter := range c.exporters {
+		if err := exporter.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Sampled reports whether a request is picked given the sample percentage.
+func Sampled(percent uint32) bool {
+	if percent == 0 {
+		return false
+	}
+	return uint32(rand.Intn(100)) < percent
+}
+
+// Compare replays a request to both the baseline and the candidate in the background and
+// records the responses under the given model route. It returns false when the comparator
+// is saturated and the sample is dropped.
+func (c *Comparator) Compare(modelRoute, requestID, model string, baseline, candidate Target) bool {
+	select {
+	case c.slots <- struct{}{}:
+	default:
+		klog.V(4).Infof("traffic compare for model route %s is saturated, dropping request %s", modelRoute, requestID)
+		return false
+	}
+
+	go func() {
+		defer func() { <-c.slots }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		defer cancel()
+
+		sample := &Sample{
+			RequestID: requestID,
+			Model:     model,
+			Timestamp: time.Now(),
+		}
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			sample.Baseline = c.send(ctx, baseline)
+		}()
+		go func() {
+			defer wg.Done()
+			sample.Candidate = c.send(ctx, candidate)
+		}()
+		wg.Wait()
+
+		c.Record(ctx, modelRoute, sample)
+	}()
+	return true
+}
+
+// Record scores the similarity of the outputs of a compared sample, then stores and exports it.
+func (c *Comparator) Record(ctx context.Context, modelRoute string, sample *Sample) {
+	if c.embedder != nil && sample.Succeeded() {
+		similarity, err := c.embedder.Similarity(ctx, sample.Baseline.Output, sample.Candidate.Output)
+		if err != nil {
+			klog.V(4).Infof("failed to score the similarity of request %s of model route %s: %v", sample.RequestID, modelRoute, err)
+		} else {
+			sample.Similarity = &similarity
+		}
+	}
+	c.store.Add(modelRoute, sample)
+	for _, exporter := range c.exporters {
+		if err := exporter.Export(modelRoute, sample); err != nil {
+			klog.Errorf("failed to export the compared request %s of model route %s: %v", sample.RequestID, modelRoute, err)
+		}
+	}
+	klog.V(4).Infof("compared request %s of model route %s, exact match: %v", sample.RequestID, modelRoute, sample.ExactMatch())
+}
+
+func (c *Comparator) send(ctx context.Context, target Target) (result Result) {
+	result.ModelServer = target.ModelServer
+	result.Pod = target.Pod
+	start := time.Now()
+	defer func() {
+		result.LatencyMilliseconds = time.Since(start).Milliseconds()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(target.Body))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(c.maxBodyBytes)+1))
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to read response: %v", err)
+		return result
+	}
+	if len(body) > c.maxBodyBytes {
+		result.Error = fmt.Sprintf("response exceeds %d bytes", c.maxBodyBytes)
+		return result
+	}
+	if resp.StatusCode != http.StatusOK {
+		result.Error = fmt.Sprintf("http resp error, http code is %d", resp.StatusCode)
+		return result
+	}
+	result.Output = ExtractOutput(body)
+	return result
+}
+
+// NewResult builds the result of a response which was already received, e.g. the one returned to the client.
+func NewResult(modelServer, pod string, statusCode int, body []byte, latency time.Duration) Result {
+	result := Result{
+		ModelServer:         modelServer,
+		Pod:                 pod,
+		StatusCode:          statusCode,
+		LatencyMilliseconds: latency.Milliseconds(),
+	}
+	if statusCode != http.StatusOK {
+		result.Error = fmt.Sprintf("http resp error, http code is %d", statusCode)
+		return result
+	}
+	result.Output = ExtractOutput(body)
+	return result
+}
@@ -0,0 +1,267 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "chat completion",
+			body: `{"id":"chatcmpl-1","created":1,"choices":[{"message":{"role":"assistant","content":"hello"}}]}`,
+			want: "hello",
+		},
+		{
+			name: "completion with multiple choices",
+			body: `{"id":"cmpl-1","choices":[{"text":"a"},{"text":"b"}]}`,
+			want: "a\nb",
+		},
+		{
+			name: "chat completion with tool calls",
+			body: `{"choices":[{"message":{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]}}]}`,
+			want: `get_weather({"city":"Paris"})`,
+		},
+		{
+			name: "responses API",
+			body: `{"id":"resp_1","object":"response","output":[{"type":"reasoning","summary":[]},{"type":"message","role":"assistant","content":[{"type":"output_text","text":"hello","annotations":[]}]}]}`,
+			want: "hello",
+		},
+		{
+			name: "not a completion",
+			body: ` {"data":[]} `,
+			want: `{"data":[]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExtractOutput([]byte(tt.body)))
+		})
+	}
+}
+
+func TestStoreEvictsOldestSamples(t *testing.T) {
+	store := NewStore(2)
+	for i := 0; i < 3; i++ {
+		store.Add("default/route", &Sample{RequestID: fmt.Sprintf("req-%d", i)})
+	}
+	samples := store.List("default/route")
+	require.Len(t, samples, 2)
+	assert.Equal(t, "req-1", samples[0].RequestID)
+	assert.Equal(t, "req-2", samples[1].RequestID)
+	assert.Equal(t, []string{"default/route"}, store.ModelRoutes())
+
+	store.Delete("default/route")
+	assert.Empty(t, store.List("default/route"))
+}
+
+func TestBuildReport(t *testing.T) {
+	now := time.Now()
+	samples := []*Sample{
+		{
+			Timestamp:  now,
+			Baseline:   Result{Output: "hello", LatencyMilliseconds: 100},
+			Candidate:  Result{Output: "hello", LatencyMilliseconds: 80},
+			Similarity: func(s float64) *float64 { return &s }(1),
+		},
+		{
+			Timestamp:  now.Add(time.Second),
+			Baseline:   Result{Output: "hello", LatencyMilliseconds: 100},
+			Candidate:  Result{Output: "hello world", LatencyMilliseconds: 120},
+			Similarity: func(s float64) *float64 { return &s }(0.5),
+		},
+		{
+			Timestamp: now.Add(2 * time.Second),
+			Baseline:  Result{Output: "hello", LatencyMilliseconds: 100},
+			Candidate: Result{Error: "http resp error, http code is 500", LatencyMilliseconds: 5},
+		},
+	}
+
+	report := BuildReport("default/route", samples)
+	assert.Equal(t, 3, report.Samples)
+	assert.Equal(t, 1, report.FailedSamples)
+	assert.Equal(t, 0, report.BaselineErrors)
+	assert.Equal(t, 1, report.CandidateErrors)
+	assert.Equal(t, 1, report.ExactMatches)
+	assert.Equal(t, 0.5, report.ExactMatchRate)
+	assert.Equal(t, 3.0, report.AvgLengthDelta)
+	assert.Equal(t, 2, report.ScoredSamples)
+	assert.Equal(t, 0.75, report.AvgSimilarity)
+	assert.Equal(t, 100.0, report.BaselineAvgLatencyMilliseconds)
+	assert.Equal(t, 100.0, report.CandidateAvgLatencyMilliseconds)
+	assert.Equal(t, 0.0, report.AvgLatencyDeltaMilliseconds)
+	assert.Equal(t, int64(120), report.CandidateP99LatencyMilliseconds)
+	assert.Equal(t, now, *report.From)
+	assert.Equal(t, now.Add(2*time.Second), *report.To)
+
+	empty := BuildReport("default/other", nil)
+	assert.Equal(t, 0, empty.Samples)
+	assert.Nil(t, empty.From)
+}
+
+func newModelServer(t *testing.T, status int, content string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = fmt.Fprintf(w, `{"choices":[{"message":{"content":%q}}]}`, content)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestComparatorCompare(t *testing.T) {
+	baseline := newModelServer(t, http.StatusOK, "hello")
+	candidate := newModelServer(t, http.StatusInternalServerError, "")
+
+	comparator := NewComparator(&Config{
+		MaxSamples:     10,
+		MaxConcurrency: 1,
+		Timeout:        5 * time.Second,
+		MaxBodyBytes:   1 << 10,
+	})
+	ok := comparator.Compare("default/route", "req-1", "llama",
+		Target{ModelServer: "default/baseline", URL: baseline.URL + "/v1/chat/completions", Body: []byte(`{}`)},
+		Target{ModelServer: "default/candidate", URL: candidate.URL + "/v1/chat/completions", Body: []byte(`{}`)},
+	)
+	require.True(t, ok)
+
+	require.Eventually(t, func() bool {
+		return len(comparator.Store().List("default/route")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	sample := comparator.Store().List("default/route")[0]
+	assert.Equal(t, "req-1", sample.RequestID)
+	assert.Equal(t, "llama", sample.Model)
+	assert.Equal(t, "default/baseline", sample.Baseline.ModelServer)
+	assert.Equal(t, "hello", sample.Baseline.Output)
+	assert.Empty(t, sample.Baseline.Error)
+	assert.Equal(t, http.StatusInternalServerError, sample.Candidate.StatusCode)
+	assert.NotEmpty(t, sample.Candidate.Error)
+	assert.False(t, sample.ExactMatch())
+}
+
+func TestComparatorDropsWhenSaturated(t *testing.T) {
+	comparator := NewComparator(&Config{MaxSamples: 10, MaxConcurrency: 1, Timeout: time.Second, MaxBodyBytes: 1 << 10})
+	comparator.slots <- struct{}{}
+	assert.False(t, comparator.Compare("default/route", "req-1", "llama", Target{}, Target{}))
+}
+
+func TestSampled(t *testing.T) {
+	assert.False(t, Sampled(0))
+	assert.True(t, Sampled(100))
+}
+
+func TestEmbedderSimilarity(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		assert.Equal(t, "bge-m3", req.Model)
+		assert.Equal(t, []string{"hello", "hi"}, req.Input)
+		// The embeddings are not returned in the order of the inputs
+		_, _ = fmt.Fprint(w, `{"data":[{"index":1,"embedding":[1,1]},{"index":0,"embedding":[1,0]}]}`)
+	}))
+	defer server.Close()
+	embedder := NewEmbedder(server.URL+"/v1/embeddings", "bge-m3")
+
+	similarity, err := embedder.Similarity(context.Background(), "hello", "hi")
+	require.NoError(t, err)
+	assert.InDelta(t, 1/math.Sqrt2, similarity, 1e-9)
+
+	similarity, err = embedder.Similarity(context.Background(), "hello", "hello")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, similarity)
+	similarity, err = embedder.Similarity(context.Background(), "hello", "")
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, similarity)
+	assert.Equal(t, 1, requests)
+}
+
+func TestComparatorRecordScoresAndExports(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"data":[{"index":0,"embedding":[1,0]},{"index":1,"embedding":[0,1]}]}`)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	exporter := NewBatchExporter(DirWriter(dir), 2, time.Hour)
+	comparator := NewComparator(&Config{
+		MaxSamples:     10,
+		MaxConcurrency: 1,
+		Timeout:        time.Second,
+		MaxBodyBytes:   1 << 10,
+		Embedder:       NewEmbedder(server.URL, ""),
+		Exporters:      []Exporter{exporter},
+	})
+
+	comparator.Record(context.Background(), "default/route", &Sample{
+		RequestID: "req-1",
+		Baseline:  NewResult("default/primary", "pod-1", http.StatusOK, []byte(`{"choices":[{"text":"yes"}]}`), time.Second),
+		Candidate: NewResult("default/shadow", "pod-2", http.StatusOK, []byte(`{"choices":[{"text":"no"}]}`), time.Second),
+	})
+	comparator.Record(context.Background(), "default/route", &Sample{
+		RequestID: "req-2",
+		Baseline:  NewResult("default/primary", "pod-1", http.StatusOK, []byte(`{"choices":[{"text":"yes"}]}`), time.Second),
+		Candidate: NewResult("default/shadow", "pod-2", http.StatusServiceUnavailable, nil, time.Second),
+	})
+	comparator.Record(context.Background(), "default/route", &Sample{RequestID: "req-3"})
+
+	samples := comparator.Store().List("default/route")
+	require.Len(t, samples, 3)
+	require.NotNil(t, samples[0].Similarity)
+	assert.Equal(t, 0.0, *samples[0].Similarity)
+	assert.Equal(t, "yes", samples[0].Baseline.Output)
+	assert.Nil(t, samples[1].Similarity)
+	assert.NotEmpty(t, samples[1].Candidate.Error)
+
+	// The first batch is written once full, the rest when the comparator is closed
+	files, err := filepath.Glob(filepath.Join(dir, "default", "route", "*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	body, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	require.Len(t, lines, 2)
+	var exported Sample
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &exported))
+	assert.Equal(t, "req-1", exported.RequestID)
+
+	require.NoError(t, comparator.Close())
+	files, err = filepath.Glob(filepath.Join(dir, "default", "route", "*.jsonl"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compare
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Exporter receives the compared samples, e.g. to keep them for an offline review.
+type Exporter interface {
+	Export(modelRoute string, sample *Sample) error
+	// Close writes the samples not exported yet.
+	Close() error
+}
+
+// BatchWriter stores a batch of samples, encoded as JSON lines, under a key <namespace>/<name>/<time>-<hostname>.jsonl.
+type BatchWriter func(key string, data []byte) error
+
+// DirWriter writes the batches to new files of the directory. The files are never appended to, so that the
+// directory can be a mounted object storage bucket, e.g. with the Mountpoint for Amazon S3 or the Cloud Storage
+// FUSE CSI driver.
+func DirWriter(dir string) BatchWriter {
+	return func(key string, data []byte) error {
+		path := filepath.Join(dir, filepath.FromSlash(key))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, 0o644)
+	}
+}
+
+// BatchExporter writes the samples of each ModelRoute in batches with its BatchWriter.
+type BatchExporter struct {
+	write     BatchWriter
+	hostname  string
+	batchSize int
+	interval  time.Duration
+
+	mutex sync.Mutex
+	// modelRoute -> samples not written yet
+	pending map[string][]*Sample
+}
+
+// NewBatchExporter creates an exporter writing a batch once a ModelRoute has batchSize samples, or interval after
+// its first sample not written yet.
+func NewBatchExporter(write BatchWriter, batchSize int, interval time.Duration) *BatchExporter {
+	hostname, _ := os.Hostname()
+	return &BatchExporter{
+		write:     write,
+		hostname:  hostname,
+		batchSize: batchSize,
+		interval:  interval,
+		pending:   make(map[string][]*Sample),
+	}
+}
+
+func (e *BatchExporter) Export(modelRoute string, sample *Sample) error {
+	e.mutex.Lock()
+	samples := append(e.pending[modelRoute], sample)
+	e.pending[modelRoute] = samples
+	e.mutex.Unlock()
+
+	if len(samples) == 1 {
+		time.AfterFunc(e.interval, func() {
+			if err := e.flush(modelRoute); err != nil {
+				klog.Errorf("failed to export the compared samples of model route %s: %v", modelRoute, err)
+			}
+		})
+	}
+	if len(samples) >= e.batchSize {
+		return e.flush(modelRoute)
+	}
+	return nil
+}
+
+func (e *BatchExporter) Close() error {
+	e.mutex.Lock()
+	modelRoutes := make([]string, 0, len(e.pending))
+	for modelRoute := range e.pending {
+		modelRoutes = append(modelRoutes, modelRoute)
+	}
+	e.mutex.Unlock()
+
+	var errs []string
+	for _, modelRoute := range modelRoutes {
+		if err := e.flush(modelRoute); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to export the compared samples: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// flush writes the pending samples of the ModelRoute in a new batch.
+func (e *BatchExporter) flush(modelRoute string) error {
+	e.mutex.Lock()
+	samples := e.pending[modelRoute]
+	delete(e.pending, modelRoute)
+	e.mutex.Unlock()
+	if len(samples) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, sample := range samples {
+		if err := encoder.Encode(sample); err != nil {
+			return err
+		}
+	}
+	key := fmt.Sprintf("%s/%s-%s.jsonl", modelRoute, time.Now().UTC().Format("20060102T150405.000000000Z"), e.hostname)
+	return e.write(key, body.Bytes())
+}
@@ -0,0 +1,110 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+)
+
+// Embedder scores the semantic similarity of two outputs with the cosine similarity of their embeddings,
+// requested from an OpenAI compatible embeddings endpoint.
+type Embedder struct {
+	url    string
+	model  string
+	client *http.Client
+}
+
+// NewEmbedder creates an embedder requesting the embeddings of the model from the endpoint URL.
+// The model may be empty when the endpoint serves a single one.
+func NewEmbedder(url, model string) *Embedder {
+	return &Embedder{
+		url:    url,
+		model:  model,
+		client: &http.Client{},
+	}
+}
+
+// Similarity returns the cosine similarity of the embeddings of both outputs, 1 for identical outputs.
+// An empty output is not similar to any other one.
+func (e *Embedder) Similarity(ctx context.Context, a, b string) (float64, error) {
+	if a == b {
+		return 1, nil
+	}
+	if a == "" || b == "" {
+		return 0, nil
+	}
+	request := map[string]interface{}{"input": []string{a, b}}
+	if e.model != "" {
+		request["model"] = e.model
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return 0, fmt.Errorf("embeddings request failed, http code is %d", resp.StatusCode)
+	}
+
+	var embeddings struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&embeddings); err != nil {
+		return 0, fmt.Errorf("invalid embeddings response: %v", err)
+	}
+	if len(embeddings.Data) != 2 {
+		return 0, fmt.Errorf("expected 2 embeddings, got %d", len(embeddings.Data))
+	}
+	sort.Slice(embeddings.Data, func(i, j int) bool { return embeddings.Data[i].Index < embeddings.Data[j].Index })
+	return cosineSimilarity(embeddings.Data[0].Embedding, embeddings.Data[1].Embedding)
+}
+
+func cosineSimilarity(a, b []float64) (float64, error) {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0, fmt.Errorf("embeddings have different dimensions: %d and %d", len(a), len(b))
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0, nil
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB)), nil
+}
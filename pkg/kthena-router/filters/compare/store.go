@@ -0,0 +1,221 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compare
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/handlers"
+)
+
+// Result is the response of one model server to a replayed request.
+type Result struct {
+	ModelServer         string `json:"modelServer"`
+	Pod                 string `json:"pod,omitempty"`
+	StatusCode          int    `json:"statusCode,omitempty"`
+	Output              string `json:"output,omitempty"`
+	LatencyMilliseconds int64  `json:"latencyMilliseconds"`
+	Error               string `json:"error,omitempty"`
+}
+
+// Sample is a request replayed to both the baseline and the candidate.
+type Sample struct {
+	RequestID string    `json:"requestID"`
+	Model     string    `json:"model"`
+	Timestamp time.Time `json:"timestamp"`
+	Baseline  Result    `json:"baseline"`
+	Candidate Result    `json:"candidate"`
+	// Similarity is the cosine similarity of the embeddings of both outputs, when it was scored.
+	Similarity *float64 `json:"similarity,omitempty"`
+}
+
+// Succeeded reports whether both model servers answered the request.
+func (s *Sample) Succeeded() bool {
+	return s.Baseline.Error == "" && s.Candidate.Error == ""
+}
+
+// ExactMatch reports whether both model servers generated the same output.
+func (s *Sample) ExactMatch() bool {
+	return s.Succeeded() && s.Baseline.Output == s.Candidate.Output
+}
+
+// Report summarizes the differences between the baseline and the candidate of a ModelRoute.
+// Deltas are computed as candidate minus baseline over the samples answered by both model servers.
+type Report struct {
+	ModelRoute      string  `json:"modelRoute"`
+	Samples         int     `json:"samples"`
+	FailedSamples   int     `json:"failedSamples"`
+	BaselineErrors  int     `json:"baselineErrors"`
+	CandidateErrors int     `json:"candidateErrors"`
+	ExactMatches    int     `json:"exactMatches"`
+	ExactMatchRate  float64 `json:"exactMatchRate"`
+	// AvgLengthDelta is the average difference of the output length in characters.
+	AvgLengthDelta float64 `json:"avgLengthDelta"`
+	// ScoredSamples are the samples whose similarity was scored, AvgSimilarity is their average similarity.
+	ScoredSamples int     `json:"scoredSamples,omitempty"`
+	AvgSimilarity float64 `json:"avgSimilarity,omitempty"`
+	// AvgLatencyDeltaMilliseconds is the average difference of the end-to-end latency.
+	AvgLatencyDeltaMilliseconds     float64    `json:"avgLatencyDeltaMilliseconds"`
+	BaselineAvgLatencyMilliseconds  float64    `json:"baselineAvgLatencyMilliseconds"`
+	CandidateAvgLatencyMilliseconds float64    `json:"candidateAvgLatencyMilliseconds"`
+	BaselineP99LatencyMilliseconds  int64      `json:"baselineP99LatencyMilliseconds"`
+	CandidateP99LatencyMilliseconds int64      `json:"candidateP99LatencyMilliseconds"`
+	From                            *time.Time `json:"from,omitempty"`
+	To                              *time.Time `json:"to,omitempty"`
+}
+
+// Store keeps the most recent samples of each ModelRoute in memory.
+type Store struct {
+	mutex      sync.RWMutex
+	maxSamples int
+	// modelRoute -> samples, oldest first
+	samples map[string][]*Sample
+}
+
+func NewStore(maxSamples int) *Store {
+	return &Store{
+		maxSamples: maxSamples,
+		samples:    make(map[string][]*Sample),
+	}
+}
+
+// Add records a sample, evicting the oldest one once the store is full.
+func (s *Store) Add(modelRoute string, sample *Sample) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	samples := append(s.samples[modelRoute], sample)
+	if len(samples) > s.maxSamples {
+		samples = samples[len(samples)-s.maxSamples:]
+	}
+	s.samples[modelRoute] = samples
+}
+
+// List returns the samples of a ModelRoute, oldest first.
+func (s *Store) List(modelRoute string) []*Sample {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]*Sample(nil), s.samples[modelRoute]...)
+}
+
+// ModelRoutes returns the model routes that have samples, sorted by name.
+func (s *Store) ModelRoutes() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	routes := make([]string, 0, len(s.samples))
+	for route := range s.samples {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// Delete drops the samples of a ModelRoute.
+func (s *Store) Delete(modelRoute string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.samples, modelRoute)
+}
+
+// Report builds the diff report of a ModelRoute from its samples.
+func (s *Store) Report(modelRoute string) *Report {
+	return BuildReport(modelRoute, s.List(modelRoute))
+}
+
+// BuildReport summarizes the given samples.
+func BuildReport(modelRoute string, samples []*Sample) *Report {
+	report := &Report{
+		ModelRoute: modelRoute,
+		Samples:    len(samples),
+	}
+	if len(samples) == 0 {
+		return report
+	}
+	from, to := samples[0].Timestamp, samples[len(samples)-1].Timestamp
+	report.From, report.To = &from, &to
+
+	var lengthDelta, baselineLatency, candidateLatency int64
+	var similarity float64
+	baselineLatencies := make([]int64, 0, len(samples))
+	candidateLatencies := make([]int64, 0, len(samples))
+	for _, sample := range samples {
+		if sample.Baseline.Error != "" {
+			report.BaselineErrors++
+		}
+		if sample.Candidate.Error != "" {
+			report.CandidateErrors++
+		}
+		if !sample.Succeeded() {
+			report.FailedSamples++
+			continue
+		}
+		if sample.ExactMatch() {
+			report.ExactMatches++
+		}
+		if sample.Similarity != nil {
+			report.ScoredSamples++
+			similarity += *sample.Similarity
+		}
+		lengthDelta += int64(utf8.RuneCountInString(sample.Candidate.Output) - utf8.RuneCountInString(sample.Baseline.Output))
+		baselineLatency += sample.Baseline.LatencyMilliseconds
+		candidateLatency += sample.Candidate.LatencyMilliseconds
+		baselineLatencies = append(baselineLatencies, sample.Baseline.LatencyMilliseconds)
+		candidateLatencies = append(candidateLatencies, sample.Candidate.LatencyMilliseconds)
+	}
+
+	compared := float64(len(samples) - report.FailedSamples)
+	if compared == 0 {
+		return report
+	}
+	report.ExactMatchRate = float64(report.ExactMatches) / compared
+	report.AvgLengthDelta = float64(lengthDelta) / compared
+	if report.ScoredSamples > 0 {
+		report.AvgSimilarity = similarity / float64(report.ScoredSamples)
+	}
+	report.BaselineAvgLatencyMilliseconds = float64(baselineLatency) / compared
+	report.CandidateAvgLatencyMilliseconds = float64(candidateLatency) / compared
+	report.AvgLatencyDeltaMilliseconds = report.CandidateAvgLatencyMilliseconds - report.BaselineAvgLatencyMilliseconds
+	report.BaselineP99LatencyMilliseconds = percentile(baselineLatencies, 0.99)
+	report.CandidateP99LatencyMilliseconds = percentile(candidateLatencies, 0.99)
+	return report
+}
+
+func percentile(values []int64, p float64) int64 {
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	index := int(float64(len(values))*p+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(values) {
+		index = len(values) - 1
+	}
+	return values[index]
+}
+
+// ExtractOutput returns the generated text of a completion, chat completion or Responses API response,
+// so that fields which always differ, like the id and the creation time, are not compared.
+// Other responses are compared as a whole.
+func ExtractOutput(body []byte) string {
+	outputs, ok := handlers.OutputTexts(body)
+	if !ok || len(outputs) == 0 {
+		return strings.TrimSpace(string(body))
+	}
+	return strings.Join(outputs, "\n")
+}
@@ -0,0 +1,210 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrency
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+)
+
+const (
+	// DefaultMaxQueueSize is the number of requests waiting for a slot when the ModelRoute does not set it.
+	DefaultMaxQueueSize = 100
+	// DefaultMaxQueueWait is the time a request waits for a slot when the ModelRoute does not set it.
+	DefaultMaxQueueWait = 30 * time.Second
+)
+
+var (
+	// ErrQueueFull is returned when the request can not be queued.
+	ErrQueueFull = errors.New("concurrency limit exceeded and the queue is full")
+	// ErrQueueTimeout is returned when the request waited for a slot longer than the max queue wait.
+	ErrQueueTimeout = errors.New("concurrency limit exceeded and the queue wait expired")
+)
+
+// Limiter bounds the concurrent requests of a model. The requests exceeding the limit wait for a slot in a
+// FIFO queue, a released slot is handed over to the first waiting request.
+type Limiter struct {
+	model string
+
+	mu        sync.Mutex
+	limit     int
+	queueSize int
+	maxWait   time.Duration
+	fallback  string
+	inflight  int
+	// waiters are the queued requests, each waiting for its channel to be closed.
+	waiters *list.List
+}
+
+func newLimiter(model string) *Limiter {
+	return &Limiter{model: model, waiters: list.New()}
+}
+
+// update applies the limit of the ModelRoute, a nil limit lets all the requests through.
+func (l *Limiter) update(config *networkingv1alpha1.ConcurrencyLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit, l.queueSize, l.maxWait, l.fallback = 0, DefaultMaxQueueSize, DefaultMaxQueueWait, ""
+	if config != nil {
+		l.limit = int(config.MaxConcurrentRequests)
+		if config.MaxQueueSize != nil {
+			l.queueSize = int(*config.MaxQueueSize)
+		}
+		if config.MaxQueueWait != nil && config.MaxQueueWait.Duration > 0 {
+			l.maxWait = config.MaxQueueWait.Duration
+		}
+		l.fallback = config.FallbackModel
+	}
+	// A raised or removed limit admits the waiting requests
+	for l.waiters.Len() > 0 && (l.limit == 0 || l.inflight < l.limit) {
+		l.grant(l.waiters.Front())
+	}
+}
+
+// Acquire reserves a slot for a request, waiting for one in the queue if the model is at its limit.
+// The returned release function must be called once the request is done.
+func (l *Limiter) Acquire(ctx context.Context) (func(), error) {
+	l.mu.Lock()
+	if l.limit == 0 || (l.inflight < l.limit && l.waiters.Len() == 0) {
+		l.inflight++
+		l.mu.Unlock()
+		return l.release, nil
+	}
+	if l.waiters.Len() >= l.queueSize {
+		l.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+	ready := make(chan struct{})
+	element := l.waiters.PushBack(ready)
+	maxWait := l.maxWait
+	l.mu.Unlock()
+
+	metrics.DefaultMetrics.IncConcurrencyQueueSize(l.model)
+	start := time.Now()
+	defer func() {
+		metrics.DefaultMetrics.DecConcurrencyQueueSize(l.model)
+		metrics.DefaultMetrics.RecordConcurrencyQueueDuration(l.model, time.Since(start))
+	}()
+
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+	var err error
+	select {
+	case <-ready:
+		return l.release, nil
+	case <-timer.C:
+		err = ErrQueueTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-ready:
+		// The slot was handed over while giving up
+		if err == ErrQueueTimeout {
+			return l.release, nil
+		}
+		l.releaseLocked()
+	default:
+		l.waiters.Remove(element)
+	}
+	return nil, err
+}
+
+// Fallback returns the model the requests spill over to, empty when they are rejected.
+func (l *Limiter) Fallback() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.fallback
+}
+
+func (l *Limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.releaseLocked()
+}
+
+func (l *Limiter) releaseLocked() {
+	l.inflight--
+	// The slot goes to the first waiting request, unless the limit has been lowered below the requests in flight
+	if l.waiters.Len() > 0 && (l.limit == 0 || l.inflight < l.limit) {
+		l.grant(l.waiters.Front())
+	}
+}
+
+// grant hands a slot over to a waiting request, it is called with the lock held.
+func (l *Limiter) grant(element *list.Element) {
+	l.waiters.Remove(element)
+	l.inflight++
+	close(element.Value.(chan struct{}))
+}
+
+// Limiters holds the concurrency limiters of the models.
+type Limiters struct {
+	mu       sync.RWMutex
+	limiters map[string]*Limiter
+}
+
+// NewLimiters creates the limiters of the models, without any limit.
+func NewLimiters() *Limiters {
+	return &Limiters{
+		limiters: make(map[string]*Limiter),
+	}
+}
+
+// AddOrUpdateLimiter sets the concurrency limit of a model, a nil limit removes it. The requests in flight
+// keep their slots, they count against the new limit.
+func (l *Limiters) AddOrUpdateLimiter(model string, config *networkingv1alpha1.ConcurrencyLimit) {
+	if config == nil {
+		l.DeleteLimiter(model)
+		return
+	}
+	l.mu.Lock()
+	limiter, ok := l.limiters[model]
+	if !ok {
+		limiter = newLimiter(model)
+		l.limiters[model] = limiter
+	}
+	l.mu.Unlock()
+	limiter.update(config)
+}
+
+// DeleteLimiter removes the concurrency limit of a model, the waiting requests are admitted.
+func (l *Limiters) DeleteLimiter(model string) {
+	l.mu.Lock()
+	limiter, ok := l.limiters[model]
+	delete(l.limiters, model)
+	l.mu.Unlock()
+	if ok {
+		limiter.update(nil)
+	}
+}
+
+// Get returns the limiter of the model, nil when it has no concurrency limit.
+func (l *Limiters) Get(model string) *Limiter {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.limiters[model]
+}
@@ -0,0 +1,124 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+func newConfig(limit, queueSize uint32, maxWait time.Duration) *networkingv1alpha1.ConcurrencyLimit {
+	return &networkingv1alpha1.ConcurrencyLimit{
+		MaxConcurrentRequests: limit,
+		MaxQueueSize:          &queueSize,
+		MaxQueueWait:          &metav1.Duration{Duration: maxWait},
+		FallbackModel:         "llama-small",
+	}
+}
+
+func TestLimiterQueue(t *testing.T) {
+	limiters := NewLimiters()
+	assert.Nil(t, limiters.Get("llama"))
+	limiters.AddOrUpdateLimiter("llama", newConfig(1, 1, time.Minute))
+	limiter := limiters.Get("llama")
+	require.NotNil(t, limiter)
+	assert.Equal(t, "llama-small", limiter.Fallback())
+
+	release, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+
+	acquired := make(chan func())
+	go func() {
+		queued, err := limiter.Acquire(context.Background())
+		assert.NoError(t, err)
+		acquired <- queued
+	}()
+	assert.Eventually(t, func() bool {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return limiter.waiters.Len() == 1
+	}, time.Second, time.Millisecond)
+
+	_, err = limiter.Acquire(context.Background())
+	assert.ErrorIs(t, err, ErrQueueFull)
+
+	// The released slot is handed over to the queued request
+	release()
+	queued := <-acquired
+	limiter.mu.Lock()
+	assert.Equal(t, 1, limiter.inflight)
+	limiter.mu.Unlock()
+	queued()
+	limiter.mu.Lock()
+	assert.Equal(t, 0, limiter.inflight)
+	limiter.mu.Unlock()
+}
+
+func TestLimiterQueueTimeout(t *testing.T) {
+	limiters := NewLimiters()
+	limiters.AddOrUpdateLimiter("llama", newConfig(1, 1, 10*time.Millisecond))
+	limiter := limiters.Get("llama")
+
+	release, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+	defer release()
+
+	_, err = limiter.Acquire(context.Background())
+	assert.ErrorIs(t, err, ErrQueueTimeout)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = limiter.Acquire(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, limiter.waiters.Len())
+}
+
+func TestLimiterUpdate(t *testing.T) {
+	limiters := NewLimiters()
+	limiters.AddOrUpdateLimiter("llama", newConfig(1, 10, time.Minute))
+	limiter := limiters.Get("llama")
+
+	release, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+	defer release()
+
+	acquired := make(chan error)
+	go func() {
+		_, err := limiter.Acquire(context.Background())
+		acquired <- err
+	}()
+	assert.Eventually(t, func() bool {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return limiter.waiters.Len() == 1
+	}, time.Second, time.Millisecond)
+
+	// Raising the limit admits the queued request
+	limiters.AddOrUpdateLimiter("llama", newConfig(2, 10, time.Minute))
+	assert.NoError(t, <-acquired)
+
+	// Removing the limit lets all the requests through
+	limiters.AddOrUpdateLimiter("llama", nil)
+	assert.Nil(t, limiters.Get("llama"))
+}
@@ -0,0 +1,197 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fault injects faults in the requests proxied by the router, so that the resilience of the clients
+// and of their retry policies can be tested without touching the model servers.
+package fault
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Rule injects faults in the requests of a model or of a ModelRoute. The requests matched by no rule are
+// proxied as usual.
+type Rule struct {
+	// Model is the model of the requests, as requested by the clients. Empty matches all the models.
+	Model string `json:"model,omitempty"`
+	// ModelRoute is the namespace/name of the ModelRoute of the requests. Empty matches all the routes.
+	ModelRoute string `json:"modelRoute,omitempty"`
+	// Delay delays the requests before they are proxied.
+	Delay *Delay `json:"delay,omitempty"`
+	// Abort answers the requests with an error instead of proxying them.
+	Abort *Abort `json:"abort,omitempty"`
+	// Truncate cuts the streamed responses short.
+	Truncate *Truncate `json:"truncate,omitempty"`
+}
+
+// Delay delays a percentage of the requests.
+type Delay struct {
+	Percentage float64  `json:"percentage"`
+	Duration   Duration `json:"duration"`
+}
+
+// Abort answers a percentage of the requests with the status code.
+type Abort struct {
+	Percentage float64 `json:"percentage"`
+	StatusCode int     `json:"statusCode"`
+}
+
+// Truncate closes the connection of a percentage of the streaming requests once AfterEvents server-sent
+// events of the response have been sent, without the end of the stream.
+type Truncate struct {
+	Percentage  float64 `json:"percentage"`
+	AfterEvents int     `json:"afterEvents"`
+}
+
+// Duration is a time.Duration written as a string such as "500ms" in JSON.
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"500ms\": %w", err)
+	}
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = duration
+	return nil
+}
+
+// Faults are the faults injected in a request.
+type Faults struct {
+	// Delay is how long the request is delayed, zero when it is not.
+	Delay time.Duration
+	// AbortStatusCode is the status code the request is answered with, zero when it is proxied.
+	AbortStatusCode int
+	// TruncateAfterEvents is the number of events sent before a streamed response is cut short, negative
+	// when it is not.
+	TruncateAfterEvents int
+}
+
+// Injector holds the fault injection rules set through the admin API.
+type Injector struct {
+	mu    sync.RWMutex
+	rules []Rule
+	// random returns a number in [0, 100)
+	random func() float64
+}
+
+// NewInjector creates an Injector without rules.
+func NewInjector() *Injector {
+	return &Injector{
+		random: func() float64 { return rand.Float64() * 100 },
+	}
+}
+
+// Rules returns the rules in the order they are matched.
+func (i *Injector) Rules() []Rule {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return slices.Clone(i.rules)
+}
+
+// SetRules replaces the rules, an empty list disables the fault injection.
+func (i *Injector) SetRules(rules []Rule) error {
+	for idx, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", idx, err)
+		}
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rules = slices.Clone(rules)
+	return nil
+}
+
+// Match returns the faults to inject in a request of the model through the ModelRoute. Only the first rule
+// matching the request applies, and each of its faults is injected in its own percentage of the requests.
+func (i *Injector) Match(model, modelRoute string) (Faults, bool) {
+	faults := Faults{TruncateAfterEvents: -1}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for _, rule := range i.rules {
+		if (rule.Model != "" && rule.Model != model) || (rule.ModelRoute != "" && rule.ModelRoute != modelRoute) {
+			continue
+		}
+		injected := false
+		if rule.Delay != nil && i.random() < rule.Delay.Percentage {
+			faults.Delay = rule.Delay.Duration.Duration
+			injected = true
+		}
+		if rule.Abort != nil && i.random() < rule.Abort.Percentage {
+			faults.AbortStatusCode = rule.Abort.StatusCode
+			injected = true
+		}
+		if rule.Truncate != nil && i.random() < rule.Truncate.Percentage {
+			faults.TruncateAfterEvents = rule.Truncate.AfterEvents
+			injected = true
+		}
+		return faults, injected
+	}
+	return faults, false
+}
+
+func (r *Rule) validate() error {
+	if r.Delay == nil && r.Abort == nil && r.Truncate == nil {
+		return fmt.Errorf("one of delay, abort or truncate must be set")
+	}
+	if r.Delay != nil {
+		if err := validatePercentage(r.Delay.Percentage); err != nil {
+			return fmt.Errorf("delay: %w", err)
+		}
+		if r.Delay.Duration.Duration <= 0 {
+			return fmt.Errorf("delay: duration must be positive")
+		}
+	}
+	if r.Abort != nil {
+		if err := validatePercentage(r.Abort.Percentage); err != nil {
+			return fmt.Errorf("abort: %w", err)
+		}
+		if r.Abort.StatusCode < 400 || r.Abort.StatusCode > 599 || http.StatusText(r.Abort.StatusCode) == "" {
+			return fmt.Errorf("abort: statusCode must be an HTTP error status code, got %d", r.Abort.StatusCode)
+		}
+	}
+	if r.Truncate != nil {
+		if err := validatePercentage(r.Truncate.Percentage); err != nil {
+			return fmt.Errorf("truncate: %w", err)
+		}
+		if r.Truncate.AfterEvents < 0 {
+			return fmt.Errorf("truncate: afterEvents must not be negative")
+		}
+	}
+	return nil
+}
+
+func validatePercentage(percentage float64) error {
+	if percentage < 0 || percentage > 100 {
+		return fmt.Errorf("percentage must be between 0 and 100, got %v", percentage)
+	}
+	return nil
+}
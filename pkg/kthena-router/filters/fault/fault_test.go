@@ -0,0 +1,134 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fault
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectorMatch(t *testing.T) {
+	injector := NewInjector()
+	injector.random = func() float64 { return 50 }
+	require.NoError(t, injector.SetRules([]Rule{
+		{Model: "llama", Abort: &Abort{Percentage: 100, StatusCode: http.StatusServiceUnavailable}},
+		{ModelRoute: "default/qwen", Delay: &Delay{Percentage: 60, Duration: Duration{time.Second}}, Truncate: &Truncate{Percentage: 40, AfterEvents: 2}},
+		{Abort: &Abort{Percentage: 10, StatusCode: http.StatusInternalServerError}},
+	}))
+
+	faults, ok := injector.Match("llama", "default/llama")
+	assert.True(t, ok)
+	assert.Equal(t, Faults{AbortStatusCode: http.StatusServiceUnavailable, TruncateAfterEvents: -1}, faults)
+
+	// Only the first matching rule applies, each fault in its own percentage of the requests
+	faults, ok = injector.Match("qwen", "default/qwen")
+	assert.True(t, ok)
+	assert.Equal(t, Faults{Delay: time.Second, TruncateAfterEvents: -1}, faults)
+
+	faults, ok = injector.Match("deepseek", "default/deepseek")
+	assert.False(t, ok)
+	assert.Equal(t, Faults{TruncateAfterEvents: -1}, faults)
+
+	require.NoError(t, injector.SetRules(nil))
+	_, ok = injector.Match("llama", "default/llama")
+	assert.False(t, ok)
+}
+
+func TestSetRulesValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+	}{
+		{name: "no fault", rule: Rule{Model: "llama"}},
+		{name: "percentage above 100", rule: Rule{Abort: &Abort{Percentage: 150, StatusCode: 503}}},
+		{name: "success status code", rule: Rule{Abort: &Abort{Percentage: 10, StatusCode: 200}}},
+		{name: "no delay", rule: Rule{Delay: &Delay{Percentage: 10}}},
+		{name: "negative events", rule: Rule{Truncate: &Truncate{Percentage: 10, AfterEvents: -1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injector := NewInjector()
+			assert.Error(t, injector.SetRules([]Rule{tt.rule}))
+			assert.Empty(t, injector.Rules())
+		})
+	}
+}
+
+func TestRuleJSON(t *testing.T) {
+	var rule Rule
+	require.NoError(t, json.Unmarshal([]byte(`{"model": "llama", "delay": {"percentage": 5, "duration": "250ms"}}`), &rule))
+	assert.Equal(t, 250*time.Millisecond, rule.Delay.Duration.Duration)
+
+	data, err := json.Marshal(rule)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"model": "llama", "delay": {"percentage": 5, "duration": "250ms"}}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"delay": {"percentage": 5, "duration": 250}}`), &rule))
+}
+
+func TestTruncateWriter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	truncated := make(chan struct{})
+	engine := gin.New()
+	engine.GET("/stream", func(c *gin.Context) {
+		writer := NewTruncateWriter(c.Writer, 2, func() { close(truncated) })
+		c.Writer = writer
+		for i := 0; i < 4; i++ {
+			_, _ = c.Writer.WriteString("data: {\"index\": " + strconv.Itoa(i) + "}\n")
+			_, _ = c.Writer.WriteString("\n")
+		}
+		_, _ = c.Writer.WriteString("data: [DONE]\n\n")
+		assert.True(t, writer.Truncated())
+		writer.CloseConnection()
+	})
+	server := httptest.NewServer(engine)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var events []string
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if strings.HasPrefix(line, "data:") {
+			events = append(events, strings.TrimSpace(line))
+		}
+		if err != nil {
+			// The connection is closed before the end of the chunked response
+			assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+			break
+		}
+	}
+	assert.Equal(t, []string{`data: {"index": 0}`, `data: {"index": 1}`}, events)
+	select {
+	case <-truncated:
+	default:
+		t.Fatal("the truncation was not reported")
+	}
+}
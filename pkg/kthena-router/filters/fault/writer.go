@@ -0,0 +1,100 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fault
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TruncateWriter wraps the gin ResponseWriter of a streamed response and drops everything written once
+// the given number of server-sent events has been sent.
+type TruncateWriter struct {
+	gin.ResponseWriter
+	remaining int
+	truncated bool
+	// onTruncate is called once the response has been truncated, to stop reading the upstream response.
+	onTruncate func()
+}
+
+// NewTruncateWriter wraps w, afterEvents events are sent before the response is truncated.
+func NewTruncateWriter(w gin.ResponseWriter, afterEvents int, onTruncate func()) *TruncateWriter {
+	return &TruncateWriter{ResponseWriter: w, remaining: afterEvents, onTruncate: onTruncate}
+}
+
+func (w *TruncateWriter) Write(data []byte) (int, error) {
+	if w.truncated {
+		return len(data), nil
+	}
+	if bytes.HasPrefix(data, []byte("data:")) {
+		if w.remaining == 0 {
+			w.truncate()
+			return len(data), nil
+		}
+		w.remaining--
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *TruncateWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *TruncateWriter) truncate() {
+	w.truncated = true
+	w.ResponseWriter.Flush()
+	if w.onTruncate != nil {
+		w.onTruncate()
+	}
+}
+
+// Truncated reports whether the response has been truncated.
+func (w *TruncateWriter) Truncated() bool {
+	return w.truncated
+}
+
+// CloseConnection closes the connection of the truncated response, so that the client sees the stream
+// end abruptly instead of completing. It does nothing when the connection cannot be hijacked, as with
+// HTTP/2, the response then just ends early.
+func (w *TruncateWriter) CloseConnection() {
+	if !w.truncated {
+		return
+	}
+	// gin panics when hijacking a connection which does not support it, the connection of the server is hijacked
+	var rw http.ResponseWriter = w.ResponseWriter
+	for {
+		unwrapper, ok := rw.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		rw = unwrapper.Unwrap()
+	}
+	if _, ok := rw.(gin.ResponseWriter); ok {
+		return
+	}
+	hijacker, ok := rw.(http.Hijacker)
+	if !ok {
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	_ = conn.Close()
+}
@@ -0,0 +1,198 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guardrail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+// defaultClassifierTimeout bounds the classifier requests when the filter sets no timeout.
+const defaultClassifierTimeout = time.Second
+
+// maxClassifierResponseBytes bounds the classifier response read by the router.
+const maxClassifierResponseBytes = 64 << 10
+
+// Content is the text checked by a filter.
+type Content struct {
+	Model string
+	Stage networkingv1alpha1.GuardrailStage
+	Text  string
+}
+
+// Verdict is the outcome of a filter on some content.
+type Verdict struct {
+	Flagged bool
+	// Reason explains why the content is flagged.
+	Reason string
+}
+
+// Filter flags unsafe content. An error means the filter could not decide.
+type Filter interface {
+	Check(ctx context.Context, content Content) (Verdict, error)
+}
+
+// NewFilter builds the filter configured by a GuardrailFilter of a ModelRoute.
+func NewFilter(spec *networkingv1alpha1.GuardrailFilter, client *http.Client) (Filter, error) {
+	switch {
+	case spec.Keywords != nil:
+		return newKeywordFilter(spec.Keywords)
+	case spec.Regex != nil:
+		return newRegexFilter(spec.Regex)
+	case spec.HTTP != nil:
+		return newHTTPFilter(spec.HTTP, client)
+	default:
+		return nil, fmt.Errorf("one of keywords, regex and http must be set")
+	}
+}
+
+type keywordFilter struct {
+	keywords      []string
+	caseSensitive bool
+}
+
+func newKeywordFilter(spec *networkingv1alpha1.KeywordGuardrail) (Filter, error) {
+	f := &keywordFilter{caseSensitive: spec.CaseSensitive}
+	for _, keyword := range spec.Keywords {
+		if keyword == "" {
+			return nil, fmt.Errorf("keywords cannot be empty")
+		}
+		if !f.caseSensitive {
+			keyword = strings.ToLower(keyword)
+		}
+		f.keywords = append(f.keywords, keyword)
+	}
+	if len(f.keywords) == 0 {
+		return nil, fmt.Errorf("at least one keyword must be set")
+	}
+	return f, nil
+}
+
+func (f *keywordFilter) Check(_ context.Context, content Content) (Verdict, error) {
+	text := content.Text
+	if !f.caseSensitive {
+		text = strings.ToLower(text)
+	}
+	for _, keyword := range f.keywords {
+		if strings.Contains(text, keyword) {
+			return Verdict{Flagged: true, Reason: fmt.Sprintf("contains keyword %q", keyword)}, nil
+		}
+	}
+	return Verdict{}, nil
+}
+
+type regexFilter struct {
+	patterns []*regexp.Regexp
+}
+
+func newRegexFilter(spec *networkingv1alpha1.RegexGuardrail) (Filter, error) {
+	f := &regexFilter{}
+	for _, pattern := range spec.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		f.patterns = append(f.patterns, re)
+	}
+	if len(f.patterns) == 0 {
+		return nil, fmt.Errorf("at least one pattern must be set")
+	}
+	return f, nil
+}
+
+func (f *regexFilter) Check(_ context.Context, content Content) (Verdict, error) {
+	for _, re := range f.patterns {
+		if re.MatchString(content.Text) {
+			return Verdict{Flagged: true, Reason: fmt.Sprintf("matches pattern %q", re.String())}, nil
+		}
+	}
+	return Verdict{}, nil
+}
+
+// classifierRequest is the body sent to the external classifiers.
+type classifierRequest struct {
+	Model   string `json:"model"`
+	Stage   string `json:"stage"`
+	Content string `json:"content"`
+}
+
+// classifierResponse is the body expected from the external classifiers.
+type classifierResponse struct {
+	Flagged bool   `json:"flagged"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+type httpFilter struct {
+	url     string
+	timeout time.Duration
+	client  *http.Client
+}
+
+func newHTTPFilter(spec *networkingv1alpha1.HTTPGuardrail, client *http.Client) (Filter, error) {
+	u, err := url.Parse(spec.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("url %q must be an absolute http or https url", spec.URL)
+	}
+	f := &httpFilter{url: spec.URL, timeout: defaultClassifierTimeout, client: client}
+	if spec.Timeout != nil {
+		if spec.Timeout.Duration <= 0 {
+			return nil, fmt.Errorf("timeout must be positive")
+		}
+		f.timeout = spec.Timeout.Duration
+	}
+	return f, nil
+}
+
+func (f *httpFilter) Check(ctx context.Context, content Content) (Verdict, error) {
+	body, err := json.Marshal(classifierRequest{Model: content.Model, Stage: string(content.Stage), Content: content.Text})
+	if err != nil {
+		return Verdict{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url, bytes.NewReader(body))
+	if err != nil {
+		return Verdict{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return Verdict{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Verdict{}, fmt.Errorf("classifier responded with status %d", resp.StatusCode)
+	}
+	var result classifierResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxClassifierResponseBytes)).Decode(&result); err != nil {
+		return Verdict{}, fmt.Errorf("invalid classifier response: %w", err)
+	}
+	return Verdict{Flagged: result.Flagged, Reason: result.Reason}, nil
+}
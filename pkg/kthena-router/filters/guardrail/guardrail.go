@@ -0,0 +1,210 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guardrail
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+)
+
+// Verdict values of the guardrail metrics.
+const (
+	verdictPassed   = "passed"
+	verdictFlagged  = "flagged"
+	verdictRejected = "rejected"
+	verdictError    = "error"
+)
+
+// Result is the outcome of the guardrails of a route on some content.
+type Result struct {
+	// Rejected is the name of the filter rejecting the content, empty if the content is accepted.
+	Rejected string
+	// Reason explains why the content is rejected.
+	Reason string
+	// Flagged are the names of the annotating filters which flagged the content.
+	Flagged []string
+}
+
+// UnavailableError is returned when a filter failing closed could not check the content.
+type UnavailableError struct {
+	Filter string
+	Err    error
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("guardrail %s is unavailable: %v", e.Filter, e.Err)
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// configuredFilter is a filter with the settings of its GuardrailFilter.
+type configuredFilter struct {
+	name     string
+	stages   map[networkingv1alpha1.GuardrailStage]bool
+	action   networkingv1alpha1.GuardrailAction
+	failOpen bool
+	filter   Filter
+}
+
+// routeFilters are the filters built for a revision of a ModelRoute.
+type routeFilters struct {
+	resourceVersion string
+	filters         []*configuredFilter
+	err             error
+}
+
+// Guardrails runs the filters configured by the ModelRoutes on the content of their requests and responses.
+// The filters of a route are built on first use and rebuilt whenever the route changes.
+type Guardrails struct {
+	client *http.Client
+
+	mu     sync.RWMutex
+	routes map[string]*routeFilters
+}
+
+// New creates the guardrails, client is used for the external classifiers.
+func New(client *http.Client) *Guardrails {
+	return &Guardrails{
+		client: client,
+		routes: make(map[string]*routeFilters),
+	}
+}
+
+// Enabled reports whether the route has filters running at the stage.
+func (g *Guardrails) Enabled(route *networkingv1alpha1.ModelRoute, stage networkingv1alpha1.GuardrailStage) bool {
+	if route == nil || route.Spec.Guardrails == nil {
+		return false
+	}
+	rf := g.filtersOf(route)
+	if rf.err != nil {
+		// The content is rejected by Check
+		return true
+	}
+	for _, f := range rf.filters {
+		if f.stages[stage] {
+			return true
+		}
+	}
+	return false
+}
+
+// Check runs the filters of the route at the stage, in order, until one of them rejects the content.
+// A route whose guardrails are invalid rejects all its content, so that a typo never disables them.
+func (g *Guardrails) Check(ctx context.Context, route *networkingv1alpha1.ModelRoute, model string, stage networkingv1alpha1.GuardrailStage, text string) (Result, error) {
+	var result Result
+	if route == nil || route.Spec.Guardrails == nil {
+		return result, nil
+	}
+	rf := g.filtersOf(route)
+	if rf.err != nil {
+		return result, &UnavailableError{Filter: route.Namespace + "/" + route.Name, Err: rf.err}
+	}
+	content := Content{Model: model, Stage: stage, Text: text}
+	for _, f := range rf.filters {
+		if !f.stages[stage] {
+			continue
+		}
+		verdict, err := f.filter.Check(ctx, content)
+		if err != nil {
+			metrics.DefaultMetrics.RecordGuardrailVerdict(model, f.name, string(stage), verdictError)
+			if f.failOpen {
+				klog.Warningf("guardrail %s failed open on the %s of model %s: %v", f.name, stage, model, err)
+				continue
+			}
+			return result, &UnavailableError{Filter: f.name, Err: err}
+		}
+		switch {
+		case !verdict.Flagged:
+			metrics.DefaultMetrics.RecordGuardrailVerdict(model, f.name, string(stage), verdictPassed)
+		case f.action == networkingv1alpha1.GuardrailActionAnnotate:
+			metrics.DefaultMetrics.RecordGuardrailVerdict(model, f.name, string(stage), verdictFlagged)
+			result.Flagged = append(result.Flagged, f.name)
+		default:
+			metrics.DefaultMetrics.RecordGuardrailVerdict(model, f.name, string(stage), verdictRejected)
+			result.Rejected = f.name
+			result.Reason = verdict.Reason
+			return result, nil
+		}
+	}
+	return result, nil
+}
+
+// Prune forgets the filters of the routes which no longer exist.
+func (g *Guardrails) Prune(exists func(key string) bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for key := range g.routes {
+		if !exists(key) {
+			delete(g.routes, key)
+		}
+	}
+}
+
+func (g *Guardrails) filtersOf(route *networkingv1alpha1.ModelRoute) *routeFilters {
+	key := route.Namespace + "/" + route.Name
+	g.mu.RLock()
+	rf, ok := g.routes[key]
+	g.mu.RUnlock()
+	if ok && rf.resourceVersion == route.ResourceVersion {
+		return rf
+	}
+
+	rf = &routeFilters{resourceVersion: route.ResourceVersion}
+	rf.filters, rf.err = g.build(route.Spec.Guardrails)
+	if rf.err != nil {
+		klog.Errorf("invalid guardrails of model route %s, its requests are rejected: %v", key, rf.err)
+	}
+	g.mu.Lock()
+	g.routes[key] = rf
+	g.mu.Unlock()
+	return rf
+}
+
+func (g *Guardrails) build(spec *networkingv1alpha1.Guardrails) ([]*configuredFilter, error) {
+	filters := make([]*configuredFilter, 0, len(spec.Filters))
+	for i := range spec.Filters {
+		filterSpec := &spec.Filters[i]
+		filter, err := NewFilter(filterSpec, g.client)
+		if err != nil {
+			return nil, fmt.Errorf("filter %s: %w", filterSpec.Name, err)
+		}
+		f := &configuredFilter{
+			name:     filterSpec.Name,
+			stages:   make(map[networkingv1alpha1.GuardrailStage]bool),
+			action:   filterSpec.Action,
+			failOpen: filterSpec.HTTP != nil && filterSpec.HTTP.FailOpen,
+			filter:   filter,
+		}
+		if len(filterSpec.Stages) == 0 {
+			f.stages[networkingv1alpha1.GuardrailStageRequest] = true
+		}
+		for _, stage := range filterSpec.Stages {
+			f.stages[stage] = true
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
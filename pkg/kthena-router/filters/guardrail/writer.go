@@ -0,0 +1,92 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guardrail
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BufferWriter wraps the gin ResponseWriter and holds back the response, so that its content
+// can be checked before anything is sent to the client.
+type BufferWriter struct {
+	gin.ResponseWriter
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+// NewBufferWriter wraps w until Release is called.
+func NewBufferWriter(w gin.ResponseWriter) *BufferWriter {
+	return &BufferWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (w *BufferWriter) WriteHeader(code int) {
+	if code > 0 && !w.wroteHeader {
+		w.status = code
+	}
+}
+
+func (w *BufferWriter) WriteHeaderNow() {
+	w.wroteHeader = true
+}
+
+func (w *BufferWriter) Write(data []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(data)
+}
+
+func (w *BufferWriter) WriteString(s string) (int, error) {
+	w.wroteHeader = true
+	return w.body.WriteString(s)
+}
+
+// Flush is a no-op, nothing is sent before Release.
+func (w *BufferWriter) Flush() {}
+
+func (w *BufferWriter) Status() int {
+	return w.status
+}
+
+func (w *BufferWriter) Size() int {
+	if !w.wroteHeader {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *BufferWriter) Written() bool {
+	return w.wroteHeader
+}
+
+// Body returns the response held back.
+func (w *BufferWriter) Body() []byte {
+	return w.body.Bytes()
+}
+
+// Release sends the response held back to the client.
+func (w *BufferWriter) Release() error {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.body.Len() == 0 {
+		w.ResponseWriter.WriteHeaderNow()
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.body.Bytes())
+	return err
+}
@@ -0,0 +1,50 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guardrail
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferWriter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	writer := NewBufferWriter(c.Writer)
+	c.Writer = writer
+
+	c.Header("Content-Type", "application/json")
+	c.Status(http.StatusCreated)
+	_, err := c.Writer.Write([]byte(`{"id":"1"}`))
+	require.NoError(t, err)
+	c.Writer.Flush()
+	assert.True(t, c.Writer.Written())
+	assert.Equal(t, http.StatusCreated, c.Writer.Status())
+	assert.Empty(t, w.Body.String(), "nothing is sent before the response is released")
+	assert.Equal(t, `{"id":"1"}`, string(writer.Body()))
+
+	require.NoError(t, writer.Release())
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"id":"1"}`, w.Body.String())
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+}
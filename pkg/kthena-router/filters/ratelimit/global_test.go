@@ -211,3 +211,40 @@ func TestTokenRateLimiter_RedisConnectionFailure(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to connect to redis")
 }
+
+func TestTokenRateLimiter_UpdateKeepsState(t *testing.T) {
+	mr, redisConfig := setupMiniRedis(t)
+	defer mr.Close()
+
+	rl := NewTokenRateLimiter()
+	model := "test-model"
+	tokens := uint32(3)
+	streams := uint32(1)
+	spec := &networkingv1alpha1.RateLimit{
+		InputTokensPerUnit:   &tokens,
+		MaxConcurrentStreams: &streams,
+		Unit:                 networkingv1alpha1.Minute,
+		Global:               &networkingv1alpha1.GlobalRateLimit{Redis: redisConfig},
+	}
+	require.NoError(t, rl.AddOrUpdateLimiter(model, spec))
+	require.NoError(t, rl.RateLimit(model, "hello world"))
+	release, err := rl.AcquireStream(model)
+	require.NoError(t, err)
+	defer release()
+
+	// An update of the route with the same rate limit keeps the limiters and their state
+	unchanged := spec.DeepCopy()
+	require.NoError(t, rl.AddOrUpdateLimiter(model, unchanged))
+	assert.Error(t, rl.RateLimit(model, "hello world"))
+	_, err = rl.AcquireStream(model)
+	assert.Error(t, err)
+
+	// A changed rate limit rebuilds them, and the limits it no longer sets are removed
+	changed := spec.DeepCopy()
+	changed.InputTokensPerUnit = nil
+	changed.MaxConcurrentStreams = nil
+	require.NoError(t, rl.AddOrUpdateLimiter(model, changed))
+	assert.NoError(t, rl.RateLimit(model, "hello world"))
+	_, err = rl.AcquireStream(model)
+	assert.NoError(t, err)
+}
@@ -22,8 +22,9 @@ import (
 	"sync"
 	"time"
 
-	"github.com/go-redis/redis/v8"
+	"github.com/redis/go-redis/v9"
 	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/klog/v2"
 
 	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
@@ -64,6 +65,9 @@ type TokenRateLimiter struct {
 	// Unified rate limiters using Limiter interface
 	inputLimiter  map[string]Limiter
 	outputLimiter map[string]Limiter
+	streamLimiter map[string]StreamLimiter
+	// specs are the rate limits the limiters of each model were built from.
+	specs map[string]*networkingv1alpha1.RateLimit
 
 	// Redis client for global rate limiting
 	redisClient *redis.Client
@@ -93,6 +97,8 @@ func NewTokenRateLimiter() *TokenRateLimiter {
 	return &TokenRateLimiter{
 		inputLimiter:  make(map[string]Limiter),
 		outputLimiter: make(map[string]Limiter),
+		streamLimiter: make(map[string]StreamLimiter),
+		specs:         make(map[string]*networkingv1alpha1.RateLimit),
 		tokenizer:     tokenizer.NewSimpleEstimateTokenizer(),
 	}
 }
@@ -136,11 +142,37 @@ func (r *TokenRateLimiter) RecordOutputTokens(model string, tokenCount int) {
 	}
 }
 
-// AddOrUpdateLimiter adds or updates rate limiter for a model
+// AcquireStream reserves a concurrent stream slot for the model. The returned release
+// function must be called once the stream has completed.
+func (r *TokenRateLimiter) AcquireStream(model string) (func(), error) {
+	r.mutex.RLock()
+	streamLimiter, exists := r.streamLimiter[model]
+	r.mutex.RUnlock()
+
+	if !exists {
+		return func() {}, nil
+	}
+	if !streamLimiter.Acquire() {
+		return nil, &ConcurrentStreamLimitExceededError{
+			Limit:      streamLimiter.Limit(),
+			RetryAfter: DefaultStreamRetryAfter,
+		}
+	}
+	return streamLimiter.Release, nil
+}
+
+// AddOrUpdateLimiter adds or updates rate limiter for a model. The limiters are kept as they are when the
+// rate limit is unchanged, so that the updates of the ModelRoute which do not touch it keep their state.
 func (r *TokenRateLimiter) AddOrUpdateLimiter(model string, ratelimit *networkingv1alpha1.RateLimit) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	if spec, exists := r.specs[model]; exists && equality.Semantic.DeepEqual(spec, ratelimit) {
+		return nil
+	}
+	delete(r.inputLimiter, model)
+	delete(r.outputLimiter, model)
+
 	// Determine if we should use global or local rate limiting
 	useGlobal := ratelimit.Global != nil && ratelimit.Global.Redis != nil
 
@@ -181,6 +213,10 @@ func (r *TokenRateLimiter) AddOrUpdateLimiter(model string, ratelimit *networkin
 				ratelimit.Unit,
 			)
 		}
+
+		if ratelimit.MaxConcurrentStreams != nil {
+			r.setStreamLimit(model, true, *ratelimit.MaxConcurrentStreams)
+		}
 	} else {
 		// Create local rate limiters
 		duration := getTimeUnitDuration(ratelimit.Unit)
@@ -198,11 +234,57 @@ func (r *TokenRateLimiter) AddOrUpdateLimiter(model string, ratelimit *networkin
 				int(*ratelimit.OutputTokensPerUnit),
 			)
 		}
+
+		if ratelimit.MaxConcurrentStreams != nil {
+			r.setStreamLimit(model, false, *ratelimit.MaxConcurrentStreams)
+		}
+	}
+
+	if ratelimit.MaxConcurrentStreams == nil {
+		r.deleteStreamLimiter(model)
 	}
+	r.specs[model] = ratelimit.DeepCopy()
 
 	return nil
 }
 
+// setStreamLimit sets the concurrent stream limit of a model, must be called with the mutex held. The limiter of
+// the model only has its limit changed, so that the streams in flight keep counting against the new limit. When the
+// model switches between local and global limits, the new limiter carries over the streams of the previous one,
+// which are released against it.
+func (r *TokenRateLimiter) setStreamLimit(model string, global bool, limit uint32) {
+	old, exists := r.streamLimiter[model]
+	if exists {
+		if _, oldGlobal := old.(*GlobalStreamLimiter); oldGlobal == global {
+			old.SetLimit(limit)
+			return
+		}
+		old.Stop()
+	}
+	if global {
+		limiter := NewGlobalStreamLimiter(r.redisClient, "kthena:streams", model, limit)
+		if exists {
+			limiter.carried.carry(old)
+		}
+		r.streamLimiter[model] = limiter
+	} else {
+		limiter := NewLocalStreamLimiter(limit)
+		if exists {
+			limiter.carried.carry(old)
+		}
+		r.streamLimiter[model] = limiter
+	}
+}
+
+// deleteStreamLimiter removes the stream limiter of a model, must be called with the mutex held.
+// Streams admitted by the limiter are released against it.
+func (r *TokenRateLimiter) deleteStreamLimiter(model string) {
+	if old, exists := r.streamLimiter[model]; exists {
+		old.Stop()
+		delete(r.streamLimiter, model)
+	}
+}
+
 // DeleteLimiter deletes rate limiter for a model
 func (r *TokenRateLimiter) DeleteLimiter(model string) {
 	r.mutex.Lock()
@@ -210,6 +292,18 @@ func (r *TokenRateLimiter) DeleteLimiter(model string) {
 
 	delete(r.inputLimiter, model)
 	delete(r.outputLimiter, model)
+	delete(r.specs, model)
+	r.deleteStreamLimiter(model)
+}
+
+// Deregister removes this replica from the global stream limiters, once it no longer serves requests.
+func (r *TokenRateLimiter) Deregister() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, limiter := range r.streamLimiter {
+		limiter.Deregister()
+	}
 }
 
 func getTimeUnitDuration(unit networkingv1alpha1.RateLimitUnit) time.Duration {
@@ -0,0 +1,361 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultStreamRetryAfter is the retry hint returned to clients when the
+	// concurrent stream limit of a model is exceeded.
+	DefaultStreamRetryAfter = time.Second
+
+	// replicaTTL is how long a router replica is considered alive after its last heartbeat.
+	replicaTTL = 30 * time.Second
+	// replicaHeartbeatInterval is how often a replica with in-flight streams refreshes its liveness.
+	replicaHeartbeatInterval = 10 * time.Second
+)
+
+// localReplicaID identifies this router instance in the shared stream limiter state. It is
+// shared by all limiters of the process so that a limiter replaced on a ModelRoute update
+// keeps accounting for the streams admitted before the update.
+var localReplicaID = replicaID()
+
+// ConcurrentStreamLimitExceededError is returned when a streaming request can not be
+// admitted because the model has reached its maximum number of concurrent streams.
+type ConcurrentStreamLimitExceededError struct {
+	// Limit is the configured maximum number of concurrent streams of the model.
+	Limit uint32
+	// RetryAfter is a hint of when the client should retry.
+	RetryAfter time.Duration
+}
+
+func (e *ConcurrentStreamLimitExceededError) Error() string {
+	return fmt.Sprintf("concurrent stream limit %d exceeded", e.Limit)
+}
+
+// StreamLimiter bounds the number of concurrent streams of a model.
+type StreamLimiter interface {
+	// Acquire reports whether a new stream may be started and reserves a slot if so
+	Acquire() bool
+	// Release frees a slot reserved by a successful Acquire
+	Release()
+	// Limit returns the maximum number of concurrent streams
+	Limit() uint32
+	// SetLimit changes the maximum number of concurrent streams, the streams in flight still count against it
+	SetLimit(limit uint32)
+	// InFlight returns the streams admitted and not released yet, including the ones carried over
+	InFlight() int64
+	// Stop releases resources held by the limiter
+	Stop()
+	// Deregister stops the limiter and removes this replica from the state shared with the other
+	// replicas, so that its fair share is handed over to them. It is called once the replica is drained.
+	Deregister()
+}
+
+// carriedStreams counts the streams still in flight of the limiter a new one replaced, when the rate limit of a
+// model switches between local and global. They are released against the previous limiter, but keep counting
+// against the limit of the new one until they complete.
+type carriedStreams struct {
+	mu       sync.Mutex
+	previous StreamLimiter
+}
+
+func (c *carriedStreams) carry(previous StreamLimiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.previous = previous
+}
+
+func (c *carriedStreams) count() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.previous == nil {
+		return 0
+	}
+	// The previous limiter admits no new streams, it is dropped once its streams are done
+	n := c.previous.InFlight()
+	if n <= 0 {
+		c.previous = nil
+		return 0
+	}
+	return n
+}
+
+// LocalStreamLimiter bounds the concurrent streams handled by this router instance.
+type LocalStreamLimiter struct {
+	limit    atomic.Uint32
+	inflight atomic.Int64
+	carried  carriedStreams
+}
+
+// NewLocalStreamLimiter creates a new LocalStreamLimiter
+func NewLocalStreamLimiter(limit uint32) *LocalStreamLimiter {
+	l := &LocalStreamLimiter{}
+	l.limit.Store(limit)
+	return l
+}
+
+func (l *LocalStreamLimiter) Acquire() bool {
+	if l.inflight.Add(1)+l.carried.count() > int64(l.limit.Load()) {
+		l.inflight.Add(-1)
+		return false
+	}
+	return true
+}
+
+func (l *LocalStreamLimiter) Release() {
+	l.inflight.Add(-1)
+}
+
+func (l *LocalStreamLimiter) Limit() uint32 {
+	return l.limit.Load()
+}
+
+func (l *LocalStreamLimiter) SetLimit(limit uint32) {
+	l.limit.Store(limit)
+}
+
+func (l *LocalStreamLimiter) InFlight() int64 {
+	return l.inflight.Load() + l.carried.count()
+}
+
+func (l *LocalStreamLimiter) Stop() {}
+
+func (l *LocalStreamLimiter) Deregister() {}
+
+// GlobalStreamLimiter bounds the concurrent streams of a model across all router replicas.
+//
+// Every replica registers itself with a heartbeat in a Redis sorted set, and the number
+// of in-flight streams of each replica is kept in a Redis hash. A stream is admitted only if
+// the model is below its global limit and the replica is below its fair share, which is the
+// global limit divided by the number of live replicas. This prevents a single replica from
+// consuming the entire allowance while others are starved. Replicas which stop sending
+// heartbeats are evicted together with their in-flight counts, so a crashed replica can not
+// leak slots.
+type GlobalStreamLimiter struct {
+	client    *redis.Client
+	keyPrefix string
+	modelName string
+	replicaID string
+	limit     atomic.Uint32
+
+	inflight atomic.Int64
+	// carried are the streams of a previous local limiter, which are not counted in Redis
+	carried  carriedStreams
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// acquireStreamScript atomically evicts stale replicas, refreshes the heartbeat of the
+// calling replica and reserves a stream slot if both the global limit and the fair share allow it.
+var acquireStreamScript = redis.NewScript(`
+	local replicas_key = KEYS[1]                  -- sorted set: replica -> last heartbeat
+	local inflight_key = KEYS[2]                  -- hash: replica -> in-flight streams
+	local replica = ARGV[1]
+	local limit = tonumber(ARGV[2])
+	local ttl = tonumber(ARGV[3])
+	local carried = tonumber(ARGV[4])             -- in-flight streams of the replica not counted in the hash
+
+	local time_result = redis.call('time')
+	local now = tonumber(time_result[1])
+
+	-- Evict replicas which stopped sending heartbeats, releasing their slots
+	local stale = redis.call('zrangebyscore', replicas_key, '-inf', now - ttl)
+	for _, r in ipairs(stale) do
+		redis.call('hdel', inflight_key, r)
+	end
+	redis.call('zremrangebyscore', replicas_key, '-inf', now - ttl)
+
+	redis.call('zadd', replicas_key, now, replica)
+	redis.call('expire', replicas_key, ttl * 2)
+	redis.call('expire', inflight_key, ttl * 2)
+
+	local total = carried
+	for _, v in ipairs(redis.call('hvals', inflight_key)) do
+		total = total + tonumber(v)
+	end
+	local mine = (tonumber(redis.call('hget', inflight_key, replica)) or 0) + carried
+	local fair_share = math.ceil(limit / redis.call('zcard', replicas_key))
+
+	if total >= limit or mine >= fair_share then
+		return 0
+	end
+	redis.call('hincrby', inflight_key, replica, 1)
+	return 1
+`)
+
+// releaseStreamScript frees a stream slot of the calling replica.
+var releaseStreamScript = redis.NewScript(`
+	local inflight_key = KEYS[1]
+	local replica = ARGV[1]
+
+	local remaining = redis.call('hincrby', inflight_key, replica, -1)
+	if remaining <= 0 then
+		redis.call('hdel', inflight_key, replica)
+	end
+	return remaining
+`)
+
+// heartbeatScript refreshes the liveness of the calling replica.
+var heartbeatScript = redis.NewScript(`
+	local replicas_key = KEYS[1]
+	local inflight_key = KEYS[2]
+	local replica = ARGV[1]
+	local ttl = tonumber(ARGV[2])
+
+	local time_result = redis.call('time')
+	redis.call('zadd', replicas_key, tonumber(time_result[1]), replica)
+	redis.call('expire', replicas_key, ttl * 2)
+	redis.call('expire', inflight_key, ttl * 2)
+	return 1
+`)
+
+// deregisterScript removes the calling replica and its in-flight streams.
+var deregisterScript = redis.NewScript(`
+	local replicas_key = KEYS[1]
+	local inflight_key = KEYS[2]
+	local replica = ARGV[1]
+
+	redis.call('zrem', replicas_key, replica)
+	redis.call('hdel', inflight_key, replica)
+	return 1
+`)
+
+// NewGlobalStreamLimiter creates a new GlobalStreamLimiter instance
+func NewGlobalStreamLimiter(client *redis.Client, keyPrefix, modelName string, limit uint32) *GlobalStreamLimiter {
+	g := &GlobalStreamLimiter{
+		client:    client,
+		keyPrefix: keyPrefix,
+		modelName: modelName,
+		replicaID: localReplicaID,
+		stopCh:    make(chan struct{}),
+	}
+	g.limit.Store(limit)
+	go g.heartbeatLoop()
+	return g
+}
+
+func (g *GlobalStreamLimiter) Acquire() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := acquireStreamScript.Run(ctx, g.client, g.keys(), g.replicaID, g.limit.Load(), int(replicaTTL.Seconds()), g.carried.count()).Int64()
+	if err != nil {
+		klog.Errorf("failed to execute acquire stream lua script: %v", err)
+		return false
+	}
+	if result != 1 {
+		return false
+	}
+	g.inflight.Add(1)
+	return true
+}
+
+func (g *GlobalStreamLimiter) Release() {
+	g.inflight.Add(-1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	keys := g.keys()
+	if err := releaseStreamScript.Run(ctx, g.client, keys[1:], g.replicaID).Err(); err != nil {
+		klog.Errorf("failed to execute release stream lua script: %v", err)
+	}
+}
+
+func (g *GlobalStreamLimiter) Limit() uint32 {
+	return g.limit.Load()
+}
+
+func (g *GlobalStreamLimiter) SetLimit(limit uint32) {
+	g.limit.Store(limit)
+}
+
+func (g *GlobalStreamLimiter) InFlight() int64 {
+	return g.inflight.Load() + g.carried.count()
+}
+
+func (g *GlobalStreamLimiter) Stop() {
+	g.stopOnce.Do(func() {
+		close(g.stopCh)
+	})
+}
+
+func (g *GlobalStreamLimiter) Deregister() {
+	g.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := deregisterScript.Run(ctx, g.client, g.keys(), g.replicaID).Err(); err != nil {
+		klog.Errorf("failed to deregister replica %s from stream limiter: %v", g.replicaID, err)
+	}
+}
+
+// heartbeatLoop keeps the replica alive while it is serving long-lived streams, otherwise
+// other replicas would evict it and its slots would be handed out twice.
+func (g *GlobalStreamLimiter) heartbeatLoop() {
+	ticker := time.NewTicker(replicaHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.stopCh:
+			return
+		case <-ticker.C:
+			if g.InFlight() > 0 {
+				g.heartbeat()
+			}
+		}
+	}
+}
+
+func (g *GlobalStreamLimiter) heartbeat() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := heartbeatScript.Run(ctx, g.client, g.keys(), g.replicaID, int(replicaTTL.Seconds())).Err(); err != nil {
+		klog.Errorf("failed to send stream limiter heartbeat: %v", err)
+	}
+}
+
+func (g *GlobalStreamLimiter) keys() []string {
+	return []string{
+		fmt.Sprintf("%s:%s:replicas", g.keyPrefix, g.modelName),
+		fmt.Sprintf("%s:%s:inflight", g.keyPrefix, g.modelName),
+	}
+}
+
+// replicaID identifies this router instance. The pod name is used when available
+// to make the Redis state easy to inspect, with a random suffix to survive restarts.
+func replicaID() string {
+	name := os.Getenv("POD_NAME")
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+	return fmt.Sprintf("%s-%s", name, uuid.New().String()[:8])
+}
@@ -0,0 +1,197 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+func TestLocalStreamLimiter(t *testing.T) {
+	limiter := NewLocalStreamLimiter(2)
+
+	assert.True(t, limiter.Acquire())
+	assert.True(t, limiter.Acquire())
+	assert.False(t, limiter.Acquire(), "third stream should exceed the limit")
+
+	limiter.Release()
+	assert.True(t, limiter.Acquire(), "released slot should be reusable")
+}
+
+func TestGlobalStreamLimiter_FairShare(t *testing.T) {
+	mr, redisConfig := setupMiniRedis(t)
+	defer mr.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: redisConfig.Address})
+	defer client.Close()
+
+	replicaA := NewGlobalStreamLimiter(client, "test:streams", "model", 4)
+	replicaA.replicaID = "replica-a"
+	defer replicaA.Stop()
+	replicaB := NewGlobalStreamLimiter(client, "test:streams", "model", 4)
+	replicaB.replicaID = "replica-b"
+	defer replicaB.Stop()
+
+	// Replica B registers itself, so the fair share of each replica is 2
+	require.True(t, replicaB.Acquire())
+	replicaB.Release()
+
+	assert.True(t, replicaA.Acquire())
+	assert.True(t, replicaA.Acquire())
+	assert.False(t, replicaA.Acquire(), "replica A should not exceed its fair share")
+
+	assert.True(t, replicaB.Acquire())
+	assert.True(t, replicaB.Acquire())
+	assert.False(t, replicaB.Acquire(), "global limit should be reached")
+
+	replicaA.Release()
+	assert.True(t, replicaA.Acquire(), "released slot should be reusable")
+}
+
+func TestGlobalStreamLimiter_EvictsStaleReplicas(t *testing.T) {
+	mr, redisConfig := setupMiniRedis(t)
+	defer mr.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: redisConfig.Address})
+	defer client.Close()
+
+	crashed := NewGlobalStreamLimiter(client, "test:streams", "model", 2)
+	crashed.replicaID = "crashed"
+	defer crashed.Stop()
+	alive := NewGlobalStreamLimiter(client, "test:streams", "model", 2)
+	alive.replicaID = "alive"
+	defer alive.Stop()
+
+	require.True(t, crashed.Acquire())
+	require.True(t, alive.Acquire())
+	assert.False(t, alive.Acquire(), "global limit should be reached")
+
+	// The crashed replica never releases its stream, its slot is reclaimed once it misses heartbeats
+	mr.SetTime(time.Now().Add(2 * replicaTTL))
+	alive.heartbeat()
+	assert.True(t, alive.Acquire())
+	assert.False(t, alive.Acquire(), "streams of the alive replica should still be counted")
+}
+
+func TestGlobalStreamLimiter_Deregister(t *testing.T) {
+	mr, redisConfig := setupMiniRedis(t)
+	defer mr.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: redisConfig.Address})
+	defer client.Close()
+
+	drained := NewGlobalStreamLimiter(client, "test:streams", "model", 4)
+	drained.replicaID = "drained"
+	alive := NewGlobalStreamLimiter(client, "test:streams", "model", 4)
+	alive.replicaID = "alive"
+	defer alive.Stop()
+
+	require.True(t, drained.Acquire())
+	drained.Release()
+	assert.True(t, alive.Acquire())
+	assert.True(t, alive.Acquire())
+	assert.False(t, alive.Acquire(), "alive replica should be bounded by its fair share")
+
+	// Once the drained replica leaves, the alive replica gets the whole limit without waiting for the eviction
+	drained.Deregister()
+	assert.True(t, alive.Acquire())
+	assert.True(t, alive.Acquire())
+	assert.False(t, alive.Acquire(), "global limit should be reached")
+}
+
+func TestTokenRateLimiter_AcquireStream(t *testing.T) {
+	rl := NewTokenRateLimiter()
+	streams := uint32(1)
+
+	release, err := rl.AcquireStream("unlimited-model")
+	require.NoError(t, err)
+	release()
+
+	err = rl.AddOrUpdateLimiter("model", &networkingv1alpha1.RateLimit{
+		MaxConcurrentStreams: &streams,
+		Unit:                 networkingv1alpha1.Second,
+	})
+	require.NoError(t, err)
+
+	release, err = rl.AcquireStream("model")
+	require.NoError(t, err)
+
+	_, err = rl.AcquireStream("model")
+	require.Error(t, err)
+	limitErr, ok := err.(*ConcurrentStreamLimitExceededError)
+	require.True(t, ok)
+	assert.Equal(t, streams, limitErr.Limit)
+	assert.Equal(t, DefaultStreamRetryAfter, limitErr.RetryAfter)
+
+	release()
+	release, err = rl.AcquireStream("model")
+	require.NoError(t, err)
+	release()
+
+	// Removing the limit from the ModelRoute removes the stream limiter
+	err = rl.AddOrUpdateLimiter("model", &networkingv1alpha1.RateLimit{Unit: networkingv1alpha1.Second})
+	require.NoError(t, err)
+	_, err = rl.AcquireStream("model")
+	assert.NoError(t, err)
+}
+
+func TestTokenRateLimiter_StreamLimitUpdate(t *testing.T) {
+	mr, redisConfig := setupMiniRedis(t)
+	defer mr.Close()
+
+	rl := NewTokenRateLimiter()
+	model := "test-model"
+	limit := uint32(2)
+	require.NoError(t, rl.AddOrUpdateLimiter(model, &networkingv1alpha1.RateLimit{MaxConcurrentStreams: &limit}))
+	release1, err := rl.AcquireStream(model)
+	require.NoError(t, err)
+	release2, err := rl.AcquireStream(model)
+	require.NoError(t, err)
+
+	// The streams in flight count against the new limit
+	limit = 3
+	require.NoError(t, rl.AddOrUpdateLimiter(model, &networkingv1alpha1.RateLimit{MaxConcurrentStreams: &limit}))
+	release3, err := rl.AcquireStream(model)
+	require.NoError(t, err)
+	_, err = rl.AcquireStream(model)
+	assert.Error(t, err, "the streams admitted before the update should count against the new limit")
+
+	// The local streams are carried over to the global limiter
+	limit = 4
+	require.NoError(t, rl.AddOrUpdateLimiter(model, &networkingv1alpha1.RateLimit{
+		MaxConcurrentStreams: &limit,
+		Global:               &networkingv1alpha1.GlobalRateLimit{Redis: redisConfig},
+	}))
+	defer rl.Deregister()
+	release4, err := rl.AcquireStream(model)
+	require.NoError(t, err)
+	_, err = rl.AcquireStream(model)
+	assert.Error(t, err, "the local streams should count against the global limit")
+
+	release1()
+	release2()
+	release3()
+	_, err = rl.AcquireStream(model)
+	assert.NoError(t, err, "the released local streams should free their slots")
+	release4()
+}
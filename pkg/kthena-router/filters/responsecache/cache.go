@@ -0,0 +1,231 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package responsecache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/redis/go-redis/v9"
+	"k8s.io/klog/v2"
+)
+
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+
+	// CacheStatusHeader reports whether the response was served from the cache.
+	CacheStatusHeader = "X-Kthena-Cache"
+	CacheStatusHit    = "HIT"
+	CacheStatusMiss   = "MISS"
+
+	redisKeyPrefix = "kthena:responsecache"
+)
+
+// Fields of the request body which do not change the generated output and are
+// therefore excluded from the cache key.
+var ignoredRequestFields = []string{"stream", "stream_options", "user", "userId"}
+
+// Entry is a cached upstream response.
+type Entry struct {
+	StatusCode  int       `json:"statusCode"`
+	ContentType string    `json:"contentType"`
+	Body        []byte    `json:"body"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Backend is the storage used by the ResponseCache.
+type Backend interface {
+	Get(ctx context.Context, key string) (*Entry, bool)
+	Set(ctx context.Context, key string, entry *Entry, ttl time.Duration) error
+}
+
+// Config holds the response cache configuration.
+type Config struct {
+	// Backend is either "memory" or "redis".
+	Backend string
+	// TTL is how long a response stays cached.
+	TTL time.Duration
+	// MaxEntries bounds the in-memory LRU.
+	MaxEntries int
+	// MaxBodyBytes is the largest response body that will be cached.
+	MaxBodyBytes int
+}
+
+// ResponseCache caches responses of deterministic (temperature=0) completions,
+// so that identical requests are served without hitting the model server.
+// Only the exact matches of a normalized request are served: similar prompts are not matched semantically.
+type ResponseCache struct {
+	backend      Backend
+	ttl          time.Duration
+	maxBodyBytes int
+}
+
+// NewResponseCache creates a ResponseCache with the backend selected by config.
+func NewResponseCache(config *Config, redisClient *redis.Client) (*ResponseCache, error) {
+	if config.TTL <= 0 {
+		return nil, fmt.Errorf("response cache ttl must be positive, got %v", config.TTL)
+	}
+
+	var backend Backend
+	switch config.Backend {
+	case BackendMemory, "":
+		if config.MaxEntries <= 0 {
+			return nil, fmt.Errorf("response cache max entries must be positive, got %d", config.MaxEntries)
+		}
+		backend = newMemoryBackend(config.MaxEntries, config.TTL)
+	case BackendRedis:
+		if redisClient == nil {
+			return nil, fmt.Errorf("redis backend requires a redis client")
+		}
+		backend = &redisBackend{client: redisClient}
+	default:
+		return nil, fmt.Errorf("unknown response cache backend %q", config.Backend)
+	}
+
+	return &ResponseCache{
+		backend:      backend,
+		ttl:          config.TTL,
+		maxBodyBytes: config.MaxBodyBytes,
+	}, nil
+}
+
+// Key returns the cache key for the request of a consumer in a tenant, and false if the request is not cacheable.
+// Only non-streaming requests which explicitly set temperature to 0 and ask for a single
+// choice are considered deterministic. The tenant is empty for the requests of no tenant, and the consumer
+// for the anonymous requests. A cached response is only served to the consumer it was cached for, so that
+// the consumers never read the completions of each other.
+func Key(tenant, consumer, model string, request map[string]interface{}) (string, bool) {
+	temperature, ok := request["temperature"].(float64)
+	if !ok || temperature != 0 {
+		return "", false
+	}
+	if stream, ok := request["stream"].(bool); ok && stream {
+		return "", false
+	}
+	if n, ok := request["n"].(float64); ok && n != 1 {
+		return "", false
+	}
+
+	normalized := make(map[string]interface{}, len(request))
+	for k, v := range request {
+		normalized[k] = v
+	}
+	for _, field := range ignoredRequestFields {
+		delete(normalized, field)
+	}
+	normalized["model"] = model
+
+	// encoding/json sorts map keys, which makes the encoding canonical.
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", false
+	}
+	if tenant == "" && consumer == "" {
+		sum := sha256.Sum256(data)
+		return model + ":" + hex.EncodeToString(sum[:]), true
+	}
+	// The tenant and the consumer are hashed too, so that their keys never collide whatever their names,
+	// and the credentials of the consumers never appear in the keys.
+	hash := sha256.New()
+	hash.Write([]byte(tenant))
+	hash.Write([]byte{0})
+	hash.Write([]byte(consumer))
+	hash.Write([]byte{0})
+	hash.Write(data)
+	if tenant == "" {
+		return model + ":" + hex.EncodeToString(hash.Sum(nil)), true
+	}
+	return tenant + "/" + model + ":" + hex.EncodeToString(hash.Sum(nil)), true
+}
+
+// Get returns the cached entry for key.
+func (r *ResponseCache) Get(ctx context.Context, key string) (*Entry, bool) {
+	return r.backend.Get(ctx, key)
+}
+
+// Set stores a successful response for key. Responses that are not 200 or exceed
+// the body size limit are not cached.
+func (r *ResponseCache) Set(ctx context.Context, key string, entry *Entry) {
+	if entry.StatusCode != 200 || len(entry.Body) == 0 {
+		return
+	}
+	if r.maxBodyBytes > 0 && len(entry.Body) > r.maxBodyBytes {
+		return
+	}
+	if err := r.backend.Set(ctx, key, entry, r.ttl); err != nil {
+		klog.Errorf("failed to store response in cache: %v", err)
+	}
+}
+
+// memoryBackend is an in-memory LRU with per-entry expiration.
+type memoryBackend struct {
+	cache *expirable.LRU[string, *Entry]
+}
+
+func newMemoryBackend(size int, ttl time.Duration) *memoryBackend {
+	return &memoryBackend{
+		cache: expirable.NewLRU[string, *Entry](size, nil, ttl),
+	}
+}
+
+func (m *memoryBackend) Get(_ context.Context, key string) (*Entry, bool) {
+	return m.cache.Get(key)
+}
+
+func (m *memoryBackend) Set(_ context.Context, key string, entry *Entry, _ time.Duration) error {
+	m.cache.Add(key, entry)
+	return nil
+}
+
+// redisBackend shares cached responses across router instances.
+type redisBackend struct {
+	client *redis.Client
+}
+
+func (b *redisBackend) Get(ctx context.Context, key string) (*Entry, bool) {
+	data, err := b.client.Get(ctx, redisKey(key)).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			klog.Errorf("failed to get response from redis cache: %v", err)
+		}
+		return nil, false
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		klog.Errorf("failed to unmarshal cached response: %v", err)
+		return nil, false
+	}
+	return &entry, true
+}
+
+func (b *redisBackend) Set(ctx context.Context, key string, entry *Entry, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return b.client.Set(ctx, redisKey(key), data, ttl).Err()
+}
+
+func redisKey(key string) string {
+	return fmt.Sprintf("%s:%s", redisKeyPrefix, key)
+}
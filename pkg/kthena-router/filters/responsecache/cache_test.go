@@ -0,0 +1,176 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package responsecache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   map[string]interface{}
+		cacheable bool
+	}{
+		{
+			name:      "temperature zero",
+			request:   map[string]interface{}{"prompt": "hello", "temperature": float64(0)},
+			cacheable: true,
+		},
+		{
+			name:      "temperature not set",
+			request:   map[string]interface{}{"prompt": "hello"},
+			cacheable: false,
+		},
+		{
+			name:      "temperature non zero",
+			request:   map[string]interface{}{"prompt": "hello", "temperature": 0.7},
+			cacheable: false,
+		},
+		{
+			name:      "streaming",
+			request:   map[string]interface{}{"prompt": "hello", "temperature": float64(0), "stream": true},
+			cacheable: false,
+		},
+		{
+			name:      "multiple choices",
+			request:   map[string]interface{}{"prompt": "hello", "temperature": float64(0), "n": float64(2)},
+			cacheable: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := Key("", "", "model", tt.request)
+			assert.Equal(t, tt.cacheable, ok)
+		})
+	}
+}
+
+func TestKeyNormalization(t *testing.T) {
+	a, ok := Key("", "", "model", map[string]interface{}{"prompt": "hello", "temperature": float64(0), "max_tokens": float64(10), "user": "alice"})
+	require.True(t, ok)
+	b, ok := Key("", "", "model", map[string]interface{}{"max_tokens": float64(10), "temperature": float64(0), "prompt": "hello", "stream": false})
+	require.True(t, ok)
+	assert.Equal(t, a, b)
+
+	c, ok := Key("", "", "other-model", map[string]interface{}{"prompt": "hello", "temperature": float64(0), "max_tokens": float64(10)})
+	require.True(t, ok)
+	assert.NotEqual(t, a, c)
+
+	d, ok := Key("", "", "model", map[string]interface{}{"prompt": "hello!", "temperature": float64(0), "max_tokens": float64(10)})
+	require.True(t, ok)
+	assert.NotEqual(t, a, d)
+
+	// The same request of another tenant has another key
+	e, ok := Key("team-a", "", "model", map[string]interface{}{"prompt": "hello", "temperature": float64(0), "max_tokens": float64(10)})
+	require.True(t, ok)
+	f, ok := Key("team-b", "", "model", map[string]interface{}{"prompt": "hello", "temperature": float64(0), "max_tokens": float64(10)})
+	require.True(t, ok)
+	assert.NotEqual(t, a, e)
+	assert.NotEqual(t, e, f)
+
+	// The same request of another consumer has another key, which does not reveal its credentials
+	g, ok := Key("", "apikey:secret-a", "model", map[string]interface{}{"prompt": "hello", "temperature": float64(0), "max_tokens": float64(10)})
+	require.True(t, ok)
+	h, ok := Key("", "apikey:secret-b", "model", map[string]interface{}{"prompt": "hello", "temperature": float64(0), "max_tokens": float64(10)})
+	require.True(t, ok)
+	assert.NotEqual(t, a, g)
+	assert.NotEqual(t, g, h)
+	assert.NotContains(t, g, "secret-a")
+}
+
+func TestParseCacheControl(t *testing.T) {
+	tests := []struct {
+		header string
+		lookup bool
+		store  bool
+	}{
+		{header: "", lookup: true, store: true},
+		{header: "no-cache", lookup: false, store: true},
+		{header: "no-store", lookup: false, store: false},
+		{header: "max-age=0, No-Cache", lookup: false, store: true},
+	}
+
+	for _, tt := range tests {
+		lookup, store := ParseCacheControl(tt.header)
+		assert.Equal(t, tt.lookup, lookup, tt.header)
+		assert.Equal(t, tt.store, store, tt.header)
+	}
+}
+
+func TestMemoryBackend(t *testing.T) {
+	cache, err := NewResponseCache(&Config{Backend: BackendMemory, TTL: 50 * time.Millisecond, MaxEntries: 2, MaxBodyBytes: 8}, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	cache.Set(ctx, "k1", &Entry{StatusCode: 200, Body: []byte("ok")})
+	entry, ok := cache.Get(ctx, "k1")
+	require.True(t, ok)
+	assert.Equal(t, []byte("ok"), entry.Body)
+
+	// Failed and oversized responses are not cached.
+	cache.Set(ctx, "k2", &Entry{StatusCode: 500, Body: []byte("error")})
+	_, ok = cache.Get(ctx, "k2")
+	assert.False(t, ok)
+	cache.Set(ctx, "k3", &Entry{StatusCode: 200, Body: []byte("too large body")})
+	_, ok = cache.Get(ctx, "k3")
+	assert.False(t, ok)
+
+	time.Sleep(100 * time.Millisecond)
+	_, ok = cache.Get(ctx, "k1")
+	assert.False(t, ok, "entry should expire after ttl")
+}
+
+func TestRedisBackend(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	cache, err := NewResponseCache(&Config{Backend: BackendRedis, TTL: time.Minute}, client)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	cache.Set(ctx, "k1", &Entry{StatusCode: 200, ContentType: "application/json", Body: []byte(`{"id":"1"}`)})
+	entry, ok := cache.Get(ctx, "k1")
+	require.True(t, ok)
+	assert.Equal(t, "application/json", entry.ContentType)
+	assert.Equal(t, []byte(`{"id":"1"}`), entry.Body)
+
+	mr.FastForward(2 * time.Minute)
+	_, ok = cache.Get(ctx, "k1")
+	assert.False(t, ok, "entry should expire after ttl")
+}
+
+func TestNewResponseCacheErrors(t *testing.T) {
+	_, err := NewResponseCache(&Config{Backend: BackendRedis, TTL: time.Minute}, nil)
+	assert.Error(t, err)
+	_, err = NewResponseCache(&Config{Backend: "unknown", TTL: time.Minute}, nil)
+	assert.Error(t, err)
+	_, err = NewResponseCache(&Config{Backend: BackendMemory}, nil)
+	assert.Error(t, err)
+}
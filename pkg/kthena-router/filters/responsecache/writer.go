@@ -0,0 +1,88 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package responsecache
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseCacheControl reports whether the request's Cache-Control header allows
+// serving from the cache (lookup) and storing the response (store).
+// "no-cache" forces revalidation with the model server, "no-store" disables caching entirely.
+func ParseCacheControl(header string) (lookup bool, store bool) {
+	lookup, store = true, true
+	for _, directive := range strings.Split(header, ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-cache":
+			lookup = false
+		case "no-store":
+			lookup, store = false, false
+		}
+	}
+	return lookup, store
+}
+
+// CaptureWriter wraps the gin ResponseWriter and keeps a copy of the response body,
+// up to limit bytes, so that it can be stored after the response has been proxied.
+type CaptureWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+// NewCaptureWriter wraps w. A limit <= 0 means the body is captured without bound.
+func NewCaptureWriter(w gin.ResponseWriter, limit int) *CaptureWriter {
+	return &CaptureWriter{ResponseWriter: w, limit: limit}
+}
+
+func (w *CaptureWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *CaptureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *CaptureWriter) capture(data []byte) {
+	if w.overflow {
+		return
+	}
+	if w.limit > 0 && w.body.Len()+len(data) > w.limit {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
+
+// Entry returns the captured response, or nil if the body exceeded the limit.
+func (w *CaptureWriter) Entry() *Entry {
+	if w.overflow {
+		return nil
+	}
+	return &Entry{
+		StatusCode:  w.Status(),
+		ContentType: w.Header().Get("Content-Type"),
+		Body:        w.body.Bytes(),
+	}
+}
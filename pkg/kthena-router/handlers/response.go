@@ -30,6 +30,27 @@ type Usage struct {
 	TotalTokens      int `json:"total_tokens"`
 }
 
+// UnmarshalJSON also reads the usage of the Responses API, which counts input and output tokens.
+func (u *Usage) UnmarshalJSON(data []byte) error {
+	type usage Usage
+	var v struct {
+		usage
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*u = Usage(v.usage)
+	if u.PromptTokens == 0 {
+		u.PromptTokens = v.InputTokens
+	}
+	if u.CompletionTokens == 0 {
+		u.CompletionTokens = v.OutputTokens
+	}
+	return nil
+}
+
 // Define a struct to represent the OpenAI response body
 type OpenAIResponse struct {
 	ID      string `json:"id"`
@@ -37,6 +58,11 @@ type OpenAIResponse struct {
 	Created int64  `json:"created"`
 	Model   string `json:"model"`
 	Usage   Usage  `json:"usage"`
+	// Response is the response carried by the streaming events of the Responses API,
+	// the usage is reported by the last one, response.completed.
+	Response *struct {
+		Usage Usage `json:"usage"`
+	} `json:"response,omitempty"`
 }
 
 // Function to parse the OpenAI response body
@@ -51,6 +77,78 @@ func ParseOpenAIResponseBody(resp []byte) (*OpenAIResponse, error) {
 	return &responseBody, nil
 }
 
+// OutputTexts returns the generated outputs of a completion, chat completion or Responses API response, one per
+// choice or output item. The tool calls are rendered as name(arguments). It returns false if the body is not
+// such a response.
+func OutputTexts(body []byte) ([]string, bool) {
+	var resp struct {
+		Choices []struct {
+			Text    string `json:"text"`
+			Message *struct {
+				Content   string `json:"content"`
+				ToolCalls []struct {
+					Function functionCall `json:"function"`
+				} `json:"tool_calls"`
+			} `json:"message"`
+		} `json:"choices"`
+		Output []struct {
+			Type string `json:"type"`
+			functionCall
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"output"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || (resp.Choices == nil && resp.Output == nil) {
+		return nil, false
+	}
+	outputs := make([]string, 0, len(resp.Choices)+len(resp.Output))
+	for _, choice := range resp.Choices {
+		if choice.Message == nil {
+			outputs = append(outputs, choice.Text)
+			continue
+		}
+		texts := []string{choice.Message.Content}
+		for _, toolCall := range choice.Message.ToolCalls {
+			texts = append(texts, toolCall.Function.String())
+		}
+		outputs = append(outputs, strings.Join(nonEmpty(texts), "\n"))
+	}
+	for _, item := range resp.Output {
+		switch item.Type {
+		case "message":
+			texts := make([]string, 0, len(item.Content))
+			for _, content := range item.Content {
+				texts = append(texts, content.Text)
+			}
+			outputs = append(outputs, strings.Join(nonEmpty(texts), "\n"))
+		case "function_call":
+			outputs = append(outputs, item.functionCall.String())
+		}
+	}
+	return outputs, true
+}
+
+// functionCall is a function call of the model, in chat completions and in the Responses API.
+type functionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+func (f functionCall) String() string {
+	return f.Name + "(" + f.Arguments + ")"
+}
+
+func nonEmpty(texts []string) []string {
+	result := texts[:0]
+	for _, text := range texts {
+		if text != "" {
+			result = append(result, text)
+		}
+	}
+	return result
+}
+
 const (
 	streamingRespPrefix = "data: "
 	streamingEndMsg     = "data: [DONE]"
@@ -81,6 +179,9 @@ func ParseStreamRespForUsage(
 		klog.Error(err, "unmarshaling response body ", content)
 		return response
 	}
+	if response.Response != nil && response.Usage == (Usage{}) {
+		response.Usage = response.Response.Usage
+	}
 
 	return response
 }
@@ -0,0 +1,63 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOpenAIResponseBodyUsage(t *testing.T) {
+	chat, err := ParseOpenAIResponseBody([]byte(`{"object":"chat.completion","usage":{"prompt_tokens":7,"completion_tokens":10,"total_tokens":17}}`))
+	require.NoError(t, err)
+	assert.Equal(t, Usage{PromptTokens: 7, CompletionTokens: 10, TotalTokens: 17}, chat.Usage)
+
+	resp, err := ParseOpenAIResponseBody([]byte(`{"object":"response","usage":{"input_tokens":7,"output_tokens":10,"total_tokens":17}}`))
+	require.NoError(t, err)
+	assert.Equal(t, Usage{PromptTokens: 7, CompletionTokens: 10, TotalTokens: 17}, resp.Usage)
+}
+
+func TestParseStreamRespForUsage(t *testing.T) {
+	chunk := ParseStreamRespForUsage(`data: {"id":"1","choices":[],"usage":{"prompt_tokens":7,"completion_tokens":10,"total_tokens":17}}` + "\n")
+	assert.Equal(t, 10, chunk.Usage.CompletionTokens)
+
+	// The Responses API reports the usage in the response of its last event
+	completed := ParseStreamRespForUsage(`data: {"type":"response.completed","response":{"id":"resp_1","usage":{"input_tokens":5,"output_tokens":3,"total_tokens":8}}}` + "\n")
+	assert.Equal(t, Usage{PromptTokens: 5, CompletionTokens: 3, TotalTokens: 8}, completed.Usage)
+
+	delta := ParseStreamRespForUsage(`data: {"type":"response.output_text.delta","delta":"hi"}` + "\n")
+	assert.Equal(t, Usage{}, delta.Usage)
+	assert.Equal(t, Usage{}, ParseStreamRespForUsage("event: response.completed\n").Usage)
+}
+
+func TestOutputTexts(t *testing.T) {
+	outputs, ok := OutputTexts([]byte(`{"choices":[{"message":{"content":"checking","tool_calls":[{"function":{"name":"f","arguments":"{}"}}]}},{"text":"b"}]}`))
+	require.True(t, ok)
+	assert.Equal(t, []string{"checking\nf({})", "b"}, outputs)
+
+	outputs, ok = OutputTexts([]byte(`{"output":[{"type":"function_call","call_id":"c1","name":"f","arguments":"{\"x\":1}"},` +
+		`{"type":"message","content":[{"type":"output_text","text":"a"},{"type":"output_text","text":"b"}]}]}`))
+	require.True(t, ok)
+	assert.Equal(t, []string{`f({"x":1})`, "a\nb"}, outputs)
+
+	_, ok = OutputTexts([]byte(`{"data":[]}`))
+	assert.False(t, ok)
+	_, ok = OutputTexts([]byte("plain"))
+	assert.False(t, ok)
+}
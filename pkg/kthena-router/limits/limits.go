@@ -0,0 +1,183 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package limits protects the router and the model servers from pathological clients: oversized request
+// bodies, clients sending their request too slowly, requests running forever and streams stalled upstream.
+package limits
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+)
+
+// ErrStreamIdleTimeout is returned by the bodies wrapped by IdleTimeoutBody once they timed out.
+var ErrStreamIdleTimeout = errors.New("no data received from the model server within the stream idle timeout")
+
+// Limits are the limits of the requests served by the router. A zero value disables the limit.
+type Limits struct {
+	// MaxRequestBodyBytes is the largest request body accepted, larger ones are rejected with 413.
+	MaxRequestBodyBytes int64
+	// BodyReadTimeout is the time a client has to send the request body once its headers are received,
+	// slower clients are rejected with 408.
+	BodyReadTimeout time.Duration
+	// RequestTimeout is the time a request is served for, its upstream requests and streams included.
+	// The requests timing out before a response is sent are answered with 504.
+	RequestTimeout time.Duration
+}
+
+// Middleware reads the request body within the limits, so that the handlers never wait on a slow client,
+// and bounds the duration of the request.
+func (l *Limits) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l.MaxRequestBodyBytes > 0 && c.Request.ContentLength > l.MaxRequestBodyBytes {
+			abortBodyTooLarge(c, l.MaxRequestBodyBytes)
+			return
+		}
+		if !l.readBody(c) {
+			return
+		}
+		if l.RequestTimeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), l.RequestTimeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return
+		}
+		metrics.DefaultMetrics.RecordRequestLimitExceeded(metrics.RequestLimitRequestTimeout)
+		if !c.Writer.Written() {
+			c.AbortWithStatusJSON(http.StatusGatewayTimeout, "request timeout")
+		}
+	}
+}
+
+// readBody replaces the body of the request with its content read within the limits. It returns false when
+// the request has been rejected.
+func (l *Limits) readBody(c *gin.Context) bool {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody || (l.MaxRequestBodyBytes <= 0 && l.BodyReadTimeout <= 0) {
+		return true
+	}
+	body := c.Request.Body
+	if l.MaxRequestBodyBytes > 0 {
+		body = http.MaxBytesReader(c.Writer, body, l.MaxRequestBodyBytes)
+	}
+	if l.BodyReadTimeout > 0 {
+		rc := http.NewResponseController(c.Writer)
+		if err := rc.SetReadDeadline(time.Now().Add(l.BodyReadTimeout)); err == nil {
+			defer func() {
+				_ = rc.SetReadDeadline(time.Time{})
+			}()
+		} else if !errors.Is(err, http.ErrNotSupported) {
+			klog.V(4).Infof("failed to set the read deadline of the request body: %v", err)
+		}
+	}
+
+	data, err := io.ReadAll(body)
+	if err == nil {
+		c.Request.Body = io.NopCloser(bytes.NewReader(data))
+		c.Request.ContentLength = int64(len(data))
+		return true
+	}
+	var maxBytesErr *http.MaxBytesError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &maxBytesErr):
+		abortBodyTooLarge(c, l.MaxRequestBodyBytes)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		metrics.DefaultMetrics.RecordRequestLimitExceeded(metrics.RequestLimitBodyReadTimeout)
+		// The rest of the body is still on the connection, it cannot be reused
+		c.Header("Connection", "close")
+		c.AbortWithStatusJSON(http.StatusRequestTimeout, "request body not received in time")
+	default:
+		c.AbortWithStatusJSON(http.StatusBadRequest, "failed to read the request body")
+	}
+	return false
+}
+
+func abortBodyTooLarge(c *gin.Context, limit int64) {
+	metrics.DefaultMetrics.RecordRequestLimitExceeded(metrics.RequestLimitBodyTooLarge)
+	c.Header("Connection", "close")
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+		"message":   "request body too large",
+		"max_bytes": limit,
+	})
+}
+
+// IdleTimeoutBody closes the body of an upstream response when no data is received for the timeout, so
+// that a stalled model server does not hold the stream forever. The reads then fail with ErrStreamIdleTimeout.
+// A zero timeout returns the body unchanged.
+func IdleTimeoutBody(body io.ReadCloser, timeout time.Duration) io.ReadCloser {
+	if timeout <= 0 {
+		return body
+	}
+	b := &idleTimeoutBody{ReadCloser: body}
+	b.timer = time.AfterFunc(timeout, b.expire)
+	b.timeout = timeout
+	return b
+}
+
+type idleTimeoutBody struct {
+	io.ReadCloser
+	timeout  time.Duration
+	timer    *time.Timer
+	timedOut atomic.Bool
+	once     sync.Once
+}
+
+func (b *idleTimeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if b.timedOut.Load() {
+		return n, ErrStreamIdleTimeout
+	}
+	if n > 0 {
+		b.timer.Reset(b.timeout)
+	}
+	return n, err
+}
+
+func (b *idleTimeoutBody) Close() error {
+	b.timer.Stop()
+	var err error
+	b.once.Do(func() {
+		err = b.ReadCloser.Close()
+	})
+	return err
+}
+
+func (b *idleTimeoutBody) expire() {
+	b.timedOut.Store(true)
+	metrics.DefaultMetrics.RecordRequestLimitExceeded(metrics.RequestLimitStreamIdleTimeout)
+	klog.V(2).Infof("closing an upstream response idle for %s", b.timeout)
+	b.once.Do(func() {
+		_ = b.ReadCloser.Close()
+	})
+}
@@ -0,0 +1,151 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limits
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLimitedServer(t *testing.T, limits *Limits, handler gin.HandlerFunc) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(limits.Middleware())
+	engine.POST("/v1/completions", handler)
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func echoBody(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.String(http.StatusOK, string(body))
+}
+
+func TestMiddlewareBodyLimits(t *testing.T) {
+	server := newLimitedServer(t, &Limits{MaxRequestBodyBytes: 16, BodyReadTimeout: 200 * time.Millisecond}, echoBody)
+
+	resp, err := http.Post(server.URL+"/v1/completions", "application/json", strings.NewReader(`{"model":"a"}`))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"model":"a"}`, string(body))
+
+	// Rejected from the Content-Length
+	resp, err = http.Post(server.URL+"/v1/completions", "application/json", strings.NewReader(strings.Repeat("a", 17)))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	// Rejected while reading a chunked body
+	resp, err = http.Post(server.URL+"/v1/completions", "application/json", io.MultiReader(strings.NewReader(strings.Repeat("a", 10)), strings.NewReader(strings.Repeat("a", 10))))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	// A client which does not send the announced body is timed out
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = fmt.Fprintf(conn, "POST /v1/completions HTTP/1.1\r\nHost: router\r\nContent-Length: 10\r\n\r\n{\"mo")
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	slowResp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	slowResp.Body.Close()
+	assert.Equal(t, http.StatusRequestTimeout, slowResp.StatusCode)
+	assert.True(t, slowResp.Close)
+}
+
+func TestMiddlewareRequestTimeout(t *testing.T) {
+	server := newLimitedServer(t, &Limits{RequestTimeout: 100 * time.Millisecond}, func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+	resp, err := http.Post(server.URL+"/v1/completions", "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+
+	// The responses already started are not changed
+	server = newLimitedServer(t, &Limits{RequestTimeout: 100 * time.Millisecond}, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		c.Writer.WriteHeaderNow()
+		<-c.Request.Context().Done()
+	})
+	resp, err = http.Post(server.URL+"/v1/completions", "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+type blockingBody struct {
+	data   chan string
+	closed chan struct{}
+}
+
+func (b *blockingBody) Read(p []byte) (int, error) {
+	select {
+	case s := <-b.data:
+		return copy(p, s), nil
+	case <-b.closed:
+		return 0, io.ErrUnexpectedEOF
+	}
+}
+
+func (b *blockingBody) Close() error {
+	close(b.closed)
+	return nil
+}
+
+func TestIdleTimeoutBody(t *testing.T) {
+	upstream := &blockingBody{data: make(chan string), closed: make(chan struct{})}
+	body := IdleTimeoutBody(upstream, 100*time.Millisecond)
+
+	// The data received in time keeps the body open
+	for i := 0; i < 3; i++ {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			upstream.data <- "data: chunk\n\n"
+		}()
+		n, err := body.Read(make([]byte, 64))
+		require.NoError(t, err)
+		assert.Equal(t, len("data: chunk\n\n"), n)
+	}
+
+	_, err := body.Read(make([]byte, 64))
+	assert.ErrorIs(t, err, ErrStreamIdleTimeout)
+	assert.NoError(t, body.Close())
+
+	plain := io.NopCloser(strings.NewReader(""))
+	assert.Equal(t, plain, IdleTimeoutBody(plain, 0))
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package metrics
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -36,6 +37,7 @@ const (
 	LabelModelRoute  = "model_route"
 	LabelModelServer = "model_server"
 	LabelUserID      = "user_id"
+	LabelReason      = "reason"
 
 	// Token type values
 	TokenTypeInput  = "input"
@@ -45,10 +47,43 @@ const (
 	PluginTypeFilter = "filter"
 	PluginTypeScore  = "score"
 
+	// Plugin skip reason values
+	PluginSkipReasonTimeout  = "timeout"
+	PluginSkipReasonDegraded = "degraded"
+	PluginSkipReasonDisabled = "disabled"
+
 	// Limit type values
-	LimitTypeInputTokens  = "input_tokens"
-	LimitTypeOutputTokens = "output_tokens"
-	LimitTypeRequests     = "requests"
+	LimitTypeInputTokens       = "input_tokens"
+	LimitTypeOutputTokens      = "output_tokens"
+	LimitTypeRequests          = "requests"
+	LimitTypeConcurrentStreams = "concurrent_streams"
+	LimitTypeConcurrentQueue   = "concurrent_requests_queue_full"
+	LimitTypeConcurrentWait    = "concurrent_requests_queue_timeout"
+
+	// Request limit reason values
+	RequestLimitBodyTooLarge      = "body_too_large"
+	RequestLimitBodyReadTimeout   = "body_read_timeout"
+	RequestLimitRequestTimeout    = "request_timeout"
+	RequestLimitStreamIdleTimeout = "stream_idle_timeout"
+
+	// Fallback reason values
+	FallbackReasonNoPods        = "no_pods"
+	FallbackReasonScheduling    = "scheduling"
+	FallbackReasonLatencyBudget = "latency_budget"
+
+	// Mirror target values
+	MirrorTargetPrimary = "primary"
+	MirrorTargetShadow  = "shadow"
+
+	// Mirror result values
+	MirrorResultSuccess = "success"
+	MirrorResultError   = "error"
+	MirrorResultDropped = "dropped"
+
+	// Compare result values
+	CompareResultExactMatch = "exact_match"
+	CompareResultMismatch   = "mismatch"
+	CompareResultFailed     = "failed"
 )
 
 // Metrics holds all Prometheus metrics for the kthena-router
@@ -66,15 +101,80 @@ type Metrics struct {
 
 	// Scheduler plugin duration metrics
 	SchedulerPluginDuration prometheus.HistogramVec
+	SchedulerPluginSkipped  prometheus.CounterVec
 
 	// Rate limiting metrics
 	RateLimitExceeded prometheus.CounterVec
 
+	// Request limits metrics
+	RequestLimitsExceeded prometheus.CounterVec
+
+	// Upstream connection pool metrics
+	UpstreamConnections        prometheus.GaugeVec
+	UpstreamConnectionRequests prometheus.CounterVec
+	UpstreamConnectionWait     prometheus.HistogramVec
+
 	// Request and scheduling metrics
 	ActiveDownstreamRequests prometheus.GaugeVec
 	ActiveUpstreamRequests   prometheus.GaugeVec
 	FairnessQueueSize        prometheus.GaugeVec
 	FairnessQueueDuration    prometheus.HistogramVec
+
+	// Concurrency limit metrics
+	ConcurrencyQueueSize     prometheus.GaugeVec
+	ConcurrencyQueueDuration prometheus.HistogramVec
+	ConcurrencySpillovers    prometheus.CounterVec
+
+	// Fallback chain metrics
+	Fallbacks prometheus.CounterVec
+
+	// Degraded mode metrics
+	TokenizationFailures    prometheus.CounterVec
+	KVCacheAffinityDegraded prometheus.GaugeVec
+
+	// Leader election metrics
+	Leader prometheus.Gauge
+
+	// Configuration reload metrics
+	ConfigVersion prometheus.Gauge
+	ConfigReloads prometheus.CounterVec
+
+	// Audit trail metrics
+	AuditRecords prometheus.CounterVec
+
+	// Guardrail metrics
+	GuardrailVerdicts prometheus.CounterVec
+
+	// Request hedging metrics
+	HedgedRequests       prometheus.CounterVec
+	HedgeDuplicateTokens prometheus.CounterVec
+
+	// Fault injection metrics
+	FaultsInjected prometheus.CounterVec
+
+	// Traffic mirroring metrics, of both the primary and the shadow model servers of the mirrored requests
+	MirroredRequests       prometheus.CounterVec
+	MirrorRequestDuration  prometheus.HistogramVec
+	MirrorTimeToFirstToken prometheus.HistogramVec
+	MirrorOutputTokens     prometheus.CounterVec
+
+	// Traffic compare metrics, of the samples compared by traffic compare and traffic mirroring
+	CompareSamples    prometheus.CounterVec
+	CompareSimilarity prometheus.HistogramVec
+
+	// Service level objective metrics
+	SLORequests prometheus.CounterVec
+
+	// Chargeback metrics
+	ChargebackRequests   prometheus.CounterVec
+	ChargebackTokens     prometheus.CounterVec
+	ChargebackGPUSeconds prometheus.CounterVec
+	ChargebackReports    prometheus.CounterVec
+
+	// Tenant metrics
+	TenantRequests      prometheus.CounterVec
+	TenantTokens        prometheus.CounterVec
+	TenantQuotaExceeded prometheus.CounterVec
 }
 
 // NewMetrics creates a new Metrics instance with all Prometheus metrics registered
@@ -132,6 +232,14 @@ func NewMetrics() *Metrics {
 			[]string{LabelModel, LabelPlugin, LabelType},
 		),
 
+		SchedulerPluginSkipped: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_scheduler_plugin_skipped_total",
+				Help: "Number of times a score plugin was left out of a scheduling decision, because it timed out or is degraded",
+			},
+			[]string{LabelModel, LabelPlugin, LabelReason},
+		),
+
 		RateLimitExceeded: *promauto.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "kthena_router_rate_limit_exceeded_total",
@@ -140,6 +248,39 @@ func NewMetrics() *Metrics {
 			[]string{LabelModel, LabelLimitType, LabelPath},
 		),
 
+		RequestLimitsExceeded: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_request_limits_exceeded_total",
+				Help: "Total number of requests cut short by the size limits and timeouts protecting the router and the model servers",
+			},
+			[]string{LabelReason}, // reason: body_too_large, body_read_timeout, request_timeout, stream_idle_timeout
+		),
+
+		UpstreamConnections: *promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "kthena_router_upstream_connections",
+				Help: "Number of connections open to the model server pods",
+			},
+			[]string{"protocol"}, // protocol: http1, h2c
+		),
+
+		UpstreamConnectionRequests: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_upstream_connection_requests_total",
+				Help: "Total number of requests sent to the model server pods, by whether they reused a pooled connection",
+			},
+			[]string{"protocol", "reused"},
+		),
+
+		UpstreamConnectionWait: *promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "kthena_router_upstream_connection_wait_seconds",
+				Help:    "Time the requests waited for a connection to a model server pod, dials included",
+				Buckets: []float64{0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5},
+			},
+			[]string{"protocol"},
+		),
+
 		ActiveDownstreamRequests: *promauto.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "kthena_router_active_downstream_requests",
@@ -172,6 +313,232 @@ func NewMetrics() *Metrics {
 			},
 			[]string{LabelModel, LabelUserID},
 		),
+
+		ConcurrencyQueueSize: *promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "kthena_router_concurrency_queue_size",
+				Help: "Current number of requests waiting for a slot of the concurrency limit of their model",
+			},
+			[]string{LabelModel},
+		),
+
+		ConcurrencyQueueDuration: *promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "kthena_router_concurrency_queue_duration_seconds",
+				Help:    "Time requests spend waiting for a slot of the concurrency limit of their model",
+				Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+			},
+			[]string{LabelModel},
+		),
+
+		ConcurrencySpillovers: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_concurrency_spillovers_total",
+				Help: "Number of requests sent to the fallback model because their model was at its concurrency limit",
+			},
+			[]string{LabelModel, "fallback_model"},
+		),
+
+		Fallbacks: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_fallbacks_total",
+				Help: "Number of requests served by the fallback chain of their route because their model was degraded",
+			},
+			[]string{LabelModel, "fallback_model", LabelReason}, // reason: no_pods, scheduling, latency_budget
+		),
+
+		TokenizationFailures: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_tokenization_failures_total",
+				Help: "Number of prompts the router failed to tokenize for KV-cache aware scoring",
+			},
+			[]string{LabelModel, LabelReason},
+		),
+
+		KVCacheAffinityDegraded: *promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "kthena_router_kvcache_affinity_degraded",
+				Help: "Whether KV-cache affinity is degraded for a model server because its prompts cannot be tokenized (1 = degraded)",
+			},
+			[]string{LabelModelServer},
+		),
+
+		Leader: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "kthena_router_leader",
+				Help: "Whether the router replica is the leader writing ModelRoute snapshots and ModelServer statuses (1 = leader)",
+			},
+		),
+
+		ConfigVersion: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "kthena_router_config_version",
+				Help: "Version of the router configuration being applied, incremented by each successful reload",
+			},
+		),
+
+		ConfigReloads: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_config_reloads_total",
+				Help: "Total number of router configuration reloads",
+			},
+			[]string{"result"}, // success, failure
+		),
+
+		AuditRecords: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_audit_records_total",
+				Help: "Total number of audit records by result",
+			},
+			[]string{"result"}, // written, failed, dropped
+		),
+
+		GuardrailVerdicts: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_guardrail_verdicts_total",
+				Help: "Total number of guardrail filter verdicts on the requests and responses",
+			},
+			[]string{LabelModel, "filter", "stage", "verdict"}, // verdict: passed, flagged, rejected, error
+		),
+
+		HedgedRequests: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_hedged_requests_total",
+				Help: "Total number of requests duplicated to a second pod by the winning pod",
+			},
+			[]string{LabelModelServer, "winner"}, // winner: primary, hedge, none
+		),
+
+		HedgeDuplicateTokens: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_hedge_duplicate_tokens_total",
+				Help: "Total number of prompt tokens processed by both pods of the hedged requests",
+			},
+			[]string{LabelModelServer},
+		),
+
+		FaultsInjected: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_faults_injected_total",
+				Help: "Total number of faults injected in the requests by the fault injection rules",
+			},
+			[]string{LabelModel, "fault"}, // fault: delay, abort, truncate
+		),
+
+		MirroredRequests: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_mirrored_requests_total",
+				Help: "Total number of mirrored requests served by the primary and the shadow model servers",
+			},
+			[]string{LabelModelRoute, LabelModelServer, "target", "result"}, // target: primary, shadow; result: success, error, dropped
+		),
+
+		MirrorRequestDuration: *promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "kthena_router_mirror_request_duration_seconds",
+				Help:    "End-to-end latency distribution of the mirrored requests on the primary and the shadow model servers",
+				Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+			},
+			[]string{LabelModelRoute, LabelModelServer, "target"},
+		),
+
+		MirrorTimeToFirstToken: *promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "kthena_router_mirror_time_to_first_token_seconds",
+				Help:    "Time to first token distribution of the mirrored requests on the primary and the shadow model servers",
+				Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+			},
+			[]string{LabelModelRoute, LabelModelServer, "target"},
+		),
+
+		MirrorOutputTokens: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_mirror_output_tokens_total",
+				Help: "Total number of tokens generated for the mirrored requests by the primary and the shadow model servers",
+			},
+			[]string{LabelModelRoute, LabelModelServer, "target"},
+		),
+
+		CompareSamples: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_compare_samples_total",
+				Help: "Total number of requests whose responses from two model servers were compared",
+			},
+			[]string{LabelModelRoute, "result"}, // result: exact_match, mismatch, failed
+		),
+
+		CompareSimilarity: *promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "kthena_router_compare_similarity",
+				Help:    "Distribution of the cosine similarity of the embeddings of the outputs of the compared model servers",
+				Buckets: []float64{0.5, 0.6, 0.7, 0.8, 0.85, 0.9, 0.95, 0.98, 0.99, 1},
+			},
+			[]string{LabelModelRoute},
+		),
+
+		SLORequests: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_slo_requests_total",
+				Help: "Total number of requests measured against the service level objectives of the model servers",
+			},
+			[]string{LabelModelServer, "slo", "result"}, // result: good, bad
+		),
+
+		ChargebackRequests: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_chargeback_requests_total",
+				Help: "Total number of requests attributed to each consumer, namespace and model",
+			},
+			[]string{"consumer", "namespace", LabelModel},
+		),
+
+		ChargebackTokens: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_chargeback_tokens_total",
+				Help: "Total number of tokens attributed to each consumer, namespace and model",
+			},
+			[]string{"consumer", "namespace", LabelModel, LabelTokenType}, // token_type: input, output
+		),
+
+		ChargebackGPUSeconds: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_chargeback_gpu_seconds_total",
+				Help: "Total GPU time in seconds attributed to each consumer, namespace and model",
+			},
+			[]string{"consumer", "namespace", LabelModel},
+		),
+
+		ChargebackReports: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_chargeback_reports_total",
+				Help: "Total number of chargeback reports by result",
+			},
+			[]string{"result"}, // written, failed
+		),
+
+		TenantRequests: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_tenant_requests_total",
+				Help: "Total number of requests served for each tenant of the router",
+			},
+			[]string{"tenant", LabelModel, LabelStatusCode},
+		),
+
+		TenantTokens: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_tenant_tokens_total",
+				Help: "Total number of tokens processed for each tenant of the router",
+			},
+			[]string{"tenant", LabelModel, LabelTokenType}, // token_type: input, output
+		),
+
+		TenantQuotaExceeded: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kthena_router_tenant_quota_exceeded_total",
+				Help: "Total number of requests rejected by the quotas of the tenants",
+			},
+			[]string{"tenant", "quota"}, // quota: requests, tokens
+		),
 	}
 }
 
@@ -206,11 +573,27 @@ func (m *Metrics) RecordRateLimitExceeded(model, limitType, path string) {
 	m.RateLimitExceeded.WithLabelValues(model, limitType, path).Inc()
 }
 
+// RecordRequestLimitExceeded records a request cut short by a size limit or a timeout
+func (m *Metrics) RecordRequestLimitExceeded(reason string) {
+	m.RequestLimitsExceeded.WithLabelValues(reason).Inc()
+}
+
+// RecordUpstreamConnection records a request given a connection to a model server pod after waiting for it
+func (m *Metrics) RecordUpstreamConnection(protocol string, reused bool, wait time.Duration) {
+	m.UpstreamConnectionRequests.WithLabelValues(protocol, strconv.FormatBool(reused)).Inc()
+	m.UpstreamConnectionWait.WithLabelValues(protocol).Observe(wait.Seconds())
+}
+
 // RecordSchedulerPluginDuration records the processing time for a specific scheduler plugin
 func (m *Metrics) RecordSchedulerPluginDuration(model, pluginName, pluginType string, duration time.Duration) {
 	m.SchedulerPluginDuration.WithLabelValues(model, pluginName, pluginType).Observe(duration.Seconds())
 }
 
+// RecordSchedulerPluginSkipped records a score plugin left out of a scheduling decision
+func (m *Metrics) RecordSchedulerPluginSkipped(model, pluginName, reason string) {
+	m.SchedulerPluginSkipped.WithLabelValues(model, pluginName, reason).Inc()
+}
+
 // SetActiveDownstreamRequests sets the current number of active downstream requests
 func (m *Metrics) SetActiveDownstreamRequests(model string, count float64) {
 	m.ActiveDownstreamRequests.WithLabelValues(model).Set(count)
@@ -261,6 +644,151 @@ func (m *Metrics) RecordFairnessQueueDuration(model, userID string, duration tim
 	m.FairnessQueueDuration.WithLabelValues(model, userID).Observe(duration.Seconds())
 }
 
+// IncConcurrencyQueueSize increments the number of requests waiting for a slot of the model
+func (m *Metrics) IncConcurrencyQueueSize(model string) {
+	m.ConcurrencyQueueSize.WithLabelValues(model).Inc()
+}
+
+// DecConcurrencyQueueSize decrements the number of requests waiting for a slot of the model
+func (m *Metrics) DecConcurrencyQueueSize(model string) {
+	m.ConcurrencyQueueSize.WithLabelValues(model).Dec()
+}
+
+// RecordConcurrencyQueueDuration records the time a request waited for a slot of the model
+func (m *Metrics) RecordConcurrencyQueueDuration(model string, duration time.Duration) {
+	m.ConcurrencyQueueDuration.WithLabelValues(model).Observe(duration.Seconds())
+}
+
+// RecordConcurrencySpillover records a request sent to the fallback model of its model
+func (m *Metrics) RecordConcurrencySpillover(model, fallbackModel string) {
+	m.ConcurrencySpillovers.WithLabelValues(model, fallbackModel).Inc()
+}
+
+// RecordFallback records a request served by a fallback model of its route
+func (m *Metrics) RecordFallback(model, fallbackModel, reason string) {
+	m.Fallbacks.WithLabelValues(model, fallbackModel, reason).Inc()
+}
+
+// RecordTokenizationFailure records a prompt that could not be tokenized
+func (m *Metrics) RecordTokenizationFailure(model, reason string) {
+	m.TokenizationFailures.WithLabelValues(model, reason).Inc()
+}
+
+// SetKVCacheAffinityDegraded sets whether KV-cache affinity is degraded for a model server
+func (m *Metrics) SetKVCacheAffinityDegraded(modelServer string, degraded bool) {
+	value := 0.0
+	if degraded {
+		value = 1
+	}
+	m.KVCacheAffinityDegraded.WithLabelValues(modelServer).Set(value)
+}
+
+// DeleteKVCacheAffinityDegraded removes the degraded gauge of a deleted model server
+func (m *Metrics) DeleteKVCacheAffinityDegraded(modelServer string) {
+	m.KVCacheAffinityDegraded.DeleteLabelValues(modelServer)
+}
+
+// SetLeader sets whether the router replica is the leader
+func (m *Metrics) SetLeader(leader bool) {
+	value := 0.0
+	if leader {
+		value = 1
+	}
+	m.Leader.Set(value)
+}
+
+// RecordConfigReload records a reload of the router configuration, and the version applied after it
+func (m *Metrics) RecordConfigReload(version int64, err error) {
+	if err != nil {
+		m.ConfigReloads.WithLabelValues("failure").Inc()
+		return
+	}
+	m.ConfigReloads.WithLabelValues("success").Inc()
+	m.ConfigVersion.Set(float64(version))
+}
+
+// RecordAuditRecords records audit records written to the audit sink, failed to be written or dropped
+func (m *Metrics) RecordAuditRecords(result string, count int) {
+	m.AuditRecords.WithLabelValues(result).Add(float64(count))
+}
+
+// RecordGuardrailVerdict records the verdict of a guardrail filter on the request or the response of a model
+func (m *Metrics) RecordGuardrailVerdict(model, filter, stage, verdict string) {
+	m.GuardrailVerdicts.WithLabelValues(model, filter, stage, verdict).Inc()
+}
+
+// RecordHedgedRequest records a request duplicated to a second pod and the prompt tokens processed twice
+func (m *Metrics) RecordHedgedRequest(modelServer, winner string, duplicateTokens int) {
+	m.HedgedRequests.WithLabelValues(modelServer, winner).Inc()
+	m.HedgeDuplicateTokens.WithLabelValues(modelServer).Add(float64(duplicateTokens))
+}
+
+// RecordFaultInjected records a fault injected in a request of a model
+func (m *Metrics) RecordFaultInjected(model, fault string) {
+	m.FaultsInjected.WithLabelValues(model, fault).Inc()
+}
+
+// RecordMirrorDropped records a request which was not mirrored to the shadow model server, as too many were in flight
+func (m *Metrics) RecordMirrorDropped(modelRoute, modelServer string) {
+	m.MirroredRequests.WithLabelValues(modelRoute, modelServer, MirrorTargetShadow, MirrorResultDropped).Inc()
+}
+
+// RecordMirrorResponse records the response of the primary or the shadow model server to a mirrored request. The
+// latencies and the tokens of the failed requests are not recorded.
+func (m *Metrics) RecordMirrorResponse(modelRoute, modelServer, target string, failed bool, duration, timeToFirstToken time.Duration, outputTokens int) {
+	if failed {
+		m.MirroredRequests.WithLabelValues(modelRoute, modelServer, target, MirrorResultError).Inc()
+		return
+	}
+	m.MirroredRequests.WithLabelValues(modelRoute, modelServer, target, MirrorResultSuccess).Inc()
+	m.MirrorRequestDuration.WithLabelValues(modelRoute, modelServer, target).Observe(duration.Seconds())
+	m.MirrorTimeToFirstToken.WithLabelValues(modelRoute, modelServer, target).Observe(timeToFirstToken.Seconds())
+	m.MirrorOutputTokens.WithLabelValues(modelRoute, modelServer, target).Add(float64(outputTokens))
+}
+
+// RecordCompareSample records the result of the comparison of the responses of two model servers, and the
+// similarity of their outputs when it was scored
+func (m *Metrics) RecordCompareSample(modelRoute, result string, similarity *float64) {
+	m.CompareSamples.WithLabelValues(modelRoute, result).Inc()
+	if similarity != nil {
+		m.CompareSimilarity.WithLabelValues(modelRoute).Observe(*similarity)
+	}
+}
+
+// RecordSLORequest records whether a request met a service level objective of its model server
+func (m *Metrics) RecordSLORequest(modelServer, slo string, met bool) {
+	result := "bad"
+	if met {
+		result = "good"
+	}
+	m.SLORequests.WithLabelValues(modelServer, slo, result).Inc()
+}
+
+// RecordChargeback records the tokens and the GPU time of a request attributed to its consumer
+func (m *Metrics) RecordChargeback(consumer, namespace, model string, inputTokens, outputTokens int, gpuSeconds float64) {
+	m.ChargebackRequests.WithLabelValues(consumer, namespace, model).Inc()
+	m.ChargebackTokens.WithLabelValues(consumer, namespace, model, TokenTypeInput).Add(float64(inputTokens))
+	m.ChargebackTokens.WithLabelValues(consumer, namespace, model, TokenTypeOutput).Add(float64(outputTokens))
+	m.ChargebackGPUSeconds.WithLabelValues(consumer, namespace, model).Add(gpuSeconds)
+}
+
+// RecordTenantRequest records a request served for a tenant, with its tokens
+func (m *Metrics) RecordTenantRequest(tenant, model, statusCode string, inputTokens, outputTokens int) {
+	m.TenantRequests.WithLabelValues(tenant, model, statusCode).Inc()
+	m.TenantTokens.WithLabelValues(tenant, model, TokenTypeInput).Add(float64(inputTokens))
+	m.TenantTokens.WithLabelValues(tenant, model, TokenTypeOutput).Add(float64(outputTokens))
+}
+
+// RecordTenantQuotaExceeded records a request rejected by a quota of its tenant
+func (m *Metrics) RecordTenantQuotaExceeded(tenant, quota string) {
+	m.TenantQuotaExceeded.WithLabelValues(tenant, quota).Inc()
+}
+
+// RecordChargebackReport records a chargeback report written to the sink or failed to be written
+func (m *Metrics) RecordChargebackReport(result string) {
+	m.ChargebackReports.WithLabelValues(result).Inc()
+}
+
 // RequestMetricsRecorder is a helper struct to record detailed metrics for individual requests
 type RequestMetricsRecorder struct {
 	metrics          *Metrics
@@ -347,6 +875,11 @@ func (r *RequestMetricsRecorder) RecordSchedulerPluginDuration(pluginName, plugi
 	r.metrics.RecordSchedulerPluginDuration(r.model, pluginName, pluginType, duration)
 }
 
+// RecordSchedulerPluginSkipped records a score plugin left out of the scheduling decision of the request
+func (r *RequestMetricsRecorder) RecordSchedulerPluginSkipped(pluginName, reason string) {
+	r.metrics.RecordSchedulerPluginSkipped(r.model, pluginName, reason)
+}
+
 // RecordFairnessQueueDuration records the time spent in fairness queue
 func (r *RequestMetricsRecorder) RecordFairnessQueueDuration(userID string, duration time.Duration) {
 	r.metrics.RecordFairnessQueueDuration(r.model, userID, duration)
@@ -0,0 +1,151 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistence
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultRedisKey is the Redis hash holding the learned states.
+	DefaultRedisKey = "kthena-router:learned-state"
+	// DefaultConfigMapName is the ConfigMap holding the learned states.
+	DefaultConfigMapName = "kthena-router-learned-state"
+
+	// redisTTL expires the states of a router which is gone, they would be too stale to be restored.
+	redisTTL = 24 * time.Hour
+	// maxConfigMapSize is the size of the states kept in a ConfigMap, the API server rejects objects over 1MiB.
+	maxConfigMapSize = 1000 * 1024
+)
+
+// RedisBackend stores the learned states as the fields of a Redis hash.
+type RedisBackend struct {
+	client *redis.Client
+	key    string
+}
+
+func NewRedisBackend(client *redis.Client, key string) *RedisBackend {
+	if key == "" {
+		key = DefaultRedisKey
+	}
+	return &RedisBackend{client: client, key: key}
+}
+
+func (b *RedisBackend) Load(ctx context.Context) (map[string][]byte, error) {
+	fields, err := b.client.HGetAll(ctx, b.key).Result()
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string][]byte, len(fields))
+	for name, data := range fields {
+		states[name] = []byte(data)
+	}
+	return states, nil
+}
+
+func (b *RedisBackend) Save(ctx context.Context, states map[string][]byte) error {
+	_, err := b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, b.key)
+		for name, data := range states {
+			pipe.HSet(ctx, b.key, name, data)
+		}
+		pipe.Expire(ctx, b.key, redisTTL)
+		return nil
+	})
+	return err
+}
+
+// ConfigMapBackend stores the learned states as the binary data of a ConfigMap.
+type ConfigMapBackend struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	name       string
+}
+
+func NewConfigMapBackend(kubeClient kubernetes.Interface, namespace, name string) *ConfigMapBackend {
+	if name == "" {
+		name = DefaultConfigMapName
+	}
+	return &ConfigMapBackend{kubeClient: kubeClient, namespace: namespace, name: name}
+}
+
+func (b *ConfigMapBackend) Load(ctx context.Context) (map[string][]byte, error) {
+	cm, err := b.kubeClient.CoreV1().ConfigMaps(b.namespace).Get(ctx, b.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cm.BinaryData, nil
+}
+
+// Save replaces the data of the ConfigMap. The states which do not fit in a ConfigMap are left out,
+// the largest ones first.
+func (b *ConfigMapBackend) Save(ctx context.Context, states map[string][]byte) error {
+	data := make(map[string][]byte, len(states))
+	size := 0
+	for _, name := range bySize(states) {
+		if size+len(states[name]) > maxConfigMapSize {
+			klog.Warningf("Learned state %s of %d bytes does not fit in ConfigMap %s/%s, it is not saved, use the redis backend",
+				name, len(states[name]), b.namespace, b.name)
+			continue
+		}
+		size += len(states[name])
+		data[name] = states[name]
+	}
+
+	cms := b.kubeClient.CoreV1().ConfigMaps(b.namespace)
+	cm, err := cms.Get(ctx, b.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = cms.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: b.name, Namespace: b.namespace},
+			BinaryData: data,
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	cm.BinaryData = data
+	_, err = cms.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// bySize returns the names of the states, from the smallest to the largest.
+func bySize(states map[string][]byte) []string {
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(states[names[i]]) != len(states[names[j]]) {
+			return len(states[names[i]]) < len(states[names[j]])
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
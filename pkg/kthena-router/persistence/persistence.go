@@ -0,0 +1,164 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package persistence snapshots the learned states of the router datastore, e.g. the prompt prefixes cached by
+// each pod, to Redis or a ConfigMap, and restores them when a router replica starts, so that it does not route
+// blindly while it learns them again.
+package persistence
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+// DefaultInterval is the default interval between two snapshots.
+const DefaultInterval = time.Minute
+
+// saveTimeout bounds the last snapshot taken on shutdown.
+const saveTimeout = 5 * time.Second
+
+// Backend stores the snapshots of the learned states, each under its name.
+type Backend interface {
+	// Load returns the last saved states, empty when there is none.
+	Load(ctx context.Context) (map[string][]byte, error)
+	// Save replaces the saved states.
+	Save(ctx context.Context, states map[string][]byte) error
+}
+
+// Persister saves the learned states of the store periodically, and restores them on start.
+// The states are compressed, the prefix index of large deployments is several megabytes.
+type Persister struct {
+	store    datastore.Store
+	backend  Backend
+	interval time.Duration
+
+	// restored is set once the states have been restored, the states of a replica which has not restored
+	// them yet would overwrite the snapshot with nearly empty ones.
+	restored atomic.Bool
+}
+
+func NewPersister(store datastore.Store, backend Backend, interval time.Duration) *Persister {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Persister{
+		store:    store,
+		backend:  backend,
+		interval: interval,
+	}
+}
+
+// Restore loads the saved states into the registered learned states. It must be called once the store
+// has synced, the states of the pods and model servers which are gone are dropped.
+func (p *Persister) Restore(ctx context.Context) error {
+	defer p.restored.Store(true)
+	saved, err := p.backend.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load the learned states: %w", err)
+	}
+	for _, state := range p.store.LearnedStates() {
+		compressed, ok := saved[state.Name()]
+		if !ok {
+			continue
+		}
+		data, err := decompress(compressed)
+		if err != nil {
+			klog.Warningf("Ignoring invalid learned state %s: %v", state.Name(), err)
+			continue
+		}
+		if err := state.Restore(data); err != nil {
+			klog.Warningf("Failed to restore learned state %s: %v", state.Name(), err)
+			continue
+		}
+		klog.Infof("Restored learned state %s", state.Name())
+	}
+	return nil
+}
+
+// Save snapshots the registered learned states.
+func (p *Persister) Save(ctx context.Context) error {
+	states := make(map[string][]byte)
+	for _, state := range p.store.LearnedStates() {
+		data, err := state.Save()
+		if err != nil {
+			klog.Warningf("Failed to save learned state %s: %v", state.Name(), err)
+			continue
+		}
+		compressed, err := compress(data)
+		if err != nil {
+			return err
+		}
+		states[state.Name()] = compressed
+	}
+	return p.backend.Save(ctx, states)
+}
+
+// Run saves the learned states every interval until the context is done, and once more before returning.
+func (p *Persister) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.save(ctx)
+		case <-ctx.Done():
+			saveCtx, cancel := context.WithTimeout(context.Background(), saveTimeout)
+			defer cancel()
+			p.save(saveCtx)
+			return nil
+		}
+	}
+}
+
+func (p *Persister) save(ctx context.Context) {
+	if !p.restored.Load() {
+		klog.V(4).Info("Learned states not restored yet, skipping the snapshot")
+		return
+	}
+	if err := p.Save(ctx); err != nil {
+		klog.Errorf("Failed to snapshot the learned states: %v", err)
+	}
+}
+
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
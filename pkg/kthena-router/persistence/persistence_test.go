@@ -0,0 +1,141 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+type fakeState struct {
+	name     string
+	data     []byte
+	restored []byte
+}
+
+func (f *fakeState) Name() string          { return f.name }
+func (f *fakeState) Save() ([]byte, error) { return f.data, nil }
+func (f *fakeState) Restore(data []byte) error {
+	f.restored = data
+	return nil
+}
+
+type memoryBackend struct {
+	states map[string][]byte
+	saves  atomic.Int32
+}
+
+func (m *memoryBackend) Load(ctx context.Context) (map[string][]byte, error) {
+	return m.states, nil
+}
+
+func (m *memoryBackend) Save(ctx context.Context, states map[string][]byte) error {
+	m.states = states
+	m.saves.Add(1)
+	return nil
+}
+
+func TestPersisterSaveRestore(t *testing.T) {
+	backend := &memoryBackend{}
+	old := datastore.New()
+	old.RegisterState(&fakeState{name: "prefix-cache", data: []byte("prefixes")})
+	require.NoError(t, NewPersister(old, backend, time.Minute).Save(context.Background()))
+	assert.Contains(t, backend.states, "prefix-cache")
+	assert.NotEqual(t, []byte("prefixes"), backend.states["prefix-cache"], "the states are compressed")
+
+	restarted := datastore.New()
+	state := &fakeState{name: "prefix-cache"}
+	restarted.RegisterState(state)
+	other := &fakeState{name: "other"}
+	restarted.RegisterState(other)
+	require.NoError(t, NewPersister(restarted, backend, time.Minute).Restore(context.Background()))
+	assert.Equal(t, []byte("prefixes"), state.restored)
+	assert.Nil(t, other.restored, "the states missing from the snapshot are left as they are")
+}
+
+func TestPersisterRunWaitsForRestore(t *testing.T) {
+	backend := &memoryBackend{}
+	store := datastore.New()
+	store.RegisterState(&fakeState{name: "prefix-cache", data: []byte("prefixes")})
+	p := NewPersister(store, backend, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = p.Run(ctx)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, backend.saves.Load(), "nothing is saved before the states are restored")
+
+	require.NoError(t, p.Restore(context.Background()))
+	assert.Eventually(t, func() bool { return backend.saves.Load() > 0 }, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
+
+func TestRedisBackend(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	backend := NewRedisBackend(client, "")
+	ctx := context.Background()
+
+	states, err := backend.Load(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, states)
+
+	require.NoError(t, backend.Save(ctx, map[string][]byte{"a": []byte("1"), "b": []byte("2")}))
+	require.NoError(t, backend.Save(ctx, map[string][]byte{"a": []byte("3")}))
+	states, err = backend.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"a": []byte("3")}, states)
+	assert.Equal(t, redisTTL, server.TTL(DefaultRedisKey))
+}
+
+func TestConfigMapBackend(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	backend := NewConfigMapBackend(client, "kthena-system", "")
+	ctx := context.Background()
+
+	states, err := backend.Load(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, states)
+
+	require.NoError(t, backend.Save(ctx, map[string][]byte{"a": []byte("1")}))
+	// The states which do not fit are left out
+	large := bytes.Repeat([]byte("x"), maxConfigMapSize)
+	require.NoError(t, backend.Save(ctx, map[string][]byte{"a": []byte("2"), "b": large}))
+	states, err = backend.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"a": []byte("2")}, states)
+
+	cm, err := client.CoreV1().ConfigMaps("kthena-system").Get(ctx, DefaultConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Len(t, cm.BinaryData, 1)
+}
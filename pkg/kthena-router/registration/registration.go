@@ -0,0 +1,258 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registration registers ModelRoutes in the datastore of the router before they are received from the
+// API server, so that a new model is routed as soon as its ModelRoute is admitted. The registered routes are
+// provisional: they are replaced by the ModelRoute once the informer delivers it, and removed when it is not
+// delivered within their time to live.
+package registration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+const (
+	// DefaultTTL is how long a registered route is kept without being delivered by the informer.
+	DefaultTTL = 2 * time.Minute
+	// MaxTTL bounds the time to live requested for a registration.
+	MaxTTL = time.Hour
+
+	cleanupInterval = time.Second
+)
+
+// ErrConflict is returned when the route is already served from the API server, or its model by another route.
+var ErrConflict = errors.New("conflict")
+
+// Registration is a provisional route.
+type Registration struct {
+	Namespace    string    `json:"namespace"`
+	Name         string    `json:"name"`
+	ModelName    string    `json:"modelName,omitempty"`
+	LoraAdapters []string  `json:"loraAdapters,omitempty"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+type entry struct {
+	route     *networkingv1alpha1.ModelRoute
+	expiresAt time.Time
+}
+
+// Registry holds the provisional routes of the router.
+type Registry struct {
+	store datastore.Store
+
+	mu     sync.Mutex
+	routes map[string]*entry
+}
+
+// New creates a registry of provisional routes in the store. The routes are confirmed, and forgotten by the
+// registry, when the informer adds the ModelRoute of the same name to the store.
+func New(store datastore.Store) *Registry {
+	r := &Registry{
+		store:  store,
+		routes: make(map[string]*entry),
+	}
+	store.RegisterCallback("ModelRoute", r.onModelRoute)
+	return r
+}
+
+// Register validates the route optimistically against the datastore and adds it to the store for ttl.
+// Registering a route again extends its time to live and replaces its spec.
+func (r *Registry) Register(route *networkingv1alpha1.ModelRoute, ttl time.Duration) (*Registration, error) {
+	if ttl <= 0 || ttl > MaxTTL {
+		return nil, field.Invalid(field.NewPath("ttl"), ttl.String(), fmt.Sprintf("must be in the range of (0, %s]", MaxTTL))
+	}
+	if errs := r.validate(route); len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+	key := route.Namespace + "/" + route.Name
+
+	r.mu.Lock()
+	existing := r.store.GetModelRoute(key)
+	if previous, ok := r.routes[key]; existing != nil && (!ok || previous.route != existing) {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%w: ModelRoute %s is already synced from the API server", ErrConflict, key)
+	}
+	if other := r.routeOfModel(route, key); other != "" {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%w: model %s is already routed by ModelRoute %s", ErrConflict, route.Spec.ModelName, other)
+	}
+	e := &entry{route: route, expiresAt: time.Now().Add(ttl)}
+	r.routes[key] = e
+	r.mu.Unlock()
+
+	// The store is updated outside of the lock, its callbacks lock the registry
+	if err := r.store.AddOrUpdateModelRoute(route); err != nil {
+		r.forget(key, e)
+		return nil, err
+	}
+	klog.Infof("Registered ModelRoute %s for model %q until %s", key, route.Spec.ModelName, e.expiresAt.Format(time.RFC3339))
+	return registrationOf(route, e), nil
+}
+
+// Unregister removes a provisional route from the store. It returns false if the route is not provisional.
+func (r *Registry) Unregister(namespace, name string) bool {
+	key := namespace + "/" + name
+	r.mu.Lock()
+	e, ok := r.routes[key]
+	r.mu.Unlock()
+	if !ok {
+		return false
+	}
+	r.remove(key, e)
+	klog.Infof("Unregistered ModelRoute %s", key)
+	return true
+}
+
+// List returns the provisional routes, sorted by namespace and name.
+func (r *Registry) List() []*Registration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	registrations := make([]*Registration, 0, len(r.routes))
+	for _, e := range r.routes {
+		registrations = append(registrations, registrationOf(e.route, e))
+	}
+	sort.Slice(registrations, func(i, j int) bool {
+		if registrations[i].Namespace != registrations[j].Namespace {
+			return registrations[i].Namespace < registrations[j].Namespace
+		}
+		return registrations[i].Name < registrations[j].Name
+	})
+	return registrations
+}
+
+// Run removes the expired routes until the context is done.
+func (r *Registry) Run(ctx context.Context) {
+	wait.UntilWithContext(ctx, func(context.Context) {
+		r.expire(time.Now())
+	}, cleanupInterval)
+}
+
+func (r *Registry) expire(now time.Time) {
+	r.mu.Lock()
+	expired := make(map[string]*entry)
+	for key, e := range r.routes {
+		if !now.Before(e.expiresAt) {
+			expired[key] = e
+		}
+	}
+	r.mu.Unlock()
+	for key, e := range expired {
+		klog.Infof("ModelRoute %s was not synced from the API server within its time to live, removing it", key)
+		r.remove(key, e)
+	}
+}
+
+// remove deletes the route from the store, unless it has been replaced by the informer in the meantime.
+func (r *Registry) remove(key string, e *entry) {
+	if !r.forget(key, e) {
+		return
+	}
+	if r.store.GetModelRoute(key) == e.route {
+		_ = r.store.DeleteModelRoute(key)
+	}
+}
+
+// forget drops the entry of the route, it returns false if the entry has been replaced or confirmed.
+func (r *Registry) forget(key string, e *entry) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.routes[key] != e {
+		return false
+	}
+	delete(r.routes, key)
+	return true
+}
+
+// onModelRoute confirms the provisional route once the informer has added the ModelRoute of the same name.
+func (r *Registry) onModelRoute(data datastore.EventData) {
+	if data.ModelRoute == nil || (data.EventType != datastore.EventAdd && data.EventType != datastore.EventUpdate) {
+		return
+	}
+	key := data.ModelRoute.Namespace + "/" + data.ModelRoute.Name
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// The callbacks run asynchronously, the store tells whether the route is still the registered one
+	if e, ok := r.routes[key]; ok && e.route != data.ModelRoute && r.store.GetModelRoute(key) != e.route {
+		delete(r.routes, key)
+		klog.Infof("ModelRoute %s is synced from the API server, its registration is confirmed", key)
+	}
+}
+
+// validate checks that the route is complete and that its model servers are known to the router.
+func (r *Registry) validate(route *networkingv1alpha1.ModelRoute) field.ErrorList {
+	var errs field.ErrorList
+	if route.Namespace == "" || route.Name == "" {
+		errs = append(errs, field.Required(field.NewPath("metadata"), "namespace and name are required"))
+	}
+	specPath := field.NewPath("spec")
+	if route.Spec.ModelName == "" && len(route.Spec.LoraAdapters) == 0 {
+		errs = append(errs, field.Required(specPath.Child("modelName"), "modelName or loraAdapters is required"))
+	}
+	if len(route.Spec.Rules) == 0 {
+		errs = append(errs, field.Required(specPath.Child("rules"), "at least one rule is required"))
+	}
+	for i, rule := range route.Spec.Rules {
+		rulePath := specPath.Child("rules").Index(i)
+		if rule == nil || len(rule.TargetModels) == 0 {
+			errs = append(errs, field.Required(rulePath.Child("targetModels"), "at least one target model is required"))
+			continue
+		}
+		for j, target := range rule.TargetModels {
+			name := types.NamespacedName{Namespace: route.Namespace, Name: target.ModelServerName}
+			if r.store.GetModelServer(name) == nil {
+				errs = append(errs, field.NotFound(rulePath.Child("targetModels").Index(j).Child("modelServerName"), target.ModelServerName))
+			}
+		}
+	}
+	return errs
+}
+
+// routeOfModel returns the other route serving the model of the route, if any. It is called with the lock held.
+func (r *Registry) routeOfModel(route *networkingv1alpha1.ModelRoute, key string) string {
+	if route.Spec.ModelName == "" {
+		return ""
+	}
+	for other, mr := range r.store.GetAllModelRoutes() {
+		if other != key && mr.Spec.ModelName == route.Spec.ModelName {
+			return other
+		}
+	}
+	return ""
+}
+
+func registrationOf(route *networkingv1alpha1.ModelRoute, e *entry) *Registration {
+	return &Registration{
+		Namespace:    route.Namespace,
+		Name:         route.Name,
+		ModelName:    route.Spec.ModelName,
+		LoraAdapters: route.Spec.LoraAdapters,
+		ExpiresAt:    e.expiresAt,
+	}
+}
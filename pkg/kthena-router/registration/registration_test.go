@@ -0,0 +1,120 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"istio.io/istio/pkg/util/sets"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+func newStore(t *testing.T) datastore.Store {
+	store := datastore.New()
+	ms := &networkingv1alpha1.ModelServer{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "llama"}}
+	require.NoError(t, store.AddOrUpdateModelServer(ms, sets.New[types.NamespacedName]()))
+	return store
+}
+
+func newRoute(name, model, modelServer string) *networkingv1alpha1.ModelRoute {
+	return &networkingv1alpha1.ModelRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: networkingv1alpha1.ModelRouteSpec{
+			ModelName: model,
+			Rules: []*networkingv1alpha1.Rule{{
+				TargetModels: []*networkingv1alpha1.TargetModel{{ModelServerName: modelServer}},
+			}},
+		},
+	}
+}
+
+func TestRegister(t *testing.T) {
+	store := newStore(t)
+	registry := New(store)
+
+	route := newRoute("llama", "llama-3", "llama")
+	registered, err := registry.Register(route, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "llama-3", registered.ModelName)
+	assert.Same(t, route, store.GetModelRoute("default/llama"))
+	assert.Len(t, registry.List(), 1)
+
+	// Registering again extends the registration
+	_, err = registry.Register(newRoute("llama", "llama-3", "llama"), time.Minute)
+	require.NoError(t, err)
+
+	_, err = registry.Register(newRoute("other", "llama-3", "llama"), time.Minute)
+	assert.ErrorIs(t, err, ErrConflict, "the model is routed by another route")
+
+	_, err = registry.Register(newRoute("missing", "mistral", "mistral"), time.Minute)
+	assert.Error(t, err, "the model server is unknown")
+	assert.NotErrorIs(t, err, ErrConflict)
+
+	_, err = registry.Register(newRoute("mistral", "mistral", "llama"), 2*MaxTTL)
+	assert.Error(t, err, "the ttl is too long")
+
+	assert.True(t, registry.Unregister("default", "llama"))
+	assert.Nil(t, store.GetModelRoute("default/llama"))
+	assert.False(t, registry.Unregister("default", "llama"))
+}
+
+func TestRegisterSyncedRoute(t *testing.T) {
+	store := newStore(t)
+	registry := New(store)
+	require.NoError(t, store.AddOrUpdateModelRoute(newRoute("llama", "llama-3", "llama")))
+
+	_, err := registry.Register(newRoute("llama", "llama-3", "llama"), time.Minute)
+	assert.ErrorIs(t, err, ErrConflict)
+}
+
+func TestRegistrationConfirmed(t *testing.T) {
+	store := newStore(t)
+	registry := New(store)
+	_, err := registry.Register(newRoute("llama", "llama-3", "llama"), time.Minute)
+	require.NoError(t, err)
+
+	synced := newRoute("llama", "llama-3", "llama")
+	require.NoError(t, store.AddOrUpdateModelRoute(synced))
+	assert.Eventually(t, func() bool {
+		return len(registry.List()) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// The synced route is not removed once the registration expires
+	registry.expire(time.Now().Add(time.Hour))
+	assert.Same(t, synced, store.GetModelRoute("default/llama"))
+}
+
+func TestRegistrationExpired(t *testing.T) {
+	store := newStore(t)
+	registry := New(store)
+	_, err := registry.Register(newRoute("llama", "llama-3", "llama"), time.Minute)
+	require.NoError(t, err)
+
+	registry.expire(time.Now())
+	assert.NotNil(t, store.GetModelRoute("default/llama"))
+
+	registry.expire(time.Now().Add(time.Minute))
+	assert.Nil(t, store.GetModelRoute("default/llama"))
+	assert.Empty(t, registry.List())
+}
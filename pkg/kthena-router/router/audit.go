@@ -0,0 +1,69 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/audit"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+)
+
+// auditPromptKey is the key of the prompt of a request in the gin context, set when the model records the prompts.
+const auditPromptKey = "audit_prompt"
+
+// setAuditPrompt keeps the prompt of the request if its model records the prompts in the audit trail.
+func (r *Router) setAuditPrompt(c *gin.Context, modelName, prompt string) {
+	if _, includePrompt := r.config.Load().auditor.Audited(modelName); includePrompt {
+		c.Set(auditPromptKey, prompt)
+	}
+}
+
+// audit records a completed request in the audit trail, if its model is audited. It runs after the access
+// log middleware, which holds the metadata of the request.
+func (r *Router) audit(c *gin.Context) {
+	ctx := accesslog.GetAccessLogContext(c)
+	if ctx == nil || ctx.ModelName == "" {
+		return
+	}
+	auditor := r.config.Load().auditor
+	if audited, _ := auditor.Audited(ctx.ModelName); !audited {
+		return
+	}
+	entry := ctx.ToAccessLogEntry(c.Writer.Status())
+	record := &audit.Record{
+		Timestamp:    entry.Timestamp,
+		RequestID:    entry.RequestID,
+		User:         c.GetString(common.UserIdKey),
+		Method:       entry.Method,
+		Path:         entry.Path,
+		StatusCode:   entry.StatusCode,
+		Model:        entry.ModelName,
+		ModelRoute:   entry.ModelRoute,
+		ModelServer:  entry.ModelServer,
+		Pod:          entry.SelectedPod,
+		InputTokens:  entry.InputTokens,
+		OutputTokens: entry.OutputTokens,
+		DurationMs:   entry.DurationTotal,
+		Prompt:       c.GetString(auditPromptKey),
+	}
+	if entry.Error != nil {
+		record.Error = entry.Error.Type + ": " + entry.Error.Message
+	}
+	auditor.Audit(record)
+}
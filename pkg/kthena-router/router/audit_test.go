@@ -0,0 +1,67 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/audit"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+func TestAudit(t *testing.T) {
+	r := newConfigTestRouter(t, "")
+	r.accessLogger, _ = accesslog.NewAccessLogger(&accesslog.AccessLoggerConfig{Enabled: false})
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditor, err := audit.New(conf.AuditConfig{
+		Models: []conf.AuditModel{{Name: "llama", IncludePrompt: true}},
+		Sink:   conf.AuditSink{Type: "file", File: conf.AuditFileSink{Path: path}},
+	})
+	require.NoError(t, err)
+	r.config.Load().auditor = auditor
+
+	engine := gin.New()
+	engine.Use(r.AccessLog())
+	engine.POST("/v1/chat/completions", func(c *gin.Context) {
+		model := c.Query("model")
+		c.Set(common.UserIdKey, "alice")
+		accesslog.SetModelName(c, model)
+		r.setAuditPrompt(c, model, "hello")
+		c.Status(http.StatusOK)
+	})
+	for _, model := range []string{"llama", "qwen"} {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/chat/completions?model="+model, nil))
+	}
+	require.NoError(t, auditor.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"user":"alice"`)
+	assert.Contains(t, string(data), `"model":"llama"`)
+	assert.Contains(t, string(data), `"prompt":"hello"`)
+	assert.NotContains(t, string(data), "qwen", "the model is not audited")
+}
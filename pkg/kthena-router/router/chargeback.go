@@ -0,0 +1,79 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/chargeback"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+// gpuShareKey is the key of the GPUs attributed to the request in the gin context, set once it is scheduled.
+const gpuShareKey = "chargeback_gpu_share"
+
+// setGPUShare keeps the GPUs of the pods serving the request attributed to it, shared with the requests
+// running on the pods when it is scheduled. Both the prefill and the decode pod serve a disaggregated request.
+func (r *Router) setGPUShare(c *gin.Context, ctx *framework.Context) {
+	if r.config.Load().chargeback == nil {
+		return
+	}
+	share := 0.0
+	for _, pods := range [][]*datastore.PodInfo{ctx.BestPods, ctx.PrefillPods, ctx.DecodePods} {
+		if len(pods) > 0 && pods[0] != nil {
+			share += chargeback.GPUShare(pods[0].Pod, pods[0].RequestRunningNum)
+		}
+	}
+	c.Set(gpuShareKey, share)
+}
+
+// recordChargeback attributes the tokens and the GPU time of a completed request to its consumer. It runs
+// after the access log middleware, which holds the metadata of the request. The requests which were not
+// routed to a model server used no GPU and are not recorded.
+func (r *Router) recordChargeback(c *gin.Context) {
+	ctx := accesslog.GetAccessLogContext(c)
+	if ctx == nil || ctx.ModelName == "" || ctx.ModelServer == "" {
+		return
+	}
+	reporter := r.config.Load().chargeback
+	if reporter == nil {
+		return
+	}
+	namespace, _, _ := strings.Cut(ctx.ModelServer, "/")
+	usage := chargeback.Usage{
+		Consumer:     reporter.Consumer(c.Request.Header, c.GetString(common.UserIdKey)),
+		Namespace:    namespace,
+		Model:        ctx.ModelName,
+		InputTokens:  ctx.InputTokens,
+		OutputTokens: ctx.OutputTokens,
+	}
+	if share, ok := c.Get(gpuShareKey); ok && !ctx.UpstreamStart.IsZero() {
+		// The end of the disaggregated requests is not marked, their response is complete by now
+		end := ctx.UpstreamEnd
+		if end.IsZero() {
+			end = time.Now()
+		}
+		usage.GPUSeconds = share.(float64) * end.Sub(ctx.UpstreamStart).Seconds()
+	}
+	reporter.Record(usage)
+}
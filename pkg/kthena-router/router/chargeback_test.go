@@ -0,0 +1,84 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/chargeback"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+func TestRecordChargeback(t *testing.T) {
+	r := newConfigTestRouter(t, "")
+	r.accessLogger, _ = accesslog.NewAccessLogger(&accesslog.AccessLoggerConfig{Enabled: false})
+	dir := t.TempDir()
+	reporter, err := chargeback.New(conf.ChargebackConfig{
+		Enabled: true,
+		Sink:    conf.ChargebackSink{Type: "file", File: conf.ChargebackFileSink{Directory: dir}},
+	})
+	require.NoError(t, err)
+	r.config.Load().chargeback = reporter
+
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("2")}},
+	}}}}
+	engine := gin.New()
+	engine.Use(r.AccessLog())
+	engine.POST("/v1/chat/completions", func(c *gin.Context) {
+		model := c.Query("model")
+		accesslog.SetModelName(c, model)
+		if model == "llama" {
+			r.setGPUShare(c, &framework.Context{BestPods: []*datastore.PodInfo{{Pod: pod, RequestRunningNum: 1}}})
+			accesslog.SetRequestRouting(c, "", "team-a/llama", "llama-0")
+			accesslog.MarkUpstreamStart(c)
+			accesslog.SetTokenCounts(c, 10, 20)
+			accesslog.MarkUpstreamEnd(c)
+		}
+		c.Status(http.StatusOK)
+	})
+	// The request to qwen was not routed to a model server
+	for _, model := range []string{"llama", "qwen"} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions?model="+model, nil)
+		req.Header.Set("X-API-Key", "sk-team-a")
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	require.NoError(t, reporter.Close())
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(dir + "/" + files[0].Name())
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], ","+chargeback.APIKeyFingerprint("sk-team-a")+",team-a,llama,1,10,20,")
+	assert.NotContains(t, string(data), "qwen")
+}
@@ -0,0 +1,199 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"istio.io/istio/pkg/env"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/audit"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/compare"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/utils"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
+)
+
+var (
+	trafficCompareMaxSamples     = env.RegisterIntVar("TRAFFIC_COMPARE_MAX_SAMPLES", 1000, "Number of compared requests kept for each ModelRoute").Get()
+	trafficCompareMaxConcurrency = env.RegisterIntVar("TRAFFIC_COMPARE_MAX_CONCURRENCY", 16, "Maximum number of sampled requests replayed at the same time").Get()
+	trafficCompareTimeout        = env.RegisterDurationVar("TRAFFIC_COMPARE_TIMEOUT", 2*time.Minute, "Timeout of a request replayed for traffic compare").Get()
+	trafficCompareMaxBodyBytes   = env.RegisterIntVar("TRAFFIC_COMPARE_MAX_BODY_BYTES", 1<<20, "Largest response body read from a model server for traffic compare").Get()
+	trafficCompareEmbeddingURL   = env.RegisterStringVar("TRAFFIC_COMPARE_EMBEDDING_URL", "", "OpenAI compatible embeddings endpoint scoring the similarity of the compared outputs, disabled when empty").Get()
+	trafficCompareEmbeddingModel = env.RegisterStringVar("TRAFFIC_COMPARE_EMBEDDING_MODEL", "", "Model of the embeddings requests scoring the similarity of the compared outputs").Get()
+	trafficCompareExportDir      = env.RegisterStringVar("TRAFFIC_COMPARE_EXPORT_DIR", "", "Directory the compared samples are written to as JSON lines, e.g. a mounted object storage bucket, disabled when empty").Get()
+	trafficCompareExportS3       = env.RegisterStringVar("TRAFFIC_COMPARE_EXPORT_S3", "", "S3 compatible bucket the compared samples are written to as JSON lines, as <endpoint>/<bucket>[/<prefix>], disabled when empty").Get()
+	trafficCompareExportS3Region = env.RegisterStringVar("TRAFFIC_COMPARE_EXPORT_S3_REGION", "us-east-1", "Region of the bucket the compared samples are written to").Get()
+	trafficCompareExportBatch    = env.RegisterIntVar("TRAFFIC_COMPARE_EXPORT_BATCH_SIZE", 100, "Number of compared samples of a ModelRoute written to a single file").Get()
+	trafficCompareExportInterval = env.RegisterDurationVar("TRAFFIC_COMPARE_EXPORT_INTERVAL", time.Minute, "Longest time a compared sample waits to be written").Get()
+)
+
+func newComparator(metricsInstance *metrics.Metrics) *compare.Comparator {
+	config := &compare.Config{
+		MaxSamples:     trafficCompareMaxSamples,
+		MaxConcurrency: trafficCompareMaxConcurrency,
+		Timeout:        trafficCompareTimeout,
+		MaxBodyBytes:   trafficCompareMaxBodyBytes,
+		Exporters:      []compare.Exporter{&compareMetricsExporter{metrics: metricsInstance}},
+	}
+	if trafficCompareEmbeddingURL != "" {
+		config.Embedder = compare.NewEmbedder(trafficCompareEmbeddingURL, trafficCompareEmbeddingModel)
+	}
+	if trafficCompareExportDir != "" {
+		config.Exporters = append(config.Exporters,
+			compare.NewBatchExporter(compare.DirWriter(trafficCompareExportDir), trafficCompareExportBatch, trafficCompareExportInterval))
+	}
+	if trafficCompareExportS3 != "" {
+		writer, err := s3BatchWriter(trafficCompareExportS3, trafficCompareExportS3Region)
+		if err != nil {
+			klog.Errorf("Traffic compare samples are not exported to S3: %v", err)
+		} else {
+			config.Exporters = append(config.Exporters, compare.NewBatchExporter(writer, trafficCompareExportBatch, trafficCompareExportInterval))
+		}
+	}
+	return compare.NewComparator(config)
+}
+
+// s3BatchWriter writes the batches of compared samples to the bucket at <endpoint>/<bucket>[/<prefix>], with the
+// credentials of the audit sink.
+func s3BatchWriter(location, region string) (compare.BatchWriter, error) {
+	u, err := url.Parse(location)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 location %q", location)
+	}
+	bucket, prefix, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	s3Bucket, err := audit.NewS3Bucket(conf.AuditS3Sink{
+		Endpoint: u.Scheme + "://" + u.Host,
+		Region:   region,
+		Bucket:   bucket,
+		Prefix:   prefix,
+	}, "traffic compare")
+	if err != nil {
+		return nil, err
+	}
+	return func(key string, data []byte) error {
+		ctx, cancel := context.WithTimeout(context.Background(), trafficCompareTimeout)
+		defer cancel()
+		return s3Bucket.Put(ctx, key, "application/x-ndjson", data, time.Now())
+	}, nil
+}
+
+// compareMetricsExporter records the compared samples in the router metrics.
+type compareMetricsExporter struct {
+	metrics *metrics.Metrics
+}
+
+func (e *compareMetricsExporter) Export(modelRoute string, sample *compare.Sample) error {
+	result := metrics.CompareResultMismatch
+	switch {
+	case !sample.Succeeded():
+		result = metrics.CompareResultFailed
+	case sample.ExactMatch():
+		result = metrics.CompareResultExactMatch
+	}
+	e.metrics.RecordCompareSample(modelRoute, result, sample.Similarity)
+	return nil
+}
+
+func (e *compareMetricsExporter) Close() error {
+	return nil
+}
+
+// CompareStore returns the samples recorded for routes with traffic compare enabled.
+func (r *Router) CompareStore() *compare.Store {
+	return r.comparator.Store()
+}
+
+// handleTrafficCompare replays a sample of the non-streaming generation requests of a route
+// to its baseline and candidate model servers. The replay happens out-of-band and never
+// affects the response returned to the client.
+func (r *Router) handleTrafficCompare(c *gin.Context, modelRoute *v1alpha1.ModelRoute, modelRequest ModelRequest, isLora bool) {
+	if modelRoute == nil || modelRoute.Spec.TrafficCompare == nil {
+		return
+	}
+	if isStreaming(modelRequest) || !utils.GetRequestType(c.Request.URL.Path).IsGenerative() {
+		return
+	}
+	trafficCompare := modelRoute.Spec.TrafficCompare
+	samplePercent := uint32(1)
+	if trafficCompare.SamplePercent != nil {
+		samplePercent = *trafficCompare.SamplePercent
+	}
+	if !compare.Sampled(samplePercent) {
+		return
+	}
+
+	modelRouteName := fmt.Sprintf("%s/%s", modelRoute.Namespace, modelRoute.Name)
+	baseline, err := r.compareTarget(c, modelRoute.Namespace, trafficCompare.BaselineModelServerName, modelRequest, isLora)
+	if err != nil {
+		klog.Errorf("failed to resolve baseline of traffic compare for model route %s: %v", modelRouteName, err)
+		return
+	}
+	candidate, err := r.compareTarget(c, modelRoute.Namespace, trafficCompare.CandidateModelServerName, modelRequest, isLora)
+	if err != nil {
+		klog.Errorf("failed to resolve candidate of traffic compare for model route %s: %v", modelRouteName, err)
+		return
+	}
+	model, _ := modelRequest["model"].(string)
+	r.comparator.Compare(modelRouteName, c.Request.Header.Get("x-request-id"), model, baseline, candidate)
+}
+
+// compareTarget picks a pod of the model server and builds the request replayed to it.
+func (r *Router) compareTarget(c *gin.Context, namespace, name string, modelRequest ModelRequest, isLora bool) (compare.Target, error) {
+	modelServerName := types.NamespacedName{Namespace: namespace, Name: name}
+	pods, modelServer, err := r.getPodsAndServer(modelServerName)
+	if err != nil {
+		return compare.Target{}, err
+	}
+	if modelServer.Spec.WorkloadSelector != nil && modelServer.Spec.WorkloadSelector.PDGroup != nil {
+		return compare.Target{}, fmt.Errorf("model server %s is prefill/decode disaggregated, which is not supported", modelServerName)
+	}
+
+	request := make(ModelRequest, len(modelRequest))
+	for k, v := range modelRequest {
+		request[k] = v
+	}
+	if modelServer.Spec.Model != nil && !isLora {
+		request["model"] = *modelServer.Spec.Model
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return compare.Target{}, err
+	}
+
+	pod := pods[rand.Intn(len(pods))].Pod
+	return compare.Target{
+		ModelServer: modelServerName.String(),
+		Pod:         pod.Name,
+		URL:         "http://" + netutil.HostPort(pod.Status.PodIP, modelServer.Spec.WorkloadPort.Port) + c.Request.URL.RequestURI(),
+		Body:        body,
+	}, nil
+}
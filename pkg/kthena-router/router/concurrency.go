@@ -0,0 +1,81 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/concurrency"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+)
+
+// acquireConcurrency reserves a slot of the concurrency limit of the requested model, waiting in its queue if
+// needed. A request which can not be queued, or waited too long, spills over to the fallback model of the
+// route, whose own limit applies. The model of the request is then rewritten to the fallback model, which is
+// returned in the served model header.
+// It returns false when the request has been rejected, the release function must be called otherwise.
+func (r *Router) acquireConcurrency(c *gin.Context, modelRequest ModelRequest, metricsRecorder *metrics.RequestMetricsRecorder) (func(), bool) {
+	modelName := modelRequest["model"].(string)
+	limiter := r.concurrency.Get(modelName)
+	if limiter == nil {
+		return func() {}, true
+	}
+	release, err := limiter.Acquire(c.Request.Context())
+	if err == nil {
+		return release, true
+	}
+
+	if fallback := limiter.Fallback(); fallback != "" && c.Request.Context().Err() == nil {
+		release, fallbackErr := func() {}, error(nil)
+		if fallbackLimiter := r.concurrency.Get(fallback); fallbackLimiter != nil {
+			release, fallbackErr = fallbackLimiter.Acquire(c.Request.Context())
+		}
+		if fallbackErr == nil {
+			klog.V(4).Infof("model %s is at its concurrency limit, the request spills over to %s", modelName, fallback)
+			r.metrics.RecordConcurrencySpillover(modelName, fallback)
+			c.Header(servedModelHeader, fallback)
+			modelRequest["model"] = fallback
+			return release, true
+		}
+		err = fallbackErr
+	}
+
+	accesslog.SetError(c, "concurrency_limit", err.Error())
+	if c.Request.Context().Err() != nil {
+		// The client is gone, there is no one to respond to
+		c.Abort()
+		metricsRecorder.Finish(strconv.Itoa(c.Writer.Status()), "concurrency_limit")
+		return nil, false
+	}
+	limitType := metrics.LimitTypeConcurrentQueue
+	if errors.Is(err, concurrency.ErrQueueTimeout) {
+		limitType = metrics.LimitTypeConcurrentWait
+	}
+	metricsRecorder.RecordRateLimitExceeded(limitType)
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": gin.H{
+		"type":    "concurrency_limit",
+		"message": err.Error(),
+	}})
+	metricsRecorder.Finish(strconv.Itoa(http.StatusTooManyRequests), "concurrency_limit")
+	return nil, false
+}
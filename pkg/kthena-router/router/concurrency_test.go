@@ -0,0 +1,76 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/concurrency"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+)
+
+func TestRouter_AcquireConcurrency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	noQueue := uint32(0)
+	limiters := concurrency.NewLimiters()
+	limiters.AddOrUpdateLimiter("llama", &aiv1alpha1.ConcurrencyLimit{MaxConcurrentRequests: 1, MaxQueueSize: &noQueue, FallbackModel: "llama-small"})
+	limiters.AddOrUpdateLimiter("llama-small", &aiv1alpha1.ConcurrencyLimit{MaxConcurrentRequests: 1, MaxQueueSize: &noQueue})
+	router := &Router{concurrency: limiters, metrics: metrics.DefaultMetrics}
+
+	acquire := func(model string) (*httptest.ResponseRecorder, ModelRequest, func(), bool) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+		modelRequest := ModelRequest{"model": model}
+		release, ok := router.acquireConcurrency(c, modelRequest, metrics.NewRequestMetricsRecorder(metrics.DefaultMetrics, model, "/v1/chat/completions"))
+		return w, modelRequest, release, ok
+	}
+
+	_, modelRequest, releaseFirst, ok := acquire("llama")
+	require.True(t, ok)
+	assert.Equal(t, "llama", modelRequest["model"])
+
+	// The model is at its limit, the request spills over to the fallback model
+	_, modelRequest, releaseSecond, ok := acquire("llama")
+	require.True(t, ok)
+	assert.Equal(t, "llama-small", modelRequest["model"])
+
+	// Both are at their limit
+	w, _, _, ok := acquire("llama")
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), "concurrency_limit")
+
+	releaseFirst()
+	releaseSecond()
+	_, modelRequest, release, ok := acquire("llama")
+	require.True(t, ok)
+	assert.Equal(t, "llama", modelRequest["model"])
+	release()
+
+	// Models without a limit are not limited
+	_, _, release, ok = acquire("mistral")
+	assert.True(t, ok)
+	release()
+}
@@ -0,0 +1,229 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/audit"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/chargeback"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/auth"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/tenancy"
+)
+
+// configReloadDelay groups the events of a single update of the configuration file, a ConfigMap volume
+// is updated by swapping a symlink, which produces several events.
+const configReloadDelay = 500 * time.Millisecond
+
+// configState is the part of the router built from the configuration file.
+type configState struct {
+	// version is incremented by each reload applied
+	version int64
+	data    []byte
+	config  *conf.RouterConfiguration
+
+	scheduler     scheduler.Scheduler
+	authenticator *auth.JWTAuthenticator
+	authorizer    *auth.ModelAuthorizer
+	auditor       *audit.Auditor
+	chargeback    *chargeback.Reporter
+	tenants       *tenancy.Tenants
+}
+
+// loadConfig builds the first configuration of the router.
+func loadConfig(store datastore.Store, path string) (*configState, error) {
+	config, err := conf.ParseRouterConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	// The content is compared on reload to skip the events which don't change the file
+	data, _ := os.ReadFile(path)
+	auditor, err := audit.New(config.Audit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit configuration: %w", err)
+	}
+	reporter, err := chargeback.New(config.Chargeback)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chargeback configuration: %w", err)
+	}
+	tenants, err := tenancy.New(config, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tenants configuration: %w", err)
+	}
+	return &configState{
+		version:       1,
+		data:          data,
+		config:        config,
+		scheduler:     scheduler.NewScheduler(store, config),
+		authenticator: auth.NewJWTAuthenticator(config),
+		authorizer:    auth.NewModelAuthorizer(config),
+		auditor:       auditor,
+		chargeback:    reporter,
+		tenants:       tenants,
+	}, nil
+}
+
+// ReloadConfig reads the configuration file again and applies it if it changed. The new configuration is
+// validated and built before it replaces the current one at once, the requests being served keep the
+// configuration they started with. An invalid configuration is rejected and the current one is kept.
+//
+// The scheduler is rebuilt, so the prefix cache starts empty and the plugins toggled through the admin API
+// are enabled again. The JWKS are only fetched again when the authentication configuration changed, and the
+// audit and chargeback sinks are only reopened when their configuration changed.
+func (r *Router) ReloadConfig() error {
+	current := r.config.Load()
+	data, err := os.ReadFile(r.configPath)
+	if err != nil {
+		return r.recordConfigReload(current, fmt.Errorf("failed to read config file %s: %w", r.configPath, err))
+	}
+	if bytes.Equal(data, current.data) {
+		return nil
+	}
+	config, err := conf.UnmarshalRouterConfig(data)
+	if err != nil {
+		return r.recordConfigReload(current, err)
+	}
+	if err := scheduler.ValidateConfig(config); err != nil {
+		return r.recordConfigReload(current, fmt.Errorf("invalid scheduler configuration: %w", err))
+	}
+	if err := auth.ValidateAccessControl(config.Access); err != nil {
+		return r.recordConfigReload(current, err)
+	}
+	// The quotas of the tenants are carried over unless they changed
+	tenants, err := tenancy.New(config, current.tenants)
+	if err != nil {
+		return r.recordConfigReload(current, fmt.Errorf("invalid tenants configuration: %w", err))
+	}
+
+	next := &configState{
+		version:       current.version + 1,
+		data:          data,
+		config:        config,
+		authenticator: current.authenticator,
+		auditor:       current.auditor,
+		chargeback:    current.chargeback,
+		tenants:       tenants,
+	}
+	auditChanged := !reflect.DeepEqual(config.Audit, current.config.Audit)
+	if auditChanged {
+		if next.auditor, err = audit.New(config.Audit); err != nil {
+			return r.recordConfigReload(current, fmt.Errorf("invalid audit configuration: %w", err))
+		}
+	}
+	chargebackChanged := !reflect.DeepEqual(config.Chargeback, current.config.Chargeback)
+	if chargebackChanged {
+		if next.chargeback, err = chargeback.New(config.Chargeback); err != nil {
+			next.close(false, auditChanged, false)
+			return r.recordConfigReload(current, fmt.Errorf("invalid chargeback configuration: %w", err))
+		}
+	}
+	next.scheduler = scheduler.NewScheduler(r.store, config)
+	next.authorizer = auth.NewModelAuthorizer(config)
+	authChanged := !reflect.DeepEqual(config.Auth, current.config.Auth)
+	if authChanged {
+		next.authenticator = auth.NewJWTAuthenticator(config)
+	}
+	if !r.config.CompareAndSwap(current, next) {
+		// Reloads are serialized by the watcher, this only happens if ReloadConfig is called concurrently.
+		next.close(authChanged, auditChanged, chargebackChanged)
+		return fmt.Errorf("the configuration was reloaded concurrently")
+	}
+	// The records of the requests still being served with the previous auditor are lost, and so is the
+	// usage of those served with the previous chargeback reporter once its last report is written
+	current.close(authChanged, auditChanged, chargebackChanged)
+	return r.recordConfigReload(next, nil)
+}
+
+// close releases the authenticator, the auditor and the chargeback reporter of the configuration if they are replaced.
+func (s *configState) close(authenticator, auditor, reporter bool) {
+	if authenticator {
+		s.authenticator.Close()
+	}
+	if auditor {
+		if err := s.auditor.Close(); err != nil {
+			klog.Errorf("Failed to close the audit sink: %v", err)
+		}
+	}
+	if reporter {
+		if err := s.chargeback.Close(); err != nil {
+			klog.Errorf("Failed to close the chargeback sink: %v", err)
+		}
+	}
+}
+
+func (r *Router) recordConfigReload(state *configState, err error) error {
+	r.metrics.RecordConfigReload(state.version, err)
+	if err != nil {
+		klog.Errorf("Failed to reload router config, keeping version %d: %v", state.version, err)
+		return err
+	}
+	klog.Infof("Reloaded router config %s, version %d", r.configPath, state.version)
+	return nil
+}
+
+// WatchConfig reloads the configuration whenever the configuration file changes, until the context is done.
+// The directory of the file is watched, as the file of a ConfigMap volume is replaced rather than written.
+func (r *Router) WatchConfig(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	defer watcher.Close()
+	dir := filepath.Dir(r.configPath)
+	if err := watcher.Add(dir); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+	klog.Infof("Watching router config %s", r.configPath)
+
+	reload := time.NewTimer(configReloadDelay)
+	reload.Stop()
+	defer reload.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// The file itself, or the ..data symlink of a ConfigMap volume, changed
+			if filepath.Clean(event.Name) == filepath.Clean(r.configPath) || filepath.Base(event.Name) == "..data" {
+				reload.Reset(configReloadDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			klog.Errorf("Router config watcher error: %v", err)
+		case <-reload.C:
+			// The error is logged and counted, the current configuration is kept
+			_ = r.ReloadConfig()
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+)
+
+func schedulerConfig(scorePlugin string, weight int) string {
+	return `scheduler:
+  plugins:
+    Filter:
+      enabled:
+        - least-request
+    Score:
+      enabled:
+        - name: ` + scorePlugin + `
+          weight: ` + strconv.Itoa(weight) + `
+`
+}
+
+func newConfigTestRouter(t *testing.T, config string) *Router {
+	path := filepath.Join(t.TempDir(), "routerConfiguration.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o644))
+	store := datastore.New()
+	state, err := loadConfig(store, path)
+	require.NoError(t, err)
+	r := &Router{configPath: path, store: store, metrics: metrics.DefaultMetrics}
+	r.config.Store(state)
+	return r
+}
+
+func TestReloadConfig(t *testing.T) {
+	r := newConfigTestRouter(t, schedulerConfig("least-request", 1))
+	initial := r.config.Load()
+	assert.Equal(t, int64(1), initial.version)
+
+	// An unchanged file is not applied again
+	require.NoError(t, r.ReloadConfig())
+	assert.Same(t, initial, r.config.Load())
+
+	// An invalid configuration is rejected, the current one is kept. The validation of the scheduler
+	// plugins is covered by the scheduler tests, as the scheduler configuration is stubbed in TestMain.
+	for _, config := range []string{
+		"scheduler: [",
+		"access:\n  defaultAction: maybe\n",
+		"tenants:\n  - name: team-a\n",
+	} {
+		require.NoError(t, os.WriteFile(r.configPath, []byte(config), 0o644))
+		assert.Error(t, r.ReloadConfig(), config)
+		assert.Same(t, initial, r.config.Load())
+	}
+
+	require.NoError(t, os.WriteFile(r.configPath, []byte(schedulerConfig("least-latency", 2)), 0o644))
+	require.NoError(t, r.ReloadConfig())
+	reloaded := r.config.Load()
+	assert.Equal(t, int64(2), reloaded.version)
+	assert.NotSame(t, initial.scheduler, reloaded.scheduler)
+	assert.Same(t, initial.authenticator, reloaded.authenticator, "the authentication configuration didn't change")
+	assert.Equal(t, "least-latency", reloaded.config.Scheduler.Plugins.Score.Enabled[0].Name)
+	assert.Equal(t, 2, reloaded.config.Scheduler.Plugins.Score.Enabled[0].Weight)
+}
+
+func TestWatchConfig(t *testing.T) {
+	r := newConfigTestRouter(t, schedulerConfig("least-request", 1))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- r.WatchConfig(ctx)
+	}()
+
+	// Wait for the watcher to be started
+	time.Sleep(100 * time.Millisecond)
+	// The file is replaced like the files of a ConfigMap volume
+	tmp := r.configPath + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte(schedulerConfig("least-latency", 1)), 0o644))
+	require.NoError(t, os.Rename(tmp, r.configPath))
+	assert.Eventually(t, func() bool { return r.config.Load().version == 2 }, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, "least-latency", r.config.Load().config.Scheduler.Plugins.Score.Enabled[0].Name)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"istio.io/istio/pkg/env"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+// SchedulingDecisionHeader explains the scheduling decision of a request in its response.
+const SchedulingDecisionHeader = "x-kthena-scheduling-decision"
+
+var (
+	schedulingDecisionsPerModel     = env.RegisterIntVar("SCHEDULING_DECISIONS_PER_MODEL", 100, "Number of scheduling decisions kept for each model, 0 disables recording them").Get()
+	schedulingDecisionHeaderEnabled = env.RegisterBoolVar("SCHEDULING_DECISION_HEADER_ENABLED", false, "Whether to explain the scheduling decision in the "+SchedulingDecisionHeader+" response header").Get()
+)
+
+// DecisionStore returns the last scheduling decisions of each model.
+func (r *Router) DecisionStore() *scheduler.DecisionStore {
+	return r.decisions
+}
+
+// newDecision returns the decision to record while scheduling a request, or nil if
+// the decisions are neither kept nor returned in the response.
+func (r *Router) newDecision(c *gin.Context, modelName string, modelServerName types.NamespacedName) *framework.Decision {
+	if !r.decisions.Enabled() && !schedulingDecisionHeaderEnabled {
+		return nil
+	}
+	return &framework.Decision{
+		RequestID:   c.Request.Header.Get("x-request-id"),
+		Model:       modelName,
+		ModelServer: modelServerName.String(),
+		Time:        time.Now(),
+	}
+}
+
+// recordDecision keeps the scheduling decision of a request and explains it in the response
+// header if enabled. The decision must not be modified afterwards.
+func (r *Router) recordDecision(c *gin.Context, decision *framework.Decision, err error) {
+	if decision == nil {
+		return
+	}
+	if err != nil {
+		decision.Error = err.Error()
+	}
+	r.decisions.Add(decision)
+	if schedulingDecisionHeaderEnabled && err == nil {
+		c.Header(SchedulingDecisionHeader, decisionHeader(decision))
+	}
+}
+
+// decisionHeader summarizes a decision as the selected pod, its final score and
+// the score given by each plugin, e.g. `pod=default/vllm-0; score=4200; kvcache-aware=60; least-request=40`.
+func decisionHeader(decision *framework.Decision) string {
+	pod := decision.Selected()
+	parts := []string{"pod=" + pod}
+	if len(decision.Rounds) > 0 {
+		round := decision.Rounds[0]
+		parts = append(parts, fmt.Sprintf("score=%d", round.Scores[pod]))
+		for _, plugin := range round.Plugins {
+			parts = append(parts, fmt.Sprintf("%s=%d", plugin.Plugin, plugin.Scores[pod]))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,302 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/utils"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
+)
+
+const (
+	// DestinationEndpointKey is the header and the metadata key the gateway reads the picked endpoints from,
+	// as specified by the endpoint picker protocol of the Gateway API Inference Extension.
+	DestinationEndpointKey = "x-gateway-destination-endpoint"
+	// destinationEndpointNamespace is the namespace of the dynamic metadata holding the picked endpoints.
+	destinationEndpointNamespace = "envoy.lb"
+)
+
+// extProcError is a request the gateway is told to answer itself, instead of forwarding it.
+type extProcError struct {
+	code    int
+	message string
+}
+
+func (e *extProcError) Error() string {
+	return e.message
+}
+
+// ExtProcServer picks the endpoints of the requests of an Envoy based gateway, through the external processing
+// filter of Envoy, as the endpoint picker of the Gateway API Inference Extension. The gateway proxies the requests
+// itself, Kthena only schedules them with its plugins.
+//
+// The filter must send the request body in the BUFFERED mode. The endpoints are returned in the
+// x-gateway-destination-endpoint header of the request, the first one only, and in the metadata of the
+// envoy.lb namespace, in the order they should be tried. The model of the body is rewritten as it would be by the
+// router, and the post schedule hooks of the plugins are run once the picked endpoint has answered successfully.
+type ExtProcServer struct {
+	extprocv3.UnimplementedExternalProcessorServer
+	router *Router
+}
+
+// ExtProcServer returns the external processing server scheduling the requests with the plugins of the router.
+func (r *Router) ExtProcServer() *ExtProcServer {
+	return &ExtProcServer{router: r}
+}
+
+// extProcStream is the state of the request processed by a stream.
+type extProcStream struct {
+	req  *http.Request
+	body []byte
+	// scheduler and ctx are set once the request is scheduled, for the post schedule hooks
+	scheduler scheduler.Scheduler
+	ctx       *framework.Context
+}
+
+// Process handles the messages of an HTTP request, from its headers to its response.
+func (s *ExtProcServer) Process(srv extprocv3.ExternalProcessor_ProcessServer) error {
+	stream := &extProcStream{}
+	for {
+		req, err := srv.Recv()
+		if err == io.EOF || status.Code(err) == codes.Canceled {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Unknown, "cannot receive the processing request: %v", err)
+		}
+
+		var resp *extprocv3.ProcessingResponse
+		switch v := req.Request.(type) {
+		case *extprocv3.ProcessingRequest_RequestHeaders:
+			stream.req = requestOfHeaders(v.RequestHeaders.GetHeaders())
+			resp = &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_RequestHeaders{
+				RequestHeaders: &extprocv3.HeadersResponse{},
+			}}
+		case *extprocv3.ProcessingRequest_RequestBody:
+			stream.body = append(stream.body, v.RequestBody.GetBody()...)
+			if !v.RequestBody.GetEndOfStream() {
+				resp = &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_RequestBody{
+					RequestBody: &extprocv3.BodyResponse{},
+				}}
+				break
+			}
+			resp = s.schedule(stream)
+		case *extprocv3.ProcessingRequest_ResponseHeaders:
+			if stream.ctx != nil && successful(v.ResponseHeaders.GetHeaders()) {
+				stream.scheduler.RunPostHooks(stream.ctx, 0)
+			}
+			resp = &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_ResponseHeaders{
+				ResponseHeaders: &extprocv3.HeadersResponse{},
+			}}
+		case *extprocv3.ProcessingRequest_ResponseBody:
+			resp = &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_ResponseBody{
+				ResponseBody: &extprocv3.BodyResponse{},
+			}}
+		case *extprocv3.ProcessingRequest_RequestTrailers:
+			resp = &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_RequestTrailers{
+				RequestTrailers: &extprocv3.TrailersResponse{},
+			}}
+		case *extprocv3.ProcessingRequest_ResponseTrailers:
+			resp = &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_ResponseTrailers{
+				ResponseTrailers: &extprocv3.TrailersResponse{},
+			}}
+		default:
+			return status.Errorf(codes.Unimplemented, "unknown processing request %T", v)
+		}
+
+		if err := srv.Send(resp); err != nil {
+			return status.Errorf(codes.Unknown, "cannot send the processing response: %v", err)
+		}
+	}
+}
+
+// schedule picks the endpoints of the buffered request, or answers it with an error.
+func (s *ExtProcServer) schedule(stream *extProcStream) *extprocv3.ProcessingResponse {
+	body, endpoints, err := s.pick(stream)
+	if err != nil {
+		var e *extProcError
+		if !errors.As(err, &e) {
+			e = &extProcError{code: http.StatusInternalServerError, message: err.Error()}
+		}
+		klog.V(4).Infof("External processing request rejected with %d: %s", e.code, e.message)
+		return &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_ImmediateResponse{
+			ImmediateResponse: &extprocv3.ImmediateResponse{
+				Status: &typev3.HttpStatus{Code: typev3.StatusCode(e.code)},
+				Body:   []byte(e.message),
+			},
+		}}
+	}
+
+	metadata, err := structpb.NewStruct(map[string]interface{}{
+		destinationEndpointNamespace: map[string]interface{}{
+			DestinationEndpointKey: strings.Join(endpoints, ","),
+		},
+	})
+	if err != nil {
+		klog.Errorf("Failed to build the metadata of the picked endpoints: %v", err)
+	}
+	return &extprocv3.ProcessingResponse{
+		Response: &extprocv3.ProcessingResponse_RequestBody{RequestBody: &extprocv3.BodyResponse{
+			Response: &extprocv3.CommonResponse{
+				HeaderMutation: &extprocv3.HeaderMutation{SetHeaders: []*corev3.HeaderValueOption{
+					{Header: &corev3.HeaderValue{Key: DestinationEndpointKey, RawValue: []byte(endpoints[0])}},
+					{Header: &corev3.HeaderValue{Key: "Content-Length", RawValue: []byte(strconv.Itoa(len(body)))}},
+				}},
+				BodyMutation: &extprocv3.BodyMutation{Mutation: &extprocv3.BodyMutation_Body{Body: body}},
+			},
+		}},
+		DynamicMetadata: metadata,
+	}
+}
+
+// pick schedules the request like the router does, and returns its rewritten body and the addresses of the
+// picked pods.
+func (s *ExtProcServer) pick(stream *extProcStream) ([]byte, []string, error) {
+	if stream.req == nil {
+		return nil, nil, &extProcError{code: http.StatusBadRequest, message: "request headers not received"}
+	}
+	var modelRequest ModelRequest
+	if err := json.Unmarshal(stream.body, &modelRequest); err != nil {
+		return nil, nil, &extProcError{code: http.StatusBadRequest, message: fmt.Sprintf("invalid request body: %v", err)}
+	}
+	modelName, ok := modelRequest["model"].(string)
+	if !ok {
+		return nil, nil, &extProcError{code: http.StatusNotFound, message: "model not found"}
+	}
+
+	r := s.router
+	modelServerName, isLora, _, adapter, err := r.matchModelServer(modelName, stream.req)
+	if err != nil {
+		return nil, nil, &extProcError{code: http.StatusNotFound, message: fmt.Sprintf("can't find corresponding model server: %v", err)}
+	}
+	pods, modelServer, err := r.getPodsAndServer(modelServerName)
+	if err != nil {
+		return nil, nil, &extProcError{code: http.StatusServiceUnavailable, message: err.Error()}
+	}
+	if adapter != "" {
+		modelName = adapter
+		modelRequest["model"] = adapter
+	}
+	if model := modelServer.Spec.Model; model != nil && !isLora {
+		modelRequest["model"] = *model
+	}
+
+	requestType := utils.GetRequestType(stream.req.URL.Path)
+	// A single endpoint is returned to the gateway, which cannot send the prefill and the decode to different pods
+	if modelServer.Spec.WorkloadSelector != nil && modelServer.Spec.WorkloadSelector.PDGroup != nil && requestType.IsGenerative() {
+		return nil, nil, &extProcError{code: http.StatusNotImplemented,
+			message: fmt.Sprintf("model server %s is disaggregated, which is not supported through external processing", modelServerName)}
+	}
+	prompt, err := utils.ParsePrompt(modelRequest)
+	if err != nil {
+		return nil, nil, &extProcError{code: http.StatusNotFound, message: "prompt not found"}
+	}
+
+	ctx := &framework.Context{
+		Model:            modelName,
+		Prompt:           prompt,
+		LoraAdapter:      loraAdapterOf(modelName, isLora),
+		ServedModel:      servedModelOf(modelServer, modelName, isLora),
+		RequestType:      requestType,
+		BatchSize:        utils.GetBatchSize(modelRequest),
+		ModelServerName:  modelServerName,
+		SchedulingPolicy: modelServer.Spec.SchedulingPolicy,
+	}
+	sched := r.Scheduler()
+	if err := sched.Schedule(ctx, pods); err != nil {
+		return nil, nil, &extProcError{code: http.StatusServiceUnavailable, message: fmt.Sprintf("can't schedule to target pod: %v", err)}
+	}
+	var endpoints []string
+	for _, pod := range ctx.BestPods {
+		if pod.Pod == nil || pod.Pod.Status.PodIP == "" {
+			continue
+		}
+		endpoints = append(endpoints, netutil.HostPort(pod.Pod.Status.PodIP, modelServer.Spec.WorkloadPort.Port))
+	}
+	if len(endpoints) == 0 {
+		return nil, nil, &extProcError{code: http.StatusServiceUnavailable, message: "no pod with an address was picked"}
+	}
+
+	body, err := json.Marshal(modelRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	stream.scheduler = sched
+	stream.ctx = ctx
+	return body, endpoints, nil
+}
+
+// requestOfHeaders builds the request the ModelRoutes are matched against from the headers sent by Envoy.
+func requestOfHeaders(headers *corev3.HeaderMap) *http.Request {
+	req := &http.Request{Method: http.MethodPost, Header: http.Header{}, URL: &url.URL{Path: "/"}}
+	for _, header := range headers.GetHeaders() {
+		value := header.GetValue()
+		if len(header.GetRawValue()) > 0 {
+			value = string(header.GetRawValue())
+		}
+		switch header.GetKey() {
+		case ":method":
+			req.Method = value
+		case ":path":
+			if u, err := url.ParseRequestURI(value); err == nil {
+				req.URL = u
+			}
+			req.RequestURI = value
+		case ":authority":
+			req.Host = value
+		default:
+			if !strings.HasPrefix(header.GetKey(), ":") {
+				req.Header.Add(header.GetKey(), value)
+			}
+		}
+	}
+	return req
+}
+
+// successful reports whether the :status of the response headers is 2xx.
+func successful(headers *corev3.HeaderMap) bool {
+	for _, header := range headers.GetHeaders() {
+		if header.GetKey() != ":status" {
+			continue
+		}
+		value := header.GetValue()
+		if len(header.GetRawValue()) > 0 {
+			value = string(header.GetRawValue())
+		}
+		code, err := strconv.Atoi(value)
+		return err == nil && code >= 200 && code < 300
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"istio.io/istio/pkg/util/sets"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+func TestExtProcServer(t *testing.T) {
+	store := datastore.New()
+	router := NewRouter(store, "")
+	model := "llama-3-8b-instruct"
+	modelServer := &aiv1alpha1.ModelServer{
+		ObjectMeta: v1.ObjectMeta{Name: "ms-1", Namespace: "default"},
+		Spec: aiv1alpha1.ModelServerSpec{
+			Model:           &model,
+			WorkloadPort:    aiv1alpha1.WorkloadPort{Port: 8000},
+			InferenceEngine: "vLLM",
+		},
+	}
+	require.NoError(t, store.AddOrUpdateModelServer(modelServer, sets.New(types.NamespacedName{Namespace: "default", Name: "pod-1"})))
+	require.NoError(t, store.AddOrUpdatePod(&corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "pod-1", Namespace: "default"},
+		Status:     corev1.PodStatus{PodIP: "10.0.0.1", Phase: corev1.PodRunning},
+	}, []*aiv1alpha1.ModelServer{modelServer}))
+	require.NoError(t, store.AddOrUpdateModelRoute(&aiv1alpha1.ModelRoute{
+		ObjectMeta: v1.ObjectMeta{Name: "llama", Namespace: "default"},
+		Spec: aiv1alpha1.ModelRouteSpec{
+			ModelName: "llama",
+			Rules:     []*aiv1alpha1.Rule{{TargetModels: []*aiv1alpha1.TargetModel{{ModelServerName: "ms-1"}}}},
+		},
+	}))
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	extprocv3.RegisterExternalProcessorServer(server, router.ExtProcServer())
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := extprocv3.NewExternalProcessorClient(conn)
+
+	process := func(body string) *extprocv3.ProcessingResponse {
+		stream, err := client.Process(context.Background())
+		require.NoError(t, err)
+		defer func() { _ = stream.CloseSend() }()
+		require.NoError(t, stream.Send(&extprocv3.ProcessingRequest{Request: &extprocv3.ProcessingRequest_RequestHeaders{
+			RequestHeaders: &extprocv3.HttpHeaders{Headers: &corev3.HeaderMap{Headers: []*corev3.HeaderValue{
+				{Key: ":method", RawValue: []byte("POST")},
+				{Key: ":path", RawValue: []byte("/v1/chat/completions")},
+				{Key: "content-type", RawValue: []byte("application/json")},
+			}}},
+		}}))
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		require.NotNil(t, resp.GetRequestHeaders())
+
+		require.NoError(t, stream.Send(&extprocv3.ProcessingRequest{Request: &extprocv3.ProcessingRequest_RequestBody{
+			RequestBody: &extprocv3.HttpBody{Body: []byte(body), EndOfStream: true},
+		}}))
+		resp, err = stream.Recv()
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := process(`{"model": "llama", "messages": [{"role": "user", "content": "hello"}]}`)
+	common := resp.GetRequestBody().GetResponse()
+	require.NotNil(t, common)
+	headers := map[string]string{}
+	for _, h := range common.GetHeaderMutation().GetSetHeaders() {
+		headers[h.GetHeader().GetKey()] = string(h.GetHeader().GetRawValue())
+	}
+	assert.Equal(t, "10.0.0.1:8000", headers[DestinationEndpointKey])
+	var modelRequest ModelRequest
+	require.NoError(t, json.Unmarshal(common.GetBodyMutation().GetBody(), &modelRequest))
+	assert.Equal(t, model, modelRequest["model"])
+	assert.Equal(t, "10.0.0.1:8000", resp.GetDynamicMetadata().GetFields()["envoy.lb"].GetStructValue().
+		GetFields()[DestinationEndpointKey].GetStringValue())
+
+	resp = process(`{"model": "unknown", "messages": [{"role": "user", "content": "hello"}]}`)
+	require.NotNil(t, resp.GetImmediateResponse())
+	assert.EqualValues(t, 404, resp.GetImmediateResponse().GetStatus().GetCode())
+
+	resp = process(`not json`)
+	require.NotNil(t, resp.GetImmediateResponse())
+	assert.EqualValues(t, 400, resp.GetImmediateResponse().GetStatus().GetCode())
+}
@@ -0,0 +1,247 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+)
+
+const (
+	// servedModelHeader is the response header carrying the model serving the requests of the routes with a fallback chain.
+	servedModelHeader = "X-Kthena-Served-Model"
+	// fallbackKey marks the requests served by a fallback target, which do not fall back again.
+	fallbackKey = "fallback"
+
+	// latencyBudgetPercentile is the percentile of the recent times to first token compared to the latency budget.
+	latencyBudgetPercentile = 90
+	// fallbackProbePercent is the share of the requests still sent to a model over its latency budget, whose times
+	// to first token tell when it has recovered.
+	fallbackProbePercent = 5
+
+	// apiKeyTTL is how long the API keys of the external models are cached.
+	apiKeyTTL = time.Minute
+)
+
+// externalClient sends the requests to the external models, which stream their responses for as long as they need.
+var externalClient = &http.Client{}
+
+// fallback serves the request with the first target of the fallback chain of the route which is not degraded.
+// It returns false when the route has no fallback chain, the request is already served by a fallback target,
+// or all its targets are degraded, the request is then answered by the model of the route.
+func (r *Router) fallback(c *gin.Context, modelRequest ModelRequest, requestedModel string, route *v1alpha1.ModelRoute, reason string) bool {
+	if route == nil || route.Spec.Fallback == nil || c.GetBool(fallbackKey) {
+		return false
+	}
+	c.Set(fallbackKey, true)
+	for _, target := range route.Spec.Fallback.Targets {
+		switch {
+		case target.External != nil:
+			model := target.External.Model
+			if model == "" {
+				model = requestedModel
+			}
+			klog.V(4).Infof("model %s is degraded (%s), falling back to %s at %s", requestedModel, reason, model, target.External.URL)
+			r.metrics.RecordFallback(requestedModel, model, reason)
+			c.Header(servedModelHeader, model)
+			modelRequest["model"] = model
+			if err := r.proxyExternal(c, modelRequest, route.Namespace, target.External); err != nil {
+				klog.Errorf("request to the external model %s failed: %v", model, err)
+				accesslog.SetError(c, "fallback", err.Error())
+				c.AbortWithStatusJSON(http.StatusBadGateway, fmt.Sprintf("request to the fallback model %s failed", model))
+			}
+			return true
+		case target.ModelName != "" && r.available(target.ModelName, c.Request):
+			klog.V(4).Infof("model %s is degraded (%s), falling back to %s", requestedModel, reason, target.ModelName)
+			r.metrics.RecordFallback(requestedModel, target.ModelName, reason)
+			c.Header(servedModelHeader, target.ModelName)
+			modelRequest["model"] = target.ModelName
+			r.doLoadbalance(c, modelRequest)
+			return true
+		}
+	}
+	return false
+}
+
+// available reports whether the model is routed to a model server with pods, within the latency budget of its route.
+func (r *Router) available(model string, req *http.Request) bool {
+	modelServerName, _, route, _, err := r.matchModelServer(model, req)
+	if err != nil {
+		return false
+	}
+	if pods, err := r.store.GetPodsByModelServer(modelServerName); err != nil || len(pods) == 0 {
+		return false
+	}
+	return !r.overLatencyBudget(modelServerName, route)
+}
+
+// overLatencyBudget reports whether the recent times to first token of the model server exceed the latency budget
+// of the fallback chain of the route.
+func (r *Router) overLatencyBudget(modelServerName types.NamespacedName, route *v1alpha1.ModelRoute) bool {
+	if route == nil || route.Spec.Fallback == nil || route.Spec.Fallback.LatencyBudget == nil {
+		return false
+	}
+	ttft, ok := r.store.GetTimeToFirstTokenPercentile(modelServerName, latencyBudgetPercentile)
+	return ok && ttft > route.Spec.Fallback.LatencyBudget.Duration
+}
+
+// shouldProbe picks the requests sent to a model over its latency budget anyway.
+func shouldProbe() bool {
+	return rand.IntN(100) < fallbackProbePercent
+}
+
+// measureTimeToFirstToken records the time to first token of the requests to the model servers of the routes with a
+// latency budget. The hedged requests record it already.
+func (r *Router) measureTimeToFirstToken(c *gin.Context, modelServerName types.NamespacedName, route *v1alpha1.ModelRoute) func() {
+	if route == nil || route.Spec.Fallback == nil || route.Spec.Fallback.LatencyBudget == nil {
+		return func() {}
+	}
+	if _, hedged := r.hedgingDelay(modelServerName); hedged {
+		return func() {}
+	}
+	start := time.Now()
+	writer := &firstByteWriter{ResponseWriter: c.Writer}
+	c.Writer = writer
+	return func() {
+		c.Writer = writer.ResponseWriter
+		if !writer.firstByte.IsZero() && writer.Status() < http.StatusBadRequest {
+			r.store.RecordTimeToFirstToken(modelServerName, writer.firstByte.Sub(start))
+		}
+	}
+}
+
+// proxyExternal sends the request to the external model and forwards its response.
+func (r *Router) proxyExternal(c *gin.Context, modelRequest ModelRequest, namespace string, external *v1alpha1.ExternalModel) error {
+	body, err := json.Marshal(modelRequest)
+	if err != nil {
+		return err
+	}
+	url := strings.TrimSuffix(external.URL, "/") + c.Request.URL.Path
+	req, err := http.NewRequestWithContext(c.Request.Context(), c.Request.Method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if accept := c.Request.Header.Get("Accept"); accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if external.APIKeySecretRef != nil {
+		apiKey, err := r.apiKeys.get(c.Request.Context(), namespace, external.APIKeySecretRef)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	accesslog.MarkUpstreamStart(c)
+	resp, err := externalClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	accesslog.MarkUpstreamEnd(c)
+
+	for k, vv := range resp.Header {
+		if k == "Content-Length" {
+			continue
+		}
+		for _, v := range vv {
+			c.Writer.Header().Add(k, v)
+		}
+	}
+	c.Status(resp.StatusCode)
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := c.Writer.Write(buf[:n]); err != nil {
+				return nil
+			}
+			c.Writer.Flush()
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			klog.Errorf("error reading the response of the external model: %v", readErr)
+			return nil
+		}
+	}
+}
+
+// SetKubeClient lets the router read the API keys of the external fallback models from their Secrets.
+func (r *Router) SetKubeClient(client kubernetes.Interface) {
+	r.apiKeys = &apiKeyCache{client: client, keys: make(map[string]cachedAPIKey)}
+}
+
+// apiKeyCache reads the API keys from the Secrets, and keeps them for apiKeyTTL.
+type apiKeyCache struct {
+	client kubernetes.Interface
+
+	mutex sync.Mutex
+	keys  map[string]cachedAPIKey
+}
+
+type cachedAPIKey struct {
+	value   string
+	expires time.Time
+}
+
+func (a *apiKeyCache) get(ctx context.Context, namespace string, ref *corev1.SecretKeySelector) (string, error) {
+	if a == nil {
+		return "", fmt.Errorf("the API key of the external model can not be read, there is no Kubernetes client")
+	}
+	cacheKey := namespace + "/" + ref.Name + "/" + ref.Key
+	a.mutex.Lock()
+	cached, ok := a.keys[cacheKey]
+	a.mutex.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	secret, err := a.client.CoreV1().Secrets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the API key Secret %s/%s: %w", namespace, ref.Name, err)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("the API key Secret %s/%s has no key %s", namespace, ref.Name, ref.Key)
+	}
+	apiKey := strings.TrimSpace(string(value))
+	a.mutex.Lock()
+	a.keys[cacheKey] = cachedAPIKey{value: apiKey, expires: time.Now().Add(apiKeyTTL)}
+	a.mutex.Unlock()
+	return apiKey, nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"istio.io/istio/pkg/util/sets"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+func TestRouter_HandlerFunc_Fallback(t *testing.T) {
+	router, store, backend := setupTestRouter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"in-cluster"}`)
+	}))
+	defer backend.Close()
+
+	var externalModel, externalAuth string
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		var modelRequest ModelRequest
+		_ = json.Unmarshal(body, &modelRequest)
+		externalModel, _ = modelRequest["model"].(string)
+		externalAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"external"}`)
+	}))
+	defer external.Close()
+	router.SetKubeClient(fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: "external-api", Namespace: "default"},
+		Data:       map[string][]byte{"api-key": []byte("secret-key\n")},
+	}))
+
+	backendURL, _ := url.Parse(backend.URL)
+	backendPort, _ := strconv.Atoi(backendURL.Port())
+	addModelServer := func(name string, pods ...string) {
+		modelServer := &aiv1alpha1.ModelServer{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: aiv1alpha1.ModelServerSpec{
+				WorkloadPort:    aiv1alpha1.WorkloadPort{Port: int32(backendPort)},
+				InferenceEngine: "vLLM",
+			},
+		}
+		names := sets.New[types.NamespacedName]()
+		for _, pod := range pods {
+			names.Insert(types.NamespacedName{Namespace: "default", Name: pod})
+		}
+		require.NoError(t, store.AddOrUpdateModelServer(modelServer, names))
+		for _, pod := range pods {
+			require.NoError(t, store.AddOrUpdatePod(&corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: pod, Namespace: "default"},
+				Status:     corev1.PodStatus{PodIP: backendURL.Hostname(), Phase: corev1.PodRunning},
+			}, []*aiv1alpha1.ModelServer{modelServer}))
+		}
+	}
+	addModelRoute := func(model, modelServer string, fallback *aiv1alpha1.FallbackChain) {
+		require.NoError(t, store.AddOrUpdateModelRoute(&aiv1alpha1.ModelRoute{
+			ObjectMeta: v1.ObjectMeta{Name: model, Namespace: "default"},
+			Spec: aiv1alpha1.ModelRouteSpec{
+				ModelName: model,
+				Rules:     []*aiv1alpha1.Rule{{TargetModels: []*aiv1alpha1.TargetModel{{ModelServerName: modelServer}}}},
+				Fallback:  fallback,
+			},
+		}))
+	}
+	externalTarget := aiv1alpha1.FallbackTarget{External: &aiv1alpha1.ExternalModel{
+		URL:   external.URL + "/",
+		Model: "gpt-mini",
+		APIKeySecretRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "external-api"},
+			Key:                  "api-key",
+		},
+	}}
+
+	addModelServer("ms-large")
+	addModelServer("ms-medium")
+	addModelServer("ms-small", "pod-small")
+	addModelRoute("small", "ms-small", nil)
+	addModelRoute("medium", "ms-medium", nil)
+	addModelRoute("large", "ms-large", &aiv1alpha1.FallbackChain{Targets: []aiv1alpha1.FallbackTarget{{ModelName: "medium"}, {ModelName: "small"}, externalTarget}})
+	addModelRoute("large-external", "ms-large", &aiv1alpha1.FallbackChain{Targets: []aiv1alpha1.FallbackTarget{{ModelName: "medium"}, externalTarget}})
+
+	serve := func(model string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		body := fmt.Sprintf(`{"model": %q, "messages": [{"role": "user", "content": "hello"}]}`, model)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		router.HandlerFunc()(c)
+		return w
+	}
+
+	// The first target without pods is skipped
+	w := serve("large")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "in-cluster")
+	assert.Equal(t, "small", w.Header().Get(servedModelHeader))
+
+	w = serve("large-external")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "external")
+	assert.Equal(t, "gpt-mini", w.Header().Get(servedModelHeader))
+	assert.Equal(t, "gpt-mini", externalModel)
+	assert.Equal(t, "Bearer secret-key", externalAuth)
+
+	// The routes without a fallback chain are not changed
+	w = serve("medium")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get(servedModelHeader))
+}
+
+func TestRouter_OverLatencyBudget(t *testing.T) {
+	router, store, backend := setupTestRouter(http.NotFoundHandler())
+	defer backend.Close()
+	name := types.NamespacedName{Namespace: "default", Name: "ms"}
+	require.NoError(t, store.AddOrUpdateModelServer(&aiv1alpha1.ModelServer{ObjectMeta: v1.ObjectMeta{Name: "ms", Namespace: "default"}}, sets.New[types.NamespacedName]()))
+	route := &aiv1alpha1.ModelRoute{Spec: aiv1alpha1.ModelRouteSpec{Fallback: &aiv1alpha1.FallbackChain{
+		LatencyBudget: &v1.Duration{Duration: time.Second},
+	}}}
+
+	for i := 0; i < 50; i++ {
+		store.RecordTimeToFirstToken(name, 500*time.Millisecond)
+	}
+	assert.False(t, router.overLatencyBudget(name, route))
+	for i := 0; i < 50; i++ {
+		store.RecordTimeToFirstToken(name, 2*time.Second)
+	}
+	assert.True(t, router.overLatencyBudget(name, route))
+	assert.False(t, router.overLatencyBudget(name, &aiv1alpha1.ModelRoute{}), "routes without a latency budget are never over it")
+}
@@ -0,0 +1,88 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"istio.io/istio/pkg/env"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/fault"
+)
+
+// EnableFaultInjection exposes the fault injection rules in the admin API. It should only be enabled in
+// the environments where the resilience of the clients is tested.
+var EnableFaultInjection = env.RegisterBoolVar("ENABLE_FAULT_INJECTION", false,
+	"Enable setting fault injection rules delaying, aborting or truncating requests through the admin API").Get()
+
+// injectFaults delays or aborts the request, or truncates its streamed response, as set by the fault injection
+// rules. It returns false once the request has been aborted, and a function to call once it has been proxied.
+func (r *Router) injectFaults(c *gin.Context, model, modelRouteName string, stream bool) (bool, func()) {
+	noop := func() {}
+	if r.faults == nil {
+		return true, noop
+	}
+	faults, ok := r.faults.Match(model, modelRouteName)
+	if !ok {
+		return true, noop
+	}
+
+	if faults.Delay > 0 {
+		r.metrics.RecordFaultInjected(model, "delay")
+		klog.V(4).Infof("injecting a delay of %s in request %s", faults.Delay, c.Request.Header.Get("x-request-id"))
+		timer := time.NewTimer(faults.Delay)
+		select {
+		case <-timer.C:
+		case <-c.Request.Context().Done():
+			timer.Stop()
+		}
+	}
+
+	if faults.AbortStatusCode != 0 {
+		r.metrics.RecordFaultInjected(model, "abort")
+		message := fmt.Sprintf("fault injected by the router, status code %d", faults.AbortStatusCode)
+		accesslog.SetError(c, "fault_injection", message)
+		c.AbortWithStatusJSON(faults.AbortStatusCode, gin.H{"error": gin.H{
+			"type":    "fault_injection",
+			"message": message,
+		}})
+		return false, noop
+	}
+
+	if stream && faults.TruncateAfterEvents >= 0 {
+		r.metrics.RecordFaultInjected(model, "truncate")
+		// The upstream response is no longer read once the response has been truncated
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		writer := fault.NewTruncateWriter(c.Writer, faults.TruncateAfterEvents, cancel)
+		c.Writer = writer
+		return true, func() {
+			c.Writer = writer.ResponseWriter
+			if writer.Truncated() {
+				accesslog.SetError(c, "fault_injection", "streamed response truncated by the router")
+				writer.CloseConnection()
+			}
+			cancel()
+		}
+	}
+	return true, noop
+}
@@ -0,0 +1,128 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"istio.io/istio/pkg/util/sets"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/fault"
+)
+
+// closeNotifyRecorder is a ResponseRecorder which can stream responses with gin.
+type closeNotifyRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (closeNotifyRecorder) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+func TestRouter_HandlerFunc_FaultInjection(t *testing.T) {
+	var requests atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 3; i++ {
+			_, _ = fmt.Fprintf(w, "data: {\"id\":\"cmpl-fault\",\"choices\":[{\"index\":0,\"text\":\"%d\"}]}\n\n", i)
+		}
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer backend.Close()
+	backendURL, _ := url.Parse(backend.URL)
+	backendPort, _ := strconv.Atoi(backendURL.Port())
+
+	store := datastore.New()
+	router := NewRouter(store, "")
+	router.faults = fault.NewInjector()
+	modelServer := &aiv1alpha1.ModelServer{
+		ObjectMeta: v1.ObjectMeta{Name: "ms-1", Namespace: "default"},
+		Spec: aiv1alpha1.ModelServerSpec{
+			WorkloadPort:    aiv1alpha1.WorkloadPort{Port: int32(backendPort)},
+			InferenceEngine: "vLLM",
+		},
+	}
+	store.AddOrUpdateModelServer(modelServer, sets.New(types.NamespacedName{Name: "pod-1", Namespace: "default"}))
+	store.AddOrUpdatePod(&corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "pod-1", Namespace: "default"},
+		Status:     corev1.PodStatus{PodIP: backendURL.Hostname(), Phase: corev1.PodRunning},
+	}, []*aiv1alpha1.ModelServer{modelServer})
+	store.AddOrUpdateModelRoute(&aiv1alpha1.ModelRoute{
+		ObjectMeta: v1.ObjectMeta{Name: "mr-1", Namespace: "default"},
+		Spec: aiv1alpha1.ModelRouteSpec{
+			ModelName: "test-model",
+			Rules:     []*aiv1alpha1.Rule{{TargetModels: []*aiv1alpha1.TargetModel{{ModelServerName: "ms-1"}}}},
+		},
+	})
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(closeNotifyRecorder{w})
+		c.Request, _ = http.NewRequest("POST", "/v1/completions", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		router.HandlerFunc()(c)
+		return w
+	}
+
+	t.Run("abort", func(t *testing.T) {
+		require.NoError(t, router.Faults().SetRules([]fault.Rule{{
+			ModelRoute: "default/mr-1",
+			Abort:      &fault.Abort{Percentage: 100, StatusCode: http.StatusServiceUnavailable},
+		}}))
+		w := serve(`{"model": "test-model", "prompt": "hello"}`)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), "fault_injection")
+		assert.Equal(t, int32(0), requests.Load(), "the aborted request is not proxied")
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		require.NoError(t, router.Faults().SetRules([]fault.Rule{{
+			Model:    "test-model",
+			Truncate: &fault.Truncate{Percentage: 100, AfterEvents: 1},
+		}}))
+		w := serve(`{"model": "test-model", "prompt": "hello", "stream": true}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, bytes.Count(w.Body.Bytes(), []byte("data:")))
+		assert.NotContains(t, w.Body.String(), "[DONE]")
+	})
+
+	t.Run("other model", func(t *testing.T) {
+		require.NoError(t, router.Faults().SetRules([]fault.Rule{{
+			Model: "other-model",
+			Abort: &fault.Abort{Percentage: 100, StatusCode: http.StatusServiceUnavailable},
+		}}))
+		w := serve(`{"model": "test-model", "prompt": "hello", "stream": true}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "[DONE]")
+	})
+}
@@ -0,0 +1,162 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/guardrail"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/handlers"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/utils"
+)
+
+// guardrailHeader lists the annotating guardrail filters which flagged the request or its response.
+// It is set on the request sent to the model server and on the response returned to the client.
+const guardrailHeader = "X-Kthena-Guardrail-Flagged"
+
+// guardrailRoute returns the route of the model when it has guardrails.
+func (r *Router) guardrailRoute(modelName string, req *http.Request) *v1alpha1.ModelRoute {
+	_, _, modelRoute, _, err := r.matchModelServer(modelName, req)
+	if err != nil || modelRoute == nil || modelRoute.Spec.Guardrails == nil {
+		return nil
+	}
+	return modelRoute
+}
+
+// checkRequestGuardrails runs the request guardrails of the route on the prompt, before the request is routed.
+// It returns false once the request has been rejected.
+func (r *Router) checkRequestGuardrails(c *gin.Context, route *v1alpha1.ModelRoute, modelName, prompt string, metricsRecorder *metrics.RequestMetricsRecorder) bool {
+	if route == nil {
+		return true
+	}
+	result, err := r.guardrails.Check(c.Request.Context(), route, modelName, v1alpha1.GuardrailStageRequest, prompt)
+	if !r.handleGuardrailResult(c, result, err, v1alpha1.GuardrailStageRequest) {
+		metricsRecorder.Finish(strconv.Itoa(c.Writer.Status()), "guardrail")
+		return false
+	}
+	if len(result.Flagged) > 0 {
+		flagged := strings.Join(result.Flagged, ",")
+		c.Request.Header.Set(guardrailHeader, flagged)
+		c.Header(guardrailHeader, flagged)
+	}
+	return true
+}
+
+// guardResponse holds back the non-streaming responses of the routes with response guardrails. The returned
+// function checks the response once it has been proxied, and sends or rejects it.
+func (r *Router) guardResponse(c *gin.Context, route *v1alpha1.ModelRoute, modelName string, modelRequest ModelRequest) func() {
+	noop := func() {}
+	if route == nil || isStreaming(modelRequest) || !utils.GetRequestType(c.Request.URL.Path).IsGenerative() {
+		return noop
+	}
+	if !r.guardrails.Enabled(route, v1alpha1.GuardrailStageResponse) {
+		return noop
+	}
+	writer := guardrail.NewBufferWriter(c.Writer)
+	c.Writer = writer
+	return func() {
+		c.Writer = writer.ResponseWriter
+		if writer.Status() != http.StatusOK {
+			// Errors of the router and of the model servers carry no generated content
+			if err := writer.Release(); err != nil {
+				klog.Errorf("failed to write response: %v", err)
+			}
+			return
+		}
+		result, err := r.guardrails.Check(c.Request.Context(), route, modelName, v1alpha1.GuardrailStageResponse, responseText(writer.Body()))
+		if !r.handleGuardrailResult(c, result, err, v1alpha1.GuardrailStageResponse) {
+			return
+		}
+		if len(result.Flagged) > 0 {
+			// The filters flagging the request are listed once
+			var flagged []string
+			if existing := c.Writer.Header().Get(guardrailHeader); existing != "" {
+				flagged = strings.Split(existing, ",")
+			}
+			for _, name := range result.Flagged {
+				if !slices.Contains(flagged, name) {
+					flagged = append(flagged, name)
+				}
+			}
+			c.Header(guardrailHeader, strings.Join(flagged, ","))
+		}
+		if err := writer.Release(); err != nil {
+			klog.Errorf("failed to write response: %v", err)
+		}
+	}
+}
+
+// handleGuardrailResult aborts the request when its content is rejected or could not be checked.
+// It returns false once the request has been aborted.
+func (r *Router) handleGuardrailResult(c *gin.Context, result guardrail.Result, err error, stage v1alpha1.GuardrailStage) bool {
+	if err == nil && result.Rejected == "" {
+		return true
+	}
+	// The headers of the response held back belong to the model server
+	if stage == v1alpha1.GuardrailStageResponse {
+		for _, header := range []string{"Content-Length", "Content-Encoding", "Content-Type"} {
+			c.Writer.Header().Del(header)
+		}
+	}
+	var unavailable *guardrail.UnavailableError
+	if errors.As(err, &unavailable) {
+		accesslog.SetError(c, "guardrail", err.Error())
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": gin.H{
+			"type":    "guardrail_unavailable",
+			"message": "content could not be checked by the guardrails",
+			"filter":  unavailable.Filter,
+		}})
+		return false
+	}
+	message := string(stage) + " rejected by guardrail " + result.Rejected
+	if result.Reason != "" {
+		message += ": " + result.Reason
+	}
+	accesslog.SetError(c, "guardrail", message)
+	c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": gin.H{
+		"type":    "guardrail",
+		"message": message,
+		"filter":  result.Rejected,
+	}})
+	return false
+}
+
+// responseText returns the generated text of a completion, chat completion or Responses API response.
+func responseText(body []byte) string {
+	outputs, ok := handlers.OutputTexts(body)
+	if !ok {
+		// Not a response of the OpenAI API, the whole body is checked
+		return string(body)
+	}
+	texts := make([]string, 0, len(outputs))
+	for _, output := range outputs {
+		if output != "" {
+			texts = append(texts, output)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
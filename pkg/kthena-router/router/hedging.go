@@ -0,0 +1,211 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/handlers"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+// Defaults of the hedging fields of the traffic policy, matching the defaults of the API.
+const (
+	defaultHedgingPercentile = 95
+	defaultHedgingMinDelay   = 100 * time.Millisecond
+	defaultHedgingMaxDelay   = 2 * time.Second
+)
+
+// Winners of a hedged request, as reported in the metrics.
+const (
+	hedgeWinnerPrimary = "primary"
+	hedgeWinnerHedge   = "hedge"
+	hedgeWinnerNone    = "none"
+)
+
+// hedgeAttempt is a request sent to one of the best pods.
+type hedgeAttempt struct {
+	// pod is the index of the pod in the best pods of the scheduling context.
+	pod    int
+	start  time.Time
+	resp   *http.Response
+	err    error
+	cancel context.CancelFunc
+}
+
+// bufferedBody is a response body whose first bytes have been read ahead.
+type bufferedBody struct {
+	*bufio.Reader
+	io.Closer
+}
+
+// hedgingDelay returns the delay after which the requests to the model server are hedged, and false when its
+// traffic policy does not hedge them. The delay is the percentile of the recent times to first token, within
+// the bounds of the policy, and the maximum delay until enough times have been recorded.
+func (r *Router) hedgingDelay(modelServerName types.NamespacedName) (time.Duration, bool) {
+	modelServer := r.store.GetModelServer(modelServerName)
+	if modelServer == nil || modelServer.Spec.TrafficPolicy == nil || modelServer.Spec.TrafficPolicy.Hedging == nil {
+		return 0, false
+	}
+	hedging := modelServer.Spec.TrafficPolicy.Hedging
+	percentile := hedging.Percentile
+	if percentile == 0 {
+		percentile = defaultHedgingPercentile
+	}
+	minDelay, maxDelay := defaultHedgingMinDelay, defaultHedgingMaxDelay
+	if hedging.MinDelay != nil {
+		minDelay = hedging.MinDelay.Duration
+	}
+	if hedging.MaxDelay != nil {
+		maxDelay = hedging.MaxDelay.Duration
+	}
+	delay, ok := r.store.GetTimeToFirstTokenPercentile(modelServerName, percentile)
+	if !ok {
+		return maxDelay, true
+	}
+	return min(max(delay, minDelay), maxDelay), true
+}
+
+// proxyHedged sends the request to the best pod and, when its first token has not arrived after the delay or
+// the pod cannot be reached, duplicates it to the second best pod. The response arriving first is proxied and
+// the other request is cancelled, which aborts its generation on the model server.
+func (r *Router) proxyHedged(
+	c *gin.Context,
+	req *http.Request,
+	ctx *framework.Context,
+	modelRequest ModelRequest,
+	delay time.Duration,
+	stream bool,
+	port int32,
+	onUsage func(u handlers.OpenAIResponse),
+) error {
+	body, err := json.Marshal(modelRequest)
+	if err != nil {
+		return err
+	}
+	modelServerName := fmt.Sprintf("%s/%s", ctx.ModelServerName.Namespace, ctx.ModelServerName.Name)
+	var modelRouteName string
+	if routeName, exists := c.Get("modelRouteName"); exists {
+		if name, ok := routeName.(string); ok {
+			modelRouteName = name
+		}
+	}
+
+	results := make(chan *hedgeAttempt, 2)
+	send := func(pod int) *hedgeAttempt {
+		attemptCtx, cancel := context.WithCancel(c.Request.Context())
+		attempt := &hedgeAttempt{pod: pod, start: time.Now(), cancel: cancel}
+		r.metrics.IncActiveUpstreamRequests(modelServerName, modelRouteName)
+		go func() {
+			defer r.metrics.DecActiveUpstreamRequests(modelServerName, modelRouteName)
+			attempt.resp, attempt.err = doRequest(cloneRequest(attemptCtx, req, body), ctx.BestPods[pod], port)
+			if attempt.err == nil {
+				// The response has started once its first bytes arrived
+				reader := bufio.NewReader(attempt.resp.Body)
+				if _, err := reader.Peek(1); err != nil && err != io.EOF {
+					attempt.resp.Body.Close()
+					attempt.resp, attempt.err = nil, err
+				} else {
+					attempt.resp.Body = bufferedBody{Reader: reader, Closer: attempt.resp.Body}
+				}
+			}
+			results <- attempt
+		}()
+		return attempt
+	}
+
+	primary := send(0)
+	var hedge, winner *hedgeAttempt
+	pending := 1
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	for winner == nil && pending > 0 {
+		select {
+		case attempt := <-results:
+			pending--
+			if attempt.err != nil {
+				klog.Errorf("request to pod %s error: %v", ctx.BestPods[attempt.pod].Pod.Name, attempt.err)
+				attempt.cancel()
+				if hedge == nil {
+					hedge = send(1)
+					pending++
+				}
+				continue
+			}
+			winner = attempt
+		case <-timer.C:
+			if hedge == nil {
+				klog.V(4).Infof("hedging request to model server %s after %v", modelServerName, delay)
+				hedge = send(1)
+				pending++
+			}
+		}
+	}
+
+	// The losing request is cancelled, its response is discarded once it returns
+	if pending > 0 {
+		loser := primary
+		if winner == primary {
+			loser = hedge
+		}
+		loser.cancel()
+		go func() {
+			if attempt := <-results; attempt.resp != nil {
+				attempt.resp.Body.Close()
+			}
+		}()
+	}
+
+	if hedge != nil {
+		// The prompt has been processed twice
+		var duplicateTokens int
+		if accessCtx := accesslog.GetAccessLogContext(c); accessCtx != nil {
+			duplicateTokens = accessCtx.InputTokens
+		}
+		winnerName := hedgeWinnerNone
+		switch winner {
+		case primary:
+			winnerName = hedgeWinnerPrimary
+		case hedge:
+			winnerName = hedgeWinnerHedge
+		}
+		r.store.RecordHedgedRequest(ctx.ModelServerName, winner == hedge, duplicateTokens)
+		r.metrics.RecordHedgedRequest(modelServerName, winnerName, duplicateTokens)
+	}
+
+	if winner == nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, "request to all pods failed")
+		return fmt.Errorf("request to all pods failed")
+	}
+	defer winner.cancel()
+	r.store.RecordTimeToFirstToken(ctx.ModelServerName, time.Since(winner.start))
+
+	forwardResponse(c, winner.resp, stream, onUsage)
+	r.Scheduler().RunPostHooks(ctx, winner.pod)
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"istio.io/istio/pkg/util/sets"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+func TestHedgingDelay(t *testing.T) {
+	store := datastore.New()
+	router := NewRouter(store, "")
+	name := types.NamespacedName{Namespace: "default", Name: "ms-1"}
+	modelServer := &aiv1alpha1.ModelServer{ObjectMeta: v1.ObjectMeta{Name: "ms-1", Namespace: "default"}}
+	store.AddOrUpdateModelServer(modelServer, sets.New[types.NamespacedName]())
+
+	_, ok := router.hedgingDelay(name)
+	assert.False(t, ok, "hedging is disabled without a traffic policy")
+
+	modelServer.Spec.TrafficPolicy = &aiv1alpha1.TrafficPolicy{Hedging: &aiv1alpha1.Hedging{}}
+	store.AddOrUpdateModelServer(modelServer, sets.New[types.NamespacedName]())
+	delay, ok := router.hedgingDelay(name)
+	assert.True(t, ok)
+	assert.Equal(t, defaultHedgingMaxDelay, delay, "the maximum delay is used until enough samples are recorded")
+
+	for i := 1; i <= 100; i++ {
+		store.RecordTimeToFirstToken(name, time.Duration(i)*10*time.Millisecond)
+	}
+	delay, _ = router.hedgingDelay(name)
+	assert.Equal(t, 950*time.Millisecond, delay)
+
+	modelServer.Spec.TrafficPolicy.Hedging = &aiv1alpha1.Hedging{
+		Percentile: 99,
+		MaxDelay:   &v1.Duration{Duration: 500 * time.Millisecond},
+	}
+	store.AddOrUpdateModelServer(modelServer, sets.New[types.NamespacedName]())
+	delay, _ = router.hedgingDelay(name)
+	assert.Equal(t, 500*time.Millisecond, delay)
+
+	modelServer.Spec.TrafficPolicy.Hedging = &aiv1alpha1.Hedging{
+		Percentile: 50,
+		MinDelay:   &v1.Duration{Duration: 800 * time.Millisecond},
+	}
+	store.AddOrUpdateModelServer(modelServer, sets.New[types.NamespacedName]())
+	delay, _ = router.hedgingDelay(name)
+	assert.Equal(t, 800*time.Millisecond, delay)
+}
+
+func TestRouter_HandlerFunc_Hedging(t *testing.T) {
+	var requests atomic.Int32
+	cancelled := make(chan struct{})
+	// The first request never starts its response, until it is cancelled
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The disconnection of the client is detected once the body has been read
+		_, _ = io.ReadAll(r.Body)
+		if requests.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+				close(cancelled)
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":      "cmpl-hedge",
+			"object":  "text_completion",
+			"choices": []map[string]interface{}{{"index": 0, "text": "hi"}},
+			"usage":   map[string]int{"prompt_tokens": 5, "completion_tokens": 1, "total_tokens": 6},
+		})
+	}))
+	defer backend.Close()
+	backendURL, _ := url.Parse(backend.URL)
+	backendPort, _ := strconv.Atoi(backendURL.Port())
+
+	store := datastore.New()
+	router := NewRouter(store, "")
+	name := types.NamespacedName{Namespace: "default", Name: "ms-1"}
+	modelServer := &aiv1alpha1.ModelServer{
+		ObjectMeta: v1.ObjectMeta{Name: "ms-1", Namespace: "default"},
+		Spec: aiv1alpha1.ModelServerSpec{
+			WorkloadPort:    aiv1alpha1.WorkloadPort{Port: int32(backendPort)},
+			InferenceEngine: "vLLM",
+			TrafficPolicy: &aiv1alpha1.TrafficPolicy{Hedging: &aiv1alpha1.Hedging{
+				MinDelay: &v1.Duration{Duration: 10 * time.Millisecond},
+				MaxDelay: &v1.Duration{Duration: 50 * time.Millisecond},
+			}},
+		},
+	}
+	podNames := sets.New[types.NamespacedName]()
+	for _, podName := range []string{"pod-1", "pod-2"} {
+		podNames.Insert(types.NamespacedName{Name: podName, Namespace: "default"})
+	}
+	store.AddOrUpdateModelServer(modelServer, podNames)
+	for _, podName := range []string{"pod-1", "pod-2"} {
+		store.AddOrUpdatePod(&corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: podName, Namespace: "default"},
+			Status:     corev1.PodStatus{PodIP: backendURL.Hostname(), Phase: corev1.PodRunning},
+		}, []*aiv1alpha1.ModelServer{modelServer})
+	}
+	store.AddOrUpdateModelRoute(&aiv1alpha1.ModelRoute{
+		ObjectMeta: v1.ObjectMeta{Name: "mr-1", Namespace: "default"},
+		Spec: aiv1alpha1.ModelRouteSpec{
+			ModelName: "test-model",
+			Rules:     []*aiv1alpha1.Rule{{TargetModels: []*aiv1alpha1.TargetModel{{ModelServerName: "ms-1"}}}},
+		},
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/v1/completions", bytes.NewBufferString(`{"model": "test-model", "prompt": "hello"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	start := time.Now()
+	router.HandlerFunc()(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "cmpl-hedge")
+	assert.Less(t, time.Since(start), 2*time.Second, "the response of the duplicate is used")
+	assert.Equal(t, int32(2), requests.Load())
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the losing request was not cancelled")
+	}
+
+	stats := store.GetHedgingStats(name)
+	assert.Equal(t, int64(1), stats.Hedged)
+	assert.Equal(t, int64(1), stats.Won)
+}
@@ -0,0 +1,254 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"istio.io/istio/pkg/env"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/compare"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/responsecache"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/handlers"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/utils"
+)
+
+var (
+	trafficMirrorMaxConcurrency = env.RegisterIntVar("TRAFFIC_MIRROR_MAX_CONCURRENCY", 64, "Maximum number of requests in flight to the shadow model servers, the excess requests are not mirrored").Get()
+	trafficMirrorTimeout        = env.RegisterDurationVar("TRAFFIC_MIRROR_TIMEOUT", 5*time.Minute, "Timeout of a request mirrored to a shadow model server").Get()
+)
+
+// mirrorResult is the response of the shadow model server to a mirrored request.
+type mirrorResult struct {
+	failed           bool
+	duration         time.Duration
+	timeToFirstToken time.Duration
+	outputTokens     int
+	// statusCode and body are the response of a non-streaming request, err why it failed.
+	statusCode int
+	body       []byte
+	err        error
+}
+
+// mirrorRequest sends a copy of a sample of the requests matching a rule with a mirror to its shadow model server,
+// out-of-band: the response of the shadow is only measured. It returns the function measuring the response of the
+// primary model server once the request is served, with whether the router failed to serve it, so that both model
+// servers are compared on the same requests. The responses of a sample of the non-streaming generation requests are
+// compared too, and recorded by the comparator.
+func (r *Router) mirrorRequest(c *gin.Context, modelRoute *v1alpha1.ModelRoute, model string, modelServerName types.NamespacedName,
+	modelRequest ModelRequest, isLora bool) func(failed bool) {
+	if modelRoute == nil {
+		return func(bool) {}
+	}
+	rule, err := datastore.SelectRule(model, c.Request, modelRoute.Spec.Rules)
+	if err != nil || rule.Mirror == nil {
+		return func(bool) {}
+	}
+	percent := uint32(100)
+	if rule.Mirror.Percent != nil {
+		percent = *rule.Mirror.Percent
+	}
+	if !compare.Sampled(percent) {
+		return func(bool) {}
+	}
+
+	modelRouteName := fmt.Sprintf("%s/%s", modelRoute.Namespace, modelRoute.Name)
+	shadowName := types.NamespacedName{Namespace: modelRoute.Namespace, Name: rule.Mirror.ModelServerName}
+	select {
+	case r.mirrorSlots <- struct{}{}:
+	default:
+		klog.V(4).Infof("too many requests mirrored to model server %s, dropping request %s", shadowName, c.Request.Header.Get("x-request-id"))
+		r.metrics.RecordMirrorDropped(modelRouteName, shadowName.String())
+		return func(bool) {}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), trafficMirrorTimeout)
+	req, pod, port, err := r.buildMirrorRequest(ctx, c, shadowName, modelRequest, isLora)
+	if err != nil {
+		cancel()
+		<-r.mirrorSlots
+		klog.Errorf("failed to mirror request of model route %s to model server %s: %v", modelRouteName, shadowName, err)
+		r.metrics.RecordMirrorResponse(modelRouteName, shadowName.String(), metrics.MirrorTargetShadow, true, 0, 0, 0)
+		return func(bool) {}
+	}
+	stream := isStreaming(modelRequest)
+	var primaryResults chan compare.Result
+	if rule.Mirror.ComparePercent != nil && !stream && utils.GetRequestType(c.Request.URL.Path).IsGenerative() &&
+		compare.Sampled(*rule.Mirror.ComparePercent) {
+		primaryResults = make(chan compare.Result, 1)
+	}
+	start := time.Now()
+	sample := &compare.Sample{RequestID: c.Request.Header.Get("x-request-id"), Model: model, Timestamp: start}
+	go func() {
+		defer func() { <-r.mirrorSlots }()
+		defer cancel()
+		result := sendMirrorRequest(req, pod, port, stream)
+		r.metrics.RecordMirrorResponse(modelRouteName, shadowName.String(), metrics.MirrorTargetShadow,
+			result.failed, result.duration, result.timeToFirstToken, result.outputTokens)
+		if primaryResults == nil {
+			return
+		}
+		// The primary response is usually complete, its model server is not slowed down by the comparison
+		select {
+		case sample.Baseline = <-primaryResults:
+			sample.Candidate = r.shadowCompareResult(shadowName.String(), pod.Pod.Name, result)
+			r.comparator.Record(ctx, modelRouteName, sample)
+		case <-ctx.Done():
+		}
+	}()
+
+	original := c.Writer
+	writer := &firstByteWriter{ResponseWriter: c.Writer}
+	c.Writer = writer
+	var capture *responsecache.CaptureWriter
+	if primaryResults != nil {
+		capture = responsecache.NewCaptureWriter(writer, r.comparator.MaxBodyBytes())
+		c.Writer = capture
+	}
+	return func(failed bool) {
+		c.Writer = original
+		duration := time.Since(start)
+		timeToFirstToken := duration
+		if !writer.firstByte.IsZero() {
+			timeToFirstToken = writer.firstByte.Sub(start)
+		}
+		outputTokens := 0
+		if accessCtx := accesslog.GetAccessLogContext(c); accessCtx != nil {
+			outputTokens = accessCtx.OutputTokens
+		}
+		failed = failed || writer.Status() >= http.StatusBadRequest
+		r.metrics.RecordMirrorResponse(modelRouteName, modelServerName.String(), metrics.MirrorTargetPrimary,
+			failed, duration, timeToFirstToken, outputTokens)
+		if capture != nil {
+			primaryResults <- primaryCompareResult(modelServerName.String(), capture, failed, duration)
+		}
+	}
+}
+
+// primaryCompareResult is the compared response returned to the client by the primary model server.
+func primaryCompareResult(modelServer string, capture *responsecache.CaptureWriter, failed bool, duration time.Duration) compare.Result {
+	entry := capture.Entry()
+	switch {
+	case entry == nil:
+		return compare.Result{ModelServer: modelServer, StatusCode: capture.Status(), LatencyMilliseconds: duration.Milliseconds(),
+			Error: "response exceeds the compared body size"}
+	case failed && entry.StatusCode == http.StatusOK:
+		return compare.Result{ModelServer: modelServer, LatencyMilliseconds: duration.Milliseconds(), Error: "request failed"}
+	}
+	return compare.NewResult(modelServer, "", entry.StatusCode, entry.Body, duration)
+}
+
+// shadowCompareResult is the compared response of the shadow model server.
+func (r *Router) shadowCompareResult(modelServer, pod string, result mirrorResult) compare.Result {
+	switch {
+	case result.err != nil:
+		return compare.Result{ModelServer: modelServer, Pod: pod, Error: result.err.Error()}
+	case len(result.body) > r.comparator.MaxBodyBytes():
+		return compare.Result{ModelServer: modelServer, Pod: pod, StatusCode: result.statusCode,
+			LatencyMilliseconds: result.duration.Milliseconds(), Error: "response exceeds the compared body size"}
+	}
+	return compare.NewResult(modelServer, pod, result.statusCode, result.body, result.duration)
+}
+
+// buildMirrorRequest picks a pod of the shadow model server and builds the request mirrored to it. The streaming
+// requests include their token usage.
+func (r *Router) buildMirrorRequest(ctx context.Context, c *gin.Context, shadowName types.NamespacedName, modelRequest ModelRequest,
+	isLora bool) (*http.Request, *datastore.PodInfo, int32, error) {
+	pods, modelServer, err := r.getPodsAndServer(shadowName)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if modelServer.Spec.WorkloadSelector != nil && modelServer.Spec.WorkloadSelector.PDGroup != nil {
+		return nil, nil, 0, fmt.Errorf("model server %s is prefill/decode disaggregated, which is not supported", shadowName)
+	}
+
+	request := make(ModelRequest, len(modelRequest))
+	for k, v := range modelRequest {
+		request[k] = v
+	}
+	if modelServer.Spec.Model != nil && !isLora {
+		request["model"] = *modelServer.Spec.Model
+	}
+	if isStreaming(request) {
+		request["stream_options"] = map[string]interface{}{"include_usage": true}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return cloneRequest(ctx, c.Request, body), pods[rand.Intn(len(pods))], modelServer.Spec.WorkloadPort.Port, nil
+}
+
+// sendMirrorRequest sends the mirrored request to the pod of the shadow model server and measures its response,
+// which is discarded.
+func sendMirrorRequest(req *http.Request, pod *datastore.PodInfo, port int32, stream bool) mirrorResult {
+	start := time.Now()
+	resp, err := doRequest(req, pod, port)
+	if err != nil {
+		klog.V(4).Infof("mirrored request to pod %s failed: %v", pod.Pod.Name, err)
+		return mirrorResult{failed: true, err: err}
+	}
+	defer resp.Body.Close()
+
+	result := mirrorResult{failed: resp.StatusCode >= http.StatusBadRequest, statusCode: resp.StatusCode}
+	var body bytes.Buffer
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if result.timeToFirstToken == 0 {
+				result.timeToFirstToken = time.Since(start)
+			}
+			if stream {
+				if parsed := handlers.ParseStreamRespForUsage(string(line)); parsed.Usage.CompletionTokens > 0 {
+					result.outputTokens = parsed.Usage.CompletionTokens
+				}
+			} else {
+				body.Write(line)
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				klog.V(4).Infof("failed to read the response of mirrored request to pod %s: %v", pod.Pod.Name, err)
+				return mirrorResult{failed: true, err: err}
+			}
+			break
+		}
+	}
+	if !stream {
+		if parsed, _ := handlers.ParseOpenAIResponseBody(body.Bytes()); parsed != nil {
+			result.outputTokens = parsed.Usage.CompletionTokens
+		}
+		result.body = body.Bytes()
+	}
+	result.duration = time.Since(start)
+	return result
+}
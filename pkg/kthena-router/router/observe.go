@@ -0,0 +1,65 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/handlers"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+// proxyObservedRequest proxies the request to the pod at index in the best pods like proxyRequest, and passes the
+// latency and the usage of its successful responses to the scheduler plugins learning from them. The hedged, the
+// parallel sampling and the PD disaggregated requests are not observed, their latency is not the one of a pod.
+func (r *Router) proxyObservedRequest(
+	c *gin.Context,
+	req *http.Request,
+	ctx *framework.Context,
+	index int,
+	port int32,
+	stream bool,
+	onUsage func(u handlers.OpenAIResponse),
+) error {
+	var usage handlers.Usage
+	observeUsage := func(u handlers.OpenAIResponse) {
+		usage = u.Usage
+		if onUsage != nil {
+			onUsage(u)
+		}
+	}
+	writer := &firstByteWriter{ResponseWriter: c.Writer}
+	c.Writer = writer
+	start := time.Now()
+	err := proxyRequest(c, req, ctx.BestPods[index], port, stream, observeUsage)
+	c.Writer = writer.ResponseWriter
+	if err != nil || writer.firstByte.IsZero() {
+		return err
+	}
+
+	r.Scheduler().ObserveResponse(ctx, index, framework.Response{
+		Stream:           stream,
+		TimeToFirstToken: writer.firstByte.Sub(start),
+		Duration:         time.Since(start),
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+	})
+	return nil
+}
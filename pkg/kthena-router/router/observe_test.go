@@ -0,0 +1,103 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/handlers"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+// observingScheduler records the responses passed to the scheduler.
+type observingScheduler struct {
+	scheduler.Scheduler
+	responses []framework.Response
+}
+
+func (s *observingScheduler) ObserveResponse(ctx *framework.Context, index int, response framework.Response) {
+	s.responses = append(s.responses, response)
+}
+
+func TestProxyObservedRequest(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":5,\"completion_tokens\":8,\"total_tokens\":13}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer backend.Close()
+	backendURL, _ := url.Parse(backend.URL)
+	backendPort, _ := strconv.Atoi(backendURL.Port())
+
+	observer := &observingScheduler{}
+	r := &Router{store: datastore.New(), metrics: metrics.DefaultMetrics}
+	r.config.Store(&configState{scheduler: observer})
+	pod := &datastore.PodInfo{Pod: &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "pod-1", Namespace: "default"},
+		Status:     corev1.PodStatus{PodIP: backendURL.Hostname()},
+	}}
+	ctx := &framework.Context{BestPods: []*datastore.PodInfo{pod}}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(closeNotifyRecorder{w})
+	req := httptest.NewRequest(http.MethodPost, "http://router/v1/chat/completions", nil)
+	var usage handlers.Usage
+	err := r.proxyObservedRequest(c, req, ctx, 0, int32(backendPort), true, func(u handlers.OpenAIResponse) {
+		usage = u.Usage
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 8, usage.CompletionTokens, "the usage is still passed on")
+	assert.Contains(t, w.Body.String(), "[DONE]")
+
+	require.Len(t, observer.responses, 1)
+	response := observer.responses[0]
+	assert.True(t, response.Stream)
+	assert.GreaterOrEqual(t, response.TimeToFirstToken, 20*time.Millisecond)
+	assert.GreaterOrEqual(t, response.Duration, response.TimeToFirstToken+20*time.Millisecond)
+	assert.Equal(t, 5, response.PromptTokens)
+	assert.Equal(t, 8, response.CompletionTokens)
+
+	// The failed requests are not observed
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	req = httptest.NewRequest(http.MethodPost, "http://router/fail", nil)
+	err = r.proxyObservedRequest(c, req, ctx, 0, int32(backendPort), true, nil)
+	assert.Error(t, err)
+	assert.Len(t, observer.responses, 1)
+}
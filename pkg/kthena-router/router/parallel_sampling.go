@@ -0,0 +1,353 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"istio.io/istio/pkg/env"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/handlers"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+// ParallelSamplingMaxPods is the maximum number of pods the completions of a request with n > 1 are split across.
+var ParallelSamplingMaxPods = env.RegisterIntVar("PARALLEL_SAMPLING_MAX_PODS", 1,
+	"Maximum number of pods the parallel completions (n > 1) of a request are split across, 1 disables the splitting").Get()
+
+const (
+	sseDataPrefix = "data: "
+	sseDone       = "[DONE]"
+)
+
+// samplingShard is the part of the completions of a request generated by one pod.
+type samplingShard struct {
+	// pod is the index of the pod in the best pods of the scheduling context.
+	pod int
+	n   int
+	// offset is the index of its first choice in the merged response.
+	offset int
+	resp   *http.Response
+}
+
+// parallelSamplingShards splits the n completions of the request as evenly as possible across the best pods.
+// It returns nil when the request is not split.
+func parallelSamplingShards(ctx *framework.Context, modelRequest ModelRequest) []*samplingShard {
+	if ParallelSamplingMaxPods <= 1 || !ctx.RequestType.IsGenerative() {
+		return nil
+	}
+	n, ok := modelRequest["n"].(float64)
+	if !ok || n <= 1 || n != math.Trunc(n) {
+		return nil
+	}
+	// best_of ranks all the completions on a single pod, and a seed would generate the same completions on every pod
+	if _, ok := modelRequest["best_of"]; ok {
+		return nil
+	}
+	if seed, ok := modelRequest["seed"]; ok && seed != nil {
+		return nil
+	}
+	pods := min(int(n), ParallelSamplingMaxPods, len(ctx.BestPods))
+	if pods <= 1 {
+		return nil
+	}
+
+	shards := make([]*samplingShard, 0, pods)
+	offset := 0
+	for i := 0; i < pods; i++ {
+		shardN := int(n) / pods
+		if i < int(n)%pods {
+			shardN++
+		}
+		shards = append(shards, &samplingShard{pod: i, n: shardN, offset: offset})
+		offset += shardN
+	}
+	return shards
+}
+
+// proxyParallelSampling sends the shards of the request to their pods and merges their responses into a single
+// response. An error is returned when one of the pods cannot be reached, before anything is written, so that the
+// request can be proxied as a whole instead.
+func (r *Router) proxyParallelSampling(
+	c *gin.Context,
+	req *http.Request,
+	ctx *framework.Context,
+	modelRequest ModelRequest,
+	shards []*samplingShard,
+	stream bool,
+	port int32,
+	onUsage func(u handlers.OpenAIResponse),
+) error {
+	modelServerName := fmt.Sprintf("%s/%s", ctx.ModelServerName.Namespace, ctx.ModelServerName.Name)
+	var modelRouteName string
+	if routeName, exists := c.Get("modelRouteName"); exists {
+		if name, ok := routeName.(string); ok {
+			modelRouteName = name
+		}
+	}
+	for range shards {
+		r.metrics.IncActiveUpstreamRequests(modelServerName, modelRouteName)
+	}
+	defer func() {
+		for range shards {
+			r.metrics.DecActiveUpstreamRequests(modelServerName, modelRouteName)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(shards))
+	for i, shard := range shards {
+		shardReq, err := buildShardRequest(req, modelRequest, shard.n)
+		if err != nil {
+			return err
+		}
+		wg.Add(1)
+		go func(i int, shard *samplingShard) {
+			defer wg.Done()
+			shard.resp, errs[i] = doRequest(shardReq, ctx.BestPods[shard.pod], port)
+		}(i, shard)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			for _, shard := range shards {
+				if shard.resp != nil {
+					shard.resp.Body.Close()
+				}
+			}
+			return fmt.Errorf("parallel sampling request to pod %s failed: %w", ctx.BestPods[shards[i].pod].Pod.Name, err)
+		}
+	}
+	defer func() {
+		for _, shard := range shards {
+			shard.resp.Body.Close()
+		}
+	}()
+
+	for k, vv := range shards[0].resp.Header {
+		if k == "Content-Length" {
+			continue
+		}
+		for _, v := range vv {
+			c.Header(k, v)
+		}
+	}
+	c.Status(http.StatusOK)
+
+	var usage *handlers.Usage
+	if stream {
+		usage = mergeSamplingStreams(c, shards)
+	} else {
+		usage = mergeSamplingResponses(c, shards)
+	}
+	if usage != nil && usage.CompletionTokens > 0 && onUsage != nil {
+		onUsage(handlers.OpenAIResponse{Usage: *usage})
+	}
+
+	for _, shard := range shards {
+		r.Scheduler().RunPostHooks(ctx, shard.pod)
+	}
+	return nil
+}
+
+// buildShardRequest copies the request, generating n completions.
+func buildShardRequest(req *http.Request, modelRequest ModelRequest, n int) (*http.Request, error) {
+	shardRequest := make(ModelRequest, len(modelRequest))
+	for k, v := range modelRequest {
+		shardRequest[k] = v
+	}
+	shardRequest["n"] = n
+	body, err := json.Marshal(shardRequest)
+	if err != nil {
+		return nil, err
+	}
+	return cloneRequest(req.Context(), req, body), nil
+}
+
+// cloneRequest copies the request with the body and the context.
+func cloneRequest(ctx context.Context, req *http.Request, body []byte) *http.Request {
+	clone := req.Clone(ctx)
+	clone.URL.Scheme = "http"
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	clone.ContentLength = int64(len(body))
+	return clone
+}
+
+// mergeSamplingResponses writes the choices of all the shards in a single response, and returns the merged usage.
+func mergeSamplingResponses(c *gin.Context, shards []*samplingShard) *handlers.Usage {
+	var merged map[string]interface{}
+	var choices []interface{}
+	var usage *handlers.Usage
+	for _, shard := range shards {
+		body, err := io.ReadAll(shard.resp.Body)
+		if err != nil {
+			klog.Errorf("failed to read parallel sampling response: %v", err)
+			continue
+		}
+		var resp map[string]interface{}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			klog.Errorf("invalid parallel sampling response: %v", err)
+			continue
+		}
+		if merged == nil {
+			merged = resp
+		}
+		choices = append(choices, offsetChoices(resp, shard.offset)...)
+		usage = mergeUsage(usage, resp["usage"])
+	}
+	if merged == nil {
+		c.AbortWithStatusJSON(http.StatusBadGateway, "invalid responses of the model servers")
+		return nil
+	}
+
+	merged["choices"] = choices
+	if usage != nil {
+		merged["usage"] = usage
+	}
+	c.JSON(http.StatusOK, merged)
+	return usage
+}
+
+// mergeSamplingStreams forwards the chunks of all the shards as a single stream, and returns the merged usage.
+// The chunks carry the id of the first one, their choices are indexed in the merged response, and the stream ends
+// with the merged usage, when requested by the client, and a single [DONE].
+func mergeSamplingStreams(c *gin.Context, shards []*samplingShard) *handlers.Usage {
+	type chunk struct {
+		shard *samplingShard
+		data  map[string]interface{}
+	}
+	chunks := make(chan chunk)
+	var wg sync.WaitGroup
+	for _, shard := range shards {
+		wg.Add(1)
+		go func(shard *samplingShard) {
+			defer wg.Done()
+			reader := bufio.NewReader(shard.resp.Body)
+			for {
+				line, err := reader.ReadString('\n')
+				data := strings.TrimSpace(strings.TrimPrefix(line, sseDataPrefix))
+				if strings.HasPrefix(line, sseDataPrefix) && data != sseDone {
+					var parsed map[string]interface{}
+					if jsonErr := json.Unmarshal([]byte(data), &parsed); jsonErr == nil {
+						chunks <- chunk{shard: shard, data: parsed}
+					}
+				}
+				if err != nil {
+					if err != io.EOF {
+						klog.Errorf("error reading parallel sampling stream: %v", err)
+					}
+					return
+				}
+			}
+		}(shard)
+	}
+	go func() {
+		wg.Wait()
+		close(chunks)
+	}()
+
+	var first map[string]interface{}
+	// The last usage of each shard is kept, some engines report a running usage in every chunk
+	usages := make(map[*samplingShard]interface{})
+	for ch := range chunks {
+		if first == nil {
+			first = ch.data
+		}
+		if u, ok := ch.data["usage"]; ok && u != nil {
+			usages[ch.shard] = u
+		}
+		delete(ch.data, "usage")
+		choices := offsetChoices(ch.data, ch.shard.offset)
+		if len(choices) == 0 {
+			continue
+		}
+		ch.data["choices"] = choices
+		ch.data["id"] = first["id"]
+		writeSSEData(c, ch.data)
+	}
+
+	var usage *handlers.Usage
+	for _, shard := range shards {
+		if u, ok := usages[shard]; ok {
+			usage = mergeUsage(usage, u)
+		}
+	}
+	// The usage requested by the router is not sent to the client
+	if v, ok := c.Get(common.TokenUsageKey); (!ok || !v.(bool)) && usage != nil && first != nil {
+		writeSSEData(c, map[string]interface{}{
+			"id":      first["id"],
+			"object":  first["object"],
+			"created": first["created"],
+			"model":   first["model"],
+			"choices": []interface{}{},
+			"usage":   usage,
+		})
+	}
+	_, _ = c.Writer.WriteString(sseDataPrefix + sseDone + "\n\n")
+	c.Writer.Flush()
+	return usage
+}
+
+func writeSSEData(c *gin.Context, data map[string]interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		klog.Errorf("failed to marshal parallel sampling chunk: %v", err)
+		return
+	}
+	_, _ = c.Writer.WriteString(sseDataPrefix + string(body) + "\n\n")
+	c.Writer.Flush()
+}
+
+// offsetChoices returns the choices of a response or chunk, with their index shifted by offset.
+func offsetChoices(resp map[string]interface{}, offset int) []interface{} {
+	choices, _ := resp["choices"].([]interface{})
+	for _, choice := range choices {
+		if m, ok := choice.(map[string]interface{}); ok {
+			index, _ := m["index"].(float64)
+			m["index"] = int(index) + offset
+		}
+	}
+	return choices
+}
+
+// mergeUsage adds the usage of a shard. The prompt is counted once, as it is for the n completions of a single pod.
+func mergeUsage(merged *handlers.Usage, usage interface{}) *handlers.Usage {
+	m, ok := usage.(map[string]interface{})
+	if !ok {
+		return merged
+	}
+	promptTokens, _ := m["prompt_tokens"].(float64)
+	completionTokens, _ := m["completion_tokens"].(float64)
+	if merged == nil {
+		merged = &handlers.Usage{PromptTokens: int(promptTokens)}
+	}
+	merged.CompletionTokens += int(completionTokens)
+	merged.TotalTokens = merged.PromptTokens + merged.CompletionTokens
+	return merged
+}
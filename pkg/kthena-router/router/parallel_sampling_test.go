@@ -0,0 +1,222 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"istio.io/istio/pkg/util/sets"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+func TestParallelSamplingShards(t *testing.T) {
+	defer func(maxPods int) { ParallelSamplingMaxPods = maxPods }(ParallelSamplingMaxPods)
+	ctx := &framework.Context{
+		RequestType: common.RequestTypeGeneration,
+		BestPods:    []*datastore.PodInfo{buildPodInfo("pod-1", "1.1.1.1"), buildPodInfo("pod-2", "1.1.1.2"), buildPodInfo("pod-3", "1.1.1.3")},
+	}
+
+	ParallelSamplingMaxPods = 1
+	assert.Nil(t, parallelSamplingShards(ctx, ModelRequest{"n": float64(4)}))
+
+	ParallelSamplingMaxPods = 4
+	shards := parallelSamplingShards(ctx, ModelRequest{"n": float64(5)})
+	require.Len(t, shards, 3)
+	assert.Equal(t, []int{2, 2, 1}, []int{shards[0].n, shards[1].n, shards[2].n})
+	assert.Equal(t, []int{0, 2, 4}, []int{shards[0].offset, shards[1].offset, shards[2].offset})
+
+	shards = parallelSamplingShards(ctx, ModelRequest{"n": float64(2)})
+	require.Len(t, shards, 2)
+	assert.Equal(t, []int{1, 1}, []int{shards[0].n, shards[1].n})
+
+	for _, req := range []ModelRequest{
+		{},
+		{"n": float64(1)},
+		{"n": 1.5},
+		{"n": float64(4), "best_of": float64(8)},
+		{"n": float64(4), "seed": float64(42)},
+	} {
+		assert.Nil(t, parallelSamplingShards(ctx, req), req)
+	}
+
+	ctx.RequestType = common.RequestTypeEmbedding
+	assert.Nil(t, parallelSamplingShards(ctx, ModelRequest{"n": float64(4)}))
+	ctx.RequestType = common.RequestTypeGeneration
+	ctx.BestPods = ctx.BestPods[:1]
+	assert.Nil(t, parallelSamplingShards(ctx, ModelRequest{"n": float64(4)}))
+}
+
+// newSamplingBackend serves n completions per request, one chunk per choice when streaming.
+func newSamplingBackend(requests *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var req ModelRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		n := 1
+		if v, ok := req["n"].(float64); ok {
+			n = int(v)
+		}
+		id := "cmpl-" + strconv.Itoa(int(requests.Load()))
+		usage := map[string]int{"prompt_tokens": 5, "completion_tokens": 3 * n, "total_tokens": 5 + 3*n}
+		if stream, _ := req["stream"].(bool); !stream {
+			choices := make([]map[string]interface{}, 0, n)
+			for i := 0; i < n; i++ {
+				choices = append(choices, map[string]interface{}{"index": i, "text": "sample"})
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": id, "object": "text_completion", "choices": choices, "usage": usage})
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < n; i++ {
+			chunk, _ := json.Marshal(map[string]interface{}{"id": id, "object": "text_completion", "choices": []map[string]interface{}{{"index": i, "text": "sample"}}})
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+		chunk, _ := json.Marshal(map[string]interface{}{"id": id, "object": "text_completion", "choices": []interface{}{}, "usage": usage})
+		fmt.Fprintf(w, "data: %s\n\ndata: [DONE]\n\n", chunk)
+	}))
+}
+
+func TestRouter_HandlerFunc_ParallelSampling(t *testing.T) {
+	defer func(maxPods int) { ParallelSamplingMaxPods = maxPods }(ParallelSamplingMaxPods)
+	ParallelSamplingMaxPods = 2
+
+	var requests atomic.Int32
+	backend := newSamplingBackend(&requests)
+	defer backend.Close()
+	backendURL, _ := url.Parse(backend.URL)
+	backendPort, _ := strconv.Atoi(backendURL.Port())
+
+	store := datastore.New()
+	router := NewRouter(store, "")
+	modelServer := &aiv1alpha1.ModelServer{
+		ObjectMeta: v1.ObjectMeta{Name: "ms-1", Namespace: "default"},
+		Spec: aiv1alpha1.ModelServerSpec{
+			WorkloadPort:    aiv1alpha1.WorkloadPort{Port: int32(backendPort)},
+			InferenceEngine: "vLLM",
+		},
+	}
+	podNames := sets.New[types.NamespacedName]()
+	for _, name := range []string{"pod-1", "pod-2"} {
+		podNames.Insert(types.NamespacedName{Name: name, Namespace: "default"})
+	}
+	store.AddOrUpdateModelServer(modelServer, podNames)
+	for _, name := range []string{"pod-1", "pod-2"} {
+		store.AddOrUpdatePod(&corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     corev1.PodStatus{PodIP: backendURL.Hostname(), Phase: corev1.PodRunning},
+		}, []*aiv1alpha1.ModelServer{modelServer})
+	}
+	store.AddOrUpdateModelRoute(&aiv1alpha1.ModelRoute{
+		ObjectMeta: v1.ObjectMeta{Name: "mr-1", Namespace: "default"},
+		Spec: aiv1alpha1.ModelRouteSpec{
+			ModelName: "test-model",
+			Rules:     []*aiv1alpha1.Rule{{TargetModels: []*aiv1alpha1.TargetModel{{ModelServerName: "ms-1"}}}},
+		},
+	})
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/v1/completions", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		router.HandlerFunc()(c)
+		return w
+	}
+
+	t.Run("non-streaming", func(t *testing.T) {
+		requests.Store(0)
+		w := serve(`{"model": "test-model", "prompt": "hello", "n": 3}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int32(2), requests.Load())
+
+		var resp struct {
+			Choices []struct {
+				Index int `json:"index"`
+			} `json:"choices"`
+			Usage map[string]int `json:"usage"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Choices, 3)
+		for i, choice := range resp.Choices {
+			assert.Equal(t, i, choice.Index)
+		}
+		assert.Equal(t, map[string]int{"prompt_tokens": 5, "completion_tokens": 9, "total_tokens": 14}, resp.Usage)
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		requests.Store(0)
+		w := serve(`{"model": "test-model", "prompt": "hello", "n": 4, "stream": true, "stream_options": {"include_usage": true}}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int32(2), requests.Load())
+
+		var ids []string
+		indexes := sets.New[int]()
+		var usage map[string]int
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n\n")
+		require.Equal(t, "data: [DONE]", lines[len(lines)-1])
+		for _, line := range lines[:len(lines)-1] {
+			var chunk struct {
+				ID      string `json:"id"`
+				Choices []struct {
+					Index int `json:"index"`
+				} `json:"choices"`
+				Usage map[string]int `json:"usage"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &chunk))
+			ids = append(ids, chunk.ID)
+			for _, choice := range chunk.Choices {
+				indexes.Insert(choice.Index)
+			}
+			if chunk.Usage != nil {
+				usage = chunk.Usage
+			}
+		}
+		assert.Equal(t, sets.New(0, 1, 2, 3), indexes)
+		assert.Len(t, sets.New(ids...), 1, "all the chunks carry the same id")
+		assert.Equal(t, map[string]int{"prompt_tokens": 5, "completion_tokens": 12, "total_tokens": 17}, usage)
+	})
+
+	t.Run("single completion is not split", func(t *testing.T) {
+		requests.Store(0)
+		w := serve(`{"model": "test-model", "prompt": "hello"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		body, _ := io.ReadAll(w.Body)
+		assert.Contains(t, string(body), `"index":0`)
+		assert.Equal(t, int32(1), requests.Load())
+	})
+}
@@ -0,0 +1,143 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"istio.io/istio/pkg/env"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/features"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/auth"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/responsecache"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/tenancy"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/utils"
+)
+
+var (
+	responseCacheEnabled      = env.RegisterBoolVar("RESPONSE_CACHE_ENABLED", false, "Enable caching of deterministic (temperature=0) completions").Get()
+	responseCacheBackend      = env.RegisterStringVar("RESPONSE_CACHE_BACKEND", responsecache.BackendMemory, "Response cache backend, memory or redis").Get()
+	responseCacheTTL          = env.RegisterDurationVar("RESPONSE_CACHE_TTL", 5*time.Minute, "How long a cached response is served").Get()
+	responseCacheMaxEntries   = env.RegisterIntVar("RESPONSE_CACHE_MAX_ENTRIES", 10000, "Maximum number of responses kept by the memory backend").Get()
+	responseCacheMaxBodyBytes = env.RegisterIntVar("RESPONSE_CACHE_MAX_BODY_BYTES", 1<<20, "Largest response body that will be cached").Get()
+)
+
+func newResponseCache() *responsecache.ResponseCache {
+	if !responseCacheEnabled {
+		return nil
+	}
+	if !features.DefaultFeatureGate.Enabled(features.ResponseCache) {
+		klog.Warningf("RESPONSE_CACHE_ENABLED is set but feature gate %s is disabled, response caching is disabled", features.ResponseCache)
+		return nil
+	}
+	config := &responsecache.Config{
+		Backend:      responseCacheBackend,
+		TTL:          responseCacheTTL,
+		MaxEntries:   responseCacheMaxEntries,
+		MaxBodyBytes: responseCacheMaxBodyBytes,
+	}
+	var redisClient *redis.Client
+	if config.Backend == responsecache.BackendRedis {
+		redisClient = utils.TryGetRedisClient()
+	}
+	cache, err := responsecache.NewResponseCache(config, redisClient)
+	if err != nil {
+		klog.Errorf("failed to create response cache, response caching is disabled: %v", err)
+		return nil
+	}
+	klog.Infof("response cache enabled with %s backend, ttl %v", config.Backend, config.TTL)
+	return cache
+}
+
+// handleResponseCache serves the request from the response cache when possible.
+// On a cache miss, the response writer is wrapped so that the proxied response can be
+// captured, and the returned function stores it once the request has completed.
+func (r *Router) handleResponseCache(c *gin.Context, modelName string, modelRequest ModelRequest) (bool, func()) {
+	noop := func() {}
+	if r.responseCache == nil {
+		return false, noop
+	}
+	// The responses are cached per tenant, as the tenants may serve the same model names with different backends.
+	var tenantName string
+	if tenant := tenancy.FromContext(c.Request.Context()); tenant != nil {
+		tenantName = tenant.Name
+	}
+	key, ok := responsecache.Key(tenantName, r.cacheConsumer(c), modelName, modelRequest)
+	if !ok {
+		return false, noop
+	}
+	lookup, store := responsecache.ParseCacheControl(c.GetHeader("Cache-Control"))
+
+	ctx := c.Request.Context()
+	if lookup {
+		if entry, found := r.responseCache.Get(ctx, key); found {
+			klog.V(4).Infof("response cache hit for model %s", modelName)
+			c.Header(responsecache.CacheStatusHeader, responsecache.CacheStatusHit)
+			c.Header("Age", strconv.Itoa(int(time.Since(entry.CreatedAt).Seconds())))
+			accesslog.MarkUpstreamStart(c)
+			c.Data(entry.StatusCode, entry.ContentType, entry.Body)
+			accesslog.MarkUpstreamEnd(c)
+			return true, noop
+		}
+	}
+
+	c.Header(responsecache.CacheStatusHeader, responsecache.CacheStatusMiss)
+	if !store {
+		return false, noop
+	}
+	writer := responsecache.NewCaptureWriter(c.Writer, responseCacheMaxBodyBytes)
+	c.Writer = writer
+	return false, func() {
+		if c.IsAborted() || writer.Status() != http.StatusOK {
+			return
+		}
+		entry := writer.Entry()
+		if entry == nil {
+			return
+		}
+		entry.CreatedAt = time.Now()
+		r.responseCache.Set(ctx, key, entry)
+		c.Writer = writer.ResponseWriter
+		klog.V(4).Infof("stored response in cache for model %s", modelName)
+	}
+}
+
+// cacheConsumer returns the identity of the consumer sending the request, which scopes its cached responses:
+// the subject of its JWT, or else the credentials it presents. It is empty for the anonymous requests.
+func (r *Router) cacheConsumer(c *gin.Context) string {
+	if user := c.GetString(common.UserIdKey); user != "" {
+		return "user:" + user
+	}
+	var authorizer *auth.ModelAuthorizer
+	if config := r.config.Load(); config != nil {
+		authorizer = config.authorizer
+	}
+	if apiKey := c.GetHeader(authorizer.APIKeyHeader()); apiKey != "" {
+		return "apikey:" + apiKey
+	}
+	if authorization := c.GetHeader("Authorization"); authorization != "" {
+		return "authorization:" + authorization
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/responsecache"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/tenancy"
+)
+
+func TestHandleResponseCacheTenants(t *testing.T) {
+	cache, err := responsecache.NewResponseCache(&responsecache.Config{
+		Backend:      responsecache.BackendMemory,
+		TTL:          time.Minute,
+		MaxEntries:   10,
+		MaxBodyBytes: 1024,
+	}, nil)
+	require.NoError(t, err)
+	r := &Router{responseCache: cache}
+
+	engine := gin.New()
+	engine.POST("/v1/completions", func(c *gin.Context) {
+		request := ModelRequest{"model": "llama", "prompt": "hello", "temperature": float64(0)}
+		hit, storeResponse := r.handleResponseCache(c, "llama", request)
+		if hit {
+			return
+		}
+		defer storeResponse()
+		// The tenants serve the model with different backends
+		c.Data(http.StatusOK, "text/plain", []byte(c.GetHeader("X-Backend")))
+	})
+
+	send := func(tenant, backend string) (string, string) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/completions", strings.NewReader("{}"))
+		req.Header.Set("X-Backend", backend)
+		if tenant != "" {
+			req = req.WithContext(tenancy.WithTenant(req.Context(), &tenancy.Tenant{Name: tenant}))
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String(), w.Header().Get(responsecache.CacheStatusHeader)
+	}
+
+	body, status := send("team-a", "backend-a")
+	assert.Equal(t, "backend-a", body)
+	assert.Equal(t, responsecache.CacheStatusMiss, status)
+
+	// The identical request of another tenant is not served the response of the first tenant
+	body, status = send("team-b", "backend-b")
+	assert.Equal(t, "backend-b", body)
+	assert.Equal(t, responsecache.CacheStatusMiss, status)
+	body, status = send("", "backend-default")
+	assert.Equal(t, "backend-default", body)
+	assert.Equal(t, responsecache.CacheStatusMiss, status)
+
+	// Within a tenant, the cached response is shared
+	body, status = send("team-a", "other")
+	assert.Equal(t, "backend-a", body)
+	assert.Equal(t, responsecache.CacheStatusHit, status)
+	body, status = send("team-b", "other")
+	assert.Equal(t, "backend-b", body)
+	assert.Equal(t, responsecache.CacheStatusHit, status)
+}
+
+func TestHandleResponseCacheConsumers(t *testing.T) {
+	cache, err := responsecache.NewResponseCache(&responsecache.Config{
+		Backend:      responsecache.BackendMemory,
+		TTL:          time.Minute,
+		MaxEntries:   10,
+		MaxBodyBytes: 1024,
+	}, nil)
+	require.NoError(t, err)
+	r := &Router{responseCache: cache}
+
+	engine := gin.New()
+	engine.POST("/v1/completions", func(c *gin.Context) {
+		if user := c.GetHeader("X-User"); user != "" {
+			c.Set(common.UserIdKey, user)
+		}
+		request := ModelRequest{"model": "llama", "prompt": "hello", "temperature": float64(0)}
+		hit, storeResponse := r.handleResponseCache(c, "llama", request)
+		if hit {
+			return
+		}
+		defer storeResponse()
+		c.Data(http.StatusOK, "text/plain", []byte(c.GetHeader("X-Answer")))
+	})
+
+	send := func(header, value, answer string) (string, string) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/completions", strings.NewReader("{}"))
+		req.Header.Set("X-Answer", answer)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String(), w.Header().Get(responsecache.CacheStatusHeader)
+	}
+
+	body, status := send("X-API-Key", "key-a", "answer-a")
+	assert.Equal(t, "answer-a", body)
+	assert.Equal(t, responsecache.CacheStatusMiss, status)
+
+	// The consumers presenting other credentials, or none, are not served the response cached for the first one
+	for _, consumer := range []struct{ header, value string }{
+		{header: "X-API-Key", value: "key-b"},
+		{header: "Authorization", value: "Bearer token"},
+		{header: "X-User", value: "alice"},
+		{},
+	} {
+		body, status = send(consumer.header, consumer.value, "answer-"+consumer.value)
+		assert.Equal(t, "answer-"+consumer.value, body)
+		assert.Equal(t, responsecache.CacheStatusMiss, status)
+	}
+
+	// The consumer is served its own cached response
+	body, status = send("X-API-Key", "key-a", "other")
+	assert.Equal(t, "answer-a", body)
+	assert.Equal(t, responsecache.CacheStatusHit, status)
+}
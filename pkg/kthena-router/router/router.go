@@ -22,9 +22,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -34,31 +37,50 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/features"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/connectors"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
-	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/auth"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/compare"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/concurrency"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/fault"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/guardrail"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/ratelimit"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/responsecache"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/tokenizer"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/handlers"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/limits"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
-	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/upstream"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/utils"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
 )
 
 var EnableFairnessScheduling = env.RegisterBoolVar("ENABLE_FAIRNESS_SCHEDULING", false, "Enable fairness scheduling for inference requests").Get()
 
+// StreamIdleTimeout is the time the response of a model server may go without data before it is closed.
+var StreamIdleTimeout = env.RegisterDurationVar("STREAM_IDLE_TIMEOUT", 0, "Time the response of a model server may go without data before it is closed, 0 disables the timeout").Get()
+
 type Router struct {
-	scheduler       scheduler.Scheduler
-	authenticator   *auth.JWTAuthenticator
+	// config holds the scheduler and the auth filters built from the configuration file, it is replaced on reload
+	config          atomic.Pointer[configState]
+	configPath      string
 	store           datastore.Store
 	loadRateLimiter *ratelimit.TokenRateLimiter
+	concurrency     *concurrency.Limiters
+	apiKeys         *apiKeyCache
 	accessLogger    accesslog.AccessLogger
 	metrics         *metrics.Metrics
 	tokenizer       tokenizer.Tokenizer
+	responseCache   *responsecache.ResponseCache
+	comparator      *compare.Comparator
+	mirrorSlots     chan struct{}
+	decisions       *scheduler.DecisionStore
+	guardrails      *guardrail.Guardrails
+	faults          *fault.Injector
 
 	// KV Connector management
 	connectorFactory *connectors.Factory
@@ -67,6 +89,7 @@ type Router struct {
 func NewRouter(store datastore.Store, routerConfigPath string) *Router {
 	// Create a unified rate limiter for all models
 	loadRateLimiter := ratelimit.NewTokenRateLimiter()
+	concurrencyLimiters := concurrency.NewLimiters()
 
 	// Use global metrics instance
 	metricsInstance := metrics.DefaultMetrics
@@ -74,10 +97,18 @@ func NewRouter(store datastore.Store, routerConfigPath string) *Router {
 	// Initialize tokenizer
 	tokenizerInstance := tokenizer.NewSimpleEstimateTokenizer()
 
+	decisions := scheduler.NewDecisionStore(schedulingDecisionsPerModel)
+
+	guardrails := guardrail.New(&http.Client{})
+
 	store.RegisterCallback("ModelRoute", func(data datastore.EventData) {
 		switch data.EventType {
 		case datastore.EventAdd, datastore.EventUpdate:
-			if data.ModelRoute == nil || data.ModelRoute.Spec.RateLimit == nil {
+			if data.ModelRoute == nil {
+				return
+			}
+			concurrencyLimiters.AddOrUpdateLimiter(data.ModelName, data.ModelRoute.Spec.Concurrency)
+			if data.ModelRoute.Spec.RateLimit == nil {
 				return
 			}
 			klog.Infof("add or update rate limit for model %s", data.ModelName)
@@ -90,10 +121,15 @@ func NewRouter(store datastore.Store, routerConfigPath string) *Router {
 		case datastore.EventDelete:
 			klog.Infof("delete rate limit for model %s", data.ModelName)
 			loadRateLimiter.DeleteLimiter(data.ModelName)
+			concurrencyLimiters.DeleteLimiter(data.ModelName)
+			decisions.Delete(data.ModelName)
+			guardrails.Prune(func(key string) bool {
+				return store.GetModelRoute(key) != nil
+			})
 		}
 	})
 
-	routerConfig, err := conf.ParseRouterConfig(routerConfigPath)
+	config, err := loadConfig(store, routerConfigPath)
 	if err != nil {
 		klog.Fatalf("failed to parse router config: %v", err)
 	}
@@ -129,20 +165,59 @@ func NewRouter(store datastore.Store, routerConfigPath string) *Router {
 		klog.Fatalf("failed to create access logger: %v", err)
 	}
 
-	return &Router{
+	r := &Router{
+		configPath:       routerConfigPath,
 		store:            store,
-		scheduler:        scheduler.NewScheduler(store, routerConfig),
-		authenticator:    auth.NewJWTAuthenticator(routerConfig),
+		responseCache:    newResponseCache(),
+		comparator:       newComparator(metricsInstance),
+		mirrorSlots:      make(chan struct{}, trafficMirrorMaxConcurrency),
+		decisions:        decisions,
+		guardrails:       guardrails,
 		loadRateLimiter:  loadRateLimiter,
+		concurrency:      concurrencyLimiters,
 		accessLogger:     accessLogger,
 		metrics:          metricsInstance,
 		tokenizer:        tokenizerInstance,
 		connectorFactory: connectors.NewDefaultFactory(),
 	}
+	if EnableFaultInjection {
+		r.faults = fault.NewInjector()
+	}
+	r.config.Store(config)
+	metricsInstance.RecordConfigReload(config.version, nil)
+	return r
 }
 
 type ModelRequest map[string]interface{}
 
+// Deregister removes the router from the state shared with the other router replicas.
+// It must be called once the router no longer serves requests.
+func (r *Router) Deregister() {
+	r.loadRateLimiter.Deregister()
+	// The pending audit records are written before the router exits
+	if err := r.config.Load().auditor.Close(); err != nil {
+		klog.Errorf("Failed to close the audit sink: %v", err)
+	}
+	// The report of the current period is written too
+	if err := r.config.Load().chargeback.Close(); err != nil {
+		klog.Errorf("Failed to close the chargeback sink: %v", err)
+	}
+	// And the compared samples not exported yet
+	if err := r.comparator.Close(); err != nil {
+		klog.Errorf("Failed to close the traffic compare exporters: %v", err)
+	}
+}
+
+// Scheduler returns the scheduler picking the pods of the requests.
+func (r *Router) Scheduler() scheduler.Scheduler {
+	return r.config.Load().scheduler
+}
+
+// Faults returns the fault injection rules applied to the requests, nil when the fault injection is disabled.
+func (r *Router) Faults() *fault.Injector {
+	return r.faults
+}
+
 func (r *Router) HandlerFunc() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Step 1: Parse and validate request
@@ -155,6 +230,11 @@ func (r *Router) HandlerFunc() gin.HandlerFunc {
 		// step 2: Detection of rate limit
 		modelName := modelRequest["model"].(string)
 
+		// Embedding and rerank responses are returned at once, never stream them
+		if !utils.GetRequestType(c.Request.URL.Path).IsGenerative() {
+			delete(modelRequest, "stream")
+		}
+
 		// Set model name in access log
 		accesslog.SetModelName(c, modelName)
 
@@ -165,6 +245,20 @@ func (r *Router) HandlerFunc() gin.HandlerFunc {
 		path := c.Request.URL.Path
 		metricsRecorder := metrics.NewRequestMetricsRecorder(r.metrics, modelName, path)
 
+		// Enforce the access policies of the consumer before spending any work on the request
+		if err := r.config.Load().authorizer.Authorize(c, modelName); err != nil {
+			accesslog.SetError(c, "authorization", err.Error())
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			metricsRecorder.Finish(strconv.Itoa(http.StatusForbidden), "authorization")
+			return
+		}
+		if err := r.authorizeTenant(c); err != nil {
+			accesslog.SetError(c, "tenant_authorization", err.Error())
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			metricsRecorder.Finish(strconv.Itoa(http.StatusForbidden), "tenant_authorization")
+			return
+		}
+
 		// Increment downstream request count at request start
 		r.metrics.IncActiveDownstreamRequests(modelName)
 		defer func() {
@@ -180,6 +274,13 @@ func (r *Router) HandlerFunc() gin.HandlerFunc {
 			return
 		}
 		promptStr := utils.GetPromptString(prompt)
+		r.setAuditPrompt(c, modelName, promptStr)
+
+		// Run the guardrails of the route on the prompt before the request is routed
+		guardrailRoute := r.guardrailRoute(modelName, c.Request)
+		if !r.checkRequestGuardrails(c, guardrailRoute, modelName, promptStr, metricsRecorder) {
+			return
+		}
 
 		// Calculate input tokens for metrics using tokenizer
 		inputTokens, err := r.tokenizer.CalculateTokenNum(promptStr)
@@ -197,6 +298,14 @@ func (r *Router) HandlerFunc() gin.HandlerFunc {
 		// Record input tokens immediately
 		metricsRecorder.RecordInputTokens(inputTokens)
 
+		// Apply the quotas of the tenant of the request
+		if err := r.admitTenant(c, inputTokens); err != nil {
+			accesslog.SetError(c, "tenant_quota", err.Error())
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			metricsRecorder.Finish(strconv.Itoa(http.StatusTooManyRequests), "tenant_quota")
+			return
+		}
+
 		// Apply rate limiting using the unified rate limiter
 		if err := r.loadRateLimiter.RateLimit(modelName, promptStr); err != nil {
 			var errorMsg string
@@ -225,6 +334,19 @@ func (r *Router) HandlerFunc() gin.HandlerFunc {
 			return
 		}
 
+		// Apply the concurrent stream limit for streaming requests
+		if isStreaming(modelRequest) {
+			release, err := r.loadRateLimiter.AcquireStream(modelName)
+			if err != nil {
+				accesslog.SetError(c, "concurrent_stream_limit", err.Error())
+				metricsRecorder.RecordRateLimitExceeded(metrics.LimitTypeConcurrentStreams)
+				abortWithStreamLimitExceeded(c, err)
+				metricsRecorder.Finish(strconv.Itoa(http.StatusTooManyRequests), "concurrent_stream_limit")
+				return
+			}
+			defer release()
+		}
+
 		requestID := uuid.New().String()
 		if c.Request.Header.Get("x-request-id") == "" {
 			c.Request.Header.Set("x-request-id", requestID)
@@ -233,13 +355,31 @@ func (r *Router) HandlerFunc() gin.HandlerFunc {
 		// Store metrics recorder in context for use in other functions
 		c.Set("metricsRecorder", metricsRecorder)
 
-		// step 3.1: load balancing
+		// step 3: serve deterministic completions from the response cache
+		hit, storeResponse := r.handleResponseCache(c, modelName, modelRequest)
+		if hit {
+			metricsRecorder.Finish(strconv.Itoa(c.Writer.Status()), "")
+			return
+		}
+		defer storeResponse()
+
+		// Hold back the response until the guardrails of the route have checked it
+		defer r.guardResponse(c, guardrailRoute, modelName, modelRequest)()
+
+		// Wait for a slot of the concurrency limit of the model, or spill over to its fallback model
+		releaseConcurrency, ok := r.acquireConcurrency(c, modelRequest, metricsRecorder)
+		if !ok {
+			return
+		}
+		defer releaseConcurrency()
+
+		// step 4.1: load balancing
 		if !EnableFairnessScheduling {
 			r.doLoadbalance(c, modelRequest)
 			return
 		}
 
-		// step 3.2: load balancing for Fairness scheduling enabled case
+		// step 4.2: load balancing for Fairness scheduling enabled case
 		if err := r.handleFairnessScheduling(c, modelRequest, requestID, modelName); err != nil {
 			accesslog.SetError(c, "scheduling", err.Error())
 			metricsRecorder.Finish(strconv.Itoa(c.Writer.Status()), "scheduling")
@@ -250,8 +390,9 @@ func (r *Router) HandlerFunc() gin.HandlerFunc {
 
 func (r *Router) doLoadbalance(c *gin.Context, modelRequest ModelRequest) {
 	modelName := modelRequest["model"].(string)
+	requestedModel := modelName
 	// step 3: Find pods and model server details
-	modelServerName, isLora, modelRoute, err := r.store.MatchModelServer(modelName, c.Request)
+	modelServerName, isLora, modelRoute, adapter, err := r.matchModelServer(modelName, c.Request)
 	if err != nil {
 		accesslog.SetError(c, "model_server_matching", fmt.Sprintf("can't find corresponding model server: %v", err))
 		c.AbortWithStatusJSON(http.StatusNotFound, fmt.Sprintf("can't find corresponding model server: %v", err))
@@ -260,21 +401,60 @@ func (r *Router) doLoadbalance(c *gin.Context, modelRequest ModelRequest) {
 	klog.V(4).Infof("modelServer is %v, is_lora: %v", modelServerName, isLora)
 	pods, modelServer, err := r.getPodsAndServer(modelServerName)
 	if err != nil || len(pods) == 0 {
+		if r.fallback(c, modelRequest, requestedModel, modelRoute, metrics.FallbackReasonNoPods) {
+			return
+		}
 		klog.Errorf("failed to get pods and model server: %v, %v", modelServerName, err)
 		accesslog.SetError(c, "pod_discovery", fmt.Sprintf("can't find model server: %v", modelServerName))
 		c.AbortWithStatusJSON(http.StatusNotFound, fmt.Sprintf("can't find model server: %v", modelServerName))
 		return
 	}
+	if r.overLatencyBudget(modelServerName, modelRoute) && !shouldProbe() &&
+		r.fallback(c, modelRequest, requestedModel, modelRoute, metrics.FallbackReasonLatencyBudget) {
+		return
+	}
+	if modelRoute != nil && modelRoute.Spec.Fallback != nil && !c.GetBool(fallbackKey) {
+		c.Header(servedModelHeader, requestedModel)
+	}
+
+	// Measure the request against the service level objectives of the model server once it is served
+	failed := false
+	measured := r.measureSLO(c, modelServerName, modelServer)
+	defer func() { measured(failed) }()
+
+	if adapter != "" {
+		modelName = adapter
+		modelRequest["model"] = adapter
+	}
+
+	// Replay a sample of the requests to the baseline and the candidate before the model is rewritten
+	r.handleTrafficCompare(c, modelRoute, modelRequest, isLora)
+
+	// Mirror a sample of the requests of the rule to its shadow model server, and measure the primary against it
+	matchedModel := requestedModel
+	if adapter != "" {
+		matchedModel, _, _ = splitLoraModelName(requestedModel)
+	}
+	mirrored := r.mirrorRequest(c, modelRoute, matchedModel, modelServerName, modelRequest, isLora)
+	defer func() { mirrored(failed) }()
 
 	model := modelServer.Spec.Model
 	if model != nil && !isLora {
 		modelRequest["model"] = *model
 	}
 
+	requestType := utils.GetRequestType(c.Request.URL.Path)
 	var pdGroup *v1alpha1.PDGroup
-	if modelServer.Spec.WorkloadSelector != nil {
+	// Only generation requests can be disaggregated into prefill and decode
+	if modelServer.Spec.WorkloadSelector != nil && requestType.IsGenerative() {
 		pdGroup = modelServer.Spec.WorkloadSelector.PDGroup
 	}
+	if pdGroup != nil && !features.DefaultFeatureGate.Enabled(features.PDDisaggregation) {
+		accesslog.SetError(c, "pd_disaggregation", "PD disaggregation is disabled")
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, fmt.Sprintf("model server %s/%s is disaggregated, but feature gate %s is disabled",
+			modelServer.Namespace, modelServer.Name, features.PDDisaggregation))
+		return
+	}
 	prompt, err := utils.ParsePrompt(modelRequest)
 	if err != nil {
 		accesslog.SetError(c, "prompt_parsing", "prompt not found")
@@ -290,19 +470,31 @@ func (r *Router) doLoadbalance(c *gin.Context, modelRequest ModelRequest) {
 	}
 
 	ctx := &framework.Context{
-		Model:           modelName,
-		Prompt:          prompt,
-		ModelServerName: modelServerName,
-		PDGroup:         pdGroup,
-		MetricsRecorder: metricsRecorder,
+		Model:            modelName,
+		Prompt:           prompt,
+		LoraAdapter:      loraAdapterOf(modelName, isLora),
+		ServedModel:      servedModelOf(modelServer, modelName, isLora),
+		RequestType:      requestType,
+		BatchSize:        utils.GetBatchSize(modelRequest),
+		ModelServerName:  modelServerName,
+		PDGroup:          pdGroup,
+		SchedulingPolicy: modelServer.Spec.SchedulingPolicy,
+		MetricsRecorder:  metricsRecorder,
+		Decision:         r.newDecision(c, modelName, modelServerName),
 	}
 
-	err = r.scheduler.Schedule(ctx, pods)
+	err = r.Scheduler().Schedule(ctx, pods)
+	r.recordDecision(c, ctx.Decision, err)
 	if err != nil {
+		failed = true
+		if r.fallback(c, modelRequest, requestedModel, modelRoute, metrics.FallbackReasonScheduling) {
+			return
+		}
 		accesslog.SetError(c, "scheduling", fmt.Sprintf("can't schedule to target pod: %v", err))
 		c.AbortWithStatusJSON(http.StatusBadRequest, fmt.Sprintf("can't schedule to target pod: %v", err))
 		return
 	}
+	r.setGPUShare(c, ctx)
 
 	// Set complete request routing information in access log
 	modelServerFullName := fmt.Sprintf("%s/%s", modelServerName.Namespace, modelServerName.Name)
@@ -321,14 +513,69 @@ func (r *Router) doLoadbalance(c *gin.Context, modelRequest ModelRequest) {
 		accesslog.SetRequestRouting(c, modelRouteName, modelServerFullName, "")
 	}
 
+	proceed, done := r.injectFaults(c, requestedModel, modelRouteName, isStreaming(modelRequest))
+	if !proceed {
+		return
+	}
+	defer done()
+
+	defer r.measureTimeToFirstToken(c, modelServerName, modelRoute)()
 	req := c.Request
 	if err := r.proxyModelEndpoint(c, req, ctx, modelRequest, modelServer.Spec.WorkloadPort.Port); err != nil {
 		klog.Errorf("request failed reqID: %s: %v", c.Request.Header.Get("x-request-id"), err)
+		failed = true
 		accesslog.SetError(c, "proxy", "request processing failed")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, "request processing failed")
 	}
 }
 
+// matchModelServer matches the model server serving a model. A model "<base>:<adapter>" without a route of its own
+// addresses a LoRA adapter loaded dynamically on the model servers of the base model, and the adapter name is returned.
+func (r *Router) matchModelServer(modelName string, req *http.Request) (types.NamespacedName, bool, *v1alpha1.ModelRoute, string, error) {
+	modelServerName, isLora, modelRoute, err := r.store.MatchModelServer(modelName, req)
+	if err == nil {
+		return modelServerName, isLora, modelRoute, "", nil
+	}
+	base, adapter, ok := splitLoraModelName(modelName)
+	if !ok {
+		return types.NamespacedName{}, false, nil, "", err
+	}
+	modelServerName, _, modelRoute, baseErr := r.store.MatchModelServer(base, req)
+	if baseErr != nil {
+		return types.NamespacedName{}, false, nil, "", err
+	}
+	return modelServerName, true, modelRoute, adapter, nil
+}
+
+// splitLoraModelName splits a model name "<base>:<adapter>" into the base model and the LoRA adapter.
+func splitLoraModelName(modelName string) (string, string, bool) {
+	i := strings.LastIndex(modelName, ":")
+	if i <= 0 || i == len(modelName)-1 {
+		return "", "", false
+	}
+	return modelName[:i], modelName[i+1:], true
+}
+
+// loraAdapterOf returns the LoRA adapter a request is for, which the lora-adapter plugin schedules on the pods having it loaded.
+func loraAdapterOf(modelName string, isLora bool) string {
+	if !isLora {
+		return ""
+	}
+	return modelName
+}
+
+// servedModelOf returns the model the pods of the model server serve a request with, which the pods serving several
+// models are checked against. The LoRA adapters are served with the base model, unknown without a model.
+func servedModelOf(modelServer *v1alpha1.ModelServer, modelName string, isLora bool) string {
+	if modelServer.Spec.Model != nil {
+		return *modelServer.Spec.Model
+	}
+	if isLora {
+		return ""
+	}
+	return modelName
+}
+
 func ParseModelRequest(c *gin.Context) (ModelRequest, error) {
 	bodyBytes, err := io.ReadAll(c.Request.Body)
 	if err != nil {
@@ -386,7 +633,7 @@ func (r *Router) proxy(
 		r.metrics.IncActiveUpstreamRequests(modelServerName, modelRouteName)
 
 		// Request dispatched to the pod.
-		err := proxyRequest(c, req, ctx.BestPods[i].Pod.Status.PodIP, port, stream, onUsage)
+		err := r.proxyObservedRequest(c, req, ctx, i, port, stream, onUsage)
 
 		// Decrement upstream request count when request completes
 		r.metrics.DecActiveUpstreamRequests(modelServerName, modelRouteName)
@@ -396,9 +643,13 @@ func (r *Router) proxy(
 			continue
 		}
 		// record in prefix cache
-		r.scheduler.RunPostHooks(ctx, i)
+		r.Scheduler().RunPostHooks(ctx, i)
 		return nil
 	}
+	if err := req.Context().Err(); err != nil {
+		// The request timed out or the client went away, it is answered by the request limits
+		return fmt.Errorf("request to all pods failed: %w", err)
+	}
 	c.AbortWithStatusJSON(http.StatusNotFound, "request to all pods failed")
 	return fmt.Errorf("request to all pods failed")
 }
@@ -431,7 +682,7 @@ func (r *Router) proxyModelEndpoint(
 			userID = v
 		}
 		modelName := ctx.Model
-		err := r.proxy(c, decodeRequest, ctx, stream, port, func(resp handlers.OpenAIResponse) {
+		onUsage := func(resp handlers.OpenAIResponse) {
 			if resp.Usage.TotalTokens <= 0 {
 				return
 			}
@@ -453,7 +704,24 @@ func (r *Router) proxyModelEndpoint(
 				return
 			}
 			_ = r.store.UpdateTokenCount(userID, modelName, float64(resp.Usage.PromptTokens), float64(resp.Usage.CompletionTokens))
-		})
+		}
+
+		// Split the parallel completions across pods, or fall back to a single pod
+		if shards := parallelSamplingShards(ctx, modelRequest); shards != nil {
+			err := r.proxyParallelSampling(c, decodeRequest, ctx, modelRequest, shards, stream, port, onUsage)
+			if err == nil {
+				accesslog.MarkUpstreamEnd(c)
+				return nil
+			}
+			klog.Warningf("parallel sampling failed, proxying the request to a single pod: %v", err)
+		}
+		// Duplicate the request to the second best pod when its first token is late
+		if delay, ok := r.hedgingDelay(ctx.ModelServerName); ok && len(ctx.BestPods) > 1 {
+			err := r.proxyHedged(c, decodeRequest, ctx, modelRequest, delay, stream, port, onUsage)
+			accesslog.MarkUpstreamEnd(c)
+			return err
+		}
+		err := r.proxy(c, decodeRequest, ctx, stream, port, onUsage)
 
 		// Mark end of upstream processing
 		accesslog.MarkUpstreamEnd(c)
@@ -472,7 +740,7 @@ func (r *Router) proxyModelEndpoint(
 }
 
 func (r *Router) GetModelServer(modelName string, req *http.Request) (*v1alpha1.ModelServer, error) {
-	modelServerName, isLora, _, err := r.store.MatchModelServer(modelName, req)
+	modelServerName, isLora, _, _, err := r.matchModelServer(modelName, req)
 	if err != nil {
 		return nil, fmt.Errorf("can't find corresponding model server: %v", err)
 	}
@@ -488,26 +756,38 @@ func (r *Router) GetModelServer(modelName string, req *http.Request) (*v1alpha1.
 }
 
 func (r *Router) Auth() gin.HandlerFunc {
-	return r.authenticator.Authenticate()
+	return r.config.Load().authenticator.Authenticate()
 }
 
 func (r *Router) AccessLog() gin.HandlerFunc {
-	return accesslog.AccessLogMiddleware(r.accessLogger)
+	logAccess := accesslog.AccessLogMiddleware(r.accessLogger)
+	return func(c *gin.Context) {
+		logAccess(c)
+		r.audit(c)
+		r.recordChargeback(c)
+		r.recordTenant(c)
+	}
 }
 
 // proxyRequest proxies the request to the model server pods, returns response to downstream.
 func proxyRequest(
 	c *gin.Context,
 	req *http.Request,
-	podIP string,
+	pod *datastore.PodInfo,
 	port int32,
 	stream bool,
 	onUsage func(u handlers.OpenAIResponse),
 ) error {
-	resp, err := doRequest(req, podIP, port)
+	resp, err := doRequest(req, pod, port)
 	if err != nil {
 		return fmt.Errorf("decode request error: %w", err)
 	}
+	forwardResponse(c, resp, stream, onUsage)
+	return nil
+}
+
+// forwardResponse forwards the response of a model server to downstream, and parses its token usage.
+func forwardResponse(c *gin.Context, resp *http.Response, stream bool, onUsage func(u handlers.OpenAIResponse)) {
 	for k, vv := range resp.Header {
 		for _, v := range vv {
 			c.Header(k, v)
@@ -529,14 +809,14 @@ func proxyRequest(
 				parsed := handlers.ParseStreamRespForUsage(string(line))
 				if parsed.Usage.CompletionTokens > 0 {
 					klog.V(4).Infof("Parsed usage: %+v", parsed.Usage)
+					if onUsage != nil {
+						onUsage(parsed)
+					}
 
 					// The token usage is set by router, so remove it before sending to downstream
 					if v, ok := c.Get(common.TokenUsageKey); ok && v.(bool) {
 						return true
 					}
-					if onUsage != nil {
-						onUsage(parsed)
-					}
 				}
 				// Forward to downstream
 				_, _ = w.Write(line)
@@ -557,7 +837,7 @@ func proxyRequest(
 		_, err := io.Copy(c.Writer, ttee)
 		if err != nil {
 			klog.Errorf("copy response to downstream failed: %v", err)
-			return nil
+			return
 		}
 
 		// Parse usage if present
@@ -569,30 +849,43 @@ func proxyRequest(
 			}
 		}
 	}
-
-	return nil
 }
 
 func doRequest(
 	req *http.Request,
-	podIP string,
+	pod *datastore.PodInfo,
 	port int32,
 ) (*http.Response, error) {
-	// step 1: change request URL to prefill pod URL.
-	req.URL.Host = fmt.Sprintf("%s:%d", podIP, port)
+	// step 1: change request URL to the pod URL.
+	req.URL.Host = netutil.HostPort(pod.Pod.Status.PodIP, port)
 
-	// step 2: use http.Transport to do request to prefill pod.
-	transport := http.DefaultTransport
-	resp, err := transport.RoundTrip(req)
+	// step 2: send the request on the connection pool of the engine of the pod.
+	resp, err := upstream.Default().For(pod.Engine()).RoundTrip(req)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return nil, fmt.Errorf("http resp error, http code is %d", resp.StatusCode)
 	}
+	resp.Body = limits.IdleTimeoutBody(resp.Body, StreamIdleTimeout)
 	return resp, nil
 }
 
+// abortWithStreamLimitExceeded responds with a structured 429 carrying a hint of when to retry.
+func abortWithStreamLimitExceeded(c *gin.Context, err error) {
+	body := gin.H{
+		"type":    "concurrent_stream_limit",
+		"message": "concurrent stream limit exceeded",
+	}
+	if limitErr, ok := err.(*ratelimit.ConcurrentStreamLimitExceededError); ok {
+		retryAfter := int(math.Ceil(limitErr.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		body["limit"] = limitErr.Limit
+		body["retry_after_seconds"] = retryAfter
+	}
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": body})
+}
+
 // isStreaming checks if the given model request has streaming enabled
 func isStreaming(modelRequest ModelRequest) bool {
 	if v, ok := modelRequest["stream"]; ok {
@@ -668,8 +961,8 @@ func (r *Router) proxyToPDDisaggregated(
 		}
 
 		// Build addresses for prefill and decode pods
-		prefillAddr := fmt.Sprintf("%s:%d", ctx.PrefillPods[i].Pod.Status.PodIP, port)
-		decodeAddr := fmt.Sprintf("%s:%d", ctx.DecodePods[i].Pod.Status.PodIP, port)
+		prefillAddr := netutil.HostPort(ctx.PrefillPods[i].Pod.Status.PodIP, port)
+		decodeAddr := netutil.HostPort(ctx.DecodePods[i].Pod.Status.PodIP, port)
 
 		klog.V(4).Infof("Attempting PD disaggregated request: prefill=%s, decode=%s", prefillAddr, decodeAddr)
 
@@ -693,7 +986,7 @@ func (r *Router) proxyToPDDisaggregated(
 		}
 
 		// Record successful operation in cache
-		r.scheduler.RunPostHooks(ctx, i)
+		r.Scheduler().RunPostHooks(ctx, i)
 
 		klog.V(4).Infof("kv connector run successful for prefill pod %s, decode pod %s, output tokens: %d",
 			ctx.PrefillPods[i].Pod.Name, ctx.DecodePods[i].Pod.Name, outputTokens)
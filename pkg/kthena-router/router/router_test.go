@@ -29,9 +29,11 @@ import (
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"istio.io/istio/pkg/util/sets"
 	corev1 "k8s.io/api/core/v1"
@@ -45,6 +47,8 @@ import (
 	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/connectors"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/handlers"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
@@ -95,7 +99,7 @@ func TestProxyModelEndpoint(t *testing.T) {
 	req, _ := http.NewRequest("POST", "/", nil)
 	modelReq := ModelRequest{"model": "test"}
 	r := NewRouter(datastore.New(), "testdata/comfigmap.yaml")
-	hookPatch := gomonkey.ApplyMethod(r.scheduler, "RunPostHooks", func(s scheduler.Scheduler, ctx *framework.Context, index int) {})
+	hookPatch := gomonkey.ApplyMethod(r.Scheduler(), "RunPostHooks", func(s scheduler.Scheduler, ctx *framework.Context, index int) {})
 	defer hookPatch.Reset()
 
 	tests := []struct {
@@ -118,7 +122,7 @@ func TestProxyModelEndpoint(t *testing.T) {
 				patches.ApplyFunc(isStreaming, func(modelRequest ModelRequest) bool {
 					return false
 				})
-				patches.ApplyFunc(proxyRequest, func(c *gin.Context, req *http.Request, podIP string, port int32, stream bool) error {
+				patches.ApplyFunc(proxyRequest, func(c *gin.Context, req *http.Request, pod *datastore.PodInfo, port int32, stream bool, onUsage func(u handlers.OpenAIResponse)) error {
 					return nil
 				})
 				return patches
@@ -139,7 +143,7 @@ func TestProxyModelEndpoint(t *testing.T) {
 				patches.ApplyFunc(isStreaming, func(modelRequest ModelRequest) bool {
 					return false
 				})
-				patches.ApplyFunc(proxyRequest, func(c *gin.Context, req *http.Request, podIP string, port int32, stream bool) error {
+				patches.ApplyFunc(proxyRequest, func(c *gin.Context, req *http.Request, pod *datastore.PodInfo, port int32, stream bool, onUsage func(u handlers.OpenAIResponse)) error {
 					return errors.New("proxy error")
 				})
 				return patches
@@ -242,6 +246,85 @@ func TestRouter_HandlerFunc_AggregatedMode(t *testing.T) {
 	assert.Contains(t, w.Body.String(), `"id":"response-id"`)
 }
 
+func TestRouter_HandlerFunc_Embeddings(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		reqBody string
+	}{
+		{
+			name:    "embeddings with batched input",
+			path:    "/v1/embeddings",
+			reqBody: `{"model": "test-model", "input": ["hello", "world"], "stream": true}`,
+		},
+		{
+			name:    "rerank",
+			path:    "/v1/rerank",
+			reqBody: `{"model": "test-model", "query": "hello", "documents": ["hello", "world"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backendHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.path, r.URL.Path)
+				body, _ := io.ReadAll(r.Body)
+				var reqBody ModelRequest
+				json.Unmarshal(body, &reqBody)
+				assert.Equal(t, "test-model-base", reqBody["model"])
+				assert.NotContains(t, reqBody, "stream") // Embedding requests are never streamed
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprint(w, `{"object":"list"}`)
+			})
+			router, store, backend := setupTestRouter(backendHandler)
+			defer backend.Close()
+
+			backendURL, _ := url.Parse(backend.URL)
+			backendPort, _ := strconv.Atoi(backendURL.Port())
+
+			modelServer := &aiv1alpha1.ModelServer{
+				ObjectMeta: v1.ObjectMeta{Name: "ms-1", Namespace: "default"},
+				Spec: aiv1alpha1.ModelServerSpec{
+					Model:           func(s string) *string { return &s }("test-model-base"),
+					WorkloadPort:    aiv1alpha1.WorkloadPort{Port: int32(backendPort)},
+					InferenceEngine: "vLLM",
+				},
+			}
+			pod1 := &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: "pod-1", Namespace: "default"},
+				Status:     corev1.PodStatus{PodIP: backendURL.Hostname(), Phase: corev1.PodRunning},
+			}
+			modelRoute := &aiv1alpha1.ModelRoute{
+				ObjectMeta: v1.ObjectMeta{Name: "mr-1", Namespace: "default"},
+				Spec: aiv1alpha1.ModelRouteSpec{
+					ModelName: "test-model",
+					Rules: []*aiv1alpha1.Rule{
+						{
+							TargetModels: []*aiv1alpha1.TargetModel{
+								{ModelServerName: "ms-1"},
+							},
+						},
+					},
+				},
+			}
+
+			store.AddOrUpdateModelServer(modelServer, sets.New(types.NamespacedName{Name: "pod-1", Namespace: "default"}))
+			store.AddOrUpdatePod(pod1, []*aiv1alpha1.ModelServer{modelServer})
+			store.AddOrUpdateModelRoute(modelRoute)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", tt.path, bytes.NewBufferString(tt.reqBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			router.HandlerFunc()(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), `"object":"list"`)
+		})
+	}
+}
+
 func TestRouter_HandlerFunc_DisaggregatedMode(t *testing.T) {
 	// 1. Setup backend mock
 	prefillReqs := 0
@@ -347,6 +430,296 @@ func TestRouter_HandlerFunc_DisaggregatedMode(t *testing.T) {
 	assert.Contains(t, w.Body.String(), `data: {"id":"decode-resp"}`)
 }
 
+func TestRouter_HandlerFunc_TrafficCompare(t *testing.T) {
+	newBackend := func(content string) (*httptest.Server, string, int) {
+		backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"choices":[{"message":{"content":%q}}]}`, content)
+		}))
+		backendURL, _ := url.Parse(backend.URL)
+		port, _ := strconv.Atoi(backendURL.Port())
+		return backend, backendURL.Hostname(), port
+	}
+	baseline, baselineIP, baselinePort := newBackend("hello")
+	defer baseline.Close()
+	candidate, candidateIP, candidatePort := newBackend("hello world")
+	defer candidate.Close()
+
+	store := datastore.New()
+	router := NewRouter(store, "")
+	for _, server := range []struct {
+		name string
+		ip   string
+		port int
+	}{
+		{name: "baseline", ip: baselineIP, port: baselinePort},
+		{name: "candidate", ip: candidateIP, port: candidatePort},
+	} {
+		modelServer := &aiv1alpha1.ModelServer{
+			ObjectMeta: v1.ObjectMeta{Name: server.name, Namespace: "default"},
+			Spec: aiv1alpha1.ModelServerSpec{
+				Model:           func(s string) *string { return &s }("test-model-" + server.name),
+				WorkloadPort:    aiv1alpha1.WorkloadPort{Port: int32(server.port)},
+				InferenceEngine: "vLLM",
+			},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: server.name + "-pod", Namespace: "default"},
+			Status:     corev1.PodStatus{PodIP: server.ip, Phase: corev1.PodRunning},
+		}
+		store.AddOrUpdateModelServer(modelServer, sets.New(types.NamespacedName{Name: pod.Name, Namespace: "default"}))
+		store.AddOrUpdatePod(pod, []*aiv1alpha1.ModelServer{modelServer})
+	}
+	store.AddOrUpdateModelRoute(&aiv1alpha1.ModelRoute{
+		ObjectMeta: v1.ObjectMeta{Name: "mr-1", Namespace: "default"},
+		Spec: aiv1alpha1.ModelRouteSpec{
+			ModelName: "test-model",
+			Rules: []*aiv1alpha1.Rule{
+				{
+					TargetModels: []*aiv1alpha1.TargetModel{
+						{ModelServerName: "baseline"},
+					},
+				},
+			},
+			TrafficCompare: &aiv1alpha1.TrafficCompare{
+				BaselineModelServerName:  "baseline",
+				CandidateModelServerName: "candidate",
+				SamplePercent:            func(p uint32) *uint32 { return &p }(100),
+			},
+		},
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	reqBody := `{"model": "test-model", "messages": [{"role": "user", "content": "hello"}], "temperature": 0}`
+	c.Request, _ = http.NewRequest("POST", "/v1/chat/completions", bytes.NewBufferString(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	router.HandlerFunc()(c)
+
+	// The client is only served by the model server the route targets
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"content":"hello"`)
+
+	assert.Eventually(t, func() bool {
+		return len(router.CompareStore().List("default/mr-1")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	sample := router.CompareStore().List("default/mr-1")[0]
+	assert.Equal(t, "test-model", sample.Model)
+	assert.Equal(t, "default/baseline", sample.Baseline.ModelServer)
+	assert.Equal(t, "hello", sample.Baseline.Output)
+	assert.Equal(t, "default/candidate", sample.Candidate.ModelServer)
+	assert.Equal(t, "hello world", sample.Candidate.Output)
+	assert.False(t, sample.ExactMatch())
+}
+
+func TestRouter_HandlerFunc_TrafficMirror(t *testing.T) {
+	shadowModels := make(chan string, 1)
+	newBackend := func(content string, received chan string) (*httptest.Server, string, int) {
+		backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if received != nil {
+				var reqBody ModelRequest
+				_ = json.NewDecoder(r.Body).Decode(&reqBody)
+				received <- reqBody["model"].(string)
+			}
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"choices":[{"message":{"content":%q}}],"usage":{"prompt_tokens":1,"completion_tokens":2,"total_tokens":3}}`, content)
+		}))
+		backendURL, _ := url.Parse(backend.URL)
+		port, _ := strconv.Atoi(backendURL.Port())
+		return backend, backendURL.Hostname(), port
+	}
+	primary, primaryIP, primaryPort := newBackend("hello", nil)
+	defer primary.Close()
+	shadow, shadowIP, shadowPort := newBackend("hello world", shadowModels)
+	defer shadow.Close()
+
+	store := datastore.New()
+	router := NewRouter(store, "")
+	for _, server := range []struct {
+		name string
+		ip   string
+		port int
+	}{
+		{name: "primary", ip: primaryIP, port: primaryPort},
+		{name: "shadow", ip: shadowIP, port: shadowPort},
+	} {
+		modelServer := &aiv1alpha1.ModelServer{
+			ObjectMeta: v1.ObjectMeta{Name: server.name, Namespace: "default"},
+			Spec: aiv1alpha1.ModelServerSpec{
+				Model:           func(s string) *string { return &s }("test-model-" + server.name),
+				WorkloadPort:    aiv1alpha1.WorkloadPort{Port: int32(server.port)},
+				InferenceEngine: "vLLM",
+			},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: server.name + "-pod", Namespace: "default"},
+			Status:     corev1.PodStatus{PodIP: server.ip, Phase: corev1.PodRunning},
+		}
+		store.AddOrUpdateModelServer(modelServer, sets.New(types.NamespacedName{Name: pod.Name, Namespace: "default"}))
+		store.AddOrUpdatePod(pod, []*aiv1alpha1.ModelServer{modelServer})
+	}
+	store.AddOrUpdateModelRoute(&aiv1alpha1.ModelRoute{
+		ObjectMeta: v1.ObjectMeta{Name: "mr-mirror", Namespace: "default"},
+		Spec: aiv1alpha1.ModelRouteSpec{
+			ModelName: "test-model",
+			Rules: []*aiv1alpha1.Rule{
+				{
+					TargetModels: []*aiv1alpha1.TargetModel{
+						{ModelServerName: "primary"},
+					},
+					Mirror: &aiv1alpha1.TrafficMirror{
+						ModelServerName: "shadow",
+						ComparePercent:  func(p uint32) *uint32 { return &p }(100),
+					},
+				},
+			},
+		},
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	reqBody := `{"model": "test-model", "messages": [{"role": "user", "content": "hello"}]}`
+	c.Request, _ = http.NewRequest("POST", "/v1/chat/completions", bytes.NewBufferString(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	router.HandlerFunc()(c)
+
+	// The client is only served by the model server the rule targets
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"content":"hello"`)
+
+	select {
+	case model := <-shadowModels:
+		assert.Equal(t, "test-model-shadow", model)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the request was not mirrored to the shadow model server")
+	}
+	shadowRequests := router.metrics.MirroredRequests.WithLabelValues("default/mr-mirror", "default/shadow", metrics.MirrorTargetShadow, metrics.MirrorResultSuccess)
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(shadowRequests) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	primaryRequests := router.metrics.MirroredRequests.WithLabelValues("default/mr-mirror", "default/primary", metrics.MirrorTargetPrimary, metrics.MirrorResultSuccess)
+	assert.Equal(t, float64(1), testutil.ToFloat64(primaryRequests))
+
+	// The responses of both model servers are compared
+	assert.Eventually(t, func() bool {
+		return len(router.CompareStore().List("default/mr-mirror")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	sample := router.CompareStore().List("default/mr-mirror")[0]
+	assert.Equal(t, "default/primary", sample.Baseline.ModelServer)
+	assert.Equal(t, "hello", sample.Baseline.Output)
+	assert.Equal(t, "default/shadow", sample.Candidate.ModelServer)
+	assert.Equal(t, "shadow-pod", sample.Candidate.Pod)
+	assert.Equal(t, "hello world", sample.Candidate.Output)
+	assert.Equal(t, float64(1), testutil.ToFloat64(router.metrics.CompareSamples.WithLabelValues("default/mr-mirror", metrics.CompareResultMismatch)))
+}
+
+func TestRouter_HandlerFunc_LoraAdapter(t *testing.T) {
+	backendHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var reqBody ModelRequest
+		json.Unmarshal(body, &reqBody)
+		assert.Equal(t, "my-lora", reqBody["model"]) // Model name replaced by the adapter name
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"id":"response-id"}`)
+	})
+	router, store, backend := setupTestRouter(backendHandler)
+	defer backend.Close()
+
+	backendURL, _ := url.Parse(backend.URL)
+	backendIP := backendURL.Hostname()
+	backendPort, _ := strconv.Atoi(backendURL.Port())
+
+	modelServer := &aiv1alpha1.ModelServer{
+		ObjectMeta: v1.ObjectMeta{Name: "ms-1", Namespace: "default"},
+		Spec: aiv1alpha1.ModelServerSpec{
+			Model:           func(s string) *string { return &s }("test-model-base"),
+			WorkloadPort:    aiv1alpha1.WorkloadPort{Port: int32(backendPort)},
+			InferenceEngine: "vLLM",
+		},
+	}
+	// Only pod-2 has loaded the adapter, pod-1 is not reachable
+	pod1 := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "pod-1", Namespace: "default"},
+		Status:     corev1.PodStatus{PodIP: "127.0.0.2", Phase: corev1.PodRunning},
+	}
+	pod2 := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "pod-2", Namespace: "default"},
+		Status:     corev1.PodStatus{PodIP: backendIP, Phase: corev1.PodRunning},
+	}
+	modelRoute := &aiv1alpha1.ModelRoute{
+		ObjectMeta: v1.ObjectMeta{Name: "mr-1", Namespace: "default"},
+		Spec: aiv1alpha1.ModelRouteSpec{
+			ModelName: "test-model",
+			Rules: []*aiv1alpha1.Rule{
+				{
+					TargetModels: []*aiv1alpha1.TargetModel{
+						{ModelServerName: "ms-1"},
+					},
+				},
+			},
+		},
+	}
+
+	store.AddOrUpdateModelServer(modelServer, sets.New(
+		types.NamespacedName{Name: "pod-1", Namespace: "default"},
+		types.NamespacedName{Name: "pod-2", Namespace: "default"},
+	))
+	store.AddOrUpdatePod(pod1, []*aiv1alpha1.ModelServer{modelServer})
+	store.AddOrUpdatePod(pod2, []*aiv1alpha1.ModelServer{modelServer})
+	store.AddOrUpdateModelRoute(modelRoute)
+	// Both pods have loaded the maximum number of adapters
+	store.GetPodInfo(types.NamespacedName{Name: "pod-1", Namespace: "default"}).UpdateModels([]string{"test-model-base", "lora-1", "lora-2", "lora-3", "lora-4"})
+	store.GetPodInfo(types.NamespacedName{Name: "pod-2", Namespace: "default"}).UpdateModels([]string{"test-model-base", "my-lora", "lora-2", "lora-3", "lora-4"})
+
+	for i := 0; i < 5; i++ {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/v1/completions", bytes.NewBufferString(`{"model": "test-model:my-lora", "prompt": "hello"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		router.HandlerFunc()(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":"response-id"`)
+	}
+
+	// An adapter loaded nowhere can't be loaded on pods without room for it
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/v1/completions", bytes.NewBufferString(`{"model": "test-model:other-lora", "prompt": "hello"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	router.HandlerFunc()(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `filtered out by \"lora-adapter\"`)
+}
+
+func TestSplitLoraModelName(t *testing.T) {
+	tests := []struct {
+		modelName string
+		base      string
+		adapter   string
+		ok        bool
+	}{
+		{modelName: "llama:sql-lora", base: "llama", adapter: "sql-lora", ok: true},
+		{modelName: "llama:8b:sql-lora", base: "llama:8b", adapter: "sql-lora", ok: true},
+		{modelName: "llama", ok: false},
+		{modelName: ":sql-lora", ok: false},
+		{modelName: "llama:", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.modelName, func(t *testing.T) {
+			base, adapter, ok := splitLoraModelName(tt.modelName)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.base, base)
+			assert.Equal(t, tt.adapter, adapter)
+		})
+	}
+}
+
 func TestRouter_HandlerFunc_ModelNotFound(t *testing.T) {
 	router, _, backend := setupTestRouter(nil)
 	defer backend.Close()
@@ -573,3 +946,65 @@ func parseBool(str string) (bool, error) {
 	}
 	return false, &strconv.NumError{Func: "ParseBool", Num: str, Err: strconv.ErrSyntax}
 }
+
+func TestDecisionHeader(t *testing.T) {
+	decision := &framework.Decision{
+		Rounds: []*framework.ScoreRound{{
+			Stage: framework.ScoreStageAggregated,
+			Plugins: []framework.PluginScores{
+				{Plugin: "kvcache-aware", Weight: 50, Scores: map[string]int{"default/pod1": 60, "default/pod2": 10}},
+				{Plugin: "least-request", Weight: 30, Scores: map[string]int{"default/pod1": 40, "default/pod2": 90}},
+			},
+			Scores:   map[string]int{"default/pod1": 4200, "default/pod2": 3200},
+			Selected: []string{"default/pod1", "default/pod2"},
+		}},
+	}
+	assert.Equal(t, "pod=default/pod1; score=4200; kvcache-aware=60; least-request=40", decisionHeader(decision))
+}
+
+func TestForwardResponseStreamUsage(t *testing.T) {
+	tests := []struct {
+		name       string
+		routerSet  bool
+		body       string
+		wantTokens int
+		wantBody   string
+	}{
+		{
+			name:       "usage requested by the router",
+			routerSet:  true,
+			body:       "data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\ndata: {\"choices\":[],\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":2,\"total_tokens\":5}}\n\ndata: [DONE]\n\n",
+			wantTokens: 2,
+			wantBody:   "data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\n\ndata: [DONE]\n\n",
+		},
+		{
+			name:       "responses API",
+			body:       "event: response.output_text.delta\ndata: {\"type\":\"response.output_text.delta\",\"delta\":\"hi\"}\n\nevent: response.completed\ndata: {\"type\":\"response.completed\",\"response\":{\"usage\":{\"input_tokens\":3,\"output_tokens\":4,\"total_tokens\":7}}}\n\n",
+			wantTokens: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := closeNotifyRecorder{httptest.NewRecorder()}
+			c, _ := gin.CreateTestContext(w)
+			if tt.routerSet {
+				c.Set(common.TokenUsageKey, true)
+			}
+			resp := &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+				Body:       io.NopCloser(bytes.NewBufferString(tt.body)),
+			}
+			tokens := 0
+			forwardResponse(c, resp, true, func(u handlers.OpenAIResponse) {
+				tokens += u.Usage.CompletionTokens
+			})
+			assert.Equal(t, tt.wantTokens, tokens)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, w.Body.String(), "the usage requested by the router is not forwarded")
+			} else {
+				assert.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+)
+
+// firstByteWriter records when the first byte of the response is written, which is the first token of the
+// streaming responses.
+type firstByteWriter struct {
+	gin.ResponseWriter
+	firstByte time.Time
+}
+
+func (w *firstByteWriter) mark() {
+	if w.firstByte.IsZero() {
+		w.firstByte = time.Now()
+	}
+}
+
+func (w *firstByteWriter) Write(data []byte) (int, error) {
+	w.mark()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *firstByteWriter) WriteString(s string) (int, error) {
+	w.mark()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// measureSLO measures the request against the service level objectives of its model server. The returned
+// function is called once the request is served, with whether the router failed to serve it. The latency is
+// counted from the arrival of the request in the router.
+func (r *Router) measureSLO(c *gin.Context, modelServerName types.NamespacedName, modelServer *v1alpha1.ModelServer) func(failed bool) {
+	if modelServer.Spec.SLO == nil {
+		return func(bool) {}
+	}
+	start := time.Now()
+	if accessCtx := accesslog.GetAccessLogContext(c); accessCtx != nil && !accessCtx.StartTime.IsZero() {
+		start = accessCtx.StartTime
+	}
+	writer := &firstByteWriter{ResponseWriter: c.Writer}
+	c.Writer = writer
+	return func(failed bool) {
+		c.Writer = writer.ResponseWriter
+		latency := time.Since(start)
+		if !writer.firstByte.IsZero() {
+			latency = writer.firstByte.Sub(start)
+		}
+		failed = failed || writer.Status() >= http.StatusInternalServerError
+		for slo, met := range r.store.RecordSLO(modelServerName, failed, latency) {
+			r.metrics.RecordSLORequest(modelServerName.String(), slo, met)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/tenancy"
+)
+
+// TenantHandler assigns the requests to the tenants of the router before they are routed. The path prefix of the
+// tenant is removed from the path, and the models of the request are resolved among the ModelRoutes of its tenant.
+func (r *Router) TenantHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tenants := r.config.Load().tenants
+		if tenants == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+		tenant := tenants.Match(req.Host, req.URL.Path)
+		ctx := datastore.WithRouteScope(req.Context(), tenants.Scope(tenant))
+		if tenant != nil {
+			ctx = tenancy.WithTenant(ctx, tenant)
+		}
+		req = req.WithContext(ctx)
+		if tenant != nil {
+			url := *req.URL
+			url.Path = tenant.StripPrefix(url.Path)
+			url.RawPath = ""
+			req.URL = &url
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// authorizeTenant checks whether the consumer of the request may use its tenant.
+func (r *Router) authorizeTenant(c *gin.Context) error {
+	tenant := tenancy.FromContext(c.Request.Context())
+	if tenant == nil {
+		return nil
+	}
+	claims, _ := c.Get(common.UserClaimsKey)
+	claimsMap, _ := claims.(map[string]interface{})
+	return tenant.Authorize(c.Request.Header, claimsMap)
+}
+
+// admitTenant checks the quotas of the tenant of the request, the input tokens are counted in its token quota.
+func (r *Router) admitTenant(c *gin.Context, inputTokens int) error {
+	tenant := tenancy.FromContext(c.Request.Context())
+	if tenant == nil {
+		return nil
+	}
+	err := tenant.Admit(inputTokens)
+	if exceeded, ok := err.(*tenancy.QuotaExceededError); ok {
+		r.metrics.RecordTenantQuotaExceeded(exceeded.Tenant, exceeded.Quota)
+	}
+	return err
+}
+
+// recordTenant records the request in the metrics of its tenant, and counts its output tokens in the token quota
+// of the tenant. It runs after the access log middleware, which holds the metadata of the request.
+func (r *Router) recordTenant(c *gin.Context) {
+	tenant := tenancy.FromContext(c.Request.Context())
+	ctx := accesslog.GetAccessLogContext(c)
+	if tenant == nil || ctx == nil || ctx.ModelName == "" {
+		return
+	}
+	tenant.RecordOutputTokens(ctx.OutputTokens)
+	r.metrics.RecordTenantRequest(tenant.Name, ctx.ModelName, strconv.Itoa(c.Writer.Status()), ctx.InputTokens, ctx.OutputTokens)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/accesslog"
+)
+
+const tenantsConfig = `tenants:
+  - name: team-a
+    hosts: ["team-a.example.com"]
+    consumers:
+      apiKeyHashes: ["sha256:6b74:35e348f7f90b1dfb90ae84c02a1cb9c99871706fbc9c32c0618c6aa251c65aef"]
+  - name: team-b
+    pathPrefix: /team-b
+    quota:
+      requestsPerMinute: 1
+`
+
+func TestTenantHandler(t *testing.T) {
+	// The router configuration file is only read on reload in the tests, ParseRouterConfig is stubbed in TestMain
+	r := newConfigTestRouter(t, "")
+	require.NoError(t, os.WriteFile(r.configPath, []byte(tenantsConfig), 0o644))
+	require.NoError(t, r.ReloadConfig())
+	r.accessLogger, _ = accesslog.NewAccessLogger(&accesslog.AccessLoggerConfig{Enabled: false})
+	for _, namespace := range []string{"team-a", "team-b", "default"} {
+		require.NoError(t, r.store.AddOrUpdateModelRoute(&aiv1alpha1.ModelRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "llama"},
+			Spec: aiv1alpha1.ModelRouteSpec{
+				ModelName: "llama",
+				Rules: []*aiv1alpha1.Rule{{
+					Name:         "default",
+					TargetModels: []*aiv1alpha1.TargetModel{{ModelServerName: "llama-" + namespace}},
+				}},
+			},
+		}))
+	}
+
+	engine := gin.New()
+	engine.Use(r.AccessLog())
+	engine.POST("/v1/chat/completions", func(c *gin.Context) {
+		accesslog.SetModelName(c, "llama")
+		if err := r.authorizeTenant(c); err != nil {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		if err := r.admitTenant(c, 10); err != nil {
+			c.AbortWithStatus(http.StatusTooManyRequests)
+			return
+		}
+		server, _, _, _, err := r.matchModelServer("llama", c.Request)
+		require.NoError(t, err)
+		c.String(http.StatusOK, server.String())
+	})
+	handler := r.TenantHandler(engine.Handler())
+
+	serve := func(host, path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Host = host
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("team-a.example.com", "/v1/chat/completions", "sk-team-a")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "team-a/llama-team-a", w.Body.String())
+
+	// The consumers of the tenant are restricted
+	w = serve("team-a.example.com", "/v1/chat/completions", "sk-team-b")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// The path prefix of the tenant is removed before the request is routed
+	w = serve("api.example.com", "/team-b/v1/chat/completions", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "team-b/llama-team-b", w.Body.String())
+	w = serve("api.example.com", "/team-b/v1/chat/completions", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	// The requests of no tenant don't reach the routes of the tenants
+	w = serve("api.example.com", "/v1/chat/completions", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "default/llama-default", w.Body.String())
+
+	// The quota is kept across reloads when it did not change
+	require.NoError(t, os.WriteFile(r.configPath, []byte(tenantsConfig+"    namespaces: [team-b, default]\n"), 0o644))
+	require.NoError(t, r.ReloadConfig())
+	w = serve("api.example.com", "/team-b/v1/chat/completions", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
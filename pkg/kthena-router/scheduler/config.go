@@ -0,0 +1,144 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+// Config is the effective configuration of the scheduler, as reported by the admin API.
+type Config struct {
+	FilterPlugins []FilterPluginConfig `json:"filterPlugins"`
+	ScorePlugins  []ScorePluginConfig  `json:"scorePlugins"`
+}
+
+// FilterPluginConfig is the effective configuration of a filter plugin.
+type FilterPluginConfig struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ScorePluginConfig is the effective configuration and state of a score plugin.
+type ScorePluginConfig struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Weight  int    `json:"weight"`
+	Timeout string `json:"timeout"`
+	// DegradedUntil is set while the plugin is skipped for missing its deadline.
+	DegradedUntil *time.Time `json:"degradedUntil,omitempty"`
+}
+
+// Config returns the filter plugins in the order they run and the score plugins with their weights.
+func (s *SchedulerImpl) Config() Config {
+	now := time.Now()
+	config := Config{
+		FilterPlugins: make([]FilterPluginConfig, 0, len(s.filterPlugins)),
+		ScorePlugins:  make([]ScorePluginConfig, 0, len(s.scorePlugins)),
+	}
+	for _, p := range s.filterPlugins {
+		config.FilterPlugins = append(config.FilterPlugins, FilterPluginConfig{
+			Name:    p.Name(),
+			Enabled: !s.pluginDisabled(p.Name()),
+		})
+	}
+	for _, p := range s.scorePlugins {
+		plugin := ScorePluginConfig{
+			Name:    p.plugin.Name(),
+			Enabled: !s.pluginDisabled(p.plugin.Name()),
+			Weight:  p.weight,
+			Timeout: p.timeout.String(),
+		}
+		if p.degraded(now) {
+			until := time.Unix(0, p.skipUntil.Load())
+			plugin.DegradedUntil = &until
+		}
+		config.ScorePlugins = append(config.ScorePlugins, plugin)
+	}
+	// The score plugins run concurrently, they are sorted for a stable output.
+	sort.Slice(config.ScorePlugins, func(i, j int) bool {
+		return config.ScorePlugins[i].Name < config.ScorePlugins[j].Name
+	})
+	return config
+}
+
+// SetPluginEnabled enables or disables the filter and score plugins with the given name. A disabled plugin
+// is skipped by every scheduling decision until it is enabled again, the setting is lost on restart.
+func (s *SchedulerImpl) SetPluginEnabled(name string, enabled bool) error {
+	if !s.hasPlugin(name) {
+		return fmt.Errorf("plugin %q is not configured", name)
+	}
+	if enabled {
+		s.disabled.Delete(name)
+	} else {
+		s.disabled.Store(name, struct{}{})
+	}
+	return nil
+}
+
+func (s *SchedulerImpl) hasPlugin(name string) bool {
+	for _, p := range s.filterPlugins {
+		if p.Name() == name {
+			return true
+		}
+	}
+	for _, p := range s.scorePlugins {
+		if p.plugin.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SchedulerImpl) pluginDisabled(name string) bool {
+	_, disabled := s.disabled.Load(name)
+	return disabled
+}
+
+// ValidateConfig checks the scheduler configuration of the router, so that an invalid configuration is rejected
+// instead of stopping the router or silently dropping the unknown plugins.
+func ValidateConfig(routerConfig *conf.RouterConfiguration) error {
+	if routerConfig == nil {
+		return nil
+	}
+	scorePlugins, filterPlugins, _, err := conf.LoadSchedulerConfig(&routerConfig.Scheduler)
+	if err != nil {
+		return err
+	}
+	if _, _, err := conf.LoadScoreTimeouts(&routerConfig.Scheduler); err != nil {
+		return err
+	}
+	registry := NewPluginRegistry()
+	registerDefaultPlugins(registry)
+	for name, weight := range scorePlugins {
+		if _, ok := registry.getScorePlugin(name); !ok {
+			return fmt.Errorf("unknown score plugin %q", name)
+		}
+		if weight < 0 {
+			return fmt.Errorf("score plugin %q has a negative weight %d", name, weight)
+		}
+	}
+	for _, name := range filterPlugins {
+		if _, ok := registry.getFilterPlugin(name); !ok {
+			return fmt.Errorf("unknown filter plugin %q", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+type rejectAllFilterPlugin struct{}
+
+func (rejectAllFilterPlugin) Name() string {
+	return "reject-all"
+}
+
+func (rejectAllFilterPlugin) Filter(ctx *framework.Context, pods []*datastore.PodInfo) []*datastore.PodInfo {
+	return nil
+}
+
+func TestSchedulerConfig(t *testing.T) {
+	s := &SchedulerImpl{
+		filterPlugins: []framework.FilterPlugin{rejectAllFilterPlugin{}},
+		scorePlugins: []*scorePlugin{
+			{plugin: &fakeScorePlugin{name: "slow"}, weight: 1, timeout: time.Second},
+			{plugin: &fakeScorePlugin{name: "fast"}, weight: 2, timeout: 100 * time.Millisecond},
+		},
+	}
+	s.scorePlugins[0].skipUntil.Store(time.Now().Add(time.Minute).UnixNano())
+
+	config := s.Config()
+	assert.Equal(t, []FilterPluginConfig{{Name: "reject-all", Enabled: true}}, config.FilterPlugins)
+	require.Len(t, config.ScorePlugins, 2)
+	assert.Equal(t, ScorePluginConfig{Name: "fast", Enabled: true, Weight: 2, Timeout: "100ms"}, config.ScorePlugins[0])
+	assert.Equal(t, "slow", config.ScorePlugins[1].Name)
+	assert.NotNil(t, config.ScorePlugins[1].DegradedUntil)
+
+	assert.Error(t, s.SetPluginEnabled("unknown", false))
+	require.NoError(t, s.SetPluginEnabled("reject-all", false))
+	require.NoError(t, s.SetPluginEnabled("fast", false))
+	config = s.Config()
+	assert.False(t, config.FilterPlugins[0].Enabled)
+	assert.False(t, config.ScorePlugins[0].Enabled)
+	assert.True(t, config.ScorePlugins[1].Enabled)
+
+	require.NoError(t, s.SetPluginEnabled("fast", true))
+	assert.True(t, s.Config().ScorePlugins[0].Enabled)
+}
+
+func TestDisabledPluginsAreSkipped(t *testing.T) {
+	pod := &datastore.PodInfo{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod1"}}}
+	s := &SchedulerImpl{
+		filterPlugins: []framework.FilterPlugin{rejectAllFilterPlugin{}},
+		scorePlugins: []*scorePlugin{
+			{plugin: &fakeScorePlugin{name: "a", score: 10}, weight: 1, timeout: time.Second},
+			{plugin: &fakeScorePlugin{name: "b", score: 20}, weight: 1, timeout: time.Second},
+		},
+	}
+	pods := []*datastore.PodInfo{pod}
+
+	_, err := s.RunFilterPlugins(pods, &framework.Context{})
+	assert.Error(t, err)
+	require.NoError(t, s.SetPluginEnabled("reject-all", false))
+	filtered, err := s.RunFilterPlugins(pods, &framework.Context{})
+	require.NoError(t, err)
+	assert.Equal(t, pods, filtered)
+
+	require.NoError(t, s.SetPluginEnabled("b", false))
+	ctx := &framework.Context{Decision: &framework.Decision{}}
+	ctx.Decision.StartRound(framework.ScoreStageAggregated)
+	scores := s.RunScorePlugins(pods, ctx)
+	assert.Equal(t, map[*datastore.PodInfo]int{pod: 10}, scores)
+	assert.Equal(t, []framework.SkippedPlugin{{Plugin: "b", Reason: metrics.PluginSkipReasonDisabled}}, ctx.Decision.Rounds[0].Skipped)
+}
+
+func TestValidateConfig(t *testing.T) {
+	assert.NoError(t, ValidateConfig(nil))
+	newConfig := func() *conf.RouterConfiguration {
+		return &conf.RouterConfiguration{Scheduler: conf.SchedulerConfiguration{Plugins: conf.Plugins{
+			Filter: conf.Filter{Enabled: []string{"least-request"}},
+			Score:  conf.Score{Enabled: []conf.PluginWithWeight{{Name: "prefix-cache", Weight: 1}}, Timeout: "100ms"},
+		}}}
+	}
+	assert.NoError(t, ValidateConfig(newConfig()))
+
+	unknownScore := newConfig()
+	unknownScore.Scheduler.Plugins.Score.Enabled[0].Name = "unknown"
+	assert.EqualError(t, ValidateConfig(unknownScore), `unknown score plugin "unknown"`)
+
+	unknownFilter := newConfig()
+	unknownFilter.Scheduler.Plugins.Filter.Enabled = []string{"unknown"}
+	assert.EqualError(t, ValidateConfig(unknownFilter), `unknown filter plugin "unknown"`)
+
+	invalidTimeout := newConfig()
+	invalidTimeout.Scheduler.Plugins.Score.Timeout = "soon"
+	assert.Error(t, ValidateConfig(invalidTimeout))
+}
@@ -0,0 +1,177 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cespare/xxhash"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+const (
+	// virtualNodes is the number of points of each pod on the ring, which spread the prompts evenly.
+	virtualNodes = 100
+	// defaultMaxLoadPercent is the load a pod may take in percent of the average load, without a policy.
+	defaultMaxLoadPercent = 125
+)
+
+// hashRing places the pods of a model server on a consistent hash ring. It is rebuilt only when the
+// pods of the model server change, so that most prompts keep hashing to the same pod.
+type hashRing struct {
+	// members are the sorted names of the pods the ring was built from.
+	members string
+	points  []ringPoint
+}
+
+type ringPoint struct {
+	hash uint64
+	pod  string
+}
+
+func newHashRing(members []string) *hashRing {
+	ring := &hashRing{
+		members: strings.Join(members, ","),
+		points:  make([]ringPoint, 0, len(members)*virtualNodes),
+	}
+	for _, pod := range members {
+		for i := 0; i < virtualNodes; i++ {
+			ring.points = append(ring.points, ringPoint{hash: xxhash.Sum64String(pod + "#" + strconv.Itoa(i)), pod: pod})
+		}
+	}
+	sort.Slice(ring.points, func(i, j int) bool {
+		return ring.points[i].hash < ring.points[j].hash
+	})
+	return ring
+}
+
+// walk returns the pods in the order they follow the hash on the ring.
+func (r *hashRing) walk(hash uint64) []string {
+	start := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= hash
+	})
+	seen := make(map[string]struct{})
+	var pods []string
+	for i := 0; i < len(r.points); i++ {
+		point := r.points[(start+i)%len(r.points)]
+		if _, ok := seen[point.pod]; ok {
+			continue
+		}
+		seen[point.pod] = struct{}{}
+		pods = append(pods, point.pod)
+	}
+	return pods
+}
+
+// ringOf returns the ring of the pods of the model server, building it when its pods changed.
+func (s *SchedulerImpl) ringOf(ctx *framework.Context, pods []*datastore.PodInfo) *hashRing {
+	members := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		members = append(members, podKey(pod))
+	}
+	sort.Strings(members)
+	if cached, ok := s.rings.Load(ctx.ModelServerName); ok {
+		if ring := cached.(*hashRing); ring.members == strings.Join(members, ",") {
+			return ring
+		}
+	}
+	ring := newHashRing(members)
+	s.rings.Store(ctx.ModelServerName, ring)
+	return ring
+}
+
+// scheduleConsistentHash picks the pods following the hash of the prompt on the ring, with bounded loads:
+// the pods whose running and waiting requests exceed the max load percent of the average are moved
+// after the others. A batch of embedding or rerank inputs weighs as many requests as it has inputs.
+// The ring is built from all the pods of the model server, so that the pods removed by the filter
+// plugins for a request do not move the other prompts.
+func (s *SchedulerImpl) scheduleConsistentHash(ctx *framework.Context, all, filtered []*datastore.PodInfo) {
+	candidates := make(map[string]*datastore.PodInfo, len(filtered))
+	totalLoad := 0.0
+	for _, pod := range filtered {
+		candidates[podKey(pod)] = pod
+		totalLoad += pendingRequests(pod)
+	}
+	// The request is counted in the average, so that idle pods can take it
+	load := requestLoad(ctx)
+	capacity := math.Ceil((totalLoad + load) / float64(len(filtered)) * float64(maxLoadPercentOf(ctx.SchedulingPolicy)) / 100)
+
+	var selected, overloaded []*datastore.PodInfo
+	for _, name := range s.ringOf(ctx, all).walk(xxhash.Sum64String(hashKey(ctx))) {
+		pod, ok := candidates[name]
+		if !ok {
+			continue
+		}
+		if pendingRequests(pod)+load > capacity {
+			overloaded = append(overloaded, pod)
+			continue
+		}
+		selected = append(selected, pod)
+	}
+	selected = append(selected, overloaded...)
+	if len(selected) > topN {
+		selected = selected[:topN]
+	}
+
+	ctx.Decision.StartRound(framework.ScoreStageConsistentHash)
+	ctx.Decision.FinishRound(nil, selected)
+	ctx.BestPods = selected
+}
+
+// hashKey is the key of the request on the ring, the model and its prompt.
+func hashKey(ctx *framework.Context) string {
+	var key strings.Builder
+	key.WriteString(ctx.Model)
+	key.WriteString("\n")
+	key.WriteString(ctx.Prompt.Text)
+	for _, message := range ctx.Prompt.Messages {
+		key.WriteString("\n")
+		key.WriteString(message.Role)
+		key.WriteString(": ")
+		key.WriteString(message.Content)
+	}
+	return key.String()
+}
+
+// requestLoad is the load the request adds to its pod, the engines run each input of a batch as a sequence.
+func requestLoad(ctx *framework.Context) float64 {
+	return float64(max(ctx.BatchSize, 1))
+}
+
+func podKey(pod *datastore.PodInfo) string {
+	if pod == nil || pod.Pod == nil {
+		return ""
+	}
+	return pod.Pod.Namespace + "/" + pod.Pod.Name
+}
+
+func consistentHashOf(policy *aiv1alpha1.SchedulingPolicy) bool {
+	return policy != nil && policy.Mode == aiv1alpha1.SchedulingModeConsistentHash
+}
+
+func maxLoadPercentOf(policy *aiv1alpha1.SchedulingPolicy) int32 {
+	if policy == nil || policy.ConsistentHash == nil || policy.ConsistentHash.MaxLoadPercent < 100 {
+		return defaultMaxLoadPercent
+	}
+	return policy.ConsistentHash.MaxLoadPercent
+}
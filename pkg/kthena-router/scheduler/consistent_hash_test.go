@@ -0,0 +1,158 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+func newHashPods(n int) []*datastore.PodInfo {
+	pods := make([]*datastore.PodInfo, 0, n)
+	for i := 0; i < n; i++ {
+		pods = append(pods, &datastore.PodInfo{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("pod%d", i)}}})
+	}
+	return pods
+}
+
+func newHashContext(prompt string, policy *aiv1alpha1.SchedulingPolicy) *framework.Context {
+	return &framework.Context{
+		Model:            "bge-m3",
+		Prompt:           common.ChatMessage{Text: prompt},
+		RequestType:      common.RequestTypeEmbedding,
+		ModelServerName:  types.NamespacedName{Namespace: "default", Name: "bge-m3"},
+		SchedulingPolicy: policy,
+		Decision:         &framework.Decision{},
+	}
+}
+
+func TestScheduleConsistentHash(t *testing.T) {
+	policy := &aiv1alpha1.SchedulingPolicy{Mode: aiv1alpha1.SchedulingModeConsistentHash}
+	s := &SchedulerImpl{}
+	pods := newHashPods(4)
+
+	ctx := newHashContext("the same input", policy)
+	require.NoError(t, s.Schedule(ctx, pods))
+	require.Len(t, ctx.BestPods, 4)
+	first := ctx.BestPods[0]
+	assert.Equal(t, framework.ScoreStageConsistentHash, ctx.Decision.Rounds[0].Stage)
+
+	// The same prompt goes to the same pod
+	for i := 0; i < 10; i++ {
+		ctx = newHashContext("the same input", policy)
+		require.NoError(t, s.Schedule(ctx, pods))
+		assert.Same(t, first, ctx.BestPods[0])
+	}
+
+	// The prompts are spread across the pods
+	used := map[*datastore.PodInfo]int{}
+	for i := 0; i < 400; i++ {
+		ctx = newHashContext(fmt.Sprintf("input %d", i), policy)
+		require.NoError(t, s.Schedule(ctx, pods))
+		used[ctx.BestPods[0]]++
+	}
+	assert.Len(t, used, 4)
+	for _, count := range used {
+		assert.Greater(t, count, 40)
+	}
+}
+
+func TestScheduleConsistentHashBoundedLoad(t *testing.T) {
+	policy := &aiv1alpha1.SchedulingPolicy{Mode: aiv1alpha1.SchedulingModeConsistentHash}
+	s := &SchedulerImpl{}
+	pods := newHashPods(4)
+
+	ctx := newHashContext("hot input", policy)
+	require.NoError(t, s.Schedule(ctx, pods))
+	hot := ctx.BestPods[0]
+
+	// The pod the prompt hashes to is overloaded, the next pod on the ring takes the request
+	hot.RequestRunningNum = 10
+	ctx = newHashContext("hot input", policy)
+	require.NoError(t, s.Schedule(ctx, pods))
+	assert.NotSame(t, hot, ctx.BestPods[0])
+	assert.Same(t, hot, ctx.BestPods[len(ctx.BestPods)-1], "the overloaded pod is the last fallback")
+
+	// A higher bound tolerates the load
+	policy.ConsistentHash = &aiv1alpha1.ConsistentHash{MaxLoadPercent: 500}
+	ctx = newHashContext("hot input", policy)
+	require.NoError(t, s.Schedule(ctx, pods))
+	assert.Same(t, hot, ctx.BestPods[0])
+}
+
+func TestHashRingStability(t *testing.T) {
+	before := newHashRing([]string{"default/pod0", "default/pod1", "default/pod2", "default/pod3"})
+	after := newHashRing([]string{"default/pod0", "default/pod1", "default/pod2", "default/pod3", "default/pod4"})
+
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		hash := uint64(i) * 0x9E3779B97F4A7C15
+		from, to := before.walk(hash)[0], after.walk(hash)[0]
+		if from != to {
+			// Only the prompts taken over by the new pod move
+			assert.Equal(t, "default/pod4", to)
+			moved++
+		}
+	}
+	assert.Less(t, moved, 400)
+}
+
+func TestRingOfRebuildsOnPodChanges(t *testing.T) {
+	s := &SchedulerImpl{}
+	pods := newHashPods(3)
+	ctx := newHashContext("", nil)
+
+	ring := s.ringOf(ctx, pods)
+	assert.Same(t, ring, s.ringOf(ctx, []*datastore.PodInfo{pods[2], pods[0], pods[1]}))
+	assert.NotSame(t, ring, s.ringOf(ctx, pods[:2]))
+}
+
+func TestScheduleConsistentHashBatchLoad(t *testing.T) {
+	policy := &aiv1alpha1.SchedulingPolicy{Mode: aiv1alpha1.SchedulingModeConsistentHash}
+	s := &SchedulerImpl{}
+	pods := newHashPods(4)
+	for _, pod := range pods {
+		pod.RequestRunningNum = 4
+	}
+
+	ctx := newHashContext("batch input", policy)
+	require.NoError(t, s.Schedule(ctx, pods))
+	hot := ctx.BestPods[0]
+	hot.RequestRunningNum = 5
+
+	// A single input still fits on the pod it hashes to
+	ctx = newHashContext("batch input", policy)
+	require.NoError(t, s.Schedule(ctx, pods))
+	assert.Same(t, hot, ctx.BestPods[0])
+
+	// A batch of inputs would overload it, it goes to the next pod on the ring
+	ctx = newHashContext("batch input", policy)
+	ctx.BatchSize = 3
+	require.NoError(t, s.Schedule(ctx, pods))
+	assert.NotSame(t, hot, ctx.BestPods[0])
+}
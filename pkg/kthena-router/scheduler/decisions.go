@@ -0,0 +1,124 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+// DecisionStore keeps the last scheduling decisions of each model in a ring buffer,
+// so that operators can find out why a request was sent to a pod.
+type DecisionStore struct {
+	size int
+
+	mutex   sync.RWMutex
+	buffers map[string]*decisionRing
+}
+
+type decisionRing struct {
+	decisions []*framework.Decision
+	// next is the index the next decision is written to
+	next int
+}
+
+// NewDecisionStore creates a store keeping the last size decisions of each model.
+// A store of size 0 keeps nothing.
+func NewDecisionStore(size int) *DecisionStore {
+	if size < 0 {
+		size = 0
+	}
+	return &DecisionStore{
+		size:    size,
+		buffers: make(map[string]*decisionRing),
+	}
+}
+
+// Enabled returns whether the store keeps any decision.
+func (s *DecisionStore) Enabled() bool {
+	return s != nil && s.size > 0
+}
+
+// Add records a decision, evicting the oldest decision of the model if its buffer is full.
+// The decision must not be modified afterwards.
+func (s *DecisionStore) Add(decision *framework.Decision) {
+	if !s.Enabled() || decision == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ring, ok := s.buffers[decision.Model]
+	if !ok {
+		ring = &decisionRing{decisions: make([]*framework.Decision, 0, s.size)}
+		s.buffers[decision.Model] = ring
+	}
+	if len(ring.decisions) < s.size {
+		ring.decisions = append(ring.decisions, decision)
+	} else {
+		ring.decisions[ring.next] = decision
+	}
+	ring.next = (ring.next + 1) % s.size
+}
+
+// List returns the decisions of a model, newest first.
+func (s *DecisionStore) List(model string) []*framework.Decision {
+	if !s.Enabled() {
+		return nil
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ring, ok := s.buffers[model]
+	if !ok {
+		return nil
+	}
+	n := len(ring.decisions)
+	res := make([]*framework.Decision, 0, n)
+	for i := 1; i <= n; i++ {
+		res = append(res, ring.decisions[(ring.next-i+n)%n])
+	}
+	return res
+}
+
+// Models returns the models with recorded decisions, sorted by name.
+func (s *DecisionStore) Models() []string {
+	if !s.Enabled() {
+		return nil
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	models := make([]string, 0, len(s.buffers))
+	for model := range s.buffers {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+	return models
+}
+
+// Delete drops the decisions of a model.
+func (s *DecisionStore) Delete(model string) {
+	if !s.Enabled() {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.buffers, model)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+func TestDecisionStore(t *testing.T) {
+	store := NewDecisionStore(3)
+	for _, id := range []string{"1", "2", "3", "4"} {
+		store.Add(&framework.Decision{RequestID: id, Model: "model"})
+	}
+	store.Add(&framework.Decision{RequestID: "5", Model: "other"})
+
+	var ids []string
+	for _, decision := range store.List("model") {
+		ids = append(ids, decision.RequestID)
+	}
+	assert.Equal(t, []string{"4", "3", "2"}, ids)
+	assert.Equal(t, []string{"model", "other"}, store.Models())
+
+	store.Delete("model")
+	assert.Empty(t, store.List("model"))
+	assert.Equal(t, []string{"other"}, store.Models())
+
+	disabled := NewDecisionStore(0)
+	disabled.Add(&framework.Decision{RequestID: "1", Model: "model"})
+	assert.False(t, disabled.Enabled())
+	assert.Empty(t, disabled.List("model"))
+}
+
+func TestScheduleRecordsDecision(t *testing.T) {
+	newPod := func(name string) *datastore.PodInfo {
+		return &datastore.PodInfo{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}}
+	}
+	pod1, pod2, pod3 := newPod("pod1"), newPod("pod2"), newPod("pod3")
+	s := &SchedulerImpl{
+		filterPlugins: []framework.FilterPlugin{&podFilterPlugin{name: "drop-pod3", drop: pod3}},
+		scorePlugins: []*scorePlugin{
+			{plugin: &mapScorePlugin{name: "a", scores: map[*datastore.PodInfo]int{pod1: 10, pod2: 30}}, weight: 2},
+			{plugin: &mapScorePlugin{name: "b", scores: map[*datastore.PodInfo]int{pod1: 50, pod2: 20}}, weight: 1},
+		},
+	}
+
+	ctx := &framework.Context{Decision: &framework.Decision{Model: "model"}}
+	assert.NoError(t, s.Schedule(ctx, []*datastore.PodInfo{pod1, pod2, pod3}))
+
+	decision := ctx.Decision
+	assert.Equal(t, []framework.FilterResult{{Plugin: "drop-pod3", Input: 3, Removed: []string{"default/pod3"}}}, decision.Filters)
+	assert.Len(t, decision.Rounds, 1)
+	round := decision.Rounds[0]
+	assert.Equal(t, framework.ScoreStageAggregated, round.Stage)
+	assert.Equal(t, []framework.PluginScores{
+		{Plugin: "a", Weight: 2, Scores: map[string]int{"default/pod1": 10, "default/pod2": 30}},
+		{Plugin: "b", Weight: 1, Scores: map[string]int{"default/pod1": 50, "default/pod2": 20}},
+	}, round.Plugins)
+	assert.Equal(t, map[string]int{"default/pod1": 70, "default/pod2": 80}, round.Scores)
+	assert.Equal(t, []string{"default/pod2", "default/pod1"}, round.Selected)
+	assert.Equal(t, "default/pod2", decision.Selected())
+}
+
+type podFilterPlugin struct {
+	name string
+	drop *datastore.PodInfo
+}
+
+func (p *podFilterPlugin) Name() string {
+	return p.name
+}
+
+func (p *podFilterPlugin) Filter(ctx *framework.Context, pods []*datastore.PodInfo) []*datastore.PodInfo {
+	res := []*datastore.PodInfo{}
+	for _, pod := range pods {
+		if pod != p.drop {
+			res = append(res, pod)
+		}
+	}
+	return res
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package scheduler
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -62,8 +65,75 @@ func (r *PluginRegistry) getFilterPlugin(name string) (FilterPluginBuilder, bool
 	return fp, exist
 }
 
-// registerDefaultPlugins registers all default plugins to the given registry
+// outOfTreePlugins holds the plugins registered by the programs embedding the scheduler.
+var outOfTreePlugins = struct {
+	sync.Mutex
+	*PluginRegistry
+}{PluginRegistry: NewPluginRegistry()}
+
+// RegisterScorePlugin registers a score plugin built outside of Kthena, so that the schedulers created afterwards
+// can enable it by name in their configuration. It fails when a plugin of Kthena or another plugin has the name.
+func RegisterScorePlugin(name string, builder ScorePluginBuilder) error {
+	outOfTreePlugins.Lock()
+	defer outOfTreePlugins.Unlock()
+	if _, exist := outOfTreePlugins.getScorePlugin(name); exist || inTreeScorePlugin(name) {
+		return fmt.Errorf("score plugin %q is already registered", name)
+	}
+	outOfTreePlugins.registerScorePlugin(name, builder)
+	return nil
+}
+
+// RegisterFilterPlugin registers a filter plugin built outside of Kthena, so that the schedulers created afterwards
+// can enable it by name in their configuration. It fails when a plugin of Kthena or another plugin has the name.
+func RegisterFilterPlugin(name string, builder FilterPluginBuilder) error {
+	outOfTreePlugins.Lock()
+	defer outOfTreePlugins.Unlock()
+	if _, exist := outOfTreePlugins.getFilterPlugin(name); exist || inTreeFilterPlugin(name) {
+		return fmt.Errorf("filter plugin %q is already registered", name)
+	}
+	outOfTreePlugins.registerFilterPlugin(name, builder)
+	return nil
+}
+
+// IsScorePlugin reports whether a score plugin of Kthena, or registered outside of Kthena, has the name.
+func IsScorePlugin(name string) bool {
+	outOfTreePlugins.Lock()
+	defer outOfTreePlugins.Unlock()
+	if _, exist := outOfTreePlugins.getScorePlugin(name); exist {
+		return true
+	}
+	return inTreeScorePlugin(name)
+}
+
+func inTreeScorePlugin(name string) bool {
+	registry := NewPluginRegistry()
+	registerInTreePlugins(registry)
+	_, exist := registry.getScorePlugin(name)
+	return exist
+}
+
+func inTreeFilterPlugin(name string) bool {
+	registry := NewPluginRegistry()
+	registerInTreePlugins(registry)
+	_, exist := registry.getFilterPlugin(name)
+	return exist
+}
+
+// registerDefaultPlugins registers the plugins of Kthena and the out of tree plugins to the given registry
 func registerDefaultPlugins(registry *PluginRegistry) {
+	registerInTreePlugins(registry)
+	outOfTreePlugins.Lock()
+	defer outOfTreePlugins.Unlock()
+	for name, builder := range outOfTreePlugins.scorePluginBuilders {
+		registry.registerScorePlugin(name, builder)
+	}
+	for name, builder := range outOfTreePlugins.filterPluginBuilders {
+		registry.registerFilterPlugin(name, builder)
+	}
+}
+
+// registerInTreePlugins registers the plugins of Kthena to the given registry
+func registerInTreePlugins(registry *PluginRegistry) {
 	// scorePlugin
 	registry.registerScorePlugin(plugins.GPUCacheUsagePluginName, func(args runtime.RawExtension) framework.ScorePlugin {
 		return plugins.NewGPUCacheUsage()
@@ -85,6 +155,15 @@ func registerDefaultPlugins(registry *PluginRegistry) {
 	registry.registerScorePlugin(plugins.KVCacheAwarePluginName, func(args runtime.RawExtension) framework.ScorePlugin {
 		return plugins.NewKVCacheAware(args)
 	})
+	registry.registerScorePlugin(plugins.LoraAdapterPluginName, func(args runtime.RawExtension) framework.ScorePlugin {
+		return plugins.NewLoraAdapter(args)
+	})
+	registry.registerScorePlugin(plugins.PredictedLatencyPluginName, func(args runtime.RawExtension) framework.ScorePlugin {
+		return plugins.NewPredictedLatency(args)
+	})
+	registry.registerScorePlugin(plugins.AcceleratorPluginName, func(args runtime.RawExtension) framework.ScorePlugin {
+		return plugins.NewAccelerator()
+	})
 	// filterPlugin
 	registry.registerFilterPlugin(plugins.LeastRequestPluginName, func(args runtime.RawExtension) framework.FilterPlugin {
 		return plugins.NewLeastRequest(args)
@@ -92,6 +171,9 @@ func registerDefaultPlugins(registry *PluginRegistry) {
 	registry.registerFilterPlugin(plugins.LoraAffinityPluginName, func(args runtime.RawExtension) framework.FilterPlugin {
 		return plugins.NewLoraAffinity()
 	})
+	registry.registerFilterPlugin(plugins.LoraAdapterPluginName, func(args runtime.RawExtension) framework.FilterPlugin {
+		return plugins.NewLoraAdapter(args)
+	})
 }
 
 func getFilterPlugins(registry *PluginRegistry, filterPluginMap []string, pluginsArgMap map[string]runtime.RawExtension) []framework.FilterPlugin {
@@ -141,3 +223,41 @@ func getScorePlugins(registry *PluginRegistry, prefixCache *plugins.PrefixCache,
 	}
 	return list
 }
+
+// getPostScheduleHooks returns the post schedule hooks of the plugins, the prefix cache first.
+func getPostScheduleHooks(prefixCache *plugins.PrefixCache, filterPlugins []framework.FilterPlugin, scorePlugins []*scorePlugin) []framework.PostScheduleHook {
+	hooks := []framework.PostScheduleHook{prefixCache}
+	seen := map[string]bool{prefixCache.Name(): true}
+	add := func(plugin any) {
+		if hook, ok := plugin.(framework.PostScheduleHook); ok && !seen[hook.Name()] {
+			seen[hook.Name()] = true
+			hooks = append(hooks, hook)
+		}
+	}
+	for _, p := range filterPlugins {
+		add(p)
+	}
+	for _, p := range scorePlugins {
+		add(p.plugin)
+	}
+	return hooks
+}
+
+// getResponseObservers returns the plugins learning from the responses of the pods.
+func getResponseObservers(filterPlugins []framework.FilterPlugin, scorePlugins []*scorePlugin) []framework.ResponseObserver {
+	var observers []framework.ResponseObserver
+	seen := map[string]bool{}
+	add := func(plugin any) {
+		if observer, ok := plugin.(framework.ResponseObserver); ok && !seen[observer.Name()] {
+			seen[observer.Name()] = true
+			observers = append(observers, observer)
+		}
+	}
+	for _, p := range filterPlugins {
+		add(p)
+	}
+	for _, p := range scorePlugins {
+		add(p.plugin)
+	}
+	return observers
+}
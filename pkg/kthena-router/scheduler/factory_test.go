@@ -53,6 +53,8 @@ func TestRegisterDefaultPlugins(t *testing.T) {
 		plugins.RandomPluginName,
 		plugins.PrefixCachePluginName,
 		plugins.KVCacheAwarePluginName,
+		plugins.LoraAdapterPluginName,
+		plugins.PredictedLatencyPluginName,
 	}
 
 	for _, pluginName := range expectedScorePlugins {
@@ -75,6 +77,7 @@ func TestRegisterDefaultPlugins(t *testing.T) {
 	expectedFilterPlugins := []string{
 		plugins.LeastRequestPluginName,
 		plugins.LoraAffinityPluginName,
+		plugins.LoraAdapterPluginName,
 	}
 
 	for _, pluginName := range expectedFilterPlugins {
@@ -264,3 +267,40 @@ func TestGetScorePlugins(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPostScheduleHooks(t *testing.T) {
+	registry := NewPluginRegistry()
+	registerDefaultPlugins(registry)
+	prefixCache := plugins.NewPrefixCache(datastore.New(), runtime.RawExtension{Raw: []byte(`{"blockSizeToHash": 64}`)})
+
+	filterPlugins := getFilterPlugins(registry, []string{plugins.LeastRequestPluginName, plugins.LoraAdapterPluginName}, nil)
+	scorePlugins := getScorePlugins(registry, prefixCache, map[string]int{
+		plugins.PrefixCachePluginName:  1,
+		plugins.LeastRequestPluginName: 1,
+		plugins.LoraAdapterPluginName:  1,
+	}, nil)
+
+	// The hooks of the plugins enabled both as filter and score plugins run once
+	hooks := getPostScheduleHooks(prefixCache, filterPlugins, scorePlugins)
+	var names []string
+	for _, hook := range hooks {
+		names = append(names, hook.Name())
+	}
+	assert.Equal(t, []string{plugins.PrefixCachePluginName, plugins.LoraAdapterPluginName}, names)
+}
+
+func TestGetResponseObservers(t *testing.T) {
+	registry := NewPluginRegistry()
+	registerDefaultPlugins(registry)
+	prefixCache := plugins.NewPrefixCache(datastore.New(), runtime.RawExtension{Raw: []byte(`{"blockSizeToHash": 64}`)})
+
+	scorePlugins := getScorePlugins(registry, prefixCache, map[string]int{
+		plugins.PrefixCachePluginName:      1,
+		plugins.LeastRequestPluginName:     1,
+		plugins.PredictedLatencyPluginName: 1,
+	}, nil)
+
+	observers := getResponseObservers(nil, scorePlugins)
+	assert.Len(t, observers, 1)
+	assert.Equal(t, plugins.PredictedLatencyPluginName, observers[0].Name())
+}
@@ -0,0 +1,178 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"sort"
+	"time"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+const (
+	// ScoreStageAggregated scores the pods of a PD aggregated model server.
+	ScoreStageAggregated = "aggregated"
+	// ScoreStageDecode scores the decode pods of a PD disaggregated model server.
+	ScoreStageDecode = "decode"
+	// ScoreStagePrefill scores the prefill pods of the PD group of a decode pod.
+	ScoreStagePrefill = "prefill"
+	// ScoreStageConsistentHash picks the pods of a model server in the ConsistentHash mode, without scores.
+	ScoreStageConsistentHash = "consistent-hash"
+)
+
+// Decision explains how the scheduler picked the pods of a request, i.e. which pods
+// the filter plugins removed and how each score plugin ranked the remaining ones.
+// All its methods are no-ops on a nil Decision, so that it is only built when asked for.
+type Decision struct {
+	RequestID   string    `json:"requestID,omitempty"`
+	Model       string    `json:"model"`
+	ModelServer string    `json:"modelServer"`
+	Time        time.Time `json:"time"`
+
+	Filters []FilterResult `json:"filters,omitempty"`
+	Rounds  []*ScoreRound  `json:"rounds,omitempty"`
+	// Error is set when no pod could be scheduled.
+	Error string `json:"error,omitempty"`
+}
+
+// FilterResult lists the pods removed by a filter plugin.
+type FilterResult struct {
+	Plugin  string   `json:"plugin"`
+	Input   int      `json:"input"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// ScoreRound is a run of the score plugins over a set of candidate pods. A PD disaggregated
+// request has a decode round, followed by a prefill round for each of the selected decode pods.
+type ScoreRound struct {
+	Stage   string          `json:"stage"`
+	Plugins []PluginScores  `json:"plugins,omitempty"`
+	Skipped []SkippedPlugin `json:"skipped,omitempty"`
+	// Scores are the final weighted scores of the pods.
+	Scores map[string]int `json:"scores,omitempty"`
+	// Selected are the pods picked by the round, best first. The proxy falls
+	// back to the next one when a request to a pod fails.
+	Selected []string `json:"selected,omitempty"`
+}
+
+// PluginScores are the scores given by a score plugin, before they are weighted.
+type PluginScores struct {
+	Plugin string         `json:"plugin"`
+	Weight int            `json:"weight"`
+	Scores map[string]int `json:"scores"`
+}
+
+// SkippedPlugin is a score plugin left out of a round.
+type SkippedPlugin struct {
+	Plugin string `json:"plugin"`
+	Reason string `json:"reason"`
+}
+
+// RecordFilter records the pods removed by a filter plugin.
+func (d *Decision) RecordFilter(plugin string, input, output []*datastore.PodInfo) {
+	if d == nil {
+		return
+	}
+	kept := make(map[*datastore.PodInfo]struct{}, len(output))
+	for _, pod := range output {
+		kept[pod] = struct{}{}
+	}
+	result := FilterResult{Plugin: plugin, Input: len(input)}
+	for _, pod := range input {
+		if _, ok := kept[pod]; !ok {
+			result.Removed = append(result.Removed, podName(pod))
+		}
+	}
+	d.Filters = append(d.Filters, result)
+}
+
+// StartRound starts recording a new run of the score plugins.
+func (d *Decision) StartRound(stage string) {
+	if d == nil {
+		return
+	}
+	d.Rounds = append(d.Rounds, &ScoreRound{Stage: stage})
+}
+
+// RecordScores records the scores given by a score plugin in the current round.
+func (d *Decision) RecordScores(plugin string, weight int, scores map[*datastore.PodInfo]int) {
+	round := d.currentRound()
+	if round == nil {
+		return
+	}
+	round.Plugins = append(round.Plugins, PluginScores{
+		Plugin: plugin,
+		Weight: weight,
+		Scores: podScores(scores),
+	})
+}
+
+// RecordSkipped records a score plugin left out of the current round.
+func (d *Decision) RecordSkipped(plugin, reason string) {
+	round := d.currentRound()
+	if round == nil {
+		return
+	}
+	round.Skipped = append(round.Skipped, SkippedPlugin{Plugin: plugin, Reason: reason})
+}
+
+// FinishRound records the final scores of the current round and the pods it selected.
+func (d *Decision) FinishRound(scores map[*datastore.PodInfo]int, selected []*datastore.PodInfo) {
+	round := d.currentRound()
+	if round == nil {
+		return
+	}
+	// Plugins finish in any order, sort them to keep the decisions comparable
+	sort.SliceStable(round.Plugins, func(i, j int) bool {
+		return round.Plugins[i].Plugin < round.Plugins[j].Plugin
+	})
+	round.Scores = podScores(scores)
+	for _, pod := range selected {
+		round.Selected = append(round.Selected, podName(pod))
+	}
+}
+
+// Selected returns the pod picked by the first round, which is the pod a PD aggregated
+// request is sent to, or the decode pod of a PD disaggregated request.
+func (d *Decision) Selected() string {
+	if d == nil || len(d.Rounds) == 0 || len(d.Rounds[0].Selected) == 0 {
+		return ""
+	}
+	return d.Rounds[0].Selected[0]
+}
+
+func (d *Decision) currentRound() *ScoreRound {
+	if d == nil || len(d.Rounds) == 0 {
+		return nil
+	}
+	return d.Rounds[len(d.Rounds)-1]
+}
+
+func podScores(scores map[*datastore.PodInfo]int) map[string]int {
+	res := make(map[string]int, len(scores))
+	for pod, score := range scores {
+		res[podName(pod)] = score
+	}
+	return res
+}
+
+func podName(pod *datastore.PodInfo) string {
+	if pod == nil || pod.Pod == nil {
+		return ""
+	}
+	return pod.Pod.Namespace + "/" + pod.Pod.Name
+}
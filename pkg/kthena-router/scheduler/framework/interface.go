@@ -17,6 +17,9 @@ limitations under the License.
 package framework
 
 import (
+	"context"
+	"time"
+
 	"k8s.io/apimachinery/pkg/types"
 
 	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
@@ -27,14 +30,34 @@ import (
 
 // Context stores information which maybe useful in Filter or Score plugins.
 type Context struct {
+	// Ctx is canceled when the deadline of the running score plugin expires.
+	// Plugins doing I/O, e.g. querying Redis, should pass it on. It may be nil.
+	Ctx context.Context
+
 	Model  string
 	Prompt common.ChatMessage
+	// LoraAdapter is the LoRA adapter the request is for, if any. Model is then the adapter name too.
+	LoraAdapter string
+	// ServedModel is the model the pods serve the request with, the model of the ModelServer or else the requested
+	// model. It is empty when unknown, e.g. for a LoRA adapter of a ModelServer without model.
+	ServedModel string
+
+	// RequestType is the kind of the request, embedding and rerank requests are never
+	// streamed nor served by PD disaggregated model servers.
+	RequestType common.RequestType
+	// BatchSize is the number of inputs of an embedding or rerank request, each of which
+	// is processed as a separate sequence by the inference engine. The consistent hash scheduling
+	// counts it as the load the request adds to its pod.
+	BatchSize int
 
+	// Hashes of the prompt, set by the prefix cache score plugin for its post schedule hook.
 	Hashes []uint64
 
 	// ModelServer information for efficient PDGroup scheduling
 	ModelServerName types.NamespacedName
 	PDGroup         *aiv1alpha1.PDGroup
+	// SchedulingPolicy of the ModelServer, if any, overrides the weights of the score plugins.
+	SchedulingPolicy *aiv1alpha1.SchedulingPolicy
 	// 1. In PD Disaggregated mode, both DecodePods and PrefillPods are set.
 	DecodePods  []*datastore.PodInfo
 	PrefillPods []*datastore.PodInfo
@@ -44,12 +67,17 @@ type Context struct {
 
 	// MetricsRecorder for recording scheduler plugin metrics
 	MetricsRecorder *metrics.RequestMetricsRecorder
+
+	// Decision, if set, records how the pods were picked. It is not visible to the score plugins.
+	Decision *Decision
 }
 
 type ScorePlugin interface {
 	Name() string
 	// Score is a method that is used to rank pods that have passed the filter plugins.
 	// Note each plugin should generate score for a pod within [0, 100]
+	// Score plugins run concurrently, each with its own copy of the Context. Only the Hashes they set
+	// are kept, and only if the plugin meets its deadline.
 	Score(ctx *Context, pods []*datastore.PodInfo) map[*datastore.PodInfo]int
 }
 
@@ -64,3 +92,23 @@ type PostScheduleHook interface {
 	Name() string
 	PostSchedule(ctx *Context, index int)
 }
+
+// ResponseObserver is implemented by the plugins learning from the responses of the pods. It is called once the
+// response of the pod at index in the best pods, or the decode pods, was fully forwarded to the client.
+type ResponseObserver interface {
+	Name() string
+	ObserveResponse(ctx *Context, index int, response Response)
+}
+
+// Response describes a response of a pod, as observed by the router.
+type Response struct {
+	// Stream is whether the response was streamed, only then is TimeToFirstToken the time of the first token.
+	Stream bool
+	// TimeToFirstToken is the time from sending the request to the first byte of the response.
+	TimeToFirstToken time.Duration
+	// Duration is the time from sending the request to the end of the response.
+	Duration time.Duration
+	// PromptTokens and CompletionTokens are the usage reported by the engine, zero when it reported none.
+	PromptTokens     int
+	CompletionTokens int
+}
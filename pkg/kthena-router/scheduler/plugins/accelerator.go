@@ -0,0 +1,89 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+var _ framework.ScorePlugin = &Accelerator{}
+
+const AcceleratorPluginName = "accelerator"
+
+// Accelerator scores the pods by the accelerator of their node, following the accelerator policy of the
+// ModelServer: the pods of the preferred classes score 50, and the cheapest pods score up to 50 more, from
+// the most expensive class, 0, to the cheapest one. The pods score 0 without a policy.
+type Accelerator struct {
+	name string
+}
+
+func NewAccelerator() *Accelerator {
+	return &Accelerator{
+		name: AcceleratorPluginName,
+	}
+}
+
+func (a *Accelerator) Name() string {
+	return a.name
+}
+
+func (a *Accelerator) Score(ctx *framework.Context, pods []*datastore.PodInfo) map[*datastore.PodInfo]int {
+	scoreResults := make(map[*datastore.PodInfo]int, len(pods))
+	if ctx.SchedulingPolicy == nil || ctx.SchedulingPolicy.Accelerators == nil {
+		for _, info := range pods {
+			scoreResults[info] = 0
+		}
+		return scoreResults
+	}
+	policy := ctx.SchedulingPolicy.Accelerators
+
+	costs := make(map[*datastore.PodInfo]int32, len(pods))
+	for _, info := range pods {
+		accelerator := info.Accelerator()
+		for _, cost := range policy.Costs {
+			if datastore.MatchesAccelerator(accelerator, cost.Accelerator) {
+				costs[info] = cost.Cost
+				break
+			}
+		}
+	}
+	lowest, highest := int32(-1), int32(0)
+	for _, cost := range costs {
+		if lowest < 0 || cost < lowest {
+			lowest = cost
+		}
+		highest = max(highest, cost)
+	}
+
+	for _, info := range pods {
+		score := 0
+		if datastore.MatchesAnyAccelerator(info.Accelerator(), policy.Preferred) {
+			score += 50
+		}
+		// The pods of the classes without a cost score like the most expensive ones
+		cost, ok := costs[info]
+		switch {
+		case highest == lowest || len(costs) == 0:
+			score += 50
+		case ok:
+			score += int(50 * int64(highest-cost) / int64(highest-lowest))
+		}
+		scoreResults[info] = score
+	}
+	return scoreResults
+}
@@ -0,0 +1,113 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+// newAcceleratorPods returns a pod on a node of each accelerator, in order.
+func newAcceleratorPods(t *testing.T, accelerators ...string) []*datastore.PodInfo {
+	store := datastore.New()
+	ms := &aiv1alpha1.ModelServer{ObjectMeta: metav1.ObjectMeta{Name: "ms", Namespace: "default"}}
+	pods := make([]*datastore.PodInfo, 0, len(accelerators))
+	for i, accelerator := range accelerators {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   accelerator + "-node",
+			Labels: map[string]string{datastore.AcceleratorLabelKey: accelerator},
+		}}
+		require.NoError(t, store.AddOrUpdateNode(node))
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: string(rune('a' + i)), Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: node.Name},
+		}
+		require.NoError(t, store.AddOrUpdatePod(pod, []*aiv1alpha1.ModelServer{ms}))
+		pods = append(pods, store.GetPodInfo(types.NamespacedName{Namespace: "default", Name: pod.Name}))
+	}
+	return pods
+}
+
+func TestAcceleratorScore(t *testing.T) {
+	pods := newAcceleratorPods(t, "NVIDIA-A100-SXM4-80GB", "NVIDIA-H100-80GB-HBM3", "NVIDIA-H200", "NVIDIA-L40S")
+	a100, h100, h200, l40s := pods[0], pods[1], pods[2], pods[3]
+	plugin := NewAccelerator()
+	assert.Equal(t, AcceleratorPluginName, plugin.Name())
+
+	tests := []struct {
+		name   string
+		policy *aiv1alpha1.SchedulingPolicy
+		want   map[*datastore.PodInfo]int
+	}{
+		{
+			name: "no policy",
+			want: map[*datastore.PodInfo]int{a100: 0, h100: 0, h200: 0, l40s: 0},
+		},
+		{
+			name:   "no accelerator policy",
+			policy: &aiv1alpha1.SchedulingPolicy{},
+			want:   map[*datastore.PodInfo]int{a100: 0, h100: 0, h200: 0, l40s: 0},
+		},
+		{
+			name:   "preferred",
+			policy: &aiv1alpha1.SchedulingPolicy{Accelerators: &aiv1alpha1.AcceleratorPolicy{Preferred: []string{"H100", "H200"}}},
+			want:   map[*datastore.PodInfo]int{a100: 50, h100: 100, h200: 100, l40s: 50},
+		},
+		{
+			name: "costs",
+			policy: &aiv1alpha1.SchedulingPolicy{Accelerators: &aiv1alpha1.AcceleratorPolicy{Costs: []aiv1alpha1.AcceleratorCost{
+				{Accelerator: "A100", Cost: 2},
+				{Accelerator: "H100", Cost: 4},
+				{Accelerator: "H200", Cost: 6},
+			}}},
+			want: map[*datastore.PodInfo]int{a100: 50, h100: 25, h200: 0, l40s: 0},
+		},
+		{
+			name: "preferred and costs",
+			policy: &aiv1alpha1.SchedulingPolicy{Accelerators: &aiv1alpha1.AcceleratorPolicy{
+				Preferred: []string{"H200"},
+				Costs: []aiv1alpha1.AcceleratorCost{
+					{Accelerator: "A100", Cost: 2},
+					{Accelerator: "H200", Cost: 6},
+				},
+			}},
+			want: map[*datastore.PodInfo]int{a100: 50, h100: 0, h200: 50, l40s: 0},
+		},
+		{
+			name: "a single cost",
+			policy: &aiv1alpha1.SchedulingPolicy{Accelerators: &aiv1alpha1.AcceleratorPolicy{Costs: []aiv1alpha1.AcceleratorCost{
+				{Accelerator: "A100", Cost: 2},
+			}}},
+			want: map[*datastore.PodInfo]int{a100: 50, h100: 50, h200: 50, l40s: 50},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &framework.Context{SchedulingPolicy: tt.policy}
+			assert.Equal(t, tt.want, plugin.Score(ctx, pods))
+		})
+	}
+}
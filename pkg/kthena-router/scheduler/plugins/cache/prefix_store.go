@@ -17,6 +17,7 @@ limitations under the License.
 package cache
 
 import (
+	"encoding/json"
 	"sync"
 
 	"istio.io/istio/pkg/util/sets"
@@ -35,6 +36,9 @@ const (
 	// numShards is the number of shards to use for the modelHashes map.
 	// Using a power of 2 can be slightly more efficient for the modulo operation.
 	numShards = 32
+
+	// PrefixStateName is the name of the prefix index in the persisted learned states.
+	PrefixStateName = "prefix-cache"
 )
 
 // modelHashesShard holds a shard of the hashes for a specific model.
@@ -66,6 +70,8 @@ func (mh *modelHashes) getShard(hash uint64) *modelHashesShard {
 
 // ModelPrefixStore manages a three-level map structure for model inference requests
 type ModelPrefixStore struct {
+	store datastore.Store
+
 	// Mutex to protect the entries map itself
 	entriesMu sync.RWMutex
 	// map: model -> modelHashes
@@ -87,6 +93,7 @@ type MatchResult struct {
 // NewModelPrefixStore creates a new ModelPrefixStore with the specified capacity and topK
 func NewModelPrefixStore(store datastore.Store, hashCapacity, topK int) *ModelPrefixStore {
 	s := &ModelPrefixStore{
+		store:        store,
 		entries:      make(map[string]*modelHashes),
 		podHashes:    make(map[types.NamespacedName]Cache[hashModelKey, struct{}]),
 		topK:         topK,
@@ -95,6 +102,7 @@ func NewModelPrefixStore(store datastore.Store, hashCapacity, topK int) *ModelPr
 
 	// Register callback for pod deletion
 	store.RegisterCallback("Pod", s.onPodDeleted)
+	store.RegisterState(s)
 
 	return s
 }
@@ -199,11 +207,13 @@ func (s *ModelPrefixStore) FindTopMatches(model string, hashes []uint64, pods []
 
 // Add adds new hash->pod mappings to cache, using LRU for eviction
 func (s *ModelPrefixStore) Add(model string, hashes []uint64, pod *datastore.PodInfo) {
-	nsName := types.NamespacedName{
+	s.add(model, hashes, types.NamespacedName{
 		Namespace: pod.Pod.Namespace,
 		Name:      pod.Pod.Name,
-	}
+	})
+}
 
+func (s *ModelPrefixStore) add(model string, hashes []uint64, nsName types.NamespacedName) {
 	s.podHashesMu.Lock()
 	podLRU, exists := s.podHashes[nsName]
 	if !exists {
@@ -261,3 +271,44 @@ func (s *ModelPrefixStore) onHashEvicted(model string, hashSlice []uint64, nsNam
 		shard.mu.Unlock()
 	}
 }
+
+// podPrefixes are the hashes cached by a pod for each model, from the least to the most recently used.
+type podPrefixes map[string][]uint64
+
+// Name returns the name of the prefix index in the persisted learned states.
+func (s *ModelPrefixStore) Name() string {
+	return PrefixStateName
+}
+
+// Save encodes the hashes cached by each pod.
+func (s *ModelPrefixStore) Save() ([]byte, error) {
+	s.podHashesMu.RLock()
+	pods := make(map[string]podPrefixes, len(s.podHashes))
+	for nsName, podLRU := range s.podHashes {
+		prefixes := podPrefixes{}
+		for _, key := range podLRU.Keys() {
+			prefixes[key.model] = append(prefixes[key.model], key.hash)
+		}
+		pods[nsName.String()] = prefixes
+	}
+	s.podHashesMu.RUnlock()
+	return json.Marshal(pods)
+}
+
+// Restore adds the hashes cached by the pods which still exist.
+func (s *ModelPrefixStore) Restore(data []byte) error {
+	var pods map[string]podPrefixes
+	if err := json.Unmarshal(data, &pods); err != nil {
+		return err
+	}
+	for _, pod := range s.store.GetAllPods() {
+		nsName := types.NamespacedName{Namespace: pod.Pod.Namespace, Name: pod.Pod.Name}
+		for model, hashes := range pods[nsName.String()] {
+			// The hashes are added one by one, so that they keep their order in the LRU of the pod
+			for _, hash := range hashes {
+				s.add(model, []uint64{hash}, nsName)
+			}
+		}
+	}
+	return nil
+}
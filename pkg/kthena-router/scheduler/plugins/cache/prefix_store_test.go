@@ -682,3 +682,44 @@ func BenchmarkModelPrefixStore_FindAndAdd(b *testing.B) {
 		wg.Wait()
 	}
 }
+
+func TestModelPrefixStoreSaveRestore(t *testing.T) {
+	pod1 := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "ns1"}}
+	pod2 := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "ns1"}}
+
+	oldStore := datastore.New()
+	old := NewModelPrefixStore(oldStore, 3, 5)
+	old.Add("model-a", []uint64{1, 2, 3}, &datastore.PodInfo{Pod: pod1})
+	old.Add("model-a", []uint64{4}, &datastore.PodInfo{Pod: pod1})
+	old.Add("model-b", []uint64{5, 6}, &datastore.PodInfo{Pod: pod2})
+	states := oldStore.LearnedStates()
+	var data []byte
+	for _, state := range states {
+		if state.Name() == PrefixStateName {
+			var err error
+			data, err = state.Save()
+			assert.NoError(t, err)
+		}
+	}
+	assert.NotEmpty(t, data)
+
+	// pod2 is gone after the restart
+	newStore := datastore.New()
+	assert.NoError(t, newStore.AddOrUpdatePod(pod1, nil))
+	restored := NewModelPrefixStore(newStore, 3, 5)
+	assert.NoError(t, restored.Restore(data))
+
+	pods := []*datastore.PodInfo{{Pod: pod1}, {Pod: pod2}}
+	pod1Name := types.NamespacedName{Namespace: "ns1", Name: "pod1"}
+	// The hashes are added from the last one, 3 was the least recently used and was evicted before the snapshot
+	assert.Empty(t, restored.FindTopMatches("model-a", []uint64{3}, pods))
+	assert.Equal(t, []MatchResult{{NamespacedName: pod1Name, MatchLen: 1}}, restored.FindTopMatches("model-a", []uint64{1}, pods))
+	assert.Equal(t, []MatchResult{{NamespacedName: pod1Name, MatchLen: 2}}, restored.FindTopMatches("model-a", []uint64{1, 4}, pods))
+	assert.Empty(t, restored.FindTopMatches("model-b", []uint64{5, 6}, pods))
+
+	// The restored hashes keep their order in the LRU of the pod, 2 is the least recently used
+	restored.Add("model-a", []uint64{7}, &datastore.PodInfo{Pod: pod1})
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, restored.FindTopMatches("model-a", []uint64{2}, pods))
+	assert.NotEmpty(t, restored.FindTopMatches("model-a", []uint64{1}, pods))
+}
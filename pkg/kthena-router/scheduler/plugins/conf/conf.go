@@ -19,6 +19,7 @@ package conf
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
@@ -26,8 +27,13 @@ import (
 )
 
 type RouterConfiguration struct {
-	Scheduler SchedulerConfiguration `yaml:"scheduler"`
-	Auth      AuthenticationConfig   `yaml:"auth"`
+	Scheduler  SchedulerConfiguration `yaml:"scheduler"`
+	Auth       AuthenticationConfig   `yaml:"auth"`
+	Access     AccessControlConfig    `yaml:"access"`
+	Audit      AuditConfig            `yaml:"audit"`
+	Chargeback ChargebackConfig       `yaml:"chargeback"`
+	// Tenants serve several tenants on the router, each routing its models to the ModelRoutes of its namespaces.
+	Tenants []TenantConfig `yaml:"tenants"`
 }
 
 type SchedulerConfiguration struct {
@@ -48,11 +54,16 @@ type Filter struct {
 type Score struct {
 	Enabled  []PluginWithWeight `yaml:"enabled"`
 	Disabled []PluginWithWeight `yaml:"disabled"`
+	// Timeout is the default deadline of each score plugin, e.g. "100ms".
+	// The scores of a plugin missing its deadline are left out of the scheduling decision.
+	Timeout string `yaml:"timeout"`
 }
 
 type PluginWithWeight struct {
 	Name   string `yaml:"name"`
 	Weight int    `yaml:"weight"`
+	// Timeout overrides the default deadline of the score plugin.
+	Timeout string `yaml:"timeout"`
 }
 
 type PluginConfig struct {
@@ -66,11 +77,165 @@ type AuthenticationConfig struct {
 	JwksUri   string   `yaml:"jwksUri"`
 }
 
+// AccessControlConfig restricts the models each consumer of the router may use.
+// If no policies are configured, all consumers may use all models.
+type AccessControlConfig struct {
+	// APIKeyHeader is the request header carrying the API key of the consumer, defaults to "X-API-Key".
+	APIKeyHeader string `yaml:"apiKeyHeader"`
+	// DefaultAction applies to consumers not matched by any policy, "allow" or "deny". Defaults to "deny".
+	DefaultAction string `yaml:"defaultAction"`
+	// Policies maps consumers to the models they are allowed or denied to use.
+	Policies []AccessPolicy `yaml:"policies"`
+}
+
+// AccessPolicy maps a group of consumers to model name patterns. Patterns support the
+// '*' and '?' wildcards. Denied models take precedence over allowed models, and an empty
+// list of allowed models allows all models which are not denied.
+type AccessPolicy struct {
+	Name          string         `yaml:"name"`
+	Consumers     ConsumerSelect `yaml:"consumers"`
+	AllowedModels []string       `yaml:"allowedModels"`
+	DeniedModels  []string       `yaml:"deniedModels"`
+}
+
+// ConsumerSelect selects consumers presenting one of the API keys, or
+// a JWT carrying all of the listed claims with one of the listed values.
+// The API keys are configured as salted hashes, "sha256:<salt>:<digest>", the digest being the hex
+// encoded SHA-256 of the salt followed by the key, so that the configuration never holds the keys themselves.
+type ConsumerSelect struct {
+	APIKeyHashes []string            `yaml:"apiKeyHashes"`
+	Claims       map[string][]string `yaml:"claims"`
+}
+
+// AuditConfig records the requests for the opted-in models to an audit sink.
+// Auditing is disabled if no models or no sink are configured.
+type AuditConfig struct {
+	// Models opts models in the audit trail, the first entry matching the model of a request applies.
+	Models []AuditModel `yaml:"models"`
+	Sink   AuditSink    `yaml:"sink"`
+	// Redaction removes the personal data from the audited prompts.
+	Redaction AuditRedaction `yaml:"redaction"`
+	// BatchSize is the maximum number of records written at once, defaults to 100.
+	BatchSize int `yaml:"batchSize"`
+	// FlushInterval is how often the pending records are written, e.g. "5s". Defaults to 5s.
+	FlushInterval string `yaml:"flushInterval"`
+	// BufferSize is the number of records waiting to be written, beyond which records are dropped. Defaults to 10000.
+	BufferSize int `yaml:"bufferSize"`
+}
+
+// AuditModel opts the models matching a name pattern in the audit trail.
+type AuditModel struct {
+	// Name is a model name pattern supporting the '*' and '?' wildcards.
+	Name string `yaml:"name"`
+	// IncludePrompt records the redacted prompt of the requests, only the request metadata is recorded otherwise.
+	IncludePrompt bool `yaml:"includePrompt"`
+}
+
+// AuditSink is where the audit records are written, Type selects one of the sinks.
+type AuditSink struct {
+	// Type is "file", "s3" or "webhook".
+	Type    string           `yaml:"type"`
+	File    AuditFileSink    `yaml:"file"`
+	S3      AuditS3Sink      `yaml:"s3"`
+	Webhook AuditWebhookSink `yaml:"webhook"`
+}
+
+// AuditFileSink appends the records to a file as JSON lines.
+type AuditFileSink struct {
+	Path string `yaml:"path"`
+}
+
+// AuditS3Sink writes each batch of records as a JSON lines object to an S3 compatible bucket.
+// The credentials are read from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.
+type AuditS3Sink struct {
+	// Endpoint is the URL of the object storage, e.g. "https://s3.us-east-1.amazonaws.com".
+	Endpoint string `yaml:"endpoint"`
+	Region   string `yaml:"region"`
+	Bucket   string `yaml:"bucket"`
+	// Prefix is prepended to the keys of the objects.
+	Prefix string `yaml:"prefix"`
+}
+
+// AuditWebhookSink posts each batch of records as a JSON array.
+type AuditWebhookSink struct {
+	URL string `yaml:"url"`
+	// TokenFile holds a bearer token sent with the records.
+	TokenFile string `yaml:"tokenFile"`
+	// Timeout of each request, e.g. "5s". Defaults to 10s.
+	Timeout string `yaml:"timeout"`
+}
+
+// AuditRedaction selects the redactors applied to the audited prompts.
+type AuditRedaction struct {
+	// Redactors are registered redactors, e.g. "email", "phone", "credit-card" or "ipv4".
+	Redactors []string `yaml:"redactors"`
+	// Patterns are regular expressions whose matches are redacted.
+	Patterns []string `yaml:"patterns"`
+}
+
+// ChargebackConfig attributes the tokens and the GPU time of the requests to their consumers, namespaces
+// and models, exported as metrics and written in periodic reports. Chargeback is disabled unless enabled.
+type ChargebackConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// APIKeyHeader is the request header carrying the API key of the consumers without a JWT,
+	// defaults to the API key header of the access control.
+	APIKeyHeader string `yaml:"apiKeyHeader"`
+	// ReportInterval is the period covered by each report, e.g. "1h". Defaults to 1h.
+	ReportInterval string `yaml:"reportInterval"`
+	// Sink is where the reports are written, only the metrics are exported if it is not set.
+	Sink ChargebackSink `yaml:"sink"`
+}
+
+// ChargebackSink is where the chargeback reports are written as CSV, Type selects one of the sinks.
+type ChargebackSink struct {
+	// Type is "file" or "s3".
+	Type string             `yaml:"type"`
+	File ChargebackFileSink `yaml:"file"`
+	// S3 writes each report as an object, with the settings and the credentials of the S3 audit sink.
+	S3 AuditS3Sink `yaml:"s3"`
+}
+
+// ChargebackFileSink writes each report to a file of the directory.
+type ChargebackFileSink struct {
+	Directory string `yaml:"directory"`
+}
+
+// TenantConfig is a tenant of the router, selected by the host or the path of the requests. The first tenant
+// matching a request applies. The models requested through a tenant are resolved among the ModelRoutes of its
+// namespaces only, so that tenants may use the same model names for different backends. The requests matching no
+// tenant are resolved among the ModelRoutes of the namespaces of no tenant.
+type TenantConfig struct {
+	Name string `yaml:"name"`
+	// Hosts are the hosts of the requests of the tenant, e.g. "team-a.example.com" or "*.team-a.example.com".
+	Hosts []string `yaml:"hosts"`
+	// PathPrefix selects the requests of the tenant by path, e.g. "/team-a". It is removed from the path before
+	// the request is routed, so "/team-a/v1/chat/completions" is served as "/v1/chat/completions".
+	PathPrefix string `yaml:"pathPrefix"`
+	// Namespaces are the namespaces of the ModelRoutes of the tenant, searched in order. Defaults to the tenant name.
+	Namespaces []string `yaml:"namespaces"`
+	// Consumers restricts the tenant to the selected consumers, all consumers may use it if it is not set.
+	Consumers ConsumerSelect `yaml:"consumers"`
+	// Quota limits the requests and the tokens of the tenant on each router replica.
+	Quota TenantQuota `yaml:"quota"`
+}
+
+// TenantQuota limits the usage of a tenant, a zero limit is unlimited.
+type TenantQuota struct {
+	RequestsPerMinute int `yaml:"requestsPerMinute"`
+	// TokensPerMinute counts the input and the output tokens of the requests.
+	TokensPerMinute int `yaml:"tokensPerMinute"`
+}
+
 func ParseRouterConfig(configMapPath string) (*RouterConfiguration, error) {
 	data, err := os.ReadFile(configMapPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file %s: %w", configMapPath, err)
 	}
+	return UnmarshalRouterConfig(data)
+}
+
+// UnmarshalRouterConfig parses the content of a router configuration file.
+func UnmarshalRouterConfig(data []byte) (*RouterConfiguration, error) {
 	var routerConfig RouterConfiguration
 	if err := yaml.Unmarshal(data, &routerConfig); err != nil {
 		klog.Errorf("failed to Unmarshal routerConfiguration: %v", err)
@@ -102,6 +267,47 @@ func LoadSchedulerConfig(schedulerConfig *SchedulerConfiguration) (map[string]in
 	return scorePluginMap, filterPlugins, pluginsArgMap, nil
 }
 
+// LoadScoreTimeouts returns the default deadline of the score plugins and the deadlines overridden per plugin.
+// A zero default means the default of the scheduler should be used.
+func LoadScoreTimeouts(schedulerConfig *SchedulerConfiguration) (time.Duration, map[string]time.Duration, error) {
+	if schedulerConfig == nil {
+		return 0, nil, fmt.Errorf("schedulerConfig is nil")
+	}
+
+	var defaultTimeout time.Duration
+	if schedulerConfig.Plugins.Score.Timeout != "" {
+		timeout, err := parseTimeout(schedulerConfig.Plugins.Score.Timeout)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid score timeout: %v", err)
+		}
+		defaultTimeout = timeout
+	}
+
+	pluginTimeouts := make(map[string]time.Duration)
+	for _, plugin := range schedulerConfig.Plugins.Score.Enabled {
+		if plugin.Timeout == "" {
+			continue
+		}
+		timeout, err := parseTimeout(plugin.Timeout)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid timeout of score plugin %s: %v", plugin.Name, err)
+		}
+		pluginTimeouts[plugin.Name] = timeout
+	}
+	return defaultTimeout, pluginTimeouts, nil
+}
+
+func parseTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("timeout must be positive, got %s", value)
+	}
+	return timeout, nil
+}
+
 // handleRandomPluginConflicts checks if random plugin is configured with other score plugins
 // and removes the random plugin while logging a warning if conflicts are detected
 func handleRandomPluginConflicts(scorePluginMap map[string]int) map[string]int {
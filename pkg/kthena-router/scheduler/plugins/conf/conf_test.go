@@ -19,6 +19,7 @@ package conf
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadSchedulerConfig(t *testing.T) {
@@ -140,3 +141,32 @@ func TestHandleRandomPluginConflicts(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadScoreTimeouts(t *testing.T) {
+	config := &SchedulerConfiguration{
+		Plugins: Plugins{
+			Score: Score{
+				Timeout: "50ms",
+				Enabled: []PluginWithWeight{
+					{Name: "least-request", Weight: 1},
+					{Name: "kvcache-aware", Weight: 1, Timeout: "20ms"},
+				},
+			},
+		},
+	}
+	defaultTimeout, pluginTimeouts, err := LoadScoreTimeouts(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if defaultTimeout != 50*time.Millisecond {
+		t.Errorf("expected default timeout 50ms, got %v", defaultTimeout)
+	}
+	if len(pluginTimeouts) != 1 || pluginTimeouts["kvcache-aware"] != 20*time.Millisecond {
+		t.Errorf("unexpected plugin timeouts %v", pluginTimeouts)
+	}
+
+	config.Plugins.Score.Enabled[1].Timeout = "-1s"
+	if _, _, err := LoadScoreTimeouts(config); err == nil || !strings.Contains(err.Error(), "kvcache-aware") {
+		t.Errorf("expected an error for the invalid timeout, got %v", err)
+	}
+}
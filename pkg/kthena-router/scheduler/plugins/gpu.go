@@ -41,7 +41,7 @@ func (g *GPUCacheUsage) Name() string {
 func (g *GPUCacheUsage) Score(ctx *framework.Context, pods []*datastore.PodInfo) map[*datastore.PodInfo]int {
 	scoreResults := make(map[*datastore.PodInfo]int)
 	for _, info := range pods {
-		score := int((1.0 - info.GPUCacheUsage) * 100)
+		score := int((1.0 - info.MetricsOf(ctx.ServedModel).GPUCacheUsage) * 100)
 		scoreResults[info] = score
 	}
 
@@ -37,10 +37,13 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/tokenization"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/utils"
+	"istio.io/istio/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/yaml"
 )
@@ -50,8 +53,8 @@ const (
 	KVCacheAwarePluginName = "kvcache-aware"
 
 	// kvCacheKeyPrefix is the Redis key prefix for storing token block mappings
-	// Redis key format: "matrix:kv:block:{model}@{hash}"
-	// Example: "matrix:kv:block:deepseek-ai/DeepSeek-R1-Distill-Qwen-7B@12345678901234567890"
+	// Redis key format: "matrix:kv:block:{hash scheme}:{model}@{hash}"
+	// Example: "matrix:kv:block:sha256-v1:deepseek-ai/DeepSeek-R1-Distill-Qwen-7B@12345678901234567890"
 	kvCacheKeyPrefix = "matrix:kv:block:"
 
 	// defaultBlockSizeToHash is the default number of tokens per block for hashing
@@ -61,11 +64,56 @@ const (
 	// defaultMaxBlocksToMatch is the default maximum number of blocks to process for scoring
 	// Limits the number of blocks to prevent excessive Redis queries and processing time
 	defaultMaxBlocksToMatch = 128
+
+	// FallbackStrategyNone scores all pods 0 when the prompt cannot be tokenized,
+	// leaving the decision to the other score plugins
+	FallbackStrategyNone = "none"
+	// FallbackStrategyLeastRequest scores pods like the least-request plugin when the
+	// prompt cannot be tokenized
+	FallbackStrategyLeastRequest = "least-request"
 )
 
 type KVCacheAwareArgs struct {
 	BlockSizeToHash  int `yaml:"blockSizeToHash,omitempty"`
 	MaxBlocksToMatch int `yaml:"maxBlocksToMatch,omitempty"`
+	// HashAlgorithm is the algorithm token blocks are hashed with, sha256 or xxhash.
+	// It must match the KV_CACHE_HASH_ALGORITHM of the runtime.
+	HashAlgorithm string `yaml:"hashAlgorithm,omitempty"`
+	// FallbackStrategy is how pods are scored when the prompt cannot be tokenized,
+	// none or least-request. Defaults to none.
+	FallbackStrategy string `yaml:"fallbackStrategy,omitempty"`
+	// TokenizerService, if set, tokenizes the prompts with the kthena tokenizer server
+	// instead of the inference engines of the model.
+	TokenizerService *TokenizerServiceArgs `yaml:"tokenizerService,omitempty"`
+	// LocalTokenizers are the tokenizers loaded in process, keyed by model. They take
+	// precedence over the tokenizer service and the inference engines.
+	LocalTokenizers map[string]LocalTokenizerArgs `yaml:"localTokenizers,omitempty"`
+}
+
+type LocalTokenizerArgs struct {
+	// Type of the tokenizer file, sentencepiece or tiktoken
+	Type string `yaml:"type"`
+	// Path of the tokenizer file, e.g. a tokenizer.model mounted from a ConfigMap or a volume
+	Path string `yaml:"path"`
+	// Pattern splitting the text before BPE, tiktoken only. Defaults to the pattern of Llama 3.
+	Pattern string `yaml:"pattern,omitempty"`
+	// SpecialTokens and their ids, tiktoken only. Defaults to the special tokens of Llama 3.
+	SpecialTokens map[string]int `yaml:"specialTokens,omitempty"`
+	// ChatTemplate the chat messages are rendered with, llama3 or chatml, tiktoken only.
+	ChatTemplate string `yaml:"chatTemplate,omitempty"`
+}
+
+type TokenizerServiceArgs struct {
+	// Endpoint of the tokenizer server, host:port
+	Endpoint string `yaml:"endpoint"`
+	// Models tokenized by the tokenizer server, all models when empty
+	Models []string `yaml:"models,omitempty"`
+	// CacheSize is the number of tokenized prompts cached by the router. Defaults to 10000.
+	CacheSize int `yaml:"cacheSize,omitempty"`
+	// Timeout of a call to the tokenizer server, e.g. 50ms. Defaults to 50ms.
+	Timeout string `yaml:"timeout,omitempty"`
+	// DisableFallback disables tokenizing with the inference engines when the tokenizer server fails.
+	DisableFallback bool `yaml:"disableFallback,omitempty"`
 }
 
 type KVCacheAware struct {
@@ -75,27 +123,32 @@ type KVCacheAware struct {
 	redisClient      *redis.Client
 	processor        *TokenBlockProcessor
 	tokenizerManager *tokenization.TokenizerManager
+	// fallback scores the pods when the prompt cannot be tokenized, nil means all pods score 0
+	fallback      framework.ScorePlugin
+	healthTracker *tokenization.HealthTracker
 }
 
 var _ framework.ScorePlugin = &KVCacheAware{}
 
 type TokenBlockProcessor struct {
 	blockSize int
+	// hasher defaults to SHA-256 when nil
+	hasher BlockHasher
 }
 
 // KVCacheAwareBlock represents a token block for Redis storage
 type KVCacheAwareBlock struct {
 	ModelName string // Model name (e.g., "deepseek-ai/DeepSeek-R1-Distill-Qwen-7B")
-	ChunkHash uint64 // SHA-256 hash of the token block
+	ChunkHash uint64 // Hash of the token block
 }
 
 // String generates the Redis key for this token block
 // Format: "{prefix}{model}@{hash}"
-// Example: "matrix:kv:block:deepseek-ai/DeepSeek-R1-Distill-Qwen-7B@12345678901234567890"
+// Example: "matrix:kv:block:sha256-v1:deepseek-ai/DeepSeek-R1-Distill-Qwen-7B@12345678901234567890"
 //
 // The resulting Redis hash structure:
 //
-//	Key: "matrix:kv:block:deepseek-ai/DeepSeek-R1-Distill-Qwen-7B@12345678901234567890"
+//	Key: "matrix:kv:block:sha256-v1:deepseek-ai/DeepSeek-R1-Distill-Qwen-7B@12345678901234567890"
 //	Fields: {
 //	  "pod-name-1.namespace": "1703123456",
 //	  "pod-name-2.namespace": "1703123789"
@@ -121,22 +174,80 @@ func NewKVCacheAware(pluginArg runtime.RawExtension) *KVCacheAware {
 		maxBlocksToMatch = defaultMaxBlocksToMatch
 	}
 
+	hasher, err := NewBlockHasher(args.HashAlgorithm)
+	if err != nil {
+		klog.Warningf("KVCacheAware: %v, fallback to %s", err, defaultHashAlgorithm)
+		hasher, _ = NewBlockHasher(defaultHashAlgorithm)
+	}
+	keyPrefix := blockKeyPrefix(hasher)
+
+	var fallback framework.ScorePlugin
+	switch args.FallbackStrategy {
+	case "", FallbackStrategyNone:
+	case FallbackStrategyLeastRequest:
+		fallback = &LeastRequest{name: LeastRequestPluginName}
+	default:
+		klog.Warningf("KVCacheAware: unknown fallback strategy %q, fallback to %s", args.FallbackStrategy, FallbackStrategyNone)
+	}
+
 	managerConfig := tokenization.TokenizerManagerConfig{
 		EnableVLLMRemote: true,
 		EndpointTemplate: "http://%s:8000",
+		Service:          tokenizerServiceConfig(args.TokenizerService),
+		Local:            localTokenizerConfigs(args.LocalTokenizers),
 	}
 	manager := tokenization.NewTokenizerManager(managerConfig)
 
 	redisClient := utils.TryGetRedisClient()
+	if redisClient != nil {
+		go checkIndexScheme(context.Background(), redisClient, keyPrefix)
+	}
 
 	return &KVCacheAware{
 		name:             KVCacheAwarePluginName,
 		maxBlocksToMatch: maxBlocksToMatch,
-		keyPrefix:        kvCacheKeyPrefix,
+		keyPrefix:        keyPrefix,
 		redisClient:      redisClient,
-		processor:        &TokenBlockProcessor{blockSize: blockSizeToHash},
+		processor:        &TokenBlockProcessor{blockSize: blockSizeToHash, hasher: hasher},
 		tokenizerManager: manager,
+		fallback:         fallback,
+		healthTracker:    tokenization.DefaultHealthTracker,
+	}
+}
+
+func tokenizerServiceConfig(args *TokenizerServiceArgs) *tokenization.TokenizerServiceConfig {
+	if args == nil || args.Endpoint == "" {
+		return nil
+	}
+	config := &tokenization.TokenizerServiceConfig{
+		Endpoint:  args.Endpoint,
+		Models:    args.Models,
+		CacheSize: args.CacheSize,
+		Fallback:  !args.DisableFallback,
+	}
+	if args.Timeout != "" {
+		timeout, err := time.ParseDuration(args.Timeout)
+		if err != nil {
+			klog.Warningf("KVCacheAware: invalid tokenizer service timeout %q, fallback to the default: %v", args.Timeout, err)
+		} else {
+			config.Timeout = timeout
+		}
+	}
+	return config
+}
+
+func localTokenizerConfigs(args map[string]LocalTokenizerArgs) map[string]tokenization.LocalTokenizerConfig {
+	configs := make(map[string]tokenization.LocalTokenizerConfig, len(args))
+	for model, arg := range args {
+		configs[model] = tokenization.LocalTokenizerConfig{
+			Type:          arg.Type,
+			Path:          arg.Path,
+			Pattern:       arg.Pattern,
+			SpecialTokens: arg.SpecialTokens,
+			ChatTemplate:  arg.ChatTemplate,
+		}
 	}
+	return configs
 }
 
 func (t *KVCacheAware) Name() string {
@@ -145,7 +256,7 @@ func (t *KVCacheAware) Name() string {
 
 func (t *KVCacheAware) normalizeAndTokenizePrompt(ctx *framework.Context, pods []*datastore.PodInfo) ([]uint32, error) {
 	if t.tokenizerManager == nil {
-		return nil, fmt.Errorf("tokenizer manager not available")
+		return nil, tokenization.ErrTokenizerUnavailable{Model: ctx.Model}
 	}
 	return t.tokenizerManager.TokenizePrompt(ctx.Model, ctx.Prompt, pods)
 }
@@ -166,8 +277,14 @@ func (t *KVCacheAware) Score(ctx *framework.Context, pods []*datastore.PodInfo)
 	tokenizerDuration := time.Since(start)
 	klog.V(4).Infof("Tokenizer processing time: %v", tokenizerDuration)
 
-	if err != nil || len(tokens) == 0 {
-		return scoreResults
+	if err == nil && len(tokens) == 0 {
+		err = tokenization.ErrTokenizationFailed{Message: "prompt produced no tokens"}
+	}
+	if err != nil {
+		return t.scoreDegraded(ctx, pods, err)
+	}
+	if t.healthTracker != nil {
+		t.healthTracker.ReportSuccess(modelServersOf(pods))
 	}
 
 	blockHashes := t.processor.TokensToBlockHashes(tokens, t.maxBlocksToMatch)
@@ -175,7 +292,11 @@ func (t *KVCacheAware) Score(ctx *framework.Context, pods []*datastore.PodInfo)
 		return scoreResults
 	}
 
-	blockToPods, err := t.queryRedisForBlocks(blockHashes, ctx.Model)
+	parent := ctx.Ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	blockToPods, err := t.queryRedisForBlocks(parent, blockHashes, ctx.Model)
 	if err != nil {
 		return scoreResults
 	}
@@ -191,10 +312,41 @@ func (t *KVCacheAware) Score(ctx *framework.Context, pods []*datastore.PodInfo)
 	return scoreResults
 }
 
+// scoreDegraded scores the pods when KV-cache affinity can not be computed because the prompt
+// failed to tokenize. The failure is surfaced through metrics and the ModelServer status, so
+// that operators notice the affinity is off instead of routing silently degrading.
+func (t *KVCacheAware) scoreDegraded(ctx *framework.Context, pods []*datastore.PodInfo, err error) map[*datastore.PodInfo]int {
+	reason := tokenization.FailureReason(err)
+	klog.V(2).Infof("KVCacheAware: failed to tokenize prompt of model %s, KV-cache affinity is degraded: %v", ctx.Model, err)
+	metrics.DefaultMetrics.RecordTokenizationFailure(ctx.Model, reason)
+	if t.healthTracker != nil {
+		t.healthTracker.ReportFailure(modelServersOf(pods), reason, err.Error())
+	}
+
+	if t.fallback != nil {
+		return t.fallback.Score(ctx, pods)
+	}
+	scoreResults := make(map[*datastore.PodInfo]int, len(pods))
+	for _, pod := range pods {
+		scoreResults[pod] = 0
+	}
+	return scoreResults
+}
+
+// modelServersOf returns the ModelServers the pods belong to.
+func modelServersOf(pods []*datastore.PodInfo) []types.NamespacedName {
+	modelServers := sets.New[types.NamespacedName]()
+	for _, pod := range pods {
+		modelServers.InsertAll(pod.GetModelServers().UnsortedList()...)
+	}
+	return modelServers.UnsortedList()
+}
+
 // queryRedisForBlocks queries Redis to find which pods have cached the given token block hashes
 // Returns a map from block hash to list of pod names that have cached that block
-func (t *KVCacheAware) queryRedisForBlocks(blockHashes []uint64, modelName string) (map[uint64][]string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// The query is canceled with the parent context, e.g. when the plugin misses its deadline.
+func (t *KVCacheAware) queryRedisForBlocks(parent context.Context, blockHashes []uint64, modelName string) (map[uint64][]string, error) {
+	ctx, cancel := context.WithTimeout(parent, 5*time.Second)
 	defer cancel()
 
 	blockToPods := make(map[uint64][]string)
@@ -343,14 +495,13 @@ func (tbp *TokenBlockProcessor) chunkTokens(tokens []uint32, maxBlocks int) [][]
 }
 
 func (tbp *TokenBlockProcessor) computeBlockHashes(chunks [][]uint32) []uint64 {
+	hasher := tbp.hasher
+	if hasher == nil {
+		hasher = sha256Hasher{}
+	}
 	hashes := make([]uint64, len(chunks))
 	for i, chunk := range chunks {
-		tokenInts := make([]int, len(chunk))
-		for j, token := range chunk {
-			tokenInts[j] = int(token)
-		}
-
-		hashes[i] = computeStandardizedHash(tokenInts)
+		hashes[i] = hasher.Hash(chunk)
 	}
 	return hashes
 }
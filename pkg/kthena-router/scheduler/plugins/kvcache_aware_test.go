@@ -24,6 +24,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
@@ -31,6 +33,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/yaml"
 )
 
@@ -2144,3 +2147,71 @@ func TestKVCacheAware_Integration_Comprehensive_Core(t *testing.T) {
 		})
 	}
 }
+
+func TestKVCacheAware_Score_Degraded(t *testing.T) {
+	ms := types.NamespacedName{Namespace: "test-namespace", Name: "test-ms"}
+	pods := createTestPods("pod1", "pod2", "pod3")
+	for i, pod := range pods {
+		pod.AddModelServer(ms)
+		pod.RequestWaitingNum = float64(i)
+	}
+	ctx := &framework.Context{
+		Model:  "test-model",
+		Prompt: common.ChatMessage{Text: "Hello world"},
+	}
+
+	tests := []struct {
+		name           string
+		pluginArg      string
+		expectedScores map[string]int
+	}{
+		{
+			name:      "No fallback scores all pods 0",
+			pluginArg: `{}`,
+			expectedScores: map[string]int{
+				"pod1": 0,
+				"pod2": 0,
+				"pod3": 0,
+			},
+		},
+		{
+			name:      "Least request fallback",
+			pluginArg: `{"fallbackStrategy": "least-request"}`,
+			expectedScores: map[string]int{
+				"pod1": 100,
+				"pod2": 50,
+				"pod3": 0,
+			},
+		},
+		{
+			name:      "Unknown fallback scores all pods 0",
+			pluginArg: `{"fallbackStrategy": "random"}`,
+			expectedScores: map[string]int{
+				"pod1": 0,
+				"pod2": 0,
+				"pod3": 0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := NewKVCacheAware(runtime.RawExtension{Raw: []byte(tt.pluginArg)})
+			plugin.tokenizerManager = nil
+			plugin.healthTracker = tokenization.NewHealthTracker()
+
+			result := plugin.Score(ctx, pods)
+
+			resultMap := make(map[string]int)
+			for pod, score := range result {
+				resultMap[pod.Pod.Name] = score
+			}
+			assert.Equal(t, tt.expectedScores, resultMap)
+
+			health, exists := plugin.healthTracker.Get(ms)
+			require.True(t, exists)
+			assert.True(t, health.Degraded)
+			assert.Equal(t, tokenization.ReasonTokenizerUnavailable, health.Reason)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cespare/xxhash"
+	"github.com/redis/go-redis/v9"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// HashAlgorithmSHA256 hashes token blocks with SHA-256 truncated to 63 bits
+	HashAlgorithmSHA256 = "sha256"
+	// HashAlgorithmXXHash hashes token blocks with XXH64 truncated to 63 bits
+	HashAlgorithmXXHash = "xxhash"
+
+	defaultHashAlgorithm = HashAlgorithmSHA256
+
+	// indexSchemeScanCount is the number of keys asked for by each SCAN when checking the hash scheme of the index
+	indexSchemeScanCount = 100
+)
+
+// BlockHasher computes the hash of a token block.
+// Hashes computed by the router must match the hashes written by the runtime, so
+// any change to the hashing of a BlockHasher must come with a new Tag.
+type BlockHasher interface {
+	// Tag identifies the algorithm and its version, it is embedded in the Redis key prefix
+	Tag() string
+	Hash(tokenIds []uint32) uint64
+}
+
+type sha256Hasher struct{}
+
+func (sha256Hasher) Tag() string { return "sha256-v1" }
+
+func (sha256Hasher) Hash(tokenIds []uint32) uint64 {
+	tokenInts := make([]int, len(tokenIds))
+	for i, token := range tokenIds {
+		tokenInts[i] = int(token)
+	}
+	return computeStandardizedHash(tokenInts)
+}
+
+type xxhashHasher struct{}
+
+func (xxhashHasher) Tag() string { return "xxhash-v1" }
+
+func (xxhashHasher) Hash(tokenIds []uint32) uint64 {
+	if len(tokenIds) == 0 {
+		return 0
+	}
+	tokenBytes := make([]byte, 4*len(tokenIds))
+	for i, token := range tokenIds {
+		binary.BigEndian.PutUint32(tokenBytes[4*i:], token)
+	}
+	return xxhash.Sum64(tokenBytes) & 0x7FFFFFFFFFFFFFFF
+}
+
+// schemeTagPattern matches the tags of all hash schemes, including the ones unknown to this router
+var schemeTagPattern = regexp.MustCompile(`^[a-z0-9]+-v[0-9]+$`)
+
+var blockHashers = map[string]BlockHasher{
+	HashAlgorithmSHA256: sha256Hasher{},
+	HashAlgorithmXXHash: xxhashHasher{},
+}
+
+// NewBlockHasher returns the BlockHasher of the algorithm, the default one if algorithm is empty.
+func NewBlockHasher(algorithm string) (BlockHasher, error) {
+	if algorithm == "" {
+		algorithm = defaultHashAlgorithm
+	}
+	hasher, ok := blockHashers[strings.ToLower(algorithm)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported hash algorithm %q", algorithm)
+	}
+	return hasher, nil
+}
+
+// blockKeyPrefix returns the Redis key prefix of the blocks hashed by the hasher.
+// Format: "matrix:kv:block:{tag}:"
+func blockKeyPrefix(hasher BlockHasher) string {
+	return kvCacheKeyPrefix + hasher.Tag() + ":"
+}
+
+// checkIndexScheme scans the block keys in Redis and warns when blocks are only indexed with
+// other hash schemes, which happens when the router and the runtime are configured differently
+// or while they are upgraded to a new scheme. Every query would miss in that case.
+func checkIndexScheme(ctx context.Context, client *redis.Client, keyPrefix string) {
+	schemes := make(map[string]struct{})
+	var cursor uint64
+	for {
+		// A page may be empty while the cursor is not done yet
+		keys, next, err := client.Scan(ctx, cursor, kvCacheKeyPrefix+"*", indexSchemeScanCount).Result()
+		if err != nil {
+			klog.Warningf("KVCacheAware: failed to check the hash scheme of the index: %v", err)
+			return
+		}
+		for _, key := range keys {
+			if strings.HasPrefix(key, keyPrefix) {
+				return
+			}
+			schemes[indexScheme(key)] = struct{}{}
+		}
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+	if len(schemes) == 0 {
+		return
+	}
+	found := make([]string, 0, len(schemes))
+	for scheme := range schemes {
+		found = append(found, scheme)
+	}
+	sort.Strings(found)
+	klog.Warningf("KVCacheAware: blocks are indexed with hash schemes %v, but the router queries %q; no cache hits are possible until the runtime uses the same hashAlgorithm",
+		found, strings.TrimSuffix(strings.TrimPrefix(keyPrefix, kvCacheKeyPrefix), ":"))
+}
+
+// indexScheme returns the tag of a block key, "legacy" for keys written before tags were introduced.
+func indexScheme(key string) string {
+	rest := strings.TrimPrefix(key, kvCacheKeyPrefix)
+	tag, _, found := strings.Cut(rest, ":")
+	if !found || !schemeTagPattern.MatchString(tag) {
+		return "legacy"
+	}
+	return tag
+}
@@ -0,0 +1,115 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/klog/v2"
+)
+
+func TestNewBlockHasher(t *testing.T) {
+	hasher, err := NewBlockHasher("")
+	require.NoError(t, err)
+	assert.Equal(t, "sha256-v1", hasher.Tag())
+	assert.Equal(t, "matrix:kv:block:sha256-v1:", blockKeyPrefix(hasher))
+
+	hasher, err = NewBlockHasher("XXHash")
+	require.NoError(t, err)
+	assert.Equal(t, "xxhash-v1", hasher.Tag())
+
+	_, err = NewBlockHasher("md5")
+	assert.Error(t, err)
+}
+
+func TestBlockHashers(t *testing.T) {
+	tokens := []uint32{1, 2, 3, 4}
+
+	// SHA-256 keeps the hashes of the untagged scheme
+	assert.Equal(t, computeStandardizedHash([]int{1, 2, 3, 4}), sha256Hasher{}.Hash(tokens))
+
+	for name, hasher := range blockHashers {
+		t.Run(name, func(t *testing.T) {
+			hash := hasher.Hash(tokens)
+			assert.Equal(t, hash, hasher.Hash(tokens))
+			assert.NotEqual(t, hash, hasher.Hash([]uint32{1, 2, 3, 5}))
+			assert.Zero(t, hash>>63, "hash must fit in 63 bits")
+			assert.Zero(t, hasher.Hash(nil))
+		})
+	}
+	assert.NotEqual(t, sha256Hasher{}.Hash(tokens), xxhashHasher{}.Hash(tokens))
+}
+
+func TestTokenBlockProcessor_Hasher(t *testing.T) {
+	tokens := []uint32{1, 2, 3, 4, 5, 6}
+	processor := &TokenBlockProcessor{blockSize: 3, hasher: xxhashHasher{}}
+	assert.Equal(t, []uint64{xxhashHasher{}.Hash(tokens[:3]), xxhashHasher{}.Hash(tokens[3:])}, processor.TokensToBlockHashes(tokens, 10))
+
+	// nil hasher defaults to SHA-256
+	processor = &TokenBlockProcessor{blockSize: 3}
+	assert.Equal(t, sha256Hasher{}.Hash(tokens[:3]), processor.TokensToBlockHashes(tokens, 10)[0])
+}
+
+func TestIndexScheme(t *testing.T) {
+	assert.Equal(t, "sha256-v1", indexScheme("matrix:kv:block:sha256-v1:deepseek-ai/DeepSeek-R1@123"))
+	assert.Equal(t, "sha256-v2", indexScheme("matrix:kv:block:sha256-v2:deepseek-ai/DeepSeek-R1@123"))
+	assert.Equal(t, "legacy", indexScheme("matrix:kv:block:deepseek-ai/DeepSeek-R1@123"))
+	assert.Equal(t, "legacy", indexScheme("matrix:kv:block:qwen:7b@123"))
+}
+
+func TestCheckIndexScheme(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	var buf bytes.Buffer
+	flags := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(flags)
+	require.NoError(t, flags.Set("logtostderr", "false"))
+	klog.SetOutput(&buf)
+	defer func() {
+		klog.SetOutput(nil)
+		_ = flags.Set("logtostderr", "true")
+	}()
+
+	keyPrefix := blockKeyPrefix(xxhashHasher{})
+
+	// Only legacy and SHA-256 blocks are indexed
+	mr.HSet("matrix:kv:block:model@1", "pod-1", "1")
+	mr.HSet("matrix:kv:block:sha256-v1:model@2", "pod-1", "1")
+	checkIndexScheme(context.Background(), client, keyPrefix)
+	klog.Flush()
+	assert.Contains(t, buf.String(), "[legacy sha256-v1]")
+
+	// Blocks of the scheme of the router are found beyond the first page of the scan
+	buf.Reset()
+	for i := 0; i < 2*indexSchemeScanCount; i++ {
+		mr.HSet(fmt.Sprintf("matrix:kv:block:sha256-v1:model@%d", 10+i), "pod-1", "1")
+	}
+	mr.HSet(keyPrefix+"model@3", "pod-1", "1")
+	checkIndexScheme(context.Background(), client, keyPrefix)
+	klog.Flush()
+	assert.NotContains(t, buf.String(), "KVCacheAware")
+}
@@ -74,18 +74,22 @@ func (l *LeastLatency) Score(ctx *framework.Context, pods []*datastore.PodInfo)
 	// Initialize with extreme values to ensure any valid latency updates them
 	// ctx.MaxToken is the max token that the model is allowed to generate in its response.
 	// Calculate min/max values for TTFT and TPOT in calculateMinMaxMetrics
-	minTTFT, maxTTFT, minTPOT, maxTPOT := calculateMinMaxMetrics(pods)
+	metrics := make([]datastore.ModelMetrics, len(pods))
+	for i, info := range pods {
+		metrics[i] = info.MetricsOf(ctx.ServedModel)
+	}
+	minTTFT, maxTTFT, minTPOT, maxTPOT := calculateMinMaxMetrics(metrics)
 	// 2. Second pass: Compute scores using linear normalization
 	// Note: If all pods have identical latency (max == min), all pods get MaxScore
-	for _, info := range pods {
+	for i, info := range pods {
 		scoreTTFT := MaxScore
 		scoreTPOT := MaxScore
 		// Only compute normalized score if there's variance in latency values
 		if maxTTFT > minTTFT {
-			scoreTTFT = MaxScore * (maxTTFT - info.TTFT) / (maxTTFT - minTTFT)
+			scoreTTFT = MaxScore * (maxTTFT - metrics[i].TTFT) / (maxTTFT - minTTFT)
 		}
 		if maxTPOT > minTPOT {
-			scoreTPOT = MaxScore * (maxTPOT - info.TPOT) / (maxTPOT - minTPOT)
+			scoreTPOT = MaxScore * (maxTPOT - metrics[i].TPOT) / (maxTPOT - minTPOT)
 		}
 		scoreResults[info] = int(scoreTTFT*l.TTFTTPOTWeightFactor + scoreTPOT*(1-l.TTFTTPOTWeightFactor))
 	}
@@ -93,13 +97,13 @@ func (l *LeastLatency) Score(ctx *framework.Context, pods []*datastore.PodInfo)
 	return scoreResults
 }
 
-func calculateMinMaxMetrics(pods []*datastore.PodInfo) (minTTFT, maxTTFT, minTPOT, maxTPOT float64) {
+func calculateMinMaxMetrics(metrics []datastore.ModelMetrics) (minTTFT, maxTTFT, minTPOT, maxTPOT float64) {
 	minTTFT = math.MaxFloat64
 	maxTTFT = 0.0
 	minTPOT = math.MaxFloat64
 	maxTPOT = 0.0
 
-	for _, info := range pods {
+	for _, info := range metrics {
 		// Skip pods with invalid values
 		if info.TTFT < 0 || info.TPOT < 0 {
 			continue
@@ -61,7 +61,7 @@ func (l *LeastRequest) Name() string {
 
 func (l *LeastRequest) Filter(ctx *framework.Context, pods []*datastore.PodInfo) []*datastore.PodInfo {
 	return slices.FilterInPlace(pods, func(info *datastore.PodInfo) bool {
-		return info.RequestWaitingNum < float64(l.maxWaitingRequest)
+		return info.MetricsOf(ctx.ServedModel).RequestWaitingNum < float64(l.maxWaitingRequest)
 	})
 }
 
@@ -76,7 +76,8 @@ func (l *LeastRequest) Score(ctx *framework.Context, pods []*datastore.PodInfo)
 	maxScore := 0.0
 	for _, info := range pods {
 		// The weight of waiting requests is 100. It's a magic number just to sinificantly lower the score of the pod when there are waiting reqs.
-		base := info.RequestRunningNum + 100*info.RequestWaitingNum
+		metrics := info.MetricsOf(ctx.ServedModel)
+		base := metrics.RequestRunningNum + 100*metrics.RequestWaitingNum
 		baseScores[info] = base
 		if base > maxScore {
 			maxScore = base
@@ -85,6 +86,11 @@ func (l *LeastRequest) Score(ctx *framework.Context, pods []*datastore.PodInfo)
 
 	// 2. Calculate the score for each pod as a percentage of the max base score
 	for _, info := range pods {
+		if maxScore == 0 {
+			// All pods are idle
+			scoreResults[info] = 100
+			continue
+		}
 		score := ((maxScore - baseScores[info]) / maxScore) * 100
 		scoreResults[info] = int(score)
 	}
@@ -0,0 +1,151 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"github.com/stretchr/testify/assert/yaml"
+	"istio.io/istio/pkg/slices"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+const (
+	LoraAdapterPluginName = "lora-adapter"
+
+	defaultMaxLoadedAdapters = 4
+	// loadScoreRange bounds the score of the pods the adapter would be loaded on,
+	// so that they always rank below the pods which have it loaded.
+	loadScoreRange = 90
+)
+
+var _ framework.FilterPlugin = &LoraAdapter{}
+var _ framework.ScorePlugin = &LoraAdapter{}
+var _ framework.PostScheduleHook = &LoraAdapter{}
+
+// LoraAdapter schedules the requests for a LoRA adapter on the pods having it loaded. When no pod has it,
+// the request goes to the least loaded pod with room for another adapter, on which the engine loads the
+// adapter on demand, e.g. with a vLLM LoRA resolver.
+type LoraAdapter struct {
+	name              string
+	maxLoadedAdapters int
+}
+
+type LoraAdapterArgs struct {
+	// MaxLoadedAdapters is the maximum number of LoRA adapters loaded on a pod at the same time,
+	// it should match the --max-loras of the engine.
+	MaxLoadedAdapters int `yaml:"maxLoadedAdapters,omitempty"`
+}
+
+func NewLoraAdapter(pluginArg runtime.RawExtension) *LoraAdapter {
+	args := LoraAdapterArgs{
+		MaxLoadedAdapters: defaultMaxLoadedAdapters,
+	}
+	if err := yaml.Unmarshal(pluginArg.Raw, &args); err != nil {
+		klog.Errorf("Unmarshal LoraAdapterArgs error, setting default value: %v", err)
+		args.MaxLoadedAdapters = defaultMaxLoadedAdapters
+	}
+	if args.MaxLoadedAdapters <= 0 {
+		args.MaxLoadedAdapters = defaultMaxLoadedAdapters
+	}
+
+	return &LoraAdapter{
+		name:              LoraAdapterPluginName,
+		maxLoadedAdapters: args.MaxLoadedAdapters,
+	}
+}
+
+func (l *LoraAdapter) Name() string {
+	return l.name
+}
+
+// Filter keeps the pods having the adapter loaded, or, if there are none, the pods which can load it.
+func (l *LoraAdapter) Filter(ctx *framework.Context, pods []*datastore.PodInfo) []*datastore.PodInfo {
+	if ctx.LoraAdapter == "" {
+		return pods
+	}
+	loaded := slices.Filter(pods, func(info *datastore.PodInfo) bool {
+		return info.Contains(ctx.LoraAdapter)
+	})
+	if len(loaded) > 0 {
+		return loaded
+	}
+	return slices.FilterInPlace(pods, func(info *datastore.PodInfo) bool {
+		return loadedAdapters(info) < l.maxLoadedAdapters
+	})
+}
+
+// Score ranks the pods having the adapter loaded first, then the pods with room for the adapter by their load.
+func (l *LoraAdapter) Score(ctx *framework.Context, pods []*datastore.PodInfo) map[*datastore.PodInfo]int {
+	scoreResults := make(map[*datastore.PodInfo]int, len(pods))
+	if ctx.LoraAdapter == "" || len(pods) == 0 {
+		return scoreResults
+	}
+
+	candidates := make([]*datastore.PodInfo, 0, len(pods))
+	maxLoad := 0.0
+	for _, info := range pods {
+		if info.Contains(ctx.LoraAdapter) {
+			scoreResults[info] = 100
+			continue
+		}
+		if loadedAdapters(info) >= l.maxLoadedAdapters {
+			scoreResults[info] = 0
+			continue
+		}
+		candidates = append(candidates, info)
+		maxLoad = max(maxLoad, podLoad(info))
+	}
+	for _, info := range candidates {
+		if maxLoad == 0 {
+			scoreResults[info] = loadScoreRange
+			continue
+		}
+		scoreResults[info] = int((maxLoad - podLoad(info)) / maxLoad * loadScoreRange)
+	}
+	return scoreResults
+}
+
+// PostSchedule records the adapter as loaded on the pod the request was sent to, so that the next requests
+// stick to it until the models of the pod are scraped again.
+func (l *LoraAdapter) PostSchedule(ctx *framework.Context, index int) {
+	if ctx.LoraAdapter == "" {
+		return
+	}
+	var pod *datastore.PodInfo
+	switch {
+	case ctx.BestPods != nil && index < len(ctx.BestPods):
+		pod = ctx.BestPods[index]
+	case index < len(ctx.DecodePods):
+		pod = ctx.DecodePods[index]
+	}
+	if pod != nil && !pod.Contains(ctx.LoraAdapter) {
+		pod.AddModel(ctx.LoraAdapter)
+	}
+}
+
+// loadedAdapters returns the number of adapters loaded on a pod, whose models are the base model and its adapters.
+func loadedAdapters(info *datastore.PodInfo) int {
+	return max(info.NumModels()-1, 0)
+}
+
+// podLoad weighs the waiting requests like the least-request plugin.
+func podLoad(info *datastore.PodInfo) float64 {
+	return info.RequestRunningNum + 100*info.RequestWaitingNum
+}
@@ -0,0 +1,108 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+func newLoraPod(name string, running float64, models ...string) *datastore.PodInfo {
+	info := &datastore.PodInfo{
+		Pod:               &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+		RequestRunningNum: running,
+	}
+	info.UpdateModels(models)
+	return info
+}
+
+func TestNewLoraAdapter(t *testing.T) {
+	plugin := NewLoraAdapter(runtime.RawExtension{Raw: []byte(`{"maxLoadedAdapters": 2}`)})
+	assert.Equal(t, LoraAdapterPluginName, plugin.Name())
+	assert.Equal(t, 2, plugin.maxLoadedAdapters)
+
+	plugin = NewLoraAdapter(runtime.RawExtension{})
+	assert.Equal(t, defaultMaxLoadedAdapters, plugin.maxLoadedAdapters)
+}
+
+func TestLoraAdapterFilter(t *testing.T) {
+	plugin := NewLoraAdapter(runtime.RawExtension{Raw: []byte(`{"maxLoadedAdapters": 2}`)})
+	resident := newLoraPod("resident", 5, "base", "sql", "chat")
+	room := newLoraPod("room", 0, "base", "chat")
+	full := newLoraPod("full", 0, "base", "chat", "code")
+
+	// Requests for the base model are not filtered
+	pods := plugin.Filter(&framework.Context{Model: "base"}, []*datastore.PodInfo{resident, room, full})
+	assert.Len(t, pods, 3)
+
+	// The pods having the adapter loaded are preferred
+	pods = plugin.Filter(&framework.Context{Model: "sql", LoraAdapter: "sql"}, []*datastore.PodInfo{resident, room, full})
+	assert.Equal(t, []*datastore.PodInfo{resident}, pods)
+
+	// Otherwise only the pods with room for the adapter are kept
+	pods = plugin.Filter(&framework.Context{Model: "code-v2", LoraAdapter: "code-v2"}, []*datastore.PodInfo{resident, room, full})
+	assert.Equal(t, []*datastore.PodInfo{room}, pods)
+
+	pods = plugin.Filter(&framework.Context{Model: "code-v2", LoraAdapter: "code-v2"}, []*datastore.PodInfo{resident, full})
+	assert.Empty(t, pods)
+}
+
+func TestLoraAdapterScore(t *testing.T) {
+	plugin := NewLoraAdapter(runtime.RawExtension{Raw: []byte(`{"maxLoadedAdapters": 2}`)})
+	resident := newLoraPod("resident", 5, "base", "sql")
+	busy := newLoraPod("busy", 10, "base")
+	idle := newLoraPod("idle", 0, "base")
+	full := newLoraPod("full", 0, "base", "chat", "code")
+	pods := []*datastore.PodInfo{resident, busy, idle, full}
+
+	scores := plugin.Score(&framework.Context{Model: "base"}, pods)
+	assert.Empty(t, scores)
+
+	scores = plugin.Score(&framework.Context{Model: "sql", LoraAdapter: "sql"}, pods)
+	assert.Equal(t, 100, scores[resident])
+	assert.Equal(t, loadScoreRange, scores[idle])
+	assert.Equal(t, 0, scores[busy])
+	assert.Equal(t, 0, scores[full])
+
+	// Without a resident pod, the least loaded pod with room wins
+	scores = plugin.Score(&framework.Context{Model: "chat", LoraAdapter: "chat"}, []*datastore.PodInfo{busy, idle})
+	assert.Greater(t, scores[idle], scores[busy])
+}
+
+func TestLoraAdapterPostSchedule(t *testing.T) {
+	plugin := NewLoraAdapter(runtime.RawExtension{})
+	pod := newLoraPod("pod", 0, "base")
+
+	plugin.PostSchedule(&framework.Context{Model: "base", BestPods: []*datastore.PodInfo{pod}}, 0)
+	assert.Equal(t, 1, pod.NumModels())
+
+	// The adapter is loaded on demand by the engine of the pod the request was sent to
+	plugin.PostSchedule(&framework.Context{Model: "sql", LoraAdapter: "sql", BestPods: []*datastore.PodInfo{pod}}, 0)
+	assert.True(t, pod.Contains("sql"))
+	assert.Equal(t, 2, pod.NumModels())
+
+	decode := newLoraPod("decode", 0, "base")
+	plugin.PostSchedule(&framework.Context{Model: "sql", LoraAdapter: "sql", DecodePods: []*datastore.PodInfo{decode}}, 0)
+	assert.True(t, decode.Contains("sql"))
+}
@@ -0,0 +1,284 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/stretchr/testify/assert/yaml"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+const (
+	PredictedLatencyPluginName = "predicted-latency"
+
+	defaultEWMAAlpha            = 0.3
+	defaultExpectedOutputTokens = 256
+	defaultStaleAfter           = 5 * time.Minute
+	// promptTokensOffset is the part of the time to first token which does not depend on the prompt, in prompt
+	// tokens, so that the prediction of short prompts is not scaled down to nothing.
+	promptTokensOffset = 256
+	// charactersPerToken estimates the prompt tokens from the prompt length, like the simple estimate tokenizer.
+	charactersPerToken = 4
+)
+
+var _ framework.ScorePlugin = &PredictedLatency{}
+var _ framework.ResponseObserver = &PredictedLatency{}
+
+// PredictedLatency scores the pods by the predicted completion time of the request. The router passes it the
+// responses of the pods, from which it keeps moving averages of the time to first token and of the decode speed
+// of each pod. The time to first token is scaled to the prompt of the request and multiplied by the requests
+// waiting on the pod, so that a slow pod with a short queue can rank below a fast pod with a longer one.
+// The pods without recent responses are predicted from the metrics scraped from their engine instead.
+type PredictedLatency struct {
+	name                 string
+	alpha                float64
+	expectedOutputTokens int
+	staleAfter           time.Duration
+
+	mutex     sync.Mutex
+	stats     map[latencyKey]*latencyStats
+	lastPrune time.Time
+}
+
+// latencyKey is a pod, and the model it served the requests with, as a pod may serve several models.
+type latencyKey struct {
+	pod   types.NamespacedName
+	model string
+}
+
+// latencyStats are the moving averages of the responses of a pod.
+type latencyStats struct {
+	// ttft is the time to first token in seconds, 0 until a streamed response was observed.
+	ttft float64
+	// promptTokens is the estimated prompt tokens of the requests ttft was observed on.
+	promptTokens float64
+	// tokensPerSecond is the decode speed, 0 until a response reported its usage.
+	tokensPerSecond float64
+	updated         time.Time
+}
+
+type PredictedLatencyArgs struct {
+	// Alpha is the weight of the last response in the moving averages, within (0, 1].
+	Alpha float64 `yaml:"alpha,omitempty"`
+	// ExpectedOutputTokens is the number of tokens a request is expected to generate.
+	ExpectedOutputTokens int `yaml:"expectedOutputTokens,omitempty"`
+	// StaleAfter is the duration, e.g. 5m, after which the averages of a pod without responses are forgotten.
+	StaleAfter string `yaml:"staleAfter,omitempty"`
+}
+
+func NewPredictedLatency(pluginArg runtime.RawExtension) *PredictedLatency {
+	args := PredictedLatencyArgs{
+		Alpha:                defaultEWMAAlpha,
+		ExpectedOutputTokens: defaultExpectedOutputTokens,
+	}
+	if err := yaml.Unmarshal(pluginArg.Raw, &args); err != nil {
+		klog.Errorf("Unmarshal PredictedLatencyArgs error, setting default value: %v", err)
+		args = PredictedLatencyArgs{Alpha: defaultEWMAAlpha, ExpectedOutputTokens: defaultExpectedOutputTokens}
+	}
+	if args.Alpha <= 0 || args.Alpha > 1 {
+		args.Alpha = defaultEWMAAlpha
+	}
+	if args.ExpectedOutputTokens <= 0 {
+		args.ExpectedOutputTokens = defaultExpectedOutputTokens
+	}
+	staleAfter := defaultStaleAfter
+	if args.StaleAfter != "" {
+		if d, err := time.ParseDuration(args.StaleAfter); err != nil || d <= 0 {
+			klog.Errorf("Invalid staleAfter %q of plugin %s, setting default value", args.StaleAfter, PredictedLatencyPluginName)
+		} else {
+			staleAfter = d
+		}
+	}
+
+	return &PredictedLatency{
+		name:                 PredictedLatencyPluginName,
+		alpha:                args.Alpha,
+		expectedOutputTokens: args.ExpectedOutputTokens,
+		staleAfter:           staleAfter,
+		stats:                make(map[latencyKey]*latencyStats),
+	}
+}
+
+func (p *PredictedLatency) Name() string {
+	return p.name
+}
+
+// Score ranks the pods from the shortest predicted completion time, scored 100, to the longest, scored 0.
+// The pods whose completion time cannot be predicted are scored like the average of the others.
+func (p *PredictedLatency) Score(ctx *framework.Context, pods []*datastore.PodInfo) map[*datastore.PodInfo]int {
+	scoreResults := make(map[*datastore.PodInfo]int, len(pods))
+	if len(pods) == 0 {
+		return scoreResults
+	}
+
+	promptTokens := estimatePromptTokens(ctx.Prompt)
+	now := time.Now()
+	predictions := make([]float64, len(pods))
+	minLatency, maxLatency := math.MaxFloat64, 0.0
+	total, predicted := 0.0, 0
+	p.mutex.Lock()
+	for i, pod := range pods {
+		latency, ok := p.predict(latencyKey{podName(pod), ctx.ServedModel}, pod.MetricsOf(ctx.ServedModel), promptTokens, now)
+		if !ok {
+			predictions[i] = -1
+			continue
+		}
+		predictions[i] = latency
+		minLatency = min(minLatency, latency)
+		maxLatency = max(maxLatency, latency)
+		total += latency
+		predicted++
+	}
+	p.mutex.Unlock()
+
+	for i, pod := range pods {
+		latency := predictions[i]
+		if latency < 0 {
+			if predicted == 0 {
+				scoreResults[pod] = MaxScore
+				continue
+			}
+			latency = total / float64(predicted)
+		}
+		if maxLatency == minLatency {
+			scoreResults[pod] = MaxScore
+			continue
+		}
+		scoreResults[pod] = int((maxLatency - latency) / (maxLatency - minLatency) * MaxScore)
+	}
+	return scoreResults
+}
+
+// predict returns the predicted completion time of the request on the pod in seconds, and whether it could be
+// predicted. The caller holds the mutex.
+func (p *PredictedLatency) predict(key latencyKey, metrics datastore.ModelMetrics, promptTokens float64, now time.Time) (float64, bool) {
+	var ttft, tokensPerSecond float64
+	if stats, ok := p.stats[key]; ok && now.Sub(stats.updated) < p.staleAfter {
+		ttft = stats.scaledTTFT(promptTokens)
+		tokensPerSecond = stats.tokensPerSecond
+	}
+	if ttft == 0 {
+		ttft = metrics.TTFT
+	}
+	if tokensPerSecond == 0 && metrics.TPOT > 0 {
+		tokensPerSecond = 1 / metrics.TPOT
+	}
+	if ttft == 0 && tokensPerSecond == 0 {
+		return 0, false
+	}
+
+	latency := (1 + metrics.RequestWaitingNum) * ttft
+	if tokensPerSecond > 0 {
+		latency += float64(p.expectedOutputTokens) / tokensPerSecond
+	}
+	return latency, true
+}
+
+// ObserveResponse updates the moving averages of the pod the response came from. The time to first token is
+// only known from the streamed responses, the decode speed of the others is counted from their duration less
+// the predicted time to first token.
+func (p *PredictedLatency) ObserveResponse(ctx *framework.Context, index int, response framework.Response) {
+	var pod *datastore.PodInfo
+	switch {
+	case ctx.BestPods != nil && index < len(ctx.BestPods):
+		pod = ctx.BestPods[index]
+	case index < len(ctx.DecodePods):
+		pod = ctx.DecodePods[index]
+	}
+	if pod == nil || pod.Pod == nil {
+		return
+	}
+
+	promptTokens := estimatePromptTokens(ctx.Prompt)
+	now := time.Now()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.prune(now)
+
+	key := latencyKey{podName(pod), ctx.ServedModel}
+	stats, ok := p.stats[key]
+	if !ok || now.Sub(stats.updated) >= p.staleAfter {
+		stats = &latencyStats{}
+		p.stats[key] = stats
+	}
+	stats.updated = now
+
+	ttft := response.TimeToFirstToken.Seconds()
+	if response.Stream && ttft > 0 {
+		stats.ttft = p.average(stats.ttft, ttft)
+		stats.promptTokens = p.average(stats.promptTokens, promptTokens)
+	} else if stats.ttft > 0 {
+		ttft = stats.scaledTTFT(promptTokens)
+	} else {
+		return
+	}
+	if decode := response.Duration.Seconds() - ttft; response.CompletionTokens > 1 && decode > 0 {
+		// The first token is generated with the prompt
+		stats.tokensPerSecond = p.average(stats.tokensPerSecond, float64(response.CompletionTokens-1)/decode)
+	}
+}
+
+// average adds the value to the moving average, which is the value itself when it is the first one.
+func (p *PredictedLatency) average(average, value float64) float64 {
+	if average == 0 {
+		return value
+	}
+	return p.alpha*value + (1-p.alpha)*average
+}
+
+// prune forgets the pods without responses for staleAfter, at most once per staleAfter. The caller holds the mutex.
+func (p *PredictedLatency) prune(now time.Time) {
+	if now.Sub(p.lastPrune) < p.staleAfter {
+		return
+	}
+	p.lastPrune = now
+	for key, stats := range p.stats {
+		if now.Sub(stats.updated) >= p.staleAfter {
+			delete(p.stats, key)
+		}
+	}
+}
+
+// scaledTTFT is the time to first token of a prompt of promptTokens, 0 when it is unknown.
+func (s *latencyStats) scaledTTFT(promptTokens float64) float64 {
+	return s.ttft * (promptTokens + promptTokensOffset) / (s.promptTokens + promptTokensOffset)
+}
+
+func podName(pod *datastore.PodInfo) types.NamespacedName {
+	if pod.Pod == nil {
+		return types.NamespacedName{}
+	}
+	return types.NamespacedName{Namespace: pod.Pod.Namespace, Name: pod.Pod.Name}
+}
+
+// estimatePromptTokens estimates the tokens of the prompt from its length.
+func estimatePromptTokens(prompt common.ChatMessage) float64 {
+	length := len(prompt.Text)
+	for _, message := range prompt.Messages {
+		length += len(message.Content)
+	}
+	return math.Ceil(float64(length) / charactersPerToken)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+func newLatencyPod(name string, waiting float64) *datastore.PodInfo {
+	return &datastore.PodInfo{
+		Pod:               &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}},
+		RequestWaitingNum: waiting,
+	}
+}
+
+// observe records a streamed response of the pod with the time to first token and the decode speed.
+func observe(plugin *PredictedLatency, pod *datastore.PodInfo, prompt common.ChatMessage, ttft time.Duration, tokensPerSecond float64) {
+	completionTokens := 101
+	plugin.ObserveResponse(&framework.Context{Prompt: prompt, BestPods: []*datastore.PodInfo{pod}}, 0, framework.Response{
+		Stream:           true,
+		TimeToFirstToken: ttft,
+		Duration:         ttft + time.Duration(float64(completionTokens-1)/tokensPerSecond*float64(time.Second)),
+		CompletionTokens: completionTokens,
+	})
+}
+
+func TestNewPredictedLatency(t *testing.T) {
+	plugin := NewPredictedLatency(runtime.RawExtension{Raw: []byte(`{"alpha": 0.5, "expectedOutputTokens": 100, "staleAfter": "1m"}`)})
+	assert.Equal(t, PredictedLatencyPluginName, plugin.Name())
+	assert.Equal(t, 0.5, plugin.alpha)
+	assert.Equal(t, 100, plugin.expectedOutputTokens)
+	assert.Equal(t, time.Minute, plugin.staleAfter)
+
+	plugin = NewPredictedLatency(runtime.RawExtension{Raw: []byte(`{"alpha": 2, "staleAfter": "soon"}`)})
+	assert.Equal(t, defaultEWMAAlpha, plugin.alpha)
+	assert.Equal(t, defaultExpectedOutputTokens, plugin.expectedOutputTokens)
+	assert.Equal(t, defaultStaleAfter, plugin.staleAfter)
+}
+
+func TestPredictedLatencyObserveResponse(t *testing.T) {
+	plugin := NewPredictedLatency(runtime.RawExtension{Raw: []byte(`{"alpha": 0.5}`)})
+	pod := newLatencyPod("pod", 0)
+	prompt := common.ChatMessage{Text: "hello"}
+
+	observe(plugin, pod, prompt, time.Second, 50)
+	stats := plugin.stats[latencyKey{pod: podName(pod)}]
+	assert.InDelta(t, 1.0, stats.ttft, 1e-6)
+	assert.InDelta(t, 50.0, stats.tokensPerSecond, 1e-6)
+
+	observe(plugin, pod, prompt, 3*time.Second, 100)
+	assert.InDelta(t, 2.0, stats.ttft, 1e-6)
+	assert.InDelta(t, 75.0, stats.tokensPerSecond, 1e-6)
+
+	// The first byte of a response which is not streamed is its end, only its decode speed is learned
+	plugin.ObserveResponse(&framework.Context{Prompt: prompt, BestPods: []*datastore.PodInfo{pod}}, 0, framework.Response{
+		TimeToFirstToken: 4 * time.Second,
+		Duration:         4 * time.Second,
+		CompletionTokens: 41,
+	})
+	assert.InDelta(t, 2.0, stats.ttft, 1e-6)
+	assert.InDelta(t, 47.5, stats.tokensPerSecond, 1e-6)
+
+	// The responses of the decode pods are observed too
+	decode := newLatencyPod("decode", 0)
+	plugin.ObserveResponse(&framework.Context{Prompt: prompt, DecodePods: []*datastore.PodInfo{decode}}, 0, framework.Response{
+		Stream:           true,
+		TimeToFirstToken: time.Second,
+		Duration:         time.Second,
+	})
+	assert.InDelta(t, 1.0, plugin.stats[latencyKey{pod: podName(decode)}].ttft, 1e-6)
+}
+
+func TestPredictedLatencyScore(t *testing.T) {
+	plugin := NewPredictedLatency(runtime.RawExtension{})
+	prompt := common.ChatMessage{Text: "hello"}
+	fast := newLatencyPod("fast", 4)
+	slow := newLatencyPod("slow", 0)
+	observe(plugin, fast, prompt, 100*time.Millisecond, 100)
+	observe(plugin, slow, prompt, 2*time.Second, 20)
+
+	// The fast pod wins despite its queue
+	scores := plugin.Score(&framework.Context{Prompt: prompt}, []*datastore.PodInfo{fast, slow})
+	assert.Equal(t, 100, scores[fast])
+	assert.Equal(t, 0, scores[slow])
+
+	// A long queue outweighs the speed
+	fast.RequestWaitingNum = 200
+	scores = plugin.Score(&framework.Context{Prompt: prompt}, []*datastore.PodInfo{fast, slow})
+	assert.Equal(t, 0, scores[fast])
+	assert.Equal(t, 100, scores[slow])
+
+	// A pod without responses is predicted from its scraped metrics, or scored like the average
+	scraped := newLatencyPod("scraped", 0)
+	scraped.TTFT = 0.05
+	scraped.TPOT = 0.005
+	unknown := newLatencyPod("unknown", 0)
+	fast.RequestWaitingNum = 0
+	scores = plugin.Score(&framework.Context{Prompt: prompt}, []*datastore.PodInfo{fast, slow, scraped, unknown})
+	assert.Equal(t, 100, scores[scraped])
+	assert.Equal(t, 0, scores[slow])
+	assert.Greater(t, scores[fast], scores[unknown])
+	assert.Greater(t, scores[unknown], scores[slow])
+
+	// Without any prediction, all the pods are equal
+	scores = plugin.Score(&framework.Context{Prompt: prompt}, []*datastore.PodInfo{unknown, newLatencyPod("other", 0)})
+	assert.Equal(t, 100, scores[unknown])
+}
+
+func TestPredictedLatencyScalesWithPrompt(t *testing.T) {
+	plugin := NewPredictedLatency(runtime.RawExtension{Raw: []byte(`{"expectedOutputTokens": 1}`)})
+	short := common.ChatMessage{Text: "hello"}
+	long := common.ChatMessage{Messages: []common.Message{{Role: "user", Content: strings.Repeat("a", 40000)}}}
+	pod := newLatencyPod("pod", 0)
+	observe(plugin, pod, short, time.Second, 100)
+
+	now := time.Now()
+	shortLatency, ok := plugin.predict(latencyKey{pod: podName(pod)}, pod.MetricsOf(""), estimatePromptTokens(short), now)
+	assert.True(t, ok)
+	longLatency, ok := plugin.predict(latencyKey{pod: podName(pod)}, pod.MetricsOf(""), estimatePromptTokens(long), now)
+	assert.True(t, ok)
+	assert.Greater(t, longLatency, 10*shortLatency)
+}
+
+func TestPredictedLatencyStale(t *testing.T) {
+	plugin := NewPredictedLatency(runtime.RawExtension{Raw: []byte(`{"staleAfter": "1m"}`)})
+	prompt := common.ChatMessage{Text: "hello"}
+	stale := newLatencyPod("stale", 0)
+	observe(plugin, stale, prompt, time.Second, 10)
+	plugin.stats[latencyKey{pod: podName(stale)}].updated = time.Now().Add(-2 * time.Minute)
+
+	_, ok := plugin.predict(latencyKey{pod: podName(stale)}, stale.MetricsOf(""), 1, time.Now())
+	assert.False(t, ok)
+
+	// The stale pods are forgotten with the next response
+	plugin.lastPrune = time.Time{}
+	observe(plugin, newLatencyPod("fresh", 0), prompt, time.Second, 10)
+	assert.NotContains(t, plugin.stats, latencyKey{pod: podName(stale)})
+	assert.Len(t, plugin.stats, 1)
+}
@@ -50,3 +50,22 @@ func (e ErrHTTPRequest) Error() string {
 	}
 	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
 }
+
+type ErrTokenizerUnavailable struct {
+	Model string
+}
+
+func (e ErrTokenizerUnavailable) Error() string {
+	return fmt.Sprintf("no tokenizer available for model %s", e.Model)
+}
+
+type ErrChatTemplateUnsupported struct {
+	ChatTemplate string
+}
+
+func (e ErrChatTemplateUnsupported) Error() string {
+	if e.ChatTemplate == "" {
+		return "chat messages can not be tokenized without a chat template"
+	}
+	return fmt.Sprintf("unsupported chat template %s", e.ChatTemplate)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenization
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+)
+
+const (
+	// ReasonTokenizerUnavailable means no tokenizer could be created for the model.
+	ReasonTokenizerUnavailable = "TokenizerUnavailable"
+	// ReasonTokenizationFailed means the tokenizer exists but failed to tokenize a prompt.
+	ReasonTokenizationFailed = "TokenizationFailed"
+	// ReasonTokenizerReady means prompts are tokenized successfully.
+	ReasonTokenizerReady = "TokenizerReady"
+
+	// HealthStateName is the name of the tokenization states in the persisted learned states.
+	HealthStateName = "tokenizer-health"
+)
+
+// FailureReason classifies a tokenization error into a condition reason.
+func FailureReason(err error) string {
+	var unavailable ErrTokenizerUnavailable
+	if errors.As(err, &unavailable) {
+		return ReasonTokenizerUnavailable
+	}
+	return ReasonTokenizationFailed
+}
+
+// TokenizerHealth is the tokenization state of a ModelServer as observed by the router.
+type TokenizerHealth struct {
+	Degraded bool
+	Reason   string
+	Message  string
+	// Since is the time the state last transitioned.
+	Since time.Time
+}
+
+// HealthTracker records whether prompts of each ModelServer can be tokenized, so that the router
+// can surface degraded KV-cache affinity through metrics and the ModelServer status.
+type HealthTracker struct {
+	mutex  sync.RWMutex
+	states map[types.NamespacedName]TokenizerHealth
+}
+
+func NewHealthTracker() *HealthTracker {
+	return &HealthTracker{
+		states: make(map[types.NamespacedName]TokenizerHealth),
+	}
+}
+
+// ReportFailure marks the given ModelServers as degraded.
+func (h *HealthTracker) ReportFailure(modelServers []types.NamespacedName, reason, message string) {
+	h.report(modelServers, TokenizerHealth{Degraded: true, Reason: reason, Message: message})
+}
+
+// ReportSuccess marks the given ModelServers as healthy.
+func (h *HealthTracker) ReportSuccess(modelServers []types.NamespacedName) {
+	h.report(modelServers, TokenizerHealth{Reason: ReasonTokenizerReady, Message: "prompts are tokenized for KV-cache aware scoring"})
+}
+
+func (h *HealthTracker) report(modelServers []types.NamespacedName, health TokenizerHealth) {
+	if len(modelServers) == 0 {
+		return
+	}
+
+	now := time.Now()
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for _, ms := range modelServers {
+		old, exists := h.states[ms]
+		if exists && old.Degraded == health.Degraded && old.Reason == health.Reason {
+			continue
+		}
+		health.Since = now
+		h.states[ms] = health
+		metrics.DefaultMetrics.SetKVCacheAffinityDegraded(ms.String(), health.Degraded)
+	}
+}
+
+// Get returns the tokenization state of a ModelServer, the second return value is false
+// if no prompt has been scored for it yet.
+func (h *HealthTracker) Get(ms types.NamespacedName) (TokenizerHealth, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	health, exists := h.states[ms]
+	return health, exists
+}
+
+// List returns the ModelServers with a recorded tokenization state.
+func (h *HealthTracker) List() []types.NamespacedName {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	modelServers := make([]types.NamespacedName, 0, len(h.states))
+	for ms := range h.states {
+		modelServers = append(modelServers, ms)
+	}
+	return modelServers
+}
+
+// Delete forgets a ModelServer, it is called when the ModelServer is deleted.
+func (h *HealthTracker) Delete(ms types.NamespacedName) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if _, exists := h.states[ms]; !exists {
+		return
+	}
+	delete(h.states, ms)
+	metrics.DefaultMetrics.DeleteKVCacheAffinityDegraded(ms.String())
+}
+
+// DefaultHealthTracker is shared by the scheduler plugins and the ModelServer status updater.
+var DefaultHealthTracker = NewHealthTracker()
+
+// healthState persists the tokenization states of the ModelServers, so that a restarted router does not
+// report a degraded ModelServer as healthy until a prompt of it fails again.
+type healthState struct {
+	tracker *HealthTracker
+	store   datastore.Store
+}
+
+// NewHealthState returns the learned state of the tracker, restored for the ModelServers of the store.
+func NewHealthState(tracker *HealthTracker, store datastore.Store) datastore.LearnedState {
+	return &healthState{tracker: tracker, store: store}
+}
+
+func (h *healthState) Name() string {
+	return HealthStateName
+}
+
+func (h *healthState) Save() ([]byte, error) {
+	h.tracker.mutex.RLock()
+	states := make(map[string]TokenizerHealth, len(h.tracker.states))
+	for ms, health := range h.tracker.states {
+		states[ms.String()] = health
+	}
+	h.tracker.mutex.RUnlock()
+	return json.Marshal(states)
+}
+
+func (h *healthState) Restore(data []byte) error {
+	var states map[string]TokenizerHealth
+	if err := json.Unmarshal(data, &states); err != nil {
+		return err
+	}
+	h.tracker.mutex.Lock()
+	defer h.tracker.mutex.Unlock()
+	for ms := range h.store.GetAllModelServers() {
+		health, ok := states[ms.String()]
+		if !ok {
+			continue
+		}
+		// The states observed since the start win over the restored ones
+		if _, exists := h.tracker.states[ms]; exists {
+			continue
+		}
+		h.tracker.states[ms] = health
+		metrics.DefaultMetrics.SetKVCacheAffinityDegraded(ms.String(), health.Degraded)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenization
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestFailureReason(t *testing.T) {
+	assert.Equal(t, ReasonTokenizerUnavailable, FailureReason(ErrTokenizerUnavailable{Model: "m"}))
+	assert.Equal(t, ReasonTokenizerUnavailable, FailureReason(fmt.Errorf("wrapped: %w", ErrTokenizerUnavailable{Model: "m"})))
+	assert.Equal(t, ReasonTokenizationFailed, FailureReason(fmt.Errorf("text tokenization failed")))
+}
+
+func TestHealthTracker(t *testing.T) {
+	tracker := NewHealthTracker()
+	ms := types.NamespacedName{Namespace: "default", Name: "ms"}
+
+	_, exists := tracker.Get(ms)
+	assert.False(t, exists)
+
+	tracker.ReportFailure([]types.NamespacedName{ms}, ReasonTokenizerUnavailable, "no tokenizer")
+	health, exists := tracker.Get(ms)
+	require.True(t, exists)
+	assert.True(t, health.Degraded)
+	assert.Equal(t, ReasonTokenizerUnavailable, health.Reason)
+	since := health.Since
+
+	// Repeated failures with the same reason keep the transition time
+	tracker.ReportFailure([]types.NamespacedName{ms}, ReasonTokenizerUnavailable, "no tokenizer")
+	health, _ = tracker.Get(ms)
+	assert.Equal(t, since, health.Since)
+
+	tracker.ReportSuccess([]types.NamespacedName{ms})
+	health, _ = tracker.Get(ms)
+	assert.False(t, health.Degraded)
+	assert.Equal(t, ReasonTokenizerReady, health.Reason)
+	assert.Equal(t, []types.NamespacedName{ms}, tracker.List())
+
+	tracker.Delete(ms)
+	assert.Empty(t, tracker.List())
+}
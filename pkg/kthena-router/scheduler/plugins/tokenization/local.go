@@ -0,0 +1,109 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenization
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// LocalTokenizerSentencePiece loads a SentencePiece BPE model, e.g. the tokenizer.model of Llama 2.
+	LocalTokenizerSentencePiece = "sentencepiece"
+	// LocalTokenizerTiktoken loads tiktoken BPE ranks, e.g. the tokenizer.model of Llama 3.
+	LocalTokenizerTiktoken = "tiktoken"
+
+	// ChatTemplateLlama3 renders chat messages like the chat template of Llama 3.
+	ChatTemplateLlama3 = "llama3"
+	// ChatTemplateChatML renders chat messages in the ChatML format, e.g. of Qwen.
+	ChatTemplateChatML = "chatml"
+)
+
+// LocalTokenizerConfig configures a tokenizer loaded in the router from a file, for the models
+// whose HuggingFace tokenizer.json is absent.
+type LocalTokenizerConfig struct {
+	// Type of the tokenizer file, sentencepiece or tiktoken
+	Type string
+	// Path of the tokenizer file
+	Path string
+	// Pattern splitting the text before BPE, tiktoken only. Defaults to the pattern of Llama 3.
+	Pattern string
+	// SpecialTokens and their ids, tiktoken only. Defaults to the special tokens of Llama 3.
+	SpecialTokens map[string]int
+	// ChatTemplate the chat messages are rendered with, llama3 or chatml, tiktoken only.
+	// Chat messages can not be tokenized without it.
+	ChatTemplate string
+}
+
+// localTokenizers loads the local tokenizers of the models on first use.
+type localTokenizers struct {
+	configs map[string]LocalTokenizerConfig
+
+	mutex      sync.Mutex
+	tokenizers map[string]ExtendedTokenizer
+	errs       map[string]error
+}
+
+func newLocalTokenizers(configs map[string]LocalTokenizerConfig) *localTokenizers {
+	return &localTokenizers{
+		configs:    configs,
+		tokenizers: make(map[string]ExtendedTokenizer),
+		errs:       make(map[string]error),
+	}
+}
+
+// get returns the local tokenizer of a model, or nil if it has none. A tokenizer
+// failing to load is not retried, as its file is not expected to change.
+func (l *localTokenizers) get(model string) (ExtendedTokenizer, error) {
+	config, ok := l.configs[model]
+	if !ok {
+		return nil, nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if tokenizer, ok := l.tokenizers[model]; ok {
+		return tokenizer, nil
+	}
+	if err, ok := l.errs[model]; ok {
+		return nil, err
+	}
+
+	tokenizer, err := newLocalTokenizer(config)
+	if err != nil {
+		err = fmt.Errorf("failed to load %s tokenizer of model %s from %s: %w", config.Type, model, config.Path, err)
+		klog.Error(err)
+		l.errs[model] = err
+		return nil, err
+	}
+	klog.Infof("Loaded %s tokenizer of model %s from %s", config.Type, model, config.Path)
+	l.tokenizers[model] = tokenizer
+	return tokenizer, nil
+}
+
+func newLocalTokenizer(config LocalTokenizerConfig) (ExtendedTokenizer, error) {
+	switch config.Type {
+	case LocalTokenizerSentencePiece:
+		return newSentencePieceTokenizer(config)
+	case LocalTokenizerTiktoken:
+		return newTiktokenTokenizer(config)
+	default:
+		return nil, ErrInvalidConfig{Message: fmt.Sprintf("unknown tokenizer type %q", config.Type)}
+	}
+}
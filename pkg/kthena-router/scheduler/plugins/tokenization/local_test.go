@@ -0,0 +1,189 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenization
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+)
+
+// writeTiktokenRanks writes BPE ranks with all the bytes and the merges "he", "ll" and "hell".
+func writeTiktokenRanks(t *testing.T) string {
+	var sb strings.Builder
+	for b := 0; b < 256; b++ {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(b)}), b)
+	}
+	for i, merge := range []string{"he", "ll", "hell"} {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(merge)), 256+i)
+	}
+	path := filepath.Join(t.TempDir(), "tokenizer.model")
+	require.NoError(t, os.WriteFile(path, []byte(sb.String()), 0o600))
+	return path
+}
+
+type spTestPiece struct {
+	piece     string
+	score     float32
+	pieceType uint64
+}
+
+// writeSentencePieceModel writes a SentencePiece model with the given pieces.
+func writeSentencePieceModel(t *testing.T, modelType uint64, pieces []spTestPiece) string {
+	var model []byte
+	for _, p := range pieces {
+		var piece []byte
+		piece = protowire.AppendTag(piece, spPieceField, protowire.BytesType)
+		piece = protowire.AppendString(piece, p.piece)
+		piece = protowire.AppendTag(piece, spScoreField, protowire.Fixed32Type)
+		piece = protowire.AppendFixed32(piece, math.Float32bits(p.score))
+		piece = protowire.AppendTag(piece, spTypeField, protowire.VarintType)
+		piece = protowire.AppendVarint(piece, p.pieceType)
+		model = protowire.AppendTag(model, spModelPiecesField, protowire.BytesType)
+		model = protowire.AppendBytes(model, piece)
+	}
+	var trainer []byte
+	trainer = protowire.AppendTag(trainer, spTrainerModelTypeField, protowire.VarintType)
+	trainer = protowire.AppendVarint(trainer, modelType)
+	trainer = protowire.AppendTag(trainer, spTrainerByteFallbackField, protowire.VarintType)
+	trainer = protowire.AppendVarint(trainer, 1)
+	model = protowire.AppendTag(model, spModelTrainerField, protowire.BytesType)
+	model = protowire.AppendBytes(model, trainer)
+
+	path := filepath.Join(t.TempDir(), "tokenizer.model")
+	require.NoError(t, os.WriteFile(path, model, 0o600))
+	return path
+}
+
+var spTestPieces = []spTestPiece{
+	{piece: "<unk>", pieceType: 2},
+	{piece: "<s>", pieceType: 3},
+	{piece: "</s>", pieceType: 3},
+	{piece: "<0x21>", pieceType: spPieceTypeByte},
+	{piece: "▁", score: -1, pieceType: spPieceTypeNormal},
+	{piece: "h", score: -2, pieceType: spPieceTypeNormal},
+	{piece: "e", score: -3, pieceType: spPieceTypeNormal},
+	{piece: "l", score: -4, pieceType: spPieceTypeNormal},
+	{piece: "o", score: -5, pieceType: spPieceTypeNormal},
+	{piece: "▁h", score: -0.5, pieceType: spPieceTypeNormal},
+	{piece: "ll", score: -0.6, pieceType: spPieceTypeNormal},
+	{piece: "▁he", score: -0.7, pieceType: spPieceTypeNormal},
+	{piece: "llo", score: -0.8, pieceType: spPieceTypeNormal},
+	{piece: "▁hello", score: -0.9, pieceType: spPieceTypeNormal},
+}
+
+func TestSentencePieceTokenizer(t *testing.T) {
+	path := writeSentencePieceModel(t, spModelTypeBPE, spTestPieces)
+	tokenizer, err := newLocalTokenizer(LocalTokenizerConfig{Type: LocalTokenizerSentencePiece, Path: path})
+	require.NoError(t, err)
+
+	// "▁hello" is merged step by step, "!" is not in the vocabulary and falls back to its byte
+	result, err := tokenizer.TokenizeWithOptions(context.Background(), TokenizeInput{Type: CompletionInput, Text: "hello!", AddSpecialTokens: true})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 13, 3}, result.Tokens)
+
+	// Extra whitespaces are removed, each word gets a space prefix
+	result, err = tokenizer.TokenizeWithOptions(context.Background(), TokenizeInput{Type: CompletionInput, Text: "  hello   hello "})
+	require.NoError(t, err)
+	assert.Equal(t, []int{13, 13}, result.Tokens)
+
+	// Characters without a piece nor a byte piece are unknown
+	result, err = tokenizer.TokenizeWithOptions(context.Background(), TokenizeInput{Type: CompletionInput, Text: "x"})
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 0}, result.Tokens)
+
+	_, err = tokenizer.TokenizeWithOptions(context.Background(), TokenizeInput{Type: ChatInput, Messages: []common.Message{{Role: "user", Content: "hello"}}})
+	assert.ErrorAs(t, err, &ErrChatTemplateUnsupported{})
+
+	// Unigram models are not supported
+	path = writeSentencePieceModel(t, 1, spTestPieces)
+	_, err = newLocalTokenizer(LocalTokenizerConfig{Type: LocalTokenizerSentencePiece, Path: path})
+	assert.Error(t, err)
+}
+
+func TestTiktokenTokenizer(t *testing.T) {
+	path := writeTiktokenRanks(t)
+	tokenizer, err := newLocalTokenizer(LocalTokenizerConfig{Type: LocalTokenizerTiktoken, Path: path, ChatTemplate: ChatTemplateLlama3})
+	require.NoError(t, err)
+
+	result, err := tokenizer.TokenizeWithOptions(context.Background(), TokenizeInput{Type: CompletionInput, Text: "hello", AddSpecialTokens: true})
+	require.NoError(t, err)
+	assert.Equal(t, []int{128000, 258, 'o'}, result.Tokens)
+
+	// Special tokens in text prompts are plain text
+	result, err = tokenizer.TokenizeWithOptions(context.Background(), TokenizeInput{Type: CompletionInput, Text: "<|eot_id|>"})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Tokens, 128009)
+
+	result, err = tokenizer.TokenizeWithOptions(context.Background(), TokenizeInput{
+		Type:                ChatInput,
+		Messages:            []common.Message{{Role: "user", Content: " hello "}},
+		AddGenerationPrompt: true,
+	})
+	require.NoError(t, err)
+	expected := []int{128000, 128006, 'u', 's', 'e', 'r', 128007, '\n', '\n', 258, 'o', 128009, 128006}
+	expected = append(expected, []int{'a', 's', 's', 'i', 's', 't', 'a', 'n', 't', 128007, '\n', '\n'}...)
+	assert.Equal(t, expected, result.Tokens)
+
+	// The special tokens of the chat template must be configured
+	_, err = newLocalTokenizer(LocalTokenizerConfig{Type: LocalTokenizerTiktoken, Path: path, ChatTemplate: ChatTemplateChatML})
+	assert.Error(t, err)
+	tokenizer, err = newLocalTokenizer(LocalTokenizerConfig{
+		Type:          LocalTokenizerTiktoken,
+		Path:          path,
+		SpecialTokens: map[string]int{"<|im_start|>": 300, "<|im_end|>": 301},
+		ChatTemplate:  ChatTemplateChatML,
+	})
+	require.NoError(t, err)
+	result, err = tokenizer.TokenizeWithOptions(context.Background(), TokenizeInput{
+		Type:     ChatInput,
+		Messages: []common.Message{{Role: "user", Content: "hello"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{300, 'u', 's', 'e', 'r', '\n', 258, 'o', 301, '\n'}, result.Tokens)
+}
+
+func TestTokenizePromptWithLocalTokenizer(t *testing.T) {
+	manager := NewTokenizerManager(TokenizerManagerConfig{
+		EndpointTemplate: "http://%s:8000",
+		Local: map[string]LocalTokenizerConfig{
+			"llama3":  {Type: LocalTokenizerTiktoken, Path: writeTiktokenRanks(t)},
+			"missing": {Type: LocalTokenizerTiktoken, Path: filepath.Join(t.TempDir(), "missing")},
+		},
+	})
+
+	tokens, err := manager.TokenizePrompt("llama3", common.ChatMessage{Text: "hello"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{128000, 258, 'o'}, tokens)
+
+	// Without a chat template, and with a missing tokenizer file, it falls back to the pods
+	_, err = manager.TokenizePrompt("llama3", common.ChatMessage{Messages: []common.Message{{Role: "user", Content: "hello"}}}, nil)
+	assert.ErrorAs(t, err, &ErrTokenizerUnavailable{})
+	_, err = manager.TokenizePrompt("missing", common.ChatMessage{Text: "hello"}, nil)
+	assert.ErrorAs(t, err, &ErrTokenizerUnavailable{})
+}
@@ -0,0 +1,394 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenization
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers and enums of sentencepiece_model.proto
+const (
+	spModelPiecesField     = 1
+	spModelTrainerField    = 2
+	spModelNormalizerField = 3
+
+	spPieceField = 1
+	spScoreField = 2
+	spTypeField  = 3
+
+	spTrainerModelTypeField    = 3
+	spTrainerByteFallbackField = 35
+	spTrainerUnkIDField        = 40
+	spTrainerBosIDField        = 41
+
+	spNormalizerAddDummyPrefixField         = 3
+	spNormalizerRemoveExtraWhitespacesField = 4
+	spNormalizerEscapeWhitespacesField      = 5
+
+	spModelTypeBPE = 2
+
+	spPieceTypeNormal      = 1
+	spPieceTypeUserDefined = 4
+	spPieceTypeByte        = 6
+)
+
+// spaceSymbol is how SentencePiece escapes spaces.
+const spaceSymbol = "▁"
+
+// sentencePieceTokenizer tokenizes prompts in process with a SentencePiece BPE model, e.g. the
+// tokenizer.model of Llama 2 or Mistral. The precompiled normalization rules of the model are not
+// applied, which is exact for the models using the identity normalizer, like Llama 2.
+type sentencePieceTokenizer struct {
+	pieces       map[string]int
+	scores       []float32
+	userDefined  []string
+	byteFallback bool
+	byteIDs      [256]int
+	unkID        int
+	bosID        int
+
+	addDummyPrefix         bool
+	removeExtraWhitespaces bool
+	escapeWhitespaces      bool
+}
+
+func newSentencePieceTokenizer(config LocalTokenizerConfig) (*sentencePieceTokenizer, error) {
+	data, err := os.ReadFile(config.Path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := parseSentencePieceModel(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SentencePiece model %s: %w", config.Path, err)
+	}
+	if config.ChatTemplate != "" {
+		return nil, ErrInvalidConfig{Message: "chat templates are not supported by SentencePiece tokenizers"}
+	}
+	return t, nil
+}
+
+func parseSentencePieceModel(data []byte) (*sentencePieceTokenizer, error) {
+	t := &sentencePieceTokenizer{
+		pieces:                 make(map[string]int),
+		unkID:                  0,
+		bosID:                  1,
+		addDummyPrefix:         true,
+		removeExtraWhitespaces: true,
+		escapeWhitespaces:      true,
+	}
+	for i := range t.byteIDs {
+		t.byteIDs[i] = -1
+	}
+	modelType := uint64(1)
+
+	err := parseMessage(data, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error {
+		switch num {
+		case spModelPiecesField:
+			return t.parsePiece(value)
+		case spModelTrainerField:
+			return parseMessage(value, func(num protowire.Number, _ protowire.Type, _ []byte, varint uint64) error {
+				switch num {
+				case spTrainerModelTypeField:
+					modelType = varint
+				case spTrainerByteFallbackField:
+					t.byteFallback = varint != 0
+				case spTrainerUnkIDField:
+					t.unkID = int(int32(varint))
+				case spTrainerBosIDField:
+					t.bosID = int(int32(varint))
+				}
+				return nil
+			})
+		case spModelNormalizerField:
+			return parseMessage(value, func(num protowire.Number, _ protowire.Type, _ []byte, varint uint64) error {
+				switch num {
+				case spNormalizerAddDummyPrefixField:
+					t.addDummyPrefix = varint != 0
+				case spNormalizerRemoveExtraWhitespacesField:
+					t.removeExtraWhitespaces = varint != 0
+				case spNormalizerEscapeWhitespacesField:
+					t.escapeWhitespaces = varint != 0
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(t.scores) == 0 {
+		return nil, fmt.Errorf("no pieces found")
+	}
+	if modelType != spModelTypeBPE {
+		return nil, fmt.Errorf("only BPE models are supported, got model type %d", modelType)
+	}
+	return t, nil
+}
+
+func (t *sentencePieceTokenizer) parsePiece(data []byte) error {
+	var (
+		piece     string
+		score     float32
+		pieceType uint64 = spPieceTypeNormal
+	)
+	err := parseMessage(data, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error {
+		switch num {
+		case spPieceField:
+			piece = string(value)
+		case spScoreField:
+			if typ != protowire.Fixed32Type {
+				return fmt.Errorf("unexpected wire type %d of score", typ)
+			}
+			score = math.Float32frombits(uint32(varint))
+		case spTypeField:
+			pieceType = varint
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	id := len(t.scores)
+	t.scores = append(t.scores, score)
+	switch pieceType {
+	case spPieceTypeNormal:
+		t.pieces[piece] = id
+	case spPieceTypeUserDefined:
+		t.pieces[piece] = id
+		t.userDefined = append(t.userDefined, piece)
+	case spPieceTypeByte:
+		// Byte pieces look like <0x0A>
+		var b byte
+		if _, err := fmt.Sscanf(piece, "<0x%02X>", &b); err == nil {
+			t.byteIDs[b] = id
+		}
+	}
+	return nil
+}
+
+// parseMessage calls fn for each field of a protobuf message. value is set for length-delimited
+// fields, varint for varint and fixed32 fields.
+func parseMessage(data []byte, fn func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		var (
+			value  []byte
+			varint uint64
+		)
+		switch typ {
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(data)
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(data)
+			varint = uint64(v)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		if err := fn(num, typ, value, varint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *sentencePieceTokenizer) TokenizeInputText(text string) ([]byte, error) {
+	result, err := t.TokenizeWithOptions(context.Background(), TokenizeInput{
+		Type:             CompletionInput,
+		Text:             text,
+		AddSpecialTokens: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return intToByteArray(result.Tokens), nil
+}
+
+func (t *sentencePieceTokenizer) TokenizeWithOptions(_ context.Context, input TokenizeInput) (*TokenizeResult, error) {
+	if input.Type != CompletionInput {
+		return nil, ErrChatTemplateUnsupported{}
+	}
+	var tokens []int
+	if input.AddSpecialTokens && t.bosID >= 0 {
+		tokens = append(tokens, t.bosID)
+	}
+	tokens = append(tokens, t.encode(t.normalize(input.Text))...)
+	return &TokenizeResult{
+		Count:  len(tokens),
+		Tokens: tokens,
+	}, nil
+}
+
+func (t *sentencePieceTokenizer) normalize(text string) string {
+	if t.removeExtraWhitespaces {
+		text = strings.Join(strings.Fields(text), " ")
+	}
+	if text == "" {
+		return text
+	}
+	if t.addDummyPrefix {
+		text = " " + text
+	}
+	if t.escapeWhitespaces {
+		text = strings.ReplaceAll(text, " ", spaceSymbol)
+	}
+	return text
+}
+
+// spSymbol is a piece of the text being merged, linked to its neighbours.
+type spSymbol struct {
+	text       string
+	prev, next int
+	// frozen symbols, i.e. user defined pieces, are never merged
+	frozen bool
+}
+
+// spPair is a candidate merge of two adjacent symbols.
+type spPair struct {
+	left, right int
+	score       float32
+	// size of the symbols when the pair was queued, to skip stale pairs
+	size int
+}
+
+type spPairQueue []spPair
+
+func (q spPairQueue) Len() int { return len(q) }
+func (q spPairQueue) Less(i, j int) bool {
+	if q[i].score != q[j].score {
+		return q[i].score > q[j].score
+	}
+	return q[i].left < q[j].left
+}
+func (q spPairQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *spPairQueue) Push(x any)   { *q = append(*q, x.(spPair)) }
+func (q *spPairQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// encode merges the characters of the text greedily, the pair with the highest score first,
+// like the BPE model of SentencePiece does.
+func (t *sentencePieceTokenizer) encode(text string) []int {
+	symbols := t.split(text)
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	queue := &spPairQueue{}
+	tryPush := func(left, right int) {
+		if left < 0 || right < 0 || symbols[left].frozen || symbols[right].frozen {
+			return
+		}
+		merged := symbols[left].text + symbols[right].text
+		if id, ok := t.pieces[merged]; ok {
+			heap.Push(queue, spPair{left: left, right: right, score: t.scores[id], size: len(merged)})
+		}
+	}
+	for i := 1; i < len(symbols); i++ {
+		tryPush(i-1, i)
+	}
+
+	for queue.Len() > 0 {
+		pair := heap.Pop(queue).(spPair)
+		left, right := &symbols[pair.left], &symbols[pair.right]
+		// Skip the pairs whose symbols were merged with others since they were queued
+		if left.text == "" || right.text == "" || len(left.text)+len(right.text) != pair.size {
+			continue
+		}
+		left.text += right.text
+		right.text = ""
+		left.next = right.next
+		if right.next >= 0 {
+			symbols[right.next].prev = pair.left
+		}
+		tryPush(left.prev, pair.left)
+		tryPush(pair.left, left.next)
+	}
+
+	var tokens []int
+	for i := 0; i >= 0; i = symbols[i].next {
+		tokens = append(tokens, t.pieceIDs(symbols[i].text)...)
+	}
+	return tokens
+}
+
+// split splits the text into characters, keeping the user defined pieces whole.
+func (t *sentencePieceTokenizer) split(text string) []spSymbol {
+	var symbols []spSymbol
+	for len(text) > 0 {
+		size, frozen := 0, false
+		for _, piece := range t.userDefined {
+			if len(piece) > size && strings.HasPrefix(text, piece) {
+				size, frozen = len(piece), true
+			}
+		}
+		if size == 0 {
+			_, size = utf8.DecodeRuneInString(text)
+		}
+		symbols = append(symbols, spSymbol{text: text[:size], prev: len(symbols) - 1, next: len(symbols) + 1, frozen: frozen})
+		text = text[size:]
+	}
+	if len(symbols) > 0 {
+		symbols[len(symbols)-1].next = -1
+	}
+	return symbols
+}
+
+// pieceIDs returns the id of a piece, or of its bytes if it is not in the vocabulary.
+func (t *sentencePieceTokenizer) pieceIDs(piece string) []int {
+	if id, ok := t.pieces[piece]; ok {
+		return []int{id}
+	}
+	if !t.byteFallback {
+		return []int{t.unkID}
+	}
+	ids := make([]int, 0, len(piece))
+	for i := 0; i < len(piece); i++ {
+		id := t.byteIDs[piece[i]]
+		if id < 0 {
+			id = t.unkID
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+var _ ExtendedTokenizer = (*sentencePieceTokenizer)(nil)
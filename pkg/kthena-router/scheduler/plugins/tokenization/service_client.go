@@ -0,0 +1,181 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenization
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/cache"
+	tokenizerv1alpha1 "github.com/volcano-sh/kthena/pkg/tokenizer/api/v1alpha1"
+)
+
+const (
+	defaultServiceCacheSize = 10000
+	defaultServiceTimeout   = 50 * time.Millisecond
+)
+
+// TokenizerServiceConfig configures the tokenizer service the prompts are tokenized with,
+// instead of the inference engines of the model.
+type TokenizerServiceConfig struct {
+	// Endpoint of the tokenizer service, host:port
+	Endpoint string
+	// Models tokenized by the service, all models when empty
+	Models []string
+	// CacheSize is the number of tokenized prompts kept in memory
+	CacheSize int
+	// Timeout of a call to the service
+	Timeout time.Duration
+	// Fallback tokenizes the prompt with the inference engines of the model when the service fails
+	Fallback bool
+}
+
+// serviceTokenizer tokenizes prompts with the tokenizer service, caching the tokens of the
+// recent prompts since the same prompt, e.g. a system prompt, is often scored many times.
+type serviceTokenizer struct {
+	config TokenizerServiceConfig
+	conn   *grpc.ClientConn
+	client tokenizerv1alpha1.TokenizerClient
+	cache  cache.Cache[[sha256.Size]byte, []int]
+}
+
+func newServiceTokenizer(config TokenizerServiceConfig) (*serviceTokenizer, error) {
+	if config.Endpoint == "" {
+		return nil, ErrInvalidConfig{Message: "tokenizer service endpoint is required"}
+	}
+	if config.CacheSize <= 0 {
+		config.CacheSize = defaultServiceCacheSize
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultServiceTimeout
+	}
+	tokenCache, err := cache.NewLRUCache[[sha256.Size]byte, []int](config.CacheSize, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token cache: %w", err)
+	}
+	// The connection is established lazily and re-established by grpc when the service restarts
+	conn, err := grpc.NewClient(config.Endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tokenizer service client: %w", err)
+	}
+	return &serviceTokenizer{
+		config: config,
+		conn:   conn,
+		client: tokenizerv1alpha1.NewTokenizerClient(conn),
+		cache:  tokenCache,
+	}, nil
+}
+
+// serves returns whether the prompts of the model are tokenized by the service.
+func (t *serviceTokenizer) serves(model string) bool {
+	return len(t.config.Models) == 0 || slices.Contains(t.config.Models, model)
+}
+
+func (t *serviceTokenizer) tokenize(ctx context.Context, model string, input TokenizeInput) ([]int, error) {
+	key := cacheKey(model, input)
+	if tokens, ok := t.cache.Get(key); ok {
+		return tokens, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.config.Timeout)
+	defer cancel()
+	req := &tokenizerv1alpha1.TokenizeRequest{
+		Model:               model,
+		AddSpecialTokens:    input.AddSpecialTokens,
+		AddGenerationPrompt: input.AddGenerationPrompt,
+	}
+	switch input.Type {
+	case CompletionInput:
+		req.Text = input.Text
+	case ChatInput:
+		for _, message := range input.Messages {
+			req.Messages = append(req.Messages, &tokenizerv1alpha1.Message{Role: message.Role, Content: message.Content})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported input type: %s", input.Type)
+	}
+
+	resp, err := t.client.Tokenize(ctx, req)
+	if err != nil {
+		return nil, ErrTokenizationFailed{
+			Message: "tokenizer service request failed",
+			Cause:   err,
+		}
+	}
+	tokens := make([]int, len(resp.Tokens))
+	for i, token := range resp.Tokens {
+		tokens[i] = int(token)
+	}
+	t.cache.Add(key, tokens)
+	return tokens, nil
+}
+
+func (t *serviceTokenizer) Close() error {
+	return t.conn.Close()
+}
+
+// cacheKey hashes everything the tokens of a prompt depend on, with the length of each
+// field, so that different prompts can not be concatenated to the same key.
+func cacheKey(model string, input TokenizeInput) [sha256.Size]byte {
+	h := sha256.New()
+	writeString := func(s string) {
+		_ = binary.Write(h, binary.BigEndian, uint64(len(s)))
+		h.Write([]byte(s))
+	}
+	writeString(model)
+	writeString(string(input.Type))
+	_ = binary.Write(h, binary.BigEndian, input.AddSpecialTokens)
+	_ = binary.Write(h, binary.BigEndian, input.AddGenerationPrompt)
+	writeString(input.Text)
+	for _, message := range input.Messages {
+		writeString(message.Role)
+		writeString(message.Content)
+	}
+
+	var key [sha256.Size]byte
+	h.Sum(key[:0])
+	return key
+}
+
+// promptInput returns how a prompt is tokenized: text prompts with the special tokens,
+// chat messages with the generation prompt appended by the chat template.
+func promptInput(prompt common.ChatMessage) (TokenizeInput, error) {
+	if prompt.Text != "" {
+		return TokenizeInput{
+			Type:             CompletionInput,
+			Text:             prompt.Text,
+			AddSpecialTokens: true,
+		}, nil
+	}
+	if len(prompt.Messages) > 0 {
+		return TokenizeInput{
+			Type:                ChatInput,
+			Messages:            prompt.Messages,
+			AddSpecialTokens:    false,
+			AddGenerationPrompt: true,
+		}, nil
+	}
+	return TokenizeInput{}, fmt.Errorf("empty prompt provided")
+}
@@ -0,0 +1,138 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenization
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	tokenizerv1alpha1 "github.com/volcano-sh/kthena/pkg/tokenizer/api/v1alpha1"
+)
+
+type fakeTokenizerServer struct {
+	tokenizerv1alpha1.UnimplementedTokenizerServer
+	calls atomic.Int32
+	fail  bool
+}
+
+func (s *fakeTokenizerServer) Tokenize(_ context.Context, req *tokenizerv1alpha1.TokenizeRequest) (*tokenizerv1alpha1.TokenizeResponse, error) {
+	s.calls.Add(1)
+	if s.fail {
+		return nil, status.Error(codes.Unavailable, "unavailable")
+	}
+	tokens := []int32{int32(len(req.Text))}
+	for _, message := range req.Messages {
+		tokens = append(tokens, int32(len(message.Content)))
+	}
+	if req.AddGenerationPrompt {
+		tokens = append(tokens, -1)
+	}
+	return &tokenizerv1alpha1.TokenizeResponse{Tokens: tokens}, nil
+}
+
+func startFakeTokenizerServer(t *testing.T, fake *fakeTokenizerServer) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	tokenizerv1alpha1.RegisterTokenizerServer(server, fake)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestTokenizePromptWithService(t *testing.T) {
+	fake := &fakeTokenizerServer{}
+	endpoint := startFakeTokenizerServer(t, fake)
+	manager := NewTokenizerManager(TokenizerManagerConfig{
+		EndpointTemplate: "http://%s:8000",
+		Service: &TokenizerServiceConfig{
+			Endpoint: endpoint,
+			Models:   []string{"served"},
+		},
+	})
+	require.NotNil(t, manager.service)
+	defer manager.service.Close()
+
+	tokens, err := manager.TokenizePrompt("served", common.ChatMessage{Text: "hello"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{5}, tokens)
+
+	chat := common.ChatMessage{Messages: []common.Message{{Role: "user", Content: "hi"}}}
+	tokens, err = manager.TokenizePrompt("served", chat, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{0, 2, 0xFFFFFFFF}, tokens)
+	assert.Equal(t, int32(2), fake.calls.Load())
+
+	// The tokens of a prompt seen before are cached
+	_, err = manager.TokenizePrompt("served", common.ChatMessage{Text: "hello"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), fake.calls.Load())
+
+	// Models not served by the service are tokenized with their pods
+	_, err = manager.TokenizePrompt("other", common.ChatMessage{Text: "hello"}, nil)
+	assert.ErrorAs(t, err, &ErrTokenizerUnavailable{})
+	assert.Equal(t, int32(2), fake.calls.Load())
+}
+
+func TestTokenizePromptWithServiceFallback(t *testing.T) {
+	fake := &fakeTokenizerServer{fail: true}
+	endpoint := startFakeTokenizerServer(t, fake)
+
+	for _, fallback := range []bool{true, false} {
+		manager := NewTokenizerManager(TokenizerManagerConfig{
+			EndpointTemplate: "http://%s:8000",
+			Service:          &TokenizerServiceConfig{Endpoint: endpoint, Fallback: fallback},
+		})
+		require.NotNil(t, manager.service)
+
+		_, err := manager.TokenizePrompt("model", common.ChatMessage{Text: "hello"}, nil)
+		if fallback {
+			// There is no pod to fall back to
+			assert.ErrorAs(t, err, &ErrTokenizerUnavailable{})
+		} else {
+			assert.ErrorAs(t, err, &ErrTokenizationFailed{})
+		}
+		manager.service.Close()
+	}
+	assert.Equal(t, int32(2), fake.calls.Load())
+}
+
+func TestCacheKey(t *testing.T) {
+	text := TokenizeInput{Type: CompletionInput, Text: "ab", AddSpecialTokens: true}
+	assert.Equal(t, cacheKey("model", text), cacheKey("model", text))
+	assert.NotEqual(t, cacheKey("model", text), cacheKey("other", text))
+
+	noSpecialTokens := text
+	noSpecialTokens.AddSpecialTokens = false
+	assert.NotEqual(t, cacheKey("model", text), cacheKey("model", noSpecialTokens))
+
+	// Messages are not concatenated
+	chat1 := TokenizeInput{Type: ChatInput, Messages: []common.Message{{Role: "user", Content: "ab"}}}
+	chat2 := TokenizeInput{Type: ChatInput, Messages: []common.Message{{Role: "user", Content: "a"}, {Role: "b"}}}
+	assert.NotEqual(t, cacheKey("model", chat1), cacheKey("model", chat2))
+}
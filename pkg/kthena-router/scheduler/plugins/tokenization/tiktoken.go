@@ -0,0 +1,212 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenization
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// llama3Pattern splits text into the pieces encoded by BPE, it is shared by cl100k_base and Llama 3.
+const llama3Pattern = `(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+(?!\S)|\s+`
+
+const (
+	llama3BeginOfText    = "<|begin_of_text|>"
+	llama3StartHeaderID  = "<|start_header_id|>"
+	llama3EndHeaderID    = "<|end_header_id|>"
+	llama3EndOfTurn      = "<|eot_id|>"
+	chatMLStart          = "<|im_start|>"
+	chatMLEnd            = "<|im_end|>"
+	llama3SpecialTokenID = 128000
+)
+
+// llama3SpecialTokens are the special tokens of Llama 3 used by its chat template.
+var llama3SpecialTokens = map[string]int{
+	llama3BeginOfText:   llama3SpecialTokenID,
+	"<|end_of_text|>":   llama3SpecialTokenID + 1,
+	llama3StartHeaderID: llama3SpecialTokenID + 6,
+	llama3EndHeaderID:   llama3SpecialTokenID + 7,
+	llama3EndOfTurn:     llama3SpecialTokenID + 9,
+}
+
+// tiktokenTokenizer tokenizes prompts in process with tiktoken BPE ranks, e.g. the tokenizer.model of Llama 3.
+type tiktokenTokenizer struct {
+	encoding     *tiktoken.Tiktoken
+	bos          string
+	chatTemplate string
+}
+
+func newTiktokenTokenizer(config LocalTokenizerConfig) (*tiktokenTokenizer, error) {
+	ranks, err := loadTiktokenRanks(config.Path)
+	if err != nil {
+		return nil, err
+	}
+	pattern := config.Pattern
+	if pattern == "" {
+		pattern = llama3Pattern
+	}
+	specialTokens := config.SpecialTokens
+	if len(specialTokens) == 0 {
+		specialTokens = llama3SpecialTokens
+	}
+
+	bpe, err := tiktoken.NewCoreBPE(ranks, specialTokens, pattern)
+	if err != nil {
+		return nil, err
+	}
+	specialTokensSet := make(map[string]any, len(specialTokens))
+	for token := range specialTokens {
+		specialTokensSet[token] = nil
+	}
+	encoding := &tiktoken.Encoding{
+		Name:           config.Path,
+		PatStr:         pattern,
+		MergeableRanks: ranks,
+		SpecialTokens:  specialTokens,
+	}
+
+	t := &tiktokenTokenizer{
+		encoding:     tiktoken.NewTiktoken(bpe, encoding, specialTokensSet),
+		chatTemplate: config.ChatTemplate,
+	}
+	if _, ok := specialTokens[llama3BeginOfText]; ok {
+		t.bos = llama3BeginOfText
+	}
+	for _, token := range chatTemplateTokens(config.ChatTemplate) {
+		if _, ok := specialTokens[token]; !ok {
+			return nil, ErrInvalidConfig{Message: fmt.Sprintf("special token %s of chat template %s is not configured", token, config.ChatTemplate)}
+		}
+	}
+	return t, nil
+}
+
+// loadTiktokenRanks reads BPE ranks in the tiktoken format, a base64 encoded token and its rank per line.
+func loadTiktokenRanks(path string) (map[string]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		token, rank, ok := strings.Cut(text, " ")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected a token and its rank", path, line)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid token: %w", path, line, err)
+		}
+		value, err := strconv.Atoi(rank)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid rank: %w", path, line, err)
+		}
+		ranks[string(decoded)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ranks, nil
+}
+
+func (t *tiktokenTokenizer) TokenizeInputText(text string) ([]byte, error) {
+	result, err := t.TokenizeWithOptions(context.Background(), TokenizeInput{
+		Type:             CompletionInput,
+		Text:             text,
+		AddSpecialTokens: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return intToByteArray(result.Tokens), nil
+}
+
+func (t *tiktokenTokenizer) TokenizeWithOptions(_ context.Context, input TokenizeInput) (*TokenizeResult, error) {
+	var tokens []int
+	switch input.Type {
+	case CompletionInput:
+		// Special tokens in a text prompt are plain text
+		tokens = t.encoding.EncodeOrdinary(input.Text)
+		if input.AddSpecialTokens && t.bos != "" {
+			tokens = append(t.encoding.Encode(t.bos, []string{"all"}, nil), tokens...)
+		}
+	case ChatInput:
+		text, err := renderChatTemplate(t.chatTemplate, t.bos, input)
+		if err != nil {
+			return nil, err
+		}
+		tokens = t.encoding.Encode(text, []string{"all"}, nil)
+	default:
+		return nil, fmt.Errorf("unsupported input type: %s", input.Type)
+	}
+	return &TokenizeResult{
+		Count:  len(tokens),
+		Tokens: tokens,
+	}, nil
+}
+
+// chatTemplateTokens returns the special tokens a chat template needs.
+func chatTemplateTokens(chatTemplate string) []string {
+	switch chatTemplate {
+	case ChatTemplateLlama3:
+		return []string{llama3BeginOfText, llama3StartHeaderID, llama3EndHeaderID, llama3EndOfTurn}
+	case ChatTemplateChatML:
+		return []string{chatMLStart, chatMLEnd}
+	default:
+		return nil
+	}
+}
+
+// renderChatTemplate renders chat messages like the HuggingFace chat template of the model does.
+func renderChatTemplate(chatTemplate, bos string, input TokenizeInput) (string, error) {
+	var sb strings.Builder
+	switch chatTemplate {
+	case ChatTemplateLlama3:
+		sb.WriteString(bos)
+		for _, message := range input.Messages {
+			sb.WriteString(llama3StartHeaderID + message.Role + llama3EndHeaderID + "\n\n")
+			sb.WriteString(strings.TrimSpace(message.Content) + llama3EndOfTurn)
+		}
+		if input.AddGenerationPrompt {
+			sb.WriteString(llama3StartHeaderID + "assistant" + llama3EndHeaderID + "\n\n")
+		}
+	case ChatTemplateChatML:
+		for _, message := range input.Messages {
+			sb.WriteString(chatMLStart + message.Role + "\n" + message.Content + chatMLEnd + "\n")
+		}
+		if input.AddGenerationPrompt {
+			sb.WriteString(chatMLStart + "assistant\n")
+		}
+	default:
+		return "", ErrChatTemplateUnsupported{ChatTemplate: chatTemplate}
+	}
+	return sb.String(), nil
+}
+
+var _ ExtendedTokenizer = (*tiktokenTokenizer)(nil)
@@ -25,22 +25,41 @@ import (
 
 	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
 	"k8s.io/klog/v2"
 )
 
 type TokenizerManagerConfig struct {
 	EnableVLLMRemote bool
 	EndpointTemplate string
+	// Service, if set, tokenizes the prompts of its models with the tokenizer service
+	// instead of the inference engines of the model.
+	Service *TokenizerServiceConfig
+	// Local tokenizers of the models, loaded in process. They take precedence over the
+	// tokenizer service and the inference engines.
+	Local map[string]LocalTokenizerConfig
 }
 
 type TokenizerManager struct {
-	config TokenizerManagerConfig
+	config  TokenizerManagerConfig
+	service *serviceTokenizer
+	local   *localTokenizers
 }
 
 func NewTokenizerManager(config TokenizerManagerConfig) *TokenizerManager {
-	return &TokenizerManager{
+	manager := &TokenizerManager{
 		config: config,
+		local:  newLocalTokenizers(config.Local),
 	}
+	if config.Service != nil {
+		service, err := newServiceTokenizer(*config.Service)
+		if err != nil {
+			klog.Errorf("Failed to create tokenizer service client, tokenizing with the inference engines: %v", err)
+		} else {
+			manager.service = service
+		}
+	}
+	return manager
 }
 
 // GetTokenizer creates a tokenizer by randomly selecting from the provided pods
@@ -62,7 +81,7 @@ func (m *TokenizerManager) createTokenizerFromPods(model string, pods []*datasto
 		podIdx := (startIdx + i) % len(pods)
 		podInfo := pods[podIdx]
 
-		endpoint := fmt.Sprintf(m.config.EndpointTemplate, podInfo.Pod.Status.PodIP)
+		endpoint := fmt.Sprintf(m.config.EndpointTemplate, netutil.URLHost(podInfo.Pod.Status.PodIP))
 
 		config := RemoteTokenizerConfig{
 			Engine:             "vllm",
@@ -91,10 +110,66 @@ func (m *TokenizerManager) TokenizePrompt(
 	model string,
 	prompt common.ChatMessage,
 	pods []*datastore.PodInfo,
+) ([]uint32, error) {
+	// A local tokenizer failing to load has been logged already
+	if tokenizer, _ := m.local.get(model); tokenizer != nil {
+		tokens, err := tokenizeWith(tokenizer, prompt)
+		if err == nil {
+			return tokens, nil
+		}
+		klog.V(2).Infof("Failed to tokenize prompt of model %s with its local tokenizer, falling back: %v", model, err)
+	}
+	if m.service != nil && m.service.serves(model) {
+		tokens, err := m.tokenizeWithService(model, prompt)
+		if err == nil || !m.service.config.Fallback {
+			return tokens, err
+		}
+		klog.V(2).Infof("Failed to tokenize prompt of model %s with the tokenizer service, falling back to the inference engines: %v", model, err)
+	}
+	return m.tokenizeWithPods(model, prompt, pods)
+}
+
+func (m *TokenizerManager) tokenizeWithService(model string, prompt common.ChatMessage) ([]uint32, error) {
+	input, err := promptInput(prompt)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := m.service.tokenize(context.Background(), model, input)
+	if err != nil {
+		return nil, err
+	}
+	return toUint32(tokens), nil
+}
+
+func tokenizeWith(tokenizer ExtendedTokenizer, prompt common.ChatMessage) ([]uint32, error) {
+	input, err := promptInput(prompt)
+	if err != nil {
+		return nil, err
+	}
+	result, err := tokenizer.TokenizeWithOptions(context.Background(), input)
+	if err != nil {
+		return nil, err
+	}
+	return toUint32(result.Tokens), nil
+}
+
+func toUint32(tokens []int) []uint32 {
+	tokens32 := make([]uint32, len(tokens))
+	for i, token := range tokens {
+		tokens32[i] = uint32(token)
+	}
+	return tokens32
+}
+
+// tokenizeWithPods tokenizes a prompt with the inference engine of one of the pods
+func (m *TokenizerManager) tokenizeWithPods(
+	model string,
+	prompt common.ChatMessage,
+	pods []*datastore.PodInfo,
 ) ([]uint32, error) {
 	tokenizer := m.GetTokenizer(model, pods)
 	if tokenizer == nil {
-		return nil, fmt.Errorf("no tokenizer available for model %s", model)
+		return nil, ErrTokenizerUnavailable{Model: model}
 	}
 
 	// Handle text prompts directly
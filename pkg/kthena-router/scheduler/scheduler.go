@@ -25,4 +25,10 @@ import (
 type Scheduler interface {
 	Schedule(ctx *framework.Context, pods []*datastore.PodInfo) error
 	RunPostHooks(ctx *framework.Context, index int)
+	// ObserveResponse passes the response of the pod at index to the plugins learning from the responses.
+	ObserveResponse(ctx *framework.Context, index int, response framework.Response)
+	// Config returns the effective configuration of the scheduler plugins.
+	Config() Config
+	// SetPluginEnabled enables or disables a filter or score plugin at runtime.
+	SetPluginEnabled(name string, enabled bool) error
 }
@@ -17,14 +17,19 @@ limitations under the License.
 package scheduler
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
 	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
@@ -35,8 +40,34 @@ import (
 const (
 	// Get the top five scoring podinfo
 	topN = 5
+
+	// defaultScoreTimeout is the default deadline of each score plugin.
+	defaultScoreTimeout = 100 * time.Millisecond
+	// A score plugin missing its deadline degradedThreshold times in a row is considered degraded,
+	// and is skipped for degradedCooldown instead of slowing down every scheduling decision.
+	degradedThreshold = 3
+	degradedCooldown  = 10 * time.Second
+
+	// maxScore is the upper bound of the score of each plugin
+	maxScore = 100
+
+	// servedModelFilter records the pods left out as they do not serve the model in the scheduling decisions.
+	servedModelFilter = "served-model"
+	// acceleratorFilter records the pods left out as their accelerator is not required by the scheduling policy.
+	acceleratorFilter = "accelerator"
+
+	// defaultAcceleratorScoreWeight is the weight of the accelerator plugin when the scheduling policy has
+	// accelerator preferences but no weight for it.
+	defaultAcceleratorScoreWeight = 50
 )
 
+// defaultPolicyScoreWeights are the weights of the score plugins of a scheduling policy without weights.
+var defaultPolicyScoreWeights = map[string]int{
+	plugins.KVCacheAwarePluginName: 50,
+	plugins.LeastRequestPluginName: 30,
+	plugins.LeastLatencyPluginName: 20,
+}
+
 type SchedulerImpl struct {
 	store datastore.Store
 
@@ -44,11 +75,50 @@ type SchedulerImpl struct {
 	scorePlugins  []*scorePlugin
 
 	postScheduleHooks []framework.PostScheduleHook
+	responseObservers []framework.ResponseObserver
+
+	// disabled holds the names of the plugins disabled through the admin API.
+	disabled sync.Map
+	// rings holds the consistent hash rings of the model servers, map[types.NamespacedName]*hashRing.
+	rings sync.Map
 }
 
 type scorePlugin struct {
-	plugin framework.ScorePlugin
-	weight int
+	plugin  framework.ScorePlugin
+	weight  int
+	timeout time.Duration
+
+	// consecutiveTimeouts counts the deadlines missed in a row
+	consecutiveTimeouts atomic.Int32
+	// skipUntil is the unix time in nanoseconds until which the degraded plugin is skipped
+	skipUntil atomic.Int64
+}
+
+// degraded returns true if the plugin should be skipped at the given time.
+func (p *scorePlugin) degraded(now time.Time) bool {
+	return now.UnixNano() < p.skipUntil.Load()
+}
+
+// observe updates the degraded mode of the plugin with the outcome of a run.
+func (p *scorePlugin) observe(timedOut bool, now time.Time) {
+	if !timedOut {
+		p.consecutiveTimeouts.Store(0)
+		return
+	}
+	if p.consecutiveTimeouts.Add(1) >= degradedThreshold {
+		p.consecutiveTimeouts.Store(0)
+		p.skipUntil.Store(now.Add(degradedCooldown).UnixNano())
+		klog.Warningf("Score plugin %s missed its deadline of %v %d times in a row, skipping it for %v", p.plugin.Name(), p.timeout, degradedThreshold, degradedCooldown)
+	}
+}
+
+type scoreResult struct {
+	plugin   *scorePlugin
+	weight   int
+	scores   map[*datastore.PodInfo]int
+	hashes   []uint64
+	duration time.Duration
+	timedOut bool
 }
 
 type podInfoWithValue struct {
@@ -57,6 +127,24 @@ type podInfoWithValue struct {
 }
 
 func NewScheduler(store datastore.Store, routerConfig *conf.RouterConfiguration) Scheduler {
+	scheduler, err := newScheduler(store, routerConfig)
+	if err != nil {
+		klog.Fatalf("failed to Load Scheduler: %v", err)
+	}
+	return scheduler
+}
+
+// New creates a scheduler of the pods of the store with the plugins enabled by the router configuration,
+// or the default plugins without one. Unlike NewScheduler, it returns an error for an invalid configuration,
+// including the unknown plugins NewScheduler skips.
+func New(store datastore.Store, routerConfig *conf.RouterConfiguration) (Scheduler, error) {
+	if err := ValidateConfig(routerConfig); err != nil {
+		return nil, err
+	}
+	return newScheduler(store, routerConfig)
+}
+
+func newScheduler(store datastore.Store, routerConfig *conf.RouterConfiguration) (*SchedulerImpl, error) {
 	// For backward compatibility, use the default registry and ensure plugins are registered
 	registry := NewPluginRegistry()
 	registerDefaultPlugins(registry)
@@ -66,14 +154,18 @@ func NewScheduler(store datastore.Store, routerConfig *conf.RouterConfiguration)
 		"least-request": 1,
 		"least-latency": 1,
 		"prefix-cache":  1,
+		"lora-adapter":  1,
+		"accelerator":   1,
 	}
 	filterPluginMap := []string{
 		"least-request",
+		"lora-adapter",
 	}
 	pluginsArgMap := map[string]runtime.RawExtension{
 		"least-request": {Raw: []byte(`{"maxWaitingRequests": 10}`)},
 		"least-latency": {Raw: []byte(`{"TTFTTPOTWeightFactor": 0.5}`)},
 		"prefix-cache":  {Raw: []byte(`{"blockSizeToHash": 64, "maxBlocksToMatch": 128, "maxHashCacheSize": 50000}`)},
+		"lora-adapter":  {Raw: []byte(`{"maxLoadedAdapters": 4}`)},
 	}
 
 	var err error
@@ -83,28 +175,63 @@ func NewScheduler(store datastore.Store, routerConfig *conf.RouterConfiguration)
 	} else {
 		scorePluginMap, filterPluginMap, pluginsArgMap, err = conf.LoadSchedulerConfig(&routerConfig.Scheduler)
 		if err != nil {
-			klog.Fatalf("failed to Load Scheduler: %v", err)
+			return nil, err
 		}
 	}
 
+	scoreTimeout := defaultScoreTimeout
+	pluginTimeouts := map[string]time.Duration{}
+	if routerConfig != nil {
+		timeout, timeouts, err := conf.LoadScoreTimeouts(&routerConfig.Scheduler)
+		if err != nil {
+			return nil, err
+		}
+		if timeout > 0 {
+			scoreTimeout = timeout
+		}
+		pluginTimeouts = timeouts
+	}
+
 	prefixCache := plugins.NewPrefixCache(store, pluginsArgMap[plugins.PrefixCachePluginName])
-	return &SchedulerImpl{
-		store:         store,
-		filterPlugins: getFilterPlugins(registry, filterPluginMap, pluginsArgMap),
-		scorePlugins:  getScorePlugins(registry, prefixCache, scorePluginMap, pluginsArgMap),
-		postScheduleHooks: []framework.PostScheduleHook{
-			prefixCache,
-		},
+	scorePlugins := getScorePlugins(registry, prefixCache, scorePluginMap, pluginsArgMap)
+	for _, p := range scorePlugins {
+		p.timeout = scoreTimeout
+		if timeout, ok := pluginTimeouts[p.plugin.Name()]; ok {
+			p.timeout = timeout
+		}
 	}
+	filterPlugins := getFilterPlugins(registry, filterPluginMap, pluginsArgMap)
+	return &SchedulerImpl{
+		store:             store,
+		filterPlugins:     filterPlugins,
+		scorePlugins:      scorePlugins,
+		postScheduleHooks: getPostScheduleHooks(prefixCache, filterPlugins, scorePlugins),
+		responseObservers: getResponseObservers(filterPlugins, scorePlugins),
+	}, nil
 }
 
 func (s *SchedulerImpl) Schedule(ctx *framework.Context, pods []*datastore.PodInfo) error {
+	all := pods
+	pods, err := filterServedModel(ctx, pods)
+	if err != nil {
+		return err
+	}
+	if pods, err = filterAccelerators(ctx, pods); err != nil {
+		return err
+	}
 	// first filter out invalid pods that wonot be selected to loadbalance to.
-	pods, err := s.RunFilterPlugins(pods, ctx)
+	pods, err = s.RunFilterPlugins(pods, ctx)
 	if err != nil {
 		return err
 	}
 
+	// PD disaggregated model servers always score their pods, the decode and prefill pods are paired
+	if ctx.PDGroup == nil && consistentHashOf(ctx.SchedulingPolicy) {
+		klog.V(4).Info("Using consistent hash scheduling")
+		s.scheduleConsistentHash(ctx, all, pods)
+		return nil
+	}
+
 	if ctx.PDGroup != nil {
 		// Use optimized PDGroup scheduling with pre-categorized pods from store
 		klog.V(4).Info("Using optimized PD disaggregated scheduling")
@@ -118,11 +245,19 @@ func (s *SchedulerImpl) Schedule(ctx *framework.Context, pods []*datastore.PodIn
 		if len(decodePods) == 0 {
 			return fmt.Errorf("no decode pod found")
 		}
+		if decodePods, err = filterServedModel(ctx, decodePods); err != nil {
+			return err
+		}
+		if decodePods, err = filterAccelerators(ctx, decodePods); err != nil {
+			return err
+		}
 
 		klog.V(4).Info("Running score plugins for decode pod")
+		ctx.Decision.StartRound(framework.ScoreStageDecode)
 		scores := s.RunScorePlugins(decodePods, ctx)
 
-		topNDecodePods := TopNPodInfos(scores, topN)
+		topNDecodePods := TopNPodInfos(scores, topN, tieBreakOf(ctx.SchedulingPolicy))
+		ctx.Decision.FinishRound(scores, topNDecodePods)
 		ctx.DecodePods = topNDecodePods
 		prefillPods := make([]*datastore.PodInfo, len(topNDecodePods))
 
@@ -133,14 +268,19 @@ func (s *SchedulerImpl) Schedule(ctx *framework.Context, pods []*datastore.PodIn
 					Namespace: decodePod.Pod.Namespace,
 					Name:      decodePod.Pod.Name,
 				})
+			if err == nil {
+				selectedPods, err = filterAccelerators(ctx, selectedPods)
+			}
 			if err != nil || len(selectedPods) == 0 {
 				klog.V(4).InfoS("prefill pods for decode group not found", "decode instance", klog.KObj(decodePod.Pod), "error", err)
 				continue
 			}
 
 			klog.V(4).Info("Running score plugins for prefill pod")
+			ctx.Decision.StartRound(framework.ScoreStagePrefill)
 			scores = s.RunScorePlugins(selectedPods, ctx)
-			bestPrefillPod := TopNPodInfos(scores, 1)
+			bestPrefillPod := TopNPodInfos(scores, 1, tieBreakOf(ctx.SchedulingPolicy))
+			ctx.Decision.FinishRound(scores, bestPrefillPod)
 			prefillPods[i] = bestPrefillPod[0]
 		}
 		ctx.PrefillPods = prefillPods
@@ -148,18 +288,26 @@ func (s *SchedulerImpl) Schedule(ctx *framework.Context, pods []*datastore.PodIn
 	}
 
 	klog.V(4).Info("Running score plugins for PD aggregated pod")
+	ctx.Decision.StartRound(framework.ScoreStageAggregated)
 	scores := s.RunScorePlugins(pods, ctx)
-	ctx.BestPods = TopNPodInfos(scores, topN)
+	ctx.BestPods = TopNPodInfos(scores, topN, tieBreakOf(ctx.SchedulingPolicy))
+	ctx.Decision.FinishRound(scores, ctx.BestPods)
 
 	return nil
 }
 
 func (s *SchedulerImpl) RunFilterPlugins(pods []*datastore.PodInfo, ctx *framework.Context) ([]*datastore.PodInfo, error) {
 	for _, filterPlugin := range s.filterPlugins {
+		if s.pluginDisabled(filterPlugin.Name()) {
+			klog.V(4).Infof("FilterPlugin %s is disabled, skipping it", filterPlugin.Name())
+			continue
+		}
 		// Record filter plugin execution time
 		startTime := time.Now()
-		pods = filterPlugin.Filter(ctx, pods)
+		filtered := filterPlugin.Filter(ctx, pods)
 		duration := time.Since(startTime)
+		ctx.Decision.RecordFilter(filterPlugin.Name(), pods, filtered)
+		pods = filtered
 
 		// Use the MetricsRecorder from context to record plugin duration
 		if ctx.MetricsRecorder != nil {
@@ -174,29 +322,90 @@ func (s *SchedulerImpl) RunFilterPlugins(pods []*datastore.PodInfo, ctx *framewo
 	return pods, nil
 }
 
+// RunScorePlugins runs the score plugins concurrently and sums up their weighted scores.
+// The scores of the plugins missing their deadline are left out, so that a slow plugin can not
+// block the scheduling decision, and the plugins which keep missing it are skipped for a while.
+// With a scheduling policy, the weights of the policy are used and the scores of each plugin are
+// normalized across the pods before they are weighted.
 func (s *SchedulerImpl) RunScorePlugins(pods []*datastore.PodInfo, ctx *framework.Context) map[*datastore.PodInfo]int {
-	res := make(map[*datastore.PodInfo]int)
-	for _, scorePlugin := range s.scorePlugins {
-		// Record score plugin execution time
-		startTime := time.Now()
-		scores := scorePlugin.plugin.Score(ctx, pods)
-		duration := time.Since(startTime)
+	parent := ctx.Ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	now := time.Now()
+	policyWeights := scoreWeightsOf(ctx.SchedulingPolicy)
+	if policyWeights != nil && !s.runsWeightedPlugin(policyWeights, now) {
+		// None of the weighted plugins is enabled in the router, fall back to the default weights of a policy
+		klog.V(4).Infof("No score plugin of the scheduling policy can run, using the default weights")
+		policyWeights = scoreWeightsOf(&aiv1alpha1.SchedulingPolicy{Accelerators: ctx.SchedulingPolicy.Accelerators})
+	}
+
+	results := make(chan scoreResult, len(s.scorePlugins))
+	running := 0
+	for _, sp := range s.scorePlugins {
+		weight := sp.weight
+		if policyWeights != nil {
+			weight = policyWeights[sp.plugin.Name()]
+			if weight == 0 {
+				// left out by the policy
+				continue
+			}
+		}
+		if s.pluginDisabled(sp.plugin.Name()) {
+			klog.V(4).Infof("ScorePlugin %s is disabled, skipping it", sp.plugin.Name())
+			if ctx.MetricsRecorder != nil {
+				ctx.MetricsRecorder.RecordSchedulerPluginSkipped(sp.plugin.Name(), metrics.PluginSkipReasonDisabled)
+			}
+			ctx.Decision.RecordSkipped(sp.plugin.Name(), metrics.PluginSkipReasonDisabled)
+			continue
+		}
+		if sp.degraded(now) {
+			klog.V(4).Infof("ScorePlugin %s is degraded, skipping it", sp.plugin.Name())
+			if ctx.MetricsRecorder != nil {
+				ctx.MetricsRecorder.RecordSchedulerPluginSkipped(sp.plugin.Name(), metrics.PluginSkipReasonDegraded)
+			}
+			ctx.Decision.RecordSkipped(sp.plugin.Name(), metrics.PluginSkipReasonDegraded)
+			continue
+		}
+		running++
+		// The context is copied here so that the plugins never race with the merge of the results below.
+		go runScorePlugin(parent, sp, weight, *ctx, pods, results)
+	}
+
+	// Every candidate starts with a score of 0, so that a pod is still selected when all the plugins are skipped.
+	res := make(map[*datastore.PodInfo]int, len(pods))
+	for _, pod := range pods {
+		res[pod] = 0
+	}
+	for i := 0; i < running; i++ {
+		result := <-results
+		scorePlugin := result.plugin
+		scorePlugin.observe(result.timedOut, time.Now())
 
 		// Use the MetricsRecorder from context to record plugin duration
 		if ctx.MetricsRecorder != nil {
-			ctx.MetricsRecorder.RecordSchedulerPluginDuration(scorePlugin.plugin.Name(), metrics.PluginTypeScore, duration)
+			ctx.MetricsRecorder.RecordSchedulerPluginDuration(scorePlugin.plugin.Name(), metrics.PluginTypeScore, result.duration)
+		}
+		if result.timedOut {
+			klog.V(2).Infof("ScorePlugin %s missed its deadline of %v, leaving it out", scorePlugin.plugin.Name(), scorePlugin.timeout)
+			if ctx.MetricsRecorder != nil {
+				ctx.MetricsRecorder.RecordSchedulerPluginSkipped(scorePlugin.plugin.Name(), metrics.PluginSkipReasonTimeout)
+			}
+			ctx.Decision.RecordSkipped(scorePlugin.plugin.Name(), metrics.PluginSkipReasonTimeout)
+			continue
+		}
+		if result.hashes != nil {
+			ctx.Hashes = result.hashes
 		}
 
+		scores := normalizeScores(result.scores, policyWeights != nil)
+		ctx.Decision.RecordScores(scorePlugin.plugin.Name(), result.weight, scores)
 		klog.V(4).Infof("ScorePlugin: %s", scorePlugin.plugin.Name())
 		for k, v := range scores {
 			if k.Pod != nil {
 				klog.V(4).Infof("Pod: %s/%s, Score: %d", k.Pod.Namespace, k.Pod.Name, v)
 			}
-			if _, ok := res[k]; !ok {
-				res[k] = v * scorePlugin.weight
-			} else {
-				res[k] += v * scorePlugin.weight
-			}
+			res[k] += v * result.weight
 		}
 	}
 
@@ -212,20 +421,178 @@ func (s *SchedulerImpl) RunScorePlugins(pods []*datastore.PodInfo, ctx *framewor
 	return res
 }
 
+// runScorePlugin runs a score plugin with a copy of the context and sends its result, or a timeout if the
+// plugin misses its deadline. A plugin missing its deadline keeps running in the background, its result is dropped.
+func runScorePlugin(parent context.Context, sp *scorePlugin, weight int, pluginCtx framework.Context, pods []*datastore.PodInfo, results chan<- scoreResult) {
+	timeout := sp.timeout
+	if timeout <= 0 {
+		timeout = defaultScoreTimeout
+	}
+	deadlineCtx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+	pluginCtx.Ctx = deadlineCtx
+	pluginCtx.Decision = nil
+
+	startTime := time.Now()
+	done := make(chan map[*datastore.PodInfo]int, 1)
+	go func() {
+		done <- sp.plugin.Score(&pluginCtx, pods)
+	}()
+
+	select {
+	case scores := <-done:
+		results <- scoreResult{plugin: sp, weight: weight, scores: scores, hashes: pluginCtx.Hashes, duration: time.Since(startTime)}
+	case <-deadlineCtx.Done():
+		results <- scoreResult{plugin: sp, weight: weight, duration: time.Since(startTime), timedOut: true}
+	}
+}
+
+// filterServedModel keeps the pods serving the model of the request. Only the pods backing several ModelServers may
+// serve other models, they are checked against the models listed by their engine, unless these are not known yet.
+func filterServedModel(ctx *framework.Context, pods []*datastore.PodInfo) ([]*datastore.PodInfo, error) {
+	if ctx.ServedModel == "" {
+		return pods, nil
+	}
+	filtered := make([]*datastore.PodInfo, 0, len(pods))
+	for _, pod := range pods {
+		if pod.GetModelServerCount() <= 1 || pod.NumModels() == 0 || pod.Contains(ctx.ServedModel) {
+			filtered = append(filtered, pod)
+		}
+	}
+	if len(filtered) == len(pods) {
+		return pods, nil
+	}
+	ctx.Decision.RecordFilter(servedModelFilter, pods, filtered)
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("no pod serves model %q", ctx.ServedModel)
+	}
+	return filtered, nil
+}
+
+// filterAccelerators keeps the pods whose accelerator is one of the classes required by the scheduling policy.
+func filterAccelerators(ctx *framework.Context, pods []*datastore.PodInfo) ([]*datastore.PodInfo, error) {
+	if ctx.SchedulingPolicy == nil || ctx.SchedulingPolicy.Accelerators == nil || len(ctx.SchedulingPolicy.Accelerators.Required) == 0 {
+		return pods, nil
+	}
+	required := ctx.SchedulingPolicy.Accelerators.Required
+	filtered := make([]*datastore.PodInfo, 0, len(pods))
+	for _, pod := range pods {
+		if datastore.MatchesAnyAccelerator(pod.Accelerator(), required) {
+			filtered = append(filtered, pod)
+		}
+	}
+	if len(filtered) == len(pods) {
+		return pods, nil
+	}
+	ctx.Decision.RecordFilter(acceleratorFilter, pods, filtered)
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("no pod has one of the required accelerators %v", required)
+	}
+	return filtered, nil
+}
+
+// pendingRequests returns the number of running and waiting requests of the pod.
+func pendingRequests(pod *datastore.PodInfo) float64 {
+	return pod.RequestRunningNum + pod.RequestWaitingNum
+}
+
 func (s *SchedulerImpl) RunPostHooks(ctx *framework.Context, index int) {
 	for _, hook := range s.postScheduleHooks {
 		hook.PostSchedule(ctx, index)
 	}
 }
 
-func TopNPodInfos(m map[*datastore.PodInfo]int, n int) []*datastore.PodInfo {
+func (s *SchedulerImpl) ObserveResponse(ctx *framework.Context, index int, response framework.Response) {
+	for _, observer := range s.responseObservers {
+		observer.ObserveResponse(ctx, index, response)
+	}
+}
+
+// scoreWeightsOf returns the weights of the score plugins of a scheduling policy, or nil without a policy.
+func scoreWeightsOf(policy *aiv1alpha1.SchedulingPolicy) map[string]int {
+	if policy == nil {
+		return nil
+	}
+	weights := make(map[string]int, len(policy.ScoreWeights)+1)
+	if len(policy.ScoreWeights) == 0 {
+		for plugin, weight := range defaultPolicyScoreWeights {
+			weights[plugin] = weight
+		}
+	}
+	for _, w := range policy.ScoreWeights {
+		weights[w.Plugin] = int(w.Weight)
+	}
+	if accelerators := policy.Accelerators; accelerators != nil && (len(accelerators.Preferred) > 0 || len(accelerators.Costs) > 0) {
+		if _, ok := weights[plugins.AcceleratorPluginName]; !ok {
+			weights[plugins.AcceleratorPluginName] = defaultAcceleratorScoreWeight
+		}
+	}
+	return weights
+}
+
+// runsWeightedPlugin reports whether a score plugin with a weight is enabled and not degraded.
+func (s *SchedulerImpl) runsWeightedPlugin(weights map[string]int, now time.Time) bool {
+	for _, sp := range s.scorePlugins {
+		if weights[sp.plugin.Name()] > 0 && !s.pluginDisabled(sp.plugin.Name()) && !sp.degraded(now) {
+			return true
+		}
+	}
+	return false
+}
+
+func tieBreakOf(policy *aiv1alpha1.SchedulingPolicy) aiv1alpha1.TieBreakPolicy {
+	if policy == nil || policy.TieBreak == "" {
+		return aiv1alpha1.TieBreakRandom
+	}
+	return policy.TieBreak
+}
+
+// normalizeScores clamps the scores of a plugin to [0, maxScore]. With minMax, the scores are also rescaled
+// so that the best pod scores maxScore and the worst 0, and a plugin scoring all pods the same has no effect.
+func normalizeScores(scores map[*datastore.PodInfo]int, minMax bool) map[*datastore.PodInfo]int {
+	if len(scores) == 0 {
+		return scores
+	}
+	lowest, highest := maxScore, 0
+	normalized := make(map[*datastore.PodInfo]int, len(scores))
+	for pod, score := range scores {
+		score = min(max(score, 0), maxScore)
+		normalized[pod] = score
+		lowest = min(lowest, score)
+		highest = max(highest, score)
+	}
+	if !minMax {
+		return normalized
+	}
+	for pod, score := range normalized {
+		if highest == lowest {
+			normalized[pod] = 0
+		} else {
+			normalized[pod] = (score - lowest) * maxScore / (highest - lowest)
+		}
+	}
+	return normalized
+}
+
+// TopNPodInfos returns the n pods with the highest scores, breaking ties with the given policy.
+func TopNPodInfos(m map[*datastore.PodInfo]int, n int, tieBreak aiv1alpha1.TieBreakPolicy) []*datastore.PodInfo {
 	var list []podInfoWithValue
 	for k, v := range m {
 		list = append(list, podInfoWithValue{pod: k, score: v})
 	}
 
-	sort.Slice(list, func(i, j int) bool {
-		return list[i].score > list[j].score
+	// Shuffle first, so that the pods with the same score are in random order after the stable sort.
+	rand.Shuffle(len(list), func(i, j int) {
+		list[i], list[j] = list[j], list[i]
+	})
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].score != list[j].score {
+			return list[i].score > list[j].score
+		}
+		if tieBreak == aiv1alpha1.TieBreakLeastRequest {
+			return pendingRequests(list[i].pod) < pendingRequests(list[j].pod)
+		}
+		return false
 	})
 
 	res := []*datastore.PodInfo{}
@@ -0,0 +1,288 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins"
+)
+
+type fakeScorePlugin struct {
+	name   string
+	score  int
+	delay  time.Duration
+	hashes []uint64
+}
+
+func (f *fakeScorePlugin) Name() string {
+	return f.name
+}
+
+func (f *fakeScorePlugin) Score(ctx *framework.Context, pods []*datastore.PodInfo) map[*datastore.PodInfo]int {
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+	if f.hashes != nil {
+		ctx.Hashes = f.hashes
+	}
+	scores := make(map[*datastore.PodInfo]int, len(pods))
+	for _, pod := range pods {
+		scores[pod] = f.score
+	}
+	return scores
+}
+
+func TestRunScorePlugins(t *testing.T) {
+	pod := &datastore.PodInfo{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod1"}}}
+	slow := &scorePlugin{
+		plugin:  &fakeScorePlugin{name: "slow", score: 100, delay: 200 * time.Millisecond},
+		weight:  1,
+		timeout: 10 * time.Millisecond,
+	}
+	s := &SchedulerImpl{
+		scorePlugins: []*scorePlugin{
+			{plugin: &fakeScorePlugin{name: "fast", score: 10}, weight: 2, timeout: time.Second},
+			{plugin: &fakeScorePlugin{name: "prefix", score: 5, hashes: []uint64{1, 2}}, weight: 1, timeout: time.Second},
+			slow,
+		},
+	}
+
+	// The slow plugin is left out without delaying the decision
+	for i := 0; i < degradedThreshold; i++ {
+		ctx := &framework.Context{}
+		start := time.Now()
+		scores := s.RunScorePlugins([]*datastore.PodInfo{pod}, ctx)
+		assert.Less(t, time.Since(start), 150*time.Millisecond)
+		assert.Equal(t, map[*datastore.PodInfo]int{pod: 25}, scores)
+		assert.Equal(t, []uint64{1, 2}, ctx.Hashes)
+	}
+
+	// After missing its deadline too many times in a row, the slow plugin is skipped
+	assert.True(t, slow.degraded(time.Now()))
+	assert.False(t, slow.degraded(time.Now().Add(degradedCooldown)))
+	start := time.Now()
+	scores := s.RunScorePlugins([]*datastore.PodInfo{pod}, &framework.Context{})
+	assert.Less(t, time.Since(start), 10*time.Millisecond)
+	assert.Equal(t, map[*datastore.PodInfo]int{pod: 25}, scores)
+
+	// A plugin meeting its deadline again resets the count
+	slow.skipUntil.Store(0)
+	slow.consecutiveTimeouts.Store(degradedThreshold - 1)
+	slow.timeout = time.Second
+	scores = s.RunScorePlugins([]*datastore.PodInfo{pod}, &framework.Context{})
+	assert.Equal(t, map[*datastore.PodInfo]int{pod: 125}, scores)
+	assert.Equal(t, int32(0), slow.consecutiveTimeouts.Load())
+}
+
+func TestRunScorePluginsWithSchedulingPolicy(t *testing.T) {
+	pod1 := &datastore.PodInfo{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}}}
+	pod2 := &datastore.PodInfo{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod2"}}}
+	s := &SchedulerImpl{
+		scorePlugins: []*scorePlugin{
+			{plugin: &mapScorePlugin{name: plugins.KVCacheAwarePluginName, scores: map[*datastore.PodInfo]int{pod1: 60, pod2: 40}}, weight: 1},
+			{plugin: &mapScorePlugin{name: plugins.LeastRequestPluginName, scores: map[*datastore.PodInfo]int{pod1: 10, pod2: 20}}, weight: 1},
+			{plugin: &mapScorePlugin{name: plugins.LeastLatencyPluginName, scores: map[*datastore.PodInfo]int{pod1: 50, pod2: 50}}, weight: 1},
+			{plugin: &mapScorePlugin{name: plugins.RandomPluginName, scores: map[*datastore.PodInfo]int{pod1: 0, pod2: 100}}, weight: 1},
+		},
+	}
+	pods := []*datastore.PodInfo{pod1, pod2}
+
+	// Without a policy, the scores are summed up as they are
+	scores := s.RunScorePlugins(pods, &framework.Context{})
+	assert.Equal(t, map[*datastore.PodInfo]int{pod1: 120, pod2: 210}, scores)
+
+	// With the default policy, the normalized scores are weighted and the random plugin is left out
+	policy := &aiv1alpha1.SchedulingPolicy{}
+	scores = s.RunScorePlugins(pods, &framework.Context{SchedulingPolicy: policy})
+	assert.Equal(t, map[*datastore.PodInfo]int{pod1: 5000, pod2: 3000}, scores)
+
+	policy.ScoreWeights = []aiv1alpha1.ScoreWeight{
+		{Plugin: plugins.KVCacheAwarePluginName, Weight: 1},
+		{Plugin: plugins.LeastRequestPluginName, Weight: 2},
+	}
+	scores = s.RunScorePlugins(pods, &framework.Context{SchedulingPolicy: policy})
+	assert.Equal(t, map[*datastore.PodInfo]int{pod1: 100, pod2: 200}, scores)
+
+	// Without any weighted plugin to run, the default weights are used
+	policy.ScoreWeights = []aiv1alpha1.ScoreWeight{{Plugin: plugins.PrefixCachePluginName, Weight: 1}}
+	scores = s.RunScorePlugins(pods, &framework.Context{SchedulingPolicy: policy})
+	assert.Equal(t, map[*datastore.PodInfo]int{pod1: 5000, pod2: 3000}, scores)
+	policy.ScoreWeights = []aiv1alpha1.ScoreWeight{{Plugin: plugins.KVCacheAwarePluginName, Weight: 1}}
+	assert.NoError(t, s.SetPluginEnabled(plugins.KVCacheAwarePluginName, false))
+	scores = s.RunScorePlugins(pods, &framework.Context{SchedulingPolicy: policy})
+	assert.Equal(t, map[*datastore.PodInfo]int{pod1: 0, pod2: 3000}, scores)
+}
+
+func TestRunScorePluginsAllSkipped(t *testing.T) {
+	pod1 := &datastore.PodInfo{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}}}
+	pod2 := &datastore.PodInfo{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod2"}}}
+	s := &SchedulerImpl{
+		scorePlugins: []*scorePlugin{
+			{plugin: &fakeScorePlugin{name: "disabled", score: 10}, weight: 1, timeout: time.Second},
+		},
+	}
+	assert.NoError(t, s.SetPluginEnabled("disabled", false))
+	pods := []*datastore.PodInfo{pod1, pod2}
+
+	// Every candidate is kept with a score of 0
+	scores := s.RunScorePlugins(pods, &framework.Context{})
+	assert.Equal(t, map[*datastore.PodInfo]int{pod1: 0, pod2: 0}, scores)
+	assert.Len(t, TopNPodInfos(scores, 1, ""), 1)
+}
+
+func TestTopNPodInfosTieBreak(t *testing.T) {
+	busy := &datastore.PodInfo{RequestRunningNum: 5}
+	idle := &datastore.PodInfo{RequestWaitingNum: 1}
+	worst := &datastore.PodInfo{}
+	scores := map[*datastore.PodInfo]int{busy: 10, idle: 10, worst: 5}
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, []*datastore.PodInfo{idle, busy, worst}, TopNPodInfos(scores, 3, aiv1alpha1.TieBreakLeastRequest))
+	}
+
+	seen := map[*datastore.PodInfo]bool{}
+	for i := 0; i < 100; i++ {
+		seen[TopNPodInfos(scores, 1, aiv1alpha1.TieBreakRandom)[0]] = true
+	}
+	assert.Equal(t, map[*datastore.PodInfo]bool{busy: true, idle: true}, seen)
+}
+
+type mapScorePlugin struct {
+	name   string
+	scores map[*datastore.PodInfo]int
+}
+
+func (m *mapScorePlugin) Name() string {
+	return m.name
+}
+
+func (m *mapScorePlugin) Score(ctx *framework.Context, pods []*datastore.PodInfo) map[*datastore.PodInfo]int {
+	return m.scores
+}
+
+func TestFilterServedModel(t *testing.T) {
+	newPod := func(name string, modelServers int, models ...string) *datastore.PodInfo {
+		pod := &datastore.PodInfo{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}}
+		for i := 0; i < modelServers; i++ {
+			pod.AddModelServer(types.NamespacedName{Namespace: "default", Name: fmt.Sprintf("ms-%d", i)})
+		}
+		pod.UpdateModels(models)
+		return pod
+	}
+	single := newPod("single", 1, "mistral")
+	llama := newPod("llama", 2, "llama", "qwen")
+	qwen := newPod("qwen", 2, "qwen", "mistral")
+	unknown := newPod("unknown", 2)
+	pods := []*datastore.PodInfo{single, llama, qwen, unknown}
+
+	// The pods of a single ModelServer and the pods whose models are not known yet are kept
+	filtered, err := filterServedModel(&framework.Context{ServedModel: "llama"}, pods)
+	assert.NoError(t, err)
+	assert.Equal(t, []*datastore.PodInfo{single, llama, unknown}, filtered)
+	assert.Len(t, pods, 4, "the pods of the store are not changed")
+
+	filtered, err = filterServedModel(&framework.Context{ServedModel: "qwen"}, pods)
+	assert.NoError(t, err)
+	assert.Equal(t, pods, filtered)
+
+	filtered, err = filterServedModel(&framework.Context{}, pods)
+	assert.NoError(t, err)
+	assert.Equal(t, pods, filtered)
+
+	_, err = filterServedModel(&framework.Context{ServedModel: "gemma"}, []*datastore.PodInfo{llama, qwen})
+	assert.ErrorContains(t, err, `no pod serves model "gemma"`)
+}
+
+func TestFilterAccelerators(t *testing.T) {
+	store := datastore.New()
+	ms := &aiv1alpha1.ModelServer{ObjectMeta: metav1.ObjectMeta{Name: "ms", Namespace: "default"}}
+	var pods []*datastore.PodInfo
+	for _, accelerator := range []string{"NVIDIA-A100-SXM4-80GB", "NVIDIA-H100-80GB-HBM3", ""} {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-" + accelerator}}
+		if accelerator != "" {
+			node.Labels = map[string]string{"nvidia.com/gpu.product": accelerator}
+		}
+		assert.NoError(t, store.AddOrUpdateNode(node))
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-" + accelerator, Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: node.Name},
+		}
+		assert.NoError(t, store.AddOrUpdatePod(pod, []*aiv1alpha1.ModelServer{ms}))
+		pods = append(pods, store.GetPodInfo(types.NamespacedName{Namespace: "default", Name: pod.Name}))
+	}
+	a100, h100 := pods[0], pods[1]
+	withRequired := func(classes ...string) *framework.Context {
+		return &framework.Context{SchedulingPolicy: &aiv1alpha1.SchedulingPolicy{
+			Accelerators: &aiv1alpha1.AcceleratorPolicy{Required: classes},
+		}}
+	}
+
+	// The pods of unknown accelerators are left out
+	filtered, err := filterAccelerators(withRequired("H100"), pods)
+	assert.NoError(t, err)
+	assert.Equal(t, []*datastore.PodInfo{h100}, filtered)
+
+	filtered, err = filterAccelerators(withRequired("A100", "H100"), pods)
+	assert.NoError(t, err)
+	assert.Equal(t, []*datastore.PodInfo{a100, h100}, filtered)
+
+	filtered, err = filterAccelerators(&framework.Context{SchedulingPolicy: &aiv1alpha1.SchedulingPolicy{}}, pods)
+	assert.NoError(t, err)
+	assert.Equal(t, pods, filtered)
+
+	_, err = filterAccelerators(withRequired("Ascend910B"), pods)
+	assert.ErrorContains(t, err, "no pod has one of the required accelerators [Ascend910B]")
+}
+
+func TestScoreWeightsOfAcceleratorPolicy(t *testing.T) {
+	assert.Nil(t, scoreWeightsOf(nil))
+
+	// The accelerator plugin scores the preferences unless the policy weights it
+	weights := scoreWeightsOf(&aiv1alpha1.SchedulingPolicy{
+		Accelerators: &aiv1alpha1.AcceleratorPolicy{Preferred: []string{"H100"}},
+	})
+	assert.Equal(t, map[string]int{
+		plugins.KVCacheAwarePluginName: 50,
+		plugins.LeastRequestPluginName: 30,
+		plugins.LeastLatencyPluginName: 20,
+		plugins.AcceleratorPluginName:  defaultAcceleratorScoreWeight,
+	}, weights)
+	assert.NotContains(t, defaultPolicyScoreWeights, plugins.AcceleratorPluginName)
+
+	weights = scoreWeightsOf(&aiv1alpha1.SchedulingPolicy{
+		ScoreWeights: []aiv1alpha1.ScoreWeight{{Plugin: plugins.AcceleratorPluginName, Weight: 10}},
+		Accelerators: &aiv1alpha1.AcceleratorPolicy{Costs: []aiv1alpha1.AcceleratorCost{{Accelerator: "A100", Cost: 1}}},
+	})
+	assert.Equal(t, map[string]int{plugins.AcceleratorPluginName: 10}, weights)
+
+	weights = scoreWeightsOf(&aiv1alpha1.SchedulingPolicy{
+		ScoreWeights: []aiv1alpha1.ScoreWeight{{Plugin: plugins.LeastRequestPluginName, Weight: 10}},
+		Accelerators: &aiv1alpha1.AcceleratorPolicy{Required: []string{"H100"}},
+	})
+	assert.Equal(t, map[string]int{plugins.LeastRequestPluginName: 10}, weights)
+}
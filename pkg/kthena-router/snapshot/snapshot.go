@@ -0,0 +1,252 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+const (
+	// ModelRouteLabelKey is set on every snapshot to the name of the ModelRoute it was taken from.
+	ModelRouteLabelKey = "networking.serving.volcano.sh/modelroute"
+	// VersionLabelKey is the version of a snapshot, which is the generation of the ModelRoute it was taken from.
+	VersionLabelKey = "networking.serving.volcano.sh/snapshot-version"
+	// RestoredFromAnnotationKey records the snapshot version a ModelRoute was last rolled back to.
+	RestoredFromAnnotationKey = "networking.serving.volcano.sh/restored-from"
+
+	specDataKey = "spec.yaml"
+
+	// DefaultHistoryLimit is the number of snapshots kept for each ModelRoute by default.
+	DefaultHistoryLimit = 10
+)
+
+// Snapshot is a versioned copy of an accepted ModelRoute spec.
+type Snapshot struct {
+	ModelRoute        string                            `json:"modelRoute"`
+	Namespace         string                            `json:"namespace"`
+	Version           int64                             `json:"version"`
+	CreationTimestamp metav1.Time                       `json:"creationTimestamp"`
+	Spec              networkingv1alpha1.ModelRouteSpec `json:"spec"`
+}
+
+// Manager persists a snapshot of every accepted ModelRoute change as a ConfigMap, and restores
+// a ModelRoute from one of them. The ConfigMaps are owned by the ModelRoute, so they are
+// garbage collected with it.
+type Manager struct {
+	kubeClient   kubernetes.Interface
+	kthenaClient clientset.Interface
+	historyLimit int
+
+	mutex sync.Mutex
+	// recorded is the latest generation recorded for each ModelRoute, which avoids hitting
+	// the API server on resyncs and status updates.
+	recorded map[string]int64
+}
+
+// NewManager creates a snapshot manager that keeps at most historyLimit snapshots for each ModelRoute.
+func NewManager(kubeClient kubernetes.Interface, kthenaClient clientset.Interface, historyLimit int) *Manager {
+	if historyLimit <= 0 {
+		historyLimit = DefaultHistoryLimit
+	}
+	return &Manager{
+		kubeClient:   kubeClient,
+		kthenaClient: kthenaClient,
+		historyLimit: historyLimit,
+		recorded:     make(map[string]int64),
+	}
+}
+
+// Name returns the name of the ConfigMap holding the given snapshot version of a ModelRoute.
+func Name(modelRoute string, version int64) string {
+	return fmt.Sprintf("%s-snapshot-%d", modelRoute, version)
+}
+
+// Record persists the spec of the ModelRoute if its generation has not been recorded yet,
+// and prunes snapshots beyond the history limit.
+func (m *Manager) Record(ctx context.Context, mr *networkingv1alpha1.ModelRoute) error {
+	key := mr.Namespace + "/" + mr.Name
+	m.mutex.Lock()
+	recorded := m.recorded[key]
+	m.mutex.Unlock()
+	if recorded >= mr.Generation {
+		return nil
+	}
+
+	data, err := yaml.Marshal(mr.Spec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ModelRoute spec: %v", err)
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(mr.Name, mr.Generation),
+			Namespace: mr.Namespace,
+			Labels: map[string]string{
+				ModelRouteLabelKey: mr.Name,
+				VersionLabelKey:    strconv.FormatInt(mr.Generation, 10),
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: networkingv1alpha1.SchemeGroupVersion.String(),
+				Kind:       "ModelRoute",
+				Name:       mr.Name,
+				UID:        mr.UID,
+			}},
+		},
+		Data: map[string]string{specDataKey: string(data)},
+	}
+	// Every router replica records the same snapshot, the first one wins.
+	_, err = m.kubeClient.CoreV1().ConfigMaps(mr.Namespace).Create(ctx, cm, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create snapshot %s: %v", cm.Name, err)
+	}
+	if err == nil {
+		klog.V(4).Infof("Recorded snapshot %s/%s", cm.Namespace, cm.Name)
+	}
+
+	if err := m.prune(ctx, mr.Namespace, mr.Name); err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	if m.recorded[key] < mr.Generation {
+		m.recorded[key] = mr.Generation
+	}
+	m.mutex.Unlock()
+	return nil
+}
+
+// Forget drops the in-memory state of a deleted ModelRoute.
+func (m *Manager) Forget(namespace, name string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.recorded, namespace+"/"+name)
+}
+
+func (m *Manager) prune(ctx context.Context, namespace, name string) error {
+	snapshots, err := m.List(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) <= m.historyLimit {
+		return nil
+	}
+	for _, s := range snapshots[:len(snapshots)-m.historyLimit] {
+		err := m.kubeClient.CoreV1().ConfigMaps(namespace).Delete(ctx, Name(name, s.Version), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete snapshot %s: %v", Name(name, s.Version), err)
+		}
+	}
+	return nil
+}
+
+// List returns the snapshots of a ModelRoute, sorted from the oldest to the latest version.
+func (m *Manager) List(ctx context.Context, namespace, name string) ([]*Snapshot, error) {
+	selector := labels.SelectorFromSet(labels.Set{ModelRouteLabelKey: name})
+	cms, err := m.kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots of ModelRoute %s/%s: %v", namespace, name, err)
+	}
+
+	snapshots := make([]*Snapshot, 0, len(cms.Items))
+	for i := range cms.Items {
+		s, err := fromConfigMap(&cms.Items[i])
+		if err != nil {
+			klog.Warningf("Ignoring invalid snapshot %s/%s: %v", namespace, cms.Items[i].Name, err)
+			continue
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Version < snapshots[j].Version
+	})
+	return snapshots, nil
+}
+
+// Get returns the given snapshot version of a ModelRoute.
+func (m *Manager) Get(ctx context.Context, namespace, name string, version int64) (*Snapshot, error) {
+	cm, err := m.kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, Name(name, version), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if cm.Labels[ModelRouteLabelKey] != name {
+		return nil, apierrors.NewNotFound(corev1.Resource("configmaps"), cm.Name)
+	}
+	return fromConfigMap(cm)
+}
+
+// Rollback restores the spec of a ModelRoute from the given snapshot version. The whole spec is
+// replaced in a single update, so the router never observes a partially restored route.
+// Rolling back creates a new generation, which is recorded as a new snapshot in turn.
+func (m *Manager) Rollback(ctx context.Context, namespace, name string, version int64) (*networkingv1alpha1.ModelRoute, error) {
+	s, err := m.Get(ctx, namespace, name, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot %d of ModelRoute %s/%s: %w", version, namespace, name, err)
+	}
+
+	var updated *networkingv1alpha1.ModelRoute
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		mr, err := m.kthenaClient.NetworkingV1alpha1().ModelRoutes(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		mr = mr.DeepCopy()
+		mr.Spec = *s.Spec.DeepCopy()
+		if mr.Annotations == nil {
+			mr.Annotations = map[string]string{}
+		}
+		mr.Annotations[RestoredFromAnnotationKey] = strconv.FormatInt(version, 10)
+		updated, err = m.kthenaClient.NetworkingV1alpha1().ModelRoutes(namespace).Update(ctx, mr, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll back ModelRoute %s/%s to snapshot %d: %w", namespace, name, version, err)
+	}
+	klog.Infof("Rolled back ModelRoute %s/%s to snapshot %d", namespace, name, version)
+	return updated, nil
+}
+
+func fromConfigMap(cm *corev1.ConfigMap) (*Snapshot, error) {
+	version, err := strconv.ParseInt(cm.Labels[VersionLabelKey], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q: %v", cm.Labels[VersionLabelKey], err)
+	}
+	s := &Snapshot{
+		ModelRoute:        cm.Labels[ModelRouteLabelKey],
+		Namespace:         cm.Namespace,
+		Version:           version,
+		CreationTimestamp: cm.CreationTimestamp,
+	}
+	if err := yaml.Unmarshal([]byte(cm.Data[specDataKey]), &s.Spec); err != nil {
+		return nil, fmt.Errorf("invalid spec: %v", err)
+	}
+	return s, nil
+}
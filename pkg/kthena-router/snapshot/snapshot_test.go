@@ -0,0 +1,123 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+func newModelRoute(generation int64, weight uint32) *networkingv1alpha1.ModelRoute {
+	return &networkingv1alpha1.ModelRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "route",
+			Namespace:  "default",
+			UID:        "route-uid",
+			Generation: generation,
+		},
+		Spec: networkingv1alpha1.ModelRouteSpec{
+			ModelName: "llama",
+			Rules: []*networkingv1alpha1.Rule{{
+				Name: "default",
+				TargetModels: []*networkingv1alpha1.TargetModel{
+					{ModelServerName: "stable", Weight: &weight},
+				},
+			}},
+		},
+	}
+}
+
+func TestRecordAndPrune(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := kubefake.NewSimpleClientset()
+	manager := NewManager(kubeClient, kthenafake.NewSimpleClientset(), 2)
+
+	for generation := int64(1); generation <= 3; generation++ {
+		require.NoError(t, manager.Record(ctx, newModelRoute(generation, uint32(generation*10))))
+		// Recording the same generation again is a no-op
+		require.NoError(t, manager.Record(ctx, newModelRoute(generation, 0)))
+	}
+
+	snapshots, err := manager.List(ctx, "default", "route")
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, int64(2), snapshots[0].Version)
+	assert.Equal(t, int64(3), snapshots[1].Version)
+	assert.Equal(t, uint32(30), *snapshots[1].Spec.Rules[0].TargetModels[0].Weight)
+
+	cm, err := kubeClient.CoreV1().ConfigMaps("default").Get(ctx, "route-snapshot-3", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, cm.OwnerReferences, 1)
+	assert.Equal(t, "ModelRoute", cm.OwnerReferences[0].Kind)
+	assert.Equal(t, "route-uid", string(cm.OwnerReferences[0].UID))
+
+	_, err = manager.Get(ctx, "default", "route", 1)
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestRecordExistingSnapshot(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := kubefake.NewSimpleClientset()
+	// Another router replica recorded the snapshot first
+	require.NoError(t, NewManager(kubeClient, nil, 0).Record(ctx, newModelRoute(1, 10)))
+	require.NoError(t, NewManager(kubeClient, nil, 0).Record(ctx, newModelRoute(1, 10)))
+
+	snapshots, err := NewManager(kubeClient, nil, 0).List(ctx, "default", "route")
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+}
+
+func TestRollback(t *testing.T) {
+	ctx := context.Background()
+	current := newModelRoute(2, 100)
+	kubeClient := kubefake.NewSimpleClientset(
+		// Snapshots of other ModelRoutes are never used
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      Name("route", 5),
+			Namespace: "default",
+			Labels:    map[string]string{ModelRouteLabelKey: "other", VersionLabelKey: "5"},
+		}},
+	)
+	kthenaClient := kthenafake.NewSimpleClientset(current)
+	manager := NewManager(kubeClient, kthenaClient, 0)
+	require.NoError(t, manager.Record(ctx, newModelRoute(1, 10)))
+	require.NoError(t, manager.Record(ctx, current))
+
+	mr, err := manager.Rollback(ctx, "default", "route", 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(10), *mr.Spec.Rules[0].TargetModels[0].Weight)
+	assert.Equal(t, "1", mr.Annotations[RestoredFromAnnotationKey])
+
+	stored, err := kthenaClient.NetworkingV1alpha1().ModelRoutes("default").Get(ctx, "route", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, mr.Spec, stored.Spec)
+
+	_, err = manager.Rollback(ctx, "default", "route", 4)
+	assert.True(t, apierrors.IsNotFound(err))
+	_, err = manager.Rollback(ctx, "default", "route", 5)
+	assert.True(t, apierrors.IsNotFound(err))
+}
@@ -0,0 +1,307 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tenancy serves several tenants on one router. The requests are assigned to a tenant by their host or
+// their path prefix, and their models are resolved among the ModelRoutes of the namespaces of the tenant only, so
+// that the tenants of a shared platform may use the same model names for different backends. Each tenant may be
+// restricted to some consumers and limited by request and token quotas.
+package tenancy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/auth"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+const (
+	defaultAPIKeyHeader = "X-API-Key"
+
+	// QuotaRequests and QuotaTokens are the quotas of a tenant.
+	QuotaRequests = "requests"
+	QuotaTokens   = "tokens"
+)
+
+// QuotaExceededError is returned when a request exceeds a quota of its tenant.
+type QuotaExceededError struct {
+	Tenant string
+	Quota  string
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s quota of tenant %q exceeded", e.Quota, e.Tenant)
+}
+
+// TenantAccessDeniedError is returned when a consumer is not allowed to use a tenant.
+type TenantAccessDeniedError struct {
+	Tenant string
+}
+
+func (e *TenantAccessDeniedError) Error() string {
+	return fmt.Sprintf("access to tenant %q is denied", e.Tenant)
+}
+
+// Tenant is a tenant of the router.
+type Tenant struct {
+	Name string
+	// Namespaces are the namespaces of the ModelRoutes of the tenant, searched in order.
+	Namespaces []string
+
+	hosts sets.Set[string]
+	// wildcards are the domains of the wildcard hosts, with their leading dot, e.g. ".example.com".
+	wildcards  []string
+	pathPrefix string
+
+	apiKeyHeader string
+	// consumers is nil if all the consumers may use the tenant.
+	consumers *auth.ConsumerSelector
+
+	quota conf.TenantQuota
+	// requests and tokens are nil if they are not limited.
+	requests *rate.Limiter
+	tokens   *rate.Limiter
+}
+
+// Tenants are the tenants of the router. A nil Tenants has no tenants.
+type Tenants struct {
+	tenants []*Tenant
+	// namespaces are the namespaces of all the tenants, out of reach of the requests matching no tenant.
+	namespaces []string
+}
+
+// Validate checks the configuration of the tenants.
+func Validate(configs []conf.TenantConfig) error {
+	names := sets.New[string]()
+	for _, config := range configs {
+		if config.Name == "" {
+			return fmt.Errorf("tenant name is required")
+		}
+		if names.Has(config.Name) {
+			return fmt.Errorf("duplicate tenant %q", config.Name)
+		}
+		names.Insert(config.Name)
+		if len(config.Hosts) == 0 && config.PathPrefix == "" {
+			return fmt.Errorf("tenant %q selects no requests, it needs hosts or a path prefix", config.Name)
+		}
+		for _, host := range config.Hosts {
+			if host == "" || strings.Contains(strings.TrimPrefix(host, "*."), "*") {
+				return fmt.Errorf("invalid host %q of tenant %q", host, config.Name)
+			}
+		}
+		if config.PathPrefix != "" && (!strings.HasPrefix(config.PathPrefix, "/") || strings.TrimSuffix(config.PathPrefix, "/") == "") {
+			return fmt.Errorf("invalid path prefix %q of tenant %q", config.PathPrefix, config.Name)
+		}
+		if config.Quota.RequestsPerMinute < 0 || config.Quota.TokensPerMinute < 0 {
+			return fmt.Errorf("invalid quota of tenant %q", config.Name)
+		}
+		if err := auth.ValidateConsumers(config.Consumers); err != nil {
+			return fmt.Errorf("invalid consumers of tenant %q: %v", config.Name, err)
+		}
+	}
+	return nil
+}
+
+// New creates the tenants of the router configuration. It returns nil if no tenants are configured.
+// The quotas of the previous tenants are carried over when they did not change, so that reloading the
+// configuration does not reset them.
+func New(config *conf.RouterConfiguration, previous *Tenants) (*Tenants, error) {
+	if config == nil || len(config.Tenants) == 0 {
+		return nil, nil
+	}
+	if err := Validate(config.Tenants); err != nil {
+		return nil, err
+	}
+	apiKeyHeader := config.Access.APIKeyHeader
+	if apiKeyHeader == "" {
+		apiKeyHeader = defaultAPIKeyHeader
+	}
+
+	t := &Tenants{}
+	namespaces := sets.New[string]()
+	for _, tenantConfig := range config.Tenants {
+		tenant := &Tenant{
+			Name:         tenantConfig.Name,
+			Namespaces:   tenantConfig.Namespaces,
+			hosts:        sets.New[string](),
+			pathPrefix:   strings.TrimSuffix(tenantConfig.PathPrefix, "/"),
+			apiKeyHeader: apiKeyHeader,
+			quota:        tenantConfig.Quota,
+		}
+		if len(tenant.Namespaces) == 0 {
+			tenant.Namespaces = []string{tenant.Name}
+		}
+		for _, host := range tenantConfig.Hosts {
+			host = strings.ToLower(host)
+			if strings.HasPrefix(host, "*.") {
+				tenant.wildcards = append(tenant.wildcards, host[1:])
+			} else {
+				tenant.hosts.Insert(host)
+			}
+		}
+		if consumers := auth.NewConsumerSelector(tenantConfig.Consumers); !consumers.Empty() {
+			tenant.consumers = consumers
+		}
+		if old := previous.get(tenant.Name); old != nil && old.quota == tenant.quota {
+			tenant.requests, tenant.tokens = old.requests, old.tokens
+		} else {
+			tenant.requests = newLimiter(tenant.quota.RequestsPerMinute)
+			tenant.tokens = newLimiter(tenant.quota.TokensPerMinute)
+		}
+		namespaces.Insert(tenant.Namespaces...)
+		t.tenants = append(t.tenants, tenant)
+	}
+	t.namespaces = sets.List(namespaces)
+	return t, nil
+}
+
+// newLimiter returns a limiter allowing perMinute events per minute, in bursts of up to a minute of events.
+// It returns nil if perMinute is zero.
+func newLimiter(perMinute int) *rate.Limiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(float64(perMinute)/time.Minute.Seconds()), perMinute)
+}
+
+func (t *Tenants) get(name string) *Tenant {
+	if t == nil {
+		return nil
+	}
+	for _, tenant := range t.tenants {
+		if tenant.Name == name {
+			return tenant
+		}
+	}
+	return nil
+}
+
+// Match returns the first tenant selecting the request to the host and the path, nil if none does.
+func (t *Tenants) Match(host, path string) *Tenant {
+	if t == nil {
+		return nil
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	for _, tenant := range t.tenants {
+		if tenant.matchHost(host) && tenant.matchPath(path) {
+			return tenant
+		}
+	}
+	return nil
+}
+
+// Scope returns the route scope of the requests of the tenant. The requests matching no tenant are resolved
+// among the ModelRoutes of the namespaces of no tenant.
+func (t *Tenants) Scope(tenant *Tenant) datastore.RouteScope {
+	if tenant != nil {
+		return datastore.RouteScope{Namespaces: tenant.Namespaces}
+	}
+	if t == nil {
+		return datastore.RouteScope{}
+	}
+	return datastore.RouteScope{ExcludedNamespaces: t.namespaces}
+}
+
+func (t *Tenant) matchHost(host string) bool {
+	if t.hosts.Len() == 0 && len(t.wildcards) == 0 {
+		return true
+	}
+	if t.hosts.Has(host) {
+		return true
+	}
+	for _, domain := range t.wildcards {
+		if strings.HasSuffix(host, domain) && len(host) > len(domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Tenant) matchPath(path string) bool {
+	return t.pathPrefix == "" || path == t.pathPrefix || strings.HasPrefix(path, t.pathPrefix+"/")
+}
+
+// StripPrefix removes the path prefix of the tenant from the path.
+func (t *Tenant) StripPrefix(path string) string {
+	if t.pathPrefix == "" {
+		return path
+	}
+	path = strings.TrimPrefix(path, t.pathPrefix)
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// Authorize checks whether the consumer presenting the headers of the request and the JWT claims may use the tenant.
+func (t *Tenant) Authorize(header http.Header, claims map[string]interface{}) error {
+	if t.consumers == nil || t.consumers.Selects(header.Get(t.apiKeyHeader), claims) {
+		return nil
+	}
+	return &TenantAccessDeniedError{Tenant: t.Name}
+}
+
+// Admit checks the quotas of the tenant for a request with the input tokens, and consumes them if it is admitted.
+// The token quota only has to have a token left to admit a request, as its output tokens are only known once
+// it is served. The tokens of the requests running over the quota are taken from the next minutes.
+func (t *Tenant) Admit(inputTokens int) error {
+	now := time.Now()
+	if t.tokens != nil && t.tokens.TokensAt(now) < 1 {
+		return &QuotaExceededError{Tenant: t.Name, Quota: QuotaTokens}
+	}
+	if t.requests != nil && !t.requests.AllowN(now, 1) {
+		return &QuotaExceededError{Tenant: t.Name, Quota: QuotaRequests}
+	}
+	t.consumeTokens(now, inputTokens)
+	return nil
+}
+
+// RecordOutputTokens consumes the output tokens of a request served for the tenant.
+func (t *Tenant) RecordOutputTokens(outputTokens int) {
+	t.consumeTokens(time.Now(), outputTokens)
+}
+
+func (t *Tenant) consumeTokens(now time.Time, n int) {
+	if t.tokens == nil || n <= 0 {
+		return
+	}
+	// A reservation larger than the burst fails without consuming anything
+	t.tokens.ReserveN(now, min(n, t.tokens.Burst()))
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of the context carrying the tenant of the request.
+func WithTenant(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// FromContext returns the tenant of the request context, nil if it has none.
+func FromContext(ctx context.Context) *Tenant {
+	tenant, _ := ctx.Value(tenantKey{}).(*Tenant)
+	return tenant
+}
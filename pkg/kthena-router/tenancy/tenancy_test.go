@@ -0,0 +1,191 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenancy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/auth"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+func newTestTenants(t *testing.T, tenants ...conf.TenantConfig) *Tenants {
+	result, err := New(&conf.RouterConfiguration{Tenants: tenants}, nil)
+	require.NoError(t, err)
+	return result
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenants []conf.TenantConfig
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			tenants: []conf.TenantConfig{{Name: "a", Hosts: []string{"a.example.com", "*.a.example.com"}}, {Name: "b", PathPrefix: "/b"}},
+		},
+		{
+			name:    "missing name",
+			tenants: []conf.TenantConfig{{Hosts: []string{"a.example.com"}}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate name",
+			tenants: []conf.TenantConfig{{Name: "a", PathPrefix: "/a"}, {Name: "a", PathPrefix: "/b"}},
+			wantErr: true,
+		},
+		{
+			name:    "no hosts nor path prefix",
+			tenants: []conf.TenantConfig{{Name: "a"}},
+			wantErr: true,
+		},
+		{
+			name:    "wildcard in the middle of the host",
+			tenants: []conf.TenantConfig{{Name: "a", Hosts: []string{"a.*.example.com"}}},
+			wantErr: true,
+		},
+		{
+			name:    "relative path prefix",
+			tenants: []conf.TenantConfig{{Name: "a", PathPrefix: "a"}},
+			wantErr: true,
+		},
+		{
+			name:    "root path prefix",
+			tenants: []conf.TenantConfig{{Name: "a", PathPrefix: "/"}},
+			wantErr: true,
+		},
+		{
+			name:    "negative quota",
+			tenants: []conf.TenantConfig{{Name: "a", PathPrefix: "/a", Quota: conf.TenantQuota{TokensPerMinute: -1}}},
+			wantErr: true,
+		},
+		{
+			name:    "plaintext API key",
+			tenants: []conf.TenantConfig{{Name: "a", PathPrefix: "/a", Consumers: conf.ConsumerSelect{APIKeyHashes: []string{"key-a"}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.tenants)
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tenants := newTestTenants(t,
+		conf.TenantConfig{Name: "team-a", Hosts: []string{"team-a.example.com", "*.team-a.example.com"}},
+		conf.TenantConfig{Name: "team-b", PathPrefix: "/team-b/", Namespaces: []string{"team-b", "shared"}},
+		conf.TenantConfig{Name: "team-c", Hosts: []string{"api.example.com"}, PathPrefix: "/team-c"},
+	)
+
+	tests := []struct {
+		host, path string
+		want       string
+	}{
+		{host: "team-a.example.com", path: "/v1/chat/completions", want: "team-a"},
+		{host: "Team-A.example.com:8080", path: "/v1/chat/completions", want: "team-a"},
+		{host: "eu.team-a.example.com", path: "/v1/chat/completions", want: "team-a"},
+		{host: "other-team-a.example.com", path: "/v1/chat/completions"},
+		{host: "api.example.com", path: "/team-b/v1/chat/completions", want: "team-b"},
+		{host: "api.example.com", path: "/team-bb/v1/chat/completions"},
+		{host: "api.example.com", path: "/team-c/v1/completions", want: "team-c"},
+		{host: "other.example.com", path: "/team-c/v1/completions"},
+		{host: "api.example.com", path: "/v1/chat/completions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host+tt.path, func(t *testing.T) {
+			tenant := tenants.Match(tt.host, tt.path)
+			if tt.want == "" {
+				assert.Nil(t, tenant)
+				return
+			}
+			require.NotNil(t, tenant)
+			assert.Equal(t, tt.want, tenant.Name)
+		})
+	}
+
+	teamB := tenants.Match("api.example.com", "/team-b/v1/models")
+	assert.Equal(t, "/v1/models", teamB.StripPrefix("/team-b/v1/models"))
+	assert.Equal(t, "/", teamB.StripPrefix("/team-b"))
+	assert.Equal(t, datastore.RouteScope{Namespaces: []string{"team-b", "shared"}}, tenants.Scope(teamB))
+	assert.Equal(t, datastore.RouteScope{Namespaces: []string{"team-a"}}, tenants.Scope(tenants.Match("team-a.example.com", "/")))
+	assert.Equal(t, datastore.RouteScope{ExcludedNamespaces: []string{"shared", "team-a", "team-b", "team-c"}}, tenants.Scope(nil))
+
+	var none *Tenants
+	assert.Nil(t, none.Match("team-a.example.com", "/"))
+	assert.Equal(t, datastore.RouteScope{}, none.Scope(nil))
+}
+
+func TestAuthorize(t *testing.T) {
+	tenants := newTestTenants(t,
+		conf.TenantConfig{Name: "open", PathPrefix: "/open"},
+		conf.TenantConfig{Name: "closed", PathPrefix: "/closed", Consumers: conf.ConsumerSelect{
+			APIKeyHashes: []string{auth.HashAPIKey("salt", "key-a")},
+			Claims:       map[string][]string{"groups": {"team-a"}},
+		}},
+	)
+	open := tenants.Match("", "/open/v1/completions")
+	closed := tenants.Match("", "/closed/v1/completions")
+
+	header := http.Header{}
+	assert.NoError(t, open.Authorize(header, nil))
+	var denied *TenantAccessDeniedError
+	assert.ErrorAs(t, closed.Authorize(header, nil), &denied)
+
+	header.Set("X-API-Key", "key-a")
+	assert.NoError(t, closed.Authorize(header, nil))
+	assert.NoError(t, closed.Authorize(http.Header{}, map[string]interface{}{"groups": []interface{}{"team-a"}}))
+	assert.Error(t, closed.Authorize(http.Header{}, map[string]interface{}{"groups": []interface{}{"team-b"}}))
+}
+
+func TestAdmit(t *testing.T) {
+	config := &conf.RouterConfiguration{Tenants: []conf.TenantConfig{
+		{Name: "requests", PathPrefix: "/requests", Quota: conf.TenantQuota{RequestsPerMinute: 2}},
+		{Name: "tokens", PathPrefix: "/tokens", Quota: conf.TenantQuota{TokensPerMinute: 100}},
+	}}
+	tenants, err := New(config, nil)
+	require.NoError(t, err)
+
+	requests := tenants.Match("", "/requests/v1/completions")
+	assert.NoError(t, requests.Admit(1000))
+	assert.NoError(t, requests.Admit(1000))
+	var exceeded *QuotaExceededError
+	require.ErrorAs(t, requests.Admit(1), &exceeded)
+	assert.Equal(t, QuotaRequests, exceeded.Quota)
+
+	tokens := tenants.Match("", "/tokens/v1/completions")
+	assert.NoError(t, tokens.Admit(60))
+	// The request running over the quota is admitted, the next one is not
+	tokens.RecordOutputTokens(60)
+	require.ErrorAs(t, tokens.Admit(1), &exceeded)
+	assert.Equal(t, QuotaTokens, exceeded.Quota)
+
+	// The quotas are kept across reloads when they did not change
+	config.Tenants[1].Quota.TokensPerMinute = 200
+	reloaded, err := New(config, tenants)
+	require.NoError(t, err)
+	assert.Error(t, reloaded.Match("", "/requests/v1/completions").Admit(1))
+	assert.NoError(t, reloaded.Match("", "/tokens/v1/completions").Admit(1))
+}
@@ -0,0 +1,164 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upstream holds the connection pools the router uses to send the requests to the model server pods.
+package upstream
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"istio.io/istio/pkg/env"
+
+	"github.com/volcano-sh/kthena/pkg/engines"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+)
+
+const (
+	ProtocolHTTP1 = "http1"
+	ProtocolH2C   = "h2c"
+)
+
+var (
+	maxIdleConnsPerPod = env.RegisterIntVar("UPSTREAM_MAX_IDLE_CONNS_PER_POD", 100, "Maximum number of idle keep-alive connections kept open to each model server pod").Get()
+	maxConnsPerPod     = env.RegisterIntVar("UPSTREAM_MAX_CONNS_PER_POD", 0, "Maximum number of connections open to each model server pod, the requests wait for a connection beyond it, 0 means no limit").Get()
+	idleConnTimeout    = env.RegisterDurationVar("UPSTREAM_IDLE_CONN_TIMEOUT", 90*time.Second, "Time an idle keep-alive connection to a model server pod is kept open").Get()
+	http2Enabled       = env.RegisterBoolVar("UPSTREAM_HTTP2_ENABLED", false, "Send the requests over HTTP/2 without TLS to the model servers whose inference engine supports it").Get()
+)
+
+// Config tunes the connection pools.
+type Config struct {
+	// MaxIdleConnsPerPod is the number of idle keep-alive connections kept open to each pod.
+	MaxIdleConnsPerPod int
+	// MaxConnsPerPod limits the connections open to each pod, 0 means no limit. With HTTP/2 the requests
+	// are multiplexed on the connections.
+	MaxConnsPerPod int
+	// IdleConnTimeout is the time an idle connection is kept open.
+	IdleConnTimeout time.Duration
+	// HTTP2 sends the requests to the engines supporting it over HTTP/2 without TLS.
+	HTTP2 bool
+}
+
+// Transports are the connection pools to the pods, one per protocol, shared by all the requests of the router.
+type Transports struct {
+	http1 http.RoundTripper
+	// h2c is nil when HTTP/2 is disabled.
+	h2c http.RoundTripper
+}
+
+var (
+	defaultTransports *Transports
+	once              sync.Once
+)
+
+// Default returns the transports configured by the UPSTREAM_* environment variables.
+func Default() *Transports {
+	once.Do(func() {
+		defaultTransports = NewTransports(Config{
+			MaxIdleConnsPerPod: maxIdleConnsPerPod,
+			MaxConnsPerPod:     maxConnsPerPod,
+			IdleConnTimeout:    idleConnTimeout,
+			HTTP2:              http2Enabled,
+		})
+	})
+	return defaultTransports
+}
+
+// NewTransports creates the connection pools.
+func NewTransports(config Config) *Transports {
+	t := &Transports{http1: newTransport(config, ProtocolHTTP1)}
+	if config.HTTP2 {
+		t.h2c = newTransport(config, ProtocolH2C)
+	}
+	return t
+}
+
+// For returns the transport of the requests to the pods of an inference engine. The engines which are
+// unknown or do not support HTTP/2 are sent the requests over HTTP/1.1.
+func (t *Transports) For(engine string) http.RoundTripper {
+	if t.h2c != nil {
+		if e := engines.Get(engine); e != nil && e.HTTP2() {
+			return t.h2c
+		}
+	}
+	return t.http1
+}
+
+// HTTP1 returns the HTTP/1.1 transport, for the requests whose engine is not known.
+func (t *Transports) HTTP1() http.RoundTripper {
+	return t.http1
+}
+
+func newTransport(config Config, protocol string) http.RoundTripper {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	connections := metrics.DefaultMetrics.UpstreamConnections.WithLabelValues(protocol)
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			connections.Inc()
+			return &countedConn{Conn: conn, release: connections.Dec}, nil
+		},
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerPod,
+		MaxConnsPerHost:       config.MaxConnsPerPod,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	if protocol == ProtocolH2C {
+		// Only HTTP/2 is spoken on the connections of plain http URLs, with prior knowledge
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetUnencryptedHTTP2(true)
+	}
+	return &tracedTransport{transport: transport, protocol: protocol}
+}
+
+// tracedTransport records how long the requests waited for a connection, and whether it was reused.
+type tracedTransport struct {
+	transport *http.Transport
+	protocol  string
+}
+
+func (t *tracedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var start time.Time
+	trace := &httptrace.ClientTrace{
+		GetConn: func(string) {
+			start = time.Now()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			metrics.DefaultMetrics.RecordUpstreamConnection(t.protocol, info.Reused, time.Since(start))
+		},
+	}
+	return t.transport.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+}
+
+// countedConn releases its count in the open connections once closed.
+type countedConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *countedConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
@@ -0,0 +1,79 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upstream
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/metrics"
+)
+
+func newProtoServer(t *testing.T) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, transport http.RoundTripper, url string) string {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestTransports(t *testing.T) {
+	server := newProtoServer(t)
+
+	transports := NewTransports(Config{MaxIdleConnsPerPod: 10, HTTP2: true})
+	assert.Equal(t, "HTTP/2.0", get(t, transports.For("TGI"), server.URL))
+	assert.Equal(t, "HTTP/1.1", get(t, transports.For("vLLM"), server.URL))
+	assert.Equal(t, "HTTP/1.1", get(t, transports.For("unknown"), server.URL))
+
+	transports = NewTransports(Config{MaxIdleConnsPerPod: 10})
+	assert.Equal(t, "HTTP/1.1", get(t, transports.For("TGI"), server.URL))
+}
+
+func TestTransportReusesConnections(t *testing.T) {
+	server := newProtoServer(t)
+	reused := metrics.DefaultMetrics.UpstreamConnectionRequests.WithLabelValues(ProtocolHTTP1, "true")
+	before := testutil.ToFloat64(reused)
+
+	transport := NewTransports(Config{MaxIdleConnsPerPod: 10}).HTTP1()
+	for i := 0; i < 3; i++ {
+		get(t, transport, server.URL)
+	}
+	// The sequential requests share a single connection
+	assert.Equal(t, 2.0, testutil.ToFloat64(reused)-before)
+	assert.GreaterOrEqual(t, testutil.ToFloat64(metrics.DefaultMetrics.UpstreamConnections.WithLabelValues(ProtocolHTTP1)), 1.0)
+}
@@ -18,6 +18,7 @@ package utils
 
 import (
 	"context"
+	"net"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -30,7 +31,7 @@ func TryGetRedisClient() *redis.Client {
 	redisPassword := LoadEnv("REDIS_PASSWORD", "")
 
 	client := redis.NewClient(&redis.Options{
-		Addr:     redisHost + ":" + redisPort,
+		Addr:     net.JoinHostPort(redisHost, redisPort),
 		Password: redisPassword,
 		DB:       0,
 	})
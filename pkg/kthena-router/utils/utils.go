@@ -17,9 +17,12 @@ limitations under the License.
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/volcano-sh/kthena/pkg/engines"
 	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -27,11 +30,11 @@ import (
 )
 
 var (
-	GPUCacheUsage     = "gpu_usage"
-	RequestWaitingNum = "request_waiting_num"
-	RequestRunningNum = "request_running_num"
-	TPOT              = "TPOT"
-	TTFT              = "TTFT"
+	GPUCacheUsage     = engines.GPUCacheUsage
+	RequestWaitingNum = engines.RequestWaitingNum
+	RequestRunningNum = engines.RequestRunningNum
+	TPOT              = engines.TPOT
+	TTFT              = engines.TTFT
 )
 
 func GetNamespaceName(obj metav1.Object) types.NamespacedName {
@@ -41,6 +44,41 @@ func GetNamespaceName(obj metav1.Object) types.NamespacedName {
 	}
 }
 
+// GetRequestType returns the type of the request served by the given API path.
+func GetRequestType(path string) common.RequestType {
+	switch {
+	case strings.HasSuffix(path, "/embeddings"):
+		return common.RequestTypeEmbedding
+	case strings.HasSuffix(path, "/rerank"):
+		return common.RequestTypeRerank
+	default:
+		return common.RequestTypeGeneration
+	}
+}
+
+// GetBatchSize returns the number of inputs carried by an embedding or rerank request.
+// Generation requests always carry a single prompt.
+func GetBatchSize(body map[string]interface{}) int {
+	if documents, ok := body["documents"].([]interface{}); ok {
+		return len(documents)
+	}
+	if input, ok := body["input"].([]interface{}); ok && len(input) > 0 {
+		// A flat list of token ids is a single input, and so are the items of a Responses API request
+		switch input[0].(type) {
+		case float64, map[string]interface{}:
+			return 1
+		}
+		return len(input)
+	}
+	return 1
+}
+
+// IsResponsesAPI reports whether the path is the OpenAI Responses API. Its requests carry their prompt in
+// instructions and input items, and its responses always report their usage, as input and output tokens.
+func IsResponsesAPI(path string) bool {
+	return strings.HasSuffix(path, "/responses")
+}
+
 func ParsePrompt(body map[string]interface{}) (common.ChatMessage, error) {
 	if prompt, ok := body["prompt"]; ok {
 		promptStr, ok := prompt.(string)
@@ -70,7 +108,7 @@ func ParsePrompt(body map[string]interface{}) (common.ChatMessage, error) {
 				continue
 			}
 
-			content, ok := msgMap["content"].(string)
+			content, ok := messageContent(msgMap)
 			if !ok {
 				continue
 			}
@@ -83,20 +121,194 @@ func ParsePrompt(body map[string]interface{}) (common.ChatMessage, error) {
 
 		return common.ChatMessage{
 			Messages: msgs,
+			Tools:    parseTools(body),
+		}, nil
+	}
+
+	// Responses API request: instructions followed by input items
+	if isResponsesRequest(body) {
+		return parseResponsesInput(body)
+	}
+
+	// Embeddings request: input is a string, a list of strings or token ids
+	if input, ok := body["input"]; ok {
+		texts, err := parseTexts(input)
+		if err != nil {
+			return common.ChatMessage{}, fmt.Errorf("input is %v", err)
+		}
+		return common.ChatMessage{
+			Text:  strings.Join(texts, "\n"),
+			Tools: parseTools(body),
+		}, nil
+	}
+
+	// Rerank request: a query scored against a list of documents
+	if query, ok := body["query"]; ok {
+		queryStr, ok := query.(string)
+		if !ok {
+			return common.ChatMessage{}, fmt.Errorf("query is not a string")
+		}
+		documents, err := parseTexts(body["documents"])
+		if err != nil {
+			return common.ChatMessage{}, fmt.Errorf("documents is %v", err)
+		}
+		return common.ChatMessage{
+			Text: strings.Join(append([]string{queryStr}, documents...), "\n"),
 		}, nil
 	}
 
 	return common.ChatMessage{}, fmt.Errorf("prompt or messages not found in request body")
 }
 
+// messageContent returns the text of a chat message or of a Responses API message item: its content, either a
+// string or a list of parts of which the text parts are kept, followed by the tool calls of an assistant
+// message. It returns false when the message has neither.
+func messageContent(message map[string]interface{}) (string, bool) {
+	var texts []string
+	found := false
+	switch content := message["content"].(type) {
+	case string:
+		texts, found = append(texts, content), true
+	case []interface{}:
+		// Text parts are "text" in chat completions, "input_text" and "output_text" in the Responses API
+		for _, part := range content {
+			if partMap, ok := part.(map[string]interface{}); ok {
+				if text, ok := partMap["text"].(string); ok {
+					texts = append(texts, text)
+				}
+			}
+		}
+		found = true
+	}
+	if toolCalls, ok := message["tool_calls"].([]interface{}); ok {
+		for _, toolCall := range toolCalls {
+			callMap, ok := toolCall.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if function, ok := callMap["function"].(map[string]interface{}); ok {
+				texts, found = append(texts, functionCall(function["name"], function["arguments"])), true
+			}
+		}
+	}
+	return strings.Join(texts, "\n"), found
+}
+
+// functionCall renders a function call of the model as name(arguments).
+func functionCall(name, arguments interface{}) string {
+	nameStr, _ := name.(string)
+	argumentsStr, _ := arguments.(string)
+	return nameStr + "(" + argumentsStr + ")"
+}
+
+// parseTools returns the JSON definitions of the tools of the request, which are part of the prompt the model reads.
+func parseTools(body map[string]interface{}) []string {
+	toolList, ok := body["tools"].([]interface{})
+	if !ok {
+		return nil
+	}
+	tools := make([]string, 0, len(toolList))
+	for _, tool := range toolList {
+		if data, err := json.Marshal(tool); err == nil {
+			tools = append(tools, string(data))
+		}
+	}
+	return tools
+}
+
+// isResponsesRequest reports whether the body is a Responses API request: it has instructions, or its input is
+// a list of items rather than the texts or token ids of an embeddings request. A Responses API request with a
+// single text input and no instructions is parsed like an embeddings request, into the same text prompt.
+func isResponsesRequest(body map[string]interface{}) bool {
+	if _, ok := body["instructions"]; ok {
+		return true
+	}
+	input, ok := body["input"].([]interface{})
+	if !ok || len(input) == 0 {
+		return false
+	}
+	_, isItem := input[0].(map[string]interface{})
+	return isItem
+}
+
+// parseResponsesInput parses the prompt of a Responses API request into chat messages: the instructions are
+// the system message, followed by the messages, function calls and function call outputs of the input.
+func parseResponsesInput(body map[string]interface{}) (common.ChatMessage, error) {
+	prompt := common.ChatMessage{Tools: parseTools(body)}
+	if instructions, ok := body["instructions"].(string); ok && instructions != "" {
+		prompt.Messages = append(prompt.Messages, common.Message{Role: "system", Content: instructions})
+	}
+	switch input := body["input"].(type) {
+	case string:
+		prompt.Messages = append(prompt.Messages, common.Message{Role: "user", Content: input})
+	case []interface{}:
+		for _, item := range input {
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			switch itemMap["type"] {
+			case "function_call":
+				prompt.Messages = append(prompt.Messages, common.Message{
+					Role:    "assistant",
+					Content: functionCall(itemMap["name"], itemMap["arguments"]),
+				})
+			case "function_call_output":
+				output, _ := itemMap["output"].(string)
+				prompt.Messages = append(prompt.Messages, common.Message{Role: "tool", Content: output})
+			default:
+				role, ok := itemMap["role"].(string)
+				if !ok {
+					continue
+				}
+				content, _ := messageContent(itemMap)
+				prompt.Messages = append(prompt.Messages, common.Message{Role: role, Content: content})
+			}
+		}
+	case nil:
+	default:
+		return common.ChatMessage{}, fmt.Errorf("input is neither a string nor a list")
+	}
+	return prompt, nil
+}
+
+// parseTexts extracts the text inputs of an embedding or rerank request. Token id inputs
+// carry no text and are skipped, documents may also be objects with a text field.
+func parseTexts(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		texts := make([]string, 0, len(v))
+		for _, item := range v {
+			switch i := item.(type) {
+			case string:
+				texts = append(texts, i)
+			case map[string]interface{}:
+				if text, ok := i["text"].(string); ok {
+					texts = append(texts, text)
+				}
+			}
+		}
+		return texts, nil
+	default:
+		return nil, fmt.Errorf("neither a string nor a list")
+	}
+}
+
 func GetPromptString(chatMessage common.ChatMessage) string {
+	// The tool definitions come first, as in the chat templates of the models
+	result := ""
+	if len(chatMessage.Tools) > 0 {
+		result = fmt.Sprintf("<|im_start|>tools\n%s<|im_end|>\n", strings.Join(chatMessage.Tools, "\n"))
+	}
+
 	// If Text field is present, return text directly (for prompt format)
 	if chatMessage.Text != "" {
-		return chatMessage.Text
+		return result + chatMessage.Text
 	}
 
 	// For chat messages, convert to ChatML format
-	result := ""
 	for _, msg := range chatMessage.Messages {
 		result += fmt.Sprintf("<|im_start|>%s\n%s<|im_end|>\n", msg.Role, msg.Content)
 	}
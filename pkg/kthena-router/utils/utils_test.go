@@ -0,0 +1,122 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+)
+
+func parseBody(t *testing.T, body string) map[string]interface{} {
+	var parsed map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(body), &parsed))
+	return parsed
+}
+
+func TestParsePrompt(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		want      common.ChatMessage
+		wantBatch int
+	}{
+		{
+			name:      "completion",
+			body:      `{"model":"llama","prompt":"hello"}`,
+			want:      common.ChatMessage{Text: "hello"},
+			wantBatch: 1,
+		},
+		{
+			name: "chat with content parts and tool calls",
+			body: `{"model":"llama","messages":[
+				{"role":"user","content":[{"type":"text","text":"weather in"},{"type":"image_url","image_url":{"url":"x"}},{"type":"text","text":"Paris?"}]},
+				{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]},
+				{"role":"tool","tool_call_id":"call_1","content":"sunny"}],
+				"tools":[{"type":"function","function":{"name":"get_weather","parameters":{"type":"object"}}}],
+				"response_format":{"type":"json_object"},"logprobs":true,"top_logprobs":2}`,
+			want: common.ChatMessage{
+				Messages: []common.Message{
+					{Role: "user", Content: "weather in\nParis?"},
+					{Role: "assistant", Content: `get_weather({"city":"Paris"})`},
+					{Role: "tool", Content: "sunny"},
+				},
+				Tools: []string{`{"function":{"name":"get_weather","parameters":{"type":"object"}},"type":"function"}`},
+			},
+			wantBatch: 1,
+		},
+		{
+			name: "responses API items",
+			body: `{"model":"llama","instructions":"Be brief.","input":[
+				{"role":"user","content":"weather in Paris?"},
+				{"type":"function_call","call_id":"c1","name":"get_weather","arguments":"{}"},
+				{"type":"function_call_output","call_id":"c1","output":"sunny"},
+				{"type":"message","role":"user","content":[{"type":"input_text","text":"thanks"}]}],
+				"include":["message.output_text.logprobs"],"text":{"format":{"type":"json_schema","name":"w","schema":{}}}}`,
+			want: common.ChatMessage{Messages: []common.Message{
+				{Role: "system", Content: "Be brief."},
+				{Role: "user", Content: "weather in Paris?"},
+				{Role: "assistant", Content: "get_weather({})"},
+				{Role: "tool", Content: "sunny"},
+				{Role: "user", Content: "thanks"},
+			}},
+			wantBatch: 1,
+		},
+		{
+			name: "responses API text input with instructions",
+			body: `{"model":"llama","instructions":"Be brief.","input":"hello"}`,
+			want: common.ChatMessage{Messages: []common.Message{
+				{Role: "system", Content: "Be brief."},
+				{Role: "user", Content: "hello"},
+			}},
+			wantBatch: 1,
+		},
+		{
+			name:      "embeddings",
+			body:      `{"model":"bge","input":["a","b"]}`,
+			want:      common.ChatMessage{Text: "a\nb"},
+			wantBatch: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := parseBody(t, tt.body)
+			prompt, err := ParsePrompt(body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, prompt)
+			assert.Equal(t, tt.wantBatch, GetBatchSize(body))
+		})
+	}
+}
+
+func TestGetPromptStringWithTools(t *testing.T) {
+	prompt := common.ChatMessage{
+		Messages: []common.Message{{Role: "user", Content: "hi"}},
+		Tools:    []string{`{"type":"function"}`},
+	}
+	assert.Equal(t, "<|im_start|>tools\n{\"type\":\"function\"}<|im_end|>\n<|im_start|>user\nhi<|im_end|>\n", GetPromptString(prompt))
+	assert.Equal(t, "hi", GetPromptString(common.ChatMessage{Text: "hi"}))
+}
+
+func TestIsResponsesAPI(t *testing.T) {
+	assert.True(t, IsResponsesAPI("/v1/responses"))
+	assert.False(t, IsResponsesAPI("/v1/chat/completions"))
+}
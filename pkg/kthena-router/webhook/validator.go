@@ -18,11 +18,11 @@ package webhook
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
-	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,59 +30,29 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
+	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
 	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/features"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/filters/guardrail"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler"
+	"github.com/volcano-sh/kthena/pkg/webhook/tenancy"
 )
 
-const timeout = 30 * time.Second
-
 // KthenaRouterValidator handles validation of ModelRoute and ModelServer resources.
 type KthenaRouterValidator struct {
-	httpServer *http.Server
 	kubeClient kubernetes.Interface
+	// tenancy checks the models referenced by the namespaces against the TenancyPolicies.
+	tenancy *tenancy.Guard
 }
 
 // NewKthenaRouterValidator creates a new KthenaRouterValidator.
-func NewKthenaRouterValidator(kubeClient kubernetes.Interface, port int) *KthenaRouterValidator {
-	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", port),
-		ReadTimeout:  timeout,
-		WriteTimeout: timeout,
-		TLSConfig: &tls.Config{
-			MinVersion: tls.VersionTLS12,
-		},
-	}
-
+func NewKthenaRouterValidator(kubeClient kubernetes.Interface, client clientset.Interface) *KthenaRouterValidator {
 	return &KthenaRouterValidator{
-		httpServer: server,
 		kubeClient: kubeClient,
+		tenancy:    tenancy.NewGuard(kubeClient, client),
 	}
 }
 
-func (v *KthenaRouterValidator) Run(ctx context.Context, tlsCertFile, tlsPrivateKey string) {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/validate/modelroute", v.HandleModelRoute)
-	mux.HandleFunc("/validate/modelserver", v.HandleModelServer)
-	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		if _, err := w.Write([]byte("ok")); err != nil {
-			klog.Errorf("failed to write health check response: %v", err)
-		}
-	})
-	v.httpServer.Handler = mux
-
-	// Start server
-	klog.Infof("Starting webhook server on %s", v.httpServer.Addr)
-	go func() {
-		if err := v.httpServer.ListenAndServeTLS(tlsCertFile, tlsPrivateKey); err != nil && err != http.ErrServerClosed {
-			klog.Fatalf("failed to listen and serve validating webhook: %v", err)
-		}
-	}()
-
-	// shutdown gracefully shuts down the server
-	<-ctx.Done()
-	v.shutdown()
-}
-
 // HandleModelRoute handles admission requests for ModelRoute resources
 func (v *KthenaRouterValidator) HandleModelRoute(w http.ResponseWriter, r *http.Request) {
 	// Parse the admission request
@@ -94,7 +64,7 @@ func (v *KthenaRouterValidator) HandleModelRoute(w http.ResponseWriter, r *http.
 	}
 
 	// Validate the ModelRoute
-	allowed, reason := v.validateModelRoute(modelRoute)
+	allowed, reason := v.validateModelRoute(r.Context(), modelRoute)
 
 	// Create the admission response
 	admissionResponse := admissionv1.AdmissionResponse{
@@ -130,7 +100,7 @@ func (v *KthenaRouterValidator) HandleModelServer(w http.ResponseWriter, r *http
 	}
 
 	// Validate the ModelServer
-	allowed, reason := v.validateModelServer(modelServer)
+	allowed, reason := v.validateModelServer(r.Context(), modelServer)
 
 	// Create the admission response
 	admissionResponse := admissionv1.AdmissionResponse{
@@ -156,7 +126,7 @@ func (v *KthenaRouterValidator) HandleModelServer(w http.ResponseWriter, r *http
 }
 
 // validateModelRoute validates the ModelRoute resource
-func (v *KthenaRouterValidator) validateModelRoute(modelRoute *networkingv1alpha1.ModelRoute) (bool, string) {
+func (v *KthenaRouterValidator) validateModelRoute(ctx context.Context, modelRoute *networkingv1alpha1.ModelRoute) (bool, string) {
 	var allErrs field.ErrorList
 	specField := field.NewPath("spec")
 
@@ -170,6 +140,59 @@ func (v *KthenaRouterValidator) validateModelRoute(modelRoute *networkingv1alpha
 		}
 	}
 
+	if trafficCompare := modelRoute.Spec.TrafficCompare; trafficCompare != nil {
+		compareField := specField.Child("trafficCompare")
+		if trafficCompare.BaselineModelServerName == "" {
+			allErrs = append(allErrs, field.Required(compareField.Child("baselineModelServerName"), "baseline model server must be specified"))
+		}
+		if trafficCompare.CandidateModelServerName == "" {
+			allErrs = append(allErrs, field.Required(compareField.Child("candidateModelServerName"), "candidate model server must be specified"))
+		} else if trafficCompare.CandidateModelServerName == trafficCompare.BaselineModelServerName {
+			allErrs = append(allErrs, field.Invalid(compareField.Child("candidateModelServerName"), trafficCompare.CandidateModelServerName, "candidate model server must be different from the baseline"))
+		}
+		if trafficCompare.SamplePercent != nil && (*trafficCompare.SamplePercent < 1 || *trafficCompare.SamplePercent > 100) {
+			allErrs = append(allErrs, field.Invalid(compareField.Child("samplePercent"), int64(*trafficCompare.SamplePercent), "sample percent must be in the range of [1, 100]"))
+		}
+	}
+
+	for i, rule := range modelRoute.Spec.Rules {
+		if rule != nil && rule.Mirror != nil {
+			allErrs = append(allErrs, validateMirror(rule, specField.Child("rules").Index(i).Child("mirror"))...)
+		}
+	}
+
+	if limit := modelRoute.Spec.Concurrency; limit != nil {
+		concurrencyField := specField.Child("concurrency")
+		if limit.MaxConcurrentRequests < 1 {
+			allErrs = append(allErrs, field.Invalid(concurrencyField.Child("maxConcurrentRequests"), int64(limit.MaxConcurrentRequests), "must be at least 1"))
+		}
+		if limit.MaxQueueWait != nil && limit.MaxQueueWait.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(concurrencyField.Child("maxQueueWait"), limit.MaxQueueWait.Duration.String(), "must not be negative"))
+		}
+		if limit.FallbackModel != "" && (limit.FallbackModel == modelRoute.Spec.ModelName || slices.Contains(modelRoute.Spec.LoraAdapters, limit.FallbackModel)) {
+			allErrs = append(allErrs, field.Invalid(concurrencyField.Child("fallbackModel"), limit.FallbackModel, "fallback model must be routed by another ModelRoute"))
+		}
+	}
+
+	if chain := modelRoute.Spec.Fallback; chain != nil {
+		allErrs = append(allErrs, validateFallback(modelRoute, chain, specField.Child("fallback"))...)
+	}
+
+	if guardrails := modelRoute.Spec.Guardrails; guardrails != nil {
+		allErrs = append(allErrs, validateGuardrails(guardrails, specField.Child("guardrails"))...)
+	}
+
+	var models []tenancy.Reference
+	if modelRoute.Spec.ModelName != "" {
+		models = append(models, tenancy.Reference{Path: specField.Child("modelName"), Model: modelRoute.Spec.ModelName})
+	}
+	for i, lora := range modelRoute.Spec.LoraAdapters {
+		if lora != "" {
+			models = append(models, tenancy.Reference{Path: specField.Child("loraAdapters").Index(i), Model: lora})
+		}
+	}
+	allErrs = append(allErrs, v.tenancy.ValidateModels(ctx, modelRoute.Namespace, models)...)
+
 	if len(allErrs) > 0 {
 		var messages []string
 		for _, err := range allErrs {
@@ -180,17 +203,122 @@ func (v *KthenaRouterValidator) validateModelRoute(modelRoute *networkingv1alpha
 	return true, ""
 }
 
-// validateModelServer validates the ModelServer resource
-func (v *KthenaRouterValidator) validateModelServer(*networkingv1alpha1.ModelServer) (bool, string) {
-	return true, ""
+// validateMirror checks that the requests of a rule are mirrored to a shadow model server which does not serve them.
+func validateMirror(rule *networkingv1alpha1.Rule, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	mirror := rule.Mirror
+	if mirror.ModelServerName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("modelServerName"), "shadow model server must be specified"))
+	}
+	for _, target := range rule.TargetModels {
+		if target != nil && target.ModelServerName == mirror.ModelServerName {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("modelServerName"), mirror.ModelServerName, "shadow model server must not be a target of the rule"))
+			break
+		}
+	}
+	if mirror.Percent != nil && (*mirror.Percent < 1 || *mirror.Percent > 100) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("percent"), int64(*mirror.Percent), "percent must be in the range of [1, 100]"))
+	}
+	if mirror.ComparePercent != nil && *mirror.ComparePercent > 100 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("comparePercent"), int64(*mirror.ComparePercent), "compare percent must be in the range of [0, 100]"))
+	}
+	return allErrs
 }
 
-func (v *KthenaRouterValidator) shutdown() {
-	klog.Info("shutting down webhook server")
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+// validateGuardrails checks that the guardrail filters can be built, so that a route is never left with
+// guardrails rejecting all its requests.
+func validateGuardrails(guardrails *networkingv1alpha1.Guardrails, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(guardrails.Filters) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("filters"), "at least one filter must be specified"))
+	}
+	names := make(map[string]bool, len(guardrails.Filters))
+	for i := range guardrails.Filters {
+		filter := &guardrails.Filters[i]
+		filterField := fldPath.Child("filters").Index(i)
+		if filter.Name == "" {
+			allErrs = append(allErrs, field.Required(filterField.Child("name"), "filter name must be specified"))
+		} else if names[filter.Name] {
+			allErrs = append(allErrs, field.Duplicate(filterField.Child("name"), filter.Name))
+		}
+		names[filter.Name] = true
 
-	if err := v.httpServer.Shutdown(ctx); err != nil {
-		klog.Errorf("failed to shutdown server: %v", err)
+		kinds := 0
+		for _, set := range []bool{filter.Keywords != nil, filter.Regex != nil, filter.HTTP != nil} {
+			if set {
+				kinds++
+			}
+		}
+		if kinds != 1 {
+			allErrs = append(allErrs, field.Invalid(filterField, filter.Name, "exactly one of keywords, regex and http must be set"))
+			continue
+		}
+		if _, err := guardrail.NewFilter(filter, nil); err != nil {
+			allErrs = append(allErrs, field.Invalid(filterField, filter.Name, err.Error()))
+		}
 	}
+	return allErrs
+}
+
+// validateFallback checks that the fallback targets are other models or absolute URLs of external APIs.
+func validateFallback(modelRoute *networkingv1alpha1.ModelRoute, chain *networkingv1alpha1.FallbackChain, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if chain.LatencyBudget != nil && chain.LatencyBudget.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("latencyBudget"), chain.LatencyBudget.Duration.String(), "must be positive"))
+	}
+	if len(chain.Targets) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("targets"), "at least one target must be specified"))
+	}
+	for i, target := range chain.Targets {
+		targetField := fldPath.Child("targets").Index(i)
+		if (target.ModelName == "") == (target.External == nil) {
+			allErrs = append(allErrs, field.Invalid(targetField, target.ModelName, "exactly one of modelName and external must be set"))
+			continue
+		}
+		if target.ModelName != "" && (target.ModelName == modelRoute.Spec.ModelName || slices.Contains(modelRoute.Spec.LoraAdapters, target.ModelName)) {
+			allErrs = append(allErrs, field.Invalid(targetField.Child("modelName"), target.ModelName, "fallback model must be routed by another ModelRoute"))
+		}
+		if target.External != nil {
+			u, err := url.Parse(target.External.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(targetField.Child("external", "url"), target.External.URL, "must be an absolute http or https url"))
+			}
+			if ref := target.External.APIKeySecretRef; ref != nil && (ref.Name == "" || ref.Key == "") {
+				allErrs = append(allErrs, field.Required(targetField.Child("external", "apiKeySecretRef"), "name and key must be specified"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateModelServer validates the ModelServer resource
+func (v *KthenaRouterValidator) validateModelServer(ctx context.Context, modelServer *networkingv1alpha1.ModelServer) (bool, string) {
+	var allErrs field.ErrorList
+	if modelServer.Spec.Model != nil && *modelServer.Spec.Model != "" {
+		allErrs = append(allErrs, v.tenancy.ValidateModels(ctx, modelServer.Namespace, []tenancy.Reference{
+			{Path: field.NewPath("spec").Child("model"), Model: *modelServer.Spec.Model},
+		})...)
+	}
+	if modelServer.Spec.WorkloadSelector != nil && modelServer.Spec.WorkloadSelector.PDGroup != nil &&
+		!features.DefaultFeatureGate.Enabled(features.PDDisaggregation) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("workloadSelector", "pdGroup"),
+			fmt.Sprintf("feature gate %s is disabled", features.PDDisaggregation)))
+	}
+	if policy := modelServer.Spec.SchedulingPolicy; policy != nil {
+		fldPath := field.NewPath("spec").Child("schedulingPolicy", "scoreWeights")
+		for i, weight := range policy.ScoreWeights {
+			if !scheduler.IsScorePlugin(weight.Plugin) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("plugin"), weight.Plugin, "unknown score plugin"))
+			}
+		}
+	}
+
+	if len(allErrs) > 0 {
+		var messages []string
+		for _, err := range allErrs {
+			messages = append(messages, fmt.Sprintf("  - %s", err.Error()))
+		}
+		return false, fmt.Sprintf("validation failed: %s", strings.Join(messages, ""))
+	}
+	return true, ""
 }
@@ -17,13 +17,19 @@ limitations under the License.
 package webhook
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes/fake"
 
 	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/features"
 )
 
 func TestValidateModelRoute(t *testing.T) {
@@ -255,15 +261,258 @@ func TestValidateModelRoute(t *testing.T) {
 			expectValid:    false,
 			expectedReason: "validation failed:   - spec: Required value: either modelName or loraAdapters must be specified",
 		},
+		{
+			name: "valid model route with traffic compare",
+			modelRoute: &networkingv1alpha1.ModelRoute{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "networking.serving.volcano.sh/v1alpha1",
+					Kind:       "ModelRoute",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-route",
+					Namespace: "default",
+				},
+				Spec: networkingv1alpha1.ModelRouteSpec{
+					ModelName: "test-model",
+					Rules: []*networkingv1alpha1.Rule{
+						{
+							Name: "test-rule",
+							TargetModels: []*networkingv1alpha1.TargetModel{
+								{
+									ModelServerName: "test-server",
+								},
+							},
+						},
+					},
+					TrafficCompare: &networkingv1alpha1.TrafficCompare{
+						BaselineModelServerName:  "test-server",
+						CandidateModelServerName: "test-server-candidate",
+					},
+				},
+			},
+			expectValid: true,
+		},
+		{
+			name: "invalid model route - traffic compare against the baseline itself",
+			modelRoute: &networkingv1alpha1.ModelRoute{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "networking.serving.volcano.sh/v1alpha1",
+					Kind:       "ModelRoute",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-route",
+					Namespace: "default",
+				},
+				Spec: networkingv1alpha1.ModelRouteSpec{
+					ModelName: "test-model",
+					Rules: []*networkingv1alpha1.Rule{
+						{
+							Name: "test-rule",
+							TargetModels: []*networkingv1alpha1.TargetModel{
+								{
+									ModelServerName: "test-server",
+								},
+							},
+						},
+					},
+					TrafficCompare: &networkingv1alpha1.TrafficCompare{
+						BaselineModelServerName:  "test-server",
+						CandidateModelServerName: "test-server",
+					},
+				},
+			},
+			expectValid:    false,
+			expectedReason: "validation failed:   - spec.trafficCompare.candidateModelServerName: Invalid value: \"test-server\": candidate model server must be different from the baseline",
+		},
+		{
+			name: "invalid model route - traffic compare sample percent out of range",
+			modelRoute: &networkingv1alpha1.ModelRoute{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "networking.serving.volcano.sh/v1alpha1",
+					Kind:       "ModelRoute",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-route",
+					Namespace: "default",
+				},
+				Spec: networkingv1alpha1.ModelRouteSpec{
+					ModelName: "test-model",
+					Rules: []*networkingv1alpha1.Rule{
+						{
+							Name: "test-rule",
+							TargetModels: []*networkingv1alpha1.TargetModel{
+								{
+									ModelServerName: "test-server",
+								},
+							},
+						},
+					},
+					TrafficCompare: &networkingv1alpha1.TrafficCompare{
+						BaselineModelServerName:  "test-server",
+						CandidateModelServerName: "test-server-candidate",
+						SamplePercent:            func(p uint32) *uint32 { return &p }(0),
+					},
+				},
+			},
+			expectValid:    false,
+			expectedReason: "validation failed:   - spec.trafficCompare.samplePercent: Invalid value: 0: sample percent must be in the range of [1, 100]",
+		},
+		{
+			name: "valid model route with mirror",
+			modelRoute: &networkingv1alpha1.ModelRoute{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "networking.serving.volcano.sh/v1alpha1",
+					Kind:       "ModelRoute",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-route",
+					Namespace: "default",
+				},
+				Spec: networkingv1alpha1.ModelRouteSpec{
+					ModelName: "test-model",
+					Rules: []*networkingv1alpha1.Rule{
+						{
+							Name: "test-rule",
+							TargetModels: []*networkingv1alpha1.TargetModel{
+								{
+									ModelServerName: "test-server",
+								},
+							},
+							Mirror: &networkingv1alpha1.TrafficMirror{ModelServerName: "test-server-shadow"},
+						},
+					},
+				},
+			},
+			expectValid: true,
+		},
+		{
+			name: "invalid model route - mirror to a target of the rule",
+			modelRoute: &networkingv1alpha1.ModelRoute{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "networking.serving.volcano.sh/v1alpha1",
+					Kind:       "ModelRoute",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-route",
+					Namespace: "default",
+				},
+				Spec: networkingv1alpha1.ModelRouteSpec{
+					ModelName: "test-model",
+					Rules: []*networkingv1alpha1.Rule{
+						{
+							Name: "test-rule",
+							TargetModels: []*networkingv1alpha1.TargetModel{
+								{
+									ModelServerName: "test-server",
+								},
+							},
+							Mirror: &networkingv1alpha1.TrafficMirror{ModelServerName: "test-server"},
+						},
+					},
+				},
+			},
+			expectValid:    false,
+			expectedReason: "validation failed:   - spec.rules[0].mirror.modelServerName: Invalid value: \"test-server\": shadow model server must not be a target of the rule",
+		},
+		{
+			name: "invalid model route - mirror percent out of range",
+			modelRoute: &networkingv1alpha1.ModelRoute{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "networking.serving.volcano.sh/v1alpha1",
+					Kind:       "ModelRoute",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-route",
+					Namespace: "default",
+				},
+				Spec: networkingv1alpha1.ModelRouteSpec{
+					ModelName: "test-model",
+					Rules: []*networkingv1alpha1.Rule{
+						{
+							Name: "test-rule",
+							TargetModels: []*networkingv1alpha1.TargetModel{
+								{
+									ModelServerName: "test-server",
+								},
+							},
+							Mirror: &networkingv1alpha1.TrafficMirror{ModelServerName: "test-server-shadow", Percent: func(p uint32) *uint32 { return &p }(101)},
+						},
+					},
+				},
+			},
+			expectValid:    false,
+			expectedReason: "validation failed:   - spec.rules[0].mirror.percent: Invalid value: 101: percent must be in the range of [1, 100]",
+		},
+		{
+			name: "invalid mirror compare percent",
+			modelRoute: &networkingv1alpha1.ModelRoute{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "networking.serving.volcano.sh/v1alpha1",
+					Kind:       "ModelRoute",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-route",
+					Namespace: "default",
+				},
+				Spec: networkingv1alpha1.ModelRouteSpec{
+					ModelName: "test-model",
+					Rules: []*networkingv1alpha1.Rule{
+						{
+							Name: "test-rule",
+							TargetModels: []*networkingv1alpha1.TargetModel{
+								{
+									ModelServerName: "test-server",
+								},
+							},
+							Mirror: &networkingv1alpha1.TrafficMirror{ModelServerName: "test-server-shadow", ComparePercent: func(p uint32) *uint32 { return &p }(200)},
+						},
+					},
+				},
+			},
+			expectValid:    false,
+			expectedReason: "validation failed:   - spec.rules[0].mirror.comparePercent: Invalid value: 200: compare percent must be in the range of [0, 100]",
+		},
+		{
+			name: "invalid concurrency fallback to the same model",
+			modelRoute: &networkingv1alpha1.ModelRoute{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "networking.serving.volcano.sh/v1alpha1",
+					Kind:       "ModelRoute",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-route",
+					Namespace: "default",
+				},
+				Spec: networkingv1alpha1.ModelRouteSpec{
+					ModelName: "test-model",
+					Rules: []*networkingv1alpha1.Rule{
+						{
+							Name: "test-rule",
+							TargetModels: []*networkingv1alpha1.TargetModel{
+								{
+									ModelServerName: "test-server",
+								},
+							},
+						},
+					},
+					Concurrency: &networkingv1alpha1.ConcurrencyLimit{
+						MaxConcurrentRequests: 8,
+						FallbackModel:         "test-model",
+					},
+				},
+			},
+			expectValid:    false,
+			expectedReason: "validation failed:   - spec.concurrency.fallbackModel: Invalid value: \"test-model\": fallback model must be routed by another ModelRoute",
+		},
 	}
 
 	// Create a validator instance
 	kubeClient := fake.NewSimpleClientset()
-	validator := NewKthenaRouterValidator(kubeClient, 8080)
+	validator := NewKthenaRouterValidator(kubeClient, nil)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			allowed, reason := validator.validateModelRoute(tt.modelRoute)
+			allowed, reason := validator.validateModelRoute(context.Background(), tt.modelRoute)
 
 			assert.Equal(t, tt.expectValid, allowed, "Expected validation result should match")
 
@@ -275,3 +524,113 @@ func TestValidateModelRoute(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateGuardrails(t *testing.T) {
+	fldPath := field.NewPath("spec", "guardrails")
+	valid := &networkingv1alpha1.Guardrails{Filters: []networkingv1alpha1.GuardrailFilter{
+		{Name: "keywords", Keywords: &networkingv1alpha1.KeywordGuardrail{Keywords: []string{"ignore previous instructions"}}},
+		{Name: "secrets", Regex: &networkingv1alpha1.RegexGuardrail{Patterns: []string{`sk-[A-Za-z0-9]{20,}`}}},
+		{Name: "classifier", HTTP: &networkingv1alpha1.HTTPGuardrail{URL: "http://classifier.default.svc/check"}},
+	}}
+	assert.Empty(t, validateGuardrails(valid, fldPath))
+
+	invalid := &networkingv1alpha1.Guardrails{Filters: []networkingv1alpha1.GuardrailFilter{
+		{Name: "regex", Regex: &networkingv1alpha1.RegexGuardrail{Patterns: []string{"("}}},
+		{Name: "regex", Keywords: &networkingv1alpha1.KeywordGuardrail{Keywords: []string{"secret"}}},
+		{Name: "both", Keywords: &networkingv1alpha1.KeywordGuardrail{Keywords: []string{"secret"}}, Regex: &networkingv1alpha1.RegexGuardrail{Patterns: []string{"secret"}}},
+		{Name: "relative", HTTP: &networkingv1alpha1.HTTPGuardrail{URL: "/check"}},
+	}}
+	errs := validateGuardrails(invalid, fldPath)
+	require.Len(t, errs, 4)
+	assert.Equal(t, "spec.guardrails.filters[0]", errs[0].Field)
+	assert.Contains(t, errs[0].Detail, "invalid pattern")
+	assert.Equal(t, field.ErrorTypeDuplicate, errs[1].Type)
+	assert.Contains(t, errs[2].Detail, "exactly one of keywords, regex and http must be set")
+	assert.Contains(t, errs[3].Detail, "must be an absolute http or https url")
+}
+
+func TestValidateModelServerPDGroup(t *testing.T) {
+	defer func() {
+		require.NoError(t, features.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(features.PDDisaggregation): true}))
+	}()
+	modelServer := &networkingv1alpha1.ModelServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-server", Namespace: "default"},
+		Spec: networkingv1alpha1.ModelServerSpec{
+			WorkloadSelector: &networkingv1alpha1.WorkloadSelector{
+				MatchLabels: map[string]string{"app": "llama"},
+				PDGroup: &networkingv1alpha1.PDGroup{
+					GroupKey:      "group",
+					PrefillLabels: map[string]string{"role": "prefill"},
+					DecodeLabels:  map[string]string{"role": "decode"},
+				},
+			},
+		},
+	}
+	validator := NewKthenaRouterValidator(fake.NewSimpleClientset(), nil)
+
+	allowed, reason := validator.validateModelServer(context.Background(), modelServer)
+	assert.True(t, allowed, reason)
+
+	require.NoError(t, features.DefaultMutableFeatureGate.Set("PDDisaggregation=false"))
+	allowed, reason = validator.validateModelServer(context.Background(), modelServer)
+	assert.False(t, allowed)
+	assert.Equal(t, "validation failed:   - spec.workloadSelector.pdGroup: Forbidden: feature gate PDDisaggregation is disabled", reason)
+}
+
+func TestValidateModelServerScoreWeights(t *testing.T) {
+	modelServer := &networkingv1alpha1.ModelServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-server", Namespace: "default"},
+		Spec: networkingv1alpha1.ModelServerSpec{
+			SchedulingPolicy: &networkingv1alpha1.SchedulingPolicy{
+				ScoreWeights: []networkingv1alpha1.ScoreWeight{
+					{Plugin: "kvcache-aware", Weight: 60},
+					{Plugin: "least-request", Weight: 40},
+				},
+			},
+		},
+	}
+	validator := NewKthenaRouterValidator(fake.NewSimpleClientset(), nil)
+
+	allowed, reason := validator.validateModelServer(context.Background(), modelServer)
+	assert.True(t, allowed, reason)
+
+	modelServer.Spec.SchedulingPolicy.ScoreWeights[1].Plugin = "least-requests"
+	allowed, reason = validator.validateModelServer(context.Background(), modelServer)
+	assert.False(t, allowed)
+	assert.Equal(t, `validation failed:   - spec.schedulingPolicy.scoreWeights[1].plugin: Invalid value: "least-requests": unknown score plugin`, reason)
+}
+
+func TestValidateFallback(t *testing.T) {
+	fldPath := field.NewPath("spec", "fallback")
+	modelRoute := &networkingv1alpha1.ModelRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "llama-70b", Namespace: "default"},
+		Spec:       networkingv1alpha1.ModelRouteSpec{ModelName: "llama-70b"},
+	}
+	valid := &networkingv1alpha1.FallbackChain{
+		LatencyBudget: &metav1.Duration{Duration: 2 * time.Second},
+		Targets: []networkingv1alpha1.FallbackTarget{
+			{ModelName: "llama-8b"},
+			{External: &networkingv1alpha1.ExternalModel{
+				URL:             "https://api.example.com",
+				Model:           "gpt-4o-mini",
+				APIKeySecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "openai"}, Key: "api-key"},
+			}},
+		},
+	}
+	assert.Empty(t, validateFallback(modelRoute, valid, fldPath))
+
+	invalid := &networkingv1alpha1.FallbackChain{
+		LatencyBudget: &metav1.Duration{},
+		Targets: []networkingv1alpha1.FallbackTarget{
+			{ModelName: "llama-70b"},
+			{ModelName: "llama-8b", External: &networkingv1alpha1.ExternalModel{URL: "https://api.example.com"}},
+			{External: &networkingv1alpha1.ExternalModel{URL: "/v1"}},
+		},
+	}
+	errs := validateFallback(modelRoute, invalid, fldPath)
+	require.Len(t, errs, 4)
+	assert.Equal(t, "spec.fallback.latencyBudget", errs[0].Field)
+	assert.Equal(t, "spec.fallback.targets[0].modelName", errs[1].Field)
+	assert.Contains(t, errs[2].Detail, "exactly one of modelName and external must be set")
+	assert.Equal(t, "spec.fallback.targets[2].external.url", errs[3].Field)
+}
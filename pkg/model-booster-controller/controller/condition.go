@@ -20,6 +20,7 @@ import (
 	"context"
 
 	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
@@ -30,6 +31,9 @@ const (
 	ModelActiveReason     = "ModelAvailable"
 	ModelProcessingReason = "ModelProcessing"
 	ModelFailedReason     = "ModelAbnormal"
+
+	ProvenanceVerifiedReason   = "ProvenanceVerified"
+	ProvenanceUnverifiedReason = "ProvenanceUnverified"
 )
 
 // setModelInitCondition sets model condition to initialized
@@ -58,6 +62,7 @@ func (mc *ModelBoosterController) setModelProcessingCondition(ctx context.Contex
 func (mc *ModelBoosterController) setModelFailedCondition(ctx context.Context, model *workloadv1alpha1.ModelBooster, err error) {
 	meta.SetStatusCondition(&model.Status.Conditions, newCondition(string(workloadv1alpha1.ModelStatusConditionTypeFailed),
 		metav1.ConditionTrue, ModelFailedReason, err.Error()))
+	mc.recorder.Event(model, corev1.EventTypeWarning, ModelFailedReason, err.Error())
 	if err := mc.updateModelBoosterStatus(ctx, model); err != nil {
 		klog.Errorf("update ModelBooster status failed: %v", err)
 	}
@@ -0,0 +1,142 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"time"
+
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// downloadProgress is what the model downloader reports in the ModelDownloadProgressAnnotationKey annotation of its pod.
+type downloadProgress struct {
+	Phase           workload.ModelDownloadPhase `json:"phase"`
+	DownloadedBytes int64                       `json:"downloadedBytes"`
+	TotalBytes      int64                       `json:"totalBytes,omitempty"`
+	ETASeconds      *int64                      `json:"etaSeconds,omitempty"`
+	UpdateTime      *metav1.Time                `json:"updateTime,omitempty"`
+}
+
+// phaseOrder orders phases from the least to the most advanced, a failed download is always reported.
+var phaseOrder = map[workload.ModelDownloadPhase]int{
+	workload.ModelDownloadPhaseFailed:      0,
+	workload.ModelDownloadPhaseDownloading: 1,
+	workload.ModelDownloadPhaseVerifying:   2,
+	workload.ModelDownloadPhaseCompleted:   3,
+}
+
+// lessAdvanced returns true if progress a is behind progress b.
+func (a *downloadProgress) lessAdvanced(b *downloadProgress) bool {
+	if phaseOrder[a.Phase] != phaseOrder[b.Phase] {
+		return phaseOrder[a.Phase] < phaseOrder[b.Phase]
+	}
+	if a.TotalBytes > 0 && b.TotalBytes > 0 {
+		return float64(a.DownloadedBytes)/float64(a.TotalBytes) < float64(b.DownloadedBytes)/float64(b.TotalBytes)
+	}
+	return a.DownloadedBytes < b.DownloadedBytes
+}
+
+func (a *downloadProgress) toStatus() *workload.ModelDownloadStatus {
+	status := &workload.ModelDownloadStatus{
+		Phase:           a.Phase,
+		DownloadedBytes: a.DownloadedBytes,
+		TotalBytes:      a.TotalBytes,
+		LastUpdateTime:  a.UpdateTime,
+	}
+	if a.TotalBytes > 0 {
+		status.Percentage = int32(min(100, a.DownloadedBytes*100/a.TotalBytes))
+	}
+	if a.ETASeconds != nil && a.Phase == workload.ModelDownloadPhaseDownloading {
+		status.EstimatedTimeRemaining = &metav1.Duration{Duration: time.Duration(*a.ETASeconds) * time.Second}
+	}
+	return status
+}
+
+// getModelDownloadStatus aggregates the download progress reported by the pods of a ModelServing.
+// The least advanced download is reported since the backend is only ready once every pod has the model.
+// Returns nil if no pod reported progress.
+func (mc *ModelBoosterController) getModelDownloadStatus(modelServing *workload.ModelServing) *workload.ModelDownloadStatus {
+	pods, err := mc.podsLister.Pods(modelServing.Namespace).List(labels.SelectorFromSet(labels.Set{
+		workload.ModelServingNameLabelKey: modelServing.Name,
+	}))
+	if err != nil {
+		klog.Errorf("failed to list pods of ModelServing %s: %v", klog.KObj(modelServing), err)
+		return nil
+	}
+	var slowest *downloadProgress
+	for _, pod := range pods {
+		progress := parseDownloadProgress(pod)
+		if progress == nil {
+			continue
+		}
+		if slowest == nil || progress.lessAdvanced(slowest) {
+			slowest = progress
+		}
+	}
+	if slowest == nil {
+		return nil
+	}
+	return slowest.toStatus()
+}
+
+func parseDownloadProgress(pod *corev1.Pod) *downloadProgress {
+	value, ok := pod.Annotations[workload.ModelDownloadProgressAnnotationKey]
+	if !ok {
+		return nil
+	}
+	progress := &downloadProgress{}
+	if err := json.Unmarshal([]byte(value), progress); err != nil {
+		klog.V(4).Infof("ignore invalid download progress of pod %s: %v", klog.KObj(pod), err)
+		return nil
+	}
+	if _, ok := phaseOrder[progress.Phase]; !ok {
+		klog.V(4).Infof("ignore unknown download phase %q of pod %s", progress.Phase, klog.KObj(pod))
+		return nil
+	}
+	return progress
+}
+
+// triggerModelByPod reconciles the ModelBooster owning a pod when the pod reports new download progress.
+func (mc *ModelBoosterController) triggerModelByPod(old any, new any) {
+	oldPod, ok := old.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	newPod, ok := new.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	if oldPod.Annotations[workload.ModelDownloadProgressAnnotationKey] == newPod.Annotations[workload.ModelDownloadProgressAnnotationKey] {
+		return
+	}
+	modelServingName, ok := newPod.Labels[workload.ModelServingNameLabelKey]
+	if !ok {
+		return
+	}
+	modelServing, err := mc.modelServingLister.ModelServings(newPod.Namespace).Get(modelServingName)
+	if err != nil || len(modelServing.OwnerReferences) == 0 {
+		return
+	}
+	if model, err := mc.modelBoosterLister.ModelBoosters(modelServing.Namespace).Get(modelServing.OwnerReferences[0].Name); err == nil {
+		mc.enqueueModelBooster(model)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newDownloaderPod(name, progress string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{workload.ModelServingNameLabelKey: "test-model-backend1"},
+		},
+	}
+	if progress != "" {
+		pod.Annotations = map[string]string{workload.ModelDownloadProgressAnnotationKey: progress}
+	}
+	return pod
+}
+
+func TestGetModelDownloadStatus(t *testing.T) {
+	modelServing := &workload.ModelServing{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model-backend1", Namespace: "default"},
+	}
+	tests := []struct {
+		name     string
+		pods     []*corev1.Pod
+		expected *workload.ModelDownloadStatus
+	}{
+		{
+			name:     "no progress reported",
+			pods:     []*corev1.Pod{newDownloaderPod("pod-0", ""), newDownloaderPod("pod-1", "not json")},
+			expected: nil,
+		},
+		{
+			name: "slowest download is reported",
+			pods: []*corev1.Pod{
+				newDownloaderPod("pod-0", `{"phase":"Downloading","downloadedBytes":750,"totalBytes":1000,"etaSeconds":10}`),
+				newDownloaderPod("pod-1", `{"phase":"Downloading","downloadedBytes":250,"totalBytes":1000,"etaSeconds":90,"updateTime":"2025-01-01T00:00:00Z"}`),
+				newDownloaderPod("pod-2", `{"phase":"Completed","downloadedBytes":1000,"totalBytes":1000}`),
+			},
+			expected: &workload.ModelDownloadStatus{
+				Phase:                  workload.ModelDownloadPhaseDownloading,
+				DownloadedBytes:        250,
+				TotalBytes:             1000,
+				Percentage:             25,
+				EstimatedTimeRemaining: &metav1.Duration{Duration: 90 * time.Second},
+				LastUpdateTime:         &metav1.Time{Time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "failed download is reported",
+			pods: []*corev1.Pod{
+				newDownloaderPod("pod-0", `{"phase":"Downloading","downloadedBytes":10}`),
+				newDownloaderPod("pod-1", `{"phase":"Failed","downloadedBytes":900,"totalBytes":1000,"etaSeconds":5}`),
+			},
+			expected: &workload.ModelDownloadStatus{
+				Phase:           workload.ModelDownloadPhaseFailed,
+				DownloadedBytes: 900,
+				TotalBytes:      1000,
+				Percentage:      90,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, pod := range tt.pods {
+				assert.NoError(t, indexer.Add(pod))
+			}
+			mc := &ModelBoosterController{podsLister: listerv1.NewPodLister(indexer)}
+			got := mc.getModelDownloadStatus(modelServing)
+			if tt.expected == nil {
+				assert.Nil(t, got)
+				return
+			}
+			assert.NotNil(t, got)
+			assert.Equal(t, tt.expected.Phase, got.Phase)
+			assert.Equal(t, tt.expected.DownloadedBytes, got.DownloadedBytes)
+			assert.Equal(t, tt.expected.TotalBytes, got.TotalBytes)
+			assert.Equal(t, tt.expected.Percentage, got.Percentage)
+			assert.Equal(t, tt.expected.EstimatedTimeRemaining, got.EstimatedTimeRemaining)
+			if tt.expected.LastUpdateTime != nil {
+				assert.True(t, tt.expected.LastUpdateTime.Equal(got.LastUpdateTime))
+			}
+		})
+	}
+}
@@ -0,0 +1,493 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
+	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
+	workloadLister "github.com/volcano-sh/kthena/client-go/listers/workload/v1alpha1"
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/controller/metrics"
+	"github.com/volcano-sh/kthena/pkg/model-booster-controller/convert"
+	"github.com/volcano-sh/kthena/pkg/model-booster-controller/env"
+	"github.com/volcano-sh/kthena/pkg/model-booster-controller/utils"
+	icUtils "github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
+)
+
+const (
+	loraAdapterControllerName = "lora-adapter"
+
+	// LoraAdapterFinalizer makes sure a LoraAdapter is unloaded from the pods of its base model before it is deleted.
+	LoraAdapterFinalizer = workload.GroupName + "/lora-adapter"
+
+	// loraAdapterLoadWorkers bounds the pods an adapter is loaded on at the same time, loading can take minutes
+	loraAdapterLoadWorkers = 16
+
+	reasonAdapterLoaded     = "Loaded"
+	reasonAdapterLoading    = "Loading"
+	reasonNoReplicas        = "NoReplicas"
+	reasonBaseModelNotFound = "BaseModelNotFound"
+	reasonNoEligibleBackend = "NoEligibleBackend"
+)
+
+// LoraAdapterController loads the LoraAdapters dynamically on the running pods of their base models,
+// through the LoRA API of the runtime sidecar.
+type LoraAdapterController struct {
+	client     clientset.Interface
+	httpClient *http.Client
+
+	syncHandler           func(ctx context.Context, key string) error
+	loraAdapterLister     workloadLister.LoraAdapterLister
+	loraAdapterInformer   cache.SharedIndexInformer
+	modelBoosterLister    workloadLister.ModelBoosterLister
+	modelBoosterInformer  cache.SharedIndexInformer
+	podsLister            listerv1.PodLister
+	podsInformer          cache.SharedIndexInformer
+	kthenaInformerFactory informersv1alpha1.SharedInformerFactory
+	kubeInformerFactory   informers.SharedInformerFactory
+	workQueue             workqueue.TypedRateLimitingInterface[any]
+}
+
+func NewLoraAdapterController(kubeClient kubernetes.Interface, client clientset.Interface) *LoraAdapterController {
+	kthenaInformerFactory := informersv1alpha1.NewSharedInformerFactory(client, 0)
+	loraAdapterInformer := kthenaInformerFactory.Workload().V1alpha1().LoraAdapters()
+	modelBoosterInformer := kthenaInformerFactory.Workload().V1alpha1().ModelBoosters()
+
+	// Only watch the pods of ModelServings
+	kubeInformerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0,
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = workload.ModelServingNameLabelKey
+		}))
+	podsInformer := kubeInformerFactory.Core().V1().Pods()
+
+	c := &LoraAdapterController{
+		client: client,
+		// Loading an adapter includes downloading it
+		httpClient:            &http.Client{Timeout: 5 * time.Minute},
+		loraAdapterLister:     loraAdapterInformer.Lister(),
+		loraAdapterInformer:   loraAdapterInformer.Informer(),
+		modelBoosterLister:    modelBoosterInformer.Lister(),
+		modelBoosterInformer:  modelBoosterInformer.Informer(),
+		podsLister:            podsInformer.Lister(),
+		podsInformer:          podsInformer.Informer(),
+		kthenaInformerFactory: kthenaInformerFactory,
+		kubeInformerFactory:   kubeInformerFactory,
+		workQueue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[any](),
+			workqueue.TypedRateLimitingQueueConfig[any]{Name: loraAdapterControllerName}),
+	}
+	c.syncHandler = c.reconcile
+
+	_, err := c.loraAdapterInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueLoraAdapter,
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueLoraAdapter(newObj)
+		},
+	})
+	if err != nil {
+		klog.Fatal("Unable to add LoraAdapter event handler")
+		return nil
+	}
+	_, err = c.modelBoosterInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueLoraAdaptersOfModel,
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueLoraAdaptersOfModel(newObj)
+		},
+	})
+	if err != nil {
+		klog.Fatal("Unable to add ModelBooster event handler")
+		return nil
+	}
+	_, err = c.podsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: c.updatePod,
+	})
+	if err != nil {
+		klog.Fatal("Unable to add pod event handler")
+		return nil
+	}
+	return c
+}
+
+func (c *LoraAdapterController) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.workQueue.ShutDown()
+
+	c.kthenaInformerFactory.Start(ctx.Done())
+	c.kubeInformerFactory.Start(ctx.Done())
+
+	metrics.WaitForCacheSync(loraAdapterControllerName, ctx.Done(),
+		c.loraAdapterInformer.HasSynced,
+		c.modelBoosterInformer.HasSynced,
+		c.podsInformer.HasSynced,
+	)
+
+	klog.Info("start lora adapter controller")
+	for i := 0; i < workers; i++ {
+		go c.worker(ctx)
+	}
+	<-ctx.Done()
+	klog.Info("shut down lora adapter controller")
+}
+
+func (c *LoraAdapterController) worker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *LoraAdapterController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := c.workQueue.Get()
+	if quit {
+		return false
+	}
+	defer c.workQueue.Done(key)
+
+	start := time.Now()
+	err := c.syncHandler(ctx, key.(string))
+	metrics.ObserveReconcile(loraAdapterControllerName, start, err)
+	if err == nil {
+		c.workQueue.Forget(key)
+		return true
+	}
+	utilruntime.HandleError(fmt.Errorf("sync %q failed with %v", key, err))
+	c.workQueue.AddRateLimited(key)
+	return true
+}
+
+func (c *LoraAdapterController) enqueueLoraAdapter(obj interface{}) {
+	if key, err := cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+	} else {
+		c.workQueue.Add(key)
+	}
+}
+
+// enqueueLoraAdaptersOfModel enqueues the LoraAdapters of a base model, whose backends may have changed
+func (c *LoraAdapterController) enqueueLoraAdaptersOfModel(obj interface{}) {
+	model, ok := obj.(*workload.ModelBooster)
+	if !ok {
+		return
+	}
+	adapters, err := c.loraAdapterLister.LoraAdapters(model.Namespace).List(labels.Everything())
+	if err != nil {
+		return
+	}
+	for _, adapter := range adapters {
+		if adapter.Spec.BaseModel == model.Name {
+			c.enqueueLoraAdapter(adapter)
+		}
+	}
+}
+
+// updatePod enqueues the LoraAdapters to load on a pod when it becomes ready, e.g. when it is created or restarted.
+func (c *LoraAdapterController) updatePod(oldObj, newObj interface{}) {
+	oldPod, ok := oldObj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	newPod, ok := newObj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	if icUtils.IsPodRunningAndReady(oldPod) || !icUtils.IsPodRunningAndReady(newPod) {
+		return
+	}
+	modelServingName := newPod.Labels[workload.ModelServingNameLabelKey]
+	adapters, err := c.loraAdapterLister.LoraAdapters(newPod.Namespace).List(labels.Everything())
+	if err != nil {
+		return
+	}
+	for _, adapter := range adapters {
+		model, err := c.modelBoosterLister.ModelBoosters(adapter.Namespace).Get(adapter.Spec.BaseModel)
+		if err != nil {
+			continue
+		}
+		for _, backend := range loraAdapterBackends(adapter, model) {
+			if utils.GetBackendResourceName(model.Name, backend.Name) == modelServingName {
+				c.enqueueLoraAdapter(adapter)
+				break
+			}
+		}
+	}
+}
+
+// reconcile loads the LoraAdapter on the ready pods of its base model, and unloads it when it is deleted.
+func (c *LoraAdapterController) reconcile(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return fmt.Errorf("invalid resource key: %s", err)
+	}
+	adapter, err := c.loraAdapterLister.LoraAdapters(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if adapter.DeletionTimestamp != nil {
+		return c.finalize(ctx, adapter)
+	}
+	if !slices.Contains(adapter.Finalizers, LoraAdapterFinalizer) {
+		adapterCopy := adapter.DeepCopy()
+		adapterCopy.Finalizers = append(adapterCopy.Finalizers, LoraAdapterFinalizer)
+		// The update triggers another reconciliation
+		_, err := c.client.WorkloadV1alpha1().LoraAdapters(namespace).Update(ctx, adapterCopy, metav1.UpdateOptions{})
+		return err
+	}
+
+	newStatus := adapter.Status.DeepCopy()
+	newStatus.ObservedGeneration = adapter.Generation
+	model, err := c.modelBoosterLister.ModelBoosters(namespace).Get(adapter.Spec.BaseModel)
+	if apierrors.IsNotFound(err) {
+		setLoraAdapterReadyCondition(newStatus, 0, 0, reasonBaseModelNotFound,
+			fmt.Sprintf("ModelBooster %s is not found", adapter.Spec.BaseModel))
+		return c.updateStatus(ctx, adapter, newStatus)
+	} else if err != nil {
+		return err
+	}
+	backends := loraAdapterBackends(adapter, model)
+	if len(backends) == 0 {
+		setLoraAdapterReadyCondition(newStatus, 0, 0, reasonNoEligibleBackend,
+			fmt.Sprintf("ModelBooster %s has no vLLM backend with VLLM_ALLOW_RUNTIME_LORA_UPDATING enabled", model.Name))
+		return c.updateStatus(ctx, adapter, newStatus)
+	}
+
+	// An adapter loaded from another artifact must be unloaded first
+	reload := adapter.Status.ArtifactURL != "" && adapter.Status.ArtifactURL != adapter.Spec.ArtifactURL
+	var targets []loraAdapterTarget
+	for _, backend := range backends {
+		pods, err := c.getReadyPods(model, backend)
+		if err != nil {
+			return err
+		}
+		outputDir := convert.GetCachePath(backend.CacheURI) + convert.GetMountPath(adapter.Spec.ArtifactURL)
+		for _, pod := range pods {
+			targets = append(targets, loraAdapterTarget{pod: pod, runtimeURL: getRuntimeURL(pod, backend), outputDir: outputDir})
+		}
+	}
+
+	// The pods load the adapter in parallel, each of them downloads it first
+	loadErrs := make([]error, len(targets))
+	workqueue.ParallelizeUntil(ctx, loraAdapterLoadWorkers, len(targets), func(i int) {
+		target := targets[i]
+		if err := c.ensureLoaded(ctx, target.runtimeURL, adapter, target.outputDir, reload); err != nil {
+			loadErrs[i] = fmt.Errorf("pod %s: %v", target.pod.Name, err)
+		}
+	})
+	var errs []error
+	for _, err := range loadErrs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	replicas := int32(len(targets))
+	loaded := replicas - int32(len(errs))
+
+	newStatus.Replicas = replicas
+	newStatus.LoadedReplicas = loaded
+	switch {
+	case replicas == 0:
+		setLoraAdapterReadyCondition(newStatus, replicas, loaded, reasonNoReplicas,
+			fmt.Sprintf("ModelBooster %s has no ready replicas", model.Name))
+	case loaded < replicas:
+		setLoraAdapterReadyCondition(newStatus, replicas, loaded, reasonAdapterLoading,
+			fmt.Sprintf("Loaded on %d/%d replicas: %v", loaded, replicas, errs))
+	default:
+		newStatus.ArtifactURL = adapter.Spec.ArtifactURL
+		setLoraAdapterReadyCondition(newStatus, replicas, loaded, reasonAdapterLoaded,
+			fmt.Sprintf("Loaded on %d/%d replicas", loaded, replicas))
+	}
+	if err := c.updateStatus(ctx, adapter, newStatus); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to load LoRA adapter %s on %d replicas: %v", key, len(errs), errs)
+	}
+	return nil
+}
+
+// loraAdapterTarget is a ready pod to load a LoraAdapter on.
+type loraAdapterTarget struct {
+	pod        *corev1.Pod
+	runtimeURL string
+	outputDir  string
+}
+
+// ensureLoaded loads the adapter through the runtime at runtimeURL, if it is not loaded yet or must be reloaded.
+func (c *LoraAdapterController) ensureLoaded(ctx context.Context, runtimeURL string, adapter *workload.LoraAdapter, outputDir string, reload bool) error {
+	models, err := listServedModels(ctx, c.httpClient, runtimeURL)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(models, adapter.Name) {
+		if !reload {
+			return nil
+		}
+		if err := unloadLoraAdapter(ctx, c.httpClient, runtimeURL, adapter.Name); err != nil {
+			return err
+		}
+	}
+	return loadLoraAdapter(ctx, c.httpClient, runtimeURL, adapter.Name, adapter.Spec.ArtifactURL, outputDir)
+}
+
+// finalize unloads the adapter from the pods of its base model and removes the finalizer.
+// Failures are only logged, the pods may be gone already and a pod restart unloads the adapter anyway.
+func (c *LoraAdapterController) finalize(ctx context.Context, adapter *workload.LoraAdapter) error {
+	if !slices.Contains(adapter.Finalizers, LoraAdapterFinalizer) {
+		return nil
+	}
+	if model, err := c.modelBoosterLister.ModelBoosters(adapter.Namespace).Get(adapter.Spec.BaseModel); err == nil {
+		for _, backend := range loraAdapterBackends(adapter, model) {
+			pods, err := c.getReadyPods(model, backend)
+			if err != nil {
+				return err
+			}
+			for _, pod := range pods {
+				if err := unloadLoraAdapter(ctx, c.httpClient, getRuntimeURL(pod, backend), adapter.Name); err != nil {
+					klog.Warningf("Failed to unload LoRA adapter %s/%s from pod %s: %v", adapter.Namespace, adapter.Name, pod.Name, err)
+				}
+			}
+		}
+	}
+
+	adapterCopy := adapter.DeepCopy()
+	adapterCopy.Finalizers = slices.DeleteFunc(adapterCopy.Finalizers, func(f string) bool {
+		return f == LoraAdapterFinalizer
+	})
+	_, err := c.client.WorkloadV1alpha1().LoraAdapters(adapter.Namespace).Update(ctx, adapterCopy, metav1.UpdateOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to remove finalizer of LoraAdapter %s/%s: %v", adapter.Namespace, adapter.Name, err)
+	}
+	return nil
+}
+
+// getReadyPods returns the ready entry pods of the ModelServing of a backend, the only ones running the runtime.
+func (c *LoraAdapterController) getReadyPods(model *workload.ModelBooster, backend *workload.ModelBackend) ([]*corev1.Pod, error) {
+	selector := labels.SelectorFromSet(labels.Set{
+		workload.ModelServingNameLabelKey: utils.GetBackendResourceName(model.Name, backend.Name),
+		workload.EntryLabelKey:            icUtils.Entry,
+	})
+	pods, err := c.podsLister.Pods(model.Namespace).List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+	return slices.DeleteFunc(pods, func(pod *corev1.Pod) bool {
+		return pod.DeletionTimestamp != nil || pod.Status.PodIP == "" || !icUtils.IsPodRunningAndReady(pod)
+	}), nil
+}
+
+func (c *LoraAdapterController) updateStatus(ctx context.Context, adapter *workload.LoraAdapter, newStatus *workload.LoraAdapterStatus) error {
+	if apiequality.Semantic.DeepEqual(adapter.Status, *newStatus) {
+		return nil
+	}
+	adapterCopy := adapter.DeepCopy()
+	adapterCopy.Status = *newStatus
+	_, err := c.client.WorkloadV1alpha1().LoraAdapters(adapter.Namespace).UpdateStatus(ctx, adapterCopy, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update status of LoraAdapter %s/%s: %v", adapter.Namespace, adapter.Name, err)
+	}
+	return nil
+}
+
+// loraAdapterBackends returns the backends of the base model the adapter is loaded on.
+// Only vLLM backends allowing runtime LoRA updates can load adapters dynamically.
+func loraAdapterBackends(adapter *workload.LoraAdapter, model *workload.ModelBooster) []*workload.ModelBackend {
+	var backends []*workload.ModelBackend
+	for i := range model.Spec.Backends {
+		backend := &model.Spec.Backends[i]
+		if len(adapter.Spec.Backends) > 0 && !slices.Contains(adapter.Spec.Backends, backend.Name) {
+			continue
+		}
+		if backend.Type != workload.ModelBackendTypeVLLM || !isRuntimeLoraUpdateEnabled(backend) {
+			continue
+		}
+		backends = append(backends, backend)
+	}
+	return backends
+}
+
+// getRuntimeURL returns the URL of the runtime sidecar of a pod.
+func getRuntimeURL(pod *corev1.Pod, backend *workload.ModelBackend) string {
+	return "http://" + netutil.HostPort(pod.Status.PodIP, env.GetEnvValueOrDefault[int32](backend, env.RuntimePort, 8100))
+}
+
+func setLoraAdapterReadyCondition(status *workload.LoraAdapterStatus, replicas, loaded int32, reason, message string) {
+	status.Replicas = replicas
+	status.LoadedReplicas = loaded
+	condition := metav1.Condition{
+		Type:    string(workload.LoraAdapterReady),
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	}
+	if reason == reasonAdapterLoaded {
+		condition.Status = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// listServedModels returns the models served by the engine behind the runtime at runtimeURL,
+// including the LoRA adapters loaded.
+func listServedModels(ctx context.Context, httpClient *http.Client, runtimeURL string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, runtimeURL+"/v1/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send HTTP request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("list models failed with status code: %d", resp.StatusCode)
+	}
+
+	var models struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return nil, fmt.Errorf("failed to decode models: %v", err)
+	}
+	names := make([]string, 0, len(models.Data))
+	for _, model := range models.Data {
+		names = append(names, model.ID)
+	}
+	return names, nil
+}
@@ -0,0 +1,301 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-booster-controller/env"
+	icUtils "github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+// fakeRuntime mocks the LoRA API of the runtime sidecar.
+type fakeRuntime struct {
+	sync.Mutex
+	models  sets.Set[string]
+	loads   int
+	unloads int
+}
+
+func (f *fakeRuntime) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	var body map[string]interface{}
+	if r.Body != nil {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}
+	switch r.URL.Path {
+	case "/v1/models":
+		var data []map[string]string
+		for _, model := range sets.List(f.models) {
+			data = append(data, map[string]string{"id": model})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	case "/v1/load_lora_adapter":
+		f.loads++
+		f.models.Insert(body["lora_name"].(string))
+	case "/v1/unload_lora_adapter":
+		f.unloads++
+		f.models.Delete(body["lora_name"].(string))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestLoraAdapterReconcile(t *testing.T) {
+	ctx := context.Background()
+	runtime := &fakeRuntime{models: sets.New("base")}
+	server := httptest.NewServer(runtime)
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	model := &workload.ModelBooster{
+		ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "default"},
+		Spec: workload.ModelBoosterSpec{
+			Backends: []workload.ModelBackend{{
+				Name:     "backend1",
+				Type:     workload.ModelBackendTypeVLLM,
+				CacheURI: "hostpath://tmp/cache",
+				Env: []corev1.EnvVar{
+					{Name: "VLLM_ALLOW_RUNTIME_LORA_UPDATING", Value: "true"},
+					{Name: env.RuntimePort, Value: serverURL.Port()},
+				},
+			}},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "base-backend1-0-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				workload.ModelServingNameLabelKey: "base-backend1",
+				workload.EntryLabelKey:            icUtils.Entry,
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			PodIP:      serverURL.Hostname(),
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	adapter := &workload.LoraAdapter{
+		ObjectMeta: metav1.ObjectMeta{Name: "sql-lora", Namespace: "default", Generation: 1},
+		Spec: workload.LoraAdapterSpec{
+			BaseModel:   "base",
+			ArtifactURL: "hf://org/sql-lora",
+		},
+	}
+
+	kthenaClient := kthenafake.NewSimpleClientset(model, adapter)
+	c := NewLoraAdapterController(fake.NewClientset(pod), kthenaClient)
+	assert.NoError(t, c.modelBoosterInformer.GetIndexer().Add(model))
+	assert.NoError(t, c.podsInformer.GetIndexer().Add(pod))
+
+	// reconcile syncs the adapter in the informer from the client and reconciles it
+	reconcile := func() *workload.LoraAdapter {
+		current, err := kthenaClient.WorkloadV1alpha1().LoraAdapters("default").Get(ctx, adapter.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.NoError(t, c.loraAdapterInformer.GetIndexer().Update(current))
+		assert.NoError(t, c.reconcile(ctx, "default/sql-lora"))
+		current, err = kthenaClient.WorkloadV1alpha1().LoraAdapters("default").Get(ctx, adapter.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		return current
+	}
+
+	// The finalizer is added first
+	current := reconcile()
+	assert.Contains(t, current.Finalizers, LoraAdapterFinalizer)
+	assert.Equal(t, 0, runtime.loads)
+
+	// The adapter is loaded on the ready pod
+	current = reconcile()
+	assert.Equal(t, 1, runtime.loads)
+	assert.Equal(t, int32(1), current.Status.Replicas)
+	assert.Equal(t, int32(1), current.Status.LoadedReplicas)
+	assert.Equal(t, "hf://org/sql-lora", current.Status.ArtifactURL)
+	assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, string(workload.LoraAdapterReady)))
+
+	// An adapter already loaded is not loaded again
+	reconcile()
+	assert.Equal(t, 1, runtime.loads)
+	assert.Equal(t, 0, runtime.unloads)
+
+	// A new artifact is reloaded
+	current.Spec.ArtifactURL = "hf://org/sql-lora-v2"
+	_, err := kthenaClient.WorkloadV1alpha1().LoraAdapters("default").Update(ctx, current, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	current = reconcile()
+	assert.Equal(t, 2, runtime.loads)
+	assert.Equal(t, 1, runtime.unloads)
+	assert.Equal(t, "hf://org/sql-lora-v2", current.Status.ArtifactURL)
+
+	// The adapter is unloaded on deletion and the finalizer removed
+	now := metav1.Now()
+	current.DeletionTimestamp = &now
+	_, err = kthenaClient.WorkloadV1alpha1().LoraAdapters("default").Update(ctx, current, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	current = reconcile()
+	assert.Equal(t, 2, runtime.unloads)
+	assert.False(t, runtime.models.Has("sql-lora"))
+	assert.NotContains(t, current.Finalizers, LoraAdapterFinalizer)
+}
+
+func TestLoraAdapterReconcileLoadsPodsInParallel(t *testing.T) {
+	ctx := context.Background()
+	const podNum = 3
+	var mu sync.Mutex
+	loading := 0
+	allLoading := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": []map[string]string{{"id": "base"}}})
+		case "/v1/load_lora_adapter":
+			// Each load only returns once all the pods are loading the adapter
+			mu.Lock()
+			loading++
+			if loading == podNum {
+				close(allLoading)
+			}
+			mu.Unlock()
+			select {
+			case <-allLoading:
+			case <-time.After(5 * time.Second):
+				w.WriteHeader(http.StatusGatewayTimeout)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	model := &workload.ModelBooster{
+		ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "default"},
+		Spec: workload.ModelBoosterSpec{
+			Backends: []workload.ModelBackend{{
+				Name:     "backend1",
+				Type:     workload.ModelBackendTypeVLLM,
+				CacheURI: "hostpath://tmp/cache",
+				Env: []corev1.EnvVar{
+					{Name: "VLLM_ALLOW_RUNTIME_LORA_UPDATING", Value: "true"},
+					{Name: env.RuntimePort, Value: serverURL.Port()},
+				},
+			}},
+		},
+	}
+	adapter := &workload.LoraAdapter{
+		ObjectMeta: metav1.ObjectMeta{Name: "sql-lora", Namespace: "default", Finalizers: []string{LoraAdapterFinalizer}},
+		Spec: workload.LoraAdapterSpec{
+			BaseModel:   "base",
+			ArtifactURL: "hf://org/sql-lora",
+		},
+	}
+	kthenaClient := kthenafake.NewSimpleClientset(model, adapter)
+	c := NewLoraAdapterController(fake.NewClientset(), kthenaClient)
+	assert.NoError(t, c.modelBoosterInformer.GetIndexer().Add(model))
+	assert.NoError(t, c.loraAdapterInformer.GetIndexer().Add(adapter))
+	for i := 0; i < podNum; i++ {
+		assert.NoError(t, c.podsInformer.GetIndexer().Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("base-backend1-%d-0", i),
+				Namespace: "default",
+				Labels: map[string]string{
+					workload.ModelServingNameLabelKey: "base-backend1",
+					workload.EntryLabelKey:            icUtils.Entry,
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				PodIP:      serverURL.Hostname(),
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}))
+	}
+
+	assert.NoError(t, c.reconcile(ctx, "default/sql-lora"))
+	current, err := kthenaClient.WorkloadV1alpha1().LoraAdapters("default").Get(ctx, adapter.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(podNum), current.Status.Replicas)
+	assert.Equal(t, int32(podNum), current.Status.LoadedReplicas)
+	assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, string(workload.LoraAdapterReady)))
+}
+
+func TestLoraAdapterReconcileBaseModelNotFound(t *testing.T) {
+	ctx := context.Background()
+	adapter := &workload.LoraAdapter{
+		ObjectMeta: metav1.ObjectMeta{Name: "sql-lora", Namespace: "default", Finalizers: []string{LoraAdapterFinalizer}},
+		Spec: workload.LoraAdapterSpec{
+			BaseModel:   "missing",
+			ArtifactURL: "hf://org/sql-lora",
+		},
+	}
+	kthenaClient := kthenafake.NewSimpleClientset(adapter)
+	c := NewLoraAdapterController(fake.NewClientset(), kthenaClient)
+	assert.NoError(t, c.loraAdapterInformer.GetIndexer().Add(adapter))
+
+	assert.NoError(t, c.reconcile(ctx, "default/sql-lora"))
+	current, err := kthenaClient.WorkloadV1alpha1().LoraAdapters("default").Get(ctx, adapter.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	condition := meta.FindStatusCondition(current.Status.Conditions, string(workload.LoraAdapterReady))
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, reasonBaseModelNotFound, condition.Reason)
+}
+
+func TestLoraAdapterBackends(t *testing.T) {
+	loraEnv := []corev1.EnvVar{{Name: "VLLM_ALLOW_RUNTIME_LORA_UPDATING", Value: "true"}}
+	model := &workload.ModelBooster{
+		Spec: workload.ModelBoosterSpec{
+			Backends: []workload.ModelBackend{
+				{Name: "vllm", Type: workload.ModelBackendTypeVLLM, Env: loraEnv},
+				{Name: "vllm-static", Type: workload.ModelBackendTypeVLLM},
+				{Name: "sglang", Type: workload.ModelBackendTypeSGLang, Env: loraEnv},
+				{Name: "vllm-2", Type: workload.ModelBackendTypeVLLM, Env: loraEnv},
+			},
+		},
+	}
+	names := func(backends []*workload.ModelBackend) []string {
+		var result []string
+		for _, backend := range backends {
+			result = append(result, backend.Name)
+		}
+		return result
+	}
+
+	adapter := &workload.LoraAdapter{}
+	assert.Equal(t, []string{"vllm", "vllm-2"}, names(loraAdapterBackends(adapter, model)))
+	adapter.Spec.Backends = []string{"vllm-2", "sglang"}
+	assert.Equal(t, []string{"vllm-2"}, names(loraAdapterBackends(adapter, model)))
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/volcano-sh/kthena/pkg/model-booster-controller/convert"
 	"github.com/volcano-sh/kthena/pkg/model-booster-controller/env"
 	"github.com/volcano-sh/kthena/pkg/model-booster-controller/utils"
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/klog/v2"
@@ -127,12 +128,12 @@ func (mc *ModelBoosterController) updateLoraAdapters(ctx context.Context, newBac
 	klog.Infof("Updating LoRA adapters for ModelServing %s across %d replicas", modelServing.Name, len(runtimeURLs))
 
 	// Prepare adapter maps for comparison
-	oldAdapterMap := make(map[string]workload.LoraAdapter)
+	oldAdapterMap := make(map[string]workload.BackendLoraAdapter)
 	for _, adapter := range oldBackend.LoraAdapters {
 		oldAdapterMap[adapter.Name] = adapter
 	}
 
-	newAdapterMap := make(map[string]workload.LoraAdapter)
+	newAdapterMap := make(map[string]workload.BackendLoraAdapter)
 	for _, adapter := range newBackend.LoraAdapters {
 		newAdapterMap[adapter.Name] = adapter
 	}
@@ -162,7 +163,7 @@ func (mc *ModelBoosterController) updateLoraAdapters(ctx context.Context, newBac
 	}
 
 	// Phase 2: Load new or updated adapters
-	adaptersToLoad := make([]workload.LoraAdapter, 0)
+	adaptersToLoad := make([]workload.BackendLoraAdapter, 0)
 	for _, adapter := range newBackend.LoraAdapters {
 		oldAdapter, existed := oldAdapterMap[adapter.Name]
 		// Load adapter if it's new or if the artifact URL changed
@@ -203,7 +204,7 @@ func (mc *ModelBoosterController) unloadLoraAdaptersFromAllReplicas(ctx context.
 	for _, runtimeURL := range runtimeURLs {
 		replicaSuccess := true
 		for _, adapterName := range adapterNames {
-			if err := mc.unloadLoraAdapter(ctx, runtimeURL, adapterName); err != nil {
+			if err := unloadLoraAdapter(ctx, mc.httpClient, runtimeURL, adapterName); err != nil {
 				klog.Errorf("Failed to unload LoRA adapter %s from %s: %v", adapterName, runtimeURL, err)
 				if replicaSuccess {
 					// Only record the replica as failed once
@@ -225,7 +226,7 @@ func (mc *ModelBoosterController) unloadLoraAdaptersFromAllReplicas(ctx context.
 }
 
 // loadLoraAdaptersToAllReplicas loads LoRA adapters to all replicas
-func (mc *ModelBoosterController) loadLoraAdaptersToAllReplicas(ctx context.Context, runtimeURLs []string, adapters []workload.LoraAdapter, backend *workload.ModelBackend) LoraUpdateResult {
+func (mc *ModelBoosterController) loadLoraAdaptersToAllReplicas(ctx context.Context, runtimeURLs []string, adapters []workload.BackendLoraAdapter, backend *workload.ModelBackend) LoraUpdateResult {
 	result := LoraUpdateResult{
 		TotalReplicas:   len(runtimeURLs),
 		PartialFailures: make([]string, 0),
@@ -235,7 +236,8 @@ func (mc *ModelBoosterController) loadLoraAdaptersToAllReplicas(ctx context.Cont
 	for _, runtimeURL := range runtimeURLs {
 		replicaSuccess := true
 		for _, adapter := range adapters {
-			if err := mc.loadLoraAdapter(ctx, runtimeURL, adapter, backend); err != nil {
+			outputDir := convert.GetCachePath(backend.CacheURI) + convert.GetMountPath(adapter.ArtifactURL)
+			if err := loadLoraAdapter(ctx, mc.httpClient, runtimeURL, adapter.Name, adapter.ArtifactURL, outputDir); err != nil {
 				klog.Errorf("Failed to load LoRA adapter %s to %s: %v", adapter.Name, runtimeURL, err)
 				if replicaSuccess {
 					// Only record the replica as failed once
@@ -269,7 +271,7 @@ func (mc *ModelBoosterController) getModelServingRuntimeURLs(modelServing *workl
 
 	var runtimeURLs []string
 	for _, podIP := range podIPs {
-		runtimeURLs = append(runtimeURLs, fmt.Sprintf("http://%s:%d", podIP, port))
+		runtimeURLs = append(runtimeURLs, "http://"+netutil.HostPort(podIP, port))
 	}
 
 	return runtimeURLs, nil
@@ -306,14 +308,13 @@ func (mc *ModelBoosterController) getModelServingPodIPs(modelServing *workload.M
 	return podIPs, nil
 }
 
-// loadLoraAdapter calls the load_lora_adapter API
-func (mc *ModelBoosterController) loadLoraAdapter(ctx context.Context, runtimeURL string, adapter workload.LoraAdapter, backend *workload.ModelBackend) error {
+// loadLoraAdapter calls the load_lora_adapter API of the runtime, downloading the adapter to outputDir first
+func loadLoraAdapter(ctx context.Context, httpClient *http.Client, runtimeURL, name, artifactURL, outputDir string) error {
 	url := fmt.Sprintf("%s/v1/load_lora_adapter", runtimeURL)
-	outputDir := convert.GetCachePath(backend.CacheURI) + convert.GetMountPath(adapter.ArtifactURL)
 
 	requestBody := map[string]interface{}{
-		"lora_name":      adapter.Name,
-		"source":         adapter.ArtifactURL,
+		"lora_name":      name,
+		"source":         artifactURL,
 		"output_dir":     outputDir,
 		"async_download": false, // Use synchronous download for better error handling
 	}
@@ -330,9 +331,9 @@ func (mc *ModelBoosterController) loadLoraAdapter(ctx context.Context, runtimeUR
 
 	req.Header.Set("Content-Type", "application/json")
 
-	klog.Infof("Loading LoRA adapter %s from %s", adapter.Name, adapter.ArtifactURL)
+	klog.Infof("Loading LoRA adapter %s from %s", name, artifactURL)
 
-	resp, err := mc.httpClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send HTTP request: %v", err)
 	}
@@ -355,12 +356,12 @@ func (mc *ModelBoosterController) loadLoraAdapter(ctx context.Context, runtimeUR
 		return fmt.Errorf("load LoRA adapter failed with status code: %d, error: %s", resp.StatusCode, errorDetail)
 	}
 
-	klog.Infof("Successfully loaded LoRA adapter %s", adapter.Name)
+	klog.Infof("Successfully loaded LoRA adapter %s", name)
 	return nil
 }
 
-// unloadLoraAdapter calls the unload_lora_adapter API
-func (mc *ModelBoosterController) unloadLoraAdapter(ctx context.Context, runtimeURL string, adapterName string) error {
+// unloadLoraAdapter calls the unload_lora_adapter API of the runtime
+func unloadLoraAdapter(ctx context.Context, httpClient *http.Client, runtimeURL string, adapterName string) error {
 	url := fmt.Sprintf("%s/v1/unload_lora_adapter", runtimeURL)
 
 	requestBody := map[string]interface{}{
@@ -381,7 +382,7 @@ func (mc *ModelBoosterController) unloadLoraAdapter(ctx context.Context, runtime
 
 	klog.Infof("Unloading LoRA adapter %s", adapterName)
 
-	resp, err := mc.httpClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send HTTP request: %v", err)
 	}
@@ -473,7 +474,7 @@ func (mc *ModelBoosterController) canUseDynamicLoraUpdate(oldBackend, newBackend
 	}
 
 	// Check if runtime LoRA update is enabled for this backend
-	if !mc.isRuntimeLoraUpdateEnabled(newBackend) {
+	if !isRuntimeLoraUpdateEnabled(newBackend) {
 		return false
 	}
 
@@ -493,7 +494,7 @@ func (mc *ModelBoosterController) canUseDynamicLoraUpdate(oldBackend, newBackend
 }
 
 // isRuntimeLoraUpdateEnabled checks if runtime LoRA update is enabled for a backend
-func (mc *ModelBoosterController) isRuntimeLoraUpdateEnabled(backend *workload.ModelBackend) bool {
+func isRuntimeLoraUpdateEnabled(backend *workload.ModelBackend) bool {
 	for _, envVar := range backend.Env {
 		if envVar.Name == "VLLM_ALLOW_RUNTIME_LORA_UPDATING" {
 			return strings.ToLower(envVar.Value) == "true"
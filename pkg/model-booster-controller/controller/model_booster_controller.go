@@ -32,6 +32,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	listerv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,12 +42,22 @@ import (
 	networkingLister "github.com/volcano-sh/kthena/client-go/listers/networking/v1alpha1"
 	workloadLister "github.com/volcano-sh/kthena/client-go/listers/workload/v1alpha1"
 	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/controller/events"
+	"github.com/volcano-sh/kthena/pkg/controller/metrics"
 	"github.com/volcano-sh/kthena/pkg/model-booster-controller/config"
+	"github.com/volcano-sh/kthena/pkg/model-booster-controller/provenance"
 	"github.com/volcano-sh/kthena/pkg/model-booster-controller/utils"
 )
 
 const (
 	ConfigMapName = "model-booster-controller-config"
+
+	modelBoosterControllerName = "model-booster"
+
+	// Reasons of the events recorded on the ModelBoosters for the resources they own
+	ResourceCreatedReason = "SuccessfulCreate"
+	ResourceUpdatedReason = "SuccessfulUpdate"
+	ResourceDeletedReason = "SuccessfulDelete"
 )
 
 type ModelBoosterController struct {
@@ -56,6 +67,9 @@ type ModelBoosterController struct {
 	client clientset.Interface
 	// httpClient for HTTP requests to LoRA adapter APIs
 	httpClient *http.Client
+	recorder   record.EventRecorder
+	// provenance verifies the images and the model files before the serving workloads are generated
+	provenance *provenance.Verifier
 
 	syncHandler                       func(ctx context.Context, miKey string) error
 	modelBoosterLister                workloadLister.ModelBoosterLister
@@ -95,7 +109,7 @@ func (mc *ModelBoosterController) Run(ctx context.Context, workers int) {
 	// start Kubernetes informer factory
 	go mc.kubeInformerFactory.Start(ctx.Done())
 
-	cache.WaitForCacheSync(ctx.Done(),
+	metrics.WaitForCacheSync(modelBoosterControllerName, ctx.Done(),
 		mc.modelsInformer.HasSynced,
 		mc.modelServingInformer.HasSynced,
 		mc.autoscalingPoliciesInformer.HasSynced,
@@ -125,7 +139,9 @@ func (mc *ModelBoosterController) processNextWorkItem(ctx context.Context) bool
 	}
 	defer mc.workQueue.Done(key)
 
+	start := time.Now()
 	err := mc.syncHandler(ctx, key.(string))
+	metrics.ObserveReconcile(modelBoosterControllerName, start, err)
 	if err == nil {
 		mc.workQueue.Forget(key)
 		return true
@@ -204,7 +220,12 @@ func (mc *ModelBoosterController) reconcile(ctx context.Context, namespaceAndNam
 	if err := mc.setModelProcessingCondition(ctx, model); err != nil {
 		return err
 	}
-	if err := mc.createOrUpdateModelServing(ctx, model); err != nil {
+	servingModel, err := mc.verifyProvenance(ctx, model)
+	if err != nil {
+		mc.setModelFailedCondition(ctx, model, err)
+		return err
+	}
+	if err := mc.createOrUpdateModelServing(ctx, servingModel); err != nil {
 		mc.setModelFailedCondition(ctx, model, err)
 		return err
 	}
@@ -264,6 +285,7 @@ func (mc *ModelBoosterController) updateModelBoosterStatus(ctx context.Context,
 		backendStatus = append(backendStatus, workload.ModelBackendStatus{
 			Name:     modelServing.Name,
 			Replicas: modelServing.Status.Replicas,
+			Download: mc.getModelDownloadStatus(modelServing),
 		})
 	}
 	modelBooster.Status.BackendStatuses = backendStatus
@@ -321,6 +343,8 @@ func NewModelBoosterController(kubeClient kubernetes.Interface, client clientset
 		kubeClient:                        kubeClient,
 		client:                            client,
 		httpClient:                        httpClient,
+		recorder:                          events.NewRecorder(kubeClient, modelBoosterControllerName),
+		provenance:                        provenance.NewVerifier(kubeClient, nil),
 		modelBoosterLister:                modelBoosterInformer.Lister(),
 		modelsInformer:                    modelBoosterInformer.Informer(),
 		modelServingLister:                modelServingInformer.Lister(),
@@ -339,7 +363,7 @@ func NewModelBoosterController(kubeClient kubernetes.Interface, client clientset
 		loraUpdateCache:                   make(map[string]*workload.ModelBooster),
 
 		workQueue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[any](),
-			workqueue.TypedRateLimitingQueueConfig[any]{}),
+			workqueue.TypedRateLimitingQueueConfig[any]{Name: modelBoosterControllerName}),
 	}
 	klog.Info("Set the ModelBooster event handler")
 	_, err = modelBoosterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -359,6 +383,13 @@ func NewModelBoosterController(kubeClient kubernetes.Interface, client clientset
 		klog.Fatal("Unable to add ModelServing event handler")
 		return nil
 	}
+	_, err = podsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: mc.triggerModelByPod,
+	})
+	if err != nil {
+		klog.Fatal("Unable to add pod event handler")
+		return nil
+	}
 	_, err = modelRouteInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		DeleteFunc: mc.deleteModelRoute,
 	})
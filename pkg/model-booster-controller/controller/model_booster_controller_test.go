@@ -75,6 +75,20 @@ func TestReconcile(t *testing.T) {
 	modelRoutes, err := kthenaClient.NetworkingV1alpha1().ModelRoutes(model.Namespace).List(ctx, metav1.ListOptions{})
 	assert.NoError(t, err)
 	assert.Len(t, modelRoutes.Items, 1, "Expected 1 ModelRoute to be create")
+	// the creation of the ModelServing should be recorded as an event of the model
+	assert.True(t, waitForCondition(func() bool {
+		events, err := kubeClient.CoreV1().Events(model.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false
+		}
+		for _, event := range events.Items {
+			if event.InvolvedObject.Name == model.Name && event.Reason == ResourceCreatedReason &&
+				event.Message == "Created ModelServing "+modelServingList.Items[0].Name {
+				return true
+			}
+		}
+		return false
+	}))
 	// Step3. mock model serving status available
 	modelServing := &modelServingList.Items[0]
 	meta.SetStatusCondition(&modelServing.Status.Conditions, newCondition(string(workload.ModelServingAvailable),
@@ -241,7 +255,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "backend1",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1"},
 							},
 						},
@@ -255,7 +269,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "backend1",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1"},
 							},
 						},
@@ -273,7 +287,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "backend1",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1"},
 								{Name: "adapter3", ArtifactURL: "uri3"},
 							},
@@ -288,7 +302,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "backend1",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1"},
 								{Name: "adapter2", ArtifactURL: "uri2"},
 							},
@@ -307,7 +321,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "backend1",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1"},
 							},
 						},
@@ -321,7 +335,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "backend1",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1-modified"},
 							},
 						},
@@ -339,7 +353,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "backend1",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1"},
 							},
 						},
@@ -353,7 +367,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "backend2",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1"},
 							},
 						},
@@ -371,7 +385,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "backend1",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1"},
 							},
 						},
@@ -385,7 +399,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "backend1",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri-changed",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1"},
 								{Name: "adapter2", ArtifactURL: "uri2"},
 							},
@@ -404,7 +418,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "backend1",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1"},
 							},
 						},
@@ -418,7 +432,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "backend1",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1"},
 							},
 						},
@@ -441,7 +455,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "vllm-backend",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1"},
 							},
 						},
@@ -460,7 +474,7 @@ func TestHasOnlyLoraAdaptersChanged(t *testing.T) {
 							Name:     "vllm-backend",
 							Type:     workload.ModelBackendTypeVLLM,
 							ModelURI: "model-uri",
-							LoraAdapters: []workload.LoraAdapter{
+							LoraAdapters: []workload.BackendLoraAdapter{
 								{Name: "adapter1", ArtifactURL: "uri1"},
 								{Name: "adapter2", ArtifactURL: "uri2"},
 							},
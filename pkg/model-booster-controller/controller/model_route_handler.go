@@ -22,6 +22,7 @@ import (
 	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 	"github.com/volcano-sh/kthena/pkg/model-booster-controller/convert"
 	"github.com/volcano-sh/kthena/pkg/model-booster-controller/utils"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
@@ -37,6 +38,7 @@ func (mc *ModelBoosterController) createOrUpdateModelRoute(ctx context.Context,
 				klog.Errorf("failed to create ModelRoute %s: %v", klog.KObj(modelRoute), err)
 				return err
 			}
+			mc.recorder.Eventf(model, corev1.EventTypeNormal, ResourceCreatedReason, "Created ModelRoute %s", modelRoute.Name)
 			return nil
 		}
 		klog.Errorf("failed to get ModelRoute %s: %v", klog.KObj(modelRoute), err)
@@ -51,5 +53,6 @@ func (mc *ModelBoosterController) createOrUpdateModelRoute(ctx context.Context,
 		klog.Errorf("failed to update ModelRoute %s: %v", klog.KObj(modelRoute), err)
 		return err
 	}
+	mc.recorder.Eventf(model, corev1.EventTypeNormal, ResourceUpdatedReason, "Updated ModelRoute %s", modelRoute.Name)
 	return nil
 }
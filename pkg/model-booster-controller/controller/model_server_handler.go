@@ -23,6 +23,7 @@ import (
 	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 	"github.com/volcano-sh/kthena/pkg/model-booster-controller/convert"
 	"github.com/volcano-sh/kthena/pkg/model-booster-controller/utils"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -49,6 +50,7 @@ func (mc *ModelBoosterController) createOrUpdateModelServer(ctx context.Context,
 					klog.Errorf("failed to create ModelServer %s: %v", klog.KObj(modelServer), err)
 					return err
 				}
+				mc.recorder.Eventf(model, corev1.EventTypeNormal, ResourceCreatedReason, "Created ModelServer %s", modelServer.Name)
 				continue
 			}
 			klog.Errorf("failed to get ModelServer %s: %v", klog.KObj(modelServer), err)
@@ -63,6 +65,7 @@ func (mc *ModelBoosterController) createOrUpdateModelServer(ctx context.Context,
 			klog.Errorf("failed to update ModelServer %s: %v", klog.KObj(modelServer), err)
 			return err
 		}
+		mc.recorder.Eventf(model, corev1.EventTypeNormal, ResourceUpdatedReason, "Updated ModelServer %s", modelServer.Name)
 		klog.V(4).Infof("Updated ModelBooster Server %s for model %s", modelServer.Name, model.Name)
 	}
 	for _, existingModelServer := range existingModelServers {
@@ -71,6 +74,7 @@ func (mc *ModelBoosterController) createOrUpdateModelServer(ctx context.Context,
 				return err
 			}
 			klog.V(4).Infof("Delete ModelServer %s", existingModelServer.Name)
+			mc.recorder.Eventf(model, corev1.EventTypeNormal, ResourceDeletedReason, "Deleted ModelServer %s", existingModelServer.Name)
 		}
 	}
 	return nil
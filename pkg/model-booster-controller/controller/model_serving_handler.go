@@ -22,6 +22,7 @@ import (
 	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 	"github.com/volcano-sh/kthena/pkg/model-booster-controller/convert"
 	"github.com/volcano-sh/kthena/pkg/model-booster-controller/utils"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -74,6 +75,7 @@ func (mc *ModelBoosterController) createOrUpdateModelServing(ctx context.Context
 					klog.Errorf("failed to create ModelServing %s: %v", klog.KObj(modelServing), err)
 					return err
 				}
+				mc.recorder.Eventf(model, corev1.EventTypeNormal, ResourceCreatedReason, "Created ModelServing %s", modelServing.Name)
 				continue
 			}
 			klog.Errorf("failed to get ModelServing %s: %v", klog.KObj(modelServing), err)
@@ -84,10 +86,14 @@ func (mc *ModelBoosterController) createOrUpdateModelServing(ctx context.Context
 			continue
 		}
 		modelServing.ResourceVersion = oldModelServing.ResourceVersion
+		// ModelServings are paused and suspended by hand, which must survive the updates of the ModelBooster
+		modelServing.Spec.Paused = oldModelServing.Spec.Paused
+		modelServing.Spec.Suspend = oldModelServing.Spec.Suspend
 		if _, err := mc.client.WorkloadV1alpha1().ModelServings(model.Namespace).Update(ctx, modelServing, metav1.UpdateOptions{}); err != nil {
 			klog.Errorf("failed to update ModelServing %s: %v", klog.KObj(modelServing), err)
 			return err
 		}
+		mc.recorder.Eventf(model, corev1.EventTypeNormal, ResourceUpdatedReason, "Updated ModelServing %s", modelServing.Name)
 		klog.V(4).Infof("Updated ModelServing %s for model %s", modelServing.Name, model.Name)
 	}
 	for _, existingModelServing := range existingModelServings {
@@ -97,6 +103,7 @@ func (mc *ModelBoosterController) createOrUpdateModelServing(ctx context.Context
 				return err
 			}
 			klog.V(4).Infof("Delete ModelServing %s", existingModelServing.Name)
+			mc.recorder.Eventf(model, corev1.EventTypeNormal, ResourceDeletedReason, "Deleted ModelServing %s", existingModelServing.Name)
 		}
 	}
 	return nil
@@ -0,0 +1,55 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+// verifyProvenance verifies the provenance of the model and reports it in the ProvenanceVerified condition.
+// It returns the ModelBooster the serving workloads are generated from: the model itself when its namespace
+// has no provenance policy, otherwise a copy running the verified images and model files.
+func (mc *ModelBoosterController) verifyProvenance(ctx context.Context, model *workload.ModelBooster) (*workload.ModelBooster, error) {
+	conditionType := string(workload.ModelStatusConditionTypeProvenanceVerified)
+	result, err := mc.provenance.Verify(ctx, model)
+	if err != nil {
+		meta.SetStatusCondition(&model.Status.Conditions, newCondition(conditionType,
+			metav1.ConditionFalse, ProvenanceUnverifiedReason, err.Error()))
+		return nil, err
+	}
+	var changed bool
+	if result == nil {
+		changed = meta.RemoveStatusCondition(&model.Status.Conditions, conditionType)
+	} else {
+		changed = meta.SetStatusCondition(&model.Status.Conditions, newCondition(conditionType,
+			metav1.ConditionTrue, ProvenanceVerifiedReason, result.String()))
+	}
+	if changed {
+		if err := mc.updateModelBoosterStatus(ctx, model); err != nil {
+			return nil, err
+		}
+	}
+	if result == nil {
+		return model, nil
+	}
+	return result.Apply(model), nil
+}
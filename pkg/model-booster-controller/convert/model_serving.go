@@ -23,10 +23,10 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/volcano-sh/kthena/pkg/model-booster-controller/env"
-	icUtils "github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
 	"k8s.io/utils/ptr"
 
 	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
@@ -45,9 +45,13 @@ const (
 	VllmTemplatePath               = "templates/vllm.yaml"
 	VllmDisaggregatedTemplatePath  = "templates/vllm-pd.yaml"
 	VllmMultiNodeServingScriptPath = "/vllm-workspace/vllm/examples/online_serving/multi-node-serving.sh"
+	RuntimeStartupPath             = "/v1/startup"
 	modelRouteRuleName             = "default"
 )
 
+// ModelCacheAffinityWeight is the weight of the preference for nodes already caching the model.
+const ModelCacheAffinityWeight = 100
+
 //go:embed templates/*
 var templateFS embed.FS
 
@@ -68,11 +72,47 @@ func BuildModelServing(model *workload.ModelBooster) ([]*workload.ModelServing,
 		if err != nil {
 			return nil, err
 		}
+		addModelCacheAffinity(serving, &model.Spec.Backends[idx])
 		servings = append(servings, serving)
 	}
 	return servings, nil
 }
 
+// addModelCacheAffinity makes the pods of a backend caching the model on the node's host path prefer nodes
+// that already hold the model, as advertised by the model cache agent.
+func addModelCacheAffinity(serving *workload.ModelServing, backend *workload.ModelBackend) {
+	if !strings.HasPrefix(backend.CacheURI, CacheURIPrefixHostPath) {
+		return
+	}
+	term := corev1.PreferredSchedulingTerm{
+		Weight: ModelCacheAffinityWeight,
+		Preference: corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{
+				Key:      workload.ModelCacheNodeLabelPrefix + strings.TrimPrefix(GetMountPath(backend.ModelURI), "/"),
+				Operator: corev1.NodeSelectorOpExists,
+			}},
+		},
+	}
+	for i := range serving.Spec.Template.Roles {
+		role := &serving.Spec.Template.Roles[i]
+		addPreferredNodeAffinity(&role.EntryTemplate.Spec, term)
+		if role.WorkerTemplate != nil && len(role.WorkerTemplate.Spec.Containers) > 0 {
+			addPreferredNodeAffinity(&role.WorkerTemplate.Spec, term)
+		}
+	}
+}
+
+func addPreferredNodeAffinity(spec *corev1.PodSpec, term corev1.PreferredSchedulingTerm) {
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	nodeAffinity := spec.Affinity.NodeAffinity
+	nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, term)
+}
+
 // buildVllmDisaggregatedModelServing handles VLLM disaggregated backend creation.
 func buildVllmDisaggregatedModelServing(model *workload.ModelBooster, idx int) (*workload.ModelServing, error) {
 	backend := &model.Spec.Backends[idx]
@@ -90,34 +130,8 @@ func buildVllmDisaggregatedModelServing(model *workload.ModelBooster, idx int) (
 	modelDownloadPath := GetCachePath(backend.CacheURI) + GetMountPath(backend.ModelURI)
 
 	// Build an initial container list including model downloader container
-	var envVars []corev1.EnvVar
-	endpointEnvVars := env.GetEnvValueOrDefault[[]corev1.EnvVar](backend, env.Endpoint, []corev1.EnvVar{
-		{Name: env.Endpoint},
-	})
-	if len(endpointEnvVars) > 0 && endpointEnvVars[0].Value != "" {
-		envVars = append(envVars, endpointEnvVars[0])
-	}
-	hfEndpointEnvVars := env.GetEnvValueOrDefault[[]corev1.EnvVar](backend, env.HfEndpoint, []corev1.EnvVar{
-		{Name: env.HfEndpoint},
-	})
-	if len(hfEndpointEnvVars) > 0 && hfEndpointEnvVars[0].Value != "" {
-		envVars = append(envVars, hfEndpointEnvVars[0])
-	}
 	initContainers := []corev1.Container{
-		{
-			Name:  model.Name + "-model-downloader",
-			Image: config.Config.DownloaderImage(),
-			Args: []string{
-				"--source", backend.ModelURI,
-				"--output-dir", modelDownloadPath,
-			},
-			Env:     envVars,
-			EnvFrom: backend.EnvFrom,
-			VolumeMounts: []corev1.VolumeMount{{
-				Name:      cacheVolume.Name,
-				MountPath: GetCachePath(backend.CacheURI),
-			}},
-		},
+		buildModelDownloaderContainer(model, backend, cacheVolume.Name, modelDownloadPath),
 	}
 
 	var preFillCommand []string
@@ -162,7 +176,7 @@ func buildVllmDisaggregatedModelServing(model *workload.ModelBooster, idx int) (
 		"MODEL_SERVING_TEMPLATE_METADATA": &metav1.ObjectMeta{
 			Name:      utils.GetBackendResourceName(model.Name, backend.Name),
 			Namespace: model.Namespace,
-			Labels:    utils.GetModelControllerLabels(model, backend.Name, icUtils.Revision(backend)),
+			Labels:    utils.GetModelControllerLabels(model, backend.Name, utils.BackendRevision(backend)),
 			OwnerReferences: []metav1.OwnerReference{
 				{
 					APIVersion: workload.GroupVersion.String(),
@@ -179,27 +193,28 @@ func buildVllmDisaggregatedModelServing(model *workload.ModelBooster, idx int) (
 		"VOLUMES": []*corev1.Volume{
 			cacheVolume,
 		},
-		"MODEL_NAME":                         model.Name,
-		"BACKEND_REPLICAS":                   backend.MinReplicas, // todo: backend replicas
-		"INIT_CONTAINERS":                    initContainers,
-		"MODEL_DOWNLOAD_ENVFROM":             backend.EnvFrom,
-		"ENGINE_PREFILL_COMMAND":             preFillCommand,
-		"ENGINE_DECODE_COMMAND":              decodeCommand,
-		"MODEL_SERVING_RUNTIME_IMAGE":        config.Config.RuntimeImage(),
-		"MODEL_SERVING_RUNTIME_PORT":         env.GetEnvValueOrDefault[int32](backend, env.RuntimePort, 8100),
-		"MODEL_SERVING_RUNTIME_URL":          env.GetEnvValueOrDefault[string](backend, env.RuntimeUrl, "http://localhost:8000"),
-		"MODEL_SERVING_RUNTIME_METRICS_PATH": env.GetEnvValueOrDefault[string](backend, env.RuntimeMetricsPath, "/metrics"),
-		"ENGINE_PREFILL_ENV":                 prefillEngineEnv,
-		"ENGINE_DECODE_ENV":                  decodeEngineEnv,
-		"MODEL_SERVING_RUNTIME_ENGINE":       strings.ToLower(string(backend.Type)),
-		"MODEL_SERVING_RUNTIME_POD":          "$(POD_NAME).$(NAMESPACE)",
-		"PREFILL_REPLICAS":                   workersMap[workload.ModelWorkerTypePrefill].Replicas,
-		"DECODE_REPLICAS":                    workersMap[workload.ModelWorkerTypeDecode].Replicas,
-		"ENGINE_DECODE_RESOURCES":            workersMap[workload.ModelWorkerTypeDecode].Resources,
-		"ENGINE_DECODE_IMAGE":                workersMap[workload.ModelWorkerTypeDecode].Image,
-		"ENGINE_PREFILL_RESOURCES":           workersMap[workload.ModelWorkerTypePrefill].Resources,
-		"ENGINE_PREFILL_IMAGE":               workersMap[workload.ModelWorkerTypePrefill].Image,
-		"SCHEDULER_NAME":                     backend.SchedulerName,
+		"MODEL_NAME":                             model.Name,
+		"BACKEND_REPLICAS":                       backend.MinReplicas, // todo: backend replicas
+		"INIT_CONTAINERS":                        initContainers,
+		"MODEL_DOWNLOAD_ENVFROM":                 backend.EnvFrom,
+		"ENGINE_PREFILL_COMMAND":                 preFillCommand,
+		"ENGINE_DECODE_COMMAND":                  decodeCommand,
+		"MODEL_SERVING_RUNTIME_IMAGE":            config.Config.RuntimeImage(),
+		"MODEL_SERVING_RUNTIME_PORT":             env.GetEnvValueOrDefault[int32](backend, env.RuntimePort, 8100),
+		"MODEL_SERVING_RUNTIME_STARTUP_ENDPOINT": runtimeStartupEndpoint(backend),
+		"MODEL_SERVING_RUNTIME_URL":              env.GetEnvValueOrDefault[string](backend, env.RuntimeUrl, "http://localhost:8000"),
+		"MODEL_SERVING_RUNTIME_METRICS_PATH":     env.GetEnvValueOrDefault[string](backend, env.RuntimeMetricsPath, "/metrics"),
+		"ENGINE_PREFILL_ENV":                     prefillEngineEnv,
+		"ENGINE_DECODE_ENV":                      decodeEngineEnv,
+		"MODEL_SERVING_RUNTIME_ENGINE":           strings.ToLower(string(backend.Type)),
+		"MODEL_SERVING_RUNTIME_POD":              "$(POD_NAME).$(NAMESPACE)",
+		"PREFILL_REPLICAS":                       workersMap[workload.ModelWorkerTypePrefill].Replicas,
+		"DECODE_REPLICAS":                        workersMap[workload.ModelWorkerTypeDecode].Replicas,
+		"ENGINE_DECODE_RESOURCES":                workersMap[workload.ModelWorkerTypeDecode].Resources,
+		"ENGINE_DECODE_IMAGE":                    workersMap[workload.ModelWorkerTypeDecode].Image,
+		"ENGINE_PREFILL_RESOURCES":               workersMap[workload.ModelWorkerTypePrefill].Resources,
+		"ENGINE_PREFILL_IMAGE":                   workersMap[workload.ModelWorkerTypePrefill].Image,
+		"SCHEDULER_NAME":                         backend.SchedulerName,
 	}
 	return loadModelServingTemplate(VllmDisaggregatedTemplatePath, &data)
 }
@@ -223,34 +238,8 @@ func buildVllmModelServing(model *workload.ModelBooster, idx int) (*workload.Mod
 	}
 
 	// Build an initial container list including model downloader container
-	var envVars []corev1.EnvVar
-	endpointEnvVars := env.GetEnvValueOrDefault[[]corev1.EnvVar](backend, env.Endpoint, []corev1.EnvVar{
-		{Name: env.Endpoint},
-	})
-	if len(endpointEnvVars) > 0 && endpointEnvVars[0].Value != "" {
-		envVars = append(envVars, endpointEnvVars[0])
-	}
-	hfEndpointEnvVars := env.GetEnvValueOrDefault[[]corev1.EnvVar](backend, env.HfEndpoint, []corev1.EnvVar{
-		{Name: env.HfEndpoint},
-	})
-	if len(hfEndpointEnvVars) > 0 && hfEndpointEnvVars[0].Value != "" {
-		envVars = append(envVars, hfEndpointEnvVars[0])
-	}
 	initContainers := []corev1.Container{
-		{
-			Name:  model.Name + "-model-downloader",
-			Image: config.Config.DownloaderImage(),
-			Args: []string{
-				"--source", backend.ModelURI,
-				"--output-dir", modelDownloadPath,
-			},
-			Env:     envVars,
-			EnvFrom: backend.EnvFrom,
-			VolumeMounts: []corev1.VolumeMount{{
-				Name:      cacheVolume.Name,
-				MountPath: GetCachePath(backend.CacheURI),
-			}},
-		},
+		buildModelDownloaderContainer(model, backend, cacheVolume.Name, modelDownloadPath),
 	}
 
 	// Handle LoRA adapters
@@ -264,7 +253,7 @@ func buildVllmModelServing(model *workload.ModelBooster, idx int) (*workload.Mod
 		"MODEL_SERVING_TEMPLATE_METADATA": &metav1.ObjectMeta{
 			Name:      utils.GetBackendResourceName(model.Name, backend.Name),
 			Namespace: model.Namespace,
-			Labels:    utils.GetModelControllerLabels(model, backend.Name, icUtils.Revision(backend)),
+			Labels:    utils.GetModelControllerLabels(model, backend.Name, utils.BackendRevision(backend)),
 			OwnerReferences: []metav1.OwnerReference{
 				{
 					APIVersion: workload.GroupVersion.String(),
@@ -282,7 +271,10 @@ func buildVllmModelServing(model *workload.ModelBooster, idx int) (*workload.Mod
 		"WORKER_ENV":       backend.Env,
 		"SERVER_REPLICAS":  workersMap[workload.ModelWorkerTypeServer].Replicas,
 		"SERVER_ENTRY_TEMPLATE_METADATA": &metav1.ObjectMeta{
-			Labels: utils.GetModelControllerLabels(model, backend.Name, icUtils.Revision(backend)),
+			Labels: utils.GetModelControllerLabels(model, backend.Name, utils.BackendRevision(backend)),
+			Annotations: map[string]string{
+				workload.StartupEndpointAnnotationKey: runtimeStartupEndpoint(backend),
+			},
 		},
 		"SERVER_WORKER_TEMPLATE_METADATA": nil,
 		"VOLUMES": []*corev1.Volume{
@@ -309,6 +301,11 @@ func buildVllmModelServing(model *workload.ModelBooster, idx int) (*workload.Mod
 	return loadModelServingTemplate(VllmTemplatePath, &data)
 }
 
+// runtimeStartupEndpoint returns the endpoint of the runtime sidecar reporting the startup progress of the engine.
+func runtimeStartupEndpoint(backend *workload.ModelBackend) string {
+	return fmt.Sprintf(":%d%s", env.GetEnvValueOrDefault[int32](backend, env.RuntimePort, 8100), RuntimeStartupPath)
+}
+
 // mapWorkers creates a map of workers by type.
 func mapWorkers(workers []workload.ModelWorker) map[workload.ModelWorkerType]*workload.ModelWorker {
 	workersMap := make(map[workload.ModelWorkerType]*workload.ModelWorker, len(workers))
@@ -415,6 +412,76 @@ func loadModelServingTemplate(templatePath string, data *map[string]interface{})
 	return modelServing, nil
 }
 
+// buildModelDownloaderContainer builds the init container downloading the model of the backend.
+func buildModelDownloaderContainer(model *workload.ModelBooster, backend *workload.ModelBackend, cacheVolumeName, outputDir string) corev1.Container {
+	var envVars []corev1.EnvVar
+	endpointEnvVars := env.GetEnvValueOrDefault[[]corev1.EnvVar](backend, env.Endpoint, []corev1.EnvVar{
+		{Name: env.Endpoint},
+	})
+	if len(endpointEnvVars) > 0 && endpointEnvVars[0].Value != "" {
+		envVars = append(envVars, endpointEnvVars[0])
+	}
+	hfEndpointEnvVars := env.GetEnvValueOrDefault[[]corev1.EnvVar](backend, env.HfEndpoint, []corev1.EnvVar{
+		{Name: env.HfEndpoint},
+	})
+	if len(hfEndpointEnvVars) > 0 && hfEndpointEnvVars[0].Value != "" {
+		envVars = append(envVars, hfEndpointEnvVars[0])
+	}
+	// The downloader reports its progress as an annotation on its own pod.
+	envVars = append(envVars,
+		corev1.EnvVar{
+			Name: "POD_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+			},
+		},
+		corev1.EnvVar{
+			Name: "POD_NAMESPACE",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+			},
+		},
+	)
+	return corev1.Container{
+		Name:    model.Name + "-model-downloader",
+		Image:   config.Config.DownloaderImage(),
+		Args:    buildModelDownloaderArgs(backend, outputDir),
+		Env:     envVars,
+		EnvFrom: backend.EnvFrom,
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      cacheVolumeName,
+			MountPath: GetCachePath(backend.CacheURI),
+		}},
+	}
+}
+
+// buildModelDownloaderArgs builds the downloader arguments from the download policy of the backend.
+func buildModelDownloaderArgs(backend *workload.ModelBackend, outputDir string) []string {
+	args := []string{
+		"--source", backend.ModelURI,
+		"--output-dir", outputDir,
+	}
+	policy := backend.Download
+	if policy == nil {
+		return args
+	}
+	if len(policy.Checksums) > 0 {
+		// Marshalling a map of strings cannot fail, and sorts the keys so the pod template is stable.
+		checksums, _ := json.Marshal(policy.Checksums)
+		args = append(args, "--checksums", string(checksums))
+	}
+	if policy.MaxConcurrency != nil {
+		args = append(args, "--max-workers", strconv.Itoa(int(*policy.MaxConcurrency)))
+	}
+	if policy.ChunkSizeMB != nil {
+		args = append(args, "--chunk-size-mb", strconv.Itoa(int(*policy.ChunkSizeMB)))
+	}
+	if policy.MaxRetries != nil {
+		args = append(args, "--max-retries", strconv.Itoa(int(*policy.MaxRetries)))
+	}
+	return args
+}
+
 // buildDownloaderContainer builds downloader container to reduce code duplication
 func buildDownloaderContainer(name, image, source, outputDir string, backend *workload.ModelBackend, cacheVolumeName string) corev1.Container {
 	return corev1.Container{
@@ -22,6 +22,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-booster-controller/utils"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 )
@@ -201,3 +202,23 @@ func TestBuildCacheVolume(t *testing.T) {
 		})
 	}
 }
+
+func TestBackendRevision(t *testing.T) {
+	backend := &workload.ModelBackend{
+		Name:     "backend1",
+		Type:     workload.ModelBackendTypeVLLM,
+		ModelURI: "hf://Qwen/Qwen2.5-0.5B-Instruct",
+		Workers:  []workload.ModelWorker{{Type: workload.ModelWorkerTypeServer, Image: "vllm"}},
+	}
+	revision := utils.BackendRevision(backend)
+
+	// The optional fields left unset do not change the revision
+	unset := backend.DeepCopy()
+	unset.LoraAdapters = []workload.BackendLoraAdapter{}
+	unset.Download = nil
+	assert.Equal(t, revision, utils.BackendRevision(unset))
+
+	changed := backend.DeepCopy()
+	changed.LoraAdapters = []workload.BackendLoraAdapter{{Name: "sql", ArtifactURL: "hf://org/sql-lora"}}
+	assert.NotEqual(t, revision, utils.BackendRevision(changed))
+}
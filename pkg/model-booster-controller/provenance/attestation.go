@@ -0,0 +1,180 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	// inTotoPayloadType is the DSSE payload type of the in-toto statements.
+	inTotoPayloadType = "application/vnd.in-toto+json"
+
+	inTotoStatementV1  = "https://in-toto.io/Statement/v1"
+	inTotoStatementV01 = "https://in-toto.io/Statement/v0.1"
+)
+
+// envelope is a DSSE envelope.
+type envelope struct {
+	PayloadType string              `json:"payloadType"`
+	Payload     string              `json:"payload"`
+	Signatures  []envelopeSignature `json:"signatures"`
+}
+
+type envelopeSignature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   string `json:"sig"`
+}
+
+// bundle is the part of a Sigstore bundle carrying the DSSE envelope.
+type bundle struct {
+	MediaType    string    `json:"mediaType"`
+	DSSEEnvelope *envelope `json:"dsseEnvelope"`
+}
+
+// statement is an in-toto statement.
+type statement struct {
+	Type          string    `json:"_type"`
+	PredicateType string    `json:"predicateType"`
+	Subject       []subject `json:"subject"`
+}
+
+type subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// verifyAttestation checks that the DSSE envelope, or the Sigstore bundle, is signed by all the signers, or
+// by one of the keys of the policy when there are none, and returns the SHA256 digests of its subjects by their path.
+func verifyAttestation(data []byte, policy *Policy, signers []string) (map[string]string, error) {
+	var b bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid attestation: %v", err)
+	}
+	env := b.DSSEEnvelope
+	if env == nil {
+		env = &envelope{}
+		if err := json.Unmarshal(data, env); err != nil {
+			return nil, fmt.Errorf("invalid attestation: %v", err)
+		}
+	}
+	if env.PayloadType != inTotoPayloadType {
+		return nil, fmt.Errorf("unsupported attestation payload type %q, %q is expected", env.PayloadType, inTotoPayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attestation payload: %v", err)
+	}
+	var sigs [][]byte
+	for _, s := range env.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attestation signature: %v", err)
+		}
+		sigs = append(sigs, sig)
+	}
+	if err := checkSigners(pae(env.PayloadType, payload), sigs, policy, signers); err != nil {
+		return nil, err
+	}
+
+	var st statement
+	if err := json.Unmarshal(payload, &st); err != nil {
+		return nil, fmt.Errorf("invalid in-toto statement: %v", err)
+	}
+	if st.Type != inTotoStatementV1 && st.Type != inTotoStatementV01 {
+		return nil, fmt.Errorf("unsupported in-toto statement type %q", st.Type)
+	}
+	digests := make(map[string]string, len(st.Subject))
+	for _, sub := range st.Subject {
+		name := path.Clean(strings.TrimPrefix(sub.Name, "./"))
+		if name == "." || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("invalid subject %q, a path relative to the model root is expected", sub.Name)
+		}
+		digest := strings.ToLower(sub.Digest["sha256"])
+		if b, err := hex.DecodeString(digest); err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("subject %q has no valid sha256 digest", sub.Name)
+		}
+		digests[name] = digest
+	}
+	if len(digests) == 0 {
+		return nil, fmt.Errorf("the in-toto statement has no subject")
+	}
+	return digests, nil
+}
+
+// pae is the pre-authentication encoding of DSSE, the bytes actually signed.
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// checkSigners checks that each signer has a valid signature of the data among sigs, or when there are no
+// signers, that one of the keys of the policy has.
+func checkSigners(data []byte, sigs [][]byte, policy *Policy, signers []string) error {
+	if len(signers) == 0 {
+		for _, key := range policy.keys {
+			for _, sig := range sigs {
+				if verifySignature(key, data, sig) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("no valid signature of any key of the provenance policy")
+	}
+	var missing []string
+	for _, signer := range signers {
+		signed := false
+		for _, sig := range sigs {
+			if verifySignature(policy.keys[signer], data, sig) {
+				signed = true
+				break
+			}
+		}
+		if !signed {
+			missing = append(missing, signer)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("no valid signature of %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// verifySignature verifies the signature of the data with the key, as signed by cosign: ECDSA and RSA
+// over the SHA256 digest of the data, Ed25519 over the data itself.
+func verifySignature(key crypto.PublicKey, data, sig []byte) bool {
+	digest := sha256.Sum256(data)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, digest[:], sig)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil ||
+			rsa.VerifyPSS(k, crypto.SHA256, digest[:], sig, nil) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, data, sig)
+	}
+	return false
+}
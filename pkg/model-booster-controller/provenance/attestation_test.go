@@ -0,0 +1,195 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+// newPolicy returns the parsed policy document of the keys and the required signers.
+func newPolicy(t *testing.T, keys map[string]crypto.PublicKey, imageSigners, modelSigners []string, insecureRegistries ...string) *Policy {
+	policy := Policy{
+		Keys:               make(map[string]string, len(keys)),
+		Images:             ArtifactPolicy{RequiredSigners: imageSigners},
+		Models:             ArtifactPolicy{RequiredSigners: modelSigners},
+		InsecureRegistries: insecureRegistries,
+	}
+	for name, key := range keys {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		require.NoError(t, err)
+		policy.Keys[name] = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	}
+	data, err := yaml.Marshal(policy)
+	require.NoError(t, err)
+	parsed, err := ParsePolicy(data)
+	require.NoError(t, err)
+	return parsed
+}
+
+// sign signs the data as cosign does.
+func sign(t *testing.T, key crypto.Signer, data []byte) []byte {
+	var sig []byte
+	var err error
+	switch key.(type) {
+	case ed25519.PrivateKey:
+		sig, err = key.Sign(rand.Reader, data, crypto.Hash(0))
+	default:
+		digest := sha256.Sum256(data)
+		sig, err = key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	require.NoError(t, err)
+	return sig
+}
+
+func digestOf(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// newEnvelope returns a DSSE envelope of an in-toto statement of the subjects, signed by the keys.
+func newEnvelope(t *testing.T, subjects map[string]string, keys ...crypto.Signer) []byte {
+	st := statement{Type: inTotoStatementV1, PredicateType: "https://slsa.dev/provenance/v1"}
+	for name, digest := range subjects {
+		st.Subject = append(st.Subject, subject{Name: name, Digest: map[string]string{"sha256": digest}})
+	}
+	payload, err := json.Marshal(st)
+	require.NoError(t, err)
+	env := envelope{PayloadType: inTotoPayloadType, Payload: base64.StdEncoding.EncodeToString(payload)}
+	for _, key := range keys {
+		env.Signatures = append(env.Signatures, envelopeSignature{
+			Sig: base64.StdEncoding.EncodeToString(sign(t, key, pae(inTotoPayloadType, payload))),
+		})
+	}
+	data, err := json.Marshal(env)
+	require.NoError(t, err)
+	return data
+}
+
+func TestVerifyAttestation(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	policy := newPolicy(t, map[string]crypto.PublicKey{
+		"publisher": ecKey.Public(),
+		"builder":   rsaKey.Public(),
+		"scanner":   edKey.Public(),
+	}, nil, []string{"publisher", "builder"})
+
+	subjects := map[string]string{"config.json": digestOf("config"), "./model.safetensors": digestOf("weights")}
+	want := map[string]string{"config.json": digestOf("config"), "model.safetensors": digestOf("weights")}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		signers []string
+		wantErr string
+	}{
+		{
+			name:    "signed by all the signers",
+			data:    newEnvelope(t, subjects, ecKey, rsaKey),
+			signers: []string{"publisher", "builder"},
+		},
+		{
+			name:    "sigstore bundle",
+			data:    []byte(`{"mediaType":"application/vnd.dev.sigstore.bundle.v0.3+json","dsseEnvelope":` + string(newEnvelope(t, subjects, ecKey, rsaKey)) + `}`),
+			signers: []string{"publisher", "builder"},
+		},
+		{
+			name:    "ed25519",
+			data:    newEnvelope(t, subjects, edKey),
+			signers: []string{"scanner"},
+		},
+		{
+			name:    "any key without required signers",
+			data:    newEnvelope(t, subjects, edKey),
+			signers: nil,
+		},
+		{
+			name:    "missing signer",
+			data:    newEnvelope(t, subjects, ecKey, otherKey),
+			signers: []string{"publisher", "builder"},
+			wantErr: "no valid signature of builder",
+		},
+		{
+			name:    "unknown key without required signers",
+			data:    newEnvelope(t, subjects, otherKey),
+			wantErr: "no valid signature of any key",
+		},
+		{
+			name:    "path outside of the model",
+			data:    newEnvelope(t, map[string]string{"../etc/passwd": digestOf("root")}, ecKey),
+			signers: []string{"publisher"},
+			wantErr: "a path relative to the model root is expected",
+		},
+		{
+			name:    "invalid digest",
+			data:    newEnvelope(t, map[string]string{"config.json": "abc"}, ecKey),
+			signers: []string{"publisher"},
+			wantErr: "no valid sha256 digest",
+		},
+		{
+			name:    "not an attestation",
+			data:    []byte(`{"payloadType":"text/plain","payload":"","signatures":[]}`),
+			wantErr: "unsupported attestation payload type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			digests, err := verifyAttestation(tt.data, policy, tt.signers)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, want, digests)
+		})
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	_, err := ParsePolicy([]byte("images:\n  requiredSigners: [platform]\n"))
+	assert.ErrorContains(t, err, `required signer "platform" has no key`)
+
+	_, err = ParsePolicy([]byte("keys:\n  platform: not a key\n"))
+	assert.ErrorContains(t, err, `invalid key "platform"`)
+
+	_, err = ParsePolicy([]byte("signers: []\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package provenance verifies the provenance of the ModelBoosters before their serving workloads are
+// generated: the cosign signatures of the container images and the in-toto attestations of the model
+// files, against the signers required by the provenance policy of their namespace.
+package provenance
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// PolicyConfigMapName is the ConfigMap holding the provenance policy, in the namespace of the ModelBoosters
+	// it applies to. The provenance of the ModelBoosters of a namespace without it is not verified.
+	PolicyConfigMapName = "kthena-provenance-policy"
+	// PolicyKey is the key of the policy document in the ConfigMap.
+	PolicyKey = "policy.yaml"
+)
+
+// Policy is the provenance policy of a namespace.
+type Policy struct {
+	// Keys maps the names of the signers to their PEM encoded public keys, ECDSA, RSA or Ed25519.
+	Keys map[string]string `json:"keys,omitempty"`
+	// Images are the requirements on the images of the workers.
+	Images ArtifactPolicy `json:"images,omitempty"`
+	// Models are the requirements on the attestations of the model files.
+	Models ArtifactPolicy `json:"models,omitempty"`
+	// InsecureRegistries are the registries, as host[:port], reached over plain HTTP.
+	InsecureRegistries []string `json:"insecureRegistries,omitempty"`
+
+	keys map[string]crypto.PublicKey
+}
+
+// ArtifactPolicy lists the signers of a kind of artifacts.
+type ArtifactPolicy struct {
+	// RequiredSigners are the names of the keys which must all have signed the artifacts.
+	// The artifacts are not verified when it is empty.
+	RequiredSigners []string `json:"requiredSigners,omitempty"`
+}
+
+// ParsePolicy parses and checks a policy document.
+func ParsePolicy(data []byte) (*Policy, error) {
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, err
+	}
+	policy.keys = make(map[string]crypto.PublicKey, len(policy.Keys))
+	for name, data := range policy.Keys {
+		key, err := parsePublicKey([]byte(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %v", name, err)
+		}
+		policy.keys[name] = key
+	}
+	for _, signers := range [][]string{policy.Images.RequiredSigners, policy.Models.RequiredSigners} {
+		for _, signer := range signers {
+			if _, ok := policy.keys[signer]; !ok {
+				return nil, fmt.Errorf("required signer %q has no key", signer)
+			}
+		}
+	}
+	return policy, nil
+}
+
+// LoadPolicy reads the provenance policy of the namespace, nil when there is none.
+func LoadPolicy(ctx context.Context, kubeClient kubernetes.Interface, namespace string) (*Policy, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, PolicyConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	policy, err := ParsePolicy([]byte(cm.Data[PolicyKey]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in ConfigMap %s/%s: %v", PolicyKey, namespace, PolicyConfigMapName, err)
+	}
+	return policy, nil
+}
+
+// signersKey identifies the required signers and their keys, so that a verification is only reused
+// as long as the policy requires the same signers.
+func (p *Policy) signersKey(signers []string) string {
+	sorted := append([]string{}, signers...)
+	sort.Strings(sorted)
+	key := ""
+	for _, signer := range sorted {
+		key += signer + "=" + p.Keys[signer] + ";"
+	}
+	return key
+}
+
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("unsupported PEM block %q, a PUBLIC KEY is expected", block.Type)
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
@@ -0,0 +1,315 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const (
+	// cosignSignatureAnnotation is the annotation of the layers of a cosign signature manifest carrying the signature.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	dockerHub         = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+
+	// maxManifestSize and maxPayloadSize bound what is read from the registries.
+	maxManifestSize = 4 << 20
+	maxPayloadSize  = 1 << 20
+)
+
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// imageReference is a parsed image name.
+type imageReference struct {
+	registry   string
+	repository string
+	// reference is the digest of the image when it is pinned, its tag otherwise.
+	reference string
+}
+
+func parseImageReference(image string) (*imageReference, error) {
+	ref := &imageReference{}
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.reference = name[:i], name[i+1:]
+		if !strings.HasPrefix(ref.reference, "sha256:") {
+			return nil, fmt.Errorf("invalid image %q: unsupported digest", image)
+		}
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		if ref.reference == "" {
+			ref.reference = name[i+1:]
+		}
+		name = name[:i]
+	}
+	if ref.reference == "" {
+		ref.reference = "latest"
+	}
+	ref.registry = dockerHub
+	if i := strings.Index(name, "/"); i >= 0 {
+		if host := name[:i]; strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.registry, name = host, name[i+1:]
+		}
+	}
+	if ref.registry == dockerHub {
+		ref.registry = dockerHubRegistry
+		if !strings.Contains(name, "/") {
+			name = "library/" + name
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("invalid image %q", image)
+	}
+	ref.repository = name
+	return ref, nil
+}
+
+// manifest is the part of an OCI manifest listing the layers.
+type manifest struct {
+	Layers []struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// simpleSigning is the payload of a cosign signature.
+type simpleSigning struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// registryClient reads the images and their cosign signatures with the OCI distribution API, as an
+// anonymous client.
+type registryClient struct {
+	httpClient *http.Client
+	policy     *Policy
+	// tokens are the bearer tokens by scope.
+	tokens map[string]string
+}
+
+// verifyImage checks that the image is signed by all the signers with cosign, and returns its digest.
+func (c *registryClient) verifyImage(ctx context.Context, image string, signers []string) (string, error) {
+	ref, err := parseImageReference(image)
+	if err != nil {
+		return "", err
+	}
+	digest, _, err := c.getManifest(ctx, ref, ref.reference)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve image %s: %v", image, err)
+	}
+	_, body, err := c.getManifest(ctx, ref, strings.Replace(digest, ":", "-", 1)+".sig")
+	if err != nil {
+		return "", fmt.Errorf("failed to get the cosign signatures of image %s: %v", image, err)
+	}
+	var m manifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return "", fmt.Errorf("invalid cosign signature manifest of image %s: %v", image, err)
+	}
+
+	var payloads [][]byte
+	var sigs [][]byte
+	for _, layer := range m.Layers {
+		encoded, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			continue
+		}
+		payload, err := c.getBlob(ctx, ref, layer.Digest)
+		if err != nil {
+			return "", fmt.Errorf("failed to get the cosign signature of image %s: %v", image, err)
+		}
+		var signing simpleSigning
+		if err := json.Unmarshal(payload, &signing); err != nil || signing.Critical.Image.DockerManifestDigest != digest {
+			// The signature of another image
+			continue
+		}
+		payloads = append(payloads, payload)
+		sigs = append(sigs, sig)
+	}
+
+	var missing []string
+	for _, signer := range signers {
+		signed := false
+		for i := range payloads {
+			if verifySignature(c.policy.keys[signer], payloads[i], sigs[i]) {
+				signed = true
+				break
+			}
+		}
+		if !signed {
+			missing = append(missing, signer)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("image %s has no valid cosign signature of %s", image, strings.Join(missing, ", "))
+	}
+	return digest, nil
+}
+
+// getManifest returns the digest and the content of a manifest.
+func (c *registryClient) getManifest(ctx context.Context, ref *imageReference, reference string) (string, []byte, error) {
+	resp, err := c.get(ctx, ref, "manifests/"+reference, strings.Join(manifestMediaTypes, ", "))
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	if err != nil {
+		return "", nil, err
+	}
+	sum := sha256.Sum256(body)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	if strings.HasPrefix(reference, "sha256:") && digest != reference {
+		return "", nil, fmt.Errorf("manifest digest %s does not match %s", digest, reference)
+	}
+	return digest, body, nil
+}
+
+// getBlob returns the content of a blob, checked against its digest.
+func (c *registryClient) getBlob(ctx context.Context, ref *imageReference, digest string) ([]byte, error) {
+	resp, err := c.get(ctx, ref, "blobs/"+digest, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPayloadSize))
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	if "sha256:"+hex.EncodeToString(sum[:]) != digest {
+		return nil, fmt.Errorf("blob digest does not match %s", digest)
+	}
+	return body, nil
+}
+
+// get sends a GET request to the registry API of the repository, requesting an anonymous token when the
+// registry asks for one.
+func (c *registryClient) get(ctx context.Context, ref *imageReference, apiPath, accept string) (*http.Response, error) {
+	scheme := "https"
+	if slices.Contains(c.policy.InsecureRegistries, ref.registry) {
+		scheme = "http"
+	}
+	u := fmt.Sprintf("%s://%s/v2/%s/%s", scheme, ref.registry, ref.repository, apiPath)
+	scope := "repository:" + ref.repository + ":pull"
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if token, ok := c.tokens[scope]; ok {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return nil, fmt.Errorf("GET %s: %s", u, resp.Status)
+		}
+		token, err := c.requestToken(ctx, resp.Header.Get("WWW-Authenticate"), scope)
+		if err != nil {
+			return nil, fmt.Errorf("GET %s: %v", u, err)
+		}
+		c.tokens[scope] = token
+	}
+}
+
+// requestToken requests an anonymous token from the authorization server of a Bearer challenge.
+func (c *registryClient) requestToken(ctx context.Context, challenge, scope string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+	values := parseChallenge(params)
+	realm := values["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("authentication challenge %q has no realm", challenge)
+	}
+	query := url.Values{"scope": {scope}}
+	if service := values["service"]; service != "" {
+		query.Set("service", service)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request failed: %s", resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxPayloadSize)).Decode(&token); err != nil {
+		return "", fmt.Errorf("invalid token response: %v", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+// parseChallenge parses the comma separated key="value" parameters of a WWW-Authenticate challenge.
+func parseChallenge(params string) map[string]string {
+	values := make(map[string]string)
+	for params != "" {
+		var key, value string
+		key, params, _ = strings.Cut(strings.TrimLeft(params, ", "), "=")
+		if strings.HasPrefix(params, `"`) {
+			value, params, _ = strings.Cut(params[1:], `"`)
+		} else {
+			value, params, _ = strings.Cut(params, ",")
+		}
+		values[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return values
+}
@@ -0,0 +1,219 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+// imageVerificationTTL is how long the verification of an image is reused before its tag is resolved
+// and its signatures are checked again.
+const imageVerificationTTL = 10 * time.Minute
+
+// Verifier verifies the provenance of the ModelBoosters.
+type Verifier struct {
+	kubeClient kubernetes.Interface
+	httpClient *http.Client
+
+	mutex sync.Mutex
+	// images are the verified images by image and signers.
+	images map[string]verifiedImage
+	now    func() time.Time
+}
+
+type verifiedImage struct {
+	digest  string
+	expires time.Time
+}
+
+// Result is the outcome of a successful verification.
+type Result struct {
+	// Images maps the verified images of the workers to their digest.
+	Images map[string]string
+	// Checksums maps the backends with an attestation to the digests of their model files, merged with
+	// the checksums of their download policy.
+	Checksums map[string]map[string]string
+}
+
+func NewVerifier(kubeClient kubernetes.Interface, httpClient *http.Client) *Verifier {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Verifier{
+		kubeClient: kubeClient,
+		httpClient: httpClient,
+		images:     make(map[string]verifiedImage),
+		now:        time.Now,
+	}
+}
+
+// Verify checks the images and the model attestations of the ModelBooster against the provenance policy of
+// its namespace. It returns nil without error when the namespace has no policy.
+func (v *Verifier) Verify(ctx context.Context, model *workload.ModelBooster) (*Result, error) {
+	policy, err := LoadPolicy(ctx, v.kubeClient, model.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		for _, backend := range model.Spec.Backends {
+			if backend.Download != nil && backend.Download.Attestation != nil {
+				return nil, fmt.Errorf("backend %s references an attestation, but there is no provenance policy, ConfigMap %s, in namespace %s to verify it",
+					backend.Name, PolicyConfigMapName, model.Namespace)
+			}
+		}
+		return nil, nil
+	}
+
+	result := &Result{Images: make(map[string]string), Checksums: make(map[string]map[string]string)}
+	registry := &registryClient{httpClient: v.httpClient, policy: policy, tokens: make(map[string]string)}
+	for _, backend := range model.Spec.Backends {
+		if len(policy.Images.RequiredSigners) > 0 {
+			for _, worker := range backend.Workers {
+				if worker.Image == "" || result.Images[worker.Image] != "" {
+					continue
+				}
+				digest, err := v.verifyImage(ctx, registry, policy, worker.Image)
+				if err != nil {
+					return nil, err
+				}
+				result.Images[worker.Image] = digest
+			}
+		}
+
+		checksums, err := v.verifyModel(ctx, model.Namespace, policy, &backend)
+		if err != nil {
+			return nil, fmt.Errorf("backend %s: %v", backend.Name, err)
+		}
+		if checksums != nil {
+			result.Checksums[backend.Name] = checksums
+		}
+	}
+	return result, nil
+}
+
+// verifyImage verifies the image, reusing a previous verification with the same signers.
+func (v *Verifier) verifyImage(ctx context.Context, registry *registryClient, policy *Policy, image string) (string, error) {
+	key := image + "|" + policy.signersKey(policy.Images.RequiredSigners)
+	v.mutex.Lock()
+	cached, ok := v.images[key]
+	v.mutex.Unlock()
+	if ok && v.now().Before(cached.expires) {
+		return cached.digest, nil
+	}
+
+	digest, err := registry.verifyImage(ctx, image, policy.Images.RequiredSigners)
+	if err != nil {
+		return "", err
+	}
+	klog.V(4).Infof("Verified the signatures of image %s, digest %s", image, digest)
+	v.mutex.Lock()
+	v.images[key] = verifiedImage{digest: digest, expires: v.now().Add(imageVerificationTTL)}
+	v.mutex.Unlock()
+	return digest, nil
+}
+
+// verifyModel verifies the attestation of the model of the backend, and returns the checksums of its files,
+// nil when it has no attestation.
+func (v *Verifier) verifyModel(ctx context.Context, namespace string, policy *Policy, backend *workload.ModelBackend) (map[string]string, error) {
+	var selector *corev1.ConfigMapKeySelector
+	if backend.Download != nil {
+		selector = backend.Download.Attestation
+	}
+	var data []byte
+	if selector != nil {
+		cm, err := v.kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, selector.Name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if cm != nil {
+			if value, ok := cm.Data[selector.Key]; ok {
+				data = []byte(value)
+			} else if value, ok := cm.BinaryData[selector.Key]; ok {
+				data = value
+			}
+		}
+		if data == nil && (selector.Optional == nil || !*selector.Optional) {
+			return nil, fmt.Errorf("attestation %s not found in ConfigMap %s/%s", selector.Key, namespace, selector.Name)
+		}
+	}
+	if data == nil {
+		if len(policy.Models.RequiredSigners) > 0 {
+			return nil, fmt.Errorf("the provenance policy requires an attestation of the model signed by %s",
+				strings.Join(policy.Models.RequiredSigners, ", "))
+		}
+		return nil, nil
+	}
+
+	digests, err := verifyAttestation(data, policy, policy.Models.RequiredSigners)
+	if err != nil {
+		return nil, err
+	}
+	checksums := make(map[string]string, len(digests))
+	for name, digest := range digests {
+		checksums[name] = digest
+	}
+	for name, checksum := range backend.Download.Checksums {
+		if digest, ok := digests[name]; ok && !strings.EqualFold(digest, checksum) {
+			return nil, fmt.Errorf("checksum of %s does not match the digest %s of the attestation", name, digest)
+		}
+		if _, ok := digests[name]; !ok {
+			checksums[name] = checksum
+		}
+	}
+	return checksums, nil
+}
+
+// Apply returns a copy of the ModelBooster with the images of the workers pinned to their verified digest
+// and the checksums of the model files set from the attestations, so that the serving workloads run what
+// was verified.
+func (r *Result) Apply(model *workload.ModelBooster) *workload.ModelBooster {
+	model = model.DeepCopy()
+	for i := range model.Spec.Backends {
+		backend := &model.Spec.Backends[i]
+		for j := range backend.Workers {
+			worker := &backend.Workers[j]
+			if digest, ok := r.Images[worker.Image]; ok && !strings.Contains(worker.Image, "@") {
+				worker.Image += "@" + digest
+			}
+		}
+		if checksums, ok := r.Checksums[backend.Name]; ok {
+			if backend.Download == nil {
+				backend.Download = &workload.ModelDownloadPolicy{}
+			}
+			backend.Download.Checksums = checksums
+		}
+	}
+	return model
+}
+
+// String describes what was verified, for the status condition of the ModelBooster.
+func (r *Result) String() string {
+	return fmt.Sprintf("Verified the signatures of %d images and the attestations of %d backends", len(r.Images), len(r.Checksums))
+}
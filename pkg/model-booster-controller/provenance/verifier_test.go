@@ -0,0 +1,287 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+// fakeRegistry serves an image and its cosign signatures, to clients holding an anonymous token.
+type fakeRegistry struct {
+	server    *httptest.Server
+	manifests map[string][]byte
+	blobs     map[string][]byte
+}
+
+func newFakeRegistry(t *testing.T) *fakeRegistry {
+	r := &fakeRegistry{manifests: make(map[string][]byte), blobs: make(map[string][]byte)}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			assert.Equal(t, "repository:team/llm:pull", req.URL.Query().Get("scope"))
+			_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+			return
+		}
+		if req.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+r.server.URL+`/token",service="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var content []byte
+		var ok bool
+		if reference, found := strings.CutPrefix(req.URL.Path, "/v2/team/llm/manifests/"); found {
+			content, ok = r.manifests[reference]
+		} else if digest, found := strings.CutPrefix(req.URL.Path, "/v2/team/llm/blobs/"); found {
+			content, ok = r.blobs[digest]
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *fakeRegistry) host() string {
+	u, _ := url.Parse(r.server.URL)
+	return u.Host
+}
+
+// push adds an image with the tag, signed with cosign by the keys, and returns its digest.
+func (r *fakeRegistry) push(t *testing.T, tag string, keys ...crypto.Signer) string {
+	image := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"digest":"sha256:` + tag + `"}}`)
+	digest := "sha256:" + sha256Hex(image)
+	r.manifests[tag] = image
+	r.manifests[digest] = image
+
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"` + r.host() + `/team/llm"},"image":{"docker-manifest-digest":"` + digest + `"},"type":"cosign container image signature"},"optional":null}`)
+	payloadDigest := "sha256:" + sha256Hex(payload)
+	r.blobs[payloadDigest] = payload
+	var signatures manifest
+	for _, key := range keys {
+		layer := struct {
+			Digest      string            `json:"digest"`
+			Annotations map[string]string `json:"annotations"`
+		}{Digest: payloadDigest, Annotations: map[string]string{
+			cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+		}}
+		signatures.Layers = append(signatures.Layers, layer)
+	}
+	data, err := json.Marshal(signatures)
+	require.NoError(t, err)
+	r.manifests[strings.Replace(digest, ":", "-", 1)+".sig"] = data
+	return digest
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func policyConfigMap(t *testing.T, policy *Policy) *corev1.ConfigMap {
+	data, err := yaml.Marshal(policy)
+	require.NoError(t, err)
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: PolicyConfigMapName, Namespace: "default"},
+		Data:       map[string]string{PolicyKey: string(data)},
+	}
+}
+
+func newModelBooster(image string, download *workload.ModelDownloadPolicy) *workload.ModelBooster {
+	return &workload.ModelBooster{
+		ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "default"},
+		Spec: workload.ModelBoosterSpec{
+			Backends: []workload.ModelBackend{{
+				Name:     "vllm",
+				Type:     workload.ModelBackendTypeVLLM,
+				ModelURI: "hf://meta-llama/Llama-3.1-8B",
+				Download: download,
+				Workers:  []workload.ModelWorker{{Type: workload.ModelWorkerTypeServer, Image: image}},
+			}},
+		},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	platformKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	publisherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	registry := newFakeRegistry(t)
+	signedDigest := registry.push(t, "v1", platformKey)
+	registry.push(t, "v2", otherKey)
+	policy := newPolicy(t, map[string]crypto.PublicKey{
+		"platform":  platformKey.Public(),
+		"publisher": publisherKey.Public(),
+	}, []string{"platform"}, []string{"publisher"}, registry.host())
+
+	attestation := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "llama-attestation", Namespace: "default"},
+		Data: map[string]string{
+			"publisher.json": string(newEnvelope(t, map[string]string{"model.safetensors": digestOf("weights")}, publisherKey)),
+			"other.json":     string(newEnvelope(t, map[string]string{"model.safetensors": digestOf("weights")}, otherKey)),
+		},
+	}
+	selector := func(key string) *workload.ModelDownloadPolicy {
+		return &workload.ModelDownloadPolicy{
+			Attestation: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "llama-attestation"}, Key: key},
+			Checksums:   map[string]string{"tokenizer.json": digestOf("tokenizer")},
+		}
+	}
+	image := registry.host() + "/team/llm"
+
+	tests := []struct {
+		name          string
+		model         *workload.ModelBooster
+		wantImage     string
+		wantChecksums map[string]string
+		wantErr       string
+	}{
+		{
+			name:          "signed image and attested model",
+			model:         newModelBooster(image+":v1", selector("publisher.json")),
+			wantImage:     image + ":v1@" + signedDigest,
+			wantChecksums: map[string]string{"model.safetensors": digestOf("weights"), "tokenizer.json": digestOf("tokenizer")},
+		},
+		{
+			name:    "image signed by another key",
+			model:   newModelBooster(image+":v2", selector("publisher.json")),
+			wantErr: "has no valid cosign signature of platform",
+		},
+		{
+			name:    "unknown image",
+			model:   newModelBooster(image+":v3", selector("publisher.json")),
+			wantErr: "404 Not Found",
+		},
+		{
+			name:    "model attested by another key",
+			model:   newModelBooster(image+":v1", selector("other.json")),
+			wantErr: "backend vllm: no valid signature of publisher",
+		},
+		{
+			name:    "model without attestation",
+			model:   newModelBooster(image+":v1", nil),
+			wantErr: "requires an attestation of the model signed by publisher",
+		},
+		{
+			name: "checksum conflicting with the attestation",
+			model: newModelBooster(image+":v1", &workload.ModelDownloadPolicy{
+				Attestation: selector("publisher.json").Attestation,
+				Checksums:   map[string]string{"model.safetensors": digestOf("other weights")},
+			}),
+			wantErr: "checksum of model.safetensors does not match",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(policyConfigMap(t, policy), attestation)
+			result, err := NewVerifier(kubeClient, nil).Verify(context.Background(), tt.model)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			model := result.Apply(tt.model)
+			assert.Equal(t, tt.wantImage, model.Spec.Backends[0].Workers[0].Image)
+			assert.Equal(t, tt.wantChecksums, model.Spec.Backends[0].Download.Checksums)
+			assert.Equal(t, image+":v1", tt.model.Spec.Backends[0].Workers[0].Image, "the model itself is not changed")
+		})
+	}
+}
+
+func TestVerifyWithoutPolicy(t *testing.T) {
+	verifier := NewVerifier(fake.NewSimpleClientset(), nil)
+	result, err := verifier.Verify(context.Background(), newModelBooster("vllm/vllm-openai:latest", nil))
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	_, err = verifier.Verify(context.Background(), newModelBooster("vllm/vllm-openai:latest", &workload.ModelDownloadPolicy{
+		Attestation: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "attestation"}, Key: "bundle.json"},
+	}))
+	assert.ErrorContains(t, err, "there is no provenance policy")
+}
+
+func TestVerifyImageCache(t *testing.T) {
+	platformKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	registry := newFakeRegistry(t)
+	digest := registry.push(t, "v1", platformKey)
+	policy := newPolicy(t, map[string]crypto.PublicKey{"platform": platformKey.Public()}, []string{"platform"}, nil, registry.host())
+
+	now := time.Now()
+	verifier := NewVerifier(fake.NewSimpleClientset(policyConfigMap(t, policy)), nil)
+	verifier.now = func() time.Time { return now }
+	model := newModelBooster(registry.host()+"/team/llm:v1", nil)
+	result, err := verifier.Verify(context.Background(), model)
+	require.NoError(t, err)
+	assert.Equal(t, digest, result.Images[registry.host()+"/team/llm:v1"])
+
+	// The verification is reused while the registry is unreachable, until it expires
+	registry.server.Close()
+	_, err = verifier.Verify(context.Background(), model)
+	require.NoError(t, err)
+	now = now.Add(imageVerificationTTL)
+	_, err = verifier.Verify(context.Background(), model)
+	assert.Error(t, err)
+}
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		image string
+		want  imageReference
+	}{
+		{"vllm/vllm-openai", imageReference{registry: dockerHubRegistry, repository: "vllm/vllm-openai", reference: "latest"}},
+		{"ubuntu:24.04", imageReference{registry: dockerHubRegistry, repository: "library/ubuntu", reference: "24.04"}},
+		{"ghcr.io/volcano-sh/runtime:v1@sha256:abc", imageReference{registry: "ghcr.io", repository: "volcano-sh/runtime", reference: "sha256:abc"}},
+		{"localhost:5000/llm:v1", imageReference{registry: "localhost:5000", repository: "llm", reference: "v1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			ref, err := parseImageReference(tt.image)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *ref)
+		})
+	}
+}
@@ -19,11 +19,13 @@ package utils
 import (
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"os"
 
 	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/utils/ptr"
 )
 
@@ -44,6 +46,17 @@ func TryGetField(config []byte, key string) (any, error) {
 	return configMap[key], nil
 }
 
+// BackendRevision returns the revision of the resources generated from a backend. It hashes the JSON
+// serialization of the backend, which depends neither on the names of its Go types nor on the optional
+// fields left unset, so that extending the API does not roll out the ServingGroups of the existing backends.
+func BackendRevision(backend *workloadv1alpha1.ModelBackend) string {
+	hasher := fnv.New32()
+	// Marshalling the API types does not fail
+	data, _ := json.Marshal(backend)
+	hasher.Write(data)
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+}
+
 func GetDeviceNum(worker *workloadv1alpha1.ModelWorker) int64 {
 	sum := int64(0)
 	if worker.Resources.Requests != nil {
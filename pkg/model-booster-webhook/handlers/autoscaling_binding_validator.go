@@ -85,7 +85,9 @@ func (v *AutoscalingBindingValidator) validateAutoscalingBinding(asp_binding *wo
 	var allErrs field.ErrorList
 
 	allErrs = append(allErrs, validateOptimizeAndScalingPolicyExistence(asp_binding)...)
+	allErrs = append(allErrs, validateTargetNamespace(asp_binding)...)
 	allErrs = append(allErrs, v.validateAutoscalingPolicyExistence(ctx, asp_binding)...)
+	allErrs = append(allErrs, v.validateTargetRole(ctx, asp_binding)...)
 
 	if len(allErrs) > 0 {
 		// Convert field errors to a formatted multi-line error message
@@ -122,3 +124,60 @@ func validateOptimizeAndScalingPolicyExistence(asp_binding *workloadv1alpha1.Aut
 	}
 	return allErrs
 }
+
+// validateTargetNamespace validates that the targets are in the namespace of the binding. The autoscaler looks
+// the targets up in the namespace of the binding, a binding cannot scale the workloads of another namespace.
+func validateTargetNamespace(asp_binding *workloadv1alpha1.AutoscalingPolicyBinding) field.ErrorList {
+	var allErrs field.ErrorList
+	check := func(target *workloadv1alpha1.Target, fldPath *field.Path) {
+		if ns := target.TargetRef.Namespace; ns != "" && ns != asp_binding.Namespace {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("targetRef").Child("namespace"),
+				fmt.Sprintf("the target must be in the namespace of the binding %s, got %s", asp_binding.Namespace, ns)))
+		}
+	}
+	if asp_binding.Spec.ScalingConfiguration != nil {
+		check(&asp_binding.Spec.ScalingConfiguration.Target, field.NewPath("spec").Child("scalingConfiguration").Child("target"))
+	}
+	if asp_binding.Spec.OptimizerConfiguration != nil {
+		for i := range asp_binding.Spec.OptimizerConfiguration.Params {
+			check(&asp_binding.Spec.OptimizerConfiguration.Params[i].Target,
+				field.NewPath("spec").Child("optimizerConfiguration").Child("params").Index(i).Child("target"))
+		}
+	}
+	return allErrs
+}
+
+// validateTargetRole validates that roles are only targeted by scaling configurations, and exist in the target ModelServing
+func (v *AutoscalingBindingValidator) validateTargetRole(ctx context.Context, asp_binding *workloadv1alpha1.AutoscalingPolicyBinding) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if asp_binding.Spec.OptimizerConfiguration != nil {
+		for i, param := range asp_binding.Spec.OptimizerConfiguration.Params {
+			if param.Target.RoleName != "" {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("optimizerConfiguration").Child("params").Index(i).Child("target").Child("roleName"),
+					"roleName is only supported in scalingConfiguration"))
+			}
+		}
+	}
+
+	if asp_binding.Spec.ScalingConfiguration == nil || asp_binding.Spec.ScalingConfiguration.Target.RoleName == "" {
+		return allErrs
+	}
+	target := asp_binding.Spec.ScalingConfiguration.Target
+	rolePath := field.NewPath("spec").Child("scalingConfiguration").Child("target").Child("roleName")
+	modelServing, err := v.client.WorkloadV1alpha1().ModelServings(asp_binding.Namespace).Get(ctx, target.TargetRef.Name, metav1.GetOptions{})
+	if err != nil {
+		// The ModelServing may be created after the binding
+		if !apierrors.IsNotFound(err) {
+			allErrs = append(allErrs, field.InternalError(rolePath, err))
+		}
+		return allErrs
+	}
+	for _, role := range modelServing.Spec.Template.Roles {
+		if role.Name == target.RoleName {
+			return allErrs
+		}
+	}
+	allErrs = append(allErrs, field.Invalid(rolePath, target.RoleName, fmt.Sprintf("role %s does not exist in model serving %s", target.RoleName, target.TargetRef.Name)))
+	return allErrs
+}
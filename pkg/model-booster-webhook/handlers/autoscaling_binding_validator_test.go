@@ -34,6 +34,16 @@ func TestValidateAutoscalingBinding(t *testing.T) {
 			Namespace: "default",
 		},
 		Spec: v1alpha1.AutoscalingPolicySpec{},
+	}, &v1alpha1.ModelServing{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pd-serving",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.ModelServingSpec{
+			Template: v1alpha1.ServingGroup{
+				Roles: []v1alpha1.Role{{Name: "prefill"}, {Name: "decode"}},
+			},
+		},
 	})
 	validator := NewAutoscalingBindingValidator(fakeClient)
 
@@ -122,6 +132,109 @@ func TestValidateAutoscalingBinding(t *testing.T) {
 			},
 			expected: []string{"  - spec.PolicyRef: Invalid value: \"not-exist-policy\": autoscaling policy resource not-exist-policy does not exist"},
 		},
+		{
+			name: "scaling config targets an existing role",
+			input: &v1alpha1.AutoscalingPolicyBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "decode-binding",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.AutoscalingPolicyBindingSpec{
+					PolicyRef: corev1.LocalObjectReference{
+						Name: "dummy-policy",
+					},
+					ScalingConfiguration: &v1alpha1.ScalingConfiguration{
+						Target: v1alpha1.Target{
+							TargetRef: corev1.ObjectReference{
+								Name: "pd-serving",
+							},
+							RoleName: "decode",
+						},
+						MinReplicas: 1,
+						MaxReplicas: 4,
+					},
+				},
+			},
+		},
+		{
+			name: "scaling config targets a missing role",
+			input: &v1alpha1.AutoscalingPolicyBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "decode-binding",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.AutoscalingPolicyBindingSpec{
+					PolicyRef: corev1.LocalObjectReference{
+						Name: "dummy-policy",
+					},
+					ScalingConfiguration: &v1alpha1.ScalingConfiguration{
+						Target: v1alpha1.Target{
+							TargetRef: corev1.ObjectReference{
+								Name: "pd-serving",
+							},
+							RoleName: "worker",
+						},
+						MinReplicas: 1,
+						MaxReplicas: 4,
+					},
+				},
+			},
+			expected: []string{"  - spec.scalingConfiguration.target.roleName: Invalid value: \"worker\": role worker does not exist in model serving pd-serving"},
+		},
+		{
+			name: "optimizer config targets a role",
+			input: &v1alpha1.AutoscalingPolicyBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "decode-binding",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.AutoscalingPolicyBindingSpec{
+					PolicyRef: corev1.LocalObjectReference{
+						Name: "dummy-policy",
+					},
+					OptimizerConfiguration: &v1alpha1.OptimizerConfiguration{
+						Params: []v1alpha1.OptimizerParam{
+							{
+								Target: v1alpha1.Target{
+									TargetRef: corev1.ObjectReference{
+										Name: "pd-serving",
+									},
+									RoleName: "decode",
+								},
+								MinReplicas: 1,
+								MaxReplicas: 2,
+							},
+						},
+					},
+				},
+			},
+			expected: []string{"  - spec.optimizerConfiguration.params[0].target.roleName: Forbidden: roleName is only supported in scalingConfiguration"},
+		},
+		{
+			name: "scaling config targets another namespace",
+			input: &v1alpha1.AutoscalingPolicyBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cross-namespace-binding",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.AutoscalingPolicyBindingSpec{
+					PolicyRef: corev1.LocalObjectReference{
+						Name: "dummy-policy",
+					},
+					ScalingConfiguration: &v1alpha1.ScalingConfiguration{
+						Target: v1alpha1.Target{
+							TargetRef: corev1.ObjectReference{
+								Name:      "pd-serving",
+								Namespace: "team-b",
+							},
+						},
+						MinReplicas: 1,
+						MaxReplicas: 4,
+					},
+				},
+			},
+			expected: []string{"  - spec.scalingConfiguration.target.targetRef.namespace: Forbidden: the target must be in the namespace of the binding default, got team-b"},
+		},
 	}
 
 	for _, tt := range tests {
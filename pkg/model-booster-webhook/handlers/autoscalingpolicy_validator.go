@@ -94,6 +94,12 @@ func (v *AutoscalingPolicyValidator) validateAutoscalingPolicy(policy *registryv
 	// Validate scale up behavior
 	allErrs = append(allErrs, v.validateScaleUpBehavior(policy)...)
 
+	// Validate predictive scaling
+	allErrs = append(allErrs, v.validatePredictive(policy)...)
+
+	// Validate vertical recommendation
+	allErrs = append(allErrs, v.validateVerticalRecommendation(policy)...)
+
 	if len(allErrs) > 0 {
 		var messages []string
 		for _, err := range allErrs {
@@ -163,6 +169,56 @@ func (v *AutoscalingPolicyValidator) validateScaleDownBehavior(policy *registryv
 	return allErrs
 }
 
+// validatePredictive validates the predictive scaling configuration
+func (v *AutoscalingPolicyValidator) validatePredictive(policy *registryv1.AutoscalingPolicy) field.ErrorList {
+	var allErrs field.ErrorList
+	predictive := policy.Spec.Predictive
+	if predictive == nil {
+		return allErrs
+	}
+	predictivePath := field.NewPath("spec").Child("predictive")
+
+	if predictive.RequestMetricName == "" {
+		allErrs = append(allErrs, field.Required(predictivePath.Child("requestMetricName"), "request metric name is required"))
+	}
+
+	targetRequestRate := predictive.TargetRequestRate.AsFloat64Slow()
+	if targetRequestRate <= 0 || math.IsInf(targetRequestRate, 0) {
+		allErrs = append(allErrs, field.Invalid(
+			predictivePath.Child("targetRequestRate"),
+			predictive.TargetRequestRate,
+			"target request rate must be greater than 0 and not equal to infinity",
+		))
+	}
+
+	// A season must hold at least one slot of one minute
+	if predictive.SeasonPeriod != nil && predictive.SeasonPeriod.Minutes() < 1 {
+		allErrs = append(allErrs, field.Invalid(
+			predictivePath.Child("seasonPeriod"),
+			predictive.SeasonPeriod,
+			"season period must be at least 1 minute",
+		))
+	}
+
+	// The request rates are recorded per minute, a shorter lookahead would forecast the current minute
+	if predictive.Lookahead != nil && predictive.Lookahead.Minutes() < 1 {
+		allErrs = append(allErrs, field.Invalid(
+			predictivePath.Child("lookahead"),
+			predictive.Lookahead,
+			"lookahead must be at least 1 minute",
+		))
+	}
+	if predictive.Lookahead != nil && predictive.SeasonPeriod != nil && predictive.Lookahead.Duration >= predictive.SeasonPeriod.Duration {
+		allErrs = append(allErrs, field.Invalid(
+			predictivePath.Child("lookahead"),
+			predictive.Lookahead,
+			"lookahead must be shorter than the season period",
+		))
+	}
+
+	return allErrs
+}
+
 // validateScaleUpBehavior validates the scale up behavior configuration
 func (v *AutoscalingPolicyValidator) validateScaleUpBehavior(policy *registryv1.AutoscalingPolicy) field.ErrorList {
 	var allErrs field.ErrorList
@@ -231,3 +287,32 @@ func (v *AutoscalingPolicyValidator) validatePanicPolicy(policy *registryv1.Auto
 
 	return allErrs
 }
+
+// validateVerticalRecommendation validates the vertical recommendation configuration
+func (v *AutoscalingPolicyValidator) validateVerticalRecommendation(policy *registryv1.AutoscalingPolicy) field.ErrorList {
+	var allErrs field.ErrorList
+	vertical := policy.Spec.VerticalRecommendation
+	if vertical == nil {
+		return allErrs
+	}
+	verticalPath := field.NewPath("spec").Child("verticalRecommendation")
+
+	// The peak usage must be observed over several autoscaling periods
+	if vertical.Window != nil && vertical.Window.Minutes() < 1 {
+		allErrs = append(allErrs, field.Invalid(
+			verticalPath.Child("window"),
+			vertical.Window,
+			"window must be at least 1 minute",
+		))
+	}
+
+	if vertical.KVCacheUsageMetricName != "" && vertical.KVCacheUsageMetricName == vertical.BatchSizeMetricName {
+		allErrs = append(allErrs, field.Invalid(
+			verticalPath.Child("batchSizeMetricName"),
+			vertical.BatchSizeMetricName,
+			"batch size metric must differ from the KV-cache usage metric",
+		))
+	}
+
+	return allErrs
+}
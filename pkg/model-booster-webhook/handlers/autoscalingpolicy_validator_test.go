@@ -165,6 +165,82 @@ func TestValidateAutoscalingPolicy_NoErrors(t *testing.T) {
 	assert.Empty(t, errorMsg)
 }
 
+func TestValidateAutoscalingPolicy_Predictive(t *testing.T) {
+	validator := NewAutoscalingPolicyValidator()
+
+	policy := &registryv1.AutoscalingPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-policy",
+			Namespace: "default",
+		},
+		Spec: registryv1.AutoscalingPolicySpec{
+			Metrics: []registryv1.AutoscalingPolicyMetric{
+				{
+					MetricName:  "cpu",
+					TargetValue: resource.MustParse("80"),
+				},
+			},
+			Predictive: &registryv1.AutoscalingPolicyPredictive{
+				RequestMetricName: "vllm:request_success_total",
+				TargetRequestRate: resource.MustParse("5"),
+				SeasonPeriod:      &metav1.Duration{Duration: 24 * time.Hour},
+				Lookahead:         &metav1.Duration{Duration: 5 * time.Minute},
+			},
+		},
+	}
+	allowed, errorMsg := validator.validateAutoscalingPolicy(policy)
+	assert.True(t, allowed)
+	assert.Empty(t, errorMsg)
+
+	policy.Spec.Predictive.RequestMetricName = ""
+	policy.Spec.Predictive.TargetRequestRate = resource.MustParse("0")
+	policy.Spec.Predictive.Lookahead = &metav1.Duration{Duration: 25 * time.Hour}
+	allowed, errorMsg = validator.validateAutoscalingPolicy(policy)
+	assert.False(t, allowed)
+	assert.Contains(t, errorMsg, "spec.predictive.requestMetricName")
+	assert.Contains(t, errorMsg, "spec.predictive.targetRequestRate")
+	assert.Contains(t, errorMsg, "lookahead must be shorter than the season period")
+
+	policy.Spec.Predictive.Lookahead = &metav1.Duration{}
+	allowed, errorMsg = validator.validateAutoscalingPolicy(policy)
+	assert.False(t, allowed)
+	assert.Contains(t, errorMsg, "lookahead must be at least 1 minute")
+}
+
+func TestValidateAutoscalingPolicy_VerticalRecommendation(t *testing.T) {
+	validator := NewAutoscalingPolicyValidator()
+
+	policy := &registryv1.AutoscalingPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-policy",
+			Namespace: "default",
+		},
+		Spec: registryv1.AutoscalingPolicySpec{
+			Metrics: []registryv1.AutoscalingPolicyMetric{
+				{
+					MetricName:  "cpu",
+					TargetValue: resource.MustParse("80"),
+				},
+			},
+			VerticalRecommendation: &registryv1.AutoscalingPolicyVerticalRecommendation{
+				KVCacheUsageMetricName: "vllm:gpu_cache_usage_perc",
+				BatchSizeMetricName:    "vllm:num_requests_running",
+				Window:                 &metav1.Duration{Duration: time.Hour},
+			},
+		},
+	}
+	allowed, errorMsg := validator.validateAutoscalingPolicy(policy)
+	assert.True(t, allowed)
+	assert.Empty(t, errorMsg)
+
+	policy.Spec.VerticalRecommendation.BatchSizeMetricName = "vllm:gpu_cache_usage_perc"
+	policy.Spec.VerticalRecommendation.Window = &metav1.Duration{Duration: 30 * time.Second}
+	allowed, errorMsg = validator.validateAutoscalingPolicy(policy)
+	assert.False(t, allowed)
+	assert.Contains(t, errorMsg, "spec.verticalRecommendation.batchSizeMetricName")
+	assert.Contains(t, errorMsg, "window must be at least 1 minute")
+}
+
 func TestAutoscalingPolicyValidator_Handle_ValidPolicy(t *testing.T) {
 	validator := NewAutoscalingPolicyValidator()
 
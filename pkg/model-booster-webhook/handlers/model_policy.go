@@ -0,0 +1,117 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	registryv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+const (
+	// ModelPolicyConfigMapName is the ConfigMap holding the model policy, in the namespace of the webhook.
+	// No policy is enforced without it.
+	ModelPolicyConfigMapName = "kthena-model-policy"
+	// ModelPolicyKey is the key of the policy document in the ConfigMap.
+	ModelPolicyKey = "policy.yaml"
+)
+
+// modelCardFields are the fields of the model card a policy can require, with their value.
+var modelCardFields = map[string]func(card *registryv1alpha1.ModelCard) string{
+	"license":     func(card *registryv1alpha1.ModelCard) string { return card.License },
+	"intendedUse": func(card *registryv1alpha1.ModelCard) string { return card.IntendedUse },
+	"safetyTier":  func(card *registryv1alpha1.ModelCard) string { return card.SafetyTier },
+	"url":         func(card *registryv1alpha1.ModelCard) string { return card.URL },
+}
+
+// ModelPolicy is the policy the models registered as ModelBoosters must comply with.
+type ModelPolicy struct {
+	// RequiredFields are the fields of the model card every model must set: license, intendedUse, safetyTier or url.
+	RequiredFields []string `json:"requiredFields,omitempty"`
+	// DeniedLicenses are the licenses of the models which cannot be registered, compared case-insensitively.
+	DeniedLicenses []string `json:"deniedLicenses,omitempty"`
+	// SafetyTiers are the safety tiers a model card may declare, any tier is accepted when empty.
+	SafetyTiers []string `json:"safetyTiers,omitempty"`
+}
+
+// ParseModelPolicy parses and checks a policy document.
+func ParseModelPolicy(data []byte) (*ModelPolicy, error) {
+	policy := &ModelPolicy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, err
+	}
+	for _, name := range policy.RequiredFields {
+		if _, ok := modelCardFields[name]; !ok {
+			return nil, fmt.Errorf("unknown model card field %q in requiredFields", name)
+		}
+	}
+	return policy, nil
+}
+
+// loadModelPolicy reads the model policy, nil when there is none.
+func (v *ModelValidator) loadModelPolicy(ctx context.Context) (*ModelPolicy, error) {
+	if v.kubeClient == nil {
+		return nil, nil
+	}
+	cm, err := v.kubeClient.CoreV1().ConfigMaps(v.namespace).Get(ctx, ModelPolicyConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	policy, err := ParseModelPolicy([]byte(cm.Data[ModelPolicyKey]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in ConfigMap %s/%s: %v", ModelPolicyKey, v.namespace, ModelPolicyConfigMapName, err)
+	}
+	return policy, nil
+}
+
+// validateModelCard checks the model card against the policy.
+func validateModelCard(model *registryv1alpha1.ModelBooster, policy *ModelPolicy) field.ErrorList {
+	var allErrs field.ErrorList
+	cardPath := field.NewPath("spec").Child("card")
+	card := model.Spec.Card
+	if card == nil {
+		card = &registryv1alpha1.ModelCard{}
+	}
+
+	for _, name := range policy.RequiredFields {
+		if strings.TrimSpace(modelCardFields[name](card)) == "" {
+			allErrs = append(allErrs, field.Required(cardPath.Child(name), "required by the model policy"))
+		}
+	}
+	license := strings.ToLower(strings.TrimSpace(card.License))
+	if license != "" && slices.ContainsFunc(policy.DeniedLicenses, func(denied string) bool {
+		return strings.ToLower(strings.TrimSpace(denied)) == license
+	}) {
+		allErrs = append(allErrs, field.Forbidden(cardPath.Child("license"),
+			fmt.Sprintf("models under the license %q cannot be registered, it is denied by the model policy", card.License)))
+	}
+	if card.SafetyTier != "" && len(policy.SafetyTiers) > 0 && !slices.Contains(policy.SafetyTiers, card.SafetyTier) {
+		allErrs = append(allErrs, field.NotSupported(cardPath.Child("safetyTier"), card.SafetyTier, policy.SafetyTiers))
+	}
+	return allErrs
+}
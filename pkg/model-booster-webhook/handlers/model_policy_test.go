@@ -0,0 +1,142 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	registryv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+const testPolicy = `
+requiredFields: [license, intendedUse, safetyTier]
+deniedLicenses: [CC-BY-NC-4.0]
+safetyTiers: [low, medium]
+`
+
+func newCardModel(card *registryv1alpha1.ModelCard) *registryv1alpha1.ModelBooster {
+	return &registryv1alpha1.ModelBooster{
+		ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "default"},
+		Spec: registryv1alpha1.ModelBoosterSpec{
+			Backends: []registryv1alpha1.ModelBackend{{
+				Name:        "backend1",
+				Type:        registryv1alpha1.ModelBackendTypeVLLM,
+				MinReplicas: 1,
+				MaxReplicas: 1,
+				Workers:     []registryv1alpha1.ModelWorker{{Type: registryv1alpha1.ModelWorkerTypeServer, Pods: 1}},
+			}},
+			Card: card,
+		},
+	}
+}
+
+func newPolicyValidator(policy string) *ModelValidator {
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ModelPolicyConfigMapName, Namespace: "kthena-system"},
+		Data:       map[string]string{ModelPolicyKey: policy},
+	})
+	return NewModelValidator(client, "kthena-system")
+}
+
+func TestParseModelPolicy(t *testing.T) {
+	policy, err := ParseModelPolicy([]byte(testPolicy))
+	require.NoError(t, err)
+	assert.Equal(t, &ModelPolicy{
+		RequiredFields: []string{"license", "intendedUse", "safetyTier"},
+		DeniedLicenses: []string{"CC-BY-NC-4.0"},
+		SafetyTiers:    []string{"low", "medium"},
+	}, policy)
+
+	_, err = ParseModelPolicy([]byte("requiredFields: [owner]"))
+	assert.Error(t, err)
+	_, err = ParseModelPolicy([]byte("deniedLicences: [gpl-3.0]"))
+	assert.Error(t, err)
+}
+
+func TestValidateModelCard(t *testing.T) {
+	policy, err := ParseModelPolicy([]byte(testPolicy))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		card       *registryv1alpha1.ModelCard
+		wantFields []string
+	}{
+		{
+			name: "compliant",
+			card: &registryv1alpha1.ModelCard{License: "apache-2.0", IntendedUse: "chat", SafetyTier: "low"},
+		},
+		{
+			name:       "no card",
+			wantFields: []string{"spec.card.license", "spec.card.intendedUse", "spec.card.safetyTier"},
+		},
+		{
+			name:       "denied license",
+			card:       &registryv1alpha1.ModelCard{License: "cc-by-nc-4.0", IntendedUse: "chat", SafetyTier: "low"},
+			wantFields: []string{"spec.card.license"},
+		},
+		{
+			name:       "unknown safety tier",
+			card:       &registryv1alpha1.ModelCard{License: "apache-2.0", IntendedUse: "chat", SafetyTier: "high"},
+			wantFields: []string{"spec.card.safetyTier"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, err := range validateModelCard(newCardModel(tt.card), policy) {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
+
+func TestValidateModelPolicy(t *testing.T) {
+	validator := newPolicyValidator(testPolicy)
+	denied := newCardModel(&registryv1alpha1.ModelCard{License: "CC-BY-NC-4.0", IntendedUse: "chat", SafetyTier: "low"})
+
+	allowed, reason := validator.validateModel(context.Background(), denied, nil)
+	assert.False(t, allowed)
+	assert.Contains(t, reason, "denied by the model policy")
+
+	// A model registered before the policy can still be updated, as long as its card is unchanged
+	updated := denied.DeepCopy()
+	updated.Spec.Backends[0].MinReplicas, updated.Spec.Backends[0].MaxReplicas = 2, 2
+	allowed, _ = validator.validateModel(context.Background(), updated, denied)
+	assert.True(t, allowed)
+
+	updated.Spec.Card.IntendedUse = "coding"
+	allowed, _ = validator.validateModel(context.Background(), updated, denied)
+	assert.False(t, allowed)
+
+	// Without a policy, any card is accepted
+	allowed, _ = NewModelValidator(fake.NewSimpleClientset(), "kthena-system").validateModel(context.Background(), denied, nil)
+	assert.True(t, allowed)
+
+	// An invalid policy denies the registrations rather than letting them through
+	allowed, reason = newPolicyValidator("requiredFields: [owner]").validateModel(context.Background(), newCardModel(nil), nil)
+	assert.False(t, allowed)
+	assert.Contains(t, reason, "failed to load the model policy")
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package handlers
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -24,18 +26,23 @@ import (
 
 	registryv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 )
 
 // ModelValidator handles validation of ModelBooster resources
 type ModelValidator struct {
+	// kubeClient reads the model policy from the namespace, no policy is enforced when it is nil.
+	kubeClient kubernetes.Interface
+	namespace  string
 }
 
-// NewModelValidator creates a new ModelValidator
-func NewModelValidator() *ModelValidator {
-	return &ModelValidator{}
+// NewModelValidator creates a new ModelValidator, enforcing the model policy of the namespace
+func NewModelValidator(kubeClient kubernetes.Interface, namespace string) *ModelValidator {
+	return &ModelValidator{kubeClient: kubeClient, namespace: namespace}
 }
 
 // Handle handles admission requests for ModelBooster resources
@@ -48,8 +55,18 @@ func (v *ModelValidator) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var oldModel *registryv1alpha1.ModelBooster
+	if len(admissionReview.Request.OldObject.Raw) > 0 {
+		oldModel = &registryv1alpha1.ModelBooster{}
+		if err := json.Unmarshal(admissionReview.Request.OldObject.Raw, oldModel); err != nil {
+			klog.Errorf("Failed to decode old ModelBooster: %v", err)
+			http.Error(w, fmt.Sprintf("failed to decode old ModelBooster: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Validate the ModelBooster
-	allowed, reason := v.validateModel(model)
+	allowed, reason := v.validateModel(r.Context(), model, oldModel)
 
 	// Create the admission response
 	admissionResponse := admissionv1.AdmissionResponse{
@@ -74,8 +91,10 @@ func (v *ModelValidator) Handle(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// validateModel validates the ModelBooster resource
-func (v *ModelValidator) validateModel(model *registryv1alpha1.ModelBooster) (bool, string) {
+// validateModel validates the ModelBooster resource. The model card is checked against the model policy
+// when the model is registered or its card changes, so that the models registered before a policy change
+// can still be updated.
+func (v *ModelValidator) validateModel(ctx context.Context, model, oldModel *registryv1alpha1.ModelBooster) (bool, string) {
 	var allErrs field.ErrorList
 
 	allErrs = append(allErrs, validateScaleToZeroGracePeriod(model)...)
@@ -84,6 +103,14 @@ func (v *ModelValidator) validateModel(model *registryv1alpha1.ModelBooster) (bo
 	allErrs = append(allErrs, validateAutoScalingPolicyScope(model)...)
 	allErrs = append(allErrs, validateBackendWorkerTypes(model)...)
 	allErrs = append(allErrs, validateLoraAdapterName(model)...)
+	if oldModel == nil || !equality.Semantic.DeepEqual(oldModel.Spec.Card, model.Spec.Card) {
+		policy, err := v.loadModelPolicy(ctx)
+		if err != nil {
+			allErrs = append(allErrs, field.InternalError(field.NewPath("spec").Child("card"), fmt.Errorf("failed to load the model policy: %v", err)))
+		} else if policy != nil {
+			allErrs = append(allErrs, validateModelCard(model, policy)...)
+		}
+	}
 
 	if len(allErrs) > 0 {
 		// Convert field errors to a formatted multi-line error message
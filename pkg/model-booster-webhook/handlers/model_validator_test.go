@@ -17,6 +17,7 @@ limitations under the License.
 package handlers
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -72,7 +73,7 @@ func TestValidateModel_ErrorFormatting(t *testing.T) {
 		},
 	}
 
-	valid, errorMsg := validator.validateModel(model)
+	valid, errorMsg := validator.validateModel(context.Background(), model, nil)
 
 	// Should not be valid due to multiple errors
 	assert.False(t, valid)
@@ -130,7 +131,7 @@ func TestValidateModel_NoErrors(t *testing.T) {
 		},
 	}
 
-	valid, errorMsg := validator.validateModel(model)
+	valid, errorMsg := validator.validateModel(context.Background(), model, nil)
 
 	// Should be valid with no errors
 	assert.True(t, valid)
@@ -0,0 +1,222 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+// Config configures the model cache agent of a node.
+type Config struct {
+	// NodeName is the node the agent runs on.
+	NodeName string
+	// CacheDirs are the host paths used as model caches, the paths of hostpath:// cache URIs.
+	CacheDirs []string
+	// HighWatermarkPercent is the disk usage above which models are evicted.
+	HighWatermarkPercent int
+	// LowWatermarkPercent is the disk usage eviction brings a cache back to.
+	LowWatermarkPercent int
+	// Interval is the period between two scans of the caches.
+	Interval time.Duration
+}
+
+// Validate checks the configuration.
+func (c *Config) Validate() error {
+	if c.NodeName == "" {
+		return fmt.Errorf("node name is required")
+	}
+	if len(c.CacheDirs) == 0 {
+		return fmt.Errorf("at least one cache directory is required")
+	}
+	if c.HighWatermarkPercent <= 0 || c.HighWatermarkPercent > 100 {
+		return fmt.Errorf("high watermark must be in (0, 100], got %d", c.HighWatermarkPercent)
+	}
+	if c.LowWatermarkPercent <= 0 || c.LowWatermarkPercent > c.HighWatermarkPercent {
+		return fmt.Errorf("low watermark must be in (0, %d], got %d", c.HighWatermarkPercent, c.LowWatermarkPercent)
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", c.Interval)
+	}
+	return nil
+}
+
+// Agent tracks the models cached on a node. It evicts the least recently used models when the disk
+// usage of a cache crosses the high watermark, and labels the node with the models it holds so that
+// new replicas prefer nodes that do not need to download the weights.
+type Agent struct {
+	config     Config
+	kubeClient kubernetes.Interface
+	now        func() time.Time
+	diskUsage  func(path string) (used, capacity uint64, err error)
+}
+
+func NewAgent(kubeClient kubernetes.Interface, config Config) (*Agent, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	for i, dir := range config.CacheDirs {
+		config.CacheDirs[i] = filepath.Clean(dir)
+	}
+	return &Agent{
+		config:     config,
+		kubeClient: kubeClient,
+		now:        time.Now,
+		diskUsage:  diskUsage,
+	}, nil
+}
+
+// Run scans the caches every interval until the context is done.
+func (a *Agent) Run(ctx context.Context) {
+	klog.Infof("Start model cache agent on node %s, caches: %v", a.config.NodeName, a.config.CacheDirs)
+	wait.UntilWithContext(ctx, a.sync, a.config.Interval)
+}
+
+func (a *Agent) sync(ctx context.Context) {
+	inUse, err := a.modelsInUse(ctx)
+	if err != nil {
+		// Without knowing which models are in use nothing can be evicted safely.
+		klog.Errorf("Failed to list models in use on node %s: %v", a.config.NodeName, err)
+		return
+	}
+	cached := sets.New[string]()
+	for _, dir := range a.config.CacheDirs {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		models, err := a.syncCache(dir, inUse)
+		if err != nil {
+			klog.Errorf("Failed to sync model cache %s: %v", dir, err)
+			continue
+		}
+		for _, model := range models {
+			if model.Complete && !model.Downloading {
+				cached.Insert(model.Name)
+			}
+		}
+	}
+	if err := a.updateNodeLabels(ctx, cached); err != nil {
+		klog.Errorf("Failed to update model cache labels of node %s: %v", a.config.NodeName, err)
+	}
+}
+
+// syncCache scans a cache directory and evicts models if it is above the high watermark.
+// It returns the models left in the cache.
+func (a *Agent) syncCache(dir string, inUse sets.Set[string]) ([]*cachedModel, error) {
+	now := a.now()
+	models, err := scanCache(dir, inUse, now)
+	if err != nil {
+		return nil, err
+	}
+	used, capacity, err := a.diskUsage(dir)
+	if err != nil {
+		return nil, err
+	}
+	if capacity == 0 || used*100 < capacity*uint64(a.config.HighWatermarkPercent) {
+		return models, nil
+	}
+	klog.Infof("Model cache %s uses %d of %d bytes, above the high watermark of %d%%", dir, used, capacity, a.config.HighWatermarkPercent)
+	evicted := sets.New[string]()
+	for _, model := range selectEvictions(models, used, capacity, a.config.LowWatermarkPercent) {
+		// A downloader may have started since the scan.
+		if isLocked(model.Path, a.now()) {
+			continue
+		}
+		klog.Infof("Evict model %s from cache %s, %d bytes, last used at %s", model.Name, dir, model.SizeBytes, model.LastUsed.Format(time.RFC3339))
+		if err := os.RemoveAll(model.Path); err != nil {
+			klog.Errorf("Failed to evict model %s: %v", model.Path, err)
+			continue
+		}
+		evicted.Insert(model.Name)
+	}
+	var remaining []*cachedModel
+	for _, model := range models {
+		if !evicted.Has(model.Name) {
+			remaining = append(remaining, model)
+		}
+	}
+	return remaining, nil
+}
+
+// modelsInUse returns the paths of the models the downloaders of pods on the node write into.
+func (a *Agent) modelsInUse(ctx context.Context) (sets.Set[string], error) {
+	pods, err := a.kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", a.config.NodeName).String(),
+		LabelSelector: workload.ModelServingNameLabelKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	inUse := sets.New[string]()
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, container := range pod.Spec.InitContainers {
+			for i, arg := range container.Args {
+				if arg == "--output-dir" && i+1 < len(container.Args) {
+					inUse.Insert(filepath.Clean(container.Args[i+1]))
+				}
+			}
+		}
+	}
+	return inUse, nil
+}
+
+// updateNodeLabels sets a label on the node for every cached model and removes the labels of the others.
+func (a *Agent) updateNodeLabels(ctx context.Context, cached sets.Set[string]) error {
+	node, err := a.kubeClient.CoreV1().Nodes().Get(ctx, a.config.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	labels := make(map[string]any)
+	for key := range node.Labels {
+		if name, ok := strings.CutPrefix(key, workload.ModelCacheNodeLabelPrefix); ok && !cached.Has(name) {
+			labels[key] = nil
+		}
+	}
+	for name := range cached {
+		if _, ok := node.Labels[workload.ModelCacheNodeLabelPrefix+name]; !ok {
+			labels[workload.ModelCacheNodeLabelPrefix+name] = "true"
+		}
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]any{"metadata": map[string]any{"labels": labels}})
+	if err != nil {
+		return err
+	}
+	_, err = a.kubeClient.CoreV1().Nodes().Patch(ctx, a.config.NodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
@@ -0,0 +1,184 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+const (
+	modelA = "0123456789abcdef0123456789abcdef"
+	modelB = "11111111111111111111111111111111"
+	modelC = "22222222222222222222222222222222"
+)
+
+// writeModel creates a complete model of size bytes in dir, last modified at modTime.
+func writeModel(t *testing.T, dir, name string, size int, modTime time.Time) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(path, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "model.safetensors"), make([]byte, size), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(path, completeMarkerFile), nil, 0o644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	return path
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{NodeName: "node-1", CacheDirs: []string{"/models"}, HighWatermarkPercent: 85, LowWatermarkPercent: 70, Interval: time.Minute}
+	assert.NoError(t, valid.Validate())
+
+	invalid := valid
+	invalid.LowWatermarkPercent = 90
+	assert.Error(t, invalid.Validate())
+
+	invalid = valid
+	invalid.CacheDirs = nil
+	assert.Error(t, invalid.Validate())
+
+	invalid = valid
+	invalid.NodeName = ""
+	assert.Error(t, invalid.Validate())
+}
+
+func TestSyncEvictsLeastRecentlyUsedModels(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	pathA := writeModel(t, dir, modelA, 100, now.Add(-3*time.Hour))
+	pathB := writeModel(t, dir, modelB, 100, now.Add(-2*time.Hour))
+	pathC := writeModel(t, dir, modelC, 100, now.Add(-1*time.Hour))
+
+	// Model A is the least recently used but still mounted by a pod.
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "serving-0",
+			Namespace: "default",
+			Labels:    map[string]string{workload.ModelServingNameLabelKey: "serving"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node-1",
+			InitContainers: []corev1.Container{{
+				Name: "model-downloader",
+				Args: []string{"--source", "hf://org/model", "--output-dir", pathA + "/"},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "node-1",
+		Labels: map[string]string{
+			"kubernetes.io/hostname":                        "node-1",
+			workload.ModelCacheNodeLabelPrefix + "deadbeef": "true",
+		},
+	}}
+	kubeClient := fake.NewClientset(pod, node)
+
+	agent, err := NewAgent(kubeClient, Config{
+		NodeName:             "node-1",
+		CacheDirs:            []string{dir},
+		HighWatermarkPercent: 80,
+		LowWatermarkPercent:  60,
+		Interval:             time.Minute,
+	})
+	require.NoError(t, err)
+	agent.now = func() time.Time { return now }
+	// 300 cached bytes and 40 other bytes use 85% of the disk, evicting B reaches 60%.
+	agent.diskUsage = func(string) (uint64, uint64, error) {
+		return 300 + 40, 400, nil
+	}
+
+	agent.sync(context.Background())
+
+	assert.DirExists(t, pathA)
+	assert.NoDirExists(t, pathB)
+	assert.DirExists(t, pathC)
+
+	node, err = kubeClient.CoreV1().Nodes().Get(context.Background(), "node-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"kubernetes.io/hostname":                    "node-1",
+		workload.ModelCacheNodeLabelPrefix + modelA: "true",
+		workload.ModelCacheNodeLabelPrefix + modelC: "true",
+	}, node.Labels)
+
+	// Model A was used now, so it is recorded as the most recently used model.
+	state := loadState(dir)
+	assert.True(t, state[modelA].Equal(now))
+	assert.True(t, state[modelC].Equal(now.Add(-1*time.Hour)))
+}
+
+func TestSyncBelowHighWatermark(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	pathA := writeModel(t, dir, modelA, 100, now.Add(-time.Hour))
+	kubeClient := fake.NewClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+
+	agent, err := NewAgent(kubeClient, Config{
+		NodeName:             "node-1",
+		CacheDirs:            []string{dir, filepath.Join(dir, "missing")},
+		HighWatermarkPercent: 80,
+		LowWatermarkPercent:  60,
+		Interval:             time.Minute,
+	})
+	require.NoError(t, err)
+	agent.diskUsage = func(string) (uint64, uint64, error) {
+		return 100, 400, nil
+	}
+	agent.sync(context.Background())
+
+	assert.DirExists(t, pathA)
+	node, err := kubeClient.CoreV1().Nodes().Get(context.Background(), "node-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "true", node.Labels[workload.ModelCacheNodeLabelPrefix+modelA])
+}
+
+func TestModelsInUseSkipsTerminatedPods(t *testing.T) {
+	newPod := func(name string, phase corev1.PodPhase, outputDir string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{workload.ModelServingNameLabelKey: "serving"},
+			},
+			Spec: corev1.PodSpec{
+				NodeName:       "node-1",
+				InitContainers: []corev1.Container{{Args: []string{"--output-dir", outputDir}}},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	kubeClient := fake.NewClientset(
+		newPod("running", corev1.PodRunning, "/models/a"),
+		newPod("pending", corev1.PodPending, "/models/b/"),
+		newPod("failed", corev1.PodFailed, "/models/c"),
+	)
+	agent := &Agent{config: Config{NodeName: "node-1"}, kubeClient: kubeClient}
+	inUse, err := agent.modelsInUse(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, sets.New("/models/a", "/models/b"), inUse)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// lockFile is held by the model downloader while it writes into a model directory.
+	lockFile = ".lock"
+	// lockTimeout matches the timeout after which the downloader considers a lock stale.
+	lockTimeout = 15 * time.Second
+	// completeMarkerFile is written by the model downloader once the model is downloaded and verified.
+	completeMarkerFile = ".kthena-complete"
+	// stateFile records when each model of a cache directory was last used by a pod.
+	stateFile = ".kthena-cache-state.json"
+)
+
+// modelDirPattern matches model directories, named after the md5 of the model URI.
+var modelDirPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// cachedModel is a model stored in a cache directory of the node.
+type cachedModel struct {
+	// Name is the name of the model directory.
+	Name      string
+	Path      string
+	SizeBytes int64
+	LastUsed  time.Time
+	// InUse is true if a pod on the node mounts the model.
+	InUse bool
+	// Downloading is true while a downloader holds the lock of the model directory.
+	Downloading bool
+	// Complete is true once the downloader finished and verified the model.
+	Complete bool
+}
+
+// Evictable returns true if the model can be deleted from the cache.
+func (m *cachedModel) Evictable() bool {
+	return !m.InUse && !m.Downloading
+}
+
+// scanCache lists the models in a cache directory. Models used by a pod, identified by their path in
+// inUse, are marked as used at now. The last use of every model is persisted in the cache directory.
+func scanCache(dir string, inUse sets.Set[string], now time.Time) ([]*cachedModel, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	lastUsed := loadState(dir)
+	var models []*cachedModel
+	state := make(map[string]time.Time)
+	for _, entry := range entries {
+		if !entry.IsDir() || !modelDirPattern.MatchString(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		model := &cachedModel{
+			Name:        entry.Name(),
+			Path:        path,
+			InUse:       inUse.Has(path),
+			Downloading: isLocked(path, now),
+			Complete:    fileExists(filepath.Join(path, completeMarkerFile)),
+		}
+		model.SizeBytes = directorySize(path)
+		switch {
+		case model.InUse:
+			model.LastUsed = now
+		case !lastUsed[model.Name].IsZero():
+			model.LastUsed = lastUsed[model.Name]
+		default:
+			// Models cached before the agent started are assumed to be used when last written.
+			if info, err := entry.Info(); err == nil {
+				model.LastUsed = info.ModTime()
+			}
+		}
+		state[model.Name] = model.LastUsed
+		models = append(models, model)
+	}
+	saveState(dir, state)
+	return models, nil
+}
+
+// selectEvictions returns the least recently used evictable models to delete so that used bytes out of
+// capacity bytes fall to targetPercent. It returns as many as possible if the target cannot be reached.
+func selectEvictions(models []*cachedModel, used, capacity uint64, targetPercent int) []*cachedModel {
+	target := capacity * uint64(targetPercent) / 100
+	if used <= target {
+		return nil
+	}
+	var candidates []*cachedModel
+	for _, model := range models {
+		if model.Evictable() {
+			candidates = append(candidates, model)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].LastUsed.Before(candidates[j].LastUsed)
+	})
+	var evictions []*cachedModel
+	for _, model := range candidates {
+		if used <= target {
+			break
+		}
+		evictions = append(evictions, model)
+		used -= min(used, uint64(model.SizeBytes))
+	}
+	return evictions
+}
+
+func isLocked(modelPath string, now time.Time) bool {
+	info, err := os.Stat(filepath.Join(modelPath, lockFile))
+	if err != nil {
+		return false
+	}
+	return now.Sub(info.ModTime()) < lockTimeout
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func directorySize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			// Files may be removed while walking, count what is left.
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+func loadState(dir string) map[string]time.Time {
+	state := make(map[string]time.Time)
+	data, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		klog.Warningf("Ignore invalid model cache state in %s: %v", dir, err)
+	}
+	return state
+}
+
+func saveState(dir string, state map[string]time.Time) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	tmp := filepath.Join(dir, stateFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		klog.Warningf("Failed to save model cache state in %s: %v", dir, err)
+		return
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, stateFile)); err != nil {
+		klog.Warningf("Failed to save model cache state in %s: %v", dir, err)
+	}
+}
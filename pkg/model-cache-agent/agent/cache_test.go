@@ -0,0 +1,90 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestScanCache(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeModel(t, dir, modelA, 10, now.Add(-time.Hour))
+	pathB := writeModel(t, dir, modelB, 20, now.Add(-time.Hour))
+	// Model B is being downloaded again.
+	require.NoError(t, os.Remove(filepath.Join(pathB, completeMarkerFile)))
+	require.NoError(t, os.WriteFile(filepath.Join(pathB, lockFile), nil, 0o644))
+	// Model C was interrupted long ago, its lock is stale.
+	pathC := filepath.Join(dir, modelC)
+	require.NoError(t, os.MkdirAll(pathC, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(pathC, lockFile), nil, 0o644))
+	stale := now.Add(-time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(pathC, lockFile), stale, stale))
+	// Directories that are not models are ignored.
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "lost+found"), 0o755))
+
+	models, err := scanCache(dir, sets.New(filepath.Join(dir, modelA)), now)
+	require.NoError(t, err)
+	require.Len(t, models, 3)
+
+	byName := make(map[string]*cachedModel)
+	for _, model := range models {
+		byName[model.Name] = model
+	}
+	assert.True(t, byName[modelA].InUse)
+	assert.True(t, byName[modelA].Complete)
+	assert.Equal(t, int64(10), byName[modelA].SizeBytes)
+	assert.True(t, byName[modelA].LastUsed.Equal(now))
+	assert.False(t, byName[modelA].Evictable())
+
+	assert.True(t, byName[modelB].Downloading)
+	assert.False(t, byName[modelB].Complete)
+	assert.False(t, byName[modelB].Evictable())
+
+	assert.False(t, byName[modelC].Downloading)
+	assert.True(t, byName[modelC].Evictable())
+}
+
+func TestSelectEvictions(t *testing.T) {
+	now := time.Now()
+	models := []*cachedModel{
+		{Name: "recent", SizeBytes: 30, LastUsed: now},
+		{Name: "oldest", SizeBytes: 10, LastUsed: now.Add(-3 * time.Hour)},
+		{Name: "in-use", SizeBytes: 50, LastUsed: now.Add(-4 * time.Hour), InUse: true},
+		{Name: "old", SizeBytes: 20, LastUsed: now.Add(-2 * time.Hour)},
+	}
+	names := func(models []*cachedModel) []string {
+		var names []string
+		for _, model := range models {
+			names = append(names, model.Name)
+		}
+		return names
+	}
+
+	assert.Empty(t, selectEvictions(models, 50, 100, 60))
+	assert.Equal(t, []string{"oldest"}, names(selectEvictions(models, 65, 100, 60)))
+	assert.Equal(t, []string{"oldest", "old"}, names(selectEvictions(models, 85, 100, 60)))
+	// The model in use is never evicted even if the target cannot be reached.
+	assert.Equal(t, []string{"oldest", "old", "recent"}, names(selectEvictions(models, 110, 100, 10)))
+}
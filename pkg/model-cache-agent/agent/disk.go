@@ -0,0 +1,31 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import "golang.org/x/sys/unix"
+
+// diskUsage returns the used and total bytes of the filesystem holding path.
+func diskUsage(path string) (used, capacity uint64, err error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	capacity = stat.Blocks * uint64(stat.Bsize)
+	// Blocks reserved for root are not available to the cache, count them as used.
+	used = capacity - stat.Bavail*uint64(stat.Bsize)
+	return used, capacity, nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+// manageDisruptionBudgets creates a PodDisruptionBudget for each ServingGroup or role replica according to
+// the disruption policy of the ModelServing, and deletes the ones which are no longer needed.
+// The budgets allow no healthy pod to be evicted, so that voluntary disruptions never leave a partial group behind.
+// They do not affect the pods deleted by the controller itself, e.g. during rolling updates and recovery, which is
+// how a blocked node drain proceeds: the deleted pods make the controller recreate the whole group or role replica.
+func (c *ModelServingController) manageDisruptionBudgets(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
+	desired, err := c.desiredDisruptionBudgets(mi)
+	if err != nil {
+		return err
+	}
+
+	selector := labels.SelectorFromSet(map[string]string{
+		workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
+	})
+	existing, err := c.pdbLister.PodDisruptionBudgets(mi.Namespace).List(selector)
+	if err != nil {
+		return fmt.Errorf("failed to list PodDisruptionBudgets: %v", err)
+	}
+
+	for _, pdb := range existing {
+		if !metav1.IsControlledBy(pdb, mi) {
+			continue
+		}
+		want, ok := desired[pdb.Name]
+		if !ok {
+			err := c.kubeClientSet.PolicyV1().PodDisruptionBudgets(mi.Namespace).Delete(ctx, pdb.Name, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete PodDisruptionBudget %s/%s: %v", pdb.Namespace, pdb.Name, err)
+			}
+			klog.V(4).Infof("Deleted PodDisruptionBudget %s/%s", pdb.Namespace, pdb.Name)
+			continue
+		}
+		delete(desired, pdb.Name)
+		if equality.Semantic.DeepEqual(pdb.Spec, want.Spec) {
+			continue
+		}
+		err := utils.RetryOnConflict(pdb, func() (*policyv1.PodDisruptionBudget, error) {
+			return c.kubeClientSet.PolicyV1().PodDisruptionBudgets(mi.Namespace).Get(ctx, pdb.Name, metav1.GetOptions{})
+		}, func(latest *policyv1.PodDisruptionBudget) error {
+			updated := latest.DeepCopy()
+			updated.Spec = want.Spec
+			_, err := c.kubeClientSet.PolicyV1().PodDisruptionBudgets(mi.Namespace).Update(ctx, updated, metav1.UpdateOptions{FieldManager: utils.FieldManager})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update PodDisruptionBudget %s/%s: %v", pdb.Namespace, pdb.Name, err)
+		}
+	}
+
+	for _, pdb := range desired {
+		_, err := c.kubeClientSet.PolicyV1().PodDisruptionBudgets(mi.Namespace).Create(ctx, pdb, metav1.CreateOptions{FieldManager: utils.FieldManager})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create PodDisruptionBudget %s/%s: %v", pdb.Namespace, pdb.Name, err)
+		}
+		klog.V(4).Infof("Created PodDisruptionBudget %s/%s", pdb.Namespace, pdb.Name)
+	}
+	return nil
+}
+
+// desiredDisruptionBudgets returns the PodDisruptionBudgets of the ServingGroups and roles in the store, keyed by name.
+func (c *ModelServingController) desiredDisruptionBudgets(mi *workloadv1alpha1.ModelServing) (map[string]*policyv1.PodDisruptionBudget, error) {
+	desired := make(map[string]*policyv1.PodDisruptionBudget)
+	if mi.Spec.DisruptionPolicy != workloadv1alpha1.ServingGroupIntact && mi.Spec.DisruptionPolicy != workloadv1alpha1.RoleIntact {
+		return desired, nil
+	}
+
+	groups, err := c.store.GetServingGroupByModelServing(utils.GetNamespaceName(mi))
+	if err != nil {
+		// No ServingGroups have been created yet
+		return desired, nil
+	}
+	for _, group := range groups {
+		if mi.Spec.DisruptionPolicy == workloadv1alpha1.ServingGroupIntact {
+			pdb := newDisruptionBudget(mi, group.Name, map[string]string{
+				workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
+				workloadv1alpha1.GroupNameLabelKey:        group.Name,
+			})
+			desired[pdb.Name] = pdb
+			continue
+		}
+		for _, role := range mi.Spec.Template.Roles {
+			roleList, err := c.store.GetRoleList(utils.GetNamespaceName(mi), group.Name, role.Name)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range roleList {
+				pdb := newDisruptionBudget(mi, group.Name+"-"+r.Name, map[string]string{
+					workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
+					workloadv1alpha1.GroupNameLabelKey:        group.Name,
+					workloadv1alpha1.RoleIDKey:                r.Name,
+				})
+				desired[pdb.Name] = pdb
+			}
+		}
+	}
+	return desired, nil
+}
+
+// newDisruptionBudget returns the budget which keeps all the selected pods available.
+// The unhealthy pods can always be evicted, they do not serve anyway and must not block a node drain.
+func newDisruptionBudget(mi *workloadv1alpha1.ModelServing, name string, selector map[string]string) *policyv1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt32(0)
+	unhealthyPodEvictionPolicy := policyv1.AlwaysAllow
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: mi.Namespace,
+			// The selector labels identify the ModelServing, and the group or role replica the budget belongs to
+			Labels: maps.Clone(selector),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(mi, workloadv1alpha1.ModelServingKind),
+			},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+			MaxUnavailable:             &maxUnavailable,
+			UnhealthyPodEvictionPolicy: &unhealthyPodEvictionPolicy,
+		},
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	volcanofake "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+func listDisruptionBudgets(t *testing.T, c *ModelServingController) map[string]policyv1.PodDisruptionBudget {
+	pdbs, err := c.kubeClientSet.PolicyV1().PodDisruptionBudgets("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	result := make(map[string]policyv1.PodDisruptionBudget)
+	for _, pdb := range pdbs.Items {
+		result[pdb.Name] = pdb
+	}
+	return result
+}
+
+func TestManageDisruptionBudgets(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mi := createStandardModelServing("test-mi", 2, 2)
+	mi.UID = "test-uid"
+	key := utils.GetNamespaceName(mi)
+
+	// A budget which is not owned by the ModelServing is left untouched
+	unowned := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{
+		Name:      "unowned",
+		Namespace: "default",
+		Labels: map[string]string{
+			workloadv1alpha1.ModelServingNameLabelKey: "test-mi",
+			workloadv1alpha1.GroupNameLabelKey:        "test-mi-0",
+		},
+	}}
+	kubeClient := kubefake.NewSimpleClientset(unowned)
+	c, err := NewModelServingController(kubeClient, kthenafake.NewSimpleClientset(), volcanofake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	require.NoError(t, err)
+	go c.pdbInformer.RunWithContext(ctx)
+	cache.WaitForCacheSync(ctx.Done(), c.pdbInformer.HasSynced)
+	for _, group := range []string{"test-mi-0", "test-mi-1"} {
+		for _, roleID := range []string{"prefill-0", "prefill-1"} {
+			c.store.AddRole(key, group, "prefill", roleID, "rev")
+		}
+	}
+	// manage runs a sync and waits for the budgets to reach the lister
+	manage := func(count int) map[string]policyv1.PodDisruptionBudget {
+		require.NoError(t, c.manageDisruptionBudgets(ctx, mi))
+		pdbs := listDisruptionBudgets(t, c)
+		require.Len(t, pdbs, count)
+		require.Eventually(t, func() bool {
+			cached, err := c.pdbLister.List(labels.Everything())
+			return err == nil && len(cached) == count
+		}, time.Second, 10*time.Millisecond)
+		return pdbs
+	}
+
+	// No budgets by default
+	manage(1)
+
+	mi.Spec.DisruptionPolicy = workloadv1alpha1.ServingGroupIntact
+	pdbs := manage(3)
+	pdb := pdbs["test-mi-1"]
+	assert.Equal(t, map[string]string{
+		workloadv1alpha1.ModelServingNameLabelKey: "test-mi",
+		workloadv1alpha1.GroupNameLabelKey:        "test-mi-1",
+	}, pdb.Spec.Selector.MatchLabels)
+	// No healthy pod of the group may be evicted
+	assert.Equal(t, 0, pdb.Spec.MaxUnavailable.IntValue())
+	assert.Nil(t, pdb.Spec.MinAvailable)
+	assert.Equal(t, policyv1.AlwaysAllow, *pdb.Spec.UnhealthyPodEvictionPolicy)
+	assert.True(t, metav1.IsControlledBy(&pdb, mi))
+
+	mi.Spec.DisruptionPolicy = workloadv1alpha1.RoleIntact
+	pdbs = manage(5)
+	assert.Contains(t, pdbs, "test-mi-0-prefill-1")
+	assert.Equal(t, "prefill-1", pdbs["test-mi-0-prefill-1"].Spec.Selector.MatchLabels[workloadv1alpha1.RoleIDKey])
+	assert.Equal(t, 0, pdbs["test-mi-0-prefill-1"].Spec.MaxUnavailable.IntValue())
+
+	// The budgets follow the ServingGroups in the store
+	c.store.DeleteServingGroup(key, "test-mi-1")
+	pdbs = manage(3)
+	assert.NotContains(t, pdbs, "test-mi-1-prefill-0")
+
+	mi.Spec.DisruptionPolicy = workloadv1alpha1.DisruptionPolicyNone
+	manage(1)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+// expectationsTimeout is the time after which the expectations of a role are considered satisfied even though
+// they have not been observed, so that a missed watch event does not block the role forever.
+const expectationsTimeout = 5 * time.Minute
+
+// podExpectations tracks the pods created and deleted by the controller until the pod informer observes them.
+// Like the expectations of the ReplicaSet controller, a ServingGroup or role is neither considered deleted nor
+// created again while its expectations are not satisfied, as the informer cache does not reflect the previous
+// creations and deletions yet. The expectations are keyed by role, as namespace/group/role/roleID like the RoleIDKey
+// index of the pods.
+type podExpectations struct {
+	lock  sync.Mutex
+	items map[string]*expectation
+}
+
+type expectation struct {
+	creations sets.Set[string]
+	deletions sets.Set[string]
+	timestamp time.Time
+}
+
+func newPodExpectations() *podExpectations {
+	return &podExpectations{items: make(map[string]*expectation)}
+}
+
+func roleExpectationsKey(namespace, groupName, roleName, roleID string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", namespace, groupName, roleName, roleID)
+}
+
+func podExpectationsKey(pod *corev1.Pod) string {
+	return roleExpectationsKey(pod.Namespace, pod.Labels[workloadv1alpha1.GroupNameLabelKey], utils.PodRoleName(pod), utils.PodRoleID(pod))
+}
+
+// ExpectCreations records pods about to be created.
+func (e *podExpectations) ExpectCreations(key string, pods ...string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	item := e.get(key)
+	item.creations.Insert(pods...)
+}
+
+// ExpectDeletions records pods about to be deleted.
+func (e *podExpectations) ExpectDeletions(key string, pods ...string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	item := e.get(key)
+	item.deletions.Insert(pods...)
+}
+
+// CreationObserved lowers the expectations once the pod is observed, or its creation has failed.
+func (e *podExpectations) CreationObserved(key, pod string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if item, ok := e.items[key]; ok {
+		item.creations.Delete(pod)
+		e.forgetIfSatisfied(key, item)
+	}
+}
+
+// DeletionObserved lowers the expectations once the pod deletion is observed, or the deletion has failed.
+func (e *podExpectations) DeletionObserved(key, pod string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if item, ok := e.items[key]; ok {
+		item.deletions.Delete(pod)
+		e.forgetIfSatisfied(key, item)
+	}
+}
+
+// RoleSatisfied reports whether all the creations and deletions of pods of the role have been observed.
+func (e *podExpectations) RoleSatisfied(namespace, groupName, roleName, roleID string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	key := roleExpectationsKey(namespace, groupName, roleName, roleID)
+	item, ok := e.items[key]
+	return !ok || e.expired(key, item)
+}
+
+// GroupSatisfied reports whether all the creations and deletions of pods of the ServingGroup have been observed.
+func (e *podExpectations) GroupSatisfied(namespace, groupName string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	prefix := namespace + "/" + groupName + "/"
+	for key, item := range e.items {
+		if strings.HasPrefix(key, prefix) && !e.expired(key, item) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *podExpectations) get(key string) *expectation {
+	item, ok := e.items[key]
+	if !ok {
+		item = &expectation{creations: sets.New[string](), deletions: sets.New[string]()}
+		e.items[key] = item
+	}
+	item.timestamp = time.Now()
+	return item
+}
+
+func (e *podExpectations) forgetIfSatisfied(key string, item *expectation) {
+	if item.creations.Len() == 0 && item.deletions.Len() == 0 {
+		delete(e.items, key)
+	}
+}
+
+// expired drops the expectations not observed within the timeout.
+func (e *podExpectations) expired(key string, item *expectation) bool {
+	if time.Since(item.timestamp) < expectationsTimeout {
+		return false
+	}
+	klog.Warningf("Expectations of %s have not been observed within %v, creations: %v, deletions: %v",
+		key, expectationsTimeout, sets.List(item.creations), sets.List(item.deletions))
+	delete(e.items, key)
+	return true
+}
@@ -0,0 +1,77 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+func TestPodExpectations(t *testing.T) {
+	e := newPodExpectations()
+	prefill := roleExpectationsKey("default", "llama-0", "prefill", "prefill-0")
+	decode := roleExpectationsKey("default", "llama-0", "decode", "decode-0")
+	assert.True(t, e.GroupSatisfied("default", "llama-0"))
+
+	e.ExpectCreations(prefill, "llama-0-prefill-0-0", "llama-0-prefill-0-1")
+	e.ExpectDeletions(decode, "llama-0-decode-0-0")
+	assert.False(t, e.RoleSatisfied("default", "llama-0", "prefill", "prefill-0"))
+	assert.False(t, e.GroupSatisfied("default", "llama-0"))
+	// The ServingGroups sharing a prefix are not mixed up
+	assert.True(t, e.GroupSatisfied("default", "llama-1"))
+	assert.True(t, e.GroupSatisfied("default", "llama"))
+
+	e.CreationObserved(prefill, "llama-0-prefill-0-0")
+	assert.False(t, e.RoleSatisfied("default", "llama-0", "prefill", "prefill-0"))
+	e.CreationObserved(prefill, "llama-0-prefill-0-1")
+	assert.True(t, e.RoleSatisfied("default", "llama-0", "prefill", "prefill-0"))
+	assert.False(t, e.GroupSatisfied("default", "llama-0"))
+
+	e.DeletionObserved(decode, "llama-0-decode-0-0")
+	assert.True(t, e.GroupSatisfied("default", "llama-0"))
+	assert.Empty(t, e.items)
+}
+
+func TestPodExpectationsTimeout(t *testing.T) {
+	e := newPodExpectations()
+	key := roleExpectationsKey("default", "llama-0", "prefill", "prefill-0")
+	e.ExpectCreations(key, "llama-0-prefill-0-0")
+	e.items[key].timestamp = time.Now().Add(-expectationsTimeout)
+
+	// The creation was never observed, the expectations expire instead of blocking the role
+	assert.True(t, e.RoleSatisfied("default", "llama-0", "prefill", "prefill-0"))
+	assert.Empty(t, e.items)
+}
+
+func TestPodExpectationsKey(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "llama-0-prefill-0-0",
+		Labels: map[string]string{
+			workloadv1alpha1.GroupNameLabelKey: "llama-0",
+			workloadv1alpha1.RoleLabelKey:      "prefill",
+			workloadv1alpha1.RoleIDKey:         "prefill-0",
+		},
+	}}
+	assert.Equal(t, roleExpectationsKey("default", "llama-0", "prefill", "prefill-0"), podExpectationsKey(pod))
+}
@@ -0,0 +1,118 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/datastore"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+// servingGroupStatuses returns the status of the ServingGroups of the ModelServing, ordered by ordinal. The
+// ServingGroups which are not running are kept first when there are more than MaxServingGroupStatuses of them.
+// The transition times of the phases which did not change are taken from the current status.
+func (c *ModelServingController) servingGroupStatuses(mi *workloadv1alpha1.ModelServing, now metav1.Time) ([]workloadv1alpha1.ServingGroupStatus, error) {
+	groups, err := c.store.GetServingGroupByModelServing(utils.GetNamespaceName(mi))
+	if errors.Is(err, datastore.ErrServingGroupNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	previous := make(map[string]workloadv1alpha1.ServingGroupStatus, len(mi.Status.Groups))
+	for _, status := range mi.Status.Groups {
+		previous[status.Name] = status
+	}
+
+	statuses := make([]workloadv1alpha1.ServingGroupStatus, 0, len(groups))
+	for _, group := range groups {
+		_, ordinal := utils.GetParentNameAndOrdinal(group.Name)
+		status := workloadv1alpha1.ServingGroupStatus{
+			Name:               group.Name,
+			Phase:              workloadv1alpha1.ServingGroupPhase(group.Status),
+			Revision:           group.Revision,
+			Standby:            utils.IsStandbyServingGroup(mi, ordinal),
+			LastTransitionTime: now,
+		}
+		if prev, ok := previous[group.Name]; ok && prev.Phase == status.Phase {
+			status.LastTransitionTime = prev.LastTransitionTime
+		}
+		if status.Roles, err = c.roleReplicaStatuses(mi, group.Name); err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	byOrdinal := func(statuses []workloadv1alpha1.ServingGroupStatus) {
+		sort.SliceStable(statuses, func(i, j int) bool {
+			_, left := utils.GetParentNameAndOrdinal(statuses[i].Name)
+			_, right := utils.GetParentNameAndOrdinal(statuses[j].Name)
+			return left < right
+		})
+	}
+	byOrdinal(statuses)
+	if len(statuses) > workloadv1alpha1.MaxServingGroupStatuses {
+		sort.SliceStable(statuses, func(i, j int) bool {
+			return statuses[i].Phase != workloadv1alpha1.ServingGroupPhaseRunning && statuses[j].Phase == workloadv1alpha1.ServingGroupPhaseRunning
+		})
+		statuses = statuses[:workloadv1alpha1.MaxServingGroupStatuses]
+		byOrdinal(statuses)
+	}
+	return statuses, nil
+}
+
+// roleReplicaStatuses counts the replicas of each role of a ServingGroup, and the ones whose pods are all running
+// and ready.
+func (c *ModelServingController) roleReplicaStatuses(mi *workloadv1alpha1.ModelServing, groupName string) ([]workloadv1alpha1.RoleReplicaStatus, error) {
+	pods, err := c.getPodsByIndex(GroupNameKey, fmt.Sprintf("%s/%s", mi.Namespace, groupName))
+	if err != nil {
+		return nil, err
+	}
+	readyPods := make(map[string]int32)
+	for _, pod := range pods {
+		if utils.IsPodRunningAndReady(pod) {
+			readyPods[utils.PodRoleID(pod)]++
+		}
+	}
+
+	statuses := make([]workloadv1alpha1.RoleReplicaStatus, 0, len(mi.Spec.Template.Roles))
+	for _, role := range mi.Spec.Template.Roles {
+		replicas, err := c.store.GetRoleList(utils.GetNamespaceName(mi), groupName, role.Name)
+		if err != nil {
+			return nil, err
+		}
+		status := workloadv1alpha1.RoleReplicaStatus{Name: role.Name}
+		for _, replica := range replicas {
+			if replica.Status == datastore.RoleDeleting {
+				continue
+			}
+			status.Replicas++
+			// the entry pod and the worker pods
+			if readyPods[replica.Name] == 1+role.WorkerReplicas {
+				status.ReadyReplicas++
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
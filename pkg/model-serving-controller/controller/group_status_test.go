@@ -0,0 +1,125 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	volcanofake "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/datastore"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+func newRolePod(name, groupName, roleName, roleID string, ready bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels: map[string]string{
+				workloadv1alpha1.GroupNameLabelKey: groupName,
+				workloadv1alpha1.RoleLabelKey:      roleName,
+				workloadv1alpha1.RoleIDKey:         roleID,
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	if ready {
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	}
+	return pod
+}
+
+func TestServingGroupStatuses(t *testing.T) {
+	c, err := NewModelServingController(kubefake.NewSimpleClientset(), kthenafake.NewSimpleClientset(), volcanofake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	require.NoError(t, err)
+	mi := createStandardModelServing("llama", 2, 2)
+	mi.Spec.Template.Roles[0].WorkerReplicas = 1
+	key := utils.GetNamespaceName(mi)
+
+	// llama-0 has one of its two prefill replicas ready, the entry pod of the other one is not ready yet
+	for _, roleID := range []string{"prefill-0", "prefill-1"} {
+		c.store.AddRole(key, "llama-0", "prefill", roleID, "rev-1")
+	}
+	indexer := c.podsInformer.GetIndexer()
+	require.NoError(t, indexer.Add(newRolePod("llama-0-prefill-0-0", "llama-0", "prefill", "prefill-0", true)))
+	require.NoError(t, indexer.Add(newRolePod("llama-0-prefill-0-1", "llama-0", "prefill", "prefill-0", true)))
+	require.NoError(t, indexer.Add(newRolePod("llama-0-prefill-1-0", "llama-0", "prefill", "prefill-1", false)))
+	require.NoError(t, indexer.Add(newRolePod("llama-0-prefill-1-1", "llama-0", "prefill", "prefill-1", true)))
+	c.store.AddServingGroup(key, 1, "rev-2")
+	require.NoError(t, c.store.UpdateServingGroupStatus(key, "llama-1", datastore.ServingGroupRunning))
+
+	created := metav1.NewTime(time.Unix(1700000000, 0))
+	mi.Status.Groups = []workloadv1alpha1.ServingGroupStatus{
+		{Name: "llama-0", Phase: workloadv1alpha1.ServingGroupPhaseCreating, LastTransitionTime: created},
+		{Name: "llama-1", Phase: workloadv1alpha1.ServingGroupPhaseCreating, LastTransitionTime: created},
+	}
+	now := metav1.NewTime(created.Add(time.Minute))
+	statuses, err := c.servingGroupStatuses(mi, now)
+	require.NoError(t, err)
+	assert.Equal(t, []workloadv1alpha1.ServingGroupStatus{
+		{
+			Name:               "llama-0",
+			Phase:              workloadv1alpha1.ServingGroupPhaseCreating,
+			Revision:           "rev-1",
+			Roles:              []workloadv1alpha1.RoleReplicaStatus{{Name: "prefill", Replicas: 2, ReadyReplicas: 1}},
+			LastTransitionTime: created,
+		},
+		{
+			Name:               "llama-1",
+			Phase:              workloadv1alpha1.ServingGroupPhaseRunning,
+			Revision:           "rev-2",
+			Roles:              []workloadv1alpha1.RoleReplicaStatus{{Name: "prefill"}},
+			LastTransitionTime: now,
+		},
+	}, statuses)
+}
+
+func TestServingGroupStatusesBounded(t *testing.T) {
+	c, err := NewModelServingController(kubefake.NewSimpleClientset(), kthenafake.NewSimpleClientset(), volcanofake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	require.NoError(t, err)
+	replicas := workloadv1alpha1.MaxServingGroupStatuses + 10
+	mi := createStandardModelServing("llama", int32(replicas), 1)
+	key := utils.GetNamespaceName(mi)
+	for i := 0; i < replicas; i++ {
+		c.store.AddServingGroup(key, i, "rev")
+		// The last ServingGroup is the only one not running
+		if i < replicas-1 {
+			require.NoError(t, c.store.UpdateServingGroupStatus(key, fmt.Sprintf("llama-%d", i), datastore.ServingGroupRunning))
+		}
+	}
+
+	statuses, err := c.servingGroupStatuses(mi, metav1.Now())
+	require.NoError(t, err)
+	require.Len(t, statuses, workloadv1alpha1.MaxServingGroupStatuses)
+	assert.Equal(t, "llama-0", statuses[0].Name)
+	last := statuses[len(statuses)-1]
+	assert.Equal(t, fmt.Sprintf("llama-%d", replicas-1), last.Name, "the ServingGroups which are not running are kept")
+	assert.Equal(t, workloadv1alpha1.ServingGroupPhaseCreating, last.Phase)
+}
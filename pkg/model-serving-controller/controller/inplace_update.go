@@ -0,0 +1,100 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/datastore"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+// updateServingGroupInPlace patches the pods of an outdated ServingGroup to the revision when the roles only changed
+// by the fields which can be updated in place: the pod labels and annotations and the container images. It returns
+// false, without changing anything, when the ServingGroup has to be recreated.
+func (c *ModelServingController) updateServingGroupInPlace(ctx context.Context, mi *workloadv1alpha1.ModelServing, servingGroupName, revision string) (bool, error) {
+	pods, err := c.getPodsByIndex(GroupNameKey, fmt.Sprintf("%s/%s", mi.Namespace, servingGroupName))
+	if err != nil {
+		return false, err
+	}
+	if len(pods) == 0 {
+		return false, nil
+	}
+	roles := make(map[string]workloadv1alpha1.Role, len(mi.Spec.Template.Roles))
+	for _, role := range mi.Spec.Template.Roles {
+		roles[role.Name] = role
+	}
+
+	// All the pods are checked before any is patched, so that a ServingGroup is never half updated in place.
+	specRevision := utils.SpecRevision(mi.Spec.Template.Roles)
+	templates := make(map[string]workloadv1alpha1.PodTemplateSpec, len(pods))
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || utils.PodSpecRevision(pod) != specRevision {
+			return false, nil
+		}
+		role, ok := roles[utils.PodRoleName(pod)]
+		if !ok {
+			return false, nil
+		}
+		if pod.Labels[workloadv1alpha1.EntryLabelKey] == utils.Entry {
+			templates[pod.Name] = role.EntryTemplate
+		} else if role.WorkerTemplate != nil {
+			templates[pod.Name] = *role.WorkerTemplate
+		} else {
+			return false, nil
+		}
+	}
+
+	restarting := false
+	for _, pod := range pods {
+		if utils.PodRevision(pod) == revision {
+			continue
+		}
+		patch, err := utils.InPlaceUpdatePatch(pod, templates[pod.Name], revision)
+		if err != nil {
+			return false, err
+		}
+		_, err = c.kubeClientSet.CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{FieldManager: utils.FieldManager})
+		if err != nil {
+			return false, fmt.Errorf("failed to update pod %s/%s in place: %v", pod.Namespace, pod.Name, err)
+		}
+		klog.V(4).Infof("Updated pod %s/%s in place to revision %s", pod.Namespace, pod.Name, revision)
+		if utils.InPlaceUpdateRestartsContainers(pod, templates[pod.Name]) {
+			// The pod runs again once its containers are restarted with the new images and it is ready
+			c.store.DeleteRunningPodFromServingGroup(utils.GetNamespaceName(mi), servingGroupName, pod.Name)
+			restarting = true
+		}
+	}
+	if err := c.store.UpdateServingGroupRevision(utils.GetNamespaceName(mi), servingGroupName, revision); err != nil {
+		return false, err
+	}
+	if restarting && c.store.GetServingGroupStatus(utils.GetNamespaceName(mi), servingGroupName) == datastore.ServingGroupRunning {
+		// The rolling update does not go on to the next ServingGroup until this one is running again
+		if err := c.store.UpdateServingGroupStatus(utils.GetNamespaceName(mi), servingGroupName, datastore.ServingGroupCreating); err != nil {
+			return false, err
+		}
+	}
+	c.recorder.Eventf(mi, corev1.EventTypeNormal, reasonRollingUpdate, "Updated ServingGroup %s in place to revision %s", servingGroupName, revision)
+	return true, nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	volcanofake "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/datastore"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+func TestUpdateServingGroupInPlace(t *testing.T) {
+	tests := []struct {
+		name        string
+		update      func(role *workloadv1alpha1.Role)
+		wantInPlace bool
+	}{
+		{
+			name: "labels annotations and images",
+			update: func(role *workloadv1alpha1.Role) {
+				role.EntryTemplate.Metadata = &workloadv1alpha1.Metadata{
+					Labels:      map[string]string{"team": "inference"},
+					Annotations: map[string]string{"owner": "ml"},
+				}
+				role.EntryTemplate.Spec.Containers[0].Image = "test-image:v2"
+			},
+			wantInPlace: true,
+		},
+		{
+			name: "command",
+			update: func(role *workloadv1alpha1.Role) {
+				role.EntryTemplate.Spec.Containers[0].Command = []string{"serve"}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			kubeClient := kubefake.NewSimpleClientset()
+			c, err := NewModelServingController(kubeClient, kthenafake.NewSimpleClientset(), volcanofake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+			require.NoError(t, err)
+			go c.podsInformer.RunWithContext(ctx)
+
+			mi := createStandardModelServing("test-mi", 1, 1)
+			oldRevision := utils.Revision(utils.RemoveRoleReplicasForRevision(mi).Spec.Template.Roles)
+			pod := utils.GenerateEntryPod(mi.Spec.Template.Roles[0], mi, "test-mi-0", 0, oldRevision)
+			_, err = kubeClient.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{})
+			require.NoError(t, err)
+			require.True(t, waitForObjectInCache(t, 2*time.Second, func() bool {
+				pods, _ := c.podsLister.List(labels.Everything())
+				return len(pods) == 1
+			}))
+			c.store.AddServingGroup(utils.GetNamespaceName(mi), 0, oldRevision)
+
+			tt.update(&mi.Spec.Template.Roles[0])
+			revision := utils.Revision(utils.RemoveRoleReplicasForRevision(mi).Spec.Template.Roles)
+			inPlace, err := c.updateServingGroupInPlace(ctx, mi, "test-mi-0", revision)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantInPlace, inPlace)
+
+			updated, err := kubeClient.CoreV1().Pods("default").Get(ctx, pod.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			group := c.store.GetServingGroup(utils.GetNamespaceName(mi), "test-mi-0")
+			if !tt.wantInPlace {
+				assert.Equal(t, pod, updated)
+				assert.Equal(t, oldRevision, group.Revision)
+				return
+			}
+			assert.Equal(t, revision, utils.PodRevision(updated))
+			assert.Equal(t, "inference", updated.Labels["team"])
+			assert.Equal(t, "ml", updated.Annotations["owner"])
+			assert.Equal(t, "test-image:v2", updated.Spec.Containers[0].Image)
+			assert.Equal(t, revision, group.Revision)
+		})
+	}
+}
+
+func TestUpdateServingGroupInPlaceContainerRestart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kubeClient := kubefake.NewSimpleClientset()
+	c, err := NewModelServingController(kubeClient, kthenafake.NewSimpleClientset(), volcanofake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	require.NoError(t, err)
+	c.initialSync = true
+	go c.podsInformer.RunWithContext(ctx)
+
+	mi := createStandardModelServing("test-mi", 1, 1)
+	require.NoError(t, c.modelServingsInformer.GetIndexer().Add(mi))
+	key := utils.GetNamespaceName(mi)
+	oldRevision := utils.Revision(utils.RemoveRoleReplicasForRevision(mi).Spec.Template.Roles)
+	pod := utils.GenerateEntryPod(mi.Spec.Template.Roles[0], mi, "test-mi-0", 0, oldRevision)
+	containerName := pod.Spec.Containers[0].Name
+	pod.Status = corev1.PodStatus{
+		Phase:             corev1.PodRunning,
+		Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		ContainerStatuses: []corev1.ContainerStatus{{Name: containerName, Ready: true}},
+	}
+	_, err = kubeClient.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.True(t, waitForObjectInCache(t, 2*time.Second, func() bool {
+		pods, _ := c.podsLister.List(labels.Everything())
+		return len(pods) == 1
+	}))
+	c.store.AddServingGroup(key, 0, oldRevision)
+	c.updatePod(nil, pod)
+	require.Equal(t, datastore.ServingGroupRunning, c.store.GetServingGroupStatus(key, "test-mi-0"))
+
+	// Updating the image restarts the container, the ServingGroup is not running until the pod is ready again
+	mi.Spec.Template.Roles[0].EntryTemplate.Spec.Containers[0].Image = "test-image:v2"
+	revision := utils.Revision(utils.RemoveRoleReplicasForRevision(mi).Spec.Template.Roles)
+	inPlace, err := c.updateServingGroupInPlace(ctx, mi, "test-mi-0", revision)
+	require.NoError(t, err)
+	require.True(t, inPlace)
+	assert.Equal(t, datastore.ServingGroupCreating, c.store.GetServingGroupStatus(key, "test-mi-0"))
+	updated, err := kubeClient.CoreV1().Pods("default").Get(ctx, pod.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{%q:1}`, containerName), updated.Annotations[utils.InPlaceUpdateRestartsAnnotationKey])
+
+	// The pod is still ready with the old image
+	c.updatePod(nil, updated)
+	assert.Equal(t, datastore.ServingGroupCreating, c.store.GetServingGroupStatus(key, "test-mi-0"))
+
+	// The restart of the in-place update is not a failure
+	restarted := updated.DeepCopy()
+	restarted.Status.ContainerStatuses[0].RestartCount = 1
+	restarted.Status.Conditions[0].Status = corev1.ConditionFalse
+	c.updatePod(nil, restarted)
+	assert.Empty(t, c.store.GetPodGraceDeadlines(key))
+	assert.Equal(t, datastore.ServingGroupCreating, c.store.GetServingGroupStatus(key, "test-mi-0"))
+
+	restarted.Status.Conditions[0].Status = corev1.ConditionTrue
+	c.updatePod(nil, restarted)
+	assert.Empty(t, c.store.GetPodGraceDeadlines(key))
+	assert.Equal(t, datastore.ServingGroupRunning, c.store.GetServingGroupStatus(key, "test-mi-0"))
+
+	// A later restart is a failure
+	crashed := restarted.DeepCopy()
+	crashed.Status.ContainerStatuses[0].RestartCount = 2
+	crashed.Status.Conditions[0].Status = corev1.ConditionFalse
+	c.updatePod(nil, crashed)
+	assert.Contains(t, c.store.GetPodGraceDeadlines(key), pod.Name)
+	assert.Equal(t, datastore.ServingGroupCreating, c.store.GetServingGroupStatus(key, "test-mi-0"))
+}
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,18 +33,26 @@ import (
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	applymetav1 "k8s.io/client-go/applyconfigurations/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	listerv1 "k8s.io/client-go/listers/core/v1"
+	listerpolicyv1 "k8s.io/client-go/listers/policy/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	volcano "volcano.sh/apis/pkg/client/clientset/versioned"
 
+	applyworkloadv1alpha1 "github.com/volcano-sh/kthena/client-go/applyconfiguration/workload/v1alpha1"
 	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
 	informersv1alpha1 "github.com/volcano-sh/kthena/client-go/informers/externalversions"
 	listerv1alpha1 "github.com/volcano-sh/kthena/client-go/listers/workload/v1alpha1"
 	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/controller/events"
+	"github.com/volcano-sh/kthena/pkg/controller/metrics"
+	"github.com/volcano-sh/kthena/pkg/features"
 	"github.com/volcano-sh/kthena/pkg/model-serving-controller/datastore"
 	"github.com/volcano-sh/kthena/pkg/model-serving-controller/gangscheduling"
 	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
@@ -52,6 +61,22 @@ import (
 const (
 	GroupNameKey = "GroupName"
 	RoleIDKey    = "RoleID"
+
+	modelServingControllerName = "model-serving"
+
+	// Reasons of the events recorded on the ModelServings
+	reasonCreatedServingGroup      = "CreatedServingGroup"
+	reasonFailedCreateServingGroup = "FailedCreateServingGroup"
+	reasonDeletingServingGroup     = "DeletingServingGroup"
+	reasonFailedDeleteServingGroup = "FailedDeleteServingGroup"
+	reasonRollingUpdate            = "RollingUpdate"
+	reasonPodFailed                = "PodFailed"
+	reasonPodRecovered             = "PodRecovered"
+	reasonDeletingFailedPod        = "DeletingFailedPod"
+	reasonRecreatingServingGroup   = "RecreatingServingGroup"
+	reasonRecreatingRole           = "RecreatingRole"
+	reasonFailedGangScheduling     = "FailedGangScheduling"
+	reasonUnschedulable            = "Unschedulable"
 )
 
 type ModelServingController struct {
@@ -64,17 +89,29 @@ type ModelServingController struct {
 	podsInformer          cache.SharedIndexInformer
 	servicesLister        listerv1.ServiceLister
 	servicesInformer      cache.SharedIndexInformer
+	nodesLister           listerv1.NodeLister
+	nodesInformer         cache.SharedIndexInformer
+	pdbLister             listerpolicyv1.PodDisruptionBudgetLister
+	pdbInformer           cache.SharedIndexInformer
 	modelServingLister    listerv1alpha1.ModelServingLister
 	modelServingsInformer cache.SharedIndexInformer
 
+	// startupProber probes the startup endpoints of the pods
+	startupProber *startupProber
+	recorder      record.EventRecorder
+
 	// nolint
-	workqueue   workqueue.RateLimitingInterface
-	store       datastore.Store
-	graceMap    sync.Map // key: errorPod.namespace/errorPod.name, value:time
-	initialSync bool     // indicates whether the initial sync has been completed
+	workqueue workqueue.RateLimitingInterface
+	store     datastore.Store
+	// expectations holds the pods created and deleted but not observed yet by the pod informer
+	expectations *podExpectations
+	pausedLock   sync.Mutex
+	// deferredPods holds the paused ModelServings, with the pods deleted while they are paused
+	deferredPods map[types.NamespacedName][]deletedPod
+	initialSync  bool // indicates whether the initial sync has been completed
 }
 
-func NewModelServingController(kubeClientSet kubernetes.Interface, modelServingClient clientset.Interface, volcanoClient volcano.Interface) (*ModelServingController, error) {
+func NewModelServingController(kubeClientSet kubernetes.Interface, modelServingClient clientset.Interface, volcanoClient volcano.Interface, dynamicClient dynamic.Interface) (*ModelServingController, error) {
 	selector, err := labels.NewRequirement(workloadv1alpha1.GroupNameLabelKey, selection.Exists, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create label selector, err: %v", err)
@@ -89,6 +126,10 @@ func NewModelServingController(kubeClientSet kubernetes.Interface, modelServingC
 	)
 	podsInformer := kubeInformerFactory.Core().V1().Pods()
 	servicesInformer := kubeInformerFactory.Core().V1().Services()
+	// the budgets carry the group name label of the pods they select
+	pdbInformer := kubeInformerFactory.Policy().V1().PodDisruptionBudgets()
+	// the nodes have none of the labels of the pods and services
+	nodesInformer := informers.NewSharedInformerFactory(kubeClientSet, 0).Core().V1().Nodes()
 	modelServingInformerFactory := informersv1alpha1.NewSharedInformerFactory(modelServingClient, 0)
 	modelServingInformer := modelServingInformerFactory.Workload().V1alpha1().ModelServings()
 
@@ -113,16 +154,23 @@ func NewModelServingController(kubeClientSet kubernetes.Interface, modelServingC
 	c := &ModelServingController{
 		kubeClientSet:         kubeClientSet,
 		modelServingClient:    modelServingClient,
-		gangManager:           gangscheduling.NewManager(kubeClientSet, volcanoClient),
+		gangManager:           gangscheduling.NewManager(kubeClientSet, podsInformer.Lister(), volcanoClient, dynamicClient),
 		podsLister:            podsInformer.Lister(),
 		podsInformer:          podsInformer.Informer(),
 		servicesLister:        servicesInformer.Lister(),
 		servicesInformer:      servicesInformer.Informer(),
+		nodesLister:           nodesInformer.Lister(),
+		nodesInformer:         nodesInformer.Informer(),
+		pdbLister:             pdbInformer.Lister(),
+		pdbInformer:           pdbInformer.Informer(),
 		modelServingLister:    modelServingInformer.Lister(),
 		modelServingsInformer: modelServingInformer.Informer(),
+		recorder:              events.NewRecorder(kubeClientSet, modelServingControllerName),
 		// nolint
-		workqueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ModelServings"),
-		store:     store,
+		workqueue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), modelServingControllerName),
+		store:        store,
+		expectations: newPodExpectations(),
+		deferredPods: make(map[types.NamespacedName][]deletedPod),
 	}
 
 	klog.Info("Set the ModelServing event handler")
@@ -150,7 +198,26 @@ func NewModelServingController(kubeClientSet kubernetes.Interface, modelServingC
 		},
 	})
 
+	_, _ = c.nodesInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if node, ok := obj.(*corev1.Node); ok {
+				c.enqueueUnschedulableModelServings(nil, node)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNode, oldOk := oldObj.(*corev1.Node)
+			node, ok := newObj.(*corev1.Node)
+			if oldOk && ok {
+				c.enqueueUnschedulableModelServings(oldNode, node)
+			}
+		},
+	})
+
 	c.syncHandler = c.syncModelServing
+	c.startupProber = newStartupProber(func(pod *corev1.Pod) {
+		// the status reflects the new startup progress of the pod
+		c.workqueue.Add(pod.Namespace + "/" + pod.Labels[workloadv1alpha1.ModelServingNameLabelKey])
+	})
 
 	return c, nil
 }
@@ -216,9 +283,13 @@ func (c *ModelServingController) deleteModelServing(obj interface{}) {
 		Namespace: mi.Namespace,
 		Name:      mi.Name,
 	})
+	c.forgetPaused(utils.GetNamespaceName(mi))
 }
 
 func (c *ModelServingController) addPod(obj interface{}) {
+	if pod, ok := obj.(*corev1.Pod); ok {
+		c.expectations.CreationObserved(podExpectationsKey(pod), pod.Name)
+	}
 	c.updatePod(nil, obj)
 }
 
@@ -250,6 +321,11 @@ func (c *ModelServingController) updatePod(oldObj, newObj interface{}) {
 		return
 	}
 
+	if _, hinted := newPod.Annotations[nodeProvisioningHintsAnnotationKey]; hinted && newPod.Spec.NodeName != "" {
+		// The autoscaler hints of the scheduled pods are removed by the reconcile of the modelServing
+		c.enqueueModelServing(mi)
+	}
+
 	switch {
 	case utils.IsPodRunningAndReady(newPod):
 		// The pod is available, that is, the state is running, and the container is ready
@@ -257,9 +333,14 @@ func (c *ModelServingController) updatePod(oldObj, newObj interface{}) {
 		if err != nil {
 			klog.Errorf("handle running pod failed: %v", err)
 		}
+	case isPodNewlyWaitingForNodeProvisioning(oldObj, newPod):
+		// Pods waiting for accelerators need autoscaler hints, and are reflected in the modelServing status
+		c.enqueueModelServing(mi)
 	case utils.IsPodFailed(newPod) || utils.ContainerRestarted(newPod):
 		// handleErrorPod is not called until modelServing has been called.
 		if !c.initialSync {
+			// Only the grace period is started, the pod is deleted at its end by the reconcile of the ModelServing
+			c.store.AddPodGraceDeadline(utils.GetNamespaceName(mi), newPod.Name, podGraceDeadline(mi, newPod))
 			return
 		}
 		// Failure occurs in pod and we need to wait for a grace period before making a judgment.
@@ -285,6 +366,8 @@ func (c *ModelServingController) deletePod(obj interface{}) {
 			return
 		}
 	}
+	c.expectations.DeletionObserved(podExpectationsKey(pod), pod.Name)
+	c.startupProber.forget(pod.UID)
 
 	mi, servingGroupName, err := c.getModelServing(pod)
 	if err != nil {
@@ -295,6 +378,7 @@ func (c *ModelServingController) deletePod(obj interface{}) {
 		}
 		return
 	}
+	c.recordServingGroupPreemption(mi, servingGroupName, pod)
 
 	roleName, roleID := utils.PodRoleName(pod), utils.PodRoleID(pod)
 	// check ServingGroup status
@@ -308,6 +392,12 @@ func (c *ModelServingController) deletePod(obj interface{}) {
 		}
 		return
 	}
+	if c.store.GetServingGroupStatus(utils.GetNamespaceName(mi), servingGroupName) == datastore.ServingGroupSuspended {
+		// The pods of suspended ServingGroups are recreated once the ModelServing is resumed, after they are all deleted.
+		c.store.DeleteRunningPodFromServingGroup(utils.GetNamespaceName(mi), servingGroupName, pod.Name)
+		c.enqueueModelServing(mi)
+		return
+	}
 
 	c.store.DeleteRunningPodFromServingGroup(types.NamespacedName{
 		Namespace: mi.Namespace,
@@ -358,7 +448,9 @@ func (c *ModelServingController) processNextWorkItem(ctx context.Context) bool {
 	}
 	defer c.workqueue.Done(key)
 
+	start := time.Now()
 	err := c.syncHandler(ctx, key.(string))
+	metrics.ObserveReconcile(modelServingControllerName, start, err)
 	if err == nil {
 		c.workqueue.Forget(key)
 		return true
@@ -391,8 +483,45 @@ func (c *ModelServingController) syncModelServing(ctx context.Context, key strin
 	copy := utils.RemoveRoleReplicasForRevision(mi)
 	revision := utils.Revision(copy.Spec.Template.Roles)
 
+	if c.setPaused(mi) {
+		if mi.Spec.Paused {
+			c.recorder.Event(mi, corev1.EventTypeNormal, reasonPaused, "Paused the reconciliation")
+		} else {
+			c.recorder.Event(mi, corev1.EventTypeNormal, reasonResumed, "Resumed the reconciliation")
+			c.resumeModelServing(mi)
+		}
+	}
+	if mi.Spec.Paused {
+		// Nothing is scaled, rolled out or recovered while paused, only the status is kept up to date.
+		klog.V(4).Infof("ModelServing %s is paused, skip reconciling", key)
+		if err := c.UpdateModelServingStatus(mi, revision); err != nil {
+			return fmt.Errorf("failed to update status of mi %s/%s: %v", namespace, name, err)
+		}
+		return nil
+	}
+
+	if mi.Spec.Suspend {
+		// The ServingGroups are kept without pods, nothing else is reconciled until the ModelServing is resumed.
+		if err := c.suspendModelServing(ctx, mi); err != nil {
+			return fmt.Errorf("cannot suspend ModelServing: %v", err)
+		}
+		if err := c.UpdateModelServingStatus(mi, revision); err != nil {
+			return fmt.Errorf("failed to update status of mi %s/%s: %v", namespace, name, err)
+		}
+		return nil
+	}
+	c.resetRecoveryBackoff(mi)
+	if err := c.resumeServingGroups(ctx, mi, revision); err != nil {
+		return fmt.Errorf("cannot resume ServingGroups: %v", err)
+	}
+
+	if err := c.manageFailedPods(ctx, mi); err != nil {
+		return fmt.Errorf("cannot manage failed pods: %v", err)
+	}
+
 	// PodGroup Manager
 	if err := c.gangManager.ManagePodGroups(ctx, mi); err != nil {
+		c.recorder.Eventf(mi, corev1.EventTypeWarning, reasonFailedGangScheduling, "Failed to manage the gang scheduling of the ServingGroups: %v", err)
 		return fmt.Errorf("Failed to manage PodGroups for ModelServing %s/%s: %v", mi.Namespace, mi.Name, err)
 	}
 
@@ -401,16 +530,28 @@ func (c *ModelServingController) syncModelServing(ctx context.Context, key strin
 		return fmt.Errorf("cannot manage ServingGroup replicas: %v", err)
 	}
 
+	if err := c.manageStandbyLabels(ctx, mi); err != nil {
+		return fmt.Errorf("cannot manage standby ServingGroups: %v", err)
+	}
+
 	err = c.manageRole(ctx, mi, revision)
 	if err != nil {
 		return fmt.Errorf("cannot manage role replicas: %v", err)
 	}
 
-	err = c.manageServingGroupRollingUpdate(mi, revision)
+	err = c.manageServingGroupRollingUpdate(ctx, mi, revision)
 	if err != nil {
 		return fmt.Errorf("cannot manage ServingGroup rollingUpdate: %v", err)
 	}
 
+	if err := c.manageNodeProvisioningHints(ctx, mi); err != nil {
+		return fmt.Errorf("cannot manage node provisioning hints: %v", err)
+	}
+
+	if err := c.manageDisruptionBudgets(ctx, mi); err != nil {
+		return fmt.Errorf("cannot manage PodDisruptionBudgets: %v", err)
+	}
+
 	if err := c.UpdateModelServingStatus(mi, revision); err != nil {
 		return fmt.Errorf("failed to update status of mi %s/%s: %v", namespace, name, err)
 	}
@@ -425,11 +566,15 @@ func (c *ModelServingController) Run(ctx context.Context, workers int) {
 	// start informers
 	go c.podsInformer.RunWithContext(ctx)
 	go c.servicesInformer.RunWithContext(ctx)
+	go c.nodesInformer.RunWithContext(ctx)
+	go c.pdbInformer.RunWithContext(ctx)
 	go c.modelServingsInformer.RunWithContext(ctx)
 
-	cache.WaitForCacheSync(ctx.Done(),
+	metrics.WaitForCacheSync(modelServingControllerName, ctx.Done(),
 		c.podsInformer.HasSynced,
 		c.servicesInformer.HasSynced,
+		c.nodesInformer.HasSynced,
+		c.pdbInformer.HasSynced,
 		c.modelServingsInformer.HasSynced,
 	)
 
@@ -468,13 +613,32 @@ func (c *ModelServingController) UpdateModelServingConditionsStatus(mi *workload
 // UpdateModelServingStatus update replicas in modelServing status.
 func (c *ModelServingController) UpdateModelServingStatus(mi *workloadv1alpha1.ModelServing, revision string) error {
 	groups, err := c.store.GetServingGroupByModelServing(utils.GetNamespaceName(mi))
-	if err != nil {
+	if err != nil && !errors.Is(err, datastore.ErrServingGroupNotFound) {
 		return err
 	}
 
-	available, updated, current := 0, 0, 0
+	replicas, available, updated, current, standby := 0, 0, 0, 0, 0
 	progressingGroups, updatedGroups, currentGroups := []int{}, []int{}, []int{}
 	for index := range groups {
+		if groups[index].Status == datastore.ServingGroupSuspended {
+			// suspended ServingGroups have no pods
+			continue
+		}
+		if _, ordinal := utils.GetParentNameAndOrdinal(groups[index].Name); utils.IsStandbyServingGroup(mi, ordinal) &&
+			groups[index].Status != datastore.ServingGroupDeleting {
+			// standby ServingGroups do not serve traffic, only track how many of them are ready
+			if groups[index].Status == datastore.ServingGroupRunning {
+				standby = standby + 1
+			} else if ok, err := c.checkServingGroupReady(mi, groups[index].Name); ok && err == nil {
+				if err := c.store.UpdateServingGroupStatus(utils.GetNamespaceName(mi), groups[index].Name, datastore.ServingGroupRunning); err != nil {
+					return fmt.Errorf("failed to set servingGroup %s status: %v", groups[index].Name, err)
+				}
+				standby = standby + 1
+			}
+			continue
+		}
+		replicas = replicas + 1
+
 		if groups[index].Status == datastore.ServingGroupDeleting {
 			// Scaling -> Running or
 			// Creating -> Running
@@ -507,11 +671,57 @@ func (c *ModelServingController) UpdateModelServingStatus(mi *workloadv1alpha1.M
 		}
 	}
 
+	startup, err := c.getStartupState(mi)
+	if err != nil {
+		return fmt.Errorf("failed to get startup progress: %v", err)
+	}
+
 	copy := mi.DeepCopy()
 	shouldUpdate := utils.SetCondition(copy, progressingGroups, updatedGroups, currentGroups)
-	if copy.Status.Replicas != int32(len(groups)) || copy.Status.AvailableReplicas != int32(available) || copy.Status.UpdatedReplicas != int32(updated) || copy.Status.CurrentReplicas != int32(current) {
+	if changed, err := c.setUnschedulableCondition(copy, len(progressingGroups)); err != nil {
+		return fmt.Errorf("failed to set unschedulable condition: %v", err)
+	} else if changed {
+		shouldUpdate = true
+	}
+	if changed, err := c.setNodeProvisioningCondition(copy); err != nil {
+		return fmt.Errorf("failed to set node provisioning condition: %v", err)
+	} else if changed {
+		shouldUpdate = true
+	}
+	if setStartupConditions(copy, startup) {
 		shouldUpdate = true
-		copy.Status.Replicas = int32(len(groups))
+	}
+	if c.setRecoveryBackOffCondition(copy) {
+		shouldUpdate = true
+	}
+	if c.setPreemptedCondition(copy) {
+		shouldUpdate = true
+	}
+	if setPausedCondition(copy) {
+		shouldUpdate = true
+	}
+	if setSuspendedCondition(copy) {
+		shouldUpdate = true
+	}
+	groupStatuses, err := c.servingGroupStatuses(copy, metav1.Now())
+	if err != nil {
+		return fmt.Errorf("failed to get ServingGroup statuses: %v", err)
+	}
+	if !equality.Semantic.DeepEqual(copy.Status.Groups, groupStatuses) {
+		shouldUpdate = true
+		copy.Status.Groups = groupStatuses
+	}
+	// the selector of the scale subresource
+	selector := labels.SelectorFromSet(map[string]string{workloadv1alpha1.ModelServingNameLabelKey: mi.Name}).String()
+	if copy.Status.LabelSelector != selector {
+		shouldUpdate = true
+		copy.Status.LabelSelector = selector
+	}
+	if copy.Status.Replicas != int32(replicas) || copy.Status.AvailableReplicas != int32(available) || copy.Status.UpdatedReplicas != int32(updated) ||
+		copy.Status.CurrentReplicas != int32(current) || copy.Status.StandbyReplicas != int32(standby) {
+		shouldUpdate = true
+		copy.Status.Replicas = int32(replicas)
+		copy.Status.StandbyReplicas = int32(standby)
 		copy.Status.AvailableReplicas = int32(available)
 		copy.Status.UpdatedReplicas = int32(updated)
 		copy.Status.CurrentReplicas = int32(current)
@@ -534,21 +744,70 @@ func (c *ModelServingController) UpdateModelServingStatus(mi *workloadv1alpha1.M
 	}
 
 	if shouldUpdate {
-		_, err := c.modelServingClient.WorkloadV1alpha1().ModelServings(copy.GetNamespace()).UpdateStatus(context.TODO(), copy, metav1.UpdateOptions{})
-		if err != nil {
+		if err := c.applyModelServingStatus(context.TODO(), copy); err != nil {
 			return err
 		}
 	}
 
+	if startup.starting() {
+		// the startup progress of the engines does not trigger any event, check it again later
+		c.workqueue.AddAfter(utils.GetNamespaceName(mi).String(), startupResyncPeriod)
+	}
+
 	return nil
 }
 
+// applyModelServingStatus applies the status fields owned by the controller server side. Unlike an update, it does not
+// conflict with the other writers of the status, such as the autoscaler writing the resource recommendations.
+func (c *ModelServingController) applyModelServingStatus(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
+	status := applyworkloadv1alpha1.ModelServingStatus().
+		WithObservedGeneration(mi.Status.ObservedGeneration).
+		WithReplicas(mi.Status.Replicas).
+		WithCurrentReplicas(mi.Status.CurrentReplicas).
+		WithUpdatedReplicas(mi.Status.UpdatedReplicas).
+		WithAvailableReplicas(mi.Status.AvailableReplicas).
+		WithStandbyReplicas(mi.Status.StandbyReplicas).
+		WithLoadingPercentage(mi.Status.LoadingPercentage).
+		WithLabelSelector(mi.Status.LabelSelector)
+	for _, condition := range mi.Status.Conditions {
+		status.WithConditions(applymetav1.Condition().
+			WithType(condition.Type).
+			WithStatus(condition.Status).
+			WithObservedGeneration(condition.ObservedGeneration).
+			WithLastTransitionTime(condition.LastTransitionTime).
+			WithReason(condition.Reason).
+			WithMessage(condition.Message))
+	}
+	for _, group := range mi.Status.Groups {
+		groupStatus := applyworkloadv1alpha1.ServingGroupStatus().
+			WithName(group.Name).
+			WithPhase(group.Phase).
+			WithRevision(group.Revision).
+			WithStandby(group.Standby).
+			WithLastTransitionTime(group.LastTransitionTime)
+		for _, role := range group.Roles {
+			groupStatus.WithRoles(applyworkloadv1alpha1.RoleReplicaStatus().
+				WithName(role.Name).
+				WithReplicas(role.Replicas).
+				WithReadyReplicas(role.ReadyReplicas))
+		}
+		status.WithGroups(groupStatus)
+	}
+	modelServing := applyworkloadv1alpha1.ModelServing(mi.Name, mi.Namespace).WithStatus(status)
+	_, err := c.modelServingClient.WorkloadV1alpha1().ModelServings(mi.Namespace).ApplyStatus(ctx, modelServing, metav1.ApplyOptions{
+		FieldManager: utils.FieldManager,
+		Force:        true,
+	})
+	return err
+}
+
 func (c *ModelServingController) manageServingGroupReplicas(ctx context.Context, mi *workloadv1alpha1.ModelServing, newRevision string) error {
 	servingGroupList, err := c.store.GetServingGroupByModelServing(utils.GetNamespaceName(mi))
 	if err != nil && !errors.Is(err, datastore.ErrServingGroupNotFound) {
 		return fmt.Errorf("cannot get servingGroup of modelServing: %s from map: %v", mi.GetName(), err)
 	}
-	expectedCount := int(*mi.Spec.Replicas)
+	// standby ServingGroups take the ordinals after the active ones
+	expectedCount := utils.ServingGroupCount(mi)
 	curReplicas := len(servingGroupList)
 	if curReplicas == expectedCount {
 		klog.V(4).Info("The number of replicas is consistent, no need to scale up or down")
@@ -572,17 +831,26 @@ func (c *ModelServingController) manageServingGroupReplicas(ctx context.Context,
 	for idx := 0; idx < expectedCount; idx++ {
 		if replicas[idx] == nil {
 			// Create pods for ServingGroup
+			groupName := utils.GenerateServingGroupName(mi.Name, idx)
+			if c.holdRecreation(mi, groupName) {
+				continue
+			}
 			err = c.CreatePodsForServingGroup(ctx, mi, idx, newRevision)
 			if err != nil {
+				c.recorder.Eventf(mi, corev1.EventTypeWarning, reasonFailedCreateServingGroup, "Failed to create ServingGroup %s: %v", groupName, err)
 				// I think that after create a pod failed, a period of time should pass before joining the coordination queue.
 				return fmt.Errorf("create Serving group failed: %v", err)
 			} else {
 				// Insert new ServingGroup to global storage
 				c.store.AddServingGroup(utils.GetNamespaceName(mi), idx, newRevision)
+				c.store.MarkServingGroupRecreated(utils.GetNamespaceName(mi), groupName)
+				c.recorder.Eventf(mi, corev1.EventTypeNormal, reasonCreatedServingGroup, "Created ServingGroup %s", groupName)
 			}
 		}
 	}
 	for _, group := range condemned {
+		c.store.DeleteRecoveryBackoff(utils.GetNamespaceName(mi), group.Name)
+		c.store.DeleteServingGroupPreemption(utils.GetNamespaceName(mi), group.Name)
 		c.DeleteServingGroup(mi, group.Name)
 	}
 	return nil
@@ -619,6 +887,11 @@ func (c *ModelServingController) DeleteServingGroup(mi *workloadv1alpha1.ModelSe
 			return
 		}
 		// Delete all pods in ServingGroup
+		pods, err := c.getPodsByIndex(GroupNameKey, groupNameValue)
+		if err != nil {
+			klog.Errorf("failed to get pods of ServingGroup %s: %v", groupname, err)
+		}
+		c.expectPodDeletions(pods)
 		err = c.kubeClientSet.CoreV1().Pods(miNamedName.Namespace).DeleteCollection(
 			context.TODO(),
 			metav1.DeleteOptions{},
@@ -627,9 +900,12 @@ func (c *ModelServingController) DeleteServingGroup(mi *workloadv1alpha1.ModelSe
 			},
 		)
 		if err != nil {
+			c.lowerPodDeletions(pods)
 			klog.Errorf("failed to delete ServingGroup %s/%s: %v", miNamedName.Namespace+"/"+mi.Name, groupname, err)
+			c.recorder.Eventf(mi, corev1.EventTypeWarning, reasonFailedDeleteServingGroup, "Failed to delete ServingGroup %s: %v", groupname, err)
 			return
 		}
+		c.recorder.Eventf(mi, corev1.EventTypeNormal, reasonDeletingServingGroup, "Deleting ServingGroup %s", groupname)
 		// There is no DeleteCollection operation in the service of client-go. We need to list and delete them one by one.
 		services, err := c.getServicesByIndex(GroupNameKey, groupNameValue)
 		if err != nil {
@@ -658,7 +934,8 @@ func (c *ModelServingController) DeleteServingGroup(mi *workloadv1alpha1.ModelSe
 	if err != nil {
 		klog.Errorf("failed to get service, err:%v", err)
 	}
-	if len(pods) == 0 && len(services) == 0 {
+	// The pods created or deleted but not observed yet may not be in the cache
+	if len(pods) == 0 && len(services) == 0 && c.expectations.GroupSatisfied(miNamedName.Namespace, groupname) {
 		klog.V(2).Infof("ServingGroup %s has been deleted", groupname)
 		c.store.DeleteServingGroup(miNamedName, groupname)
 		c.enqueueModelServing(mi)
@@ -674,8 +951,12 @@ func (c *ModelServingController) CreatePodByRole(ctx context.Context, role workl
 
 	c.gangManager.AnnotatePodWithPodGroup(entryPod, mi, 1+int(role.WorkerReplicas), groupName, taskName)
 
-	_, err := c.kubeClientSet.CoreV1().Pods(mi.Namespace).Create(ctx, entryPod, metav1.CreateOptions{})
+	expectationsKey := roleExpectationsKey(mi.Namespace, groupName, role.Name, utils.GenerateRoleID(role.Name, roleIndex))
+	c.expectations.ExpectCreations(expectationsKey, entryPod.Name)
+	_, err := c.kubeClientSet.CoreV1().Pods(mi.Namespace).Create(ctx, entryPod, metav1.CreateOptions{FieldManager: utils.FieldManager})
 	if err != nil {
+		// No event is received for a pod which has not been created, or was already observed
+		c.expectations.CreationObserved(expectationsKey, entryPod.Name)
 		if !apierrors.IsAlreadyExists(err) {
 			klog.Errorf("create entry pod failed: %v", err)
 			return err
@@ -697,8 +978,10 @@ func (c *ModelServingController) CreatePodByRole(ctx context.Context, role workl
 	for podIndex := range int(role.WorkerReplicas) {
 		workerPod := utils.GenerateWorkerPod(role, mi, entryPod, groupName, roleIndex, podIndex+1, newHash) // worker-pod sequence number starts from 1, so we use index+1 here.
 		c.gangManager.AnnotatePodWithPodGroup(workerPod, mi, 1+int(role.WorkerReplicas), groupName, taskName)
-		_, err = c.kubeClientSet.CoreV1().Pods(mi.Namespace).Create(ctx, workerPod, metav1.CreateOptions{})
+		c.expectations.ExpectCreations(expectationsKey, workerPod.Name)
+		_, err = c.kubeClientSet.CoreV1().Pods(mi.Namespace).Create(ctx, workerPod, metav1.CreateOptions{FieldManager: utils.FieldManager})
 		if err != nil {
+			c.expectations.CreationObserved(expectationsKey, workerPod.Name)
 			if !apierrors.IsAlreadyExists(err) {
 				klog.Errorf("create worker pod failed: %v", err)
 				return err
@@ -714,8 +997,9 @@ func (c *ModelServingController) manageRole(ctx context.Context, mi *workloadv1a
 		return fmt.Errorf("cannot get ServingGroup of modelServing: %s from map: %v", mi.GetName(), err)
 	}
 	for _, servingGroup := range servingGroupList {
-		if c.store.GetServingGroupStatus(utils.GetNamespaceName(mi), servingGroup.Name) == datastore.ServingGroupDeleting {
-			// Deleting ServingGroup will be recreated after the deletion is complete, so there is no need to scale the roles
+		if status := c.store.GetServingGroupStatus(utils.GetNamespaceName(mi), servingGroup.Name); status == datastore.ServingGroupDeleting || status == datastore.ServingGroupSuspended {
+			// Deleting ServingGroup will be recreated after the deletion is complete, so there is no need to scale the roles.
+			// Suspended ServingGroup get the pods of all the roles once resumed.
 			continue
 		}
 		_, servingGroupOrdinal := utils.GetParentNameAndOrdinal(servingGroup.Name)
@@ -763,6 +1047,9 @@ func (c *ModelServingController) manageRoleReplicas(ctx context.Context, mi *wor
 	// Handle scale up
 	for idx := 0; idx < expectedCount; idx++ {
 		if replicas[idx] == nil {
+			if c.holdRecreation(mi, groupName) {
+				continue
+			}
 			// Role needs to scale up, and the ServingGroup status needs to be set to Scaling
 			if c.store.GetServingGroupStatus(utils.GetNamespaceName(mi), groupName) != datastore.ServingGroupScaling {
 				err := c.store.UpdateServingGroupStatus(utils.GetNamespaceName(mi), groupName, datastore.ServingGroupScaling)
@@ -778,6 +1065,7 @@ func (c *ModelServingController) manageRoleReplicas(ctx context.Context, mi *wor
 			} else {
 				// Insert new Role to global storage
 				c.store.AddRole(utils.GetNamespaceName(mi), groupName, targetRole.Name, utils.GenerateRoleID(targetRole.Name, idx), newRevision)
+				c.store.MarkServingGroupRecreated(utils.GetNamespaceName(mi), groupName)
 			}
 		}
 	}
@@ -813,6 +1101,12 @@ func (c *ModelServingController) DeleteRole(ctx context.Context, mi *workloadv1a
 		return
 	}
 	// Delete all pods in role
+	roleIDValue := fmt.Sprintf("%s/%s/%s/%s", mi.Namespace, groupName, roleName, roleID)
+	pods, err := c.getPodsByIndex(RoleIDKey, roleIDValue)
+	if err != nil {
+		klog.Errorf("failed to get pods of role %s/%s: %v", groupName, roleID, err)
+	}
+	c.expectPodDeletions(pods)
 	err = c.kubeClientSet.CoreV1().Pods(mi.Namespace).DeleteCollection(
 		ctx,
 		metav1.DeleteOptions{},
@@ -821,10 +1115,10 @@ func (c *ModelServingController) DeleteRole(ctx context.Context, mi *workloadv1a
 		},
 	)
 	if err != nil {
+		c.lowerPodDeletions(pods)
 		klog.Errorf("failed to delete pods of role %s/%s: %v", groupName, roleID, err)
 	}
 	// There is no DeleteCollection operation in the service of client-go. We need to list and delete them one by one.
-	roleIDValue := fmt.Sprintf("%s/%s/%s/%s", mi.Namespace, groupName, roleName, roleID)
 	services, err := c.getServicesByIndex(RoleIDKey, roleIDValue)
 	if err != nil {
 		klog.Errorf("failed to get service %v", err)
@@ -841,7 +1135,7 @@ func (c *ModelServingController) DeleteRole(ctx context.Context, mi *workloadv1a
 	}
 }
 
-func (c *ModelServingController) manageServingGroupRollingUpdate(mi *workloadv1alpha1.ModelServing, revision string) error {
+func (c *ModelServingController) manageServingGroupRollingUpdate(ctx context.Context, mi *workloadv1alpha1.ModelServing, revision string) error {
 	// we compute the minimum ordinal of the target sequence for a destructive update based on the strategy.
 	updateMin := 0
 	if mi.Spec.RolloutStrategy != nil && mi.Spec.RolloutStrategy.RollingUpdateConfiguration != nil && mi.Spec.RolloutStrategy.RollingUpdateConfiguration.Partition != nil {
@@ -855,7 +1149,20 @@ func (c *ModelServingController) manageServingGroupRollingUpdate(mi *workloadv1a
 	for i := len(servingGroupList) - 1; i >= updateMin; i-- {
 		if c.isServingGroupOutdated(servingGroupList[i], mi.Namespace, revision) {
 			// target ServingGroup is not the latest version, needs to be updated
+			if servingGroupList[i].Status != datastore.ServingGroupDeleting && features.DefaultFeatureGate.Enabled(features.InPlacePodUpdate) {
+				// Non-disruptive changes are patched on the running pods, the next group is updated once this one is running.
+				updated, err := c.updateServingGroupInPlace(ctx, mi, servingGroupList[i].Name, revision)
+				if err != nil {
+					return fmt.Errorf("cannot update ServingGroup %s in place: %v", servingGroupList[i].Name, err)
+				}
+				if updated {
+					return nil
+				}
+			}
 			klog.V(2).Infof("ServingGroup %s will be terminating for update", servingGroupList[i].Name)
+			if servingGroupList[i].Status != datastore.ServingGroupDeleting {
+				c.recorder.Eventf(mi, corev1.EventTypeNormal, reasonRollingUpdate, "Recreating ServingGroup %s to update it to revision %s", servingGroupList[i].Name, revision)
+			}
 			c.DeleteServingGroup(mi, servingGroupList[i].Name)
 			return nil
 		}
@@ -898,15 +1205,11 @@ func (c *ModelServingController) handleReadyPod(mi *workloadv1alpha1.ModelServin
 }
 
 func (c *ModelServingController) handleErrorPod(mi *workloadv1alpha1.ModelServing, servingGroupName string, errPod *corev1.Pod) error {
-	// pod is already in the grace period and does not need to be processed for the time being.
-	_, exists := c.graceMap.Load(utils.GetNamespaceName(errPod))
-	now := time.Now()
-	if exists {
+	// add pod to the grace period, a pod already in the grace period does not need to be processed for the time being.
+	if !c.store.AddPodGraceDeadline(utils.GetNamespaceName(mi), errPod.Name, podGraceDeadline(mi, errPod)) {
 		klog.V(4).Infof("Pod %v failed, waiting for grace time", utils.GetNamespaceName(errPod))
 		return nil
 	}
-	// add pod to the grace period map
-	c.graceMap.Store(utils.GetNamespaceName(errPod), now)
 	c.store.DeleteRunningPodFromServingGroup(types.NamespacedName{
 		Namespace: mi.Namespace,
 		Name:      mi.Name,
@@ -919,58 +1222,117 @@ func (c *ModelServingController) handleErrorPod(mi *workloadv1alpha1.ModelServin
 		}
 		klog.V(2).Infof("update ServingGroup %s to processing when pod fails", servingGroupName)
 	}
-	// Wait for the grace period before processing
-	go c.handlePodAfterGraceTime(mi, errPod)
-	// ServingGroup status may change, needs reconcile
+	if mi.Spec.Paused {
+		// The pod is handled again once the ModelServing is resumed
+		c.store.DeletePodGraceDeadline(utils.GetNamespaceName(mi), errPod.Name)
+		klog.V(2).Infof("ModelServing %s is paused, do not recover pod %s", utils.GetNamespaceName(mi), errPod.Name)
+		return nil
+	}
+	gracePeriod := int64(0)
+	if mi.Spec.Template.RestartGracePeriodSeconds != nil {
+		gracePeriod = *mi.Spec.Template.RestartGracePeriodSeconds
+	}
+	c.recorder.Eventf(mi, corev1.EventTypeWarning, reasonPodFailed, "Pod %s of ServingGroup %s failed, it will be deleted unless it recovers within %ds", errPod.Name, servingGroupName, gracePeriod)
+	// ServingGroup status may change, needs reconcile. The reconcile deletes the pod at the end of its grace period.
 	c.enqueueModelServing(mi)
 	return nil
 }
 
-func (c *ModelServingController) handlePodAfterGraceTime(mi *workloadv1alpha1.ModelServing, errPod *corev1.Pod) {
-	if mi.Spec.Template.RestartGracePeriodSeconds != nil && *mi.Spec.Template.RestartGracePeriodSeconds > 0 {
-		// Wait for the grace period before making a decision
-		time.Sleep(time.Duration(*mi.Spec.Template.RestartGracePeriodSeconds) * time.Second)
-		klog.V(4).Infof("%s after grace time", errPod.Name)
-		defer c.graceMap.Delete(utils.GetNamespaceName(errPod))
-
-		newPod, err := c.podsLister.Pods(mi.Namespace).Get(errPod.Name)
-		if err != nil {
-			if apierrors.IsNotFound(err) {
-				klog.V(4).Infof("pod %s has been deleted after grace time", errPod.Name)
-			} else {
-				klog.Errorf("cannot get pod %s after grace time, err: %v", errPod.Name, err)
+// podGraceDeadline returns the time a failed pod is deleted unless it has recovered. The grace period starts when the
+// pod failed according to its status rather than when the failure is observed, so that the deadline does not move
+// when the controller restarts.
+func podGraceDeadline(mi *workloadv1alpha1.ModelServing, pod *corev1.Pod) time.Time {
+	var failedAt time.Time
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, status := range statuses {
+			for _, terminated := range []*corev1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+				if terminated != nil && terminated.FinishedAt.After(failedAt) {
+					failedAt = terminated.FinishedAt.Time
+				}
 			}
-			return
 		}
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status != corev1.ConditionTrue && condition.LastTransitionTime.After(failedAt) {
+			failedAt = condition.LastTransitionTime.Time
+		}
+	}
+	if failedAt.IsZero() {
+		failedAt = time.Now()
+	}
+	if mi.Spec.Template.RestartGracePeriodSeconds == nil {
+		return failedAt
+	}
+	return failedAt.Add(time.Duration(*mi.Spec.Template.RestartGracePeriodSeconds) * time.Second)
+}
 
-		if !utils.IsPodRunningAndReady(newPod) {
-			// pod has not recovered after the grace period, needs to be rebuilt
-			// After this pod has been deleted, we will rebuild the ServingGroup in deletePod function
-			err = c.kubeClientSet.CoreV1().Pods(mi.Namespace).Delete(context.TODO(), newPod.Name, metav1.DeleteOptions{})
-			if err != nil {
-				klog.Errorf("cannot delete pod %s after grace time, err: %v", newPod.Name, err)
-				return
+// manageFailedPods deletes the failed pods which have not recovered at the end of their grace period, and requeues
+// the ModelServing at the next deadline.
+func (c *ModelServingController) manageFailedPods(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
+	key := utils.GetNamespaceName(mi)
+	now := time.Now()
+	var next time.Duration
+	for podName, deadline := range c.store.GetPodGraceDeadlines(key) {
+		if wait := deadline.Sub(now); wait > 0 {
+			if next == 0 || wait < next {
+				next = wait
 			}
-			klog.V(2).Infof("%s been deleted after grace time", errPod.Name)
+			continue
 		}
-	} else {
-		// grace period is not set or the grace period is 0, the deletion will be executed immediately.
-		defer c.graceMap.Delete(utils.GetNamespaceName(errPod))
+		if err := c.handlePodAfterGraceTime(ctx, mi, podName); err != nil {
+			return err
+		}
+		c.store.DeletePodGraceDeadline(key, podName)
+	}
+	if next > 0 {
+		c.workqueue.AddAfter(key.String(), next)
+	}
+	return nil
+}
 
-		err := c.kubeClientSet.CoreV1().Pods(mi.Namespace).Delete(context.TODO(), errPod.Name, metav1.DeleteOptions{})
-		if err != nil {
-			klog.Errorf("cannot delete pod %s when it error, err: %v", errPod.Name, err)
-			return
+func (c *ModelServingController) handlePodAfterGraceTime(ctx context.Context, mi *workloadv1alpha1.ModelServing, podName string) error {
+	klog.V(4).Infof("%s after grace time", podName)
+	pod, err := c.podsLister.Pods(mi.Namespace).Get(podName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("pod %s has been deleted after grace time", podName)
+			return nil
 		}
-		klog.V(2).Infof("%s been deleted without grace time", errPod.Name)
+		return fmt.Errorf("cannot get pod %s after grace time, err: %v", podName, err)
+	}
+
+	if utils.IsPodRunningAndReady(pod) {
+		c.recorder.Eventf(mi, corev1.EventTypeNormal, reasonPodRecovered, "Pod %s recovered within the grace period", podName)
+		return nil
+	}
+	// pod has not recovered after the grace period, needs to be rebuilt
+	// After this pod has been deleted, we will rebuild the ServingGroup in deletePod function
+	err = c.kubeClientSet.CoreV1().Pods(mi.Namespace).Delete(ctx, podName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("cannot delete pod %s after grace time, err: %v", podName, err)
+	}
+	klog.V(2).Infof("%s been deleted after grace time", podName)
+	if mi.Spec.RecoveryPolicy != workloadv1alpha1.NoneRestartPolicy {
+		c.recordServingGroupFailure(mi, pod)
 	}
+	if mi.Spec.Template.RestartGracePeriodSeconds != nil && *mi.Spec.Template.RestartGracePeriodSeconds > 0 {
+		c.recorder.Eventf(mi, corev1.EventTypeNormal, reasonDeletingFailedPod, "Deleted pod %s which did not recover within the grace period", podName)
+	} else {
+		c.recorder.Eventf(mi, corev1.EventTypeNormal, reasonDeletingFailedPod, "Deleted failed pod %s", podName)
+	}
+	return nil
 }
 
 func (c *ModelServingController) handleDeletedPod(mi *workloadv1alpha1.ModelServing, servingGroupName string, pod *corev1.Pod) error {
 	// pod is deleted due to failure or other reasons and needs to be rebuilt according to the RecoveryPolicy
+	if mi.Spec.Paused {
+		c.deferPodRecovery(mi, servingGroupName, pod)
+		return nil
+	}
 	switch mi.Spec.RecoveryPolicy {
 	case workloadv1alpha1.ServingGroupRecreate:
 		// Rebuild the entire ServingGroup directly
+		c.recorder.Eventf(mi, corev1.EventTypeNormal, reasonRecreatingServingGroup, "Recreating ServingGroup %s since pod %s was deleted", servingGroupName, pod.Name)
 		c.DeleteServingGroup(mi, servingGroupName)
 	case workloadv1alpha1.RoleRecreate:
 		// If Rolling update in RoleRecreate mode, requires re-entering the queue during the pod delete event.
@@ -984,6 +1346,7 @@ func (c *ModelServingController) handleDeletedPod(mi *workloadv1alpha1.ModelServ
 				return fmt.Errorf("failed to set ServingGroup %s status: %v", servingGroupName, err)
 			}
 		}
+		c.recorder.Eventf(mi, corev1.EventTypeNormal, reasonRecreatingRole, "Recreating role %s of ServingGroup %s since pod %s was deleted", utils.PodRoleID(pod), servingGroupName, pod.Name)
 		c.DeleteRole(context.Background(), mi, servingGroupName, utils.PodRoleName(pod), utils.PodRoleID(pod))
 	}
 	return nil
@@ -1065,7 +1428,7 @@ func (c *ModelServingController) isServingGroupDeleted(mi *workloadv1alpha1.Mode
 		klog.Errorf("failed to get service, err:%v", err)
 		return false
 	}
-	return len(pods) == 0 && len(services) == 0
+	return len(pods) == 0 && len(services) == 0 && c.expectations.GroupSatisfied(mi.Namespace, servingGroupName)
 }
 
 func (c *ModelServingController) isRoleDeleted(mi *workloadv1alpha1.ModelServing, servingGroupName, roleName, roleID string) bool {
@@ -1085,7 +1448,23 @@ func (c *ModelServingController) isRoleDeleted(mi *workloadv1alpha1.ModelServing
 		klog.Errorf("failed to get service, err:%v", err)
 		return false
 	}
-	return len(pods) == 0 && len(services) == 0
+	return len(pods) == 0 && len(services) == 0 && c.expectations.RoleSatisfied(mi.Namespace, servingGroupName, roleName, roleID)
+}
+
+// expectPodDeletions records the pods about to be deleted in the expectations of their roles.
+func (c *ModelServingController) expectPodDeletions(pods []*corev1.Pod) {
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil {
+			c.expectations.ExpectDeletions(podExpectationsKey(pod), pod.Name)
+		}
+	}
+}
+
+// lowerPodDeletions drops the expected deletions of pods which have not been deleted.
+func (c *ModelServingController) lowerPodDeletions(pods []*corev1.Pod) {
+	for _, pod := range pods {
+		c.expectations.DeletionObserved(podExpectationsKey(pod), pod.Name)
+	}
 }
 
 // getPodsByIndex filter pods using the informer indexer.
@@ -23,7 +23,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/informers"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
@@ -146,6 +148,7 @@ func TestCheckServingGroupReady(t *testing.T) {
 		podsInformer: podInformer.Informer(),
 		podsLister:   podInformer.Lister(),
 		store:        store,
+		expectations: newPodExpectations(),
 	}
 	stop := make(chan struct{})
 	defer close(stop)
@@ -255,6 +258,7 @@ func TestIsServingGroupDeleted(t *testing.T) {
 		podsLister:       podInformer.Lister(),
 		servicesLister:   serviceInformer.Lister(),
 		store:            store,
+		expectations:     newPodExpectations(),
 	}
 
 	stop := make(chan struct{})
@@ -453,6 +457,7 @@ func TestIsRoleDeleted(t *testing.T) {
 		podsLister:       podInformer.Lister(),
 		servicesLister:   serviceInformer.Lister(),
 		store:            store,
+		expectations:     newPodExpectations(),
 	}
 
 	stop := make(chan struct{})
@@ -732,13 +737,14 @@ func TestModelServingControllerModelServingLifecycle(t *testing.T) {
 	kubeClient := kubefake.NewSimpleClientset()
 	kthenaClient := kthenafake.NewSimpleClientset()
 	volcanoClient := volcanofake.NewSimpleClientset()
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
 
 	// Create informer factories
 	kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
 	kthenaInformerFactory := informersv1alpha1.NewSharedInformerFactory(kthenaClient, 0)
 
 	// Create controller
-	controller, err := NewModelServingController(kubeClient, kthenaClient, volcanoClient)
+	controller, err := NewModelServingController(kubeClient, kthenaClient, volcanoClient, dynamicClient)
 	assert.NoError(t, err)
 
 	stop := make(chan struct{})
@@ -793,6 +799,18 @@ func TestModelServingControllerModelServingLifecycle(t *testing.T) {
 		verifyRoles(t, controller, mi, 2)
 		// Verify each ServingGroup has correct pods
 		verifyPodCount(t, controller, mi, 2)
+		// Verify the creation of the ServingGroups is recorded as events
+		found = waitForObjectInCache(t, 2*time.Second, func() bool {
+			events, _ := kubeClient.CoreV1().Events("default").List(context.Background(), metav1.ListOptions{})
+			created := 0
+			for _, event := range events.Items {
+				if event.InvolvedObject.Name == mi.Name && event.Reason == reasonCreatedServingGroup {
+					created++
+				}
+			}
+			return created == 2
+		})
+		assert.True(t, found, "ServingGroup creation events should be recorded")
 	})
 
 	// Test Case 2: ModelServing Scale Up
@@ -1254,3 +1272,114 @@ func verifyRoles(t *testing.T, controller *ModelServingController, mi *workloadv
 		}
 	}
 }
+
+func TestPodGraceDeadline(t *testing.T) {
+	mi := createStandardModelServing("test-mi", 1, 1)
+	failedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{
+				Type:               corev1.PodReady,
+				Status:             corev1.ConditionFalse,
+				LastTransitionTime: metav1.NewTime(failedAt.Add(-time.Hour)),
+			}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				RestartCount: 1,
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(failedAt)},
+				},
+			}},
+		},
+	}
+
+	// The deadline only depends on the pod status, it does not move when the failure is observed again
+	assert.Equal(t, failedAt, podGraceDeadline(mi, pod))
+	mi.Spec.Template.RestartGracePeriodSeconds = ptr.To[int64](30)
+	assert.Equal(t, failedAt.Add(30*time.Second), podGraceDeadline(mi, pod))
+
+	pod.Status.Conditions[0].LastTransitionTime = metav1.NewTime(failedAt.Add(time.Minute))
+	assert.Equal(t, failedAt.Add(time.Minute+30*time.Second), podGraceDeadline(mi, pod))
+}
+
+func TestManageFailedPods(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kubeClient := kubefake.NewSimpleClientset()
+	kthenaClient := kthenafake.NewSimpleClientset()
+	c, err := NewModelServingController(kubeClient, kthenaClient, volcanofake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	assert.NoError(t, err)
+	go c.podsInformer.RunWithContext(ctx)
+
+	mi := createStandardModelServing("test-mi", 1, 1)
+	newPod := func(name string, ready bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+				Labels:    map[string]string{workloadv1alpha1.GroupNameLabelKey: "test-mi-0"},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		if ready {
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		}
+		_, err := kubeClient.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+		return pod
+	}
+	newPod("failed", false)
+	newPod("recovered", true)
+	newPod("waiting", false)
+	assert.True(t, waitForObjectInCache(t, 2*time.Second, func() bool {
+		pods, _ := c.podsLister.List(labels.Everything())
+		return len(pods) == 3
+	}))
+
+	key := utils.GetNamespaceName(mi)
+	now := time.Now()
+	c.store.AddPodGraceDeadline(key, "failed", now.Add(-time.Second))
+	c.store.AddPodGraceDeadline(key, "recovered", now.Add(-time.Second))
+	c.store.AddPodGraceDeadline(key, "waiting", now.Add(time.Hour))
+	// The pods deleted during their grace period are forgotten
+	c.store.AddPodGraceDeadline(key, "deleted", now.Add(-time.Second))
+
+	assert.NoError(t, c.manageFailedPods(ctx, mi))
+
+	pods, err := kubeClient.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	var names []string
+	for _, pod := range pods.Items {
+		names = append(names, pod.Name)
+	}
+	assert.ElementsMatch(t, []string{"recovered", "waiting"}, names)
+	assert.Equal(t, map[string]time.Time{"waiting": now.Add(time.Hour)}, c.store.GetPodGraceDeadlines(key))
+}
+
+func TestApplyModelServingStatus(t *testing.T) {
+	ctx := context.Background()
+	mi := createStandardModelServing("test-mi", 2, 1)
+	// The resource recommendations are written by the autoscaler
+	mi.Status.ResourceRecommendations = []workloadv1alpha1.ResourceRecommendation{{Role: "prefill"}}
+	kthenaClient := kthenafake.NewSimpleClientset(mi)
+	c := &ModelServingController{modelServingClient: kthenaClient}
+
+	status := mi.DeepCopy()
+	status.Status.Replicas = 2
+	status.Status.AvailableReplicas = 1
+	status.Status.ObservedGeneration = 3
+	status.Status.Conditions = []metav1.Condition{{
+		Type:               string(workloadv1alpha1.ModelServingProgressing),
+		Status:             metav1.ConditionTrue,
+		Reason:             "GroupsProgressing",
+		LastTransitionTime: metav1.NewTime(time.Unix(1700000000, 0)),
+	}}
+	assert.NoError(t, c.applyModelServingStatus(ctx, status))
+
+	got, err := kthenaClient.WorkloadV1alpha1().ModelServings("default").Get(ctx, "test-mi", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), got.Status.Replicas)
+	assert.Equal(t, int32(1), got.Status.AvailableReplicas)
+	assert.Equal(t, int64(3), got.Status.ObservedGeneration)
+	assert.Len(t, got.Status.Conditions, 1)
+	assert.Equal(t, mi.Status.ResourceRecommendations, got.Status.ResourceRecommendations)
+}
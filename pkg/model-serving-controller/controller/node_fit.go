@@ -0,0 +1,405 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/features"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+const (
+	reasonNoFittingNodes = "NoFittingNodes"
+	reasonNodesFit       = "NodesFit"
+)
+
+// checkServingGroupFit checks whether the pods of a ServingGroup fit on the nodes of the cluster. It returns why
+// they do not, empty when they do or when the check does not apply: the nodes of the ModelServings with a
+// provisioning class are provisioned for their pending pods, and a cluster without any node is not known yet.
+func (c *ModelServingController) checkServingGroupFit(mi *workloadv1alpha1.ModelServing) (string, error) {
+	if c.nodesLister == nil || !features.DefaultFeatureGate.Enabled(features.ServingGroupFitCheck) ||
+		mi.Annotations[workloadv1alpha1.ProvisioningClassAnnotationKey] != "" {
+		return "", nil
+	}
+	nodes, err := c.nodesLister.List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+	if len(nodes) == 0 {
+		return "", nil
+	}
+	return servingGroupUnfitReason(mi, nodes), nil
+}
+
+// setUnschedulableCondition reflects the ServingGroups which are not running and do not fit on the nodes in the
+// ModelServing status. Their pods are created all the same, so that the cluster autoscaler may add the nodes they
+// fit on, the condition only tells why they stay pending. It returns true if the status has changed.
+func (c *ModelServingController) setUnschedulableCondition(mi *workloadv1alpha1.ModelServing, progressing int) (bool, error) {
+	conditionType := string(workloadv1alpha1.ModelServingUnschedulable)
+	reason := ""
+	if progressing > 0 {
+		var err error
+		if reason, err = c.checkServingGroupFit(mi); err != nil {
+			return false, err
+		}
+		if reason != "" {
+			changed := meta.SetStatusCondition(&mi.Status.Conditions, metav1.Condition{
+				Type:    conditionType,
+				Status:  metav1.ConditionTrue,
+				Reason:  reasonNoFittingNodes,
+				Message: fmt.Sprintf("%d ServingGroups are not running, they do not fit on the nodes: %s", progressing, reason),
+			})
+			if changed {
+				c.recorder.Eventf(mi, corev1.EventTypeWarning, reasonUnschedulable, "The ServingGroups do not fit on the nodes: %s", reason)
+			}
+			return changed, nil
+		}
+	}
+	if !meta.IsStatusConditionTrue(mi.Status.Conditions, conditionType) {
+		return false, nil
+	}
+	return meta.SetStatusCondition(&mi.Status.Conditions, metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonNodesFit,
+		Message: "The ServingGroups fit on the nodes",
+	}), nil
+}
+
+// enqueueUnschedulableModelServings enqueues the ModelServings whose ServingGroups did not fit on the nodes,
+// when a node they may now fit on joins the cluster or changes.
+func (c *ModelServingController) enqueueUnschedulableModelServings(oldNode, node *corev1.Node) {
+	if oldNode != nil && oldNode.Spec.Unschedulable == node.Spec.Unschedulable &&
+		equality.Semantic.DeepEqual(oldNode.Labels, node.Labels) &&
+		equality.Semantic.DeepEqual(oldNode.Spec.Taints, node.Spec.Taints) &&
+		equality.Semantic.DeepEqual(oldNode.Status.Allocatable, node.Status.Allocatable) {
+		return
+	}
+	modelServings, err := c.modelServingLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list ModelServings: %v", err)
+		return
+	}
+	for _, mi := range modelServings {
+		if meta.IsStatusConditionTrue(mi.Status.Conditions, string(workloadv1alpha1.ModelServingUnschedulable)) {
+			c.enqueueModelServing(mi)
+		}
+	}
+}
+
+// servingGroupUnfitReason checks whether the pods of a ServingGroup of the ModelServing fit on the nodes, as if the
+// nodes were empty: it finds the ServingGroups which can never be scheduled, not the ones waiting for other pods
+// to free the resources. Each pod must fit on a node it may run on, the replicas of the roles colocated in a
+// topology domain must fit in one, and the ServingGroup, which is scheduled as a whole, must fit on all of them.
+// It returns why it does not fit, empty when it does.
+func servingGroupUnfitReason(mi *workloadv1alpha1.ModelServing, nodes []*corev1.Node) string {
+	groupName := utils.GenerateServingGroupName(mi.Name, 0)
+	var reasons []string
+	total := corev1.ResourceList{}
+	usable := make(map[string]*corev1.Node)
+	for _, role := range mi.Spec.Template.Roles {
+		replicas := int64(1)
+		if role.Replicas != nil {
+			replicas = int64(*role.Replicas)
+		}
+		if replicas == 0 {
+			continue
+		}
+
+		entryPod := utils.GenerateEntryPod(role, mi, groupName, 0, "")
+		pods := map[string]*corev1.Pod{"entry": entryPod}
+		counts := map[string]int64{"entry": 1}
+		if role.WorkerTemplate != nil && role.WorkerReplicas > 0 {
+			pods["worker"] = utils.GenerateWorkerPod(role, mi, entryPod, groupName, 0, 1, "")
+			counts["worker"] = int64(role.WorkerReplicas)
+		}
+		replicaRequests := corev1.ResourceList{}
+		var roleNodes []*corev1.Node
+		fit := true
+		for _, kind := range []string{"entry", "worker"} {
+			pod, ok := pods[kind]
+			if !ok {
+				continue
+			}
+			requests := podRequests(pod)
+			candidates := eligibleNodes(pod, nodes)
+			if reason := podUnfitReason(requests, candidates); reason != "" {
+				reasons = append(reasons, fmt.Sprintf("the %s pods of role %s %s", kind, role.Name, reason))
+				fit = false
+				continue
+			}
+			addResources(replicaRequests, requests, counts[kind])
+			roleNodes = append(roleNodes, candidates...)
+		}
+		if !fit {
+			continue
+		}
+		if key := colocationTopologyKey(role); key != "" {
+			if reason := domainUnfitReason(replicaRequests, roleNodes, key); reason != "" {
+				reasons = append(reasons, fmt.Sprintf("a replica of role %s %s", role.Name, reason))
+				continue
+			}
+		}
+		addResources(total, replicaRequests, replicas)
+		for _, node := range roleNodes {
+			usable[node.Name] = node
+		}
+	}
+	if len(reasons) > 0 {
+		return strings.Join(reasons, "; ")
+	}
+
+	allocatable := corev1.ResourceList{}
+	for _, node := range usable {
+		addResources(allocatable, node.Status.Allocatable, 1)
+	}
+	if name, ok := shortResource(total, []corev1.ResourceList{allocatable}); ok {
+		have := allocatable[name]
+		need := total[name]
+		return fmt.Sprintf("the ServingGroup needs %s %s, the nodes its pods may run on have %s", need.String(), name, have.String())
+	}
+	return ""
+}
+
+// podUnfitReason returns why a pod requesting the resources does not fit on any of the nodes it may run on.
+func podUnfitReason(requests corev1.ResourceList, nodes []*corev1.Node) string {
+	if len(nodes) == 0 {
+		return "match no node by their node selector, affinity and tolerations"
+	}
+	capacities := make([]corev1.ResourceList, 0, len(nodes))
+	for _, node := range nodes {
+		if fits(requests, node.Status.Allocatable) {
+			return ""
+		}
+		capacities = append(capacities, node.Status.Allocatable)
+	}
+	if name, ok := shortResource(requests, capacities); ok {
+		need := requests[name]
+		largest := largestOf(name, capacities)
+		return fmt.Sprintf("need %s %s, the largest node has %s", need.String(), name, largest.String())
+	}
+	return "fit on no node with all their resources"
+}
+
+// domainUnfitReason returns why the pods requesting the resources together do not fit in any topology domain
+// of the nodes, their nodes with the same value of the key label.
+func domainUnfitReason(requests corev1.ResourceList, nodes []*corev1.Node, key string) string {
+	domains := make(map[string]corev1.ResourceList)
+	seen := make(map[string]bool)
+	for _, node := range nodes {
+		domain, ok := node.Labels[key]
+		if !ok || seen[node.Name] {
+			continue
+		}
+		seen[node.Name] = true
+		if domains[domain] == nil {
+			domains[domain] = corev1.ResourceList{}
+		}
+		addResources(domains[domain], node.Status.Allocatable, 1)
+	}
+	if len(domains) == 0 {
+		return fmt.Sprintf("may run on no node labeled %s", key)
+	}
+	capacities := make([]corev1.ResourceList, 0, len(domains))
+	for _, capacity := range domains {
+		if fits(requests, capacity) {
+			return ""
+		}
+		capacities = append(capacities, capacity)
+	}
+	if name, ok := shortResource(requests, capacities); ok {
+		need := requests[name]
+		largest := largestOf(name, capacities)
+		return fmt.Sprintf("needs %s %s in a %s domain, the largest has %s", need.String(), name, key, largest.String())
+	}
+	return fmt.Sprintf("fits in no %s domain with all its resources", key)
+}
+
+func colocationTopologyKey(role workloadv1alpha1.Role) string {
+	if role.Placement == nil {
+		return ""
+	}
+	return role.Placement.ColocationTopologyKey
+}
+
+// eligibleNodes returns the nodes the pod may run on, by their node selector, required node affinity and taints.
+func eligibleNodes(pod *corev1.Pod, nodes []*corev1.Node) []*corev1.Node {
+	var eligible []*corev1.Node
+	for _, node := range nodes {
+		if node.Spec.Unschedulable || !toleratesTaints(pod.Spec.Tolerations, node.Spec.Taints) {
+			continue
+		}
+		if !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil &&
+			affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil &&
+			!matchesNodeSelector(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution, node) {
+			continue
+		}
+		eligible = append(eligible, node)
+	}
+	return eligible
+}
+
+func toleratesTaints(tolerations []corev1.Toleration, taints []corev1.Taint) bool {
+	for i := range taints {
+		if taints[i].Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(&taints[i]) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesNodeSelector reports whether the node matches one of the terms of the selector.
+func matchesNodeSelector(selector *corev1.NodeSelector, node *corev1.Node) bool {
+	for _, term := range selector.NodeSelectorTerms {
+		// an empty term matches no node
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+		matches := true
+		for _, requirement := range term.MatchExpressions {
+			matches = matches && matchesRequirement(requirement, labels.Set(node.Labels))
+		}
+		for _, requirement := range term.MatchFields {
+			matches = matches && requirement.Key == "metadata.name" && matchesRequirement(requirement, labels.Set{"metadata.name": node.Name})
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesRequirement(requirement corev1.NodeSelectorRequirement, values labels.Set) bool {
+	operators := map[corev1.NodeSelectorOperator]selection.Operator{
+		corev1.NodeSelectorOpIn:           selection.In,
+		corev1.NodeSelectorOpNotIn:        selection.NotIn,
+		corev1.NodeSelectorOpExists:       selection.Exists,
+		corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		corev1.NodeSelectorOpGt:           selection.GreaterThan,
+		corev1.NodeSelectorOpLt:           selection.LessThan,
+	}
+	operator, ok := operators[requirement.Operator]
+	if !ok {
+		return false
+	}
+	r, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+	if err != nil {
+		return false
+	}
+	return r.Matches(values)
+}
+
+// podRequests returns the resources requested by the pod, as counted by the scheduler: the requests of its
+// containers, which default to their limits, or of its largest init container, and its overhead.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for i := range pod.Spec.Containers {
+		addResources(requests, containerRequests(&pod.Spec.Containers[i]), 1)
+	}
+	for i := range pod.Spec.InitContainers {
+		for name, quantity := range containerRequests(&pod.Spec.InitContainers[i]) {
+			if current, ok := requests[name]; !ok || quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	addResources(requests, pod.Spec.Overhead, 1)
+	return requests
+}
+
+func containerRequests(container *corev1.Container) corev1.ResourceList {
+	requests := container.Resources.Requests.DeepCopy()
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+	for name, quantity := range container.Resources.Limits {
+		if _, ok := requests[name]; !ok {
+			requests[name] = quantity.DeepCopy()
+		}
+	}
+	return requests
+}
+
+// addResources adds count times the resources to the list.
+func addResources(list, resources corev1.ResourceList, count int64) {
+	for name, quantity := range resources {
+		current := list[name]
+		for range count {
+			current.Add(quantity)
+		}
+		list[name] = current
+	}
+}
+
+func fits(requests, capacity corev1.ResourceList) bool {
+	_, short := shortResource(requests, []corev1.ResourceList{capacity})
+	return !short
+}
+
+// shortResource returns the first resource, by name, requested beyond all the capacities.
+func shortResource(requests corev1.ResourceList, capacities []corev1.ResourceList) (corev1.ResourceName, bool) {
+	names := make([]string, 0, len(requests))
+	for name, quantity := range requests {
+		if quantity.Sign() > 0 {
+			names = append(names, string(name))
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		request := requests[corev1.ResourceName(name)]
+		if request.Cmp(largestOf(corev1.ResourceName(name), capacities)) > 0 {
+			return corev1.ResourceName(name), true
+		}
+	}
+	return "", false
+}
+
+func largestOf(name corev1.ResourceName, capacities []corev1.ResourceList) resource.Quantity {
+	var largest resource.Quantity
+	for _, capacity := range capacities {
+		if quantity, ok := capacity[name]; ok && quantity.Cmp(largest) > 0 {
+			largest = quantity
+		}
+	}
+	return largest
+}
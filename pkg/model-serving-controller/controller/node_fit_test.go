@@ -0,0 +1,263 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	volcanofake "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+func newGPUNode(name string, gpus int64, nodeLabels map[string]string, taints ...corev1.Taint) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels},
+		Spec:       corev1.NodeSpec{Taints: taints},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("64"),
+			corev1.ResourceMemory: resource.MustParse("512Gi"),
+			"nvidia.com/gpu":      *resource.NewQuantity(gpus, resource.DecimalSI),
+		}},
+	}
+}
+
+func newGPUModelServing(roleReplicas, gpus int32, workers int32) *workloadv1alpha1.ModelServing {
+	mi := createStandardModelServing("llama", 1, roleReplicas)
+	role := &mi.Spec.Template.Roles[0]
+	role.EntryTemplate.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+		"nvidia.com/gpu": *resource.NewQuantity(int64(gpus), resource.DecimalSI),
+	}
+	if workers > 0 {
+		role.WorkerReplicas = workers
+		role.WorkerTemplate = role.EntryTemplate.DeepCopy()
+	}
+	return mi
+}
+
+func TestServingGroupUnfitReason(t *testing.T) {
+	gpuTaint := corev1.Taint{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}
+	nodes := []*corev1.Node{
+		newGPUNode("node-a", 8, map[string]string{"kubernetes.io/hostname": "node-a", "pool": "h100"}, gpuTaint),
+		newGPUNode("node-b", 8, map[string]string{"kubernetes.io/hostname": "node-b", "pool": "h100"}, gpuTaint),
+		newGPUNode("node-c", 0, map[string]string{"kubernetes.io/hostname": "node-c"}),
+	}
+	tolerate := func(mi *workloadv1alpha1.ModelServing) {
+		for i := range mi.Spec.Template.Roles {
+			role := &mi.Spec.Template.Roles[i]
+			toleration := corev1.Toleration{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}
+			role.EntryTemplate.Spec.Tolerations = append(role.EntryTemplate.Spec.Tolerations, toleration)
+			if role.WorkerTemplate != nil {
+				role.WorkerTemplate.Spec.Tolerations = append(role.WorkerTemplate.Spec.Tolerations, toleration)
+			}
+		}
+	}
+	tests := []struct {
+		name      string
+		mi        *workloadv1alpha1.ModelServing
+		customize func(mi *workloadv1alpha1.ModelServing)
+		want      string
+	}{
+		{
+			name:      "fits",
+			mi:        newGPUModelServing(2, 8, 0),
+			customize: tolerate,
+		},
+		{
+			name: "taint not tolerated",
+			mi:   newGPUModelServing(1, 8, 0),
+			want: "the entry pods of role prefill need 8 nvidia.com/gpu, the largest node has 0",
+		},
+		{
+			name:      "pod larger than the nodes",
+			mi:        newGPUModelServing(1, 16, 0),
+			customize: tolerate,
+			want:      "the entry pods of role prefill need 16 nvidia.com/gpu, the largest node has 8",
+		},
+		{
+			name: "node selector",
+			mi:   newGPUModelServing(1, 8, 0),
+			customize: func(mi *workloadv1alpha1.ModelServing) {
+				tolerate(mi)
+				mi.Spec.Template.Roles[0].EntryTemplate.Spec.NodeSelector = map[string]string{"pool": "b200"}
+			},
+			want: "the entry pods of role prefill match no node by their node selector, affinity and tolerations",
+		},
+		{
+			name: "required node affinity",
+			mi:   newGPUModelServing(1, 8, 0),
+			customize: func(mi *workloadv1alpha1.ModelServing) {
+				tolerate(mi)
+				mi.Spec.Template.Roles[0].EntryTemplate.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"h100"}}}},
+					}},
+				}}
+			},
+		},
+		{
+			name:      "ServingGroup larger than the nodes",
+			mi:        newGPUModelServing(3, 8, 0),
+			customize: tolerate,
+			want:      "the ServingGroup needs 24 nvidia.com/gpu, the nodes its pods may run on have 16",
+		},
+		{
+			name:      "multi-node replica",
+			mi:        newGPUModelServing(1, 8, 1),
+			customize: tolerate,
+		},
+		{
+			name: "replica colocated on a node",
+			mi:   newGPUModelServing(1, 8, 1),
+			customize: func(mi *workloadv1alpha1.ModelServing) {
+				tolerate(mi)
+				mi.Spec.Template.Roles[0].Placement = &workloadv1alpha1.RolePlacement{ColocationTopologyKey: "kubernetes.io/hostname"}
+			},
+			want: "a replica of role prefill needs 16 nvidia.com/gpu in a kubernetes.io/hostname domain, the largest has 8",
+		},
+		{
+			name: "replica colocated in a pool",
+			mi:   newGPUModelServing(1, 8, 1),
+			customize: func(mi *workloadv1alpha1.ModelServing) {
+				tolerate(mi)
+				mi.Spec.Template.Roles[0].Placement = &workloadv1alpha1.RolePlacement{ColocationTopologyKey: "pool"}
+			},
+		},
+		{
+			name: "init container",
+			mi:   newGPUModelServing(1, 8, 0),
+			customize: func(mi *workloadv1alpha1.ModelServing) {
+				tolerate(mi)
+				mi.Spec.Template.Roles[0].EntryTemplate.Spec.InitContainers = []corev1.Container{{
+					Name:      "download",
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("128")}},
+				}}
+			},
+			want: "the entry pods of role prefill need 128 cpu, the largest node has 64",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.customize != nil {
+				tt.customize(tt.mi)
+			}
+			assert.Equal(t, tt.want, servingGroupUnfitReason(tt.mi, nodes))
+		})
+	}
+}
+
+func TestMatchesNodeSelector(t *testing.T) {
+	node := newGPUNode("node-a", 8, map[string]string{"pool": "h100", "gpus": "8"})
+	term := func(requirements ...corev1.NodeSelectorRequirement) corev1.NodeSelectorTerm {
+		return corev1.NodeSelectorTerm{MatchExpressions: requirements}
+	}
+	tests := []struct {
+		name  string
+		terms []corev1.NodeSelectorTerm
+		want  bool
+	}{
+		{name: "empty term", terms: []corev1.NodeSelectorTerm{{}}, want: false},
+		{name: "in", terms: []corev1.NodeSelectorTerm{term(corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"h100", "b200"}})}, want: true},
+		{name: "not in", terms: []corev1.NodeSelectorTerm{term(corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"h100"}})}, want: false},
+		{name: "greater than", terms: []corev1.NodeSelectorTerm{term(corev1.NodeSelectorRequirement{Key: "gpus", Operator: corev1.NodeSelectorOpGt, Values: []string{"4"}})}, want: true},
+		{name: "does not exist", terms: []corev1.NodeSelectorTerm{term(corev1.NodeSelectorRequirement{Key: "zone", Operator: corev1.NodeSelectorOpDoesNotExist})}, want: true},
+		{
+			name: "terms are ORed",
+			terms: []corev1.NodeSelectorTerm{
+				term(corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"b200"}}),
+				{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-a"}}}},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchesNodeSelector(&corev1.NodeSelector{NodeSelectorTerms: tt.terms}, node))
+		})
+	}
+}
+
+func TestServingGroupFitCheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kubeClient := kubefake.NewSimpleClientset(newGPUNode("node-a", 8, nil))
+	kthenaClient := kthenafake.NewSimpleClientset()
+	c, err := NewModelServingController(kubeClient, kthenaClient, volcanofake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	require.NoError(t, err)
+	go c.podsInformer.RunWithContext(ctx)
+	go c.nodesInformer.RunWithContext(ctx)
+	go c.modelServingsInformer.RunWithContext(ctx)
+	cache.WaitForCacheSync(ctx.Done(), c.podsInformer.HasSynced, c.nodesInformer.HasSynced, c.modelServingsInformer.HasSynced)
+
+	// The pods of a ServingGroup which does not fit are created all the same, for the cluster autoscaler to add nodes
+	mi := newGPUModelServing(1, 16, 0)
+	require.NoError(t, c.manageServingGroupReplicas(ctx, mi, "rev"))
+	pods, err := kubeClient.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, pods.Items, 1)
+
+	changed, err := c.setUnschedulableCondition(mi, 1)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	condition := meta.FindStatusCondition(mi.Status.Conditions, string(workloadv1alpha1.ModelServingUnschedulable))
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "1 ServingGroups are not running, they do not fit on the nodes: the entry pods of role prefill need 16 nvidia.com/gpu, the largest node has 8", condition.Message)
+
+	// A node the ServingGroup fits on joins the cluster
+	_, err = kubeClient.CoreV1().Nodes().Create(ctx, newGPUNode("node-b", 16, nil), metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		nodes, err := c.nodesLister.List(labels.Everything())
+		return err == nil && len(nodes) == 2
+	}, time.Second, 10*time.Millisecond)
+	changed, err = c.setUnschedulableCondition(mi, 1)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, meta.IsStatusConditionFalse(mi.Status.Conditions, string(workloadv1alpha1.ModelServingUnschedulable)))
+
+	// Once the ServingGroups are running, the nodes are not checked anymore
+	require.NoError(t, kubeClient.CoreV1().Nodes().Delete(ctx, "node-b", metav1.DeleteOptions{}))
+	require.Eventually(t, func() bool {
+		nodes, err := c.nodesLister.List(labels.Everything())
+		return err == nil && len(nodes) == 1
+	}, time.Second, 10*time.Millisecond)
+	changed, err = c.setUnschedulableCondition(mi, 0)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// The nodes of the ModelServings provisioning their nodes are not checked
+	provisioned := newGPUModelServing(1, 32, 0)
+	provisioned.Annotations = map[string]string{workloadv1alpha1.ProvisioningClassAnnotationKey: "queued-provisioning.gke.io"}
+	reason, err := c.checkServingGroupFit(provisioned)
+	require.NoError(t, err)
+	assert.Empty(t, reason)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+const (
+	// Annotations understood by node autoscalers.
+	// Pods waiting for accelerators are marked as not disruptable, so that the nodes provisioned for a
+	// ServingGroup are not consolidated while the rest of the group is still being scheduled.
+	karpenterDoNotDisruptAnnotationKey           = "karpenter.sh/do-not-disrupt"
+	clusterAutoscalerSafeToEvictAnnotationKey    = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+	clusterAutoscalerProvisioningClassAnnotation = "cluster-autoscaler.kubernetes.io/provisioning-class-name"
+	// nodeProvisioningHintsAnnotationKey lists the hints added to a pod by the controller, removed once it is scheduled.
+	nodeProvisioningHintsAnnotationKey = "modelserving.volcano.sh/node-provisioning-hints"
+
+	// defaultProvisioningDuration is the expected time for a new node to be provisioned,
+	// used to estimate when pods waiting for node provisioning will be ready.
+	defaultProvisioningDuration = 10 * time.Minute
+
+	reasonWaitingForNodeProvisioning = "InsufficientAccelerators"
+	reasonNodesProvisioned           = "NodesProvisioned"
+)
+
+// nodeProvisioningState summarizes the pods of a ModelServing waiting for node provisioning.
+type nodeProvisioningState struct {
+	pods   []*corev1.Pod
+	groups []string
+	// since is the earliest time a pod was marked unschedulable
+	since time.Time
+}
+
+func (c *ModelServingController) getNodeProvisioningState(mi *workloadv1alpha1.ModelServing) (*nodeProvisioningState, error) {
+	selector := labels.SelectorFromSet(map[string]string{
+		workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
+	})
+	pods, err := c.podsLister.Pods(mi.Namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &nodeProvisioningState{}
+	groups := make(map[string]bool)
+	for _, pod := range pods {
+		waiting, since := utils.IsPodWaitingForNodeProvisioning(pod)
+		if !waiting {
+			continue
+		}
+		state.pods = append(state.pods, pod)
+		if state.since.IsZero() || since.Before(state.since) {
+			state.since = since
+		}
+		if groupName := pod.Labels[workloadv1alpha1.GroupNameLabelKey]; !groups[groupName] {
+			groups[groupName] = true
+			state.groups = append(state.groups, groupName)
+		}
+	}
+	sort.Strings(state.groups)
+	return state, nil
+}
+
+// manageNodeProvisioningHints annotates the pods waiting for accelerators with the hints used by
+// the cluster autoscaler and Karpenter when provisioning nodes for them. The hints are removed once
+// the pods are scheduled and no pod of their ServingGroup is waiting any more, so that their nodes
+// can be consolidated and drained again.
+func (c *ModelServingController) manageNodeProvisioningHints(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
+	selector := labels.SelectorFromSet(map[string]string{
+		workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
+	})
+	pods, err := c.podsLister.Pods(mi.Namespace).List(selector)
+	if err != nil {
+		return err
+	}
+
+	hints := map[string]string{
+		karpenterDoNotDisruptAnnotationKey:        "true",
+		clusterAutoscalerSafeToEvictAnnotationKey: "false",
+	}
+	if class := mi.Annotations[workloadv1alpha1.ProvisioningClassAnnotationKey]; class != "" {
+		hints[clusterAutoscalerProvisioningClassAnnotation] = class
+	}
+
+	waitingGroups := make(map[string]bool)
+	for _, pod := range pods {
+		if waiting, _ := utils.IsPodWaitingForNodeProvisioning(pod); waiting {
+			waitingGroups[pod.Labels[workloadv1alpha1.GroupNameLabelKey]] = true
+		}
+	}
+
+	for _, pod := range pods {
+		var annotations map[string]interface{}
+		waiting, _ := utils.IsPodWaitingForNodeProvisioning(pod)
+		if waiting {
+			annotations = missingHints(pod, hints)
+		} else if pod.Spec.NodeName != "" && !waitingGroups[pod.Labels[workloadv1alpha1.GroupNameLabelKey]] {
+			annotations = removedHints(pod)
+		}
+		if len(annotations) == 0 {
+			continue
+		}
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": annotations,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := c.kubeClientSet.CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{FieldManager: utils.FieldManager}); err != nil {
+			return fmt.Errorf("failed to update node provisioning hints of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+		if waiting {
+			klog.V(2).Infof("pod %s/%s is waiting for node provisioning, added autoscaler hints", pod.Namespace, pod.Name)
+		} else {
+			klog.V(2).Infof("pod %s/%s is scheduled, removed autoscaler hints", pod.Namespace, pod.Name)
+		}
+	}
+	return nil
+}
+
+// missingHints returns the hints the pod does not have yet, and the list of the hints added by the controller,
+// which are the only ones removed later: the hints set in the pod template are kept.
+func missingHints(pod *corev1.Pod, hints map[string]string) map[string]interface{} {
+	added := sets.New[string]()
+	if value := pod.Annotations[nodeProvisioningHintsAnnotationKey]; value != "" {
+		added.Insert(strings.Split(value, ",")...)
+	}
+	missing := make(map[string]interface{})
+	for key, value := range hints {
+		if _, exists := pod.Annotations[key]; !exists {
+			missing[key] = value
+			added.Insert(key)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	missing[nodeProvisioningHintsAnnotationKey] = strings.Join(sets.List(added), ",")
+	return missing
+}
+
+// removedHints returns the hints added by the controller to the pod, set to null to be removed by a merge patch.
+func removedHints(pod *corev1.Pod) map[string]interface{} {
+	value, exists := pod.Annotations[nodeProvisioningHintsAnnotationKey]
+	if !exists {
+		return nil
+	}
+	removed := map[string]interface{}{nodeProvisioningHintsAnnotationKey: nil}
+	for _, key := range strings.Split(value, ",") {
+		if key != "" {
+			removed[key] = nil
+		}
+	}
+	return removed
+}
+
+// setNodeProvisioningCondition reflects the pods waiting for node provisioning in the ModelServing status,
+// together with the estimated time the nodes will be ready. It returns true if the status has changed.
+func (c *ModelServingController) setNodeProvisioningCondition(mi *workloadv1alpha1.ModelServing) (bool, error) {
+	state, err := c.getNodeProvisioningState(mi)
+	if err != nil {
+		return false, err
+	}
+
+	conditionType := string(workloadv1alpha1.ModelServingWaitingForNodeProvisioning)
+	if len(state.pods) == 0 {
+		if !meta.IsStatusConditionTrue(mi.Status.Conditions, conditionType) {
+			return false, nil
+		}
+		return meta.SetStatusCondition(&mi.Status.Conditions, metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonNodesProvisioned,
+			Message: "No pods are waiting for node provisioning",
+		}), nil
+	}
+
+	eta := state.since.Add(provisioningDuration(mi))
+	message := fmt.Sprintf("%d pods of ServingGroups %v are waiting for node provisioning, estimated ready at %s",
+		len(state.pods), state.groups, eta.UTC().Format(time.RFC3339))
+	return meta.SetStatusCondition(&mi.Status.Conditions, metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonWaitingForNodeProvisioning,
+		Message: message,
+	}), nil
+}
+
+// provisioningDuration returns the expected time to provision a node for the ModelServing.
+func provisioningDuration(mi *workloadv1alpha1.ModelServing) time.Duration {
+	value, ok := mi.Annotations[workloadv1alpha1.ProvisioningDurationAnnotationKey]
+	if !ok {
+		return defaultProvisioningDuration
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		klog.Warningf("invalid %s annotation %q of modelServing %s/%s, using default %v",
+			workloadv1alpha1.ProvisioningDurationAnnotationKey, value, mi.Namespace, mi.Name, defaultProvisioningDuration)
+		return defaultProvisioningDuration
+	}
+	return duration
+}
+
+// isPodNewlyWaitingForNodeProvisioning returns true if the pod has just been marked unschedulable
+// because of insufficient accelerators.
+func isPodNewlyWaitingForNodeProvisioning(oldObj interface{}, newPod *corev1.Pod) bool {
+	if waiting, _ := utils.IsPodWaitingForNodeProvisioning(newPod); !waiting {
+		return false
+	}
+	oldPod, ok := oldObj.(*corev1.Pod)
+	if !ok {
+		return true
+	}
+	wasWaiting, _ := utils.IsPodWaitingForNodeProvisioning(oldPod)
+	return !wasWaiting
+}
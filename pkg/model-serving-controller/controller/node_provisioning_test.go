@@ -0,0 +1,133 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	volcanofake "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+func newProvisioningPod(name, group string, annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels: map[string]string{
+				workloadv1alpha1.ModelServingNameLabelKey: "llama",
+				workloadv1alpha1.GroupNameLabelKey:        group,
+			},
+			Annotations: annotations,
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:      "engine",
+			Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("8")}},
+		}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{{
+				Type:               corev1.PodScheduled,
+				Status:             corev1.ConditionFalse,
+				Reason:             corev1.PodReasonUnschedulable,
+				Message:            "0/3 nodes are available: 3 Insufficient nvidia.com/gpu.",
+				LastTransitionTime: metav1.NewTime(time.Now()),
+			}},
+		},
+	}
+}
+
+// scheduleProvisioningPod binds the pod to a node, as the scheduler does once the node is provisioned.
+func scheduleProvisioningPod(pod *corev1.Pod) *corev1.Pod {
+	pod = pod.DeepCopy()
+	pod.Spec.NodeName = "gpu-node"
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}}
+	return pod
+}
+
+func TestManageNodeProvisioningHints(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The template of pod-1 sets its own eviction policy, which is kept
+	pods := []*corev1.Pod{
+		newProvisioningPod("pod-0", "llama-0", nil),
+		newProvisioningPod("pod-1", "llama-0", map[string]string{clusterAutoscalerSafeToEvictAnnotationKey: "true"}),
+	}
+	kubeClient := kubefake.NewSimpleClientset(pods[0], pods[1])
+	c, err := NewModelServingController(kubeClient, kthenafake.NewSimpleClientset(), volcanofake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	require.NoError(t, err)
+	go c.podsInformer.RunWithContext(ctx)
+	cache.WaitForCacheSync(ctx.Done(), c.podsInformer.HasSynced)
+
+	mi := createStandardModelServing("llama", 1, 1)
+	mi.Annotations = map[string]string{workloadv1alpha1.ProvisioningClassAnnotationKey: "queued-provisioning.gke.io"}
+	// refresh updates the pods lister with the pods of the API server
+	refresh := func() map[string]*corev1.Pod {
+		got := make(map[string]*corev1.Pod)
+		for _, pod := range pods {
+			updated, err := kubeClient.CoreV1().Pods("default").Get(ctx, pod.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.NoError(t, c.podsInformer.GetIndexer().Update(updated))
+			got[pod.Name] = updated
+		}
+		return got
+	}
+	sync := func() map[string]*corev1.Pod {
+		require.NoError(t, c.manageNodeProvisioningHints(ctx, mi))
+		return refresh()
+	}
+
+	// The waiting pods are annotated with the hints they do not set
+	got := sync()
+	assert.Equal(t, map[string]string{
+		karpenterDoNotDisruptAnnotationKey:           "true",
+		clusterAutoscalerSafeToEvictAnnotationKey:    "false",
+		clusterAutoscalerProvisioningClassAnnotation: "queued-provisioning.gke.io",
+		nodeProvisioningHintsAnnotationKey:           "cluster-autoscaler.kubernetes.io/provisioning-class-name,cluster-autoscaler.kubernetes.io/safe-to-evict,karpenter.sh/do-not-disrupt",
+	}, got["pod-0"].Annotations)
+	assert.Equal(t, "true", got["pod-1"].Annotations[clusterAutoscalerSafeToEvictAnnotationKey])
+	assert.Equal(t, "cluster-autoscaler.kubernetes.io/provisioning-class-name,karpenter.sh/do-not-disrupt", got["pod-1"].Annotations[nodeProvisioningHintsAnnotationKey])
+
+	// A scheduled pod keeps its hints while the rest of its ServingGroup is waiting
+	_, err = kubeClient.CoreV1().Pods("default").Update(ctx, scheduleProvisioningPod(got["pod-0"]), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	refresh()
+	got = sync()
+	assert.Equal(t, "true", got["pod-0"].Annotations[karpenterDoNotDisruptAnnotationKey])
+
+	// Once the whole ServingGroup is scheduled, the hints added by the controller are removed
+	_, err = kubeClient.CoreV1().Pods("default").Update(ctx, scheduleProvisioningPod(got["pod-1"]), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	refresh()
+	got = sync()
+	assert.Empty(t, got["pod-0"].Annotations)
+	assert.Equal(t, map[string]string{clusterAutoscalerSafeToEvictAnnotationKey: "true"}, got["pod-1"].Annotations)
+}
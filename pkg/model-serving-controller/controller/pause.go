@@ -0,0 +1,118 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+const (
+	reasonPaused  = "Paused"
+	reasonResumed = "Resumed"
+)
+
+// deletedPod is a pod deleted while its ModelServing was paused, whose recovery is deferred until it is resumed.
+type deletedPod struct {
+	servingGroupName string
+	pod              *corev1.Pod
+}
+
+// deferPodRecovery records a pod deleted while the ModelServing is paused, to recover it once resumed.
+func (c *ModelServingController) deferPodRecovery(mi *workloadv1alpha1.ModelServing, servingGroupName string, pod *corev1.Pod) {
+	c.pausedLock.Lock()
+	defer c.pausedLock.Unlock()
+	key := utils.GetNamespaceName(mi)
+	c.deferredPods[key] = append(c.deferredPods[key], deletedPod{servingGroupName: servingGroupName, pod: pod})
+	klog.V(2).Infof("ModelServing %s is paused, defer the recovery of pod %s", key, pod.Name)
+}
+
+// setPaused records whether the ModelServing is paused, and returns true if it has just been paused or resumed.
+func (c *ModelServingController) setPaused(mi *workloadv1alpha1.ModelServing) bool {
+	c.pausedLock.Lock()
+	defer c.pausedLock.Unlock()
+	key := utils.GetNamespaceName(mi)
+	_, wasPaused := c.deferredPods[key]
+	if mi.Spec.Paused && !wasPaused {
+		c.deferredPods[key] = nil
+	}
+	return mi.Spec.Paused != wasPaused
+}
+
+// resumeModelServing recovers the pods which failed or were deleted while the ModelServing was paused.
+func (c *ModelServingController) resumeModelServing(mi *workloadv1alpha1.ModelServing) {
+	key := utils.GetNamespaceName(mi)
+	c.pausedLock.Lock()
+	pods := c.deferredPods[key]
+	delete(c.deferredPods, key)
+	c.pausedLock.Unlock()
+
+	klog.V(2).Infof("ModelServing %s is resumed, recover %d deleted pods", key, len(pods))
+	for _, deleted := range pods {
+		if err := c.handleDeletedPod(mi, deleted.servingGroupName, deleted.pod); err != nil {
+			klog.Errorf("failed to recover pod %s deleted while paused: %v", deleted.pod.Name, err)
+		}
+	}
+
+	// The failed pods are not notified again, so handle the existing pods as if they had just been updated.
+	selector := labels.SelectorFromSet(map[string]string{
+		workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
+	})
+	existing, err := c.podsLister.Pods(mi.Namespace).List(selector)
+	if err != nil {
+		klog.Errorf("failed to list pods of ModelServing %s: %v", key, err)
+		return
+	}
+	for _, pod := range existing {
+		c.updatePod(nil, pod)
+	}
+}
+
+// forgetPaused drops the pause state of a deleted ModelServing.
+func (c *ModelServingController) forgetPaused(key types.NamespacedName) {
+	c.pausedLock.Lock()
+	defer c.pausedLock.Unlock()
+	delete(c.deferredPods, key)
+}
+
+// setPausedCondition reflects spec.paused in the ModelServing status. It returns true if the status has changed.
+func setPausedCondition(mi *workloadv1alpha1.ModelServing) bool {
+	if !mi.Spec.Paused {
+		if meta.FindStatusCondition(mi.Status.Conditions, string(workloadv1alpha1.ModelServingPaused)) == nil {
+			return false
+		}
+		return meta.SetStatusCondition(&mi.Status.Conditions, metav1.Condition{
+			Type:    string(workloadv1alpha1.ModelServingPaused),
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonResumed,
+			Message: "The reconciliation of the ModelServing is resumed",
+		})
+	}
+	return meta.SetStatusCondition(&mi.Status.Conditions, metav1.Condition{
+		Type:    string(workloadv1alpha1.ModelServingPaused),
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonPaused,
+		Message: "The ModelServing is neither scaled, rolled out nor recovered until spec.paused is unset",
+	})
+}
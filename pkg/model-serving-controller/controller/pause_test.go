@@ -0,0 +1,107 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	volcanofake "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+func TestPausedModelServing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kubeClient := kubefake.NewSimpleClientset()
+	kthenaClient := kthenafake.NewSimpleClientset()
+	c, err := NewModelServingController(kubeClient, kthenaClient, volcanofake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	require.NoError(t, err)
+	go c.podsInformer.RunWithContext(ctx)
+	go c.servicesInformer.RunWithContext(ctx)
+	go c.modelServingsInformer.RunWithContext(ctx)
+	cache.WaitForCacheSync(ctx.Done(), c.podsInformer.HasSynced, c.servicesInformer.HasSynced, c.modelServingsInformer.HasSynced)
+
+	mi := createStandardModelServing("test-mi", 1, 1)
+	mi.Spec.Paused = true
+	_, err = kthenaClient.WorkloadV1alpha1().ModelServings("default").Create(ctx, mi, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.True(t, waitForObjectInCache(t, 2*time.Second, func() bool {
+		_, err := c.modelServingLister.ModelServings("default").Get("test-mi")
+		return err == nil
+	}))
+
+	// Nothing is created while paused
+	require.NoError(t, c.syncModelServing(ctx, "default/test-mi"))
+	pods, err := kubeClient.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, pods.Items)
+	got, err := kthenaClient.WorkloadV1alpha1().ModelServings("default").Get(ctx, "test-mi", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, string(workloadv1alpha1.ModelServingPaused)))
+
+	// The recovery of the pods deleted while paused is deferred
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-mi-0-prefill-0-0"}}
+	require.NoError(t, c.handleDeletedPod(mi, "test-mi-0", pod))
+	assert.Len(t, c.deferredPods[utils.GetNamespaceName(mi)], 1)
+
+	// Resuming reconciles the ModelServing again
+	got.Spec.Paused = false
+	_, err = kthenaClient.WorkloadV1alpha1().ModelServings("default").Update(ctx, got, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.True(t, waitForObjectInCache(t, 2*time.Second, func() bool {
+		current, err := c.modelServingLister.ModelServings("default").Get("test-mi")
+		return err == nil && !current.Spec.Paused
+	}))
+	require.NoError(t, c.syncModelServing(ctx, "default/test-mi"))
+	pods, err = kubeClient.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, pods.Items, utils.ExpectedPodNum(mi))
+	assert.NotContains(t, c.deferredPods, utils.GetNamespaceName(mi))
+	got, err = kthenaClient.WorkloadV1alpha1().ModelServings("default").Get(ctx, "test-mi", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, meta.IsStatusConditionFalse(got.Status.Conditions, string(workloadv1alpha1.ModelServingPaused)))
+}
+
+func TestSetPausedCondition(t *testing.T) {
+	mi := createStandardModelServing("test-mi", 1, 1)
+	// The condition is not reported for ModelServings never paused
+	assert.False(t, setPausedCondition(mi))
+	assert.Empty(t, mi.Status.Conditions)
+
+	mi.Spec.Paused = true
+	assert.True(t, setPausedCondition(mi))
+	assert.False(t, setPausedCondition(mi))
+	assert.True(t, meta.IsStatusConditionTrue(mi.Status.Conditions, string(workloadv1alpha1.ModelServingPaused)))
+
+	mi.Spec.Paused = false
+	assert.True(t, setPausedCondition(mi))
+	assert.True(t, meta.IsStatusConditionFalse(mi.Status.Conditions, string(workloadv1alpha1.ModelServingPaused)))
+}
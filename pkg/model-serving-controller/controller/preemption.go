@@ -0,0 +1,77 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+const (
+	reasonPreempted            = "Preempted"
+	reasonPreemptedByScheduler = "PreemptedByScheduler"
+	reasonRescheduled          = "Rescheduled"
+)
+
+// recordServingGroupPreemption records the preemption of the ServingGroup of a pod deleted by the scheduler in favor
+// of workloads of a higher priority. An event is emitted once per preemption of the ServingGroup.
+func (c *ModelServingController) recordServingGroupPreemption(mi *workloadv1alpha1.ModelServing, groupName string, pod *corev1.Pod) {
+	message, preempted := utils.PreemptionMessage(pod)
+	if !preempted || groupName == "" {
+		return
+	}
+	if c.store.RecordServingGroupPreemption(utils.GetNamespaceName(mi), groupName, message) {
+		c.recorder.Eventf(mi, corev1.EventTypeWarning, reasonPreempted, "Pod %s of ServingGroup %s was preempted: %s",
+			pod.Name, groupName, message)
+	}
+}
+
+// setPreemptedCondition sets the Preempted condition, true until the preempted ServingGroups are running again.
+func (c *ModelServingController) setPreemptedCondition(mi *workloadv1alpha1.ModelServing) bool {
+	conditionType := string(workloadv1alpha1.ModelServingPreempted)
+	preemptions := c.store.GetServingGroupPreemptions(utils.GetNamespaceName(mi))
+	if len(preemptions) > 0 {
+		groups := make([]string, 0, len(preemptions))
+		for groupName, message := range preemptions {
+			groups = append(groups, fmt.Sprintf("%s (%s)", groupName, message))
+		}
+		sort.Strings(groups)
+		return meta.SetStatusCondition(&mi.Status.Conditions, metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  reasonPreemptedByScheduler,
+			Message: fmt.Sprintf("ServingGroups were preempted in favor of workloads of a higher priority: %s", strings.Join(groups, ", ")),
+		})
+	}
+	if !meta.IsStatusConditionTrue(mi.Status.Conditions, conditionType) {
+		return false
+	}
+	return meta.SetStatusCondition(&mi.Status.Conditions, metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonRescheduled,
+		Message: "The preempted ServingGroups are running again",
+	})
+}
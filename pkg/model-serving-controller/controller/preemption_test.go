@@ -0,0 +1,89 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	volcanofake "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/datastore"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+func TestPreemptedCondition(t *testing.T) {
+	c, err := NewModelServingController(kubefake.NewSimpleClientset(), kthenafake.NewSimpleClientset(), volcanofake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	require.NoError(t, err)
+
+	mi := createStandardModelServing("llama", 2, 1)
+	key := utils.GetNamespaceName(mi)
+	conditionType := string(workloadv1alpha1.ModelServingPreempted)
+	c.store.AddServingGroup(key, 0, "rev")
+	c.store.AddServingGroup(key, 1, "rev")
+	newPod := func(name string, conditions ...corev1.PodCondition) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: mi.Namespace},
+			Status:     corev1.PodStatus{Conditions: conditions},
+		}
+	}
+
+	// The pods deleted for another reason are not preemptions
+	c.recordServingGroupPreemption(mi, "llama-0", newPod("llama-0-leader-0-0"))
+	assert.False(t, c.setPreemptedCondition(mi))
+	assert.Nil(t, meta.FindStatusCondition(mi.Status.Conditions, conditionType))
+
+	c.recordServingGroupPreemption(mi, "llama-1", newPod("llama-1-leader-0-0", corev1.PodCondition{
+		Type:    corev1.DisruptionTarget,
+		Status:  corev1.ConditionTrue,
+		Reason:  corev1.PodReasonPreemptionByScheduler,
+		Message: "default-scheduler: preempting to accommodate a higher priority pod",
+	}))
+	c.recordServingGroupPreemption(mi, "llama-0", newPod("llama-0-leader-0-0", corev1.PodCondition{
+		Type:   corev1.PodReady,
+		Status: corev1.ConditionFalse,
+		Reason: "Evict",
+	}))
+	assert.True(t, c.setPreemptedCondition(mi))
+	condition := meta.FindStatusCondition(mi.Status.Conditions, conditionType)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, reasonPreemptedByScheduler, condition.Reason)
+	assert.Equal(t, "ServingGroups were preempted in favor of workloads of a higher priority: "+
+		"llama-0 (Evict), llama-1 (default-scheduler: preempting to accommodate a higher priority pod)", condition.Message)
+
+	// The condition is unset once all the preempted ServingGroups are running again
+	require.NoError(t, c.store.UpdateServingGroupStatus(key, "llama-0", datastore.ServingGroupRunning))
+	assert.True(t, c.setPreemptedCondition(mi))
+	assert.True(t, meta.IsStatusConditionTrue(mi.Status.Conditions, conditionType))
+	require.NoError(t, c.store.UpdateServingGroupStatus(key, "llama-1", datastore.ServingGroupRunning))
+	assert.True(t, c.setPreemptedCondition(mi))
+	condition = meta.FindStatusCondition(mi.Status.Conditions, conditionType)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, reasonRescheduled, condition.Reason)
+	assert.False(t, c.setPreemptedCondition(mi))
+}
@@ -0,0 +1,166 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/datastore"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+const (
+	// defaultInitialBackoff and defaultMaxBackoff are the recovery backoff of the ModelServings without one,
+	// the same as the CrashLoopBackOff of the kubelet.
+	defaultInitialBackoff = 10 * time.Second
+	defaultMaxBackoff     = 5 * time.Minute
+
+	reasonBackOff              = "BackOff"
+	reasonRetryLimitExceeded   = "RetryLimitExceeded"
+	reasonRecoveryBackoffReset = "RecoveryBackoffReset"
+	reasonRecovered            = "Recovered"
+)
+
+// backoffDelay returns the delay before recreating a ServingGroup after its failures in a row, which doubles with
+// each failure up to the max delay.
+func backoffDelay(mi *workloadv1alpha1.ModelServing, restarts int32) time.Duration {
+	initial, maxDelay := defaultInitialBackoff, defaultMaxBackoff
+	if policy := mi.Spec.RecoveryBackoff; policy != nil {
+		if policy.InitialDelaySeconds > 0 {
+			initial = time.Duration(policy.InitialDelaySeconds) * time.Second
+		}
+		if policy.MaxDelaySeconds > 0 {
+			maxDelay = time.Duration(policy.MaxDelaySeconds) * time.Second
+		}
+	}
+	delay := initial
+	for i := int32(1); i < restarts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// retriesExceeded reports whether a ServingGroup failed more times in a row than the max retries of the ModelServing.
+func retriesExceeded(mi *workloadv1alpha1.ModelServing, backoff datastore.RecoveryBackoff) bool {
+	policy := mi.Spec.RecoveryBackoff
+	return policy != nil && policy.MaxRetries > 0 && backoff.Restarts > policy.MaxRetries
+}
+
+// holdRecreation reports whether a failed ServingGroup, or its failed role, must not be recreated yet. The ModelServing
+// is requeued at the end of the backoff, the ServingGroups which exceeded their retries wait for a reset.
+func (c *ModelServingController) holdRecreation(mi *workloadv1alpha1.ModelServing, groupName string) bool {
+	key := utils.GetNamespaceName(mi)
+	backoff, ok := c.store.GetRecoveryBackoffs(key)[groupName]
+	if !ok || backoff.Recreated {
+		return false
+	}
+	if retriesExceeded(mi, backoff) {
+		klog.V(4).Infof("ServingGroup %s of ModelServing %s exceeded its retries, not recreating it", groupName, key)
+		return true
+	}
+	if wait := time.Until(backoff.LastFailure.Add(backoffDelay(mi, backoff.Restarts))); wait > 0 {
+		klog.V(4).Infof("ServingGroup %s of ModelServing %s is backing off, recreating it in %v", groupName, key, wait)
+		c.workqueue.AddAfter(key.String(), wait)
+		return true
+	}
+	return false
+}
+
+// recordServingGroupFailure counts the failure of the ServingGroup of a pod deleted after failing.
+func (c *ModelServingController) recordServingGroupFailure(mi *workloadv1alpha1.ModelServing, pod *corev1.Pod) {
+	groupName := pod.Labels[workloadv1alpha1.GroupNameLabelKey]
+	if groupName == "" {
+		return
+	}
+	backoff, counted := c.store.RecordServingGroupFailure(utils.GetNamespaceName(mi), groupName, time.Now())
+	if !counted {
+		return
+	}
+	if retriesExceeded(mi, backoff) {
+		c.recorder.Eventf(mi, corev1.EventTypeWarning, reasonRetryLimitExceeded,
+			"ServingGroup %s failed %d times in a row, it is no longer recreated until the %s annotation is changed",
+			groupName, backoff.Restarts, workloadv1alpha1.ResetRecoveryBackoffAnnotationKey)
+		return
+	}
+	c.recorder.Eventf(mi, corev1.EventTypeWarning, reasonBackOff, "ServingGroup %s failed %d times in a row, recreating it in %v",
+		groupName, backoff.Restarts, backoffDelay(mi, backoff.Restarts))
+}
+
+// resetRecoveryBackoff forgets the failures of the ServingGroups when the reset annotation changes.
+func (c *ModelServingController) resetRecoveryBackoff(mi *workloadv1alpha1.ModelServing) {
+	token, ok := mi.Annotations[workloadv1alpha1.ResetRecoveryBackoffAnnotationKey]
+	if !ok {
+		return
+	}
+	if c.store.ResetRecoveryBackoffs(utils.GetNamespaceName(mi), token) {
+		c.recorder.Event(mi, corev1.EventTypeNormal, reasonRecoveryBackoffReset, "Reset the recovery backoff of the failed ServingGroups")
+	}
+}
+
+// setRecoveryBackOffCondition sets the RecoveryBackOff condition, true while failed ServingGroups are not recreated.
+func (c *ModelServingController) setRecoveryBackOffCondition(mi *workloadv1alpha1.ModelServing) bool {
+	conditionType := string(workloadv1alpha1.ModelServingRecoveryBackOff)
+	var waiting, exceeded []string
+	for groupName, backoff := range c.store.GetRecoveryBackoffs(utils.GetNamespaceName(mi)) {
+		if backoff.Recreated {
+			continue
+		}
+		failures := fmt.Sprintf("%s failed %d times", groupName, backoff.Restarts)
+		if retriesExceeded(mi, backoff) {
+			exceeded = append(exceeded, failures)
+		} else {
+			waiting = append(waiting, failures)
+		}
+	}
+	if len(exceeded) > 0 {
+		sort.Strings(exceeded)
+		return meta.SetStatusCondition(&mi.Status.Conditions, metav1.Condition{
+			Type:   conditionType,
+			Status: metav1.ConditionTrue,
+			Reason: reasonRetryLimitExceeded,
+			Message: fmt.Sprintf("ServingGroups are no longer recreated until the %s annotation is changed: %s",
+				workloadv1alpha1.ResetRecoveryBackoffAnnotationKey, strings.Join(exceeded, ", ")),
+		})
+	}
+	if len(waiting) > 0 {
+		sort.Strings(waiting)
+		return meta.SetStatusCondition(&mi.Status.Conditions, metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  reasonBackOff,
+			Message: fmt.Sprintf("ServingGroups are waiting for their recovery backoff: %s", strings.Join(waiting, ", ")),
+		})
+	}
+	if !meta.IsStatusConditionTrue(mi.Status.Conditions, conditionType) {
+		return false
+	}
+	return meta.SetStatusCondition(&mi.Status.Conditions, metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonRecovered,
+		Message: "The failed ServingGroups are recreated",
+	})
+}
@@ -0,0 +1,135 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	volcanofake "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   *workloadv1alpha1.RecoveryBackoff
+		restarts int32
+		want     time.Duration
+	}{
+		{name: "first failure", restarts: 1, want: 10 * time.Second},
+		{name: "doubles", restarts: 3, want: 40 * time.Second},
+		{name: "capped", restarts: 10, want: 5 * time.Minute},
+		{name: "no overflow", restarts: 1000, want: 5 * time.Minute},
+		{
+			name:     "policy",
+			policy:   &workloadv1alpha1.RecoveryBackoff{InitialDelaySeconds: 1, MaxDelaySeconds: 60},
+			restarts: 4,
+			want:     8 * time.Second,
+		},
+		{
+			name:     "policy capped",
+			policy:   &workloadv1alpha1.RecoveryBackoff{InitialDelaySeconds: 1, MaxDelaySeconds: 60},
+			restarts: 8,
+			want:     time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mi := &workloadv1alpha1.ModelServing{Spec: workloadv1alpha1.ModelServingSpec{RecoveryBackoff: tt.policy}}
+			assert.Equal(t, tt.want, backoffDelay(mi, tt.restarts))
+		})
+	}
+}
+
+func TestRecoveryBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kubeClient := kubefake.NewSimpleClientset()
+	c, err := NewModelServingController(kubeClient, kthenafake.NewSimpleClientset(), volcanofake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	require.NoError(t, err)
+	go c.podsInformer.RunWithContext(ctx)
+	go c.nodesInformer.RunWithContext(ctx)
+	cache.WaitForCacheSync(ctx.Done(), c.podsInformer.HasSynced, c.nodesInformer.HasSynced)
+
+	mi := createStandardModelServing("llama", 1, 1)
+	mi.Spec.RecoveryBackoff = &workloadv1alpha1.RecoveryBackoff{InitialDelaySeconds: 60, MaxDelaySeconds: 600, MaxRetries: 2}
+	key := utils.GetNamespaceName(mi)
+	groupName := utils.GenerateServingGroupName(mi.Name, 0)
+	conditionType := string(workloadv1alpha1.ModelServingRecoveryBackOff)
+	countPods := func() int {
+		pods, err := kubeClient.CoreV1().Pods(mi.Namespace).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		return len(pods.Items)
+	}
+
+	// The ServingGroup just failed, it is recreated after the backoff
+	c.store.RecordServingGroupFailure(key, groupName, time.Now())
+	require.NoError(t, c.manageServingGroupReplicas(ctx, mi, "rev"))
+	assert.Zero(t, countPods(), "a ServingGroup backing off is not recreated")
+	assert.True(t, c.setRecoveryBackOffCondition(mi))
+	condition := meta.FindStatusCondition(mi.Status.Conditions, conditionType)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, reasonBackOff, condition.Reason)
+	assert.Equal(t, "ServingGroups are waiting for their recovery backoff: llama-0 failed 1 times", condition.Message)
+
+	// Once the backoff has elapsed
+	c.store.DeleteRecoveryBackoff(key, groupName)
+	c.store.RecordServingGroupFailure(key, groupName, time.Now().Add(-time.Minute))
+	require.NoError(t, c.manageServingGroupReplicas(ctx, mi, "rev"))
+	assert.Equal(t, 1, countPods())
+	assert.True(t, c.setRecoveryBackOffCondition(mi))
+	assert.True(t, meta.IsStatusConditionFalse(mi.Status.Conditions, conditionType))
+
+	// The ServingGroup keeps failing beyond its retries
+	c.store.DeleteServingGroup(key, groupName)
+	pods, err := kubeClient.CoreV1().Pods(mi.Namespace).List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	for _, pod := range pods.Items {
+		require.NoError(t, kubeClient.CoreV1().Pods(mi.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}))
+	}
+	c.store.RecordServingGroupFailure(key, groupName, time.Now().Add(-time.Hour))
+	c.store.MarkServingGroupRecreated(key, groupName)
+	c.store.RecordServingGroupFailure(key, groupName, time.Now().Add(-time.Hour))
+	require.NoError(t, c.manageServingGroupReplicas(ctx, mi, "rev"))
+	assert.Zero(t, countPods(), "a ServingGroup which exceeded its retries is not recreated")
+	assert.True(t, c.setRecoveryBackOffCondition(mi))
+	condition = meta.FindStatusCondition(mi.Status.Conditions, conditionType)
+	require.NotNil(t, condition)
+	assert.Equal(t, reasonRetryLimitExceeded, condition.Reason)
+
+	// Until the backoff is reset
+	mi.Annotations = map[string]string{workloadv1alpha1.ResetRecoveryBackoffAnnotationKey: "1"}
+	c.resetRecoveryBackoff(mi)
+	require.NoError(t, c.manageServingGroupReplicas(ctx, mi, "rev"))
+	assert.Equal(t, 1, countPods())
+	assert.True(t, c.setRecoveryBackOffCondition(mi))
+	assert.True(t, meta.IsStatusConditionFalse(mi.Status.Conditions, conditionType))
+}
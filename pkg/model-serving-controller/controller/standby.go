@@ -0,0 +1,77 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+// manageStandbyLabels keeps the standby label of the pods in line with the replicas of the ModelServing.
+// When scaling up, the label is removed from the pods of the standby ServingGroups that become active,
+// so that they receive traffic without being recreated. When scaling down, the ServingGroups kept as
+// standby are labeled instead.
+func (c *ModelServingController) manageStandbyLabels(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
+	selector := labels.SelectorFromSet(map[string]string{
+		workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
+	})
+	pods, err := c.podsLister.Pods(mi.Namespace).List(selector)
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods {
+		_, ordinal := utils.GetParentNameAndOrdinal(pod.Labels[workloadv1alpha1.GroupNameLabelKey])
+		if ordinal < 0 {
+			continue
+		}
+		standby := utils.IsStandbyServingGroup(mi, ordinal)
+		if standby == utils.IsStandbyPod(pod) {
+			continue
+		}
+
+		// A null value removes the label in a merge patch.
+		var value interface{}
+		if standby {
+			value = "true"
+		}
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": map[string]interface{}{
+					workloadv1alpha1.StandbyLabelKey: value,
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := c.kubeClientSet.CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{FieldManager: utils.FieldManager}); err != nil {
+			return fmt.Errorf("failed to update standby label of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+		klog.V(2).Infof("Set standby of pod %s/%s to %t", pod.Namespace, pod.Name, standby)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+func TestManageStandbyLabels(t *testing.T) {
+	ctx := context.Background()
+	mi := createStandardModelServing("test-mi", 2, 1)
+	mi.Spec.StandbyReplicas = ptr.To[int32](1)
+
+	newPod := func(group string, standby bool) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      group + "-prefill-0-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				workloadv1alpha1.ModelServingNameLabelKey: "test-mi",
+				workloadv1alpha1.GroupNameLabelKey:        group,
+			},
+		}}
+		if standby {
+			pod.Labels[workloadv1alpha1.StandbyLabelKey] = "true"
+		}
+		return pod
+	}
+	// test-mi-1 was a standby ServingGroup before scaling up, test-mi-2 is not labeled yet
+	pods := []*corev1.Pod{newPod("test-mi-0", false), newPod("test-mi-1", true), newPod("test-mi-2", false)}
+
+	kubeClient := kubefake.NewSimpleClientset()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, pod := range pods {
+		_, err := kubeClient.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{})
+		require.NoError(t, err)
+		require.NoError(t, indexer.Add(pod))
+	}
+	c := &ModelServingController{
+		kubeClientSet: kubeClient,
+		podsLister:    listerv1.NewPodLister(indexer),
+	}
+
+	require.NoError(t, c.manageStandbyLabels(ctx, mi))
+
+	for group, standby := range map[string]bool{"test-mi-0": false, "test-mi-1": false, "test-mi-2": true} {
+		pod, err := kubeClient.CoreV1().Pods("default").Get(ctx, group+"-prefill-0-0", metav1.GetOptions{})
+		require.NoError(t, err)
+		_, labeled := pod.Labels[workloadv1alpha1.StandbyLabelKey]
+		assert.Equal(t, standby, labeled, "pod of ServingGroup %s", group)
+		assert.Equal(t, "test-mi", pod.Labels[workloadv1alpha1.ModelServingNameLabelKey])
+	}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+const (
+	// startupProbeTimeout bounds the time spent probing the startup endpoint of a pod.
+	startupProbeTimeout = 2 * time.Second
+	// startupResyncPeriod is the period the ModelServing is resynced at while its engines are starting,
+	// since the startup progress is not reflected in pod events.
+	startupResyncPeriod = 15 * time.Second
+
+	reasonWeightsLoaded  = "WeightsLoaded"
+	reasonLoadingWeights = "LoadingWeights"
+	reasonEngineWarmedUp = "EngineWarmedUp"
+	reasonWarmingUp      = "WarmingUp"
+)
+
+// startupProgress is the startup progress reported by the startup endpoint of a pod.
+type startupProgress struct {
+	WeightsLoaded  bool  `json:"weightsLoaded"`
+	EngineWarmedUp bool  `json:"engineWarmedUp"`
+	Percentage     int32 `json:"percentage"`
+}
+
+// startupState summarizes the startup progress of the pods of a ModelServing.
+type startupState struct {
+	pods          int
+	weightsLoaded int
+	warmedUp      int
+	percentage    int32
+}
+
+// starting returns true if some engines have not finished warming up yet.
+func (s *startupState) starting() bool {
+	return s.warmedUp < s.pods
+}
+
+// startupProber probes the startup endpoints of the pods in the background, one probe at a time per pod, and caches
+// their progress by pod UID, so that a slow pod does not block the sync of its ModelServing. The pods which have
+// warmed up their engine are not probed anymore.
+type startupProber struct {
+	client *http.Client
+	// probed is called when the progress of a pod changed
+	probed func(pod *corev1.Pod)
+
+	mu       sync.Mutex
+	progress map[types.UID]startupProgress
+	probing  sets.Set[types.UID]
+}
+
+func newStartupProber(probed func(pod *corev1.Pod)) *startupProber {
+	return &startupProber{
+		client:   &http.Client{Timeout: startupProbeTimeout},
+		probed:   probed,
+		progress: make(map[types.UID]startupProgress),
+		probing:  sets.New[types.UID](),
+	}
+}
+
+// progressOf returns the last known progress of the pod, nil if it has not been probed yet. The pod is probed again
+// in the background unless its engine has warmed up or it is being probed already.
+func (p *startupProber) progressOf(pod *corev1.Pod, endpoint string) *startupProgress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	progress, probed := p.progress[pod.UID]
+	if probed && progress.EngineWarmedUp {
+		return &progress
+	}
+	if !p.probing.Has(pod.UID) {
+		p.probing.Insert(pod.UID)
+		go p.probe(pod, endpoint)
+	}
+	if !probed {
+		return nil
+	}
+	return &progress
+}
+
+func (p *startupProber) probe(pod *corev1.Pod, endpoint string) {
+	progress, err := probeStartupProgress(p.client, pod, endpoint)
+	if err != nil {
+		klog.V(4).Infof("failed to probe startup progress of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+
+	p.mu.Lock()
+	if !p.probing.Has(pod.UID) {
+		// the pod has been deleted meanwhile
+		p.mu.Unlock()
+		return
+	}
+	p.probing.Delete(pod.UID)
+	changed := false
+	if last, probed := p.progress[pod.UID]; err == nil && (!probed || last != *progress) {
+		p.progress[pod.UID] = *progress
+		changed = true
+	}
+	p.mu.Unlock()
+
+	if changed && p.probed != nil {
+		p.probed(pod)
+	}
+}
+
+// forget drops the progress of a deleted pod.
+func (p *startupProber) forget(uid types.UID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.progress, uid)
+	p.probing.Delete(uid)
+}
+
+// getStartupState summarizes the startup progress of the running pods of the ModelServing, as last probed.
+// Pods which do not report their startup progress are ignored, pods not probed yet or failing to be probed are
+// considered not loaded.
+func (c *ModelServingController) getStartupState(mi *workloadv1alpha1.ModelServing) (*startupState, error) {
+	selector := labels.SelectorFromSet(map[string]string{
+		workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
+	})
+	pods, err := c.podsLister.Pods(mi.Namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &startupState{}
+	var total int32
+	for _, pod := range pods {
+		endpoint, ok := pod.Annotations[workloadv1alpha1.StartupEndpointAnnotationKey]
+		if !ok || pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		state.pods++
+		progress := c.startupProber.progressOf(pod, endpoint)
+		if progress == nil {
+			continue
+		}
+		if progress.WeightsLoaded {
+			state.weightsLoaded++
+		}
+		if progress.EngineWarmedUp {
+			state.warmedUp++
+		}
+		total += min(max(progress.Percentage, 0), 100)
+	}
+	if state.pods > 0 {
+		state.percentage = total / int32(state.pods)
+	}
+	return state, nil
+}
+
+// probeStartupProgress gets the startup progress from the endpoint, in the form ":<port><path>", of the pod.
+func probeStartupProgress(client *http.Client, pod *corev1.Pod, endpoint string) (*startupProgress, error) {
+	port, path, ok := strings.Cut(strings.TrimPrefix(endpoint, ":"), "/")
+	if !ok || port == "" {
+		return nil, fmt.Errorf("invalid %s annotation %q", workloadv1alpha1.StartupEndpointAnnotationKey, endpoint)
+	}
+	url := fmt.Sprintf("http://%s/%s", net.JoinHostPort(pod.Status.PodIP, port), path)
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+
+	progress := &startupProgress{}
+	if err := json.NewDecoder(resp.Body).Decode(progress); err != nil {
+		return nil, fmt.Errorf("failed to decode startup progress from %s: %v", url, err)
+	}
+	return progress, nil
+}
+
+// setStartupConditions reflects the startup progress of the engines in the ModelServing status.
+// It returns true if the status has changed.
+func setStartupConditions(mi *workloadv1alpha1.ModelServing, state *startupState) bool {
+	if state.pods == 0 {
+		return false
+	}
+
+	changed := false
+	if mi.Status.LoadingPercentage != state.percentage {
+		mi.Status.LoadingPercentage = state.percentage
+		changed = true
+	}
+
+	weightsLoaded := metav1.Condition{
+		Type:    string(workloadv1alpha1.ModelServingWeightsLoaded),
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonWeightsLoaded,
+		Message: fmt.Sprintf("%d/%d pods have loaded the model weights", state.weightsLoaded, state.pods),
+	}
+	if state.weightsLoaded < state.pods {
+		weightsLoaded.Status = metav1.ConditionFalse
+		weightsLoaded.Reason = reasonLoadingWeights
+	}
+	if meta.SetStatusCondition(&mi.Status.Conditions, weightsLoaded) {
+		changed = true
+	}
+
+	warmedUp := metav1.Condition{
+		Type:    string(workloadv1alpha1.ModelServingEngineWarmedUp),
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonEngineWarmedUp,
+		Message: fmt.Sprintf("%d/%d pods have warmed up the engine", state.warmedUp, state.pods),
+	}
+	if state.starting() {
+		warmedUp.Status = metav1.ConditionFalse
+		warmedUp.Reason = reasonWarmingUp
+	}
+	if meta.SetStatusCondition(&mi.Status.Conditions, warmedUp) {
+		changed = true
+	}
+	return changed
+}
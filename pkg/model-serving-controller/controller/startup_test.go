@@ -0,0 +1,138 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+func TestGetStartupState(t *testing.T) {
+	mi := createStandardModelServing("test-mi", 2, 1)
+
+	newServer := func(progress startupProgress) (string, string) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/startup", r.URL.Path)
+			_ = json.NewEncoder(w).Encode(progress)
+		}))
+		t.Cleanup(server.Close)
+		u, err := url.Parse(server.URL)
+		require.NoError(t, err)
+		host, port, err := net.SplitHostPort(u.Host)
+		require.NoError(t, err)
+		return host, port
+	}
+	newPod := func(name, ip, port string, phase corev1.PodPhase) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       types.UID(name),
+				Labels:    map[string]string{workloadv1alpha1.ModelServingNameLabelKey: "test-mi"},
+			},
+			Status: corev1.PodStatus{Phase: phase, PodIP: ip},
+		}
+		if port != "" {
+			pod.Annotations = map[string]string{workloadv1alpha1.StartupEndpointAnnotationKey: ":" + port + "/v1/startup"}
+		}
+		return pod
+	}
+
+	readyIP, readyPort := newServer(startupProgress{WeightsLoaded: true, EngineWarmedUp: true, Percentage: 100})
+	warmingIP, warmingPort := newServer(startupProgress{WeightsLoaded: true, Percentage: 60})
+	pods := []*corev1.Pod{
+		newPod("ready", readyIP, readyPort, corev1.PodRunning),
+		newPod("warming", warmingIP, warmingPort, corev1.PodRunning),
+		// not probed
+		newPod("pending", "", readyPort, corev1.PodPending),
+		newPod("no-endpoint", readyIP, "", corev1.PodRunning),
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, pod := range pods {
+		require.NoError(t, indexer.Add(pod))
+	}
+	probed := make(chan string, 10)
+	c := &ModelServingController{
+		podsLister: listerv1.NewPodLister(indexer),
+		startupProber: newStartupProber(func(pod *corev1.Pod) {
+			probed <- pod.Name
+		}),
+	}
+
+	// The pods are probed in the background
+	state, err := c.getStartupState(mi)
+	require.NoError(t, err)
+	assert.Equal(t, &startupState{pods: 2}, state)
+	assert.ElementsMatch(t, []string{"ready", "warming"}, []string{<-probed, <-probed})
+
+	state, err = c.getStartupState(mi)
+	require.NoError(t, err)
+	assert.Equal(t, &startupState{pods: 2, weightsLoaded: 2, warmedUp: 1, percentage: 80}, state)
+	assert.True(t, state.starting())
+
+	// The warmed up pod is not probed anymore, the warming one is probed again and keeps its progress
+	require.Eventually(t, func() bool {
+		c.startupProber.mu.Lock()
+		defer c.startupProber.mu.Unlock()
+		return !c.startupProber.probing.Has("warming")
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.False(t, c.startupProber.probing.Has("ready"))
+	assert.Empty(t, probed)
+
+	// A deleted pod is forgotten
+	c.startupProber.forget("warming")
+	assert.NotContains(t, c.startupProber.progress, types.UID("warming"))
+}
+
+func TestSetStartupConditions(t *testing.T) {
+	mi := createStandardModelServing("test-mi", 2, 1)
+
+	assert.False(t, setStartupConditions(mi, &startupState{}), "no pods report their startup progress")
+	assert.Empty(t, mi.Status.Conditions)
+
+	assert.True(t, setStartupConditions(mi, &startupState{pods: 2, weightsLoaded: 1, percentage: 25}))
+	assert.Equal(t, int32(25), mi.Status.LoadingPercentage)
+	weightsLoaded := meta.FindStatusCondition(mi.Status.Conditions, string(workloadv1alpha1.ModelServingWeightsLoaded))
+	require.NotNil(t, weightsLoaded)
+	assert.Equal(t, metav1.ConditionFalse, weightsLoaded.Status)
+	assert.Equal(t, reasonLoadingWeights, weightsLoaded.Reason)
+	assert.Equal(t, "1/2 pods have loaded the model weights", weightsLoaded.Message)
+	assert.True(t, meta.IsStatusConditionFalse(mi.Status.Conditions, string(workloadv1alpha1.ModelServingEngineWarmedUp)))
+
+	assert.False(t, setStartupConditions(mi, &startupState{pods: 2, weightsLoaded: 1, percentage: 25}), "nothing changed")
+
+	assert.True(t, setStartupConditions(mi, &startupState{pods: 2, weightsLoaded: 2, warmedUp: 2, percentage: 100}))
+	assert.Equal(t, int32(100), mi.Status.LoadingPercentage)
+	assert.True(t, meta.IsStatusConditionTrue(mi.Status.Conditions, string(workloadv1alpha1.ModelServingWeightsLoaded)))
+	assert.True(t, meta.IsStatusConditionTrue(mi.Status.Conditions, string(workloadv1alpha1.ModelServingEngineWarmedUp)))
+}
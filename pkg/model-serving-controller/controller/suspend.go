@@ -0,0 +1,136 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/datastore"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+const (
+	reasonSuspended = "Suspended"
+)
+
+// suspendModelServing deletes the pods of the ServingGroups of a suspended ModelServing. The ServingGroups are kept
+// in the store, with their headless services and PodGroups, so that resuming only recreates their pods.
+func (c *ModelServingController) suspendModelServing(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
+	key := utils.GetNamespaceName(mi)
+	groups, err := c.store.GetServingGroupByModelServing(key)
+	if err != nil && !errors.Is(err, datastore.ErrServingGroupNotFound) {
+		return err
+	}
+	for _, group := range groups {
+		if group.Status == datastore.ServingGroupSuspended || group.Status == datastore.ServingGroupDeleting {
+			continue
+		}
+		pods, err := c.getPodsByIndex(GroupNameKey, fmt.Sprintf("%s/%s", mi.Namespace, group.Name))
+		if err != nil {
+			return err
+		}
+		// The status is set first, so that the deleted pods are not recovered
+		if err := c.store.UpdateServingGroupStatus(key, group.Name, datastore.ServingGroupSuspended); err != nil {
+			return err
+		}
+		c.expectPodDeletions(pods)
+		err = c.kubeClientSet.CoreV1().Pods(mi.Namespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(map[string]string{workloadv1alpha1.GroupNameLabelKey: group.Name}).String(),
+		})
+		if err != nil {
+			c.lowerPodDeletions(pods)
+			if statusErr := c.store.UpdateServingGroupStatus(key, group.Name, group.Status); statusErr != nil {
+				klog.Errorf("failed to restore the status of ServingGroup %s: %v", group.Name, statusErr)
+			}
+			return fmt.Errorf("failed to delete the pods of ServingGroup %s: %v", group.Name, err)
+		}
+		c.recorder.Eventf(mi, corev1.EventTypeNormal, reasonSuspended, "Suspended ServingGroup %s", group.Name)
+	}
+	return nil
+}
+
+// resumeServingGroups recreates the pods of the ServingGroups suspended until the ModelServing was resumed, once
+// their former pods are gone. The suspended ServingGroups beyond the replicas are deleted by the scaling instead.
+func (c *ModelServingController) resumeServingGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing, revision string) error {
+	key := utils.GetNamespaceName(mi)
+	groups, err := c.store.GetServingGroupByModelServing(key)
+	if err != nil && !errors.Is(err, datastore.ErrServingGroupNotFound) {
+		return err
+	}
+	for _, group := range groups {
+		_, ordinal := utils.GetParentNameAndOrdinal(group.Name)
+		if group.Status != datastore.ServingGroupSuspended || ordinal < 0 || ordinal >= utils.ServingGroupCount(mi) {
+			continue
+		}
+		pods, err := c.getPodsByIndex(GroupNameKey, fmt.Sprintf("%s/%s", mi.Namespace, group.Name))
+		if err != nil {
+			return err
+		}
+		if len(pods) > 0 || !c.expectations.GroupSatisfied(mi.Namespace, group.Name) {
+			// The new pods have the names of the former ones, the ModelServing is enqueued once they are deleted
+			klog.V(4).Infof("waiting for the pods of suspended ServingGroup %s to be deleted", group.Name)
+			continue
+		}
+		// The revision is set before the pods are created, so that their events are not skipped
+		if err := c.store.UpdateServingGroupRevision(key, group.Name, revision); err != nil {
+			return err
+		}
+		if err := c.store.UpdateServingGroupStatus(key, group.Name, datastore.ServingGroupCreating); err != nil {
+			return err
+		}
+		if err := c.CreatePodsForServingGroup(ctx, mi, ordinal, revision); err != nil {
+			// The pods already created are kept, creating them again is a no-op
+			if statusErr := c.store.UpdateServingGroupStatus(key, group.Name, datastore.ServingGroupSuspended); statusErr != nil {
+				klog.Errorf("failed to restore the status of ServingGroup %s: %v", group.Name, statusErr)
+			}
+			c.recorder.Eventf(mi, corev1.EventTypeWarning, reasonFailedCreateServingGroup, "Failed to resume ServingGroup %s: %v", group.Name, err)
+			return fmt.Errorf("failed to resume ServingGroup %s: %v", group.Name, err)
+		}
+		c.recorder.Eventf(mi, corev1.EventTypeNormal, reasonResumed, "Resumed ServingGroup %s", group.Name)
+	}
+	return nil
+}
+
+// setSuspendedCondition reflects spec.suspend in the ModelServing status. It returns true if the status has changed.
+func setSuspendedCondition(mi *workloadv1alpha1.ModelServing) bool {
+	if !mi.Spec.Suspend {
+		if meta.FindStatusCondition(mi.Status.Conditions, string(workloadv1alpha1.ModelServingSuspended)) == nil {
+			return false
+		}
+		return meta.SetStatusCondition(&mi.Status.Conditions, metav1.Condition{
+			Type:    string(workloadv1alpha1.ModelServingSuspended),
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonResumed,
+			Message: "The ModelServing is resumed",
+		})
+	}
+	return meta.SetStatusCondition(&mi.Status.Conditions, metav1.Condition{
+		Type:    string(workloadv1alpha1.ModelServingSuspended),
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonSuspended,
+		Message: "The ModelServing is scaled to zero until spec.suspend is unset",
+	})
+}
@@ -0,0 +1,126 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	volcanofake "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/datastore"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+func TestSuspendedModelServing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kubeClient := kubefake.NewSimpleClientset()
+	// The fake clientset does not implement the deletion of collections
+	kubeClient.PrependReactor("delete-collection", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		selector, err := labels.Parse(action.(k8stesting.DeleteCollectionAction).GetListRestrictions().Labels.String())
+		if err != nil {
+			return true, nil, err
+		}
+		// The reactors run with the lock of the clientset held, the tracker is used directly
+		obj, err := kubeClient.Tracker().List(action.GetResource(), corev1.SchemeGroupVersion.WithKind("Pod"), action.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		for _, pod := range obj.(*corev1.PodList).Items {
+			if !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			if err := kubeClient.Tracker().Delete(action.GetResource(), pod.Namespace, pod.Name); err != nil {
+				return true, nil, err
+			}
+		}
+		return true, nil, nil
+	})
+	kthenaClient := kthenafake.NewSimpleClientset()
+	c, err := NewModelServingController(kubeClient, kthenaClient, volcanofake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	require.NoError(t, err)
+	go c.podsInformer.RunWithContext(ctx)
+	go c.servicesInformer.RunWithContext(ctx)
+	go c.modelServingsInformer.RunWithContext(ctx)
+	cache.WaitForCacheSync(ctx.Done(), c.podsInformer.HasSynced, c.servicesInformer.HasSynced, c.modelServingsInformer.HasSynced)
+
+	mi := createStandardModelServing("test-mi", 1, 1)
+	_, err = kthenaClient.WorkloadV1alpha1().ModelServings("default").Create(ctx, mi, metav1.CreateOptions{})
+	require.NoError(t, err)
+	setSuspend := func(suspend bool) {
+		current, err := kthenaClient.WorkloadV1alpha1().ModelServings("default").Get(ctx, "test-mi", metav1.GetOptions{})
+		require.NoError(t, err)
+		current.Spec.Suspend = suspend
+		_, err = kthenaClient.WorkloadV1alpha1().ModelServings("default").Update(ctx, current, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		require.True(t, waitForObjectInCache(t, 2*time.Second, func() bool {
+			current, err := c.modelServingLister.ModelServings("default").Get("test-mi")
+			return err == nil && current.Spec.Suspend == suspend
+		}))
+	}
+	podsInCache := func(count int) bool {
+		return waitForObjectInCache(t, 2*time.Second, func() bool {
+			pods, _ := c.podsLister.List(labels.Everything())
+			return len(pods) == count
+		})
+	}
+	require.True(t, waitForObjectInCache(t, 2*time.Second, func() bool {
+		_, err := c.modelServingLister.ModelServings("default").Get("test-mi")
+		return err == nil
+	}))
+	require.NoError(t, c.syncModelServing(ctx, "default/test-mi"))
+	require.True(t, podsInCache(utils.ExpectedPodNum(mi)))
+
+	// Suspending deletes the pods and keeps the ServingGroup
+	setSuspend(true)
+	require.NoError(t, c.syncModelServing(ctx, "default/test-mi"))
+	require.True(t, podsInCache(0))
+	assert.Equal(t, datastore.ServingGroupSuspended, c.store.GetServingGroupStatus(utils.GetNamespaceName(mi), "test-mi-0"))
+	got, err := kthenaClient.WorkloadV1alpha1().ModelServings("default").Get(ctx, "test-mi", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, string(workloadv1alpha1.ModelServingSuspended)))
+	assert.Equal(t, int32(0), got.Status.Replicas)
+	assert.Equal(t, workloadv1alpha1.ModelServingNameLabelKey+"=test-mi", got.Status.LabelSelector)
+	assert.Equal(t, int32(1), *got.Spec.Replicas)
+
+	// Resuming recreates the pods of the same ServingGroup
+	setSuspend(false)
+	require.NoError(t, c.syncModelServing(ctx, "default/test-mi"))
+	pods, err := kubeClient.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, pods.Items, utils.ExpectedPodNum(mi))
+	assert.Equal(t, datastore.ServingGroupCreating, c.store.GetServingGroupStatus(utils.GetNamespaceName(mi), "test-mi-0"))
+	got, err = kthenaClient.WorkloadV1alpha1().ModelServings("default").Get(ctx, "test-mi", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, meta.IsStatusConditionFalse(got.Status.Conditions, string(workloadv1alpha1.ModelServingSuspended)))
+	assert.Equal(t, int32(1), got.Status.Replicas)
+}
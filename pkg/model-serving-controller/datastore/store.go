@@ -22,6 +22,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/types"
 
@@ -45,6 +46,18 @@ type Store interface {
 	AddRunningPodToServingGroup(modelServingName types.NamespacedName, groupName, pod, revision, roleName, roleID string)
 	DeleteRunningPodFromServingGroup(modelServingName types.NamespacedName, groupName string, pod string)
 	UpdateServingGroupStatus(modelServingName types.NamespacedName, groupName string, Status ServingGroupStatus) error
+	UpdateServingGroupRevision(modelServingName types.NamespacedName, groupName, revision string) error
+	AddPodGraceDeadline(modelServingName types.NamespacedName, pod string, deadline time.Time) bool
+	GetPodGraceDeadlines(modelServingName types.NamespacedName) map[string]time.Time
+	DeletePodGraceDeadline(modelServingName types.NamespacedName, pod string)
+	RecordServingGroupFailure(modelServingName types.NamespacedName, groupName string, at time.Time) (RecoveryBackoff, bool)
+	MarkServingGroupRecreated(modelServingName types.NamespacedName, groupName string)
+	GetRecoveryBackoffs(modelServingName types.NamespacedName) map[string]RecoveryBackoff
+	DeleteRecoveryBackoff(modelServingName types.NamespacedName, groupName string)
+	ResetRecoveryBackoffs(modelServingName types.NamespacedName, token string) bool
+	RecordServingGroupPreemption(modelServingName types.NamespacedName, groupName, message string) bool
+	GetServingGroupPreemptions(modelServingName types.NamespacedName) map[string]string
+	DeleteServingGroupPreemption(modelServingName types.NamespacedName, groupName string)
 }
 
 type store struct {
@@ -53,6 +66,17 @@ type store struct {
 	// ServingGroup is a map of modelServing names to their ServingGroups
 	// modelServing -> group name-> ServingGroup
 	servingGroup map[types.NamespacedName]map[string]*ServingGroup
+	// graceDeadlines holds the failed pods waiting for their restart grace period
+	// modelServing -> pod name -> time the pod is deleted unless it has recovered
+	graceDeadlines map[types.NamespacedName]map[string]time.Time
+	// recoveryBackoffs holds the failures in a row of the ServingGroups, which delay their recreation
+	// modelServing -> group name -> RecoveryBackoff
+	recoveryBackoffs map[types.NamespacedName]map[string]*RecoveryBackoff
+	// backoffResets holds the last value of the reset annotation handled for each modelServing
+	backoffResets map[types.NamespacedName]string
+	// preemptions holds the ServingGroups whose pods were preempted by the scheduler, until they are running again
+	// modelServing -> group name -> reason given by the scheduler
+	preemptions map[types.NamespacedName]map[string]string
 }
 
 type ServingGroup struct {
@@ -69,6 +93,17 @@ type Role struct {
 	Status   RoleStatus
 }
 
+// RecoveryBackoff tracks the failures in a row of a ServingGroup.
+type RecoveryBackoff struct {
+	// Restarts is the number of failures in a row.
+	Restarts int32
+	// LastFailure is the time of the last failure, the backoff delay starts from it.
+	LastFailure time.Time
+	// Recreated is set once the ServingGroup or its role has been recreated after the last failure,
+	// so that the other pods failing with it are not counted as new failures.
+	Recreated bool
+}
+
 type ServingGroupStatus string
 
 const (
@@ -77,6 +112,8 @@ const (
 	ServingGroupDeleting ServingGroupStatus = "Deleting"
 	ServingGroupScaling  ServingGroupStatus = "Scaling"
 	ServingGroupNotFound ServingGroupStatus = "NotFound"
+	// ServingGroupSuspended is a ServingGroup whose pods are deleted while its ModelServing is suspended
+	ServingGroupSuspended ServingGroupStatus = "Suspended"
 )
 
 type RoleStatus string
@@ -91,7 +128,11 @@ var ErrServingGroupNotFound = errors.New("serving group not found")
 
 func New() Store {
 	return &store{
-		servingGroup: make(map[types.NamespacedName]map[string]*ServingGroup),
+		servingGroup:     make(map[types.NamespacedName]map[string]*ServingGroup),
+		graceDeadlines:   make(map[types.NamespacedName]map[string]time.Time),
+		recoveryBackoffs: make(map[types.NamespacedName]map[string]*RecoveryBackoff),
+		backoffResets:    make(map[types.NamespacedName]string),
+		preemptions:      make(map[types.NamespacedName]map[string]string),
 	}
 }
 
@@ -240,6 +281,10 @@ func (s *store) DeleteModelServing(modelServingName types.NamespacedName) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	delete(s.servingGroup, modelServingName)
+	delete(s.graceDeadlines, modelServingName)
+	delete(s.recoveryBackoffs, modelServingName)
+	delete(s.backoffResets, modelServingName)
+	delete(s.preemptions, modelServingName)
 }
 
 // DeleteServingGroup delete ServingGroup in map
@@ -394,8 +439,193 @@ func (s *store) UpdateServingGroupStatus(modelServingName types.NamespacedName,
 	if group, ok := groups[groupName]; ok {
 		group.Status = status
 		groups[groupName] = group
+		if status == ServingGroupRunning {
+			// The failures are no longer in a row once the ServingGroup is running
+			delete(s.recoveryBackoffs[modelServingName], groupName)
+			deleteGroupEntry(s.preemptions, modelServingName, groupName)
+		}
 	} else {
 		return fmt.Errorf("failed to find ServingGroup %s in modelServing %s", groupName, modelServingName.Namespace+"/"+modelServingName.Name)
 	}
 	return nil
 }
+
+// UpdateServingGroupRevision updates the revision of one ServingGroup and of its roles, once its pods are updated in place
+func (s *store) UpdateServingGroupRevision(modelServingName types.NamespacedName, groupName, revision string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	group, ok := s.servingGroup[modelServingName][groupName]
+	if !ok {
+		return fmt.Errorf("failed to find ServingGroup %s in modelServing %s", groupName, modelServingName.Namespace+"/"+modelServingName.Name)
+	}
+	group.Revision = revision
+	for _, roles := range group.roles {
+		for _, role := range roles {
+			role.Revision = revision
+		}
+	}
+	return nil
+}
+
+// AddPodGraceDeadline records the end of the grace period of a failed pod. It returns false, without changing it,
+// if the pod is already in its grace period.
+func (s *store) AddPodGraceDeadline(modelServingName types.NamespacedName, pod string, deadline time.Time) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deadlines, ok := s.graceDeadlines[modelServingName]
+	if !ok {
+		deadlines = make(map[string]time.Time)
+		s.graceDeadlines[modelServingName] = deadlines
+	}
+	if _, exists := deadlines[pod]; exists {
+		return false
+	}
+	deadlines[pod] = deadline
+	return true
+}
+
+// GetPodGraceDeadlines returns a copy of the grace period deadlines of the failed pods of a modelServing
+func (s *store) GetPodGraceDeadlines(modelServingName types.NamespacedName) map[string]time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	deadlines := make(map[string]time.Time, len(s.graceDeadlines[modelServingName]))
+	for pod, deadline := range s.graceDeadlines[modelServingName] {
+		deadlines[pod] = deadline
+	}
+	return deadlines
+}
+
+// DeletePodGraceDeadline ends the grace period of a pod
+func (s *store) DeletePodGraceDeadline(modelServingName types.NamespacedName, pod string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deleteGroupEntry(s.graceDeadlines, modelServingName, pod)
+}
+
+// RecordServingGroupFailure records a failure of a ServingGroup at the given time and returns its backoff.
+// It returns false, without counting it, if the ServingGroup has not been recreated since its last failure.
+func (s *store) RecordServingGroupFailure(modelServingName types.NamespacedName, groupName string, at time.Time) (RecoveryBackoff, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.recoveryBackoffs == nil {
+		s.recoveryBackoffs = make(map[types.NamespacedName]map[string]*RecoveryBackoff)
+	}
+	backoffs, ok := s.recoveryBackoffs[modelServingName]
+	if !ok {
+		backoffs = make(map[string]*RecoveryBackoff)
+		s.recoveryBackoffs[modelServingName] = backoffs
+	}
+	backoff, ok := backoffs[groupName]
+	if !ok {
+		backoff = &RecoveryBackoff{}
+		backoffs[groupName] = backoff
+	} else if !backoff.Recreated {
+		return *backoff, false
+	}
+	backoff.Restarts++
+	backoff.LastFailure = at
+	backoff.Recreated = false
+	return *backoff, true
+}
+
+// MarkServingGroupRecreated records that a failed ServingGroup, or its failed role, has been recreated
+func (s *store) MarkServingGroupRecreated(modelServingName types.NamespacedName, groupName string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if backoff, ok := s.recoveryBackoffs[modelServingName][groupName]; ok {
+		backoff.Recreated = true
+	}
+}
+
+// GetRecoveryBackoffs returns a copy of the backoffs of the failed ServingGroups of a modelServing
+func (s *store) GetRecoveryBackoffs(modelServingName types.NamespacedName) map[string]RecoveryBackoff {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	backoffs := make(map[string]RecoveryBackoff, len(s.recoveryBackoffs[modelServingName]))
+	for groupName, backoff := range s.recoveryBackoffs[modelServingName] {
+		backoffs[groupName] = *backoff
+	}
+	return backoffs
+}
+
+// DeleteRecoveryBackoff forgets the failures of a ServingGroup
+func (s *store) DeleteRecoveryBackoff(modelServingName types.NamespacedName, groupName string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deleteGroupEntry(s.recoveryBackoffs, modelServingName, groupName)
+}
+
+// ResetRecoveryBackoffs forgets the failures of all the ServingGroups of a modelServing when the token differs from
+// the last one. It returns true if failures were forgotten.
+func (s *store) ResetRecoveryBackoffs(modelServingName types.NamespacedName, token string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.backoffResets[modelServingName] == token {
+		return false
+	}
+	if s.backoffResets == nil {
+		s.backoffResets = make(map[types.NamespacedName]string)
+	}
+	s.backoffResets[modelServingName] = token
+	_, reset := s.recoveryBackoffs[modelServingName]
+	delete(s.recoveryBackoffs, modelServingName)
+	return reset
+}
+
+// RecordServingGroupPreemption records that pods of a ServingGroup were preempted by the scheduler with the given
+// reason. It returns false if the preemption of the ServingGroup was already recorded.
+func (s *store) RecordServingGroupPreemption(modelServingName types.NamespacedName, groupName, message string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.preemptions == nil {
+		s.preemptions = make(map[types.NamespacedName]map[string]string)
+	}
+	preemptions, ok := s.preemptions[modelServingName]
+	if !ok {
+		preemptions = make(map[string]string)
+		s.preemptions[modelServingName] = preemptions
+	}
+	_, recorded := preemptions[groupName]
+	preemptions[groupName] = message
+	return !recorded
+}
+
+// GetServingGroupPreemptions returns a copy of the reasons of the preemptions of the ServingGroups of a modelServing
+func (s *store) GetServingGroupPreemptions(modelServingName types.NamespacedName) map[string]string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	preemptions := make(map[string]string, len(s.preemptions[modelServingName]))
+	for groupName, message := range s.preemptions[modelServingName] {
+		preemptions[groupName] = message
+	}
+	return preemptions
+}
+
+// DeleteServingGroupPreemption forgets the preemption of a ServingGroup
+func (s *store) DeleteServingGroupPreemption(modelServingName types.NamespacedName, groupName string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deleteGroupEntry(s.preemptions, modelServingName, groupName)
+}
+
+// deleteGroupEntry deletes the entry of a ServingGroup or pod, and the map of the modelServing once empty.
+func deleteGroupEntry[V any](entries map[types.NamespacedName]map[string]V, modelServingName types.NamespacedName, name string) {
+	if groupEntries, ok := entries[modelServingName]; ok {
+		delete(groupEntries, name)
+		if len(groupEntries) == 0 {
+			delete(entries, modelServingName)
+		}
+	}
+}
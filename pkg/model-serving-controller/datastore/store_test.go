@@ -19,6 +19,7 @@ package datastore
 import (
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/types"
@@ -301,3 +302,80 @@ func TestAddRole(t *testing.T) {
 	role4 := s.servingGroup[key]["group0"].roles["prefill"]["prefill-0"]
 	assert.Equal(t, "revision4", role4.Revision, "role should be overwritten")
 }
+
+func TestPodGraceDeadlines(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "model"}
+	s := New()
+	deadline := time.Unix(1700000000, 0)
+
+	assert.True(t, s.AddPodGraceDeadline(key, "pod-0", deadline))
+	// The grace period of a pod is not extended by its next failures
+	assert.False(t, s.AddPodGraceDeadline(key, "pod-0", deadline.Add(time.Minute)))
+	assert.True(t, s.AddPodGraceDeadline(key, "pod-1", deadline.Add(time.Minute)))
+	assert.Equal(t, map[string]time.Time{"pod-0": deadline, "pod-1": deadline.Add(time.Minute)}, s.GetPodGraceDeadlines(key))
+
+	s.DeletePodGraceDeadline(key, "pod-0")
+	assert.Equal(t, map[string]time.Time{"pod-1": deadline.Add(time.Minute)}, s.GetPodGraceDeadlines(key))
+
+	s.DeleteModelServing(key)
+	assert.Empty(t, s.GetPodGraceDeadlines(key))
+}
+
+func TestRecoveryBackoffs(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "model"}
+	s := New()
+	now := time.Unix(1700000000, 0)
+	s.AddServingGroup(key, 0, "revision")
+
+	backoff, counted := s.RecordServingGroupFailure(key, "model-0", now)
+	assert.True(t, counted)
+	assert.Equal(t, RecoveryBackoff{Restarts: 1, LastFailure: now}, backoff)
+	// The other pods failing before the ServingGroup is recreated are the same failure
+	_, counted = s.RecordServingGroupFailure(key, "model-0", now.Add(time.Second))
+	assert.False(t, counted)
+
+	s.MarkServingGroupRecreated(key, "model-0")
+	backoff, counted = s.RecordServingGroupFailure(key, "model-0", now.Add(time.Minute))
+	assert.True(t, counted)
+	assert.Equal(t, RecoveryBackoff{Restarts: 2, LastFailure: now.Add(time.Minute)}, backoff)
+	assert.Equal(t, map[string]RecoveryBackoff{"model-0": backoff}, s.GetRecoveryBackoffs(key))
+
+	// The failures are forgotten once the ServingGroup is running
+	assert.NoError(t, s.UpdateServingGroupStatus(key, "model-0", ServingGroupRunning))
+	assert.Empty(t, s.GetRecoveryBackoffs(key))
+
+	// The backoffs are reset once per token
+	s.RecordServingGroupFailure(key, "model-0", now)
+	assert.True(t, s.ResetRecoveryBackoffs(key, "1"))
+	assert.Empty(t, s.GetRecoveryBackoffs(key))
+	s.RecordServingGroupFailure(key, "model-0", now)
+	assert.False(t, s.ResetRecoveryBackoffs(key, "1"))
+	assert.Len(t, s.GetRecoveryBackoffs(key), 1)
+
+	s.DeleteRecoveryBackoff(key, "model-0")
+	assert.Empty(t, s.GetRecoveryBackoffs(key))
+}
+
+func TestServingGroupPreemptions(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "model"}
+	s := New()
+	s.AddServingGroup(key, 0, "revision")
+	s.AddServingGroup(key, 1, "revision")
+
+	assert.True(t, s.RecordServingGroupPreemption(key, "model-0", "preempted"))
+	// The other pods of the ServingGroup are the same preemption
+	assert.False(t, s.RecordServingGroupPreemption(key, "model-0", "evicted"))
+	assert.True(t, s.RecordServingGroupPreemption(key, "model-1", "preempted"))
+	assert.Equal(t, map[string]string{"model-0": "evicted", "model-1": "preempted"}, s.GetServingGroupPreemptions(key))
+
+	// The preemption is forgotten once the ServingGroup is running
+	assert.NoError(t, s.UpdateServingGroupStatus(key, "model-0", ServingGroupRunning))
+	assert.Equal(t, map[string]string{"model-1": "preempted"}, s.GetServingGroupPreemptions(key))
+
+	s.DeleteServingGroupPreemption(key, "model-1")
+	assert.Empty(t, s.GetServingGroupPreemptions(key))
+
+	s.RecordServingGroupPreemption(key, "model-1", "preempted")
+	s.DeleteModelServing(key)
+	assert.Empty(t, s.GetServingGroupPreemptions(key))
+}
@@ -0,0 +1,232 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gangscheduling
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+const (
+	// CoschedulingPodGroupLabelKey is the pod label key of the coscheduling PodGroup the pod belongs to.
+	CoschedulingPodGroupLabelKey = "scheduling.x-k8s.io/pod-group"
+)
+
+var coschedulingPodGroupGVR = schema.GroupVersionResource{
+	Group:    "scheduling.x-k8s.io",
+	Version:  "v1alpha1",
+	Resource: "podgroups",
+}
+
+// coschedulingPodGroupSpec mirrors the spec of the scheduler-plugins PodGroup API.
+type coschedulingPodGroupSpec struct {
+	MinMember    int32               `json:"minMember"`
+	MinResources corev1.ResourceList `json:"minResources,omitempty"`
+}
+
+// coschedulingBackend manages the PodGroups of the scheduler-plugins coscheduling plugin for gang scheduling
+type coschedulingBackend struct {
+	dynamicClient dynamic.Interface
+}
+
+func newCoschedulingBackend(dynamicClient dynamic.Interface) *coschedulingBackend {
+	return &coschedulingBackend{
+		dynamicClient: dynamicClient,
+	}
+}
+
+func (cs *coschedulingBackend) PodSchedulerName() string {
+	return workloadv1alpha1.SchedulerNameCoscheduling
+}
+
+// ManagePodGroups manages the coscheduling PodGroup of each ServingGroup
+func (cs *coschedulingBackend) ManagePodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
+	expectedReplicas := servingGroupCount(mi)
+
+	existingPodGroups, err := cs.getExistingPodGroups(ctx, mi)
+	if err != nil {
+		return fmt.Errorf("failed to get existing coscheduling PodGroups: %v", err)
+	}
+
+	minMember, _, minResources := calculateRequirements(mi)
+	spec := coschedulingPodGroupSpec{
+		MinMember:    int32(minMember),
+		MinResources: minResources,
+	}
+	for i := 0; i < expectedReplicas; i++ {
+		podGroupName := generatePodGroupName(mi.Name, i)
+
+		if existing, exists := existingPodGroups[podGroupName]; exists {
+			if err := cs.updatePodGroupIfNeeded(ctx, existing, spec); err != nil {
+				return fmt.Errorf("failed to update coscheduling PodGroup %s: %v", podGroupName, err)
+			}
+		} else {
+			if err := cs.createPodGroup(ctx, mi, podGroupName, spec); err != nil {
+				return fmt.Errorf("failed to create coscheduling PodGroup %s: %v", podGroupName, err)
+			}
+		}
+	}
+
+	for podGroupName := range existingPodGroups {
+		if isPodGroupNeeded(mi, podGroupName, expectedReplicas) {
+			continue
+		}
+		if err := cs.deletePodGroup(ctx, mi.Namespace, podGroupName); err != nil {
+			return fmt.Errorf("failed to delete excess coscheduling PodGroup %s: %v", podGroupName, err)
+		}
+		klog.V(2).Infof("Deleted excess coscheduling PodGroup %s", podGroupName)
+	}
+	return nil
+}
+
+func (cs *coschedulingBackend) createPodGroup(ctx context.Context, mi *workloadv1alpha1.ModelServing, podGroupName string, spec coschedulingPodGroupSpec) error {
+	podGroup := &unstructured.Unstructured{}
+	podGroup.SetAPIVersion(coschedulingPodGroupGVR.GroupVersion().String())
+	podGroup.SetKind("PodGroup")
+	podGroup.SetName(podGroupName)
+	podGroup.SetNamespace(mi.Namespace)
+	podGroup.SetLabels(podGroupLabels(mi, podGroupName))
+	podGroup.SetOwnerReferences(buildOwnerReference(mi))
+	if err := setSpec(podGroup, &spec); err != nil {
+		return err
+	}
+
+	_, err := cs.dynamicClient.Resource(coschedulingPodGroupGVR).Namespace(mi.Namespace).Create(ctx, podGroup, metav1.CreateOptions{FieldManager: utils.FieldManager})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	klog.V(2).Infof("Created coscheduling PodGroup %s for group-level gang scheduling", podGroupName)
+	return nil
+}
+
+// updatePodGroupIfNeeded updates the spec of a PodGroup if needed. The PodGroup is read again when the scheduler
+// has updated it in the meantime.
+func (cs *coschedulingBackend) updatePodGroupIfNeeded(ctx context.Context, existing *unstructured.Unstructured, spec coschedulingPodGroupSpec) error {
+	return utils.RetryOnConflict(existing, func() (*unstructured.Unstructured, error) {
+		return cs.dynamicClient.Resource(coschedulingPodGroupGVR).Namespace(existing.GetNamespace()).Get(ctx, existing.GetName(), metav1.GetOptions{})
+	}, func(latest *unstructured.Unstructured) error {
+		return cs.updatePodGroup(ctx, latest, spec)
+	})
+}
+
+func (cs *coschedulingBackend) updatePodGroup(ctx context.Context, existing *unstructured.Unstructured, spec coschedulingPodGroupSpec) error {
+	var current coschedulingPodGroupSpec
+	if err := getSpec(existing, &current); err != nil {
+		return err
+	}
+	if current.MinMember == spec.MinMember && equalResourceList(&current.MinResources, &spec.MinResources) {
+		return nil
+	}
+
+	updated := existing.DeepCopy()
+	if err := setSpec(updated, &spec); err != nil {
+		return err
+	}
+	if _, err := cs.dynamicClient.Resource(coschedulingPodGroupGVR).Namespace(existing.GetNamespace()).Update(ctx, updated, metav1.UpdateOptions{FieldManager: utils.FieldManager}); err != nil {
+		return err
+	}
+	klog.V(2).Infof("Updated coscheduling PodGroup %s for group-level gang scheduling", existing.GetName())
+	return nil
+}
+
+func (cs *coschedulingBackend) getExistingPodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) (map[string]*unstructured.Unstructured, error) {
+	return listGangObjects(ctx, cs.dynamicClient, coschedulingPodGroupGVR, mi)
+}
+
+func (cs *coschedulingBackend) deletePodGroup(ctx context.Context, namespace, name string) error {
+	err := cs.dynamicClient.Resource(coschedulingPodGroupGVR).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// CleanupPodGroups deletes all the coscheduling PodGroups of a ModelServing
+func (cs *coschedulingBackend) CleanupPodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
+	existingPodGroups, err := cs.getExistingPodGroups(ctx, mi)
+	if err != nil {
+		return fmt.Errorf("failed to get existing coscheduling PodGroups for cleanup: %v", err)
+	}
+	for podGroupName := range existingPodGroups {
+		if err := cs.deletePodGroup(ctx, mi.Namespace, podGroupName); err != nil {
+			return fmt.Errorf("failed to delete coscheduling PodGroup %s: %v", podGroupName, err)
+		}
+		klog.V(2).Infof("Deleted coscheduling PodGroup %s (gang scheduling disabled)", podGroupName)
+	}
+	return nil
+}
+
+// AnnotatePod labels a pod with the coscheduling PodGroup of its ServingGroup
+func (cs *coschedulingBackend) AnnotatePod(pod *corev1.Pod, mi *workloadv1alpha1.ModelServing, groupName, taskName string) {
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	pod.Labels[CoschedulingPodGroupLabelKey] = groupName
+}
+
+// listGangObjects lists the gang objects of a ModelServing, keyed by name
+func listGangObjects(ctx context.Context, dynamicClient dynamic.Interface, gvr schema.GroupVersionResource, mi *workloadv1alpha1.ModelServing) (map[string]*unstructured.Unstructured, error) {
+	selector := labels.SelectorFromSet(map[string]string{
+		workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
+	})
+	list, err := dynamicClient.Resource(gvr).Namespace(mi.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*unstructured.Unstructured, len(list.Items))
+	for i := range list.Items {
+		result[list.Items[i].GetName()] = &list.Items[i]
+	}
+	return result, nil
+}
+
+// setSpec sets the spec of an unstructured object from a typed spec
+func setSpec(obj *unstructured.Unstructured, spec interface{}) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+	if err != nil {
+		return fmt.Errorf("failed to convert spec of %s: %v", obj.GetName(), err)
+	}
+	return unstructured.SetNestedMap(obj.Object, content, "spec")
+}
+
+// getSpec reads the spec of an unstructured object into a typed spec
+func getSpec(obj *unstructured.Unstructured, spec interface{}) error {
+	content, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return fmt.Errorf("failed to read spec of %s: %v", obj.GetName(), err)
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, spec); err != nil {
+		return fmt.Errorf("failed to convert spec of %s: %v", obj.GetName(), err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gangscheduling
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+func newFakeDynamicClient() *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		coschedulingPodGroupGVR: "PodGroupList",
+		kueueWorkloadGVR:        "WorkloadList",
+		kueueResourceFlavorGVR:  "ResourceFlavorList",
+	})
+}
+
+func newGangModelServing(schedulerName string, replicas int32) *workloadv1alpha1.ModelServing {
+	return &workloadv1alpha1.ModelServing{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-model",
+			Namespace: "default",
+			Labels:    map[string]string{KueueQueueNameLabelKey: "user-queue"},
+		},
+		Spec: workloadv1alpha1.ModelServingSpec{
+			Replicas:      ptr.To(replicas),
+			SchedulerName: schedulerName,
+			Template: workloadv1alpha1.ServingGroup{
+				GangPolicy: &workloadv1alpha1.GangPolicy{},
+				Roles: []workloadv1alpha1.Role{
+					{
+						Name:           "decode",
+						Replicas:       ptr.To[int32](2),
+						WorkerReplicas: 1,
+						EntryTemplate: workloadv1alpha1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									Name: "engine",
+									Resources: corev1.ResourceRequirements{
+										Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+									},
+								}},
+							},
+						},
+						WorkerTemplate: &workloadv1alpha1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									Name: "engine",
+									Resources: corev1.ResourceRequirements{
+										Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+									},
+								}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCoschedulingManagePodGroups(t *testing.T) {
+	ctx := context.Background()
+	dynamicClient := newFakeDynamicClient()
+	backend := newCoschedulingBackend(dynamicClient)
+	mi := newGangModelServing(workloadv1alpha1.SchedulerNameCoscheduling, 2)
+
+	require.NoError(t, backend.ManagePodGroups(ctx, mi))
+	podGroups, err := backend.getExistingPodGroups(ctx, mi)
+	require.NoError(t, err)
+	require.Len(t, podGroups, 2)
+
+	var spec coschedulingPodGroupSpec
+	require.NoError(t, getSpec(podGroups["test-model-0"], &spec))
+	assert.Equal(t, int32(4), spec.MinMember)
+	assert.True(t, resource.MustParse("6").Equal(spec.MinResources[corev1.ResourceCPU]))
+	assert.Equal(t, "test-model-0", podGroups["test-model-0"].GetLabels()[workloadv1alpha1.GroupNameLabelKey])
+
+	// Scale in and grow the roles
+	mi.Spec.Replicas = ptr.To[int32](1)
+	mi.Spec.Template.Roles[0].Replicas = ptr.To[int32](3)
+	require.NoError(t, backend.ManagePodGroups(ctx, mi))
+	podGroups, err = backend.getExistingPodGroups(ctx, mi)
+	require.NoError(t, err)
+	require.Len(t, podGroups, 1)
+	require.NoError(t, getSpec(podGroups["test-model-0"], &spec))
+	assert.Equal(t, int32(6), spec.MinMember)
+
+	require.NoError(t, backend.CleanupPodGroups(ctx, mi))
+	podGroups, err = backend.getExistingPodGroups(ctx, mi)
+	require.NoError(t, err)
+	assert.Empty(t, podGroups)
+}
+
+func TestManagerAnnotatePodWithPodGroup(t *testing.T) {
+	manager := NewManager(nil, nil, nil, newFakeDynamicClient())
+
+	tests := []struct {
+		name            string
+		schedulerName   string
+		gang            bool
+		wantScheduler   string
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+		wantGates       int
+	}{
+		{
+			name:          "volcano",
+			schedulerName: workloadv1alpha1.SchedulerNameVolcano,
+			gang:          true,
+			wantScheduler: "volcano",
+			wantAnnotations: map[string]string{
+				"scheduling.k8s.io/group-name": "test-model-0",
+				"volcano.sh/task-spec":         "decode-0",
+			},
+		},
+		{
+			name:          "coscheduling",
+			schedulerName: workloadv1alpha1.SchedulerNameCoscheduling,
+			gang:          true,
+			wantScheduler: "scheduler-plugins-scheduler",
+			wantLabels:    map[string]string{CoschedulingPodGroupLabelKey: "test-model-0"},
+		},
+		{
+			name:          "kueue",
+			schedulerName: workloadv1alpha1.SchedulerNameKueue,
+			gang:          true,
+			wantScheduler: corev1.DefaultSchedulerName,
+			wantGates:     1,
+		},
+		{
+			name:          "kueue without gang scheduling",
+			schedulerName: workloadv1alpha1.SchedulerNameKueue,
+			wantScheduler: corev1.DefaultSchedulerName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mi := newGangModelServing(tt.schedulerName, 1)
+			if !tt.gang {
+				mi.Spec.Template.GangPolicy = nil
+			}
+			pod := &corev1.Pod{Spec: corev1.PodSpec{SchedulerName: tt.schedulerName}}
+			manager.AnnotatePodWithPodGroup(pod, mi, 2, "test-model-0", "decode-0")
+
+			assert.Equal(t, tt.wantScheduler, pod.Spec.SchedulerName)
+			for k, v := range tt.wantLabels {
+				assert.Equal(t, v, pod.Labels[k])
+			}
+			for k, v := range tt.wantAnnotations {
+				assert.Equal(t, v, pod.Annotations[k])
+			}
+			assert.Len(t, pod.Spec.SchedulingGates, tt.wantGates)
+		})
+	}
+}
+
+func TestManagerUnsupportedScheduler(t *testing.T) {
+	manager := NewManager(nil, nil, nil, nil)
+	mi := newGangModelServing("default-scheduler", 1)
+	assert.Error(t, manager.ManagePodGroups(context.Background(), mi))
+	assert.NoError(t, manager.CleanupPodGroups(context.Background(), mi))
+
+	mi.Spec.Template.GangPolicy = nil
+	assert.NoError(t, manager.ManagePodGroups(context.Background(), mi))
+}
@@ -0,0 +1,536 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gangscheduling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+const (
+	// KueueQueueNameLabelKey is the ModelServing label key of the Kueue LocalQueue the ServingGroups are submitted to.
+	KueueQueueNameLabelKey = "kueue.x-k8s.io/queue-name"
+	// KueueAdmissionGateName is the scheduling gate which holds the pods of a ServingGroup
+	// until the Workload of the ServingGroup is admitted by Kueue.
+	KueueAdmissionGateName = "modelserving.volcano.sh/kueue-admission"
+
+	// kueueSpecHashAnnotationKey records the hash of the spec a Workload was last synced with.
+	kueueSpecHashAnnotationKey = "modelserving.volcano.sh/spec-hash"
+	// kueueMaxPodSets is the maximum number of pod sets of a Workload.
+	kueueMaxPodSets = 8
+	// kueueResyncPeriod bounds how long a pod created while its Workload was being admitted stays gated.
+	kueueResyncPeriod = time.Minute
+)
+
+var kueueWorkloadGVR = schema.GroupVersionResource{
+	Group:    "kueue.x-k8s.io",
+	Version:  "v1beta1",
+	Resource: "workloads",
+}
+
+var kueueResourceFlavorGVR = schema.GroupVersionResource{
+	Group:    "kueue.x-k8s.io",
+	Version:  "v1beta1",
+	Resource: "resourceflavors",
+}
+
+// kueuePodSet mirrors a pod set of the Kueue Workload API.
+type kueuePodSet struct {
+	Name     string                 `json:"name"`
+	Count    int32                  `json:"count"`
+	Template corev1.PodTemplateSpec `json:"template"`
+}
+
+// kueueWorkloadSpec mirrors the spec of the Kueue Workload API.
+type kueueWorkloadSpec struct {
+	QueueName string        `json:"queueName,omitempty"`
+	PodSets   []kueuePodSet `json:"podSets"`
+}
+
+// kueuePodSetAssignment mirrors the flavors assigned to a pod set in the admission of a Kueue Workload.
+type kueuePodSetAssignment struct {
+	Name    string                         `json:"name"`
+	Flavors map[corev1.ResourceName]string `json:"flavors,omitempty"`
+}
+
+// kueueAdmission mirrors the admission in the status of the Kueue Workload API.
+type kueueAdmission struct {
+	PodSetAssignments []kueuePodSetAssignment `json:"podSetAssignments"`
+}
+
+// kueueResourceFlavorSpec mirrors the spec of the Kueue ResourceFlavor API, with what places the pods of a flavor.
+type kueueResourceFlavorSpec struct {
+	NodeLabels  map[string]string   `json:"nodeLabels,omitempty"`
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// kueueBackend admits each ServingGroup as a Kueue Workload. The pods of a ServingGroup are created
+// with a scheduling gate, which is removed once Kueue admits the Workload, and are bound by the default scheduler.
+type kueueBackend struct {
+	kubeClient    kubernetes.Interface
+	podLister     listerv1.PodLister
+	dynamicClient dynamic.Interface
+
+	// The Workloads of ModelServings are watched to release the pods of admitted ServingGroups.
+	// The informer is started on first use, so that Kueue only needs to be installed when it is used.
+	informerOnce     sync.Once
+	informerFactory  dynamicinformer.DynamicSharedInformerFactory
+	workloadInformer informers.GenericInformer
+}
+
+func newKueueBackend(kubeClient kubernetes.Interface, podLister listerv1.PodLister, dynamicClient dynamic.Interface) *kueueBackend {
+	k := &kueueBackend{
+		kubeClient:    kubeClient,
+		podLister:     podLister,
+		dynamicClient: dynamicClient,
+	}
+	if dynamicClient == nil {
+		return k
+	}
+	k.informerFactory = dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, kueueResyncPeriod, metav1.NamespaceAll, func(opts *metav1.ListOptions) {
+		opts.LabelSelector = workloadv1alpha1.ModelServingNameLabelKey
+	})
+	k.workloadInformer = k.informerFactory.ForResource(kueueWorkloadGVR)
+	_, _ = k.workloadInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			k.onWorkload(obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			k.onWorkload(newObj)
+		},
+	})
+	return k
+}
+
+func (k *kueueBackend) PodSchedulerName() string {
+	return corev1.DefaultSchedulerName
+}
+
+// ManagePodGroups manages the Kueue Workload of each ServingGroup
+func (k *kueueBackend) ManagePodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
+	k.informerOnce.Do(func() {
+		if k.informerFactory != nil {
+			k.informerFactory.Start(ctx.Done())
+		}
+	})
+
+	expectedReplicas := servingGroupCount(mi)
+
+	existingWorkloads, err := listGangObjects(ctx, k.dynamicClient, kueueWorkloadGVR, mi)
+	if err != nil {
+		return fmt.Errorf("failed to get existing Kueue Workloads: %v", err)
+	}
+
+	spec, err := buildKueueWorkloadSpec(mi)
+	if err != nil {
+		return err
+	}
+	specHash, err := hashKueueWorkloadSpec(spec)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < expectedReplicas; i++ {
+		workloadName := generatePodGroupName(mi.Name, i)
+
+		if existing, exists := existingWorkloads[workloadName]; exists {
+			if existing.GetAnnotations()[kueueSpecHashAnnotationKey] == specHash {
+				continue
+			}
+			if err := k.updateWorkload(ctx, existing, spec, specHash); err != nil {
+				return fmt.Errorf("failed to update Kueue Workload %s: %v", workloadName, err)
+			}
+			continue
+		}
+		if err := k.createWorkload(ctx, mi, workloadName, spec, specHash); err != nil {
+			return fmt.Errorf("failed to create Kueue Workload %s: %v", workloadName, err)
+		}
+	}
+
+	for workloadName := range existingWorkloads {
+		if isPodGroupNeeded(mi, workloadName, expectedReplicas) {
+			continue
+		}
+		if err := k.deleteWorkload(ctx, mi.Namespace, workloadName); err != nil {
+			return fmt.Errorf("failed to delete excess Kueue Workload %s: %v", workloadName, err)
+		}
+		klog.V(2).Infof("Deleted excess Kueue Workload %s", workloadName)
+	}
+	return nil
+}
+
+func (k *kueueBackend) onWorkload(obj interface{}) {
+	workload, ok := obj.(*unstructured.Unstructured)
+	if !ok || !isKueueWorkloadAdmitted(workload) {
+		return
+	}
+	if err := k.releasePods(context.TODO(), workload); err != nil {
+		klog.Errorf("failed to release pods of admitted Kueue Workload %s/%s: %v", workload.GetNamespace(), workload.GetName(), err)
+	}
+}
+
+// releasePods removes the admission scheduling gate from the pods of the ServingGroup of an admitted Workload.
+// As Kueue does for the pods it manages, the node labels and tolerations of the flavors assigned to the pod set
+// of a pod are applied in the same update, so that the pods land on the nodes their quota was reserved on.
+func (k *kueueBackend) releasePods(ctx context.Context, workload *unstructured.Unstructured) error {
+	workloadLabels := workload.GetLabels()
+	selector := labels.SelectorFromSet(map[string]string{
+		workloadv1alpha1.ModelServingNameLabelKey: workloadLabels[workloadv1alpha1.ModelServingNameLabelKey],
+		workloadv1alpha1.GroupNameLabelKey:        workloadLabels[workloadv1alpha1.GroupNameLabelKey],
+	})
+	pods, err := k.podLister.Pods(workload.GetNamespace()).List(selector)
+	if err != nil {
+		return err
+	}
+
+	var placements map[string]kueueResourceFlavorSpec
+	for _, pod := range pods {
+		if !hasSchedulingGate(pod, KueueAdmissionGateName) {
+			continue
+		}
+		if placements == nil {
+			if placements, err = k.getPodSetPlacements(ctx, workload); err != nil {
+				return err
+			}
+		}
+		podSetName := kueuePodSetName(pod)
+		placement, ok := placements[podSetName]
+		if !ok {
+			return fmt.Errorf("pod set %s of pod %s is not assigned in the admission of Kueue Workload %s", podSetName, pod.Name, workload.GetName())
+		}
+		released := false
+		err := utils.RetryOnConflict(pod, func() (*corev1.Pod, error) {
+			return k.kubeClient.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		}, func(latest *corev1.Pod) error {
+			gates := make([]corev1.PodSchedulingGate, 0, len(latest.Spec.SchedulingGates))
+			for _, gate := range latest.Spec.SchedulingGates {
+				if gate.Name != KueueAdmissionGateName {
+					gates = append(gates, gate)
+				}
+			}
+			if len(gates) == len(latest.Spec.SchedulingGates) {
+				return nil
+			}
+			updated := latest.DeepCopy()
+			updated.Spec.SchedulingGates = gates
+			applyKueuePlacement(updated, placement)
+			_, err := k.kubeClient.CoreV1().Pods(pod.Namespace).Update(ctx, updated, metav1.UpdateOptions{FieldManager: utils.FieldManager})
+			released = err == nil
+			return err
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to remove scheduling gate of pod %s: %v", pod.Name, err)
+		}
+		if released {
+			klog.V(2).Infof("Released pod %s/%s admitted by Kueue Workload %s", pod.Namespace, pod.Name, workload.GetName())
+		}
+	}
+	return nil
+}
+
+// getPodSetPlacements returns the node labels and tolerations of the flavors assigned to each pod set of an admitted Workload
+func (k *kueueBackend) getPodSetPlacements(ctx context.Context, workload *unstructured.Unstructured) (map[string]kueueResourceFlavorSpec, error) {
+	if k.dynamicClient == nil {
+		return nil, fmt.Errorf("no dynamic client to get the flavors of Kueue Workload %s", workload.GetName())
+	}
+	content, _, err := unstructured.NestedMap(workload.Object, "status", "admission")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read admission of Kueue Workload %s: %v", workload.GetName(), err)
+	}
+	var admission kueueAdmission
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &admission); err != nil {
+		return nil, fmt.Errorf("failed to convert admission of Kueue Workload %s: %v", workload.GetName(), err)
+	}
+
+	flavors := make(map[string]kueueResourceFlavorSpec)
+	placements := make(map[string]kueueResourceFlavorSpec, len(admission.PodSetAssignments))
+	for _, assignment := range admission.PodSetAssignments {
+		placement := kueueResourceFlavorSpec{NodeLabels: make(map[string]string)}
+		for _, flavorName := range assignment.Flavors {
+			flavor, cached := flavors[flavorName]
+			if !cached {
+				obj, err := k.dynamicClient.Resource(kueueResourceFlavorGVR).Get(ctx, flavorName, metav1.GetOptions{})
+				if err != nil {
+					return nil, fmt.Errorf("failed to get Kueue ResourceFlavor %s: %v", flavorName, err)
+				}
+				if err := getSpec(obj, &flavor); err != nil {
+					return nil, err
+				}
+				flavors[flavorName] = flavor
+			}
+			for key, value := range flavor.NodeLabels {
+				placement.NodeLabels[key] = value
+			}
+			placement.Tolerations = appendMissingTolerations(placement.Tolerations, flavor.Tolerations)
+		}
+		placements[assignment.Name] = placement
+	}
+	return placements, nil
+}
+
+func (k *kueueBackend) createWorkload(ctx context.Context, mi *workloadv1alpha1.ModelServing, workloadName string, spec kueueWorkloadSpec, specHash string) error {
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion(kueueWorkloadGVR.GroupVersion().String())
+	workload.SetKind("Workload")
+	workload.SetName(workloadName)
+	workload.SetNamespace(mi.Namespace)
+	workload.SetLabels(podGroupLabels(mi, workloadName))
+	workload.SetAnnotations(map[string]string{
+		kueueSpecHashAnnotationKey: specHash,
+	})
+	workload.SetOwnerReferences(buildOwnerReference(mi))
+	if err := setSpec(workload, &spec); err != nil {
+		return err
+	}
+
+	_, err := k.dynamicClient.Resource(kueueWorkloadGVR).Namespace(mi.Namespace).Create(ctx, workload, metav1.CreateOptions{FieldManager: utils.FieldManager})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	klog.V(2).Infof("Created Kueue Workload %s for group-level gang scheduling", workloadName)
+	return nil
+}
+
+// updateWorkload updates the pod sets of a Workload in place. Kueue forbids changing the pod sets of a Workload
+// holding a quota reservation: as long as the resources to admit are the same, e.g. after an image update, the
+// reservation keeps covering the ServingGroup and only the recorded hash is updated. Otherwise the Workload is
+// deleted, to be recreated and admitted again in the next sync.
+func (k *kueueBackend) updateWorkload(ctx context.Context, existing *unstructured.Unstructured, spec kueueWorkloadSpec, specHash string) error {
+	updated := existing.DeepCopy()
+	if isKueueQuotaReserved(existing) {
+		var current kueueWorkloadSpec
+		if err := getSpec(existing, &current); err != nil {
+			return err
+		}
+		if !equality.Semantic.DeepEqual(kueueAdmissionSpec(current), kueueAdmissionSpec(spec)) {
+			if err := k.deleteWorkload(ctx, existing.GetNamespace(), existing.GetName()); err != nil {
+				return err
+			}
+			klog.V(2).Infof("Deleted Kueue Workload %s, the resources to admit have changed", existing.GetName())
+			return nil
+		}
+	} else if err := setSpec(updated, &spec); err != nil {
+		return err
+	}
+	annotations := updated.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[kueueSpecHashAnnotationKey] = specHash
+	updated.SetAnnotations(annotations)
+	if _, err := k.dynamicClient.Resource(kueueWorkloadGVR).Namespace(existing.GetNamespace()).Update(ctx, updated, metav1.UpdateOptions{FieldManager: utils.FieldManager}); err != nil {
+		return err
+	}
+	klog.V(2).Infof("Updated Kueue Workload %s", existing.GetName())
+	return nil
+}
+
+func (k *kueueBackend) deleteWorkload(ctx context.Context, namespace, name string) error {
+	err := k.dynamicClient.Resource(kueueWorkloadGVR).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// CleanupPodGroups deletes all the Kueue Workloads of a ModelServing
+func (k *kueueBackend) CleanupPodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
+	existingWorkloads, err := listGangObjects(ctx, k.dynamicClient, kueueWorkloadGVR, mi)
+	if err != nil {
+		return fmt.Errorf("failed to get existing Kueue Workloads for cleanup: %v", err)
+	}
+	for workloadName := range existingWorkloads {
+		if err := k.deleteWorkload(ctx, mi.Namespace, workloadName); err != nil {
+			return fmt.Errorf("failed to delete Kueue Workload %s: %v", workloadName, err)
+		}
+		klog.V(2).Infof("Deleted Kueue Workload %s (gang scheduling disabled)", workloadName)
+	}
+	return nil
+}
+
+// AnnotatePod holds a pod with the admission scheduling gate, unless its ServingGroup has already been admitted
+func (k *kueueBackend) AnnotatePod(pod *corev1.Pod, mi *workloadv1alpha1.ModelServing, groupName, taskName string) {
+	if k.workloadInformer != nil {
+		workload, err := k.workloadInformer.Lister().ByNamespace(mi.Namespace).Get(groupName)
+		if err == nil {
+			if u, ok := workload.(*unstructured.Unstructured); ok && isKueueWorkloadAdmitted(u) {
+				return
+			}
+		}
+	}
+	if hasSchedulingGate(pod, KueueAdmissionGateName) {
+		return
+	}
+	pod.Spec.SchedulingGates = append(pod.Spec.SchedulingGates, corev1.PodSchedulingGate{Name: KueueAdmissionGateName})
+}
+
+// buildKueueWorkloadSpec builds the Workload of a ServingGroup, with a pod set for the entry and the worker pods of each role.
+// All the pods of the ServingGroup are admitted at once.
+func buildKueueWorkloadSpec(mi *workloadv1alpha1.ModelServing) (kueueWorkloadSpec, error) {
+	spec := kueueWorkloadSpec{
+		QueueName: mi.Labels[KueueQueueNameLabelKey],
+	}
+	for _, role := range mi.Spec.Template.Roles {
+		replicas := *role.Replicas
+		if replicas == 0 {
+			continue
+		}
+		spec.PodSets = append(spec.PodSets, kueuePodSet{
+			Name:     role.Name + "-entry",
+			Count:    replicas,
+			Template: corev1.PodTemplateSpec{Spec: utils.TranslateGPUSharing(role.EntryTemplate.Spec, role.GPUSharing)},
+		})
+		if role.WorkerTemplate != nil && role.WorkerReplicas > 0 {
+			spec.PodSets = append(spec.PodSets, kueuePodSet{
+				Name:     role.Name + "-worker",
+				Count:    replicas * role.WorkerReplicas,
+				Template: corev1.PodTemplateSpec{Spec: utils.TranslateGPUSharing(role.WorkerTemplate.Spec, role.GPUSharing)},
+			})
+		}
+	}
+	if len(spec.PodSets) == 0 {
+		return spec, fmt.Errorf("ModelServing %s/%s has no pods to admit with Kueue", mi.Namespace, mi.Name)
+	}
+	if len(spec.PodSets) > kueueMaxPodSets {
+		return spec, fmt.Errorf("ModelServing %s/%s needs %d pod sets, Kueue supports at most %d", mi.Namespace, mi.Name, len(spec.PodSets), kueueMaxPodSets)
+	}
+	return spec, nil
+}
+
+func hashKueueWorkloadSpec(spec kueueWorkloadSpec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash Kueue Workload spec: %v", err)
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write(data)
+	return strconv.FormatUint(uint64(hasher.Sum32()), 16), nil
+}
+
+// kueueAdmissionSpec returns the parts of the Workload spec Kueue admits the pods by: the queue, the number of
+// pods of each pod set and what their pods request and are placed by.
+func kueueAdmissionSpec(spec kueueWorkloadSpec) kueueWorkloadSpec {
+	admission := kueueWorkloadSpec{QueueName: spec.QueueName}
+	for _, podSet := range spec.PodSets {
+		podSpec := corev1.PodSpec{
+			NodeSelector:      podSet.Template.Spec.NodeSelector,
+			Affinity:          podSet.Template.Spec.Affinity,
+			Tolerations:       podSet.Template.Spec.Tolerations,
+			PriorityClassName: podSet.Template.Spec.PriorityClassName,
+			Overhead:          podSet.Template.Spec.Overhead,
+		}
+		for _, container := range podSet.Template.Spec.InitContainers {
+			podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{Resources: container.Resources, RestartPolicy: container.RestartPolicy})
+		}
+		for _, container := range podSet.Template.Spec.Containers {
+			podSpec.Containers = append(podSpec.Containers, corev1.Container{Resources: container.Resources})
+		}
+		admission.PodSets = append(admission.PodSets, kueuePodSet{
+			Name:     podSet.Name,
+			Count:    podSet.Count,
+			Template: corev1.PodTemplateSpec{Spec: podSpec},
+		})
+	}
+	return admission
+}
+
+// isKueueWorkloadAdmitted checks whether the Workload has the Admitted condition
+func isKueueWorkloadAdmitted(workload *unstructured.Unstructured) bool {
+	return hasKueueCondition(workload, "Admitted")
+}
+
+// isKueueQuotaReserved checks whether the Workload holds a quota reservation, which makes its pod sets immutable
+func isKueueQuotaReserved(workload *unstructured.Unstructured) bool {
+	return hasKueueCondition(workload, "QuotaReserved")
+}
+
+func hasKueueCondition(workload *unstructured.Unstructured, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(workload.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == conditionType && condition["status"] == string(metav1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
+
+// kueuePodSetName returns the name of the Workload pod set a pod belongs to, see buildKueueWorkloadSpec
+func kueuePodSetName(pod *corev1.Pod) string {
+	if pod.Labels[workloadv1alpha1.EntryLabelKey] == utils.Entry {
+		return pod.Labels[workloadv1alpha1.RoleLabelKey] + "-entry"
+	}
+	return pod.Labels[workloadv1alpha1.RoleLabelKey] + "-worker"
+}
+
+// applyKueuePlacement adds the node labels of the assigned flavors to the node selector of a pod and their tolerations to its tolerations
+func applyKueuePlacement(pod *corev1.Pod, placement kueueResourceFlavorSpec) {
+	if len(placement.NodeLabels) > 0 && pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = make(map[string]string, len(placement.NodeLabels))
+	}
+	for key, value := range placement.NodeLabels {
+		pod.Spec.NodeSelector[key] = value
+	}
+	pod.Spec.Tolerations = appendMissingTolerations(pod.Spec.Tolerations, placement.Tolerations)
+}
+
+func appendMissingTolerations(tolerations, toAdd []corev1.Toleration) []corev1.Toleration {
+	for i := range toAdd {
+		if !slices.ContainsFunc(tolerations, func(existing corev1.Toleration) bool {
+			return existing.MatchToleration(&toAdd[i])
+		}) {
+			tolerations = append(tolerations, toAdd[i])
+		}
+	}
+	return tolerations
+}
+
+func hasSchedulingGate(pod *corev1.Pod, name string) bool {
+	for _, gate := range pod.Spec.SchedulingGates {
+		if gate.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,223 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gangscheduling
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+func TestKueueManageWorkloads(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	backend := newKueueBackend(kubefake.NewSimpleClientset(), nil, newFakeDynamicClient())
+	mi := newGangModelServing(workloadv1alpha1.SchedulerNameKueue, 2)
+
+	require.NoError(t, backend.ManagePodGroups(ctx, mi))
+	workloads, err := listGangObjects(ctx, backend.dynamicClient, kueueWorkloadGVR, mi)
+	require.NoError(t, err)
+	require.Len(t, workloads, 2)
+
+	var spec kueueWorkloadSpec
+	require.NoError(t, getSpec(workloads["test-model-1"], &spec))
+	assert.Equal(t, "user-queue", spec.QueueName)
+	require.Len(t, spec.PodSets, 2)
+	assert.Equal(t, "decode-entry", spec.PodSets[0].Name)
+	assert.Equal(t, int32(2), spec.PodSets[0].Count)
+	assert.Equal(t, "decode-worker", spec.PodSets[1].Name)
+	assert.Equal(t, int32(2), spec.PodSets[1].Count)
+	hash := workloads["test-model-1"].GetAnnotations()[kueueSpecHashAnnotationKey]
+	assert.NotEmpty(t, hash)
+
+	// An outdated Workload is updated in place
+	mi.Spec.Replicas = ptr.To[int32](1)
+	mi.Spec.Template.Roles[0].WorkerReplicas = 2
+	require.NoError(t, backend.ManagePodGroups(ctx, mi))
+	workloads, err = listGangObjects(ctx, backend.dynamicClient, kueueWorkloadGVR, mi)
+	require.NoError(t, err)
+	require.Len(t, workloads, 1)
+	hash2 := workloads["test-model-0"].GetAnnotations()[kueueSpecHashAnnotationKey]
+	assert.NotEqual(t, hash, hash2)
+	require.NoError(t, getSpec(workloads["test-model-0"], &spec))
+	assert.Equal(t, int32(4), spec.PodSets[1].Count)
+
+	// The pod sets of a Workload holding a quota reservation are kept while the resources to admit are the same
+	reserved := workloads["test-model-0"]
+	require.NoError(t, unstructured.SetNestedSlice(reserved.Object, []interface{}{
+		map[string]interface{}{"type": "QuotaReserved", "status": "True"},
+	}, "status", "conditions"))
+	_, err = backend.dynamicClient.Resource(kueueWorkloadGVR).Namespace("default").Update(ctx, reserved, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	mi.Spec.Template.Roles[0].EntryTemplate.Spec.Containers[0].Image = "engine:v2"
+	require.NoError(t, backend.ManagePodGroups(ctx, mi))
+	workloads, err = listGangObjects(ctx, backend.dynamicClient, kueueWorkloadGVR, mi)
+	require.NoError(t, err)
+	require.Len(t, workloads, 1)
+	assert.NotEqual(t, hash2, workloads["test-model-0"].GetAnnotations()[kueueSpecHashAnnotationKey])
+	require.NoError(t, getSpec(workloads["test-model-0"], &spec))
+	assert.Empty(t, spec.PodSets[0].Template.Spec.Containers[0].Image)
+
+	// and the Workload is admitted again when they change
+	mi.Spec.Template.Roles[0].WorkerReplicas = 1
+	require.NoError(t, backend.ManagePodGroups(ctx, mi))
+	workloads, err = listGangObjects(ctx, backend.dynamicClient, kueueWorkloadGVR, mi)
+	require.NoError(t, err)
+	assert.Empty(t, workloads)
+	require.NoError(t, backend.ManagePodGroups(ctx, mi))
+	workloads, err = listGangObjects(ctx, backend.dynamicClient, kueueWorkloadGVR, mi)
+	require.NoError(t, err)
+	require.Len(t, workloads, 1)
+	require.NoError(t, getSpec(workloads["test-model-0"], &spec))
+	assert.Equal(t, "engine:v2", spec.PodSets[0].Template.Spec.Containers[0].Image)
+
+	require.NoError(t, backend.CleanupPodGroups(ctx, mi))
+	workloads, err = listGangObjects(ctx, backend.dynamicClient, kueueWorkloadGVR, mi)
+	require.NoError(t, err)
+	assert.Empty(t, workloads)
+}
+
+func TestBuildKueueWorkloadSpecTooManyPodSets(t *testing.T) {
+	mi := newGangModelServing(workloadv1alpha1.SchedulerNameKueue, 1)
+	role := mi.Spec.Template.Roles[0]
+	for _, name := range []string{"a", "b", "c", "d"} {
+		r := role
+		r.Name = name
+		mi.Spec.Template.Roles = append(mi.Spec.Template.Roles, r)
+	}
+	_, err := buildKueueWorkloadSpec(mi)
+	assert.Error(t, err)
+}
+
+func TestKueueReleasePods(t *testing.T) {
+	ctx := context.Background()
+	labels := map[string]string{
+		workloadv1alpha1.ModelServingNameLabelKey: "test-model",
+		workloadv1alpha1.GroupNameLabelKey:        "test-model-0",
+	}
+	entryLabels := map[string]string{
+		workloadv1alpha1.ModelServingNameLabelKey: "test-model",
+		workloadv1alpha1.GroupNameLabelKey:        "test-model-0",
+		workloadv1alpha1.RoleLabelKey:             "prefill",
+		workloadv1alpha1.EntryLabelKey:            "true",
+	}
+	gpuToleration := corev1.Toleration{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	spotToleration := corev1.Toleration{Key: "spot", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule}
+	pods := []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "gated", Namespace: "default", Labels: entryLabels},
+			Spec: corev1.PodSpec{
+				NodeSelector: map[string]string{"zone": "a"},
+				Tolerations:  []corev1.Toleration{gpuToleration},
+				SchedulingGates: []corev1.PodSchedulingGate{
+					{Name: "other"},
+					{Name: KueueAdmissionGateName},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "released", Namespace: "default", Labels: labels},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-group", Namespace: "default", Labels: map[string]string{
+				workloadv1alpha1.ModelServingNameLabelKey: "test-model",
+				workloadv1alpha1.GroupNameLabelKey:        "test-model-1",
+			}},
+			Spec: corev1.PodSpec{SchedulingGates: []corev1.PodSchedulingGate{{Name: KueueAdmissionGateName}}},
+		},
+	}
+	kubeClient := kubefake.NewSimpleClientset()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pod := range pods {
+		_, err := kubeClient.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{})
+		require.NoError(t, err)
+		require.NoError(t, indexer.Add(pod))
+	}
+	kubeClient.ClearActions()
+	dynamicClient := newFakeDynamicClient()
+	for name, spec := range map[string]kueueResourceFlavorSpec{
+		"gpu":  {NodeLabels: map[string]string{"accelerator": "a100"}, Tolerations: []corev1.Toleration{gpuToleration}},
+		"spot": {NodeLabels: map[string]string{"capacity": "spot"}, Tolerations: []corev1.Toleration{spotToleration}},
+	} {
+		flavor := &unstructured.Unstructured{}
+		flavor.SetAPIVersion(kueueResourceFlavorGVR.GroupVersion().String())
+		flavor.SetKind("ResourceFlavor")
+		flavor.SetName(name)
+		require.NoError(t, setSpec(flavor, &spec))
+		_, err := dynamicClient.Resource(kueueResourceFlavorGVR).Create(ctx, flavor, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	backend := newKueueBackend(kubeClient, listerv1.NewPodLister(indexer), dynamicClient)
+
+	workload := &unstructured.Unstructured{}
+	workload.SetName("test-model-0")
+	workload.SetNamespace("default")
+	workload.SetLabels(labels)
+
+	// Not admitted yet
+	backend.onWorkload(workload)
+	pod, err := kubeClient.CoreV1().Pods("default").Get(ctx, "gated", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Len(t, pod.Spec.SchedulingGates, 2)
+
+	require.NoError(t, unstructured.SetNestedSlice(workload.Object, []interface{}{
+		map[string]interface{}{"type": "QuotaReserved", "status": "True"},
+		map[string]interface{}{"type": "Admitted", "status": "True"},
+	}, "status", "conditions"))
+	backend.onWorkload(workload)
+
+	// The pod set of the pod has no flavor assignment, the pod stays gated
+	pod, err = kubeClient.CoreV1().Pods("default").Get(ctx, "gated", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Len(t, pod.Spec.SchedulingGates, 2)
+
+	require.NoError(t, unstructured.SetNestedSlice(workload.Object, []interface{}{
+		map[string]interface{}{
+			"name":    "prefill-entry",
+			"flavors": map[string]interface{}{"nvidia.com/gpu": "gpu", "cpu": "spot"},
+		},
+	}, "status", "admission", "podSetAssignments"))
+	backend.onWorkload(workload)
+
+	pod, err = kubeClient.CoreV1().Pods("default").Get(ctx, "gated", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []corev1.PodSchedulingGate{{Name: "other"}}, pod.Spec.SchedulingGates)
+	assert.Equal(t, map[string]string{"zone": "a", "accelerator": "a100", "capacity": "spot"}, pod.Spec.NodeSelector)
+	assert.Equal(t, []corev1.Toleration{gpuToleration, spotToleration}, pod.Spec.Tolerations)
+	pod, err = kubeClient.CoreV1().Pods("default").Get(ctx, "other-group", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Len(t, pod.Spec.SchedulingGates, 1)
+
+	// The pods are listed from the cache, and only the gated ones are updated
+	verbs := map[string]int{}
+	for _, action := range kubeClient.Actions() {
+		verbs[action.GetVerb()]++
+	}
+	assert.Zero(t, verbs["list"])
+	assert.Equal(t, 1, verbs["update"])
+}
@@ -21,117 +21,101 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/klog/v2"
+	listerv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/utils/ptr"
-
-	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
-	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	volcanoclient "volcano.sh/apis/pkg/client/clientset/versioned"
 
 	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
 )
 
-// Manager manages PodGroups for gang scheduling
+// Backend manages the gang scheduling objects of one scheduler for the ServingGroups of a ModelServing.
+type Backend interface {
+	// PodSchedulerName returns the name of the scheduler which binds the pods of the ModelServing.
+	PodSchedulerName() string
+	// ManagePodGroups creates, updates and deletes the gang of each ServingGroup.
+	ManagePodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) error
+	// CleanupPodGroups deletes all the gangs of the ModelServing.
+	CleanupPodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) error
+	// AnnotatePod associates a pod with the gang of its ServingGroup.
+	AnnotatePod(pod *corev1.Pod, mi *workloadv1alpha1.ModelServing, groupName, taskName string)
+}
+
+// Manager manages the gang scheduling objects of ModelServings, using the backend
+// selected by the spec.schedulerName of each ModelServing.
 type Manager struct {
-	kubeClient    kubernetes.Interface
-	volcanoClient volcanoclient.Interface
+	backends map[string]Backend
 }
 
-// NewManager creates a new gang scheduling manager
-func NewManager(kubeClient kubernetes.Interface, volcanoClient volcanoclient.Interface) Manager {
+// NewManager creates a new gang scheduling manager, the pod lister lists the pods of the ServingGroups
+func NewManager(kubeClient kubernetes.Interface, podLister listerv1.PodLister, volcanoClient volcanoclient.Interface, dynamicClient dynamic.Interface) Manager {
 	return Manager{
-		kubeClient:    kubeClient,
-		volcanoClient: volcanoClient,
+		backends: map[string]Backend{
+			workloadv1alpha1.SchedulerNameVolcano:      newVolcanoBackend(volcanoClient),
+			workloadv1alpha1.SchedulerNameKueue:        newKueueBackend(kubeClient, podLister, dynamicClient),
+			workloadv1alpha1.SchedulerNameCoscheduling: newCoschedulingBackend(dynamicClient),
+		},
 	}
 }
 
+// IsSupportedScheduler reports whether gang scheduling is supported for the scheduler name.
+func IsSupportedScheduler(schedulerName string) bool {
+	switch schedulerName {
+	case workloadv1alpha1.SchedulerNameVolcano, workloadv1alpha1.SchedulerNameKueue, workloadv1alpha1.SchedulerNameCoscheduling:
+		return true
+	}
+	return false
+}
+
 // ManagePodGroups manages PodGroups for a ModelServing instance
 func (m *Manager) ManagePodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
-	if m.isSchedulingEnabled(mi) {
-		return m.managePodGroups(ctx, mi)
+	if !isSchedulingEnabled(mi) {
+		return nil
+	}
+	backend, ok := m.backends[mi.Spec.SchedulerName]
+	if !ok {
+		return fmt.Errorf("gang scheduling is not supported by scheduler %q", mi.Spec.SchedulerName)
 	}
-	return nil
+	return backend.ManagePodGroups(ctx, mi)
 }
 
-// isSchedulingEnabled checks if gang scheduling or networkTopology scheduling is enabled for the ModelServing
-func (m *Manager) isSchedulingEnabled(mi *workloadv1alpha1.ModelServing) bool {
-	return mi.Spec.Template.GangPolicy != nil || mi.Spec.Template.NetworkTopology != nil
+// CleanupPodGroups cleans up all PodGroups for a ModelServing
+func (m *Manager) CleanupPodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
+	backend, ok := m.backends[mi.Spec.SchedulerName]
+	if !ok {
+		return nil
+	}
+	return backend.CleanupPodGroups(ctx, mi)
 }
 
-// managePodGroups manages PodGroups for group-level gang scheduling
-func (m *Manager) managePodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
-	expectedReplicas := int(*mi.Spec.Replicas)
-
-	// Get existing PodGroups
-	existingPodGroups, err := m.getExistingPodGroups(ctx, mi)
-	if err != nil {
-		return fmt.Errorf("failed to get existing PodGroups: %v", err)
+// AnnotatePodWithPodGroup sets the scheduler of a pod and annotates it with the appropriate PodGroup information
+func (m *Manager) AnnotatePodWithPodGroup(pod *corev1.Pod, mi *workloadv1alpha1.ModelServing, minMember int, groupName, taskName string) {
+	backend, ok := m.backends[mi.Spec.SchedulerName]
+	if !ok {
+		return
 	}
-
-	// Create or update PodGroups for each ServingGroup
-	for i := 0; i < expectedReplicas; i++ {
-		podGroupName := m.generatePodGroupName(mi.Name, i)
-
-		if existingPG, exists := existingPodGroups[podGroupName]; exists {
-			// Update existing PodGroup if needed
-			if err := m.updatePodGroupIfNeeded(ctx, existingPG, mi); err != nil {
-				return fmt.Errorf("failed to update PodGroup %s: %v", podGroupName, err)
-			}
-		} else {
-			// Create new PodGroup
-			if err := m.createPodGroup(ctx, mi, i); err != nil {
-				return fmt.Errorf("failed to create PodGroup %s: %v", podGroupName, err)
-			}
-		}
+	pod.Spec.SchedulerName = backend.PodSchedulerName()
+	if !isSchedulingEnabled(mi) {
+		return
 	}
-
-	// Clean up excess PodGroups
-	return m.cleanupExcessPodGroups(ctx, mi, existingPodGroups, expectedReplicas)
+	backend.AnnotatePod(pod, mi, groupName, taskName)
 }
 
-// createPodGroup creates a PodGroup for group-level gang scheduling
-func (m *Manager) createPodGroup(ctx context.Context, mi *workloadv1alpha1.ModelServing, groupIndex int) error {
-	podGroupName := m.generatePodGroupName(mi.Name, groupIndex)
-
-	// Calculate total pods and resources for this ServingGroup
-	minMember, minTaskMember, minResources := m.calculateRequirements(mi)
-
-	podGroup := &schedulingv1beta1.PodGroup{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podGroupName,
-			Namespace: mi.Namespace,
-			Labels: map[string]string{
-				workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
-				workloadv1alpha1.GroupNameLabelKey:        podGroupName,
-			},
-			Annotations: map[string]string{
-				schedulingv1beta1.KubeGroupNameAnnotationKey: podGroupName,
-			},
-			OwnerReferences: m.buildOwnerReference(mi),
-		},
-		Spec: schedulingv1beta1.PodGroupSpec{
-			MinMember:       int32(minMember),
-			MinTaskMember:   minTaskMember,
-			MinResources:    &minResources,
-			NetworkTopology: mi.Spec.Template.NetworkTopology,
-		},
-	}
-
-	_, err := m.volcanoClient.SchedulingV1beta1().PodGroups(mi.Namespace).Create(ctx, podGroup, metav1.CreateOptions{})
-	if err != nil && !apierrors.IsAlreadyExists(err) {
-		return err
-	}
+// GenerateTaskName generates task name for MinTaskMember
+func (m *Manager) GenerateTaskName(roleName string, roleIndex int) string {
+	return generateTaskName(roleName, roleIndex)
+}
 
-	klog.V(2).Infof("Created PodGroup %s for group-level gang scheduling", podGroupName)
-	return nil
+// isSchedulingEnabled checks if gang scheduling or networkTopology scheduling is enabled for the ModelServing
+func isSchedulingEnabled(mi *workloadv1alpha1.ModelServing) bool {
+	return mi.Spec.Template.GangPolicy != nil || mi.Spec.Template.NetworkTopology != nil
 }
 
 // To build ownerReferences of PodGroup
-func (m *Manager) buildOwnerReference(mi *workloadv1alpha1.ModelServing) []metav1.OwnerReference {
+func buildOwnerReference(mi *workloadv1alpha1.ModelServing) []metav1.OwnerReference {
 	return []metav1.OwnerReference{
 		{
 			APIVersion: workloadv1alpha1.GroupVersion.String(),
@@ -143,8 +127,16 @@ func (m *Manager) buildOwnerReference(mi *workloadv1alpha1.ModelServing) []metav
 	}
 }
 
+// podGroupLabels returns the labels of the gang of a ServingGroup
+func podGroupLabels(mi *workloadv1alpha1.ModelServing, podGroupName string) map[string]string {
+	return map[string]string{
+		workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
+		workloadv1alpha1.GroupNameLabelKey:        podGroupName,
+	}
+}
+
 // calculateRequirements calculates requirements for role-level gang scheduling
-func (m *Manager) calculateRequirements(mi *workloadv1alpha1.ModelServing) (int, map[string]int32, corev1.ResourceList) {
+func calculateRequirements(mi *workloadv1alpha1.ModelServing) (int, map[string]int32, corev1.ResourceList) {
 	minMember := 0
 	minTaskMember := make(map[string]int32)
 	minResources := corev1.ResourceList{}
@@ -154,7 +146,7 @@ func (m *Manager) calculateRequirements(mi *workloadv1alpha1.ModelServing) (int,
 		roleReplicas := int(*role.Replicas)
 		minRoleReplicas := roleReplicas // Default to all replicas
 
-		if mi.Spec.Template.GangPolicy.MinRoleReplicas != nil {
+		if mi.Spec.Template.GangPolicy != nil && mi.Spec.Template.GangPolicy.MinRoleReplicas != nil {
 			if minReplicas, exists := mi.Spec.Template.GangPolicy.MinRoleReplicas[role.Name]; exists {
 				minRoleReplicas = int(minReplicas)
 			}
@@ -162,16 +154,18 @@ func (m *Manager) calculateRequirements(mi *workloadv1alpha1.ModelServing) (int,
 
 		// Only include role replicas up to the minimum required
 		for roleIndex := 0; roleIndex < minRoleReplicas && roleIndex < roleReplicas; roleIndex++ {
-			taskName := m.GenerateTaskName(role.Name, roleIndex)
+			taskName := generateTaskName(role.Name, roleIndex)
 			podsPerTask := 1 + int(role.WorkerReplicas) // entry + workers
 			minTaskMember[taskName] = int32(podsPerTask)
 			minMember += podsPerTask
 
-			// Aggregate resources
-			m.aggregateResources(&minResources, &role.EntryTemplate.Spec)
+			// Aggregate resources, with the GPUs translated as in the pods
+			entrySpec := utils.TranslateGPUSharing(role.EntryTemplate.Spec, role.GPUSharing)
+			aggregateResources(&minResources, &entrySpec)
 			if role.WorkerTemplate != nil {
+				workerSpec := utils.TranslateGPUSharing(role.WorkerTemplate.Spec, role.GPUSharing)
 				for i := 0; i < int(role.WorkerReplicas); i++ {
-					m.aggregateResources(&minResources, &role.WorkerTemplate.Spec)
+					aggregateResources(&minResources, &workerSpec)
 				}
 			}
 		}
@@ -181,7 +175,7 @@ func (m *Manager) calculateRequirements(mi *workloadv1alpha1.ModelServing) (int,
 }
 
 // aggregateResources aggregates resource requirements from a pod spec
-func (m *Manager) aggregateResources(total *corev1.ResourceList, podSpec *corev1.PodSpec) {
+func aggregateResources(total *corev1.ResourceList, podSpec *corev1.PodSpec) {
 	if *total == nil {
 		*total = corev1.ResourceList{}
 	}
@@ -199,150 +193,32 @@ func (m *Manager) aggregateResources(total *corev1.ResourceList, podSpec *corev1
 }
 
 // generatePodGroupName generates PodGroup name for group-level scheduling
-func (m *Manager) generatePodGroupName(modelServingName string, groupIndex int) string {
+func generatePodGroupName(modelServingName string, groupIndex int) string {
 	return fmt.Sprintf("%s-%d", modelServingName, groupIndex)
 }
 
-// GenerateTaskName generates task name for MinTaskMember
-func (m *Manager) GenerateTaskName(roleName string, roleIndex int) string {
+// generateTaskName generates task name for MinTaskMember
+func generateTaskName(roleName string, roleIndex int) string {
 	return fmt.Sprintf("%s-%d", roleName, roleIndex)
 }
 
-// getExistingPodGroups gets existing PodGroups for a ModelServing
-func (m *Manager) getExistingPodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) (map[string]*schedulingv1beta1.PodGroup, error) {
-	selector := labels.SelectorFromSet(map[string]string{
-		workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
-	})
-
-	podGroupList, err := m.volcanoClient.SchedulingV1beta1().PodGroups(mi.Namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: selector.String(),
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	result := make(map[string]*schedulingv1beta1.PodGroup)
-	for i := range podGroupList.Items {
-		pg := &podGroupList.Items[i]
-		result[pg.Name] = pg
-	}
-
-	return result, nil
-}
-
-// updatePodGroupIfNeeded updates a PodGroup if needed for group-level scheduling
-func (m *Manager) updatePodGroupIfNeeded(ctx context.Context, existing *schedulingv1beta1.PodGroup, mi *workloadv1alpha1.ModelServing) error {
-	// Calculate current requirements
-	minMember, minTaskMember, minResources := m.calculateRequirements(mi)
-
-	needsUpdate := false
-	updated := existing.DeepCopy()
-
-	// Check if MinMember needs update
-	if updated.Spec.MinMember != int32(minMember) {
-		updated.Spec.MinMember = int32(minMember)
-		needsUpdate = true
-	}
-
-	// Check if MinTaskMember needs update
-	if !equalMinTaskMember(updated.Spec.MinTaskMember, minTaskMember) {
-		updated.Spec.MinTaskMember = minTaskMember
-		needsUpdate = true
-	}
-
-	// Check if MinResources needs update
-	if !equalResourceList(updated.Spec.MinResources, &minResources) {
-		updated.Spec.MinResources = &minResources
-		needsUpdate = true
-	}
-
-	if !equalVolcanoNetworkTopology(updated.Spec.NetworkTopology, mi.Spec.Template.NetworkTopology) {
-		updated.Spec.NetworkTopology = mi.Spec.Template.NetworkTopology
-		needsUpdate = true
-	}
-
-	if needsUpdate {
-		_, err := m.volcanoClient.SchedulingV1beta1().PodGroups(mi.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
-		if err != nil {
-			return err
-		}
-		klog.V(2).Infof("Updated PodGroup %s for group-level gang scheduling", existing.Name)
-	}
-
-	return nil
-}
-
-// cleanupExcessPodGroups cleans up excess PodGroups
-func (m *Manager) cleanupExcessPodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing, existingPodGroups map[string]*schedulingv1beta1.PodGroup, expectedReplicas int) error {
-	for podGroupName, podGroup := range existingPodGroups {
-		// Check if this PodGroup is still needed
-		isNeeded := false
-		for i := 0; i < expectedReplicas; i++ {
-			expectedName := m.generatePodGroupName(mi.Name, i)
-			if podGroupName == expectedName {
-				isNeeded = true
-				break
-			}
-		}
-
-		if !isNeeded {
-			err := m.volcanoClient.SchedulingV1beta1().PodGroups(mi.Namespace).Delete(ctx, podGroup.Name, metav1.DeleteOptions{})
-			if err != nil && !apierrors.IsNotFound(err) {
-				return fmt.Errorf("failed to delete excess PodGroup %s: %v", podGroup.Name, err)
-			}
-			klog.V(2).Infof("Deleted excess PodGroup %s", podGroup.Name)
-		}
-	}
-
-	return nil
-}
-
-// cleanupPodGroups cleans up all PodGroups for a ModelServing
-func (m *Manager) CleanupPodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
-	existingPodGroups, err := m.getExistingPodGroups(ctx, mi)
-	if err != nil {
-		return fmt.Errorf("failed to get existing PodGroups for cleanup: %v", err)
-	}
-
-	for _, podGroup := range existingPodGroups {
-		err := m.volcanoClient.SchedulingV1beta1().PodGroups(mi.Namespace).Delete(ctx, podGroup.Name, metav1.DeleteOptions{})
-		if err != nil && !apierrors.IsNotFound(err) {
-			return fmt.Errorf("failed to delete PodGroup %s: %v", podGroup.Name, err)
-		}
-		klog.V(2).Infof("Deleted PodGroup %s (gang scheduling disabled)", podGroup.Name)
+// servingGroupCount returns the number of ServingGroups to gang schedule, including the standby ones
+func servingGroupCount(mi *workloadv1alpha1.ModelServing) int {
+	count := int(*mi.Spec.Replicas)
+	if mi.Spec.StandbyReplicas != nil {
+		count += int(*mi.Spec.StandbyReplicas)
 	}
-
-	return nil
+	return count
 }
 
-// AnnotatePodWithPodGroup annotates a pod with the appropriate PodGroup information
-func (m *Manager) AnnotatePodWithPodGroup(pod *corev1.Pod, mi *workloadv1alpha1.ModelServing, minMember int, groupName, taskName string) {
-	if !m.isSchedulingEnabled(mi) {
-		return
-	}
-
-	if pod.Annotations == nil {
-		pod.Annotations = make(map[string]string)
-	}
-
-	// Add volcano annotation
-	pod.Annotations[schedulingv1beta1.KubeGroupNameAnnotationKey] = groupName
-	pod.Annotations[batchv1alpha1.TaskSpecKey] = taskName
-}
-
-// equalMinTaskMember compares two MinTaskMember maps
-func equalMinTaskMember(a, b map[string]int32) bool {
-	if len(a) != len(b) {
-		return false
-	}
-
-	for key, valueA := range a {
-		if valueB, exists := b[key]; !exists || valueA != valueB {
-			return false
+// isPodGroupNeeded checks whether the gang is still needed by one of the expected ServingGroups
+func isPodGroupNeeded(mi *workloadv1alpha1.ModelServing, podGroupName string, expectedReplicas int) bool {
+	for i := 0; i < expectedReplicas; i++ {
+		if podGroupName == generatePodGroupName(mi.Name, i) {
+			return true
 		}
 	}
-
-	return true
+	return false
 }
 
 // equalResourceList compares two ResourceList
@@ -369,20 +245,3 @@ func equalResourceList(a, b *corev1.ResourceList) bool {
 
 	return true
 }
-
-// equalVolcanoNetworkTopology compares two volcano NetworkTopologySpec pointers for equality
-func equalVolcanoNetworkTopology(a, b *schedulingv1beta1.NetworkTopologySpec) bool {
-	// If both are nil, they are equal
-	if a == nil && b == nil {
-		return true
-	}
-
-	// If one is nil and the other is not, they are not equal
-	if a == nil || b == nil {
-		return false
-	}
-
-	// Both are non-nil, compare their values
-	return a.Mode == b.Mode &&
-		a.HighestTierAllowed == b.HighestTierAllowed
-}
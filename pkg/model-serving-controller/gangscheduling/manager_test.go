@@ -84,10 +84,9 @@ func TestCalculateRequirements(t *testing.T) {
 	}
 
 	t.Run("basic calculation", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		mi := createBasicModelServing()
 
-		minMember, minTaskMember, minResources := manager.calculateRequirements(mi)
+		minMember, minTaskMember, minResources := calculateRequirements(mi)
 
 		// For 2 prefill roles (each with 1 entry + 3 workers) and 1 decode role (1 entry + 2 workers)
 		// Total pods = (1+3)*2 + (1+2)*1 = 8 + 3 = 11
@@ -115,7 +114,6 @@ func TestCalculateRequirements(t *testing.T) {
 	})
 
 	t.Run("with MinRoleReplicas constraint", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		mi := createBasicModelServing()
 
 		// Set MinRoleReplicas to limit the number of roles considered
@@ -125,7 +123,7 @@ func TestCalculateRequirements(t *testing.T) {
 		}
 		mi.Spec.Template.GangPolicy.MinRoleReplicas = minRoleReplicas
 
-		minMember, minTaskMember, minResources := manager.calculateRequirements(mi)
+		minMember, minTaskMember, minResources := calculateRequirements(mi)
 
 		// For 1 prefill role (1 entry + 3 workers) and 1 decode role (1 entry + 2 workers)
 		// Total pods = (1+3)*1 + (1+2)*1 = 4 + 3 = 7
@@ -152,11 +150,10 @@ func TestCalculateRequirements(t *testing.T) {
 	})
 
 	t.Run("nil MinRoleReplicas", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		mi := createBasicModelServing()
 		mi.Spec.Template.GangPolicy.MinRoleReplicas = nil
 
-		minMember, _, _ := manager.calculateRequirements(mi)
+		minMember, _, _ := calculateRequirements(mi)
 
 		// Should consider all roles without constraint
 		// Same as basic calculation: 11 pods
@@ -164,11 +161,10 @@ func TestCalculateRequirements(t *testing.T) {
 	})
 
 	t.Run("empty roles", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		mi := createBasicModelServing()
 		mi.Spec.Template.Roles = []workloadv1alpha1.Role{} // Empty roles
 
-		minMember, minTaskMember, minResources := manager.calculateRequirements(mi)
+		minMember, minTaskMember, minResources := calculateRequirements(mi)
 
 		// Should have no requirements
 		assert.Equal(t, 0, minMember)
@@ -177,13 +173,12 @@ func TestCalculateRequirements(t *testing.T) {
 	})
 
 	t.Run("role with no worker template", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		mi := createBasicModelServing()
 
 		// Modify one role to have no worker template
 		mi.Spec.Template.Roles[1].WorkerTemplate = nil
 		mi.Spec.Template.Roles[1].WorkerReplicas = 0
-		minMember, minTaskMember, _ := manager.calculateRequirements(mi)
+		minMember, minTaskMember, _ := calculateRequirements(mi)
 
 		// For 2 prefill roles (each with 1 entry + 3 workers) and 1 decode role (1 entry only)
 		// Total pods = (1+3)*2 + (1+0)*1 = 8 + 1 = 9
@@ -199,13 +194,12 @@ func TestCalculateRequirements(t *testing.T) {
 	})
 
 	t.Run("zero worker replicas", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		mi := createBasicModelServing()
 
 		// Set worker replicas to zero for one role
 		mi.Spec.Template.Roles[0].WorkerReplicas = 0
 
-		minMember, minTaskMember, _ := manager.calculateRequirements(mi)
+		minMember, minTaskMember, _ := calculateRequirements(mi)
 
 		// For 2 prefill roles (each with 1 entry + 0 workers) and 1 decode role (1 entry + 2 workers)
 		// Total pods = (1+0)*2 + (1+2)*1 = 2 + 3 = 5
@@ -219,11 +213,28 @@ func TestCalculateRequirements(t *testing.T) {
 		}
 		assert.Equal(t, expectedTaskMembers, minTaskMember)
 	})
+
+	t.Run("GPU sharing", func(t *testing.T) {
+		mi := createBasicModelServing()
+
+		// The decode role shares MIG instances, its GPUs are counted as in its pods
+		decode := &mi.Spec.Template.Roles[1]
+		decode.GPUSharing = &workloadv1alpha1.GPUSharing{Mode: workloadv1alpha1.GPUSharingMIG, MIGProfile: "1g.10gb"}
+		decode.EntryTemplate.Spec.Containers[0].Resources.Requests["nvidia.com/gpu"] = resource.MustParse("1")
+		decode.WorkerTemplate.Spec.Containers[0].Resources.Requests["nvidia.com/gpu"] = resource.MustParse("1")
+
+		_, _, minResources := calculateRequirements(mi)
+
+		// Decode roles: 1*1 + 1*2*1 = 3 MIG instances
+		expectedMIG := resource.MustParse("3")
+		assert.True(t, expectedMIG.Equal(minResources["nvidia.com/mig-1g.10gb"]),
+			"Expected MIG instances %v, got %v", expectedMIG, minResources["nvidia.com/mig-1g.10gb"])
+		assert.NotContains(t, minResources, corev1.ResourceName("nvidia.com/gpu"))
+	})
 }
 
 func TestAggregateResources(t *testing.T) {
 	t.Run("basic aggregation", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		total := corev1.ResourceList{}
 
 		podSpec := &corev1.PodSpec{
@@ -249,7 +260,7 @@ func TestAggregateResources(t *testing.T) {
 			},
 		}
 
-		manager.aggregateResources(&total, podSpec)
+		aggregateResources(&total, podSpec)
 
 		expectedCPU := resource.MustParse("3")
 		expectedMemory := resource.MustParse("3Gi")
@@ -259,7 +270,6 @@ func TestAggregateResources(t *testing.T) {
 	})
 
 	t.Run("nil total resource list", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		var total corev1.ResourceList = nil
 
 		podSpec := &corev1.PodSpec{
@@ -275,7 +285,7 @@ func TestAggregateResources(t *testing.T) {
 			},
 		}
 
-		manager.aggregateResources(&total, podSpec)
+		aggregateResources(&total, podSpec)
 
 		assert.NotNil(t, total)
 		assert.Len(t, total, 1)
@@ -283,33 +293,30 @@ func TestAggregateResources(t *testing.T) {
 	})
 
 	t.Run("empty containers", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		total := corev1.ResourceList{}
 
 		podSpec := &corev1.PodSpec{
 			Containers: []corev1.Container{}, // Empty containers
 		}
 
-		manager.aggregateResources(&total, podSpec)
+		aggregateResources(&total, podSpec)
 
 		assert.Empty(t, total)
 	})
 
 	t.Run("nil containers", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		total := corev1.ResourceList{}
 
 		podSpec := &corev1.PodSpec{
 			Containers: nil, // Nil containers
 		}
 
-		manager.aggregateResources(&total, podSpec)
+		aggregateResources(&total, podSpec)
 
 		assert.Empty(t, total)
 	})
 
 	t.Run("container with no resources", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		total := corev1.ResourceList{}
 
 		podSpec := &corev1.PodSpec{
@@ -321,13 +328,12 @@ func TestAggregateResources(t *testing.T) {
 			},
 		}
 
-		manager.aggregateResources(&total, podSpec)
+		aggregateResources(&total, podSpec)
 
 		assert.Empty(t, total)
 	})
 
 	t.Run("container with empty resources", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		total := corev1.ResourceList{}
 
 		podSpec := &corev1.PodSpec{
@@ -341,13 +347,12 @@ func TestAggregateResources(t *testing.T) {
 			},
 		}
 
-		manager.aggregateResources(&total, podSpec)
+		aggregateResources(&total, podSpec)
 
 		assert.Empty(t, total)
 	})
 
 	t.Run("multiple calls to aggregate resources", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		total := corev1.ResourceList{}
 
 		podSpec1 := &corev1.PodSpec{
@@ -377,16 +382,15 @@ func TestAggregateResources(t *testing.T) {
 		}
 
 		// First call
-		manager.aggregateResources(&total, podSpec1)
+		aggregateResources(&total, podSpec1)
 		assert.True(t, resource.MustParse("1").Equal(total[corev1.ResourceCPU]))
 
 		// Second call
-		manager.aggregateResources(&total, podSpec2)
+		aggregateResources(&total, podSpec2)
 		assert.True(t, resource.MustParse("3").Equal(total[corev1.ResourceCPU]))
 	})
 
 	t.Run("different resource types", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		total := corev1.ResourceList{}
 
 		podSpec := &corev1.PodSpec{
@@ -404,7 +408,7 @@ func TestAggregateResources(t *testing.T) {
 			},
 		}
 
-		manager.aggregateResources(&total, podSpec)
+		aggregateResources(&total, podSpec)
 
 		assert.Len(t, total, 3)
 		assert.True(t, resource.MustParse("1").Equal(total[corev1.ResourceCPU]))
@@ -413,7 +417,6 @@ func TestAggregateResources(t *testing.T) {
 	})
 
 	t.Run("existing resources get updated", func(t *testing.T) {
-		manager := NewManager(nil, nil)
 		total := corev1.ResourceList{
 			corev1.ResourceCPU: resource.MustParse("1"),
 		}
@@ -431,7 +434,7 @@ func TestAggregateResources(t *testing.T) {
 			},
 		}
 
-		manager.aggregateResources(&total, podSpec)
+		aggregateResources(&total, podSpec)
 
 		// Should have 1+2=3 CPUs
 		assert.True(t, resource.MustParse("3").Equal(total[corev1.ResourceCPU]))
@@ -490,9 +493,9 @@ func TestGetExistingPodGroups(t *testing.T) {
 	t.Run("successful retrieval of existing pod groups", func(t *testing.T) {
 		// Create fake volcano client with test data
 		fakeVolcanoClient := volcanofake.NewSimpleClientset(podGroup1, podGroup2, podGroup3, podGroupDifferentNamespace)
-		manager := NewManager(nil, fakeVolcanoClient)
+		backend := newVolcanoBackend(fakeVolcanoClient)
 
-		result, err := manager.getExistingPodGroups(context.Background(), modelServing)
+		result, err := backend.getExistingPodGroups(context.Background(), modelServing)
 
 		// Assertions
 		assert.NoError(t, err)
@@ -514,9 +517,9 @@ func TestGetExistingPodGroups(t *testing.T) {
 	t.Run("no existing pod groups", func(t *testing.T) {
 		// Create fake volcano client with only unrelated pod groups
 		fakeVolcanoClient := volcanofake.NewSimpleClientset(podGroup3)
-		manager := NewManager(nil, fakeVolcanoClient)
+		backend := newVolcanoBackend(fakeVolcanoClient)
 
-		result, err := manager.getExistingPodGroups(context.Background(), modelServing)
+		result, err := backend.getExistingPodGroups(context.Background(), modelServing)
 
 		// Assertions
 		assert.NoError(t, err)
@@ -527,9 +530,9 @@ func TestGetExistingPodGroups(t *testing.T) {
 	t.Run("empty pod group list", func(t *testing.T) {
 		// Create fake volcano client with no pod groups
 		fakeVolcanoClient := volcanofake.NewSimpleClientset()
-		manager := NewManager(nil, fakeVolcanoClient)
+		backend := newVolcanoBackend(fakeVolcanoClient)
 
-		result, err := manager.getExistingPodGroups(context.Background(), modelServing)
+		result, err := backend.getExistingPodGroups(context.Background(), modelServing)
 
 		// Assertions
 		assert.NoError(t, err)
@@ -540,9 +543,9 @@ func TestGetExistingPodGroups(t *testing.T) {
 	t.Run("pod group with same name in different namespace", func(t *testing.T) {
 		// Create fake volcano client with pod groups
 		fakeVolcanoClient := volcanofake.NewSimpleClientset(podGroup1, podGroupDifferentNamespace)
-		manager := NewManager(nil, fakeVolcanoClient)
+		backend := newVolcanoBackend(fakeVolcanoClient)
 
-		result, err := manager.getExistingPodGroups(context.Background(), modelServing)
+		result, err := backend.getExistingPodGroups(context.Background(), modelServing)
 
 		// Should only get pod groups from the same namespace
 		assert.NoError(t, err)
@@ -554,16 +557,60 @@ func TestGetExistingPodGroups(t *testing.T) {
 
 	t.Run("nil model Serving parameter", func(t *testing.T) {
 		fakeVolcanoClient := volcanofake.NewSimpleClientset(podGroup1)
-		manager := NewManager(nil, fakeVolcanoClient)
+		backend := newVolcanoBackend(fakeVolcanoClient)
 
 		// Test with nil ModelServing - this would cause a panic in the real code
 		// but we're checking that our test handles it gracefully
 		assert.Panics(t, func() {
-			_, _ = manager.getExistingPodGroups(context.Background(), nil)
+			_, _ = backend.getExistingPodGroups(context.Background(), nil)
 		})
 	})
 }
 
+func TestVolcanoPodGroupPriority(t *testing.T) {
+	newModelServing := func(groupPriority string, rolePriorities ...string) *workloadv1alpha1.ModelServing {
+		ms := &workloadv1alpha1.ModelServing{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-model",
+				Namespace:   "default",
+				Annotations: map[string]string{schedulingv1beta1.QueueNameAnnotationKey: "online"},
+			},
+		}
+		ms.Spec.Replicas = ptr.To[int32](1)
+		ms.Spec.Template.PriorityClassName = groupPriority
+		for i, priority := range rolePriorities {
+			ms.Spec.Template.Roles = append(ms.Spec.Template.Roles, workloadv1alpha1.Role{
+				Name:              []string{"prefill", "decode"}[i],
+				Replicas:          ptr.To[int32](1),
+				PriorityClassName: priority,
+			})
+		}
+		return ms
+	}
+
+	assert.Equal(t, "high", podGroupPriorityClass(newModelServing("high", "low", "")))
+	assert.Equal(t, "low", podGroupPriorityClass(newModelServing("", "low", "low")))
+	assert.Equal(t, "", podGroupPriorityClass(newModelServing("", "low", "high")))
+
+	client := volcanofake.NewSimpleClientset()
+	backend := newVolcanoBackend(client)
+	ms := newModelServing("high", "")
+	assert.NoError(t, backend.ManagePodGroups(context.Background(), ms))
+	pg, err := client.SchedulingV1beta1().PodGroups("default").Get(context.Background(), "test-model-0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "high", pg.Spec.PriorityClassName)
+	assert.Equal(t, "online", pg.Spec.Queue)
+
+	// The priority class follows the ModelServing, the queue is left as created
+	ms = newModelServing("low", "")
+	ms.Annotations[schedulingv1beta1.QueueNameAnnotationKey] = "batch"
+	assert.NoError(t, backend.ManagePodGroups(context.Background(), ms))
+	pg, err = client.SchedulingV1beta1().PodGroups("default").Get(context.Background(), "test-model-0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "low", pg.Spec.PriorityClassName)
+	assert.Equal(t, "online", pg.Spec.Queue)
+}
+
 func TestEqualMinTaskMember(t *testing.T) {
 	t.Run("equal maps", func(t *testing.T) {
 		a := map[string]int32{
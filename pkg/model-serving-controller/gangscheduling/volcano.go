@@ -0,0 +1,287 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gangscheduling
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	volcanoclient "volcano.sh/apis/pkg/client/clientset/versioned"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+// volcanoBackend manages Volcano PodGroups for gang scheduling
+type volcanoBackend struct {
+	volcanoClient volcanoclient.Interface
+}
+
+func newVolcanoBackend(volcanoClient volcanoclient.Interface) *volcanoBackend {
+	return &volcanoBackend{
+		volcanoClient: volcanoClient,
+	}
+}
+
+func (v *volcanoBackend) PodSchedulerName() string {
+	return workloadv1alpha1.SchedulerNameVolcano
+}
+
+// ManagePodGroups manages PodGroups for group-level gang scheduling
+func (v *volcanoBackend) ManagePodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
+	expectedReplicas := servingGroupCount(mi)
+
+	// Get existing PodGroups
+	existingPodGroups, err := v.getExistingPodGroups(ctx, mi)
+	if err != nil {
+		return fmt.Errorf("failed to get existing PodGroups: %v", err)
+	}
+
+	// Create or update PodGroups for each ServingGroup
+	for i := 0; i < expectedReplicas; i++ {
+		podGroupName := generatePodGroupName(mi.Name, i)
+
+		if existingPG, exists := existingPodGroups[podGroupName]; exists {
+			// Update existing PodGroup if needed
+			if err := v.updatePodGroupIfNeeded(ctx, existingPG, mi); err != nil {
+				return fmt.Errorf("failed to update PodGroup %s: %v", podGroupName, err)
+			}
+		} else {
+			// Create new PodGroup
+			if err := v.createPodGroup(ctx, mi, i); err != nil {
+				return fmt.Errorf("failed to create PodGroup %s: %v", podGroupName, err)
+			}
+		}
+	}
+
+	// Clean up excess PodGroups
+	return v.cleanupExcessPodGroups(ctx, mi, existingPodGroups, expectedReplicas)
+}
+
+// createPodGroup creates a PodGroup for group-level gang scheduling
+func (v *volcanoBackend) createPodGroup(ctx context.Context, mi *workloadv1alpha1.ModelServing, groupIndex int) error {
+	podGroupName := generatePodGroupName(mi.Name, groupIndex)
+
+	// Calculate total pods and resources for this ServingGroup
+	minMember, minTaskMember, minResources := calculateRequirements(mi)
+
+	podGroup := &schedulingv1beta1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podGroupName,
+			Namespace: mi.Namespace,
+			Labels:    podGroupLabels(mi, podGroupName),
+			Annotations: map[string]string{
+				schedulingv1beta1.KubeGroupNameAnnotationKey: podGroupName,
+			},
+			OwnerReferences: buildOwnerReference(mi),
+		},
+		Spec: schedulingv1beta1.PodGroupSpec{
+			MinMember:       int32(minMember),
+			MinTaskMember:   minTaskMember,
+			MinResources:    &minResources,
+			NetworkTopology: mi.Spec.Template.NetworkTopology,
+			// The queue is set only at creation, Volcano does not move a PodGroup between queues
+			Queue:             mi.Annotations[schedulingv1beta1.QueueNameAnnotationKey],
+			PriorityClassName: podGroupPriorityClass(mi),
+		},
+	}
+
+	_, err := v.volcanoClient.SchedulingV1beta1().PodGroups(mi.Namespace).Create(ctx, podGroup, metav1.CreateOptions{FieldManager: utils.FieldManager})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	klog.V(2).Infof("Created PodGroup %s for group-level gang scheduling", podGroupName)
+	return nil
+}
+
+// getExistingPodGroups gets existing PodGroups for a ModelServing
+func (v *volcanoBackend) getExistingPodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) (map[string]*schedulingv1beta1.PodGroup, error) {
+	selector := labels.SelectorFromSet(map[string]string{
+		workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
+	})
+
+	podGroupList, err := v.volcanoClient.SchedulingV1beta1().PodGroups(mi.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*schedulingv1beta1.PodGroup)
+	for i := range podGroupList.Items {
+		pg := &podGroupList.Items[i]
+		result[pg.Name] = pg
+	}
+
+	return result, nil
+}
+
+// updatePodGroupIfNeeded updates a PodGroup if needed for group-level scheduling. The PodGroup is read again when
+// the scheduler has updated it in the meantime.
+func (v *volcanoBackend) updatePodGroupIfNeeded(ctx context.Context, existing *schedulingv1beta1.PodGroup, mi *workloadv1alpha1.ModelServing) error {
+	return utils.RetryOnConflict(existing, func() (*schedulingv1beta1.PodGroup, error) {
+		return v.volcanoClient.SchedulingV1beta1().PodGroups(existing.Namespace).Get(ctx, existing.Name, metav1.GetOptions{})
+	}, func(latest *schedulingv1beta1.PodGroup) error {
+		return v.updatePodGroup(ctx, latest, mi)
+	})
+}
+
+func (v *volcanoBackend) updatePodGroup(ctx context.Context, existing *schedulingv1beta1.PodGroup, mi *workloadv1alpha1.ModelServing) error {
+	// Calculate current requirements
+	minMember, minTaskMember, minResources := calculateRequirements(mi)
+
+	needsUpdate := false
+	updated := existing.DeepCopy()
+
+	// Check if MinMember needs update
+	if updated.Spec.MinMember != int32(minMember) {
+		updated.Spec.MinMember = int32(minMember)
+		needsUpdate = true
+	}
+
+	// Check if MinTaskMember needs update
+	if !equalMinTaskMember(updated.Spec.MinTaskMember, minTaskMember) {
+		updated.Spec.MinTaskMember = minTaskMember
+		needsUpdate = true
+	}
+
+	// Check if MinResources needs update
+	if !equalResourceList(updated.Spec.MinResources, &minResources) {
+		updated.Spec.MinResources = &minResources
+		needsUpdate = true
+	}
+
+	if !equalVolcanoNetworkTopology(updated.Spec.NetworkTopology, mi.Spec.Template.NetworkTopology) {
+		updated.Spec.NetworkTopology = mi.Spec.Template.NetworkTopology
+		needsUpdate = true
+	}
+
+	if priorityClassName := podGroupPriorityClass(mi); updated.Spec.PriorityClassName != priorityClassName {
+		updated.Spec.PriorityClassName = priorityClassName
+		needsUpdate = true
+	}
+
+	if needsUpdate {
+		_, err := v.volcanoClient.SchedulingV1beta1().PodGroups(mi.Namespace).Update(ctx, updated, metav1.UpdateOptions{FieldManager: utils.FieldManager})
+		if err != nil {
+			return err
+		}
+		klog.V(2).Infof("Updated PodGroup %s for group-level gang scheduling", existing.Name)
+	}
+
+	return nil
+}
+
+// cleanupExcessPodGroups cleans up excess PodGroups
+func (v *volcanoBackend) cleanupExcessPodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing, existingPodGroups map[string]*schedulingv1beta1.PodGroup, expectedReplicas int) error {
+	for podGroupName, podGroup := range existingPodGroups {
+		if !isPodGroupNeeded(mi, podGroupName, expectedReplicas) {
+			err := v.volcanoClient.SchedulingV1beta1().PodGroups(mi.Namespace).Delete(ctx, podGroup.Name, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete excess PodGroup %s: %v", podGroup.Name, err)
+			}
+			klog.V(2).Infof("Deleted excess PodGroup %s", podGroup.Name)
+		}
+	}
+
+	return nil
+}
+
+// CleanupPodGroups cleans up all PodGroups for a ModelServing
+func (v *volcanoBackend) CleanupPodGroups(ctx context.Context, mi *workloadv1alpha1.ModelServing) error {
+	existingPodGroups, err := v.getExistingPodGroups(ctx, mi)
+	if err != nil {
+		return fmt.Errorf("failed to get existing PodGroups for cleanup: %v", err)
+	}
+
+	for _, podGroup := range existingPodGroups {
+		err := v.volcanoClient.SchedulingV1beta1().PodGroups(mi.Namespace).Delete(ctx, podGroup.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete PodGroup %s: %v", podGroup.Name, err)
+		}
+		klog.V(2).Infof("Deleted PodGroup %s (gang scheduling disabled)", podGroup.Name)
+	}
+
+	return nil
+}
+
+// AnnotatePod annotates a pod with the Volcano PodGroup and task
+func (v *volcanoBackend) AnnotatePod(pod *corev1.Pod, mi *workloadv1alpha1.ModelServing, groupName, taskName string) {
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+
+	// Add volcano annotation
+	pod.Annotations[schedulingv1beta1.KubeGroupNameAnnotationKey] = groupName
+	pod.Annotations[batchv1alpha1.TaskSpecKey] = taskName
+}
+
+// podGroupPriorityClass returns the priority class of the PodGroups of the ServingGroups, which Volcano uses to
+// order and preempt them: the one of the ServingGroup, or else the one shared by all the roles.
+func podGroupPriorityClass(mi *workloadv1alpha1.ModelServing) string {
+	if mi.Spec.Template.PriorityClassName != "" {
+		return mi.Spec.Template.PriorityClassName
+	}
+	priorityClassName := ""
+	for i, role := range mi.Spec.Template.Roles {
+		if i > 0 && role.PriorityClassName != priorityClassName {
+			return ""
+		}
+		priorityClassName = role.PriorityClassName
+	}
+	return priorityClassName
+}
+
+// equalMinTaskMember compares two MinTaskMember maps
+func equalMinTaskMember(a, b map[string]int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, valueA := range a {
+		if valueB, exists := b[key]; !exists || valueA != valueB {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equalVolcanoNetworkTopology compares two volcano NetworkTopologySpec pointers for equality
+func equalVolcanoNetworkTopology(a, b *schedulingv1beta1.NetworkTopologySpec) bool {
+	// If both are nil, they are equal
+	if a == nil && b == nil {
+		return true
+	}
+
+	// If one is nil and the other is not, they are not equal
+	if a == nil || b == nil {
+		return false
+	}
+
+	// Both are non-nil, compare their values
+	return a.Mode == b.Mode &&
+		a.HighestTierAllowed == b.HighestTierAllowed
+}
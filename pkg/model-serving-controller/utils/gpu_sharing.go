@@ -0,0 +1,115 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+const (
+	// NVIDIAGPUResource is the resource of the whole GPUs advertised by the NVIDIA device plugin.
+	NVIDIAGPUResource corev1.ResourceName = "nvidia.com/gpu"
+	// NVIDIASharedGPUResource is the resource of the time-sliced GPUs, when the device plugin renames them.
+	NVIDIASharedGPUResource corev1.ResourceName = "nvidia.com/gpu.shared"
+	// NVIDIAMIGResourcePrefix prefixes the profile of the MIG instances advertised with the mixed MIG strategy.
+	NVIDIAMIGResourcePrefix = "nvidia.com/mig-"
+
+	// MIGStrategyLabelKey is the node label of the MIG strategy set by the GPU feature discovery.
+	MIGStrategyLabelKey = "nvidia.com/mig.strategy"
+	// SharingStrategyLabelKey is the node label of the GPU sharing strategy set by the GPU feature discovery.
+	SharingStrategyLabelKey = "nvidia.com/gpu.sharing-strategy"
+)
+
+// AcceleratorResources are the resources counted as the accelerators of a container.
+var AcceleratorResources = []corev1.ResourceName{NVIDIAGPUResource, "amd.com/gpu", "huawei.com/ascend-1980"}
+
+// AcceleratorCount returns the number of accelerators of the container, from its limits or else its requests.
+func AcceleratorCount(container *corev1.Container) int64 {
+	var count int64
+	for _, name := range AcceleratorResources {
+		if quantity, ok := container.Resources.Limits[name]; ok {
+			count += quantity.Value()
+		} else if quantity, ok := container.Resources.Requests[name]; ok {
+			count += quantity.Value()
+		}
+	}
+	return count
+}
+
+// MIGResourceName returns the resource of the MIG instances of the profile.
+func MIGResourceName(profile string) corev1.ResourceName {
+	return corev1.ResourceName(NVIDIAMIGResourcePrefix + profile)
+}
+
+// GPUSharingResourceName returns the resource the GPUs of the role are translated into, and the node label
+// selecting the nodes sharing their GPUs that way. It returns false when the role does not share GPUs.
+func GPUSharingResourceName(sharing *workloadv1alpha1.GPUSharing) (corev1.ResourceName, string, string, bool) {
+	if sharing == nil {
+		return "", "", "", false
+	}
+	switch sharing.Mode {
+	case workloadv1alpha1.GPUSharingMIG:
+		return MIGResourceName(sharing.MIGProfile), MIGStrategyLabelKey, "mixed", true
+	case workloadv1alpha1.GPUSharingTimeSlicing:
+		return NVIDIASharedGPUResource, SharingStrategyLabelKey, "time-slicing", true
+	default:
+		return "", "", "", false
+	}
+}
+
+// TranslateGPUSharing returns the pod spec with the GPUs requested by its containers translated into the
+// resources of the GPU sharing, and a node selector on the nodes sharing their GPUs that way.
+// The spec is not modified, its containers and node selector are copied when translated.
+func TranslateGPUSharing(spec corev1.PodSpec, sharing *workloadv1alpha1.GPUSharing) corev1.PodSpec {
+	resourceName, labelKey, labelValue, ok := GPUSharingResourceName(sharing)
+	if !ok {
+		return spec
+	}
+	spec.Containers = translateGPUResources(spec.Containers, resourceName)
+	spec.InitContainers = translateGPUResources(spec.InitContainers, resourceName)
+	nodeSelector := make(map[string]string, len(spec.NodeSelector)+1)
+	for k, v := range spec.NodeSelector {
+		nodeSelector[k] = v
+	}
+	nodeSelector[labelKey] = labelValue
+	spec.NodeSelector = nodeSelector
+	return spec
+}
+
+func translateGPUResources(containers []corev1.Container, resourceName corev1.ResourceName) []corev1.Container {
+	if containers == nil {
+		return nil
+	}
+	translated := make([]corev1.Container, len(containers))
+	for i := range containers {
+		containers[i].DeepCopyInto(&translated[i])
+		renameResource(translated[i].Resources.Limits, resourceName)
+		renameResource(translated[i].Resources.Requests, resourceName)
+	}
+	return translated
+}
+
+func renameResource(resources corev1.ResourceList, resourceName corev1.ResourceName) {
+	quantity, ok := resources[NVIDIAGPUResource]
+	if !ok {
+		return
+	}
+	delete(resources, NVIDIAGPUResource)
+	resources[resourceName] = quantity
+}
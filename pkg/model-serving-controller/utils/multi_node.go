@@ -0,0 +1,117 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+const (
+	// DefaultMasterPort is the port of the distributed engine rendezvous, the default port of torch.distributed.
+	DefaultMasterPort = 29500
+	// podInterface is the interface of the pod network in the pods not using the host network.
+	podInterface = "eth0"
+
+	vllmHostIPEnv      = "VLLM_HOST_IP"
+	ncclSocketIfaceEnv = "NCCL_SOCKET_IFNAME"
+	glooSocketIfaceEnv = "GLOO_SOCKET_IFNAME"
+)
+
+// createMultiNodeEnvVars returns the environment of the distributed inference engines, such as vLLM with Ray and
+// SGLang, for a pod of a role with workers. The entry pod has the rank 0.
+func createMultiNodeEnvVars(role workloadv1alpha1.Role, pod, entryPod *corev1.Pod, workerIndex int) []corev1.EnvVar {
+	if role.WorkerReplicas <= 0 {
+		return nil
+	}
+	masterAddr := entryPod.GetName() + "." + entryPod.Namespace
+	masterPort := strconv.Itoa(DefaultMasterPort)
+	envVars := []corev1.EnvVar{
+		{Name: workloadv1alpha1.MasterAddrEnv, Value: masterAddr},
+		{Name: workloadv1alpha1.MasterPortEnv, Value: masterPort},
+		{Name: workloadv1alpha1.DistInitAddrEnv, Value: masterAddr + ":" + masterPort},
+		{Name: workloadv1alpha1.NNodesEnv, Value: strconv.Itoa(int(role.WorkerReplicas) + 1)},
+		{Name: workloadv1alpha1.NodeRankEnv, Value: strconv.Itoa(workerIndex)},
+		{
+			// vLLM advertises this address to the other nodes, it must be reachable from them
+			Name: vllmHostIPEnv,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+			},
+		},
+	}
+	if worldSize := roleWorldSize(role); worldSize > 0 {
+		envVars = append(envVars, corev1.EnvVar{Name: workloadv1alpha1.WorldSizeEnv, Value: strconv.FormatInt(worldSize, 10)})
+	}
+	// The interfaces of the host network are unknown, NCCL and Gloo pick them in that case
+	if !pod.Spec.HostNetwork {
+		envVars = append(envVars,
+			corev1.EnvVar{Name: ncclSocketIfaceEnv, Value: podInterface},
+			corev1.EnvVar{Name: glooSocketIfaceEnv, Value: podInterface},
+		)
+	}
+	return envVars
+}
+
+// roleWorldSize returns the number of accelerators of a replica of the role, 0 when a pod requests none.
+func roleWorldSize(role workloadv1alpha1.Role) int64 {
+	entry := podAcceleratorCount(&role.EntryTemplate.Spec)
+	if entry == 0 || role.WorkerTemplate == nil {
+		return 0
+	}
+	worker := podAcceleratorCount(&role.WorkerTemplate.Spec)
+	if worker == 0 {
+		return 0
+	}
+	return entry + int64(role.WorkerReplicas)*worker
+}
+
+func podAcceleratorCount(spec *corev1.PodSpec) int64 {
+	var count int64
+	for i := range spec.Containers {
+		count += AcceleratorCount(&spec.Containers[i])
+	}
+	return count
+}
+
+// addPodDefaultEnvVars adds the env vars to the containers of the pod which do not set them already.
+func addPodDefaultEnvVars(pod *corev1.Pod, envVars ...corev1.EnvVar) {
+	if len(envVars) == 0 {
+		return
+	}
+	for i := range pod.Spec.Containers {
+		addDefaultEnvVars(&pod.Spec.Containers[i], envVars...)
+	}
+	for i := range pod.Spec.InitContainers {
+		addDefaultEnvVars(&pod.Spec.InitContainers[i], envVars...)
+	}
+}
+
+func addDefaultEnvVars(container *corev1.Container, envVars ...corev1.EnvVar) {
+	existing := make(map[string]struct{}, len(container.Env))
+	for _, env := range container.Env {
+		existing[env.Name] = struct{}{}
+	}
+	for _, envVar := range envVars {
+		if _, ok := existing[envVar.Name]; !ok {
+			container.Env = append(container.Env, envVar)
+		}
+	}
+}
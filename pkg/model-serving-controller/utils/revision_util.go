@@ -47,3 +47,29 @@ func RemoveRoleReplicasForRevision(mi *workloadv1alpha1.ModelServing) *workloadv
 	}
 	return Copy
 }
+
+// SpecRevision calculates the revision of the roles without their replicas and the fields which can be
+// updated in place on running pods: the labels and annotations of the templates and the container images.
+// Two revisions of a ModelServing with the same spec revision only differ by in-place updates.
+func SpecRevision(roles []workloadv1alpha1.Role) string {
+	copied := make([]workloadv1alpha1.Role, len(roles))
+	for i := range roles {
+		roles[i].DeepCopyInto(&copied[i])
+		copied[i].Replicas = nil
+		clearInPlaceFields(&copied[i].EntryTemplate)
+		if copied[i].WorkerTemplate != nil {
+			clearInPlaceFields(copied[i].WorkerTemplate)
+		}
+	}
+	return Revision(copied)
+}
+
+func clearInPlaceFields(template *workloadv1alpha1.PodTemplateSpec) {
+	template.Metadata = nil
+	for i := range template.Spec.InitContainers {
+		template.Spec.InitContainers[i].Image = ""
+	}
+	for i := range template.Spec.Containers {
+		template.Spec.Containers[i].Image = ""
+	}
+}
@@ -91,3 +91,29 @@ func TestDeepHashObject(t *testing.T) {
 		t.Errorf("DeepHashObject should produce the same hash for the same object, got %v and %v", firstHash, secondHash)
 	}
 }
+
+func TestSpecRevision(t *testing.T) {
+	role := workloadv1alpha1.Role{
+		Name:          "prefill",
+		Replicas:      &replicas,
+		EntryTemplate: *nginxPodTemplate.DeepCopy(),
+	}
+	revision := SpecRevision([]workloadv1alpha1.Role{role})
+
+	inPlace := *role.DeepCopy()
+	inPlace.Replicas = nil
+	inPlace.EntryTemplate.Metadata = &workloadv1alpha1.Metadata{Labels: map[string]string{"team": "inference"}}
+	inPlace.EntryTemplate.Spec.Containers[0].Image = "nginx:1.25.0"
+	if got := SpecRevision([]workloadv1alpha1.Role{inPlace}); got != revision {
+		t.Errorf("Spec revision should not change with in-place fields, got %s and %s", got, revision)
+	}
+	if role.EntryTemplate.Spec.Containers[0].Image != "nginx:1.14.2" {
+		t.Errorf("SpecRevision should not modify the roles")
+	}
+
+	recreate := *role.DeepCopy()
+	recreate.EntryTemplate.Spec.Containers[0].Command = []string{"nginx", "-g", "daemon off;"}
+	if got := SpecRevision([]workloadv1alpha1.Role{recreate}); got == revision {
+		t.Errorf("Spec revision should change with the container command, got %s", got)
+	}
+}
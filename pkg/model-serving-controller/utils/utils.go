@@ -24,13 +24,18 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	applycorev1 "k8s.io/client-go/applyconfigurations/core/v1"
+	applymetav1 "k8s.io/client-go/applyconfigurations/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 
@@ -40,6 +45,13 @@ import (
 const (
 	Entry = "true"
 
+	// FieldManager owns the fields of the objects written by the ModelServing controller
+	FieldManager = "kthena-model-serving-controller"
+
+	// InPlaceUpdateRestartsAnnotationKey records on a pod the restart counts its containers reach when they are
+	// restarted by the in-place updates of their images, as a JSON object of the container names to the counts.
+	InPlaceUpdateRestartsAnnotationKey = "modelserving.volcano.sh/in-place-update-restarts"
+
 	// condition status of ModelServingStatus
 	AllGroupsIsReady         = "All Serving groups are ready"
 	SomeGroupsAreProgressing = "Some groups is progressing"
@@ -98,11 +110,15 @@ func GenerateEntryPod(role workloadv1alpha1.Role, mi *workloadv1alpha1.ModelServ
 	entryPod := createBasePod(role, mi, entryPodName, groupName, revision, roleIndex)
 	entryPod.ObjectMeta.Labels[workloadv1alpha1.EntryLabelKey] = Entry
 	addPodLabelAndAnnotation(entryPod, role.EntryTemplate.Metadata)
-	entryPod.Spec = role.EntryTemplate.Spec
+	// The template belongs to the informer cache, it is copied before the env of the containers is set
+	entryPod.Spec = TranslateGPUSharing(*role.EntryTemplate.Spec.DeepCopy(), role.GPUSharing)
 	entryPod.Spec.SchedulerName = mi.Spec.SchedulerName
+	applyPriorityClass(entryPod, role, mi)
+	applyRolePlacement(entryPod, role, mi, groupName, roleIndex, true)
 	// Build environment variables into each container of all pod
 	envVars := createCommonEnvVars(role, entryPod, 0)
 	addPodEnvVars(entryPod, envVars...)
+	addPodDefaultEnvVars(entryPod, createMultiNodeEnvVars(role, entryPod, entryPod, 0)...)
 	return entryPod
 }
 
@@ -110,16 +126,20 @@ func GenerateWorkerPod(role workloadv1alpha1.Role, mi *workloadv1alpha1.ModelSer
 	workerPodName := generateWorkerPodName(groupName, GenerateRoleID(role.Name, roleIndex), podIndex)
 	workerPod := createBasePod(role, mi, workerPodName, groupName, revision, roleIndex)
 	addPodLabelAndAnnotation(workerPod, role.WorkerTemplate.Metadata)
-	workerPod.Spec = role.WorkerTemplate.Spec
-	entryPod.Spec.SchedulerName = mi.Spec.SchedulerName
+	// The template belongs to the informer cache, it is copied before the env of the containers is set
+	workerPod.Spec = TranslateGPUSharing(*role.WorkerTemplate.Spec.DeepCopy(), role.GPUSharing)
+	workerPod.Spec.SchedulerName = mi.Spec.SchedulerName
+	applyPriorityClass(workerPod, role, mi)
+	applyRolePlacement(workerPod, role, mi, groupName, roleIndex, false)
 	// Build environment variables into each container of all pod
 	envVars := createCommonEnvVars(role, entryPod, podIndex)
 	addPodEnvVars(workerPod, envVars...)
+	addPodDefaultEnvVars(workerPod, createMultiNodeEnvVars(role, workerPod, entryPod, podIndex)...)
 	return workerPod
 }
 
 func createBasePod(role workloadv1alpha1.Role, mi *workloadv1alpha1.ModelServing, name, groupName, revision string, roleIndex int) *corev1.Pod {
-	return &corev1.Pod{
+	pod := &corev1.Pod{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Pod",
 			APIVersion: "v1",
@@ -133,12 +153,90 @@ func createBasePod(role workloadv1alpha1.Role, mi *workloadv1alpha1.ModelServing
 				workloadv1alpha1.RoleLabelKey:             role.Name,
 				workloadv1alpha1.RoleIDKey:                GenerateRoleID(role.Name, roleIndex),
 				workloadv1alpha1.RevisionLabelKey:         revision,
+				workloadv1alpha1.SpecRevisionLabelKey:     SpecRevision(mi.Spec.Template.Roles),
 			},
 			OwnerReferences: []metav1.OwnerReference{
 				newModelServingOwnerRef(mi),
 			},
 		},
 	}
+	if _, ordinal := GetParentNameAndOrdinal(groupName); IsStandbyServingGroup(mi, ordinal) {
+		pod.Labels[workloadv1alpha1.StandbyLabelKey] = "true"
+	}
+	return pod
+}
+
+// applyPriorityClass sets the priority class of the role, or else of the ServingGroup, or else the provisioning
+// priority class of the ModelServing, to the pod when its template has none.
+func applyPriorityClass(pod *corev1.Pod, role workloadv1alpha1.Role, mi *workloadv1alpha1.ModelServing) {
+	if pod.Spec.PriorityClassName != "" {
+		return
+	}
+	pod.Spec.PriorityClassName = role.PriorityClassName
+	if pod.Spec.PriorityClassName == "" {
+		pod.Spec.PriorityClassName = mi.Spec.Template.PriorityClassName
+	}
+	if pod.Spec.PriorityClassName == "" {
+		pod.Spec.PriorityClassName = mi.Annotations[workloadv1alpha1.ProvisioningPriorityClassAnnotationKey]
+	}
+}
+
+// applyRolePlacement translates the placement of a role into the affinity and topology spread constraints of its pods.
+func applyRolePlacement(pod *corev1.Pod, role workloadv1alpha1.Role, mi *workloadv1alpha1.ModelServing, groupName string, roleIndex int, entry bool) {
+	placement := role.Placement
+	if placement == nil {
+		return
+	}
+
+	if placement.ColocationTopologyKey != "" {
+		affinity := pod.Spec.Affinity
+		if affinity == nil {
+			affinity = &corev1.Affinity{}
+		}
+		if affinity.PodAffinity == nil {
+			affinity.PodAffinity = &corev1.PodAffinity{}
+		}
+		// The first scheduled pod of a role replica matches its own affinity term.
+		affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution, corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
+					workloadv1alpha1.GroupNameLabelKey:        groupName,
+					workloadv1alpha1.RoleIDKey:                GenerateRoleID(role.Name, roleIndex),
+				},
+			},
+			TopologyKey: placement.ColocationTopologyKey,
+		})
+		pod.Spec.Affinity = affinity
+	}
+
+	if entry && len(placement.TopologySpread) > 0 {
+		constraints := make([]corev1.TopologySpreadConstraint, 0, len(pod.Spec.TopologySpreadConstraints)+len(placement.TopologySpread))
+		constraints = append(constraints, pod.Spec.TopologySpreadConstraints...)
+		for _, spread := range placement.TopologySpread {
+			maxSkew := spread.MaxSkew
+			if maxSkew <= 0 {
+				maxSkew = 1
+			}
+			whenUnsatisfiable := spread.WhenUnsatisfiable
+			if whenUnsatisfiable == "" {
+				whenUnsatisfiable = corev1.ScheduleAnyway
+			}
+			constraints = append(constraints, corev1.TopologySpreadConstraint{
+				MaxSkew:           maxSkew,
+				TopologyKey:       spread.TopologyKey,
+				WhenUnsatisfiable: whenUnsatisfiable,
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						workloadv1alpha1.ModelServingNameLabelKey: mi.Name,
+						workloadv1alpha1.RoleLabelKey:             role.Name,
+						workloadv1alpha1.EntryLabelKey:            Entry,
+					},
+				},
+			})
+		}
+		pod.Spec.TopologySpreadConstraints = constraints
+	}
 }
 
 func addPodLabelAndAnnotation(pod *corev1.Pod, metadata *workloadv1alpha1.Metadata) {
@@ -151,6 +249,9 @@ func addPodLabelAndAnnotation(pod *corev1.Pod, metadata *workloadv1alpha1.Metada
 		}
 	}
 	if metadata.Annotations != nil {
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string, len(metadata.Annotations))
+		}
 		for k, v := range metadata.Annotations {
 			pod.Annotations[k] = v
 		}
@@ -224,39 +325,53 @@ func newModelServingOwnerRef(mi *workloadv1alpha1.ModelServing) metav1.OwnerRefe
 	}
 }
 
+// CreateHeadlessService applies the headless service of the worker pods of a role. It is applied server side, so
+// that the fields set by other managers are kept and the service is created when it does not exist.
 func CreateHeadlessService(ctx context.Context, k8sClient kubernetes.Interface, mi *workloadv1alpha1.ModelServing, serviceSelector map[string]string, groupName, roleLabel string, roleIndex int) error {
 	serviceName := generateEntryPodName(groupName, GenerateRoleID(roleLabel, roleIndex))
-	headlessService := corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      serviceName,
-			Namespace: mi.Namespace,
-			OwnerReferences: []metav1.OwnerReference{
-				newModelServingOwnerRef(mi),
-			},
-			Labels: map[string]string{
-				workloadv1alpha1.GroupNameLabelKey: groupName,
-				workloadv1alpha1.RoleLabelKey:      roleLabel,
-				workloadv1alpha1.RoleIDKey:         GenerateRoleID(roleLabel, roleIndex),
-			},
-		},
-		Spec: corev1.ServiceSpec{
-			ClusterIP:                "None", // defines service as headless
-			Selector:                 serviceSelector,
-			PublishNotReadyAddresses: true,
-		},
-	}
-	// create the service in the cluster
-	klog.V(4).Infof("Creating headless service %s", headlessService.Name)
-	_, err := k8sClient.CoreV1().Services(mi.Namespace).Create(ctx, &headlessService, metav1.CreateOptions{})
-
+	ownerRef := newModelServingOwnerRef(mi)
+	headlessService := applycorev1.Service(serviceName, mi.Namespace).
+		WithOwnerReferences(applymetav1.OwnerReference().
+			WithAPIVersion(ownerRef.APIVersion).
+			WithKind(ownerRef.Kind).
+			WithName(ownerRef.Name).
+			WithUID(ownerRef.UID).
+			WithBlockOwnerDeletion(true).
+			WithController(true)).
+		WithLabels(map[string]string{
+			workloadv1alpha1.GroupNameLabelKey: groupName,
+			workloadv1alpha1.RoleLabelKey:      roleLabel,
+			workloadv1alpha1.RoleIDKey:         GenerateRoleID(roleLabel, roleIndex),
+		}).
+		WithSpec(applycorev1.ServiceSpec().
+			WithClusterIP("None"). // defines service as headless
+			WithSelector(serviceSelector).
+			WithPublishNotReadyAddresses(true))
+	// apply the service in the cluster
+	klog.V(4).Infof("Applying headless service %s", serviceName)
+	_, err := k8sClient.CoreV1().Services(mi.Namespace).Apply(ctx, headlessService, metav1.ApplyOptions{FieldManager: FieldManager, Force: true})
 	if err != nil {
-		if !apierrors.IsAlreadyExists(err) {
-			return fmt.Errorf("create headless service failed: %v", err)
-		}
+		return fmt.Errorf("apply headless service failed: %v", err)
 	}
 	return nil
 }
 
+// RetryOnConflict runs update with the object, and runs it again with the latest object returned by get while the
+// update conflicts with another writer of the object.
+func RetryOnConflict[T any](obj T, get func() (T, error), update func(T) error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := update(obj)
+		if apierrors.IsConflict(err) {
+			latest, getErr := get()
+			if getErr != nil {
+				return getErr
+			}
+			obj = latest
+		}
+		return err
+	})
+}
+
 func GetModelServingAndGroupByLabel(podLabels map[string]string) (string, string, bool) {
 	modelServingName, ok := podLabels[workloadv1alpha1.ModelServingNameLabelKey]
 	if !ok {
@@ -270,8 +385,34 @@ func GetModelServingAndGroupByLabel(podLabels map[string]string) (string, string
 }
 
 // IsPodRunningAndReady returns true if pod is in the PodRunning Phase, if it has a condition of PodReady.
+// A pod whose containers have not been restarted yet by an in-place update is not ready to run the new images.
 func IsPodRunningAndReady(pod *corev1.Pod) bool {
-	return pod.Status.Phase == corev1.PodRunning && isPodReady(pod)
+	return pod.Status.Phase == corev1.PodRunning && isPodReady(pod) && !InPlaceUpdateRestarting(pod)
+}
+
+// InPlaceUpdateRestarting returns true when a container of the pod has not been restarted yet with the image it was
+// updated to in place.
+func InPlaceUpdateRestarting(pod *corev1.Pod) bool {
+	expected := inPlaceUpdateRestarts(pod)
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, status := range statuses {
+			if status.RestartCount < expected[status.Name] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inPlaceUpdateRestarts returns the restart counts the containers of the pod reach with their in-place updates.
+func inPlaceUpdateRestarts(pod *corev1.Pod) map[string]int32 {
+	restarts := map[string]int32{}
+	if value, ok := pod.Annotations[InPlaceUpdateRestartsAnnotationKey]; ok {
+		if err := json.Unmarshal([]byte(value), &restarts); err != nil {
+			klog.Warningf("Invalid annotation %s of pod %s/%s: %v", InPlaceUpdateRestartsAnnotationKey, pod.Namespace, pod.Name, err)
+		}
+	}
+	return restarts
 }
 
 // CheckPodRevision determine if the pod's revision is compliant or not.
@@ -288,6 +429,90 @@ func PodRevision(pod *corev1.Pod) string {
 	return pod.Labels[workloadv1alpha1.RevisionLabelKey]
 }
 
+// PodSpecRevision returns the spec revision label of the pod, empty for the pods created before it was set.
+func PodSpecRevision(pod *corev1.Pod) string {
+	return pod.Labels[workloadv1alpha1.SpecRevisionLabelKey]
+}
+
+// InPlaceUpdatePatch returns the strategic merge patch updating the pod to the template of its role at the revision:
+// the labels and annotations of the template and the images of the containers. The labels and annotations removed
+// from the template are left on the pod.
+func InPlaceUpdatePatch(pod *corev1.Pod, template workloadv1alpha1.PodTemplateSpec, revision string) ([]byte, error) {
+	labels := map[string]string{workloadv1alpha1.RevisionLabelKey: revision}
+	annotations := map[string]string{}
+	if template.Metadata != nil {
+		for k, v := range template.Metadata.Labels {
+			if pod.Labels[k] != v {
+				labels[k] = v
+			}
+		}
+		for k, v := range template.Metadata.Annotations {
+			if pod.Annotations[k] != v {
+				annotations[k] = v
+			}
+		}
+	}
+	spec := map[string]interface{}{}
+	restarts := inPlaceUpdateRestarts(pod)
+	if images := changedImages(pod.Spec.InitContainers, template.Spec.InitContainers); len(images) > 0 {
+		spec["initContainers"] = images
+		addImageRestarts(restarts, images, pod.Status.InitContainerStatuses)
+	}
+	if images := changedImages(pod.Spec.Containers, template.Spec.Containers); len(images) > 0 {
+		spec["containers"] = images
+		addImageRestarts(restarts, images, pod.Status.ContainerStatuses)
+	}
+	if len(spec) > 0 {
+		// The kubelet restarts the containers whose image changed, which must not be taken for failures
+		data, err := json.Marshal(restarts)
+		if err != nil {
+			return nil, err
+		}
+		annotations[InPlaceUpdateRestartsAnnotationKey] = string(data)
+	}
+	metadata := map[string]interface{}{"labels": labels}
+	if len(annotations) > 0 {
+		metadata["annotations"] = annotations
+	}
+	patch := map[string]interface{}{"metadata": metadata}
+	if len(spec) > 0 {
+		patch["spec"] = spec
+	}
+	return json.Marshal(patch)
+}
+
+// InPlaceUpdateRestartsContainers returns true when updating the pod in place to the template restarts containers.
+func InPlaceUpdateRestartsContainers(pod *corev1.Pod, template workloadv1alpha1.PodTemplateSpec) bool {
+	return len(changedImages(pod.Spec.InitContainers, template.Spec.InitContainers)) > 0 ||
+		len(changedImages(pod.Spec.Containers, template.Spec.Containers)) > 0
+}
+
+// addImageRestarts expects one more restart than the current one of each container whose image is changed.
+func addImageRestarts(restarts map[string]int32, images []map[string]string, statuses []corev1.ContainerStatus) {
+	restartCounts := make(map[string]int32, len(statuses))
+	for _, status := range statuses {
+		restartCounts[status.Name] = status.RestartCount
+	}
+	for _, image := range images {
+		restarts[image["name"]] = restartCounts[image["name"]] + 1
+	}
+}
+
+// changedImages returns the containers, merged by name, whose image differs in the template.
+func changedImages(current, desired []corev1.Container) []map[string]string {
+	images := make(map[string]string, len(current))
+	for _, container := range current {
+		images[container.Name] = container.Image
+	}
+	var changed []map[string]string
+	for _, container := range desired {
+		if image, ok := images[container.Name]; ok && image != container.Image {
+			changed = append(changed, map[string]string{"name": container.Name, "image": container.Image})
+		}
+	}
+	return changed
+}
+
 // PodRoleName returns the role name of the pod.
 func PodRoleName(pod *corev1.Pod) string {
 	return pod.Labels[workloadv1alpha1.RoleLabelKey]
@@ -323,6 +548,67 @@ func getPodReadyCondition(status *corev1.PodStatus) *corev1.PodCondition {
 	return nil
 }
 
+// IsPodWaitingForNodeProvisioning returns true if the pod can not be scheduled because no node has
+// enough of the accelerators it requests, which the cluster autoscaler may resolve by provisioning nodes.
+// It also returns the time the pod was marked unschedulable.
+func IsPodWaitingForNodeProvisioning(pod *corev1.Pod) (bool, time.Time) {
+	if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" {
+		return false, time.Time{}
+	}
+	var condition *corev1.PodCondition
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == corev1.PodScheduled {
+			condition = &pod.Status.Conditions[i]
+			break
+		}
+	}
+	if condition == nil || condition.Status != corev1.ConditionFalse || condition.Reason != corev1.PodReasonUnschedulable {
+		return false, time.Time{}
+	}
+	for _, resourceName := range acceleratorResourceNames(pod) {
+		if strings.Contains(condition.Message, "Insufficient "+resourceName) {
+			return true, condition.LastTransitionTime.Time
+		}
+	}
+	return false, time.Time{}
+}
+
+// acceleratorResourceNames returns the extended resources, such as nvidia.com/gpu, requested by the pod.
+func acceleratorResourceNames(pod *corev1.Pod) []string {
+	names := make([]string, 0)
+	seen := make(map[corev1.ResourceName]bool)
+	for _, container := range pod.Spec.Containers {
+		for name := range container.Resources.Limits {
+			if seen[name] || !strings.Contains(string(name), "/") || strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix) {
+				continue
+			}
+			seen[name] = true
+			names = append(names, string(name))
+		}
+	}
+	return names
+}
+
+// volcanoEvictReason is the reason of the pod condition set by Volcano when it evicts a pod, e.g. to preempt it.
+const volcanoEvictReason = "Evict"
+
+// PreemptionMessage returns the reason given by the scheduler when it preempted the pod, either the
+// kube-scheduler or Volcano, and false when the pod was not preempted.
+func PreemptionMessage(pod *corev1.Pod) (string, bool) {
+	for _, condition := range pod.Status.Conditions {
+		preempted := condition.Type == corev1.DisruptionTarget && condition.Status == corev1.ConditionTrue &&
+			condition.Reason == corev1.PodReasonPreemptionByScheduler
+		if !preempted && condition.Reason != volcanoEvictReason {
+			continue
+		}
+		if condition.Message == "" {
+			return condition.Reason, true
+		}
+		return condition.Message, true
+	}
+	return "", false
+}
+
 // IsPodTerminating returns true if pod's DeletionTimestamp has been set
 func IsPodTerminating(pod *corev1.Pod) bool {
 	return pod.DeletionTimestamp != nil
@@ -333,6 +619,29 @@ func IsPodFailed(pod *corev1.Pod) bool {
 	return pod.Status.Phase == corev1.PodFailed
 }
 
+// ServingGroupCount returns the number of ServingGroups of the ModelServing, including the standby ones.
+func ServingGroupCount(mi *workloadv1alpha1.ModelServing) int {
+	count := int(*mi.Spec.Replicas)
+	if mi.Spec.StandbyReplicas != nil {
+		count += int(*mi.Spec.StandbyReplicas)
+	}
+	return count
+}
+
+// IsStandbyServingGroup returns true if the ServingGroup with the given ordinal is a standby ServingGroup.
+// The ServingGroups with an ordinal at or above spec.replicas are kept warm as standby.
+func IsStandbyServingGroup(mi *workloadv1alpha1.ModelServing, ordinal int) bool {
+	if mi.Spec.Replicas == nil {
+		return false
+	}
+	return ordinal >= int(*mi.Spec.Replicas)
+}
+
+// IsStandbyPod returns true if the pod is labeled as a member of a standby ServingGroup.
+func IsStandbyPod(pod *corev1.Pod) bool {
+	return pod.Labels[workloadv1alpha1.StandbyLabelKey] == "true"
+}
+
 func ExpectedPodNum(mi *workloadv1alpha1.ModelServing) int {
 	num := 0
 	for _, role := range mi.Spec.Template.Roles {
@@ -343,18 +652,20 @@ func ExpectedPodNum(mi *workloadv1alpha1.ModelServing) int {
 	return num
 }
 
-// ContainerRestarted return true when there is any container in the pod that gets restarted
+// ContainerRestarted return true when there is any container in the pod that gets restarted,
+// apart from the restarts of the in-place updates of its images.
 func ContainerRestarted(pod *corev1.Pod) bool {
 	if pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodPending {
+		expected := inPlaceUpdateRestarts(pod)
 		for j := range pod.Status.InitContainerStatuses {
 			stat := pod.Status.InitContainerStatuses[j]
-			if stat.RestartCount > 0 {
+			if stat.RestartCount > expected[stat.Name] {
 				return true
 			}
 		}
 		for j := range pod.Status.ContainerStatuses {
 			stat := pod.Status.ContainerStatuses[j]
-			if stat.RestartCount > 0 {
+			if stat.RestartCount > expected[stat.Name] {
 				return true
 			}
 		}
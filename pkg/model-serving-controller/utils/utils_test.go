@@ -17,10 +17,18 @@ limitations under the License.
 package utils
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
 
 	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
 )
@@ -97,3 +105,487 @@ func TestSetCondition(t *testing.T) {
 		assert.Contains(t, cond.Message, SomeGroupsAreProgressing)
 	})
 }
+
+func TestIsPodWaitingForNodeProvisioning(t *testing.T) {
+	transitionTime := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	newPod := func(phase corev1.PodPhase, message string) *corev1.Pod {
+		return &corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:                    resource.MustParse("8"),
+								corev1.ResourceName("nvidia.com/gpu"): resource.MustParse("8"),
+							},
+						},
+					},
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: phase,
+				Conditions: []corev1.PodCondition{
+					{
+						Type:               corev1.PodScheduled,
+						Status:             corev1.ConditionFalse,
+						Reason:             corev1.PodReasonUnschedulable,
+						Message:            message,
+						LastTransitionTime: transitionTime,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		pod     *corev1.Pod
+		waiting bool
+	}{
+		{
+			name:    "insufficient gpu",
+			pod:     newPod(corev1.PodPending, "0/3 nodes are available: 3 Insufficient nvidia.com/gpu."),
+			waiting: true,
+		},
+		{
+			name:    "insufficient cpu only",
+			pod:     newPod(corev1.PodPending, "0/3 nodes are available: 3 Insufficient cpu."),
+			waiting: false,
+		},
+		{
+			name:    "running pod",
+			pod:     newPod(corev1.PodRunning, "0/3 nodes are available: 3 Insufficient nvidia.com/gpu."),
+			waiting: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waiting, since := IsPodWaitingForNodeProvisioning(tt.pod)
+			assert.Equal(t, tt.waiting, waiting)
+			if tt.waiting {
+				assert.Equal(t, transitionTime.Time, since)
+			}
+		})
+	}
+}
+
+func TestGeneratePodsWithRolePlacement(t *testing.T) {
+	templateAffinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "gpu", Operator: corev1.NodeSelectorOpExists},
+					},
+				}},
+			},
+		},
+	}
+	role := workloadv1alpha1.Role{
+		Name:           "decode",
+		WorkerReplicas: 1,
+		EntryTemplate: workloadv1alpha1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Affinity: templateAffinity},
+		},
+		WorkerTemplate: &workloadv1alpha1.PodTemplateSpec{},
+		Placement: &workloadv1alpha1.RolePlacement{
+			ColocationTopologyKey: "nvidia.com/gpu.clique",
+			TopologySpread: []workloadv1alpha1.RoleTopologySpread{
+				{TopologyKey: "topology.kubernetes.io/zone"},
+			},
+		},
+	}
+	mi := &workloadv1alpha1.ModelServing{
+		ObjectMeta: metav1.ObjectMeta{Name: "llm", Namespace: "default"},
+	}
+
+	entryPod := GenerateEntryPod(role, mi, "llm-0", 1, "rev")
+	workerPod := GenerateWorkerPod(role, mi, entryPod, "llm-0", 1, 1, "rev")
+
+	wantAffinityTerm := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				workloadv1alpha1.ModelServingNameLabelKey: "llm",
+				workloadv1alpha1.GroupNameLabelKey:        "llm-0",
+				workloadv1alpha1.RoleIDKey:                "decode-1",
+			},
+		},
+		TopologyKey: "nvidia.com/gpu.clique",
+	}
+	for _, pod := range []*corev1.Pod{entryPod, workerPod} {
+		assert.NotNil(t, pod.Spec.Affinity)
+		assert.Equal(t, []corev1.PodAffinityTerm{wantAffinityTerm}, pod.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	}
+	// The node affinity of the template is kept, and the template itself is left untouched
+	assert.Equal(t, templateAffinity.NodeAffinity, entryPod.Spec.Affinity.NodeAffinity)
+	assert.Nil(t, templateAffinity.PodAffinity)
+
+	assert.Equal(t, []corev1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       "topology.kubernetes.io/zone",
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				workloadv1alpha1.ModelServingNameLabelKey: "llm",
+				workloadv1alpha1.RoleLabelKey:             "decode",
+				workloadv1alpha1.EntryLabelKey:            Entry,
+			},
+		},
+	}}, entryPod.Spec.TopologySpreadConstraints)
+	assert.Empty(t, workerPod.Spec.TopologySpreadConstraints)
+}
+
+func TestGeneratePodsWithGPUSharing(t *testing.T) {
+	gpus := corev1.ResourceList{NVIDIAGPUResource: resource.MustParse("2"), corev1.ResourceCPU: resource.MustParse("4")}
+	template := workloadv1alpha1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"pool": "inference"},
+			Containers: []corev1.Container{{
+				Name:      "engine",
+				Resources: corev1.ResourceRequirements{Limits: gpus.DeepCopy(), Requests: gpus.DeepCopy()},
+			}},
+		},
+	}
+	mi := &workloadv1alpha1.ModelServing{
+		ObjectMeta: metav1.ObjectMeta{Name: "llm", Namespace: "default"},
+	}
+
+	tests := []struct {
+		name             string
+		sharing          *workloadv1alpha1.GPUSharing
+		wantResource     corev1.ResourceName
+		wantNodeSelector map[string]string
+	}{
+		{
+			name:             "no sharing",
+			wantResource:     NVIDIAGPUResource,
+			wantNodeSelector: map[string]string{"pool": "inference"},
+		},
+		{
+			name:             "MIG",
+			sharing:          &workloadv1alpha1.GPUSharing{Mode: workloadv1alpha1.GPUSharingMIG, MIGProfile: "1g.10gb"},
+			wantResource:     "nvidia.com/mig-1g.10gb",
+			wantNodeSelector: map[string]string{"pool": "inference", MIGStrategyLabelKey: "mixed"},
+		},
+		{
+			name:             "time-slicing",
+			sharing:          &workloadv1alpha1.GPUSharing{Mode: workloadv1alpha1.GPUSharingTimeSlicing},
+			wantResource:     NVIDIASharedGPUResource,
+			wantNodeSelector: map[string]string{"pool": "inference", SharingStrategyLabelKey: "time-slicing"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role := workloadv1alpha1.Role{
+				Name:           "decode",
+				WorkerReplicas: 1,
+				EntryTemplate:  *template.DeepCopy(),
+				WorkerTemplate: template.DeepCopy(),
+				GPUSharing:     tt.sharing,
+			}
+			entryPod := GenerateEntryPod(role, mi, "llm-0", 0, "rev")
+			workerPod := GenerateWorkerPod(role, mi, entryPod, "llm-0", 0, 1, "rev")
+			for _, pod := range []*corev1.Pod{entryPod, workerPod} {
+				resources := pod.Spec.Containers[0].Resources
+				assert.Equal(t, resource.MustParse("2"), resources.Limits[tt.wantResource])
+				assert.Equal(t, resource.MustParse("2"), resources.Requests[tt.wantResource])
+				assert.Equal(t, resource.MustParse("4"), resources.Limits[corev1.ResourceCPU])
+				assert.Equal(t, tt.wantNodeSelector, pod.Spec.NodeSelector)
+			}
+			// The templates are left untouched
+			assert.Equal(t, template.Spec, role.EntryTemplate.Spec)
+			assert.Equal(t, template.Spec, role.WorkerTemplate.Spec)
+		})
+	}
+}
+
+func TestGeneratePodsWithMultiNodeEnv(t *testing.T) {
+	gpus := corev1.ResourceList{NVIDIAGPUResource: resource.MustParse("8")}
+	template := workloadv1alpha1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:      "engine",
+				Env:       []corev1.EnvVar{{Name: "MASTER_PORT", Value: "5000"}},
+				Resources: corev1.ResourceRequirements{Limits: gpus},
+			}},
+		},
+	}
+	role := workloadv1alpha1.Role{
+		Name:           "405b",
+		WorkerReplicas: 2,
+		EntryTemplate:  *template.DeepCopy(),
+		WorkerTemplate: template.DeepCopy(),
+	}
+	mi := &workloadv1alpha1.ModelServing{
+		ObjectMeta: metav1.ObjectMeta{Name: "llm", Namespace: "default"},
+	}
+
+	entryPod := GenerateEntryPod(role, mi, "llm-0", 0, "rev")
+	workerPod := GenerateWorkerPod(role, mi, entryPod, "llm-0", 0, 2, "rev")
+
+	envOf := func(pod *corev1.Pod) map[string]corev1.EnvVar {
+		env := make(map[string]corev1.EnvVar)
+		for _, envVar := range pod.Spec.Containers[0].Env {
+			env[envVar.Name] = envVar
+		}
+		return env
+	}
+	for rank, pod := range map[string]*corev1.Pod{"0": entryPod, "2": workerPod} {
+		env := envOf(pod)
+		assert.Equal(t, "llm-0-405b-0-0.default", env[workloadv1alpha1.MasterAddrEnv].Value)
+		// The env of the template wins
+		assert.Equal(t, "5000", env[workloadv1alpha1.MasterPortEnv].Value)
+		assert.Equal(t, "llm-0-405b-0-0.default:29500", env[workloadv1alpha1.DistInitAddrEnv].Value)
+		assert.Equal(t, "3", env[workloadv1alpha1.NNodesEnv].Value)
+		assert.Equal(t, rank, env[workloadv1alpha1.NodeRankEnv].Value)
+		assert.Equal(t, "24", env[workloadv1alpha1.WorldSizeEnv].Value)
+		assert.Equal(t, "status.podIP", env["VLLM_HOST_IP"].ValueFrom.FieldRef.FieldPath)
+		assert.Equal(t, "eth0", env["NCCL_SOCKET_IFNAME"].Value)
+		assert.Equal(t, "eth0", env["GLOO_SOCKET_IFNAME"].Value)
+	}
+	// The template is left untouched
+	assert.Equal(t, template.Spec.Containers[0].Env, role.EntryTemplate.Spec.Containers[0].Env)
+
+	t.Run("host network", func(t *testing.T) {
+		role := role
+		role.EntryTemplate = *template.DeepCopy()
+		role.EntryTemplate.Spec.HostNetwork = true
+		env := envOf(GenerateEntryPod(role, mi, "llm-0", 0, "rev"))
+		assert.NotContains(t, env, "NCCL_SOCKET_IFNAME")
+		assert.NotContains(t, env, "GLOO_SOCKET_IFNAME")
+	})
+
+	t.Run("workers without accelerators", func(t *testing.T) {
+		role := role
+		role.WorkerTemplate = &workloadv1alpha1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "ray"}}}}
+		env := envOf(GenerateEntryPod(role, mi, "llm-0", 0, "rev"))
+		assert.NotContains(t, env, workloadv1alpha1.WorldSizeEnv)
+		assert.Contains(t, env, workloadv1alpha1.NodeRankEnv)
+	})
+
+	t.Run("single node role", func(t *testing.T) {
+		role := workloadv1alpha1.Role{Name: "decode", EntryTemplate: *template.DeepCopy()}
+		env := envOf(GenerateEntryPod(role, mi, "llm-0", 0, "rev"))
+		assert.NotContains(t, env, workloadv1alpha1.MasterAddrEnv)
+		assert.NotContains(t, env, workloadv1alpha1.NodeRankEnv)
+	})
+}
+
+func TestGeneratePodsWithPriorityClass(t *testing.T) {
+	role := workloadv1alpha1.Role{
+		Name:           "decode",
+		WorkerReplicas: 1,
+		WorkerTemplate: &workloadv1alpha1.PodTemplateSpec{Spec: corev1.PodSpec{PriorityClassName: "template"}},
+	}
+	mi := &workloadv1alpha1.ModelServing{
+		ObjectMeta: metav1.ObjectMeta{Name: "llm", Namespace: "default"},
+	}
+	mi.Spec.Template.PriorityClassName = "online"
+
+	entryPod := GenerateEntryPod(role, mi, "llm-0", 0, "rev")
+	assert.Equal(t, "online", entryPod.Spec.PriorityClassName)
+	// The priority class of the template wins
+	assert.Equal(t, "template", GenerateWorkerPod(role, mi, entryPod, "llm-0", 0, 1, "rev").Spec.PriorityClassName)
+
+	role.PriorityClassName = "batch"
+	assert.Equal(t, "batch", GenerateEntryPod(role, mi, "llm-0", 0, "rev").Spec.PriorityClassName)
+
+	// The provisioning priority class applies to the pods without any other
+	mi.Annotations = map[string]string{workloadv1alpha1.ProvisioningPriorityClassAnnotationKey: "provisioning"}
+	assert.Equal(t, "batch", GenerateEntryPod(role, mi, "llm-0", 0, "rev").Spec.PriorityClassName)
+	role.PriorityClassName = ""
+	mi.Spec.Template.PriorityClassName = ""
+	assert.Equal(t, "provisioning", GenerateEntryPod(role, mi, "llm-0", 0, "rev").Spec.PriorityClassName)
+}
+
+func TestPreemptionMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		conditions  []corev1.PodCondition
+		wantMessage string
+		wantOK      bool
+	}{
+		{
+			name: "preempted by the kube-scheduler",
+			conditions: []corev1.PodCondition{{
+				Type:    corev1.DisruptionTarget,
+				Status:  corev1.ConditionTrue,
+				Reason:  corev1.PodReasonPreemptionByScheduler,
+				Message: "default-scheduler: preempting to accommodate a higher priority pod",
+			}},
+			wantMessage: "default-scheduler: preempting to accommodate a higher priority pod",
+			wantOK:      true,
+		},
+		{
+			name:        "evicted by volcano",
+			conditions:  []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse, Reason: "Evict"}},
+			wantMessage: "Evict",
+			wantOK:      true,
+		},
+		{
+			name: "evicted by the kubelet",
+			conditions: []corev1.PodCondition{{
+				Type:   corev1.DisruptionTarget,
+				Status: corev1.ConditionTrue,
+				Reason: corev1.PodReasonTerminationByKubelet,
+			}},
+		},
+		{
+			name: "no longer a disruption target",
+			conditions: []corev1.PodCondition{{
+				Type:   corev1.DisruptionTarget,
+				Status: corev1.ConditionFalse,
+				Reason: corev1.PodReasonPreemptionByScheduler,
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, ok := PreemptionMessage(&corev1.Pod{Status: corev1.PodStatus{Conditions: tt.conditions}})
+			assert.Equal(t, tt.wantMessage, message)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestStandbyServingGroups(t *testing.T) {
+	role := workloadv1alpha1.Role{
+		Name:           "decode",
+		WorkerReplicas: 1,
+		WorkerTemplate: &workloadv1alpha1.PodTemplateSpec{},
+	}
+	mi := &workloadv1alpha1.ModelServing{
+		ObjectMeta: metav1.ObjectMeta{Name: "llm", Namespace: "default"},
+		Spec: workloadv1alpha1.ModelServingSpec{
+			Replicas:        ptr.To[int32](2),
+			StandbyReplicas: ptr.To[int32](1),
+		},
+	}
+
+	assert.Equal(t, 3, ServingGroupCount(mi))
+	assert.False(t, IsStandbyServingGroup(mi, 1))
+	assert.True(t, IsStandbyServingGroup(mi, 2))
+
+	activePod := GenerateEntryPod(role, mi, "llm-1", 0, "rev")
+	assert.False(t, IsStandbyPod(activePod))
+	standbyPod := GenerateEntryPod(role, mi, "llm-2", 0, "rev")
+	assert.True(t, IsStandbyPod(standbyPod))
+	assert.True(t, IsStandbyPod(GenerateWorkerPod(role, mi, standbyPod, "llm-2", 0, 1, "rev")))
+
+	mi.Spec.StandbyReplicas = nil
+	assert.Equal(t, 2, ServingGroupCount(mi))
+}
+
+func TestRetryOnConflict(t *testing.T) {
+	var updated []string
+	err := RetryOnConflict("stale", func() (string, error) {
+		return "latest", nil
+	}, func(obj string) error {
+		updated = append(updated, obj)
+		if obj == "stale" {
+			return apierrors.NewConflict(schema.GroupResource{Resource: "pods"}, "pod", nil)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	// The update is run again with the latest object
+	assert.Equal(t, []string{"stale", "latest"}, updated)
+
+	err = RetryOnConflict("stale", func() (string, error) {
+		return "", apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "pod")
+	}, func(obj string) error {
+		return apierrors.NewConflict(schema.GroupResource{Resource: "pods"}, "pod", nil)
+	})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestCreateHeadlessService(t *testing.T) {
+	client := kubefake.NewClientset()
+	mi := &workloadv1alpha1.ModelServing{ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "default", UID: "uid"}}
+	selector := map[string]string{workloadv1alpha1.GroupNameLabelKey: "llama-0"}
+
+	// Applying the service again is a no-op
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, CreateHeadlessService(context.Background(), client, mi, selector, "llama-0", "prefill", 0))
+	}
+
+	services, err := client.CoreV1().Services("default").List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, services.Items, 1)
+	service := services.Items[0]
+	assert.Equal(t, "None", service.Spec.ClusterIP)
+	assert.Equal(t, selector, service.Spec.Selector)
+	assert.True(t, metav1.IsControlledBy(&service, mi))
+	assert.Equal(t, FieldManager, service.ManagedFields[0].Manager)
+}
+
+func TestInPlaceUpdatePatch(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{workloadv1alpha1.RevisionLabelKey: "old", "team": "inference"},
+			Annotations: map[string]string{"owner": "ml"},
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "proxy", Image: "proxy:v1"}},
+			Containers:     []corev1.Container{{Name: "engine", Image: "vllm:v1"}, {Name: "exporter", Image: "exporter:v1"}},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "engine", RestartCount: 1}, {Name: "exporter", RestartCount: 2}},
+		},
+	}
+	template := workloadv1alpha1.PodTemplateSpec{
+		Metadata: &workloadv1alpha1.Metadata{
+			Labels:      map[string]string{"team": "inference", "tier": "gold"},
+			Annotations: map[string]string{"owner": "ml"},
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "proxy", Image: "proxy:v2"}},
+			Containers:     []corev1.Container{{Name: "engine", Image: "vllm:v1"}, {Name: "exporter", Image: "exporter:v2"}},
+		},
+	}
+
+	patch, err := InPlaceUpdatePatch(pod, template, "new")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"metadata": {
+			"labels": {"modelserving.volcano.sh/revision": "new", "tier": "gold"},
+			"annotations": {"modelserving.volcano.sh/in-place-update-restarts": "{\"exporter\":3,\"proxy\":1}"}
+		},
+		"spec": {
+			"initContainers": [{"name": "proxy", "image": "proxy:v2"}],
+			"containers": [{"name": "exporter", "image": "exporter:v2"}]
+		}
+	}`, string(patch))
+}
+
+func TestInPlaceUpdateRestarts(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{InPlaceUpdateRestartsAnnotationKey: `{"engine":2}`},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "engine", RestartCount: 1}, {Name: "exporter"}},
+		},
+	}
+
+	// The engine has not been restarted with its new image yet
+	assert.True(t, InPlaceUpdateRestarting(pod))
+	assert.False(t, IsPodRunningAndReady(pod))
+	assert.False(t, ContainerRestarted(pod))
+
+	// The restart of the in-place update is not a failure
+	pod.Status.ContainerStatuses[0].RestartCount = 2
+	assert.False(t, InPlaceUpdateRestarting(pod))
+	assert.True(t, IsPodRunningAndReady(pod))
+	assert.False(t, ContainerRestarted(pod))
+
+	// Any other restart is
+	pod.Status.ContainerStatuses[0].RestartCount = 3
+	assert.True(t, ContainerRestarted(pod))
+	pod.Status.ContainerStatuses[0].RestartCount = 2
+	pod.Status.ContainerStatuses[1].RestartCount = 1
+	assert.True(t, ContainerRestarted(pod))
+}
@@ -0,0 +1,164 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/engines"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+// ModelServingMutator handles defaulting of ModelServing resources.
+type ModelServingMutator struct {
+}
+
+func NewModelServingMutator() *ModelServingMutator {
+	return &ModelServingMutator{}
+}
+
+// Handle handles admission requests for ModelServing resources
+func (m *ModelServingMutator) Handle(w http.ResponseWriter, r *http.Request) {
+	// Parse the admission request
+	admissionReview, modelServing, err := utils.ParseModelServingFromRequest(r)
+	if err != nil {
+		klog.Errorf("Failed to parse admission request: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Apply mutations on a copy of the ModelServing
+	mutated := modelServing.DeepCopy()
+	warnings := m.mutateModelServing(mutated)
+
+	patch, err := createPatch(modelServing, mutated)
+	if err != nil {
+		klog.Errorf("Failed to create patch: %v", err)
+		http.Error(w, fmt.Sprintf("could not create patch: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Create the admission response
+	patchType := admissionv1.PatchTypeJSONPatch
+	admissionReview.Response = &admissionv1.AdmissionResponse{
+		Allowed:   true,
+		UID:       admissionReview.Request.UID,
+		Patch:     patch,
+		PatchType: &patchType,
+		Warnings:  warnings,
+	}
+
+	// Send the response
+	if err := utils.SendAdmissionResponse(w, admissionReview); err != nil {
+		klog.Errorf("Failed to send admission response: %v", err)
+		http.Error(w, fmt.Sprintf("could not send response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// mutateModelServing defaults the recovery settings, and the engine arguments and the storage of the engine containers
+// of the roles. The values set by the user are never changed. It returns warnings about the ignored annotations.
+func (m *ModelServingMutator) mutateModelServing(ms *workloadv1alpha1.ModelServing) []string {
+	var warnings []string
+	mutateRecovery(ms)
+	if ms.Annotations[workloadv1alpha1.EngineArgsDefaultingAnnotationKey] != "false" {
+		warnings = append(warnings, mutateEngineArgs(ms)...)
+	}
+	if ms.Annotations[workloadv1alpha1.StorageDefaultingAnnotationKey] != "false" {
+		mutateStorage(ms)
+	}
+	return warnings
+}
+
+// mutateEngineArgs appends the missing engine arguments to the engine containers of the roles.
+func mutateEngineArgs(ms *workloadv1alpha1.ModelServing) []string {
+	var warnings []string
+	servedModelName := ms.Annotations[workloadv1alpha1.ServedModelNameAnnotationKey]
+	maxModelLen := ms.Annotations[workloadv1alpha1.MaxModelLenAnnotationKey]
+	if maxModelLen != "" {
+		if n, err := strconv.Atoi(maxModelLen); err != nil || n <= 0 {
+			warnings = append(warnings, fmt.Sprintf("annotation %s must be a positive integer, got %q, it is ignored",
+				workloadv1alpha1.MaxModelLenAnnotationKey, maxModelLen))
+			maxModelLen = ""
+		}
+	}
+
+	for i := range ms.Spec.Template.Roles {
+		role := &ms.Spec.Template.Roles[i]
+		defaultEngineArgs(&role.EntryTemplate.Spec, servedModelName, maxModelLen)
+		if role.WorkerTemplate != nil {
+			defaultEngineArgs(&role.WorkerTemplate.Spec, servedModelName, maxModelLen)
+		}
+	}
+	return warnings
+}
+
+// defaultEngineArgs appends the missing arguments to the engine containers of the pod.
+// The command lines run by a shell are not parsed, as arguments appended to them would not reach the engine.
+func defaultEngineArgs(spec *corev1.PodSpec, servedModelName, maxModelLen string) {
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		cmdline := append(append([]string{}, container.Command...), container.Args...)
+		engine := engines.Detect(cmdline)
+		if engine == nil {
+			continue
+		}
+		args := engine.Args()
+		if engines.HasFlag(cmdline, args.Config) {
+			continue
+		}
+		if servedModelName != "" && len(args.ServedModelName) > 0 && !engines.HasFlag(cmdline, args.ServedModelName...) {
+			container.Args = append(container.Args, args.ServedModelName[0], servedModelName)
+		}
+		if tp := utils.AcceleratorCount(container); tp > 1 && len(args.TensorParallelSize) > 0 && !engines.HasFlag(cmdline, args.TensorParallelSize...) {
+			container.Args = append(container.Args, args.TensorParallelSize[0], strconv.FormatInt(tp, 10))
+		}
+		if maxModelLen != "" && len(args.MaxModelLen) > 0 && !engines.HasFlag(cmdline, args.MaxModelLen...) {
+			container.Args = append(container.Args, args.MaxModelLen[0], maxModelLen)
+		}
+	}
+}
+
+// createPatch creates a JSON patch between the original and mutated ModelServing
+func createPatch(original, mutated *workloadv1alpha1.ModelServing) ([]byte, error) {
+	originalJSON, err := json.Marshal(original)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal original: %v", err)
+	}
+	mutatedJSON, err := json.Marshal(mutated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal mutated: %v", err)
+	}
+	patch, err := jsonpatch.CreatePatch(originalJSON, mutatedJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create patch: %v", err)
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal patch: %v", err)
+	}
+	return patchBytes, nil
+}
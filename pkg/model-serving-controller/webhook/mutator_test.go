@@ -0,0 +1,180 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+func newEngineModelServing(annotations map[string]string, gpus int64, command, args []string) *workloadv1alpha1.ModelServing {
+	container := corev1.Container{Name: "engine", Command: command, Args: args}
+	if gpus > 0 {
+		container.Resources.Limits = corev1.ResourceList{"nvidia.com/gpu": *resource.NewQuantity(gpus, resource.DecimalSI)}
+	}
+	return &workloadv1alpha1.ModelServing{
+		ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "default", Annotations: annotations},
+		Spec: workloadv1alpha1.ModelServingSpec{
+			Template: workloadv1alpha1.ServingGroup{
+				Roles: []workloadv1alpha1.Role{{
+					Name: "leader",
+					EntryTemplate: workloadv1alpha1.PodTemplateSpec{
+						Spec: corev1.PodSpec{Containers: []corev1.Container{container}},
+					},
+				}},
+			},
+		},
+	}
+}
+
+func TestMutateModelServing(t *testing.T) {
+	metadata := map[string]string{
+		workloadv1alpha1.ServedModelNameAnnotationKey: "llama-3-8b",
+		workloadv1alpha1.MaxModelLenAnnotationKey:     "8192",
+	}
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		gpus        int64
+		command     []string
+		args        []string
+		wantArgs    []string
+		wantWarning bool
+	}{
+		{
+			name:        "vllm serve",
+			annotations: metadata,
+			gpus:        4,
+			command:     []string{"vllm", "serve"},
+			args:        []string{"/models/llama"},
+			wantArgs:    []string{"/models/llama", "--served-model-name", "llama-3-8b", "--tensor-parallel-size", "4", "--max-model-len", "8192"},
+		},
+		{
+			name:        "vllm api server module",
+			annotations: metadata,
+			gpus:        1,
+			command:     []string{"python3", "-m", "vllm.entrypoints.openai.api_server"},
+			args:        []string{"--model", "/models/llama"},
+			wantArgs:    []string{"--model", "/models/llama", "--served-model-name", "llama-3-8b", "--max-model-len", "8192"},
+		},
+		{
+			name:        "explicit user args win",
+			annotations: metadata,
+			gpus:        8,
+			command:     []string{"/usr/local/bin/vllm"},
+			args:        []string{"serve", "/models/llama", "--served-model-name=llama", "-tp", "2", "--max-model-len=4096"},
+			wantArgs:    []string{"serve", "/models/llama", "--served-model-name=llama", "-tp", "2", "--max-model-len=4096"},
+		},
+		{
+			name:        "sglang",
+			annotations: metadata,
+			gpus:        2,
+			command:     []string{"python3", "-m", "sglang.launch_server"},
+			args:        []string{"--model-path", "/models/llama", "--tp", "2"},
+			wantArgs:    []string{"--model-path", "/models/llama", "--tp", "2", "--served-model-name", "llama-3-8b", "--context-length", "8192"},
+		},
+		{
+			name:        "tensorrt-llm without served model name",
+			annotations: metadata,
+			gpus:        4,
+			command:     []string{"trtllm-serve"},
+			args:        []string{"/models/llama"},
+			wantArgs:    []string{"/models/llama", "--tp_size", "4", "--max_seq_len", "8192"},
+		},
+		{
+			name:        "config file",
+			annotations: metadata,
+			gpus:        2,
+			command:     []string{"vllm", "serve"},
+			args:        []string{"--config", "/etc/vllm/config.yaml"},
+			wantArgs:    []string{"--config", "/etc/vllm/config.yaml"},
+		},
+		{
+			name:        "shell command line",
+			annotations: metadata,
+			gpus:        2,
+			command:     []string{"sh", "-c"},
+			args:        []string{"vllm serve /models/llama"},
+			wantArgs:    []string{"vllm serve /models/llama"},
+		},
+		{
+			name:        "defaulting disabled",
+			annotations: map[string]string{workloadv1alpha1.ServedModelNameAnnotationKey: "llama-3-8b", workloadv1alpha1.EngineArgsDefaultingAnnotationKey: "false"},
+			gpus:        2,
+			command:     []string{"vllm", "serve"},
+			args:        []string{"/models/llama"},
+			wantArgs:    []string{"/models/llama"},
+		},
+		{
+			name:        "invalid max model len",
+			annotations: map[string]string{workloadv1alpha1.MaxModelLenAnnotationKey: "8k"},
+			command:     []string{"vllm", "serve"},
+			args:        []string{"/models/llama"},
+			wantArgs:    []string{"/models/llama"},
+			wantWarning: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := newEngineModelServing(tt.annotations, tt.gpus, tt.command, tt.args)
+			warnings := NewModelServingMutator().mutateModelServing(ms)
+			assert.Equal(t, tt.wantArgs, ms.Spec.Template.Roles[0].EntryTemplate.Spec.Containers[0].Args)
+			assert.Equal(t, tt.wantWarning, len(warnings) > 0)
+		})
+	}
+}
+
+func TestModelServingMutatorHandle(t *testing.T) {
+	ms := newEngineModelServing(map[string]string{workloadv1alpha1.ServedModelNameAnnotationKey: "llama-3-8b"}, 2, []string{"vllm", "serve"}, []string{"/models/llama"})
+	raw, err := json.Marshal(ms)
+	require.NoError(t, err)
+	review := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request:  &admissionv1.AdmissionRequest{UID: "uid", Object: runtime.RawExtension{Raw: raw}},
+	}
+	body, err := json.Marshal(review)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/mutate/modelserving", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	NewModelServingMutator().Handle(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp admissionv1.AdmissionReview
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Response)
+	assert.True(t, resp.Response.Allowed)
+	assert.Equal(t, "uid", string(resp.Response.UID))
+	var patch []map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Response.Patch, &patch))
+	assert.NotEmpty(t, patch)
+	assert.Contains(t, string(resp.Response.Patch), "--tensor-parallel-size")
+}
@@ -0,0 +1,91 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+// mutateRecovery defaults the recovery policy and the restart grace period, for the API servers which did not
+// default them from the CRD schema.
+func mutateRecovery(ms *workloadv1alpha1.ModelServing) {
+	if ms.Spec.RecoveryPolicy == "" {
+		ms.Spec.RecoveryPolicy = workloadv1alpha1.RoleRecreate
+	}
+	if ms.Spec.Template.RestartGracePeriodSeconds == nil {
+		ms.Spec.Template.RestartGracePeriodSeconds = ptr.To[int64](0)
+	}
+}
+
+// validateRecovery validates the restart grace period and the recovery backoff.
+func validateRecovery(ms *workloadv1alpha1.ModelServing) field.ErrorList {
+	var allErrs field.ErrorList
+	if gracePeriod := ms.Spec.Template.RestartGracePeriodSeconds; gracePeriod != nil {
+		allErrs = append(allErrs, validateNonnegativeField(*gracePeriod,
+			field.NewPath("spec").Child("template").Child("restartGracePeriodSeconds"))...)
+	}
+	if backoff := ms.Spec.RecoveryBackoff; backoff != nil && backoff.InitialDelaySeconds > 0 && backoff.MaxDelaySeconds > 0 &&
+		backoff.MaxDelaySeconds < backoff.InitialDelaySeconds {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("recoveryBackoff").Child("maxDelaySeconds"),
+			backoff.MaxDelaySeconds, fmt.Sprintf("must not be less than initialDelaySeconds (%d)", backoff.InitialDelaySeconds)))
+	}
+	return allErrs
+}
+
+// recoveryWarnings warns about the recovery settings which are accepted but do not behave as they may be expected to.
+func recoveryWarnings(ms *workloadv1alpha1.ModelServing) []string {
+	var warnings []string
+	policyPath := field.NewPath("spec").Child("recoveryPolicy")
+	switch ms.Spec.RecoveryPolicy {
+	case workloadv1alpha1.NoneRestartPolicy:
+		warnings = append(warnings, fmt.Sprintf("%s: the failed pods are deleted at the end of their restart grace period and are not recreated, "+
+			"their ServingGroups stay degraded until they are deleted", policyPath))
+		if ms.Spec.RecoveryBackoff != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: ignored, the ServingGroups are not recreated with the %s recovery policy",
+				field.NewPath("spec").Child("recoveryBackoff"), workloadv1alpha1.NoneRestartPolicy))
+		}
+	case workloadv1alpha1.RoleRecreate:
+		if ms.Spec.Template.GangPolicy != nil && gangedPods(ms) > 1 {
+			warnings = append(warnings, fmt.Sprintf("%s: the failed roles are recreated alone while the other roles keep their nodes, "+
+				"so the ServingGroup is not gang scheduled again; use %s to reschedule the whole ServingGroup as a gang",
+				policyPath, workloadv1alpha1.ServingGroupRecreate))
+		}
+	}
+	return warnings
+}
+
+// gangedPods returns the number of pods of a ServingGroup scheduled as a gang, the pods of the role replicas
+// required by the gang policy.
+func gangedPods(ms *workloadv1alpha1.ModelServing) int32 {
+	var pods int32
+	for _, role := range ms.Spec.Template.Roles {
+		replicas := int32(1)
+		if role.Replicas != nil {
+			replicas = *role.Replicas
+		}
+		if minReplicas, ok := ms.Spec.Template.GangPolicy.MinRoleReplicas[role.Name]; ok {
+			replicas = min(replicas, minReplicas)
+		}
+		pods += max(replicas, 0) * (1 + role.WorkerReplicas)
+	}
+	return pods
+}
@@ -0,0 +1,97 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+func TestMutateRecovery(t *testing.T) {
+	ms := newEngineModelServing(nil, 0, []string{"vllm", "serve"}, nil)
+	mutateRecovery(ms)
+	assert.Equal(t, workloadv1alpha1.RoleRecreate, ms.Spec.RecoveryPolicy)
+	assert.Equal(t, ptr.To[int64](0), ms.Spec.Template.RestartGracePeriodSeconds)
+
+	// The values set by the user are kept
+	ms.Spec.RecoveryPolicy = workloadv1alpha1.ServingGroupRecreate
+	ms.Spec.Template.RestartGracePeriodSeconds = ptr.To[int64](30)
+	mutateRecovery(ms)
+	assert.Equal(t, workloadv1alpha1.ServingGroupRecreate, ms.Spec.RecoveryPolicy)
+	assert.Equal(t, ptr.To[int64](30), ms.Spec.Template.RestartGracePeriodSeconds)
+}
+
+func TestValidateRecovery(t *testing.T) {
+	tests := []struct {
+		name        string
+		gracePeriod *int64
+		backoff     *workloadv1alpha1.RecoveryBackoff
+		wantErrs    int
+	}{
+		{name: "defaults"},
+		{name: "grace period", gracePeriod: ptr.To[int64](60)},
+		{name: "negative grace period", gracePeriod: ptr.To[int64](-1), wantErrs: 1},
+		{name: "backoff", backoff: &workloadv1alpha1.RecoveryBackoff{InitialDelaySeconds: 10, MaxDelaySeconds: 10}},
+		{name: "max delay less than initial delay", backoff: &workloadv1alpha1.RecoveryBackoff{InitialDelaySeconds: 60, MaxDelaySeconds: 30}, wantErrs: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := newEngineModelServing(nil, 0, []string{"vllm", "serve"}, nil)
+			ms.Spec.Template.RestartGracePeriodSeconds = tt.gracePeriod
+			ms.Spec.RecoveryBackoff = tt.backoff
+			assert.Len(t, validateRecovery(ms), tt.wantErrs)
+		})
+	}
+}
+
+func TestRecoveryWarnings(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       workloadv1alpha1.RecoveryPolicy
+		backoff      *workloadv1alpha1.RecoveryBackoff
+		gangPolicy   *workloadv1alpha1.GangPolicy
+		roleReplicas int32
+		wantWarnings int
+	}{
+		{name: "role recreate", policy: workloadv1alpha1.RoleRecreate, roleReplicas: 2},
+		{name: "none", policy: workloadv1alpha1.NoneRestartPolicy, roleReplicas: 1, wantWarnings: 1},
+		{name: "none with backoff", policy: workloadv1alpha1.NoneRestartPolicy, backoff: &workloadv1alpha1.RecoveryBackoff{}, roleReplicas: 1, wantWarnings: 2},
+		{name: "role recreate with gang", policy: workloadv1alpha1.RoleRecreate, gangPolicy: &workloadv1alpha1.GangPolicy{}, roleReplicas: 2, wantWarnings: 1},
+		{name: "role recreate with a single pod gang", policy: workloadv1alpha1.RoleRecreate, gangPolicy: &workloadv1alpha1.GangPolicy{}, roleReplicas: 1},
+		{
+			name:         "role recreate with a gang of one role replica",
+			policy:       workloadv1alpha1.RoleRecreate,
+			gangPolicy:   &workloadv1alpha1.GangPolicy{MinRoleReplicas: map[string]int32{"leader": 1}},
+			roleReplicas: 4,
+		},
+		{name: "servingGroup recreate with gang", policy: workloadv1alpha1.ServingGroupRecreate, gangPolicy: &workloadv1alpha1.GangPolicy{}, roleReplicas: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := newEngineModelServing(nil, 0, []string{"vllm", "serve"}, nil)
+			ms.Spec.RecoveryPolicy = tt.policy
+			ms.Spec.RecoveryBackoff = tt.backoff
+			ms.Spec.Template.GangPolicy = tt.gangPolicy
+			ms.Spec.Template.Roles[0].Replicas = ptr.To(tt.roleReplicas)
+			assert.Len(t, recoveryWarnings(ms), tt.wantWarnings)
+		})
+	}
+}
@@ -0,0 +1,292 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/engines"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+const (
+	shmVolumeName = "dshm"
+	shmMountPath  = "/dev/shm"
+	// ephemeralStorageHeadroomPercent is the ephemeral storage requested on top of the model size, for the caches
+	// and the logs of the engine.
+	ephemeralStorageHeadroomPercent = 10
+	// mib rounds up the ephemeral storage requests.
+	mib = 1 << 20
+)
+
+// engineOf returns the inference engine run by the container, nil when it runs no known engine.
+func engineOf(container *corev1.Container) engines.InferenceEngine {
+	return engines.Detect(append(append([]string{}, container.Command...), container.Args...))
+}
+
+// modelSizeOf parses the model size annotation of the ModelServing, nil when it is not set.
+func modelSizeOf(ms *workloadv1alpha1.ModelServing) (*resource.Quantity, error) {
+	value, ok := ms.Annotations[workloadv1alpha1.ModelSizeAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	size, err := resource.ParseQuantity(value)
+	if err != nil {
+		return nil, err
+	}
+	if size.Sign() <= 0 {
+		return nil, errors.New("must be positive")
+	}
+	return &size, nil
+}
+
+// mutateStorage sizes the storage of the engine containers of the roles. An invalid model size is rejected by
+// the validating webhook.
+func mutateStorage(ms *workloadv1alpha1.ModelServing) {
+	modelSize, _ := modelSizeOf(ms)
+	for i := range ms.Spec.Template.Roles {
+		role := &ms.Spec.Template.Roles[i]
+		defaultStorage(&role.EntryTemplate.Spec, modelSize)
+		if role.WorkerTemplate != nil {
+			defaultStorage(&role.WorkerTemplate.Spec, modelSize)
+		}
+	}
+}
+
+// defaultStorage mounts a memory-backed /dev/shm, sized for the engine, in the engine containers without one,
+// and requests the ephemeral storage holding the model weights for the engine containers not requesting any.
+// The pods sharing the IPC namespace of the host use its /dev/shm.
+func defaultStorage(spec *corev1.PodSpec, modelSize *resource.Quantity) {
+	var shmSize int64
+	var mounts []int
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		engine := engineOf(container)
+		if engine == nil {
+			continue
+		}
+		if modelSize != nil && !requestsResource(container, corev1.ResourceEphemeralStorage) {
+			if container.Resources.Requests == nil {
+				container.Resources.Requests = corev1.ResourceList{}
+			}
+			container.Resources.Requests[corev1.ResourceEphemeralStorage] = ephemeralStorageFor(modelSize)
+		}
+		if spec.HostIPC || shmMountOf(container) != nil {
+			continue
+		}
+		size := engine.SharedMemory(utils.AcceleratorCount(container))
+		// The memory-backed volumes count against the memory limit, the engine must keep most of it
+		if limit, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+			size = min(size, limit.Value()/2)
+		}
+		shmSize = max(shmSize, size)
+		mounts = append(mounts, i)
+	}
+	if len(mounts) == 0 {
+		return
+	}
+
+	// A dshm volume declared but not mounted at /dev/shm is used as is
+	if volumeOf(spec, shmVolumeName) == nil {
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name: shmVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium:    corev1.StorageMediumMemory,
+					SizeLimit: resource.NewQuantity(shmSize, resource.BinarySI),
+				},
+			},
+		})
+	}
+	for _, i := range mounts {
+		spec.Containers[i].VolumeMounts = append(spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      shmVolumeName,
+			MountPath: shmMountPath,
+		})
+	}
+}
+
+// ephemeralStorageFor returns the ephemeral storage of a model of the size, with some headroom.
+func ephemeralStorageFor(modelSize *resource.Quantity) resource.Quantity {
+	size := modelSize.Value() * (100 + ephemeralStorageHeadroomPercent) / 100
+	return *resource.NewQuantity((size+mib-1)/mib*mib, resource.BinarySI)
+}
+
+// storageWarnings warns about the engine containers whose storage the engine may crash with, as set by the user
+// or left without defaulting.
+func storageWarnings(ms *workloadv1alpha1.ModelServing) []string {
+	modelSize, _ := modelSizeOf(ms)
+	var warnings []string
+	for i, role := range ms.Spec.Template.Roles {
+		rolePath := field.NewPath("spec").Child("template").Child("roles").Index(i)
+		warnings = append(warnings, podStorageWarnings(&role.EntryTemplate.Spec, modelSize, rolePath.Child("entryTemplate"))...)
+		if role.WorkerTemplate != nil {
+			warnings = append(warnings, podStorageWarnings(&role.WorkerTemplate.Spec, modelSize, rolePath.Child("workerTemplate"))...)
+		}
+	}
+	return warnings
+}
+
+func podStorageWarnings(spec *corev1.PodSpec, modelSize *resource.Quantity, path *field.Path) []string {
+	var warnings []string
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		engine := engineOf(container)
+		if engine == nil {
+			continue
+		}
+		containerPath := path.Child("spec").Child("containers").Index(i)
+
+		if modelSize != nil {
+			if storage, ok := requestOf(container, corev1.ResourceEphemeralStorage); ok && storage.Cmp(*modelSize) < 0 {
+				warnings = append(warnings, fmt.Sprintf("%s: the ephemeral storage %s is smaller than the model size %s",
+					containerPath.Child("resources"), storage.String(), modelSize.String()))
+			}
+		}
+
+		if spec.HostIPC {
+			continue
+		}
+		need := resource.NewQuantity(engine.SharedMemory(utils.AcceleratorCount(container)), resource.BinarySI)
+		mount := shmMountOf(container)
+		if mount == nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %s mounts no volume at %s, %s may crash with the 64Mi of the container runtime, it needs %s",
+				containerPath, container.Name, shmMountPath, engine.Name(), need.String()))
+			continue
+		}
+		volume := volumeOf(spec, mount.Name)
+		if volume == nil || volume.EmptyDir == nil {
+			continue
+		}
+		volumePath := path.Child("spec").Child("volumes").Key(volume.Name)
+		if volume.EmptyDir.Medium != corev1.StorageMediumMemory {
+			warnings = append(warnings, fmt.Sprintf("%s: %s is not memory-backed, set medium: Memory", volumePath, shmMountPath))
+			continue
+		}
+		sizeLimit := volume.EmptyDir.SizeLimit
+		if sizeLimit != nil && sizeLimit.Cmp(*need) < 0 {
+			warnings = append(warnings, fmt.Sprintf("%s: %s of %s is smaller than the %s %s needs",
+				volumePath, shmMountPath, sizeLimit.String(), need.String(), engine.Name()))
+		}
+		if limit, ok := container.Resources.Limits[corev1.ResourceMemory]; ok && (sizeLimit == nil || sizeLimit.Cmp(limit) >= 0) {
+			warnings = append(warnings, fmt.Sprintf("%s: %s is not limited below the memory limit %s of %s, which it counts against",
+				volumePath, shmMountPath, limit.String(), container.Name))
+		}
+	}
+	return warnings
+}
+
+// validateModelSize validates the model size annotation of the ModelServing.
+func validateModelSize(ms *workloadv1alpha1.ModelServing) field.ErrorList {
+	if _, err := modelSizeOf(ms); err != nil {
+		path := field.NewPath("metadata").Child("annotations").Key(workloadv1alpha1.ModelSizeAnnotationKey)
+		return field.ErrorList{field.Invalid(path, ms.Annotations[workloadv1alpha1.ModelSizeAnnotationKey],
+			fmt.Sprintf("must be a positive quantity such as 140Gi: %v", err))}
+	}
+	return nil
+}
+
+// validateHugePages validates the huge pages of the containers of the roles: like the API server does for pods,
+// their requests must equal their limits, and the containers requesting them must request cpu or memory too.
+// Otherwise no pod of the ModelServing could be created.
+func validateHugePages(ms *workloadv1alpha1.ModelServing) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, role := range ms.Spec.Template.Roles {
+		rolePath := field.NewPath("spec").Child("template").Child("roles").Index(i)
+		allErrs = append(allErrs, validatePodHugePages(&role.EntryTemplate.Spec, rolePath.Child("entryTemplate").Child("spec"))...)
+		if role.WorkerTemplate != nil {
+			allErrs = append(allErrs, validatePodHugePages(&role.WorkerTemplate.Spec, rolePath.Child("workerTemplate").Child("spec"))...)
+		}
+	}
+	return allErrs
+}
+
+func validatePodHugePages(spec *corev1.PodSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	validate := func(container *corev1.Container, containerPath *field.Path) {
+		resourcesPath := containerPath.Child("resources")
+		hugePages := false
+		for name, request := range container.Resources.Requests {
+			if !isHugePages(name) {
+				continue
+			}
+			hugePages = true
+			if limit, ok := container.Resources.Limits[name]; !ok {
+				allErrs = append(allErrs, field.Required(resourcesPath.Child("limits").Key(string(name)), "huge pages must be limited"))
+			} else if request.Cmp(limit) != 0 {
+				allErrs = append(allErrs, field.Invalid(resourcesPath.Child("requests").Key(string(name)), request.String(),
+					fmt.Sprintf("must equal the limit %s", limit.String())))
+			}
+		}
+		for name := range container.Resources.Limits {
+			hugePages = hugePages || isHugePages(name)
+		}
+		if hugePages && !requestsResource(container, corev1.ResourceCPU) && !requestsResource(container, corev1.ResourceMemory) {
+			allErrs = append(allErrs, field.Forbidden(resourcesPath, "huge pages require a cpu or memory request"))
+		}
+	}
+	for i := range spec.InitContainers {
+		validate(&spec.InitContainers[i], path.Child("initContainers").Index(i))
+	}
+	for i := range spec.Containers {
+		validate(&spec.Containers[i], path.Child("containers").Index(i))
+	}
+	return allErrs
+}
+
+func isHugePages(name corev1.ResourceName) bool {
+	return strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix)
+}
+
+// requestOf returns the request of the resource of the container, which defaults to its limit.
+func requestOf(container *corev1.Container, name corev1.ResourceName) (resource.Quantity, bool) {
+	if quantity, ok := container.Resources.Requests[name]; ok {
+		return quantity, true
+	}
+	quantity, ok := container.Resources.Limits[name]
+	return quantity, ok
+}
+
+func requestsResource(container *corev1.Container, name corev1.ResourceName) bool {
+	_, ok := requestOf(container, name)
+	return ok
+}
+
+func shmMountOf(container *corev1.Container) *corev1.VolumeMount {
+	for i := range container.VolumeMounts {
+		if strings.TrimSuffix(container.VolumeMounts[i].MountPath, "/") == shmMountPath {
+			return &container.VolumeMounts[i]
+		}
+	}
+	return nil
+}
+
+func volumeOf(spec *corev1.PodSpec, name string) *corev1.Volume {
+	for i := range spec.Volumes {
+		if spec.Volumes[i].Name == name {
+			return &spec.Volumes[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+func TestMutateStorage(t *testing.T) {
+	vllm := []string{"vllm", "serve"}
+	tests := []struct {
+		name             string
+		annotations      map[string]string
+		gpus             int64
+		command          []string
+		customize        func(spec *corev1.PodSpec)
+		wantShm          string
+		wantMounted      bool
+		wantEphemeral    string
+		wantVolumesCount int
+	}{
+		{
+			name:             "vllm with tensor parallelism",
+			gpus:             4,
+			command:          vllm,
+			wantShm:          "8Gi",
+			wantMounted:      true,
+			wantVolumesCount: 1,
+		},
+		{
+			name:             "sglang",
+			gpus:             1,
+			command:          []string{"python3", "-m", "sglang.launch_server"},
+			wantShm:          "8Gi",
+			wantMounted:      true,
+			wantVolumesCount: 1,
+		},
+		{
+			name:             "model size",
+			annotations:      map[string]string{workloadv1alpha1.ModelSizeAnnotationKey: "140Gi"},
+			gpus:             1,
+			command:          vllm,
+			wantShm:          "2Gi",
+			wantMounted:      true,
+			wantEphemeral:    "154Gi",
+			wantVolumesCount: 1,
+		},
+		{
+			name:        "memory limit",
+			gpus:        8,
+			command:     vllm,
+			wantShm:     "4Gi",
+			wantMounted: true,
+			customize: func(spec *corev1.PodSpec) {
+				spec.Containers[0].Resources.Limits[corev1.ResourceMemory] = resource.MustParse("8Gi")
+			},
+			wantVolumesCount: 1,
+		},
+		{
+			name:        "user /dev/shm and ephemeral storage",
+			annotations: map[string]string{workloadv1alpha1.ModelSizeAnnotationKey: "140Gi"},
+			gpus:        1,
+			command:     vllm,
+			customize: func(spec *corev1.PodSpec) {
+				spec.Volumes = []corev1.Volume{{Name: "shm", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}}}}
+				spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "shm", MountPath: "/dev/shm/"}}
+				spec.Containers[0].Resources.Limits[corev1.ResourceEphemeralStorage] = resource.MustParse("200Gi")
+			},
+			wantVolumesCount: 1,
+		},
+		{
+			name:    "host IPC",
+			gpus:    1,
+			command: vllm,
+			customize: func(spec *corev1.PodSpec) {
+				spec.HostIPC = true
+			},
+		},
+		{
+			name:    "no engine",
+			command: []string{"sh", "-c"},
+		},
+		{
+			name:        "defaulting disabled",
+			annotations: map[string]string{workloadv1alpha1.StorageDefaultingAnnotationKey: "false", workloadv1alpha1.ModelSizeAnnotationKey: "140Gi"},
+			gpus:        1,
+			command:     vllm,
+		},
+		{
+			name:             "invalid model size",
+			annotations:      map[string]string{workloadv1alpha1.ModelSizeAnnotationKey: "big"},
+			gpus:             1,
+			command:          vllm,
+			wantShm:          "2Gi",
+			wantMounted:      true,
+			wantVolumesCount: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := newEngineModelServing(tt.annotations, tt.gpus, tt.command, nil)
+			spec := &ms.Spec.Template.Roles[0].EntryTemplate.Spec
+			if spec.Containers[0].Resources.Limits == nil {
+				spec.Containers[0].Resources.Limits = corev1.ResourceList{}
+			}
+			if tt.customize != nil {
+				tt.customize(spec)
+			}
+			NewModelServingMutator().mutateModelServing(ms)
+
+			assert.Len(t, spec.Volumes, tt.wantVolumesCount)
+			if tt.wantShm != "" {
+				require.NotNil(t, volumeOf(spec, shmVolumeName))
+				emptyDir := volumeOf(spec, shmVolumeName).EmptyDir
+				assert.Equal(t, corev1.StorageMediumMemory, emptyDir.Medium)
+				assert.Equal(t, tt.wantShm, emptyDir.SizeLimit.String())
+			} else {
+				assert.Nil(t, volumeOf(spec, shmVolumeName))
+			}
+			mount := shmMountOf(&spec.Containers[0])
+			if tt.wantMounted {
+				require.NotNil(t, mount)
+				assert.Equal(t, shmVolumeName, mount.Name)
+			}
+			storage, ok := spec.Containers[0].Resources.Requests[corev1.ResourceEphemeralStorage]
+			if tt.wantEphemeral != "" {
+				assert.True(t, ok)
+				assert.Equal(t, tt.wantEphemeral, storage.String())
+			} else {
+				assert.False(t, ok)
+			}
+		})
+	}
+}
+
+func TestMutateStorageDeclaredVolume(t *testing.T) {
+	ms := newEngineModelServing(nil, 2, []string{"vllm", "serve"}, nil)
+	spec := &ms.Spec.Template.Roles[0].EntryTemplate.Spec
+	declared := corev1.Volume{Name: shmVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}}}
+	spec.Volumes = []corev1.Volume{declared}
+
+	mutateStorage(ms)
+
+	assert.Equal(t, []corev1.Volume{declared}, spec.Volumes, "the declared volume is used as is")
+	assert.Equal(t, []corev1.VolumeMount{{Name: shmVolumeName, MountPath: shmMountPath}}, spec.Containers[0].VolumeMounts)
+}
+
+func TestStorageWarnings(t *testing.T) {
+	memoryShm := func(sizeLimit string) corev1.Volume {
+		emptyDir := &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}
+		if sizeLimit != "" {
+			quantity := resource.MustParse(sizeLimit)
+			emptyDir.SizeLimit = &quantity
+		}
+		return corev1.Volume{Name: "shm", VolumeSource: corev1.VolumeSource{EmptyDir: emptyDir}}
+	}
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		// unmounted leaves /dev/shm of the engine container unmounted
+		unmounted bool
+		customize func(spec *corev1.PodSpec)
+		want      []string
+	}{
+		{
+			name:      "defaulted",
+			unmounted: true,
+			customize: func(spec *corev1.PodSpec) {
+				defaultStorage(spec, nil)
+			},
+		},
+		{
+			name:      "no /dev/shm",
+			unmounted: true,
+			want:      []string{"spec.template.roles[0].entryTemplate.spec.containers[0]: engine mounts no volume at /dev/shm, vLLM may crash with the 64Mi of the container runtime, it needs 4Gi"},
+		},
+		{
+			name: "host IPC",
+			customize: func(spec *corev1.PodSpec) {
+				spec.HostIPC = true
+			},
+		},
+		{
+			name: "disk-backed /dev/shm",
+			customize: func(spec *corev1.PodSpec) {
+				spec.Volumes = []corev1.Volume{{Name: "shm", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+			},
+			want: []string{"spec.template.roles[0].entryTemplate.spec.volumes[shm]: /dev/shm is not memory-backed, set medium: Memory"},
+		},
+		{
+			name: "small /dev/shm",
+			customize: func(spec *corev1.PodSpec) {
+				spec.Volumes = []corev1.Volume{memoryShm("1Gi")}
+			},
+			want: []string{"spec.template.roles[0].entryTemplate.spec.volumes[shm]: /dev/shm of 1Gi is smaller than the 4Gi vLLM needs"},
+		},
+		{
+			name: "/dev/shm not limited below the memory limit",
+			customize: func(spec *corev1.PodSpec) {
+				spec.Volumes = []corev1.Volume{memoryShm("")}
+				spec.Containers[0].Resources.Limits[corev1.ResourceMemory] = resource.MustParse("32Gi")
+			},
+			want: []string{"spec.template.roles[0].entryTemplate.spec.volumes[shm]: /dev/shm is not limited below the memory limit 32Gi of engine, which it counts against"},
+		},
+		{
+			name:        "small ephemeral storage",
+			annotations: map[string]string{workloadv1alpha1.ModelSizeAnnotationKey: "140Gi"},
+			customize: func(spec *corev1.PodSpec) {
+				spec.Volumes = []corev1.Volume{memoryShm("8Gi")}
+				spec.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("100Gi")}
+			},
+			want: []string{"spec.template.roles[0].entryTemplate.spec.containers[0].resources: the ephemeral storage 100Gi is smaller than the model size 140Gi"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := newEngineModelServing(tt.annotations, 2, []string{"vllm", "serve"}, nil)
+			spec := &ms.Spec.Template.Roles[0].EntryTemplate.Spec
+			if !tt.unmounted {
+				spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "shm", MountPath: shmMountPath}}
+			}
+			if tt.customize != nil {
+				tt.customize(spec)
+			}
+			assert.Equal(t, tt.want, storageWarnings(ms))
+		})
+	}
+}
+
+func TestValidateModelSize(t *testing.T) {
+	for value, valid := range map[string]bool{"140Gi": true, "8e9": true, "0": false, "-1Gi": false, "big": false} {
+		ms := newEngineModelServing(map[string]string{workloadv1alpha1.ModelSizeAnnotationKey: value}, 1, []string{"vllm", "serve"}, nil)
+		assert.Equal(t, valid, len(validateModelSize(ms)) == 0, value)
+	}
+	assert.Empty(t, validateModelSize(newEngineModelServing(nil, 1, []string{"vllm", "serve"}, nil)))
+}
+
+func TestValidateHugePages(t *testing.T) {
+	hugePages := corev1.ResourceName(corev1.ResourceHugePagesPrefix + "2Mi")
+	tests := []struct {
+		name      string
+		resources corev1.ResourceRequirements
+		want      []string
+	}{
+		{
+			name: "valid",
+			resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{hugePages: resource.MustParse("1Gi"), corev1.ResourceMemory: resource.MustParse("8Gi")},
+				Limits:   corev1.ResourceList{hugePages: resource.MustParse("1Gi")},
+			},
+		},
+		{
+			name: "limits only",
+			resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{hugePages: resource.MustParse("1Gi"), corev1.ResourceCPU: resource.MustParse("4")},
+			},
+		},
+		{
+			name: "requests without limits",
+			resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{hugePages: resource.MustParse("1Gi"), corev1.ResourceCPU: resource.MustParse("4")},
+			},
+			want: []string{"spec.template.roles[0].entryTemplate.spec.containers[0].resources.limits[hugepages-2Mi]: Required value: huge pages must be limited"},
+		},
+		{
+			name: "requests different from the limits",
+			resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{hugePages: resource.MustParse("512Mi"), corev1.ResourceCPU: resource.MustParse("4")},
+				Limits:   corev1.ResourceList{hugePages: resource.MustParse("1Gi")},
+			},
+			want: []string{`spec.template.roles[0].entryTemplate.spec.containers[0].resources.requests[hugepages-2Mi]: Invalid value: "512Mi": must equal the limit 1Gi`},
+		},
+		{
+			name: "without cpu or memory",
+			resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{hugePages: resource.MustParse("1Gi")},
+			},
+			want: []string{"spec.template.roles[0].entryTemplate.spec.containers[0].resources: Forbidden: huge pages require a cpu or memory request"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := newEngineModelServing(nil, 0, []string{"vllm", "serve"}, nil)
+			ms.Spec.Template.Roles[0].EntryTemplate.Spec.Containers[0].Resources = tt.resources
+			var got []string
+			for _, err := range validateHugePages(ms) {
+				got = append(got, err.Error())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
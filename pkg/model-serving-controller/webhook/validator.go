@@ -17,29 +17,45 @@ limitations under the License.
 package webhook
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
+	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
 	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/gangscheduling"
 	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+	"github.com/volcano-sh/kthena/pkg/webhook/tenancy"
 )
 
+// migProfilePattern matches the profiles of the MIG instances, e.g. 1g.10gb or 1g.10gb+me.
+var migProfilePattern = regexp.MustCompile(`^[0-9]+g\.[0-9]+gb(\+me)?$`)
+
 // ModelServingValidator handles validation of ModelServing resources.
 type ModelServingValidator struct {
+	// kubeClient lists the MIG profiles advertised by the nodes, they are not checked when it is nil.
+	kubeClient kubernetes.Interface
+	// tenancy caps the GPUs of the ModelServings of the namespaces selected by the TenancyPolicies.
+	tenancy *tenancy.Guard
 }
 
-func NewModelServingValidator() *ModelServingValidator {
-	return &ModelServingValidator{}
+func NewModelServingValidator(kubeClient kubernetes.Interface, client clientset.Interface) *ModelServingValidator {
+	return &ModelServingValidator{kubeClient: kubeClient, tenancy: tenancy.NewGuard(kubeClient, client)}
 }
 
 // Handle handles admission requests for ModelServing resources
@@ -52,13 +68,26 @@ func (v *ModelServingValidator) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The MIG profiles already used by the old object are not checked again, so that it can still be
+	// updated while the nodes advertising them are gone.
+	var oldModelServing *workloadv1alpha1.ModelServing
+	if len(admissionReview.Request.OldObject.Raw) > 0 {
+		oldModelServing = &workloadv1alpha1.ModelServing{}
+		if err := json.Unmarshal(admissionReview.Request.OldObject.Raw, oldModelServing); err != nil {
+			klog.Errorf("Failed to decode old modelServing: %v", err)
+			http.Error(w, fmt.Sprintf("failed to decode old modelServing: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Validate the ModelServing
-	allowed, reason := v.validateModelServing(modelServing)
+	allowed, reason := v.validateModelServing(r.Context(), modelServing, oldModelServing)
 
 	// Create the admission response
 	admissionResponse := admissionv1.AdmissionResponse{
-		Allowed: allowed,
-		UID:     admissionReview.Request.UID,
+		Allowed:  allowed,
+		UID:      admissionReview.Request.UID,
+		Warnings: append(storageWarnings(modelServing), recoveryWarnings(modelServing)...),
 	}
 
 	if !allowed {
@@ -79,7 +108,7 @@ func (v *ModelServingValidator) Handle(w http.ResponseWriter, r *http.Request) {
 }
 
 // validateModelServing validates the ModelServing resource
-func (v *ModelServingValidator) validateModelServing(modelServing *workloadv1alpha1.ModelServing) (bool, string) {
+func (v *ModelServingValidator) validateModelServing(ctx context.Context, modelServing, oldModelServing *workloadv1alpha1.ModelServing) (bool, string) {
 	var allErrs field.ErrorList
 
 	allErrs = append(allErrs, validGeneratedNameLength(modelServing)...)
@@ -89,6 +118,13 @@ func (v *ModelServingValidator) validateModelServing(modelServing *workloadv1alp
 	allErrs = append(allErrs, validateRollingUpdateConfiguration(modelServing)...)
 	allErrs = append(allErrs, validateGangPolicy(modelServing)...)
 	allErrs = append(allErrs, validateWorkerReplicas(modelServing)...)
+	allErrs = append(allErrs, validateRolePlacement(modelServing)...)
+	allErrs = append(allErrs, validateGPUSharing(modelServing)...)
+	allErrs = append(allErrs, validateModelSize(modelServing)...)
+	allErrs = append(allErrs, validateHugePages(modelServing)...)
+	allErrs = append(allErrs, validateRecovery(modelServing)...)
+	allErrs = append(allErrs, v.validateMIGProfiles(ctx, modelServing, oldModelServing)...)
+	allErrs = append(allErrs, v.tenancy.ValidateGPUs(ctx, modelServing, oldModelServing)...)
 
 	if len(allErrs) > 0 {
 		var messages []string
@@ -105,10 +141,29 @@ func validateScheduler(mi *workloadv1alpha1.ModelServing) field.ErrorList {
 	var allErrs field.ErrorList
 	// Support:
 	// volcano: https://github.com/volcano-sh/volcano
-	if mi.Spec.SchedulerName != "volcano" {
+	// kueue: https://github.com/kubernetes-sigs/kueue
+	// scheduler-plugins-scheduler: https://github.com/kubernetes-sigs/scheduler-plugins (coscheduling)
+	schedulerField := field.NewPath("spec").Child("schedulerName")
+	if !gangscheduling.IsSupportedScheduler(mi.Spec.SchedulerName) {
 		allErrs = append(allErrs, field.Invalid(
-			field.NewPath("spec").Child("schedulerName"), mi.Spec.SchedulerName,
-			fmt.Sprintf("invalid SchedulerName: %s, modelServing support: volcano ...", mi.Spec.SchedulerName),
+			schedulerField, mi.Spec.SchedulerName,
+			fmt.Sprintf("invalid SchedulerName: %s, modelServing support: %s, %s, %s", mi.Spec.SchedulerName,
+				workloadv1alpha1.SchedulerNameVolcano, workloadv1alpha1.SchedulerNameKueue, workloadv1alpha1.SchedulerNameCoscheduling),
+		))
+		return allErrs
+	}
+
+	if mi.Spec.SchedulerName != workloadv1alpha1.SchedulerNameVolcano && mi.Spec.Template.NetworkTopology != nil {
+		allErrs = append(allErrs, field.Forbidden(
+			field.NewPath("spec").Child("template").Child("networkTopology"),
+			fmt.Sprintf("networkTopology is only supported by %s", workloadv1alpha1.SchedulerNameVolcano),
+		))
+	}
+	if mi.Spec.SchedulerName == workloadv1alpha1.SchedulerNameKueue && mi.Spec.Template.GangPolicy != nil &&
+		mi.Labels[gangscheduling.KueueQueueNameLabelKey] == "" {
+		allErrs = append(allErrs, field.Required(
+			field.NewPath("metadata").Child("labels").Key(gangscheduling.KueueQueueNameLabelKey),
+			"the Kueue LocalQueue must be specified to gang schedule with kueue",
 		))
 	}
 
@@ -295,6 +350,154 @@ func validateWorkerReplicas(mi *workloadv1alpha1.ModelServing) field.ErrorList {
 	return allErrs
 }
 
+// validateRolePlacement validates the topology keys of the role placements
+func validateRolePlacement(mi *workloadv1alpha1.ModelServing) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, role := range mi.Spec.Template.Roles {
+		if role.Placement == nil {
+			continue
+		}
+		placementPath := field.NewPath("spec").Child("template").Child("roles").Index(i).Child("placement")
+		if key := role.Placement.ColocationTopologyKey; key != "" {
+			for _, msg := range validation.IsQualifiedName(key) {
+				allErrs = append(allErrs, field.Invalid(placementPath.Child("colocationTopologyKey"), key, msg))
+			}
+		}
+
+		// The generated constraints must not collide with the ones of the entry template
+		existing := sets.New[string]()
+		for _, constraint := range role.EntryTemplate.Spec.TopologySpreadConstraints {
+			existing.Insert(constraint.TopologyKey + "/" + string(constraint.WhenUnsatisfiable))
+		}
+		for j, spread := range role.Placement.TopologySpread {
+			spreadPath := placementPath.Child("topologySpread").Index(j)
+			for _, msg := range validation.IsQualifiedName(spread.TopologyKey) {
+				allErrs = append(allErrs, field.Invalid(spreadPath.Child("topologyKey"), spread.TopologyKey, msg))
+			}
+			whenUnsatisfiable := spread.WhenUnsatisfiable
+			if whenUnsatisfiable == "" {
+				whenUnsatisfiable = corev1.ScheduleAnyway
+			}
+			key := spread.TopologyKey + "/" + string(whenUnsatisfiable)
+			if existing.Has(key) {
+				allErrs = append(allErrs, field.Duplicate(spreadPath, key))
+			}
+			existing.Insert(key)
+		}
+	}
+
+	return allErrs
+}
+
+// validateGPUSharing validates that the roles sharing GPUs request the GPUs to translate
+func validateGPUSharing(mi *workloadv1alpha1.ModelServing) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, role := range mi.Spec.Template.Roles {
+		if role.GPUSharing == nil {
+			continue
+		}
+		sharingPath := field.NewPath("spec").Child("template").Child("roles").Index(i).Child("gpuSharing")
+		switch role.GPUSharing.Mode {
+		case workloadv1alpha1.GPUSharingMIG:
+			if role.GPUSharing.MIGProfile == "" {
+				allErrs = append(allErrs, field.Required(sharingPath.Child("migProfile"), "migProfile is required with the MIG mode"))
+			} else if !migProfilePattern.MatchString(role.GPUSharing.MIGProfile) {
+				allErrs = append(allErrs, field.Invalid(sharingPath.Child("migProfile"), role.GPUSharing.MIGProfile, "must be a MIG profile such as 1g.10gb"))
+			}
+		case workloadv1alpha1.GPUSharingTimeSlicing:
+			if role.GPUSharing.MIGProfile != "" {
+				allErrs = append(allErrs, field.Forbidden(sharingPath.Child("migProfile"), "migProfile is only allowed with the MIG mode"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(sharingPath.Child("mode"), role.GPUSharing.Mode,
+				[]workloadv1alpha1.GPUSharingMode{workloadv1alpha1.GPUSharingMIG, workloadv1alpha1.GPUSharingTimeSlicing}))
+		}
+
+		requested := requestsGPU(&role.EntryTemplate.Spec)
+		if role.WorkerTemplate != nil {
+			requested = requested || requestsGPU(&role.WorkerTemplate.Spec)
+		}
+		if !requested {
+			allErrs = append(allErrs, field.Invalid(sharingPath, role.GPUSharing.Mode,
+				fmt.Sprintf("the containers of the role must request %s to share GPUs", utils.NVIDIAGPUResource)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateMIGProfiles validates that the MIG profiles of the roles are advertised by a node of the cluster
+func (v *ModelServingValidator) validateMIGProfiles(ctx context.Context, mi, old *workloadv1alpha1.ModelServing) field.ErrorList {
+	var allErrs field.ErrorList
+	if v.kubeClient == nil {
+		return allErrs
+	}
+
+	known := sets.New[string]()
+	if old != nil {
+		for _, role := range old.Spec.Template.Roles {
+			if role.GPUSharing != nil && role.GPUSharing.Mode == workloadv1alpha1.GPUSharingMIG {
+				known.Insert(role.GPUSharing.MIGProfile)
+			}
+		}
+	}
+
+	var advertised sets.Set[corev1.ResourceName]
+	for i, role := range mi.Spec.Template.Roles {
+		if role.GPUSharing == nil || role.GPUSharing.Mode != workloadv1alpha1.GPUSharingMIG ||
+			role.GPUSharing.MIGProfile == "" || known.Has(role.GPUSharing.MIGProfile) {
+			continue
+		}
+		profilePath := field.NewPath("spec").Child("template").Child("roles").Index(i).Child("gpuSharing").Child("migProfile")
+		if advertised == nil {
+			var err error
+			if advertised, err = v.advertisedMIGResources(ctx); err != nil {
+				return append(allErrs, field.InternalError(profilePath, fmt.Errorf("failed to list the MIG profiles of the nodes: %v", err)))
+			}
+		}
+		if !advertised.Has(utils.MIGResourceName(role.GPUSharing.MIGProfile)) {
+			allErrs = append(allErrs, field.Invalid(profilePath, role.GPUSharing.MIGProfile,
+				"the MIG profile is not advertised by any node of the cluster with the mixed MIG strategy"))
+		}
+	}
+
+	return allErrs
+}
+
+// advertisedMIGResources returns the MIG resources allocatable on the nodes with the mixed MIG strategy
+func (v *ModelServingValidator) advertisedMIGResources(ctx context.Context) (sets.Set[corev1.ResourceName], error) {
+	nodes, err := v.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: utils.MIGStrategyLabelKey + "=mixed",
+	})
+	if err != nil {
+		return nil, err
+	}
+	resources := sets.New[corev1.ResourceName]()
+	for _, node := range nodes.Items {
+		for name, quantity := range node.Status.Allocatable {
+			if strings.HasPrefix(string(name), utils.NVIDIAMIGResourcePrefix) && !quantity.IsZero() {
+				resources.Insert(name)
+			}
+		}
+	}
+	return resources, nil
+}
+
+// requestsGPU returns whether a container of the pod requests whole NVIDIA GPUs
+func requestsGPU(spec *corev1.PodSpec) bool {
+	for _, container := range spec.Containers {
+		if _, ok := container.Resources.Limits[utils.NVIDIAGPUResource]; ok {
+			return true
+		}
+		if _, ok := container.Resources.Requests[utils.NVIDIAGPUResource]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func validateIntOrPercent(value intstr.IntOrString, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	switch value.Type {
@@ -17,13 +17,18 @@ limitations under the License.
 package webhook
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	volcanov1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 )
 
 func TestValidateScheduler(t *testing.T) {
@@ -45,7 +50,7 @@ func TestValidateScheduler(t *testing.T) {
 				},
 			},
 			want: field.ErrorList{
-				field.Invalid(field.NewPath("spec").Child("schedulerName"), "vo", "invalid SchedulerName: vo, modelServing support: volcano ..."),
+				field.Invalid(field.NewPath("spec").Child("schedulerName"), "vo", "invalid SchedulerName: vo, modelServing support: volcano, kueue, scheduler-plugins-scheduler"),
 			},
 		},
 		{
@@ -58,7 +63,7 @@ func TestValidateScheduler(t *testing.T) {
 				},
 			},
 			want: field.ErrorList{
-				field.Invalid(field.NewPath("spec").Child("schedulerName"), "", "invalid SchedulerName: , modelServing support: volcano ..."),
+				field.Invalid(field.NewPath("spec").Child("schedulerName"), "", "invalid SchedulerName: , modelServing support: volcano, kueue, scheduler-plugins-scheduler"),
 			},
 		},
 		{
@@ -72,6 +77,69 @@ func TestValidateScheduler(t *testing.T) {
 			},
 			want: field.ErrorList(nil),
 		},
+		{
+			name: "coscheduling scheduler",
+			args: args{
+				mi: &workloadv1alpha1.ModelServing{
+					Spec: workloadv1alpha1.ModelServingSpec{
+						SchedulerName: "scheduler-plugins-scheduler",
+						Template: workloadv1alpha1.ServingGroup{
+							GangPolicy: &workloadv1alpha1.GangPolicy{},
+						},
+					},
+				},
+			},
+			want: field.ErrorList(nil),
+		},
+		{
+			name: "kueue scheduler with queue",
+			args: args{
+				mi: &workloadv1alpha1.ModelServing{
+					ObjectMeta: v1.ObjectMeta{
+						Labels: map[string]string{"kueue.x-k8s.io/queue-name": "user-queue"},
+					},
+					Spec: workloadv1alpha1.ModelServingSpec{
+						SchedulerName: "kueue",
+						Template: workloadv1alpha1.ServingGroup{
+							GangPolicy: &workloadv1alpha1.GangPolicy{},
+						},
+					},
+				},
+			},
+			want: field.ErrorList(nil),
+		},
+		{
+			name: "kueue scheduler without queue",
+			args: args{
+				mi: &workloadv1alpha1.ModelServing{
+					Spec: workloadv1alpha1.ModelServingSpec{
+						SchedulerName: "kueue",
+						Template: workloadv1alpha1.ServingGroup{
+							GangPolicy: &workloadv1alpha1.GangPolicy{},
+						},
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.Required(field.NewPath("metadata").Child("labels").Key("kueue.x-k8s.io/queue-name"), "the Kueue LocalQueue must be specified to gang schedule with kueue"),
+			},
+		},
+		{
+			name: "network topology without volcano",
+			args: args{
+				mi: &workloadv1alpha1.ModelServing{
+					Spec: workloadv1alpha1.ModelServingSpec{
+						SchedulerName: "scheduler-plugins-scheduler",
+						Template: workloadv1alpha1.ServingGroup{
+							NetworkTopology: &volcanov1beta1.NetworkTopologySpec{},
+						},
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(field.NewPath("spec").Child("template").Child("networkTopology"), "networkTopology is only supported by volcano"),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -905,3 +973,212 @@ func int32Ptr(i int32) *int32 {
 func int32PtrNil() *int32 {
 	return nil
 }
+
+func TestValidateRolePlacement(t *testing.T) {
+	tests := []struct {
+		name          string
+		placement     *workloadv1alpha1.RolePlacement
+		entrySpread   []corev1.TopologySpreadConstraint
+		wantErrFields []string
+	}{
+		{
+			name: "no placement",
+		},
+		{
+			name: "valid placement",
+			placement: &workloadv1alpha1.RolePlacement{
+				ColocationTopologyKey: "kubernetes.io/hostname",
+				TopologySpread: []workloadv1alpha1.RoleTopologySpread{
+					{TopologyKey: "topology.kubernetes.io/zone"},
+					{TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+				},
+			},
+		},
+		{
+			name: "invalid topology keys",
+			placement: &workloadv1alpha1.RolePlacement{
+				ColocationTopologyKey: "invalid key",
+				TopologySpread: []workloadv1alpha1.RoleTopologySpread{
+					{TopologyKey: "-zone"},
+				},
+			},
+			wantErrFields: []string{
+				"spec.template.roles[0].placement.colocationTopologyKey",
+				"spec.template.roles[0].placement.topologySpread[0].topologyKey",
+			},
+		},
+		{
+			name: "duplicated topology spread",
+			placement: &workloadv1alpha1.RolePlacement{
+				TopologySpread: []workloadv1alpha1.RoleTopologySpread{
+					{TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.ScheduleAnyway},
+					{TopologyKey: "topology.kubernetes.io/zone"},
+				},
+			},
+			wantErrFields: []string{"spec.template.roles[0].placement.topologySpread[1]"},
+		},
+		{
+			name: "topology spread collides with the entry template",
+			placement: &workloadv1alpha1.RolePlacement{
+				TopologySpread: []workloadv1alpha1.RoleTopologySpread{
+					{TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+				},
+			},
+			entrySpread: []corev1.TopologySpreadConstraint{
+				{TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule, MaxSkew: 1},
+			},
+			wantErrFields: []string{"spec.template.roles[0].placement.topologySpread[0]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mi := &workloadv1alpha1.ModelServing{
+				Spec: workloadv1alpha1.ModelServingSpec{
+					Template: workloadv1alpha1.ServingGroup{
+						Roles: []workloadv1alpha1.Role{
+							{
+								Name:      "decode",
+								Placement: tt.placement,
+								EntryTemplate: workloadv1alpha1.PodTemplateSpec{
+									Spec: corev1.PodSpec{TopologySpreadConstraints: tt.entrySpread},
+								},
+							},
+						},
+					},
+				},
+			}
+			var gotFields []string
+			for _, err := range validateRolePlacement(mi) {
+				gotFields = append(gotFields, err.Field)
+			}
+			assert.Equal(t, tt.wantErrFields, gotFields)
+		})
+	}
+}
+
+func newGPUSharingModelServing(sharing *workloadv1alpha1.GPUSharing, gpus bool) *workloadv1alpha1.ModelServing {
+	container := corev1.Container{Name: "engine"}
+	if gpus {
+		container.Resources.Limits = corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}
+	}
+	return &workloadv1alpha1.ModelServing{
+		Spec: workloadv1alpha1.ModelServingSpec{
+			Template: workloadv1alpha1.ServingGroup{
+				Roles: []workloadv1alpha1.Role{
+					{
+						Name:       "decode",
+						GPUSharing: sharing,
+						EntryTemplate: workloadv1alpha1.PodTemplateSpec{
+							Spec: corev1.PodSpec{Containers: []corev1.Container{container}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestValidateGPUSharing(t *testing.T) {
+	tests := []struct {
+		name          string
+		sharing       *workloadv1alpha1.GPUSharing
+		noGPUs        bool
+		wantErrFields []string
+	}{
+		{
+			name: "no sharing",
+		},
+		{
+			name:    "MIG",
+			sharing: &workloadv1alpha1.GPUSharing{Mode: workloadv1alpha1.GPUSharingMIG, MIGProfile: "1g.10gb"},
+		},
+		{
+			name:    "time-slicing",
+			sharing: &workloadv1alpha1.GPUSharing{Mode: workloadv1alpha1.GPUSharingTimeSlicing},
+		},
+		{
+			name:          "MIG without profile",
+			sharing:       &workloadv1alpha1.GPUSharing{Mode: workloadv1alpha1.GPUSharingMIG},
+			wantErrFields: []string{"spec.template.roles[0].gpuSharing.migProfile"},
+		},
+		{
+			name:          "invalid MIG profile",
+			sharing:       &workloadv1alpha1.GPUSharing{Mode: workloadv1alpha1.GPUSharingMIG, MIGProfile: "half"},
+			wantErrFields: []string{"spec.template.roles[0].gpuSharing.migProfile"},
+		},
+		{
+			name:          "time-slicing with profile",
+			sharing:       &workloadv1alpha1.GPUSharing{Mode: workloadv1alpha1.GPUSharingTimeSlicing, MIGProfile: "1g.10gb"},
+			wantErrFields: []string{"spec.template.roles[0].gpuSharing.migProfile"},
+		},
+		{
+			name:          "unknown mode",
+			sharing:       &workloadv1alpha1.GPUSharing{Mode: "MPS"},
+			wantErrFields: []string{"spec.template.roles[0].gpuSharing.mode"},
+		},
+		{
+			name:          "no GPU requested",
+			sharing:       &workloadv1alpha1.GPUSharing{Mode: workloadv1alpha1.GPUSharingTimeSlicing},
+			noGPUs:        true,
+			wantErrFields: []string{"spec.template.roles[0].gpuSharing"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFields []string
+			for _, err := range validateGPUSharing(newGPUSharingModelServing(tt.sharing, !tt.noGPUs)) {
+				gotFields = append(gotFields, err.Field)
+			}
+			assert.Equal(t, tt.wantErrFields, gotFields)
+		})
+	}
+}
+
+func TestValidateMIGProfiles(t *testing.T) {
+	node := func(name, strategy string, allocatable corev1.ResourceList) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: v1.ObjectMeta{Name: name, Labels: map[string]string{"nvidia.com/mig.strategy": strategy}},
+			Status:     corev1.NodeStatus{Allocatable: allocatable},
+		}
+	}
+	kubeClient := kubefake.NewSimpleClientset(
+		node("mixed", "mixed", corev1.ResourceList{"nvidia.com/mig-1g.10gb": resource.MustParse("7"), "nvidia.com/mig-3g.40gb": resource.MustParse("0")}),
+		node("single", "single", corev1.ResourceList{"nvidia.com/mig-2g.20gb": resource.MustParse("3")}),
+	)
+	validator := NewModelServingValidator(kubeClient, nil)
+	mig := func(profile string) *workloadv1alpha1.GPUSharing {
+		return &workloadv1alpha1.GPUSharing{Mode: workloadv1alpha1.GPUSharingMIG, MIGProfile: profile}
+	}
+
+	tests := []struct {
+		name    string
+		sharing *workloadv1alpha1.GPUSharing
+		old     *workloadv1alpha1.GPUSharing
+		wantErr bool
+	}{
+		{name: "advertised profile", sharing: mig("1g.10gb")},
+		{name: "profile not advertised", sharing: mig("7g.80gb"), wantErr: true},
+		{name: "profile fully allocated", sharing: mig("3g.40gb"), wantErr: true},
+		{name: "profile of a node with the single strategy", sharing: mig("2g.20gb"), wantErr: true},
+		{name: "profile already used by the old object", sharing: mig("7g.80gb"), old: mig("7g.80gb")},
+		{name: "time-slicing", sharing: &workloadv1alpha1.GPUSharing{Mode: workloadv1alpha1.GPUSharingTimeSlicing}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var old *workloadv1alpha1.ModelServing
+			if tt.old != nil {
+				old = newGPUSharingModelServing(tt.old, true)
+			}
+			errs := validator.validateMIGProfiles(context.Background(), newGPUSharingModelServing(tt.sharing, true), old)
+			if tt.wantErr {
+				assert.Len(t, errs, 1)
+				assert.Equal(t, "spec.template.roles[0].gpuSharing.migProfile", errs[0].Field)
+			} else {
+				assert.Empty(t, errs)
+			}
+		})
+	}
+
+	// The profiles are not checked without a client
+	assert.Empty(t, NewModelServingValidator(nil, nil).validateMIGProfiles(context.Background(), newGPUSharingModelServing(mig("7g.80gb"), true), nil))
+}
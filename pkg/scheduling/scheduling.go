@@ -0,0 +1,101 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scheduling is the stable Go API of the scheduling of the Kthena router, for the programs embedding it,
+// such as custom gateways or Envoy external processing servers.
+//
+// The pods are tracked by a Store: the Kthena router fills it from the ModelServers, ModelRoutes and Pods of the
+// cluster, an embedding program adds them itself with Store.AddOrUpdateModelServer and Store.AddOrUpdatePod, then
+// calls Store.Run to scrape the metrics of the inference engines, which the score plugins rely on.
+//
+// A Scheduler runs the filter plugins on the pods of a model server, scores the remaining ones with the score
+// plugins and keeps the best ones in the Context, BestPods or, for PD disaggregated model servers, DecodePods and
+// PrefillPods. Once the request is sent to the pod at an index of them, RunPostHooks lets the plugins learn from
+// the decision, e.g. the prefix cache records the prompt. Once the response is forwarded, ObserveResponse lets the
+// plugins learn from the latency of the pod.
+//
+// The extension points are the FilterPlugin, ScorePlugin, PostScheduleHook and ResponseObserver interfaces. A plugin built outside
+// of Kthena is registered by name with RegisterFilterPlugin or RegisterScorePlugin, and enabled like the plugins of
+// Kthena in the scheduler section of the Configuration. A plugin implementing PostScheduleHook too is run after
+// the scheduling, one implementing ResponseObserver after the response.
+//
+// The names of this package are covered by the compatibility promise of the Kthena releases, unlike the packages
+// of the router they refer to.
+package scheduling
+
+import (
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+type (
+	// Scheduler picks the pods serving a request.
+	Scheduler = scheduler.Scheduler
+	// Context is the request being scheduled, and the pods picked for it.
+	Context = framework.Context
+	// Store tracks the model servers, their pods and the metrics of the pods.
+	Store = datastore.Store
+	// PodInfo is a pod with the metrics of its inference engine.
+	PodInfo = datastore.PodInfo
+
+	// FilterPlugin removes the pods which cannot serve the request.
+	FilterPlugin = framework.FilterPlugin
+	// ScorePlugin scores the pods within [0, 100], the scores of the plugins are added with their weights.
+	ScorePlugin = framework.ScorePlugin
+	// PostScheduleHook is run once the request is sent to one of the picked pods.
+	PostScheduleHook = framework.PostScheduleHook
+	// ResponseObserver is passed the responses of the pods.
+	ResponseObserver = framework.ResponseObserver
+	// Response is the latency and the usage of a response of a pod.
+	Response = framework.Response
+	// FilterPluginBuilder creates a filter plugin from its arguments in the Configuration.
+	FilterPluginBuilder = scheduler.FilterPluginBuilder
+	// ScorePluginBuilder creates a score plugin from its arguments in the Configuration.
+	ScorePluginBuilder = scheduler.ScorePluginBuilder
+
+	// Configuration is the configuration of the Kthena router, only its scheduler section is used here.
+	Configuration = conf.RouterConfiguration
+)
+
+// NewStore creates an empty store.
+func NewStore() Store {
+	return datastore.New()
+}
+
+// New creates a scheduler of the pods of the store with the plugins enabled by the configuration, or the default
+// plugins of the Kthena router when it is nil. It fails when the configuration enables an unknown plugin.
+func New(store Store, config *Configuration) (Scheduler, error) {
+	return scheduler.New(store, config)
+}
+
+// ParseConfiguration parses a configuration in the format of the configuration file of the Kthena router.
+func ParseConfiguration(data []byte) (*Configuration, error) {
+	return conf.UnmarshalRouterConfig(data)
+}
+
+// RegisterFilterPlugin registers a filter plugin, so that the schedulers created afterwards can enable it by name.
+// It fails when a plugin of Kthena or another registered plugin has the name.
+func RegisterFilterPlugin(name string, builder FilterPluginBuilder) error {
+	return scheduler.RegisterFilterPlugin(name, builder)
+}
+
+// RegisterScorePlugin registers a score plugin, so that the schedulers created afterwards can enable it by name.
+// It fails when a plugin of Kthena or another registered plugin has the name.
+func RegisterScorePlugin(name string, builder ScorePluginBuilder) error {
+	return scheduler.RegisterScorePlugin(name, builder)
+}
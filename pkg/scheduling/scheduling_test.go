@@ -0,0 +1,104 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+)
+
+// preferPod scores the pod with the given name 100, the others 0.
+type preferPod struct {
+	name string
+}
+
+func (p *preferPod) Name() string {
+	return "prefer-pod"
+}
+
+func (p *preferPod) Score(ctx *Context, pods []*PodInfo) map[*PodInfo]int {
+	scores := make(map[*PodInfo]int, len(pods))
+	for _, pod := range pods {
+		if pod.Pod.Name == p.name {
+			scores[pod] = 100
+		} else {
+			scores[pod] = 0
+		}
+	}
+	return scores
+}
+
+func TestSchedulingWithOutOfTreePlugin(t *testing.T) {
+	require.NoError(t, RegisterScorePlugin("prefer-pod", func(args runtime.RawExtension) ScorePlugin {
+		return &preferPod{name: "pod-2"}
+	}))
+	assert.Error(t, RegisterScorePlugin("prefer-pod", nil))
+	assert.Error(t, RegisterScorePlugin("least-request", nil))
+	assert.Error(t, RegisterFilterPlugin("lora-affinity", nil))
+
+	config, err := ParseConfiguration([]byte(`
+scheduler:
+  plugins:
+    Score:
+      enabled:
+      - name: prefer-pod
+        weight: 1
+`))
+	require.NoError(t, err)
+
+	store := NewStore()
+	modelServer := &aiv1alpha1.ModelServer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "llama"},
+		Spec:       aiv1alpha1.ModelServerSpec{InferenceEngine: aiv1alpha1.VLLM},
+	}
+	require.NoError(t, store.AddOrUpdateModelServer(modelServer, nil))
+	for _, name := range []string{"pod-1", "pod-2", "pod-3"} {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+		require.NoError(t, store.AddOrUpdatePod(pod, []*aiv1alpha1.ModelServer{modelServer}))
+	}
+	modelServerName := types.NamespacedName{Namespace: "default", Name: "llama"}
+	pods, err := store.GetPodsByModelServer(modelServerName)
+	require.NoError(t, err)
+	require.Len(t, pods, 3)
+
+	scheduler, err := New(store, config)
+	require.NoError(t, err)
+	ctx := &Context{Model: "llama", ModelServerName: modelServerName}
+	require.NoError(t, scheduler.Schedule(ctx, pods))
+	require.NotEmpty(t, ctx.BestPods)
+	assert.Equal(t, "pod-2", ctx.BestPods[0].Pod.Name)
+	scheduler.RunPostHooks(ctx, 0)
+
+	unknown, err := ParseConfiguration([]byte(`
+scheduler:
+  plugins:
+    Filter:
+      enabled:
+      - unknown
+`))
+	require.NoError(t, err)
+	_, err = New(store, unknown)
+	assert.EqualError(t, err, `unknown filter plugin "unknown"`)
+}
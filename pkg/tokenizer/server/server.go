@@ -0,0 +1,156 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/tokenization"
+	"github.com/volcano-sh/kthena/pkg/tokenizer/api/v1alpha1"
+)
+
+// Config is the configuration of the tokenizer server.
+type Config struct {
+	Models []ModelConfig `json:"models"`
+}
+
+// ModelConfig configures how the prompts of a model are tokenized. Exactly one
+// of Endpoint and Encoding is set.
+type ModelConfig struct {
+	// Name of the model, as sent by the router.
+	Name string `json:"name"`
+	// Endpoint of an inference engine serving the model, e.g. http://deepseek-r1.default:8000.
+	// Its /tokenize API is used, which applies the chat template of the model.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Encoding of a tiktoken tokenizer loaded in process, e.g. cl100k_base.
+	// It only tokenizes text prompts.
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// LoadConfig reads the configuration of the tokenizer server from a YAML file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Server serves the Tokenizer gRPC service.
+type Server struct {
+	v1alpha1.UnimplementedTokenizerServer
+
+	tokenizers map[string]tokenization.ExtendedTokenizer
+}
+
+// NewServer creates the tokenizers of the configured models.
+func NewServer(config *Config) (*Server, error) {
+	tokenizers := make(map[string]tokenization.ExtendedTokenizer, len(config.Models))
+	for _, model := range config.Models {
+		if model.Name == "" {
+			return nil, fmt.Errorf("model name is required")
+		}
+		if _, ok := tokenizers[model.Name]; ok {
+			return nil, fmt.Errorf("model %s is configured more than once", model.Name)
+		}
+		tokenizer, err := newTokenizer(model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tokenizer of model %s: %w", model.Name, err)
+		}
+		tokenizers[model.Name] = tokenizer
+		klog.Infof("Serving tokenizer of model %s", model.Name)
+	}
+	return &Server{tokenizers: tokenizers}, nil
+}
+
+func newTokenizer(model ModelConfig) (tokenization.ExtendedTokenizer, error) {
+	switch {
+	case model.Endpoint != "" && model.Encoding != "":
+		return nil, fmt.Errorf("endpoint and encoding are mutually exclusive")
+	case model.Endpoint != "":
+		tokenizer, err := tokenization.NewRemoteTokenizer(tokenization.RemoteTokenizerConfig{
+			Engine:   "vllm",
+			Endpoint: model.Endpoint,
+			Model:    model.Name,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return tokenizer.(tokenization.ExtendedTokenizer), nil
+	case model.Encoding != "":
+		return newTiktokenTokenizer(model.Encoding)
+	default:
+		return nil, fmt.Errorf("either endpoint or encoding is required")
+	}
+}
+
+// Tokenize tokenizes a text prompt or chat messages with the tokenizer of a model.
+func (s *Server) Tokenize(ctx context.Context, req *v1alpha1.TokenizeRequest) (*v1alpha1.TokenizeResponse, error) {
+	tokenizer, ok := s.tokenizers[req.Model]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no tokenizer for model %s", req.Model)
+	}
+
+	input := tokenization.TokenizeInput{
+		AddSpecialTokens:    req.AddSpecialTokens,
+		AddGenerationPrompt: req.AddGenerationPrompt,
+	}
+	switch {
+	case req.Text != "" && len(req.Messages) > 0:
+		return nil, status.Error(codes.InvalidArgument, "text and messages are mutually exclusive")
+	case req.Text != "":
+		input.Type = tokenization.CompletionInput
+		input.Text = req.Text
+	case len(req.Messages) > 0:
+		input.Type = tokenization.ChatInput
+		for _, message := range req.Messages {
+			input.Messages = append(input.Messages, common.Message{Role: message.Role, Content: message.Content})
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "either text or messages is required")
+	}
+
+	result, err := tokenizer.TokenizeWithOptions(ctx, input)
+	if err != nil {
+		if errors.Is(err, errChatUnsupported) {
+			return nil, status.Errorf(codes.InvalidArgument, "model %s: %v", req.Model, err)
+		}
+		return nil, status.Errorf(codes.Unavailable, "failed to tokenize prompt of model %s: %v", req.Model, err)
+	}
+
+	tokens := make([]int32, len(result.Tokens))
+	for i, token := range result.Tokens {
+		tokens[i] = int32(token)
+	}
+	return &v1alpha1.TokenizeResponse{
+		Tokens:      tokens,
+		MaxModelLen: int32(result.MaxModelLen),
+	}, nil
+}
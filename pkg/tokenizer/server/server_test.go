@@ -0,0 +1,93 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/volcano-sh/kthena/pkg/tokenizer/api/v1alpha1"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+models:
+- name: deepseek-ai/DeepSeek-R1
+  endpoint: http://deepseek-r1.default:8000
+- name: gpt
+  encoding: cl100k_base
+`), 0o600))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, []ModelConfig{
+		{Name: "deepseek-ai/DeepSeek-R1", Endpoint: "http://deepseek-r1.default:8000"},
+		{Name: "gpt", Encoding: "cl100k_base"},
+	}, config.Models)
+
+	server, err := NewServer(config)
+	require.NoError(t, err)
+	assert.Len(t, server.tokenizers, 2)
+}
+
+func TestNewServerInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		models []ModelConfig
+	}{
+		{name: "missing name", models: []ModelConfig{{Encoding: "cl100k_base"}}},
+		{name: "duplicated model", models: []ModelConfig{{Name: "a", Encoding: "cl100k_base"}, {Name: "a", Encoding: "cl100k_base"}}},
+		{name: "no tokenizer", models: []ModelConfig{{Name: "a"}}},
+		{name: "endpoint and encoding", models: []ModelConfig{{Name: "a", Endpoint: "http://a:8000", Encoding: "cl100k_base"}}},
+		{name: "unknown encoding", models: []ModelConfig{{Name: "a", Encoding: "unknown"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewServer(&Config{Models: tt.models})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	server, err := NewServer(&Config{Models: []ModelConfig{{Name: "gpt", Encoding: "cl100k_base"}}})
+	require.NoError(t, err)
+
+	resp, err := server.Tokenize(context.Background(), &v1alpha1.TokenizeRequest{Model: "gpt", Text: "hello world"})
+	require.NoError(t, err)
+	assert.Equal(t, []int32{15339, 1917}, resp.Tokens)
+
+	_, err = server.Tokenize(context.Background(), &v1alpha1.TokenizeRequest{Model: "unknown", Text: "hello"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = server.Tokenize(context.Background(), &v1alpha1.TokenizeRequest{Model: "gpt"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.Tokenize(context.Background(), &v1alpha1.TokenizeRequest{
+		Model:    "gpt",
+		Messages: []*v1alpha1.Message{{Role: "user", Content: "hello"}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,81 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/pkoukk/tiktoken-go"
+	tiktokenloader "github.com/pkoukk/tiktoken-go-loader"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/tokenization"
+)
+
+var (
+	errChatUnsupported = errors.New("chat messages can not be tokenized without a chat template, configure an endpoint instead of an encoding")
+
+	setBpeLoader sync.Once
+)
+
+// tiktokenTokenizer tokenizes text prompts in process with a tiktoken encoding.
+type tiktokenTokenizer struct {
+	encoding *tiktoken.Tiktoken
+}
+
+func newTiktokenTokenizer(encodingName string) (*tiktokenTokenizer, error) {
+	// The encodings are embedded in the binary, never download them
+	setBpeLoader.Do(func() {
+		tiktoken.SetBpeLoader(tiktokenloader.NewOfflineLoader())
+	})
+	encoding, err := tiktoken.GetEncoding(encodingName)
+	if err != nil {
+		return nil, err
+	}
+	return &tiktokenTokenizer{encoding: encoding}, nil
+}
+
+func (t *tiktokenTokenizer) TokenizeInputText(text string) ([]byte, error) {
+	result, err := t.TokenizeWithOptions(context.Background(), tokenization.TokenizeInput{
+		Type: tokenization.CompletionInput,
+		Text: text,
+	})
+	if err != nil {
+		return nil, err
+	}
+	bytes := make([]byte, len(result.Tokens)*4)
+	for i, token := range result.Tokens {
+		binary.BigEndian.PutUint32(bytes[i*4:(i+1)*4], uint32(token))
+	}
+	return bytes, nil
+}
+
+func (t *tiktokenTokenizer) TokenizeWithOptions(_ context.Context, input tokenization.TokenizeInput) (*tokenization.TokenizeResult, error) {
+	if input.Type != tokenization.CompletionInput {
+		return nil, errChatUnsupported
+	}
+	// Special tokens in the prompt are encoded as plain text, tiktoken encodings add none
+	tokens := t.encoding.Encode(input.Text, nil, nil)
+	return &tokenization.TokenizeResult{
+		Count:  len(tokens),
+		Tokens: tokens,
+	}, nil
+}
+
+var _ tokenization.ExtendedTokenizer = (*tiktokenTokenizer)(nil)
@@ -0,0 +1,128 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package app is the harness shared by the kthena binaries. It runs their components until a
+// termination signal is received, and provides the flags setup, the health endpoints and the
+// leader election they have in common.
+package app
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+)
+
+// Component is a long running part of a binary, such as a server or a controller.
+type Component interface {
+	// Name identifies the component in the logs.
+	Name() string
+	// Run runs the component until the context is done. An error stops the other components.
+	Run(ctx context.Context) error
+}
+
+type componentFunc struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// NewComponent returns a component running the given function.
+func NewComponent(name string, run func(ctx context.Context) error) Component {
+	return &componentFunc{name: name, run: run}
+}
+
+func (c *componentFunc) Name() string {
+	return c.name
+}
+
+func (c *componentFunc) Run(ctx context.Context) error {
+	return c.run(ctx)
+}
+
+// InitFlags adds the klog flags and the flags of the component config to the command line flags, it is called
+// before the flags of the binary are defined.
+func InitFlags() {
+	klog.InitFlags(nil)
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	addComponentConfigFlags(pflag.CommandLine)
+}
+
+// Run logs the command line flags and runs the components until the context is done or a termination signal
+// is received. The components are stopped as soon as one of them fails, and Run returns once all of them
+// have returned. A second termination signal exits immediately.
+func Run(ctx context.Context, components ...Component) error {
+	pflag.CommandLine.VisitAll(func(f *pflag.Flag) {
+		klog.Infof("Flag: %s, Value: %s", f.Name, f.Value.String())
+	})
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	signalCh := make(chan os.Signal, 2)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+	go func() {
+		select {
+		case <-signalCh:
+		case <-ctx.Done():
+			return
+		}
+		klog.Info("Received termination, signaling shutdown")
+		cancel()
+		<-signalCh
+		klog.Info("Received second termination, exiting")
+		klog.Flush()
+		os.Exit(1)
+	}()
+
+	return RunComponents(ctx, components...)
+}
+
+// RunComponents runs the components concurrently until the context is done, the components are stopped as soon
+// as one of them fails. It returns the first error once all of them have returned.
+func RunComponents(ctx context.Context, components ...Component) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for _, c := range components {
+		wg.Add(1)
+		go func(c Component) {
+			defer wg.Done()
+			klog.Infof("Starting %s", c.Name())
+			if err := c.Run(ctx); err != nil {
+				klog.Errorf("%s failed: %v", c.Name(), err)
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			klog.Infof("%s stopped", c.Name())
+		}(c)
+	}
+	wg.Wait()
+	return firstErr
+}
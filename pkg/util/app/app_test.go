@@ -0,0 +1,135 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func blockingComponent(name string, stopped chan<- string) Component {
+	return NewComponent(name, func(ctx context.Context) error {
+		<-ctx.Done()
+		stopped <- name
+		return nil
+	})
+}
+
+func TestRunComponents(t *testing.T) {
+	stopped := make(chan string, 2)
+	failure := errors.New("failed")
+	err := RunComponents(context.Background(),
+		blockingComponent("a", stopped),
+		blockingComponent("b", stopped),
+		NewComponent("failing", func(ctx context.Context) error { return failure }),
+	)
+	assert.ErrorIs(t, err, failure)
+	// The other components are stopped, and have returned
+	assert.ElementsMatch(t, []string{"a", "b"}, []string{<-stopped, <-stopped})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- RunComponents(ctx, blockingComponent("a", stopped))
+	}()
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Equal(t, "a", <-stopped)
+}
+
+func TestHealthServer(t *testing.T) {
+	h := NewHealthServer(":0")
+	var notReady error = errors.New("caches not synced")
+	h.AddReadyCheck("controller", func() error { return notReady })
+
+	get := func(path string) (int, string) {
+		w := httptest.NewRecorder()
+		h.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code, w.Body.String()
+	}
+	code, _ := get("/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	code, body := get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "controller is not ready: caches not synced", body)
+
+	notReady = nil
+	code, _ = get("/readyz")
+	assert.Equal(t, http.StatusOK, code)
+
+	var stalled error
+	h.AddLiveCheck("workqueues", func() error { return stalled })
+	code, _ = get("/livez")
+	assert.Equal(t, http.StatusOK, code)
+	stalled = errors.New("stalled")
+	code, body = get("/livez")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "workqueues is not live: stalled", body)
+
+	// The binary is not ready anymore once it shuts down
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, h.Run(ctx))
+	code, body = get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "shutting down", body)
+}
+
+func TestLeaderElected(t *testing.T) {
+	client := kubefake.NewSimpleClientset()
+	leading := make(chan struct{})
+	stopped := make(chan string, 1)
+	var started, stoppedLeading bool
+	component := LeaderElected(LeaderElection{
+		Client:           client,
+		Name:             "test",
+		Namespace:        "default",
+		LeaseName:        "lease.test",
+		RetryPeriod:      10 * time.Millisecond,
+		OnStartedLeading: func() { started = true },
+		OnStoppedLeading: func() { stoppedLeading = true },
+	}, NewComponent("controller", func(ctx context.Context) error {
+		close(leading)
+		<-ctx.Done()
+		stopped <- "controller"
+		return nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- component.Run(ctx)
+	}()
+	select {
+	case <-leading:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the lease was not acquired")
+	}
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Equal(t, "controller", <-stopped)
+	assert.True(t, started)
+	assert.True(t, stoppedLeading)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/volcano-sh/kthena/pkg/features"
+)
+
+// DefaultComponentConfigFile is where the ConfigMap of the component config is mounted by the Helm chart.
+const DefaultComponentConfigFile = "/etc/kthena/component-config/config.yaml"
+
+// otelEndpointEnv is the endpoint of the OpenTelemetry exporters, read by the OpenTelemetry SDKs.
+const otelEndpointEnv = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+// ComponentConfig is the configuration shared by the kthena binaries, so that all the components are tuned
+// consistently. It is read from a file, the ConfigMap rendered from the componentConfig values of the Helm chart.
+// The flags set on the command line of a binary take precedence over it.
+type ComponentConfig struct {
+	// LogLevel is the verbosity of the logs, as the -v flag.
+	LogLevel *int `json:"logLevel,omitempty"`
+	// FeatureGates enables or disables the gated features, as the --feature-gates flag.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// MetricsBindAddress is the address the Prometheus metrics are served on, for the binaries with a
+	// --metrics-bind-address flag.
+	MetricsBindAddress *string `json:"metricsBindAddress,omitempty"`
+	// Tracing configures the export of the traces.
+	Tracing *TracingConfig `json:"tracing,omitempty"`
+	// Components overrides the settings above for a binary, by its name such as kthena-router.
+	Components map[string]ComponentConfig `json:"components,omitempty"`
+}
+
+// TracingConfig configures the export of the traces.
+type TracingConfig struct {
+	// Endpoint is the OTLP endpoint the traces are exported to. It is exported to the binary as
+	// OTEL_EXPORTER_OTLP_ENDPOINT, unless it is already set.
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+var (
+	componentConfigFile string
+	featureGates        string
+)
+
+// addComponentConfigFlags adds the flags of the component config and of the feature gates.
+func addComponentConfigFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&componentConfigFile, "component-config", DefaultComponentConfigFile,
+		"Path to the component config shared by the kthena binaries, it is ignored when the file does not exist")
+	flags.StringVar(&featureGates, "feature-gates", "",
+		"A set of key=value pairs enabling or disabling gated features, they take precedence over the component config. Options are:\n"+
+			"  "+strings.Join(features.DefaultFeatureGate.KnownFeatures(), "\n  "))
+}
+
+// ApplyComponentConfig reads the component config and applies its settings for the named binary to the flags
+// which are not set on the command line and to the feature gates. It is called once the flags are parsed, and
+// returns the settings of the binary.
+func ApplyComponentConfig(component string) (*ComponentConfig, error) {
+	config, err := LoadComponentConfig(componentConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	config = config.For(component)
+	if err := config.apply(pflag.CommandLine); err != nil {
+		return nil, err
+	}
+	if featureGates != "" {
+		if err := features.DefaultMutableFeatureGate.Set(featureGates); err != nil {
+			return nil, fmt.Errorf("invalid --feature-gates: %v", err)
+		}
+	}
+	klog.Infof("Feature gates: %s", features.DefaultMutableFeatureGate.String())
+	return config, nil
+}
+
+// LoadComponentConfig reads the component config file. A missing file is an empty config.
+func LoadComponentConfig(path string) (*ComponentConfig, error) {
+	config := &ComponentConfig{}
+	if path == "" {
+		return config, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		klog.V(2).Infof("Component config %s does not exist, using the flags only", path)
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read component config %s: %v", path, err)
+	}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse component config %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// For returns the settings of the named binary: the shared settings, overridden by those of the binary.
+func (c *ComponentConfig) For(component string) *ComponentConfig {
+	config := &ComponentConfig{
+		LogLevel:           c.LogLevel,
+		MetricsBindAddress: c.MetricsBindAddress,
+		Tracing:            c.Tracing,
+		FeatureGates:       make(map[string]bool, len(c.FeatureGates)),
+	}
+	for name, enabled := range c.FeatureGates {
+		config.FeatureGates[name] = enabled
+	}
+	override, ok := c.Components[component]
+	if !ok {
+		return config
+	}
+	if override.LogLevel != nil {
+		config.LogLevel = override.LogLevel
+	}
+	if override.MetricsBindAddress != nil {
+		config.MetricsBindAddress = override.MetricsBindAddress
+	}
+	if override.Tracing != nil {
+		config.Tracing = override.Tracing
+	}
+	for name, enabled := range override.FeatureGates {
+		config.FeatureGates[name] = enabled
+	}
+	return config
+}
+
+// apply sets the flags which are not set on the command line, the feature gates and the tracing environment.
+func (c *ComponentConfig) apply(flags *pflag.FlagSet) error {
+	if c.LogLevel != nil {
+		if err := setUnchangedFlag(flags, "v", strconv.Itoa(*c.LogLevel)); err != nil {
+			return err
+		}
+	}
+	if c.MetricsBindAddress != nil {
+		if err := setUnchangedFlag(flags, "metrics-bind-address", *c.MetricsBindAddress); err != nil {
+			return err
+		}
+	}
+	if err := features.DefaultMutableFeatureGate.SetFromMap(c.FeatureGates); err != nil {
+		return fmt.Errorf("invalid featureGates in the component config: %v", err)
+	}
+	if c.Tracing != nil && c.Tracing.Endpoint != "" && os.Getenv(otelEndpointEnv) == "" {
+		if err := os.Setenv(otelEndpointEnv, c.Tracing.Endpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setUnchangedFlag sets a flag of the binary, unless it is set on the command line. The flags the binary does
+// not have are ignored.
+func setUnchangedFlag(flags *pflag.FlagSet, name, value string) error {
+	flag := flags.Lookup(name)
+	if flag == nil || flag.Changed {
+		return nil
+	}
+	if err := flag.Value.Set(value); err != nil {
+		return fmt.Errorf("invalid value %q of flag --%s in the component config: %v", value, name, err)
+	}
+	return nil
+}
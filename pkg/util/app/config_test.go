@@ -0,0 +1,100 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/volcano-sh/kthena/pkg/features"
+)
+
+func TestLoadComponentConfig(t *testing.T) {
+	config, err := LoadComponentConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, &ComponentConfig{}, config)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+logLevel: 2
+featureGates:
+  InPlacePodUpdate: false
+metricsBindAddress: ":8080"
+tracing:
+  endpoint: http://collector:4317
+components:
+  kthena-router:
+    logLevel: 4
+    featureGates:
+      InPlacePodUpdate: true
+`), 0o600))
+	config, err = LoadComponentConfig(path)
+	require.NoError(t, err)
+
+	router := config.For("kthena-router")
+	assert.Equal(t, ptr.To(4), router.LogLevel)
+	assert.Equal(t, ptr.To(":8080"), router.MetricsBindAddress)
+	assert.Equal(t, "http://collector:4317", router.Tracing.Endpoint)
+	assert.Equal(t, map[string]bool{"InPlacePodUpdate": true}, router.FeatureGates)
+	assert.Nil(t, router.Components)
+
+	webhook := config.For("kthena-webhook")
+	assert.Equal(t, ptr.To(2), webhook.LogLevel)
+	assert.Equal(t, map[string]bool{"InPlacePodUpdate": false}, webhook.FeatureGates)
+	// The overrides of a component do not change the shared settings
+	assert.Equal(t, map[string]bool{"InPlacePodUpdate": false}, config.FeatureGates)
+
+	require.NoError(t, os.WriteFile(path, []byte("logLevels: 2\n"), 0o600))
+	_, err = LoadComponentConfig(path)
+	assert.Error(t, err)
+}
+
+func TestApplyComponentConfig(t *testing.T) {
+	defer func() {
+		require.NoError(t, features.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(features.InPlacePodUpdate): true}))
+	}()
+	t.Setenv(otelEndpointEnv, "")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	verbosity := flags.Int("v", 0, "")
+	metricsAddr := flags.String("metrics-bind-address", ":8080", "")
+	require.NoError(t, flags.Parse([]string{"--metrics-bind-address=:9090"}))
+
+	config := &ComponentConfig{
+		LogLevel:           ptr.To(3),
+		MetricsBindAddress: ptr.To(":8081"),
+		FeatureGates:       map[string]bool{string(features.InPlacePodUpdate): false},
+		Tracing:            &TracingConfig{Endpoint: "http://collector:4317"},
+	}
+	require.NoError(t, config.apply(flags))
+	assert.Equal(t, 3, *verbosity)
+	// The flags set on the command line take precedence
+	assert.Equal(t, ":9090", *metricsAddr)
+	assert.False(t, features.DefaultFeatureGate.Enabled(features.InPlacePodUpdate))
+	assert.Equal(t, "http://collector:4317", os.Getenv(otelEndpointEnv))
+
+	config = &ComponentConfig{FeatureGates: map[string]bool{"UnknownFeature": true}}
+	assert.Error(t, config.apply(flags))
+	config = &ComponentConfig{LogLevel: ptr.To(-1)}
+	assert.NoError(t, config.apply(pflag.NewFlagSet("empty", pflag.ContinueOnError)))
+}
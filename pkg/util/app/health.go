@@ -0,0 +1,153 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const healthShutdownTimeout = 5 * time.Second
+
+type healthCheck struct {
+	name  string
+	check func() error
+}
+
+// HealthServer is a component serving /healthz, which succeeds while the binary runs, /livez, which succeeds
+// while all its liveness checks pass, and /readyz, which succeeds once all its readiness checks pass and until
+// the binary shuts down.
+type HealthServer struct {
+	addr string
+
+	mu          sync.RWMutex
+	readyChecks []healthCheck
+	liveChecks  []healthCheck
+	stopping    atomic.Bool
+}
+
+// NewHealthServer creates a health server listening on addr.
+func NewHealthServer(addr string) *HealthServer {
+	return &HealthServer{addr: addr}
+}
+
+// AddReadyCheck adds a check to /readyz, the binary is not ready while the check returns an error.
+func (h *HealthServer) AddReadyCheck(name string, check func() error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readyChecks = append(h.readyChecks, healthCheck{name: name, check: check})
+}
+
+// AddLiveCheck adds a check to /livez, the binary is restarted by Kubernetes while the check returns an error.
+func (h *HealthServer) AddLiveCheck(name string, check func() error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.liveChecks = append(h.liveChecks, healthCheck{name: name, check: check})
+}
+
+// Name implements Component.
+func (h *HealthServer) Name() string {
+	return "health server"
+}
+
+// Handler returns the handler of the health endpoints.
+func (h *HealthServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, http.StatusOK, "ok")
+	})
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		if err := h.live(); err != nil {
+			writeHealth(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		writeHealth(w, http.StatusOK, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := h.ready(); err != nil {
+			writeHealth(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		writeHealth(w, http.StatusOK, "ok")
+	})
+	return mux
+}
+
+func (h *HealthServer) ready() error {
+	if h.stopping.Load() {
+		return errors.New("shutting down")
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, c := range h.readyChecks {
+		if err := c.check(); err != nil {
+			return fmt.Errorf("%s is not ready: %v", c.name, err)
+		}
+	}
+	return nil
+}
+
+func (h *HealthServer) live() error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, c := range h.liveChecks {
+		if err := c.check(); err != nil {
+			return fmt.Errorf("%s is not live: %v", c.name, err)
+		}
+	}
+	return nil
+}
+
+// Run implements Component, it serves the health endpoints until the context is done.
+func (h *HealthServer) Run(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              h.addr,
+		Handler:           h.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		klog.Infof("Starting health server on %s", h.addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("failed to serve health endpoints: %w", err)
+	case <-ctx.Done():
+	}
+	h.stopping.Store(true)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), healthShutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+func writeHealth(w http.ResponseWriter, code int, message string) {
+	w.WriteHeader(code)
+	if _, err := w.Write([]byte(message)); err != nil {
+		klog.Errorf("failed to write health check response: %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewDeadline = 10 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+)
+
+// LeaderElection configures the election of the replica running the leader elected components.
+type LeaderElection struct {
+	Client kubernetes.Interface
+	// Name is the name of the election, Namespace and LeaseName locate its lease.
+	Name      string
+	Namespace string
+	LeaseName string
+	// LeaseDuration, RenewDeadline and RetryPeriod default to 15s, 10s and 2s.
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+	// OnStartedLeading and OnStoppedLeading are called when the replica becomes and stops being the leader.
+	OnStartedLeading func()
+	OnStoppedLeading func()
+	// Recampaign makes the replica campaign again when it loses the lease, instead of failing. It is used by
+	// binaries whose other components keep serving, the leader elected components must then be restartable.
+	Recampaign bool
+}
+
+// errLeaderElectionLost is returned by the leader elected components when the lease is lost, the binary
+// exits so that its replica restarts as a candidate.
+var errLeaderElectionLost = errors.New("leader election lost")
+
+type leaderElected struct {
+	config     LeaderElection
+	components []Component
+}
+
+// LeaderElected returns a component running the given components only while the replica is the leader.
+func LeaderElected(config LeaderElection, components ...Component) Component {
+	if config.LeaseDuration == 0 {
+		config.LeaseDuration = defaultLeaseDuration
+	}
+	if config.RenewDeadline == 0 {
+		config.RenewDeadline = defaultRenewDeadline
+	}
+	if config.RetryPeriod == 0 {
+		config.RetryPeriod = defaultRetryPeriod
+	}
+	return &leaderElected{config: config, components: components}
+}
+
+func (l *leaderElected) Name() string {
+	return fmt.Sprintf("leader election %s", l.config.Name)
+}
+
+func (l *leaderElected) Run(ctx context.Context) error {
+	lock, err := l.newResourceLock()
+	if err != nil {
+		return err
+	}
+	for {
+		err := l.campaign(ctx, lock)
+		if !errors.Is(err, errLeaderElectionLost) || !l.config.Recampaign {
+			return err
+		}
+		klog.Warningf("Lost the lease of %s, campaigning again", l.config.Name)
+	}
+}
+
+// campaign runs the components once the lease is acquired, until it is lost or the context is done.
+func (l *leaderElected) campaign(ctx context.Context, lock resourcelock.Interface) error {
+
+	// The elector calls OnStartedLeading in a goroutine, the components are run from here instead
+	// so that they have returned when Run returns.
+	leading := make(chan context.Context, 1)
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: l.config.LeaseDuration,
+		RenewDeadline: l.config.RenewDeadline,
+		RetryPeriod:   l.config.RetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				leading <- ctx
+			},
+			OnStoppedLeading: func() {
+				klog.Infof("Stopped leading %s", l.config.Name)
+				if l.config.OnStoppedLeading != nil {
+					l.config.OnStoppedLeading()
+				}
+			},
+		},
+		ReleaseOnCancel: false,
+		Name:            l.config.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	electionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	electorDone := make(chan struct{})
+	go func() {
+		defer close(electorDone)
+		elector.Run(electionCtx)
+	}()
+
+	select {
+	case leadingCtx := <-leading:
+		klog.Infof("Started leading %s", l.config.Name)
+		if l.config.OnStartedLeading != nil {
+			l.config.OnStartedLeading()
+		}
+		err := RunComponents(leadingCtx, l.components...)
+		lost := leadingCtx.Err() != nil && ctx.Err() == nil
+		// Stop renewing the lease once the components have stopped
+		cancel()
+		<-electorDone
+		if err != nil {
+			return err
+		}
+		if lost {
+			return errLeaderElectionLost
+		}
+		return nil
+	case <-electorDone:
+		// The lease was lost before the components were started
+		if ctx.Err() == nil {
+			return errLeaderElectionLost
+		}
+		return nil
+	}
+}
+
+// newResourceLock returns the lease lock of the election, with an identity unique to the replica.
+func (l *leaderElected) newResourceLock() (*resourcelock.LeaseLock, error) {
+	id, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	id = id + "_" + string(uuid.NewUUID())
+	return &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      l.config.LeaseName,
+			Namespace: l.config.Namespace,
+		},
+		Client: l.config.Client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: id,
+		},
+	}, nil
+}
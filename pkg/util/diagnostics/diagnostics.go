@@ -0,0 +1,315 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diagnostics serves the Go runtime profiles of the kthena binaries, and captures bundles of profiles on
+// demand, so that a performance regression, e.g. a spike of the scheduling latency, can be diagnosed in production.
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	rpprof "runtime/pprof"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultCaptureDuration is how long the CPU is profiled when the duration of a capture is not set.
+	DefaultCaptureDuration = 30 * time.Second
+	// MaxCaptureDuration is the longest CPU profile of a capture.
+	MaxCaptureDuration = 5 * time.Minute
+	// DefaultDir is the directory the bundles are kept in by default.
+	DefaultDir = "/tmp/kthena-profiles"
+
+	bundleSuffix = ".tar.gz"
+	// maxBundles is the number of bundles kept in the directory, the oldest ones are deleted.
+	maxBundles    = 10
+	uploadTimeout = time.Minute
+)
+
+// ErrCaptureInProgress is returned when a capture is requested while another one runs, the CPU can only be
+// profiled once at a time.
+var ErrCaptureInProgress = errors.New("a profile capture is already in progress")
+
+// errInvalidCapture wraps the errors of the capture parameters.
+var errInvalidCapture = errors.New("invalid capture")
+
+// PprofHandler serves the net/http/pprof endpoints under /debug/pprof/.
+func PprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// Bundle describes a captured bundle of profiles.
+type Bundle struct {
+	Name string `json:"name"`
+	// Location is the path of the bundle in the directory, or the URL it was uploaded to.
+	Location   string    `json:"location"`
+	Size       int64     `json:"size"`
+	CapturedAt time.Time `json:"capturedAt"`
+}
+
+// Capturer captures the bundles of profiles of a binary, one at a time. A bundle is a gzipped tarball of the CPU
+// profile over the capture duration, the heap and goroutine profiles taken at its end, and the runtime statistics.
+type Capturer struct {
+	component string
+	dir       string
+	client    *http.Client
+	running   atomic.Bool
+}
+
+// NewCapturer creates a capturer for the component, keeping the bundles in dir.
+func NewCapturer(component, dir string) *Capturer {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	return &Capturer{
+		component: component,
+		dir:       dir,
+		client:    &http.Client{Timeout: uploadTimeout},
+	}
+}
+
+// Capture captures a bundle, profiling the CPU for the duration. The bundle is uploaded with a PUT to uploadURL,
+// e.g. a pre-signed URL of an object storage bucket, or kept in the directory when it is empty.
+func (c *Capturer) Capture(ctx context.Context, duration time.Duration, uploadURL string) (*Bundle, error) {
+	if duration <= 0 {
+		duration = DefaultCaptureDuration
+	}
+	if duration > MaxCaptureDuration {
+		return nil, fmt.Errorf("%w: the duration must be at most %s", errInvalidCapture, MaxCaptureDuration)
+	}
+	var target *url.URL
+	if uploadURL != "" {
+		var err error
+		if target, err = url.Parse(uploadURL); err != nil || (target.Scheme != "http" && target.Scheme != "https") {
+			return nil, fmt.Errorf("%w: the upload URL must be an http or https URL", errInvalidCapture)
+		}
+	}
+	if !c.running.CompareAndSwap(false, true) {
+		return nil, ErrCaptureInProgress
+	}
+	defer c.running.Store(false)
+
+	capturedAt := time.Now().UTC()
+	klog.Infof("Capturing a profile bundle, the CPU is profiled for %s", duration)
+	data, err := c.capture(ctx, duration)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &Bundle{
+		Name:       fmt.Sprintf("%s-%s%s", c.component, capturedAt.Format("20060102T150405Z"), bundleSuffix),
+		Size:       int64(len(data)),
+		CapturedAt: capturedAt,
+	}
+
+	if target != nil {
+		if err := c.upload(ctx, target, data); err != nil {
+			return nil, err
+		}
+		// The query of a pre-signed URL is a credential
+		target.RawQuery = ""
+		bundle.Location = target.String()
+	} else {
+		if err := os.MkdirAll(c.dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create the profile directory: %v", err)
+		}
+		bundle.Location = filepath.Join(c.dir, bundle.Name)
+		if err := os.WriteFile(bundle.Location, data, 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write the profile bundle: %v", err)
+		}
+		c.prune()
+	}
+	klog.Infof("Captured profile bundle %s to %s", bundle.Name, bundle.Location)
+	return bundle, nil
+}
+
+func (c *Capturer) capture(ctx context.Context, duration time.Duration) ([]byte, error) {
+	var cpu bytes.Buffer
+	if err := rpprof.StartCPUProfile(&cpu); err != nil {
+		return nil, fmt.Errorf("failed to start the CPU profile: %v", err)
+	}
+	timer := time.NewTimer(duration)
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		timer.Stop()
+		rpprof.StopCPUProfile()
+		return nil, ctx.Err()
+	}
+	rpprof.StopCPUProfile()
+
+	var heap, goroutines, goroutineStacks bytes.Buffer
+	runtime.GC()
+	if err := rpprof.Lookup("heap").WriteTo(&heap, 0); err != nil {
+		return nil, fmt.Errorf("failed to write the heap profile: %v", err)
+	}
+	if err := rpprof.Lookup("goroutine").WriteTo(&goroutines, 0); err != nil {
+		return nil, fmt.Errorf("failed to write the goroutine profile: %v", err)
+	}
+	if err := rpprof.Lookup("goroutine").WriteTo(&goroutineStacks, 2); err != nil {
+		return nil, fmt.Errorf("failed to write the goroutine stacks: %v", err)
+	}
+	stats, err := json.MarshalIndent(runtimeStats(c.component, duration), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	gz := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{"cpu.pprof", cpu.Bytes()},
+		{"heap.pprof", heap.Bytes()},
+		{"goroutine.pprof", goroutines.Bytes()},
+		{"goroutine.txt", goroutineStacks.Bytes()},
+		{"runtime.json", stats},
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: file.name, Mode: 0o600, Size: int64(len(file.data)), ModTime: now}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func runtimeStats(component string, duration time.Duration) map[string]interface{} {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return map[string]interface{}{
+		"component":       component,
+		"cpuProfile":      duration.String(),
+		"goVersion":       runtime.Version(),
+		"numCPU":          runtime.NumCPU(),
+		"gomaxprocs":      runtime.GOMAXPROCS(0),
+		"goroutines":      runtime.NumGoroutine(),
+		"heapAllocBytes":  mem.HeapAlloc,
+		"heapInuseBytes":  mem.HeapInuse,
+		"heapObjects":     mem.HeapObjects,
+		"sysBytes":        mem.Sys,
+		"numGC":           mem.NumGC,
+		"gcPauseTotalNs":  mem.PauseTotalNs,
+		"gcCPUFraction":   mem.GCCPUFraction,
+		"nextGCBytes":     mem.NextGC,
+		"stackInuseBytes": mem.StackInuse,
+	}
+}
+
+func (c *Capturer) upload(ctx context.Context, target *url.URL, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload the profile bundle: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to upload the profile bundle: %s", resp.Status)
+	}
+	return nil
+}
+
+// List returns the bundles kept in the directory, the newest first.
+func (c *Capturer) List() ([]Bundle, error) {
+	entries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return []Bundle{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	bundles := []Bundle{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), bundleSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		bundles = append(bundles, Bundle{
+			Name:       entry.Name(),
+			Location:   filepath.Join(c.dir, entry.Name()),
+			Size:       info.Size(),
+			CapturedAt: info.ModTime().UTC(),
+		})
+	}
+	sort.Slice(bundles, func(i, j int) bool {
+		return bundles[i].CapturedAt.After(bundles[j].CapturedAt)
+	})
+	return bundles, nil
+}
+
+// Path returns the path of a bundle kept in the directory.
+func (c *Capturer) Path(name string) (string, error) {
+	if name != filepath.Base(name) || !strings.HasSuffix(name, bundleSuffix) {
+		return "", fmt.Errorf("invalid bundle name %q", name)
+	}
+	path := filepath.Join(c.dir, name)
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// prune deletes the oldest bundles beyond maxBundles.
+func (c *Capturer) prune() {
+	bundles, err := c.List()
+	if err != nil {
+		klog.Errorf("failed to list the profile bundles: %v", err)
+		return
+	}
+	for i := maxBundles; i < len(bundles); i++ {
+		if err := os.Remove(bundles[i].Location); err != nil {
+			klog.Errorf("failed to delete profile bundle %s: %v", bundles[i].Name, err)
+		}
+	}
+}
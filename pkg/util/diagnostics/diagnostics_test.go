@@ -0,0 +1,183 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bundleFiles(t *testing.T, data []byte) map[string][]byte {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	files := make(map[string][]byte)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = content
+	}
+	return files
+}
+
+func TestCaptureToDirectory(t *testing.T) {
+	dir := t.TempDir()
+	capturer := NewCapturer("kthena-router", dir)
+
+	bundle, err := capturer.Capture(context.Background(), 100*time.Millisecond, "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, bundle.Name), bundle.Location)
+	assert.Regexp(t, `^kthena-router-\d{8}T\d{6}Z\.tar\.gz$`, bundle.Name)
+
+	data, err := os.ReadFile(bundle.Location)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), bundle.Size)
+	files := bundleFiles(t, data)
+	for _, name := range []string{"cpu.pprof", "heap.pprof", "goroutine.pprof", "goroutine.txt", "runtime.json"} {
+		assert.NotEmpty(t, files[name], name)
+	}
+	var stats map[string]interface{}
+	require.NoError(t, json.Unmarshal(files["runtime.json"], &stats))
+	assert.Equal(t, "kthena-router", stats["component"])
+	assert.Contains(t, string(files["goroutine.txt"]), "TestCaptureToDirectory")
+
+	bundles, err := capturer.List()
+	require.NoError(t, err)
+	require.Len(t, bundles, 1)
+	assert.Equal(t, bundle.Name, bundles[0].Name)
+}
+
+func TestCaptureInProgress(t *testing.T) {
+	capturer := NewCapturer("kthena-router", t.TempDir())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := capturer.Capture(ctx, time.Minute, "")
+		done <- err
+	}()
+	require.Eventually(t, capturer.running.Load, time.Second, 10*time.Millisecond)
+
+	_, err := capturer.Capture(context.Background(), time.Second, "")
+	assert.ErrorIs(t, err, ErrCaptureInProgress)
+	assert.Equal(t, http.StatusConflict, StatusOf(err))
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.False(t, capturer.running.Load())
+}
+
+func TestCaptureInvalid(t *testing.T) {
+	capturer := NewCapturer("kthena-router", t.TempDir())
+
+	_, err := capturer.Capture(context.Background(), 10*time.Minute, "")
+	assert.Equal(t, http.StatusBadRequest, StatusOf(err))
+	_, err = capturer.Capture(context.Background(), time.Second, "file:///etc/passwd")
+	assert.Equal(t, http.StatusBadRequest, StatusOf(err))
+}
+
+func TestCaptureUpload(t *testing.T) {
+	var uploaded []byte
+	var contentType string
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		contentType = r.Header.Get("Content-Type")
+		uploaded, _ = io.ReadAll(r.Body)
+	}))
+	defer storage.Close()
+	dir := t.TempDir()
+	capturer := NewCapturer("kthena-controller-manager", dir)
+
+	bundle, err := capturer.Capture(context.Background(), 50*time.Millisecond, storage.URL+"/bucket/profile.tar.gz?X-Amz-Signature=secret")
+	require.NoError(t, err)
+	assert.Equal(t, storage.URL+"/bucket/profile.tar.gz", bundle.Location)
+	assert.Equal(t, "application/gzip", contentType)
+	assert.Equal(t, int64(len(uploaded)), bundle.Size)
+	assert.Contains(t, bundleFiles(t, uploaded), "cpu.pprof")
+
+	bundles, err := capturer.List()
+	require.NoError(t, err)
+	assert.Empty(t, bundles)
+}
+
+func TestCaptureUploadFailed(t *testing.T) {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer storage.Close()
+	capturer := NewCapturer("kthena-router", t.TempDir())
+
+	_, err := capturer.Capture(context.Background(), 50*time.Millisecond, storage.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.Equal(t, http.StatusInternalServerError, StatusOf(err))
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	capturer := NewCapturer("kthena-router", dir)
+	now := time.Now()
+	for i := 0; i < maxBundles+2; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("kthena-router-%02d.tar.gz", i))
+		require.NoError(t, os.WriteFile(path, []byte("bundle"), 0o600))
+		modTime := now.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o600))
+
+	capturer.prune()
+
+	bundles, err := capturer.List()
+	require.NoError(t, err)
+	require.Len(t, bundles, maxBundles)
+	assert.Equal(t, fmt.Sprintf("kthena-router-%02d.tar.gz", maxBundles+1), bundles[0].Name)
+	assert.Equal(t, "kthena-router-02.tar.gz", bundles[maxBundles-1].Name)
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+}
+
+func TestPath(t *testing.T) {
+	dir := t.TempDir()
+	capturer := NewCapturer("kthena-router", dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kthena-router-1.tar.gz"), []byte("bundle"), 0o600))
+
+	path, err := capturer.Path("kthena-router-1.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "kthena-router-1.tar.gz"), path)
+
+	for _, name := range []string{"../kthena-router-1.tar.gz", "kthena-router-2.tar.gz", "runtime.json", ""} {
+		_, err := capturer.Path(name)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ProfilesPath is the path capturing and listing the bundles, a bundle is downloaded at ProfilesPath/<name>.
+	ProfilesPath = "/debug/profiles"
+
+	shutdownTimeout = 5 * time.Second
+)
+
+// Server is a component serving the pprof endpoints and the captures of bundles, for the binaries which have no
+// admin API. It has no authentication, so it should listen on the loopback address and be reached with a
+// port-forward.
+type Server struct {
+	addr     string
+	capturer *Capturer
+}
+
+// NewServer creates a diagnostics server listening on addr.
+func NewServer(addr string, capturer *Capturer) *Server {
+	return &Server{addr: addr, capturer: capturer}
+}
+
+// Name implements app.Component.
+func (s *Server) Name() string {
+	return "diagnostics server"
+}
+
+// Handler returns the handler of the pprof endpoints and the captures:
+// POST /debug/profiles?seconds=30&upload_url=<url> captures a bundle, GET /debug/profiles lists the bundles
+// and GET /debug/profiles/<name> downloads one.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/pprof/", PprofHandler())
+	mux.HandleFunc(ProfilesPath, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			bundles, err := s.capturer.List()
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, bundles)
+		case http.MethodPost:
+			duration, err := ParseSeconds(r.URL.Query().Get("seconds"))
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			bundle, err := s.capturer.Capture(r.Context(), duration, r.URL.Query().Get("upload_url"))
+			if err != nil {
+				writeJSON(w, StatusOf(err), map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, bundle)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc(ProfilesPath+"/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, ProfilesPath+"/")
+		path, err := s.capturer.Path(name)
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("bundle %s not found", name)})
+			return
+		}
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+		http.ServeFile(w, r, path)
+	})
+	return mux
+}
+
+// Run implements app.Component, it serves the diagnostics endpoints until the context is done.
+func (s *Server) Run(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		klog.Infof("Starting diagnostics server on %s", s.addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("failed to serve diagnostics endpoints: %w", err)
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// ParseSeconds parses the duration of a capture in seconds, empty for the default duration.
+func ParseSeconds(seconds string) (time.Duration, error) {
+	if seconds == "" {
+		return DefaultCaptureDuration, nil
+	}
+	n, err := strconv.Atoi(seconds)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("seconds must be a positive integer, got %q", seconds)
+	}
+	return time.Duration(n) * time.Second, nil
+}
+
+// StatusOf returns the HTTP status of a capture error.
+func StatusOf(err error) int {
+	switch {
+	case errors.Is(err, ErrCaptureInProgress):
+		return http.StatusConflict
+	case errors.Is(err, errInvalidCapture):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		klog.Errorf("failed to write diagnostics response: %v", err)
+	}
+}
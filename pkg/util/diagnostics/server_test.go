@@ -0,0 +1,81 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerHandler(t *testing.T) {
+	dir := t.TempDir()
+	handler := NewServer(":0", NewCapturer("kthena-controller-manager", dir)).Handler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "goroutine")
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ProfilesPath, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, ProfilesPath+"?seconds=abc", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kthena-controller-manager-1.tar.gz"), []byte("bundle"), 0o600))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ProfilesPath, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var bundles []Bundle
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bundles))
+	require.Len(t, bundles, 1)
+	assert.Equal(t, "kthena-controller-manager-1.tar.gz", bundles[0].Name)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ProfilesPath+"/kthena-controller-manager-1.tar.gz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "bundle", w.Body.String())
+	assert.Equal(t, "application/gzip", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ProfilesPath+"/missing.tar.gz", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestParseSeconds(t *testing.T) {
+	duration, err := ParseSeconds("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCaptureDuration, duration)
+	duration, err = ParseSeconds("10")
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, duration)
+	_, err = ParseSeconds("0")
+	assert.Error(t, err)
+	_, err = ParseSeconds("1m")
+	assert.Error(t, err)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package featuregate gates the features of the kthena binaries which are not generally available, in the way
+// of the Kubernetes feature gates. The features are registered with their default and maturity, and enabled or
+// disabled with the --feature-gates flag or the featureGates of the component config.
+package featuregate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Feature is the name of a gated feature.
+type Feature string
+
+// PreRelease is the maturity of a feature.
+type PreRelease string
+
+const (
+	Alpha PreRelease = "ALPHA"
+	Beta  PreRelease = "BETA"
+	GA    PreRelease = ""
+	// Deprecated features are going to be removed.
+	Deprecated PreRelease = "DEPRECATED"
+)
+
+// FeatureSpec describes a feature.
+type FeatureSpec struct {
+	// Default is whether the feature is enabled when it is not set.
+	Default bool
+	// LockToDefault prevents the feature from being set to another value than its default.
+	LockToDefault bool
+	PreRelease    PreRelease
+}
+
+// FeatureGate reports whether the features are enabled.
+type FeatureGate interface {
+	// Enabled returns true if the feature is enabled. It panics for unknown features.
+	Enabled(feature Feature) bool
+	// KnownFeatures returns the description of the features, sorted, for the help of the flags.
+	KnownFeatures() []string
+}
+
+// MutableFeatureGate is a FeatureGate whose features can be registered and set.
+type MutableFeatureGate interface {
+	FeatureGate
+	// Add registers features. A feature cannot be registered twice with different specs.
+	Add(features map[Feature]FeatureSpec) error
+	// Set sets the features from a string of the form "Feature1=true,Feature2=false".
+	Set(value string) error
+	// SetFromMap sets the features from a map of their names.
+	SetFromMap(features map[string]bool) error
+	// String returns the features which are set, in the form accepted by Set.
+	String() string
+	// Type is the type of the flag.
+	Type() string
+}
+
+type featureGate struct {
+	lock    sync.RWMutex
+	known   map[Feature]FeatureSpec
+	enabled map[Feature]bool
+}
+
+// NewFeatureGate returns a feature gate without any feature.
+func NewFeatureGate() MutableFeatureGate {
+	return &featureGate{
+		known:   make(map[Feature]FeatureSpec),
+		enabled: make(map[Feature]bool),
+	}
+}
+
+func (f *featureGate) Add(features map[Feature]FeatureSpec) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for name, spec := range features {
+		if existing, ok := f.known[name]; ok {
+			if existing == spec {
+				continue
+			}
+			return fmt.Errorf("feature gate %s is already registered with a different spec", name)
+		}
+		f.known[name] = spec
+	}
+	return nil
+}
+
+func (f *featureGate) Enabled(feature Feature) bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	if enabled, ok := f.enabled[feature]; ok {
+		return enabled
+	}
+	spec, ok := f.known[feature]
+	if !ok {
+		panic(fmt.Sprintf("feature %q is not registered in the feature gate", feature))
+	}
+	return spec.Default
+}
+
+func (f *featureGate) Set(value string) error {
+	features := make(map[string]bool)
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		name, v, found := strings.Cut(s, "=")
+		if !found {
+			return fmt.Errorf("missing bool value for feature gate %s", name)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("invalid value of feature gate %s=%s: %v", name, v, err)
+		}
+		features[strings.TrimSpace(name)] = enabled
+	}
+	return f.SetFromMap(features)
+}
+
+func (f *featureGate) SetFromMap(features map[string]bool) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	// The features are all checked before any is set
+	for name, enabled := range features {
+		spec, ok := f.known[Feature(name)]
+		if !ok {
+			return fmt.Errorf("unrecognized feature gate: %s", name)
+		}
+		if spec.LockToDefault && spec.Default != enabled {
+			return fmt.Errorf("cannot set feature gate %s to %v, it is locked to %v", name, enabled, spec.Default)
+		}
+	}
+	for name, enabled := range features {
+		f.enabled[Feature(name)] = enabled
+	}
+	return nil
+}
+
+func (f *featureGate) String() string {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	pairs := make([]string, 0, len(f.enabled))
+	for name, enabled := range f.enabled {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", name, enabled))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f *featureGate) Type() string {
+	return "mapStringBool"
+}
+
+func (f *featureGate) KnownFeatures() []string {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	known := make([]string, 0, len(f.known))
+	for name, spec := range f.known {
+		if spec.PreRelease == GA {
+			known = append(known, fmt.Sprintf("%s=true|false (default=%t)", name, spec.Default))
+			continue
+		}
+		known = append(known, fmt.Sprintf("%s=true|false (%s - default=%t)", name, spec.PreRelease, spec.Default))
+	}
+	sort.Strings(known)
+	return known
+}
@@ -0,0 +1,59 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package featuregate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureGate(t *testing.T) {
+	gate := NewFeatureGate()
+	require.NoError(t, gate.Add(map[Feature]FeatureSpec{
+		"AlphaFeature": {Default: false, PreRelease: Alpha},
+		"BetaFeature":  {Default: true, PreRelease: Beta},
+		"GAFeature":    {Default: true, LockToDefault: true, PreRelease: GA},
+	}))
+	// Registering a feature again is allowed only with the same spec
+	assert.NoError(t, gate.Add(map[Feature]FeatureSpec{"BetaFeature": {Default: true, PreRelease: Beta}}))
+	assert.Error(t, gate.Add(map[Feature]FeatureSpec{"BetaFeature": {Default: false, PreRelease: Beta}}))
+
+	assert.False(t, gate.Enabled("AlphaFeature"))
+	assert.True(t, gate.Enabled("BetaFeature"))
+	assert.Panics(t, func() { gate.Enabled("UnknownFeature") })
+
+	require.NoError(t, gate.Set("AlphaFeature=true, BetaFeature=false"))
+	assert.True(t, gate.Enabled("AlphaFeature"))
+	assert.False(t, gate.Enabled("BetaFeature"))
+	assert.Equal(t, "AlphaFeature=true,BetaFeature=false", gate.String())
+
+	// Invalid settings are rejected as a whole
+	assert.Error(t, gate.Set("AlphaFeature=false,UnknownFeature=true"))
+	assert.Error(t, gate.Set("AlphaFeature"))
+	assert.Error(t, gate.Set("AlphaFeature=maybe"))
+	assert.Error(t, gate.SetFromMap(map[string]bool{"AlphaFeature": false, "GAFeature": false}))
+	assert.True(t, gate.Enabled("AlphaFeature"))
+	assert.NoError(t, gate.SetFromMap(map[string]bool{"GAFeature": true}))
+
+	assert.Equal(t, []string{
+		"AlphaFeature=true|false (ALPHA - default=false)",
+		"BetaFeature=true|false (BETA - default=true)",
+		"GAFeature=true|false (default=true)",
+	}, gate.KnownFeatures())
+}
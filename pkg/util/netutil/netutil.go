@@ -0,0 +1,54 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package netutil builds the network addresses of the components, so that they work with IPv4 and IPv6 addresses
+// alike in single-stack and dual-stack clusters.
+package netutil
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// HostPort joins a host, a DNS name or an IP address, and a port into an address. IPv6 addresses are
+// enclosed in square brackets, e.g. "[fd00::1]:8000".
+func HostPort[T ~int | ~int32 | ~int64 | ~uint16 | ~uint32](host string, port T) string {
+	return net.JoinHostPort(host, strconv.FormatInt(int64(port), 10))
+}
+
+// URLHost returns the host as it appears in a URL, with IPv6 addresses enclosed in square brackets.
+func URLHost(host string) string {
+	if strings.Contains(host, ":") && !strings.HasPrefix(host, "[") {
+		return "[" + host + "]"
+	}
+	return host
+}
+
+// ListenAddress returns the address a server listens on, on the port of the bind address. An empty bind
+// address listens on all the addresses of the host, both IPv4 and IPv6 ones when the host is dual-stack.
+func ListenAddress(bindAddress, port string) string {
+	return net.JoinHostPort(strings.Trim(bindAddress, "[]"), port)
+}
+
+// ValidateBindAddress checks that the bind address is empty or an IPv4 or IPv6 address, e.g. "0.0.0.0" or "::".
+func ValidateBindAddress(bindAddress string) error {
+	if bindAddress != "" && net.ParseIP(strings.Trim(bindAddress, "[]")) == nil {
+		return fmt.Errorf("invalid bind address %q, it must be an IPv4 or IPv6 address", bindAddress)
+	}
+	return nil
+}
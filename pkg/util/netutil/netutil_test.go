@@ -0,0 +1,52 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostPort(t *testing.T) {
+	assert.Equal(t, "10.0.0.1:8000", HostPort("10.0.0.1", int32(8000)))
+	assert.Equal(t, "[fd00::1]:8000", HostPort("fd00::1", 8000))
+	assert.Equal(t, "llama-0.default:29500", HostPort("llama-0.default", uint16(29500)))
+}
+
+func TestURLHost(t *testing.T) {
+	assert.Equal(t, "10.0.0.1", URLHost("10.0.0.1"))
+	assert.Equal(t, "[fd00::1]", URLHost("fd00::1"))
+	assert.Equal(t, "[fd00::1]", URLHost("[fd00::1]"))
+	assert.Equal(t, "tokenizer.default.svc", URLHost("tokenizer.default.svc"))
+}
+
+func TestListenAddress(t *testing.T) {
+	assert.Equal(t, ":8080", ListenAddress("", "8080"))
+	assert.Equal(t, "0.0.0.0:8080", ListenAddress("0.0.0.0", "8080"))
+	assert.Equal(t, "[::]:8080", ListenAddress("::", "8080"))
+	assert.Equal(t, "[::1]:8080", ListenAddress("[::1]", "8080"))
+}
+
+func TestValidateBindAddress(t *testing.T) {
+	for _, address := range []string{"", "0.0.0.0", "127.0.0.1", "::", "[::1]", "fd00::1"} {
+		assert.NoError(t, ValidateBindAddress(address), address)
+	}
+	for _, address := range []string{"localhost", "10.0.0.256", ":8080", "[::1]:8080"} {
+		assert.Error(t, ValidateBindAddress(address), address)
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	certManagerGroupVersion = "cert-manager.io/v1"
+
+	defaultCertManagerTimeout = 2 * time.Minute
+	certManagerPollInterval   = 2 * time.Second
+)
+
+var (
+	certificateGVR = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	issuerGVR      = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "issuers"}
+)
+
+// Option configures EnsureCertificate.
+type Option func(*options)
+
+type options struct {
+	dynamicClient dynamic.Interface
+	timeout       time.Duration
+	pollInterval  time.Duration
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		timeout:      defaultCertManagerTimeout,
+		pollInterval: certManagerPollInterval,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithCertManager makes EnsureCertificate ask cert-manager to issue the certificate when cert-manager is installed,
+// instead of generating a self-signed one. The secret is then renewed by cert-manager.
+func WithCertManager(dynamicClient dynamic.Interface) Option {
+	return func(o *options) {
+		o.dynamicClient = dynamicClient
+	}
+}
+
+// WithCertManagerTimeout sets how long EnsureCertificate waits for cert-manager to issue the certificate.
+func WithCertManagerTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// withPollInterval sets how often the secret is checked while waiting for cert-manager, for the tests.
+func withPollInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = interval
+	}
+}
+
+// CertManagerInstalled returns true if the cert-manager Certificate API is served by the cluster.
+func CertManagerInstalled(discoveryClient discovery.DiscoveryInterface) bool {
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(certManagerGroupVersion)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Warningf("Failed to discover cert-manager: %v", err)
+		}
+		return false
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == certificateGVR.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureCertManagerCertificate creates a self-signed Issuer and a Certificate issued into the secret,
+// then waits for cert-manager to issue it and returns its CA bundle.
+func ensureCertManagerCertificate(ctx context.Context, kubeClient kubernetes.Interface, o *options, namespace, secretName string, dnsNames []string) ([]byte, error) {
+	issuerName := secretName + "-issuer"
+	klog.Infof("cert-manager is installed, requesting certificate %s/%s from issuer %s", namespace, secretName, issuerName)
+
+	issuer := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": certManagerGroupVersion,
+		"kind":       "Issuer",
+		"metadata": map[string]interface{}{
+			"name":      issuerName,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"selfSigned": map[string]interface{}{},
+		},
+	}}
+	if err := createIfNotExists(ctx, o.dynamicClient, issuerGVR, issuer); err != nil {
+		return nil, err
+	}
+
+	names, ips := SplitSANs(dnsNames)
+	spec := map[string]interface{}{
+		"secretName": secretName,
+		"dnsNames":   toInterfaces(names),
+		"issuerRef": map[string]interface{}{
+			"kind": "Issuer",
+			"name": issuerName,
+		},
+	}
+	if len(ips) > 0 {
+		addresses := make([]string, 0, len(ips))
+		for _, ip := range ips {
+			addresses = append(addresses, ip.String())
+		}
+		spec["ipAddresses"] = toInterfaces(addresses)
+	}
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": certManagerGroupVersion,
+		"kind":       "Certificate",
+		"metadata": map[string]interface{}{
+			"name":      secretName,
+			"namespace": namespace,
+		},
+		"spec": spec,
+	}}
+	if err := createIfNotExists(ctx, o.dynamicClient, certificateGVR, certificate); err != nil {
+		return nil, err
+	}
+
+	var caBundle []byte
+	err := wait.PollUntilContextTimeout(ctx, o.pollInterval, o.timeout, true, func(ctx context.Context) (bool, error) {
+		bundle, err := LoadCertBundleFromSecret(ctx, kubeClient, namespace, secretName)
+		if err != nil {
+			klog.Warningf("Failed to get secret %s/%s: %v", namespace, secretName, err)
+			return false, nil
+		}
+		if bundle == nil || len(bundle.CAPEM) == 0 || len(bundle.CertPEM) == 0 {
+			return false, nil
+		}
+		caBundle = bundle.CAPEM
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("certificate %s/%s was not issued by cert-manager: %w", namespace, secretName, err)
+	}
+	klog.Infof("cert-manager issued certificate %s/%s", namespace, secretName)
+	return caBundle, nil
+}
+
+func createIfNotExists(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	_, err := client.Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	return nil
+}
+
+// toInterfaces converts the strings to the values of an unstructured object.
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newDynamicClient() *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		certificateGVR: "CertificateList",
+		issuerGVR:      "IssuerList",
+	})
+}
+
+func TestEnsureCertificateWithCertManager(t *testing.T) {
+	ctx := context.Background()
+	dnsNames := []string{"webhook.default.svc"}
+	client := kubefake.NewSimpleClientset()
+	dynamicClient := newDynamicClient()
+
+	// Without cert-manager, a self-signed certificate is generated
+	caBundle, err := EnsureCertificate(ctx, client, "default", "self-signed", dnsNames, WithCertManager(dynamicClient))
+	require.NoError(t, err)
+	assert.NotEmpty(t, caBundle)
+	certificates, err := dynamicClient.Resource(certificateGVR).Namespace("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, certificates.Items)
+
+	client.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+		GroupVersion: certManagerGroupVersion,
+		APIResources: []metav1.APIResource{{Name: "certificates"}, {Name: "issuers"}},
+	}}
+	assert.True(t, CertManagerInstalled(client.Discovery()))
+
+	// cert-manager issues the certificate into the secret
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = client.CoreV1().Secrets("default").Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "issued", Namespace: "default"},
+			Data:       map[string][]byte{TLSCertKey: []byte("cert"), TLSKeyKey: []byte("key"), CAKey: []byte("ca")},
+		}, metav1.CreateOptions{})
+	}()
+	caBundle, err = EnsureCertificate(ctx, client, "default", "issued", dnsNames, WithCertManager(dynamicClient), withPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ca"), caBundle)
+
+	certificate, err := dynamicClient.Resource(certificateGVR).Namespace("default").Get(ctx, "issued", metav1.GetOptions{})
+	require.NoError(t, err)
+	secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	assert.Equal(t, "issued", secretName)
+	issuerName, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "name")
+	assert.Equal(t, "issued-issuer", issuerName)
+	_, err = dynamicClient.Resource(issuerGVR).Namespace("default").Get(ctx, issuerName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// The certificate not being issued in time is an error
+	_, err = EnsureCertificate(ctx, client, "default", "pending", dnsNames, WithCertManager(dynamicClient),
+		withPollInterval(10*time.Millisecond), WithCertManagerTimeout(50*time.Millisecond))
+	assert.Error(t, err)
+}
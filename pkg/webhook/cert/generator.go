@@ -17,7 +17,6 @@ limitations under the License.
 package cert
 
 import (
-	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -25,6 +24,8 @@ import (
 	"encoding/pem"
 	"fmt"
 	"math/big"
+	"net"
+	"strings"
 	"time"
 
 	"k8s.io/klog/v2"
@@ -33,8 +34,10 @@ import (
 const (
 	// RSAKeySize is the size of the RSA key for certificate generation
 	RSAKeySize = 2048
-	// CertValidityYears is the number of years the certificate is valid
+	// CertValidityYears is the number of years the CA certificate is valid
 	CertValidityYears = 10
+	// ServerCertValidity is the validity of the server certificates, which are renewed by the Rotator before they expire
+	ServerCertValidity = 365 * 24 * time.Hour
 )
 
 // CertBundle contains the certificate, key, and CA certificate
@@ -45,9 +48,13 @@ type CertBundle struct {
 	KeyPEM []byte
 	// CAPEM is the PEM-encoded CA certificate
 	CAPEM []byte
+	// CAKeyPEM is the PEM-encoded private key of the CA, it is kept to renew the server certificate
+	// without changing the CA bundle of the webhook configurations
+	CAKeyPEM []byte
 }
 
-// GenerateSelfSignedCertificate generates a self-signed certificate for webhook server
+// GenerateSelfSignedCertificate generates a self-signed certificate for webhook server. The IPv4 and IPv6
+// addresses among the DNS names are set as IP addresses of the certificate.
 func GenerateSelfSignedCertificate(dnsNames []string) (*CertBundle, error) {
 	if len(dnsNames) == 0 {
 		return nil, fmt.Errorf("dnsNames cannot be empty")
@@ -61,8 +68,12 @@ func GenerateSelfSignedCertificate(dnsNames []string) (*CertBundle, error) {
 		return nil, fmt.Errorf("failed to generate CA key: %w", err)
 	}
 
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
 	caTemplate := &x509.Certificate{
-		SerialNumber: big.NewInt(1),
+		SerialNumber: serialNumber,
 		Subject: pkix.Name{
 			CommonName:   "kthena-webhook-ca",
 			Organization: []string{"Volcano"},
@@ -78,63 +89,145 @@ func GenerateSelfSignedCertificate(dnsNames []string) (*CertBundle, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
 	}
+	caCert, err := x509.ParseCertificate(caCertDER)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+
+	bundle, err := signServerCertificate(caCert, caKey, dnsNames)
+	if err != nil {
+		return nil, err
+	}
+
+	klog.Info("Successfully generated self-signed certificate")
+	return bundle, nil
+}
 
-	// Generate server certificate and key
+// RenewCertificate issues a new server certificate for the DNS names. The server certificate is signed by
+// the CA of the bundle when its key is known, so that the CA bundle is unchanged. Otherwise a new CA is generated.
+func RenewCertificate(bundle *CertBundle, dnsNames []string) (*CertBundle, error) {
+	if len(dnsNames) == 0 {
+		return nil, fmt.Errorf("dnsNames cannot be empty")
+	}
+	if bundle == nil || len(bundle.CAPEM) == 0 || len(bundle.CAKeyPEM) == 0 {
+		klog.Info("The key of the CA is unknown, generating a new CA")
+		return GenerateSelfSignedCertificate(dnsNames)
+	}
+
+	caCert, err := parseCertificate(bundle.CAPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+	caKeyBlock, _ := pem.Decode(bundle.CAKeyPEM)
+	if caKeyBlock == nil {
+		return nil, fmt.Errorf("failed to decode CA key")
+	}
+	caKey, err := x509.ParsePKCS1PrivateKey(caKeyBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA key: %w", err)
+	}
+	if time.Now().Add(ServerCertValidity).After(caCert.NotAfter) {
+		klog.Info("The CA expires before the renewed certificate, generating a new CA")
+		return GenerateSelfSignedCertificate(dnsNames)
+	}
+
+	klog.Infof("Renewing certificate for DNS names: %v", dnsNames)
+	return signServerCertificate(caCert, caKey, dnsNames)
+}
+
+// signServerCertificate generates a server certificate and key signed by the CA.
+func signServerCertificate(caCert *x509.Certificate, caKey *rsa.PrivateKey, dnsNames []string) (*CertBundle, error) {
 	serverKey, err := rsa.GenerateKey(rand.Reader, RSAKeySize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate server key: %w", err)
 	}
 
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	names, ips := SplitSANs(dnsNames)
 	serverTemplate := &x509.Certificate{
-		SerialNumber: big.NewInt(2),
+		SerialNumber: serialNumber,
 		Subject: pkix.Name{
 			CommonName:   dnsNames[0],
 			Organization: []string{"Volcano"},
 		},
-		DNSNames:    dnsNames,
+		DNSNames:    names,
+		IPAddresses: ips,
 		NotBefore:   time.Now(),
-		NotAfter:    time.Now().AddDate(CertValidityYears, 0, 0),
+		NotAfter:    time.Now().Add(ServerCertValidity),
 		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
 		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 	}
 
-	serverCertDER, err := x509.CreateCertificate(rand.Reader, serverTemplate, caTemplate, &serverKey.PublicKey, caKey)
+	serverCertDER, err := x509.CreateCertificate(rand.Reader, serverTemplate, caCert, &serverKey.PublicKey, caKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create server certificate: %w", err)
 	}
 
-	// Encode CA certificate to PEM
-	caCertPEM := new(bytes.Buffer)
-	if err := pem.Encode(caCertPEM, &pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: caCertDER,
-	}); err != nil {
-		return nil, fmt.Errorf("failed to encode CA certificate: %w", err)
+	return &CertBundle{
+		CertPEM:  encodePEM("CERTIFICATE", serverCertDER),
+		KeyPEM:   encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(serverKey)),
+		CAPEM:    encodePEM("CERTIFICATE", caCert.Raw),
+		CAKeyPEM: encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(caKey)),
+	}, nil
+}
+
+// SplitSANs splits the subject alternative names of a certificate into DNS names and IP addresses.
+// The IPv6 addresses may be enclosed in square brackets.
+func SplitSANs(sans []string) ([]string, []net.IP) {
+	var dnsNames []string
+	var ips []net.IP
+	for _, san := range sans {
+		if ip := net.ParseIP(strings.Trim(san, "[]")); ip != nil {
+			ips = append(ips, ip)
+		} else {
+			dnsNames = append(dnsNames, san)
+		}
+	}
+	return dnsNames, ips
+}
+
+// Covers reports whether the first certificate of the PEM data is valid for all the DNS names and IP addresses.
+func Covers(certPEM []byte, sans []string) (bool, error) {
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return false, err
 	}
+	for _, san := range sans {
+		if err := cert.VerifyHostname(strings.Trim(san, "[]")); err != nil {
+			return false, nil
+		}
+	}
+	return true, nil
+}
 
-	// Encode server certificate to PEM
-	serverCertPEM := new(bytes.Buffer)
-	if err := pem.Encode(serverCertPEM, &pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: serverCertDER,
-	}); err != nil {
-		return nil, fmt.Errorf("failed to encode server certificate: %w", err)
+// NotAfter returns the expiry of the first certificate of the PEM data.
+func NotAfter(certPEM []byte) (time.Time, error) {
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return time.Time{}, err
 	}
+	return cert.NotAfter, nil
+}
 
-	// Encode server key to PEM
-	serverKeyPEM := new(bytes.Buffer)
-	if err := pem.Encode(serverKeyPEM, &pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(serverKey),
-	}); err != nil {
-		return nil, fmt.Errorf("failed to encode server key: %w", err)
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM-encoded certificate found")
 	}
+	return x509.ParseCertificate(block.Bytes)
+}
 
-	klog.Info("Successfully generated self-signed certificate")
+func encodePEM(blockType string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+}
 
-	return &CertBundle{
-		CertPEM: serverCertPEM.Bytes(),
-		KeyPEM:  serverKeyPEM.Bytes(),
-		CAPEM:   caCertPEM.Bytes(),
-	}, nil
+func newSerialNumber() (*big.Int, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return serialNumber, nil
 }
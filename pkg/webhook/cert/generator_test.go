@@ -20,6 +20,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -110,6 +111,38 @@ func TestGenerateSelfSignedCertificate_MultipleDNSNames(t *testing.T) {
 	assert.Equal(t, dnsNames[0], serverCert.Subject.CommonName)
 }
 
+func TestGenerateSelfSignedCertificate_IPAddresses(t *testing.T) {
+	bundle, err := GenerateSelfSignedCertificate([]string{"webhook.default.svc", "10.96.0.10", "[fd00:10:96::a]"})
+	require.NoError(t, err)
+
+	certBlock, _ := pem.Decode(bundle.CertPEM)
+	serverCert, err := x509.ParseCertificate(certBlock.Bytes)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"webhook.default.svc"}, serverCert.DNSNames)
+	require.Len(t, serverCert.IPAddresses, 2)
+	assert.Equal(t, "10.96.0.10", serverCert.IPAddresses[0].String())
+	assert.Equal(t, "fd00:10:96::a", serverCert.IPAddresses[1].String())
+
+	covered, err := Covers(bundle.CertPEM, []string{"webhook.default.svc", "fd00:10:96::a"})
+	require.NoError(t, err)
+	assert.True(t, covered)
+	covered, err = Covers(bundle.CertPEM, []string{"webhook.default.svc", "fd00:10:96::b"})
+	require.NoError(t, err)
+	assert.False(t, covered)
+	_, err = Covers([]byte("invalid"), []string{"webhook.default.svc"})
+	assert.Error(t, err)
+}
+
+func TestSplitSANs(t *testing.T) {
+	names, ips := SplitSANs([]string{"webhook.default.svc", "::1", "[2001:db8::1]", "127.0.0.1"})
+	assert.Equal(t, []string{"webhook.default.svc"}, names)
+	require.Len(t, ips, 3)
+	assert.Equal(t, "::1", ips[0].String())
+	assert.Equal(t, "2001:db8::1", ips[1].String())
+	assert.Equal(t, "127.0.0.1", ips[2].String())
+}
+
 func TestGenerateSelfSignedCertificate_EmptyDNSNames(t *testing.T) {
 	dnsNames := []string{}
 
@@ -118,3 +151,35 @@ func TestGenerateSelfSignedCertificate_EmptyDNSNames(t *testing.T) {
 	require.Nil(t, bundle)
 	assert.Contains(t, err.Error(), "dnsNames cannot be empty")
 }
+
+func TestRenewCertificate(t *testing.T) {
+	dnsNames := []string{"webhook.default.svc"}
+	bundle, err := GenerateSelfSignedCertificate(dnsNames)
+	require.NoError(t, err)
+	require.NotEmpty(t, bundle.CAKeyPEM)
+
+	expiry, err := NotAfter(bundle.CertPEM)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(ServerCertValidity), expiry, time.Minute)
+
+	// The renewed certificate is signed by the same CA
+	renewed, err := RenewCertificate(bundle, dnsNames)
+	require.NoError(t, err)
+	assert.Equal(t, bundle.CAPEM, renewed.CAPEM)
+	assert.NotEqual(t, bundle.CertPEM, renewed.CertPEM)
+	assert.NotEqual(t, bundle.KeyPEM, renewed.KeyPEM)
+
+	caCert, err := parseCertificate(bundle.CAPEM)
+	require.NoError(t, err)
+	serverCert, err := parseCertificate(renewed.CertPEM)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	_, err = serverCert.Verify(x509.VerifyOptions{DNSName: dnsNames[0], Roots: roots})
+	require.NoError(t, err)
+
+	// Without the key of the CA, a new CA is generated
+	renewed, err = RenewCertificate(&CertBundle{CAPEM: bundle.CAPEM}, dnsNames)
+	require.NoError(t, err)
+	assert.NotEqual(t, bundle.CAPEM, renewed.CAPEM)
+}
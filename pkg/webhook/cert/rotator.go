@@ -0,0 +1,170 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cert
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultRenewBefore is how long before its expiry a self-signed certificate is renewed.
+	DefaultRenewBefore = 30 * 24 * time.Hour
+	// DefaultCheckInterval is how often the certificate is checked.
+	DefaultCheckInterval = time.Hour
+)
+
+// Rotator renews the self-signed certificate stored in a secret before it expires, instead of generating
+// it once at startup, and reports the changes of its CA so that the webhook configurations can follow.
+// Only the certificates generated by EnsureCertificate are renewed, the ones issued by cert-manager are renewed
+// by cert-manager and the ones provided by the user are left alone, only the changes of their CA are reported.
+// The webhook servers pick up the renewed key pair with a SecretKeyPairReloader, or a KeyPairReloader once the secret
+// volume is updated. All the replicas of a webhook server run a rotator, the certificate is renewed by one of them.
+type Rotator struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	secretName string
+	dnsNames   []string
+
+	// RenewBefore is how long before its expiry the certificate is renewed.
+	RenewBefore time.Duration
+	// CheckInterval is how often the certificate is checked.
+	CheckInterval time.Duration
+	// OnCAChange is called with the previous and the new CA bundle when the CA of the certificate changes.
+	OnCAChange func(ctx context.Context, previous, caBundle []byte)
+
+	caBundle []byte
+}
+
+// NewRotator creates a rotator of the certificate stored in the secret.
+func NewRotator(kubeClient kubernetes.Interface, namespace, secretName string, dnsNames []string) *Rotator {
+	return &Rotator{
+		kubeClient:    kubeClient,
+		namespace:     namespace,
+		secretName:    secretName,
+		dnsNames:      dnsNames,
+		RenewBefore:   DefaultRenewBefore,
+		CheckInterval: DefaultCheckInterval,
+	}
+}
+
+// Run checks the certificate every CheckInterval until the context is done.
+func (r *Rotator) Run(ctx context.Context) {
+	klog.Infof("Rotating certificate in secret %s/%s %s before its expiry", r.namespace, r.secretName, r.RenewBefore)
+	ticker := time.NewTicker(r.CheckInterval)
+	defer ticker.Stop()
+	for {
+		if err := r.check(ctx); err != nil {
+			klog.Errorf("Failed to rotate certificate in secret %s/%s: %v", r.namespace, r.secretName, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check renews the certificate if it expires within RenewBefore, then reports a change of its CA.
+func (r *Rotator) check(ctx context.Context) error {
+	var secret *corev1.Secret
+	// The replicas of a webhook server check the certificate at the same time. The update of all but one fails on
+	// conflict, they read the renewed certificate again, which needs no renewal, so that a single one is kept.
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var err error
+		secret, err = r.renew(ctx)
+		return err
+	})
+	if err != nil || secret == nil {
+		return err
+	}
+
+	caBundle := secret.Data[CAKey]
+	if r.caBundle != nil && !bytes.Equal(r.caBundle, caBundle) && r.OnCAChange != nil {
+		klog.Infof("CA of the certificate in secret %s/%s changed", r.namespace, r.secretName)
+		r.OnCAChange(ctx, r.caBundle, caBundle)
+	}
+	r.caBundle = caBundle
+	return nil
+}
+
+// renew renews the certificate of the secret if it expires within RenewBefore or is not valid for all the DNS
+// names, and returns the secret, nil when it doesn't exist.
+func (r *Rotator) renew(ctx context.Context) (*corev1.Secret, error) {
+	secret, err := r.kubeClient.CoreV1().Secrets(r.namespace).Get(ctx, r.secretName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(2).Infof("Secret %s/%s not found, skipping certificate rotation", r.namespace, r.secretName)
+			return nil, nil
+		}
+		return nil, err
+	}
+	if secret.Annotations[GeneratedAnnotation] != "true" {
+		return secret, nil
+	}
+
+	expiry, err := NotAfter(secret.Data[TLSCertKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	// The certificate is also renewed when names or addresses were added, e.g. the IPv6 address of the webhook
+	covered, err := Covers(secret.Data[TLSCertKey], r.dnsNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	var reason string
+	if !covered {
+		reason = fmt.Sprintf("is not valid for %v", r.dnsNames)
+	} else if time.Until(expiry) < r.RenewBefore {
+		reason = "expires at " + expiry.Format(time.RFC3339)
+	}
+	if reason == "" {
+		return secret, nil
+	}
+
+	klog.Infof("Certificate in secret %s/%s %s, renewing it", r.namespace, r.secretName, reason)
+	bundle, err := RenewCertificate(&CertBundle{
+		CAPEM:    secret.Data[CAKey],
+		CAKeyPEM: secret.Data[CAPrivateKeyKey],
+	}, r.dnsNames)
+	if err != nil {
+		return nil, err
+	}
+	secret = secret.DeepCopy()
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[TLSCertKey] = bundle.CertPEM
+	secret.Data[TLSKeyKey] = bundle.KeyPEM
+	secret.Data[CAKey] = bundle.CAPEM
+	secret.Data[CAPrivateKeyKey] = bundle.CAKeyPEM
+	secret, err = r.kubeClient.CoreV1().Secrets(r.namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update secret: %w", err)
+	}
+	klog.Infof("Renewed certificate in secret %s/%s", r.namespace, r.secretName)
+	return secret, nil
+}
@@ -0,0 +1,227 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cert
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRotator(t *testing.T) {
+	ctx := context.Background()
+	dnsNames := []string{"webhook.default.svc"}
+	client := kubefake.NewSimpleClientset()
+	caBundle, err := EnsureCertificate(ctx, client, "default", "webhook-certs", dnsNames)
+	require.NoError(t, err)
+
+	var changes [][]byte
+	rotator := NewRotator(client, "default", "webhook-certs", dnsNames)
+	rotator.OnCAChange = func(ctx context.Context, previous, caBundle []byte) {
+		changes = append(changes, previous, caBundle)
+	}
+	require.NoError(t, rotator.check(ctx))
+	secret, err := client.CoreV1().Secrets("default").Get(ctx, "webhook-certs", metav1.GetOptions{})
+	require.NoError(t, err)
+	cert := secret.Data[TLSCertKey]
+
+	// The certificate is renewed once it expires within RenewBefore, with the same CA
+	rotator.RenewBefore = ServerCertValidity + time.Hour
+	require.NoError(t, rotator.check(ctx))
+	secret, err = client.CoreV1().Secrets("default").Get(ctx, "webhook-certs", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotEqual(t, cert, secret.Data[TLSCertKey])
+	assert.Equal(t, caBundle, secret.Data[CAKey])
+	assert.Empty(t, changes)
+
+	// The certificate is renewed when the names it must be valid for change
+	cert = secret.Data[TLSCertKey]
+	rotator.RenewBefore = DefaultRenewBefore
+	rotator.dnsNames = append(dnsNames, "fd00::10")
+	require.NoError(t, rotator.check(ctx))
+	secret, err = client.CoreV1().Secrets("default").Get(ctx, "webhook-certs", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotEqual(t, cert, secret.Data[TLSCertKey])
+	covered, err := Covers(secret.Data[TLSCertKey], rotator.dnsNames)
+	require.NoError(t, err)
+	assert.True(t, covered)
+
+	// A new CA is reported
+	rotator.RenewBefore = ServerCertValidity + time.Hour
+	delete(secret.Data, CAPrivateKeyKey)
+	_, err = client.CoreV1().Secrets("default").Update(ctx, secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, rotator.check(ctx))
+	require.Len(t, changes, 2)
+	assert.Equal(t, caBundle, changes[0])
+	assert.NotEqual(t, caBundle, changes[1])
+
+	// The certificates provided by the user are not renewed
+	secret, err = client.CoreV1().Secrets("default").Get(ctx, "webhook-certs", metav1.GetOptions{})
+	require.NoError(t, err)
+	delete(secret.Annotations, GeneratedAnnotation)
+	_, err = client.CoreV1().Secrets("default").Update(ctx, secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, rotator.check(ctx))
+	renewed, err := client.CoreV1().Secrets("default").Get(ctx, "webhook-certs", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, secret.Data, renewed.Data)
+}
+
+func TestRotateValidatingWebhookCABundle(t *testing.T) {
+	ctx := context.Background()
+	client := kubefake.NewSimpleClientset(&admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook"},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{Name: "empty"},
+			{Name: "generated", ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: []byte("old")}},
+			{Name: "user", ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: []byte("user")}},
+		},
+	})
+
+	require.NoError(t, RotateValidatingWebhookCABundle(ctx, client, "webhook", []byte("old"), []byte("new")))
+	webhook, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, "webhook", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new"), webhook.Webhooks[0].ClientConfig.CABundle)
+	assert.Equal(t, []byte("new"), webhook.Webhooks[1].ClientConfig.CABundle)
+	assert.Equal(t, []byte("user"), webhook.Webhooks[2].ClientConfig.CABundle)
+
+	// Without the previous CA bundle, only the empty ones are filled
+	require.NoError(t, UpdateValidatingWebhookCABundle(ctx, client, "webhook", []byte("other")))
+	webhook, err = client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, "webhook", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new"), webhook.Webhooks[0].ClientConfig.CABundle)
+}
+
+func TestKeyPairReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	write := func(bundle *CertBundle, modTime time.Time) {
+		require.NoError(t, os.WriteFile(certFile, bundle.CertPEM, 0o600))
+		require.NoError(t, os.WriteFile(keyFile, bundle.KeyPEM, 0o600))
+		require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+		require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+	}
+
+	bundle, err := GenerateSelfSignedCertificate([]string{"webhook.default.svc"})
+	require.NoError(t, err)
+	write(bundle, time.Now().Add(-time.Hour))
+	reloader, err := NewKeyPairReloader(certFile, keyFile)
+	require.NoError(t, err)
+	first, err := reloader.GetCertificate(nil)
+	require.NoError(t, err)
+
+	reloaded, err := reloader.reload()
+	require.NoError(t, err)
+	assert.False(t, reloaded, "unchanged files are not reloaded")
+
+	renewed, err := RenewCertificate(bundle, []string{"webhook.default.svc"})
+	require.NoError(t, err)
+	write(renewed, time.Now())
+	reloaded, err = reloader.reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	second, err := reloader.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Certificate[0], second.Certificate[0])
+
+	// A broken key pair is not served
+	require.NoError(t, os.WriteFile(keyFile, []byte("broken"), 0o600))
+	_, err = reloader.reload()
+	assert.Error(t, err)
+	current, err := reloader.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, second, current)
+}
+
+func TestRotatorConcurrentRenewal(t *testing.T) {
+	ctx := context.Background()
+	client := kubefake.NewSimpleClientset()
+	_, err := EnsureCertificate(ctx, client, "default", "webhook-certs", []string{"webhook.default.svc"})
+	require.NoError(t, err)
+	dnsNames := []string{"webhook.default.svc", "webhook.default.svc.cluster.local"}
+
+	// Another replica renews the certificate first, the update of this one fails on conflict
+	secrets := corev1.SchemeGroupVersion.WithResource("secrets")
+	var renewedByOther []byte
+	updates := 0
+	client.PrependReactor("update", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		updates++
+		obj, err := client.Tracker().Get(secrets, "default", "webhook-certs")
+		require.NoError(t, err)
+		other := obj.(*corev1.Secret).DeepCopy()
+		bundle, err := RenewCertificate(&CertBundle{CAPEM: other.Data[CAKey], CAKeyPEM: other.Data[CAPrivateKeyKey]}, dnsNames)
+		require.NoError(t, err)
+		other.Data[TLSCertKey] = bundle.CertPEM
+		other.Data[TLSKeyKey] = bundle.KeyPEM
+		renewedByOther = bundle.CertPEM
+		require.NoError(t, client.Tracker().Update(secrets, other, "default"))
+		return true, nil, apierrors.NewConflict(corev1.Resource("secrets"), "webhook-certs", errors.New("the object has been modified"))
+	})
+
+	// The certificate renewed by the other replica is read again and kept
+	rotator := NewRotator(client, "default", "webhook-certs", dnsNames)
+	require.NoError(t, rotator.check(ctx))
+	assert.Equal(t, 1, updates)
+	secret, err := client.CoreV1().Secrets("default").Get(ctx, "webhook-certs", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, renewedByOther, secret.Data[TLSCertKey])
+}
+
+func TestSecretKeyPairReloader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := kubefake.NewSimpleClientset()
+	reloader := NewSecretKeyPairReloader(client, "default", "webhook-certs")
+	go reloader.Run(ctx)
+	_, err := reloader.GetCertificate(nil)
+	assert.Error(t, err, "no key pair before the secret is created")
+
+	// The secret created by another replica is picked up
+	_, err = EnsureCertificate(ctx, client, "default", "webhook-certs", []string{"webhook.default.svc"})
+	require.NoError(t, err)
+	require.True(t, reloader.WaitForKeyPair(ctx, 5*time.Second))
+	first, err := reloader.GetCertificate(nil)
+	require.NoError(t, err)
+
+	// The renewed certificate is served without waiting for the secret volume
+	rotator := NewRotator(client, "default", "webhook-certs", []string{"webhook.default.svc", "fd00::10"})
+	require.NoError(t, rotator.check(ctx))
+	assert.Eventually(t, func() bool {
+		current, err := reloader.GetCertificate(nil)
+		return err == nil && !bytes.Equal(first.Certificate[0], current.Certificate[0])
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A secret without a key pair is not served
+	reloader = NewSecretKeyPairReloader(kubefake.NewSimpleClientset(), "default", "webhook-certs")
+	assert.False(t, reloader.WaitForKeyPair(ctx, 10*time.Millisecond))
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package cert
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 
@@ -24,6 +25,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 )
 
@@ -34,16 +36,23 @@ const (
 	TLSKeyKey = "tls.key"
 	// CAKey is the key for the CA certificate in the secret
 	CAKey = "ca.crt"
+	// CAPrivateKeyKey is the key for the private key of the CA in the secret, set for the self-signed certificates only
+	CAPrivateKeyKey = "ca.key"
+	// GeneratedAnnotation marks the secrets holding a self-signed certificate generated by EnsureCertificate,
+	// which are renewed by the Rotator. The certificates provided by the user are never renewed.
+	GeneratedAnnotation = "serving.volcano.sh/generated-certificate"
 )
 
 // EnsureCertificate ensures that a certificate exists for the webhook server.
-// If the secret doesn't exist, it generates a new certificate and creates the secret.
+// If the secret doesn't exist, it generates a new certificate and creates the secret, or asks cert-manager
+// to issue it when WithCertManager is given and cert-manager is installed in the cluster.
 // If the secret already exists, it returns without error (reusing existing certificate).
 // Returns the CA bundle bytes that can be used to update webhook configurations.
-func EnsureCertificate(ctx context.Context, kubeClient kubernetes.Interface, namespace, secretName string, dnsNames []string) ([]byte, error) {
+func EnsureCertificate(ctx context.Context, kubeClient kubernetes.Interface, namespace, secretName string, dnsNames []string, opts ...Option) ([]byte, error) {
 	if len(dnsNames) == 0 {
 		return nil, fmt.Errorf("dnsNames cannot be empty")
 	}
+	o := newOptions(opts)
 
 	klog.Infof("Ensuring certificate exists in secret %s/%s", namespace, secretName)
 
@@ -63,6 +72,10 @@ func EnsureCertificate(ctx context.Context, kubeClient kubernetes.Interface, nam
 		return nil, fmt.Errorf("failed to get secret %s/%s: %w", namespace, secretName, err)
 	}
 
+	if o.dynamicClient != nil && CertManagerInstalled(kubeClient.Discovery()) {
+		return ensureCertManagerCertificate(ctx, kubeClient, o, namespace, secretName, dnsNames)
+	}
+
 	// Secret doesn't exist, generate new certificate
 	klog.Infof("Secret %s/%s not found, generating new certificate", namespace, secretName)
 	certBundle, err := GenerateSelfSignedCertificate(dnsNames)
@@ -75,12 +88,16 @@ func EnsureCertificate(ctx context.Context, kubeClient kubernetes.Interface, nam
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      secretName,
 			Namespace: namespace,
+			Annotations: map[string]string{
+				GeneratedAnnotation: "true",
+			},
 		},
 		Type: corev1.SecretTypeTLS,
 		Data: map[string][]byte{
-			TLSCertKey: certBundle.CertPEM,
-			TLSKeyKey:  certBundle.KeyPEM,
-			CAKey:      certBundle.CAPEM,
+			TLSCertKey:      certBundle.CertPEM,
+			TLSKeyKey:       certBundle.KeyPEM,
+			CAKey:           certBundle.CAPEM,
+			CAPrivateKeyKey: certBundle.CAKeyPEM,
 		},
 	}
 
@@ -108,76 +125,101 @@ func EnsureCertificate(ctx context.Context, kubeClient kubernetes.Interface, nam
 
 // UpdateValidatingWebhookCABundle updates the ValidatingWebhookConfiguration with the provided CA bundle
 func UpdateValidatingWebhookCABundle(ctx context.Context, kubeClient kubernetes.Interface, webhookName string, caBundle []byte) error {
-	// Get the ValidatingWebhookConfiguration
-	webhook, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, webhookName, metav1.GetOptions{})
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			klog.Infof("ValidatingWebhookConfiguration %s not found, skipping CA bundle update", webhookName)
-			return nil
+	return RotateValidatingWebhookCABundle(ctx, kubeClient, webhookName, nil, caBundle)
+}
+
+// RotateValidatingWebhookCABundle sets the CA bundle of the webhooks of the ValidatingWebhookConfiguration
+// which have no CA bundle or the previous one, so that a CA bundle provided by the user is never overwritten.
+func RotateValidatingWebhookCABundle(ctx context.Context, kubeClient kubernetes.Interface, webhookName string, previous, caBundle []byte) error {
+	// The replicas of a webhook server rotate the CA bundle at the same time, the ones failing on conflict see it rotated
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Get the ValidatingWebhookConfiguration
+		webhook, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, webhookName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				klog.Infof("ValidatingWebhookConfiguration %s not found, skipping CA bundle update", webhookName)
+				return nil
+			}
+			return fmt.Errorf("failed to get ValidatingWebhookConfiguration %s: %w", webhookName, err)
 		}
-		return fmt.Errorf("failed to get ValidatingWebhookConfiguration %s: %w", webhookName, err)
-	}
 
-	// Update all webhooks with the CA bundle
-	updated := false
-	for i := range webhook.Webhooks {
-		// Only update if caBundle is empty (meaning it's using auto-generated certs)
-		if len(webhook.Webhooks[i].ClientConfig.CABundle) == 0 {
-			webhook.Webhooks[i].ClientConfig.CABundle = caBundle
-			updated = true
+		// Update all webhooks with the CA bundle
+		updated := false
+		for i := range webhook.Webhooks {
+			if shouldUpdateCABundle(webhook.Webhooks[i].ClientConfig.CABundle, previous, caBundle) {
+				webhook.Webhooks[i].ClientConfig.CABundle = caBundle
+				updated = true
+			}
 		}
-	}
 
-	if !updated {
-		klog.Infof("ValidatingWebhookConfiguration %s already has CA bundle, skipping update", webhookName)
-		return nil
-	}
+		if !updated {
+			klog.Infof("ValidatingWebhookConfiguration %s already has CA bundle, skipping update", webhookName)
+			return nil
+		}
 
-	// Update the ValidatingWebhookConfiguration
-	_, err = kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(ctx, webhook, metav1.UpdateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to update ValidatingWebhookConfiguration %s: %w", webhookName, err)
-	}
+		// Update the ValidatingWebhookConfiguration
+		_, err = kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(ctx, webhook, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to update ValidatingWebhookConfiguration %s: %w", webhookName, err)
+		}
 
-	klog.Infof("Successfully updated ValidatingWebhookConfiguration %s with CA bundle", webhookName)
-	return nil
+		klog.Infof("Successfully updated ValidatingWebhookConfiguration %s with CA bundle", webhookName)
+		return nil
+	})
 }
 
 // UpdateMutatingWebhookCABundle updates the MutatingWebhookConfiguration with the provided CA bundle
 func UpdateMutatingWebhookCABundle(ctx context.Context, kubeClient kubernetes.Interface, webhookName string, caBundle []byte) error {
-	// Get the MutatingWebhookConfiguration
-	webhook, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, webhookName, metav1.GetOptions{})
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			klog.Infof("MutatingWebhookConfiguration %s not found, skipping CA bundle update", webhookName)
+	return RotateMutatingWebhookCABundle(ctx, kubeClient, webhookName, nil, caBundle)
+}
+
+// RotateMutatingWebhookCABundle sets the CA bundle of the webhooks of the MutatingWebhookConfiguration
+// which have no CA bundle or the previous one, so that a CA bundle provided by the user is never overwritten.
+func RotateMutatingWebhookCABundle(ctx context.Context, kubeClient kubernetes.Interface, webhookName string, previous, caBundle []byte) error {
+	// The replicas of a webhook server rotate the CA bundle at the same time, the ones failing on conflict see it rotated
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Get the MutatingWebhookConfiguration
+		webhook, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, webhookName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				klog.Infof("MutatingWebhookConfiguration %s not found, skipping CA bundle update", webhookName)
+				return nil
+			}
+			return fmt.Errorf("failed to get MutatingWebhookConfiguration %s: %w", webhookName, err)
+		}
+
+		// Update all webhooks with the CA bundle
+		updated := false
+		for i := range webhook.Webhooks {
+			if shouldUpdateCABundle(webhook.Webhooks[i].ClientConfig.CABundle, previous, caBundle) {
+				webhook.Webhooks[i].ClientConfig.CABundle = caBundle
+				updated = true
+			}
+		}
+
+		if !updated {
+			klog.Infof("MutatingWebhookConfiguration %s already has CA bundle, skipping update", webhookName)
 			return nil
 		}
-		return fmt.Errorf("failed to get MutatingWebhookConfiguration %s: %w", webhookName, err)
-	}
 
-	// Update all webhooks with the CA bundle
-	updated := false
-	for i := range webhook.Webhooks {
-		// Only update if caBundle is empty (meaning it's using auto-generated certs)
-		if len(webhook.Webhooks[i].ClientConfig.CABundle) == 0 {
-			webhook.Webhooks[i].ClientConfig.CABundle = caBundle
-			updated = true
+		// Update the MutatingWebhookConfiguration
+		_, err = kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(ctx, webhook, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to update MutatingWebhookConfiguration %s: %w", webhookName, err)
 		}
-	}
 
-	if !updated {
-		klog.Infof("MutatingWebhookConfiguration %s already has CA bundle, skipping update", webhookName)
+		klog.Infof("Successfully updated MutatingWebhookConfiguration %s with CA bundle", webhookName)
 		return nil
-	}
+	})
+}
 
-	// Update the MutatingWebhookConfiguration
-	_, err = kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(ctx, webhook, metav1.UpdateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to update MutatingWebhookConfiguration %s: %w", webhookName, err)
+// shouldUpdateCABundle returns true if a webhook with the current CA bundle must be given the new one:
+// empty CA bundles are filled (meaning it's using auto-generated certs), and the previous CA bundle is rotated.
+func shouldUpdateCABundle(current, previous, caBundle []byte) bool {
+	if bytes.Equal(current, caBundle) {
+		return false
 	}
-
-	klog.Infof("Successfully updated MutatingWebhookConfiguration %s with CA bundle", webhookName)
-	return nil
+	return len(current) == 0 || (len(previous) > 0 && bytes.Equal(current, previous))
 }
 
 // LoadCertBundleFromSecret tries to read key cert bundle from a Kubernetes Secret.
@@ -203,5 +245,8 @@ func LoadCertBundleFromSecret(ctx context.Context, kubeClient kubernetes.Interfa
 	if b, ok := s.Data[CAKey]; ok && len(b) > 0 {
 		bundle.CAPEM = b
 	}
+	if b, ok := s.Data[CAPrivateKeyKey]; ok && len(b) > 0 {
+		bundle.CAKeyPEM = b
+	}
 	return &bundle, nil
 }
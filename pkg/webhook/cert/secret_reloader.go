@@ -0,0 +1,152 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// SecretKeyPairReloader serves the key pair stored in a secret, watching the secret so that a certificate generated
+// or renewed by any replica of a webhook server is served by all of them at once, without waiting for the kubelet
+// to update the secret volume, which may take a minute, or for the secret to be created when the pod starts.
+type SecretKeyPairReloader struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	secretName string
+
+	mutex sync.RWMutex
+	cert  *tls.Certificate
+	// certPEM and keyPEM are the key pair of the secret the certificate was loaded from.
+	certPEM []byte
+	keyPEM  []byte
+	// loaded is closed once a key pair is loaded.
+	loaded     chan struct{}
+	loadedOnce sync.Once
+}
+
+// NewSecretKeyPairReloader creates a reloader of the key pair stored in the secret, it is loaded once Run is called.
+func NewSecretKeyPairReloader(kubeClient kubernetes.Interface, namespace, secretName string) *SecretKeyPairReloader {
+	return &SecretKeyPairReloader{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		secretName: secretName,
+		loaded:     make(chan struct{}),
+	}
+}
+
+// GetCertificate returns the current key pair, it is meant for tls.Config.GetCertificate.
+func (r *SecretKeyPairReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if r.cert == nil {
+		return nil, fmt.Errorf("no key pair loaded from secret %s/%s", r.namespace, r.secretName)
+	}
+	return r.cert, nil
+}
+
+// TLSConfig returns a TLS configuration serving the current key pair.
+func (r *SecretKeyPairReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+	}
+}
+
+// Run watches the secret until the context is done.
+func (r *SecretKeyPairReloader) Run(ctx context.Context) {
+	factory := informers.NewSharedInformerFactoryWithOptions(r.kubeClient, 0,
+		informers.WithNamespace(r.namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", r.secretName).String()
+		}))
+	informer := factory.Core().V1().Secrets().Informer()
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: r.onSecret,
+		UpdateFunc: func(_, obj interface{}) {
+			r.onSecret(obj)
+		},
+	}); err != nil {
+		klog.Errorf("Failed to watch secret %s/%s: %v", r.namespace, r.secretName, err)
+		return
+	}
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	factory.Shutdown()
+}
+
+// WaitForKeyPair waits for a key pair to be loaded, it returns false on timeout.
+func (r *SecretKeyPairReloader) WaitForKeyPair(ctx context.Context, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-r.loaded:
+		return true
+	case <-timer.C:
+		klog.Warningf("timeout waiting for the key pair in secret %s/%s", r.namespace, r.secretName)
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// onSecret loads the key pair of the secret when it changed. A secret without a valid key pair, e.g. while
+// cert-manager issues the certificate, is logged and the previous key pair is kept.
+func (r *SecretKeyPairReloader) onSecret(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || secret.Namespace != r.namespace || secret.Name != r.secretName {
+		return
+	}
+	certPEM, keyPEM := secret.Data[TLSCertKey], secret.Data[TLSKeyKey]
+	r.mutex.RLock()
+	unchanged := r.cert != nil && bytes.Equal(r.certPEM, certPEM) && bytes.Equal(r.keyPEM, keyPEM)
+	r.mutex.RUnlock()
+	if unchanged {
+		return
+	}
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		klog.V(2).Infof("Secret %s/%s holds no key pair yet", r.namespace, r.secretName)
+		return
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		klog.Errorf("Failed to load TLS key pair from secret %s/%s: %v", r.namespace, r.secretName, err)
+		return
+	}
+	r.mutex.Lock()
+	r.cert = &cert
+	r.certPEM = certPEM
+	r.keyPEM = keyPEM
+	r.mutex.Unlock()
+	r.loadedOnce.Do(func() {
+		close(r.loaded)
+	})
+	klog.Infof("Loaded TLS key pair from secret %s/%s", r.namespace, r.secretName)
+}
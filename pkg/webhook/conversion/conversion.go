@@ -0,0 +1,166 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conversion serves the conversion webhook of the custom resources served in several versions, which
+// converts the objects between their versions with the conversion functions registered in a scheme.
+package conversion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+// Path is the path the conversion webhook is served on.
+const Path = "/convert"
+
+// Webhook converts the objects of the ConversionReviews sent by the API server.
+type Webhook struct {
+	scheme *runtime.Scheme
+}
+
+// NewWebhook creates a conversion webhook converting the types of the scheme with its conversion functions.
+func NewWebhook(scheme *runtime.Scheme) *Webhook {
+	return &Webhook{scheme: scheme}
+}
+
+// Handle answers a ConversionReview with its objects converted to the desired API version. The conversion of all
+// the objects fails when one of them cannot be converted.
+func (w *Webhook) Handle(rw http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		http.Error(rw, "empty request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	var review apiextensionsv1.ConversionReview
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		klog.Errorf("Invalid conversion review: %v", err)
+		http.Error(rw, "invalid ConversionReview", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = w.convert(review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(&review); err != nil {
+		klog.Errorf("Failed to send conversion response: %v", err)
+	}
+}
+
+func (w *Webhook) convert(req *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	desired, err := schema.ParseGroupVersion(req.DesiredAPIVersion)
+	if err != nil {
+		return failed(fmt.Errorf("invalid desired API version %q: %w", req.DesiredAPIVersion, err))
+	}
+	converted := make([]runtime.RawExtension, 0, len(req.Objects))
+	for _, obj := range req.Objects {
+		raw, err := w.convertObject(obj.Raw, desired)
+		if err != nil {
+			return failed(err)
+		}
+		converted = append(converted, runtime.RawExtension{Raw: raw})
+	}
+	return &apiextensionsv1.ConversionResponse{
+		ConvertedObjects: converted,
+		Result:           metav1.Status{Status: metav1.StatusSuccess},
+	}
+}
+
+// convertObject converts the JSON object to the desired version, the objects already in it are returned as is.
+func (w *Webhook) convertObject(raw []byte, desired schema.GroupVersion) ([]byte, error) {
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, fmt.Errorf("invalid object: %w", err)
+	}
+	gvk := typeMeta.GroupVersionKind()
+	if gvk.GroupVersion() == desired {
+		return raw, nil
+	}
+
+	in, err := w.scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, in); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", gvk, err)
+	}
+	out, err := w.scheme.New(desired.WithKind(gvk.Kind))
+	if err != nil {
+		return nil, err
+	}
+	if err := w.scheme.Convert(in, out, nil); err != nil {
+		return nil, fmt.Errorf("failed to convert %s to %s: %w", gvk, desired, err)
+	}
+	out.GetObjectKind().SetGroupVersionKind(desired.WithKind(gvk.Kind))
+	return json.Marshal(out)
+}
+
+func failed(err error) *apiextensionsv1.ConversionResponse {
+	klog.Errorf("Conversion failed: %v", err)
+	return &apiextensionsv1.ConversionResponse{
+		Result: metav1.Status{Status: metav1.StatusFailure, Message: err.Error()},
+	}
+}
+
+var crdResource = apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")
+
+// ConfigureCustomResourceDefinition has the CustomResourceDefinition convert its objects with the conversion webhook
+// served behind the service, whose certificate is signed by the CA bundle. The CRDs are installed with the None
+// strategy, as the namespace of the service is only known once installed.
+func ConfigureCustomResourceDefinition(ctx context.Context, client dynamic.Interface, name string, service apiextensionsv1.ServiceReference, caBundle []byte) error {
+	path := Path
+	service.Path = &path
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"conversion": apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig:             &apiextensionsv1.WebhookClientConfig{Service: &service, CABundle: caBundle},
+					ConversionReviewVersions: []string{"v1"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := client.Resource(crdResource).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.Infof("CustomResourceDefinition %s not found, skipping conversion webhook configuration", name)
+			return nil
+		}
+		return err
+	}
+	klog.Infof("Configured the conversion webhook of CustomResourceDefinition %s", name)
+	return nil
+}
@@ -0,0 +1,186 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
+
+	kthenascheme "github.com/volcano-sh/kthena/client-go/clientset/versioned/scheme"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	workloadv1beta1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1beta1"
+)
+
+func newModelServing(apiVersion string) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       "ModelServing",
+		"metadata":   map[string]interface{}{"name": "llama", "namespace": "default", "uid": "ms-uid", "labels": map[string]interface{}{"app": "llama"}},
+		"spec": map[string]interface{}{
+			"replicas":      int64(2),
+			"schedulerName": "volcano",
+			"template": map[string]interface{}{
+				"roles": []interface{}{map[string]interface{}{
+					"name":     "prefill",
+					"replicas": int64(1),
+					"entryTemplate": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{map[string]interface{}{"name": "engine", "image": "vllm/vllm-openai"}},
+						},
+					},
+				}},
+			},
+		},
+	}
+}
+
+func review(t *testing.T, w *Webhook, desiredAPIVersion string, objects ...map[string]interface{}) *apiextensionsv1.ConversionReview {
+	t.Helper()
+	req := &apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request:  &apiextensionsv1.ConversionRequest{UID: "uid", DesiredAPIVersion: desiredAPIVersion},
+	}
+	for _, obj := range objects {
+		raw, err := json.Marshal(obj)
+		require.NoError(t, err)
+		req.Request.Objects = append(req.Request.Objects, runtime.RawExtension{Raw: raw})
+	}
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	w.Handle(rec, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp apiextensionsv1.ConversionReview
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Response)
+	assert.Equal(t, types.UID("uid"), resp.Response.UID)
+	assert.Equal(t, "ConversionReview", resp.Kind)
+	assert.Nil(t, resp.Request)
+	return &resp
+}
+
+func TestHandle(t *testing.T) {
+	w := NewWebhook(kthenascheme.Scheme)
+	v1alpha1 := workloadv1alpha1.SchemeGroupVersion.String()
+	v1beta1 := workloadv1beta1.SchemeGroupVersion.String()
+
+	t.Run("round trip", func(t *testing.T) {
+		original := newModelServing(v1alpha1)
+		resp := review(t, w, v1beta1, original)
+		require.Equal(t, metav1.StatusSuccess, resp.Response.Result.Status)
+		require.Len(t, resp.Response.ConvertedObjects, 1)
+		var converted workloadv1beta1.ModelServing
+		require.NoError(t, json.Unmarshal(resp.Response.ConvertedObjects[0].Raw, &converted))
+		assert.Equal(t, v1beta1, converted.APIVersion)
+		assert.Equal(t, "ModelServing", converted.Kind)
+		assert.Equal(t, types.UID("ms-uid"), converted.UID)
+		assert.Equal(t, int32(2), *converted.Spec.Replicas)
+		assert.Equal(t, "prefill", converted.Spec.Template.Roles[0].Name)
+
+		var convertedObject map[string]interface{}
+		require.NoError(t, json.Unmarshal(resp.Response.ConvertedObjects[0].Raw, &convertedObject))
+		resp = review(t, w, v1alpha1, convertedObject)
+		require.Equal(t, metav1.StatusSuccess, resp.Response.Result.Status)
+		var roundTripped workloadv1alpha1.ModelServing
+		require.NoError(t, json.Unmarshal(resp.Response.ConvertedObjects[0].Raw, &roundTripped))
+		var expected workloadv1alpha1.ModelServing
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(original, &expected))
+		assert.Equal(t, expected, roundTripped)
+	})
+
+	t.Run("object already in the desired version", func(t *testing.T) {
+		obj := newModelServing(v1beta1)
+		resp := review(t, w, v1beta1, obj)
+		require.Equal(t, metav1.StatusSuccess, resp.Response.Result.Status)
+		raw, err := json.Marshal(obj)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(raw), string(resp.Response.ConvertedObjects[0].Raw))
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		obj := newModelServing(v1alpha1)
+		obj["kind"] = "ModelInfer"
+		resp := review(t, w, v1beta1, newModelServing(v1alpha1), obj)
+		assert.Equal(t, metav1.StatusFailure, resp.Response.Result.Status)
+		assert.Contains(t, resp.Response.Result.Message, "ModelInfer")
+		assert.Empty(t, resp.Response.ConvertedObjects)
+	})
+
+	t.Run("invalid desired version", func(t *testing.T) {
+		resp := review(t, w, "workload.serving.volcano.sh/v1/beta1", newModelServing(v1alpha1))
+		assert.Equal(t, metav1.StatusFailure, resp.Response.Result.Status)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		w.Handle(rec, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader([]byte("{"))))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = httptest.NewRecorder()
+		w.Handle(rec, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader([]byte(`{"kind":"ConversionReview"}`))))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestConfigureCustomResourceDefinition(t *testing.T) {
+	const name = "modelservings.workload.serving.volcano.sh"
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"group":      "workload.serving.volcano.sh",
+			"conversion": map[string]interface{}{"strategy": "None"},
+		},
+	}}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), crd)
+	service := apiextensionsv1.ServiceReference{Namespace: "kthena-system", Name: "kthena-controller-manager-webhook", Port: ptr.To[int32](443)}
+
+	require.NoError(t, ConfigureCustomResourceDefinition(context.Background(), client, name, service, []byte("ca")))
+	got, err := client.Resource(crdResource).Get(context.Background(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	var conversion apiextensionsv1.CustomResourceConversion
+	raw, _, _ := unstructured.NestedMap(got.Object, "spec", "conversion")
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &conversion))
+	assert.Equal(t, apiextensionsv1.WebhookConverter, conversion.Strategy)
+	require.NotNil(t, conversion.Webhook)
+	assert.Equal(t, []string{"v1"}, conversion.Webhook.ConversionReviewVersions)
+	assert.Equal(t, []byte("ca"), conversion.Webhook.ClientConfig.CABundle)
+	assert.Equal(t, "kthena-system", conversion.Webhook.ClientConfig.Service.Namespace)
+	assert.Equal(t, Path, *conversion.Webhook.ClientConfig.Service.Path)
+	assert.Equal(t, int32(443), *conversion.Webhook.ClientConfig.Service.Port)
+	group, _, _ := unstructured.NestedString(got.Object, "spec", "group")
+	assert.Equal(t, "workload.serving.volcano.sh", group)
+
+	// The CRDs not installed are skipped
+	assert.NoError(t, ConfigureCustomResourceDefinition(context.Background(), client, "modelinfers.workload.serving.volcano.sh", service, []byte("ca")))
+}
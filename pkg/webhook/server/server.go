@@ -0,0 +1,317 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/pkg/util/netutil"
+	webhookcert "github.com/volcano-sh/kthena/pkg/webhook/cert"
+	"github.com/volcano-sh/kthena/pkg/webhook/conversion"
+)
+
+const (
+	// certWaitTimeout is how long the cert and key files are waited for, in case they are mounted by Kubernetes.
+	certWaitTimeout  = 30 * time.Second
+	certWaitInterval = 500 * time.Millisecond
+	shutdownTimeout  = 5 * time.Second
+	// defaultServicePort is the port of the service of the webhook server.
+	defaultServicePort int32 = 443
+)
+
+// Config configures a webhook server and the provisioning of its certificate.
+type Config struct {
+	// BindAddress is the IPv4 or IPv6 address the server listens on, all the addresses of both families when empty.
+	BindAddress string
+	Port        int
+	// CertFile and KeyFile are the key pair of the server, reloaded when they change.
+	CertFile string
+	KeyFile  string
+	// Timeout bounds the reading and writing of the admission requests.
+	Timeout time.Duration
+	// Namespace is the namespace of the certificate secret and of the webhook service.
+	Namespace string
+	// CertSecretName is the secret the certificate is read from, or generated into when the key pair files don't exist.
+	CertSecretName string
+	// ServiceName is the service of the webhook server, the DNS names of the certificate are derived from it.
+	ServiceName string
+	// ServicePort is the port of the service, 443 when not set.
+	ServicePort int32
+	// CertExtraSANs are DNS names or IP addresses the generated certificate is also valid for, e.g. the IPv6
+	// address of a webhook called by its URL.
+	CertExtraSANs []string
+	// ValidatingWebhookConfigurations and MutatingWebhookConfigurations are given the CA bundle of the certificate.
+	ValidatingWebhookConfigurations []string
+	MutatingWebhookConfigurations   []string
+	// CustomResourceDefinitions are given the conversion webhook of the server and the CA bundle of its certificate.
+	CustomResourceDefinitions []string
+}
+
+// Server serves admission webhooks over TLS. It provisions its certificate with the precedence
+// secret -> existing cert files -> auto-generated certs, keeps it renewed and reloads it when it changes.
+type Server struct {
+	config        Config
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	mux           *http.ServeMux
+	// serving is set once the server listens on its port with a certificate.
+	serving atomic.Bool
+}
+
+// New creates a webhook server serving /healthz, the webhooks are added with Handle.
+func New(config Config, kubeClient kubernetes.Interface, dynamicClient dynamic.Interface) *Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte("ok")); err != nil {
+			klog.Errorf("failed to write health check response: %v", err)
+		}
+	})
+	return &Server{
+		config:        config,
+		kubeClient:    kubeClient,
+		dynamicClient: dynamicClient,
+		mux:           mux,
+	}
+}
+
+// Handle serves the admission webhook on the path.
+func (s *Server) Handle(path string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(path, handler)
+}
+
+// ServeHTTP serves the webhooks without TLS, it is used by tests.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// AddValidatingWebhookConfiguration adds a ValidatingWebhookConfiguration to give the CA bundle of the certificate.
+func (s *Server) AddValidatingWebhookConfiguration(name string) {
+	s.config.ValidatingWebhookConfigurations = append(s.config.ValidatingWebhookConfigurations, name)
+}
+
+// AddMutatingWebhookConfiguration adds a MutatingWebhookConfiguration to give the CA bundle of the certificate.
+func (s *Server) AddMutatingWebhookConfiguration(name string) {
+	s.config.MutatingWebhookConfigurations = append(s.config.MutatingWebhookConfigurations, name)
+}
+
+// AddConversionCustomResourceDefinition adds a CustomResourceDefinition whose objects are converted between their
+// versions by the server, with Handle(conversion.Path, ...).
+func (s *Server) AddConversionCustomResourceDefinition(name string) {
+	s.config.CustomResourceDefinitions = append(s.config.CustomResourceDefinitions, name)
+}
+
+// Namespace is the namespace the webhook server runs in.
+func (s *Server) Namespace() string {
+	return s.config.Namespace
+}
+
+// Name identifies the webhook server in the logs.
+func (s *Server) Name() string {
+	return "webhook server"
+}
+
+// Ready returns an error until the server is serving the webhooks, as the certificate may still be provisioned.
+func (s *Server) Ready() error {
+	if !s.serving.Load() {
+		return errors.New("webhooks are not served yet")
+	}
+	return nil
+}
+
+// Run provisions the certificate and serves the webhooks until the context is done.
+func (s *Server) Run(ctx context.Context) error {
+	fromSecret, err := s.provisionCertificate(ctx)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := s.keyPair(ctx, fromSecret)
+	if err != nil {
+		return err
+	}
+	go s.newRotator().Run(ctx)
+
+	server := &http.Server{
+		Addr:         netutil.ListenAddress(s.config.BindAddress, strconv.Itoa(s.config.Port)),
+		Handler:      s.mux,
+		ReadTimeout:  s.config.Timeout,
+		WriteTimeout: s.config.Timeout,
+		TLSConfig:    tlsConfig,
+	}
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", server.Addr, err)
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		klog.Infof("Starting webhook server on %s", server.Addr)
+		if err := server.ServeTLS(listener, "", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+	s.serving.Store(true)
+	defer s.serving.Store(false)
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("failed to serve webhooks: %w", err)
+	case <-ctx.Done():
+	}
+	klog.Info("Shutting down webhook server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// provisionCertificate selects the CA bundle with the precedence secret -> file -> generate, and gives it to the
+// webhook configurations. The CA bundle is unknown when the key pair files are provided without a secret.
+// It reports whether the key pair is served from the secret rather than from the files.
+func (s *Server) provisionCertificate(ctx context.Context) (bool, error) {
+	var caBundle []byte
+	fromSecret := false
+
+	// 1. Try secret first.
+	if bundle, err := webhookcert.LoadCertBundleFromSecret(ctx, s.kubeClient, s.config.Namespace, s.config.CertSecretName); err != nil {
+		klog.Warningf("Error reading CA bundle from secret %s: %v", s.config.CertSecretName, err)
+	} else if bundle != nil {
+		klog.Infof("Loaded CA bundle from secret %s", s.config.CertSecretName)
+		caBundle = bundle.CAPEM
+		fromSecret = len(bundle.CertPEM) > 0 && len(bundle.KeyPEM) > 0
+	}
+
+	// 2. If not from secret, try existing cert file.
+	if caBundle == nil && (!fileExists(s.config.CertFile) || !fileExists(s.config.KeyFile)) {
+		klog.Infof("Auto-generating certificate for webhook server (secret=%s service=%s)", s.config.CertSecretName, s.config.ServiceName)
+		b, err := webhookcert.EnsureCertificate(ctx, s.kubeClient, s.config.Namespace, s.config.CertSecretName, s.dnsNames(),
+			webhookcert.WithCertManager(s.dynamicClient))
+		if err != nil {
+			return false, fmt.Errorf("failed to auto-generate webhook certificates: %w", err)
+		}
+		caBundle = b
+		fromSecret = true
+	}
+
+	if caBundle != nil {
+		s.rotateCABundle(ctx, nil, caBundle)
+	}
+	return fromSecret, nil
+}
+
+// keyPair returns the TLS configuration serving the key pair, kept up to date as it is renewed. The key pair of
+// the secret is watched rather than read from the secret volume, so that all the replicas serve the certificate
+// generated or renewed by any of them at once. The files are read when they are provided without a secret.
+func (s *Server) keyPair(ctx context.Context, fromSecret bool) (*tls.Config, error) {
+	if fromSecret {
+		reloader := webhookcert.NewSecretKeyPairReloader(s.kubeClient, s.config.Namespace, s.config.CertSecretName)
+		go reloader.Run(ctx)
+		if !reloader.WaitForKeyPair(ctx, certWaitTimeout) {
+			return nil, fmt.Errorf("TLS key pair not found in secret %s, webhook server cannot start", s.config.CertSecretName)
+		}
+		return reloader.TLSConfig(), nil
+	}
+
+	// Wait for both cert and key files to exist (in case they are mounted by Kubernetes)
+	if !waitForCertsReady(s.config.CertFile, s.config.KeyFile) {
+		return nil, fmt.Errorf("TLS cert/key files not found, webhook server cannot start")
+	}
+	// The key pair is reloaded when the certificate is renewed
+	reloader, err := webhookcert.NewKeyPairReloader(s.config.CertFile, s.config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhook key pair: %w", err)
+	}
+	go reloader.Run(ctx, webhookcert.DefaultReloadInterval)
+	return reloader.TLSConfig(), nil
+}
+
+// newRotator renews the certificate in the secret before it expires, and rotates the CA bundle
+// of the webhook configurations when its CA changes.
+func (s *Server) newRotator() *webhookcert.Rotator {
+	rotator := webhookcert.NewRotator(s.kubeClient, s.config.Namespace, s.config.CertSecretName, s.dnsNames())
+	rotator.OnCAChange = s.rotateCABundle
+	return rotator
+}
+
+func (s *Server) rotateCABundle(ctx context.Context, previous, caBundle []byte) {
+	for _, name := range s.config.ValidatingWebhookConfigurations {
+		if err := webhookcert.RotateValidatingWebhookCABundle(ctx, s.kubeClient, name, previous, caBundle); err != nil {
+			klog.Warningf("Failed to update ValidatingWebhookConfiguration %s CA bundle: %v", name, err)
+		}
+	}
+	for _, name := range s.config.MutatingWebhookConfigurations {
+		if err := webhookcert.RotateMutatingWebhookCABundle(ctx, s.kubeClient, name, previous, caBundle); err != nil {
+			klog.Warningf("Failed to update MutatingWebhookConfiguration %s CA bundle: %v", name, err)
+		}
+	}
+	if len(s.config.CustomResourceDefinitions) == 0 || s.dynamicClient == nil {
+		return
+	}
+	port := s.config.ServicePort
+	if port == 0 {
+		port = defaultServicePort
+	}
+	service := apiextensionsv1.ServiceReference{Namespace: s.config.Namespace, Name: s.config.ServiceName, Port: &port}
+	for _, name := range s.config.CustomResourceDefinitions {
+		if err := conversion.ConfigureCustomResourceDefinition(ctx, s.dynamicClient, name, service, caBundle); err != nil {
+			klog.Warningf("Failed to configure the conversion webhook of CustomResourceDefinition %s: %v", name, err)
+		}
+	}
+}
+
+func (s *Server) dnsNames() []string {
+	names := []string{
+		fmt.Sprintf("%s.%s.svc", s.config.ServiceName, s.config.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", s.config.ServiceName, s.config.Namespace),
+	}
+	return append(names, s.config.CertExtraSANs...)
+}
+
+func waitForCertsReady(certFile, keyFile string) bool {
+	start := time.Now()
+	for {
+		if fileExists(certFile) && fileExists(keyFile) {
+			return true
+		}
+		if time.Since(start) > certWaitTimeout {
+			klog.Warningf("timeout waiting for TLS cert/key files to appear at %s and %s", certFile, keyFile)
+			return false
+		}
+		time.Sleep(certWaitInterval)
+	}
+}
+
+// fileExists returns true if the file exists.
+func fileExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	webhookcert "github.com/volcano-sh/kthena/pkg/webhook/cert"
+)
+
+func TestProvisionCertificate(t *testing.T) {
+	ctx := context.Background()
+	client := kubefake.NewSimpleClientset(&admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "validating"},
+		Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "validate.example.com"}},
+	})
+	s := New(Config{Namespace: "kthena-system", CertSecretName: "webhook-certs", ServiceName: "webhook"}, client, nil)
+	s.AddValidatingWebhookConfiguration("validating")
+	assert.Equal(t, []string{"webhook.kthena-system.svc", "webhook.kthena-system.svc.cluster.local"}, s.dnsNames())
+	s.config.CertExtraSANs = []string{"fd00:10:96::a"}
+	assert.Equal(t, []string{"webhook.kthena-system.svc", "webhook.kthena-system.svc.cluster.local", "fd00:10:96::a"}, s.dnsNames())
+
+	// Without a secret nor key pair files, the certificate is generated into the secret
+	fromSecret, err := s.provisionCertificate(ctx)
+	require.NoError(t, err)
+	assert.True(t, fromSecret)
+	bundle, err := webhookcert.LoadCertBundleFromSecret(ctx, client, "kthena-system", "webhook-certs")
+	require.NoError(t, err)
+	require.NotNil(t, bundle)
+	config, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, "validating", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, bundle.CAPEM, config.Webhooks[0].ClientConfig.CABundle)
+
+	// The existing secret is reused
+	fromSecret, err = s.provisionCertificate(ctx)
+	require.NoError(t, err)
+	assert.True(t, fromSecret)
+	reloaded, err := webhookcert.LoadCertBundleFromSecret(ctx, client, "kthena-system", "webhook-certs")
+	require.NoError(t, err)
+	assert.Equal(t, bundle.CAPEM, reloaded.CAPEM)
+}
+
+func TestProvisionCertificateReplicas(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := kubefake.NewSimpleClientset(&admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "validating"},
+		Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "validate.example.com"}},
+	})
+
+	// The replicas starting at the same time serve the same certificate, generated by one of them
+	const replicas = 3
+	certs := make([][]byte, replicas)
+	var wg sync.WaitGroup
+	for i := 0; i < replicas; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s := New(Config{Namespace: "kthena-system", CertSecretName: "webhook-certs", ServiceName: "webhook"}, client, nil)
+			s.AddValidatingWebhookConfiguration("validating")
+			fromSecret, err := s.provisionCertificate(ctx)
+			if !assert.NoError(t, err) {
+				return
+			}
+			tlsConfig, err := s.keyPair(ctx, fromSecret)
+			if !assert.NoError(t, err) {
+				return
+			}
+			cert, err := tlsConfig.GetCertificate(nil)
+			if assert.NoError(t, err) {
+				certs[i] = cert.Certificate[0]
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	bundle, err := webhookcert.LoadCertBundleFromSecret(ctx, client, "kthena-system", "webhook-certs")
+	require.NoError(t, err)
+	block, _ := pem.Decode(bundle.CertPEM)
+	require.NotNil(t, block)
+	for _, cert := range certs {
+		assert.Equal(t, block.Bytes, cert)
+	}
+	config, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, "validating", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, bundle.CAPEM, config.Webhooks[0].ClientConfig.CABundle)
+}
+
+func TestHandle(t *testing.T) {
+	s := New(Config{}, kubefake.NewSimpleClientset(), nil)
+	assert.Error(t, s.Ready(), "the server is not running")
+	s.Handle("/validate/example", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	for path, code := range map[string]int{
+		"/healthz":          http.StatusOK,
+		"/validate/example": http.StatusAccepted,
+		"/validate/unknown": http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, code, w.Code, path)
+	}
+}
+
+func TestRotateCABundleConversion(t *testing.T) {
+	ctx := context.Background()
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "modelservings.workload.serving.volcano.sh"},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), crd)
+	s := New(Config{Namespace: "kthena-system", ServiceName: "webhook"}, kubefake.NewSimpleClientset(), dynamicClient)
+	s.AddConversionCustomResourceDefinition("modelservings.workload.serving.volcano.sh")
+	// The CRDs not installed are skipped
+	s.AddConversionCustomResourceDefinition("modelinfers.workload.serving.volcano.sh")
+
+	s.rotateCABundle(ctx, nil, []byte("ca"))
+
+	got, err := dynamicClient.Resource(apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")).
+		Get(ctx, "modelservings.workload.serving.volcano.sh", metav1.GetOptions{})
+	require.NoError(t, err)
+	strategy, _, _ := unstructured.NestedString(got.Object, "spec", "conversion", "strategy")
+	assert.Equal(t, "Webhook", strategy)
+	service, _, _ := unstructured.NestedMap(got.Object, "spec", "conversion", "webhook", "clientConfig", "service")
+	assert.Equal(t, map[string]interface{}{"namespace": "kthena-system", "name": "webhook", "path": "/convert", "port": int64(443)}, service)
+}
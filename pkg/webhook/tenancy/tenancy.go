@@ -0,0 +1,190 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tenancy evaluates the TenancyPolicies for the admission webhooks: the models the namespaces may
+// reference and the accelerators their ModelServings may request.
+package tenancy
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+
+	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/model-serving-controller/utils"
+)
+
+// Guard checks the admitted objects against the TenancyPolicies selecting their namespace.
+type Guard struct {
+	kubeClient kubernetes.Interface
+	client     clientset.Interface
+}
+
+// NewGuard returns a Guard. No policy is enforced when the client is nil.
+func NewGuard(kubeClient kubernetes.Interface, client clientset.Interface) *Guard {
+	return &Guard{kubeClient: kubeClient, client: client}
+}
+
+// policies returns the TenancyPolicies selecting the namespace.
+func (g *Guard) policies(ctx context.Context, namespace string) ([]workloadv1alpha1.TenancyPolicy, error) {
+	if g == nil || g.client == nil {
+		return nil, nil
+	}
+	list, err := g.client.WorkloadV1alpha1().TenancyPolicies().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var namespaceLabels labels.Set
+	var selected []workloadv1alpha1.TenancyPolicy
+	for _, policy := range list.Items {
+		if policy.Spec.NamespaceSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid namespace selector of TenancyPolicy %s: %v", policy.Name, err)
+			}
+			if namespaceLabels == nil {
+				ns, err := g.kubeClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+				if err != nil {
+					return nil, err
+				}
+				namespaceLabels = labels.Set(ns.Labels)
+			}
+			if !selector.Matches(namespaceLabels) {
+				continue
+			}
+		}
+		selected = append(selected, policy)
+	}
+	return selected, nil
+}
+
+// Reference is a model referenced by a field.
+type Reference struct {
+	Path  *field.Path
+	Model string
+}
+
+// ValidateModels checks that the namespace may reference the models.
+func (g *Guard) ValidateModels(ctx context.Context, namespace string, models []Reference) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(models) == 0 {
+		return allErrs
+	}
+	policies, err := g.policies(ctx, namespace)
+	if err != nil {
+		return append(allErrs, field.InternalError(models[0].Path, fmt.Errorf("failed to load the tenancy policies: %v", err)))
+	}
+	for _, model := range models {
+		for _, policy := range policies {
+			if len(policy.Spec.AllowedModels) > 0 && !matchesAny(policy.Spec.AllowedModels, model.Model) {
+				allErrs = append(allErrs, field.Forbidden(model.Path,
+					fmt.Sprintf("model %q is not allowed in namespace %s by TenancyPolicy %s, allowed models: %s",
+						model.Model, namespace, policy.Name, strings.Join(policy.Spec.AllowedModels, ", "))))
+				break
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateGPUs checks that the accelerators of the ModelServing and of the other ModelServings of its namespace stay
+// within the cap of the policies. An update not increasing the accelerators is always allowed, so that the ModelServings
+// of a namespace over its cap can still be scaled down.
+func (g *Guard) ValidateGPUs(ctx context.Context, modelServing, oldModelServing *workloadv1alpha1.ModelServing) field.ErrorList {
+	var allErrs field.ErrorList
+	gpus := ModelServingGPUs(modelServing)
+	if gpus == 0 || (oldModelServing != nil && gpus <= ModelServingGPUs(oldModelServing)) {
+		return allErrs
+	}
+	specPath := field.NewPath("spec")
+	policies, err := g.policies(ctx, modelServing.Namespace)
+	if err != nil {
+		return append(allErrs, field.InternalError(specPath, fmt.Errorf("failed to load the tenancy policies: %v", err)))
+	}
+	var capPolicy *workloadv1alpha1.TenancyPolicy
+	for i := range policies {
+		if maxGPUs := policies[i].Spec.MaxGPUs; maxGPUs != nil && (capPolicy == nil || *maxGPUs < *capPolicy.Spec.MaxGPUs) {
+			capPolicy = &policies[i]
+		}
+	}
+	if capPolicy == nil {
+		return allErrs
+	}
+
+	modelServings, err := g.client.WorkloadV1alpha1().ModelServings(modelServing.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return append(allErrs, field.InternalError(specPath, fmt.Errorf("failed to list the ModelServings: %v", err)))
+	}
+	total := gpus
+	for i := range modelServings.Items {
+		other := &modelServings.Items[i]
+		if other.Name == modelServing.Name || other.DeletionTimestamp != nil {
+			continue
+		}
+		total += ModelServingGPUs(other)
+	}
+	if maxGPUs := *capPolicy.Spec.MaxGPUs; total > maxGPUs {
+		allErrs = append(allErrs, field.Forbidden(specPath,
+			fmt.Sprintf("the ModelServings of namespace %s would request %d GPUs, over the cap of %d set by TenancyPolicy %s",
+				modelServing.Namespace, total, maxGPUs, capPolicy.Name)))
+	}
+	return allErrs
+}
+
+// ModelServingGPUs returns the accelerators requested by all the pods of the ModelServing.
+func ModelServingGPUs(modelServing *workloadv1alpha1.ModelServing) int64 {
+	var perGroup int64
+	for i := range modelServing.Spec.Template.Roles {
+		role := &modelServing.Spec.Template.Roles[i]
+		gpus := podGPUs(&role.EntryTemplate)
+		if role.WorkerTemplate != nil {
+			gpus += int64(role.WorkerReplicas) * podGPUs(role.WorkerTemplate)
+		}
+		perGroup += int64(replicasOf(role.Replicas)) * gpus
+	}
+	return int64(replicasOf(modelServing.Spec.Replicas)) * perGroup
+}
+
+func podGPUs(template *workloadv1alpha1.PodTemplateSpec) int64 {
+	var gpus int64
+	for i := range template.Spec.Containers {
+		gpus += utils.AcceleratorCount(&template.Spec.Containers[i])
+	}
+	return gpus
+}
+
+func replicasOf(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+func matchesAny(patterns []string, model string) bool {
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, model); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenancy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+func newGuard(objects ...runtime.Object) *Guard {
+	kubeClient := kubefake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"tenant": "a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"tenant": "b"}}},
+	)
+	return NewGuard(kubeClient, fake.NewSimpleClientset(objects...))
+}
+
+func newTenancyPolicy(name, tenant string, allowedModels []string, maxGPUs *int64) *workloadv1alpha1.TenancyPolicy {
+	policy := &workloadv1alpha1.TenancyPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       workloadv1alpha1.TenancyPolicySpec{AllowedModels: allowedModels, MaxGPUs: maxGPUs},
+	}
+	if tenant != "" {
+		policy.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": tenant}}
+	}
+	return policy
+}
+
+func newModelServing(namespace, name string, replicas, workerReplicas int32, gpus int64) *workloadv1alpha1.ModelServing {
+	pod := workloadv1alpha1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		Name:      "engine",
+		Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{"nvidia.com/gpu": *resource.NewQuantity(gpus, resource.DecimalSI)}},
+	}}}}
+	return &workloadv1alpha1.ModelServing{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: workloadv1alpha1.ModelServingSpec{
+			Replicas: ptr.To(replicas),
+			Template: workloadv1alpha1.ServingGroup{Roles: []workloadv1alpha1.Role{{
+				Name:           "leader",
+				Replicas:       ptr.To[int32](1),
+				EntryTemplate:  pod,
+				WorkerReplicas: workerReplicas,
+				WorkerTemplate: &pod,
+			}}},
+		},
+	}
+}
+
+func TestValidateModels(t *testing.T) {
+	guard := newGuard(
+		newTenancyPolicy("team-a-models", "a", []string{"llama-*", "qwen3-8b"}, nil),
+		newTenancyPolicy("no-deepseek", "", []string{"llama-*", "qwen*", "mistral-*"}, nil),
+	)
+	tests := []struct {
+		name      string
+		namespace string
+		model     string
+		wantErr   string
+	}{
+		{name: "allowed by all the policies", namespace: "team-a", model: "llama-3-8b"},
+		{name: "exact name", namespace: "team-a", model: "qwen3-8b"},
+		{name: "denied by the policy of the namespace", namespace: "team-a", model: "mistral-7b", wantErr: "TenancyPolicy team-a-models"},
+		{name: "allowed in another namespace", namespace: "team-b", model: "mistral-7b"},
+		{name: "denied by the policy of all the namespaces", namespace: "team-b", model: "deepseek-r1", wantErr: "TenancyPolicy no-deepseek"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := guard.ValidateModels(context.Background(), tt.namespace, []Reference{
+				{Path: field.NewPath("spec").Child("modelName"), Model: tt.model},
+			})
+			if tt.wantErr == "" {
+				assert.Empty(t, errs)
+				return
+			}
+			if assert.Len(t, errs, 1) {
+				assert.Equal(t, field.ErrorTypeForbidden, errs[0].Type)
+				assert.Contains(t, errs[0].Error(), tt.wantErr)
+			}
+		})
+	}
+
+	assert.Empty(t, NewGuard(nil, nil).ValidateModels(context.Background(), "team-a", []Reference{
+		{Path: field.NewPath("spec").Child("modelName"), Model: "anything"},
+	}))
+}
+
+func TestValidateGPUs(t *testing.T) {
+	// 2 replicas of 1 entry and 1 worker with 4 GPUs each use 16 GPUs
+	existing := newModelServing("team-a", "llama", 2, 1, 4)
+	guard := newGuard(
+		newTenancyPolicy("team-a-gpus", "a", nil, ptr.To[int64](24)),
+		newTenancyPolicy("all-gpus", "", nil, ptr.To[int64](64)),
+		existing,
+		newModelServing("team-b", "qwen", 6, 1, 8),
+	)
+	tests := []struct {
+		name         string
+		modelServing *workloadv1alpha1.ModelServing
+		old          *workloadv1alpha1.ModelServing
+		wantErr      string
+	}{
+		{
+			name:         "within the cap",
+			modelServing: newModelServing("team-a", "qwen", 1, 1, 4),
+		},
+		{
+			name:         "over the lowest cap",
+			modelServing: newModelServing("team-a", "qwen", 2, 1, 4),
+			wantErr:      "would request 32 GPUs, over the cap of 24 set by TenancyPolicy team-a-gpus",
+		},
+		{
+			name:         "scaling up the existing ModelServing",
+			modelServing: newModelServing("team-a", "llama", 3, 1, 4),
+			old:          existing,
+		},
+		{
+			name:         "scaling up over the cap",
+			modelServing: newModelServing("team-a", "llama", 4, 1, 4),
+			old:          existing,
+			wantErr:      "would request 32 GPUs",
+		},
+		{
+			name:         "scaling down a namespace over its cap",
+			modelServing: newModelServing("team-b", "qwen", 5, 1, 8),
+			old:          newModelServing("team-b", "qwen", 6, 1, 8),
+		},
+		{
+			name:         "without GPUs",
+			modelServing: newModelServing("team-b", "embedding", 8, 0, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := guard.ValidateGPUs(context.Background(), tt.modelServing, tt.old)
+			if tt.wantErr == "" {
+				assert.Empty(t, errs)
+				return
+			}
+			if assert.Len(t, errs, 1) {
+				assert.Contains(t, errs[0].Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModelServingGPUs(t *testing.T) {
+	assert.Equal(t, int64(24), ModelServingGPUs(newModelServing("default", "llama", 3, 1, 4)))
+	assert.Equal(t, int64(12), ModelServingGPUs(newModelServing("default", "llama", 1, 2, 4)))
+	assert.Equal(t, int64(0), ModelServingGPUs(newModelServing("default", "llama", 3, 1, 0)))
+}
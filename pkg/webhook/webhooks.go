@@ -0,0 +1,107 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook groups the admission webhooks of kthena into sets, which are served by the webhook
+// servers embedded in kthena-controller-manager and kthena-router, or all together by kthena-webhook.
+package webhook
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/kubernetes"
+
+	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
+	kthenascheme "github.com/volcano-sh/kthena/client-go/clientset/versioned/scheme"
+	networkingv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	workloadv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+	routerwebhook "github.com/volcano-sh/kthena/pkg/kthena-router/webhook"
+	"github.com/volcano-sh/kthena/pkg/model-booster-webhook/handlers"
+	modelservingwebhook "github.com/volcano-sh/kthena/pkg/model-serving-controller/webhook"
+	"github.com/volcano-sh/kthena/pkg/webhook/conversion"
+	"github.com/volcano-sh/kthena/pkg/webhook/server"
+)
+
+const (
+	// Workload serves the webhooks of the workload.serving.volcano.sh API.
+	Workload = "workload"
+	// Networking serves the webhooks of the networking.serving.volcano.sh API.
+	Networking = "networking"
+)
+
+// Clients are the clients the webhooks may need.
+type Clients struct {
+	Kube   kubernetes.Interface
+	Kthena clientset.Interface
+}
+
+// Set is a group of admission webhooks served together.
+type Set struct {
+	Name string
+	// GroupVersion is the API admitted by the webhooks, kthena-webhook skips the sets whose API is not served.
+	GroupVersion string
+	// Register adds the webhooks and their configurations to the server.
+	Register func(s *server.Server, clients Clients)
+}
+
+var sets = map[string]Set{
+	Workload: {
+		Name:         Workload,
+		GroupVersion: workloadv1alpha1.SchemeGroupVersion.String(),
+		Register: func(s *server.Server, clients Clients) {
+			s.Handle("/validate-workload-ai-v1alpha1-modelServing", modelservingwebhook.NewModelServingValidator(clients.Kube, clients.Kthena).Handle)
+			s.Handle("/mutate/modelserving", modelservingwebhook.NewModelServingMutator().Handle)
+			s.Handle("/validate/modelbooster", handlers.NewModelValidator(clients.Kube, s.Namespace()).Handle)
+			s.Handle("/mutate/modelbooster", handlers.NewModelMutator().Handle)
+			s.Handle("/validate/autoscalingpolicy", handlers.NewAutoscalingPolicyValidator().Handle)
+			s.Handle("/mutate/autoscalingpolicy", handlers.NewAutoscalingPolicyMutator().Handle)
+			s.Handle("/validate/autoscalingpolicybinding", handlers.NewAutoscalingBindingValidator(clients.Kthena).Handle)
+			s.AddValidatingWebhookConfiguration("kthena-controller-manager-validating-webhook")
+			s.AddMutatingWebhookConfiguration("kthena-controller-manager-mutating-webhook")
+			s.Handle(conversion.Path, conversion.NewWebhook(kthenascheme.Scheme).Handle)
+			s.AddConversionCustomResourceDefinition("modelservings.workload.serving.volcano.sh")
+		},
+	},
+	Networking: {
+		Name:         Networking,
+		GroupVersion: networkingv1alpha1.SchemeGroupVersion.String(),
+		Register: func(s *server.Server, clients Clients) {
+			validator := routerwebhook.NewKthenaRouterValidator(clients.Kube, clients.Kthena)
+			s.Handle("/validate/modelroute", validator.HandleModelRoute)
+			s.Handle("/validate/modelserver", validator.HandleModelServer)
+			s.AddValidatingWebhookConfiguration("kthena-router-validating-webhook")
+		},
+	},
+}
+
+// Lookup returns the set of webhooks with the given name.
+func Lookup(name string) (Set, error) {
+	set, ok := sets[name]
+	if !ok {
+		return Set{}, fmt.Errorf("unknown webhook set %q, expected one of %v", name, Names())
+	}
+	return set, nil
+}
+
+// Names returns the names of all the sets of webhooks.
+func Names() []string {
+	names := make([]string, 0, len(sets))
+	for name := range sets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,58 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	kthenafake "github.com/volcano-sh/kthena/client-go/clientset/versioned/fake"
+	"github.com/volcano-sh/kthena/pkg/webhook/server"
+)
+
+func TestLookup(t *testing.T) {
+	assert.Equal(t, []string{Networking, Workload}, Names())
+	set, err := Lookup(Networking)
+	require.NoError(t, err)
+	assert.Equal(t, "networking.serving.volcano.sh/v1alpha1", set.GroupVersion)
+	_, err = Lookup("unknown")
+	assert.Error(t, err)
+}
+
+func TestRegister(t *testing.T) {
+	paths := map[string][]string{
+		Workload:   {"/validate-workload-ai-v1alpha1-modelServing", "/mutate/modelserving", "/validate/modelbooster", "/mutate/modelbooster", "/validate/autoscalingpolicy", "/mutate/autoscalingpolicy", "/validate/autoscalingpolicybinding"},
+		Networking: {"/validate/modelroute", "/validate/modelserver"},
+	}
+	clients := Clients{Kube: kubefake.NewSimpleClientset(), Kthena: kthenafake.NewSimpleClientset()}
+	for _, name := range Names() {
+		s := server.New(server.Config{}, clients.Kube, nil)
+		set, err := Lookup(name)
+		require.NoError(t, err)
+		set.Register(s, clients)
+		for _, path := range paths[name] {
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+			assert.NotEqual(t, http.StatusNotFound, w.Code, path)
+		}
+	}
+}
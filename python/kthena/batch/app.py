@@ -0,0 +1,140 @@
+# Copyright The Volcano Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import argparse
+import json
+import os
+import signal
+import subprocess
+import tempfile
+
+from kthena.batch.runner import BatchRunner, Progress, serve_progress
+from kthena.downloader.logger import setup_logger
+
+logger = setup_logger()
+
+TERMINATION_LOG = "/dev/termination-log"
+
+
+def s3_environment() -> dict:
+    env = os.environ.copy()
+    if os.getenv("ACCESS_KEY"):
+        env["AWS_ACCESS_KEY_ID"] = os.getenv("ACCESS_KEY")
+    if os.getenv("SECRET_KEY"):
+        env["AWS_SECRET_ACCESS_KEY"] = os.getenv("SECRET_KEY")
+    if os.getenv("ENDPOINT"):
+        env["AWS_ENDPOINT_URL"] = os.getenv("ENDPOINT")
+    return env
+
+
+def copy_s3(source: str, destination: str):
+    result = subprocess.run(["aws", "s3", "cp", source, destination], env=s3_environment(),
+                            capture_output=True, text=True)
+    if result.returncode != 0:
+        raise Exception(f"failed to copy {source} to {destination}: {result.stderr.strip()}")
+
+
+def read_requests(uri: str) -> list:
+    path = uri
+    if uri.startswith("s3://"):
+        path = os.path.join(tempfile.mkdtemp(), "input.jsonl")
+        copy_s3(uri, path)
+
+    requests = []
+    with open(path) as f:
+        for number, line in enumerate(f, start=1):
+            line = line.strip()
+            if not line:
+                continue
+            try:
+                requests.append(json.loads(line))
+            except json.JSONDecodeError as e:
+                raise ValueError(f"invalid request at line {number}: {e}") from e
+    return requests
+
+
+def write_results(uri: str, results: list):
+    path = uri
+    if uri.startswith("s3://"):
+        path = os.path.join(tempfile.mkdtemp(), "output.jsonl")
+    else:
+        os.makedirs(os.path.dirname(path) or ".", exist_ok=True)
+
+    with open(path, "w") as f:
+        for result in results:
+            f.write(json.dumps(result) + "\n")
+
+    if uri.startswith("s3://"):
+        copy_s3(path, uri)
+
+
+def write_termination_message(progress: Progress):
+    try:
+        with open(TERMINATION_LOG, "w") as f:
+            json.dump(progress.to_dict(), f)
+    except OSError as e:
+        logger.warning(f"Failed to write termination message: {e}")
+
+
+def parse_arguments() -> argparse.Namespace:
+    parser = argparse.ArgumentParser(
+        description="Sends the requests of a batch file through the kthena router",
+        formatter_class=argparse.ArgumentDefaultsHelpFormatter
+    )
+    parser.add_argument("--input", type=str, required=True,
+                        help="Path or s3:// URI of the JSONL file of requests.")
+    parser.add_argument("--output", type=str, required=True,
+                        help="Path or s3:// URI of the JSONL file the results are written to.")
+    parser.add_argument("--router-url", type=str, required=True, help="Base URL of the kthena router.")
+    parser.add_argument("--endpoint", type=str, default="/v1/chat/completions",
+                        help="API endpoint the requests are sent to.")
+    parser.add_argument("--model", type=str, default=None, help="Overrides the model of every request.")
+    parser.add_argument("--concurrency", type=int, default=16, help="Maximum number of requests in flight.")
+    parser.add_argument("--max-retries", type=int, default=3, help="Maximum number of retries of a request.")
+    parser.add_argument("--progress-port", type=int, default=8090, help="Port the progress is served on.")
+    return parser.parse_args()
+
+
+def main():
+    args = parse_arguments()
+    progress = Progress()
+    serve_progress(progress, args.progress_port)
+    try:
+        requests = read_requests(args.input)
+        logger.info(f"Loaded {len(requests)} requests from {args.input}")
+        runner = BatchRunner(
+            router_url=args.router_url,
+            endpoint=args.endpoint,
+            concurrency=args.concurrency,
+            max_retries=args.max_retries,
+            model=args.model,
+            api_key=os.getenv("API_KEY"),
+            progress=progress,
+        )
+        # The controller deletes the Job of a cancelled or expired batch: stop sending requests, and write
+        # the results of the requests already sent within the grace period of the pod
+        signal.signal(signal.SIGTERM, lambda signum, frame: runner.stop())
+        results = runner.run(requests)
+        write_results(args.output, results)
+        logger.info(f"Batch finished: {progress.to_dict()}")
+    except Exception as e:
+        logger.error(f"An error occurred: {e}")
+        progress.error = str(e)
+        write_termination_message(progress)
+        exit(1)
+    write_termination_message(progress)
+
+
+if __name__ == "__main__":
+    main()
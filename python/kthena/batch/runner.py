@@ -0,0 +1,177 @@
+# Copyright The Volcano Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import json
+import threading
+import time
+import urllib.error
+import urllib.request
+import uuid
+from concurrent.futures import ThreadPoolExecutor
+from http.server import BaseHTTPRequestHandler, ThreadingHTTPServer
+
+from kthena.downloader.logger import setup_logger
+
+logger = setup_logger()
+
+RETRYABLE_STATUS_CODES = {408, 429, 500, 502, 503, 504}
+MAX_BACKOFF_SECONDS = 30
+
+
+class Progress:
+    """Thread-safe counters of the requests of a batch."""
+
+    def __init__(self):
+        self._lock = threading.Lock()
+        self.total = 0
+        self.completed = 0
+        self.failed = 0
+        self.error = None
+
+    def set_total(self, total: int):
+        with self._lock:
+            self.total = total
+
+    def record(self, succeeded: bool):
+        with self._lock:
+            if succeeded:
+                self.completed += 1
+            else:
+                self.failed += 1
+
+    def to_dict(self) -> dict:
+        with self._lock:
+            result = {"total": self.total, "completed": self.completed, "failed": self.failed}
+            if self.error:
+                result["error"] = self.error
+            return result
+
+
+def serve_progress(progress: Progress, port: int) -> ThreadingHTTPServer:
+    """Serves the progress of the batch, polled by the controller while the batch is running."""
+
+    class ProgressHandler(BaseHTTPRequestHandler):
+        def do_GET(self):
+            if self.path != "/progress":
+                self.send_error(404)
+                return
+            body = json.dumps(progress.to_dict()).encode()
+            self.send_response(200)
+            self.send_header("Content-Type", "application/json")
+            self.send_header("Content-Length", str(len(body)))
+            self.end_headers()
+            self.wfile.write(body)
+
+        def log_message(self, format, *args):
+            pass
+
+    server = ThreadingHTTPServer(("", port), ProgressHandler)
+    threading.Thread(target=server.serve_forever, daemon=True).start()
+    return server
+
+
+class BatchRunner:
+    """Sends the requests of a batch through the router at a bounded concurrency."""
+
+    def __init__(self, router_url: str, endpoint: str, concurrency: int = 16, max_retries: int = 3,
+                 model: str = None, api_key: str = None, timeout: float = 600, progress: Progress = None):
+        self.url = router_url.rstrip("/") + endpoint
+        self.concurrency = concurrency
+        self.max_retries = max_retries
+        self.model = model
+        self.api_key = api_key
+        self.timeout = timeout
+        self.progress = progress or Progress()
+        self._stopped = threading.Event()
+
+    def stop(self):
+        """Stops sending requests, the requests which were not sent are reported as stopped."""
+        self._stopped.set()
+
+    def run(self, requests: list) -> list:
+        """Returns the results of the requests, in the order of the requests."""
+        self.progress.set_total(len(requests))
+        with ThreadPoolExecutor(max_workers=self.concurrency) as executor:
+            return list(executor.map(self._process, requests))
+
+    def _process(self, request: dict) -> dict:
+        result = {
+            "id": f"batch_req_{uuid.uuid4().hex}",
+            "custom_id": request.get("custom_id"),
+            "response": None,
+            "error": None,
+        }
+        if self._stopped.is_set():
+            result["error"] = {"code": "batch_stopped", "message": "the batch was stopped before the request was sent"}
+            return result
+        body = dict(request.get("body") or {})
+        if self.model:
+            body["model"] = self.model
+
+        status_code, response_body, error = self._send_with_retries(body)
+        if status_code is not None:
+            result["response"] = {
+                "status_code": status_code,
+                "request_id": result["id"],
+                "body": response_body,
+            }
+        if error:
+            result["error"] = {"code": "request_failed", "message": error}
+        succeeded = status_code is not None and 200 <= status_code < 300
+        self.progress.record(succeeded)
+        return result
+
+    def _send_with_retries(self, body: dict):
+        data = json.dumps(body).encode()
+        headers = {"Content-Type": "application/json"}
+        if self.api_key:
+            headers["Authorization"] = f"Bearer {self.api_key}"
+
+        status_code, response_body, error = None, None, None
+        for attempt in range(self.max_retries + 1):
+            retry_after = None
+            try:
+                req = urllib.request.Request(self.url, data=data, headers=headers, method="POST")
+                with urllib.request.urlopen(req, timeout=self.timeout) as resp:
+                    return resp.status, _parse_body(resp.read()), None
+            except urllib.error.HTTPError as e:
+                status_code, response_body = e.code, _parse_body(e.read())
+                error = f"router responded with status code {e.code}"
+                if e.code not in RETRYABLE_STATUS_CODES:
+                    return status_code, response_body, error
+                retry_after = e.headers.get("Retry-After")
+            except (urllib.error.URLError, TimeoutError, ConnectionError) as e:
+                status_code, response_body, error = None, None, f"failed to send request: {e}"
+
+            if attempt < self.max_retries and not self._stopped.is_set():
+                time.sleep(_backoff(attempt, retry_after))
+            else:
+                break
+        return status_code, response_body, error
+
+
+def _backoff(attempt: int, retry_after: str = None) -> float:
+    if retry_after:
+        try:
+            return min(float(retry_after), MAX_BACKOFF_SECONDS)
+        except ValueError:
+            pass
+    return min(2 ** attempt, MAX_BACKOFF_SECONDS)
+
+
+def _parse_body(raw: bytes):
+    try:
+        return json.loads(raw)
+    except (json.JSONDecodeError, UnicodeDecodeError):
+        return raw.decode(errors="replace")
@@ -48,6 +48,16 @@ def load_config(config_str: str = None) -> dict:
     return config
 
 
+def load_checksums(checksums_str: str) -> dict:
+    try:
+        checksums = json.loads(checksums_str)
+    except json.JSONDecodeError as e:
+        raise ValueError("Invalid checksums JSON format.") from e
+    if not isinstance(checksums, dict) or not all(isinstance(v, str) for v in checksums.values()):
+        raise ValueError("Checksums must be a JSON object of file path to SHA256 digest.")
+    return checksums
+
+
 def parse_arguments() -> argparse.Namespace:
     parser = argparse.ArgumentParser(
         description="Universal Model Downloader Tool for AI/ML workflows",
@@ -86,6 +96,26 @@ def parse_arguments() -> argparse.Namespace:
              "- endpoint:  obs endpoint URL, for s3, not a required config but a private bucket \n"
              "Example: '{\"hf_token\": \"your_huggingface_token\", \"hf_endpoint\": \"custom_endpoint\", \"hf_revision\": \"main\", \"access_key\": \"your_access_key\", \"secret_key\": \"your_secret_key\", \"endpoint\": \"your_endpoint_url\"}'"
     )
+    parser.add_argument(
+        "--checksums",
+        type=str,
+        default=None,
+        help="JSON object mapping file paths relative to the output directory to their expected SHA256 digests. "
+             "Files from Hugging Face are always verified against the repository manifest, these take precedence."
+    )
+    parser.add_argument(
+        "--max-retries",
+        type=int,
+        default=3,
+        help="Number of times a failed download or checksum verification is retried. "
+             "Retries resume from the files already downloaded."
+    )
+    parser.add_argument(
+        "--chunk-size-mb",
+        type=int,
+        default=None,
+        help="Part size in MB for multipart S3 downloads."
+    )
     args = parser.parse_args()
     args.output_dir = str(Path(args.output_dir).expanduser().resolve())
     logger.info(f"Resolved output directory: {args.output_dir}")
@@ -96,6 +126,11 @@ def main():
     try:
         args = parse_arguments()
         config = load_config(args.config)
+        config["max_retries"] = args.max_retries
+        if args.checksums:
+            config["checksums"] = load_checksums(args.checksums)
+        if args.chunk_size_mb:
+            config["chunk_size_mb"] = args.chunk_size_mb
         download_model(
             source=args.source,
             output_dir=args.output_dir,
@@ -15,15 +15,27 @@
 import os
 import threading
 from abc import ABC, abstractmethod
-from typing import Optional
+from typing import Dict, Optional
 from typing import Tuple
 from urllib.parse import urlparse
 
+from kthena.downloader.checksum import verify_checksums
 from kthena.downloader.lock import LockManager
 from kthena.downloader.logger import setup_logger
+from kthena.downloader.progress import (
+    PHASE_COMPLETED,
+    PHASE_DOWNLOADING,
+    PHASE_FAILED,
+    PHASE_VERIFYING,
+    PodAnnotationPublisher,
+    ProgressReporter,
+)
 
 logger = setup_logger()
 
+# Written once the model is downloaded and verified, the model cache agent only advertises complete models.
+COMPLETE_MARKER_FILE = ".kthena-complete"
+
 
 def parse_bucket_from_model_url(url: str, scheme: str) -> Tuple[str, str]:
     result = urlparse(url, scheme=scheme)
@@ -36,11 +48,57 @@ class ModelDownloader(ABC):
     def __init__(self):
         self.lock_manager: Optional[LockManager] = None
         self.stop_event = threading.Event()
+        # Expected SHA256 digests keyed by path relative to the output directory.
+        self.checksums: Dict[str, str] = {}
+        self.max_retries = 3
+        self.retry_backoff_seconds = 10
 
     @abstractmethod
     def download(self, output_dir: str):
         pass
 
+    def expected_checksums(self) -> Dict[str, str]:
+        """Checksums published by the source, e.g. a Hugging Face repository manifest."""
+        return {}
+
+    def total_bytes(self) -> Optional[int]:
+        """Total size of the model if the source can tell it up front."""
+        return None
+
+    def _download_and_verify(self, output_dir: str):
+        reporter = ProgressReporter(output_dir, publisher=PodAnnotationPublisher.from_env())
+        try:
+            reporter.total_bytes = self.total_bytes()
+        except Exception as e:
+            logger.warning(f"Failed to determine model size, progress will not include a percentage: {e}")
+        reporter.start()
+        marker = os.path.join(output_dir, COMPLETE_MARKER_FILE)
+        if os.path.exists(marker):
+            os.remove(marker)
+        attempt = 0
+        while True:
+            try:
+                reporter.set_phase(PHASE_DOWNLOADING)
+                # Downloads resume from what is already in output_dir, so a retry only fetches missing files.
+                self.download(output_dir)
+                checksums = self.expected_checksums()
+                # User provided checksums take precedence over the ones published by the source.
+                checksums.update(self.checksums)
+                reporter.set_phase(PHASE_VERIFYING)
+                verify_checksums(output_dir, checksums)
+                with open(marker, "w"):
+                    pass
+                reporter.finish(PHASE_COMPLETED)
+                return
+            except Exception as e:
+                attempt += 1
+                if attempt > self.max_retries:
+                    reporter.finish(PHASE_FAILED)
+                    raise
+                backoff = self.retry_backoff_seconds * 2 ** (attempt - 1)
+                logger.warning(f"Download attempt {attempt} failed: {e}. Retrying in {backoff}s")
+                self.stop_event.wait(timeout=backoff)
+
     def download_model(self, output_dir: str):
         os.makedirs(output_dir, exist_ok=True)
         lock_path = os.path.join(output_dir, ".lock")
@@ -52,7 +110,7 @@ class ModelDownloader(ABC):
                         logger.info(
                             f"Acquired lock successfully. Starting download to {output_dir}"
                         )
-                        self.download(output_dir)
+                        self._download_and_verify(output_dir)
                         break
                     except Exception as e:
                         logger.error(f"Error during model download: {e}")
@@ -72,6 +130,14 @@ class ModelDownloader(ABC):
 
 
 def get_downloader(source: str, config: dict, max_workers: int = 8) -> ModelDownloader:
+    downloader = _new_downloader(source, config, max_workers)
+    downloader.checksums = dict(config.get("checksums") or {})
+    if config.get("max_retries") is not None:
+        downloader.max_retries = int(config["max_retries"])
+    return downloader
+
+
+def _new_downloader(source: str, config: dict, max_workers: int) -> ModelDownloader:
     try:
         if source.startswith("s3://") or source.startswith("obs://"):
             from kthena.downloader.s3 import S3Downloader
@@ -81,6 +147,8 @@ def get_downloader(source: str, config: dict, max_workers: int = 8) -> ModelDown
                 access_key=config.get("access_key"),
                 secret_key=config.get("secret_key"),
                 endpoint=config.get("endpoint"),
+                max_concurrency=max_workers,
+                chunk_size_mb=config.get("chunk_size_mb"),
             )
         elif source.startswith("pvc://"):
             from kthena.downloader.pvc import PVCDownloader
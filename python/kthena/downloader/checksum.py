@@ -0,0 +1,125 @@
+# Copyright The Volcano Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+import hashlib
+import json
+import os
+from typing import Dict, List
+
+from kthena.downloader.logger import setup_logger
+
+logger = setup_logger()
+
+# Prefix of expected digests taken from the git blob id of small, non-LFS files in a Hugging Face repository.
+GIT_SHA1_PREFIX = "git-sha1:"
+SHA256_PREFIX = "sha256:"
+
+# Verified digests are remembered here so that a restarted pod does not re-hash hundreds of gigabytes.
+VERIFIED_STATE_FILE = ".kthena-verified.json"
+
+_READ_CHUNK_SIZE = 8 * 1024 * 1024
+
+
+class ChecksumMismatchError(Exception):
+    def __init__(self, files: List[str]):
+        super().__init__(f"checksum verification failed for: {', '.join(sorted(files))}")
+        self.files = files
+
+
+def sha256_file(path: str) -> str:
+    digest = hashlib.sha256()
+    with open(path, "rb") as f:
+        for chunk in iter(lambda: f.read(_READ_CHUNK_SIZE), b""):
+            digest.update(chunk)
+    return digest.hexdigest()
+
+
+def git_blob_sha1_file(path: str) -> str:
+    digest = hashlib.sha1()
+    digest.update(f"blob {os.path.getsize(path)}\0".encode())
+    with open(path, "rb") as f:
+        for chunk in iter(lambda: f.read(_READ_CHUNK_SIZE), b""):
+            digest.update(chunk)
+    return digest.hexdigest()
+
+
+def _digest(path: str, expected: str) -> str:
+    if expected.startswith(GIT_SHA1_PREFIX):
+        return GIT_SHA1_PREFIX + git_blob_sha1_file(path)
+    return sha256_file(path)
+
+
+def _normalize(expected: str) -> str:
+    expected = expected.strip().lower()
+    return expected.removeprefix(SHA256_PREFIX)
+
+
+def _load_state(output_dir: str) -> Dict[str, dict]:
+    try:
+        with open(os.path.join(output_dir, VERIFIED_STATE_FILE)) as f:
+            return json.load(f)
+    except (OSError, ValueError):
+        return {}
+
+
+def _save_state(output_dir: str, state: Dict[str, dict]):
+    path = os.path.join(output_dir, VERIFIED_STATE_FILE)
+    tmp = path + ".tmp"
+    try:
+        with open(tmp, "w") as f:
+            json.dump(state, f)
+        os.replace(tmp, path)
+    except OSError as e:
+        logger.warning(f"Failed to persist verified checksums: {e}")
+
+
+def verify_checksums(output_dir: str, checksums: Dict[str, str]):
+    """
+    Verify files under output_dir against the expected digests keyed by relative path.
+    Files that are missing or do not match are deleted so that the next download attempt
+    fetches them again, and a ChecksumMismatchError listing them is raised.
+    """
+    if not checksums:
+        return
+    state = _load_state(output_dir)
+    failed = []
+    for rel_path, expected in checksums.items():
+        expected = _normalize(expected)
+        path = os.path.join(output_dir, rel_path)
+        if not os.path.isfile(path):
+            logger.error(f"File {rel_path} is missing, expected checksum {expected}")
+            failed.append(rel_path)
+            continue
+        stat = os.stat(path)
+        cached = state.get(rel_path)
+        if cached and cached.get("digest") == expected and cached.get("size") == stat.st_size \
+                and cached.get("mtime") == stat.st_mtime:
+            continue
+        logger.info(f"Verifying checksum of {rel_path}")
+        actual = _digest(path, expected)
+        if actual != expected:
+            logger.error(f"Checksum mismatch for {rel_path}: expected {expected}, got {actual}")
+            failed.append(rel_path)
+            state.pop(rel_path, None)
+            try:
+                os.remove(path)
+            except OSError as e:
+                logger.warning(f"Failed to remove corrupted file {rel_path}: {e}")
+            continue
+        state[rel_path] = {"digest": expected, "size": stat.st_size, "mtime": stat.st_mtime}
+    _save_state(output_dir, state)
+    if failed:
+        raise ChecksumMismatchError(failed)
+    logger.info(f"Verified checksums of {len(checksums)} files")
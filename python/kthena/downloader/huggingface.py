@@ -12,7 +12,11 @@
 # See the License for the specific language governing permissions and
 # limitations under the License.
 
-from huggingface_hub import snapshot_download
+from typing import Dict, Optional
+
+from huggingface_hub import HfApi, snapshot_download
+
+from kthena.downloader.checksum import GIT_SHA1_PREFIX
 
 from kthena.downloader.logger import setup_logger
 
@@ -31,10 +35,35 @@ class HuggingFaceDownloader(ModelDownloader):
         self.hf_endpoint = hf_endpoint
         self.force_download = force_download
         self.max_workers = max_workers
+        self._siblings = None
+
+    def _repo_files(self):
+        if self._siblings is None:
+            api = HfApi(endpoint=self.hf_endpoint, token=self.hf_token)
+            info = api.model_info(self.model_uri, revision=self.hf_revision, files_metadata=True)
+            self._siblings = info.siblings or []
+        return self._siblings
+
+    def expected_checksums(self) -> Dict[str, str]:
+        checksums = {}
+        for sibling in self._repo_files():
+            if sibling.lfs is not None:
+                checksums[sibling.rfilename] = sibling.lfs.sha256
+            elif sibling.blob_id:
+                # Small files are stored in git directly and only carry the git blob id.
+                checksums[sibling.rfilename] = GIT_SHA1_PREFIX + sibling.blob_id
+        return checksums
+
+    def total_bytes(self) -> Optional[int]:
+        sizes = [sibling.size for sibling in self._repo_files()]
+        if not sizes or any(size is None for size in sizes):
+            return None
+        return sum(sizes)
 
     def download(self, output_dir: str):
         logger.info(f"Downloading model from Hugging Face: {self.model_uri}")
         try:
+            # snapshot_download keeps partially downloaded files under output_dir and resumes them.
             snapshot_download(
                 repo_id=self.model_uri,
                 revision=self.hf_revision,
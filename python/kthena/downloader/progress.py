@@ -0,0 +1,167 @@
+# Copyright The Volcano Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+import json
+import os
+import ssl
+import threading
+import time
+import urllib.error
+import urllib.request
+from datetime import datetime, timezone
+from typing import Optional
+
+from kthena.downloader.logger import setup_logger
+
+logger = setup_logger()
+
+# Annotation on the downloader pod that the model booster controller aggregates into the ModelBooster status.
+PROGRESS_ANNOTATION = "modelbooster.volcano.sh/download-progress"
+
+PHASE_DOWNLOADING = "Downloading"
+PHASE_VERIFYING = "Verifying"
+PHASE_COMPLETED = "Completed"
+PHASE_FAILED = "Failed"
+
+_SERVICE_ACCOUNT_DIR = "/var/run/secrets/kubernetes.io/serviceaccount"
+_IGNORED_FILES = {".lock", ".kthena-verified.json", ".kthena-complete"}
+
+
+def directory_size(path: str) -> int:
+    total = 0
+    for root, _, files in os.walk(path):
+        for name in files:
+            if name in _IGNORED_FILES:
+                continue
+            try:
+                total += os.lstat(os.path.join(root, name)).st_size
+            except OSError:
+                # Temporary files may be renamed while walking.
+                continue
+    return total
+
+
+class PodAnnotationPublisher:
+    """Patches the progress annotation onto the pod the downloader runs in."""
+
+    def __init__(self, namespace: str, pod_name: str, host: str, port: str):
+        self.url = f"https://{host}:{port}/api/v1/namespaces/{namespace}/pods/{pod_name}"
+        self.disabled = False
+
+    @classmethod
+    def from_env(cls) -> Optional["PodAnnotationPublisher"]:
+        pod_name = os.getenv("POD_NAME")
+        namespace = os.getenv("POD_NAMESPACE")
+        host = os.getenv("KUBERNETES_SERVICE_HOST")
+        port = os.getenv("KUBERNETES_SERVICE_PORT", "443")
+        if not pod_name or not namespace or not host:
+            return None
+        return cls(namespace, pod_name, host, port)
+
+    def publish(self, progress: dict):
+        if self.disabled:
+            return
+        body = json.dumps({"metadata": {"annotations": {PROGRESS_ANNOTATION: json.dumps(progress)}}}).encode()
+        try:
+            with open(os.path.join(_SERVICE_ACCOUNT_DIR, "token")) as f:
+                token = f.read().strip()
+            request = urllib.request.Request(self.url, data=body, method="PATCH", headers={
+                "Authorization": f"Bearer {token}",
+                "Content-Type": "application/merge-patch+json",
+            })
+            context = ssl.create_default_context(cafile=os.path.join(_SERVICE_ACCOUNT_DIR, "ca.crt"))
+            with urllib.request.urlopen(request, context=context, timeout=10):
+                pass
+        except urllib.error.HTTPError as e:
+            if e.code in (401, 403):
+                # The pod's service account is not allowed to patch itself, progress is only logged.
+                logger.warning(f"Not allowed to publish download progress, disabling: {e}")
+                self.disabled = True
+                return
+            logger.warning(f"Failed to publish download progress: {e}")
+        except Exception as e:
+            logger.warning(f"Failed to publish download progress: {e}")
+
+
+class ProgressReporter:
+    """
+    Periodically measures the bytes present in the output directory and reports them, together with the
+    percentage and estimated time remaining when the total size is known. Bytes already present when the
+    reporter starts (e.g. from an interrupted download) count as downloaded but not towards the rate.
+    """
+
+    def __init__(self, output_dir: str, publisher: Optional[PodAnnotationPublisher] = None, interval: float = 10):
+        self.output_dir = output_dir
+        self.publisher = publisher
+        self.interval = interval
+        self.total_bytes: Optional[int] = None
+        self.phase = PHASE_DOWNLOADING
+        self._start_time = time.monotonic()
+        self._start_bytes = directory_size(output_dir)
+        self._stop_event = threading.Event()
+        self._thread: Optional[threading.Thread] = None
+        self._lock = threading.Lock()
+
+    def start(self):
+        self._thread = threading.Thread(target=self._run, daemon=True)
+        self._thread.start()
+
+    def set_phase(self, phase: str):
+        with self._lock:
+            self.phase = phase
+        self.report()
+
+    def finish(self, phase: str):
+        self._stop_event.set()
+        if self._thread:
+            self._thread.join()
+        self.set_phase(phase)
+
+    def _run(self):
+        while not self._stop_event.wait(self.interval):
+            self.report()
+
+    def snapshot(self) -> dict:
+        with self._lock:
+            phase = self.phase
+        downloaded = directory_size(self.output_dir)
+        progress = {
+            "phase": phase,
+            "downloadedBytes": downloaded,
+            "updateTime": datetime.now(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ"),
+        }
+        if self.total_bytes:
+            if phase == PHASE_COMPLETED:
+                downloaded = self.total_bytes
+                progress["downloadedBytes"] = downloaded
+            # Temporary files can make the directory briefly larger than the model.
+            downloaded = min(downloaded, self.total_bytes)
+            progress["totalBytes"] = self.total_bytes
+            elapsed = time.monotonic() - self._start_time
+            rate = (downloaded - self._start_bytes) / elapsed if elapsed > 0 else 0
+            if phase == PHASE_DOWNLOADING and rate > 0:
+                progress["etaSeconds"] = int((self.total_bytes - downloaded) / rate)
+        return progress
+
+    def report(self):
+        progress = self.snapshot()
+        if "totalBytes" in progress:
+            percentage = 100 * progress["downloadedBytes"] / progress["totalBytes"]
+            logger.info(f"Download {progress['phase']}: {progress['downloadedBytes']}/{progress['totalBytes']} "
+                        f"bytes ({percentage:.1f}%), eta {progress.get('etaSeconds', 'unknown')}s")
+        else:
+            logger.info(f"Download {progress['phase']}: {progress['downloadedBytes']} bytes")
+        if self.publisher:
+            self.publisher.publish(progress)
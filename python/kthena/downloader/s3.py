@@ -13,7 +13,10 @@
 # limitations under the License.
 
 import os
+import re
 import subprocess
+import tempfile
+from typing import Optional
 
 from kthena.downloader.base import ModelDownloader, parse_bucket_from_model_url
 from kthena.downloader.logger import setup_logger
@@ -22,12 +25,16 @@ logger = setup_logger()
 
 
 class S3Downloader(ModelDownloader):
-    def __init__(self, model_uri: str, access_key: str = None, secret_key: str = None, endpoint: str = None):
+    def __init__(self, model_uri: str, access_key: str = None, secret_key: str = None, endpoint: str = None,
+                 max_concurrency: int = None, chunk_size_mb: int = None):
         super().__init__()
         self.access_key = access_key
         self.secret_key = secret_key
         self.endpoint = endpoint
         self.model_uri = model_uri
+        self.max_concurrency = max_concurrency
+        self.chunk_size_mb = chunk_size_mb
+        self._aws_config_file = None
 
     def _prepare_environment(self):
         env = os.environ.copy()
@@ -37,8 +44,46 @@ class S3Downloader(ModelDownloader):
             env['AWS_SECRET_ACCESS_KEY'] = self.secret_key
         if self.endpoint:
             env['AWS_ENDPOINT_URL'] = self.endpoint
+        if self.max_concurrency or self.chunk_size_mb:
+            env['AWS_CONFIG_FILE'] = self._write_aws_config()
         return env
 
+    def _write_aws_config(self) -> str:
+        # The AWS CLI only reads transfer settings from its config file.
+        if self._aws_config_file is None:
+            lines = ["[default]", "s3 ="]
+            if self.max_concurrency:
+                lines.append(f"  max_concurrent_requests = {int(self.max_concurrency)}")
+            if self.chunk_size_mb:
+                lines.append(f"  multipart_chunksize = {int(self.chunk_size_mb)}MB")
+                lines.append(f"  multipart_threshold = {int(self.chunk_size_mb)}MB")
+            fd, path = tempfile.mkstemp(prefix="kthena-aws-", suffix=".config")
+            with os.fdopen(fd, "w") as f:
+                f.write("\n".join(lines) + "\n")
+            self._aws_config_file = path
+        return self._aws_config_file
+
+    def _source(self) -> str:
+        bucket_name, bucket_path = parse_bucket_from_model_url(self.model_uri, "s3")
+        if bucket_path:
+            return f"s3://{bucket_name}/{bucket_path}"
+        return f"s3://{bucket_name}"
+
+    def total_bytes(self) -> Optional[int]:
+        if not self.access_key or not self.secret_key:
+            return None
+        result = subprocess.run(
+            ['aws', 's3', 'ls', self._source(), '--recursive', '--summarize'],
+            env=self._prepare_environment(),
+            capture_output=True,
+            text=True,
+            timeout=300,
+        )
+        if result.returncode != 0:
+            raise Exception(f"AWS S3 ls command failed: {result.stderr.strip()}")
+        match = re.search(r"Total Size:\s*(\d+)", result.stdout)
+        return int(match.group(1)) if match else None
+
     @staticmethod
     def _build_sync_command(source, destination):
         cmd = ['aws', 's3', 'sync', source, destination]
@@ -78,11 +123,8 @@ class S3Downloader(ModelDownloader):
             return
 
         os.makedirs(output_dir, exist_ok=True)
-        bucket_name, bucket_path = parse_bucket_from_model_url(self.model_uri, "s3")
-        if bucket_path:
-            source = f"s3://{bucket_name}/{bucket_path}"
-        else:
-            source = f"s3://{bucket_name}"
+        # sync skips objects that are already complete locally, so an interrupted download resumes.
+        source = self._source()
 
         env = self._prepare_environment()
         cmd = self._build_sync_command(source, output_dir)
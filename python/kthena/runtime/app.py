@@ -35,6 +35,7 @@ from kthena.runtime.events import get_event_publisher, EventType
 from kthena.runtime.kv_cache_manager import get_vllm_kv_cache_handler
 from kthena.runtime.redis_client import get_redis_client
 from kthena.runtime.standard import MetricStandard
+from kthena.runtime.startup import StartupTracker
 from kthena.runtime.zmq_subscriber import get_vllm_zmq_subscriber
 
 
@@ -48,6 +49,8 @@ class AppState:
         self.engine_metrics_url: Optional[str] = None
         self.pod_identifier: Optional[str] = None
         self.model_name: Optional[str] = None
+        self.startup_tracker: Optional[StartupTracker] = None
+        self.startup_task: Optional[asyncio.Task] = None
 
 
 TIMEOUT = float(os.getenv("REQUEST_TIMEOUT", "30.0"))
@@ -134,8 +137,16 @@ async def lifespan(app: FastAPI):
         except Exception as e:
             logger.warning(f"Failed to initialize vLLM ZMQ subscriber: {e}")
 
+    if state.startup_tracker:
+        state.startup_task = asyncio.create_task(
+            state.startup_tracker.run(state.client, state.engine_base_url)
+        )
+
     yield
 
+    if state.startup_task and not state.startup_task.done():
+        state.startup_task.cancel()
+
     cleanup_tasks = []
 
     if state.vllm_zmq_subscriber:
@@ -168,6 +179,26 @@ async def health_check() -> JSONResponse:
     )
 
 
+@router.get("/ready", tags=["Health"])
+async def readiness_check(request: Request) -> JSONResponse:
+    """
+    Reports the pod ready only once the engine has loaded the weights and has been warmed up,
+    so that the router does not send the first user requests to a cold engine.
+    """
+    state = get_app_state(request.app)
+    startup = state.startup_tracker.snapshot()
+    return JSONResponse(
+        content=startup,
+        status_code=200 if startup["engineWarmedUp"] else 503
+    )
+
+
+@router.get("/v1/startup", tags=["Health"])
+async def startup_progress(request: Request) -> JSONResponse:
+    state = get_app_state(request.app)
+    return JSONResponse(content=state.startup_tracker.snapshot(), status_code=200)
+
+
 @router.get("/metrics", tags=["Metrics"])
 async def get_metrics(request: Request) -> Response:
     try:
@@ -209,6 +240,7 @@ def create_application(args: argparse.Namespace) -> FastAPI:
     state.engine_metrics_url = args.engine_base_url + args.engine_metrics_path
     state.pod_identifier = args.pod
     state.model_name = args.model
+    state.startup_tracker = StartupTracker(warmup_requests=args.warmup_requests)
 
     app.include_router(router)
 
@@ -217,6 +249,7 @@ def create_application(args: argparse.Namespace) -> FastAPI:
     logger.info(f"Engine metrics URL: {args.engine_base_url + args.engine_metrics_path}")
     logger.info(f"Pod: {args.pod}")
     logger.info(f"Model: {args.model}")
+    logger.info(f"Warm-up requests: {args.warmup_requests}")
 
     return app
 
@@ -276,6 +309,13 @@ def parse_arguments() -> argparse.Namespace:
         help="Model name"
     )
 
+    parser.add_argument(
+        "-W", "--warmup-requests",
+        type=int,
+        default=1,
+        help="Number of requests warming up the engine before the pod is reported ready, 0 disables the warm-up"
+    )
+
     return parser.parse_args()
 
 
@@ -423,6 +463,26 @@ async def unload_lora_adapter(request: Request) -> JSONResponse:
         raise HTTPException(status_code=500, detail=str(e))
 
 
+@router.get("/v1/models", tags=["LoRA"])
+async def list_models(request: Request) -> JSONResponse:
+    """
+    List the models served by the engine, including the LoRA adapters loaded.
+    """
+    try:
+        state = get_app_state(request.app)
+        response = await state.client.get(f"{state.engine_base_url}/v1/models")
+        if response.status_code >= 400:
+            error_detail = f"HTTP {response.status_code}: {response.text}"
+            logger.error(f"Engine request failed: {error_detail}")
+            raise HTTPException(status_code=response.status_code, detail=error_detail)
+        return JSONResponse(content=response.json(), status_code=response.status_code)
+    except HTTPException:
+        raise
+    except Exception as e:
+        logger.error(f"Error listing models: {e}")
+        raise HTTPException(status_code=500, detail=str(e))
+
+
 @router.post("/v1/download_model", tags=["Download"])
 async def download_model_endpoint(request: Request, background_tasks: BackgroundTasks) -> JSONResponse:
     """
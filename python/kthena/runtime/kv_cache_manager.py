@@ -14,6 +14,7 @@
 
 import hashlib
 import logging
+import os
 import time
 from typing import List, Optional, Dict
 
@@ -26,8 +27,27 @@ from kthena.runtime.redis_client import RedisClient, get_redis_client
 logger = logging.getLogger(__name__)
 
 
+HASH_ALGORITHM_SHA256 = "sha256"
+HASH_ALGORITHM_XXHASH = "xxhash"
+
+# Tags of the hash schemes, embedded in the block keys. They must match the tags of the
+# kvcache-aware plugin of the router, and change whenever the hashing of a scheme changes.
+HASH_SCHEME_TAGS = {
+    HASH_ALGORITHM_SHA256: "sha256-v1",
+    HASH_ALGORITHM_XXHASH: "xxhash-v1",
+}
+
+
+def get_hash_algorithm() -> str:
+    algorithm = os.getenv("KV_CACHE_HASH_ALGORITHM", HASH_ALGORITHM_SHA256).lower()
+    if algorithm not in HASH_SCHEME_TAGS:
+        logger.warning(f"Unsupported KV_CACHE_HASH_ALGORITHM {algorithm}, fallback to {HASH_ALGORITHM_SHA256}")
+        return HASH_ALGORITHM_SHA256
+    return algorithm
+
+
 def get_matrix_key_prefix() -> str:
-    return "matrix:kv:block"
+    return f"matrix:kv:block:{HASH_SCHEME_TAGS[get_hash_algorithm()]}"
 
 
 def get_vllm_mapping_key_prefix() -> str:
@@ -38,8 +58,12 @@ def compute_standardized_hash(token_ids: List[int]) -> int:
     if not token_ids:
         return 0
     token_bytes = b''.join(token_id.to_bytes(4, byteorder='big') for token_id in token_ids)
-    hash_obj = hashlib.sha256(token_bytes)
-    full_hash = int.from_bytes(hash_obj.digest()[:8], byteorder='big')
+    if get_hash_algorithm() == HASH_ALGORITHM_XXHASH:
+        import xxhash
+        full_hash = xxhash.xxh64_intdigest(token_bytes)
+    else:
+        hash_obj = hashlib.sha256(token_bytes)
+        full_hash = int.from_bytes(hash_obj.digest()[:8], byteorder='big')
     result = full_hash & 0x7FFFFFFFFFFFFFFF
     logger.info(f"KVCacheManager: compute standardized hash={result}, token_ids={token_ids}")
     return result
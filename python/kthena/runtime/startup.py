@@ -0,0 +1,129 @@
+# Copyright The Volcano Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import asyncio
+import logging
+import threading
+from typing import Optional
+
+logger = logging.getLogger(__name__)
+
+PHASE_LOADING_WEIGHTS = "LoadingWeights"
+PHASE_WARMING_UP = "WarmingUp"
+PHASE_READY = "Ready"
+
+# Share of the startup progress taken by loading the weights, the rest is taken by the warm-up requests.
+WEIGHTS_LOADED_PERCENTAGE = 50
+
+WARMUP_PROMPT = "Hello"
+WARMUP_ATTEMPTS = 3
+
+
+class StartupTracker:
+    """
+    Tracks the startup of the engine: the weights are loaded once the engine health endpoint succeeds,
+    then the engine is warmed up by sending a few small completion requests, so that CUDA graphs are
+    compiled and caches are allocated before the pod is reported ready and registered in the router.
+    """
+
+    def __init__(self, warmup_requests: int = 1, poll_interval: float = 2.0, warmup_timeout: float = 300.0):
+        self.warmup_requests = max(warmup_requests, 0)
+        self.poll_interval = poll_interval
+        self.warmup_timeout = warmup_timeout
+        self._lock = threading.Lock()
+        self._weights_loaded = False
+        self._warmed_up_requests = 0
+        self._warmed_up = False
+
+    @property
+    def weights_loaded(self) -> bool:
+        with self._lock:
+            return self._weights_loaded
+
+    @property
+    def warmed_up(self) -> bool:
+        with self._lock:
+            return self._warmed_up
+
+    def snapshot(self) -> dict:
+        with self._lock:
+            if self._warmed_up:
+                phase, percentage = PHASE_READY, 100
+            elif self._weights_loaded:
+                phase = PHASE_WARMING_UP
+                percentage = WEIGHTS_LOADED_PERCENTAGE + (100 - WEIGHTS_LOADED_PERCENTAGE) * \
+                    self._warmed_up_requests // max(self.warmup_requests, 1)
+            else:
+                phase, percentage = PHASE_LOADING_WEIGHTS, 0
+            return {
+                "phase": phase,
+                "weightsLoaded": self._weights_loaded,
+                "engineWarmedUp": self._warmed_up,
+                "percentage": percentage,
+            }
+
+    async def run(self, client, engine_base_url: str) -> None:
+        await self._wait_weights_loaded(client, engine_base_url)
+        with self._lock:
+            self._weights_loaded = True
+        logger.info("Engine has loaded the model weights")
+
+        model = await self._served_model(client, engine_base_url) if self.warmup_requests else None
+        for _ in range(self.warmup_requests):
+            if not await self._warm_up(client, engine_base_url, model):
+                logger.warning("Engine warm-up failed, skipping the remaining warm-up requests")
+                break
+            with self._lock:
+                self._warmed_up_requests += 1
+
+        with self._lock:
+            self._warmed_up = True
+        logger.info("Engine has been warmed up")
+
+    async def _wait_weights_loaded(self, client, engine_base_url: str) -> None:
+        # The engine only serves its health endpoint once the model weights are loaded.
+        while True:
+            try:
+                response = await client.get(f"{engine_base_url}/health")
+                if response.status_code == 200:
+                    return
+            except Exception as e:
+                logger.debug(f"Engine is not up yet: {e}")
+            await asyncio.sleep(self.poll_interval)
+
+    async def _served_model(self, client, engine_base_url: str) -> Optional[str]:
+        try:
+            response = await client.get(f"{engine_base_url}/v1/models")
+            models = response.json().get("data", [])
+            if models:
+                return models[0].get("id")
+        except Exception as e:
+            logger.warning(f"Failed to get the model served by the engine: {e}")
+        return None
+
+    async def _warm_up(self, client, engine_base_url: str, model: Optional[str]) -> bool:
+        body = {"prompt": WARMUP_PROMPT, "max_tokens": 1}
+        if model:
+            body["model"] = model
+        for attempt in range(1, WARMUP_ATTEMPTS + 1):
+            try:
+                response = await client.post(f"{engine_base_url}/v1/completions", json=body,
+                                             timeout=self.warmup_timeout)
+                if response.status_code < 400:
+                    return True
+                logger.warning(f"Warm-up request failed with HTTP {response.status_code}: {response.text}")
+            except Exception as e:
+                logger.warning(f"Warm-up request failed (attempt {attempt}/{WARMUP_ATTEMPTS}): {e}")
+            await asyncio.sleep(self.poll_interval)
+        return False
@@ -0,0 +1,133 @@
+# Copyright The Volcano Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import json
+import threading
+import unittest
+import urllib.request
+from http.server import BaseHTTPRequestHandler, ThreadingHTTPServer
+from unittest.mock import patch
+
+from kthena.batch.runner import BatchRunner, Progress, serve_progress
+
+
+class FakeRouterHandler(BaseHTTPRequestHandler):
+    # number of 503 responses returned before answering each prompt
+    failures_before_success = {}
+    attempts = {}
+    lock = threading.Lock()
+
+    def do_POST(self):
+        body = json.loads(self.rfile.read(int(self.headers["Content-Length"])))
+        prompt = body["messages"][0]["content"]
+        with self.lock:
+            attempt = self.attempts.get(prompt, 0)
+            self.attempts[prompt] = attempt + 1
+
+        if prompt == "bad":
+            self._respond(400, {"error": "bad request"})
+        elif attempt < self.failures_before_success.get(prompt, 0):
+            self._respond(503, {"error": "unavailable"})
+        else:
+            self._respond(200, {"model": body["model"], "choices": [{"message": {"content": prompt.upper()}}]})
+
+    def _respond(self, status: int, body: dict):
+        data = json.dumps(body).encode()
+        self.send_response(status)
+        self.send_header("Content-Type", "application/json")
+        self.send_header("Content-Length", str(len(data)))
+        self.end_headers()
+        self.wfile.write(data)
+
+    def log_message(self, format, *args):
+        pass
+
+
+class TestBatchRunner(unittest.TestCase):
+    def setUp(self):
+        FakeRouterHandler.attempts = {}
+        FakeRouterHandler.failures_before_success = {"flaky": 1}
+        self.server = ThreadingHTTPServer(("127.0.0.1", 0), FakeRouterHandler)
+        threading.Thread(target=self.server.serve_forever, daemon=True).start()
+        self.router_url = f"http://127.0.0.1:{self.server.server_address[1]}"
+
+    def tearDown(self):
+        self.server.shutdown()
+        self.server.server_close()
+
+    @staticmethod
+    def _request(custom_id: str, prompt: str) -> dict:
+        return {
+            "custom_id": custom_id,
+            "method": "POST",
+            "url": "/v1/chat/completions",
+            "body": {"model": "original", "messages": [{"role": "user", "content": prompt}]},
+        }
+
+    @patch("kthena.batch.runner.time.sleep")
+    def test_run(self, mock_sleep):
+        runner = BatchRunner(self.router_url, "/v1/chat/completions", concurrency=2, max_retries=2,
+                             model="override")
+        results = runner.run([
+            self._request("1", "hello"),
+            self._request("2", "flaky"),
+            self._request("3", "bad"),
+        ])
+
+        self.assertEqual([r["custom_id"] for r in results], ["1", "2", "3"])
+        self.assertEqual(results[0]["response"]["status_code"], 200)
+        self.assertEqual(results[0]["response"]["body"]["model"], "override")
+        self.assertIsNone(results[0]["error"])
+
+        # 503 is retried
+        self.assertEqual(results[1]["response"]["status_code"], 200)
+        self.assertEqual(FakeRouterHandler.attempts["flaky"], 2)
+        mock_sleep.assert_called_once_with(1)
+
+        # 400 is not retried, and reported as an error
+        self.assertEqual(results[2]["response"]["status_code"], 400)
+        self.assertIsNotNone(results[2]["error"])
+        self.assertEqual(FakeRouterHandler.attempts["bad"], 1)
+
+        self.assertEqual(runner.progress.to_dict(), {"total": 3, "completed": 2, "failed": 1})
+
+    def test_stop(self):
+        runner = BatchRunner(self.router_url, "/v1/chat/completions", concurrency=1)
+        runner.stop()
+        results = runner.run([self._request("1", "hello"), self._request("2", "hello")])
+
+        # The requests are not sent once the batch is stopped
+        self.assertEqual([r["custom_id"] for r in results], ["1", "2"])
+        for result in results:
+            self.assertIsNone(result["response"])
+            self.assertEqual(result["error"]["code"], "batch_stopped")
+        self.assertEqual(FakeRouterHandler.attempts, {})
+        self.assertEqual(runner.progress.to_dict(), {"total": 2, "completed": 0, "failed": 0})
+
+    def test_serve_progress(self):
+        progress = Progress()
+        progress.set_total(2)
+        progress.record(True)
+        server = serve_progress(progress, 0)
+        try:
+            url = f"http://127.0.0.1:{server.server_address[1]}/progress"
+            with urllib.request.urlopen(url) as resp:
+                self.assertEqual(json.loads(resp.read()), {"total": 2, "completed": 1, "failed": 0})
+        finally:
+            server.shutdown()
+            server.server_close()
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,139 @@
+# Copyright The Volcano Authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+import hashlib
+import os
+import tempfile
+import unittest
+
+from kthena.downloader.base import COMPLETE_MARKER_FILE, ModelDownloader
+from kthena.downloader.checksum import (
+    GIT_SHA1_PREFIX,
+    ChecksumMismatchError,
+    git_blob_sha1_file,
+    verify_checksums,
+)
+from kthena.downloader.progress import PHASE_COMPLETED, PHASE_DOWNLOADING, ProgressReporter
+
+
+class FakeDownloader(ModelDownloader):
+    def __init__(self, files, corrupt_attempts=0):
+        super().__init__()
+        self.files = files
+        self.corrupt_attempts = corrupt_attempts
+        self.attempts = 0
+        self.retry_backoff_seconds = 0
+
+    def download(self, output_dir: str):
+        self.attempts += 1
+        for name, content in self.files.items():
+            path = os.path.join(output_dir, name)
+            if os.path.exists(path):
+                continue
+            with open(path, "wb") as f:
+                f.write(b"corrupted" if self.attempts <= self.corrupt_attempts else content)
+
+    def expected_checksums(self):
+        return {name: hashlib.sha256(content).hexdigest() for name, content in self.files.items()}
+
+    def total_bytes(self):
+        return sum(len(content) for content in self.files.values())
+
+
+class TestChecksum(unittest.TestCase):
+    def setUp(self):
+        self.tmp = tempfile.TemporaryDirectory()
+        self.output_dir = self.tmp.name
+
+    def tearDown(self):
+        self.tmp.cleanup()
+
+    def write(self, name, content):
+        path = os.path.join(self.output_dir, name)
+        with open(path, "wb") as f:
+            f.write(content)
+        return path
+
+    def test_verify_checksums_success(self):
+        self.write("model.safetensors", b"weights")
+        verify_checksums(self.output_dir, {
+            "model.safetensors": "sha256:" + hashlib.sha256(b"weights").hexdigest().upper(),
+        })
+
+    def test_verify_checksums_removes_mismatched_files(self):
+        path = self.write("model.safetensors", b"weights")
+        with self.assertRaises(ChecksumMismatchError) as context:
+            verify_checksums(self.output_dir, {
+                "model.safetensors": hashlib.sha256(b"other").hexdigest(),
+                "missing.json": hashlib.sha256(b"").hexdigest(),
+            })
+        self.assertEqual(sorted(context.exception.files), ["missing.json", "model.safetensors"])
+        self.assertFalse(os.path.exists(path))
+
+    def test_git_blob_sha1(self):
+        path = self.write("config.json", b"hello\n")
+        # Same as `git hash-object config.json`.
+        self.assertEqual(git_blob_sha1_file(path), "ce013625030ba8dba906f756967f9e9ca394464a")
+        verify_checksums(self.output_dir, {"config.json": GIT_SHA1_PREFIX + "ce013625030ba8dba906f756967f9e9ca394464a"})
+
+    def test_download_model_retries_after_mismatch(self):
+        downloader = FakeDownloader({"a.bin": b"aaaa", "b.bin": b"bb"}, corrupt_attempts=1)
+        downloader.download_model(self.output_dir)
+        self.assertEqual(downloader.attempts, 2)
+        self.assertTrue(os.path.exists(os.path.join(self.output_dir, COMPLETE_MARKER_FILE)))
+        with open(os.path.join(self.output_dir, "a.bin"), "rb") as f:
+            self.assertEqual(f.read(), b"aaaa")
+
+    def test_download_model_gives_up_after_max_retries(self):
+        downloader = FakeDownloader({"a.bin": b"aaaa"}, corrupt_attempts=10)
+        downloader.max_retries = 2
+        with self.assertRaises(ChecksumMismatchError):
+            downloader.download_model(self.output_dir)
+        self.assertEqual(downloader.attempts, 3)
+        self.assertFalse(os.path.exists(os.path.join(self.output_dir, COMPLETE_MARKER_FILE)))
+
+    def test_user_checksums_take_precedence(self):
+        downloader = FakeDownloader({"a.bin": b"aaaa"})
+        downloader.max_retries = 0
+        downloader.checksums = {"a.bin": hashlib.sha256(b"bbbb").hexdigest()}
+        with self.assertRaises(ChecksumMismatchError):
+            downloader.download_model(self.output_dir)
+
+
+class TestProgressReporter(unittest.TestCase):
+    def test_snapshot(self):
+        with tempfile.TemporaryDirectory() as output_dir:
+            with open(os.path.join(output_dir, "a.bin"), "wb") as f:
+                f.write(b"x" * 25)
+            reporter = ProgressReporter(output_dir)
+            reporter.total_bytes = 100
+
+            progress = reporter.snapshot()
+            self.assertEqual(progress["phase"], PHASE_DOWNLOADING)
+            self.assertEqual(progress["downloadedBytes"], 25)
+            self.assertEqual(progress["totalBytes"], 100)
+            # Bytes present at start are not counted towards the rate, so there is no estimate yet.
+            self.assertNotIn("etaSeconds", progress)
+
+            with open(os.path.join(output_dir, "b.bin"), "wb") as f:
+                f.write(b"x" * 25)
+            self.assertIn("etaSeconds", reporter.snapshot())
+
+            reporter.phase = PHASE_COMPLETED
+            self.assertEqual(reporter.snapshot()["downloadedBytes"], 100)
+
+
+if __name__ == "__main__":
+    unittest.main()
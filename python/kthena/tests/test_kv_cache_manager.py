@@ -0,0 +1,48 @@
+# Copyright The Volcano Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+import os
+import unittest
+from unittest.mock import patch
+
+from kthena.runtime.kv_cache_manager import VLLMKVCacheRedisManager, compute_standardized_hash
+
+
+class TestBlockHashScheme(unittest.TestCase):
+    # Hashes of the token block [1, 2, 3, 4] computed by the kvcache-aware plugin of the router
+    ROUTER_HASHES = {
+        "sha256": 4233425515844289900,
+        "xxhash": 4216935045214310298,
+    }
+
+    def test_hash_matches_router(self):
+        for algorithm, expected in self.ROUTER_HASHES.items():
+            with self.subTest(algorithm=algorithm), patch.dict(os.environ, {"KV_CACHE_HASH_ALGORITHM": algorithm}):
+                self.assertEqual(compute_standardized_hash([1, 2, 3, 4]), expected)
+
+    def test_block_key_embeds_scheme(self):
+        with patch.dict(os.environ, {}, clear=True):
+            self.assertEqual(VLLMKVCacheRedisManager._get_matrix_block_key("qwen", 42),
+                             "matrix:kv:block:sha256-v1:qwen@42")
+        with patch.dict(os.environ, {"KV_CACHE_HASH_ALGORITHM": "xxhash"}):
+            self.assertEqual(VLLMKVCacheRedisManager._get_matrix_block_key("qwen", 42),
+                             "matrix:kv:block:xxhash-v1:qwen@42")
+        with patch.dict(os.environ, {"KV_CACHE_HASH_ALGORITHM": "md5"}):
+            self.assertEqual(VLLMKVCacheRedisManager._get_matrix_block_key("qwen", 42),
+                             "matrix:kv:block:sha256-v1:qwen@42")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -74,6 +74,7 @@ def _make_args():
         engine_metrics_path="/metrics",
         pod="pod-1.ns",
         model="test-model",
+        warmup_requests=0,
     )
 
 
@@ -0,0 +1,109 @@
+# Copyright The Volcano Authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+import asyncio
+import unittest
+
+from kthena.runtime.startup import (
+    PHASE_LOADING_WEIGHTS,
+    PHASE_READY,
+    PHASE_WARMING_UP,
+    StartupTracker,
+)
+
+
+class _Response:
+    def __init__(self, status_code: int = 200, payload: dict | None = None):
+        self.status_code = status_code
+        self._payload = payload or {}
+        self.text = ""
+
+    def json(self):
+        return self._payload
+
+
+class _EngineClient:
+    def __init__(self, unhealthy_polls: int = 0, completion_status: int = 200):
+        self.unhealthy_polls = unhealthy_polls
+        self.completion_status = completion_status
+        self.completions = []
+
+    async def get(self, url: str):
+        if url.endswith("/health"):
+            if self.unhealthy_polls > 0:
+                self.unhealthy_polls -= 1
+                raise ConnectionError("connection refused")
+            return _Response()
+        if url.endswith("/v1/models"):
+            return _Response(payload={"data": [{"id": "served-model"}]})
+        return _Response(status_code=404)
+
+    async def post(self, url: str, json: dict | None = None, timeout: float | None = None):
+        self.completions.append((url, json))
+        return _Response(status_code=self.completion_status)
+
+
+class TestStartupTracker(unittest.TestCase):
+    def test_snapshot_phases(self):
+        tracker = StartupTracker(warmup_requests=2)
+        self.assertEqual(tracker.snapshot(), {
+            "phase": PHASE_LOADING_WEIGHTS, "weightsLoaded": False, "engineWarmedUp": False, "percentage": 0,
+        })
+
+        tracker._weights_loaded = True
+        tracker._warmed_up_requests = 1
+        snapshot = tracker.snapshot()
+        self.assertEqual(snapshot["phase"], PHASE_WARMING_UP)
+        self.assertEqual(snapshot["percentage"], 75)
+
+        tracker._warmed_up = True
+        self.assertEqual(tracker.snapshot()["percentage"], 100)
+
+    def test_run_waits_for_weights_and_warms_up(self):
+        client = _EngineClient(unhealthy_polls=2)
+        tracker = StartupTracker(warmup_requests=2, poll_interval=0)
+        asyncio.run(tracker.run(client, "http://engine:8000"))
+
+        self.assertEqual(client.unhealthy_polls, 0)
+        self.assertEqual(len(client.completions), 2)
+        url, body = client.completions[0]
+        self.assertEqual(url, "http://engine:8000/v1/completions")
+        self.assertEqual(body["model"], "served-model")
+        self.assertEqual(body["max_tokens"], 1)
+        snapshot = tracker.snapshot()
+        self.assertEqual(snapshot["phase"], PHASE_READY)
+        self.assertTrue(snapshot["weightsLoaded"])
+        self.assertTrue(snapshot["engineWarmedUp"])
+
+    def test_run_without_warmup(self):
+        client = _EngineClient()
+        tracker = StartupTracker(warmup_requests=0, poll_interval=0)
+        asyncio.run(tracker.run(client, "http://engine:8000"))
+
+        self.assertEqual(client.completions, [])
+        self.assertEqual(tracker.snapshot()["percentage"], 100)
+
+    def test_failed_warmup_does_not_block_readiness(self):
+        client = _EngineClient(completion_status=500)
+        tracker = StartupTracker(warmup_requests=3, poll_interval=0)
+        asyncio.run(tracker.run(client, "http://engine:8000"))
+
+        # the first warm-up request is retried, the remaining ones are skipped
+        self.assertEqual(len(client.completions), 3)
+        self.assertTrue(tracker.warmed_up)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,144 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// BenchmarkScenarios runs the scenarios of the suite as Go benchmarks, e.g.
+// go test ./test/benchmark -run=^$ -bench=Scenarios/schedule
+func BenchmarkScenarios(b *testing.B) {
+	for _, scenario := range Scenarios() {
+		b.Run(scenario.Name, scenario.Run)
+	}
+}
+
+func TestNewCluster(t *testing.T) {
+	cluster, err := NewCluster(ClusterConfig{ModelServers: 3, PodsPerModelServer: 20, Seed: 1})
+	require.NoError(t, err)
+	require.Len(t, cluster.ModelServers, 3)
+	assert.Equal(t, []string{"model-0", "model-1", "model-2"}, cluster.Models)
+	assert.Len(t, cluster.Store.GetAllPods(), 60)
+
+	pods, err := cluster.Store.GetPodsByModelServer(cluster.ModelServers[1])
+	require.NoError(t, err)
+	require.Len(t, pods, 20)
+	busy := 0
+	for _, pod := range pods {
+		assert.GreaterOrEqual(t, pod.GPUCacheUsage, 0.0)
+		assert.Less(t, pod.GPUCacheUsage, 1.0)
+		assert.Greater(t, pod.TTFT, 0.0)
+		if pod.RequestRunningNum > 0 {
+			busy++
+		}
+	}
+	assert.Greater(t, busy, 0)
+
+	name, _, _, err := cluster.Store.MatchModelServer("model-2", httptest.NewRequest("POST", "/v1/chat/completions", nil))
+	require.NoError(t, err)
+	assert.Equal(t, cluster.ModelServers[2], name)
+}
+
+func TestPromptGenerator(t *testing.T) {
+	config := DefaultPromptConfig
+	generator := NewPromptGenerator(config)
+	var lengths []int
+	shared := 0
+	for i := 0; i < 2000; i++ {
+		prompt := generator.Next()
+		require.NotEmpty(t, prompt.Messages)
+		user := prompt.Messages[len(prompt.Messages)-1]
+		assert.Equal(t, "user", user.Role)
+		assert.LessOrEqual(t, len(user.Content), (config.MaxTokens+5)*charsPerToken)
+		lengths = append(lengths, len(user.Content))
+		if len(prompt.Messages) == 2 {
+			assert.Equal(t, "system", prompt.Messages[0].Role)
+			shared++
+		}
+	}
+	sort.Ints(lengths)
+	median := lengths[len(lengths)/2] / charsPerToken
+	assert.InDelta(t, config.MedianTokens, median, float64(config.MedianTokens)*0.15)
+	// The distribution has a long tail
+	assert.Greater(t, lengths[len(lengths)*99/100]/charsPerToken, 5*config.MedianTokens)
+	assert.InDelta(t, config.SharedPrefixRatio, float64(shared)/2000, 0.05)
+
+	// The prompts are reproducible
+	assert.Equal(t, NewPromptGenerator(config).Next(), NewPromptGenerator(config).Next())
+}
+
+func TestRunLoad(t *testing.T) {
+	result, err := RunLoad(context.Background(), LoadConfig{
+		Cluster:     ClusterConfig{ModelServers: 2, PodsPerModelServer: 10, Seed: 1},
+		Prompts:     DefaultPromptConfig,
+		Concurrency: 4,
+		Duration:    200 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.Greater(t, result.Requests, 0)
+	assert.Zero(t, result.Errors)
+	assert.LessOrEqual(t, result.P50, result.P90)
+	assert.LessOrEqual(t, result.P90, result.P99)
+	assert.LessOrEqual(t, result.P99, result.Max)
+	assert.Greater(t, result.OpsPerSecond(), 0.0)
+	assert.Equal(t, result.P99.Nanoseconds(), result.Result().P99Ns)
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &Report{Results: map[string]Result{
+		"schedule/pods-1000": {NsPerOp: 1000, AllocsPerOp: 100},
+		"score/random":       {NsPerOp: 100, AllocsPerOp: 2},
+		"load/schedule":      {NsPerOp: 1000, P99Ns: 5000},
+		"removed":            {NsPerOp: 100},
+	}}
+	current := &Report{Results: map[string]Result{
+		"schedule/pods-1000": {NsPerOp: 1250, AllocsPerOp: 120},
+		"score/random":       {NsPerOp: 200, AllocsPerOp: 3},
+		"load/schedule":      {NsPerOp: 900, P99Ns: 9000},
+		"added":              {NsPerOp: 1e9},
+	}}
+
+	regressions := Compare(baseline, current, DefaultThresholds)
+	require.Len(t, regressions, 3)
+	assert.Equal(t, Regression{Scenario: "load/schedule", Metric: "p99 ns", Baseline: 5000, Current: 9000}, regressions[0])
+	assert.Equal(t, Regression{Scenario: "schedule/pods-1000", Metric: "allocs/op", Baseline: 100, Current: 120}, regressions[1])
+	assert.Equal(t, Regression{Scenario: "score/random", Metric: "ns/op", Baseline: 100, Current: 200}, regressions[2])
+	assert.Equal(t, "score/random: ns/op went from 100 to 200 (+100.0%)", regressions[2].String())
+
+	assert.Empty(t, Compare(baseline, baseline, DefaultThresholds))
+}
+
+func TestReportRoundTrip(t *testing.T) {
+	report := NewReport()
+	report.Results["score/random"] = ResultOf(testing.BenchmarkResult{N: 10, T: time.Millisecond, MemAllocs: 20, MemBytes: 100})
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, report.Write(path))
+
+	read, err := ReadReport(path)
+	require.NoError(t, err)
+	assert.Equal(t, report, read)
+	assert.Equal(t, Result{NsPerOp: 100000, BytesPerOp: 10, AllocsPerOp: 2}, read.Results["score/random"])
+}
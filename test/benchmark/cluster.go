@@ -0,0 +1,125 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package benchmark measures the hot paths of the router, the scheduling of a request, the scoring of its plugins,
+// the reads of the datastore and the proxying of a request, on a simulated cluster of thousands of pods. The results
+// are written as a JSON report, compared with a baseline by CI to catch the regressions of the routing latency.
+package benchmark
+
+import (
+	"fmt"
+	"math/rand"
+
+	"istio.io/istio/pkg/util/sets"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	aiv1alpha1 "github.com/volcano-sh/kthena/pkg/apis/networking/v1alpha1"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/datastore"
+)
+
+const namespace = "benchmark"
+
+// ClusterConfig is the size of a simulated cluster.
+type ClusterConfig struct {
+	ModelServers       int
+	PodsPerModelServer int
+	// PodIP is the address of all the pods, the model servers are never reached unless it is set, e.g. to the
+	// address of a fake engine.
+	PodIP string
+	// Port is the workload port of the model servers.
+	Port int32
+	Seed int64
+}
+
+// Cluster is a datastore filled with model servers, one ModelRoute each, and pods with random engine metrics.
+type Cluster struct {
+	Store        datastore.Store
+	ModelServers []types.NamespacedName
+	// Models are the models of the ModelRoutes, in the order of the model servers.
+	Models []string
+}
+
+// NewCluster fills a datastore with the simulated cluster.
+func NewCluster(config ClusterConfig) (*Cluster, error) {
+	rnd := rand.New(rand.NewSource(config.Seed))
+	cluster := &Cluster{Store: datastore.New()}
+	podIP := config.PodIP
+	for i := 0; i < config.ModelServers; i++ {
+		model := fmt.Sprintf("model-%d", i)
+		modelServer := &aiv1alpha1.ModelServer{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: model},
+			Spec: aiv1alpha1.ModelServerSpec{
+				Model:           &model,
+				InferenceEngine: aiv1alpha1.VLLM,
+				WorkloadPort:    aiv1alpha1.WorkloadPort{Port: config.Port},
+			},
+		}
+		pods := make([]*corev1.Pod, 0, config.PodsPerModelServer)
+		podNames := sets.New[types.NamespacedName]()
+		for j := 0; j < config.PodsPerModelServer; j++ {
+			ip := podIP
+			if ip == "" {
+				// The loopback addresses refuse the scrapes of the datastore right away
+				ip = fmt.Sprintf("127.0.%d.%d", (j/250)%250, j%250+1)
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: fmt.Sprintf("%s-%d", model, j)},
+				Status:     corev1.PodStatus{PodIP: ip, Phase: corev1.PodRunning},
+			}
+			pods = append(pods, pod)
+			podNames.Insert(types.NamespacedName{Namespace: namespace, Name: pod.Name})
+		}
+		if err := cluster.Store.AddOrUpdateModelServer(modelServer, podNames); err != nil {
+			return nil, err
+		}
+		for _, pod := range pods {
+			if err := cluster.Store.AddOrUpdatePod(pod, []*aiv1alpha1.ModelServer{modelServer}); err != nil {
+				return nil, err
+			}
+			setMetrics(cluster.Store.GetPodInfo(types.NamespacedName{Namespace: namespace, Name: pod.Name}), rnd)
+		}
+		route := &aiv1alpha1.ModelRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: model},
+			Spec: aiv1alpha1.ModelRouteSpec{
+				ModelName: model,
+				Rules:     []*aiv1alpha1.Rule{{TargetModels: []*aiv1alpha1.TargetModel{{ModelServerName: model}}}},
+			},
+		}
+		if err := cluster.Store.AddOrUpdateModelRoute(route); err != nil {
+			return nil, err
+		}
+		cluster.ModelServers = append(cluster.ModelServers, types.NamespacedName{Namespace: namespace, Name: model})
+		cluster.Models = append(cluster.Models, model)
+	}
+	return cluster, nil
+}
+
+// setMetrics sets the engine metrics of a pod of a busy cluster: most pods have a few running requests and no
+// waiting one, a few of them are saturated.
+func setMetrics(pod *datastore.PodInfo, rnd *rand.Rand) {
+	if pod == nil {
+		return
+	}
+	pod.GPUCacheUsage = rnd.Float64()
+	pod.RequestRunningNum = float64(rnd.Intn(32))
+	if rnd.Float64() < 0.1 {
+		pod.RequestWaitingNum = float64(rnd.Intn(20))
+	}
+	pod.TTFT = 0.05 + rnd.ExpFloat64()*0.2
+	pod.TPOT = 0.01 + rnd.ExpFloat64()*0.02
+}
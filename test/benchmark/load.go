@@ -0,0 +1,144 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+)
+
+// LoadConfig is a load run: the requests of Concurrency clients are scheduled as fast as possible for Duration.
+type LoadConfig struct {
+	Cluster     ClusterConfig
+	Prompts     PromptConfig
+	Concurrency int
+	Duration    time.Duration
+}
+
+// LoadResult is the outcome of a load run.
+type LoadResult struct {
+	Requests int
+	Errors   int
+	Elapsed  time.Duration
+	Mean     time.Duration
+	P50      time.Duration
+	P90      time.Duration
+	P99      time.Duration
+	Max      time.Duration
+}
+
+// OpsPerSecond is the throughput of the scheduling.
+func (r *LoadResult) OpsPerSecond() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Elapsed.Seconds()
+}
+
+// Result converts the load result into a result of a report.
+func (r *LoadResult) Result() Result {
+	return Result{
+		NsPerOp:      float64(r.Mean.Nanoseconds()),
+		P50Ns:        r.P50.Nanoseconds(),
+		P99Ns:        r.P99.Nanoseconds(),
+		OpsPerSecond: r.OpsPerSecond(),
+	}
+}
+
+// RunLoad schedules the requests of the clients on the simulated cluster with the default plugins. Each request
+// is for a random model server, and is scheduled as the router does: its pods are read from the datastore,
+// filtered, scored, and the post hooks of the picked pod are run. The plugins are those of the router by default.
+func RunLoad(ctx context.Context, config LoadConfig) (*LoadResult, error) {
+	cluster, err := NewCluster(config.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	sched, err := newScheduler(cluster)
+	if err != nil {
+		return nil, err
+	}
+	concurrency := max(config.Concurrency, 1)
+	ctx, cancel := context.WithTimeout(ctx, config.Duration)
+	defer cancel()
+
+	latencies := make([][]time.Duration, concurrency)
+	failures := make([]int, concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			prompts := config.Prompts
+			prompts.Seed += int64(worker)
+			generator := NewPromptGenerator(prompts)
+			rnd := rand.New(rand.NewSource(config.Cluster.Seed + int64(worker)))
+			for ctx.Err() == nil {
+				server := rnd.Intn(len(cluster.ModelServers))
+				schedCtx := &framework.Context{
+					Model:           cluster.Models[server],
+					Prompt:          generator.Next(),
+					ModelServerName: cluster.ModelServers[server],
+				}
+				begin := time.Now()
+				pods, err := cluster.Store.GetPodsByModelServer(schedCtx.ModelServerName)
+				if err == nil {
+					err = sched.Schedule(schedCtx, pods)
+				}
+				if err != nil {
+					failures[worker]++
+					continue
+				}
+				sched.RunPostHooks(schedCtx, 0)
+				latencies[worker] = append(latencies[worker], time.Since(begin))
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	result := &LoadResult{Elapsed: time.Since(start)}
+	var all []time.Duration
+	for worker := range latencies {
+		all = append(all, latencies[worker]...)
+		result.Errors += failures[worker]
+	}
+	result.Requests = len(all)
+	if len(all) == 0 {
+		return result, nil
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	var total time.Duration
+	for _, latency := range all {
+		total += latency
+	}
+	result.Mean = total / time.Duration(len(all))
+	result.P50 = percentile(all, 0.5)
+	result.P90 = percentile(all, 0.9)
+	result.P99 = percentile(all, 0.99)
+	result.Max = all[len(all)-1]
+	return result, nil
+}
+
+// percentile returns the percentile of the sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	return sorted[min(int(float64(len(sorted))*p), len(sorted)-1)]
+}
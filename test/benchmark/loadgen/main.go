@@ -0,0 +1,116 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// loadgen runs the benchmark suite of the router and a load run of its scheduling on a simulated cluster, writes
+// the results as a JSON report, and fails when they regressed from a baseline report.
+//
+//	go run ./test/benchmark/loadgen --output report.json --baseline test/benchmark/baseline.json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/volcano-sh/kthena/test/benchmark"
+)
+
+// loadScenario is the name of the load run in the report.
+const loadScenario = "load/schedule"
+
+func main() {
+	// Registers the -test.benchtime flag, the time each scenario runs for
+	testing.Init()
+	var (
+		bench         = flag.String("bench", ".", "Regular expression selecting the scenarios to run, empty to skip them")
+		modelServers  = flag.Int("model-servers", 10, "Number of model servers of the load run, 0 to skip it")
+		pods          = flag.Int("pods", 500, "Number of pods of each model server of the load run")
+		concurrency   = flag.Int("concurrency", 16, "Number of concurrent clients of the load run")
+		duration      = flag.Duration("duration", 10*time.Second, "Duration of the load run")
+		output        = flag.String("output", "-", "File the JSON report is written to, - for the standard output")
+		baseline      = flag.String("baseline", "", "Report the results are compared with, the command fails when they regressed")
+		timeThreshold = flag.Float64("time-threshold", benchmark.DefaultThresholds.Time, "Relative increase of the time per operation and of the p99 latency which is not a regression")
+		allocsThresh  = flag.Float64("allocs-threshold", benchmark.DefaultThresholds.Allocs, "Relative increase of the allocations per operation which is not a regression")
+	)
+	klog.InitFlags(nil)
+	flag.Parse()
+	// The scenarios would be dominated by the logs of the router
+	_ = flag.Set("v", "0")
+	_ = flag.Set("logtostderr", "false")
+	_ = flag.Set("alsologtostderr", "false")
+	_ = flag.Set("stderrthreshold", "FATAL")
+
+	report := benchmark.NewReport()
+	if *bench != "" {
+		filter, err := regexp.Compile(*bench)
+		if err != nil {
+			fail("invalid --bench: %v", err)
+		}
+		for _, scenario := range benchmark.Scenarios() {
+			if !filter.MatchString(scenario.Name) {
+				continue
+			}
+			result := testing.Benchmark(scenario.Run)
+			report.Results[scenario.Name] = benchmark.ResultOf(result)
+			fmt.Fprintf(os.Stderr, "%-40s %s %s\n", scenario.Name, result.String(), result.MemString())
+		}
+	}
+	if *modelServers > 0 {
+		load, err := benchmark.RunLoad(context.Background(), benchmark.LoadConfig{
+			Cluster:     benchmark.ClusterConfig{ModelServers: *modelServers, PodsPerModelServer: *pods, Seed: 1},
+			Prompts:     benchmark.DefaultPromptConfig,
+			Concurrency: *concurrency,
+			Duration:    *duration,
+		})
+		if err != nil {
+			fail("load run failed: %v", err)
+		}
+		report.Results[loadScenario] = load.Result()
+		fmt.Fprintf(os.Stderr, "%-40s %d requests, %d errors, %.0f/s, mean %v, p50 %v, p90 %v, p99 %v, max %v\n",
+			loadScenario, load.Requests, load.Errors, load.OpsPerSecond(), load.Mean, load.P50, load.P90, load.P99, load.Max)
+	}
+	if err := report.Write(*output); err != nil {
+		fail("failed to write the report: %v", err)
+	}
+
+	if *baseline == "" {
+		return
+	}
+	base, err := benchmark.ReadReport(*baseline)
+	if err != nil {
+		fail("failed to read the baseline: %v", err)
+	}
+	regressions := benchmark.Compare(base, report, benchmark.Thresholds{Time: *timeThreshold, Allocs: *allocsThresh})
+	if len(regressions) == 0 {
+		fmt.Fprintln(os.Stderr, "No regression from the baseline")
+		return
+	}
+	for _, regression := range regressions {
+		fmt.Fprintln(os.Stderr, "REGRESSION", regression)
+	}
+	os.Exit(1)
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(2)
+}
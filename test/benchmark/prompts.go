@@ -0,0 +1,102 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+)
+
+// PromptConfig is the distribution of the simulated prompts. The lengths are in tokens, of about 4 characters.
+type PromptConfig struct {
+	// MedianTokens and Sigma are the parameters of the log-normal distribution of the lengths of the user messages.
+	MedianTokens int
+	Sigma        float64
+	MaxTokens    int
+	// SystemPrompts is the number of distinct system prompts, SharedPrefixRatio the ratio of the prompts
+	// starting with one of them, which the prefix cache can match.
+	SystemPrompts     int
+	SystemTokens      int
+	SharedPrefixRatio float64
+	Seed              int64
+}
+
+// DefaultPromptConfig is the distribution of the prompts of a chat service: short user messages with a long tail,
+// half of them after one of a few long system prompts.
+var DefaultPromptConfig = PromptConfig{
+	MedianTokens:      300,
+	Sigma:             1.0,
+	MaxTokens:         16000,
+	SystemPrompts:     16,
+	SystemTokens:      1000,
+	SharedPrefixRatio: 0.5,
+	Seed:              1,
+}
+
+const charsPerToken = 4
+
+var words = strings.Fields(`the router schedules each request on the pod of the model server with the best score,
+which accounts for the waiting requests, the latency of the engine, the usage of its cache and the prefix of
+the prompt already processed by it`)
+
+// PromptGenerator generates the prompts of a PromptConfig. It is not safe for concurrent use.
+type PromptGenerator struct {
+	config        PromptConfig
+	rnd           *rand.Rand
+	systemPrompts []string
+}
+
+// NewPromptGenerator creates a generator of the prompts.
+func NewPromptGenerator(config PromptConfig) *PromptGenerator {
+	g := &PromptGenerator{config: config, rnd: rand.New(rand.NewSource(config.Seed))}
+	for i := 0; i < config.SystemPrompts; i++ {
+		g.systemPrompts = append(g.systemPrompts, g.text(config.SystemTokens))
+	}
+	return g
+}
+
+// Next returns the next prompt, a user message after a system prompt or not.
+func (g *PromptGenerator) Next() common.ChatMessage {
+	var prompt common.ChatMessage
+	if len(g.systemPrompts) > 0 && g.rnd.Float64() < g.config.SharedPrefixRatio {
+		prompt.Messages = append(prompt.Messages, common.Message{Role: "system", Content: g.systemPrompts[g.rnd.Intn(len(g.systemPrompts))]})
+	}
+	prompt.Messages = append(prompt.Messages, common.Message{Role: "user", Content: g.text(g.length())})
+	return prompt
+}
+
+// length draws the number of tokens of a user message.
+func (g *PromptGenerator) length() int {
+	tokens := int(float64(g.config.MedianTokens) * math.Exp(g.config.Sigma*g.rnd.NormFloat64()))
+	return max(1, min(tokens, g.config.MaxTokens))
+}
+
+// text returns random words of about the number of tokens.
+func (g *PromptGenerator) text(tokens int) string {
+	var b strings.Builder
+	b.Grow(tokens * charsPerToken)
+	for b.Len() < tokens*charsPerToken {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(words[g.rnd.Intn(len(words))])
+	}
+	return b.String()
+}
@@ -0,0 +1,145 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"testing"
+)
+
+// Result is the result of a scenario, or of a load run, then NsPerOp is the mean latency.
+type Result struct {
+	NsPerOp     float64 `json:"nsPerOp"`
+	BytesPerOp  int64   `json:"bytesPerOp"`
+	AllocsPerOp int64   `json:"allocsPerOp"`
+	// The latency percentiles and the throughput are only measured by the load runs.
+	P50Ns        int64   `json:"p50Ns,omitempty"`
+	P99Ns        int64   `json:"p99Ns,omitempty"`
+	OpsPerSecond float64 `json:"opsPerSecond,omitempty"`
+}
+
+// ResultOf converts the result of testing.Benchmark.
+func ResultOf(r testing.BenchmarkResult) Result {
+	result := Result{BytesPerOp: r.AllocedBytesPerOp(), AllocsPerOp: r.AllocsPerOp()}
+	if r.N > 0 {
+		result.NsPerOp = float64(r.T.Nanoseconds()) / float64(r.N)
+	}
+	return result
+}
+
+// Report is the results of a run of the suite, with the platform they were measured on.
+type Report struct {
+	GoVersion string            `json:"goVersion"`
+	GOOS      string            `json:"goos"`
+	GOARCH    string            `json:"goarch"`
+	CPUs      int               `json:"cpus"`
+	Results   map[string]Result `json:"results"`
+}
+
+// NewReport creates an empty report of the current platform.
+func NewReport() *Report {
+	return &Report{
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		CPUs:      runtime.GOMAXPROCS(0),
+		Results:   map[string]Result{},
+	}
+}
+
+// ReadReport reads a report written by Write.
+func ReadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("invalid report %s: %v", path, err)
+	}
+	return report, nil
+}
+
+// Write writes the report as indented JSON, "-" writes it to the standard output.
+func (r *Report) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Thresholds are the relative increases of a metric over its baseline which are not regressions, e.g. 0.2 for 20%.
+type Thresholds struct {
+	// Time applies to the time per operation and to the 99th percentile of the latency.
+	Time float64
+	// Allocs applies to the allocations per operation.
+	Allocs float64
+}
+
+// DefaultThresholds leave room for the noise of shared CI runners. The allocations are deterministic, so their
+// threshold is tighter.
+var DefaultThresholds = Thresholds{Time: 0.3, Allocs: 0.1}
+
+// Regression is a metric of a scenario beyond its threshold.
+type Regression struct {
+	Scenario string
+	Metric   string
+	Baseline float64
+	Current  float64
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("%s: %s went from %.0f to %.0f (+%.1f%%)", r.Scenario, r.Metric, r.Baseline, r.Current,
+		(r.Current/r.Baseline-1)*100)
+}
+
+// Compare returns the regressions of the current report. The scenarios missing from either report are skipped.
+func Compare(baseline, current *Report, thresholds Thresholds) []Regression {
+	var regressions []Regression
+	check := func(scenario, metric string, base, cur, threshold, slack float64) {
+		if base > 0 && cur > base*(1+threshold) && cur-base > slack {
+			regressions = append(regressions, Regression{Scenario: scenario, Metric: metric, Baseline: base, Current: cur})
+		}
+	}
+	names := make([]string, 0, len(current.Results))
+	for name := range current.Results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		base, ok := baseline.Results[name]
+		if !ok {
+			continue
+		}
+		cur := current.Results[name]
+		check(name, "ns/op", base.NsPerOp, cur.NsPerOp, thresholds.Time, 0)
+		check(name, "p99 ns", float64(base.P99Ns), float64(cur.P99Ns), thresholds.Time, 0)
+		// A single allocation more is tolerated whatever the threshold, e.g. from 2 to 3 allocations
+		check(name, "allocs/op", float64(base.AllocsPerOp), float64(cur.AllocsPerOp), thresholds.Allocs, 1)
+	}
+	return regressions
+}
@@ -0,0 +1,291 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/volcano-sh/kthena/pkg/kthena-router/common"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/router"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/framework"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins"
+	"github.com/volcano-sh/kthena/pkg/kthena-router/scheduler/plugins/conf"
+)
+
+// routerConfiguration is the configuration of the router and of the schedulers of the benchmarks.
+//
+//go:embed routerConfiguration.yaml
+var routerConfiguration []byte
+
+// promptPoolSize is the number of prompts generated ahead of a scenario, which its iterations go through.
+const promptPoolSize = 1024
+
+// Scenario is a Go benchmark of a hot path of the router.
+type Scenario struct {
+	Name string
+	Run  func(b *testing.B)
+}
+
+// Scenarios returns the scenarios of the suite, in the order they are run.
+func Scenarios() []Scenario {
+	scenarios := []Scenario{}
+	for _, pods := range []int{100, 1000, 5000} {
+		scenarios = append(scenarios, Scenario{
+			Name: fmt.Sprintf("schedule/pods-%d", pods),
+			Run:  scheduleScenario(ClusterConfig{ModelServers: 1, PodsPerModelServer: pods}),
+		})
+	}
+	for _, plugin := range []framework.ScorePlugin{
+		plugins.NewLeastRequest(runtime.RawExtension{Raw: []byte(`{"maxWaitingRequests": 10}`)}),
+		plugins.NewLeastLatency(runtime.RawExtension{Raw: []byte(`{"TTFTTPOTWeightFactor": 0.5}`)}),
+		plugins.NewGPUCacheUsage(),
+		plugins.NewRandom(runtime.RawExtension{}),
+		plugins.NewPredictedLatency(runtime.RawExtension{}),
+	} {
+		scenarios = append(scenarios, Scenario{
+			Name: "score/" + plugin.Name(),
+			Run:  scoreScenario(func(*Cluster) framework.ScorePlugin { return plugin }),
+		})
+	}
+	scenarios = append(scenarios,
+		Scenario{
+			Name: "score/" + plugins.PrefixCachePluginName,
+			Run: scoreScenario(func(cluster *Cluster) framework.ScorePlugin {
+				return plugins.NewPrefixCache(cluster.Store, runtime.RawExtension{Raw: []byte(`{"blockSizeToHash": 64, "maxBlocksToMatch": 128, "maxHashCacheSize": 50000}`)})
+			}),
+		},
+		Scenario{Name: "datastore/get-pods-by-model-server", Run: getPodsScenario},
+		Scenario{Name: "datastore/get-all-pods", Run: getAllPodsScenario},
+		Scenario{Name: "datastore/match-model-server", Run: matchModelServerScenario},
+		Scenario{Name: "proxy/chat-completions", Run: proxyScenario},
+	)
+	return scenarios
+}
+
+// clusters caches the clusters of the scenarios, which testing.Benchmark runs several times.
+var clusters sync.Map // map[ClusterConfig]*Cluster
+
+func clusterOf(b *testing.B, config ClusterConfig) *Cluster {
+	if cluster, ok := clusters.Load(config); ok {
+		return cluster.(*Cluster)
+	}
+	cluster, err := NewCluster(config)
+	if err != nil {
+		b.Fatal(err)
+	}
+	actual, _ := clusters.LoadOrStore(config, cluster)
+	return actual.(*Cluster)
+}
+
+// newScheduler creates a scheduler of the cluster with the plugins of routerConfiguration.
+func newScheduler(cluster *Cluster) (scheduler.Scheduler, error) {
+	config, err := conf.UnmarshalRouterConfig(routerConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	return scheduler.New(cluster.Store, config)
+}
+
+func promptPool() []common.ChatMessage {
+	generator := NewPromptGenerator(DefaultPromptConfig)
+	prompts := make([]common.ChatMessage, promptPoolSize)
+	for i := range prompts {
+		prompts[i] = generator.Next()
+	}
+	return prompts
+}
+
+// scheduleScenario schedules the requests of a single model server with the default plugins, as the router does
+// for each request: the pods are read from the datastore, filtered, scored, and the post hooks are run.
+func scheduleScenario(config ClusterConfig) func(b *testing.B) {
+	return func(b *testing.B) {
+		cluster := clusterOf(b, config)
+		sched, err := newScheduler(cluster)
+		if err != nil {
+			b.Fatal(err)
+		}
+		prompts := promptPool()
+		var next atomic.Int64
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				i := next.Add(1)
+				pods, err := cluster.Store.GetPodsByModelServer(cluster.ModelServers[0])
+				if err != nil {
+					b.Error(err)
+					return
+				}
+				ctx := &framework.Context{
+					Model:           cluster.Models[0],
+					Prompt:          prompts[i%promptPoolSize],
+					ModelServerName: cluster.ModelServers[0],
+				}
+				if err := sched.Schedule(ctx, pods); err != nil {
+					b.Error(err)
+					return
+				}
+				sched.RunPostHooks(ctx, 0)
+			}
+		})
+	}
+}
+
+// scoreScenario scores the 1000 pods of a model server with a single plugin.
+func scoreScenario(newPlugin func(*Cluster) framework.ScorePlugin) func(b *testing.B) {
+	return func(b *testing.B) {
+		cluster := clusterOf(b, ClusterConfig{ModelServers: 1, PodsPerModelServer: 1000})
+		plugin := newPlugin(cluster)
+		pods, err := cluster.Store.GetPodsByModelServer(cluster.ModelServers[0])
+		if err != nil {
+			b.Fatal(err)
+		}
+		prompts := promptPool()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			ctx := &framework.Context{
+				Model:           cluster.Models[0],
+				Prompt:          prompts[i%promptPoolSize],
+				ModelServerName: cluster.ModelServers[0],
+			}
+			plugin.Score(ctx, pods)
+		}
+	}
+}
+
+func getPodsScenario(b *testing.B) {
+	cluster := clusterOf(b, ClusterConfig{ModelServers: 1, PodsPerModelServer: 1000})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = cluster.Store.GetPodsByModelServer(cluster.ModelServers[0])
+		}
+	})
+}
+
+// getAllPodsScenario snapshots the pods of 10 model servers of 500 pods, as the admin API and the learned state
+// persistence do.
+func getAllPodsScenario(b *testing.B) {
+	cluster := clusterOf(b, ClusterConfig{ModelServers: 10, PodsPerModelServer: 500})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = cluster.Store.GetAllPods()
+	}
+}
+
+// matchModelServerScenario matches the ModelRoutes of 100 models.
+func matchModelServerScenario(b *testing.B) {
+	cluster := clusterOf(b, ClusterConfig{ModelServers: 100, PodsPerModelServer: 1})
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, _, err := cluster.Store.MatchModelServer(cluster.Models[i%len(cluster.Models)], req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// chatCompletion is the response of the fake engine of the proxy scenario.
+const chatCompletion = `{"id":"chatcmpl-1","object":"chat.completion","model":"model-0",` +
+	`"choices":[{"index":0,"message":{"role":"assistant","content":"hello"},"finish_reason":"stop"}],` +
+	`"usage":{"prompt_tokens":10,"completion_tokens":1,"total_tokens":11}}`
+
+// proxy is the router of the proxy scenario, sending the requests to 16 pods of a fake engine.
+type proxy struct {
+	handler http.Handler
+	model   string
+}
+
+var newProxy = sync.OnceValues(func() (*proxy, error) {
+	fakeEngine := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(chatCompletion))
+	}))
+	engineURL, err := url.Parse(fakeEngine.URL)
+	if err != nil {
+		return nil, err
+	}
+	port, _ := strconv.Atoi(engineURL.Port())
+	cluster, err := NewCluster(ClusterConfig{ModelServers: 1, PodsPerModelServer: 16, PodIP: engineURL.Hostname(), Port: int32(port)})
+	if err != nil {
+		return nil, err
+	}
+	// The router reads its configuration from a file
+	configFile := filepath.Join(os.TempDir(), "kthena-benchmark-router-configuration.yaml")
+	if err := os.WriteFile(configFile, routerConfiguration, 0o600); err != nil {
+		return nil, err
+	}
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.Any("/v1/*path", router.NewRouter(cluster.Store, configFile).HandlerFunc())
+	return &proxy{handler: engine, model: cluster.Models[0]}, nil
+})
+
+// proxyScenario sends chat completions through the router to a fake engine, which answers right away.
+func proxyScenario(b *testing.B) {
+	proxy, err := newProxy()
+	if err != nil {
+		b.Fatal(err)
+	}
+	prompts := promptPool()
+	bodies := make([][]byte, len(prompts))
+	for i, prompt := range prompts {
+		bodies[i], _ = json.Marshal(map[string]interface{}{"model": proxy.model, "messages": prompt.Messages})
+	}
+	var next atomic.Int64
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			i := next.Add(1)
+			req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(bodies[i%promptPoolSize]))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			proxy.handler.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				b.Errorf("unexpected status %d: %s", w.Code, w.Body.String())
+				return
+			}
+		}
+	})
+}
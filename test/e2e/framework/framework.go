@@ -0,0 +1,212 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package framework provides the clients and the scenario runner of the kthena e2e tests.
+// It expects a cluster prepared by test/e2e/setup.sh, with kthena installed in the system namespace.
+package framework
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	clientset "github.com/volcano-sh/kthena/client-go/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// DefaultSystemNamespace is the namespace kthena is installed in by setup.sh.
+	DefaultSystemNamespace = "dev"
+	// DefaultFakeEngineImage is the image of the fake engine loaded into the Kind cluster by setup.sh.
+	DefaultFakeEngineImage = "ghcr.io/volcano-sh/fake-engine:1.0.0"
+
+	routerService = "kthena-router"
+	routerPort    = "http"
+	fieldManager  = "kthena-e2e"
+)
+
+// Framework holds the clients shared by the e2e tests.
+type Framework struct {
+	Config        *rest.Config
+	KubeClient    kubernetes.Interface
+	KthenaClient  clientset.Interface
+	DynamicClient dynamic.Interface
+
+	// SystemNamespace is the namespace of the kthena components, overridden by $KTHENA_NAMESPACE.
+	SystemNamespace string
+	// FakeEngineImage is the image substituted in the scenario manifests, overridden by $FAKE_ENGINE_IMAGE.
+	FakeEngineImage string
+
+	mapper meta.RESTMapper
+}
+
+// New builds the clients from $KUBECONFIG or the default kubeconfig, falling back to the in-cluster config.
+func New() (*Framework, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		if config, err = rest.InClusterConfig(); err != nil {
+			return nil, fmt.Errorf("failed to get kubeconfig: %w", err)
+		}
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	kthenaClient, err := clientset.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kthena client: %w", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	return &Framework{
+		Config:          config,
+		KubeClient:      kubeClient,
+		KthenaClient:    kthenaClient,
+		DynamicClient:   dynamicClient,
+		SystemNamespace: envOrDefault("KTHENA_NAMESPACE", DefaultSystemNamespace),
+		FakeEngineImage: envOrDefault("FAKE_ENGINE_IMAGE", DefaultFakeEngineImage),
+		mapper:          restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+	}, nil
+}
+
+// CreateNamespace creates a namespace with a generated name, so that the scenarios are isolated from each other.
+func (f *Framework) CreateNamespace(ctx context.Context, prefix string) (string, error) {
+	ns, err := f.KubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: prefix + "-",
+			Labels:       map[string]string{"app.kubernetes.io/managed-by": fieldManager},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return ns.Name, nil
+}
+
+// DeleteNamespace deletes the namespace and everything created in it.
+func (f *Framework) DeleteNamespace(ctx context.Context, namespace string) error {
+	return f.KubeClient.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+}
+
+// Apply server-side applies the objects of a multi-document YAML manifest.
+// Namespaced objects are applied in the given namespace, whatever namespace is set in the manifest.
+func (f *Framework) Apply(ctx context.Context, namespace string, manifest []byte) error {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to decode manifest: %w", err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if err := f.applyObject(ctx, namespace, obj); err != nil {
+			return fmt.Errorf("failed to apply %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+}
+
+func (f *Framework) applyObject(ctx context.Context, namespace string, obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	mapping, err := f.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return err
+	}
+	var resource dynamic.ResourceInterface = f.DynamicClient.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		obj.SetNamespace(namespace)
+		resource = f.DynamicClient.Resource(mapping.Resource).Namespace(namespace)
+	}
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	force := true
+	_, err = resource.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        &force,
+	})
+	return err
+}
+
+// PodProxyPost sends a POST request to a port of a pod through the API server proxy.
+func (f *Framework) PodProxyPost(ctx context.Context, namespace, pod string, port int32, path string, body []byte) ([]byte, error) {
+	return f.KubeClient.CoreV1().RESTClient().Post().
+		Namespace(namespace).
+		Resource("pods").
+		Name(fmt.Sprintf("%s:%d", pod, port)).
+		SubResource("proxy").
+		Suffix(path).
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		DoRaw(ctx)
+}
+
+// RouterPost sends a POST request to the kthena router through the API server proxy.
+// The status code is returned along with the body, as the scenarios may expect requests to be rejected.
+func (f *Framework) RouterPost(ctx context.Context, path string, body []byte) (int, []byte, error) {
+	var status int
+	result := f.KubeClient.CoreV1().RESTClient().Post().
+		Namespace(f.SystemNamespace).
+		Resource("services").
+		Name(fmt.Sprintf("%s:%s", routerService, routerPort)).
+		SubResource("proxy").
+		Suffix(path).
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		Do(ctx).
+		StatusCode(&status)
+	data, err := result.Raw()
+	if status != 0 {
+		// The request reached the router, an error status is a valid answer.
+		return status, data, nil
+	}
+	return status, data, err
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
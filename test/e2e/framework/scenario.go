@@ -0,0 +1,250 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"text/template"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const defaultStepTimeout = 3 * time.Minute
+
+// Scenario is an e2e test described in a YAML file. Its manifests are applied in a dedicated namespace,
+// then its steps are run in order. The namespace is deleted once the scenario is over.
+type Scenario struct {
+	// Name is the name of the subtest running the scenario.
+	Name string `json:"name"`
+	// Description tells what the scenario verifies.
+	Description string `json:"description,omitempty"`
+	// Vars are available in the manifests as {{ .Vars.<name> }}.
+	Vars map[string]string `json:"vars,omitempty"`
+	// Manifests are the paths, relative to the scenario file, of the manifests applied before the steps.
+	Manifests []string `json:"manifests,omitempty"`
+	// Steps are run in order, the scenario fails on the first failing step.
+	Steps []Step `json:"steps"`
+
+	dir string
+}
+
+// Step is a single action of a scenario. Exactly one action must be set.
+type Step struct {
+	// Name describes the step in the test logs.
+	Name string `json:"name"`
+	// Timeout bounds the time spent on the step, 3m by default.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	Apply           *ApplyStep           `json:"apply,omitempty"`
+	WaitForReplicas *WaitForReplicasStep `json:"waitForReplicas,omitempty"`
+	WaitForPods     *WaitForPodsStep     `json:"waitForPods,omitempty"`
+	Scale           *ScaleStep           `json:"scale,omitempty"`
+	RollingUpdate   *RollingUpdateStep   `json:"rollingUpdate,omitempty"`
+	KillPods        *KillPodsStep        `json:"killPods,omitempty"`
+	SetMetrics      *SetMetricsStep      `json:"setMetrics,omitempty"`
+	Request         *RequestStep         `json:"request,omitempty"`
+}
+
+// action is implemented by every kind of step.
+type action interface {
+	validate() error
+	run(ctx context.Context, sc *scenarioContext) error
+}
+
+// scenarioContext is the state shared by the steps of a running scenario.
+type scenarioContext struct {
+	t         *testing.T
+	framework *Framework
+	scenario  *Scenario
+	namespace string
+}
+
+func (s *Step) action() (action, error) {
+	var actions []action
+	if s.Apply != nil {
+		actions = append(actions, s.Apply)
+	}
+	if s.WaitForReplicas != nil {
+		actions = append(actions, s.WaitForReplicas)
+	}
+	if s.WaitForPods != nil {
+		actions = append(actions, s.WaitForPods)
+	}
+	if s.Scale != nil {
+		actions = append(actions, s.Scale)
+	}
+	if s.RollingUpdate != nil {
+		actions = append(actions, s.RollingUpdate)
+	}
+	if s.KillPods != nil {
+		actions = append(actions, s.KillPods)
+	}
+	if s.SetMetrics != nil {
+		actions = append(actions, s.SetMetrics)
+	}
+	if s.Request != nil {
+		actions = append(actions, s.Request)
+	}
+	if len(actions) != 1 {
+		return nil, fmt.Errorf("step %q must have exactly one action, got %d", s.Name, len(actions))
+	}
+	return actions[0], nil
+}
+
+func (s *Step) timeout() time.Duration {
+	if s.Timeout != nil {
+		return s.Timeout.Duration
+	}
+	return defaultStepTimeout
+}
+
+// LoadScenario reads and validates a scenario file.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	scenario := &Scenario{dir: filepath.Dir(path)}
+	if err := yaml.UnmarshalStrict(data, scenario); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	if err := scenario.validate(); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %w", path, err)
+	}
+	return scenario, nil
+}
+
+// LoadScenarios loads the scenario files of a directory, sorted by file name.
+func LoadScenarios(dir string) ([]*Scenario, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	scenarios := make([]*Scenario, 0, len(paths))
+	for _, path := range paths {
+		scenario, err := LoadScenario(path)
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	return scenarios, nil
+}
+
+func (s *Scenario) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("at least one step is required")
+	}
+	for _, manifest := range s.Manifests {
+		if _, err := os.Stat(filepath.Join(s.dir, manifest)); err != nil {
+			return fmt.Errorf("manifest %s: %w", manifest, err)
+		}
+	}
+	for i := range s.Steps {
+		a, err := s.Steps[i].action()
+		if err != nil {
+			return err
+		}
+		if err := a.validate(); err != nil {
+			return fmt.Errorf("step %q: %w", s.Steps[i].Name, err)
+		}
+		if apply, ok := a.(*ApplyStep); ok {
+			if _, err := os.Stat(filepath.Join(s.dir, apply.Manifest)); err != nil {
+				return fmt.Errorf("step %q: %w", s.Steps[i].Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// render reads a manifest of the scenario and executes it as a template.
+func (sc *scenarioContext) render(manifest string) ([]byte, error) {
+	tmpl, err := template.New(manifest).Option("missingkey=error").ParseFiles(filepath.Join(sc.scenario.dir, manifest))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = tmpl.ExecuteTemplate(&buf, filepath.Base(manifest), map[string]interface{}{
+		"Namespace":       sc.namespace,
+		"SystemNamespace": sc.framework.SystemNamespace,
+		"FakeEngineImage": sc.framework.FakeEngineImage,
+		"Vars":            sc.scenario.Vars,
+	})
+	return buf.Bytes(), err
+}
+
+// RunScenarios runs every scenario of a directory as a subtest.
+func (f *Framework) RunScenarios(t *testing.T, dir string) {
+	scenarios, err := LoadScenarios(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			f.RunScenario(t, scenario)
+		})
+	}
+}
+
+// RunScenario applies the manifests of a scenario in a new namespace and runs its steps.
+func (f *Framework) RunScenario(t *testing.T, scenario *Scenario) {
+	ctx := context.Background()
+	if scenario.Description != "" {
+		t.Log(scenario.Description)
+	}
+
+	namespace, err := f.CreateNamespace(ctx, "e2e-"+scenario.Name)
+	if err != nil {
+		t.Fatalf("Failed to create namespace: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := f.DeleteNamespace(context.Background(), namespace); err != nil {
+			t.Logf("Failed to delete namespace %s: %v", namespace, err)
+		}
+	})
+
+	sc := &scenarioContext{t: t, framework: f, scenario: scenario, namespace: namespace}
+	for _, manifest := range scenario.Manifests {
+		if err := (&ApplyStep{Manifest: manifest}).run(ctx, sc); err != nil {
+			t.Fatalf("Failed to apply manifest %s: %v", manifest, err)
+		}
+	}
+	for i := range scenario.Steps {
+		step := &scenario.Steps[i]
+		// The action has been validated when loading the scenario.
+		a, _ := step.action()
+		t.Logf("Step %d: %s", i+1, step.Name)
+		stepCtx, cancel := context.WithTimeout(ctx, step.timeout())
+		err := a.run(stepCtx, sc)
+		cancel()
+		if err != nil {
+			t.Fatalf("Step %d %q failed: %v", i+1, step.Name, err)
+		}
+	}
+}
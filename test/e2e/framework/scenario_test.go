@@ -0,0 +1,78 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadScenarios checks the scenarios of the e2e suite are valid, without a cluster.
+func TestLoadScenarios(t *testing.T) {
+	scenarios, err := LoadScenarios("../scenarios/testdata")
+	require.NoError(t, err)
+	assert.NotEmpty(t, scenarios)
+	names := map[string]bool{}
+	for _, scenario := range scenarios {
+		assert.False(t, names[scenario.Name], "duplicated scenario %s", scenario.Name)
+		names[scenario.Name] = true
+	}
+}
+
+func TestLoadScenarioErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario string
+	}{
+		{
+			name:     "missing name",
+			scenario: "steps:\n- name: scale\n  scale: {modelServing: a, replicas: 1}\n",
+		},
+		{
+			name:     "no steps",
+			scenario: "name: empty\n",
+		},
+		{
+			name:     "unknown field",
+			scenario: "name: unknown\nsteps:\n- name: restart\n  restart: {modelServing: a}\n",
+		},
+		{
+			name:     "several actions",
+			scenario: "name: several\nsteps:\n- name: scale\n  scale: {modelServing: a, replicas: 1}\n  killPods: {selector: a=b}\n",
+		},
+		{
+			name:     "invalid action",
+			scenario: "name: invalid\nsteps:\n- name: wait\n  waitForReplicas: {modelServing: a}\n",
+		},
+		{
+			name:     "missing manifest",
+			scenario: "name: missing\nmanifests: [missing.yaml]\nsteps:\n- name: scale\n  scale: {modelServing: a, replicas: 1}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "scenario.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.scenario), 0o600))
+			_, err := LoadScenario(path)
+			assert.Error(t, err)
+		})
+	}
+}
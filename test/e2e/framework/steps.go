@@ -0,0 +1,360 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+
+	workload "github.com/volcano-sh/kthena/pkg/apis/workload/v1alpha1"
+)
+
+const (
+	pollInterval = 2 * time.Second
+	// fakeEnginePort is the port the fake engine listens on in the scenario manifests.
+	fakeEnginePort = 8000
+)
+
+// ApplyStep applies a manifest of the scenario, e.g. to create a route once the model is ready.
+type ApplyStep struct {
+	// Manifest is the path of the manifest, relative to the scenario file.
+	Manifest string `json:"manifest"`
+}
+
+func (s *ApplyStep) validate() error {
+	if s.Manifest == "" {
+		return fmt.Errorf("apply: manifest is required")
+	}
+	return nil
+}
+
+func (s *ApplyStep) run(ctx context.Context, sc *scenarioContext) error {
+	manifest, err := sc.render(s.Manifest)
+	if err != nil {
+		return err
+	}
+	return sc.framework.Apply(ctx, sc.namespace, manifest)
+}
+
+// WaitForReplicasStep waits for the status of a ModelServing to reach the expected replicas.
+// Only the set fields are compared, and the status must be observed from the latest generation.
+type WaitForReplicasStep struct {
+	ModelServing      string `json:"modelServing"`
+	Replicas          *int32 `json:"replicas,omitempty"`
+	AvailableReplicas *int32 `json:"availableReplicas,omitempty"`
+	UpdatedReplicas   *int32 `json:"updatedReplicas,omitempty"`
+}
+
+func (s *WaitForReplicasStep) validate() error {
+	if s.ModelServing == "" {
+		return fmt.Errorf("waitForReplicas: modelServing is required")
+	}
+	if s.Replicas == nil && s.AvailableReplicas == nil && s.UpdatedReplicas == nil {
+		return fmt.Errorf("waitForReplicas: at least one of replicas, availableReplicas and updatedReplicas is required")
+	}
+	return nil
+}
+
+func (s *WaitForReplicasStep) run(ctx context.Context, sc *scenarioContext) error {
+	var last workload.ModelServingStatus
+	err := wait.PollUntilContextCancel(ctx, pollInterval, true, func(ctx context.Context) (bool, error) {
+		ms, err := sc.framework.KthenaClient.WorkloadV1alpha1().ModelServings(sc.namespace).Get(ctx, s.ModelServing, metav1.GetOptions{})
+		if err != nil {
+			sc.t.Logf("Get ModelServing %s error: %v", s.ModelServing, err)
+			return false, nil
+		}
+		last = ms.Status
+		return ms.Status.ObservedGeneration == ms.Generation &&
+			matches(s.Replicas, ms.Status.Replicas) &&
+			matches(s.AvailableReplicas, ms.Status.AvailableReplicas) &&
+			matches(s.UpdatedReplicas, ms.Status.UpdatedReplicas), nil
+	})
+	if err != nil {
+		return fmt.Errorf("ModelServing %s did not reach the expected replicas, last status: replicas=%d available=%d updated=%d: %w",
+			s.ModelServing, last.Replicas, last.AvailableReplicas, last.UpdatedReplicas, err)
+	}
+	return nil
+}
+
+func matches(expected *int32, actual int32) bool {
+	return expected == nil || *expected == actual
+}
+
+// WaitForPodsStep waits for the number of ready pods matching a label selector.
+type WaitForPodsStep struct {
+	Selector string `json:"selector"`
+	Ready    int    `json:"ready"`
+}
+
+func (s *WaitForPodsStep) validate() error {
+	_, err := labels.Parse(s.Selector)
+	return err
+}
+
+func (s *WaitForPodsStep) run(ctx context.Context, sc *scenarioContext) error {
+	var ready int
+	err := wait.PollUntilContextCancel(ctx, pollInterval, true, func(ctx context.Context) (bool, error) {
+		pods, err := sc.readyPods(ctx, s.Selector)
+		if err != nil {
+			sc.t.Logf("List pods error: %v", err)
+			return false, nil
+		}
+		ready = len(pods)
+		return ready == s.Ready, nil
+	})
+	if err != nil {
+		return fmt.Errorf("expected %d ready pods matching %q, got %d: %w", s.Ready, s.Selector, ready, err)
+	}
+	return nil
+}
+
+// ScaleStep sets the replicas of a ModelServing.
+type ScaleStep struct {
+	ModelServing string `json:"modelServing"`
+	Replicas     int32  `json:"replicas"`
+}
+
+func (s *ScaleStep) validate() error {
+	if s.ModelServing == "" {
+		return fmt.Errorf("scale: modelServing is required")
+	}
+	return nil
+}
+
+func (s *ScaleStep) run(ctx context.Context, sc *scenarioContext) error {
+	return sc.updateModelServing(ctx, s.ModelServing, func(ms *workload.ModelServing) {
+		ms.Spec.Replicas = &s.Replicas
+	})
+}
+
+// RollingUpdateStep triggers a rolling update of a ModelServing by setting environment variables on
+// all the containers of its roles, which changes the revision of the ServingGroups.
+type RollingUpdateStep struct {
+	ModelServing string            `json:"modelServing"`
+	Env          map[string]string `json:"env"`
+}
+
+func (s *RollingUpdateStep) validate() error {
+	if s.ModelServing == "" {
+		return fmt.Errorf("rollingUpdate: modelServing is required")
+	}
+	if len(s.Env) == 0 {
+		return fmt.Errorf("rollingUpdate: env is required")
+	}
+	return nil
+}
+
+func (s *RollingUpdateStep) run(ctx context.Context, sc *scenarioContext) error {
+	return sc.updateModelServing(ctx, s.ModelServing, func(ms *workload.ModelServing) {
+		for i := range ms.Spec.Template.Roles {
+			role := &ms.Spec.Template.Roles[i]
+			setEnv(role.EntryTemplate.Spec.Containers, s.Env)
+			if role.WorkerTemplate != nil {
+				setEnv(role.WorkerTemplate.Spec.Containers, s.Env)
+			}
+		}
+	})
+}
+
+func setEnv(containers []corev1.Container, env map[string]string) {
+	for i := range containers {
+		for name, value := range env {
+			found := false
+			for j := range containers[i].Env {
+				if containers[i].Env[j].Name == name {
+					containers[i].Env[j] = corev1.EnvVar{Name: name, Value: value}
+					found = true
+				}
+			}
+			if !found {
+				containers[i].Env = append(containers[i].Env, corev1.EnvVar{Name: name, Value: value})
+			}
+		}
+	}
+}
+
+// KillPodsStep force deletes running pods matching a label selector, to simulate failures.
+type KillPodsStep struct {
+	Selector string `json:"selector"`
+	// Count is the number of pods to kill, all the matching pods by default.
+	Count int `json:"count,omitempty"`
+}
+
+func (s *KillPodsStep) validate() error {
+	_, err := labels.Parse(s.Selector)
+	return err
+}
+
+func (s *KillPodsStep) run(ctx context.Context, sc *scenarioContext) error {
+	pods, err := sc.readyPods(ctx, s.Selector)
+	if err != nil {
+		return err
+	}
+	if s.Count > 0 {
+		if len(pods) < s.Count {
+			return fmt.Errorf("only %d ready pods match %q, can not kill %d", len(pods), s.Selector, s.Count)
+		}
+		pods = pods[:s.Count]
+	}
+	gracePeriod := int64(0)
+	for _, pod := range pods {
+		sc.t.Logf("Killing pod %s", pod.Name)
+		err := sc.framework.KubeClient.CoreV1().Pods(sc.namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetMetricsStep overrides the gauges exposed by the fake engine pods matching a label selector,
+// e.g. to make the autoscaler scale the ModelServing.
+type SetMetricsStep struct {
+	Selector string             `json:"selector"`
+	Metrics  map[string]float64 `json:"metrics"`
+}
+
+func (s *SetMetricsStep) validate() error {
+	if len(s.Metrics) == 0 {
+		return fmt.Errorf("setMetrics: metrics is required")
+	}
+	_, err := labels.Parse(s.Selector)
+	return err
+}
+
+func (s *SetMetricsStep) run(ctx context.Context, sc *scenarioContext) error {
+	pods, err := sc.readyPods(ctx, s.Selector)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no ready pods match %q", s.Selector)
+	}
+	body, err := json.Marshal(s.Metrics)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		if _, err := sc.framework.PodProxyPost(ctx, sc.namespace, pod.Name, fakeEnginePort, "fake/metrics", body); err != nil {
+			return fmt.Errorf("failed to set the metrics of pod %s: %w", pod.Name, err)
+		}
+	}
+	return nil
+}
+
+// RequestStep sends chat completion requests through the kthena router. The requests are retried until
+// they all get the expected status, as the routes take some time to be synced by the router.
+type RequestStep struct {
+	Model string `json:"model"`
+	// Count is the number of requests which must succeed in a row, 1 by default.
+	Count int `json:"count,omitempty"`
+	// ExpectStatus is the expected status code, 200 by default.
+	ExpectStatus int `json:"expectStatus,omitempty"`
+	// ExpectBody is a substring expected in every response.
+	ExpectBody string `json:"expectBody,omitempty"`
+}
+
+func (s *RequestStep) validate() error {
+	if s.Model == "" {
+		return fmt.Errorf("request: model is required")
+	}
+	return nil
+}
+
+func (s *RequestStep) run(ctx context.Context, sc *scenarioContext) error {
+	count, expectStatus := s.Count, s.ExpectStatus
+	if count == 0 {
+		count = 1
+	}
+	if expectStatus == 0 {
+		expectStatus = http.StatusOK
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"model":    s.Model,
+		"messages": []map[string]string{{"role": "user", "content": "Hello"}},
+		"stream":   false,
+	})
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	err = wait.PollUntilContextCancel(ctx, pollInterval, true, func(ctx context.Context) (bool, error) {
+		for i := 0; i < count; i++ {
+			status, resp, err := sc.framework.RouterPost(ctx, "v1/chat/completions", body)
+			switch {
+			case err != nil:
+				lastErr = err
+			case status != expectStatus:
+				lastErr = fmt.Errorf("got status %d: %s", status, resp)
+			case !strings.Contains(string(resp), s.ExpectBody):
+				lastErr = fmt.Errorf("response does not contain %q: %s", s.ExpectBody, resp)
+			default:
+				continue
+			}
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("requests to model %s did not get the expected response, last error: %v: %w", s.Model, lastErr, err)
+	}
+	return nil
+}
+
+func (sc *scenarioContext) updateModelServing(ctx context.Context, name string, mutate func(*workload.ModelServing)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ms, err := sc.framework.KthenaClient.WorkloadV1alpha1().ModelServings(sc.namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		mutate(ms)
+		_, err = sc.framework.KthenaClient.WorkloadV1alpha1().ModelServings(sc.namespace).Update(ctx, ms, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// readyPods lists the ready pods of the scenario namespace matching the label selector.
+func (sc *scenarioContext) readyPods(ctx context.Context, selector string) ([]corev1.Pod, error) {
+	pods, err := sc.framework.KubeClient.CoreV1().Pods(sc.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	var ready []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				ready = append(ready, pod)
+				break
+			}
+		}
+	}
+	return ready, nil
+}
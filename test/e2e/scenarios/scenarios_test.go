@@ -0,0 +1,32 @@
+/*
+Copyright The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenarios
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcano-sh/kthena/test/e2e/framework"
+)
+
+// TestScenarios runs every scenario file of testdata against the e2e cluster.
+func TestScenarios(t *testing.T) {
+	f, err := framework.New()
+	require.NoError(t, err, "Failed to create the e2e framework")
+	f.RunScenarios(t, "testdata")
+}
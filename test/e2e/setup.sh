@@ -38,6 +38,7 @@ kubectl wait --for=condition=Ready nodes --all --timeout=300s
 # Docker build
 echo "Start to build Docker images"
 make docker-build-all HUB=${HUB} TAG=${TAG}
+make docker-build-fake-engine HUB=${HUB} TAG=${TAG}
 
 # Load images into Kind cluster
 echo "Loading Docker images into Kind cluster"
@@ -45,6 +46,7 @@ kind load docker-image ${HUB}/kthena-router:${TAG} --name "${CLUSTER_NAME}"
 kind load docker-image ${HUB}/kthena-controller-manager:${TAG} --name "${CLUSTER_NAME}"
 kind load docker-image ${HUB}/downloader:${TAG} --name "${CLUSTER_NAME}"
 kind load docker-image ${HUB}/runtime:${TAG} --name "${CLUSTER_NAME}"
+kind load docker-image ${HUB}/fake-engine:${TAG} --name "${CLUSTER_NAME}"
 
 # Install cert-manager
 echo "Start to install cert-manager"